@@ -0,0 +1,555 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+	"golang.org/x/net/context"
+)
+
+const (
+	// defaultAlertTimeout is the default amount of time a webhook or
+	// command is given to handle an alert.
+	defaultAlertTimeout = 10 * time.Second
+
+	// defaultAlertInterval is the default interval between two
+	// consecutive checks of the conditions raising periodic alerts.
+	defaultAlertInterval = 10 * time.Minute
+
+	// defaultAlertPeerOffline is the default amount of time a peer we
+	// have channels with may be offline before an alert is raised.
+	defaultAlertPeerOffline = 24 * time.Hour
+
+	// defaultAlertSweepDeadline is the default number of blocks past its
+	// maturity height after which an output of a force closed channel
+	// that still hasn't been swept is considered stuck.
+	defaultAlertSweepDeadline = 144
+
+	// alertQueueSize is the number of alerts which may be queued for
+	// dispatch. Alerts raised while the queue is full are dropped.
+	alertQueueSize = 100
+)
+
+// alertType identifies the event an alert reports.
+type alertType string
+
+const (
+	// alertForceCloseRecommended is raised once a channel link fails in a
+	// manner which leaves the channel unusable until it's force closed.
+	alertForceCloseRecommended alertType = "force_close_recommended"
+
+	// alertBreachDetected is raised once the remote party of a channel
+	// has broadcast a revoked commitment.
+	alertBreachDetected alertType = "breach_detected"
+
+	// alertSweepStuck is raised once an output of a force closed channel
+	// hasn't been swept long after reaching maturity.
+	alertSweepStuck alertType = "sweep_stuck"
+
+	// alertPeerOffline is raised once a peer we have channels with has
+	// been offline for too long.
+	alertPeerOffline alertType = "peer_offline"
+
+	// alertLowReserve is raised once our confirmed on-chain balance,
+	// which is needed to pay the fees of sweeps and force closes, falls
+	// below the configured reserve.
+	alertLowReserve alertType = "low_reserve"
+)
+
+// alert is the payload delivered to webhooks and commands. It's encoded as
+// JSON.
+type alert struct {
+	// Type identifies the event the alert reports.
+	Type alertType `json:"type"`
+
+	// Timestamp is the unix timestamp at which the alert was raised.
+	Timestamp int64 `json:"timestamp"`
+
+	// Message is a human readable description of the event.
+	Message string `json:"message"`
+
+	// ChanPoint is the channel point of the channel the event concerns,
+	// if any.
+	ChanPoint string `json:"chan_point,omitempty"`
+
+	// Peer is the hex-encoded public key of the peer the event concerns,
+	// if any.
+	Peer string `json:"peer,omitempty"`
+}
+
+// alerterConfig houses the dependencies and parameters of the alerter.
+type alerterConfig struct {
+	// Webhooks are the URLs each alert is POSTed to.
+	Webhooks []string
+
+	// Commands are the paths of the executables run for each alert. The
+	// type of the alert is passed as the sole argument, and the alert
+	// itself is written to the standard input of the command.
+	Commands []string
+
+	// Timeout is the amount of time a webhook or command is given to
+	// handle an alert.
+	Timeout time.Duration
+
+	// Interval is the time between two consecutive checks of the
+	// conditions raising periodic alerts.
+	Interval time.Duration
+
+	// PeerOffline is the amount of time a peer we have channels with may
+	// be offline before an alert is raised. A value of zero disables the
+	// alert.
+	PeerOffline time.Duration
+
+	// MinReserve is the confirmed on-chain balance below which an alert
+	// is raised. A value of zero disables the alert, unless
+	// RequiredReserve is set.
+	MinReserve btcutil.Amount
+
+	// RequiredReserve, if non-nil, returns the on-chain balance set aside
+	// for our channels. An alert is raised if the confirmed on-chain
+	// balance falls below it, or below MinReserve, whichever is greater.
+	RequiredReserve func() (btcutil.Amount, error)
+
+	// SweepDeadline is the number of blocks past its maturity height
+	// after which an unswept output of a force closed channel raises an
+	// alert. A value of zero disables the alert.
+	SweepDeadline uint32
+
+	// FetchChannels returns all of our open channels.
+	FetchChannels func() ([]*channeldb.OpenChannel, error)
+
+	// IsPeerOnline returns true if we're currently connected to the peer
+	// with the passed public key.
+	IsPeerOnline func(*btcec.PublicKey) bool
+
+	// ConfirmedBalance returns our confirmed on-chain balance.
+	ConfirmedBalance func() (btcutil.Amount, error)
+
+	// BestHeight returns the height of the tip of the chain.
+	BestHeight func() (uint32, error)
+
+	// FetchNurseryReports returns the maturity reports of the force
+	// closed channels whose outputs are tracked by the utxoNursery.
+	FetchNurseryReports func() ([]*contractMaturityReport, error)
+}
+
+// alerter is an optional subsystem which notifies the operator of critical
+// events by POSTing them to webhooks, and by running commands. Events such as
+// a breach are reported as soon as they occur, while conditions such as a
+// peer being offline for too long are checked periodically, and reported
+// once each time they arise.
+type alerter struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg alerterConfig
+
+	// client is the HTTP client used to POST alerts to the webhooks.
+	client *http.Client
+
+	// startTime is the time from which the alerter began observing our
+	// peers.
+	startTime time.Time
+
+	// peerLastSeen is the last time we observed each peer to be online.
+	peerLastSeen map[[33]byte]time.Time
+
+	// active is the set of conditions which were present as of the last
+	// check, such that they're only reported once.
+	active map[string]struct{}
+
+	// alerts queues the alerts awaiting dispatch.
+	alerts chan *alert
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newAlerter creates a new alerter from the passed config.
+func newAlerter(cfg alerterConfig) *alerter {
+	return &alerter{
+		cfg:          cfg,
+		client:       &http.Client{Timeout: cfg.Timeout},
+		startTime:    time.Now(),
+		peerLastSeen: make(map[[33]byte]time.Time),
+		active:       make(map[string]struct{}),
+		alerts:       make(chan *alert, alertQueueSize),
+		quit:         make(chan struct{}),
+	}
+}
+
+// Start launches the goroutines that dispatch alerts and periodically check
+// the conditions raising alerts.
+func (a *alerter) Start() error {
+	if !atomic.CompareAndSwapUint32(&a.started, 0, 1) {
+		return nil
+	}
+
+	srvrLog.Infof("Starting alerter, webhooks=%v, commands=%v, "+
+		"interval=%v", len(a.cfg.Webhooks), len(a.cfg.Commands),
+		a.cfg.Interval)
+
+	a.wg.Add(2)
+	go a.dispatcher()
+	go a.checker()
+
+	return nil
+}
+
+// Stop signals the alerter to exit, and waits for it to do so. Alerts which
+// haven't been dispatched yet are dropped.
+func (a *alerter) Stop() error {
+	if !atomic.CompareAndSwapUint32(&a.stopped, 0, 1) {
+		return nil
+	}
+
+	close(a.quit)
+	a.wg.Wait()
+
+	return nil
+}
+
+// NotifyForceCloseRecommended raises an alert as the link of the passed
+// channel has failed in a manner which leaves the channel unusable until it's
+// force closed.
+//
+// NOTE: This method is safe for concurrent access.
+func (a *alerter) NotifyForceCloseRecommended(chanPoint wire.OutPoint,
+	peer [33]byte, reason error) {
+
+	a.raise(&alert{
+		Type: alertForceCloseRecommended,
+		Message: fmt.Sprintf("channel link failed, the channel "+
+			"should be force closed: %v", reason),
+		ChanPoint: chanPoint.String(),
+		Peer:      hex.EncodeToString(peer[:]),
+	})
+}
+
+// NotifyBreach raises an alert as the remote party of the passed channel has
+// broadcast a revoked commitment.
+//
+// NOTE: This method is safe for concurrent access.
+func (a *alerter) NotifyBreach(chanPoint wire.OutPoint,
+	revokedStateNum uint64) {
+
+	a.raise(&alert{
+		Type: alertBreachDetected,
+		Message: fmt.Sprintf("remote party broadcast revoked state "+
+			"#%v", revokedStateNum),
+		ChanPoint: chanPoint.String(),
+	})
+}
+
+// raise timestamps the passed alert, and queues it for dispatch.
+func (a *alerter) raise(al *alert) {
+	al.Timestamp = time.Now().Unix()
+
+	srvrLog.Warnf("Alert %v: %v", al.Type, al.Message)
+
+	select {
+	case a.alerts <- al:
+	default:
+		srvrLog.Errorf("Alert queue full, dropping %v alert",
+			al.Type)
+	}
+}
+
+// dispatcher delivers the queued alerts to each of the webhooks and
+// commands.
+//
+// NOTE: This MUST be run as a goroutine.
+func (a *alerter) dispatcher() {
+	defer a.wg.Done()
+
+	for {
+		select {
+		case al := <-a.alerts:
+			a.dispatch(al)
+
+		case <-a.quit:
+			return
+		}
+	}
+}
+
+// dispatch delivers the passed alert to each of the webhooks and commands.
+// Failures are only logged, such that a single unreachable webhook doesn't
+// prevent the others from being notified.
+func (a *alerter) dispatch(al *alert) {
+	payload, err := json.Marshal(al)
+	if err != nil {
+		srvrLog.Errorf("Unable to encode %v alert: %v", al.Type, err)
+		return
+	}
+
+	for _, url := range a.cfg.Webhooks {
+		if err := a.postWebhook(url, payload); err != nil {
+			srvrLog.Errorf("Unable to deliver %v alert to "+
+				"webhook: %v", al.Type, err)
+		}
+	}
+
+	for _, command := range a.cfg.Commands {
+		if err := a.runCommand(command, al.Type, payload); err != nil {
+			srvrLog.Errorf("Unable to deliver %v alert to "+
+				"command %v: %v", al.Type, command, err)
+		}
+	}
+}
+
+// postWebhook POSTs the passed payload to the webhook at the passed URL.
+func (a *alerter) postWebhook(url string, payload []byte) error {
+	resp, err := a.client.Post(
+		url, "application/json", bytes.NewReader(payload),
+	)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %v", resp.Status)
+	}
+
+	return nil
+}
+
+// runCommand runs the passed command with the type of the alert as its
+// argument, writing the payload to its standard input.
+func (a *alerter) runCommand(command string, typ alertType,
+	payload []byte) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command, string(typ))
+	cmd.Stdin = bytes.NewReader(payload)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, bytes.TrimSpace(output))
+	}
+
+	return nil
+}
+
+// checker checks the conditions raising periodic alerts on each tick of the
+// configured interval.
+//
+// NOTE: This MUST be run as a goroutine.
+func (a *alerter) checker() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, al := range a.evaluate(time.Now()) {
+				a.raise(al)
+			}
+
+		case <-a.quit:
+			return
+		}
+	}
+}
+
+// evaluate checks the conditions raising periodic alerts as of the passed
+// time, and returns an alert for each condition which has arisen since the
+// previous check. A condition which is no longer present is forgotten, such
+// that it's reported again should it arise once more.
+func (a *alerter) evaluate(now time.Time) []*alert {
+	present := make(map[string]*alert)
+
+	// retain marks the conditions found by a check we were unable to
+	// carry out as still present, as we can't tell whether they are.
+	// Otherwise, they'd be reported again once the check succeeds.
+	retain := func(prefix string) {
+		for key := range a.active {
+			if strings.HasPrefix(key, prefix) {
+				present[key] = nil
+			}
+		}
+	}
+
+	if a.cfg.PeerOffline != 0 {
+		if err := a.checkPeers(now, present); err != nil {
+			srvrLog.Errorf("Unable to check for offline peers: %v",
+				err)
+			retain("peer:")
+		}
+	}
+	if a.cfg.MinReserve != 0 || a.cfg.RequiredReserve != nil {
+		if err := a.checkReserve(present); err != nil {
+			srvrLog.Errorf("Unable to check on-chain reserve: %v",
+				err)
+			retain("reserve")
+		}
+	}
+	if a.cfg.SweepDeadline != 0 {
+		if err := a.checkSweeps(present); err != nil {
+			srvrLog.Errorf("Unable to check for stuck sweeps: %v",
+				err)
+			retain("sweep:")
+		}
+	}
+
+	for key := range a.active {
+		if _, ok := present[key]; !ok {
+			delete(a.active, key)
+		}
+	}
+
+	var alerts []*alert
+	for key, al := range present {
+		if _, ok := a.active[key]; ok {
+			continue
+		}
+
+		a.active[key] = struct{}{}
+		alerts = append(alerts, al)
+	}
+
+	return alerts
+}
+
+// checkPeers adds an alert for each peer we have channels with that has been
+// offline for longer than permitted.
+func (a *alerter) checkPeers(now time.Time, present map[string]*alert) error {
+	channels, err := a.cfg.FetchChannels()
+	if err != nil {
+		return err
+	}
+
+	numChannels := make(map[[33]byte]int)
+	for _, channel := range channels {
+		var peer [33]byte
+		copy(peer[:], channel.IdentityPub.SerializeCompressed())
+
+		numChannels[peer]++
+		if numChannels[peer] > 1 {
+			continue
+		}
+
+		if a.cfg.IsPeerOnline(channel.IdentityPub) {
+			a.peerLastSeen[peer] = now
+		}
+	}
+
+	for peer, n := range numChannels {
+		lastSeen, ok := a.peerLastSeen[peer]
+		if !ok {
+			lastSeen = a.startTime
+		}
+
+		offline := now.Sub(lastSeen)
+		if offline < a.cfg.PeerOffline {
+			continue
+		}
+
+		peerHex := hex.EncodeToString(peer[:])
+		present["peer:"+peerHex] = &alert{
+			Type: alertPeerOffline,
+			Message: fmt.Sprintf("peer with %v channel(s) offline "+
+				"for over %v", n, a.cfg.PeerOffline),
+			Peer: peerHex,
+		}
+	}
+
+	return nil
+}
+
+// checkReserve adds an alert if our confirmed on-chain balance is below the
+// configured reserve, or the reserve required for our channels.
+func (a *alerter) checkReserve(present map[string]*alert) error {
+	reserve := a.cfg.MinReserve
+	if a.cfg.RequiredReserve != nil {
+		required, err := a.cfg.RequiredReserve()
+		if err != nil {
+			return err
+		}
+		if required > reserve {
+			reserve = required
+		}
+	}
+
+	balance, err := a.cfg.ConfirmedBalance()
+	if err != nil {
+		return err
+	}
+	if balance >= reserve {
+		return nil
+	}
+
+	present["reserve"] = &alert{
+		Type: alertLowReserve,
+		Message: fmt.Sprintf("confirmed on-chain balance of %v is "+
+			"below the reserve of %v", balance, reserve),
+	}
+
+	return nil
+}
+
+// checkSweeps adds an alert for each force closed channel with an output
+// which still hasn't been swept long after reaching maturity.
+func (a *alerter) checkSweeps(present map[string]*alert) error {
+	height, err := a.cfg.BestHeight()
+	if err != nil {
+		return err
+	}
+
+	reports, err := a.cfg.FetchNurseryReports()
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		// We'll reuse the time-locked report to determine the
+		// maturity height of each of the unswept outputs of the
+		// channel.
+		timeLocked := newTimeLockedReport()
+		timeLocked.addNurseryReport(report)
+
+		var (
+			stuckAmt     btcutil.Amount
+			stuckOutputs uint32
+		)
+		for _, b := range timeLocked.buckets {
+			if b.maturityHeight == 0 ||
+				height < b.maturityHeight+a.cfg.SweepDeadline {
+
+				continue
+			}
+
+			stuckAmt += b.csvAmount + b.cltvAmount
+			stuckOutputs += b.numOutputs
+		}
+		if stuckOutputs == 0 {
+			continue
+		}
+
+		chanPoint := report.chanPoint.String()
+		present["sweep:"+chanPoint] = &alert{
+			Type: alertSweepStuck,
+			Message: fmt.Sprintf("%v output(s) worth %v unswept "+
+				"over %v blocks past maturity", stuckOutputs,
+				stuckAmt, a.cfg.SweepDeadline),
+			ChanPoint: chanPoint,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestAlerterEvaluate tests that the alerter reports each periodic condition
+// once when it arises, and again only once it has cleared and arisen anew.
+func TestAlerterEvaluate(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	channel := &channeldb.OpenChannel{
+		IdentityPub: priv.PubKey(),
+	}
+	report := &contractMaturityReport{
+		chanPoint:      wire.OutPoint{Index: 1},
+		limboBalance:   50000,
+		maturityHeight: 1000,
+	}
+
+	var (
+		peerOnline bool
+		balance    btcutil.Amount = 100000
+		balanceErr error
+		height     uint32 = 1000
+	)
+	a := newAlerter(alerterConfig{
+		Interval:      time.Minute,
+		PeerOffline:   time.Hour,
+		MinReserve:    50000,
+		SweepDeadline: 10,
+		FetchChannels: func() ([]*channeldb.OpenChannel, error) {
+			return []*channeldb.OpenChannel{channel, channel}, nil
+		},
+		IsPeerOnline: func(*btcec.PublicKey) bool {
+			return peerOnline
+		},
+		ConfirmedBalance: func() (btcutil.Amount, error) {
+			return balance, balanceErr
+		},
+		BestHeight: func() (uint32, error) {
+			return height, nil
+		},
+		FetchNurseryReports: func() ([]*contractMaturityReport, error) {
+			return []*contractMaturityReport{report}, nil
+		},
+	})
+
+	assertAlerts := func(now time.Time, expected ...alertType) {
+		alerts := a.evaluate(now)
+		if len(alerts) != len(expected) {
+			t.Fatalf("expected %v alerts, got %v", len(expected),
+				len(alerts))
+		}
+
+		types := make(map[alertType]struct{})
+		for _, al := range alerts {
+			types[al.Type] = struct{}{}
+		}
+		for _, typ := range expected {
+			if _, ok := types[typ]; !ok {
+				t.Fatalf("expected %v alert", typ)
+			}
+		}
+	}
+
+	// Initially, none of the conditions are present.
+	start := a.startTime
+	assertAlerts(start)
+
+	// Once the peer has been offline for an hour, the balance has fallen
+	// below the reserve, and the output has remained unswept for ten
+	// blocks past its maturity, each condition should be reported.
+	balance = 10000
+	height = 1010
+	assertAlerts(start.Add(time.Hour), alertPeerOffline, alertLowReserve,
+		alertSweepStuck)
+
+	// The conditions shouldn't be reported again while they persist, even
+	// if we're unable to check one of them.
+	balanceErr = errors.New("wallet unavailable")
+	assertAlerts(start.Add(2 * time.Hour))
+	balanceErr = nil
+	assertAlerts(start.Add(3 * time.Hour))
+
+	// Once the peer reconnects, it should only be reported again after it
+	// has been offline for another hour.
+	peerOnline = true
+	assertAlerts(start.Add(4 * time.Hour))
+	peerOnline = false
+	assertAlerts(start.Add(4*time.Hour + time.Minute))
+	assertAlerts(start.Add(5*time.Hour), alertPeerOffline)
+
+	// The same holds for the balance.
+	balance = 100000
+	assertAlerts(start.Add(6 * time.Hour))
+	balance = 10000
+	assertAlerts(start.Add(7*time.Hour), alertLowReserve)
+}
+
+// TestAlerterRequiredReserve tests that the on-chain reserve required for our
+// channels is alerted on once it exceeds our confirmed balance, even if it's
+// above the configured minimum reserve.
+func TestAlerterRequiredReserve(t *testing.T) {
+	t.Parallel()
+
+	var required btcutil.Amount = 10000
+	a := newAlerter(alerterConfig{
+		Interval:   time.Minute,
+		MinReserve: 20000,
+		ConfirmedBalance: func() (btcutil.Amount, error) {
+			return 50000, nil
+		},
+		RequiredReserve: func() (btcutil.Amount, error) {
+			return required, nil
+		},
+	})
+
+	start := a.startTime
+	if alerts := a.evaluate(start); len(alerts) != 0 {
+		t.Fatalf("expected no alerts, got %v", len(alerts))
+	}
+
+	required = 60000
+	alerts := a.evaluate(start.Add(time.Minute))
+	if len(alerts) != 1 || alerts[0].Type != alertLowReserve {
+		t.Fatalf("expected %v alert, got %v", alertLowReserve,
+			alerts)
+	}
+}
+
+// TestAlerterDispatch tests that alerts are delivered to both webhooks and
+// commands, and that a failing webhook doesn't prevent the others from being
+// notified.
+func TestAlerterDispatch(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan *alert, 1)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var al alert
+			err := json.NewDecoder(r.Body).Decode(&al)
+			if err != nil {
+				t.Errorf("unable to decode alert: %v", err)
+			}
+			received <- &al
+		},
+	))
+	defer server.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+	))
+	defer failing.Close()
+
+	tempDir, err := ioutil.TempDir("", "alerts")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Unless we're on Windows, we'll also run a script which records the
+	// alerts written to its standard input.
+	var commands []string
+	outputPath := filepath.Join(tempDir, "output")
+	if runtime.GOOS != "windows" {
+		scriptPath := filepath.Join(tempDir, "alert.sh")
+		script := "#!/bin/sh\necho $1 > " + outputPath +
+			"\ncat >> " + outputPath + "\n"
+		err := ioutil.WriteFile(scriptPath, []byte(script), 0700)
+		if err != nil {
+			t.Fatalf("unable to write script: %v", err)
+		}
+		commands = append(commands, scriptPath)
+	}
+
+	a := newAlerter(alerterConfig{
+		Webhooks: []string{failing.URL, server.URL},
+		Commands: commands,
+		Timeout:  5 * time.Second,
+		Interval: time.Hour,
+	})
+	if err := a.Start(); err != nil {
+		t.Fatalf("unable to start alerter: %v", err)
+	}
+	defer a.Stop()
+
+	chanPoint := wire.OutPoint{Index: 2}
+	a.NotifyBreach(chanPoint, 5)
+
+	select {
+	case al := <-received:
+		if al.Type != alertBreachDetected {
+			t.Fatalf("expected %v alert, got %v",
+				alertBreachDetected, al.Type)
+		}
+		if al.ChanPoint != chanPoint.String() {
+			t.Fatalf("expected chan point %v, got %v", chanPoint,
+				al.ChanPoint)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("alert not delivered to webhook")
+	}
+
+	if len(commands) == 0 {
+		return
+	}
+
+	// The commands are run after the webhooks, so we'll wait for the
+	// script to record the alert.
+	var output []byte
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		output, err = ioutil.ReadFile(outputPath)
+		if err == nil && len(output) > 0 &&
+			output[len(output)-1] == '}' {
+
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != 2 || lines[0] != string(alertBreachDetected) {
+		t.Fatalf("unexpected command output: %s", output)
+	}
+	var al alert
+	if err := json.Unmarshal([]byte(lines[1]), &al); err != nil {
+		t.Fatalf("unable to decode alert: %v", err)
+	}
+	if al.Type != alertBreachDetected {
+		t.Fatalf("expected %v alert, got %v", alertBreachDetected,
+			al.Type)
+	}
+}
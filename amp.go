@@ -0,0 +1,182 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// defaultAmpTimeout is the duration within which all parts of an AMP payment
+// must arrive after its first part, before the parts received are canceled.
+const defaultAmpTimeout = time.Minute
+
+// ampPart is a part of an AMP payment received by the registry.
+type ampPart struct {
+	amt        lnwire.MilliSatoshi
+	share      [32]byte
+	childIndex uint32
+}
+
+// ampSet is the set of parts of an AMP payment received so far.
+type ampSet struct {
+	totalAmt lnwire.MilliSatoshi
+	received lnwire.MilliSatoshi
+
+	// parts maps the payment hash of each part to the part.
+	parts map[chainhash.Hash]ampPart
+
+	// timer cancels the parts received once it fires, unless the set is
+	// complete by then.
+	timer *time.Timer
+}
+
+// AddAmpPart registers the passed HTLC amount, paying to the hold invoice
+// identified by the passed payment hash, as a part of the AMP payment
+// described by the passed record. Once the parts received add up to the total
+// amount of the payment, the root seed of the payment is reconstructed from
+// their shares, and the invoice of each part is settled with the preimage
+// derived from the root seed. If the derived preimages don't match the
+// payment hashes of the parts, or the parts don't add up to the total amount
+// in time, then the invoices of the parts are canceled instead. Registering
+// the same part again is a noop.
+func (i *invoiceRegistry) AddAmpPart(rHash chainhash.Hash,
+	amt lnwire.MilliSatoshi, record *htlcswitch.AmpRecord) error {
+
+	i.ampMtx.Lock()
+	set, ok := i.ampSets[record.SetID]
+	if !ok {
+		setID := record.SetID
+		set = &ampSet{
+			totalAmt: record.TotalAmt,
+			parts:    make(map[chainhash.Hash]ampPart),
+			timer: time.AfterFunc(i.ampTimeout, func() {
+				i.expireAmpSet(setID)
+			}),
+		}
+		i.ampSets[setID] = set
+	}
+
+	if _, ok := set.parts[rHash]; ok {
+		i.ampMtx.Unlock()
+		return nil
+	}
+
+	// All parts must agree upon the total amount of the payment, so we
+	// know when all of them have arrived.
+	if record.TotalAmt != set.totalAmt {
+		i.ampMtx.Unlock()
+		i.cancelAmpParts(record.SetID, []chainhash.Hash{rHash})
+
+		return fmt.Errorf("part specifies total amount %v, but set "+
+			"%x specifies %v", record.TotalAmt, record.SetID[:],
+			set.totalAmt)
+	}
+
+	set.parts[rHash] = ampPart{
+		amt:        amt,
+		share:      record.Share,
+		childIndex: record.ChildIndex,
+	}
+	set.received += amt
+
+	ltndLog.Debugf("Received AMP part of %v for set %x, %v of %v "+
+		"received", amt, record.SetID[:], set.received, set.totalAmt)
+
+	if set.received < set.totalAmt {
+		i.ampMtx.Unlock()
+		return nil
+	}
+
+	// All parts have arrived, so we'll settle them in the background, as
+	// we're called by the link of the last part.
+	set.timer.Stop()
+	delete(i.ampSets, record.SetID)
+	i.ampMtx.Unlock()
+
+	go i.settleAmpSet(record.SetID, set)
+
+	return nil
+}
+
+// settleAmpSet reconstructs the root seed of the passed complete AMP payment,
+// then settles the invoice of each of its parts with the preimage derived
+// from the root seed, releasing the HTLCs held by the links. The invoices are
+// settled atomically, so the payment is never settled in part. If any
+// preimage doesn't match the payment hash of its part, then all parts are
+// canceled instead.
+func (i *invoiceRegistry) settleAmpSet(setID [32]byte, set *ampSet) {
+	shares := make([][32]byte, 0, len(set.parts))
+	hashes := make([]chainhash.Hash, 0, len(set.parts))
+	for rHash, part := range set.parts {
+		shares = append(shares, part.share)
+		hashes = append(hashes, rHash)
+	}
+	root := htlcswitch.AmpRootSeed(shares)
+
+	preimages := make(map[[32]byte][32]byte, len(set.parts))
+	for rHash, part := range set.parts {
+		preimage := htlcswitch.AmpChildPreimage(root, part.childIndex)
+		if sha256.Sum256(preimage[:]) != rHash {
+			ltndLog.Warnf("Derived preimage of AMP part %x "+
+				"doesn't match its payment hash, canceling "+
+				"set %x", rHash[:], setID[:])
+
+			i.cancelAmpParts(setID, hashes)
+			return
+		}
+		preimages[rHash] = preimage
+	}
+
+	if err := i.cdb.SettleHoldInvoices(preimages); err != nil {
+		ltndLog.Errorf("unable to settle AMP set %x: %v", setID[:],
+			err)
+		return
+	}
+	for _, rHash := range hashes {
+		i.releaseHoldWaiters(rHash)
+	}
+
+	ltndLog.Infof("AMP payment of %v with %v parts received for set %x",
+		set.received, len(set.parts), setID[:])
+}
+
+// expireAmpSet cancels the parts of the AMP payment with the passed set ID,
+// if it's still incomplete.
+func (i *invoiceRegistry) expireAmpSet(setID [32]byte) {
+	i.ampMtx.Lock()
+	set, ok := i.ampSets[setID]
+	if !ok {
+		i.ampMtx.Unlock()
+		return
+	}
+	delete(i.ampSets, setID)
+	i.ampMtx.Unlock()
+
+	ltndLog.Warnf("AMP set %x incomplete after %v, %v of %v received, "+
+		"canceling its parts", setID[:], i.ampTimeout, set.received,
+		set.totalAmt)
+
+	hashes := make([]chainhash.Hash, 0, len(set.parts))
+	for rHash := range set.parts {
+		hashes = append(hashes, rHash)
+	}
+	i.cancelAmpParts(setID, hashes)
+}
+
+// cancelAmpParts cancels the invoices of the passed parts of the AMP payment
+// with the passed set ID, such that their HTLCs are failed back to the
+// sender.
+func (i *invoiceRegistry) cancelAmpParts(setID [32]byte,
+	hashes []chainhash.Hash) {
+
+	for _, rHash := range hashes {
+		if err := i.CancelHodlInvoice(rHash); err != nil {
+			ltndLog.Errorf("unable to cancel AMP part %x of set "+
+				"%x: %v", rHash[:], setID[:], err)
+		}
+	}
+}
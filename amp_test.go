@@ -0,0 +1,154 @@
+package main
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// ampTestPart is a part of an AMP payment made within the tests.
+type ampTestPart struct {
+	rHash    chainhash.Hash
+	preimage [32]byte
+	record   *htlcswitch.AmpRecord
+}
+
+// newAmpTestParts splits an AMP payment with the passed set ID and root seed
+// into the passed number of parts, each of which pays amt.
+func newAmpTestParts(setID, root [32]byte, numParts int,
+	amt lnwire.MilliSatoshi) []ampTestPart {
+
+	parts := make([]ampTestPart, numParts)
+	last := root
+	for i := range parts {
+		share := [32]byte{byte(i + 1)}
+		if i == numParts-1 {
+			share = last
+		}
+		for j := range last {
+			last[j] ^= share[j]
+		}
+
+		preimage := htlcswitch.AmpChildPreimage(root, uint32(i))
+		parts[i] = ampTestPart{
+			rHash:    sha256.Sum256(preimage[:]),
+			preimage: preimage,
+			record: &htlcswitch.AmpRecord{
+				SetID:      setID,
+				Share:      share,
+				ChildIndex: uint32(i),
+				TotalAmt:   amt * lnwire.MilliSatoshi(numParts),
+			},
+		}
+	}
+
+	return parts
+}
+
+// TestAmpSet tests that the hold invoices of the parts of an AMP payment are
+// only settled, with their derived preimages, once all parts have arrived,
+// and that the parts of a payment which doesn't complete in time, or whose
+// shares don't derive the payment hashes of its parts, are canceled.
+func TestAmpSet(t *testing.T) {
+	t.Parallel()
+
+	tempDir, err := ioutil.TempDir("", "amp")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cdb, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open db: %v", err)
+	}
+	defer cdb.Close()
+
+	registry := newInvoiceRegistry(cdb, 0)
+	registry.ampTimeout = 200 * time.Millisecond
+
+	const amt = lnwire.MilliSatoshi(10000)
+	addParts := func(parts []ampTestPart) {
+		for _, part := range parts {
+			invoice := &channeldb.Invoice{
+				CreationDate: time.Now(),
+				Terms: channeldb.ContractTerm{
+					Value:       amt,
+					PaymentHash: part.rHash,
+					Hold:        true,
+				},
+			}
+			err := registry.AddInvoice(invoice)
+			if err != nil {
+				t.Fatalf("unable to add invoice: %v", err)
+			}
+
+			err = registry.AddAmpPart(part.rHash, amt, part.record)
+			if err != nil {
+				t.Fatalf("unable to add amp part: %v", err)
+			}
+		}
+	}
+	assertHoldState := func(parts []ampTestPart,
+		state channeldb.HoldState) {
+
+		quit := make(chan struct{})
+		timeout := time.AfterFunc(5*time.Second, func() {
+			close(quit)
+		})
+		defer timeout.Stop()
+
+		for _, part := range parts {
+			invoice, err := registry.AwaitHoldInvoice(
+				part.rHash, quit,
+			)
+			if err != nil {
+				t.Fatalf("unable to await invoice: %v", err)
+			}
+			if invoice.Terms.HoldState != state {
+				t.Fatalf("expected hold state %v, got %v",
+					state, invoice.Terms.HoldState)
+			}
+			if state == channeldb.HoldSettling &&
+				invoice.Terms.PaymentPreimage != part.preimage {
+
+				t.Fatalf("invoice settled with incorrect " +
+					"preimage")
+			}
+		}
+	}
+
+	// Once all parts of a payment have arrived, each should be settled
+	// with its derived preimage, but not before.
+	parts := newAmpTestParts([32]byte{1}, [32]byte{9}, 3, amt)
+	addParts(parts[:2])
+	invoice, err := registry.LookupInvoice(parts[0].rHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if invoice.Terms.HoldState != channeldb.HoldAccepting {
+		t.Fatalf("part settled before all parts arrived")
+	}
+	addParts(parts[2:])
+	assertHoldState(parts, channeldb.HoldSettling)
+
+	// A payment missing one of its parts should have the parts received
+	// canceled once it times out.
+	incomplete := newAmpTestParts([32]byte{2}, [32]byte{8}, 3, amt)
+	addParts(incomplete[:2])
+	assertHoldState(incomplete[:2], channeldb.HoldCanceled)
+
+	// A payment whose shares don't reconstruct the root seed used to
+	// derive the payment hashes of its parts should be canceled as well.
+	invalid := newAmpTestParts([32]byte{3}, [32]byte{7}, 2, amt)
+	invalid[1].record.Share[0] ^= 1
+	addParts(invalid)
+	assertHoldState(invalid, channeldb.HoldCanceled)
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// defaultAutoCloseInterval is the default interval between two consecutive
+// checks of our channels against the auto-close criteria.
+const defaultAutoCloseInterval = time.Hour
+
+// parseExemptPeers parses the hex-encoded public keys of the peers whose
+// channels are exempt from being closed automatically.
+func parseExemptPeers(hexKeys []string) (map[[33]byte]struct{}, error) {
+	exempt := make(map[[33]byte]struct{}, len(hexKeys))
+	for _, hexKey := range hexKeys {
+		keyBytes, err := hex.DecodeString(hexKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode public key "+
+				"%v: %v", hexKey, err)
+		}
+
+		pubKey, err := btcec.ParsePubKey(keyBytes, btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse public key "+
+				"%v: %v", hexKey, err)
+		}
+
+		var pub [33]byte
+		copy(pub[:], pubKey.SerializeCompressed())
+		exempt[pub] = struct{}{}
+	}
+
+	return exempt, nil
+}
+
+// channelReaperConfig houses the dependencies and criteria of the
+// channelReaper.
+type channelReaperConfig struct {
+	// FetchChannels returns all of our open channels.
+	FetchChannels func() ([]*channeldb.OpenChannel, error)
+
+	// IsPeerOnline returns true if we're currently connected to the peer
+	// with the passed public key.
+	IsPeerOnline func(*btcec.PublicKey) bool
+
+	// CloseChannel initiates the cooperative close of the channel with
+	// the passed channel point.
+	CloseChannel func(*wire.OutPoint) error
+
+	// Interval is the time between two consecutive checks of our
+	// channels.
+	Interval time.Duration
+
+	// MaxIdle is the amount of time after which a channel whose
+	// commitment hasn't been updated is closed. A value of zero disables
+	// this criterion.
+	MaxIdle time.Duration
+
+	// MaxPeerOffline is the amount of time after which a channel whose
+	// peer has remained offline is closed. A value of zero disables this
+	// criterion.
+	MaxPeerOffline time.Duration
+
+	// MaxDepleted is the amount of time after which a channel whose
+	// entire balance has remained on one side is closed. A value of zero
+	// disables this criterion.
+	MaxDepleted time.Duration
+
+	// DryRun, if true, causes the channels meeting the criteria to only
+	// be reported, rather than closed.
+	DryRun bool
+
+	// Exempt is the set of peers whose channels are never closed.
+	Exempt map[[33]byte]struct{}
+}
+
+// channelObservation is what the channelReaper has learned about a channel
+// since it started observing it.
+type channelObservation struct {
+	// commitHeight is the height of our commitment when the channel was
+	// last observed.
+	commitHeight uint64
+
+	// lastUpdate is the time at which the commitment height was first
+	// observed to be commitHeight.
+	lastUpdate time.Time
+
+	// depletedSince is the time from which the balance of the channel has
+	// been observed to lie entirely on one side. It's zero if the balance
+	// isn't depleted.
+	depletedSince time.Time
+
+	// peerOfflineExpired is true once the peer has been observed to be
+	// offline for longer than permitted. It remains set once the peer
+	// reconnects, as only then can the channel be closed cooperatively.
+	peerOfflineExpired bool
+}
+
+// reapCandidate is a channel that meets at least one of the auto-close
+// criteria.
+type reapCandidate struct {
+	chanPoint wire.OutPoint
+	peer      *btcec.PublicKey
+	reasons   []string
+}
+
+// channelReaper is an optional policy engine which periodically checks our
+// channels, cooperatively closing those which haven't carried a payment for
+// too long, whose peer has been offline for too long, or whose balance has
+// remained on one side of the channel for too long. The criteria rely on
+// observations made while the daemon is running, so the clock of each
+// criterion starts afresh on restart. In dry-run mode, the channels that
+// would be closed are only reported.
+type channelReaper struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg channelReaperConfig
+
+	// startTime is the time from which the reaper began observing our
+	// channels and peers.
+	startTime time.Time
+
+	// channels holds the observations of each channel, keyed by its
+	// channel point.
+	channels map[wire.OutPoint]*channelObservation
+
+	// peerLastSeen is the last time we observed each peer to be online.
+	peerLastSeen map[[33]byte]time.Time
+
+	// closing is the set of channels we've initiated the close of.
+	closing map[wire.OutPoint]struct{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newChannelReaper creates a new channelReaper from the passed config.
+func newChannelReaper(cfg channelReaperConfig) *channelReaper {
+	return &channelReaper{
+		cfg:          cfg,
+		startTime:    time.Now(),
+		channels:     make(map[wire.OutPoint]*channelObservation),
+		peerLastSeen: make(map[[33]byte]time.Time),
+		closing:      make(map[wire.OutPoint]struct{}),
+		quit:         make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine that periodically checks our channels.
+func (c *channelReaper) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	srvrLog.Infof("Starting channel auto-close, interval=%v, max_idle=%v, "+
+		"max_peer_offline=%v, max_depleted=%v, dry_run=%v",
+		c.cfg.Interval, c.cfg.MaxIdle, c.cfg.MaxPeerOffline,
+		c.cfg.MaxDepleted, c.cfg.DryRun)
+
+	c.wg.Add(1)
+	go c.reaper()
+
+	return nil
+}
+
+// Stop signals the reaper to exit, and waits for it to do so.
+func (c *channelReaper) Stop() error {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return nil
+	}
+
+	close(c.quit)
+	c.wg.Wait()
+
+	return nil
+}
+
+// reaper is the main goroutine of the channelReaper. It checks our channels
+// against the criteria on each tick of the configured interval.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *channelReaper) reaper() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			candidates, err := c.evaluate(time.Now())
+			if err != nil {
+				srvrLog.Errorf("Unable to check channels for "+
+					"auto-close: %v", err)
+				continue
+			}
+
+			c.reap(candidates)
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// evaluate updates our observations of each channel and peer as of the
+// passed time, and returns the channels which meet at least one of the
+// criteria.
+func (c *channelReaper) evaluate(now time.Time) ([]*reapCandidate, error) {
+	channels, err := c.cfg.FetchChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	// Observations of channels which are no longer open can be dropped.
+	openChannels := make(map[wire.OutPoint]struct{}, len(channels))
+	for _, channel := range channels {
+		openChannels[channel.FundingOutpoint] = struct{}{}
+	}
+	for chanPoint := range c.channels {
+		if _, ok := openChannels[chanPoint]; !ok {
+			delete(c.channels, chanPoint)
+			delete(c.closing, chanPoint)
+		}
+	}
+
+	var candidates []*reapCandidate
+	for _, channel := range channels {
+		chanPoint := channel.FundingOutpoint
+
+		var peer [33]byte
+		copy(peer[:], channel.IdentityPub.SerializeCompressed())
+
+		commit := channel.LocalCommitment
+		obs, ok := c.channels[chanPoint]
+		if !ok || obs.commitHeight != commit.CommitHeight {
+			if !ok {
+				obs = &channelObservation{}
+				c.channels[chanPoint] = obs
+			}
+			obs.commitHeight = commit.CommitHeight
+			obs.lastUpdate = now
+		}
+
+		switch {
+		case !isDepleted(channel):
+			obs.depletedSince = time.Time{}
+		case obs.depletedSince.IsZero():
+			obs.depletedSince = now
+		}
+
+		if c.cfg.IsPeerOnline(channel.IdentityPub) {
+			c.peerLastSeen[peer] = now
+		}
+		lastSeen, ok := c.peerLastSeen[peer]
+		if !ok {
+			lastSeen = c.startTime
+		}
+		if c.cfg.MaxPeerOffline != 0 &&
+			now.Sub(lastSeen) >= c.cfg.MaxPeerOffline {
+
+			obs.peerOfflineExpired = true
+		}
+
+		if _, ok := c.cfg.Exempt[peer]; ok {
+			continue
+		}
+		if _, ok := c.closing[chanPoint]; ok {
+			continue
+		}
+
+		var reasons []string
+		idle := now.Sub(obs.lastUpdate)
+		if c.cfg.MaxIdle != 0 && idle >= c.cfg.MaxIdle {
+			reasons = append(reasons, fmt.Sprintf("no payments "+
+				"for %v", idle))
+		}
+		if obs.peerOfflineExpired {
+			reasons = append(reasons, fmt.Sprintf("peer offline "+
+				"for over %v", c.cfg.MaxPeerOffline))
+		}
+		if c.cfg.MaxDepleted != 0 && !obs.depletedSince.IsZero() {
+			depleted := now.Sub(obs.depletedSince)
+			if depleted >= c.cfg.MaxDepleted {
+				reasons = append(reasons, fmt.Sprintf("balance "+
+					"on one side for %v", depleted))
+			}
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		candidates = append(candidates, &reapCandidate{
+			chanPoint: chanPoint,
+			peer:      channel.IdentityPub,
+			reasons:   reasons,
+		})
+	}
+
+	return candidates, nil
+}
+
+// reap reports each of the passed candidates, and, unless we're in dry-run
+// mode, initiates the cooperative close of those whose peer is online.
+func (c *channelReaper) reap(candidates []*reapCandidate) {
+	for _, candidate := range candidates {
+		reasons := strings.Join(candidate.reasons, ", ")
+		peer := candidate.peer.SerializeCompressed()
+
+		if c.cfg.DryRun {
+			srvrLog.Infof("Auto-close dry run: would close "+
+				"ChannelPoint(%v) with peer=%x: %v",
+				candidate.chanPoint, peer, reasons)
+			continue
+		}
+
+		// A cooperative close requires the peer to be online, so
+		// we'll retry on a later check if it isn't.
+		if !c.cfg.IsPeerOnline(candidate.peer) {
+			srvrLog.Debugf("Deferring auto-close of "+
+				"ChannelPoint(%v) until peer=%x is online: %v",
+				candidate.chanPoint, peer, reasons)
+			continue
+		}
+
+		srvrLog.Infof("Auto-closing ChannelPoint(%v) with peer=%x: %v",
+			candidate.chanPoint, peer, reasons)
+
+		chanPoint := candidate.chanPoint
+		if err := c.cfg.CloseChannel(&chanPoint); err != nil {
+			srvrLog.Errorf("Unable to auto-close ChannelPoint(%v): "+
+				"%v", chanPoint, err)
+			continue
+		}
+
+		c.closing[chanPoint] = struct{}{}
+	}
+}
+
+// isDepleted returns true if the entire balance of the channel lies on one
+// side, i.e. either party's balance doesn't exceed its channel reserve.
+func isDepleted(channel *channeldb.OpenChannel) bool {
+	commit := channel.LocalCommitment
+
+	localReserve := lnwire.NewMSatFromSatoshis(
+		channel.LocalChanCfg.ChanReserve,
+	)
+	remoteReserve := lnwire.NewMSatFromSatoshis(
+		channel.RemoteChanCfg.ChanReserve,
+	)
+
+	return commit.LocalBalance <= localReserve ||
+		commit.RemoteBalance <= remoteReserve
+}
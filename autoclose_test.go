@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// newReaperTestChannel creates a channel with a fresh peer, and the passed
+// balances.
+func newReaperTestChannel(t *testing.T, index uint32, localBalance,
+	remoteBalance lnwire.MilliSatoshi) *channeldb.OpenChannel {
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	return &channeldb.OpenChannel{
+		FundingOutpoint: wire.OutPoint{Index: index},
+		IdentityPub:     priv.PubKey(),
+		LocalCommitment: channeldb.ChannelCommitment{
+			LocalBalance:  localBalance,
+			RemoteBalance: remoteBalance,
+		},
+	}
+}
+
+// assertReapCandidates asserts that exactly the passed channels were found to
+// meet the auto-close criteria.
+func assertReapCandidates(t *testing.T, candidates []*reapCandidate,
+	expected ...*channeldb.OpenChannel) {
+
+	if len(candidates) != len(expected) {
+		t.Fatalf("expected %v candidates, got %v", len(expected),
+			len(candidates))
+	}
+	for i, channel := range expected {
+		if candidates[i].chanPoint != channel.FundingOutpoint {
+			t.Fatalf("expected candidate %v, got %v",
+				channel.FundingOutpoint, candidates[i].chanPoint)
+		}
+	}
+}
+
+// TestChannelReaperCriteria ensures that the channel reaper only selects
+// channels which meet the configured criteria, and that it never selects the
+// channels of exempt peers.
+func TestChannelReaperCriteria(t *testing.T) {
+	t.Parallel()
+
+	const balance = lnwire.MilliSatoshi(1000000)
+
+	active := newReaperTestChannel(t, 0, balance, balance)
+	idle := newReaperTestChannel(t, 1, balance, balance)
+	exempt := newReaperTestChannel(t, 2, balance, balance)
+	offline := newReaperTestChannel(t, 3, balance, balance)
+	depleted := newReaperTestChannel(t, 4, balance, 0)
+	channels := []*channeldb.OpenChannel{
+		active, idle, exempt, offline, depleted,
+	}
+
+	var exemptPeer [33]byte
+	copy(exemptPeer[:], exempt.IdentityPub.SerializeCompressed())
+
+	var closed []wire.OutPoint
+	reaper := newChannelReaper(channelReaperConfig{
+		FetchChannels: func() ([]*channeldb.OpenChannel, error) {
+			return channels, nil
+		},
+		IsPeerOnline: func(pub *btcec.PublicKey) bool {
+			return !pub.IsEqual(offline.IdentityPub)
+		},
+		CloseChannel: func(chanPoint *wire.OutPoint) error {
+			closed = append(closed, *chanPoint)
+			return nil
+		},
+		MaxIdle:        2 * time.Hour,
+		MaxPeerOffline: 3 * time.Hour,
+		MaxDepleted:    time.Hour,
+		Exempt: map[[33]byte]struct{}{
+			exemptPeer: {},
+		},
+	})
+
+	// Initially, no channel should meet the criteria, as we've only just
+	// started observing them.
+	now := reaper.startTime
+	candidates, err := reaper.evaluate(now)
+	if err != nil {
+		t.Fatalf("unable to evaluate channels: %v", err)
+	}
+	assertReapCandidates(t, candidates)
+
+	// After an hour, only the depleted channel should be selected.
+	now = now.Add(time.Hour)
+	active.LocalCommitment.CommitHeight++
+	candidates, err = reaper.evaluate(now)
+	if err != nil {
+		t.Fatalf("unable to evaluate channels: %v", err)
+	}
+	assertReapCandidates(t, candidates, depleted)
+
+	// Once the idle threshold has been reached, all but the channel which
+	// has carried payments, and the channel of the exempt peer, should be
+	// selected.
+	now = now.Add(time.Hour)
+	active.LocalCommitment.CommitHeight++
+	candidates, err = reaper.evaluate(now)
+	if err != nil {
+		t.Fatalf("unable to evaluate channels: %v", err)
+	}
+	assertReapCandidates(t, candidates, idle, offline, depleted)
+
+	// Reaping the candidates should only close those whose peer is
+	// online, and they shouldn't be selected again.
+	reaper.reap(candidates)
+	if len(closed) != 2 || closed[0] != idle.FundingOutpoint ||
+		closed[1] != depleted.FundingOutpoint {
+
+		t.Fatalf("unexpected channels closed: %v", closed)
+	}
+
+	now = now.Add(time.Hour)
+	active.LocalCommitment.CommitHeight++
+	candidates, err = reaper.evaluate(now)
+	if err != nil {
+		t.Fatalf("unable to evaluate channels: %v", err)
+	}
+	assertReapCandidates(t, candidates, offline)
+}
+
+// TestChannelReaperDryRun ensures that no channels are closed in dry-run mode.
+func TestChannelReaperDryRun(t *testing.T) {
+	t.Parallel()
+
+	idle := newReaperTestChannel(t, 0, 1000, 1000)
+	reaper := newChannelReaper(channelReaperConfig{
+		FetchChannels: func() ([]*channeldb.OpenChannel, error) {
+			return []*channeldb.OpenChannel{idle}, nil
+		},
+		IsPeerOnline: func(*btcec.PublicKey) bool {
+			return true
+		},
+		CloseChannel: func(*wire.OutPoint) error {
+			t.Fatalf("channel closed in dry-run mode")
+			return nil
+		},
+		MaxIdle: time.Hour,
+		DryRun:  true,
+	})
+
+	if _, err := reaper.evaluate(reaper.startTime); err != nil {
+		t.Fatalf("unable to evaluate channels: %v", err)
+	}
+	candidates, err := reaper.evaluate(reaper.startTime.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unable to evaluate channels: %v", err)
+	}
+	assertReapCandidates(t, candidates, idle)
+
+	reaper.reap(candidates)
+}
+
+// TestParseExemptPeers ensures that invalid exempt peers are rejected.
+func TestParseExemptPeers(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	pubHex := fmt.Sprintf("%x", priv.PubKey().SerializeCompressed())
+
+	exempt, err := parseExemptPeers([]string{pubHex})
+	if err != nil {
+		t.Fatalf("unable to parse exempt peers: %v", err)
+	}
+	if len(exempt) != 1 {
+		t.Fatalf("expected one exempt peer, got %v", len(exempt))
+	}
+
+	if _, err := parseExemptPeers([]string{"zz"}); err == nil {
+		t.Fatalf("expected invalid hex to be rejected")
+	}
+	if _, err := parseExemptPeers([]string{"02abcd"}); err == nil {
+		t.Fatalf("expected invalid public key to be rejected")
+	}
+}
@@ -82,6 +82,15 @@ type BreachConfig struct {
 	// breached channels. This is used in conjunction with DB to recover
 	// from crashes, restarts, or other failures.
 	Store RetributionStore
+
+	// NotifyClosedChannel is called once a breached channel has been
+	// marked as fully closed within the database.
+	NotifyClosedChannel func(wire.OutPoint)
+
+	// NotifyBreach is called once the remote party of a channel is
+	// detected to have broadcast the revoked state with the passed
+	// number. If nil, then the breach is only logged.
+	NotifyBreach func(chanPoint wire.OutPoint, revokedStateNum uint64)
 }
 
 // breachArbiter is a special subsystem which is responsible for watching and
@@ -629,6 +638,10 @@ secondLevelCheck:
 			return
 		}
 
+		if b.cfg.NotifyClosedChannel != nil {
+			b.cfg.NotifyClosedChannel(breachInfo.chanPoint)
+		}
+
 		// Justice has been carried out; we can safely delete the
 		// retribution info from the database.
 		err = b.cfg.Store.Remove(&breachInfo.chanPoint)
@@ -717,6 +730,10 @@ func (b *breachArbiter) breachObserver(
 			"SKETCHY!!!", breachInfo.RevokedStateNum,
 			chanPoint)
 
+		if b.cfg.NotifyBreach != nil {
+			b.cfg.NotifyBreach(chanPoint, breachInfo.RevokedStateNum)
+		}
+
 		// Immediately notify the HTLC switch that this link has been
 		// breached in order to ensure any incoming or outgoing
 		// multi-hop HTLCs aren't sent over this link, nor any other
@@ -1060,7 +1077,9 @@ func (b *breachArbiter) sweepSpendableOutputsTxn(txWeight uint64,
 
 	// We'll actually attempt to target inclusion within the next two
 	// blocks as we'd like to sweep these funds back into our wallet ASAP.
-	feePerWeight, err := b.cfg.Estimator.EstimateFeePerWeight(2)
+	feePerWeight, err := lnwallet.EstimateRelayableFeePerWeight(
+		b.cfg.Estimator, 2,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+const (
+	// defaultHealthCheckInterval is the default interval between two
+	// consecutive checks of the chain backend.
+	defaultHealthCheckInterval = time.Minute
+
+	// defaultHealthCheckTimeout is the default amount of time a single
+	// health check may take before we consider it to have failed.
+	defaultHealthCheckTimeout = 30 * time.Second
+
+	// defaultHealthCheckMaxFailures is the default number of consecutive
+	// failed health checks after which the chain backend is deemed to be
+	// unhealthy.
+	defaultHealthCheckMaxFailures = 3
+)
+
+var (
+	// errHealthCheckTimeout is returned when the chain backend doesn't
+	// respond to a health check in time.
+	errHealthCheckTimeout = errors.New("chain backend health check " +
+		"timed out")
+
+	// errChainBackendUnhealthy is returned by sub-systems that refuse to
+	// act while the chain backend is deemed unhealthy.
+	errChainBackendUnhealthy = errors.New("chain backend is unhealthy")
+)
+
+// chainHealthConfig houses the dependencies and parameters of the
+// chainHealthMonitor.
+type chainHealthConfig struct {
+	// ChainIO is used to query the chain backend for the current best
+	// block.
+	ChainIO lnwallet.BlockChainIO
+
+	// FeeEstimator is the fee estimator used by the daemon. It's queried
+	// for a fee estimate within each health check, as many of our
+	// decisions rely on fresh fee data.
+	FeeEstimator lnwallet.FeeEstimator
+
+	// Interval is the time between two consecutive health checks.
+	Interval time.Duration
+
+	// Timeout is the maximum amount of time a single health check may
+	// take before it's considered to have failed.
+	Timeout time.Duration
+
+	// MaxFailures is the number of consecutive failed health checks after
+	// which the chain backend is deemed to be unhealthy.
+	MaxFailures uint32
+
+	// OnDegraded is called once the chain backend has been deemed to be
+	// unhealthy, with the error of the most recent failed check.
+	OnDegraded func(error)
+
+	// OnRecovered is called once a previously unhealthy chain backend
+	// passes a health check again.
+	OnRecovered func()
+}
+
+// chainHealthStatus is a snapshot of the current health of the chain backend.
+type chainHealthStatus struct {
+	// Healthy is true if the chain backend is currently deemed healthy.
+	Healthy bool
+
+	// NumFailures is the number of consecutive failed health checks.
+	NumFailures uint32
+
+	// LastErr is the error of the most recent failed health check, if
+	// any.
+	LastErr error
+
+	// LastSuccess is the time of the most recent successful health check.
+	LastSuccess time.Time
+
+	// BestHash is the hash of the best block as reported by the chain
+	// backend during the most recent successful health check.
+	BestHash chainhash.Hash
+
+	// BestHeight is the height of the best block as reported by the chain
+	// backend during the most recent successful health check.
+	BestHeight int32
+}
+
+// chainHealthMonitor periodically checks that both the chain backend and the
+// fee estimator are responsive. If the checks fail for a sustained period,
+// then the backend is marked as unhealthy, and the OnDegraded callback is
+// invoked so the rest of the daemon can stop acting on stale chain and fee
+// data. Once a check succeeds again, the OnRecovered callback is invoked.
+type chainHealthMonitor struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg chainHealthConfig
+
+	mu     sync.RWMutex
+	status chainHealthStatus
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newChainHealthMonitor creates a new chainHealthMonitor from the passed
+// config. The chain backend is assumed to be healthy until proven otherwise.
+func newChainHealthMonitor(cfg chainHealthConfig) *chainHealthMonitor {
+	return &chainHealthMonitor{
+		cfg: cfg,
+		status: chainHealthStatus{
+			Healthy: true,
+		},
+		quit: make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine that periodically checks the chain backend. If
+// the configured interval is zero, then health checking is disabled.
+func (c *chainHealthMonitor) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	if c.cfg.Interval == 0 {
+		srvrLog.Infof("Chain backend health checks disabled")
+		return nil
+	}
+
+	srvrLog.Infof("Starting chain backend health checks, interval=%v, "+
+		"timeout=%v, max_failures=%v", c.cfg.Interval, c.cfg.Timeout,
+		c.cfg.MaxFailures)
+
+	c.wg.Add(1)
+	go c.monitor()
+
+	return nil
+}
+
+// Stop signals the monitor to exit, and waits for it to do so.
+func (c *chainHealthMonitor) Stop() error {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return nil
+	}
+
+	close(c.quit)
+	c.wg.Wait()
+
+	return nil
+}
+
+// IsHealthy returns true if the chain backend is currently deemed healthy.
+//
+// NOTE: This method is safe for concurrent access.
+func (c *chainHealthMonitor) IsHealthy() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.status.Healthy
+}
+
+// Status returns a snapshot of the current health of the chain backend.
+//
+// NOTE: This method is safe for concurrent access.
+func (c *chainHealthMonitor) Status() chainHealthStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.status
+}
+
+// monitor is the main goroutine of the chainHealthMonitor. It performs a
+// health check on each tick of the configured interval.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *chainHealthMonitor) monitor() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.processCheckResult(c.runCheck())
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// checkResult is the outcome of a single health check.
+type checkResult struct {
+	bestHash   *chainhash.Hash
+	bestHeight int32
+	err        error
+}
+
+// runCheck queries both the chain backend and the fee estimator, failing the
+// check if either of them returns an error or doesn't respond in time.
+func (c *chainHealthMonitor) runCheck() checkResult {
+	// The queries are carried out in a distinct goroutine, as a hung
+	// backend may never return. The channel is buffered so the goroutine
+	// is able to exit once the backend eventually responds.
+	resultChan := make(chan checkResult, 1)
+	go func() {
+		bestHash, bestHeight, err := c.cfg.ChainIO.GetBestBlock()
+		if err != nil {
+			resultChan <- checkResult{
+				err: fmt.Errorf("unable to query best block: "+
+					"%v", err),
+			}
+			return
+		}
+
+		_, err = c.cfg.FeeEstimator.EstimateFeePerWeight(3)
+		if err != nil {
+			resultChan <- checkResult{
+				err: fmt.Errorf("unable to estimate fee: %v",
+					err),
+			}
+			return
+		}
+
+		resultChan <- checkResult{
+			bestHash:   bestHash,
+			bestHeight: bestHeight,
+		}
+	}()
+
+	select {
+	case result := <-resultChan:
+		return result
+
+	case <-time.After(c.cfg.Timeout):
+		return checkResult{err: errHealthCheckTimeout}
+
+	case <-c.quit:
+		return checkResult{err: fmt.Errorf("health monitor exiting")}
+	}
+}
+
+// processCheckResult updates the status of the chain backend according to the
+// outcome of a health check, and notifies the daemon if the backend
+// transitioned between being healthy and unhealthy.
+func (c *chainHealthMonitor) processCheckResult(result checkResult) {
+	c.mu.Lock()
+
+	// If the check succeeded, then we'll reset our failure count, and
+	// record the latest block reported by the backend.
+	if result.err == nil {
+		wasHealthy := c.status.Healthy
+		c.status = chainHealthStatus{
+			Healthy:     true,
+			LastSuccess: time.Now(),
+			BestHash:    *result.bestHash,
+			BestHeight:  result.bestHeight,
+		}
+		c.mu.Unlock()
+
+		if !wasHealthy {
+			srvrLog.Infof("Chain backend has recovered, best "+
+				"height=%v", result.bestHeight)
+
+			if c.cfg.OnRecovered != nil {
+				c.cfg.OnRecovered()
+			}
+		}
+
+		return
+	}
+
+	c.status.NumFailures++
+	c.status.LastErr = result.err
+
+	srvrLog.Warnf("Chain backend health check failed (%v/%v): %v",
+		c.status.NumFailures, c.cfg.MaxFailures, result.err)
+
+	// We'll only mark the backend as unhealthy once it has failed enough
+	// consecutive checks, to avoid reacting to transient failures.
+	if !c.status.Healthy || c.status.NumFailures < c.cfg.MaxFailures {
+		c.mu.Unlock()
+		return
+	}
+
+	c.status.Healthy = false
+	c.mu.Unlock()
+
+	srvrLog.Errorf("Chain backend deemed unhealthy after %v failed "+
+		"health checks: %v", c.cfg.MaxFailures, result.err)
+
+	if c.cfg.OnDegraded != nil {
+		c.cfg.OnDegraded(result.err)
+	}
+}
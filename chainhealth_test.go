@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// flakyChainIO is a mock BlockChainIO whose GetBestBlock method can be made to
+// fail on demand.
+type flakyChainIO struct {
+	mockChainIO
+
+	sync.Mutex
+	err error
+}
+
+func (f *flakyChainIO) setErr(err error) {
+	f.Lock()
+	f.err = err
+	f.Unlock()
+}
+
+func (f *flakyChainIO) GetBestBlock() (*chainhash.Hash, int32, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+
+	return &chainhash.Hash{}, 100, nil
+}
+
+// TestChainHealthMonitorTransitions ensures that the chain backend is only
+// deemed unhealthy after the configured number of consecutive failures, and
+// that the daemon is notified of both the degradation and the recovery.
+func TestChainHealthMonitorTransitions(t *testing.T) {
+	t.Parallel()
+
+	chainIO := &flakyChainIO{}
+
+	var numDegraded, numRecovered int
+	monitor := newChainHealthMonitor(chainHealthConfig{
+		ChainIO:      chainIO,
+		FeeEstimator: &lnwallet.StaticFeeEstimator{FeeRate: 50},
+		Timeout:      time.Second,
+		MaxFailures:  2,
+		OnDegraded: func(error) {
+			numDegraded++
+		},
+		OnRecovered: func() {
+			numRecovered++
+		},
+	})
+
+	// A successful check should leave the backend healthy, and record the
+	// best block reported by the backend.
+	monitor.processCheckResult(monitor.runCheck())
+	status := monitor.Status()
+	if !status.Healthy || status.BestHeight != 100 {
+		t.Fatalf("unexpected status after successful check: %v",
+			status)
+	}
+
+	// A single failure shouldn't be enough to mark the backend as
+	// unhealthy.
+	chainIO.setErr(errors.New("backend down"))
+	monitor.processCheckResult(monitor.runCheck())
+	if !monitor.IsHealthy() {
+		t.Fatalf("backend marked unhealthy after a single failure")
+	}
+	if numDegraded != 0 {
+		t.Fatalf("expected no degradation, got %v", numDegraded)
+	}
+
+	// After the second consecutive failure, the backend should be marked
+	// unhealthy, and the daemon notified exactly once, even if further
+	// checks fail.
+	monitor.processCheckResult(monitor.runCheck())
+	monitor.processCheckResult(monitor.runCheck())
+	status = monitor.Status()
+	if status.Healthy {
+		t.Fatalf("backend should be unhealthy")
+	}
+	if status.LastErr == nil {
+		t.Fatalf("expected error to be recorded")
+	}
+	if status.BestHeight != 100 {
+		t.Fatalf("last known best height not retained, got %v",
+			status.BestHeight)
+	}
+	if numDegraded != 1 {
+		t.Fatalf("expected a single degradation, got %v", numDegraded)
+	}
+
+	// Once the backend responds again, it should immediately be marked as
+	// healthy.
+	chainIO.setErr(nil)
+	monitor.processCheckResult(monitor.runCheck())
+	status = monitor.Status()
+	if !status.Healthy || status.NumFailures != 0 {
+		t.Fatalf("backend should have recovered: %v", status)
+	}
+	if numRecovered != 1 {
+		t.Fatalf("expected a single recovery, got %v", numRecovered)
+	}
+}
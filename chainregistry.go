@@ -82,6 +82,11 @@ type chainControl struct {
 	wallet *lnwallet.LightningWallet
 
 	routingPolicy htlcswitch.ForwardingPolicy
+
+	// peerRoutingPolicies holds the default fee and time lock policies
+	// for new channels with particular peers, overriding those of
+	// routingPolicy.
+	peerRoutingPolicies map[[33]byte]peerRoutingPolicy
 }
 
 // newChainControlFromConfig attempts to create a chainControl instance
@@ -109,6 +114,7 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 			BaseFee:       cfg.Bitcoin.BaseFee,
 			FeeRate:       cfg.Bitcoin.FeeRate,
 			TimeLockDelta: cfg.Bitcoin.TimeLockDelta,
+			MaxHTLC:       cfg.Bitcoin.MaxHTLC,
 		}
 		cc.feeEstimator = lnwallet.StaticFeeEstimator{
 			FeeRate: 50,
@@ -119,6 +125,7 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 			BaseFee:       cfg.Litecoin.BaseFee,
 			FeeRate:       cfg.Litecoin.FeeRate,
 			TimeLockDelta: cfg.Litecoin.TimeLockDelta,
+			MaxHTLC:       cfg.Litecoin.MaxHTLC,
 		}
 		cc.feeEstimator = lnwallet.StaticFeeEstimator{
 			FeeRate: 100,
@@ -143,6 +150,13 @@ func newChainControlFromConfig(cfg *config, chanDB *channeldb.DB,
 		bitcoindConn *chain.BitcoindClient
 	)
 
+	cc.peerRoutingPolicies, err = parsePeerPolicies(
+		cfg.PeerPolicies, cfg.MinCltvDelta,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// If spv mode is active, then we'll be using a distinct set of
 	// chainControl interfaces that interface directly with the p2p network
 	// of the selected chain.
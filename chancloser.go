@@ -199,6 +199,17 @@ func newChannelCloser(cfg chanCloseCfg, deliveryScript []byte,
 // initChanShutdown beings the shutdown process by un-registering the channel,
 // and creating a valid shutdown message to our target delivery address.
 func (c *channelCloser) initChanShutdown() (*lnwire.Shutdown, error) {
+	// Before we advertise our delivery script, we'll record it within the
+	// channel's state, so that our output within the closing transaction
+	// can be located once it confirms, even if the script doesn't belong
+	// to our wallet.
+	err := c.cfg.channel.State().SetLocalDeliveryScript(
+		c.localDeliveryScript,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// With both items constructed we'll now send the shutdown message for
 	// this particular channel, advertising a shutdown request to our
 	// desired closing script.
@@ -289,8 +300,12 @@ func (c *channelCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message, b
 		}
 
 		// Next, we'll note the other party's preference for their
-		// delivery address. We'll use this when we craft the closure
-		// transaction.
+		// delivery address, after ensuring it's one we can agree to.
+		// We'll use this when we craft the closure transaction.
+		err := lnwallet.ValidateDeliveryScript(shutDownMsg.Address)
+		if err != nil {
+			return nil, false, err
+		}
 		c.remoteDeliveryScript = shutDownMsg.Address
 
 		// We'll generate a shutdown message of our own to set across
@@ -337,7 +352,12 @@ func (c *channelCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message, b
 		}
 
 		// Now that we know this is a valid shutdown message, we'll
-		// record their preferred delivery closing script.
+		// record their preferred delivery closing script, after
+		// ensuring it's one we can agree to.
+		err := lnwallet.ValidateDeliveryScript(shutDownMsg.Address)
+		if err != nil {
+			return nil, false, err
+		}
 		c.remoteDeliveryScript = shutDownMsg.Address
 
 		// At this point, we can now start the fee negotiation state,
@@ -0,0 +1,103 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrNoChanSync is returned when no ChannelReestablish message has
+	// been received from the remote party of a channel.
+	ErrNoChanSync = fmt.Errorf("no chan sync message received")
+
+	// lastChanSyncKey is the key within a channel's bucket that stores the
+	// last ChannelReestablish message received from the remote party,
+	// prefixed by the unix timestamp at which it was received.
+	lastChanSyncKey = []byte("last-chan-sync-key")
+)
+
+// ChanSyncRecord is the last ChannelReestablish message received from the
+// remote party of a channel. It's kept to allow a failure to re-synchronize
+// the commitment chains of the channel to be diagnosed.
+type ChanSyncRecord struct {
+	// Msg is the ChannelReestablish message sent by the remote party.
+	Msg *lnwire.ChannelReestablish
+
+	// Received is the time the message was received.
+	Received time.Time
+}
+
+// PutLastChanSync records the passed ChannelReestablish message as the last
+// one received from the remote party, replacing the prior one, if any.
+func (c *OpenChannel) PutLastChanSync(msg *lnwire.ChannelReestablish) error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		chanBucket, err := updateChanBucket(tx, c.IdentityPub,
+			&c.FundingOutpoint, c.ChainHash)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		err = writeElements(&b, uint64(time.Now().Unix()), msg)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Put(lastChanSyncKey, b.Bytes())
+	})
+}
+
+// LastChanSync returns the last ChannelReestablish message received from the
+// remote party. If no message has been received, then ErrNoChanSync is
+// returned.
+func (c *OpenChannel) LastChanSync() (*ChanSyncRecord, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var record *ChanSyncRecord
+	err := c.Db.View(func(tx *bolt.Tx) error {
+		chanBucket, err := readChanBucket(tx, c.IdentityPub,
+			&c.FundingOutpoint, c.ChainHash)
+		if err != nil {
+			return err
+		}
+
+		recordBytes := chanBucket.Get(lastChanSyncKey)
+		if recordBytes == nil {
+			return ErrNoChanSync
+		}
+
+		var (
+			received uint64
+			msg      lnwire.Message
+		)
+		r := bytes.NewReader(recordBytes)
+		if err := readElements(r, &received, &msg); err != nil {
+			return err
+		}
+
+		chanSync, ok := msg.(*lnwire.ChannelReestablish)
+		if !ok {
+			return fmt.Errorf("expected ChannelReestablish, "+
+				"instead got %T", msg)
+		}
+
+		record = &ChanSyncRecord{
+			Msg:      chanSync,
+			Received: time.Unix(int64(received), 0),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,67 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestLastChanSync tests that the last ChannelReestablish message received
+// for a channel is persisted, and replaced by the next one.
+func TestLastChanSync(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	channel, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := channel.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	// Initially, no message should be recorded for the channel.
+	if _, err := channel.LastChanSync(); err != ErrNoChanSync {
+		t.Fatalf("expected ErrNoChanSync, instead got %v", err)
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
+	msgs := []*lnwire.ChannelReestablish{
+		{
+			ChanID:                 chanID,
+			NextLocalCommitHeight:  1,
+			RemoteCommitTailHeight: 0,
+		},
+		{
+			ChanID:                    chanID,
+			NextLocalCommitHeight:     5,
+			RemoteCommitTailHeight:    4,
+			LastRemoteCommitSecret:    [32]byte{1},
+			LocalUnrevokedCommitPoint: pubKey,
+		},
+	}
+	for _, msg := range msgs {
+		if err := channel.PutLastChanSync(msg); err != nil {
+			t.Fatalf("unable to put chan sync message: %v", err)
+		}
+
+		record, err := channel.LastChanSync()
+		if err != nil {
+			t.Fatalf("unable to fetch chan sync message: %v", err)
+		}
+		if !reflect.DeepEqual(record.Msg, msg) {
+			t.Fatalf("messages don't match: expected %v, got %v",
+				spew.Sdump(msg), spew.Sdump(record.Msg))
+		}
+		if record.Received.IsZero() {
+			t.Fatalf("expected receipt time to be set")
+		}
+	}
+}
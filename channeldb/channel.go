@@ -695,6 +695,11 @@ func (c *OpenChannel) UpdateCommitment(newCommitment *ChannelCommitment) error {
 
 	c.LocalCommitment = *newCommitment
 
+	c.Db.notifyCommitHooks(
+		c.FundingOutpoint, newCommitment.CommitHeight,
+		LocalCommitUpdated,
+	)
+
 	return nil
 }
 
@@ -923,7 +928,7 @@ func (c *OpenChannel) AppendRemoteCommitChain(diff *CommitDiff) error {
 	c.Lock()
 	defer c.Unlock()
 
-	return c.Db.Update(func(tx *bolt.Tx) error {
+	err := c.Db.Update(func(tx *bolt.Tx) error {
 		// First, we'll grab the writeable bucket where this channel's
 		// data resides.
 		chanBucket, err := updateChanBucket(tx, c.IdentityPub,
@@ -942,6 +947,16 @@ func (c *OpenChannel) AppendRemoteCommitChain(diff *CommitDiff) error {
 		}
 		return chanBucket.Put(commitDiffKey, b.Bytes())
 	})
+	if err != nil {
+		return err
+	}
+
+	c.Db.notifyCommitHooks(
+		c.FundingOutpoint, diff.Commitment.CommitHeight,
+		RemoteCommitPending,
+	)
+
+	return nil
 }
 
 // RemoteCommitChainTip returns the "tip" of the current remote commitment
@@ -1015,8 +1030,10 @@ func (c *OpenChannel) InsertNextRevocation(revKey *btcec.PublicKey) error {
 // this log can be consulted in order to reconstruct the state needed to
 // rectify the situation. This method will add the current commitment for the
 // remote party to the revocation log, and promote the current pending
-// commitment to the current remove commitment.
-func (c *OpenChannel) AdvanceCommitChainTail() error {
+// commitment to the current remove commitment. The passed forwarding package,
+// holding the updates of the remote party locked in by this transition, is
+// written within the same transaction, so it's never lost.
+func (c *OpenChannel) AdvanceCommitChainTail(fwdPkg *FwdPkg) error {
 	c.Lock()
 	defer c.Unlock()
 
@@ -1075,6 +1092,12 @@ func (c *OpenChannel) AdvanceCommitChainTail() error {
 			return err
 		}
 
+		// Lastly, we'll persist the forwarding package of the updates
+		// that were locked in by this transition.
+		if err := putFwdPkg(tx, fwdPkg); err != nil {
+			return err
+		}
+
 		newRemoteCommit = &newCommit.Commitment
 		return nil
 	})
@@ -1087,6 +1110,11 @@ func (c *OpenChannel) AdvanceCommitChainTail() error {
 	// of the commit chain.
 	c.RemoteCommitment = *newRemoteCommit
 
+	c.Db.notifyCommitHooks(
+		c.FundingOutpoint, newRemoteCommit.CommitHeight,
+		RemoteCommitAdvanced,
+	)
+
 	return nil
 }
 
@@ -1363,6 +1391,12 @@ func (c *OpenChannel) CloseChannel(summary *ChannelCloseSummary) error {
 			return err
 		}
 
+		// The forwarding packages of the channel are no longer of any
+		// use, as no further updates will be processed.
+		if err := deleteFwdPkgs(tx, c.ShortChanID); err != nil {
+			return err
+		}
+
 		// Finally, create a summary of this channel in the closed
 		// channel bucket for this node.
 		return putChannelCloseSummary(tx, chanPointBuf.Bytes(), summary)
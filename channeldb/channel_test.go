@@ -486,7 +486,9 @@ func TestChannelStateTransition(t *testing.T) {
 		t.Fatalf("unable to generate key: %v", err)
 	}
 	channel.RemoteNextRevocation = newPriv.PubKey()
-	if err := channel.AdvanceCommitChainTail(); err != nil {
+	fwdPkg := NewFwdPkg(channel.ShortChanID, oldRemoteCommit.CommitHeight,
+		nil, nil)
+	if err := channel.AdvanceCommitChainTail(fwdPkg); err != nil {
 		t.Fatalf("unable to append to revocation log: %v", err)
 	}
 
@@ -530,7 +532,9 @@ func TestChannelStateTransition(t *testing.T) {
 	if err := channel.AppendRemoteCommitChain(commitDiff); err != nil {
 		t.Fatalf("unable to add to commit chain: %v", err)
 	}
-	if err := channel.AdvanceCommitChainTail(); err != nil {
+	fwdPkg = NewFwdPkg(channel.ShortChanID, oldRemoteCommit.CommitHeight,
+		nil, nil)
+	if err := channel.AdvanceCommitChainTail(fwdPkg); err != nil {
 		t.Fatalf("unable to append to revocation log: %v", err)
 	}
 
@@ -595,6 +599,95 @@ func TestChannelStateTransition(t *testing.T) {
 	}
 }
 
+// recordedTransition is a commitment state transition received by a
+// mockCommitHook.
+type recordedTransition struct {
+	chanPoint    wire.OutPoint
+	commitHeight uint64
+	transition   CommitTransitionType
+}
+
+// mockCommitHook is a CommitHook which records all transitions it's notified
+// of.
+type mockCommitHook struct {
+	transitions []recordedTransition
+}
+
+func (m *mockCommitHook) CommitStatePersisted(chanPoint wire.OutPoint,
+	commitHeight uint64, transition CommitTransitionType) {
+
+	m.transitions = append(m.transitions, recordedTransition{
+		chanPoint:    chanPoint,
+		commitHeight: commitHeight,
+		transition:   transition,
+	})
+}
+
+// TestCommitHooks ensures that registered commit hooks are notified of each
+// commitment state transition once it has been persisted.
+func TestCommitHooks(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	hook := &mockCommitHook{}
+	cdb.RegisterCommitHook(hook)
+
+	channel, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := channel.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	// Simply persisting the channel shouldn't trigger the hook, as no
+	// state transition has taken place.
+	if len(hook.transitions) != 0 {
+		t.Fatalf("expected no transitions, got %v",
+			len(hook.transitions))
+	}
+
+	// We'll now carry out a full state transition: a new local commitment,
+	// followed by a new remote commitment which is then locked in.
+	localCommit := channel.LocalCommitment
+	localCommit.CommitHeight = 1
+	if err := channel.UpdateCommitment(&localCommit); err != nil {
+		t.Fatalf("unable to update commitment: %v", err)
+	}
+
+	remoteCommit := channel.RemoteCommitment
+	remoteCommit.CommitHeight = 1
+	commitDiff := &CommitDiff{
+		Commitment: remoteCommit,
+		CommitSig: &lnwire.CommitSig{
+			ChanID:    lnwire.ChannelID(key),
+			CommitSig: testSig,
+		},
+	}
+	if err := channel.AppendRemoteCommitChain(commitDiff); err != nil {
+		t.Fatalf("unable to add to commit chain: %v", err)
+	}
+	fwdPkg := NewFwdPkg(channel.ShortChanID, 1, nil, nil)
+	if err := channel.AdvanceCommitChainTail(fwdPkg); err != nil {
+		t.Fatalf("unable to append to revocation log: %v", err)
+	}
+
+	expected := []recordedTransition{
+		{channel.FundingOutpoint, 1, LocalCommitUpdated},
+		{channel.FundingOutpoint, 1, RemoteCommitPending},
+		{channel.FundingOutpoint, 1, RemoteCommitAdvanced},
+	}
+	if !reflect.DeepEqual(expected, hook.transitions) {
+		t.Fatalf("unexpected transitions: expected %v, got %v",
+			spew.Sdump(expected), spew.Sdump(hook.transitions))
+	}
+}
+
 func TestFetchPendingChannels(t *testing.T) {
 	t.Parallel()
 
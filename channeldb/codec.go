@@ -86,6 +86,11 @@ func writeElement(w io.Writer, element interface{}) error {
 			return err
 		}
 
+	case uint8:
+		if err := binary.Write(w, byteOrder, e); err != nil {
+			return err
+		}
+
 	case bool:
 		if err := binary.Write(w, byteOrder, e); err != nil {
 			return err
@@ -203,6 +208,11 @@ func readElement(r io.Reader, element interface{}) error {
 			return err
 		}
 
+	case *uint8:
+		if err := binary.Read(r, byteOrder, e); err != nil {
+			return err
+		}
+
 	case *bool:
 		if err := binary.Read(r, byteOrder, e); err != nil {
 			return err
@@ -0,0 +1,76 @@
+package channeldb
+
+import "github.com/roasbeef/btcd/wire"
+
+// CommitTransitionType denotes the kind of commitment state transition that
+// has been persisted for a channel.
+type CommitTransitionType uint8
+
+const (
+	// LocalCommitUpdated denotes that a new local commitment has been
+	// written to disk, which means that our prior local commitment is
+	// about to be revoked.
+	LocalCommitUpdated CommitTransitionType = iota
+
+	// RemoteCommitPending denotes that a new commitment for the remote
+	// party has been written to disk, just before the signature for it is
+	// sent to the remote party.
+	RemoteCommitPending
+
+	// RemoteCommitAdvanced denotes that the remote party has revoked their
+	// prior commitment, which has now been added to the revocation log.
+	RemoteCommitAdvanced
+)
+
+// String returns a human readable version of the CommitTransitionType.
+func (c CommitTransitionType) String() string {
+	switch c {
+	case LocalCommitUpdated:
+		return "LocalCommitUpdated"
+	case RemoteCommitPending:
+		return "RemoteCommitPending"
+	case RemoteCommitAdvanced:
+		return "RemoteCommitAdvanced"
+	default:
+		return "Unknown"
+	}
+}
+
+// CommitHook is an interface which allows callers to be notified each time
+// the commitment state of a channel has been durably persisted. As a node
+// restored from a stale copy of the database risks broadcasting a revoked
+// state, these notifications mark the precise points at which any external
+// replica of the database becomes dangerous to restore from. Operators can use
+// them to trigger replication or snapshotting of the database.
+type CommitHook interface {
+	// CommitStatePersisted is called after a commitment state transition
+	// for the channel identified by chanPoint has been committed to disk.
+	// commitHeight is the height of the commitment that was written.
+	//
+	// NOTE: This method is called synchronously while the channel's state
+	// is locked, so the state machine won't advance until it returns. As a
+	// result, implementations MUST NOT call back into the channel.
+	CommitStatePersisted(chanPoint wire.OutPoint, commitHeight uint64,
+		transition CommitTransitionType)
+}
+
+// RegisterCommitHook adds a new CommitHook which will be notified of all
+// future commitment state transitions of all channels within the database.
+func (d *DB) RegisterCommitHook(hook CommitHook) {
+	d.hookMtx.Lock()
+	d.commitHooks = append(d.commitHooks, hook)
+	d.hookMtx.Unlock()
+}
+
+// notifyCommitHooks dispatches a persisted commitment state transition to all
+// registered commit hooks.
+func (d *DB) notifyCommitHooks(chanPoint wire.OutPoint, commitHeight uint64,
+	transition CommitTransitionType) {
+
+	d.hookMtx.RLock()
+	defer d.hookMtx.RUnlock()
+
+	for _, hook := range d.commitHooks {
+		hook.CommitStatePersisted(chanPoint, commitHeight, transition)
+	}
+}
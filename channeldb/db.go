@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/go-errors/errors"
@@ -17,6 +18,10 @@ import (
 const (
 	dbName           = "channel.db"
 	dbFilePermission = 0600
+
+	// dbOpenTimeout is the amount of time to wait for the file lock of the
+	// database file, should another process hold it.
+	dbOpenTimeout = 10 * time.Second
 )
 
 // migration is a function which takes a prior outdated version of the database
@@ -57,38 +62,119 @@ var bufPool = &sync.Pool{
 type DB struct {
 	*bolt.DB
 	dbPath string
+
+	// commitHooks is the set of hooks that are notified each time the
+	// commitment state of a channel has been persisted.
+	hookMtx     sync.RWMutex
+	commitHooks []CommitHook
+
+	// lock is the exclusive lock of the database held by this process. It's
+	// nil once the database has been closed.
+	lock    *dbLock
+	lockMtx sync.Mutex
+}
+
+// Options holds the optional parameters of Open.
+type Options struct {
+	// ForceLock, if true, takes over the lock of the database regardless
+	// of the process holding it. This is intended for recovery tooling,
+	// and MUST only be used once it's certain that no other process is
+	// using the database.
+	ForceLock bool
+}
+
+// OptionModifier is a function which modifies the Options used to open the
+// database.
+type OptionModifier func(*Options)
+
+// OptionForceLock returns an OptionModifier which forcefully takes over the
+// lock of the database.
+func OptionForceLock() OptionModifier {
+	return func(o *Options) {
+		o.ForceLock = true
+	}
 }
 
 // Open opens an existing channeldb. Any necessary schemas migrations due to
-// updates will take place as necessary.
-func Open(dbPath string) (*DB, error) {
+// updates will take place as necessary. The database is locked for the
+// exclusive use of this process until it's closed. If another process holds
+// the lock, then ErrDBLocked is returned.
+func Open(dbPath string, modifiers ...OptionModifier) (*DB, error) {
+	var opts Options
+	for _, modifier := range modifiers {
+		modifier(&opts)
+	}
+
+	// The lock file resides within the database's directory, so we'll
+	// ensure it exists before acquiring the lock, and creating the
+	// database.
+	if err := os.MkdirAll(dbPath, 0700); err != nil {
+		return nil, err
+	}
+	lock, err := acquireLock(dbPath, opts.ForceLock)
+	if err != nil {
+		return nil, err
+	}
+
 	path := filepath.Join(dbPath, dbName)
 
 	if !fileExists(path) {
 		if err := createChannelDB(dbPath); err != nil {
+			lock.release()
 			return nil, err
 		}
 	}
 
-	bdb, err := bolt.Open(path, dbFilePermission, nil)
+	// Should the lock have been forcefully taken over from a process which
+	// is in fact still running, then the file lock of the database file
+	// itself ensures that we don't open it alongside that process.
+	bdb, err := bolt.Open(path, dbFilePermission, &bolt.Options{
+		Timeout: dbOpenTimeout,
+	})
+	if err == bolt.ErrTimeout {
+		lock.release()
+		return nil, fmt.Errorf("channel database file %v is in use by "+
+			"another process", path)
+	}
 	if err != nil {
+		lock.release()
 		return nil, err
 	}
 
 	chanDB := &DB{
 		DB:     bdb,
 		dbPath: dbPath,
+		lock:   lock,
 	}
 
 	// Synchronize the version of database and apply migrations if needed.
 	if err := chanDB.syncVersions(dbVersions); err != nil {
-		bdb.Close()
+		chanDB.Close()
 		return nil, err
 	}
 
 	return chanDB, nil
 }
 
+// Close closes the database, and releases its lock.
+func (d *DB) Close() error {
+	err := d.DB.Close()
+
+	d.lockMtx.Lock()
+	defer d.lockMtx.Unlock()
+
+	if d.lock == nil {
+		return err
+	}
+
+	if lockErr := d.lock.release(); lockErr != nil && err == nil {
+		err = lockErr
+	}
+	d.lock = nil
+
+	return err
+}
+
 // Path returns the file path to the channel database.
 func (d *DB) Path() string {
 	return d.dbPath
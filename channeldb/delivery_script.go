@@ -0,0 +1,56 @@
+package channeldb
+
+import (
+	"github.com/boltdb/bolt"
+)
+
+// localDeliveryScriptKey is the key within a channel's bucket that stores the
+// script our funds are paid out to upon a cooperative close of the channel.
+var localDeliveryScriptKey = []byte("local-delivery-script-key")
+
+// SetLocalDeliveryScript records the script our funds are to be paid out to
+// upon a cooperative close of the channel. It's to be called _before_ the
+// script is sent to the remote party, so that our output can be located
+// within the closing transaction, even if the script doesn't belong to our
+// wallet, or we restart before the transaction confirms.
+func (c *OpenChannel) SetLocalDeliveryScript(script []byte) error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		chanBucket, err := updateChanBucket(tx, c.IdentityPub,
+			&c.FundingOutpoint, c.ChainHash)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Put(localDeliveryScriptKey, script)
+	})
+}
+
+// LocalDeliveryScript returns the script our funds are to be paid out to upon
+// a cooperative close of the channel, or nil if none has been recorded.
+func (c *OpenChannel) LocalDeliveryScript() ([]byte, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var script []byte
+	err := c.Db.View(func(tx *bolt.Tx) error {
+		chanBucket, err := readChanBucket(tx, c.IdentityPub,
+			&c.FundingOutpoint, c.ChainHash)
+		if err != nil {
+			return err
+		}
+
+		if s := chanBucket.Get(localDeliveryScriptKey); s != nil {
+			script = append([]byte(nil), s...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return script, nil
+}
@@ -0,0 +1,50 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestLocalDeliveryScript tests that the local delivery script of a channel is
+// persisted, and that a later script replaces a prior one.
+func TestLocalDeliveryScript(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	channel, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := channel.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	// Initially, no script should be recorded for the channel.
+	script, err := channel.LocalDeliveryScript()
+	if err != nil {
+		t.Fatalf("unable to fetch delivery script: %v", err)
+	}
+	if script != nil {
+		t.Fatalf("expected no delivery script, got %x", script)
+	}
+
+	for _, expected := range [][]byte{{0, 20, 1}, {81, 32, 2}} {
+		if err := channel.SetLocalDeliveryScript(expected); err != nil {
+			t.Fatalf("unable to set delivery script: %v", err)
+		}
+
+		script, err := channel.LocalDeliveryScript()
+		if err != nil {
+			t.Fatalf("unable to fetch delivery script: %v", err)
+		}
+		if !bytes.Equal(script, expected) {
+			t.Fatalf("expected delivery script %x, got %x",
+				expected, script)
+		}
+	}
+}
@@ -31,6 +31,26 @@ var (
 	// payment hash already exists.
 	ErrDuplicateInvoice = fmt.Errorf("invoice with payment hash already exists")
 
+	// ErrInvoiceAlreadySettled is returned when attempting to accept an
+	// HTLC paying to an invoice which has already been settled.
+	ErrInvoiceAlreadySettled = fmt.Errorf("invoice already settled")
+
+	// ErrInvoiceAlreadyCanceled is returned when attempting to accept an
+	// HTLC paying to, or to settle, an invoice which has been canceled.
+	ErrInvoiceAlreadyCanceled = fmt.Errorf("invoice already canceled")
+
+	// ErrNotHoldInvoice is returned when attempting to settle or cancel
+	// an invoice which isn't a hold invoice.
+	ErrNotHoldInvoice = fmt.Errorf("invoice isn't a hold invoice")
+
+	// ErrHoldInvoiceCanceled is returned when attempting to settle a hold
+	// invoice which has already been canceled.
+	ErrHoldInvoiceCanceled = fmt.Errorf("hold invoice has been canceled")
+
+	// ErrHoldInvoiceSettling is returned when attempting to cancel a hold
+	// invoice whose preimage has already been released.
+	ErrHoldInvoiceSettling = fmt.Errorf("hold invoice is being settled")
+
 	// ErrNoPaymentsCreated is returned when bucket of payments hasn't been
 	// created.
 	ErrNoPaymentsCreated = fmt.Errorf("there are no existing payments")
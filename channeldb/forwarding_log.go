@@ -0,0 +1,210 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// forwardingLogBucket is the name of the bucket which houses the forwarding
+// log. Each event within it is keyed by the big-endian unix nanosecond time
+// at which the HTLC was settled, so the log is stored in chronological order.
+var forwardingLogBucket = []byte("forwarding-log")
+
+// ForwardingEvent records a single HTLC which was successfully forwarded by
+// the switch.
+type ForwardingEvent struct {
+	// Timestamp is the time at which the forwarded HTLC was settled.
+	Timestamp time.Time
+
+	// IncomingChanID is the channel over which the HTLC arrived.
+	IncomingChanID lnwire.ShortChannelID
+
+	// OutgoingChanID is the channel over which the HTLC was forwarded.
+	OutgoingChanID lnwire.ShortChannelID
+
+	// AmtIn is the value of the incoming HTLC.
+	AmtIn lnwire.MilliSatoshi
+
+	// AmtOut is the value of the outgoing HTLC.
+	AmtOut lnwire.MilliSatoshi
+}
+
+// Fee returns the fee earned by forwarding the HTLC.
+func (f *ForwardingEvent) Fee() lnwire.MilliSatoshi {
+	if f.AmtOut > f.AmtIn {
+		return 0
+	}
+
+	return f.AmtIn - f.AmtOut
+}
+
+// ForwardingLog is the persistent log of all HTLCs successfully forwarded by
+// the node, allowing operators to audit their routing revenue.
+type ForwardingLog struct {
+	db *DB
+}
+
+// ForwardingLog returns the forwarding log backed by the database.
+func (d *DB) ForwardingLog() *ForwardingLog {
+	return &ForwardingLog{db: d}
+}
+
+// AddForwardingEvents adds the passed events to the forwarding log. Events
+// sharing a timestamp with an event already within the log are stored a
+// nanosecond later, so that no event is overwritten.
+func (f *ForwardingLog) AddForwardingEvents(events []ForwardingEvent) error {
+	return f.db.Update(func(tx *bolt.Tx) error {
+		logBucket, err := tx.CreateBucketIfNotExists(
+			forwardingLogBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		for i := range events {
+			var b bytes.Buffer
+			err := serializeForwardingEvent(&b, &events[i])
+			if err != nil {
+				return err
+			}
+
+			key := forwardingTimeKey(events[i].Timestamp)
+			for logBucket.Get(key) != nil {
+				byteOrder.PutUint64(key, byteOrder.Uint64(key)+1)
+			}
+
+			if err := logBucket.Put(key, b.Bytes()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ForwardingEventQuery selects the events to be returned by a query of the
+// forwarding log.
+type ForwardingEventQuery struct {
+	// StartTime is the time of the earliest event to be returned,
+	// inclusive.
+	StartTime time.Time
+
+	// EndTime is the time of the latest event to be returned, inclusive.
+	EndTime time.Time
+
+	// IndexOffset is the number of events within the time range to skip,
+	// allowing the results of a query to be paginated.
+	IndexOffset uint32
+
+	// NumMaxEvents is the maximum number of events to return.
+	NumMaxEvents uint32
+}
+
+// ForwardingLogTimeSlice is the result of a query of the forwarding log.
+type ForwardingLogTimeSlice struct {
+	// ForwardingEventQuery is the query the slice was returned for.
+	ForwardingEventQuery
+
+	// ForwardingEvents holds the events returned by the query, in
+	// chronological order.
+	ForwardingEvents []ForwardingEvent
+
+	// LastIndexOffset is the offset of the event following the last one
+	// returned. It should be used as the IndexOffset of the query for the
+	// next page of events.
+	LastIndexOffset uint32
+}
+
+// Query returns the events within the forwarding log matching the passed
+// query.
+func (f *ForwardingLog) Query(
+	q ForwardingEventQuery) (ForwardingLogTimeSlice, error) {
+
+	resp := ForwardingLogTimeSlice{
+		ForwardingEventQuery: q,
+		LastIndexOffset:      q.IndexOffset,
+	}
+
+	err := f.db.View(func(tx *bolt.Tx) error {
+		logBucket := tx.Bucket(forwardingLogBucket)
+		if logBucket == nil {
+			return nil
+		}
+
+		endKey := forwardingTimeKey(q.EndTime)
+
+		var skipped uint32
+		c := logBucket.Cursor()
+		k, v := c.Seek(forwardingTimeKey(q.StartTime))
+		for ; k != nil; k, v = c.Next() {
+			if bytes.Compare(k, endKey) > 0 {
+				break
+			}
+			if uint32(len(resp.ForwardingEvents)) >= q.NumMaxEvents {
+				break
+			}
+
+			if skipped < q.IndexOffset {
+				skipped++
+				continue
+			}
+
+			event, err := deserializeForwardingEvent(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			event.Timestamp = time.Unix(
+				0, int64(byteOrder.Uint64(k)),
+			)
+
+			resp.ForwardingEvents = append(
+				resp.ForwardingEvents, *event,
+			)
+			resp.LastIndexOffset++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ForwardingLogTimeSlice{}, err
+	}
+
+	return resp, nil
+}
+
+// forwardingTimeKey returns the key of an event which occurred at the passed
+// time. Times prior to the unix epoch map to the smallest possible key.
+func forwardingTimeKey(t time.Time) []byte {
+	var unixNano uint64
+	if t.UnixNano() > 0 {
+		unixNano = uint64(t.UnixNano())
+	}
+
+	var k [8]byte
+	byteOrder.PutUint64(k[:], unixNano)
+	return k[:]
+}
+
+func serializeForwardingEvent(w io.Writer, f *ForwardingEvent) error {
+	return writeElements(w,
+		f.IncomingChanID, f.OutgoingChanID, f.AmtIn, f.AmtOut,
+	)
+}
+
+func deserializeForwardingEvent(r io.Reader) (*ForwardingEvent, error) {
+	f := &ForwardingEvent{}
+	err := readElements(r,
+		&f.IncomingChanID, &f.OutgoingChanID, &f.AmtIn, &f.AmtOut,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
@@ -0,0 +1,130 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestForwardingLogQuery tests that forwarding events can be queried by time
+// range in chronological order, that the results can be paginated, and that
+// events sharing a timestamp don't overwrite each other.
+func TestForwardingLogQuery(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	fwdLog := cdb.ForwardingLog()
+
+	// Querying an empty log should return no events.
+	base := time.Unix(0, time.Now().UnixNano())
+	slice, err := fwdLog.Query(ForwardingEventQuery{
+		StartTime:    time.Unix(0, 0),
+		EndTime:      base.Add(time.Hour),
+		NumMaxEvents: 100,
+	})
+	if err != nil {
+		t.Fatalf("unable to query log: %v", err)
+	}
+	if len(slice.ForwardingEvents) != 0 {
+		t.Fatalf("expected no events, instead got %v",
+			len(slice.ForwardingEvents))
+	}
+
+	// Add ten events a minute apart, out of chronological order.
+	events := make([]ForwardingEvent, 10)
+	for i := range events {
+		events[i] = ForwardingEvent{
+			Timestamp:      base.Add(time.Duration(i) * time.Minute),
+			IncomingChanID: lnwire.NewShortChanIDFromInt(uint64(i)),
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(
+				uint64(i + 100),
+			),
+			AmtIn:  lnwire.MilliSatoshi(1000 + i),
+			AmtOut: 1000,
+		}
+	}
+	err = fwdLog.AddForwardingEvents(events[5:])
+	if err != nil {
+		t.Fatalf("unable to add events: %v", err)
+	}
+	err = fwdLog.AddForwardingEvents(events[:5])
+	if err != nil {
+		t.Fatalf("unable to add events: %v", err)
+	}
+
+	assertQuery := func(q ForwardingEventQuery, expected []ForwardingEvent,
+		lastOffset uint32) {
+
+		slice, err := fwdLog.Query(q)
+		if err != nil {
+			t.Fatalf("unable to query log: %v", err)
+		}
+		if len(slice.ForwardingEvents) == 0 && len(expected) == 0 {
+			return
+		}
+		if !reflect.DeepEqual(slice.ForwardingEvents, expected) {
+			t.Fatalf("events don't match: expected %v, got %v",
+				spew.Sdump(expected),
+				spew.Sdump(slice.ForwardingEvents))
+		}
+		if slice.LastIndexOffset != lastOffset {
+			t.Fatalf("expected last offset %v, got %v",
+				lastOffset, slice.LastIndexOffset)
+		}
+	}
+
+	// The entire log should be returned in order.
+	assertQuery(ForwardingEventQuery{
+		StartTime:    base,
+		EndTime:      events[9].Timestamp,
+		NumMaxEvents: 100,
+	}, events, 10)
+
+	// The time range should be inclusive of both ends.
+	assertQuery(ForwardingEventQuery{
+		StartTime:    events[2].Timestamp,
+		EndTime:      events[4].Timestamp,
+		NumMaxEvents: 100,
+	}, events[2:5], 3)
+
+	// The results should be paginated by the offset and maximum number
+	// of events.
+	assertQuery(ForwardingEventQuery{
+		StartTime:    base,
+		EndTime:      events[9].Timestamp,
+		IndexOffset:  4,
+		NumMaxEvents: 3,
+	}, events[4:7], 7)
+	assertQuery(ForwardingEventQuery{
+		StartTime:    base,
+		EndTime:      events[9].Timestamp,
+		IndexOffset:  10,
+		NumMaxEvents: 3,
+	}, nil, 10)
+
+	// An event sharing its timestamp with one already within the log
+	// should be stored alongside it.
+	dup := events[0]
+	dup.AmtIn = 5000
+	if err := fwdLog.AddForwardingEvents([]ForwardingEvent{dup}); err != nil {
+		t.Fatalf("unable to add event: %v", err)
+	}
+	dup.Timestamp = dup.Timestamp.Add(time.Nanosecond)
+	assertQuery(ForwardingEventQuery{
+		StartTime:    base,
+		EndTime:      base.Add(time.Second),
+		NumMaxEvents: 100,
+	}, []ForwardingEvent{events[0], dup}, 2)
+
+	if fee := dup.Fee(); fee != 4000 {
+		t.Fatalf("expected fee of 4000, got %v", fee)
+	}
+}
@@ -0,0 +1,739 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// fwdPackagesKey is the name of the top-level bucket housing the
+	// forwarding packages of each channel. It holds a sub-bucket for each
+	// channel, keyed by its short channel ID, which in turn holds a
+	// sub-bucket for each of the channel's forwarding packages, keyed by
+	// the remote commitment height at which the package was locked in.
+	fwdPackagesKey = []byte("fwd-packages")
+
+	// addBucketKey is the name of the sub-bucket of a forwarding package
+	// holding its Adds, keyed by their index within the package.
+	addBucketKey = []byte("add-updates")
+
+	// settleFailBucketKey is the name of the sub-bucket of a forwarding
+	// package holding its Settles and Fails, keyed by their index within
+	// the package.
+	settleFailBucketKey = []byte("settle-fail-updates")
+
+	// fwdFilterKey is the key under which the filter of the Adds that are
+	// to be forwarded is stored. It's only present once the package has
+	// been processed.
+	fwdFilterKey = []byte("fwd-filter-key")
+
+	// ackFilterKey is the key under which the filter of the Adds that
+	// have been acknowledged is stored.
+	ackFilterKey = []byte("ack-filter-key")
+
+	// settleFailFilterKey is the key under which the filter of the
+	// Settles and Fails that have been acknowledged is stored.
+	settleFailFilterKey = []byte("settle-fail-filter-key")
+
+	// ErrFwdPkgNotFound is returned when attempting to modify a
+	// forwarding package that doesn't exist.
+	ErrFwdPkgNotFound = errors.New("forwarding package not found")
+
+	// ErrCorruptedFwdPkg is returned when a forwarding package is missing
+	// one of its required fields.
+	ErrCorruptedFwdPkg = errors.New("forwarding package is corrupted")
+)
+
+// FwdState is the processing state of a forwarding package.
+type FwdState byte
+
+const (
+	// FwdStateLockedIn is the state of a forwarding package that has
+	// been written to disk along with the revocation which locked in its
+	// updates, but which hasn't yet been processed by the link. On
+	// restart, all of its updates must be processed afresh.
+	FwdStateLockedIn FwdState = iota
+
+	// FwdStateProcessed is the state of a forwarding package whose
+	// updates have been processed, which has yet to have all of its
+	// forwards acknowledged. On restart, only the forwards that haven't
+	// been acknowledged are replayed.
+	FwdStateProcessed
+
+	// FwdStateCompleted is the state of a forwarding package all of whose
+	// updates have been acknowledged. It no longer serves a purpose, and
+	// may be removed.
+	FwdStateCompleted
+)
+
+// String returns a human readable description of the FwdState.
+func (s FwdState) String() string {
+	switch s {
+	case FwdStateLockedIn:
+		return "LockedIn"
+	case FwdStateProcessed:
+		return "Processed"
+	case FwdStateCompleted:
+		return "Completed"
+	default:
+		return "Unknown"
+	}
+}
+
+// PkgFilter is a bitset tracking a boolean property, such as whether it's
+// been acknowledged, of each of the updates within a forwarding package.
+type PkgFilter struct {
+	count  uint16
+	filter []byte
+}
+
+// NewPkgFilter creates a new filter for the passed number of updates, with
+// none of its bits set.
+func NewPkgFilter(count uint16) *PkgFilter {
+	return &PkgFilter{
+		count:  count,
+		filter: make([]byte, (count+7)/8),
+	}
+}
+
+// Count returns the number of updates tracked by the filter.
+func (f *PkgFilter) Count() uint16 {
+	return f.count
+}
+
+// Set sets the bit of the update with the passed index. Indexes beyond the
+// number of tracked updates are ignored.
+func (f *PkgFilter) Set(i uint16) {
+	if i >= f.count {
+		return
+	}
+
+	f.filter[i/8] |= byte(1 << (7 - i%8))
+}
+
+// Contains returns true if the bit of the update with the passed index is
+// set.
+func (f *PkgFilter) Contains(i uint16) bool {
+	if i >= f.count {
+		return false
+	}
+
+	return f.filter[i/8]&byte(1<<(7-i%8)) != 0
+}
+
+// IsFull returns true if the bits of all tracked updates are set.
+func (f *PkgFilter) IsFull() bool {
+	for i := uint16(0); i < f.count; i++ {
+		if !f.Contains(i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Equal returns true if both filters track the same number of updates, and
+// have the same bits set.
+func (f *PkgFilter) Equal(f2 *PkgFilter) bool {
+	if f == f2 {
+		return true
+	}
+	if f == nil || f2 == nil {
+		return false
+	}
+
+	return f.count == f2.count && bytes.Equal(f.filter, f2.filter)
+}
+
+// Encode writes the filter to the passed writer.
+func (f *PkgFilter) Encode(w io.Writer) error {
+	if err := writeElement(w, f.count); err != nil {
+		return err
+	}
+
+	_, err := w.Write(f.filter)
+	return err
+}
+
+// Decode reads a filter from the passed reader.
+func (f *PkgFilter) Decode(r io.Reader) error {
+	if err := readElement(r, &f.count); err != nil {
+		return err
+	}
+
+	f.filter = make([]byte, (f.count+7)/8)
+	_, err := io.ReadFull(r, f.filter)
+	return err
+}
+
+// String returns a human readable representation of the filter's bits.
+func (f *PkgFilter) String() string {
+	var b bytes.Buffer
+	for i := uint16(0); i < f.count; i++ {
+		if f.Contains(i) {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+
+	return b.String()
+}
+
+// AddRef references an Add within the forwarding package of a channel.
+type AddRef struct {
+	// Height is the remote commitment height of the forwarding package.
+	Height uint64
+
+	// Index is the index of the Add within the forwarding package.
+	Index uint16
+}
+
+// SettleFailRef references a Settle or Fail within the forwarding package of
+// any channel.
+type SettleFailRef struct {
+	// Source is the short channel ID of the channel the Settle or Fail
+	// was received on.
+	Source lnwire.ShortChannelID
+
+	// Height is the remote commitment height of the forwarding package.
+	Height uint64
+
+	// Index is the index of the Settle or Fail within the forwarding
+	// package.
+	Index uint16
+}
+
+// FwdPkg records the updates of the remote party which were locked in by a
+// single revocation of theirs, that is, at a single remote commitment height.
+// It's written to disk atomically with the revocation, so the updates are
+// never lost, even if we go down before they've been forwarded. Each of the
+// Adds which are to be forwarded, and each of the Settles and Fails, is
+// acknowledged once it's been safely handed off to the switch, which allows
+// the unacknowledged updates, and only those, to be replayed on restart.
+type FwdPkg struct {
+	// Source is the short channel ID of the channel the updates were
+	// received on.
+	Source lnwire.ShortChannelID
+
+	// Height is the remote commitment height at which the updates were
+	// locked in.
+	Height uint64
+
+	// State is the processing state of the package.
+	State FwdState
+
+	// Adds are the HTLCs added by the remote party.
+	Adds []LogUpdate
+
+	// FwdFilter marks the Adds which are to be forwarded to the switch,
+	// rather than resolved within the link. It's nil until the package
+	// has been processed.
+	FwdFilter *PkgFilter
+
+	// AckFilter marks the Adds which have been acknowledged. Adds which
+	// aren't to be forwarded are acknowledged once the package has been
+	// processed.
+	AckFilter *PkgFilter
+
+	// SettleFails are the Settles and Fails of HTLCs we previously
+	// offered to the remote party.
+	SettleFails []LogUpdate
+
+	// SettleFailFilter marks the Settles and Fails which have been
+	// acknowledged.
+	SettleFailFilter *PkgFilter
+}
+
+// NewFwdPkg creates a new forwarding package for the passed updates, none of
+// which have been acknowledged.
+func NewFwdPkg(source lnwire.ShortChannelID, height uint64,
+	addUpdates, settleFailUpdates []LogUpdate) *FwdPkg {
+
+	return &FwdPkg{
+		Source:           source,
+		Height:           height,
+		State:            FwdStateLockedIn,
+		Adds:             addUpdates,
+		AckFilter:        NewPkgFilter(uint16(len(addUpdates))),
+		SettleFails:      settleFailUpdates,
+		SettleFailFilter: NewPkgFilter(uint16(len(settleFailUpdates))),
+	}
+}
+
+// String returns a human readable summary of the forwarding package.
+func (f *FwdPkg) String() string {
+	return fmt.Sprintf("%T(src=%v, height=%v, state=%v, nadds=%v, "+
+		"nsettlefails=%v)", f, f.Source, f.Height, f.State, len(f.Adds),
+		len(f.SettleFails))
+}
+
+// putFwdPkg writes the passed forwarding package within the passed
+// transaction, replacing any prior package at the same height.
+func putFwdPkg(tx *bolt.Tx, fwdPkg *FwdPkg) error {
+	if len(fwdPkg.Adds) > 0xffff || len(fwdPkg.SettleFails) > 0xffff {
+		return fmt.Errorf("forwarding package holds too many updates")
+	}
+
+	fwdPkgBkt, err := tx.CreateBucketIfNotExists(fwdPackagesKey)
+	if err != nil {
+		return err
+	}
+	sourceBkt, err := fwdPkgBkt.CreateBucketIfNotExists(
+		fwdSourceKey(fwdPkg.Source),
+	)
+	if err != nil {
+		return err
+	}
+
+	heightKey := fwdHeightKey(fwdPkg.Height)
+	if sourceBkt.Bucket(heightKey) != nil {
+		if err := sourceBkt.DeleteBucket(heightKey); err != nil {
+			return err
+		}
+	}
+	heightBkt, err := sourceBkt.CreateBucket(heightKey)
+	if err != nil {
+		return err
+	}
+
+	err = putLogUpdates(heightBkt, addBucketKey, fwdPkg.Adds)
+	if err != nil {
+		return err
+	}
+	err = putLogUpdates(heightBkt, settleFailBucketKey, fwdPkg.SettleFails)
+	if err != nil {
+		return err
+	}
+
+	if fwdPkg.FwdFilter != nil {
+		err := putPkgFilter(heightBkt, fwdFilterKey, fwdPkg.FwdFilter)
+		if err != nil {
+			return err
+		}
+	}
+	err = putPkgFilter(heightBkt, ackFilterKey, fwdPkg.AckFilter)
+	if err != nil {
+		return err
+	}
+
+	return putPkgFilter(
+		heightBkt, settleFailFilterKey, fwdPkg.SettleFailFilter,
+	)
+}
+
+// LoadFwdPkgs returns the forwarding packages of the channel, in order of
+// ascending height.
+func (c *OpenChannel) LoadFwdPkgs() ([]*FwdPkg, error) {
+	c.RLock()
+	source := c.ShortChanID
+	c.RUnlock()
+
+	var fwdPkgs []*FwdPkg
+	err := c.Db.View(func(tx *bolt.Tx) error {
+		sourceBkt := fetchFwdSourceBucket(tx, source)
+		if sourceBkt == nil {
+			return nil
+		}
+
+		return sourceBkt.ForEach(func(k, v []byte) error {
+			heightBkt := sourceBkt.Bucket(k)
+			if heightBkt == nil {
+				return ErrCorruptedFwdPkg
+			}
+
+			fwdPkg, err := loadFwdPkg(
+				heightBkt, source, byteOrder.Uint64(k),
+			)
+			if err != nil {
+				return err
+			}
+
+			fwdPkgs = append(fwdPkgs, fwdPkg)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fwdPkgs, nil
+}
+
+// SetFwdFilter records which of the Adds of the forwarding package at the
+// passed height are to be forwarded, thereby marking the package as
+// processed. All other Adds are acknowledged, as they've been resolved within
+// the link. If the package has already been processed, then this is a no-op,
+// so that the original decision survives the package being processed again.
+func (c *OpenChannel) SetFwdFilter(height uint64, fwdFilter *PkgFilter) error {
+	c.RLock()
+	source := c.ShortChanID
+	c.RUnlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		heightBkt, err := fetchFwdHeightBucket(tx, source, height)
+		if err != nil {
+			return err
+		}
+
+		if heightBkt.Get(fwdFilterKey) != nil {
+			return nil
+		}
+
+		ackFilter, err := getPkgFilter(heightBkt, ackFilterKey)
+		if err != nil {
+			return err
+		}
+		for i := uint16(0); i < ackFilter.Count(); i++ {
+			if !fwdFilter.Contains(i) {
+				ackFilter.Set(i)
+			}
+		}
+
+		err = putPkgFilter(heightBkt, fwdFilterKey, fwdFilter)
+		if err != nil {
+			return err
+		}
+
+		return putPkgFilter(heightBkt, ackFilterKey, ackFilter)
+	})
+}
+
+// AckAddHtlcs marks the referenced Adds within the channel's forwarding
+// packages as acknowledged. References to packages which no longer exist are
+// ignored.
+func (c *OpenChannel) AckAddHtlcs(addRefs ...AddRef) error {
+	if len(addRefs) == 0 {
+		return nil
+	}
+
+	c.RLock()
+	source := c.ShortChanID
+	c.RUnlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		for _, ref := range addRefs {
+			heightBkt, err := fetchFwdHeightBucket(
+				tx, source, ref.Height,
+			)
+			if err == ErrFwdPkgNotFound {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			err = ackPkgFilter(heightBkt, ackFilterKey, ref.Index)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// AckSettleFails marks the referenced Settles and Fails within the
+// forwarding packages of any channel as acknowledged. References to packages
+// which no longer exist are ignored.
+func (d *DB) AckSettleFails(settleFailRefs ...SettleFailRef) error {
+	if len(settleFailRefs) == 0 {
+		return nil
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		for _, ref := range settleFailRefs {
+			heightBkt, err := fetchFwdHeightBucket(
+				tx, ref.Source, ref.Height,
+			)
+			if err == ErrFwdPkgNotFound {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			err = ackPkgFilter(
+				heightBkt, settleFailFilterKey, ref.Index,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// AddAcked returns whether the Add with the passed HTLC ID, received over the
+// channel with the passed short channel ID, has been acknowledged within its
+// forwarding package. Adds of packages which no longer exist have been
+// acknowledged, as packages are only removed once they're complete.
+func (d *DB) AddAcked(source lnwire.ShortChannelID, htlcID uint64) (bool,
+	error) {
+
+	acked := true
+	err := d.View(func(tx *bolt.Tx) error {
+		sourceBkt := fetchFwdSourceBucket(tx, source)
+		if sourceBkt == nil {
+			return nil
+		}
+
+		return sourceBkt.ForEach(func(k, v []byte) error {
+			heightBkt := sourceBkt.Bucket(k)
+			if heightBkt == nil {
+				return ErrCorruptedFwdPkg
+			}
+
+			adds, err := getLogUpdates(heightBkt, addBucketKey)
+			if err != nil {
+				return err
+			}
+			for i, add := range adds {
+				msg, ok := add.UpdateMsg.(*lnwire.UpdateAddHTLC)
+				if !ok || msg.ID != htlcID {
+					continue
+				}
+
+				ackFilter, err := getPkgFilter(
+					heightBkt, ackFilterKey,
+				)
+				if err != nil {
+					return err
+				}
+				acked = ackFilter.Contains(uint16(i))
+
+				return nil
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return acked, nil
+}
+
+// RemoveFwdPkg removes the channel's forwarding package at the passed height.
+// This should only be done once the package has been completed.
+func (c *OpenChannel) RemoveFwdPkg(height uint64) error {
+	c.RLock()
+	source := c.ShortChanID
+	c.RUnlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		sourceBkt := fetchFwdSourceBucket(tx, source)
+		if sourceBkt == nil {
+			return nil
+		}
+
+		heightKey := fwdHeightKey(height)
+		if sourceBkt.Bucket(heightKey) == nil {
+			return nil
+		}
+
+		return sourceBkt.DeleteBucket(heightKey)
+	})
+}
+
+// deleteFwdPkgs removes all forwarding packages of the channel with the
+// passed short channel ID within the passed transaction.
+func deleteFwdPkgs(tx *bolt.Tx, source lnwire.ShortChannelID) error {
+	fwdPkgBkt := tx.Bucket(fwdPackagesKey)
+	if fwdPkgBkt == nil {
+		return nil
+	}
+
+	sourceKey := fwdSourceKey(source)
+	if fwdPkgBkt.Bucket(sourceKey) == nil {
+		return nil
+	}
+
+	return fwdPkgBkt.DeleteBucket(sourceKey)
+}
+
+// loadFwdPkg reads the forwarding package stored within the passed bucket,
+// deriving its state from its filters.
+func loadFwdPkg(heightBkt *bolt.Bucket, source lnwire.ShortChannelID,
+	height uint64) (*FwdPkg, error) {
+
+	adds, err := getLogUpdates(heightBkt, addBucketKey)
+	if err != nil {
+		return nil, err
+	}
+	settleFails, err := getLogUpdates(heightBkt, settleFailBucketKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ackFilter, err := getPkgFilter(heightBkt, ackFilterKey)
+	if err != nil {
+		return nil, err
+	}
+	settleFailFilter, err := getPkgFilter(heightBkt, settleFailFilterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var fwdFilter *PkgFilter
+	if heightBkt.Get(fwdFilterKey) != nil {
+		fwdFilter, err = getPkgFilter(heightBkt, fwdFilterKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	state := FwdStateLockedIn
+	switch {
+	case fwdFilter == nil:
+	case ackFilter.IsFull() && settleFailFilter.IsFull():
+		state = FwdStateCompleted
+	default:
+		state = FwdStateProcessed
+	}
+
+	return &FwdPkg{
+		Source:           source,
+		Height:           height,
+		State:            state,
+		Adds:             adds,
+		FwdFilter:        fwdFilter,
+		AckFilter:        ackFilter,
+		SettleFails:      settleFails,
+		SettleFailFilter: settleFailFilter,
+	}, nil
+}
+
+// fetchFwdSourceBucket returns the bucket holding the forwarding packages of
+// the channel with the passed short channel ID, or nil if it has none.
+func fetchFwdSourceBucket(tx *bolt.Tx,
+	source lnwire.ShortChannelID) *bolt.Bucket {
+
+	fwdPkgBkt := tx.Bucket(fwdPackagesKey)
+	if fwdPkgBkt == nil {
+		return nil
+	}
+
+	return fwdPkgBkt.Bucket(fwdSourceKey(source))
+}
+
+// fetchFwdHeightBucket returns the bucket of the forwarding package of the
+// channel with the passed short channel ID at the passed height.
+func fetchFwdHeightBucket(tx *bolt.Tx, source lnwire.ShortChannelID,
+	height uint64) (*bolt.Bucket, error) {
+
+	sourceBkt := fetchFwdSourceBucket(tx, source)
+	if sourceBkt == nil {
+		return nil, ErrFwdPkgNotFound
+	}
+
+	heightBkt := sourceBkt.Bucket(fwdHeightKey(height))
+	if heightBkt == nil {
+		return nil, ErrFwdPkgNotFound
+	}
+
+	return heightBkt, nil
+}
+
+// ackPkgFilter sets the bit of the update with the passed index within the
+// filter stored under the passed key.
+func ackPkgFilter(heightBkt *bolt.Bucket, key []byte, index uint16) error {
+	filter, err := getPkgFilter(heightBkt, key)
+	if err != nil {
+		return err
+	}
+	if filter.Contains(index) {
+		return nil
+	}
+
+	filter.Set(index)
+	return putPkgFilter(heightBkt, key, filter)
+}
+
+func putPkgFilter(bkt *bolt.Bucket, key []byte, filter *PkgFilter) error {
+	var b bytes.Buffer
+	if err := filter.Encode(&b); err != nil {
+		return err
+	}
+
+	return bkt.Put(key, b.Bytes())
+}
+
+func getPkgFilter(bkt *bolt.Bucket, key []byte) (*PkgFilter, error) {
+	filterBytes := bkt.Get(key)
+	if filterBytes == nil {
+		return nil, ErrCorruptedFwdPkg
+	}
+
+	filter := &PkgFilter{}
+	if err := filter.Decode(bytes.NewReader(filterBytes)); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func putLogUpdates(bkt *bolt.Bucket, key []byte, updates []LogUpdate) error {
+	updateBkt, err := bkt.CreateBucket(key)
+	if err != nil {
+		return err
+	}
+
+	for i, update := range updates {
+		var b bytes.Buffer
+		err := writeElements(&b, update.LogIndex, update.UpdateMsg)
+		if err != nil {
+			return err
+		}
+
+		var k [2]byte
+		byteOrder.PutUint16(k[:], uint16(i))
+		if err := updateBkt.Put(k[:], b.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getLogUpdates(bkt *bolt.Bucket, key []byte) ([]LogUpdate, error) {
+	updateBkt := bkt.Bucket(key)
+	if updateBkt == nil {
+		return nil, ErrCorruptedFwdPkg
+	}
+
+	var updates []LogUpdate
+	err := updateBkt.ForEach(func(k, v []byte) error {
+		var update LogUpdate
+		err := readElements(bytes.NewReader(v), &update.LogIndex,
+			&update.UpdateMsg)
+		if err != nil {
+			return err
+		}
+
+		updates = append(updates, update)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+func fwdSourceKey(source lnwire.ShortChannelID) []byte {
+	var k [8]byte
+	byteOrder.PutUint64(k[:], source.ToUint64())
+	return k[:]
+}
+
+func fwdHeightKey(height uint64) []byte {
+	var k [8]byte
+	byteOrder.PutUint64(k[:], height)
+	return k[:]
+}
@@ -0,0 +1,238 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestPkgFilterSetContains tests that a PkgFilter only reports the indexes
+// which have been set, is full once all of them have been, and survives a
+// round trip through its encoding.
+func TestPkgFilterSetContains(t *testing.T) {
+	t.Parallel()
+
+	for _, count := range []uint16{0, 1, 7, 8, 9, 17} {
+		filter := NewPkgFilter(count)
+		if count == 0 && !filter.IsFull() {
+			t.Fatalf("expected empty filter to be full")
+		}
+
+		for i := uint16(0); i < count; i++ {
+			if filter.IsFull() {
+				t.Fatalf("filter of size %v full with only %v "+
+					"set", count, i)
+			}
+			if filter.Contains(i) {
+				t.Fatalf("filter of size %v contains unset "+
+					"index %v", count, i)
+			}
+
+			filter.Set(i)
+			if !filter.Contains(i) {
+				t.Fatalf("filter of size %v doesn't contain "+
+					"set index %v", count, i)
+			}
+		}
+		if !filter.IsFull() {
+			t.Fatalf("filter of size %v should be full", count)
+		}
+
+		var b bytes.Buffer
+		if err := filter.Encode(&b); err != nil {
+			t.Fatalf("unable to encode filter: %v", err)
+		}
+		decoded := &PkgFilter{}
+		if err := decoded.Decode(&b); err != nil {
+			t.Fatalf("unable to decode filter: %v", err)
+		}
+		if !filter.Equal(decoded) {
+			t.Fatalf("filters don't match: expected %v, got %v",
+				filter, decoded)
+		}
+	}
+}
+
+// TestFwdPkgLifecycle tests that a forwarding package advances through its
+// states as its forwarding filter is set and its updates are acknowledged,
+// that setting the forwarding filter acknowledges the Adds which weren't
+// forwarded, and that a package can be removed once it's complete.
+func TestFwdPkgLifecycle(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	channel, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := channel.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	adds := []LogUpdate{
+		{
+			LogIndex: 0,
+			UpdateMsg: &lnwire.UpdateAddHTLC{
+				ID:     0,
+				Amount: lnwire.NewMSatFromSatoshis(100),
+				Expiry: 25,
+			},
+		},
+		{
+			LogIndex: 1,
+			UpdateMsg: &lnwire.UpdateAddHTLC{
+				ID:     1,
+				Amount: lnwire.NewMSatFromSatoshis(200),
+				Expiry: 50,
+			},
+		},
+	}
+	settleFails := []LogUpdate{
+		{
+			LogIndex: 2,
+			UpdateMsg: &lnwire.UpdateFufillHTLC{
+				ID: 3,
+			},
+		},
+	}
+
+	fwdPkg := NewFwdPkg(channel.ShortChanID, 1, adds, settleFails)
+	err = cdb.Update(func(tx *bolt.Tx) error {
+		return putFwdPkg(tx, fwdPkg)
+	})
+	if err != nil {
+		t.Fatalf("unable to write forwarding package: %v", err)
+	}
+
+	assertPkg := func(state FwdState, fwdFilter, ackFilter,
+		settleFailFilter *PkgFilter) {
+
+		fwdPkgs, err := channel.LoadFwdPkgs()
+		if err != nil {
+			t.Fatalf("unable to load forwarding packages: %v", err)
+		}
+		if len(fwdPkgs) != 1 {
+			t.Fatalf("expected 1 forwarding package, got %v",
+				len(fwdPkgs))
+		}
+
+		pkg := fwdPkgs[0]
+		if pkg.Source != channel.ShortChanID || pkg.Height != 1 {
+			t.Fatalf("unexpected forwarding package: %v", pkg)
+		}
+		if len(pkg.Adds) != len(adds) {
+			t.Fatalf("expected %v adds, got %v", len(adds),
+				len(pkg.Adds))
+		}
+		if len(pkg.SettleFails) != len(settleFails) {
+			t.Fatalf("expected %v settle/fails, got %v",
+				len(settleFails), len(pkg.SettleFails))
+		}
+		if pkg.State != state {
+			t.Fatalf("expected state %v, got %v", state, pkg.State)
+		}
+		if fwdFilter == nil && pkg.FwdFilter != nil {
+			t.Fatalf("expected no fwd filter, got %v",
+				pkg.FwdFilter)
+		} else if fwdFilter != nil && !fwdFilter.Equal(pkg.FwdFilter) {
+			t.Fatalf("expected fwd filter %v, got %v", fwdFilter,
+				pkg.FwdFilter)
+		}
+		if !ackFilter.Equal(pkg.AckFilter) {
+			t.Fatalf("expected ack filter %v, got %v", ackFilter,
+				pkg.AckFilter)
+		}
+		if !settleFailFilter.Equal(pkg.SettleFailFilter) {
+			t.Fatalf("expected settle/fail filter %v, got %v",
+				settleFailFilter, pkg.SettleFailFilter)
+		}
+	}
+
+	// Initially, the package should be locked in, with none of its
+	// updates acknowledged.
+	ackFilter := NewPkgFilter(2)
+	settleFailFilter := NewPkgFilter(1)
+	assertPkg(FwdStateLockedIn, nil, ackFilter, settleFailFilter)
+
+	// Setting the forwarding filter to forward only the second Add should
+	// acknowledge the first, and process the package.
+	fwdFilter := NewPkgFilter(2)
+	fwdFilter.Set(1)
+	if err := channel.SetFwdFilter(1, fwdFilter); err != nil {
+		t.Fatalf("unable to set fwd filter: %v", err)
+	}
+	ackFilter.Set(0)
+	assertPkg(FwdStateProcessed, fwdFilter, ackFilter, settleFailFilter)
+
+	// Setting the filter a second time should have no effect.
+	if err := channel.SetFwdFilter(1, NewPkgFilter(2)); err != nil {
+		t.Fatalf("unable to set fwd filter: %v", err)
+	}
+	assertPkg(FwdStateProcessed, fwdFilter, ackFilter, settleFailFilter)
+
+	// The Add which wasn't forwarded has been acknowledged, unlike the
+	// one which was.
+	assertAcked := func(htlcID uint64, expected bool) {
+		acked, err := cdb.AddAcked(channel.ShortChanID, htlcID)
+		if err != nil {
+			t.Fatalf("unable to check ack of add %v: %v", htlcID,
+				err)
+		}
+		if acked != expected {
+			t.Fatalf("expected add %v acked: %v, got %v", htlcID,
+				expected, acked)
+		}
+	}
+	assertAcked(0, true)
+	assertAcked(1, false)
+
+	// Acknowledging the forwarded Add alone shouldn't complete the
+	// package, as the Settle remains.
+	if err := channel.AckAddHtlcs(AddRef{Height: 1, Index: 1}); err != nil {
+		t.Fatalf("unable to ack add: %v", err)
+	}
+	ackFilter.Set(1)
+	assertPkg(FwdStateProcessed, fwdFilter, ackFilter, settleFailFilter)
+	assertAcked(1, true)
+
+	// Once the Settle is acknowledged as well, the package is complete.
+	err = cdb.AckSettleFails(SettleFailRef{
+		Source: channel.ShortChanID,
+		Height: 1,
+		Index:  0,
+	})
+	if err != nil {
+		t.Fatalf("unable to ack settle: %v", err)
+	}
+	settleFailFilter.Set(0)
+	assertPkg(FwdStateCompleted, fwdFilter, ackFilter, settleFailFilter)
+
+	// Finally, removing the package should leave the channel without any.
+	if err := channel.RemoveFwdPkg(1); err != nil {
+		t.Fatalf("unable to remove forwarding package: %v", err)
+	}
+	fwdPkgs, err := channel.LoadFwdPkgs()
+	if err != nil {
+		t.Fatalf("unable to load forwarding packages: %v", err)
+	}
+	if len(fwdPkgs) != 0 {
+		t.Fatalf("expected no forwarding packages, got %v",
+			len(fwdPkgs))
+	}
+
+	// The Adds of the removed package have been acknowledged.
+	assertAcked(0, true)
+	assertAcked(1, true)
+
+	// Acknowledging updates of the removed package should be a no-op.
+	if err := channel.AckAddHtlcs(AddRef{Height: 1, Index: 1}); err != nil {
+		t.Fatalf("unable to ack add of removed package: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// htlcLossBucket is the name of the bucket which houses a record of each
+// incoming HTLC that we lost on-chain, despite knowing its preimage. Each
+// record is keyed by the outpoint of the lost HTLC output.
+var htlcLossBucket = []byte("htlc-loss")
+
+// HTLCLoss records an incoming HTLC that we had settled off-chain, yet which
+// was timed out on-chain by the remote party before we were able to claim it
+// using its preimage. As the HTLC was settled with the upstream party, its
+// value is lost.
+type HTLCLoss struct {
+	// ChanPoint is the outpoint of the channel the HTLC belonged to.
+	ChanPoint wire.OutPoint
+
+	// HtlcOutpoint is the outpoint of the HTLC output on the commitment
+	// transaction.
+	HtlcOutpoint wire.OutPoint
+
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// Amount is the value of the HTLC output.
+	Amount btcutil.Amount
+
+	// SpendTxid is the txid of the remote party's transaction which spent
+	// the HTLC output.
+	SpendTxid chainhash.Hash
+
+	// SpendHeight is the height at which the HTLC output was spent.
+	SpendHeight uint32
+
+	// DetectedAt is the time at which the loss was detected.
+	DetectedAt time.Time
+}
+
+// RecordHTLCLoss records the passed loss of an HTLC, replacing any prior
+// record of the loss of the same HTLC output.
+func (d *DB) RecordHTLCLoss(loss *HTLCLoss) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(htlcLossBucket)
+		if err != nil {
+			return err
+		}
+
+		var key bytes.Buffer
+		if err := writeOutpoint(&key, &loss.HtlcOutpoint); err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := serializeHTLCLoss(&b, loss); err != nil {
+			return err
+		}
+
+		return bucket.Put(key.Bytes(), b.Bytes())
+	})
+}
+
+// FetchHTLCLosses returns all recorded HTLC losses.
+func (d *DB) FetchHTLCLosses() ([]*HTLCLoss, error) {
+	var losses []*HTLCLoss
+	err := d.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(htlcLossBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			loss, err := deserializeHTLCLoss(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			losses = append(losses, loss)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return losses, nil
+}
+
+func serializeHTLCLoss(w io.Writer, loss *HTLCLoss) error {
+	return writeElements(w,
+		loss.ChanPoint, loss.HtlcOutpoint, loss.PaymentHash,
+		loss.Amount, loss.SpendTxid, loss.SpendHeight,
+		uint64(loss.DetectedAt.Unix()),
+	)
+}
+
+func deserializeHTLCLoss(r io.Reader) (*HTLCLoss, error) {
+	loss := &HTLCLoss{}
+
+	var detectedAt uint64
+	err := readElements(r,
+		&loss.ChanPoint, &loss.HtlcOutpoint, &loss.PaymentHash,
+		&loss.Amount, &loss.SpendTxid, &loss.SpendHeight, &detectedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	loss.DetectedAt = time.Unix(int64(detectedAt), 0)
+
+	return loss, nil
+}
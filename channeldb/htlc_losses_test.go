@@ -0,0 +1,59 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestHTLCLosses tests that recorded HTLC losses can be fetched, and that
+// recording the loss of the same HTLC output twice replaces the prior record.
+func TestHTLCLosses(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	losses, err := cdb.FetchHTLCLosses()
+	if err != nil {
+		t.Fatalf("unable to fetch losses: %v", err)
+	}
+	if len(losses) != 0 {
+		t.Fatalf("expected no losses, instead got %v", len(losses))
+	}
+
+	loss := &HTLCLoss{
+		ChanPoint:    wire.OutPoint{Hash: chainhash.Hash{1}, Index: 1},
+		HtlcOutpoint: wire.OutPoint{Hash: chainhash.Hash{2}, Index: 3},
+		PaymentHash:  [32]byte{4},
+		Amount:       50000,
+		SpendTxid:    chainhash.Hash{5},
+		SpendHeight:  600,
+		DetectedAt:   time.Unix(time.Now().Unix(), 0),
+	}
+	if err := cdb.RecordHTLCLoss(loss); err != nil {
+		t.Fatalf("unable to record loss: %v", err)
+	}
+	if err := cdb.RecordHTLCLoss(loss); err != nil {
+		t.Fatalf("unable to record loss: %v", err)
+	}
+
+	losses, err = cdb.FetchHTLCLosses()
+	if err != nil {
+		t.Fatalf("unable to fetch losses: %v", err)
+	}
+	if len(losses) != 1 {
+		t.Fatalf("expected one loss, instead got %v", len(losses))
+	}
+	if !reflect.DeepEqual(losses[0], loss) {
+		t.Fatalf("losses don't match: expected %v, got %v",
+			spew.Sdump(loss), spew.Sdump(losses[0]))
+	}
+}
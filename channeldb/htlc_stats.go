@@ -0,0 +1,180 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// htlcStatsBucket is the name of the bucket which houses the lifetime HTLC
+// statistics of our channels. It holds a sub-bucket for each peer, keyed by
+// the compressed public key of the peer, within which the statistics of each
+// channel with the peer are keyed by its channel point. The statistics are
+// retained once a channel is closed, so they can inform our decisions when
+// channels with the same peer are opened in the future.
+var htlcStatsBucket = []byte("htlc-stats")
+
+// ChannelHTLCStats aggregates the HTLCs we've offered over a channel, and how
+// the remote party has resolved them, over the lifetime of the channel.
+type ChannelHTLCStats struct {
+	// NumForwarded is the number of HTLCs we've offered over the channel.
+	NumForwarded uint64
+
+	// NumSettled is the number of HTLCs offered over the channel that the
+	// remote party has settled.
+	NumSettled uint64
+
+	// NumFailed is the number of HTLCs offered over the channel that the
+	// remote party has failed.
+	NumFailed uint64
+
+	// TotalSettleLatency is the sum of the time it took the remote party
+	// to settle each HTLC accounted for within NumSettled.
+	TotalSettleLatency time.Duration
+
+	// Uptime is the total amount of time the channel has been active,
+	// with its link to the remote party up.
+	Uptime time.Duration
+}
+
+// Add adds the passed statistics to those of the receiver.
+func (s *ChannelHTLCStats) Add(o *ChannelHTLCStats) {
+	s.NumForwarded += o.NumForwarded
+	s.NumSettled += o.NumSettled
+	s.NumFailed += o.NumFailed
+	s.TotalSettleLatency += o.TotalSettleLatency
+	s.Uptime += o.Uptime
+}
+
+// FailureRatio returns the fraction of resolved HTLCs which were failed by
+// the remote party. Zero is returned if no HTLCs have been resolved yet.
+func (s *ChannelHTLCStats) FailureRatio() float64 {
+	resolved := s.NumSettled + s.NumFailed
+	if resolved == 0 {
+		return 0
+	}
+
+	return float64(s.NumFailed) / float64(resolved)
+}
+
+// AvgSettleLatency returns the average time it took the remote party to
+// settle an HTLC. Zero is returned if no HTLCs have been settled yet.
+func (s *ChannelHTLCStats) AvgSettleLatency() time.Duration {
+	if s.NumSettled == 0 {
+		return 0
+	}
+
+	return s.TotalSettleLatency / time.Duration(s.NumSettled)
+}
+
+// AddChannelHTLCStats adds the passed statistics to the lifetime statistics
+// of the channel with the passed channel point, which is held with the target
+// peer.
+func (d *DB) AddChannelHTLCStats(peer [33]byte, chanPoint *wire.OutPoint,
+	stats *ChannelHTLCStats) error {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		statsBucket, err := tx.CreateBucketIfNotExists(htlcStatsBucket)
+		if err != nil {
+			return err
+		}
+		peerBucket, err := statsBucket.CreateBucketIfNotExists(peer[:])
+		if err != nil {
+			return err
+		}
+
+		total := &ChannelHTLCStats{}
+		if v := peerBucket.Get(chanKey.Bytes()); v != nil {
+			total, err = deserializeChannelHTLCStats(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		total.Add(stats)
+
+		var b bytes.Buffer
+		if err := serializeChannelHTLCStats(&b, total); err != nil {
+			return err
+		}
+
+		return peerBucket.Put(chanKey.Bytes(), b.Bytes())
+	})
+}
+
+// FetchPeerHTLCStats returns the lifetime statistics of all channels we've
+// held with the target peer, including those which have since been closed,
+// keyed by their channel point.
+func (d *DB) FetchPeerHTLCStats(
+	peer [33]byte) (map[wire.OutPoint]*ChannelHTLCStats, error) {
+
+	stats := make(map[wire.OutPoint]*ChannelHTLCStats)
+	err := d.View(func(tx *bolt.Tx) error {
+		statsBucket := tx.Bucket(htlcStatsBucket)
+		if statsBucket == nil {
+			return nil
+		}
+		peerBucket := statsBucket.Bucket(peer[:])
+		if peerBucket == nil {
+			return nil
+		}
+
+		return peerBucket.ForEach(func(k, v []byte) error {
+			var chanPoint wire.OutPoint
+			err := readOutpoint(bytes.NewReader(k), &chanPoint)
+			if err != nil {
+				return err
+			}
+
+			chanStats, err := deserializeChannelHTLCStats(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+
+			stats[chanPoint] = chanStats
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func serializeChannelHTLCStats(w io.Writer, s *ChannelHTLCStats) error {
+	return writeElements(w,
+		s.NumForwarded, s.NumSettled, s.NumFailed,
+		uint64(s.TotalSettleLatency), uint64(s.Uptime),
+	)
+}
+
+func deserializeChannelHTLCStats(r io.Reader) (*ChannelHTLCStats, error) {
+	var (
+		s                     ChannelHTLCStats
+		settleLatency, uptime uint64
+	)
+	err := readElements(r,
+		&s.NumForwarded, &s.NumSettled, &s.NumFailed, &settleLatency,
+		&uptime,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.TotalSettleLatency = time.Duration(settleLatency)
+	s.Uptime = time.Duration(uptime)
+
+	return &s, nil
+}
@@ -0,0 +1,92 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestChannelHTLCStats tests that the HTLC statistics of a channel accumulate
+// across additions, and are returned alongside those of any other channel
+// held with the same peer.
+func TestChannelHTLCStats(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	var peerA, peerB [33]byte
+	peerA[0] = 2
+	peerB[0] = 3
+
+	chanA := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}
+	chanB := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}
+
+	stats, err := cdb.FetchPeerHTLCStats(peerA)
+	if err != nil {
+		t.Fatalf("unable to fetch stats: %v", err)
+	}
+	if len(stats) != 0 {
+		t.Fatalf("expected no stats, instead got %v", len(stats))
+	}
+
+	delta := &ChannelHTLCStats{
+		NumForwarded:       4,
+		NumSettled:         3,
+		NumFailed:          1,
+		TotalSettleLatency: 3 * time.Second,
+		Uptime:             time.Hour,
+	}
+	if err := cdb.AddChannelHTLCStats(peerA, &chanA, delta); err != nil {
+		t.Fatalf("unable to add stats: %v", err)
+	}
+	if err := cdb.AddChannelHTLCStats(peerA, &chanA, delta); err != nil {
+		t.Fatalf("unable to add stats: %v", err)
+	}
+	if err := cdb.AddChannelHTLCStats(peerA, &chanB, delta); err != nil {
+		t.Fatalf("unable to add stats: %v", err)
+	}
+
+	stats, err = cdb.FetchPeerHTLCStats(peerA)
+	if err != nil {
+		t.Fatalf("unable to fetch stats: %v", err)
+	}
+	expected := map[wire.OutPoint]*ChannelHTLCStats{
+		chanA: {
+			NumForwarded:       8,
+			NumSettled:         6,
+			NumFailed:          2,
+			TotalSettleLatency: 6 * time.Second,
+			Uptime:             2 * time.Hour,
+		},
+		chanB: delta,
+	}
+	if !reflect.DeepEqual(stats, expected) {
+		t.Fatalf("unexpected stats: expected %v, got %v",
+			spew.Sdump(expected), spew.Sdump(stats))
+	}
+
+	if ratio := stats[chanA].FailureRatio(); ratio != 0.25 {
+		t.Fatalf("expected failure ratio of 0.25, got %v", ratio)
+	}
+	if latency := stats[chanA].AvgSettleLatency(); latency != time.Second {
+		t.Fatalf("expected average settle latency of 1s, got %v",
+			latency)
+	}
+
+	// The stats of one peer shouldn't be returned for another.
+	stats, err = cdb.FetchPeerHTLCStats(peerB)
+	if err != nil {
+		t.Fatalf("unable to fetch stats: %v", err)
+	}
+	if len(stats) != 0 {
+		t.Fatalf("expected no stats, instead got %v", len(stats))
+	}
+}
@@ -12,10 +12,13 @@ import (
 )
 
 func randInvoice(value lnwire.MilliSatoshi) (*Invoice, error) {
-	var pre [32]byte
+	var pre, addr [32]byte
 	if _, err := rand.Read(pre[:]); err != nil {
 		return nil, err
 	}
+	if _, err := rand.Read(addr[:]); err != nil {
+		return nil, err
+	}
 
 	i := &Invoice{
 		// Use single second precision to avoid false positive test
@@ -24,6 +27,7 @@ func randInvoice(value lnwire.MilliSatoshi) (*Invoice, error) {
 		Terms: ContractTerm{
 			PaymentPreimage: pre,
 			Value:           value,
+			PaymentAddr:     addr,
 		},
 	}
 	i.Memo = []byte("memo")
@@ -87,23 +91,91 @@ func TestInvoiceWorkflow(t *testing.T) {
 			spew.Sdump(fakeInvoice), spew.Sdump(dbInvoice))
 	}
 
-	// Settle the invoice, the version retrieved from the database should
-	// now have the settled bit toggle to true and a non-default
-	// SettledDate
-	if err := db.SettleInvoice(paymentHash); err != nil {
+	// Accepting an HTLC paying to the invoice should transition it to the
+	// accepted state, and add the HTLC to its set. Accepting the same HTLC
+	// again shouldn't add it twice.
+	htlc1 := InvoiceHTLC{
+		ChanID:    lnwire.NewShortChanIDFromInt(1),
+		HtlcIndex: 3,
+		Amt:       fakeInvoice.Terms.Value,
+		Expiry:    500,
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := db.AcceptInvoice(paymentHash, htlc1); err != nil {
+			t.Fatalf("unable to accept htlc: %v", err)
+		}
+	}
+	dbInvoice, err = db.LookupInvoice(paymentHash)
+	if err != nil {
+		t.Fatalf("unable to fetch invoice: %v", err)
+	}
+	if dbInvoice.Terms.State != ContractAccepted {
+		t.Fatalf("expected state %v, got %v", ContractAccepted,
+			dbInvoice.Terms.State)
+	}
+	if len(dbInvoice.Htlcs) != 1 ||
+		dbInvoice.Htlcs[0].State != HTLCAccepted ||
+		dbInvoice.Htlcs[0].Amt != htlc1.Amt {
+
+		t.Fatalf("unexpected htlc set: %v", spew.Sdump(dbInvoice.Htlcs))
+	}
+
+	// Settle the invoice with a second HTLC, the version retrieved from
+	// the database should now be in the settled state, with a non-default
+	// SettledDate, both HTLCs settled, and the amount paid, which exceeds
+	// the value of the invoice.
+	htlc2 := InvoiceHTLC{
+		ChanID:    lnwire.NewShortChanIDFromInt(2),
+		HtlcIndex: 7,
+		Amt:       1000,
+		Expiry:    500,
+	}
+	if _, err := db.SettleInvoice(paymentHash, htlc2); err != nil {
 		t.Fatalf("unable to settle invoice: %v", err)
 	}
 	dbInvoice2, err := db.LookupInvoice(paymentHash)
 	if err != nil {
 		t.Fatalf("unable to fetch invoice: %v", err)
 	}
-	if !dbInvoice2.Terms.Settled {
+	if dbInvoice2.Terms.State != ContractSettled {
 		t.Fatalf("invoice should now be settled but isn't")
 	}
 
 	if dbInvoice2.SettleDate.IsZero() {
 		t.Fatalf("invoice should have non-zero SettledDate but isn't")
 	}
+	amtPaid := htlc1.Amt + htlc2.Amt
+	if dbInvoice2.AmtPaid != amtPaid {
+		t.Fatalf("expected amount paid of %v, got %v", amtPaid,
+			dbInvoice2.AmtPaid)
+	}
+	if len(dbInvoice2.Htlcs) != 2 {
+		t.Fatalf("expected 2 htlcs, got %v", len(dbInvoice2.Htlcs))
+	}
+	for _, htlc := range dbInvoice2.Htlcs {
+		if htlc.State != HTLCSettled {
+			t.Fatalf("expected htlc to be settled, got %v",
+				htlc.State)
+		}
+	}
+
+	// Once settled, the invoice can no longer accept HTLCs, while
+	// settling it again with the same HTLC is a noop.
+	_, err = db.AcceptInvoice(paymentHash, InvoiceHTLC{HtlcIndex: 9})
+	if err != ErrInvoiceAlreadySettled {
+		t.Fatalf("expected ErrInvoiceAlreadySettled, got %v", err)
+	}
+	if _, err := db.SettleInvoice(paymentHash, htlc2); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	dbInvoice3, err := db.LookupInvoice(paymentHash)
+	if err != nil {
+		t.Fatalf("unable to fetch invoice: %v", err)
+	}
+	if !reflect.DeepEqual(dbInvoice2, dbInvoice3) {
+		t.Fatalf("invoice modified by repeated settle: %v vs %v",
+			spew.Sdump(dbInvoice2), spew.Sdump(dbInvoice3))
+	}
 
 	// Attempt to insert generated above again, this should fail as
 	// duplicates are rejected by the processing logic.
@@ -155,3 +227,195 @@ func TestInvoiceWorkflow(t *testing.T) {
 		}
 	}
 }
+
+// TestAddInvoicesBatch tests that a batch of invoices is added within a single
+// transaction in order, and that a batch containing a duplicate invoice is
+// rejected in its entirety.
+func TestAddInvoicesBatch(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	amt := lnwire.NewMSatFromSatoshis(1000)
+	newBatch := func(n int) []*Invoice {
+		batch := make([]*Invoice, n)
+		for i := range batch {
+			invoice, err := randInvoice(amt)
+			if err != nil {
+				t.Fatalf("unable to create invoice: %v", err)
+			}
+			batch[i] = invoice
+		}
+		return batch
+	}
+
+	batch := newBatch(5)
+	if err := db.AddInvoices(batch); err != nil {
+		t.Fatalf("unable to add invoices: %v", err)
+	}
+
+	// A batch containing an invoice which duplicates one within the
+	// database, or one within the batch itself, should be rejected, and
+	// none of its invoices should be added.
+	dupDB := append(newBatch(2), batch[3])
+	if err := db.AddInvoices(dupDB); err != ErrDuplicateInvoice {
+		t.Fatalf("expected ErrDuplicateInvoice, got %v", err)
+	}
+	dupBatch := newBatch(2)
+	dupBatch = append(dupBatch, dupBatch[0])
+	if err := db.AddInvoices(dupBatch); err != ErrDuplicateInvoice {
+		t.Fatalf("expected ErrDuplicateInvoice, got %v", err)
+	}
+	rejected := []*Invoice{dupDB[0], dupDB[1], dupBatch[0], dupBatch[1]}
+	for _, invoice := range rejected {
+		_, err := db.LookupInvoice(invoice.Terms.PaymentHash)
+		if err != ErrInvoiceNotFound {
+			t.Fatalf("invoice of rejected batch was added: %v",
+				err)
+		}
+	}
+
+	// Adding a further batch should continue where the first left off,
+	// such that all invoices are retrieved in the order they were added.
+	batch = append(batch, newBatch(3)...)
+	if err := db.AddInvoices(batch[5:]); err != nil {
+		t.Fatalf("unable to add invoices: %v", err)
+	}
+
+	dbInvoices, err := db.FetchAllInvoices(false)
+	if err != nil {
+		t.Fatalf("unable to fetch all invoices: %v", err)
+	}
+	if len(dbInvoices) != len(batch) {
+		t.Fatalf("expected %v invoices, got %v", len(batch),
+			len(dbInvoices))
+	}
+	for i := range batch {
+		if !reflect.DeepEqual(batch[i], dbInvoices[i]) {
+			t.Fatalf("retrieved invoices don't match %v vs %v",
+				spew.Sdump(batch[i]), spew.Sdump(dbInvoices[i]))
+		}
+	}
+}
+
+// TestHoldInvoiceWorkflow tests that a hold invoice may be added without its
+// preimage, and is only able to transition out of the accepting state once,
+// either by having its preimage released, or by being canceled.
+func TestHoldInvoiceWorkflow(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// A hold invoice must either specify its payment hash or preimage.
+	invoice, err := randInvoice(lnwire.NewMSatFromSatoshis(1000))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	preimage := invoice.Terms.PaymentPreimage
+	paymentHash := sha256.Sum256(preimage[:])
+
+	invoice.Terms.PaymentPreimage = [32]byte{}
+	invoice.Terms.Hold = true
+	if err := db.AddInvoice(invoice); err == nil {
+		t.Fatalf("hold invoice without payment hash shouldn't be added")
+	}
+
+	// Once its payment hash is specified, it should be added, and stored
+	// without a preimage.
+	invoice.Terms.PaymentHash = paymentHash
+	if err := db.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add hold invoice: %v", err)
+	}
+	dbInvoice, err := db.LookupInvoice(paymentHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if !reflect.DeepEqual(invoice, dbInvoice) {
+		t.Fatalf("invoice fetched from db doesn't match original %v vs %v",
+			spew.Sdump(invoice), spew.Sdump(dbInvoice))
+	}
+
+	// Releasing the wrong preimage should fail, while the correct one
+	// should transition the invoice to the settling state, without yet
+	// marking it as settled.
+	if err := db.SettleHoldInvoice(paymentHash, rev); err == nil {
+		t.Fatalf("hold invoice settled with incorrect preimage")
+	}
+	if err := db.SettleHoldInvoice(paymentHash, preimage); err != nil {
+		t.Fatalf("unable to settle hold invoice: %v", err)
+	}
+	dbInvoice, err = db.LookupInvoice(paymentHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if dbInvoice.Terms.HoldState != HoldSettling {
+		t.Fatalf("expected hold state %v, got %v", HoldSettling,
+			dbInvoice.Terms.HoldState)
+	}
+	if dbInvoice.Terms.PaymentPreimage != preimage {
+		t.Fatalf("preimage of hold invoice not recorded")
+	}
+	if dbInvoice.Terms.State == ContractSettled {
+		t.Fatalf("hold invoice shouldn't be settled yet")
+	}
+
+	// Now that its preimage has been released, the invoice can no longer
+	// be canceled.
+	if err := db.CancelHoldInvoice(paymentHash); err != ErrHoldInvoiceSettling {
+		t.Fatalf("expected ErrHoldInvoiceSettling, got %v", err)
+	}
+
+	// A canceled hold invoice can no longer be settled.
+	invoice2, err := randInvoice(lnwire.NewMSatFromSatoshis(1000))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	invoice2.Terms.Hold = true
+	if err := db.AddInvoice(invoice2); err != nil {
+		t.Fatalf("unable to add hold invoice: %v", err)
+	}
+	paymentHash2 := invoice2.Terms.PaymentHash
+	if err := db.CancelHoldInvoice(paymentHash2); err != nil {
+		t.Fatalf("unable to cancel hold invoice: %v", err)
+	}
+	err = db.SettleHoldInvoice(
+		paymentHash2, invoice2.Terms.PaymentPreimage,
+	)
+	if err != ErrHoldInvoiceCanceled {
+		t.Fatalf("expected ErrHoldInvoiceCanceled, got %v", err)
+	}
+	dbInvoice, err = db.LookupInvoice(paymentHash2)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if dbInvoice.Terms.State != ContractCanceled {
+		t.Fatalf("expected state %v, got %v", ContractCanceled,
+			dbInvoice.Terms.State)
+	}
+	_, err = db.AcceptInvoice(paymentHash2, InvoiceHTLC{})
+	if err != ErrInvoiceAlreadyCanceled {
+		t.Fatalf("expected ErrInvoiceAlreadyCanceled, got %v", err)
+	}
+
+	// Finally, regular invoices can't be settled or canceled as hold
+	// invoices.
+	invoice3, err := randInvoice(lnwire.NewMSatFromSatoshis(1000))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	if err := db.AddInvoice(invoice3); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+	err = db.CancelHoldInvoice(invoice3.Terms.PaymentHash)
+	if err != ErrNotHoldInvoice {
+		t.Fatalf("expected ErrNotHoldInvoice, got %v", err)
+	}
+}
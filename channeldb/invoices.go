@@ -52,6 +52,129 @@ const (
 	MaxPaymentRequestSize = 4096
 )
 
+// HoldState denotes whether the HTLCs paying to a hold invoice are to be held,
+// settled, or failed back to the sender.
+type HoldState uint8
+
+const (
+	// HoldAccepting denotes that HTLCs paying to the hold invoice are
+	// accepted, but held without being settled or failed.
+	HoldAccepting HoldState = 0
+
+	// HoldSettling denotes that the preimage of the hold invoice has been
+	// released, so the HTLCs paying to it are to be settled.
+	HoldSettling HoldState = 1
+
+	// HoldCanceled denotes that the hold invoice has been canceled, so
+	// the HTLCs paying to it are to be failed back to the sender.
+	HoldCanceled HoldState = 2
+)
+
+// String returns a human readable representation of the HoldState.
+func (h HoldState) String() string {
+	switch h {
+	case HoldAccepting:
+		return "accepting"
+	case HoldSettling:
+		return "settling"
+	case HoldCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// ContractState describes the state of an invoice within its life cycle. An
+// invoice starts out open, becomes accepted once an HTLC paying to it is held
+// by a link, and is eventually either settled or canceled.
+type ContractState uint8
+
+const (
+	// ContractOpen denotes that no HTLCs paying to the invoice have been
+	// accepted yet.
+	ContractOpen ContractState = 0
+
+	// ContractSettled denotes that the HTLCs paying to the invoice have
+	// been settled, so the invoice has been paid.
+	ContractSettled ContractState = 1
+
+	// ContractCanceled denotes that the invoice has been canceled, so any
+	// HTLCs paying to it are failed back to the sender.
+	ContractCanceled ContractState = 2
+
+	// ContractAccepted denotes that HTLCs paying to the invoice have been
+	// accepted, but are held without being settled yet.
+	ContractAccepted ContractState = 3
+)
+
+// String returns a human readable representation of the ContractState.
+func (c ContractState) String() string {
+	switch c {
+	case ContractOpen:
+		return "open"
+	case ContractSettled:
+		return "settled"
+	case ContractCanceled:
+		return "canceled"
+	case ContractAccepted:
+		return "accepted"
+	default:
+		return "unknown"
+	}
+}
+
+// InvoiceHTLCState is the state of an HTLC paying to an invoice.
+type InvoiceHTLCState uint8
+
+const (
+	// HTLCAccepted denotes that the HTLC has been accepted, but not yet
+	// resolved.
+	HTLCAccepted InvoiceHTLCState = 0
+
+	// HTLCSettled denotes that the HTLC has been settled.
+	HTLCSettled InvoiceHTLCState = 1
+
+	// HTLCCanceled denotes that the HTLC is to be failed back to the
+	// sender, as the invoice has been canceled.
+	HTLCCanceled InvoiceHTLCState = 2
+)
+
+// String returns a human readable representation of the InvoiceHTLCState.
+func (h InvoiceHTLCState) String() string {
+	switch h {
+	case HTLCAccepted:
+		return "accepted"
+	case HTLCSettled:
+		return "settled"
+	case HTLCCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// InvoiceHTLC is an HTLC paying to an invoice, identified by the channel it
+// arrived on and its index within the channel.
+type InvoiceHTLC struct {
+	// ChanID is the short channel ID of the channel the HTLC arrived on.
+	ChanID lnwire.ShortChannelID
+
+	// HtlcIndex is the index of the HTLC within the channel.
+	HtlcIndex uint64
+
+	// Amt is the value of the HTLC.
+	Amt lnwire.MilliSatoshi
+
+	// Expiry is the absolute height at which the HTLC expires.
+	Expiry uint32
+
+	// AcceptTime is the time the HTLC was accepted.
+	AcceptTime time.Time
+
+	// State is the state of the HTLC.
+	State InvoiceHTLCState
+}
+
 // ContractTerm is a companion struct to the Invoice struct. This struct houses
 // the necessary conditions required before the invoice can be considered fully
 // settled by the payee.
@@ -65,9 +188,30 @@ type ContractTerm struct {
 	// HTLC which can be satisfied by the above preimage.
 	Value lnwire.MilliSatoshi
 
-	// Settled indicates if this particular contract term has been fully
-	// settled by the payer.
-	Settled bool
+	// State is the state of the invoice within its life cycle.
+	State ContractState
+
+	// PaymentHash is the hash HTLCs paying to this invoice are locked to.
+	// If unset when the invoice is added, it's derived from the payment
+	// preimage.
+	PaymentHash [32]byte
+
+	// Hold indicates that this is a hold invoice. HTLCs paying to a hold
+	// invoice are accepted, but held until the invoice is explicitly
+	// settled or canceled. The preimage of a hold invoice may be unknown
+	// until it's settled.
+	Hold bool
+
+	// HoldState is the state of a hold invoice, which governs how the
+	// HTLCs paying to it are resolved.
+	HoldState HoldState
+
+	// PaymentAddr is the payment secret of the invoice, which its payment
+	// request hands to the payer, and which the payer includes within the
+	// onion of the payment. As only the payer knows it, payments carrying
+	// it can't be probes by intermediate nodes. Invoices created without
+	// one have the zero value.
+	PaymentAddr [32]byte
 }
 
 // Invoice is a payment invoice generated by a payee in order to request
@@ -110,6 +254,30 @@ type Invoice struct {
 	// TODO(roasbeef): later allow for multiple terms to fulfill the final
 	// invoice: payment fragmentation, etc.
 	Terms ContractTerm
+
+	// AmtPaid is the amount in milli-satoshis we were actually paid once
+	// the invoice was settled. It may exceed the value requested by the
+	// invoice, should the payer have overpaid.
+	AmtPaid lnwire.MilliSatoshi
+
+	// Htlcs is the set of HTLCs paying to the invoice, in the order they
+	// were accepted.
+	Htlcs []InvoiceHTLC
+}
+
+// findHTLC returns the HTLC of the invoice which arrived on the passed
+// channel with the passed index, if any.
+func (i *Invoice) findHTLC(chanID lnwire.ShortChannelID,
+	htlcIndex uint64) *InvoiceHTLC {
+
+	for idx := range i.Htlcs {
+		htlc := &i.Htlcs[idx]
+		if htlc.ChanID == chanID && htlc.HtlcIndex == htlcIndex {
+			return htlc
+		}
+	}
+
+	return nil
 }
 
 func validateInvoice(i *Invoice) error {
@@ -127,17 +295,66 @@ func validateInvoice(i *Invoice) error {
 			"provided was %v", MaxPaymentRequestSize,
 			len(i.PaymentRequest))
 	}
+
+	// Only hold invoices may be created without knowledge of their
+	// preimage, in which case their payment hash must be specified.
+	var zeroHash [32]byte
+	switch {
+	case i.Terms.PaymentHash == zeroHash:
+		if i.Terms.Hold && i.Terms.PaymentPreimage == zeroHash {
+			return fmt.Errorf("hold invoice must specify either " +
+				"a payment hash or preimage")
+		}
+
+	case i.Terms.PaymentPreimage == zeroHash && !i.Terms.Hold:
+		return fmt.Errorf("only hold invoices may be added without " +
+			"a payment preimage")
+
+	case i.Terms.PaymentPreimage != zeroHash &&
+		sha256.Sum256(i.Terms.PaymentPreimage[:]) != i.Terms.PaymentHash:
+		return fmt.Errorf("payment preimage doesn't match payment " +
+			"hash")
+	}
+	if i.Terms.Hold && i.Terms.HoldState != HoldAccepting {
+		return fmt.Errorf("hold invoice must be added in the %v "+
+			"state", HoldAccepting)
+	}
+	if i.Terms.State != ContractOpen || len(i.Htlcs) != 0 {
+		return fmt.Errorf("invoice must be added in the %v state, "+
+			"without any htlcs", ContractOpen)
+	}
+
 	return nil
 }
 
 // AddInvoice inserts the targeted invoice into the database. If the invoice
 // has *any* payment hashes which already exists within the database, then the
 // insertion will be aborted and rejected due to the strict policy banning any
-// duplicate payment hashes.
+// duplicate payment hashes. If the payment hash of the invoice is unset, then
+// it'll be populated from its preimage.
 func (d *DB) AddInvoice(i *Invoice) error {
-	if err := validateInvoice(i); err != nil {
-		return err
+	return d.AddInvoices([]*Invoice{i})
+}
+
+// AddInvoices inserts the passed invoices into the database within a single
+// transaction, in order. The batch is atomic: if any of the invoices is
+// invalid, or has a payment hash which already exists within the database or
+// within the batch itself, then none of the invoices are added. The payment
+// hashes of invoices without one are populated from their preimages.
+func (d *DB) AddInvoices(batch []*Invoice) error {
+	var zeroHash [32]byte
+	for _, i := range batch {
+		if err := validateInvoice(i); err != nil {
+			return err
+		}
+
+		if i.Terms.PaymentHash == zeroHash {
+			i.Terms.PaymentHash = sha256.Sum256(
+				i.Terms.PaymentPreimage[:],
+			)
+		}
 	}
+
 	return d.Update(func(tx *bolt.Tx) error {
 		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
 		if err != nil {
@@ -149,13 +366,6 @@ func (d *DB) AddInvoice(i *Invoice) error {
 			return err
 		}
 
-		// Ensure that an invoice an identical payment hash doesn't
-		// already exist within the index.
-		paymentHash := sha256.Sum256(i.Terms.PaymentPreimage[:])
-		if invoiceIndex.Get(paymentHash[:]) != nil {
-			return ErrDuplicateInvoice
-		}
-
 		// If the current running payment ID counter hasn't yet been
 		// created, then create it now.
 		var invoiceNum uint32
@@ -170,7 +380,24 @@ func (d *DB) AddInvoice(i *Invoice) error {
 			invoiceNum = byteOrder.Uint32(invoiceCounter)
 		}
 
-		return putInvoice(invoices, invoiceIndex, i, invoiceNum)
+		for _, i := range batch {
+			// Ensure that an invoice an identical payment hash
+			// doesn't already exist within the index. As each
+			// invoice of the batch is indexed as it's added, this
+			// also catches duplicates within the batch.
+			paymentHash := i.Terms.PaymentHash
+			if invoiceIndex.Get(paymentHash[:]) != nil {
+				return ErrDuplicateInvoice
+			}
+
+			err := putInvoice(invoices, invoiceIndex, i, invoiceNum)
+			if err != nil {
+				return err
+			}
+			invoiceNum++
+		}
+
+		return nil
 	})
 }
 
@@ -217,8 +444,8 @@ func (d *DB) LookupInvoice(paymentHash [32]byte) (*Invoice, error) {
 }
 
 // FetchAllInvoices returns all invoices currently stored within the database.
-// If the pendingOnly param is true, then only unsettled invoices will be
-// returned, skipping all invoices that are fully settled.
+// If the pendingOnly param is true, then only open and accepted invoices will
+// be returned, skipping all invoices that are settled or canceled.
 func (d *DB) FetchAllInvoices(pendingOnly bool) ([]*Invoice, error) {
 	var invoices []*Invoice
 
@@ -237,12 +464,15 @@ func (d *DB) FetchAllInvoices(pendingOnly bool) ([]*Invoice, error) {
 			}
 
 			invoiceReader := bytes.NewReader(v)
-			invoice, err := deserializeInvoice(invoiceReader)
+			invoice, err := deserializeStoredInvoice(invoiceReader)
 			if err != nil {
 				return err
 			}
 
-			if pendingOnly && invoice.Terms.Settled {
+			state := invoice.Terms.State
+			if pendingOnly && (state == ContractSettled ||
+				state == ContractCanceled) {
+
 				return nil
 			}
 
@@ -258,32 +488,223 @@ func (d *DB) FetchAllInvoices(pendingOnly bool) ([]*Invoice, error) {
 	return invoices, nil
 }
 
+// AcceptInvoice records the passed HTLC as accepted, but held, within the HTLC
+// set of the invoice corresponding to the passed payment hash, transitioning
+// the invoice to the ContractAccepted state. Accepting an HTLC that's already
+// part of the set is a noop. The updated invoice is returned.
+func (d *DB) AcceptInvoice(paymentHash [32]byte,
+	htlc InvoiceHTLC) (*Invoice, error) {
+
+	var invoice *Invoice
+	err := d.updateInvoice(paymentHash, func(i *Invoice) (bool, error) {
+		invoice = i
+
+		switch i.Terms.State {
+		case ContractSettled:
+			return false, ErrInvoiceAlreadySettled
+		case ContractCanceled:
+			return false, ErrInvoiceAlreadyCanceled
+		}
+
+		if i.findHTLC(htlc.ChanID, htlc.HtlcIndex) != nil {
+			return false, nil
+		}
+
+		htlc.AcceptTime = time.Now()
+		htlc.State = HTLCAccepted
+		i.Htlcs = append(i.Htlcs, htlc)
+		i.Terms.State = ContractAccepted
+
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
 // SettleInvoice attempts to mark an invoice corresponding to the passed
-// payment hash as fully settled. If an invoice matching the passed payment
-// hash doesn't existing within the database, then the action will fail with a
-// "not found" error.
-func (d *DB) SettleInvoice(paymentHash [32]byte) error {
+// payment hash as fully settled by the passed HTLC. The HTLC is added to the
+// HTLC set of the invoice if it isn't part of it yet, and is marked as settled
+// along with any other HTLCs that were accepted. The amount paid is the total
+// value of the settled HTLCs. If an invoice matching the passed payment hash
+// doesn't existing within the database, then the action will fail with a "not
+// found" error. The updated invoice is returned.
+func (d *DB) SettleInvoice(paymentHash [32]byte,
+	htlc InvoiceHTLC) (*Invoice, error) {
+
+	var invoice *Invoice
+	err := d.updateInvoice(paymentHash, func(i *Invoice) (bool, error) {
+		invoice = i
+		return settleInvoice(i, htlc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// SettleHoldInvoice releases the preimage of the hold invoice corresponding
+// to the passed payment hash, transitioning it to the HoldSettling state. The
+// invoice itself is only marked as settled once the HTLCs paying to it have
+// been settled. If the preimage of the invoice was unknown, then the passed
+// preimage is recorded, otherwise it must match the one already known.
+func (d *DB) SettleHoldInvoice(paymentHash, preimage [32]byte) error {
+	return d.SettleHoldInvoices(map[[32]byte][32]byte{
+		paymentHash: preimage,
+	})
+}
+
+// SettleHoldInvoices releases the preimages of the hold invoices
+// corresponding to the payment hashes of the passed map, as SettleHoldInvoice
+// does, within a single transaction. Either all of the invoices are settled,
+// or none are.
+func (d *DB) SettleHoldInvoices(preimages map[[32]byte][32]byte) error {
+	for paymentHash, preimage := range preimages {
+		if sha256.Sum256(preimage[:]) != paymentHash {
+			return fmt.Errorf("payment preimage doesn't match " +
+				"payment hash")
+		}
+	}
+
 	return d.Update(func(tx *bolt.Tx) error {
-		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
-		if err != nil {
-			return err
+		for paymentHash, preimage := range preimages {
+			preimage := preimage
+			err := updateHoldInvoice(tx, paymentHash,
+				func(invoice *Invoice) error {
+					return settleHoldInvoice(
+						invoice, preimage,
+					)
+				},
+			)
+			if err != nil {
+				return err
+			}
 		}
-		invoiceIndex, err := invoices.CreateBucketIfNotExists(invoiceIndexBucket)
-		if err != nil {
-			return err
+
+		return nil
+	})
+}
+
+// settleHoldInvoice releases the passed preimage of the passed hold invoice,
+// as described by SettleHoldInvoice.
+func settleHoldInvoice(invoice *Invoice, preimage [32]byte) error {
+	switch invoice.Terms.HoldState {
+	case HoldSettling:
+		return nil
+	case HoldCanceled:
+		return ErrHoldInvoiceCanceled
+	}
+
+	invoice.Terms.PaymentPreimage = preimage
+	invoice.Terms.HoldState = HoldSettling
+	return nil
+}
+
+// CancelHoldInvoice cancels the hold invoice corresponding to the passed
+// payment hash, transitioning it to the HoldCanceled and ContractCanceled
+// states. Any HTLCs paying to the invoice are to be failed back to the
+// sender. A hold invoice can't be canceled once its preimage has been
+// released.
+func (d *DB) CancelHoldInvoice(paymentHash [32]byte) error {
+	return d.updateHoldInvoice(paymentHash, func(invoice *Invoice) error {
+		switch invoice.Terms.HoldState {
+		case HoldCanceled:
+			return nil
+		case HoldSettling:
+			return ErrHoldInvoiceSettling
 		}
 
-		// Check the invoice index to see if an invoice paying to this
-		// hash exists within the DB.
-		invoiceNum := invoiceIndex.Get(paymentHash[:])
-		if invoiceNum == nil {
-			return ErrInvoiceNotFound
+		invoice.Terms.HoldState = HoldCanceled
+		invoice.Terms.State = ContractCanceled
+		for idx := range invoice.Htlcs {
+			if invoice.Htlcs[idx].State == HTLCAccepted {
+				invoice.Htlcs[idx].State = HTLCCanceled
+			}
 		}
+		return nil
+	})
+}
+
+// updateHoldInvoice applies the passed modification to the hold invoice
+// corresponding to the passed payment hash, then writes it back to disk.
+func (d *DB) updateHoldInvoice(paymentHash [32]byte,
+	modify func(*Invoice) error) error {
 
-		return settleInvoice(invoices, invoiceNum)
+	return d.Update(func(tx *bolt.Tx) error {
+		return updateHoldInvoice(tx, paymentHash, modify)
+	})
+}
+
+// updateHoldInvoice applies the passed modification to the hold invoice
+// corresponding to the passed payment hash within the passed transaction.
+func updateHoldInvoice(tx *bolt.Tx, paymentHash [32]byte,
+	modify func(*Invoice) error) error {
+
+	return updateInvoice(tx, paymentHash, func(i *Invoice) (bool, error) {
+		if !i.Terms.Hold {
+			return false, ErrNotHoldInvoice
+		}
+
+		if err := modify(i); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	})
+}
+
+// updateInvoice applies the passed modification to the invoice corresponding
+// to the passed payment hash. The invoice is only written back to disk if the
+// modification reports that it has changed it.
+func (d *DB) updateInvoice(paymentHash [32]byte,
+	modify func(*Invoice) (bool, error)) error {
+
+	return d.Update(func(tx *bolt.Tx) error {
+		return updateInvoice(tx, paymentHash, modify)
 	})
 }
 
+// updateInvoice applies the passed modification to the invoice corresponding
+// to the passed payment hash within the passed transaction, as described by
+// the method of the same name.
+func updateInvoice(tx *bolt.Tx, paymentHash [32]byte,
+	modify func(*Invoice) (bool, error)) error {
+
+	invoices := tx.Bucket(invoiceBucket)
+	if invoices == nil {
+		return ErrNoInvoicesCreated
+	}
+	invoiceIndex := invoices.Bucket(invoiceIndexBucket)
+	if invoiceIndex == nil {
+		return ErrNoInvoicesCreated
+	}
+
+	invoiceNum := invoiceIndex.Get(paymentHash[:])
+	if invoiceNum == nil {
+		return ErrInvoiceNotFound
+	}
+
+	invoice, err := fetchInvoice(invoiceNum, invoices)
+	if err != nil {
+		return err
+	}
+
+	changed, err := modify(invoice)
+	if err != nil || !changed {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := serializeStoredInvoice(&buf, invoice); err != nil {
+		return err
+	}
+
+	return invoices.Put(invoiceNum, buf.Bytes())
+}
+
 func putInvoice(invoices *bolt.Bucket, invoiceIndex *bolt.Bucket,
 	i *Invoice, invoiceNum uint32) error {
 
@@ -304,14 +725,14 @@ func putInvoice(invoices *bolt.Bucket, invoiceIndex *bolt.Bucket,
 	// Add the payment hash to the invoice index. This'll let us quickly
 	// identify if we can settle an incoming payment, and also to possibly
 	// allow a single invoice to have multiple payment installations.
-	paymentHash := sha256.Sum256(i.Terms.PaymentPreimage[:])
+	paymentHash := i.Terms.PaymentHash
 	if err := invoiceIndex.Put(paymentHash[:], invoiceKey[:]); err != nil {
 		return err
 	}
 
 	// Finally, serialize the invoice itself to be written to the disk.
 	var buf bytes.Buffer
-	if err := serializeInvoice(&buf, i); err != nil {
+	if err := serializeStoredInvoice(&buf, i); err != nil {
 		return nil
 	}
 
@@ -357,7 +778,72 @@ func serializeInvoice(w io.Writer, i *Invoice) error {
 		return err
 	}
 
-	if err := binary.Write(w, byteOrder, i.Terms.Settled); err != nil {
+	// The state takes the place of the boolean which denoted whether the
+	// invoice was settled, so ContractSettled must remain encoded as 1.
+	if err := binary.Write(w, byteOrder, i.Terms.State); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(i.Terms.PaymentHash[:]); err != nil {
+		return err
+	}
+	if err := binary.Write(w, byteOrder, i.Terms.Hold); err != nil {
+		return err
+	}
+	if err := binary.Write(w, byteOrder, i.Terms.HoldState); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// serializeStoredInvoice serializes an invoice as it's stored within the
+// invoice bucket, which is followed by the amount paid to it, its HTLC set,
+// and its payment address. These aren't written by serializeInvoice, as
+// outgoing payments embed their invoice followed by further fields.
+func serializeStoredInvoice(w io.Writer, i *Invoice) error {
+	if err := serializeInvoice(w, i); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	byteOrder.PutUint64(scratch[:], uint64(i.AmtPaid))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	numHtlcs := uint16(len(i.Htlcs))
+	if err := binary.Write(w, byteOrder, numHtlcs); err != nil {
+		return err
+	}
+	for _, htlc := range i.Htlcs {
+		err := binary.Write(w, byteOrder, htlc.ChanID.ToUint64())
+		if err != nil {
+			return err
+		}
+		err = binary.Write(w, byteOrder, htlc.HtlcIndex)
+		if err != nil {
+			return err
+		}
+		err = binary.Write(w, byteOrder, uint64(htlc.Amt))
+		if err != nil {
+			return err
+		}
+		err = binary.Write(w, byteOrder, htlc.Expiry)
+		if err != nil {
+			return err
+		}
+		err = binary.Write(w, byteOrder, htlc.AcceptTime.Unix())
+		if err != nil {
+			return err
+		}
+		err = binary.Write(w, byteOrder, htlc.State)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.Write(i.Terms.PaymentAddr[:]); err != nil {
 		return err
 	}
 
@@ -372,7 +858,7 @@ func fetchInvoice(invoiceNum []byte, invoices *bolt.Bucket) (*Invoice, error) {
 
 	invoiceReader := bytes.NewReader(invoiceBytes)
 
-	return deserializeInvoice(invoiceReader)
+	return deserializeStoredInvoice(invoiceReader)
 }
 
 func deserializeInvoice(r io.Reader) (*Invoice, error) {
@@ -419,26 +905,153 @@ func deserializeInvoice(r io.Reader) (*Invoice, error) {
 	}
 	invoice.Terms.Value = lnwire.MilliSatoshi(byteOrder.Uint64(scratch[:]))
 
-	if err := binary.Read(r, byteOrder, &invoice.Terms.Settled); err != nil {
+	if err := binary.Read(r, byteOrder, &invoice.Terms.State); err != nil {
 		return nil, err
 	}
 
+	// Invoices written before the introduction of hold invoices end here,
+	// in which case their payment hash is derived from their preimage.
+	_, err = io.ReadFull(r, invoice.Terms.PaymentHash[:])
+	if err == io.EOF {
+		invoice.Terms.PaymentHash = sha256.Sum256(
+			invoice.Terms.PaymentPreimage[:],
+		)
+		return invoice, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, byteOrder, &invoice.Terms.Hold); err != nil {
+		return nil, err
+	}
+	err = binary.Read(r, byteOrder, &invoice.Terms.HoldState)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hold invoices canceled before the introduction of the invoice state
+	// were left open, so we'll mark them as canceled.
+	if invoice.Terms.HoldState == HoldCanceled &&
+		invoice.Terms.State == ContractOpen {
+
+		invoice.Terms.State = ContractCanceled
+	}
+
 	return invoice, nil
 }
 
-func settleInvoice(invoices *bolt.Bucket, invoiceNum []byte) error {
-	invoice, err := fetchInvoice(invoiceNum, invoices)
+// deserializeStoredInvoice deserializes an invoice as it's stored within the
+// invoice bucket, along with the amount paid to it, its HTLC set, and its
+// payment address.
+func deserializeStoredInvoice(r io.Reader) (*Invoice, error) {
+	invoice, err := deserializeInvoice(r)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// Invoices written before the amount paid was recorded end here, in
+	// which case it's left unknown.
+	var scratch [8]byte
+	_, err = io.ReadFull(r, scratch[:])
+	if err == io.EOF {
+		return invoice, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+	invoice.AmtPaid = lnwire.MilliSatoshi(byteOrder.Uint64(scratch[:]))
+
+	// Likewise, invoices written before their HTLC set was tracked end
+	// here.
+	var numHtlcs uint16
+	err = binary.Read(r, byteOrder, &numHtlcs)
+	if err == io.EOF {
+		return invoice, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for idx := uint16(0); idx < numHtlcs; idx++ {
+		var (
+			htlc       InvoiceHTLC
+			chanID     uint64
+			amt        uint64
+			acceptTime int64
+		)
+		err := readElements(
+			r, &chanID, &htlc.HtlcIndex, &amt, &htlc.Expiry,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, byteOrder, &acceptTime); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, byteOrder, &htlc.State); err != nil {
+			return nil, err
+		}
 
-	invoice.Terms.Settled = true
-	invoice.SettleDate = time.Now()
+		htlc.ChanID = lnwire.NewShortChanIDFromInt(chanID)
+		htlc.Amt = lnwire.MilliSatoshi(amt)
+		htlc.AcceptTime = time.Unix(acceptTime, 0)
+		invoice.Htlcs = append(invoice.Htlcs, htlc)
+	}
 
-	var buf bytes.Buffer
-	if err := serializeInvoice(&buf, invoice); err != nil {
-		return nil
+	// Invoices written before payment addresses were introduced end here,
+	// in which case they don't have one.
+	_, err = io.ReadFull(r, invoice.Terms.PaymentAddr[:])
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// SettleStateless marks the invoice as settled by the passed HTLC, as
+// SettleInvoice does, without writing it to the database. It's used for
+// stateless invoices, which are reconstructed as they're paid rather than
+// stored.
+func (i *Invoice) SettleStateless(htlc InvoiceHTLC) error {
+	_, err := settleInvoice(i, htlc)
+	return err
+}
+
+// settleInvoice marks the passed invoice as settled by the passed HTLC, as
+// described by SettleInvoice. It returns whether the invoice was modified.
+func settleInvoice(invoice *Invoice, htlc InvoiceHTLC) (bool, error) {
+	if invoice.Terms.State == ContractCanceled {
+		return false, ErrInvoiceAlreadyCanceled
+	}
+
+	existing := invoice.findHTLC(htlc.ChanID, htlc.HtlcIndex)
+	if invoice.Terms.State == ContractSettled && existing != nil &&
+		existing.State == HTLCSettled {
+
+		return false, nil
+	}
+
+	if existing == nil {
+		htlc.AcceptTime = time.Now()
+		htlc.State = HTLCAccepted
+		invoice.Htlcs = append(invoice.Htlcs, htlc)
+	}
+
+	// All HTLCs which were accepted are settled along with the passed
+	// one, as they pay to the same preimage.
+	invoice.AmtPaid = 0
+	for idx := range invoice.Htlcs {
+		if invoice.Htlcs[idx].State == HTLCAccepted {
+			invoice.Htlcs[idx].State = HTLCSettled
+		}
+		if invoice.Htlcs[idx].State == HTLCSettled {
+			invoice.AmtPaid += invoice.Htlcs[idx].Amt
+		}
+	}
+
+	if invoice.Terms.State != ContractSettled {
+		invoice.Terms.State = ContractSettled
+		invoice.SettleDate = time.Now()
 	}
 
-	return invoices.Put(invoiceNum[:], buf.Bytes())
+	return true, nil
 }
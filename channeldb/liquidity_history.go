@@ -0,0 +1,233 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// liquidityHistoryBucket is the name of the bucket which houses the
+// liquidity history of our channels. It holds a sub-bucket for each channel,
+// keyed by its channel point, within which each snapshot is keyed by the
+// big-endian unix time at which it was taken, so a channel's snapshots are
+// stored in chronological order.
+var liquidityHistoryBucket = []byte("liquidity-history")
+
+// LiquiditySnapshot records the distribution of a channel's funds at a point
+// in time.
+type LiquiditySnapshot struct {
+	// Timestamp is the time at which the snapshot was taken. It's stored
+	// with a resolution of one second.
+	Timestamp time.Time
+
+	// LocalBalance is our settled balance within the channel.
+	LocalBalance lnwire.MilliSatoshi
+
+	// RemoteBalance is the remote party's settled balance within the
+	// channel.
+	RemoteBalance lnwire.MilliSatoshi
+
+	// PendingHTLCValue is the total value of the HTLCs pending within
+	// the channel.
+	PendingHTLCValue lnwire.MilliSatoshi
+
+	// NumPendingHTLCs is the number of HTLCs pending within the channel.
+	NumPendingHTLCs uint16
+}
+
+// RecordLiquiditySnapshots adds the passed snapshots, keyed by the channel
+// point of the channel they were taken of, to the liquidity history. A
+// snapshot taken within the same second as a prior snapshot of the same
+// channel replaces it.
+func (d *DB) RecordLiquiditySnapshots(
+	snapshots map[wire.OutPoint]*LiquiditySnapshot) error {
+
+	return d.Update(func(tx *bolt.Tx) error {
+		histBucket, err := tx.CreateBucketIfNotExists(
+			liquidityHistoryBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		for chanPoint, snapshot := range snapshots {
+			chanPoint := chanPoint
+
+			var chanKey bytes.Buffer
+			err := writeOutpoint(&chanKey, &chanPoint)
+			if err != nil {
+				return err
+			}
+
+			chanBucket, err := histBucket.CreateBucketIfNotExists(
+				chanKey.Bytes(),
+			)
+			if err != nil {
+				return err
+			}
+
+			var b bytes.Buffer
+			err = serializeLiquiditySnapshot(&b, snapshot)
+			if err != nil {
+				return err
+			}
+
+			err = chanBucket.Put(
+				liquidityTimeKey(snapshot.Timestamp), b.Bytes(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FetchLiquidityHistory returns the snapshots of the channel with the passed
+// channel point which were taken within the passed time range, inclusive of
+// both ends, in chronological order.
+func (d *DB) FetchLiquidityHistory(chanPoint *wire.OutPoint, start,
+	end time.Time) ([]*LiquiditySnapshot, error) {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return nil, err
+	}
+
+	var snapshots []*LiquiditySnapshot
+	err := d.View(func(tx *bolt.Tx) error {
+		histBucket := tx.Bucket(liquidityHistoryBucket)
+		if histBucket == nil {
+			return nil
+		}
+		chanBucket := histBucket.Bucket(chanKey.Bytes())
+		if chanBucket == nil {
+			return nil
+		}
+
+		endKey := liquidityTimeKey(end)
+
+		c := chanBucket.Cursor()
+		k, v := c.Seek(liquidityTimeKey(start))
+		for ; k != nil; k, v = c.Next() {
+			if bytes.Compare(k, endKey) > 0 {
+				break
+			}
+
+			snapshot, err := deserializeLiquiditySnapshot(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			snapshot.Timestamp = time.Unix(
+				int64(byteOrder.Uint64(k)), 0,
+			)
+
+			snapshots = append(snapshots, snapshot)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// PruneLiquidityHistory removes all snapshots taken prior to the passed time
+// from the liquidity history. The history of a channel is removed entirely
+// once none of its snapshots remain, so the history of closed channels is
+// eventually pruned as well.
+func (d *DB) PruneLiquidityHistory(before time.Time) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		histBucket := tx.Bucket(liquidityHistoryBucket)
+		if histBucket == nil {
+			return nil
+		}
+
+		// Bolt doesn't permit a bucket to be modified while it's
+		// being iterated over, so we'll first gather the keys to be
+		// deleted.
+		var chanKeys [][]byte
+		err := histBucket.ForEach(func(k, v []byte) error {
+			chanKeys = append(chanKeys, k)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		beforeKey := liquidityTimeKey(before)
+		for _, chanKey := range chanKeys {
+			chanBucket := histBucket.Bucket(chanKey)
+			if chanBucket == nil {
+				continue
+			}
+
+			var staleKeys [][]byte
+			c := chanBucket.Cursor()
+			for k, _ := c.First(); k != nil; k, _ = c.Next() {
+				if bytes.Compare(k, beforeKey) >= 0 {
+					break
+				}
+
+				staleKeys = append(staleKeys, k)
+			}
+			for _, k := range staleKeys {
+				if err := chanBucket.Delete(k); err != nil {
+					return err
+				}
+			}
+
+			if k, _ := chanBucket.Cursor().First(); k != nil {
+				continue
+			}
+			err := histBucket.DeleteBucket(chanKey)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// liquidityTimeKey returns the key of a snapshot taken at the passed time.
+// Times prior to the unix epoch map to the smallest possible key.
+func liquidityTimeKey(t time.Time) []byte {
+	var unix uint64
+	if t.Unix() > 0 {
+		unix = uint64(t.Unix())
+	}
+
+	var k [8]byte
+	byteOrder.PutUint64(k[:], unix)
+	return k[:]
+}
+
+func serializeLiquiditySnapshot(w io.Writer, s *LiquiditySnapshot) error {
+	return writeElements(w,
+		s.LocalBalance, s.RemoteBalance, s.PendingHTLCValue,
+		s.NumPendingHTLCs,
+	)
+}
+
+func deserializeLiquiditySnapshot(r io.Reader) (*LiquiditySnapshot, error) {
+	s := &LiquiditySnapshot{}
+	err := readElements(r,
+		&s.LocalBalance, &s.RemoteBalance, &s.PendingHTLCValue,
+		&s.NumPendingHTLCs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
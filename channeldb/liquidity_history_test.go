@@ -0,0 +1,97 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestLiquidityHistory tests that liquidity snapshots can be fetched by time
+// range in chronological order, and that pruning removes stale snapshots, as
+// well as the history of channels left without any snapshots.
+func TestLiquidityHistory(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	chanA := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}
+	chanB := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}
+
+	history, err := cdb.FetchLiquidityHistory(
+		&chanA, time.Unix(0, 0), time.Now(),
+	)
+	if err != nil {
+		t.Fatalf("unable to fetch history: %v", err)
+	}
+	if len(history) != 0 {
+		t.Fatalf("expected no snapshots, instead got %v", len(history))
+	}
+
+	// Record three snapshots of the first channel, and one of the
+	// second, out of chronological order.
+	base := time.Unix(time.Now().Unix(), 0)
+	snapshots := make([]*LiquiditySnapshot, 3)
+	for i := range snapshots {
+		snapshots[i] = &LiquiditySnapshot{
+			Timestamp:        base.Add(time.Duration(i) * time.Hour),
+			LocalBalance:     1000 * lnwire.MilliSatoshi(i+1),
+			RemoteBalance:    2000 * lnwire.MilliSatoshi(i+1),
+			PendingHTLCValue: 300 * lnwire.MilliSatoshi(i),
+			NumPendingHTLCs:  uint16(i),
+		}
+	}
+	for _, i := range []int{2, 0, 1} {
+		err := cdb.RecordLiquiditySnapshots(
+			map[wire.OutPoint]*LiquiditySnapshot{
+				chanA: snapshots[i],
+				chanB: snapshots[0],
+			},
+		)
+		if err != nil {
+			t.Fatalf("unable to record snapshots: %v", err)
+		}
+	}
+
+	assertHistory := func(chanPoint wire.OutPoint, start, end time.Time,
+		expected []*LiquiditySnapshot) {
+
+		history, err := cdb.FetchLiquidityHistory(&chanPoint, start, end)
+		if err != nil {
+			t.Fatalf("unable to fetch history: %v", err)
+		}
+		if len(history) == 0 && len(expected) == 0 {
+			return
+		}
+		if !reflect.DeepEqual(history, expected) {
+			t.Fatalf("history doesn't match: expected %v, got %v",
+				spew.Sdump(expected), spew.Sdump(history))
+		}
+	}
+
+	// The entire history should be returned in order, and the range
+	// should be inclusive of both ends.
+	assertHistory(chanA, base, base.Add(2*time.Hour), snapshots)
+	assertHistory(
+		chanA, base.Add(time.Hour), base.Add(time.Hour),
+		snapshots[1:2],
+	)
+	assertHistory(chanB, base, base.Add(2*time.Hour), snapshots[:1])
+
+	// Pruning everything prior to the second snapshot should leave the
+	// last two snapshots of the first channel, and remove the history of
+	// the second channel entirely.
+	if err := cdb.PruneLiquidityHistory(base.Add(time.Hour)); err != nil {
+		t.Fatalf("unable to prune history: %v", err)
+	}
+	assertHistory(chanA, base, base.Add(2*time.Hour), snapshots[1:])
+	assertHistory(chanB, base, base.Add(2*time.Hour), nil)
+}
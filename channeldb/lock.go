@@ -0,0 +1,232 @@
+package channeldb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// lockFileName is the name of the file, residing alongside the database
+// file, which guards the channel database against being opened by more than
+// a single process at once. Should two daemons operate on the same channel
+// state, each would be liable to broadcast a revoked commitment, forfeiting
+// the funds of the channel.
+const lockFileName = "channel.db.lock"
+
+var (
+	// heldLocks is the set of paths of the lock files held by this
+	// process. It allows a lock file left behind by a prior process that
+	// happened to share our PID, as is common within containers, to be
+	// told apart from a lock we hold ourselves.
+	heldLocks    = make(map[string]struct{})
+	heldLocksMtx sync.Mutex
+)
+
+// LockInfo describes the process holding the lock of a channel database.
+type LockInfo struct {
+	// PID is the process ID of the process holding the lock.
+	PID int `json:"pid"`
+
+	// Hostname is the hostname of the machine the process holding the
+	// lock runs on.
+	Hostname string `json:"hostname"`
+
+	// AcquiredAt is the time the lock was acquired.
+	AcquiredAt time.Time `json:"acquired_at"`
+}
+
+// ErrDBLocked is returned when the channel database is in use by another
+// process.
+type ErrDBLocked struct {
+	// Path is the path of the database's lock file.
+	Path string
+
+	// Holder describes the process holding the lock. It's nil if the lock
+	// file couldn't be read.
+	Holder *LockInfo
+}
+
+// Error returns a human readable description of the error.
+func (e *ErrDBLocked) Error() string {
+	if e.Holder == nil {
+		return fmt.Sprintf("channel database is in use by another "+
+			"process, lock file %v is unreadable", e.Path)
+	}
+
+	return fmt.Sprintf("channel database is in use by pid %v on %v since "+
+		"%v, lock file %v", e.Holder.PID, e.Holder.Hostname,
+		e.Holder.AcquiredAt.Format(time.RFC3339), e.Path)
+}
+
+// dbLock is an exclusive lock of a channel database, held by this process.
+type dbLock struct {
+	path string
+	info LockInfo
+}
+
+// acquireLock acquires the exclusive lock of the channel database within the
+// passed directory. If another process holds the lock, then ErrDBLocked is
+// returned, unless the lock is stale: held by a process that no longer
+// exists on this machine. Stale locks are removed. If force is true, then the
+// lock is taken over regardless of its holder, which is only safe once it's
+// certain that no other process is using the database.
+func acquireLock(dbPath string, force bool) (*dbLock, error) {
+	path := filepath.Join(dbPath, lockFileName)
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	lock := &dbLock{
+		path: path,
+		info: LockInfo{
+			PID:        os.Getpid(),
+			Hostname:   hostname,
+			AcquiredAt: time.Now(),
+		},
+	}
+
+	heldLocksMtx.Lock()
+	defer heldLocksMtx.Unlock()
+
+	// If the lock file already exists, then we'll remove it only if it's
+	// stale or we're forced to, and try once more. Should another process
+	// create it in the meantime, then we'll lose the race and back off.
+	for i := 0; i < 2; i++ {
+		err := lock.create()
+		if err == nil {
+			heldLocks[path] = struct{}{}
+			return lock, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		// An unreadable lock file may be in the midst of being written
+		// by another process, so it's only removed if forced.
+		holder, _ := readLockInfo(path)
+		switch {
+		case force:
+			log.Warnf("Forcefully taking over lock file %v, held "+
+				"by %v", path, describeHolder(holder))
+
+		case holder != nil && lock.isStale(holder):
+			log.Infof("Removing stale lock file %v, held by %v",
+				path, describeHolder(holder))
+
+		default:
+			return nil, &ErrDBLocked{Path: path, Holder: holder}
+		}
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	holder, _ := readLockInfo(path)
+	return nil, &ErrDBLocked{Path: path, Holder: holder}
+}
+
+// create exclusively creates the lock file, failing if it already exists.
+func (l *dbLock) create() error {
+	info, err := json.Marshal(&l.info)
+	if err != nil {
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	f, err := os.OpenFile(l.path, flags, dbFilePermission)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(info); err != nil {
+		f.Close()
+		os.Remove(l.path)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(l.path)
+		return err
+	}
+
+	return f.Close()
+}
+
+// isStale returns true if the lock described by the passed holder was left
+// behind by a process on this machine which no longer holds it.
+func (l *dbLock) isStale(holder *LockInfo) bool {
+	// We can't tell whether a process on another machine, sharing the
+	// database over a network file system, still exists.
+	if holder.Hostname != l.info.Hostname {
+		return false
+	}
+
+	// If the holder shares our PID, then it's either us, or a prior
+	// process that happened to be assigned the same PID.
+	if holder.PID == l.info.PID {
+		_, held := heldLocks[l.path]
+		return !held
+	}
+
+	return !processExists(holder.PID)
+}
+
+// release releases the lock, removing the lock file, provided it still
+// belongs to us.
+func (l *dbLock) release() error {
+	heldLocksMtx.Lock()
+	defer heldLocksMtx.Unlock()
+
+	delete(heldLocks, l.path)
+
+	// If the lock was forcefully taken over by another process, then it's
+	// no longer ours to remove.
+	holder, err := readLockInfo(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if holder.PID != l.info.PID || holder.Hostname != l.info.Hostname ||
+		!holder.AcquiredAt.Equal(l.info.AcquiredAt) {
+
+		log.Warnf("Lock file %v has been taken over by %v, leaving it "+
+			"in place", l.path, describeHolder(holder))
+		return nil
+	}
+
+	return os.Remove(l.path)
+}
+
+// readLockInfo reads the description of the lock holder from the lock file at
+// the passed path.
+func readLockInfo(path string) (*LockInfo, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &LockInfo{}
+	if err := json.Unmarshal(b, info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// describeHolder returns a human readable description of the lock holder.
+func describeHolder(holder *LockInfo) string {
+	if holder == nil {
+		return "an unknown process"
+	}
+
+	return fmt.Sprintf("pid %v on %v since %v", holder.PID,
+		holder.Hostname, holder.AcquiredAt.Format(time.RFC3339))
+}
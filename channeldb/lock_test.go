@@ -0,0 +1,122 @@
+package channeldb
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestLock writes a lock file into the passed directory, describing the
+// passed holder.
+func writeTestLock(t *testing.T, dbPath string, holder *LockInfo) {
+	b, err := json.Marshal(holder)
+	if err != nil {
+		t.Fatalf("unable to encode lock: %v", err)
+	}
+
+	path := filepath.Join(dbPath, lockFileName)
+	if err := ioutil.WriteFile(path, b, dbFilePermission); err != nil {
+		t.Fatalf("unable to write lock: %v", err)
+	}
+}
+
+// TestDBLock tests that the channel database can't be opened while another
+// process holds its lock, unless the lock is stale, or forcefully taken over.
+func TestDBLock(t *testing.T) {
+	t.Parallel()
+
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatalf("unable to get hostname: %v", err)
+	}
+
+	cdb, err := Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+
+	// As we hold the lock ourselves, opening the database once more
+	// should fail, reporting us as the holder.
+	_, err = Open(tempDirName)
+	lockErr, ok := err.(*ErrDBLocked)
+	if !ok {
+		t.Fatalf("expected ErrDBLocked, got %v", err)
+	}
+	if lockErr.Holder == nil || lockErr.Holder.PID != os.Getpid() ||
+		lockErr.Holder.Hostname != hostname {
+
+		t.Fatalf("unexpected lock holder: %v", lockErr.Holder)
+	}
+
+	// Once closed, the lock should be released, allowing the database to
+	// be opened again.
+	if err := cdb.Close(); err != nil {
+		t.Fatalf("unable to close channeldb: %v", err)
+	}
+	if fileExists(filepath.Join(tempDirName, lockFileName)) {
+		t.Fatalf("lock file not removed")
+	}
+	cdb, err = Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to reopen channeldb: %v", err)
+	}
+	if err := cdb.Close(); err != nil {
+		t.Fatalf("unable to close channeldb: %v", err)
+	}
+
+	// A lock left behind by a process on this machine that no longer
+	// exists, or by a prior process sharing our PID, is stale, and should
+	// be removed.
+	staleHolders := []*LockInfo{
+		{
+			PID:        math.MaxInt32,
+			Hostname:   hostname,
+			AcquiredAt: time.Now(),
+		},
+		{
+			PID:        os.Getpid(),
+			Hostname:   hostname,
+			AcquiredAt: time.Now(),
+		},
+	}
+	for _, holder := range staleHolders {
+		writeTestLock(t, tempDirName, holder)
+
+		cdb, err := Open(tempDirName)
+		if err != nil {
+			t.Fatalf("unable to open channeldb with stale lock "+
+				"held by %v: %v", describeHolder(holder), err)
+		}
+		if err := cdb.Close(); err != nil {
+			t.Fatalf("unable to close channeldb: %v", err)
+		}
+	}
+
+	// We can't tell whether a process on another machine still exists, so
+	// its lock should only be taken over when forced.
+	writeTestLock(t, tempDirName, &LockInfo{
+		PID:        1,
+		Hostname:   hostname + "-other",
+		AcquiredAt: time.Now(),
+	})
+	if _, err := Open(tempDirName); err == nil {
+		t.Fatalf("opened channeldb locked by another machine")
+	}
+	cdb, err = Open(tempDirName, OptionForceLock())
+	if err != nil {
+		t.Fatalf("unable to forcefully open channeldb: %v", err)
+	}
+	if err := cdb.Close(); err != nil {
+		t.Fatalf("unable to close channeldb: %v", err)
+	}
+}
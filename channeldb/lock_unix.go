@@ -0,0 +1,15 @@
+// +build !windows
+
+package channeldb
+
+import "syscall"
+
+// processExists returns true if a process with the passed PID exists on this
+// machine.
+func processExists(pid int) bool {
+	// Sending signal 0 performs the existence and permission checks of
+	// kill(2), without actually sending a signal. A permission error
+	// indicates that the process exists, but belongs to another user.
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
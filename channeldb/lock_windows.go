@@ -0,0 +1,19 @@
+// +build windows
+
+package channeldb
+
+import "os"
+
+// processExists returns true if a process with the passed PID exists on this
+// machine.
+func processExists(pid int) bool {
+	// On Windows, FindProcess opens a handle to the process, which fails
+	// if it doesn't exist.
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+
+	return true
+}
@@ -0,0 +1,132 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// outgoingIntentsKey is the key within a channel's bucket that stores the
+// updates we've sent to the remote party, but which aren't yet covered by a
+// commitment we've signed.
+var outgoingIntentsKey = []byte("outgoing-intents-key")
+
+// AppendOutgoingIntent records our intent to send the passed update message
+// to the remote party. It's to be called _before_ the update is sent, so
+// that, if we crash before signing a commitment covering the update, we're
+// able to decide whether the update must be re-applied and retransmitted
+// once the channel has been re-established. Once a commitment covering the
+// update is signed, the update is recorded within the CommitDiff, and the
+// intents should be cleared using ClearOutgoingIntents.
+func (c *OpenChannel) AppendOutgoingIntent(msg lnwire.Message) error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		chanBucket, err := updateChanBucket(tx, c.IdentityPub,
+			&c.FundingOutpoint, c.ChainHash)
+		if err != nil {
+			return err
+		}
+
+		var intents []lnwire.Message
+		intentBytes := chanBucket.Get(outgoingIntentsKey)
+		if intentBytes != nil {
+			r := bytes.NewReader(intentBytes)
+			intents, err = deserializeIntents(r)
+			if err != nil {
+				return err
+			}
+		}
+		intents = append(intents, msg)
+
+		var b bytes.Buffer
+		if err := serializeIntents(&b, intents); err != nil {
+			return err
+		}
+
+		return chanBucket.Put(outgoingIntentsKey, b.Bytes())
+	})
+}
+
+// OutgoingIntents returns the update messages we've recorded an intent to
+// send to the remote party since the last time the intents were cleared, in
+// the order they were recorded.
+func (c *OpenChannel) OutgoingIntents() ([]lnwire.Message, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var intents []lnwire.Message
+	err := c.Db.View(func(tx *bolt.Tx) error {
+		chanBucket, err := readChanBucket(tx, c.IdentityPub,
+			&c.FundingOutpoint, c.ChainHash)
+		if err != nil {
+			return err
+		}
+
+		intentBytes := chanBucket.Get(outgoingIntentsKey)
+		if intentBytes == nil {
+			return nil
+		}
+
+		intents, err = deserializeIntents(bytes.NewReader(intentBytes))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return intents, nil
+}
+
+// ClearOutgoingIntents removes all recorded outgoing intents. It's to be
+// called once a commitment covering the updates has been signed, or once
+// the intents have been acted upon after re-establishing the channel.
+func (c *OpenChannel) ClearOutgoingIntents() error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		chanBucket, err := updateChanBucket(tx, c.IdentityPub,
+			&c.FundingOutpoint, c.ChainHash)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Delete(outgoingIntentsKey)
+	})
+}
+
+func serializeIntents(w io.Writer, intents []lnwire.Message) error {
+	numIntents := uint16(len(intents))
+	if err := binary.Write(w, byteOrder, numIntents); err != nil {
+		return err
+	}
+
+	for _, msg := range intents {
+		if err := writeElement(w, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deserializeIntents(r io.Reader) ([]lnwire.Message, error) {
+	var numIntents uint16
+	if err := binary.Read(r, byteOrder, &numIntents); err != nil {
+		return nil, err
+	}
+
+	intents := make([]lnwire.Message, numIntents)
+	for i := range intents {
+		if err := readElement(r, &intents[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return intents, nil
+}
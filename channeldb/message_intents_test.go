@@ -0,0 +1,78 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestOutgoingIntents tests that outgoing intents are persisted in the order
+// they're appended, and that they can be cleared.
+func TestOutgoingIntents(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	channel, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := channel.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	// Initially, no intents should be recorded for the channel.
+	intents, err := channel.OutgoingIntents()
+	if err != nil {
+		t.Fatalf("unable to fetch intents: %v", err)
+	}
+	if len(intents) != 0 {
+		t.Fatalf("expected no intents, instead got %v", len(intents))
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
+	expectedIntents := []lnwire.Message{
+		&lnwire.UpdateFufillHTLC{
+			ChanID:          chanID,
+			ID:              1,
+			PaymentPreimage: [32]byte{1},
+		},
+		&lnwire.UpdateFailHTLC{
+			ChanID: chanID,
+			ID:     2,
+			Reason: lnwire.OpaqueReason([]byte{2, 2}),
+		},
+	}
+	for _, msg := range expectedIntents {
+		if err := channel.AppendOutgoingIntent(msg); err != nil {
+			t.Fatalf("unable to append intent: %v", err)
+		}
+	}
+
+	intents, err = channel.OutgoingIntents()
+	if err != nil {
+		t.Fatalf("unable to fetch intents: %v", err)
+	}
+	if !reflect.DeepEqual(intents, expectedIntents) {
+		t.Fatalf("intents don't match: expected %v, got %v",
+			spew.Sdump(expectedIntents), spew.Sdump(intents))
+	}
+
+	// Once cleared, no intents should be returned.
+	if err := channel.ClearOutgoingIntents(); err != nil {
+		t.Fatalf("unable to clear intents: %v", err)
+	}
+	intents, err = channel.OutgoingIntents()
+	if err != nil {
+		t.Fatalf("unable to fetch intents: %v", err)
+	}
+	if len(intents) != 0 {
+		t.Fatalf("expected no intents, instead got %v", len(intents))
+	}
+}
@@ -0,0 +1,184 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// paymentAttemptBucket is the name of the bucket which houses the
+	// payment attempts initiated by the switch. Each attempt is keyed by
+	// its payment hash, so that at most one attempt is tracked per hash.
+	paymentAttemptBucket = []byte("payment-attempts")
+
+	// ErrPaymentAttemptNotFound is returned when no payment attempt has
+	// been recorded for a payment hash.
+	ErrPaymentAttemptNotFound = fmt.Errorf("unable to locate payment " +
+		"attempt")
+)
+
+// PaymentAttemptStatus is the status of a payment attempt.
+type PaymentAttemptStatus uint8
+
+const (
+	// AttemptInFlight is the status of an attempt whose HTLC has been
+	// dispatched, but not yet settled or failed.
+	AttemptInFlight PaymentAttemptStatus = 1
+
+	// AttemptSucceeded is the status of an attempt whose HTLC has been
+	// settled, revealing the payment preimage.
+	AttemptSucceeded PaymentAttemptStatus = 2
+
+	// AttemptFailed is the status of an attempt whose HTLC has failed.
+	AttemptFailed PaymentAttemptStatus = 3
+)
+
+// String returns a human readable version of the status.
+func (s PaymentAttemptStatus) String() string {
+	switch s {
+	case AttemptInFlight:
+		return "InFlight"
+	case AttemptSucceeded:
+		return "Succeeded"
+	case AttemptFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// PaymentAttempt records the latest HTLC dispatched by the switch for a
+// payment initiated by the node itself, so that the outcome of the payment
+// is known even if the node restarts while the HTLC is in flight.
+type PaymentAttempt struct {
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// Amount is the value of the HTLC.
+	Amount lnwire.MilliSatoshi
+
+	// Status is the current status of the attempt.
+	Status PaymentAttemptStatus
+
+	// CreationTime is the time at which the HTLC was dispatched.
+	CreationTime time.Time
+
+	// OutgoingChanID and OutgoingHTLCID identify the HTLC within the
+	// channel it was added to. They're zero until the HTLC has been added
+	// to a channel.
+	OutgoingChanID lnwire.ShortChannelID
+	OutgoingHTLCID uint64
+
+	// Preimage is the payment preimage, which is only set once the attempt
+	// has succeeded.
+	Preimage [32]byte
+}
+
+// PutPaymentAttempt records the passed payment attempt, replacing any prior
+// attempt for the same payment hash.
+func (d *DB) PutPaymentAttempt(attempt *PaymentAttempt) error {
+	var b bytes.Buffer
+	if err := serializePaymentAttempt(&b, attempt); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		attempts, err := tx.CreateBucketIfNotExists(paymentAttemptBucket)
+		if err != nil {
+			return err
+		}
+
+		return attempts.Put(attempt.PaymentHash[:], b.Bytes())
+	})
+}
+
+// FetchPaymentAttempt returns the payment attempt recorded for the passed
+// payment hash. If none has been recorded, then ErrPaymentAttemptNotFound is
+// returned.
+func (d *DB) FetchPaymentAttempt(hash [32]byte) (*PaymentAttempt, error) {
+	var attempt *PaymentAttempt
+	err := d.View(func(tx *bolt.Tx) error {
+		attempts := tx.Bucket(paymentAttemptBucket)
+		if attempts == nil {
+			return ErrPaymentAttemptNotFound
+		}
+
+		v := attempts.Get(hash[:])
+		if v == nil {
+			return ErrPaymentAttemptNotFound
+		}
+
+		var err error
+		attempt, err = deserializePaymentAttempt(bytes.NewReader(v))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// FetchInFlightPaymentAttempts returns each of the recorded payment attempts
+// that are still in flight.
+func (d *DB) FetchInFlightPaymentAttempts() ([]*PaymentAttempt, error) {
+	var inFlight []*PaymentAttempt
+	err := d.View(func(tx *bolt.Tx) error {
+		attempts := tx.Bucket(paymentAttemptBucket)
+		if attempts == nil {
+			return nil
+		}
+
+		return attempts.ForEach(func(k, v []byte) error {
+			attempt, err := deserializePaymentAttempt(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+
+			if attempt.Status == AttemptInFlight {
+				inFlight = append(inFlight, attempt)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return inFlight, nil
+}
+
+func serializePaymentAttempt(w io.Writer, a *PaymentAttempt) error {
+	return writeElements(w,
+		a.PaymentHash, a.Amount, uint8(a.Status),
+		uint64(a.CreationTime.UnixNano()), a.OutgoingChanID,
+		a.OutgoingHTLCID, a.Preimage,
+	)
+}
+
+func deserializePaymentAttempt(r io.Reader) (*PaymentAttempt, error) {
+	var (
+		a            = &PaymentAttempt{}
+		status       uint8
+		creationTime uint64
+	)
+	err := readElements(r,
+		&a.PaymentHash, &a.Amount, &status, &creationTime,
+		&a.OutgoingChanID, &a.OutgoingHTLCID, &a.Preimage,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Status = PaymentAttemptStatus(status)
+	a.CreationTime = time.Unix(0, int64(creationTime))
+
+	return a, nil
+}
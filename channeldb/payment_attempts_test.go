@@ -0,0 +1,94 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestPaymentAttempts tests that payment attempts can be recorded, replaced,
+// and fetched by payment hash, and that only those in flight are returned as
+// such.
+func TestPaymentAttempts(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	// Fetching an attempt that was never recorded should fail.
+	var hash [32]byte
+	hash[0] = 1
+	_, err = cdb.FetchPaymentAttempt(hash)
+	if err != ErrPaymentAttemptNotFound {
+		t.Fatalf("expected ErrPaymentAttemptNotFound, got %v", err)
+	}
+
+	attempt := &PaymentAttempt{
+		PaymentHash:    hash,
+		Amount:         lnwire.MilliSatoshi(1000),
+		Status:         AttemptInFlight,
+		CreationTime:   time.Unix(0, time.Now().UnixNano()),
+		OutgoingChanID: lnwire.NewShortChanIDFromInt(5),
+		OutgoingHTLCID: 3,
+	}
+	if err := cdb.PutPaymentAttempt(attempt); err != nil {
+		t.Fatalf("unable to record attempt: %v", err)
+	}
+
+	other := *attempt
+	other.PaymentHash[0] = 2
+	other.Status = AttemptFailed
+	if err := cdb.PutPaymentAttempt(&other); err != nil {
+		t.Fatalf("unable to record attempt: %v", err)
+	}
+
+	fetched, err := cdb.FetchPaymentAttempt(hash)
+	if err != nil {
+		t.Fatalf("unable to fetch attempt: %v", err)
+	}
+	if !reflect.DeepEqual(attempt, fetched) {
+		t.Fatalf("attempts don't match: expected %v, got %v",
+			spew.Sdump(attempt), spew.Sdump(fetched))
+	}
+
+	// Only the first attempt is in flight.
+	inFlight, err := cdb.FetchInFlightPaymentAttempts()
+	if err != nil {
+		t.Fatalf("unable to fetch in-flight attempts: %v", err)
+	}
+	if len(inFlight) != 1 || !reflect.DeepEqual(inFlight[0], attempt) {
+		t.Fatalf("expected only %v in flight, got %v",
+			spew.Sdump(attempt), spew.Sdump(inFlight))
+	}
+
+	// Once the attempt succeeds, it's replaced, and no longer in flight.
+	attempt.Status = AttemptSucceeded
+	attempt.Preimage[0] = 7
+	if err := cdb.PutPaymentAttempt(attempt); err != nil {
+		t.Fatalf("unable to record attempt: %v", err)
+	}
+
+	fetched, err = cdb.FetchPaymentAttempt(hash)
+	if err != nil {
+		t.Fatalf("unable to fetch attempt: %v", err)
+	}
+	if !reflect.DeepEqual(attempt, fetched) {
+		t.Fatalf("attempts don't match: expected %v, got %v",
+			spew.Sdump(attempt), spew.Sdump(fetched))
+	}
+
+	inFlight, err = cdb.FetchInFlightPaymentAttempts()
+	if err != nil {
+		t.Fatalf("unable to fetch in-flight attempts: %v", err)
+	}
+	if len(inFlight) != 0 {
+		t.Fatalf("expected no attempts in flight, got %v",
+			len(inFlight))
+	}
+}
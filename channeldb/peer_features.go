@@ -0,0 +1,133 @@
+package channeldb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrPeerFeaturesNotFound is returned when the feature vectors of the
+	// target peer haven't been cached.
+	ErrPeerFeaturesNotFound = fmt.Errorf("no features cached for peer")
+
+	// peerFeaturesBucket is the name of the bucket which caches the
+	// feature vectors last announced by each of our channel peers within
+	// its init message. Each entry is keyed by the compressed public key
+	// of the peer, and consists of the unix timestamp at which the
+	// vectors were announced, followed by the global and local feature
+	// vectors in their wire encoding.
+	peerFeaturesBucket = []byte("peer-features")
+)
+
+// PeerFeatures holds the feature vectors last announced by a peer.
+type PeerFeatures struct {
+	// GlobalFeatures is the global feature vector announced by the peer.
+	GlobalFeatures *lnwire.RawFeatureVector
+
+	// LocalFeatures is the local feature vector announced by the peer.
+	LocalFeatures *lnwire.RawFeatureVector
+
+	// LastUpdate is the time the peer announced the feature vectors.
+	LastUpdate time.Time
+}
+
+// Fingerprint returns a short identifier of the exact set of features
+// announced by the peer. As implementations each set a distinctive
+// combination of feature bits, peers sharing a fingerprint are likely to run
+// the same implementation and version.
+func (f *PeerFeatures) Fingerprint() (string, error) {
+	var b bytes.Buffer
+	if err := f.GlobalFeatures.Encode(&b); err != nil {
+		return "", err
+	}
+	if err := f.LocalFeatures.Encode(&b); err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(b.Bytes())
+	return hex.EncodeToString(hash[:8]), nil
+}
+
+// PutPeerFeatures caches, or replaces, the feature vectors announced by the
+// target peer.
+func (d *DB) PutPeerFeatures(peer [33]byte, global,
+	local *lnwire.RawFeatureVector) error {
+
+	var b bytes.Buffer
+	var timestamp [8]byte
+	byteOrder.PutUint64(timestamp[:], uint64(time.Now().Unix()))
+	b.Write(timestamp[:])
+	if err := global.Encode(&b); err != nil {
+		return err
+	}
+	if err := local.Encode(&b); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(peerFeaturesBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(peer[:], b.Bytes())
+	})
+}
+
+// FetchPeerFeatures returns the feature vectors last announced by the target
+// peer. If none have been cached, then ErrPeerFeaturesNotFound is returned.
+func (d *DB) FetchPeerFeatures(peer [33]byte) (*PeerFeatures, error) {
+	var features *PeerFeatures
+	err := d.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(peerFeaturesBucket)
+		if bucket == nil {
+			return ErrPeerFeaturesNotFound
+		}
+
+		value := bucket.Get(peer[:])
+		if value == nil {
+			return ErrPeerFeaturesNotFound
+		}
+
+		var err error
+		features, err = deserializePeerFeatures(value)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
+
+// deserializePeerFeatures decodes a PeerFeatures from its on-disk format.
+func deserializePeerFeatures(value []byte) (*PeerFeatures, error) {
+	if len(value) < 8 {
+		return nil, fmt.Errorf("invalid peer features entry of length "+
+			"%v", len(value))
+	}
+
+	features := &PeerFeatures{
+		GlobalFeatures: lnwire.NewRawFeatureVector(),
+		LocalFeatures:  lnwire.NewRawFeatureVector(),
+		LastUpdate: time.Unix(
+			int64(byteOrder.Uint64(value[:8])), 0,
+		),
+	}
+
+	r := bytes.NewReader(value[8:])
+	if err := features.GlobalFeatures.Decode(r); err != nil {
+		return nil, err
+	}
+	if err := features.LocalFeatures.Decode(r); err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
@@ -0,0 +1,106 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestPeerFeatures tests that we're able to cache and replace the feature
+// vectors announced by our peers, and that the fingerprint of the vectors
+// only depends on the announced features.
+func TestPeerFeatures(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	var peer1, peer2 [33]byte
+	peer1[0], peer2[0] = 0x02, 0x03
+
+	// Before any features have been cached, looking up a peer's features
+	// should fail.
+	_, err = cdb.FetchPeerFeatures(peer1)
+	if err != ErrPeerFeaturesNotFound {
+		t.Fatalf("expected ErrPeerFeaturesNotFound, got %v", err)
+	}
+
+	// We'll cache the features of each peer, then replace those of the
+	// first.
+	global := lnwire.NewRawFeatureVector()
+	local1 := lnwire.NewRawFeatureVector(
+		lnwire.InitialRoutingSync, lnwire.StaticRemoteKeyOptional,
+	)
+	local2 := lnwire.NewRawFeatureVector(lnwire.TLVOnionPayloadRequired)
+
+	err = cdb.PutPeerFeatures(peer1, global, lnwire.NewRawFeatureVector())
+	if err != nil {
+		t.Fatalf("unable to cache features: %v", err)
+	}
+	if err := cdb.PutPeerFeatures(peer1, global, local1); err != nil {
+		t.Fatalf("unable to cache features: %v", err)
+	}
+	if err := cdb.PutPeerFeatures(peer2, global, local2); err != nil {
+		t.Fatalf("unable to cache features: %v", err)
+	}
+
+	features1, err := cdb.FetchPeerFeatures(peer1)
+	if err != nil {
+		t.Fatalf("unable to fetch features: %v", err)
+	}
+	if !reflect.DeepEqual(features1.LocalFeatures, local1) ||
+		!reflect.DeepEqual(features1.GlobalFeatures, global) {
+
+		t.Fatalf("features mismatch: expected %v, got %v",
+			spew.Sdump(local1), spew.Sdump(features1))
+	}
+	if features1.LastUpdate.IsZero() {
+		t.Fatalf("last update time wasn't set")
+	}
+
+	features2, err := cdb.FetchPeerFeatures(peer2)
+	if err != nil {
+		t.Fatalf("unable to fetch features: %v", err)
+	}
+	if !reflect.DeepEqual(features2.LocalFeatures, local2) {
+		t.Fatalf("features mismatch: expected %v, got %v",
+			spew.Sdump(local2), spew.Sdump(features2))
+	}
+
+	// Peers announcing different features should have different
+	// fingerprints, while the same features should always result in the
+	// same fingerprint.
+	fingerprint1, err := features1.Fingerprint()
+	if err != nil {
+		t.Fatalf("unable to compute fingerprint: %v", err)
+	}
+	fingerprint2, err := features2.Fingerprint()
+	if err != nil {
+		t.Fatalf("unable to compute fingerprint: %v", err)
+	}
+	if fingerprint1 == fingerprint2 {
+		t.Fatalf("distinct features share fingerprint %v",
+			fingerprint1)
+	}
+
+	same := &PeerFeatures{
+		GlobalFeatures: lnwire.NewRawFeatureVector(),
+		LocalFeatures: lnwire.NewRawFeatureVector(
+			lnwire.StaticRemoteKeyOptional,
+			lnwire.InitialRoutingSync,
+		),
+	}
+	fingerprint, err := same.Fingerprint()
+	if err != nil {
+		t.Fatalf("unable to compute fingerprint: %v", err)
+	}
+	if fingerprint != fingerprint1 {
+		t.Fatalf("expected fingerprint %v, got %v", fingerprint1,
+			fingerprint)
+	}
+}
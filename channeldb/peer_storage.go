@@ -0,0 +1,138 @@
+package channeldb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+var (
+	// ErrPeerStorageNotFound is returned when no blob has been stored on
+	// behalf of the target peer.
+	ErrPeerStorageNotFound = fmt.Errorf("no peer storage found for peer")
+
+	// peerStorageBucket is the name of the bucket which houses the blobs
+	// we store on behalf of our peers. Each blob is keyed by the
+	// compressed public key of the peer it belongs to, and is prefixed by
+	// the unix timestamp at which it was last updated.
+	peerStorageBucket = []byte("peer-storage")
+)
+
+// PeerStorage is an opaque blob that we store on behalf of a peer, so it can
+// be returned to the peer in the case that it loses its local state.
+type PeerStorage struct {
+	// Blob is the opaque blob provided by the peer.
+	Blob []byte
+
+	// LastUpdate is the time the peer last updated the blob.
+	LastUpdate time.Time
+}
+
+// PutPeerStorage stores, or replaces, the blob held on behalf of the target
+// peer.
+func (d *DB) PutPeerStorage(peer [33]byte, blob []byte) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(peerStorageBucket)
+		if err != nil {
+			return err
+		}
+
+		value := make([]byte, 8+len(blob))
+		byteOrder.PutUint64(value[:8], uint64(time.Now().Unix()))
+		copy(value[8:], blob)
+
+		return bucket.Put(peer[:], value)
+	})
+}
+
+// FetchPeerStorage returns the blob held on behalf of the target peer. If no
+// blob is held, then ErrPeerStorageNotFound is returned.
+func (d *DB) FetchPeerStorage(peer [33]byte) (*PeerStorage, error) {
+	var storage *PeerStorage
+	err := d.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(peerStorageBucket)
+		if bucket == nil {
+			return ErrPeerStorageNotFound
+		}
+
+		value := bucket.Get(peer[:])
+		if value == nil {
+			return ErrPeerStorageNotFound
+		}
+
+		var err error
+		storage, err = deserializePeerStorage(value)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return storage, nil
+}
+
+// DeletePeerStorage removes the blob held on behalf of the target peer, if
+// any.
+func (d *DB) DeletePeerStorage(peer [33]byte) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(peerStorageBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.Delete(peer[:])
+	})
+}
+
+// ListPeerStorage returns all blobs held on behalf of our peers, keyed by the
+// compressed public key of the peer.
+func (d *DB) ListPeerStorage() (map[[33]byte]*PeerStorage, error) {
+	storage := make(map[[33]byte]*PeerStorage)
+	err := d.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(peerStorageBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			if len(k) != 33 {
+				return nil
+			}
+
+			peerStorage, err := deserializePeerStorage(v)
+			if err != nil {
+				return err
+			}
+
+			var peer [33]byte
+			copy(peer[:], k)
+			storage[peer] = peerStorage
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return storage, nil
+}
+
+// deserializePeerStorage decodes a PeerStorage from its on-disk format.
+func deserializePeerStorage(value []byte) (*PeerStorage, error) {
+	if len(value) < 8 {
+		return nil, fmt.Errorf("invalid peer storage entry of length "+
+			"%v", len(value))
+	}
+
+	blob := make([]byte, len(value)-8)
+	copy(blob, value[8:])
+
+	return &PeerStorage{
+		Blob: blob,
+		LastUpdate: time.Unix(
+			int64(byteOrder.Uint64(value[:8])), 0,
+		),
+	}, nil
+}
@@ -0,0 +1,73 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestPeerStorage tests that we're able to store, replace, list and delete
+// the blobs held on behalf of our peers.
+func TestPeerStorage(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	var peer1, peer2 [33]byte
+	peer1[0], peer2[0] = 0x02, 0x03
+
+	// Before any blobs have been stored, looking up a peer's blob should
+	// fail.
+	if _, err := cdb.FetchPeerStorage(peer1); err != ErrPeerStorageNotFound {
+		t.Fatalf("expected ErrPeerStorageNotFound, got %v", err)
+	}
+
+	// We'll store a blob for each peer, then replace the blob of the
+	// first.
+	blob1, blob2 := []byte("blob1"), []byte("blob2")
+	if err := cdb.PutPeerStorage(peer1, []byte("stale")); err != nil {
+		t.Fatalf("unable to store blob: %v", err)
+	}
+	if err := cdb.PutPeerStorage(peer1, blob1); err != nil {
+		t.Fatalf("unable to store blob: %v", err)
+	}
+	if err := cdb.PutPeerStorage(peer2, blob2); err != nil {
+		t.Fatalf("unable to store blob: %v", err)
+	}
+
+	storage, err := cdb.FetchPeerStorage(peer1)
+	if err != nil {
+		t.Fatalf("unable to fetch blob: %v", err)
+	}
+	if !bytes.Equal(storage.Blob, blob1) {
+		t.Fatalf("blob mismatch: expected %x, got %x", blob1,
+			storage.Blob)
+	}
+	if storage.LastUpdate.IsZero() {
+		t.Fatalf("last update time wasn't set")
+	}
+
+	stored, err := cdb.ListPeerStorage()
+	if err != nil {
+		t.Fatalf("unable to list blobs: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("expected 2 blobs, got %v", len(stored))
+	}
+	if !bytes.Equal(stored[peer2].Blob, blob2) {
+		t.Fatalf("blob mismatch: expected %x, got %x", blob2,
+			stored[peer2].Blob)
+	}
+
+	// Finally, once the first peer's blob is deleted, it should no longer
+	// be found.
+	if err := cdb.DeletePeerStorage(peer1); err != nil {
+		t.Fatalf("unable to delete blob: %v", err)
+	}
+	if _, err := cdb.FetchPeerStorage(peer1); err != ErrPeerStorageNotFound {
+		t.Fatalf("expected ErrPeerStorageNotFound, got %v", err)
+	}
+}
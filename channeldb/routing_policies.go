@@ -0,0 +1,184 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrRoutingPoliciesNotFound is returned when no default routing
+	// policies have been recorded yet.
+	ErrRoutingPoliciesNotFound = fmt.Errorf("no default routing " +
+		"policies recorded")
+
+	// routingPoliciesBucket is the name of the bucket which stores the
+	// default routing policies our channels were last reconciled against.
+	routingPoliciesBucket = []byte("routing-policies")
+
+	// defaultRoutingPoliciesKey is the key within the routingPoliciesBucket
+	// under which the default routing policies are stored. They consist of
+	// the policy used for all peers, followed by the number of per-peer
+	// policies, and each per-peer policy prefixed by the compressed public
+	// key of the peer.
+	defaultRoutingPoliciesKey = []byte("default-routing-policies")
+)
+
+// RoutingPolicy is the fee and time lock policy used when forwarding HTLCs
+// over a channel.
+type RoutingPolicy struct {
+	// BaseFee is the base fee, in milli-satoshis, charged for forwarding
+	// an HTLC.
+	BaseFee lnwire.MilliSatoshi
+
+	// FeeRate is the proportional fee, in millionths of the forwarded
+	// amount, charged for forwarding an HTLC.
+	FeeRate lnwire.MilliSatoshi
+
+	// TimeLockDelta is the time lock delta required when forwarding an
+	// HTLC.
+	TimeLockDelta uint32
+}
+
+// DefaultRoutingPolicies is the set of default routing policies new channels
+// are announced with.
+type DefaultRoutingPolicies struct {
+	// Default is the routing policy used for channels with peers that
+	// don't have a policy of their own.
+	Default RoutingPolicy
+
+	// Peers holds the routing policies used for channels with particular
+	// peers, keyed by the compressed public key of the peer.
+	Peers map[[33]byte]RoutingPolicy
+}
+
+// ForPeer returns the default routing policy used for channels with the
+// passed peer.
+func (p *DefaultRoutingPolicies) ForPeer(peer [33]byte) RoutingPolicy {
+	if policy, ok := p.Peers[peer]; ok {
+		return policy
+	}
+
+	return p.Default
+}
+
+// PutDefaultRoutingPolicies records, or replaces, the default routing
+// policies our channels have been reconciled against.
+func (d *DB) PutDefaultRoutingPolicies(policies *DefaultRoutingPolicies) error {
+	var b bytes.Buffer
+	if err := writeRoutingPolicy(&b, &policies.Default); err != nil {
+		return err
+	}
+
+	var numPeers [4]byte
+	byteOrder.PutUint32(numPeers[:], uint32(len(policies.Peers)))
+	if _, err := b.Write(numPeers[:]); err != nil {
+		return err
+	}
+	for peer, policy := range policies.Peers {
+		if _, err := b.Write(peer[:]); err != nil {
+			return err
+		}
+		if err := writeRoutingPolicy(&b, &policy); err != nil {
+			return err
+		}
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(routingPoliciesBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(defaultRoutingPoliciesKey, b.Bytes())
+	})
+}
+
+// FetchDefaultRoutingPolicies returns the default routing policies our
+// channels were last reconciled against. If none have been recorded, then
+// ErrRoutingPoliciesNotFound is returned.
+func (d *DB) FetchDefaultRoutingPolicies() (*DefaultRoutingPolicies, error) {
+	var policies *DefaultRoutingPolicies
+	err := d.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(routingPoliciesBucket)
+		if bucket == nil {
+			return ErrRoutingPoliciesNotFound
+		}
+
+		value := bucket.Get(defaultRoutingPoliciesKey)
+		if value == nil {
+			return ErrRoutingPoliciesNotFound
+		}
+
+		var err error
+		policies, err = deserializeRoutingPolicies(
+			bytes.NewReader(value),
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// deserializeRoutingPolicies decodes a DefaultRoutingPolicies from its
+// on-disk format.
+func deserializeRoutingPolicies(r io.Reader) (*DefaultRoutingPolicies,
+	error) {
+
+	policies := &DefaultRoutingPolicies{
+		Peers: make(map[[33]byte]RoutingPolicy),
+	}
+	if err := readRoutingPolicy(r, &policies.Default); err != nil {
+		return nil, err
+	}
+
+	var numPeers [4]byte
+	if _, err := io.ReadFull(r, numPeers[:]); err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < byteOrder.Uint32(numPeers[:]); i++ {
+		var peer [33]byte
+		if _, err := io.ReadFull(r, peer[:]); err != nil {
+			return nil, err
+		}
+
+		var policy RoutingPolicy
+		if err := readRoutingPolicy(r, &policy); err != nil {
+			return nil, err
+		}
+		policies.Peers[peer] = policy
+	}
+
+	return policies, nil
+}
+
+// writeRoutingPolicy serializes a RoutingPolicy to the passed io.Writer.
+func writeRoutingPolicy(w io.Writer, policy *RoutingPolicy) error {
+	var b [20]byte
+	byteOrder.PutUint64(b[:8], uint64(policy.BaseFee))
+	byteOrder.PutUint64(b[8:16], uint64(policy.FeeRate))
+	byteOrder.PutUint32(b[16:], policy.TimeLockDelta)
+
+	_, err := w.Write(b[:])
+	return err
+}
+
+// readRoutingPolicy deserializes a RoutingPolicy from the passed io.Reader.
+func readRoutingPolicy(r io.Reader, policy *RoutingPolicy) error {
+	var b [20]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return err
+	}
+
+	policy.BaseFee = lnwire.MilliSatoshi(byteOrder.Uint64(b[:8]))
+	policy.FeeRate = lnwire.MilliSatoshi(byteOrder.Uint64(b[8:16]))
+	policy.TimeLockDelta = byteOrder.Uint32(b[16:])
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestDefaultRoutingPolicies tests that the default routing policies are
+// persisted, that later policies replace prior ones, and that the policy of
+// a peer falls back to the default for peers without a policy of their own.
+func TestDefaultRoutingPolicies(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	// Initially, no policies should be recorded.
+	_, err = cdb.FetchDefaultRoutingPolicies()
+	if err != ErrRoutingPoliciesNotFound {
+		t.Fatalf("expected ErrRoutingPoliciesNotFound, got %v", err)
+	}
+
+	peer := [33]byte{2, 1}
+	peerPolicy := RoutingPolicy{
+		BaseFee:       2000,
+		FeeRate:       10,
+		TimeLockDelta: 40,
+	}
+	for _, expected := range []*DefaultRoutingPolicies{
+		{
+			Default: RoutingPolicy{
+				BaseFee:       1000,
+				FeeRate:       1,
+				TimeLockDelta: 144,
+			},
+			Peers: map[[33]byte]RoutingPolicy{
+				peer: peerPolicy,
+			},
+		},
+		{
+			Default: RoutingPolicy{
+				BaseFee:       500,
+				FeeRate:       2,
+				TimeLockDelta: 80,
+			},
+			Peers: map[[33]byte]RoutingPolicy{},
+		},
+	} {
+		if err := cdb.PutDefaultRoutingPolicies(expected); err != nil {
+			t.Fatalf("unable to put routing policies: %v", err)
+		}
+
+		policies, err := cdb.FetchDefaultRoutingPolicies()
+		if err != nil {
+			t.Fatalf("unable to fetch routing policies: %v", err)
+		}
+		if !reflect.DeepEqual(policies, expected) {
+			t.Fatalf("expected routing policies %v, got %v",
+				expected, policies)
+		}
+
+		expectedPeerPolicy := expected.Default
+		if _, ok := expected.Peers[peer]; ok {
+			expectedPeerPolicy = peerPolicy
+		}
+		if policies.ForPeer(peer) != expectedPeerPolicy {
+			t.Fatalf("expected peer policy %v, got %v",
+				expectedPeerPolicy, policies.ForPeer(peer))
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package channelnotifier
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// ErrChannelNotifierShuttingDown is returned when a subscription is requested
+// from a ChannelNotifier which is shutting down.
+var ErrChannelNotifierShuttingDown = errors.New("channel notifier shutting " +
+	"down")
+
+// OpenChannelEvent is sent once a channel has been fully confirmed, and is
+// now open.
+type OpenChannelEvent struct {
+	// Channel is the newly opened channel.
+	Channel *channeldb.OpenChannel
+}
+
+// ActiveChannelEvent is sent once the link of a channel has been added to the
+// switch, after which the channel is able to forward HTLCs.
+type ActiveChannelEvent struct {
+	// ChannelPoint is the funding outpoint of the channel.
+	ChannelPoint *wire.OutPoint
+}
+
+// InactiveChannelEvent is sent once the link of a channel has been removed
+// from the switch, either as the peer has disconnected, or as the channel is
+// being closed.
+type InactiveChannelEvent struct {
+	// ChannelPoint is the funding outpoint of the channel.
+	ChannelPoint *wire.OutPoint
+}
+
+// ClosedChannelEvent is sent once a channel has been fully closed, meaning
+// its closing transaction has confirmed, and any outputs which required
+// on-chain resolution have been resolved.
+type ClosedChannelEvent struct {
+	// CloseSummary is the summary of the channel's closure.
+	CloseSummary *channeldb.ChannelCloseSummary
+}
+
+// ChannelEventSubscription is an intent to receive the channel events
+// dispatched by the ChannelNotifier. Events are delivered in the order in
+// which they occurred, and are never dropped, regardless of how quickly they
+// are consumed.
+type ChannelEventSubscription struct {
+	// Updates is a receive only channel over which the events will be
+	// sent. Each event is one of: *OpenChannelEvent, *ActiveChannelEvent,
+	// *InactiveChannelEvent or *ClosedChannelEvent. The channel is closed
+	// once the subscription is cancelled, or the notifier is shut down.
+	Updates <-chan interface{}
+
+	// Cancel is a function closure that should be executed once the
+	// client no longer wishes to receive events.
+	Cancel func()
+}
+
+// eventClient couples the queue of events which have yet to be delivered to
+// a subscriber, with the channel over which they're delivered.
+type eventClient struct {
+	updates chan interface{}
+
+	queue     []interface{}
+	queueCond *sync.Cond
+	queueMtx  sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newEventClient creates a new client, and launches the goroutine which
+// delivers its events.
+func newEventClient() *eventClient {
+	c := &eventClient{
+		updates: make(chan interface{}),
+		quit:    make(chan struct{}),
+	}
+	c.queueCond = sync.NewCond(&c.queueMtx)
+
+	c.wg.Add(1)
+	go c.deliverEvents()
+
+	return c
+}
+
+// enqueue adds the passed event to the client's queue, without blocking.
+func (c *eventClient) enqueue(event interface{}) {
+	c.queueMtx.Lock()
+	c.queue = append(c.queue, event)
+	c.queueMtx.Unlock()
+
+	c.queueCond.Signal()
+}
+
+// deliverEvents sends the client's queued events over its updates channel,
+// one by one, until the client is stopped.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *eventClient) deliverEvents() {
+	defer c.wg.Done()
+	defer close(c.updates)
+
+	for {
+		c.queueMtx.Lock()
+		for len(c.queue) == 0 {
+			select {
+			case <-c.quit:
+				c.queueMtx.Unlock()
+				return
+			default:
+			}
+
+			c.queueCond.Wait()
+		}
+
+		event := c.queue[0]
+		c.queue[0] = nil
+		c.queue = c.queue[1:]
+		c.queueMtx.Unlock()
+
+		select {
+		case c.updates <- event:
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// stop stops the client, and waits for its goroutine to exit.
+func (c *eventClient) stop() {
+	close(c.quit)
+
+	c.queueMtx.Lock()
+	c.queueCond.Signal()
+	c.queueMtx.Unlock()
+
+	c.wg.Wait()
+}
+
+// ChannelNotifier is a subsystem which dispatches events to its subscribers
+// whenever the state of one of our channels changes: a channel has been
+// opened, becomes active or inactive, or has been closed. It's used to
+// provide the SubscribeChannelEvents RPC, and may be used by any internal
+// subsystem that needs to react to these events.
+type ChannelNotifier struct {
+	started uint32
+	stopped uint32
+
+	chanDB *channeldb.DB
+
+	clients      map[uint64]*eventClient
+	nextClientID uint64
+	shuttingDown bool
+	clientsMtx   sync.Mutex
+}
+
+// New creates a new ChannelNotifier. The channel database is used to look up
+// the close summaries of closed channels.
+func New(chanDB *channeldb.DB) *ChannelNotifier {
+	return &ChannelNotifier{
+		chanDB:  chanDB,
+		clients: make(map[uint64]*eventClient),
+	}
+}
+
+// Start starts the ChannelNotifier.
+func (c *ChannelNotifier) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	log.Tracef("ChannelNotifier starting")
+
+	return nil
+}
+
+// Stop signals the ChannelNotifier to shut down, closing the updates channel
+// of each of its subscriptions.
+func (c *ChannelNotifier) Stop() error {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Tracef("ChannelNotifier shutting down")
+
+	c.clientsMtx.Lock()
+	c.shuttingDown = true
+	clients := c.clients
+	c.clients = make(map[uint64]*eventClient)
+	c.clientsMtx.Unlock()
+
+	for _, client := range clients {
+		client.stop()
+	}
+
+	return nil
+}
+
+// SubscribeChannelEvents returns a subscription to all channel events
+// dispatched from this point onwards.
+func (c *ChannelNotifier) SubscribeChannelEvents() (*ChannelEventSubscription,
+	error) {
+
+	c.clientsMtx.Lock()
+	defer c.clientsMtx.Unlock()
+
+	if c.shuttingDown {
+		return nil, ErrChannelNotifierShuttingDown
+	}
+
+	clientID := c.nextClientID
+	c.nextClientID++
+
+	client := newEventClient()
+	c.clients[clientID] = client
+
+	log.Debugf("New channel event subscription, client %v", clientID)
+
+	return &ChannelEventSubscription{
+		Updates: client.updates,
+		Cancel: func() {
+			c.clientsMtx.Lock()
+			client, ok := c.clients[clientID]
+			delete(c.clients, clientID)
+			c.clientsMtx.Unlock()
+
+			if ok {
+				client.stop()
+			}
+		},
+	}, nil
+}
+
+// notifyClients queues the passed event for delivery to each subscriber.
+func (c *ChannelNotifier) notifyClients(event interface{}) {
+	c.clientsMtx.Lock()
+	defer c.clientsMtx.Unlock()
+
+	for _, client := range c.clients {
+		client.enqueue(event)
+	}
+}
+
+// NotifyOpenChannelEvent notifies the subscribers that the channel with the
+// passed funding outpoint has been fully confirmed, and is now open. The
+// channel is fetched from the database, so this should only be called once
+// the channel has been marked as open.
+func (c *ChannelNotifier) NotifyOpenChannelEvent(chanPoint wire.OutPoint) {
+	channels, err := c.chanDB.FetchAllChannels()
+	if err != nil {
+		log.Errorf("Unable to fetch ChannelPoint(%v): %v", chanPoint,
+			err)
+		return
+	}
+
+	for _, channel := range channels {
+		if channel.FundingOutpoint != chanPoint {
+			continue
+		}
+
+		log.Debugf("Notifying open of ChannelPoint(%v)", chanPoint)
+
+		c.notifyClients(&OpenChannelEvent{Channel: channel})
+		return
+	}
+
+	log.Errorf("Unable to find open ChannelPoint(%v)", chanPoint)
+}
+
+// NotifyActiveChannelEvent notifies the subscribers that the channel with the
+// passed funding outpoint is now active.
+func (c *ChannelNotifier) NotifyActiveChannelEvent(chanPoint wire.OutPoint) {
+	log.Debugf("Notifying ChannelPoint(%v) is active", chanPoint)
+
+	c.notifyClients(&ActiveChannelEvent{ChannelPoint: &chanPoint})
+}
+
+// NotifyInactiveChannelEvent notifies the subscribers that the channel with
+// the passed funding outpoint is now inactive.
+func (c *ChannelNotifier) NotifyInactiveChannelEvent(chanPoint wire.OutPoint) {
+	log.Debugf("Notifying ChannelPoint(%v) is inactive", chanPoint)
+
+	c.notifyClients(&InactiveChannelEvent{ChannelPoint: &chanPoint})
+}
+
+// NotifyClosedChannelEvent notifies the subscribers that the channel with the
+// passed funding outpoint has been fully closed. The channel's close summary
+// is fetched from the database, so this should only be called once the
+// channel has been marked as fully closed.
+func (c *ChannelNotifier) NotifyClosedChannelEvent(chanPoint wire.OutPoint) {
+	closeSummary, err := c.chanDB.FetchClosedChannel(&chanPoint)
+	if err != nil {
+		log.Errorf("Unable to fetch close summary of "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		return
+	}
+
+	log.Debugf("Notifying close of ChannelPoint(%v)", chanPoint)
+
+	c.notifyClients(&ClosedChannelEvent{CloseSummary: closeSummary})
+}
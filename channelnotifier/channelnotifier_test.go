@@ -0,0 +1,112 @@
+package channelnotifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// receiveEvent returns the next event delivered over the passed subscription.
+func receiveEvent(t *testing.T, sub *ChannelEventSubscription) interface{} {
+	select {
+	case event, ok := <-sub.Updates:
+		if !ok {
+			t.Fatalf("subscription unexpectedly closed")
+		}
+		return event
+
+	case <-time.After(time.Second * 5):
+		t.Fatalf("event not received")
+	}
+
+	return nil
+}
+
+// TestChannelNotifier tests that the events dispatched by the ChannelNotifier
+// are delivered to each of its subscribers in order, and that subscriptions
+// are closed once cancelled, or once the notifier is stopped.
+func TestChannelNotifier(t *testing.T) {
+	t.Parallel()
+
+	notifier := New(nil)
+	if err := notifier.Start(); err != nil {
+		t.Fatalf("unable to start notifier: %v", err)
+	}
+
+	sub1, err := notifier.SubscribeChannelEvents()
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	sub2, err := notifier.SubscribeChannelEvents()
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+
+	// Dispatch a series of events before either subscriber consumes any
+	// of them. The notifier shouldn't block, and each subscriber should
+	// receive all of the events in the order they were dispatched.
+	const numEvents = 50
+	for i := 0; i < numEvents; i++ {
+		chanPoint := wire.OutPoint{Index: uint32(i)}
+		if i%2 == 0 {
+			notifier.NotifyActiveChannelEvent(chanPoint)
+		} else {
+			notifier.NotifyInactiveChannelEvent(chanPoint)
+		}
+	}
+
+	for _, sub := range []*ChannelEventSubscription{sub1, sub2} {
+		for i := 0; i < numEvents; i++ {
+			var chanPoint *wire.OutPoint
+			switch event := receiveEvent(t, sub).(type) {
+			case *ActiveChannelEvent:
+				if i%2 != 0 {
+					t.Fatalf("expected inactive event %v", i)
+				}
+				chanPoint = event.ChannelPoint
+
+			case *InactiveChannelEvent:
+				if i%2 == 0 {
+					t.Fatalf("expected active event %v", i)
+				}
+				chanPoint = event.ChannelPoint
+
+			default:
+				t.Fatalf("unexpected event: %T", event)
+			}
+
+			if chanPoint.Index != uint32(i) {
+				t.Fatalf("expected event %v, got event for "+
+					"ChannelPoint(%v)", i, chanPoint)
+			}
+		}
+	}
+
+	// Once cancelled, the first subscription should be closed, while the
+	// second one should still receive events.
+	sub1.Cancel()
+	if _, ok := <-sub1.Updates; ok {
+		t.Fatalf("cancelled subscription not closed")
+	}
+
+	notifier.NotifyActiveChannelEvent(wire.OutPoint{})
+	if _, ok := receiveEvent(t, sub2).(*ActiveChannelEvent); !ok {
+		t.Fatalf("expected active event")
+	}
+
+	// Stopping the notifier should close the remaining subscription, and
+	// prevent any new ones.
+	if err := notifier.Stop(); err != nil {
+		t.Fatalf("unable to stop notifier: %v", err)
+	}
+	if _, ok := <-sub2.Updates; ok {
+		t.Fatalf("subscription not closed on shutdown")
+	}
+	if _, err := notifier.SubscribeChannelEvents(); err == nil {
+		t.Fatalf("subscribed to stopped notifier")
+	}
+
+	// Cancelling a subscription after shutdown should be a no-op.
+	sub2.Cancel()
+}
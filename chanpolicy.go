@@ -0,0 +1,165 @@
+package main
+
+import (
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// outgoingChanPolicy is the routing policy we last advertised for one of our
+// channels.
+type outgoingChanPolicy struct {
+	// chanPoint is the funding outpoint of the channel.
+	chanPoint wire.OutPoint
+
+	// peer is the compressed public key of the remote party of the
+	// channel.
+	peer [33]byte
+
+	// policy is the routing policy within the last ChannelUpdate we
+	// broadcast for the channel.
+	policy channeldb.RoutingPolicy
+}
+
+// defaultPolicies returns the default routing policies new channels
+// are announced with, as determined by our configuration.
+func (c *chainControl) defaultPolicies() *channeldb.DefaultRoutingPolicies {
+	policies := &channeldb.DefaultRoutingPolicies{
+		Default: channeldb.RoutingPolicy{
+			BaseFee:       c.routingPolicy.BaseFee,
+			FeeRate:       c.routingPolicy.FeeRate,
+			TimeLockDelta: c.routingPolicy.TimeLockDelta,
+		},
+		Peers: make(map[[33]byte]channeldb.RoutingPolicy),
+	}
+	for peer, policy := range c.peerRoutingPolicies {
+		policies.Peers[peer] = channeldb.RoutingPolicy(policy)
+	}
+
+	return policies
+}
+
+// staleChanPolicies returns the channels whose advertised routing policies
+// have gone stale, grouped by the policy each is to be updated to. The policy
+// of a channel is stale if it still matches the prior default policy for its
+// peer, and the current default policy differs from it. Channels whose policy
+// has been changed since, such as through UpdateChannelPolicy, are left
+// untouched.
+func staleChanPolicies(prior, current *channeldb.DefaultRoutingPolicies,
+	chans []outgoingChanPolicy) (
+	stale map[channeldb.RoutingPolicy][]wire.OutPoint) {
+
+	stale = make(map[channeldb.RoutingPolicy][]wire.OutPoint)
+	for _, channel := range chans {
+		if channel.policy != prior.ForPeer(channel.peer) {
+			continue
+		}
+
+		policy := current.ForPeer(channel.peer)
+		if channel.policy == policy {
+			continue
+		}
+
+		stale[policy] = append(stale[policy], channel.chanPoint)
+	}
+
+	return stale
+}
+
+// reconcileChanPolicies brings the routing policies of our channels in line
+// with the default routing policies of our configuration, if those have
+// changed since we last started. Channels still using the prior defaults are
+// moved over to the current ones, by broadcasting a new ChannelUpdate for
+// each, and applying the new policy to its link. The defaults are recorded
+// afterwards, so that we're able to tell which channels follow them the next
+// time around. If no defaults have been recorded yet, then there's nothing to
+// compare against, so the current ones are simply recorded.
+func (s *server) reconcileChanPolicies() error {
+	current := s.cc.defaultPolicies()
+
+	prior, err := s.chanDB.FetchDefaultRoutingPolicies()
+	switch {
+	case err == channeldb.ErrRoutingPoliciesNotFound:
+		return s.chanDB.PutDefaultRoutingPolicies(current)
+
+	case err != nil:
+		return err
+	}
+
+	var chans []outgoingChanPolicy
+	selfKey := s.identityPriv.PubKey()
+	err = s.chanRouter.ForAllOutgoingChannels(
+		func(info *channeldb.ChannelEdgeInfo,
+			edge *channeldb.ChannelEdgePolicy) error {
+
+			// If we haven't yet advertised a policy for the
+			// channel, then it'll be announced with the current
+			// defaults once we do.
+			if edge == nil {
+				return nil
+			}
+
+			peerKey := info.NodeKey1
+			if peerKey.IsEqual(selfKey) {
+				peerKey = info.NodeKey2
+			}
+
+			channel := outgoingChanPolicy{
+				chanPoint: info.ChannelPoint,
+				policy: channeldb.RoutingPolicy{
+					BaseFee: edge.FeeBaseMSat,
+					FeeRate: edge.FeeProportionalMillionths,
+					TimeLockDelta: uint32(
+						edge.TimeLockDelta,
+					),
+				},
+			}
+			copy(channel.peer[:], peerKey.SerializeCompressed())
+			chans = append(chans, channel)
+
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	stale := staleChanPolicies(prior, current, chans)
+	for policy, chanPoints := range stale {
+		srvrLog.Infof("Updating routing policy of %v channels to the "+
+			"new default: base_fee=%v, fee_rate=%v, "+
+			"time_lock_delta=%v", len(chanPoints), policy.BaseFee,
+			policy.FeeRate, policy.TimeLockDelta)
+
+		// We'll first broadcast the new policy, and only apply it to
+		// the links of the channels once that succeeds, so that the
+		// policy we enforce doesn't diverge from the one we
+		// advertise.
+		err := s.authGossiper.PropagateChanPolicyUpdate(
+			routing.ChannelPolicy{
+				FeeSchema: routing.FeeSchema{
+					BaseFee: policy.BaseFee,
+					FeeRate: uint32(policy.FeeRate),
+				},
+				TimeLockDelta: policy.TimeLockDelta,
+			}, chanPoints...,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = s.htlcSwitch.UpdateForwardingPolicies(
+			htlcswitch.ForwardingPolicy{
+				BaseFee:       policy.BaseFee,
+				FeeRate:       policy.FeeRate,
+				TimeLockDelta: policy.TimeLockDelta,
+			}, chanPoints...,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.chanDB.PutDefaultRoutingPolicies(current)
+}
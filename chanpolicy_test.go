@@ -0,0 +1,92 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestStaleChanPolicies ensures that only the channels still using the prior
+// default policy for their peer are deemed stale, and only if the current
+// default policy for the peer differs from it.
+func TestStaleChanPolicies(t *testing.T) {
+	t.Parallel()
+
+	oldDefault := channeldb.RoutingPolicy{
+		BaseFee:       1000,
+		FeeRate:       1,
+		TimeLockDelta: 144,
+	}
+	newDefault := channeldb.RoutingPolicy{
+		BaseFee:       500,
+		FeeRate:       2,
+		TimeLockDelta: 144,
+	}
+	peerPolicy := channeldb.RoutingPolicy{
+		BaseFee:       2000,
+		FeeRate:       10,
+		TimeLockDelta: 40,
+	}
+	custom := channeldb.RoutingPolicy{
+		BaseFee:       1,
+		FeeRate:       1,
+		TimeLockDelta: 20,
+	}
+
+	// The policy of the peer with its own default policy remains the
+	// same, while the default for all other peers changes.
+	peer := [33]byte{2, 1}
+	prior := &channeldb.DefaultRoutingPolicies{
+		Default: oldDefault,
+		Peers:   map[[33]byte]channeldb.RoutingPolicy{peer: peerPolicy},
+	}
+	current := &channeldb.DefaultRoutingPolicies{
+		Default: newDefault,
+		Peers:   map[[33]byte]channeldb.RoutingPolicy{peer: peerPolicy},
+	}
+
+	chans := []outgoingChanPolicy{
+		{
+			chanPoint: wire.OutPoint{Index: 0},
+			peer:      [33]byte{3},
+			policy:    oldDefault,
+		},
+		{
+			chanPoint: wire.OutPoint{Index: 1},
+			peer:      [33]byte{3},
+			policy:    custom,
+		},
+		{
+			chanPoint: wire.OutPoint{Index: 2},
+			peer:      peer,
+			policy:    peerPolicy,
+		},
+		{
+			chanPoint: wire.OutPoint{Index: 3},
+			peer:      [33]byte{4},
+			policy:    newDefault,
+		},
+		{
+			chanPoint: wire.OutPoint{Index: 4},
+			peer:      [33]byte{4},
+			policy:    oldDefault,
+		},
+	}
+
+	stale := staleChanPolicies(prior, current, chans)
+	expected := map[channeldb.RoutingPolicy][]wire.OutPoint{
+		newDefault: {{Index: 0}, {Index: 4}},
+	}
+	if !reflect.DeepEqual(stale, expected) {
+		t.Fatalf("expected stale policies %v, got %v", expected, stale)
+	}
+
+	// If the defaults haven't changed at all, then no channel should be
+	// deemed stale.
+	stale = staleChanPolicies(prior, prior, chans)
+	if len(stale) != 0 {
+		t.Fatalf("expected no stale policies, got %v", stale)
+	}
+}
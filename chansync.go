@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// localChanSyncMsg returns the ChannelReestablish message we'd send to the
+// remote party of the channel upon reconnection, given its persisted state.
+// It mirrors the message created by the channel's link, without requiring the
+// link to be active.
+func localChanSyncMsg(
+	channel *channeldb.OpenChannel) (*lnwire.ChannelReestablish, error) {
+
+	localHeight := channel.LocalCommitment.CommitHeight
+	remoteTailHeight := channel.RemoteCommitment.CommitHeight
+
+	var lastCommitSecret [32]byte
+	if remoteTailHeight != 0 {
+		remoteSecret, err := channel.RevocationStore.LookUp(
+			remoteTailHeight - 1,
+		)
+		if err != nil {
+			return nil, err
+		}
+		lastCommitSecret = [32]byte(*remoteSecret)
+	}
+
+	currentCommitSecret, err := channel.RevocationProducer.AtIndex(
+		localHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnwire.ChannelReestablish{
+		ChanID: lnwire.NewChanIDFromOutPoint(
+			&channel.FundingOutpoint,
+		),
+		NextLocalCommitHeight:  localHeight + 1,
+		RemoteCommitTailHeight: remoteTailHeight,
+		LastRemoteCommitSecret: lastCommitSecret,
+		LocalUnrevokedCommitPoint: lnwallet.ComputeCommitmentPoint(
+			currentCommitSecret[:],
+		),
+	}, nil
+}
+
+// assessChanSync determines whether the commitment chains of the channel can
+// be re-synchronized, given the ChannelReestablish message received from the
+// remote party, and the pending remote commitment we've signed, if any. It
+// applies the same checks the link does upon reconnection, without mutating
+// the state of the channel, and returns whether the channel is recoverable
+// along with a description of the outcome.
+func assessChanSync(channel *channeldb.OpenChannel,
+	pendingRemote *channeldb.CommitDiff,
+	msg *lnwire.ChannelReestablish) (bool, string, error) {
+
+	localTailHeight := channel.LocalCommitment.CommitHeight
+	remoteTipHeight := channel.RemoteCommitment.CommitHeight
+	if pendingRemote != nil {
+		remoteTipHeight = pendingRemote.Commitment.CommitHeight
+	}
+
+	// If the remote party included its last commitment secret, then it
+	// must match the one we've revealed for the height it claims to be at.
+	hasRecoveryOptions := msg.LocalUnrevokedCommitPoint != nil
+	if hasRecoveryOptions && msg.RemoteCommitTailHeight != 0 {
+		secret, err := channel.RevocationProducer.AtIndex(
+			msg.RemoteCommitTailHeight - 1,
+		)
+		if err != nil {
+			return false, "", err
+		}
+		if !bytes.Equal(secret[:], msg.LastRemoteCommitSecret[:]) {
+			return false, "the remote party sent an incorrect " +
+				"commitment secret", nil
+		}
+	}
+
+	oweCommitment := pendingRemote != nil &&
+		msg.NextLocalCommitHeight == remoteTipHeight
+	oweRevocation := localTailHeight == msg.RemoteCommitTailHeight+1
+
+	var status string
+	switch {
+	case oweRevocation:
+		status = "we owe the remote party our last revocation"
+
+	case msg.RemoteCommitTailHeight > localTailHeight &&
+		hasRecoveryOptions:
+
+		return false, fmt.Sprintf("the remote party expects our "+
+			"commitment at height %v, ahead of ours at height %v, "+
+			"so we've likely lost state",
+			msg.RemoteCommitTailHeight, localTailHeight), nil
+
+	case localTailHeight != msg.RemoteCommitTailHeight:
+		return false, fmt.Sprintf("the remote party expects our "+
+			"commitment at height %v, but ours is at height %v",
+			msg.RemoteCommitTailHeight, localTailHeight), nil
+	}
+
+	switch {
+	case oweCommitment:
+		if status != "" {
+			status += ", and "
+		}
+		status += fmt.Sprintf("we owe the remote party our signature "+
+			"for its commitment at height %v, along with %v "+
+			"updates", remoteTipHeight,
+			len(pendingRemote.LogUpdates))
+
+	case remoteTipHeight+1 != msg.NextLocalCommitHeight:
+		return false, fmt.Sprintf("the remote party expects its next "+
+			"commitment at height %v, but we expect it at height "+
+			"%v", msg.NextLocalCommitHeight, remoteTipHeight+1), nil
+	}
+
+	if status == "" {
+		status = "the commitment chains are in sync"
+	}
+
+	return true, status, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/shachain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// TestAssessChanSync ensures that a channel is only deemed recoverable if the
+// ChannelReestablish message of the remote party is consistent with our
+// commitment chains, in which case we may owe it a revocation, a commitment,
+// or nothing at all.
+func TestAssessChanSync(t *testing.T) {
+	t.Parallel()
+
+	producer := shachain.NewRevocationProducer(chainhash.Hash{1})
+	channel := &channeldb.OpenChannel{
+		LocalCommitment: channeldb.ChannelCommitment{
+			CommitHeight: 5,
+		},
+		RemoteCommitment: channeldb.ChannelCommitment{
+			CommitHeight: 5,
+		},
+		RevocationProducer: producer,
+	}
+	pendingRemote := &channeldb.CommitDiff{
+		Commitment: channeldb.ChannelCommitment{
+			CommitHeight: 6,
+		},
+	}
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	secret, err := producer.AtIndex(6)
+	if err != nil {
+		t.Fatalf("unable to derive secret: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		pendingRemote *channeldb.CommitDiff
+		msg           *lnwire.ChannelReestablish
+		recoverable   bool
+	}{
+		{
+			name: "synced",
+			msg: &lnwire.ChannelReestablish{
+				NextLocalCommitHeight:  6,
+				RemoteCommitTailHeight: 5,
+			},
+			recoverable: true,
+		},
+		{
+			name: "owe revocation",
+			msg: &lnwire.ChannelReestablish{
+				NextLocalCommitHeight:  6,
+				RemoteCommitTailHeight: 4,
+			},
+			recoverable: true,
+		},
+		{
+			name:          "owe commitment",
+			pendingRemote: pendingRemote,
+			msg: &lnwire.ChannelReestablish{
+				NextLocalCommitHeight:  6,
+				RemoteCommitTailHeight: 5,
+			},
+			recoverable: true,
+		},
+		{
+			name: "remote commitment mismatch",
+			msg: &lnwire.ChannelReestablish{
+				NextLocalCommitHeight:  9,
+				RemoteCommitTailHeight: 5,
+			},
+		},
+		{
+			name: "local commitment mismatch",
+			msg: &lnwire.ChannelReestablish{
+				NextLocalCommitHeight:  6,
+				RemoteCommitTailHeight: 2,
+			},
+		},
+		{
+			name: "data loss",
+			msg: &lnwire.ChannelReestablish{
+				NextLocalCommitHeight:     6,
+				RemoteCommitTailHeight:    7,
+				LastRemoteCommitSecret:    [32]byte(*secret),
+				LocalUnrevokedCommitPoint: priv.PubKey(),
+			},
+		},
+		{
+			name: "incorrect commit secret",
+			msg: &lnwire.ChannelReestablish{
+				NextLocalCommitHeight:     6,
+				RemoteCommitTailHeight:    5,
+				LastRemoteCommitSecret:    [32]byte{1},
+				LocalUnrevokedCommitPoint: priv.PubKey(),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		recoverable, status, err := assessChanSync(
+			channel, test.pendingRemote, test.msg,
+		)
+		if err != nil {
+			t.Fatalf("%v: unable to assess chan sync: %v",
+				test.name, err)
+		}
+		if recoverable != test.recoverable {
+			t.Fatalf("%v: expected recoverable=%v, got %v: %v",
+				test.name, test.recoverable, recoverable,
+				status)
+		}
+		if status == "" {
+			t.Fatalf("%v: expected status to be set", test.name)
+		}
+	}
+}
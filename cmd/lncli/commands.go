@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
@@ -436,6 +437,20 @@ var openChannelCommand = cli.Command{
 			Usage: "(optional) the minimum value we will require " +
 				"for incoming HTLCs on the channel",
 		},
+		cli.Int64Flag{
+			Name: "remote_reserve_sat",
+			Usage: "(optional) the reserve in satoshis the remote " +
+				"node will be required to maintain within the " +
+				"channel, in place of the default of 1% of the " +
+				"channel capacity",
+		},
+		cli.Uint64Flag{
+			Name: "announcement_depth",
+			Usage: "(optional) the number of confirmations the " +
+				"funding transaction must reach before the " +
+				"channel is announced, if beyond the default " +
+				"of 6",
+		},
 	},
 	Action: actionDecorator(openChannel),
 }
@@ -461,9 +476,11 @@ func openChannel(ctx *cli.Context) error {
 	}
 
 	req := &lnrpc.OpenChannelRequest{
-		TargetConf:  int32(ctx.Int64("conf_target")),
-		SatPerByte:  ctx.Int64("sat_per_byte"),
-		MinHtlcMsat: ctx.Int64("min_htlc_msat"),
+		TargetConf:           int32(ctx.Int64("conf_target")),
+		SatPerByte:           ctx.Int64("sat_per_byte"),
+		MinHtlcMsat:          ctx.Int64("min_htlc_msat"),
+		RemoteChanReserveSat: ctx.Int64("remote_reserve_sat"),
+		AnnouncementDepth:    uint32(ctx.Uint64("announcement_depth")),
 	}
 
 	switch {
@@ -571,12 +588,17 @@ var closeChannelCommand = cli.Command{
 	A unilateral channel closure means that the latest commitment
 	transaction will be broadcast to the network. As a result, any settled
 	funds will be time locked for a few blocks before they can be swept int
-	lnd's wallet.
+	lnd's wallet. Before the commitment transaction is broadcast, the HTLCs
+	that will be resolved on chain and the estimated cost of the closure are
+	displayed, and the closure must be confirmed, unless --confirm is set.
 
 	In the case of a cooperative closure, One can manually set the fee to
 	be used for the closing transaction via either the --conf_target or
 	--sat_per_byte arguments. This will be the starting value used during
-	fee negotiation.  This is optional.`,
+	fee negotiation.  This is optional. The funds of a cooperative closure
+	are paid out to a fresh wallet address, unless an address or a raw
+	output script (such as a P2TR script) is specified via either the
+	--delivery_addr or --delivery_script arguments.`,
 	ArgsUsage: "funding_txid [output_index [time_limit]]",
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -598,6 +620,12 @@ var closeChannelCommand = cli.Command{
 			Usage: "after the time limit has passed, attempt an " +
 				"uncooperative closure",
 		},
+		cli.BoolFlag{
+			Name: "confirm",
+			Usage: "broadcast the commitment transaction of a " +
+				"force closure without displaying its cost " +
+				"and prompting for confirmation",
+		},
 		cli.BoolFlag{
 			Name:  "block",
 			Usage: "block until the channel is closed",
@@ -614,6 +642,17 @@ var closeChannelCommand = cli.Command{
 				"sat/byte that should be used when crafting " +
 				"the transaction",
 		},
+		cli.StringFlag{
+			Name: "delivery_addr",
+			Usage: "(optional) the address the funds of a " +
+				"cooperative closure should be paid out to",
+		},
+		cli.StringFlag{
+			Name: "delivery_script",
+			Usage: "(optional) the hex-encoded output script the " +
+				"funds of a cooperative closure should be " +
+				"paid out to",
+		},
 	},
 	Action: actionDecorator(closeChannel),
 }
@@ -643,6 +682,17 @@ func closeChannel(ctx *cli.Context) error {
 		SatPerByte:   ctx.Int64("sat_per_byte"),
 	}
 
+	req.DeliveryAddress = ctx.String("delivery_addr")
+	if ctx.IsSet("delivery_script") {
+		req.DeliveryScript, err = hex.DecodeString(
+			ctx.String("delivery_script"),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode delivery "+
+				"script: %v", err)
+		}
+	}
+
 	switch {
 	case ctx.IsSet("funding_txid"):
 		txid = ctx.String("funding_txid")
@@ -672,6 +722,22 @@ func closeChannel(ctx *cli.Context) error {
 		req.ChannelPoint.OutputIndex = 0
 	}
 
+	// A force closure must be confirmed before the commitment transaction
+	// is broadcast. Unless the user has done so up front, we'll display
+	// the HTLCs that will go to chain and the estimated cost of the
+	// closure, then prompt for confirmation.
+	if req.Force && !ctx.Bool("confirm") {
+		confirmed, err := confirmForceClose(ctxb, client, req)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Force closure aborted")
+			return nil
+		}
+	}
+	req.ConfirmForce = req.Force
+
 	stream, err := client.CloseChannel(ctxb, req)
 	if err != nil {
 		return err
@@ -719,6 +785,38 @@ func closeChannel(ctx *cli.Context) error {
 	}
 }
 
+// confirmForceClose fetches a preview of the requested force closure, displays
+// it, and prompts the user to confirm the closure.
+func confirmForceClose(ctxb context.Context, client lnrpc.LightningClient,
+	req *lnrpc.CloseChannelRequest) (bool, error) {
+
+	stream, err := client.CloseChannel(ctxb, req)
+	if err != nil {
+		return false, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return false, err
+	}
+
+	preview := resp.GetForceClosePreview()
+	if preview == nil {
+		return false, fmt.Errorf("expected force close preview, "+
+			"instead got %v", resp)
+	}
+	printRespJSON(preview)
+
+	fmt.Printf("Broadcast the commitment transaction, resolving %v "+
+		"HTLCs on chain? (yes/no): ", len(preview.PendingHtlcs))
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	return strings.ToLower(strings.TrimSpace(answer)) == "yes", nil
+}
+
 var listPeersCommand = cli.Command{
 	Name:   "listpeers",
 	Usage:  "List all active, currently connected peers.",
@@ -971,6 +1069,18 @@ var sendPaymentCommand = cli.Command{
 	it'll use the hash of all zeroes. This mode allows one to quickly test
 	payment connectivity without having to create an invoice at the
 	destination.
+
+	If the --dry_run flag is specified, then the payment isn't sent.
+	Instead, the route it would take is displayed, along with its fees,
+	time-lock, and an estimate of its probability of success. A payment
+	hash isn't required for a dry run.
+
+	If the --keysend flag is specified, then a random preimage is
+	generated and sent to the destination within the onion, allowing it
+	to settle the payment without an invoice. Keysend payments are
+	experimental and use an encoding specific to lnd, so both nodes must
+	be running with --experimentalkeysend. In this case, the payment hash
+	mustn't be specified.
 	`,
 	ArgsUsage: "dest amt payment_hash final_cltv_delta | --pay_req=[payment request]",
 	Flags: []cli.Flag{
@@ -999,6 +1109,17 @@ var sendPaymentCommand = cli.Command{
 			Name:  "final_cltv_delta",
 			Usage: "the number of blocks the last hop has to reveal the preimage",
 		},
+		cli.BoolFlag{
+			Name: "dry_run",
+			Usage: "only display the route the payment would " +
+				"take, without sending it",
+		},
+		cli.BoolFlag{
+			Name: "keysend",
+			Usage: "(experimental) send the payment without " +
+				"an invoice, including its preimage within " +
+				"the onion",
+		},
 	},
 	Action: sendPayment,
 }
@@ -1012,6 +1133,11 @@ func sendPayment(ctx *cli.Context) error {
 
 	var req *lnrpc.SendRequest
 	if ctx.IsSet("pay_req") {
+		if ctx.Bool("keysend") {
+			return fmt.Errorf("do not provide a payment request " +
+				"with keysend")
+		}
+
 		req = &lnrpc.SendRequest{
 			PaymentRequest: ctx.String("pay_req"),
 			Amt:            ctx.Int64("amt"),
@@ -1058,7 +1184,28 @@ func sendPayment(ctx *cli.Context) error {
 			Amt:  amount,
 		}
 
-		if ctx.Bool("debug_send") && (ctx.IsSet("payment_hash") || args.Present()) {
+		if ctx.Bool("keysend") {
+			if ctx.Bool("debug_send") || ctx.IsSet("payment_hash") {
+				return fmt.Errorf("do not provide a payment " +
+					"hash with keysend")
+			}
+			req.Keysend = true
+
+			switch {
+			case ctx.IsSet("final_cltv_delta"):
+				req.FinalCltvDelta = int32(
+					ctx.Int64("final_cltv_delta"),
+				)
+			case args.Present():
+				delta, err := strconv.ParseInt(
+					args.First(), 10, 64,
+				)
+				if err != nil {
+					return err
+				}
+				req.FinalCltvDelta = int32(delta)
+			}
+		} else if ctx.Bool("debug_send") && (ctx.IsSet("payment_hash") || args.Present()) {
 			return fmt.Errorf("do not provide a payment hash with debug send")
 		} else if !ctx.Bool("debug_send") {
 			var rHash []byte
@@ -1068,6 +1215,8 @@ func sendPayment(ctx *cli.Context) error {
 				rHash, err = hex.DecodeString(ctx.String("payment_hash"))
 			case args.Present():
 				rHash, err = hex.DecodeString(args.First())
+			case ctx.Bool("dry_run"):
+				rHash = make([]byte, 32)
 			default:
 				return fmt.Errorf("payment hash argument missing")
 			}
@@ -1101,6 +1250,8 @@ func sendPaymentRequest(ctx *cli.Context, req *lnrpc.SendRequest) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
+	req.DryRun = ctx.Bool("dry_run")
+
 	paymentStream, err := client.SendPayment(context.Background())
 	if err != nil {
 		return err
@@ -1117,6 +1268,20 @@ func sendPaymentRequest(ctx *cli.Context, req *lnrpc.SendRequest) error {
 
 	paymentStream.CloseSend()
 
+	if req.DryRun {
+		printJSON(struct {
+			E string       `json:"payment_error"`
+			R *lnrpc.Route `json:"payment_route"`
+			S float64      `json:"success_probability"`
+		}{
+			E: resp.PaymentError,
+			R: resp.PaymentRoute,
+			S: resp.SuccessProbability,
+		})
+
+		return nil
+	}
+
 	printJSON(struct {
 		E string       `json:"payment_error"`
 		P string       `json:"payment_preimage"`
@@ -1144,6 +1309,11 @@ var payInvoiceCommand = cli.Command{
 			Usage: "(optional) number of satoshis to fulfill the " +
 				"invoice",
 		},
+		cli.BoolFlag{
+			Name: "dry_run",
+			Usage: "only display the route the payment would " +
+				"take, without sending it",
+		},
 	},
 	Action: actionDecorator(payInvoice),
 }
@@ -1178,7 +1348,18 @@ var addInvoiceCommand = cli.Command{
 
 	Invoices without an amount can be created by not supplying any
 	parameters or providing an amount of 0. These invoices allow the payee
-	to specify the amount of satoshis they wish to send.`,
+	to specify the amount of satoshis they wish to send.
+
+	Hold invoices can be created by setting --hold. The HTLCs paying to a
+	hold invoice are accepted, but held until the invoice is settled with
+	settleholdinvoice, or canceled with cancelholdinvoice. A hold invoice
+	may be created for a payment hash via --hash, in which case its
+	preimage is only needed once it's settled.
+
+	Stateless invoices can be created by setting --stateless, provided the
+	node has statelessinvoices enabled. Such invoices aren't stored, as
+	their preimage is derived from their terms, which the payer hands back
+	within the payment. As such, they can't be looked up.`,
 	ArgsUsage: "value preimage",
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -1220,6 +1401,27 @@ var addInvoiceCommand = cli.Command{
 				"specified an expiry of 3600 seconds (1 hour) " +
 				"is implied.",
 		},
+		cli.BoolFlag{
+			Name: "private",
+			Usage: "include a routing hint for one of our private " +
+				"channels, so the invoice can be paid through it",
+		},
+		cli.BoolFlag{
+			Name: "hold",
+			Usage: "create a hold invoice, whose HTLCs are held " +
+				"until it's either settled or canceled",
+		},
+		cli.StringFlag{
+			Name: "hash",
+			Usage: "the hex-encoded payment hash (32 byte) of a " +
+				"hold invoice, whose preimage isn't to be " +
+				"specified until it's settled",
+		},
+		cli.BoolFlag{
+			Name: "stateless",
+			Usage: "create a stateless invoice, which isn't " +
+				"stored, but reconstructed once it's paid",
+		},
 	},
 	Action: actionDecorator(addInvoice),
 }
@@ -1227,6 +1429,7 @@ var addInvoiceCommand = cli.Command{
 func addInvoice(ctx *cli.Context) error {
 	var (
 		preimage []byte
+		rHash    []byte
 		descHash []byte
 		receipt  []byte
 		amt      int64
@@ -1260,6 +1463,17 @@ func addInvoice(ctx *cli.Context) error {
 		return fmt.Errorf("unable to parse preimage: %v", err)
 	}
 
+	if ctx.IsSet("hash") {
+		if !ctx.Bool("hold") {
+			return fmt.Errorf("hash may only be specified for " +
+				"hold invoices")
+		}
+		rHash, err = hex.DecodeString(ctx.String("hash"))
+		if err != nil {
+			return fmt.Errorf("unable to parse hash: %v", err)
+		}
+	}
+
 	descHash, err = hex.DecodeString(ctx.String("description_hash"))
 	if err != nil {
 		return fmt.Errorf("unable to parse description_hash: %v", err)
@@ -1278,6 +1492,10 @@ func addInvoice(ctx *cli.Context) error {
 		DescriptionHash: descHash,
 		FallbackAddr:    ctx.String("fallback_addr"),
 		Expiry:          ctx.Int64("expiry"),
+		Private:         ctx.Bool("private"),
+		Hold:            ctx.Bool("hold"),
+		RHash:           rHash,
+		Stateless:       ctx.Bool("stateless"),
 	}
 
 	resp, err := client.AddInvoice(context.Background(), invoice)
@@ -1346,6 +1564,60 @@ func lookupInvoice(ctx *cli.Context) error {
 	return nil
 }
 
+var subscribeInvoiceCommand = cli.Command{
+	Name:      "subscribeinvoice",
+	Usage:     "Stream the updates of an invoice.",
+	ArgsUsage: "rhash",
+	Description: `
+	Streams the current state of the invoice with the given payment hash,
+	followed by an update each time an HTLC paying to it is accepted, or
+	it's settled or canceled, until interrupted.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "rhash",
+			Usage: "the 32 byte payment hash of the invoice to " +
+				"subscribe to, the hash should be a " +
+				"hex-encoded string",
+		},
+	},
+	Action: actionDecorator(subscribeInvoice),
+}
+
+func subscribeInvoice(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var rHash string
+	switch {
+	case ctx.IsSet("rhash"):
+		rHash = ctx.String("rhash")
+	case ctx.Args().Present():
+		rHash = ctx.Args().First()
+	default:
+		return fmt.Errorf("rhash argument missing")
+	}
+
+	req := &lnrpc.PaymentHash{
+		RHashStr: rHash,
+	}
+	stream, err := client.SubscribeSingleInvoice(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		invoice, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(invoice)
+	}
+}
+
 var listInvoicesCommand = cli.Command{
 	Name:  "listinvoices",
 	Usage: "List all invoices currently stored.",
@@ -2038,12 +2310,14 @@ func feeReport(ctx *cli.Context) error {
 
 var updateChannelPolicyCommand = cli.Command{
 	Name:      "updatechanpolicy",
-	Usage:     "update the channel policy for all channels, or a single channel",
+	Usage:     "update the channel policy for all channels, a set of channels, or a peer",
 	ArgsUsage: "base_fee_msat fee_rate time_lock_delta [channel_point]",
 	Description: `
-	Updates the channel policy for all channels, or just a particular channel
-	identified by its channel point. The update will be committed, and
-	broadcast to the rest of the network within the next batch.
+	Updates the channel policy for all channels, the channels identified by
+	their channel points, or all channels with a particular peer. The update
+	will be committed, and broadcast to the rest of the network within the
+	next batch. Any targeted channels the update couldn't be applied to are
+	listed, along with the reason why.
 	Channel points are encoded as: funding_txid:output_index`,
 	Flags: []cli.Flag{
 		cli.Int64Flag{
@@ -2063,11 +2337,23 @@ var updateChannelPolicyCommand = cli.Command{
 			Usage: "the CLTV delta that will be applied to all " +
 				"forwarded HTLCs",
 		},
-		cli.StringFlag{
+		cli.Int64Flag{
+			Name: "max_htlc_msat",
+			Usage: "if set, the largest HTLC in milli-satoshis " +
+				"that will be forwarded over, or accepted from, " +
+				"the channel",
+		},
+		cli.StringSliceFlag{
 			Name: "chan_point",
 			Usage: "The channel whose fee policy should be " +
 				"updated, if nil the policies for all channels " +
-				"will be updated. Takes the form of: txid:output_index",
+				"will be updated. Takes the form of: " +
+				"txid:output_index. Can be specified multiple times",
+		},
+		cli.StringFlag{
+			Name: "peer",
+			Usage: "the hex-encoded public key of the peer whose " +
+				"channels' fee policies should be updated",
 		},
 	},
 	Action: actionDecorator(updateChannelPolicy),
@@ -2128,19 +2414,20 @@ func updateChannelPolicy(ctx *cli.Context) error {
 		return fmt.Errorf("time_lock_delta argument missing")
 	}
 
-	var (
-		chanPoint    *lnrpc.ChannelPoint
-		chanPointStr string
-	)
-
+	var chanPointStrs []string
 	switch {
 	case ctx.IsSet("chan_point"):
-		chanPointStr = ctx.String("chan_point")
+		chanPointStrs = ctx.StringSlice("chan_point")
 	case args.Present():
-		chanPointStr = args.First()
+		chanPointStrs = []string{args.First()}
 	}
 
-	if chanPointStr != "" {
+	if len(chanPointStrs) != 0 && ctx.IsSet("peer") {
+		return fmt.Errorf("chan_point and peer can't both be set")
+	}
+
+	var chanPoints []*lnrpc.ChannelPoint
+	for _, chanPointStr := range chanPointStrs {
 		split := strings.Split(chanPointStr, ":")
 		if len(split) != 2 {
 			return fmt.Errorf("expecting chan_point to be in format of: " +
@@ -2156,23 +2443,39 @@ func updateChannelPolicy(ctx *cli.Context) error {
 			return fmt.Errorf("unable to decode output index: %v", err)
 		}
 
-		chanPoint = &lnrpc.ChannelPoint{
+		chanPoints = append(chanPoints, &lnrpc.ChannelPoint{
 			FundingTxid: txHash[:],
 			OutputIndex: uint32(index),
-		}
+		})
+	}
+
+	if ctx.Int64("max_htlc_msat") < 0 {
+		return fmt.Errorf("max_htlc_msat must not be negative")
 	}
 
 	req := &lnrpc.PolicyUpdateRequest{
 		BaseFeeMsat:   baseFee,
 		FeeRate:       feeRate,
 		TimeLockDelta: uint32(timeLockDelta),
+		MaxHtlcMsat:   uint64(ctx.Int64("max_htlc_msat")),
 	}
 
-	if chanPoint != nil {
+	switch {
+	case len(chanPoints) == 1:
 		req.Scope = &lnrpc.PolicyUpdateRequest_ChanPoint{
-			ChanPoint: chanPoint,
+			ChanPoint: chanPoints[0],
 		}
-	} else {
+	case len(chanPoints) > 1:
+		req.Scope = &lnrpc.PolicyUpdateRequest_ChanPoints{
+			ChanPoints: &lnrpc.ChannelPointList{
+				ChanPoints: chanPoints,
+			},
+		}
+	case ctx.IsSet("peer"):
+		req.Scope = &lnrpc.PolicyUpdateRequest_PeerPubKey{
+			PeerPubKey: ctx.String("peer"),
+		}
+	default:
 		req.Scope = &lnrpc.PolicyUpdateRequest_Global{
 			Global: true,
 		}
@@ -2186,3 +2489,1256 @@ func updateChannelPolicy(ctx *cli.Context) error {
 	printRespJSON(resp)
 	return nil
 }
+
+var exportGraphCommand = cli.Command{
+	Name:      "exportgraph",
+	Usage:     "export the channel graph to a snapshot file",
+	ArgsUsage: "snapshot_path",
+	Description: `
+	Fetches a compact snapshot of the node's validated channel graph, and
+	writes it to the target file on the local machine.
+
+	The resulting snapshot can be loaded into a fresh node using the
+	importgraph command, allowing it to skip the initial graph sync.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "snapshot_path",
+			Usage: "the path the graph snapshot should be written to",
+		},
+	},
+	Action: actionDecorator(exportGraph),
+}
+
+func exportGraph(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	snapshotPath, err := parseSnapshotPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.ExportGraphRequest{}
+	resp, err := client.ExportGraph(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(snapshotPath, resp.Snapshot, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write graph snapshot: %v", err)
+	}
+
+	// The snapshot itself has been written to disk, so we'll only display
+	// its summary.
+	resp.Snapshot = nil
+	printRespJSON(resp)
+	return nil
+}
+
+var importGraphCommand = cli.Command{
+	Name:      "importgraph",
+	Usage:     "import a channel graph snapshot file",
+	ArgsUsage: "snapshot_path",
+	Description: `
+	Reads a channel graph snapshot created by the exportgraph command from
+	the target file on the local machine, and loads it into the node's
+	channel graph.
+
+	Each announcement within the snapshot is validated as if it had been
+	received via gossip: its signatures are verified, and the funding
+	outputs of all imported channels are verified against the chain.
+	Announcements which fail validation are skipped.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "snapshot_path",
+			Usage: "the path of the graph snapshot to import",
+		},
+	},
+	Action: actionDecorator(importGraph),
+}
+
+func importGraph(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	snapshotPath, err := parseSnapshotPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := ioutil.ReadFile(snapshotPath)
+	if err != nil {
+		return fmt.Errorf("unable to read graph snapshot: %v", err)
+	}
+
+	req := &lnrpc.ImportGraphRequest{
+		Snapshot: snapshot,
+	}
+	resp, err := client.ImportGraph(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// parseSnapshotPath extracts the graph snapshot path from either the
+// snapshot_path flag or the first positional argument.
+func parseSnapshotPath(ctx *cli.Context) (string, error) {
+	switch {
+	case ctx.IsSet("snapshot_path"):
+		return ctx.String("snapshot_path"), nil
+	case ctx.Args().Present():
+		return ctx.Args().First(), nil
+	default:
+		return "", fmt.Errorf("snapshot_path argument missing")
+	}
+}
+
+var forwardingFilterCommand = cli.Command{
+	Name:  "fwdfilter",
+	Usage: "display the forwarding allow and deny lists",
+	Description: `
+	Returns the hex-encoded public keys of the peers within the forwarding
+	allow and deny lists. HTLCs won't be forwarded from or to peers within
+	the deny list. If the allow list is non-empty, then HTLCs will only be
+	forwarded between peers within it.`,
+	Action: actionDecorator(forwardingFilter),
+}
+
+func forwardingFilter(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ForwardingFilterRequest{}
+	resp, err := client.ForwardingFilter(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var updateForwardingFilterCommand = cli.Command{
+	Name:  "updatefwdfilter",
+	Usage: "update the forwarding allow and deny lists",
+	Description: `
+	Adds peers to, or removes peers from, the forwarding allow and deny
+	lists. Each flag takes the hex-encoded public key of a peer, and can be
+	specified multiple times. Adding a peer to one list removes it from the
+	other.
+
+	The changes take effect immediately, but aren't persisted across
+	restarts. Use the forwardallow and forwarddeny config options to set
+	the lists at startup.`,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "allow",
+			Usage: "the public key of a peer to add to the allow list",
+		},
+		cli.StringSliceFlag{
+			Name:  "deny",
+			Usage: "the public key of a peer to add to the deny list",
+		},
+		cli.StringSliceFlag{
+			Name: "remove",
+			Usage: "the public key of a peer to remove from both " +
+				"lists",
+		},
+	},
+	Action: actionDecorator(updateForwardingFilter),
+}
+
+func updateForwardingFilter(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.UpdateForwardingFilterRequest{
+		Allow:  ctx.StringSlice("allow"),
+		Deny:   ctx.StringSlice("deny"),
+		Remove: ctx.StringSlice("remove"),
+	}
+	if len(req.Allow) == 0 && len(req.Deny) == 0 && len(req.Remove) == 0 {
+		return cli.ShowCommandHelp(ctx, "updatefwdfilter")
+	}
+
+	resp, err := client.UpdateForwardingFilter(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var tapPeerCommand = cli.Command{
+	Name:      "tappeer",
+	Usage:     "stream the wire messages exchanged with a peer",
+	ArgsUsage: "pubkey",
+	Description: `
+	Streams the decoded wire messages sent to, and received from, the
+	peer with the given hex-encoded public key, until interrupted. Payment
+	preimages are redacted from the streamed messages.
+
+	This is a debugging aid, which requires lnd to have been started with
+	the --debugmessagetap flag.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "pub_key",
+			Usage: "the public key of the peer",
+		},
+	},
+	Action: actionDecorator(tapPeer),
+}
+
+func tapPeer(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var pubKey string
+	switch {
+	case ctx.IsSet("pub_key"):
+		pubKey = ctx.String("pub_key")
+	case ctx.Args().Present():
+		pubKey = ctx.Args().First()
+	default:
+		return cli.ShowCommandHelp(ctx, "tappeer")
+	}
+
+	req := &lnrpc.PeerMessageSubscription{
+		PubKey: pubKey,
+	}
+	stream, err := client.SubscribePeerMessages(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(msg)
+	}
+}
+
+var liquidityHistoryCommand = cli.Command{
+	Name:      "liquidityhistory",
+	Usage:     "show the recorded liquidity of a channel over time",
+	ArgsUsage: "chan_point",
+	Description: `
+	Returns the recorded snapshots of the local and remote balances, and of
+	the pending HTLCs, of the channel with the given channel point, in
+	chronological order. The snapshots can be limited to those taken
+	within a time range, given as unix timestamps in seconds.
+
+	Snapshots are only recorded if the liquidity history is enabled, see
+	the liquidityhistory.interval option of lnd.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "the channel point of the channel, in the form " +
+				"txid:index",
+		},
+		cli.Int64Flag{
+			Name: "start_time",
+			Usage: "the unix timestamp from which snapshots should " +
+				"be returned",
+		},
+		cli.Int64Flag{
+			Name: "end_time",
+			Usage: "the unix timestamp up to which snapshots should " +
+				"be returned, defaults to the current time",
+		},
+	},
+	Action: actionDecorator(liquidityHistory),
+}
+
+func liquidityHistory(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var chanPointStr string
+	switch {
+	case ctx.IsSet("chan_point"):
+		chanPointStr = ctx.String("chan_point")
+	case ctx.Args().Present():
+		chanPointStr = ctx.Args().First()
+	default:
+		return cli.ShowCommandHelp(ctx, "liquidityhistory")
+	}
+
+	split := strings.Split(chanPointStr, ":")
+	if len(split) != 2 {
+		return fmt.Errorf("expecting chan_point to be in format of: " +
+			"txid:index")
+	}
+	txHash, err := chainhash.NewHashFromStr(split[0])
+	if err != nil {
+		return err
+	}
+	index, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		return fmt.Errorf("unable to decode output index: %v", err)
+	}
+
+	if ctx.Int64("start_time") < 0 || ctx.Int64("end_time") < 0 {
+		return fmt.Errorf("start_time and end_time must not be " +
+			"negative")
+	}
+
+	req := &lnrpc.LiquidityHistoryRequest{
+		ChanPoint: &lnrpc.ChannelPoint{
+			FundingTxid: txHash[:],
+			OutputIndex: uint32(index),
+		},
+		StartTime: uint64(ctx.Int64("start_time")),
+		EndTime:   uint64(ctx.Int64("end_time")),
+	}
+	resp, err := client.LiquidityHistory(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var injectSwitchFaultCommand = cli.Command{
+	Name:      "injectswitchfault",
+	Usage:     "drop, delay or duplicate packets forwarded by the switch",
+	ArgsUsage: "action",
+	Description: `
+	Injects a fault into the forwarding path of the htlc switch. The action,
+	one of "drop", "delay" or "duplicate", is applied to the next packets
+	forwarded which carry an update of the given type. Faults are applied in
+	the order they were injected.
+
+	This is a testing aid, which requires lnd to have been started with the
+	--debugswitchfaults flag.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "action",
+			Usage: "the action to take: drop, delay or duplicate",
+		},
+		cli.StringFlag{
+			Name: "type",
+			Usage: "the type of update to apply the fault to: add, " +
+				"settle or fail, defaults to any",
+		},
+		cli.Uint64Flag{
+			Name:  "count",
+			Usage: "the number of packets to apply the fault to",
+			Value: 1,
+		},
+		cli.Uint64Flag{
+			Name:  "delay_ms",
+			Usage: "the time in milliseconds to delay packets for",
+		},
+		cli.BoolFlag{
+			Name:  "clear",
+			Usage: "discard all pending faults instead",
+		},
+	},
+	Action: actionDecorator(injectSwitchFault),
+}
+
+func injectSwitchFault(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.InjectSwitchFaultRequest{
+		UpdateType: ctx.String("type"),
+		Count:      uint32(ctx.Uint64("count")),
+		DelayMs:    uint32(ctx.Uint64("delay_ms")),
+		Clear:      ctx.Bool("clear"),
+	}
+	switch {
+	case req.Clear:
+	case ctx.IsSet("action"):
+		req.Action = ctx.String("action")
+	case ctx.Args().Present():
+		req.Action = ctx.Args().First()
+	default:
+		return cli.ShowCommandHelp(ctx, "injectswitchfault")
+	}
+
+	resp, err := client.InjectSwitchFault(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var updateChanStatusCommand = cli.Command{
+	Name:      "updatechanstatus",
+	Usage:     "pin the announced status of a channel",
+	ArgsUsage: "chan_point action",
+	Description: `
+	Sets the disabled bit of the channel update announced for the channel
+	with the given channel point, and pins it, so that it's kept regardless
+	of the automatic management of the status of our channels. Disabling a
+	channel drains it of routing traffic ahead of maintenance.
+
+	The action is one of "disable" or "enable", or "auto" to release a
+	prior pin, returning the channel to the status of all other channels.
+	Pins don't survive a restart of lnd.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "the channel point of the channel, in the form " +
+				"txid:index",
+		},
+		cli.StringFlag{
+			Name:  "action",
+			Usage: "the action to take: disable, enable or auto",
+		},
+	},
+	Action: actionDecorator(updateChanStatus),
+}
+
+func updateChanStatus(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	var chanPointStr string
+	switch {
+	case ctx.IsSet("chan_point"):
+		chanPointStr = ctx.String("chan_point")
+	case args.Present():
+		chanPointStr = args.First()
+		args = args.Tail()
+	default:
+		return cli.ShowCommandHelp(ctx, "updatechanstatus")
+	}
+
+	var action string
+	switch {
+	case ctx.IsSet("action"):
+		action = ctx.String("action")
+	case args.Present():
+		action = args.First()
+	default:
+		return cli.ShowCommandHelp(ctx, "updatechanstatus")
+	}
+
+	split := strings.Split(chanPointStr, ":")
+	if len(split) != 2 {
+		return fmt.Errorf("expecting chan_point to be in format of: " +
+			"txid:index")
+	}
+	txHash, err := chainhash.NewHashFromStr(split[0])
+	if err != nil {
+		return err
+	}
+	index, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		return fmt.Errorf("unable to decode output index: %v", err)
+	}
+
+	req := &lnrpc.UpdateChanStatusRequest{
+		ChanPoint: &lnrpc.ChannelPoint{
+			FundingTxid: txHash[:],
+			OutputIndex: uint32(index),
+		},
+		Action: action,
+	}
+	resp, err := client.UpdateChanStatus(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var settleHoldInvoiceCommand = cli.Command{
+	Name:      "settleholdinvoice",
+	Usage:     "Settle a hold invoice by releasing its preimage.",
+	ArgsUsage: "preimage",
+	Description: `
+	Release the preimage of a hold invoice, settling the HTLCs paying to
+	it. The invoice is marked as settled once its HTLCs have been settled.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "preimage",
+			Usage: "the hex-encoded preimage (32 byte) of the " +
+				"hold invoice",
+		},
+	},
+	Action: actionDecorator(settleHoldInvoice),
+}
+
+func settleHoldInvoice(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		preimage []byte
+		err      error
+	)
+	switch {
+	case ctx.IsSet("preimage"):
+		preimage, err = hex.DecodeString(ctx.String("preimage"))
+	case ctx.Args().Present():
+		preimage, err = hex.DecodeString(ctx.Args().First())
+	default:
+		return fmt.Errorf("preimage argument missing")
+	}
+	if err != nil {
+		return fmt.Errorf("unable to parse preimage: %v", err)
+	}
+
+	req := &lnrpc.SettleHoldInvoiceRequest{
+		RPreimage: preimage,
+	}
+	resp, err := client.SettleHoldInvoice(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var cancelHoldInvoiceCommand = cli.Command{
+	Name:      "cancelholdinvoice",
+	Usage:     "Cancel a hold invoice.",
+	ArgsUsage: "rhash",
+	Description: `
+	Cancel a hold invoice whose preimage hasn't yet been released, failing
+	the HTLCs paying to it back to their senders.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "rhash",
+			Usage: "the hex-encoded payment hash of the hold invoice",
+		},
+	},
+	Action: actionDecorator(cancelHoldInvoice),
+}
+
+func cancelHoldInvoice(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var rHash string
+	switch {
+	case ctx.IsSet("rhash"):
+		rHash = ctx.String("rhash")
+	case ctx.Args().Present():
+		rHash = ctx.Args().First()
+	default:
+		return fmt.Errorf("rhash argument missing")
+	}
+
+	req := &lnrpc.PaymentHash{
+		RHashStr: rHash,
+	}
+	resp, err := client.CancelHoldInvoice(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var forwardingHistoryCommand = cli.Command{
+	Name:  "fwdinghistory",
+	Usage: "query the history of all forwarded htlcs",
+	Description: `
+	Returns the HTLCs successfully forwarded by the node within a time
+	range, given as unix timestamps in seconds, in chronological order,
+	along with the fee earned by each.
+
+	The results are paginated. The last_offset_index of a response should
+	be passed as the index_offset of the next query to fetch the following
+	page of events.`,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "start_time",
+			Usage: "the unix timestamp from which forwarding " +
+				"events should be returned",
+		},
+		cli.Int64Flag{
+			Name: "end_time",
+			Usage: "the unix timestamp up to which forwarding " +
+				"events should be returned, defaults to the " +
+				"current time",
+		},
+		cli.Int64Flag{
+			Name:  "index_offset",
+			Usage: "the number of events to skip",
+		},
+		cli.Int64Flag{
+			Name: "max_events",
+			Usage: "the maximum number of events to return, " +
+				"defaults to 100",
+		},
+	},
+	Action: actionDecorator(forwardingHistory),
+}
+
+func forwardingHistory(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	for _, flag := range []string{
+		"start_time", "end_time", "index_offset", "max_events",
+	} {
+		if ctx.Int64(flag) < 0 {
+			return fmt.Errorf("%v must not be negative", flag)
+		}
+	}
+
+	req := &lnrpc.ForwardingHistoryRequest{
+		StartTime:    uint64(ctx.Int64("start_time")),
+		EndTime:      uint64(ctx.Int64("end_time")),
+		IndexOffset:  uint32(ctx.Int64("index_offset")),
+		NumMaxEvents: uint32(ctx.Int64("max_events")),
+	}
+	resp, err := client.ForwardingHistory(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var batchAddInvoiceCommand = cli.Command{
+	Name:      "batchaddinvoice",
+	Usage:     "add a batch of invoices sharing the same parameters.",
+	ArgsUsage: "num_invoices [value]",
+	Description: `
+	Add a batch of invoices, each with a freshly generated preimage, but
+	otherwise sharing the given parameters. The invoices are added within
+	a single transaction, and are returned in the order they were added.
+	At most 1000 invoices may be created at once.`,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "num_invoices",
+			Usage: "the number of invoices to create",
+		},
+		cli.StringFlag{
+			Name: "memo",
+			Usage: "a description of the payment to attach along " +
+				"with each invoice (default=\"\")",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the amt of satoshis in each invoice",
+		},
+		cli.StringFlag{
+			Name: "description_hash",
+			Usage: "SHA-256 hash of the description of the payment. " +
+				"If provided this will be used instead of the " +
+				"description(memo) field in the encoded invoices.",
+		},
+		cli.StringFlag{
+			Name: "fallback_addr",
+			Usage: "fallback on-chain address that can be used in " +
+				"case the lightning payment fails",
+		},
+		cli.Int64Flag{
+			Name: "expiry",
+			Usage: "the expiry time of each invoice in seconds. If " +
+				"not specified an expiry of 3600 seconds (1 hour) " +
+				"is implied.",
+		},
+		cli.BoolFlag{
+			Name: "private",
+			Usage: "include a routing hint for one of our private " +
+				"channels, so the invoices can be paid through it",
+		},
+		cli.BoolFlag{
+			Name: "hold",
+			Usage: "create hold invoices, whose HTLCs are held " +
+				"until they're either settled or canceled",
+		},
+	},
+	Action: actionDecorator(batchAddInvoice),
+}
+
+func batchAddInvoice(ctx *cli.Context) error {
+	var (
+		numInvoices int64
+		amt         int64
+		err         error
+	)
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("num_invoices"):
+		numInvoices = ctx.Int64("num_invoices")
+	case args.Present():
+		numInvoices, err = strconv.ParseInt(args.First(), 10, 32)
+		args = args.Tail()
+		if err != nil {
+			return fmt.Errorf("unable to decode num_invoices "+
+				"argument: %v", err)
+		}
+	default:
+		return cli.ShowCommandHelp(ctx, "batchaddinvoice")
+	}
+	if numInvoices <= 0 {
+		return fmt.Errorf("num_invoices must be positive")
+	}
+
+	switch {
+	case ctx.IsSet("amt"):
+		amt = ctx.Int64("amt")
+	case args.Present():
+		amt, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt argument: %v", err)
+		}
+	}
+
+	descHash, err := hex.DecodeString(ctx.String("description_hash"))
+	if err != nil {
+		return fmt.Errorf("unable to parse description_hash: %v", err)
+	}
+
+	req := &lnrpc.BatchAddInvoiceRequest{
+		Invoice: &lnrpc.Invoice{
+			Memo:            ctx.String("memo"),
+			Value:           amt,
+			DescriptionHash: descHash,
+			FallbackAddr:    ctx.String("fallback_addr"),
+			Expiry:          ctx.Int64("expiry"),
+			Private:         ctx.Bool("private"),
+			Hold:            ctx.Bool("hold"),
+		},
+		NumInvoices: uint32(numInvoices),
+	}
+
+	resp, err := client.BatchAddInvoice(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	type invoiceResp struct {
+		RHash  string `json:"r_hash"`
+		PayReq string `json:"pay_req"`
+	}
+	invoices := make([]invoiceResp, 0, len(resp.Invoices))
+	for _, invoice := range resp.Invoices {
+		invoices = append(invoices, invoiceResp{
+			RHash:  hex.EncodeToString(invoice.RHash),
+			PayReq: invoice.PaymentRequest,
+		})
+	}
+
+	printJSON(struct {
+		Invoices []invoiceResp `json:"invoices"`
+	}{
+		Invoices: invoices,
+	})
+
+	return nil
+}
+
+var timeLockedBalanceCommand = cli.Command{
+	Name:  "timelockedbalance",
+	Usage: "display funds locked behind CSV or CLTV timeouts",
+	Description: `
+	Display all funds which are currently time-locked, bucketed by the
+	block height at which they become spendable. This includes the outputs
+	of force closed channels which are awaiting their CSV or CLTV maturity,
+	as well as the outgoing HTLCs of open channels, which can only be
+	reclaimed once their CLTV timeout has expired.`,
+	Action: actionDecorator(timeLockedBalance),
+}
+
+func timeLockedBalance(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.TimeLockedBalanceRequest{}
+	resp, err := client.TimeLockedBalance(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var exportDebugPackageCommand = cli.Command{
+	Name:      "exportdebugpackage",
+	Usage:     "export an encrypted debug package for support",
+	ArgsUsage: "recipient_pubkey",
+	Description: `
+	Export the sanitized metadata of all channels, a summary of their
+	pending HTLCs, the state of their links, the graph excerpts describing
+	them and the most recent log entries as an archive, which is encrypted
+	to the public key of the maintainer it's to be shared with.
+
+	No preimages, keys, revocation secrets or signatures are included
+	within the package, and any 32-byte hex value is redacted from the log
+	entries. The encrypted package is written to the output file.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "recipient_pubkey",
+			Usage: "the hex-encoded public key to encrypt the " +
+				"package to",
+		},
+		cli.Uint64Flag{
+			Name: "max_log_entries",
+			Usage: "the maximum number of recent log entries to " +
+				"include, if 0 then all retained entries are " +
+				"included",
+		},
+		cli.StringFlag{
+			Name:  "output",
+			Value: "lnd-debug.tar.gz.enc",
+			Usage: "the file to write the encrypted package to",
+		},
+	},
+	Action: actionDecorator(exportDebugPackage),
+}
+
+func exportDebugPackage(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var recipient string
+	switch {
+	case ctx.IsSet("recipient_pubkey"):
+		recipient = ctx.String("recipient_pubkey")
+	case ctx.Args().Present():
+		recipient = ctx.Args().First()
+	default:
+		return fmt.Errorf("recipient_pubkey argument missing")
+	}
+
+	req := &lnrpc.ExportDebugPackageRequest{
+		RecipientPubkey: recipient,
+		MaxLogEntries:   uint32(ctx.Uint64("max_log_entries")),
+	}
+	resp, err := client.ExportDebugPackage(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	output := ctx.String("output")
+	err = ioutil.WriteFile(output, resp.EncryptedPackage, 0600)
+	if err != nil {
+		return err
+	}
+
+	printJSON(struct {
+		Output string `json:"output"`
+		Size   int    `json:"size"`
+	}{
+		Output: output,
+		Size:   len(resp.EncryptedPackage),
+	})
+
+	return nil
+}
+
+var debugProfileCommand = cli.Command{
+	Name:      "debugprofile",
+	Usage:     "collect a runtime profile of the daemon",
+	ArgsUsage: "goroutine|heap|cpu",
+	Description: `
+	Collect a dump of the stack of each running goroutine, a heap profile,
+	or a CPU profile sampled over the given duration, and write it to the
+	output file. Heap and CPU profiles are in the pprof format, and can be
+	inspected using "go tool pprof".
+
+	CPU profiles are sampled for at most 60 seconds, only a single profile
+	is collected at a time, and profiles exceeding 3 MiB are truncated.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "type",
+			Usage: "the type of the profile to collect, one of " +
+				"goroutine, heap or cpu",
+		},
+		cli.Uint64Flag{
+			Name: "duration",
+			Usage: "the number of seconds to sample a CPU profile " +
+				"for, if 0 then it's sampled for 10 seconds",
+		},
+		cli.StringFlag{
+			Name:  "output",
+			Usage: "the file to write the profile to",
+		},
+	},
+	Action: actionDecorator(debugProfile),
+}
+
+func debugProfile(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var profileType string
+	switch {
+	case ctx.IsSet("type"):
+		profileType = ctx.String("type")
+	case ctx.Args().Present():
+		profileType = ctx.Args().First()
+	default:
+		return fmt.Errorf("type argument missing")
+	}
+
+	req := &lnrpc.DebugProfileRequest{
+		DurationSecs: uint32(ctx.Uint64("duration")),
+	}
+	output := ctx.String("output")
+	switch profileType {
+	case "goroutine":
+		req.Type = lnrpc.DebugProfileRequest_GOROUTINE
+		if output == "" {
+			output = "lnd-goroutines.txt"
+		}
+	case "heap":
+		req.Type = lnrpc.DebugProfileRequest_HEAP
+		if output == "" {
+			output = "lnd-heap.pprof"
+		}
+	case "cpu":
+		req.Type = lnrpc.DebugProfileRequest_CPU
+		if output == "" {
+			output = "lnd-cpu.pprof"
+		}
+	default:
+		return fmt.Errorf("unknown profile type %v", profileType)
+	}
+
+	resp, err := client.DebugProfile(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(output, resp.Profile, 0600); err != nil {
+		return err
+	}
+
+	printJSON(struct {
+		Output    string `json:"output"`
+		Size      int    `json:"size"`
+		Truncated bool   `json:"truncated"`
+	}{
+		Output:    output,
+		Size:      len(resp.Profile),
+		Truncated: resp.Truncated,
+	})
+
+	return nil
+}
+
+var lookupCircuitCommand = cli.Command{
+	Name:      "lookupcircuit",
+	Usage:     "look up the payment circuits of the switch",
+	ArgsUsage: "payment_hash | --chan_id=N --htlc_id=N",
+	Description: `
+	Look up the payment circuits held by the switch for the HTLCs paying to
+	the given payment hash, or the circuit whose incoming or outgoing HTLC
+	is identified by the given channel and HTLC ID. Each circuit links the
+	incoming and outgoing legs of an HTLC forwarded by the node, or sent by
+	it, until the HTLC is settled or failed.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "payment_hash",
+			Usage: "the hex-encoded payment hash of the circuits",
+		},
+		cli.Uint64Flag{
+			Name: "chan_id",
+			Usage: "the short channel ID of the incoming or " +
+				"outgoing channel of the circuit",
+		},
+		cli.Uint64Flag{
+			Name:  "htlc_id",
+			Usage: "the ID of the HTLC within the channel",
+		},
+	},
+	Action: actionDecorator(lookupCircuit),
+}
+
+func lookupCircuit(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.LookupCircuitRequest{
+		ChanId: ctx.Uint64("chan_id"),
+		HtlcId: ctx.Uint64("htlc_id"),
+	}
+	switch {
+	case ctx.IsSet("payment_hash"):
+		req.PaymentHashStr = ctx.String("payment_hash")
+	case ctx.Args().Present():
+		req.PaymentHashStr = ctx.Args().First()
+	case !ctx.IsSet("chan_id"):
+		return fmt.Errorf("payment_hash or chan_id argument missing")
+	}
+
+	resp, err := client.LookupCircuit(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var peerCompatibilityCommand = cli.Command{
+	Name:  "peercompatibility",
+	Usage: "report the protocol upgrades blocked by each channel peer",
+	Description: `
+	Report, for each of our open channels, the optional protocol upgrades
+	(tlv-onion, static-remote-key and anchors) which are blocked as the
+	channel peer didn't announce support for them. The report is based on
+	the features last announced by each peer, so peers that are currently
+	offline are included as well.`,
+	Action: actionDecorator(peerCompatibility),
+}
+
+func peerCompatibility(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.PeerCompatibilityRequest{}
+	resp, err := client.PeerCompatibility(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listAliasesCommand = cli.Command{
+	Name:  "listaliases",
+	Usage: "list the short channel IDs and aliases of each channel",
+	Description: `
+	List, for each of our open channels, its confirmed short channel ID,
+	the short channel ID used for it within the route hints of our
+	invoices, and the alias short channel IDs which are accepted for
+	forwards over it. Aliases whose channel isn't open are listed
+	separately, as forwards specifying them fail with UnknownNextPeer.`,
+	Action: actionDecorator(listAliases),
+}
+
+func listAliases(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListAliasesRequest{}
+	resp, err := client.ListAliases(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var inspectCommitmentsCommand = cli.Command{
+	Name:      "inspectcommitments",
+	Usage:     "dump the commitment state of a channel",
+	ArgsUsage: "chan_point",
+	Description: `
+	Dump the commitment state of the channel with the given channel point,
+	as persisted by the node, in order to diagnose a failure to
+	re-synchronize the channel with its peer.
+
+	This includes our view of the current and next commitments of both
+	parties, the updates not yet acknowledged by the remote party, the
+	ChannelReestablish fields we'd send, and those last received from the
+	remote party, along with whether they allow the channel to be
+	re-synchronized.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "the channel point of the channel, in the form " +
+				"txid:index",
+		},
+	},
+	Action: actionDecorator(inspectCommitments),
+}
+
+func inspectCommitments(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var chanPointStr string
+	switch {
+	case ctx.IsSet("chan_point"):
+		chanPointStr = ctx.String("chan_point")
+	case ctx.Args().Present():
+		chanPointStr = ctx.Args().First()
+	default:
+		return cli.ShowCommandHelp(ctx, "inspectcommitments")
+	}
+
+	split := strings.Split(chanPointStr, ":")
+	if len(split) != 2 {
+		return fmt.Errorf("expecting chan_point to be in format of: " +
+			"txid:index")
+	}
+	txHash, err := chainhash.NewHashFromStr(split[0])
+	if err != nil {
+		return err
+	}
+	index, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		return fmt.Errorf("unable to decode output index: %v", err)
+	}
+
+	req := &lnrpc.InspectCommitmentsRequest{
+		ChanPoint: &lnrpc.ChannelPoint{
+			FundingTxid: txHash[:],
+			OutputIndex: uint32(index),
+		},
+	}
+	resp, err := client.InspectCommitments(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var sendToRouteCommand = cli.Command{
+	Name:      "sendtoroute",
+	Usage:     "send a payment over a predefined route",
+	ArgsUsage: "--payment_hash=<hash> --route=<json>",
+	Description: `
+	Send a payment over a route which has been fully specified by the
+	caller, such as by an external path-finder, bypassing the path finding
+	of the internal router.
+
+	The route is passed as JSON, in the same format as the routes returned
+	by queryroutes. Each hop must set the chan_id, the pub_key of the node
+	it leads to, the amount to forward and the expiry. If --route is set to
+	"-", then the route is read from stdin, e.g.:
+
+	    lncli queryroutes --dest=<pubkey> --amt=<amt> | \
+	        jq '.routes[0]' | \
+	        lncli sendtoroute --payment_hash=<hash> --route=-
+
+	A single attempt is made, after which either the preimage or the
+	decoded failure is returned.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "payment_hash",
+			Usage: "the hex-encoded hash to use within the " +
+				"payment's HTLC",
+		},
+		cli.StringFlag{
+			Name: "route",
+			Usage: "the JSON-encoded route to send the payment " +
+				"over, or \"-\" to read it from stdin",
+		},
+	},
+	Action: actionDecorator(sendToRoute),
+}
+
+func sendToRoute(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("payment_hash") || !ctx.IsSet("route") {
+		return fmt.Errorf("payment_hash and route must be set")
+	}
+
+	routeJSON := ctx.String("route")
+	if routeJSON == "-" {
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("unable to read route from stdin: %v",
+				err)
+		}
+		routeJSON = string(b)
+	}
+
+	route := &lnrpc.Route{}
+	if err := jsonpb.UnmarshalString(routeJSON, route); err != nil {
+		return fmt.Errorf("unable to parse route: %v", err)
+	}
+
+	req := &lnrpc.SendToRouteRequest{
+		PaymentHashString: ctx.String("payment_hash"),
+		Route:             route,
+	}
+	resp, err := client.SendToRoute(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var subscribeChannelEventsCommand = cli.Command{
+	Name:  "subscribechannelevents",
+	Usage: "stream the events relevant to the state of our channels",
+	Description: `
+	Streams an update whenever one of our channels is opened, becomes
+	active and able to forward payments, becomes inactive, or is fully
+	closed, until interrupted.`,
+	Action: actionDecorator(subscribeChannelEvents),
+}
+
+func subscribeChannelEvents(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ChannelEventSubscription{}
+	stream, err := client.SubscribeChannelEvents(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(update)
+	}
+}
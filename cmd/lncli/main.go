@@ -178,6 +178,7 @@ func main() {
 		payInvoiceCommand,
 		addInvoiceCommand,
 		lookupInvoiceCommand,
+		subscribeInvoiceCommand,
 		listInvoicesCommand,
 		listChannelsCommand,
 		listPaymentsCommand,
@@ -194,6 +195,27 @@ func main() {
 		verifyMessageCommand,
 		feeReportCommand,
 		updateChannelPolicyCommand,
+		exportGraphCommand,
+		importGraphCommand,
+		forwardingFilterCommand,
+		updateForwardingFilterCommand,
+		tapPeerCommand,
+		liquidityHistoryCommand,
+		injectSwitchFaultCommand,
+		updateChanStatusCommand,
+		settleHoldInvoiceCommand,
+		cancelHoldInvoiceCommand,
+		forwardingHistoryCommand,
+		batchAddInvoiceCommand,
+		timeLockedBalanceCommand,
+		exportDebugPackageCommand,
+		debugProfileCommand,
+		lookupCircuitCommand,
+		peerCompatibilityCommand,
+		listAliasesCommand,
+		inspectCommitmentsCommand,
+		sendToRouteCommand,
+		subscribeChannelEventsCommand,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os/exec"
+	"strconv"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// commitHookCmd is a channeldb.CommitHook which executes an external command
+// each time the commitment state of a channel has been persisted. The command
+// is passed the channel point, the commitment height, and the type of state
+// transition as arguments.
+type commitHookCmd struct {
+	cmd string
+}
+
+// A compile time check to ensure commitHookCmd implements the
+// channeldb.CommitHook interface.
+var _ channeldb.CommitHook = (*commitHookCmd)(nil)
+
+// CommitStatePersisted executes the configured command, and waits for it to
+// exit so that any replication it triggers is complete before the channel's
+// state machine advances.
+//
+// NOTE: This is part of the channeldb.CommitHook interface.
+func (c *commitHookCmd) CommitStatePersisted(chanPoint wire.OutPoint,
+	commitHeight uint64, transition channeldb.CommitTransitionType) {
+
+	cmd := exec.Command(
+		c.cmd, chanPoint.String(),
+		strconv.FormatUint(commitHeight, 10), transition.String(),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		ltndLog.Errorf("Commit hook for ChannelPoint(%v), height=%v "+
+			"failed: %v: %s", chanPoint, commitHeight, err, out)
+	}
+}
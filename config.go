@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -19,6 +20,7 @@ import (
 
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcutil"
@@ -42,11 +44,47 @@ const (
 	defaultNoEncryptWallet    = false
 	defaultTrickleDelay       = 30 * 1000
 
+	defaultStuckHTLCThreshold = time.Hour
+
+	defaultChanSyncTimeout = htlcswitch.DefaultChanSyncTimeout
+	defaultChanSyncRetries = 2
+
+	defaultLinkRestartBackoff    = 5 * time.Second
+	defaultMaxLinkRestartBackoff = 5 * time.Minute
+
+	defaultLinkBatchSize              = htlcswitch.DefaultBatchSize
+	defaultLinkBatchTicker            = htlcswitch.DefaultBatchTicker
+	defaultLinkPendingCommitTicker    = htlcswitch.DefaultPendingCommitTicker
+	defaultLinkMaxPendingCommitTicker = htlcswitch.DefaultMaxPendingCommitTicker
+
+	defaultMailBoxMaxMsgs = htlcswitch.DefaultMailBoxMaxMessages
+	defaultMailBoxMaxPkts = htlcswitch.DefaultMailBoxMaxPackets
+
+	defaultMaxOverflowQueueLen  = htlcswitch.DefaultMaxOverflowQueueLen
+	defaultMaxOverflowResidency = htlcswitch.DefaultMaxOverflowResidency
+
 	defaultBroadcastDelta = 10
 
-	// minTimeLockDelta is the minimum timelock we require for incoming
-	// HTLCs on our channels.
-	minTimeLockDelta = 4
+	defaultMaxPendingSettles = 20
+
+	// defaultHtlcExpiryGrace is the default number of blocks prior to the
+	// expiry of an incoming HTLC we know the preimage for, at which its
+	// channel is force closed to claim it on-chain. It matches the cutoff
+	// used by the chain arbitrator to redeem HTLCs.
+	defaultHtlcExpiryGrace = 2 * defaultBroadcastDelta
+
+	// defaultMinCltvDelta is the default floor of the time lock delta we
+	// require for HTLCs forwarded across our channels. It matches the
+	// broadcast delta, as we'd otherwise be unable to claim an incoming
+	// HTLC on-chain in time once its outgoing counterpart is settled at the
+	// last moment.
+	defaultMinCltvDelta = defaultBroadcastDelta
+
+	// defaultRemoteFeeTolerance is the default multiple of our own fee
+	// estimate the commitment fee rate proposed by a peer may deviate by.
+	// It's generous, as fee estimates of different backends routinely
+	// diverge, while still guarding against absurd fee updates.
+	defaultRemoteFeeTolerance = 10
 
 	defaultBitcoinMinHTLCMSat   = 1000
 	defaultBitcoinBaseFeeMSat   = 1000
@@ -95,6 +133,7 @@ type chainConfig struct {
 	DefaultNumChanConfs int                 `long:"defaultchanconfs" description:"The default number of confirmations a channel must have before it's considered open. If this is not set, we will scale the value according to the channel size."`
 	DefaultRemoteDelay  int                 `long:"defaultremotedelay" description:"The default number of blocks we will require our channel counterparty to wait before accessing its funds in case of unilateral close. If this is not set, we will scale the value according to the channel size."`
 	MinHTLC             lnwire.MilliSatoshi `long:"minhtlc" description:"The smallest HTLC we are willing to forward on our channels, in millisatoshi"`
+	MaxHTLC             lnwire.MilliSatoshi `long:"maxhtlc" description:"The largest HTLC we are willing to forward on, or accept from, our channels, in millisatoshi. If 0, HTLCs of any value are permitted"`
 	BaseFee             lnwire.MilliSatoshi `long:"basefee" description:"The base fee in millisatoshi we will charge for forwarding payments on our channels"`
 	FeeRate             lnwire.MilliSatoshi `long:"feerate" description:"The fee rate used when forwarding payments on our channels. The total fee charged is basefee + (amount * feerate / 1000000), where amount is the forwarded amount."`
 	TimeLockDelta       uint32              `long:"timelockdelta" description:"The CLTV delta we will subtract from a forwarded HTLC's timelock value"`
@@ -130,6 +169,45 @@ type autoPilotConfig struct {
 	Allocation  float64 `long:"allocation" description:"The percentage of total funds that should be committed to automatic channel establishment"`
 }
 
+type healthCheckConfig struct {
+	Interval    time.Duration `long:"interval" description:"How often the chain backend and fee estimator should be checked for responsiveness. A value of 0 disables health checks. Valid time units are {s, m, h}."`
+	Timeout     time.Duration `long:"timeout" description:"The amount of time a single health check may take before it's considered to have failed. Valid time units are {s, m, h}."`
+	MaxFailures uint32        `long:"maxfailures" description:"The number of consecutive failed health checks after which the chain backend is considered unhealthy. While unhealthy, channels are disabled within the network, and new channels and commitment fee updates are paused."`
+}
+
+type autoCloseConfig struct {
+	Active         bool          `long:"active" description:"If true, then channels meeting any of the configured criteria will be cooperatively closed automatically."`
+	DryRun         bool          `long:"dryrun" description:"If true, then channels meeting the criteria will only be reported within the logs, rather than closed."`
+	Interval       time.Duration `long:"interval" description:"How often channels should be checked against the criteria. Valid time units are {s, m, h}."`
+	MaxIdle        time.Duration `long:"maxidle" description:"Close channels whose commitment hasn't been updated, i.e. which haven't carried any payments, for this long. A value of 0 disables this criterion. Valid time units are {s, m, h}."`
+	MaxPeerOffline time.Duration `long:"maxpeeroffline" description:"Close channels whose peer has been offline for this long. As a cooperative close requires the peer to be online, such channels are closed once the peer reconnects. A value of 0 disables this criterion. Valid time units are {s, m, h}."`
+	MaxDepleted    time.Duration `long:"maxdepleted" description:"Close channels whose entire balance has remained on one side of the channel for this long. A value of 0 disables this criterion. Valid time units are {s, m, h}."`
+	Exempt         []string      `long:"exempt" description:"The hex-encoded public key of a peer whose channels should never be closed automatically. Can be specified multiple times."`
+}
+
+type liquidityHistoryConfig struct {
+	Interval  time.Duration `long:"interval" description:"How often a snapshot of the balances and pending HTLCs of each channel should be recorded. A value of 0 disables the liquidity history. Valid time units are {s, m, h}."`
+	Retention time.Duration `long:"retention" description:"The amount of time for which liquidity snapshots are retained. A value of 0 retains them indefinitely. Valid time units are {s, m, h}."`
+}
+
+type selfCheckConfig struct {
+	OutChan    uint64        `long:"outchan" description:"The short channel ID of the channel the startup self-check payment should leave over. The self-check is only carried out if both outchan and inchan are set."`
+	InChan     uint64        `long:"inchan" description:"The short channel ID of the channel the startup self-check payment should return over."`
+	Amt        int64         `long:"amt" description:"The amount in satoshis to send around the circle, excluding routing fees."`
+	MaxLatency time.Duration `long:"maxlatency" description:"The maximum amount of time the self-check payment may take to be settled for the check to pass. Valid time units are {s, m, h}."`
+	Timeout    time.Duration `long:"timeout" description:"The maximum amount of time to wait for both channels to become active after startup before failing the check. Valid time units are {s, m, h}."`
+}
+
+type alertsConfig struct {
+	Webhook       []string      `long:"webhook" description:"A URL each alert should be POSTed to as JSON. Alerting is only enabled if at least one webhook or command is set. Can be specified multiple times."`
+	Exec          []string      `long:"exec" description:"The path of an executable which should be run for each alert. The type of the alert is passed as its sole argument, and the alert is written as JSON to its standard input. Can be specified multiple times."`
+	Timeout       time.Duration `long:"timeout" description:"The amount of time a webhook or command is given to handle an alert. Valid time units are {s, m, h}."`
+	Interval      time.Duration `long:"interval" description:"How often the conditions raising periodic alerts, such as a peer being offline for too long, should be checked. Valid time units are {s, m, h}."`
+	PeerOffline   time.Duration `long:"peeroffline" description:"Raise an alert once a peer we have channels with has been offline for this long. A value of 0 disables this alert. Valid time units are {s, m, h}."`
+	MinReserve    int64         `long:"minreserve" description:"Raise an alert once our confirmed on-chain balance, which is needed to pay the fees of sweeps and force closes, falls below this many satoshis. A value of 0 disables this alert."`
+	SweepDeadline uint32        `long:"sweepdeadline" description:"Raise an alert once an output of a force closed channel hasn't been swept this many blocks after reaching maturity. A value of 0 disables this alert."`
+}
+
 // config defines the configuration options for lnd.
 //
 // See loadConfig for further details regarding the configuration
@@ -154,13 +232,108 @@ type config struct {
 
 	DebugLevel string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
 
+	NodeProfile string `long:"nodeprofile" description:"Apply a set of defaults tailored to a kind of node, across the switch, invoices and sweeping of on-chain outputs. Options set explicitly take precedence over the profile." choice:"routing-node" choice:"merchant" choice:"mobile"`
+
 	CPUProfile string `long:"cpuprofile" description:"Write CPU profile to the specified file"`
 
 	Profile string `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
 
+	DebugConsole string `long:"debugconsole" description:"Enable the debug console on the given localhost port, allowing the live state of the htlc switch and its links to be inspected -- NOTE port must be between 1024 and 65535"`
+
+	MetricsListen string `long:"metricslisten" description:"Serve the metrics of the htlc switch and its links over HTTP at /metrics on the given address, in the format scraped by Prometheus -- NOTE the metrics aren't authenticated, so the address should only be reachable by the monitoring system"`
+
+	DebugMessageTap bool `long:"debugmessagetap" description:"Allow the decoded wire messages exchanged with a peer to be streamed over RPC using the admin macaroon, with any payment preimages redacted"`
+
+	DebugSwitchFaults bool `long:"debugswitchfaults" description:"Allow faults to be injected into the forwarding path of the htlc switch over RPC using the admin macaroon -- NOTE this is intended for integration tests only"`
+
 	DebugHTLC          bool `long:"debughtlc" description:"Activate the debug htlc mode. With the debug HTLC mode, all payments sent use a pre-determined R-Hash. Additionally, all HTLCs sent to a node with the debug HTLC R-Hash are immediately settled in the next available state transition."`
 	HodlHTLC           bool `long:"hodlhtlc" description:"Activate the hodl HTLC mode.  With hodl HTLC mode, all incoming HTLCs will be accepted by the receiving node, but no attempt will be made to settle the payment with the sender."`
 	MaxPendingChannels int  `long:"maxpendingchannels" description:"The maximum number of incoming pending channels permitted per peer."`
+	MatchChanReserve   bool `long:"matchchanreserve" description:"If the initiator of an incoming channel proposes a lower reserve for our side of the channel than the default, require the initiator to maintain the same reserve."`
+
+	MaxValueInFlightPct         uint32 `long:"maxvalueinflightpct" description:"The maximum total value of unresolved HTLCs the remote party may offer us within a new channel, as a percentage of the channel capacity. Set to 0 to use the default of the full capacity, less the remote party's reserve."`
+	MinAcceptedValueInFlightPct uint32 `long:"minacceptedvalueinflightpct" description:"The smallest maximum value in flight, as a percentage of the channel capacity, that we'll accept the remote party proposing for our side of a new channel. Set to 0 to accept any value."`
+
+	AnnouncementDepth uint32 `long:"announcementdepth" description:"The number of confirmations the funding transaction of a public channel must reach before the channel is announced, unless specified when opening the channel. Values of 6 or less use the default of 6 confirmations."`
+
+	HTLCSweepBudget   float64 `long:"htlcsweepbudget" description:"The maximum fee we'll pay to sweep an HTLC output on-chain, as a fraction of its value. Outputs which can't be swept within this budget are abandoned. Set to 0 to permit the fee to consume the entire value."`
+	CommitSweepBudget float64 `long:"commitsweepbudget" description:"The maximum fee we'll pay to sweep our output on a commitment transaction, as a fraction of its value. Outputs which can't be swept within this budget are abandoned. Set to 0 to permit the fee to consume the entire value."`
+
+	StuckHTLCThreshold time.Duration `long:"stuckhtlcthreshold" description:"The amount of time an outgoing HTLC may remain unresolved before a warning is logged for it. Set to 0 to disable."`
+
+	ChanSyncTimeout time.Duration `long:"chansynctimeout" description:"The amount of time to wait for a peer's channel reestablishment message upon reconnection, before re-sending ours or giving up"`
+	ChanSyncRetries int           `long:"chansyncretries" description:"The number of times to re-send our channel reestablishment message to a peer that hasn't responded in time, before failing the channel's link"`
+
+	LinkRestartBackoff    time.Duration `long:"linkrestartbackoff" description:"The delay imposed on the restart of a channel's link once its peer has reconnected twice within a minute of the link starting, doubled with each further reconnection. Set to 0 to disable. Valid time units are {s, m, h}."`
+	MaxLinkRestartBackoff time.Duration `long:"maxlinkrestartbackoff" description:"The maximum delay imposed on the restart of a channel's link. Valid time units are {s, m, h}."`
+
+	LinkBatchSize              uint32        `long:"linkbatchsize" description:"The number of updates a channel batches before initiating a commitment update. Larger batches mean fewer signatures, at the expense of latency."`
+	LinkBatchTicker            time.Duration `long:"linkbatchticker" description:"The interval at which a channel commits any updates it has batched, should the batch not fill up in the meantime"`
+	LinkPendingCommitTicker    time.Duration `long:"linkpendingcommitticker" description:"The minimum amount of time a channel waits after receiving a commitment signature, before checking whether it owes the peer one in return. The wait is extended to a multiple of the measured round trip time to the peer."`
+	LinkMaxPendingCommitTicker time.Duration `long:"linkmaxpendingcommitticker" description:"The maximum amount of time a channel waits after receiving a commitment signature, before checking whether it owes the peer one in return, however long the round trip time to the peer"`
+
+	ForwardAllow []string `long:"forwardallow" description:"The hex-encoded public key of a peer HTLCs may be forwarded from or to. If set, forwards involving any other peer are rejected. Can be specified multiple times."`
+	ForwardDeny  []string `long:"forwarddeny" description:"The hex-encoded public key of a peer HTLCs won't be forwarded from or to. Can be specified multiple times."`
+
+	MaxForwardHourly     lnwire.MilliSatoshi `long:"maxforwardhourly" description:"The maximum total value, in millisatoshi, of the HTLCs forwarded within an hour. Forwards above the cap are failed. A value of 0 disables the cap."`
+	MaxForwardDaily      lnwire.MilliSatoshi `long:"maxforwarddaily" description:"The maximum total value, in millisatoshi, of the HTLCs forwarded within a day. Forwards above the cap are failed. A value of 0 disables the cap."`
+	MaxPeerForwardHourly lnwire.MilliSatoshi `long:"maxpeerforwardhourly" description:"The maximum total value, in millisatoshi, of the HTLCs forwarded from or to a single peer within an hour. Forwards above the cap are failed. A value of 0 disables the cap."`
+	MaxPeerForwardDaily  lnwire.MilliSatoshi `long:"maxpeerforwarddaily" description:"The maximum total value, in millisatoshi, of the HTLCs forwarded from or to a single peer within a day. Forwards above the cap are failed. A value of 0 disables the cap."`
+
+	StrictUnknownMsgs bool   `long:"strictunknownmsgs" description:"Enforce the \"it's ok to be odd\" rule of BOLT #1 for messages of an unknown type. Unknown odd messages are ignored, while an unknown even message fails the channel it targets, or the connection if it doesn't target a channel."`
+	MaxUnknownMsgs    uint32 `long:"maxunknownmsgs" description:"The number of messages of an unknown type a peer may send before it's disconnected. Set to 0 to disable."`
+
+	PeerPolicies []string `long:"peerpolicy" description:"The default routing policy to use for new channels with a particular peer, in place of the chain's default fees and time lock delta, in the form <pubkey>:<base_fee_msat>:<fee_rate>:<time_lock_delta>. The fee rate is expressed in millionths. Can be specified multiple times."`
+
+	OverflowPolicy string `long:"overflowpolicy" description:"How outgoing HTLCs are handled once a channel holds the maximum number of HTLCs. 'queue' holds them until a slot is freed, 'reject' immediately fails them back, and 'replace' queues them, but evicts the queued HTLC paying the lowest fee in favor of a newcomer paying a higher fee once the queue is full." choice:"queue" choice:"reject" choice:"replace"`
+
+	MaxOverflowQueueLen  int32         `long:"maxoverflowqueuelen" description:"The maximum number of HTLCs queued for each channel once it holds the maximum number of HTLCs. Once reached, new HTLCs are failed back, unless they can be forwarded over another channel with the same peer. Set to 0 to disable."`
+	MaxOverflowResidency time.Duration `long:"maxoverflowresidency" description:"The maximum amount of time the oldest HTLC queued for a channel may wait for the channel to free a slot. Once exceeded, new HTLCs are failed back until the queue drains, unless they can be forwarded over another channel with the same peer. Set to 0 to disable. Valid time units are {s, m, h}."`
+
+	FeeSpikeMultiplier float64 `long:"feespikemultiplier" description:"Decline to forward HTLCs whose on-chain claim fee at the prevailing fee rate, multiplied by this factor, exceeds their value. Such HTLCs would be lost should their channel be force closed during a fee spike. Set to 0 to disable."`
+
+	MinFeeUpdateInterval time.Duration `long:"minfeeupdateinterval" description:"The minimum amount of time between two commitment fee updates sent for a channel we opened, so that the peer isn't flooded with updates while the fee estimate is volatile. Set to 0 to disable. Valid time units are {s, m, h}."`
+	MaxFeeUpdateStep     float64       `long:"maxfeeupdatestep" description:"The maximum change of the commitment fee rate within a single update of a channel we opened, as a fraction of its current fee rate. Larger changes are spread across several updates. Set to 0 to disable."`
+
+	MinRemoteFeeRate   btcutil.Amount `long:"minremotefeerate" description:"The lowest commitment fee rate, in sat/kw, we'll accept from a peer for a channel it opened. A fee update below it fails the channel. Set to 0 to disable."`
+	MaxRemoteFeeRate   btcutil.Amount `long:"maxremotefeerate" description:"The highest commitment fee rate, in sat/kw, we'll accept from a peer for a channel it opened. A fee update above it fails the channel. Set to 0 to disable."`
+	RemoteFeeTolerance float64        `long:"remotefeetolerance" description:"The multiple of our own fee estimate the commitment fee rate proposed by a peer for a channel it opened may deviate by, in either direction. A fee update outside of it fails the channel. Set to 0 to disable."`
+
+	MaxLinkHtlcs      uint16              `long:"maxlinkhtlcs" description:"The maximum number of unresolved HTLCs permitted in either direction of each channel. HTLCs in excess of it are failed back, rather than causing the channel to be closed. Set to 0 to only enforce the protocol limit."`
+	MaxLinkPendingAmt lnwire.MilliSatoshi `long:"maxlinkpendingamt" description:"The maximum total value, in millisatoshis, of unresolved HTLCs permitted in either direction of each channel. HTLCs in excess of it are failed back. Set to 0 to disable."`
+
+	MailBoxMaxMsgs int `long:"mailboxmaxmsgs" description:"The maximum number of messages from a peer queued for each of its channels. Once reached, reading further messages from the peer is paused until the channel catches up. Set to 0 to disable."`
+	MailBoxMaxPkts int `long:"mailboxmaxpkts" description:"The maximum number of HTLCs, settles and fails queued to be offered over each channel. Once reached, new HTLCs are failed back immediately, while settles and fails are still queued. Set to 0 to disable."`
+
+	SafeExitSettle bool `long:"safeexitsettle" description:"Only settle HTLCs paying to our invoices once they're irrevocably committed to the commitment transactions of both parties, and any registered HTLC acceptor has accepted them"`
+
+	StatelessInvoices bool `long:"statelessinvoices" description:"Allow the creation of stateless invoices, which aren't stored. Their preimage is derived from a secret key and the terms of the invoice, which the payer hands back within the onion, allowing payments to them to be settled without any invoice on disk. Experimental: the record within the onion is specific to lnd, so such invoices can only be paid by lnd nodes"`
+
+	RejectLegacyPayments bool `long:"rejectlegacypayments" description:"Reject payments to our invoices which don't carry the invoice's payment secret within their onion, as sent by legacy payers, logging the channel they arrived over. Payers only learn the secret from the payment request, so this prevents intermediate nodes from probing for our invoices. The secret is carried within onion records specific to lnd, so this also rejects payments from other implementations."`
+
+	MaxOverpaymentPct uint32 `long:"maxoverpaymentpct" description:"The percentage of the value of an invoice by which a payment to it may exceed the value. The amount actually paid is recorded within the invoice. Set to 0 to only accept payments of the exact value."`
+
+	InvoiceExpiry time.Duration `long:"invoiceexpiry" description:"The expiry of invoices which don't specify one. Set to 0 to use the default of the payment request encoding, which is one hour."`
+
+	MaxPendingSettles int `long:"maxpendingsettles" description:"The number of invoice settles that may be written to the database concurrently. Once reached, HTLCs paying to our invoices are held, accepted but unsettled, until the database catches up, rather than blocking their links. Set to 0 to never hold them."`
+
+	FinalCltvGrace uint32 `long:"finalcltvgrace" description:"The number of blocks an incoming HTLC paying to one of our invoices must have left until its expiry to be accepted."`
+	MinCltvDelta   uint32 `long:"mincltvdelta" description:"The minimum time lock delta we require for forwarded HTLCs, enforced in place of any lower delta of a channel's routing policy. It must be at least the broadcast delta of the chain arbitrator."`
+
+	HtlcExpiryGrace uint32 `long:"htlcexpirygrace" description:"The number of blocks prior to the expiry of an incoming HTLC we know the preimage for, at which its channel is force closed to claim the HTLC on-chain, should the remote party not have removed it by then. Expired outgoing HTLCs cause their channel to be force closed as well, and are only cancelled back once it's closed. Set to 0 to disable."`
+
+	ExperimentalKeysend bool `long:"experimentalkeysend" description:"Enable sending and accepting experimental keysend payments, which carry their preimage within the onion, settling them without a prior invoice. The preimage is carried within additional onion payloads in an encoding specific to lnd, so these payments aren't compatible with the keysend payments of other implementations."`
+
+	ExperimentalAmp bool `long:"experimentalamp" description:"Accept experimental AMP (atomic multi-path) payments, whose parts carry shares of a root seed within their onions, settling all parts once they've arrived without a prior invoice. The AMP records are carried within additional onion payloads in an encoding specific to lnd, so AMP payments of other implementations aren't accepted."`
+
+	ExperimentalEndorsement bool `long:"experimentalendorsement" description:"Enable the experimental HTLC endorsement signal. Endorsements of incoming HTLCs are relayed when forwarding, and unendorsed HTLCs are restricted to half of each channel's HTLC slots and capacity."`
+
+	PeerStorage      bool `long:"peerstorage" description:"Enable the peer storage feature. We'll store a small encrypted backup of our channels with peers that support the feature, and store a blob on behalf of each of our channel peers in return."`
+	PeerStorageQuota int  `long:"peerstoragequota" description:"The maximum size in bytes of a blob we'll store on behalf of a single peer."`
+
+	MinPeerScore float64 `long:"minpeerscore" description:"The minimum score, between 0 and 1, a peer must have for autopilot to open channels to it, or for us to accept channels from it. The score is derived from the lifetime HTLC statistics of all channels we've held with the peer, with peers we've no history with scoring 0.5. Set to 0 to disable."`
+
+	OnChainReservePerChan int64 `long:"onchainreserveperchan" description:"The confirmed on-chain balance, in satoshis, set aside for each open or pending channel to pay the fees of force closes, fee bumps, and sweeps. Sending coins or opening a channel is refused if it would leave less than the reserve, and, if alerts are configured, an alert is raised once the balance falls below it. Set to 0 to disable."`
 
 	Bitcoin      *chainConfig    `group:"Bitcoin" namespace:"bitcoin"`
 	BtcdMode     *btcdConfig     `group:"btcd" namespace:"btcd"`
@@ -172,10 +345,24 @@ type config struct {
 
 	Autopilot *autoPilotConfig `group:"autopilot" namespace:"autopilot"`
 
+	HealthCheck *healthCheckConfig `group:"healthcheck" namespace:"healthcheck"`
+
+	AutoClose *autoCloseConfig `group:"autoclose" namespace:"autoclose"`
+
+	LiquidityHistory *liquidityHistoryConfig `group:"liquidityhistory" namespace:"liquidityhistory"`
+
+	SelfCheck *selfCheckConfig `group:"selfcheck" namespace:"selfcheck"`
+
+	Alerts *alertsConfig `group:"alerts" namespace:"alerts"`
+
 	NoNetBootstrap bool `long:"nobootstrap" description:"If true, then automatic network bootstrapping will not be attempted."`
 
 	NoEncryptWallet bool `long:"noencryptwallet" description:"If set, wallet will be encrypted using the default passphrase."`
 
+	ForceUnlockDB bool `long:"forceunlockdb" description:"Take over the lock of the channel database, even if it appears to be held by another running instance of lnd. Only use this for recovery, once certain that no other instance is using the database, as two instances operating on the same channels will lead to a loss of funds."`
+
+	CommitHookCmd string `long:"commithookcmd" description:"A command to execute each time the commitment state of a channel has been persisted, e.g. to trigger replication of the channel database. The channel point, commitment height and type of state transition are passed as arguments."`
+
 	TrickleDelay int `long:"trickledelay" description:"Time in milliseconds between each release of announcements to the network"`
 
 	Alias string `long:"alias" description:"The node alias. Used as a moniker by peers and intelligence services"`
@@ -186,10 +373,12 @@ type config struct {
 // line options.
 //
 // The configuration proceeds as follows:
-// 	1) Start with a default config with sane settings
-// 	2) Pre-parse the command line to check for an alternative config file
-// 	3) Load configuration file overwriting defaults with any specified options
-// 	4) Parse CLI options and overwrite/add any specified options
+//  1. Start with a default config with sane settings
+//  2. Pre-parse the command line to check for an alternative config file
+//  3. Apply the defaults of the node profile selected on the command line or
+//     within the configuration file, if any
+//  4. Load configuration file overwriting defaults with any specified options
+//  5. Parse CLI options and overwrite/add any specified options
 func loadConfig() (*config, error) {
 	defaultCfg := config{
 		ConfigFile:   defaultConfigFile,
@@ -225,12 +414,57 @@ func loadConfig() (*config, error) {
 			RPCHost: defaultRPCHost,
 			RPCCert: defaultLtcdRPCCertFile,
 		},
-		MaxPendingChannels: defaultMaxPendingChannels,
-		NoEncryptWallet:    defaultNoEncryptWallet,
+		MaxPendingChannels:   defaultMaxPendingChannels,
+		StuckHTLCThreshold:   defaultStuckHTLCThreshold,
+		FinalCltvGrace:       htlcswitch.DefaultFinalCltvGrace,
+		MinCltvDelta:         defaultMinCltvDelta,
+		MinFeeUpdateInterval: htlcswitch.DefaultMinFeeUpdateInterval,
+		RemoteFeeTolerance:   defaultRemoteFeeTolerance,
+		HtlcExpiryGrace:      defaultHtlcExpiryGrace,
+		MaxPendingSettles:    defaultMaxPendingSettles,
+		ChanSyncTimeout:      defaultChanSyncTimeout,
+		ChanSyncRetries:      defaultChanSyncRetries,
+		PeerStorageQuota:     lnwire.MaxPeerStorageBlobSize,
+		OverflowPolicy:       "queue",
+		NoEncryptWallet:      defaultNoEncryptWallet,
 		Autopilot: &autoPilotConfig{
 			MaxChannels: 5,
 			Allocation:  0.6,
 		},
+		HealthCheck: &healthCheckConfig{
+			Interval:    defaultHealthCheckInterval,
+			Timeout:     defaultHealthCheckTimeout,
+			MaxFailures: defaultHealthCheckMaxFailures,
+		},
+		AutoClose: &autoCloseConfig{
+			Interval: defaultAutoCloseInterval,
+		},
+		LiquidityHistory: &liquidityHistoryConfig{
+			Interval:  defaultLiquidityHistoryInterval,
+			Retention: defaultLiquidityHistoryRetention,
+		},
+		SelfCheck: &selfCheckConfig{
+			Amt:        int64(defaultSelfCheckAmt),
+			MaxLatency: defaultSelfCheckMaxLatency,
+			Timeout:    defaultSelfCheckTimeout,
+		},
+		Alerts: &alertsConfig{
+			Timeout:       defaultAlertTimeout,
+			Interval:      defaultAlertInterval,
+			PeerOffline:   defaultAlertPeerOffline,
+			SweepDeadline: defaultAlertSweepDeadline,
+		},
+		LinkBatchSize:              defaultLinkBatchSize,
+		LinkBatchTicker:            defaultLinkBatchTicker,
+		LinkPendingCommitTicker:    defaultLinkPendingCommitTicker,
+		LinkMaxPendingCommitTicker: defaultLinkMaxPendingCommitTicker,
+		MailBoxMaxMsgs:             defaultMailBoxMaxMsgs,
+		MailBoxMaxPkts:             defaultMailBoxMaxPkts,
+		MaxOverflowQueueLen:        defaultMaxOverflowQueueLen,
+		MaxOverflowResidency:       defaultMaxOverflowResidency,
+		LinkRestartBackoff:         defaultLinkRestartBackoff,
+		MaxLinkRestartBackoff:      defaultMaxLinkRestartBackoff,
+
 		TrickleDelay: defaultTrickleDelay,
 		Alias:        defaultAlias,
 		Color:        defaultColor,
@@ -271,6 +505,21 @@ func loadConfig() (*config, error) {
 		return nil, err
 	}
 
+	// If a node profile was selected, either on the command line or within
+	// the config file, then we'll apply its defaults before parsing the
+	// config file and command line options, so that any options specified
+	// explicitly take precedence over those of the profile. Any error
+	// parsing the config file is reported below.
+	nodeProfile := preCfg.NodeProfile
+	if nodeProfile == "" {
+		profileCfg := defaultCfg
+		flags.IniParse(preCfg.ConfigFile, &profileCfg)
+		nodeProfile = profileCfg.NodeProfile
+	}
+	if err := applyNodeProfile(&defaultCfg, nodeProfile); err != nil {
+		return nil, err
+	}
+
 	// Next, load any additional configuration options from the file.
 	var configFileError error
 	cfg := defaultCfg
@@ -284,6 +533,26 @@ func loadConfig() (*config, error) {
 		return nil, err
 	}
 
+	// Ensure that we'd be able to claim the HTLCs we accept on-chain. An
+	// HTLC paying to us must have at least one block left until its
+	// expiry, while a forwarded one must leave us enough time to go to the
+	// chain once its outgoing counterpart is settled.
+	if cfg.FinalCltvGrace == 0 {
+		str := "%s: The finalcltvgrace must be at least 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.MinCltvDelta < defaultBroadcastDelta {
+		str := "%s: The mincltvdelta must be at least the broadcast " +
+			"delta of %v"
+		err := fmt.Errorf(str, funcName, defaultBroadcastDelta)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	switch {
 	// At this moment, multiple active chains are not supported.
 	case cfg.Litecoin.Active && cfg.Bitcoin.Active:
@@ -303,9 +572,9 @@ func loadConfig() (*config, error) {
 			return nil, fmt.Errorf(str, funcName)
 		}
 
-		if cfg.Litecoin.TimeLockDelta < minTimeLockDelta {
+		if cfg.Litecoin.TimeLockDelta < cfg.MinCltvDelta {
 			return nil, fmt.Errorf("timelockdelta must be at least %v",
-				minTimeLockDelta)
+				cfg.MinCltvDelta)
 		}
 
 		if cfg.Litecoin.Node != "btcd" {
@@ -358,9 +627,9 @@ func loadConfig() (*config, error) {
 			return nil, err
 		}
 
-		if cfg.Bitcoin.TimeLockDelta < minTimeLockDelta {
+		if cfg.Bitcoin.TimeLockDelta < cfg.MinCltvDelta {
 			return nil, fmt.Errorf("timelockdelta must be at least %v",
-				minTimeLockDelta)
+				cfg.MinCltvDelta)
 		}
 
 		switch cfg.Bitcoin.Node {
@@ -407,6 +676,296 @@ func loadConfig() (*config, error) {
 		}
 	}
 
+	// Ensure that the default invoice expiry isn't negative.
+	if cfg.InvoiceExpiry < 0 {
+		str := "%s: The invoice expiry must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that the mailbox limits aren't negative.
+	if cfg.MailBoxMaxMsgs < 0 || cfg.MailBoxMaxPkts < 0 {
+		str := "%s: The mailbox limits must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that the overflow queue budget isn't negative.
+	if cfg.MaxOverflowQueueLen < 0 || cfg.MaxOverflowResidency < 0 {
+		str := "%s: The overflow queue limits must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that the fee spike multiplier isn't negative.
+	if cfg.FeeSpikeMultiplier < 0 {
+		str := "%s: The feespikemultiplier must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that neither the minimum interval between fee updates, nor
+	// their maximum step is negative.
+	if cfg.MinFeeUpdateInterval < 0 {
+		str := "%s: The minfeeupdateinterval must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.MaxFeeUpdateStep < 0 {
+		str := "%s: The maxfeeupdatestep must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that the bounds of the commitment fee rates we accept from
+	// our peers are sane.
+	switch {
+	case cfg.MinRemoteFeeRate < 0 || cfg.MaxRemoteFeeRate < 0:
+		str := "%s: The minremotefeerate and maxremotefeerate must " +
+			"not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.MaxRemoteFeeRate != 0 &&
+		cfg.MaxRemoteFeeRate < cfg.MinRemoteFeeRate:
+
+		str := "%s: The maxremotefeerate must not be below the " +
+			"minremotefeerate"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.RemoteFeeTolerance != 0 && cfg.RemoteFeeTolerance < 1:
+		str := "%s: The remotefeetolerance must be 0 or at least 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that the maximum number of pending settles isn't negative.
+	if cfg.MaxPendingSettles < 0 {
+		str := "%s: The maximum number of pending settles must not " +
+			"be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that the minimum peer score is a valid score.
+	if cfg.MinPeerScore < 0 || cfg.MinPeerScore > 1 {
+		str := "%s: The minimum peer score must be between 0 and 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that the on-chain reserve isn't negative.
+	if cfg.OnChainReservePerChan < 0 {
+		str := "%s: The on-chain reserve must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that the sweep budgets are valid fractions.
+	if cfg.HTLCSweepBudget < 0 || cfg.HTLCSweepBudget > 1 ||
+		cfg.CommitSweepBudget < 0 || cfg.CommitSweepBudget > 1 {
+
+		str := "%s: The sweep budgets must be between 0 and 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that we'll wait for, and retry, the channel reestablishment
+	// with our peers sensibly.
+	if cfg.ChanSyncTimeout <= 0 || cfg.ChanSyncRetries < 0 {
+		str := "%s: The chansynctimeout must be positive, and the " +
+			"chansyncretries must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that the link restart backoffs are sensible.
+	if cfg.LinkRestartBackoff < 0 ||
+		cfg.MaxLinkRestartBackoff < cfg.LinkRestartBackoff {
+
+		str := "%s: The linkrestartbackoff must not be negative, " +
+			"nor exceed the maxlinkrestartbackoff"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate debug console port number.
+	if cfg.DebugConsole != "" {
+		consolePort, err := strconv.Atoi(cfg.DebugConsole)
+		if err != nil || consolePort < 1024 || consolePort > 65535 {
+			str := "%s: The debug console port must be between " +
+				"1024 and 65535"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+	}
+
+	// Validate the address of the metrics exporter.
+	if cfg.MetricsListen != "" {
+		_, _, err := net.SplitHostPort(cfg.MetricsListen)
+		if err != nil {
+			str := "%s: Invalid metricslisten address: %v"
+			err := fmt.Errorf(str, funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+	}
+
+	// Ensure that the health check parameters are sane if health checks
+	// haven't been disabled.
+	if cfg.HealthCheck.Interval != 0 && (cfg.HealthCheck.Timeout <= 0 ||
+		cfg.HealthCheck.MaxFailures == 0) {
+
+		str := "%s: healthcheck.timeout and healthcheck.maxfailures " +
+			"must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure that any per-peer default routing policies are well formed.
+	if _, err := parsePeerPolicies(
+		cfg.PeerPolicies, cfg.MinCltvDelta,
+	); err != nil {
+		str := "%s: invalid peerpolicy: %v"
+		err := fmt.Errorf(str, funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// If channels are to be closed automatically, then the criteria must
+	// be checked periodically, and each exempt peer must be a valid public
+	// key.
+	if cfg.AutoClose.Active && cfg.AutoClose.Interval <= 0 {
+		str := "%s: autoclose.interval must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if _, err := parseExemptPeers(cfg.AutoClose.Exempt); err != nil {
+		str := "%s: invalid autoclose.exempt: %v"
+		err := fmt.Errorf(str, funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// A negative liquidity history interval or retention is meaningless.
+	if cfg.LiquidityHistory.Interval < 0 ||
+		cfg.LiquidityHistory.Retention < 0 {
+
+		str := "%s: liquidityhistory.interval and " +
+			"liquidityhistory.retention must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// The self-check requires a pair of distinct channels, and sane
+	// parameters, if it's been enabled.
+	if (cfg.SelfCheck.OutChan == 0) != (cfg.SelfCheck.InChan == 0) {
+		str := "%s: selfcheck.outchan and selfcheck.inchan must be " +
+			"set together"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.SelfCheck.OutChan != 0 {
+		var err error
+		switch {
+		case cfg.SelfCheck.OutChan == cfg.SelfCheck.InChan:
+			err = fmt.Errorf("%s: selfcheck.outchan and "+
+				"selfcheck.inchan must be distinct", funcName)
+
+		case cfg.SelfCheck.Amt <= 0 || cfg.SelfCheck.MaxLatency <= 0 ||
+			cfg.SelfCheck.Timeout <= 0:
+
+			err = fmt.Errorf("%s: selfcheck.amt, "+
+				"selfcheck.maxlatency and selfcheck.timeout "+
+				"must be positive", funcName)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+	}
+
+	if cfg.Alerts.Timeout <= 0 || cfg.Alerts.Interval <= 0 ||
+		cfg.Alerts.PeerOffline < 0 || cfg.Alerts.MinReserve < 0 {
+
+		str := "%s: alerts.timeout and alerts.interval must be " +
+			"positive, and alerts.peeroffline and " +
+			"alerts.minreserve must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	for _, webhook := range cfg.Alerts.Webhook {
+		u, err := url.Parse(webhook)
+		if err == nil && u.Scheme != "http" && u.Scheme != "https" {
+			err = fmt.Errorf("unsupported scheme %q", u.Scheme)
+		}
+		if err != nil {
+			err = fmt.Errorf("%s: invalid alerts.webhook %v: %v",
+				funcName, webhook, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+	}
+
+	// The max value in flight percentages can't exceed the capacity of
+	// the channel.
+	if cfg.MaxValueInFlightPct > 100 || cfg.MinAcceptedValueInFlightPct > 100 {
+		str := "%s: maxvalueinflightpct and " +
+			"minacceptedvalueinflightpct must not exceed 100"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// At this point, we'll save the base data directory in order to ensure
 	// we don't store the macaroon database within any of the chain
 	// namespaced directories.
@@ -115,6 +115,20 @@ type ChainArbitratorConfig struct {
 
 	// ChainIO allows us to query the state of the current main chain.
 	ChainIO lnwallet.BlockChainIO
+
+	// SweepBudget bounds the fee we're willing to pay in order to sweep
+	// each class of output. Outputs which can't be swept within their
+	// budget are abandoned.
+	SweepBudget SweepBudget
+
+	// RecordHTLCLoss records the loss of an incoming HTLC that we settled
+	// off-chain, but which the remote party timed out on-chain before we
+	// were able to claim it.
+	RecordHTLCLoss func(*channeldb.HTLCLoss) error
+
+	// NotifyClosedChannel is called once a channel has been marked as
+	// fully closed within the database.
+	NotifyClosedChannel func(wire.OutPoint)
 }
 
 // ChainArbitrator is a sub-system that oversees the on-chain resolution of all
@@ -299,6 +313,10 @@ func (c *ChainArbitrator) resolveContract(chanPoint wire.OutPoint,
 	}
 	c.Unlock()
 
+	if c.cfg.NotifyClosedChannel != nil {
+		c.cfg.NotifyClosedChannel(chanPoint)
+	}
+
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package contractcourt
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -386,12 +387,27 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 }
 
 // toSelfAmount takes a transaction and returns the sum of all outputs that pay
-// to a script that the wallet controls. If no outputs pay to us, then we
-// return zero. This is possible as our output may have been trimmed due to
-// being dust.
+// to a script that the wallet controls, or to the delivery script we recorded
+// for a cooperative close of the channel. The latter may not belong to the
+// wallet, or be of a type the wallet is unable to recognize, such as P2TR. If
+// no outputs pay to us, then we return zero. This is possible as our output
+// may have been trimmed due to being dust.
 func (c *chainWatcher) toSelfAmount(tx *wire.MsgTx) btcutil.Amount {
+	deliveryScript, err := c.chanState.LocalDeliveryScript()
+	if err != nil {
+		log.Errorf("Unable to fetch delivery script for "+
+			"ChannelPoint(%v): %v", c.chanState.FundingOutpoint, err)
+	}
+
 	var selfAmt btcutil.Amount
 	for _, txOut := range tx.TxOut {
+		if deliveryScript != nil &&
+			bytes.Equal(txOut.PkScript, deliveryScript) {
+
+			selfAmt += btcutil.Amount(txOut.Value)
+			continue
+		}
+
 		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
 			// Doesn't matter what net we actually pass in.
 			txOut.PkScript, &chaincfg.TestNet3Params,
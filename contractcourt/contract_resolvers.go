@@ -14,6 +14,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/txscript"
 	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
 )
 
 var (
@@ -482,7 +483,9 @@ func (h *htlcSuccessResolver) Resolve() (ContractResolver, error) {
 			//
 			// TODO(roasbeef): signal up if fee would be too large
 			// to sweep singly, need to batch
-			satWeight, err := h.FeeEstimator.EstimateFeePerWeight(6)
+			satWeight, err := lnwallet.EstimateRelayableFeePerWeight(
+				h.FeeEstimator, 6,
+			)
 			if err != nil {
 				return nil, err
 			}
@@ -500,9 +503,27 @@ func (h *htlcSuccessResolver) Resolve() (ContractResolver, error) {
 			totalFees := int64(totalWeight) * int64(satWeight)
 			sweepAmt := h.htlcResolution.SweepSignDesc.Output.Value - totalFees
 
+			// If sweeping the output would exceed its budget, then
+			// we'll abandon it rather than sweep it at a loss.
+			htlcPoint := h.htlcResolution.ClaimOutpoint
+			htlcValue := btcutil.Amount(
+				h.htlcResolution.SweepSignDesc.Output.Value,
+			)
+			fee := btcutil.Amount(totalFees)
+			if !h.SweepBudget.withinBudget(htlcSweep, htlcValue, fee) {
+				reportAbandonedSweep(
+					h.ChanPoint, htlcSweep, htlcPoint,
+					htlcValue, fee, h.SweepBudget.maxFee(
+						htlcSweep, htlcValue,
+					),
+				)
+
+				h.resolved = true
+				return nil, h.Checkpoint(h)
+			}
+
 			// With the fee computation finished, we'll now
 			// construct the sweep transaction.
-			htlcPoint := h.htlcResolution.ClaimOutpoint
 			h.sweepTx = wire.NewMsgTx(2)
 			h.sweepTx.AddTxIn(&wire.TxIn{
 				PreviousOutPoint: htlcPoint,
@@ -543,6 +564,8 @@ func (h *htlcSuccessResolver) Resolve() (ContractResolver, error) {
 					h, h.payHash[:], err)
 				return nil, err
 			}
+
+			h.announcePreimage()
 		}
 
 		// With the sweep transaction broadcast, we'll wait for its
@@ -555,17 +578,48 @@ func (h *htlcSuccessResolver) Resolve() (ContractResolver, error) {
 			return nil, err
 		}
 
+		// We'll also watch for the spend of the HTLC output, as the
+		// remote party may win the race to time it out on-chain before
+		// our sweep confirms.
+		htlcPoint := h.htlcOutpoint()
+		spendNtfn, err := h.Notifier.RegisterSpendNtfn(
+			&htlcPoint, h.broadcastHeight,
+		)
+		if err != nil {
+			return nil, err
+		}
+
 		log.Infof("%T(%x): waiting for sweep tx (txid=%v) to be "+
 			"confirmed", h, h.payHash[:], sweepTXID)
 
-		select {
-		case _, ok := <-confNtfn.Confirmed:
-			if !ok {
+		htlcSpends := spendNtfn.Spend
+	sweepLoop:
+		for {
+			select {
+			case _, ok := <-confNtfn.Confirmed:
+				if !ok {
+					return nil, fmt.Errorf("quitting")
+				}
+
+				break sweepLoop
+
+			case spend, ok := <-htlcSpends:
+				if !ok {
+					return nil, fmt.Errorf("quitting")
+				}
+
+				// If the output was spent by our sweep, then
+				// we'll continue to wait for it to confirm.
+				if *spend.SpenderTxHash == sweepTXID {
+					htlcSpends = nil
+					continue
+				}
+
+				return nil, h.reportTimeoutRaceLoss(spend)
+
+			case <-h.Quit:
 				return nil, fmt.Errorf("quitting")
 			}
-
-		case <-h.Quit:
-			return nil, fmt.Errorf("quitting")
 		}
 
 		// Once the transaction has received a sufficient number of
@@ -577,15 +631,37 @@ func (h *htlcSuccessResolver) Resolve() (ContractResolver, error) {
 	log.Infof("%T(%x): broadcasting second-layer transition tx: %v",
 		h, h.payHash[:], spew.Sdump(h.htlcResolution.SignedSuccessTx))
 
+	// Before broadcasting the second layer transaction, we'll watch for
+	// the spend of the HTLC output, as the remote party may win the race
+	// to time it out on-chain.
+	htlcPoint := h.htlcOutpoint()
+	htlcSpendNtfn, err := h.Notifier.RegisterSpendNtfn(
+		&htlcPoint, h.broadcastHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+	htlcSpends := htlcSpendNtfn.Spend
+	successTxid := h.htlcResolution.SignedSuccessTx.TxHash()
+
 	// We'll now broadcast the second layer transaction so we can kick off
-	// the claiming process.
+	// the claiming process. If we're unable to, then the HTLC output may
+	// have already been spent, so we'll wait to learn by whom.
 	//
 	// TODO(roasbeef): after changing sighashes send to tx bundler
 	if err := h.PublishTx(h.htlcResolution.SignedSuccessTx); err != nil {
-		// TODO(roasbeef): detect double spends
-		return nil, err
+		log.Warnf("%T(%x): unable to publish second-layer tx, waiting "+
+			"for htlc output to be spent: %v", h, h.payHash[:], err)
+
+		ours, err := h.awaitHtlcSpend(htlcSpendNtfn, successTxid)
+		if err != nil || !ours {
+			return nil, err
+		}
+		htlcSpends = nil
 	}
 
+	h.announcePreimage()
+
 	// Otherwise, this is an output on our commitment transaction. In this
 	// case, we'll send it to the incubator, but only if we haven't already
 	// done so.
@@ -617,20 +693,53 @@ func (h *htlcSuccessResolver) Resolve() (ContractResolver, error) {
 	log.Infof("%T(%x): waiting for second-level HTLC output to be spent "+
 		"after csv_delay=%v", h, h.payHash[:], h.htlcResolution.CsvDelay)
 
-	select {
-	case _, ok := <-spendNtfn.Spend:
-		if !ok {
+secondLevelLoop:
+	for {
+		select {
+		case _, ok := <-spendNtfn.Spend:
+			if !ok {
+				return nil, fmt.Errorf("quitting")
+			}
+
+			break secondLevelLoop
+
+		// If the HTLC output is spent by anything other than our
+		// second layer transaction, then we've lost the HTLC.
+		case spend, ok := <-htlcSpends:
+			if !ok {
+				return nil, fmt.Errorf("quitting")
+			}
+
+			if *spend.SpenderTxHash == successTxid {
+				htlcSpends = nil
+				continue
+			}
+
+			return nil, h.reportTimeoutRaceLoss(spend)
+
+		case <-h.Quit:
 			return nil, fmt.Errorf("quitting")
 		}
-
-	case <-h.Quit:
-		return nil, fmt.Errorf("quitting")
 	}
 
 	h.resolved = true
 	return nil, h.Checkpoint(h)
 }
 
+// announcePreimage adds the preimage we've just used to claim the HTLC
+// on-chain to the witness beacon. The transaction claiming the HTLC is now in
+// our mempool, so by notifying the beacon's subscribers straight away, any
+// links with incoming HTLCs paying to the same hash are able to settle them
+// off-chain within the same block, rather than risk them timing out and
+// triggering further force closes.
+func (h *htlcSuccessResolver) announcePreimage() {
+	preimage := h.htlcResolution.Preimage
+	if err := h.PreimageDB.AddPreimage(preimage[:]); err != nil {
+		log.Errorf("%T(%x): unable to add witness to cache: %v",
+			h, h.payHash[:], err)
+	}
+}
+
 // Stop signals the resolver to cancel any current resolution processes, and
 // suspend.
 //
@@ -1248,7 +1357,9 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 		// First, we'll estimate the total weight so we can compute
 		// fees properly. We'll use a lax estimate, as this output is
 		// in no immediate danger.
-		satWeight, err := c.FeeEstimator.EstimateFeePerWeight(6)
+		satWeight, err := lnwallet.EstimateRelayableFeePerWeight(
+			c.FeeEstimator, 6,
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -1262,6 +1373,21 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 		totalFees := int64(totalWeight) * int64(satWeight)
 		sweepAmt := signDesc.Output.Value - totalFees
 
+		// If sweeping the output would exceed its budget, then we'll
+		// abandon it rather than sweep it at a loss.
+		outputValue := btcutil.Amount(signDesc.Output.Value)
+		fee := btcutil.Amount(totalFees)
+		if !c.SweepBudget.withinBudget(commitSweep, outputValue, fee) {
+			reportAbandonedSweep(
+				c.chanPoint, commitSweep,
+				c.commitResolution.SelfOutPoint, outputValue,
+				fee, c.SweepBudget.maxFee(commitSweep, outputValue),
+			)
+
+			c.resolved = true
+			return nil, c.Checkpoint(c)
+		}
+
 		c.sweepTx = wire.NewMsgTx(2)
 		c.sweepTx.AddTxIn(&wire.TxIn{
 			PreviousOutPoint: c.commitResolution.SelfOutPoint,
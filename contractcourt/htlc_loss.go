@@ -0,0 +1,87 @@
+package contractcourt
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// htlcOutpoint returns the outpoint of the HTLC output on the commitment
+// transaction.
+func (h *htlcSuccessResolver) htlcOutpoint() wire.OutPoint {
+	if h.htlcResolution.SignedSuccessTx != nil {
+		return h.htlcResolution.SignedSuccessTx.TxIn[0].PreviousOutPoint
+	}
+
+	return h.htlcResolution.ClaimOutpoint
+}
+
+// awaitHtlcSpend waits for the HTLC output on the commitment transaction to
+// be spent. It returns true if it was spent by our transaction with the passed
+// txid, and false if the HTLC was lost to a competing spend by the remote
+// party, in which case the loss has been reported, and the resolver is marked
+// as resolved.
+func (h *htlcSuccessResolver) awaitHtlcSpend(spendNtfn *chainntnfs.SpendEvent,
+	ourTxid chainhash.Hash) (bool, error) {
+
+	select {
+	case spend, ok := <-spendNtfn.Spend:
+		if !ok {
+			return false, fmt.Errorf("quitting")
+		}
+
+		if *spend.SpenderTxHash == ourTxid {
+			return true, nil
+		}
+
+		return false, h.reportTimeoutRaceLoss(spend)
+
+	case <-h.Quit:
+		return false, fmt.Errorf("quitting")
+	}
+}
+
+// reportTimeoutRaceLoss reports that the HTLC output has been spent by the
+// remote party, rather than by us using the preimage. As we've only come to
+// resolve the HTLC as we know its preimage, the HTLC has been settled
+// off-chain with the upstream party, so the remote party timing it out
+// on-chain, typically by winning a race around the HTLC's expiry, means its
+// value has been lost. The operator is alerted, the loss is recorded, and the
+// resolver is marked as resolved, as there's nothing left for us to claim.
+func (h *htlcSuccessResolver) reportTimeoutRaceLoss(
+	spend *chainntnfs.SpendDetail) error {
+
+	amt := btcutil.Amount(h.htlcResolution.SweepSignDesc.Output.Value)
+	htlcPoint := h.htlcOutpoint()
+
+	log.Errorf("ChannelArbitrator(%v): LOST incoming htlc with "+
+		"payment_hash=%x, output=%v, amount=%v: it was timed out "+
+		"on-chain by the remote party in txid=%v at height=%v, despite "+
+		"us knowing its preimage", h.ChanPoint, h.payHash[:], htlcPoint,
+		amt, spend.SpenderTxHash, spend.SpendingHeight)
+
+	if h.RecordHTLCLoss != nil {
+		err := h.RecordHTLCLoss(&channeldb.HTLCLoss{
+			ChanPoint:    h.ChanPoint,
+			HtlcOutpoint: htlcPoint,
+			PaymentHash:  h.payHash,
+			Amount:       amt,
+			SpendTxid:    *spend.SpenderTxHash,
+			SpendHeight:  uint32(spend.SpendingHeight),
+			DetectedAt:   time.Now(),
+		})
+		if err != nil {
+			log.Errorf("ChannelArbitrator(%v): unable to record "+
+				"loss of htlc with payment_hash=%x: %v",
+				h.ChanPoint, h.payHash[:], err)
+		}
+	}
+
+	h.resolved = true
+	return h.Checkpoint(h)
+}
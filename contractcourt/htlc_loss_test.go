@@ -0,0 +1,95 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestHtlcSuccessResolverTimeoutRaceLoss ensures that the success resolver
+// detects, and records, the loss of an HTLC which is timed out on-chain by the
+// remote party, while carrying on if the HTLC is spent by our own transaction.
+func TestHtlcSuccessResolverTimeoutRaceLoss(t *testing.T) {
+	t.Parallel()
+
+	var losses []*channeldb.HTLCLoss
+	htlcPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+	resolver := &htlcSuccessResolver{
+		htlcResolution: lnwallet.IncomingHtlcResolution{
+			ClaimOutpoint: htlcPoint,
+			SweepSignDesc: lnwallet.SignDescriptor{
+				Output: &wire.TxOut{Value: 10000},
+			},
+		},
+		payHash: [32]byte{3},
+		ResolverKit: ResolverKit{
+			ChannelArbitratorConfig: ChannelArbitratorConfig{
+				ChainArbitratorConfig: ChainArbitratorConfig{
+					RecordHTLCLoss: func(
+						loss *channeldb.HTLCLoss) error {
+
+						losses = append(losses, loss)
+						return nil
+					},
+				},
+			},
+			Checkpoint: func(ContractResolver) error {
+				return nil
+			},
+			Quit: make(chan struct{}),
+		},
+	}
+
+	ourTxid := chainhash.Hash{4}
+	theirTxid := chainhash.Hash{5}
+
+	// If the HTLC output is spent by our transaction, then nothing should
+	// be reported.
+	spendChan := make(chan *chainntnfs.SpendDetail, 1)
+	spendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint:  &htlcPoint,
+		SpenderTxHash:  &ourTxid,
+		SpendingHeight: 100,
+	}
+	ours, err := resolver.awaitHtlcSpend(
+		&chainntnfs.SpendEvent{Spend: spendChan}, ourTxid,
+	)
+	if err != nil {
+		t.Fatalf("unable to await spend: %v", err)
+	}
+	if !ours || resolver.resolved || len(losses) != 0 {
+		t.Fatalf("spend by our transaction shouldn't be a loss")
+	}
+
+	// Otherwise, the loss should be recorded, and the resolver should be
+	// marked as resolved.
+	spendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint:  &htlcPoint,
+		SpenderTxHash:  &theirTxid,
+		SpendingHeight: 101,
+	}
+	ours, err = resolver.awaitHtlcSpend(
+		&chainntnfs.SpendEvent{Spend: spendChan}, ourTxid,
+	)
+	if err != nil {
+		t.Fatalf("unable to await spend: %v", err)
+	}
+	if ours || !resolver.resolved {
+		t.Fatalf("spend by remote party should be a loss")
+	}
+	if len(losses) != 1 {
+		t.Fatalf("expected one loss, got %v", len(losses))
+	}
+
+	loss := losses[0]
+	if loss.HtlcOutpoint != htlcPoint || loss.SpendTxid != theirTxid ||
+		loss.SpendHeight != 101 || loss.Amount != 10000 ||
+		loss.PaymentHash != resolver.payHash {
+
+		t.Fatalf("unexpected loss recorded: %v", loss)
+	}
+}
@@ -0,0 +1,85 @@
+package contractcourt
+
+import (
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// SweepBudget bounds the fee we're willing to pay in order to sweep an
+// output, expressed as the maximum fraction of the output's value, for each
+// class of output we sweep. An output which can't be swept within its budget
+// is economically abandoned, rather than swept at a loss. A budget of zero,
+// or of one or more, permits the fee to consume the entire value of the
+// output.
+type SweepBudget struct {
+	// HTLC is the budget for sweeping an HTLC output.
+	HTLC float64
+
+	// CommitOutput is the budget for sweeping our output on a commitment
+	// transaction.
+	CommitOutput float64
+}
+
+// sweepOutputClass denotes the class of an output being swept, which
+// determines the budget used to sweep it.
+type sweepOutputClass uint8
+
+const (
+	// htlcSweep denotes the sweep of an HTLC output.
+	htlcSweep sweepOutputClass = iota
+
+	// commitSweep denotes the sweep of our output on a commitment
+	// transaction.
+	commitSweep
+)
+
+// String returns a human readable name for the sweepOutputClass.
+func (c sweepOutputClass) String() string {
+	switch c {
+	case htlcSweep:
+		return "htlc"
+	case commitSweep:
+		return "commitment"
+	default:
+		return "<unknown>"
+	}
+}
+
+// maxFee returns the maximum fee we're willing to pay in order to sweep an
+// output of the given class and value.
+func (b SweepBudget) maxFee(class sweepOutputClass,
+	value btcutil.Amount) btcutil.Amount {
+
+	var fraction float64
+	switch class {
+	case htlcSweep:
+		fraction = b.HTLC
+	case commitSweep:
+		fraction = b.CommitOutput
+	}
+
+	if fraction <= 0 || fraction >= 1 {
+		return value
+	}
+
+	return btcutil.Amount(float64(value) * fraction)
+}
+
+// withinBudget returns true if sweeping an output of the given class and
+// value for the given fee is within the budget. Sweeping an output is never
+// within budget if the fee would consume its entire value.
+func (b SweepBudget) withinBudget(class sweepOutputClass, value,
+	fee btcutil.Amount) bool {
+
+	return fee < value && fee <= b.maxFee(class, value)
+}
+
+// reportAbandonedSweep reports that the passed output is being economically
+// abandoned, as sweeping it would exceed its budget.
+func reportAbandonedSweep(chanPoint wire.OutPoint, class sweepOutputClass,
+	op wire.OutPoint, value, fee, maxFee btcutil.Amount) {
+
+	log.Warnf("ChannelArbitrator(%v): economically abandoning %v output "+
+		"%v: sweeping value=%v would cost fee=%v, exceeding "+
+		"budget=%v", chanPoint, class, op, value, fee, maxFee)
+}
@@ -0,0 +1,62 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// TestSweepBudget tests that the fee of a sweep is checked against the budget
+// of the class of output being swept.
+func TestSweepBudget(t *testing.T) {
+	t.Parallel()
+
+	budget := SweepBudget{
+		HTLC: 0.1,
+	}
+
+	tests := []struct {
+		name   string
+		class  sweepOutputClass
+		value  btcutil.Amount
+		fee    btcutil.Amount
+		within bool
+	}{
+		{
+			name:   "htlc fee within budget",
+			class:  htlcSweep,
+			value:  10000,
+			fee:    1000,
+			within: true,
+		},
+		{
+			name:   "htlc fee exceeds budget",
+			class:  htlcSweep,
+			value:  10000,
+			fee:    1001,
+			within: false,
+		},
+		{
+			name:   "commit output without budget",
+			class:  commitSweep,
+			value:  10000,
+			fee:    9999,
+			within: true,
+		},
+		{
+			name:   "commit output fee consumes value",
+			class:  commitSweep,
+			value:  10000,
+			fee:    10000,
+			within: false,
+		},
+	}
+
+	for _, test := range tests {
+		within := budget.withinBudget(test.class, test.value, test.fee)
+		if within != test.within {
+			t.Fatalf("%v: expected within budget to be %v, got %v",
+				test.name, test.within, within)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+)
+
+// switchIntrospector is the subset of the htlc switch's methods the debug
+// console uses to inspect its live state.
+type switchIntrospector interface {
+	// Links returns all of the channel links managed by the switch.
+	Links() ([]htlcswitch.ChannelLink, error)
+
+	// Circuits returns a copy of each of the payment circuits held by the
+	// switch.
+	Circuits() []*htlcswitch.PaymentCircuit
+}
+
+// debugConsoleCommands maps each command the debug console understands to a
+// short description of it.
+var debugConsoleCommands = map[string]string{
+	"help":       "list the available commands",
+	"links":      "dump the state of each of the switch's links",
+	"circuits":   "dump the switch's active payment circuits",
+	"goroutines": "dump the stack of each running goroutine",
+	"exit":       "close the console",
+}
+
+// debugConsole is an optional, line-based console served over a local TCP
+// port. It allows the live state of the htlc switch and its links to be
+// dumped, such as its circuits, the depths of the link mailboxes, and any
+// pending commitments, which is far richer than logs when diagnosing a
+// wedged channel. The console is only bound to the loopback interface, and
+// can be accessed using a tool such as netcat.
+type debugConsole struct {
+	listenAddr string
+	htlcSwitch switchIntrospector
+
+	listener net.Listener
+
+	// conns is the set of active console connections, which are closed
+	// when the console is stopped.
+	conns   map[net.Conn]struct{}
+	connMtx sync.Mutex
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// newDebugConsole creates a new debug console, which will listen on the
+// passed address once started.
+func newDebugConsole(listenAddr string,
+	htlcSwitch switchIntrospector) *debugConsole {
+
+	return &debugConsole{
+		listenAddr: listenAddr,
+		htlcSwitch: htlcSwitch,
+		conns:      make(map[net.Conn]struct{}),
+		quit:       make(chan struct{}),
+	}
+}
+
+// Start begins listening for console connections.
+func (d *debugConsole) Start() error {
+	listener, err := net.Listen("tcp", d.listenAddr)
+	if err != nil {
+		return err
+	}
+	d.listener = listener
+
+	srvrLog.Infof("Debug console listening on %v", listener.Addr())
+
+	d.wg.Add(1)
+	go d.acceptConnections()
+
+	return nil
+}
+
+// Stop closes the listener along with any active connections, and waits for
+// all goroutines to exit.
+func (d *debugConsole) Stop() error {
+	close(d.quit)
+	d.listener.Close()
+
+	d.connMtx.Lock()
+	for conn := range d.conns {
+		conn.Close()
+	}
+	d.connMtx.Unlock()
+
+	d.wg.Wait()
+
+	return nil
+}
+
+// acceptConnections accepts new console connections, serving each within its
+// own goroutine.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *debugConsole) acceptConnections() {
+	defer d.wg.Done()
+
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			select {
+			case <-d.quit:
+			default:
+				srvrLog.Errorf("Unable to accept debug console "+
+					"connection: %v", err)
+			}
+			return
+		}
+
+		d.connMtx.Lock()
+		d.conns[conn] = struct{}{}
+		d.connMtx.Unlock()
+
+		d.wg.Add(1)
+		go d.serveConnection(conn)
+	}
+}
+
+// serveConnection reads commands from the passed connection line by line,
+// writing the output of each back to it, until the connection is closed or
+// the exit command is received.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *debugConsole) serveConnection(conn net.Conn) {
+	defer d.wg.Done()
+	defer func() {
+		d.connMtx.Lock()
+		delete(d.conns, conn)
+		d.connMtx.Unlock()
+
+		conn.Close()
+	}()
+
+	fmt.Fprintln(conn, "lnd debug console, type 'help' for a list of "+
+		"commands")
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		cmd := strings.TrimSpace(scanner.Text())
+		switch cmd {
+		case "":
+			continue
+
+		case "exit", "quit":
+			return
+
+		default:
+			if err := d.runCommand(conn, cmd); err != nil {
+				fmt.Fprintf(conn, "error: %v\n", err)
+			}
+		}
+	}
+}
+
+// runCommand executes the passed command, writing its output to w.
+func (d *debugConsole) runCommand(w io.Writer, cmd string) error {
+	switch cmd {
+	case "help":
+		names := make([]string, 0, len(debugConsoleCommands))
+		for name := range debugConsoleCommands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Fprintf(w, "%-12v %v\n", name,
+				debugConsoleCommands[name])
+		}
+
+	case "links":
+		links, err := d.htlcSwitch.Links()
+		if err != nil {
+			return err
+		}
+
+		for _, link := range links {
+			snapshot, err := link.LinkSnapshot()
+			if err != nil {
+				fmt.Fprintf(w, "link %v: %v\n", link.ChanID(), err)
+				continue
+			}
+
+			fmt.Fprintf(w, "link chan_point=%v short_chan_id=%v\n"+
+				"  eligible=%v fully_synced=%v "+
+				"pending_remote_commit=%v commit_height=%v\n"+
+				"  batch_counter=%v mailbox_msgs=%v "+
+				"mailbox_pkts=%v overflow_queue=%v\n"+
+				"  peak_mailbox_msgs=%v peak_mailbox_pkts=%v "+
+				"mailbox_stalls=%v mailbox_rejects=%v\n"+
+				"  free_slots=%v peak_overflow_queue=%v "+
+				"oldest_residency=%v avg_residency=%v "+
+				"overflow_rejects=%v saturated=%v\n"+
+				"  fee_per_kw=%v claim_fee=%v "+
+				"uneconomical_declines=%v uneconomical_amt=%v\n"+
+				"  bandwidth=%v receivable=%v "+
+				"pending_incoming=%v pending_outgoing=%v "+
+				"last_commit_update=%v\n",
+				snapshot.ChannelPoint, snapshot.ShortChanID,
+				snapshot.EligibleToForward, snapshot.FullySynced,
+				snapshot.PendingRemoteCommit,
+				snapshot.CommitHeight, snapshot.BatchCounter,
+				snapshot.MailboxMessages, snapshot.MailboxPackets,
+				snapshot.OverflowQueueLen,
+				snapshot.MailboxStats.PeakMessages,
+				snapshot.MailboxStats.PeakPackets,
+				snapshot.MailboxStats.MessageStalls,
+				snapshot.MailboxStats.RejectedAdds,
+				snapshot.AdmissionStats.FreeSlots,
+				snapshot.AdmissionStats.PeakQueueLen,
+				snapshot.AdmissionStats.OldestResidency,
+				snapshot.AdmissionStats.AvgResidency,
+				snapshot.AdmissionStats.NumRejected,
+				snapshot.AdmissionStats.Saturated,
+				snapshot.FeeSpikeStats.FeePerKw,
+				snapshot.FeeSpikeStats.ClaimFee,
+				snapshot.FeeSpikeStats.NumDeclined,
+				snapshot.FeeSpikeStats.DeclinedAmount,
+				snapshot.Bandwidth, snapshot.ReceivableBandwidth,
+				snapshot.NumPendingIncoming,
+				snapshot.NumPendingOutgoing,
+				snapshot.LastCommitUpdate)
+		}
+		fmt.Fprintf(w, "%v links\n", len(links))
+
+	case "circuits":
+		circuits := d.htlcSwitch.Circuits()
+		for _, circuit := range circuits {
+			fmt.Fprintf(w, "circuit payment_hash=%x in=%v/%v "+
+				"out=%v/%v trace=%v\n", circuit.PaymentHash[:],
+				circuit.IncomingChanID, circuit.IncomingHTLCID,
+				circuit.OutgoingChanID, circuit.OutgoingHTLCID,
+				circuit.TraceID)
+		}
+		fmt.Fprintf(w, "%v circuits\n", len(circuits))
+
+	case "goroutines":
+		return pprof.Lookup("goroutine").WriteTo(w, 2)
+
+	default:
+		return fmt.Errorf("unknown command %q, type 'help' for a "+
+			"list of commands", cmd)
+	}
+
+	return nil
+}
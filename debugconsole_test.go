@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// mockSwitchIntrospector is a switchIntrospector which returns a static set
+// of circuits, and no links.
+type mockSwitchIntrospector struct {
+	circuits []*htlcswitch.PaymentCircuit
+}
+
+func (m *mockSwitchIntrospector) Links() ([]htlcswitch.ChannelLink, error) {
+	return nil, nil
+}
+
+func (m *mockSwitchIntrospector) Circuits() []*htlcswitch.PaymentCircuit {
+	return m.circuits
+}
+
+// TestDebugConsole tests that commands sent to the debug console are answered,
+// and that the connection is closed once the exit command is received.
+func TestDebugConsole(t *testing.T) {
+	t.Parallel()
+
+	introspector := &mockSwitchIntrospector{
+		circuits: []*htlcswitch.PaymentCircuit{
+			{
+				PaymentHash:    [32]byte{1},
+				IncomingChanID: lnwire.NewShortChanIDFromInt(1),
+				OutgoingChanID: lnwire.NewShortChanIDFromInt(2),
+			},
+		},
+	}
+	console := newDebugConsole("127.0.0.1:0", introspector)
+	if err := console.Start(); err != nil {
+		t.Fatalf("unable to start debug console: %v", err)
+	}
+	defer console.Stop()
+
+	conn, err := net.Dial("tcp", console.listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unable to connect to debug console: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	readLine := func() string {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unable to read from debug console: %v", err)
+		}
+		return line
+	}
+
+	// We'll skip the greeting, then dump the circuits, expecting the
+	// single circuit to be reported.
+	readLine()
+	if _, err := conn.Write([]byte("circuits\n")); err != nil {
+		t.Fatalf("unable to write to debug console: %v", err)
+	}
+	if line := readLine(); !strings.HasPrefix(line, "circuit ") {
+		t.Fatalf("expected circuit, instead got: %v", line)
+	}
+	if line := readLine(); line != "1 circuits\n" {
+		t.Fatalf("unexpected circuit count: %v", line)
+	}
+
+	// An unknown command should result in an error being reported.
+	if _, err := conn.Write([]byte("foo\n")); err != nil {
+		t.Fatalf("unable to write to debug console: %v", err)
+	}
+	if line := readLine(); !strings.HasPrefix(line, "error: ") {
+		t.Fatalf("expected error, instead got: %v", line)
+	}
+
+	// Finally, the connection should be closed once we exit.
+	if _, err := conn.Write([]byte("exit\n")); err != nil {
+		t.Fatalf("unable to write to debug console: %v", err)
+	}
+	if _, err := reader.ReadString('\n'); err == nil {
+		t.Fatalf("expected connection to be closed")
+	}
+}
@@ -0,0 +1,561 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// defaultDebugLogEntries is the number of recent log entries retained
+	// in memory for inclusion within debug packages.
+	defaultDebugLogEntries = 5000
+
+	// debugLogTimeFormat is the timestamp format of the entries written by
+	// the logging backend.
+	debugLogTimeFormat = "2006-01-02 15:04:05.000"
+
+	// redactedPlaceholder replaces any potential secret within a debug
+	// package.
+	redactedPlaceholder = "<redacted>"
+)
+
+var (
+	// recentLogs retains the most recent entries written by the logging
+	// backend, so they can be included within debug packages.
+	recentLogs = newLogRing(defaultDebugLogEntries)
+
+	// secretPattern matches any 32-byte value encoded as hex. Payment
+	// preimages, private keys and revocation secrets are all of this
+	// form, so any such value is redacted from the logs included within a
+	// debug package. This also redacts payment hashes and txids found in
+	// the logs, which is the price of ensuring no secret slips through.
+	// Compressed public keys are 33 bytes, and are left intact.
+	secretPattern = regexp.MustCompile(`\b[0-9a-fA-F]{64}\b`)
+)
+
+// logRing is a fixed size ring buffer of the most recent log entries. It
+// implements the io.Writer interface, where each write is a single entry.
+type logRing struct {
+	mu      sync.Mutex
+	entries []string
+	next    int
+	full    bool
+}
+
+// newLogRing returns a logRing which retains up to size entries.
+func newLogRing(size int) *logRing {
+	return &logRing{
+		entries: make([]string, size),
+	}
+}
+
+// Write appends a new entry to the ring, evicting the oldest entry if the
+// ring is full.
+//
+// NOTE: Part of the io.Writer interface.
+func (r *logRing) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.entries) == 0 {
+		return len(p), nil
+	}
+
+	r.entries[r.next] = string(p)
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+
+	return len(p), nil
+}
+
+// Entries returns up to the last n entries written to the ring, ordered from
+// oldest to newest. If n is zero, then all retained entries are returned.
+func (r *logRing) Entries(n int) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var entries []string
+	if r.full {
+		entries = append(entries, r.entries[r.next:]...)
+	}
+	entries = append(entries, r.entries[:r.next]...)
+
+	if n > 0 && n < len(entries) {
+		entries = entries[len(entries)-n:]
+	}
+
+	return entries
+}
+
+// redactSecrets replaces any potential secret within the passed string with a
+// placeholder.
+func redactSecrets(s string) string {
+	return secretPattern.ReplaceAllString(s, redactedPlaceholder)
+}
+
+// debugLogEntry is a structured log entry included within a debug package.
+type debugLogEntry struct {
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	Level     string    `json:"level,omitempty"`
+	Subsystem string    `json:"subsystem,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// parseLogEntry parses a raw entry written by the logging backend, which is of
+// the form "<date> <time> [<level>] <subsystem>: <message>". Entries that
+// aren't of this form are returned with only their message populated. The
+// message is stripped of any potential secrets.
+func parseLogEntry(raw string) *debugLogEntry {
+	raw = strings.TrimRight(raw, "\n")
+	entry := &debugLogEntry{
+		Message: redactSecrets(raw),
+	}
+
+	parts := strings.SplitN(raw, " ", 5)
+	if len(parts) != 5 {
+		return entry
+	}
+
+	level, subsystem := parts[2], parts[3]
+	if len(level) != 5 || level[0] != '[' || level[4] != ']' ||
+		!strings.HasSuffix(subsystem, ":") {
+
+		return entry
+	}
+
+	timestamp, err := time.ParseInLocation(
+		debugLogTimeFormat, parts[0]+" "+parts[1], time.Local,
+	)
+	if err != nil {
+		return entry
+	}
+
+	entry.Timestamp = timestamp
+	entry.Level = level[1:4]
+	entry.Subsystem = strings.TrimSuffix(subsystem, ":")
+	entry.Message = redactSecrets(parts[4])
+
+	return entry
+}
+
+// debugNodeInfo describes the node which assembled a debug package.
+type debugNodeInfo struct {
+	IdentityPubKey string    `json:"identity_pubkey"`
+	Version        string    `json:"version"`
+	Network        string    `json:"network"`
+	BlockHeight    int32     `json:"block_height"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// debugHTLC summarizes a pending HTLC. The onion blob and signature of the
+// HTLC are omitted.
+type debugHTLC struct {
+	Incoming    bool                `json:"incoming"`
+	Amount      lnwire.MilliSatoshi `json:"amount_msat"`
+	Expiry      uint32              `json:"expiry"`
+	HtlcIndex   uint64              `json:"htlc_index"`
+	PaymentHash string              `json:"payment_hash"`
+}
+
+// debugCommitment summarizes a commitment of a channel. The commitment
+// transaction and signature are omitted.
+type debugCommitment struct {
+	CommitHeight    uint64              `json:"commit_height"`
+	LocalLogIndex   uint64              `json:"local_log_index"`
+	LocalHtlcIndex  uint64              `json:"local_htlc_index"`
+	RemoteLogIndex  uint64              `json:"remote_log_index"`
+	RemoteHtlcIndex uint64              `json:"remote_htlc_index"`
+	LocalBalance    lnwire.MilliSatoshi `json:"local_balance_msat"`
+	RemoteBalance   lnwire.MilliSatoshi `json:"remote_balance_msat"`
+	CommitFee       btcutil.Amount      `json:"commit_fee"`
+	FeePerKw        btcutil.Amount      `json:"fee_per_kw"`
+	Htlcs           []debugHTLC         `json:"htlcs"`
+}
+
+// debugConstraints summarizes the constraints one party of a channel imposes
+// upon the other.
+type debugConstraints struct {
+	CsvDelay         uint16              `json:"csv_delay"`
+	DustLimit        btcutil.Amount      `json:"dust_limit"`
+	ChanReserve      btcutil.Amount      `json:"chan_reserve"`
+	MaxPendingAmount lnwire.MilliSatoshi `json:"max_pending_amount_msat"`
+	MinHTLC          lnwire.MilliSatoshi `json:"min_htlc_msat"`
+	MaxAcceptedHtlcs uint16              `json:"max_accepted_htlcs"`
+}
+
+// debugChannel is the sanitized metadata of an open channel. The keys,
+// revocation state and signatures of the channel are omitted.
+type debugChannel struct {
+	ChannelPoint      string              `json:"channel_point"`
+	ShortChanID       uint64              `json:"short_chan_id"`
+	RemotePubKey      string              `json:"remote_pubkey"`
+	Capacity          btcutil.Amount      `json:"capacity"`
+	IsPending         bool                `json:"is_pending"`
+	IsInitiator       bool                `json:"is_initiator"`
+	IsBorked          bool                `json:"is_borked"`
+	ChannelFlags      lnwire.FundingFlag  `json:"channel_flags"`
+	TotalMSatSent     lnwire.MilliSatoshi `json:"total_msat_sent"`
+	TotalMSatReceived lnwire.MilliSatoshi `json:"total_msat_received"`
+	LocalConstraints  debugConstraints    `json:"local_constraints"`
+	RemoteConstraints debugConstraints    `json:"remote_constraints"`
+	LocalCommitment   debugCommitment     `json:"local_commitment"`
+	RemoteCommitment  debugCommitment     `json:"remote_commitment"`
+}
+
+// debugPendingClose is the sanitized summary of a channel which is pending
+// closure.
+type debugPendingClose struct {
+	ChannelPoint      string         `json:"channel_point"`
+	ShortChanID       uint64         `json:"short_chan_id"`
+	ClosingTxid       string         `json:"closing_txid"`
+	RemotePubKey      string         `json:"remote_pubkey"`
+	Capacity          btcutil.Amount `json:"capacity"`
+	CloseHeight       uint32         `json:"close_height"`
+	SettledBalance    btcutil.Amount `json:"settled_balance"`
+	TimeLockedBalance btcutil.Amount `json:"time_locked_balance"`
+	CloseType         string         `json:"close_type"`
+}
+
+// debugLink summarizes the state of an active channel link.
+type debugLink struct {
+	ChannelPoint        string                    `json:"channel_point"`
+	ShortChanID         uint64                    `json:"short_chan_id"`
+	EligibleToForward   bool                      `json:"eligible_to_forward"`
+	FullySynced         bool                      `json:"fully_synced"`
+	PendingRemoteCommit bool                      `json:"pending_remote_commit"`
+	Bandwidth           lnwire.MilliSatoshi       `json:"bandwidth_msat"`
+	NumPendingIncoming  int                       `json:"num_pending_incoming"`
+	NumPendingOutgoing  int                       `json:"num_pending_outgoing"`
+	OldestOutgoingHtlc  time.Duration             `json:"oldest_outgoing_htlc"`
+	CommitHeight        uint64                    `json:"commit_height"`
+	LastCommitUpdate    time.Time                 `json:"last_commit_update"`
+	BatchCounter        uint32                    `json:"batch_counter"`
+	OverflowQueueLen    int32                     `json:"overflow_queue_len"`
+	Mailbox             htlcswitch.MailBoxStats   `json:"mailbox"`
+	Admission           htlcswitch.AdmissionStats `json:"admission"`
+	FeeSpike            htlcswitch.FeeSpikeStats  `json:"fee_spike"`
+}
+
+// debugEdgePolicy is the routing policy of one direction of a channel, as
+// advertised within the channel graph.
+type debugEdgePolicy struct {
+	LastUpdate                time.Time             `json:"last_update"`
+	Flags                     lnwire.ChanUpdateFlag `json:"flags"`
+	TimeLockDelta             uint16                `json:"time_lock_delta"`
+	MinHTLC                   lnwire.MilliSatoshi   `json:"min_htlc_msat"`
+	FeeBaseMSat               lnwire.MilliSatoshi   `json:"fee_base_msat"`
+	FeeProportionalMillionths lnwire.MilliSatoshi   `json:"fee_rate_milli_msat"`
+}
+
+// debugEdge is an excerpt of the channel graph, describing one of our
+// channels as it's known to the rest of the network.
+type debugEdge struct {
+	ChannelID uint64           `json:"channel_id"`
+	Node1     string           `json:"node1_pub"`
+	Node2     string           `json:"node2_pub"`
+	Capacity  btcutil.Amount   `json:"capacity"`
+	Policy1   *debugEdgePolicy `json:"node1_policy,omitempty"`
+	Policy2   *debugEdgePolicy `json:"node2_policy,omitempty"`
+}
+
+// debugPackage bundles the state of a node relevant to diagnosing stuck
+// channels, stripped of any secret material, so it can be shared with
+// maintainers.
+type debugPackage struct {
+	Info          debugNodeInfo
+	Channels      []*debugChannel
+	PendingCloses []*debugPendingClose
+	Links         []*debugLink
+	Graph         []*debugEdge
+	Logs          []*debugLogEntry
+}
+
+func sanitizeCommitment(c *channeldb.ChannelCommitment) debugCommitment {
+	commit := debugCommitment{
+		CommitHeight:    c.CommitHeight,
+		LocalLogIndex:   c.LocalLogIndex,
+		LocalHtlcIndex:  c.LocalHtlcIndex,
+		RemoteLogIndex:  c.RemoteLogIndex,
+		RemoteHtlcIndex: c.RemoteHtlcIndex,
+		LocalBalance:    c.LocalBalance,
+		RemoteBalance:   c.RemoteBalance,
+		CommitFee:       c.CommitFee,
+		FeePerKw:        c.FeePerKw,
+		Htlcs:           make([]debugHTLC, 0, len(c.Htlcs)),
+	}
+	for _, htlc := range c.Htlcs {
+		commit.Htlcs = append(commit.Htlcs, debugHTLC{
+			Incoming:    htlc.Incoming,
+			Amount:      htlc.Amt,
+			Expiry:      htlc.RefundTimeout,
+			HtlcIndex:   htlc.HtlcIndex,
+			PaymentHash: hex.EncodeToString(htlc.RHash[:]),
+		})
+	}
+
+	return commit
+}
+
+func sanitizeConstraints(c *channeldb.ChannelConfig) debugConstraints {
+	return debugConstraints{
+		CsvDelay:         c.CsvDelay,
+		DustLimit:        c.DustLimit,
+		ChanReserve:      c.ChanReserve,
+		MaxPendingAmount: c.MaxPendingAmount,
+		MinHTLC:          c.MinHTLC,
+		MaxAcceptedHtlcs: c.MaxAcceptedHtlcs,
+	}
+}
+
+// sanitizeChannel returns the metadata of the passed channel that's safe to
+// share, including a summary of its pending HTLCs.
+func sanitizeChannel(c *channeldb.OpenChannel) *debugChannel {
+	return &debugChannel{
+		ChannelPoint: c.FundingOutpoint.String(),
+		ShortChanID:  c.ShortChanID.ToUint64(),
+		RemotePubKey: hex.EncodeToString(
+			c.IdentityPub.SerializeCompressed(),
+		),
+		Capacity:          c.Capacity,
+		IsPending:         c.IsPending,
+		IsInitiator:       c.IsInitiator,
+		IsBorked:          c.IsBorked,
+		ChannelFlags:      c.ChannelFlags,
+		TotalMSatSent:     c.TotalMSatSent,
+		TotalMSatReceived: c.TotalMSatReceived,
+		LocalConstraints:  sanitizeConstraints(&c.LocalChanCfg),
+		RemoteConstraints: sanitizeConstraints(&c.RemoteChanCfg),
+		LocalCommitment:   sanitizeCommitment(&c.LocalCommitment),
+		RemoteCommitment:  sanitizeCommitment(&c.RemoteCommitment),
+	}
+}
+
+// sanitizePendingClose returns the summary of the passed channel which is
+// pending closure.
+func sanitizePendingClose(c *channeldb.ChannelCloseSummary) *debugPendingClose {
+	return &debugPendingClose{
+		ChannelPoint: c.ChanPoint.String(),
+		ShortChanID:  c.ShortChanID.ToUint64(),
+		ClosingTxid:  c.ClosingTXID.String(),
+		RemotePubKey: hex.EncodeToString(
+			c.RemotePub.SerializeCompressed(),
+		),
+		Capacity:          c.Capacity,
+		CloseHeight:       c.CloseHeight,
+		SettledBalance:    c.SettledBalance,
+		TimeLockedBalance: c.TimeLockedBalance,
+		CloseType:         fmt.Sprintf("%v", c.CloseType),
+	}
+}
+
+// sanitizeLinkSnapshot returns a summary of the passed link snapshot.
+func sanitizeLinkSnapshot(s *htlcswitch.LinkSnapshot) *debugLink {
+	link := &debugLink{
+		ChannelPoint:        s.ChannelPoint.String(),
+		ShortChanID:         s.ShortChanID.ToUint64(),
+		EligibleToForward:   s.EligibleToForward,
+		FullySynced:         s.FullySynced,
+		PendingRemoteCommit: s.PendingRemoteCommit,
+		Bandwidth:           s.Bandwidth,
+		NumPendingIncoming:  s.NumPendingIncoming,
+		NumPendingOutgoing:  s.NumPendingOutgoing,
+		CommitHeight:        s.CommitHeight,
+		LastCommitUpdate:    s.LastCommitUpdate,
+		BatchCounter:        s.BatchCounter,
+		OverflowQueueLen:    s.OverflowQueueLen,
+		Mailbox:             s.MailboxStats,
+		Admission:           s.AdmissionStats,
+		FeeSpike:            s.FeeSpikeStats,
+	}
+	for _, age := range s.OutgoingHtlcAges {
+		if age > link.OldestOutgoingHtlc {
+			link.OldestOutgoingHtlc = age
+		}
+	}
+
+	return link
+}
+
+func sanitizeEdgePolicy(p *channeldb.ChannelEdgePolicy) *debugEdgePolicy {
+	if p == nil {
+		return nil
+	}
+
+	return &debugEdgePolicy{
+		LastUpdate:                p.LastUpdate,
+		Flags:                     p.Flags,
+		TimeLockDelta:             p.TimeLockDelta,
+		MinHTLC:                   p.MinHTLC,
+		FeeBaseMSat:               p.FeeBaseMSat,
+		FeeProportionalMillionths: p.FeeProportionalMillionths,
+	}
+}
+
+// sanitizeEdge returns an excerpt of the channel graph for the passed edge.
+func sanitizeEdge(info *channeldb.ChannelEdgeInfo, p1,
+	p2 *channeldb.ChannelEdgePolicy) *debugEdge {
+
+	return &debugEdge{
+		ChannelID: info.ChannelID,
+		Node1:     hex.EncodeToString(info.NodeKey1.SerializeCompressed()),
+		Node2:     hex.EncodeToString(info.NodeKey2.SerializeCompressed()),
+		Capacity:  info.Capacity,
+		Policy1:   sanitizeEdgePolicy(p1),
+		Policy2:   sanitizeEdgePolicy(p2),
+	}
+}
+
+// assembleDebugPackage gathers the sanitized state of all our channels, along
+// with up to maxLogEntries of the most recent log entries, into a debug
+// package. If maxLogEntries is zero, then all retained log entries are
+// included.
+func (s *server) assembleDebugPackage(maxLogEntries int) (*debugPackage, error) {
+	_, bestHeight, err := s.cc.chainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &debugPackage{
+		Info: debugNodeInfo{
+			IdentityPubKey: hex.EncodeToString(
+				s.identityPriv.PubKey().SerializeCompressed(),
+			),
+			Version:     version(),
+			Network:     activeNetParams.Name,
+			BlockHeight: bestHeight,
+			CreatedAt:   time.Now(),
+		},
+	}
+
+	channels, err := s.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	graph := s.chanDB.ChannelGraph()
+	for _, channel := range channels {
+		pkg.Channels = append(pkg.Channels, sanitizeChannel(channel))
+
+		// Private channels, and those not yet announced, won't be found
+		// within the graph.
+		info, p1, p2, err := graph.FetchChannelEdgesByOutpoint(
+			&channel.FundingOutpoint,
+		)
+		switch {
+		case err == channeldb.ErrEdgeNotFound ||
+			err == channeldb.ErrGraphNoEdgesFound ||
+			err == channeldb.ErrGraphNotFound:
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		pkg.Graph = append(pkg.Graph, sanitizeEdge(info, p1, p2))
+	}
+
+	pendingCloses, err := s.chanDB.FetchClosedChannels(true)
+	if err != nil {
+		return nil, err
+	}
+	for _, pendingClose := range pendingCloses {
+		pkg.PendingCloses = append(
+			pkg.PendingCloses, sanitizePendingClose(pendingClose),
+		)
+	}
+
+	links, err := s.htlcSwitch.Links()
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		// A link that is shutting down is simply left out.
+		snapshot, err := link.LinkSnapshot()
+		if err != nil {
+			continue
+		}
+		pkg.Links = append(pkg.Links, sanitizeLinkSnapshot(snapshot))
+	}
+
+	for _, raw := range recentLogs.Entries(maxLogEntries) {
+		pkg.Logs = append(pkg.Logs, parseLogEntry(raw))
+	}
+
+	return pkg, nil
+}
+
+// archive serializes each section of the debug package as JSON, and bundles
+// them into a gzipped tarball.
+func (p *debugPackage) archive() ([]byte, error) {
+	sections := []struct {
+		name    string
+		content interface{}
+	}{
+		{"info.json", p.Info},
+		{"channels.json", p.Channels},
+		{"pending_closes.json", p.PendingCloses},
+		{"links.json", p.Links},
+		{"graph.json", p.Graph},
+		{"logs.json", p.Logs},
+	}
+
+	var b bytes.Buffer
+	gzipWriter := gzip.NewWriter(&b)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, section := range sections {
+		content, err := json.MarshalIndent(section.content, "", "    ")
+		if err != nil {
+			return nil, err
+		}
+
+		err = tarWriter.WriteHeader(&tar.Header{
+			Name:    section.name,
+			Mode:    0600,
+			Size:    int64(len(content)),
+			ModTime: p.Info.CreatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tarWriter.Write(content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// encrypt archives the debug package, and encrypts the archive to the passed
+// public key, so that only the holder of the corresponding private key is
+// able to inspect it.
+func (p *debugPackage) encrypt(recipient *btcec.PublicKey) ([]byte, error) {
+	archive, err := p.archive()
+	if err != nil {
+		return nil, err
+	}
+
+	return btcec.Encrypt(recipient, archive)
+}
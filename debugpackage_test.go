@@ -0,0 +1,154 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestLogRing ensures that the log ring retains only the most recent entries,
+// and returns them in the order they were written.
+func TestLogRing(t *testing.T) {
+	t.Parallel()
+
+	ring := newLogRing(3)
+	if entries := ring.Entries(0); len(entries) != 0 {
+		t.Fatalf("expected no entries, got %v", entries)
+	}
+
+	for _, entry := range []string{"a", "b", "c", "d", "e"} {
+		ring.Write([]byte(entry))
+	}
+
+	entries := ring.Entries(0)
+	if !reflect.DeepEqual(entries, []string{"c", "d", "e"}) {
+		t.Fatalf("unexpected entries: %v", entries)
+	}
+
+	entries = ring.Entries(2)
+	if !reflect.DeepEqual(entries, []string{"d", "e"}) {
+		t.Fatalf("unexpected entries: %v", entries)
+	}
+}
+
+// TestParseLogEntry ensures that log entries are parsed into their
+// components, and stripped of any potential secrets.
+func TestParseLogEntry(t *testing.T) {
+	t.Parallel()
+
+	preimage := strings.Repeat("ab", 32)
+	pubKey := "02" + strings.Repeat("cd", 32)
+
+	raw := "2018-03-14 15:09:26.535 [INF] HSWC: settled htlc with " +
+		"preimage=" + preimage + " from " + pubKey + "\n"
+	entry := parseLogEntry(raw)
+
+	timestamp := time.Date(2018, 3, 14, 15, 9, 26, 535e6, time.Local)
+	if !entry.Timestamp.Equal(timestamp) {
+		t.Fatalf("expected timestamp %v, got %v", timestamp,
+			entry.Timestamp)
+	}
+	if entry.Level != "INF" {
+		t.Fatalf("expected level INF, got %v", entry.Level)
+	}
+	if entry.Subsystem != "HSWC" {
+		t.Fatalf("expected subsystem HSWC, got %v", entry.Subsystem)
+	}
+
+	expectedMsg := "settled htlc with preimage=" + redactedPlaceholder +
+		" from " + pubKey
+	if entry.Message != expectedMsg {
+		t.Fatalf("expected message %q, got %q", expectedMsg,
+			entry.Message)
+	}
+
+	// An entry which isn't of the expected form should still be included
+	// in full, with any secrets redacted.
+	entry = parseLogEntry("panic: " + preimage + "\n")
+	if entry.Level != "" || entry.Subsystem != "" {
+		t.Fatalf("unexpected level or subsystem: %v", entry)
+	}
+	if entry.Message != "panic: "+redactedPlaceholder {
+		t.Fatalf("unexpected message: %q", entry.Message)
+	}
+}
+
+// TestDebugPackageEncryption ensures that an encrypted debug package can be
+// decrypted by the recipient, and contains each of its sections.
+func TestDebugPackageEncryption(t *testing.T) {
+	t.Parallel()
+
+	recipient, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	pkg := &debugPackage{
+		Info: debugNodeInfo{
+			Version:   version(),
+			CreatedAt: time.Now(),
+		},
+		Channels: []*debugChannel{
+			{
+				ChannelPoint: "a:0",
+				Capacity:     1000,
+			},
+		},
+		Logs: []*debugLogEntry{
+			{Message: "hello"},
+		},
+	}
+
+	encrypted, err := pkg.encrypt(recipient.PubKey())
+	if err != nil {
+		t.Fatalf("unable to encrypt package: %v", err)
+	}
+
+	archive, err := btcec.Decrypt(recipient, encrypted)
+	if err != nil {
+		t.Fatalf("unable to decrypt package: %v", err)
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("unable to read archive: %v", err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+
+	sections := make(map[string][]byte)
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			break
+		}
+		content, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			t.Fatalf("unable to read %v: %v", header.Name, err)
+		}
+		sections[header.Name] = content
+	}
+
+	for _, name := range []string{"info.json", "channels.json",
+		"pending_closes.json", "links.json", "graph.json", "logs.json"} {
+
+		if _, ok := sections[name]; !ok {
+			t.Fatalf("archive is missing %v", name)
+		}
+	}
+
+	var channels []*debugChannel
+	if err := json.Unmarshal(sections["channels.json"], &channels); err != nil {
+		t.Fatalf("unable to decode channels: %v", err)
+	}
+	if !reflect.DeepEqual(channels, pkg.Channels) {
+		t.Fatalf("expected channels %v, got %v", pkg.Channels, channels)
+	}
+}
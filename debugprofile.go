@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"golang.org/x/net/context"
+)
+
+const (
+	// defaultCPUProfileDuration is the duration a CPU profile is sampled
+	// for if the request doesn't specify one.
+	defaultCPUProfileDuration = 10 * time.Second
+
+	// maxCPUProfileDuration is the longest duration a CPU profile may be
+	// sampled for, which bounds the overhead of profiling a node in
+	// production.
+	maxCPUProfileDuration = 60 * time.Second
+
+	// maxProfileSize is the maximum size of a profile returned over RPC.
+	// Larger profiles are truncated, keeping the response below the
+	// message size limit of gRPC.
+	maxProfileSize = 3 * 1024 * 1024
+)
+
+// errProfileInProgress is returned when a profile is requested while another
+// one is still being collected.
+var errProfileInProgress = errors.New("a profile is already being collected")
+
+// profiler collects runtime profiles of the daemon on demand. Only a single
+// profile is collected at a time, so that concurrent requests can't pile up
+// the overhead of profiling.
+type profiler struct {
+	// collecting is set while a profile is being collected. It must be
+	// used atomically.
+	collecting int32
+
+	// maxSize is the size profiles are truncated to.
+	maxSize int
+
+	// quit is closed when the daemon is shutting down, which aborts the
+	// collection of a CPU profile.
+	quit chan struct{}
+}
+
+// newProfiler returns a profiler which truncates profiles to maxProfileSize,
+// and aborts the collection of CPU profiles once the quit channel is closed.
+func newProfiler(quit chan struct{}) *profiler {
+	return &profiler{
+		maxSize: maxProfileSize,
+		quit:    quit,
+	}
+}
+
+// collect collects a profile of the passed type. CPU profiles are sampled for
+// the passed duration, unless the context is cancelled in the meantime. The
+// returned bool indicates whether the profile has been truncated.
+func (p *profiler) collect(ctx context.Context,
+	profileType lnrpc.DebugProfileRequest_ProfileType,
+	duration time.Duration) ([]byte, bool, error) {
+
+	if !atomic.CompareAndSwapInt32(&p.collecting, 0, 1) {
+		return nil, false, errProfileInProgress
+	}
+	defer atomic.StoreInt32(&p.collecting, 0)
+
+	var buf bytes.Buffer
+	switch profileType {
+	case lnrpc.DebugProfileRequest_GOROUTINE:
+		err := pprof.Lookup("goroutine").WriteTo(&buf, 2)
+		if err != nil {
+			return nil, false, err
+		}
+
+	case lnrpc.DebugProfileRequest_HEAP:
+		// We'll run a garbage collection first, so that the profile
+		// reflects the live heap rather than the last collection.
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(&buf); err != nil {
+			return nil, false, err
+		}
+
+	case lnrpc.DebugProfileRequest_CPU:
+		switch {
+		case duration == 0:
+			duration = defaultCPUProfileDuration
+
+		case duration > maxCPUProfileDuration:
+			return nil, false, fmt.Errorf("CPU profile duration of "+
+				"%v exceeds the maximum of %v", duration,
+				maxCPUProfileDuration)
+		}
+
+		// This fails if the daemon is already being profiled, e.g.
+		// through the cpuprofile option.
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			return nil, false, err
+		}
+
+		select {
+		case <-time.After(duration):
+		case <-ctx.Done():
+		case <-p.quit:
+		}
+		pprof.StopCPUProfile()
+
+		if ctx.Err() != nil {
+			return nil, false, ctx.Err()
+		}
+
+	default:
+		return nil, false, fmt.Errorf("unknown profile type %v",
+			profileType)
+	}
+
+	profile := buf.Bytes()
+	if len(profile) > p.maxSize {
+		return profile[:p.maxSize], true, nil
+	}
+
+	return profile, false, nil
+}
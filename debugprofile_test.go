@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"golang.org/x/net/context"
+)
+
+// TestProfilerLimits tests that the profiler truncates profiles exceeding its
+// maximum size, rejects CPU profiles sampled for too long, only collects a
+// single profile at a time, and aborts a CPU profile once its request is
+// cancelled.
+func TestProfilerLimits(t *testing.T) {
+	t.Parallel()
+
+	quit := make(chan struct{})
+	defer close(quit)
+	p := newProfiler(quit)
+
+	// A goroutine dump should include the stack of the running test.
+	profile, truncated, err := p.collect(
+		context.Background(), lnrpc.DebugProfileRequest_GOROUTINE, 0,
+	)
+	if err != nil {
+		t.Fatalf("unable to collect goroutine dump: %v", err)
+	}
+	if truncated {
+		t.Fatalf("goroutine dump of %v bytes shouldn't be truncated",
+			len(profile))
+	}
+	if !bytes.Contains(profile, []byte("TestProfilerLimits")) {
+		t.Fatalf("goroutine dump doesn't include the running test")
+	}
+
+	// Once the maximum size is lowered, the dump should be truncated.
+	p.maxSize = 100
+	profile, truncated, err = p.collect(
+		context.Background(), lnrpc.DebugProfileRequest_GOROUTINE, 0,
+	)
+	if err != nil {
+		t.Fatalf("unable to collect goroutine dump: %v", err)
+	}
+	if !truncated || len(profile) != 100 {
+		t.Fatalf("expected dump truncated to 100 bytes, got %v "+
+			"bytes, truncated=%v", len(profile), truncated)
+	}
+
+	// A CPU profile sampled for longer than the maximum duration should
+	// be rejected.
+	_, _, err = p.collect(
+		context.Background(), lnrpc.DebugProfileRequest_CPU,
+		maxCPUProfileDuration+time.Second,
+	)
+	if err == nil {
+		t.Fatalf("expected CPU profile exceeding the maximum " +
+			"duration to be rejected")
+	}
+
+	// A profile requested while another is being collected should be
+	// rejected.
+	p.collecting = 1
+	_, _, err = p.collect(
+		context.Background(), lnrpc.DebugProfileRequest_HEAP, 0,
+	)
+	if err != errProfileInProgress {
+		t.Fatalf("expected %v, got %v", errProfileInProgress, err)
+	}
+	p.collecting = 0
+
+	// Finally, a CPU profile should be aborted once its request is
+	// cancelled.
+	ctx, cancel := context.WithTimeout(
+		context.Background(), 100*time.Millisecond,
+	)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err = p.collect(ctx, lnrpc.DebugProfileRequest_CPU, time.Minute)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if time.Since(start) > maxCPUProfileDuration/2 {
+		t.Fatalf("CPU profile not aborted once cancelled")
+	}
+}
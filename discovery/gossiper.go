@@ -54,6 +54,25 @@ type chanPolicyUpdateRequest struct {
 	errResp chan error
 }
 
+// chanStatusUpdateRequest is a request that is sent to the gossiper when a
+// caller wishes to mark all of our outgoing channels as either enabled or
+// disabled within the network. New ChannelUpdate messages for each affected
+// channel will be crafted and sent out during the next broadcast epoch.
+type chanStatusUpdateRequest struct {
+	disabled bool
+
+	// targetChan, if set, restricts the request to a single channel,
+	// whose status is pinned by the operator. Pinned channels are exempt
+	// from requests without a target channel.
+	targetChan *wire.OutPoint
+
+	// unpin indicates that the status of the target channel should no
+	// longer be pinned, returning it to the status of all other channels.
+	unpin bool
+
+	errResp chan error
+}
+
 // Config defines the configuration for the service. ALL elements within the
 // configuration MUST be non-nil for the service to carry out its duties.
 type Config struct {
@@ -180,6 +199,21 @@ type AuthenticatedGossiper struct {
 	// policy of a set of channels is sent over.
 	chanPolicyUpdates chan *chanPolicyUpdateRequest
 
+	// chanStatusUpdates is a channel that requests to enable or disable
+	// all of our outgoing channels are sent over.
+	chanStatusUpdates chan *chanStatusUpdateRequest
+
+	// chansDisabled is the status of our outgoing channels, as last
+	// requested for all of them. The status of channels which are unpinned
+	// by the operator is returned to it.
+	chansDisabled bool
+
+	// pinnedChanStatus maps each channel whose status has been pinned by
+	// the operator to whether it's pinned as disabled. These channels keep
+	// their status, regardless of the requests to enable or disable all of
+	// our channels.
+	pinnedChanStatus map[wire.OutPoint]bool
+
 	// bestHeight is the height of the block at the tip of the main chain
 	// as we know it.
 	bestHeight uint32
@@ -210,6 +244,8 @@ func New(cfg Config, selfKey *btcec.PublicKey) (*AuthenticatedGossiper, error) {
 		networkMsgs:             make(chan *networkMsg),
 		quit:                    make(chan struct{}),
 		chanPolicyUpdates:       make(chan *chanPolicyUpdateRequest),
+		chanStatusUpdates:       make(chan *chanStatusUpdateRequest),
+		pinnedChanStatus:        make(map[wire.OutPoint]bool),
 		prematureAnnouncements:  make(map[uint32][]*networkMsg),
 		prematureChannelUpdates: make(map[uint64][]*networkMsg),
 		waitingProofs:           storage,
@@ -335,6 +371,61 @@ func (d *AuthenticatedGossiper) PropagateChanPolicyUpdate(
 	}
 }
 
+// PropagateChanStatusUpdate signals the AuthenticatedGossiper to mark all of
+// our outgoing channels as either disabled or enabled, by signing and
+// broadcasting a new ChannelUpdate for each channel whose status changes.
+// Disabling our channels signals to the rest of the network that they
+// shouldn't route payments through us for the time being.
+//
+// NOTE: Channels whose status has been pinned using PinChanStatus are left
+// untouched.
+func (d *AuthenticatedGossiper) PropagateChanStatusUpdate(disabled bool) error {
+	return d.sendChanStatusUpdate(&chanStatusUpdateRequest{
+		disabled: disabled,
+	})
+}
+
+// PinChanStatus signals the AuthenticatedGossiper to mark the outgoing channel
+// with the passed channel point as either disabled or enabled, and to keep it
+// that way regardless of any subsequent status updates for all of our
+// channels, until it's unpinned. This allows an operator to drain a channel of
+// routing traffic ahead of maintenance. Pins aren't persisted, so they don't
+// survive a restart, though the status of the channel within the graph does.
+func (d *AuthenticatedGossiper) PinChanStatus(chanPoint wire.OutPoint,
+	disabled bool) error {
+
+	return d.sendChanStatusUpdate(&chanStatusUpdateRequest{
+		disabled:   disabled,
+		targetChan: &chanPoint,
+	})
+}
+
+// UnpinChanStatus signals the AuthenticatedGossiper to release the status of
+// the outgoing channel with the passed channel point, previously pinned using
+// PinChanStatus. The channel is returned to the status of all other channels.
+func (d *AuthenticatedGossiper) UnpinChanStatus(chanPoint wire.OutPoint) error {
+	return d.sendChanStatusUpdate(&chanStatusUpdateRequest{
+		targetChan: &chanPoint,
+		unpin:      true,
+	})
+}
+
+// sendChanStatusUpdate hands the passed request off to the networkHandler,
+// and waits for it to be processed.
+func (d *AuthenticatedGossiper) sendChanStatusUpdate(
+	statusUpdate *chanStatusUpdateRequest) error {
+
+	errChan := make(chan error, 1)
+	statusUpdate.errResp = errChan
+
+	select {
+	case d.chanStatusUpdates <- statusUpdate:
+		return <-errChan
+	case <-d.quit:
+		return fmt.Errorf("AuthenticatedGossiper shutting down")
+	}
+}
+
 // Start spawns network messages handler goroutine and registers on new block
 // notifications in order to properly handle the premature announcements.
 func (d *AuthenticatedGossiper) Start() error {
@@ -860,6 +951,25 @@ func (d *AuthenticatedGossiper) networkHandler() {
 
 			policyUpdate.errResp <- nil
 
+		// A request to enable or disable all of our channels has
+		// arrived. Similar to policy updates, we'll craft and sign new
+		// updates for the affected channels, and add them to the
+		// batch.
+		case statusUpdate := <-d.chanStatusUpdates:
+			newChanUpdates, err := d.processChanStatusUpdate(
+				statusUpdate,
+			)
+			if err != nil {
+				log.Errorf("Unable to craft status updates: %v",
+					err)
+				statusUpdate.errResp <- err
+				continue
+			}
+
+			announcements.AddMsgs(newChanUpdates...)
+
+			statusUpdate.errResp <- nil
+
 		case announcement := <-d.networkMsgs:
 			// Channel annoucnement signatures are the only message
 			// that we'll process serially.
@@ -1147,6 +1257,97 @@ func (d *AuthenticatedGossiper) processChanPolicyUpdate(
 	return chanUpdates, nil
 }
 
+// processChanStatusUpdate generates a new set of channel updates that flip the
+// disabled bit of each of our outgoing channels to the requested state.
+// Channels that are already in the requested state are left untouched, as are
+// those whose status is pinned, unless the request targets them specifically.
+func (d *AuthenticatedGossiper) processChanStatusUpdate(
+	statusUpdate *chanStatusUpdateRequest) ([]networkMsg, error) {
+
+	// We'll first determine the status each affected channel should have.
+	// Requests targeting a single channel pin or unpin its status, while
+	// all others apply to each of our channels that isn't pinned.
+	disabled := statusUpdate.disabled
+	target := statusUpdate.targetChan
+	switch {
+	case target == nil:
+		d.chansDisabled = disabled
+
+	case statusUpdate.unpin:
+		if _, ok := d.pinnedChanStatus[*target]; !ok {
+			return nil, fmt.Errorf("status of channel %v isn't "+
+				"pinned", target)
+		}
+		disabled = d.chansDisabled
+
+	default:
+		d.pinnedChanStatus[*target] = disabled
+	}
+
+	var (
+		chanUpdates []networkMsg
+		found       bool
+	)
+	err := d.cfg.Router.ForAllOutgoingChannels(func(info *channeldb.ChannelEdgeInfo,
+		edge *channeldb.ChannelEdgePolicy) error {
+
+		if edge == nil {
+			return nil
+		}
+
+		if target != nil {
+			if info.ChannelPoint != *target {
+				return nil
+			}
+			found = true
+		} else if _, ok := d.pinnedChanStatus[info.ChannelPoint]; ok {
+			return nil
+		}
+
+		isDisabled := edge.Flags&lnwire.ChanUpdateDisabled != 0
+		if isDisabled == disabled {
+			return nil
+		}
+
+		if disabled {
+			edge.Flags |= lnwire.ChanUpdateDisabled
+		} else {
+			edge.Flags &^= lnwire.ChanUpdateDisabled
+		}
+
+		// Re-sign and update the backing ChannelGraphSource, and
+		// retrieve our ChannelUpdate to broadcast.
+		_, chanUpdate, err := d.updateChannel(info, edge)
+		if err != nil {
+			return err
+		}
+
+		chanUpdates = append(chanUpdates, networkMsg{
+			peer: d.selfKey,
+			msg:  chanUpdate,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A channel can only be pinned or unpinned if it's one of ours.
+	if target != nil {
+		if !found {
+			delete(d.pinnedChanStatus, *target)
+			return nil, fmt.Errorf("unable to find outgoing "+
+				"channel %v", target)
+		}
+		if statusUpdate.unpin {
+			delete(d.pinnedChanStatus, *target)
+		}
+	}
+
+	return chanUpdates, nil
+}
+
 // processRejectedEdge examines a rejected edge to see if we can eexrtact any
 // new announcements from it.  An edge will get rejected if we already added
 // the same edge without AuthProof to the graph. If the received announcement
@@ -163,17 +163,49 @@ func (r *mockGraphSource) AddProof(chanID lnwire.ShortChannelID,
 	return nil
 }
 
-func (r *mockGraphSource) ForEachNode(func(node *channeldb.LightningNode) error) error {
+func (r *mockGraphSource) ForEachNode(cb func(node *channeldb.LightningNode) error) error {
+	for _, node := range r.nodes {
+		if err := cb(node); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (r *mockGraphSource) ForAllOutgoingChannels(cb func(i *channeldb.ChannelEdgeInfo,
 	c *channeldb.ChannelEdgePolicy) error) error {
+
+	for chanID, chanInfo := range r.infos {
+		var edge *channeldb.ChannelEdgePolicy
+		if edges := r.edges[chanID]; len(edges) > 0 {
+			edge = edges[len(edges)-1]
+		}
+
+		if err := cb(chanInfo, edge); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (r *mockGraphSource) ForEachChannel(func(chanInfo *channeldb.ChannelEdgeInfo,
+func (r *mockGraphSource) ForEachChannel(cb func(chanInfo *channeldb.ChannelEdgeInfo,
 	e1, e2 *channeldb.ChannelEdgePolicy) error) error {
+
+	for chanID, chanInfo := range r.infos {
+		var e1, e2 *channeldb.ChannelEdgePolicy
+		for _, edge := range r.edges[chanID] {
+			switch edge.Flags & lnwire.ChanUpdateDirection {
+			case 0:
+				e1 = edge
+			default:
+				e2 = edge
+			}
+		}
+
+		if err := cb(chanInfo, e1, e2); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -465,6 +497,7 @@ func createTestCtx(startHeight uint32) (*testCtx, func(), error) {
 			return nil
 		},
 		Router:           router,
+		AnnSigner:        &mockSigner{nodeKeyPriv1},
 		TrickleDelay:     trickleDelay,
 		RetransmitDelay:  retransmitDelay,
 		ProofMatureDelta: proofMatureDelta,
@@ -1958,3 +1991,91 @@ func TestReceiveRemoteChannelUpdateFirst(t *testing.T) {
 		t.Fatal("waiting proof should be removed from storage")
 	}
 }
+
+// TestPinChanStatus tests that the status of a channel pinned by the operator
+// is kept regardless of the status updates for all of our channels, and that
+// once unpinned, the channel returns to the status of all other channels.
+func TestPinChanStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx, cleanup, err := createTestCtx(0)
+	if err != nil {
+		t.Fatalf("can't create context: %v", err)
+	}
+	defer cleanup()
+
+	// We'll add two of our own channels to the graph, both of which are
+	// initially enabled.
+	chanPoints := []wire.OutPoint{
+		{Hash: *sha, Index: 0},
+		{Hash: *sha, Index: 1},
+	}
+	for i, chanPoint := range chanPoints {
+		chanID := uint64(i + 1)
+		ctx.router.infos[chanID] = &channeldb.ChannelEdgeInfo{
+			ChannelID:    chanID,
+			ChannelPoint: chanPoint,
+		}
+		ctx.router.edges[chanID] = []*channeldb.ChannelEdgePolicy{{
+			ChannelID: chanID,
+			Node: &channeldb.LightningNode{
+				PubKey: &btcec.PublicKey{},
+			},
+		}}
+	}
+
+	assertDisabled := func(expected ...bool) {
+		for i, disabled := range expected {
+			edges := ctx.router.edges[uint64(i+1)]
+			edge := edges[len(edges)-1]
+
+			isDisabled := edge.Flags&lnwire.ChanUpdateDisabled != 0
+			if isDisabled != disabled {
+				t.Fatalf("expected channel %v to have "+
+					"disabled=%v, got %v", i, disabled,
+					isDisabled)
+			}
+		}
+	}
+
+	// Pinning the first channel as disabled should only disable it.
+	err = ctx.gossiper.PinChanStatus(chanPoints[0], true)
+	if err != nil {
+		t.Fatalf("unable to pin channel status: %v", err)
+	}
+	assertDisabled(true, false)
+
+	// Enabling all of our channels shouldn't affect the pinned channel.
+	if err := ctx.gossiper.PropagateChanStatusUpdate(false); err != nil {
+		t.Fatalf("unable to update channel status: %v", err)
+	}
+	assertDisabled(true, false)
+
+	// Neither should disabling them, after the channel has been pinned as
+	// enabled.
+	err = ctx.gossiper.PinChanStatus(chanPoints[0], false)
+	if err != nil {
+		t.Fatalf("unable to pin channel status: %v", err)
+	}
+	if err := ctx.gossiper.PropagateChanStatusUpdate(true); err != nil {
+		t.Fatalf("unable to update channel status: %v", err)
+	}
+	assertDisabled(false, true)
+
+	// Once unpinned, the channel should return to the status of all other
+	// channels.
+	if err := ctx.gossiper.UnpinChanStatus(chanPoints[0]); err != nil {
+		t.Fatalf("unable to unpin channel status: %v", err)
+	}
+	assertDisabled(true, true)
+
+	// Unpinning a channel that isn't pinned, or pinning a channel that
+	// isn't ours, should fail.
+	if err := ctx.gossiper.UnpinChanStatus(chanPoints[0]); err == nil {
+		t.Fatalf("expected unpinning unpinned channel to fail")
+	}
+	unknown := wire.OutPoint{Hash: *sha, Index: 2}
+	if err := ctx.gossiper.PinChanStatus(unknown, true); err == nil {
+		t.Fatalf("expected pinning unknown channel to fail")
+	}
+}
@@ -0,0 +1,405 @@
+package discovery
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+var (
+	// graphSnapshotMagic is the set of bytes that prefixes every graph
+	// snapshot, allowing us to quickly reject files that aren't
+	// snapshots.
+	graphSnapshotMagic = [4]byte{'l', 'n', 'g', 's'}
+
+	// ErrInvalidGraphSnapshot is returned when attempting to import a
+	// file that isn't a graph snapshot.
+	ErrInvalidGraphSnapshot = errors.New("invalid graph snapshot")
+)
+
+const (
+	// graphSnapshotVersion is the current version of the graph snapshot
+	// encoding.
+	graphSnapshotVersion uint8 = 0
+)
+
+// GraphSnapshotStats summarizes the result of exporting or importing a graph
+// snapshot.
+type GraphSnapshotStats struct {
+	// NumChannels is the number of channel announcements processed.
+	NumChannels uint32
+
+	// NumUpdates is the number of channel updates processed.
+	NumUpdates uint32
+
+	// NumNodes is the number of node announcements processed.
+	NumNodes uint32
+
+	// NumSkipped is the number of messages within a snapshot that weren't
+	// applied to the graph during an import, either because we already
+	// knew of more recent information, or as they failed validation.
+	NumSkipped uint32
+}
+
+// ExportGraph writes a compact snapshot of our validated view of the channel
+// graph to the passed io.Writer. Only publicly announced channels are
+// exported. The snapshot is a sequence of the authenticated wire messages
+// that originally advertised the graph, meaning that the node importing it
+// is able to verify each of them exactly as if they'd arrived over gossip.
+//
+// The channel announcements (and their updates) are written before the node
+// announcements, as a node announcement is only accepted once we know of a
+// channel that the node is party to.
+func (d *AuthenticatedGossiper) ExportGraph(w io.Writer) (*GraphSnapshotStats, error) {
+	var header bytes.Buffer
+	header.Write(graphSnapshotMagic[:])
+	header.WriteByte(graphSnapshotVersion)
+	header.Write(d.cfg.ChainHash[:])
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return nil, err
+	}
+
+	var (
+		stats     GraphSnapshotStats
+		chanNodes = make(map[routing.Vertex]struct{})
+	)
+	err := d.cfg.Router.ForEachChannel(func(chanInfo *channeldb.ChannelEdgeInfo,
+		e1, e2 *channeldb.ChannelEdgePolicy) error {
+
+		// Private channels never leave our node, so we'll only export
+		// channels for which we have a full authentication proof.
+		if chanInfo.AuthProof == nil {
+			return nil
+		}
+
+		chanAnn, e1Ann, e2Ann := createChanAnnouncement(
+			chanInfo.AuthProof, chanInfo, e1, e2,
+		)
+		if err := writeSnapshotMsg(w, chanAnn); err != nil {
+			return err
+		}
+		stats.NumChannels++
+
+		for _, update := range []*lnwire.ChannelUpdate{e1Ann, e2Ann} {
+			if update == nil {
+				continue
+			}
+			if err := writeSnapshotMsg(w, update); err != nil {
+				return err
+			}
+			stats.NumUpdates++
+		}
+
+		chanNodes[routing.NewVertex(chanInfo.NodeKey1)] = struct{}{}
+		chanNodes[routing.NewVertex(chanInfo.NodeKey2)] = struct{}{}
+
+		return nil
+	})
+	if err != nil && err != channeldb.ErrGraphNoEdgesFound {
+		return nil, err
+	}
+
+	err = d.cfg.Router.ForEachNode(func(node *channeldb.LightningNode) error {
+		if !node.HaveNodeAnnouncement {
+			return nil
+		}
+
+		// As the importing node would ignore a node announcement for
+		// a node that isn't party to any of the exported channels, we
+		// skip writing it all together.
+		if _, ok := chanNodes[routing.NewVertex(node.PubKey)]; !ok {
+			return nil
+		}
+
+		alias, err := lnwire.NewNodeAlias(node.Alias)
+		if err != nil {
+			return err
+		}
+		nodeAnn := &lnwire.NodeAnnouncement{
+			Signature: node.AuthSig,
+			Timestamp: uint32(node.LastUpdate.Unix()),
+			Addresses: node.Addresses,
+			NodeID:    node.PubKey,
+			Features:  node.Features.RawFeatureVector,
+			RGBColor:  node.Color,
+			Alias:     alias,
+		}
+		if err := writeSnapshotMsg(w, nodeAnn); err != nil {
+			return err
+		}
+		stats.NumNodes++
+
+		return nil
+	})
+	if err != nil && err != channeldb.ErrGraphNodesNotFound {
+		return nil, err
+	}
+
+	log.Infof("Exported graph snapshot with %v channels, %v updates and "+
+		"%v nodes", stats.NumChannels, stats.NumUpdates, stats.NumNodes)
+
+	return &stats, nil
+}
+
+// ImportGraph reads a graph snapshot created by ExportGraph from the passed
+// io.Reader, and adds its contents to our view of the channel graph. As the
+// snapshot may come from an untrusted source, each announcement within it is
+// validated exactly as if it had arrived over gossip: announcements for
+// other chains or for channels beyond our chain tip are skipped, the
+// signatures of every announcement are verified, and the router ensures that
+// the funding output of each channel is present within the UTXO set before
+// adding it.
+//
+// NOTE: As the imported announcements didn't arrive over the network, they
+// aren't broadcast to any of our peers.
+func (d *AuthenticatedGossiper) ImportGraph(
+	r io.Reader) (*GraphSnapshotStats, error) {
+
+	var header [len(graphSnapshotMagic) + 1 + chainhash.HashSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, ErrInvalidGraphSnapshot
+	}
+	if !bytes.Equal(header[:len(graphSnapshotMagic)], graphSnapshotMagic[:]) {
+		return nil, ErrInvalidGraphSnapshot
+	}
+	if version := header[len(graphSnapshotMagic)]; version != graphSnapshotVersion {
+		return nil, errors.Errorf("unknown graph snapshot version: %v",
+			version)
+	}
+	chainHash := header[len(graphSnapshotMagic)+1:]
+	if !bytes.Equal(chainHash, d.cfg.ChainHash[:]) {
+		return nil, errors.Errorf("graph snapshot is for chain=%x, "+
+			"gossiper on chain=%v", chainHash, d.cfg.ChainHash)
+	}
+
+	var stats GraphSnapshotStats
+	for {
+		msg, err := readSnapshotMsg(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var applyErr error
+		switch msg := msg.(type) {
+		case *lnwire.ChannelAnnouncement:
+			stats.NumChannels++
+			applyErr = d.importChanAnn(msg)
+
+		case *lnwire.ChannelUpdate:
+			stats.NumUpdates++
+			applyErr = d.importChanUpdate(msg)
+
+		case *lnwire.NodeAnnouncement:
+			stats.NumNodes++
+			applyErr = d.importNodeAnn(msg)
+
+		default:
+			return nil, errors.Errorf("unexpected message %T "+
+				"within graph snapshot", msg)
+		}
+
+		switch {
+		case applyErr == nil:
+
+		case routing.IsError(applyErr, routing.ErrOutdated,
+			routing.ErrIgnored):
+
+			log.Debugf("Skipping snapshot %v: %v", msg.MsgType(),
+				applyErr)
+			stats.NumSkipped++
+
+		default:
+			log.Warnf("Unable to import snapshot %v: %v",
+				msg.MsgType(), applyErr)
+			stats.NumSkipped++
+		}
+	}
+
+	log.Infof("Imported graph snapshot with %v channels, %v updates and "+
+		"%v nodes, %v messages skipped", stats.NumChannels,
+		stats.NumUpdates, stats.NumNodes, stats.NumSkipped)
+
+	return &stats, nil
+}
+
+// checkSnapshotChannel ensures that an announcement within a snapshot for the
+// passed channel targets our chain, and that the channel isn't beyond our
+// knowledge of the chain tip, mirroring the checks applied to announcements
+// received over gossip.
+func (d *AuthenticatedGossiper) checkSnapshotChannel(chainHash chainhash.Hash,
+	chanID lnwire.ShortChannelID) error {
+
+	if !bytes.Equal(chainHash[:], d.cfg.ChainHash[:]) {
+		return errors.Errorf("announcement for chain=%v, gossiper on "+
+			"chain=%v", chainHash, d.cfg.ChainHash)
+	}
+
+	// Unlike announcements received from a peer, we don't hold on to
+	// premature ones until we've caught up, as the snapshot can simply be
+	// imported again.
+	bestHeight := atomic.LoadUint32(&d.bestHeight)
+	if chanID.BlockHeight > bestHeight {
+		return errors.Errorf("announcement for short_chan_id=%v is "+
+			"premature: advertises height %v, only height %v is "+
+			"known", chanID.ToUint64(), chanID.BlockHeight,
+			bestHeight)
+	}
+
+	return nil
+}
+
+// importChanAnn validates the passed announcement, and adds the channel it
+// advertises to the router.
+func (d *AuthenticatedGossiper) importChanAnn(
+	msg *lnwire.ChannelAnnouncement) error {
+
+	err := d.checkSnapshotChannel(msg.ChainHash, msg.ShortChannelID)
+	if err != nil {
+		return err
+	}
+	if err := ValidateChannelAnn(msg); err != nil {
+		return errors.Errorf("unable to validate announcement: %v",
+			err)
+	}
+
+	var featureBuf bytes.Buffer
+	if err := msg.Features.Encode(&featureBuf); err != nil {
+		return err
+	}
+
+	edge := &channeldb.ChannelEdgeInfo{
+		ChannelID:   msg.ShortChannelID.ToUint64(),
+		ChainHash:   msg.ChainHash,
+		NodeKey1:    msg.NodeID1,
+		NodeKey2:    msg.NodeID2,
+		BitcoinKey1: msg.BitcoinKey1,
+		BitcoinKey2: msg.BitcoinKey2,
+		AuthProof: &channeldb.ChannelAuthProof{
+			NodeSig1:    msg.NodeSig1,
+			NodeSig2:    msg.NodeSig2,
+			BitcoinSig1: msg.BitcoinSig1,
+			BitcoinSig2: msg.BitcoinSig2,
+		},
+		Features: featureBuf.Bytes(),
+	}
+
+	d.channelMtx.Lock(msg.ShortChannelID.ToUint64())
+	defer d.channelMtx.Unlock(msg.ShortChannelID.ToUint64())
+
+	// The router will verify that the funding output of the channel
+	// exists, and that it matches the keys within the announcement.
+	return d.cfg.Router.AddEdge(edge)
+}
+
+// importChanUpdate validates the passed channel update, and applies the
+// policy it advertises to its directed edge within the router.
+func (d *AuthenticatedGossiper) importChanUpdate(
+	msg *lnwire.ChannelUpdate) error {
+
+	err := d.checkSnapshotChannel(msg.ChainHash, msg.ShortChannelID)
+	if err != nil {
+		return err
+	}
+
+	d.channelMtx.Lock(msg.ShortChannelID.ToUint64())
+	defer d.channelMtx.Unlock(msg.ShortChannelID.ToUint64())
+
+	chanInfo, _, _, err := d.cfg.Router.GetChannelByID(
+		msg.ShortChannelID,
+	)
+	if err != nil {
+		return errors.Errorf("unable to fetch channel "+
+			"short_chan_id=%v: %v", msg.ShortChannelID.ToUint64(),
+			err)
+	}
+
+	var pubKey *btcec.PublicKey
+	switch {
+	case msg.Flags&lnwire.ChanUpdateDirection == 0:
+		pubKey = chanInfo.NodeKey1
+	case msg.Flags&lnwire.ChanUpdateDirection == 1:
+		pubKey = chanInfo.NodeKey2
+	}
+	if err := ValidateChannelUpdateAnn(pubKey, msg); err != nil {
+		return err
+	}
+
+	return d.cfg.Router.UpdateEdge(&channeldb.ChannelEdgePolicy{
+		Signature:                 msg.Signature,
+		ChannelID:                 msg.ShortChannelID.ToUint64(),
+		LastUpdate:                time.Unix(int64(msg.Timestamp), 0),
+		Flags:                     msg.Flags,
+		TimeLockDelta:             msg.TimeLockDelta,
+		MinHTLC:                   msg.HtlcMinimumMsat,
+		FeeBaseMSat:               lnwire.MilliSatoshi(msg.BaseFee),
+		FeeProportionalMillionths: lnwire.MilliSatoshi(msg.FeeRate),
+	})
+}
+
+// importNodeAnn validates the passed node announcement, and applies it to the
+// router.
+func (d *AuthenticatedGossiper) importNodeAnn(
+	msg *lnwire.NodeAnnouncement) error {
+
+	if err := ValidateNodeAnn(msg); err != nil {
+		return errors.Errorf("unable to validate node "+
+			"announcement: %v", err)
+	}
+
+	features := lnwire.NewFeatureVector(msg.Features, lnwire.GlobalFeatures)
+	return d.cfg.Router.AddNode(&channeldb.LightningNode{
+		HaveNodeAnnouncement: true,
+		LastUpdate:           time.Unix(int64(msg.Timestamp), 0),
+		Addresses:            msg.Addresses,
+		PubKey:               msg.NodeID,
+		Alias:                msg.Alias.String(),
+		AuthSig:              msg.Signature,
+		Features:             features,
+		Color:                msg.RGBColor,
+	})
+}
+
+// writeSnapshotMsg writes the passed message to w, prefixed by its length.
+func writeSnapshotMsg(w io.Writer, msg lnwire.Message) error {
+	var b bytes.Buffer
+	if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+		return err
+	}
+
+	var length [2]byte
+	binary.BigEndian.PutUint16(length[:], uint16(b.Len()))
+	if _, err := w.Write(length[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// readSnapshotMsg reads the next length prefixed message from r. If no
+// messages remain, then io.EOF is returned.
+func readSnapshotMsg(r io.Reader) (lnwire.Message, error) {
+	var length [2]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+
+	msgBytes := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(r, msgBytes); err != nil {
+		return nil, ErrInvalidGraphSnapshot
+	}
+
+	return lnwire.ReadMessage(bytes.NewReader(msgBytes), 0)
+}
@@ -0,0 +1,216 @@
+package discovery
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// writeTestSnapshot creates a graph snapshot that contains the passed
+// messages.
+func writeTestSnapshot(t *testing.T, msgs ...lnwire.Message) *bytes.Buffer {
+	var b bytes.Buffer
+	b.Write(graphSnapshotMagic[:])
+	b.WriteByte(graphSnapshotVersion)
+	b.Write(make([]byte, 32))
+	for _, msg := range msgs {
+		if err := writeSnapshotMsg(&b, msg); err != nil {
+			t.Fatalf("unable to write snapshot msg: %v", err)
+		}
+	}
+
+	return &b
+}
+
+// TestGraphSnapshotRoundTrip ensures that a graph exported by one gossiper can
+// be imported by another, resulting in an identical view of the graph.
+func TestGraphSnapshotRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx, cleanup, err := createTestCtx(0)
+	if err != nil {
+		t.Fatalf("can't create context: %v", err)
+	}
+	defer cleanup()
+
+	batch, err := createAnnouncements(0)
+	if err != nil {
+		t.Fatalf("can't generate announcements: %v", err)
+	}
+
+	// We'll first seed the graph of the first gossiper by importing a
+	// snapshot containing a single fully announced channel.
+	snapshot := writeTestSnapshot(
+		t, batch.remoteChanAnn, batch.chanUpdAnn1, batch.chanUpdAnn2,
+		batch.nodeAnn1, batch.nodeAnn2,
+	)
+	stats, err := ctx.gossiper.ImportGraph(snapshot)
+	if err != nil {
+		t.Fatalf("unable to import graph: %v", err)
+	}
+	if stats.NumChannels != 1 || stats.NumUpdates != 2 ||
+		stats.NumNodes != 2 || stats.NumSkipped != 0 {
+
+		t.Fatalf("unexpected import stats: %v", spew.Sdump(stats))
+	}
+	if len(ctx.router.infos) != 1 {
+		t.Fatalf("expected 1 channel, got %v", len(ctx.router.infos))
+	}
+	if len(ctx.router.nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %v", len(ctx.router.nodes))
+	}
+
+	// Next, we'll export the graph of the first gossiper and import it
+	// into a fresh gossiper.
+	var exported bytes.Buffer
+	stats, err = ctx.gossiper.ExportGraph(&exported)
+	if err != nil {
+		t.Fatalf("unable to export graph: %v", err)
+	}
+	if stats.NumChannels != 1 || stats.NumUpdates != 2 ||
+		stats.NumNodes != 2 {
+
+		t.Fatalf("unexpected export stats: %v", spew.Sdump(stats))
+	}
+
+	ctx2, cleanup2, err := createTestCtx(0)
+	if err != nil {
+		t.Fatalf("can't create context: %v", err)
+	}
+	defer cleanup2()
+
+	stats, err = ctx2.gossiper.ImportGraph(&exported)
+	if err != nil {
+		t.Fatalf("unable to import graph: %v", err)
+	}
+	if stats.NumSkipped != 0 {
+		t.Fatalf("expected no skipped messages, got %v",
+			stats.NumSkipped)
+	}
+
+	// As all signatures were kept intact, the second gossiper should now
+	// have the exact same view of the channel.
+	chanID := batch.remoteChanAnn.ShortChannelID.ToUint64()
+	info := ctx2.router.infos[chanID]
+	if info == nil {
+		t.Fatalf("channel %v not imported", chanID)
+	}
+	if !info.NodeKey1.IsEqual(nodeKeyPub1) ||
+		!info.NodeKey2.IsEqual(nodeKeyPub2) {
+
+		t.Fatalf("imported channel has wrong node keys")
+	}
+	if len(ctx2.router.edges[chanID]) != 2 {
+		t.Fatalf("expected 2 policies, got %v",
+			len(ctx2.router.edges[chanID]))
+	}
+	if len(ctx2.router.nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %v", len(ctx2.router.nodes))
+	}
+}
+
+// TestGraphSnapshotValidation ensures that announcements within a snapshot
+// are validated as if they had arrived over gossip, such that those with
+// invalid signatures, or for channels beyond our chain tip, are skipped.
+func TestGraphSnapshotValidation(t *testing.T) {
+	t.Parallel()
+
+	batch, err := createAnnouncements(0)
+	if err != nil {
+		t.Fatalf("can't generate announcements: %v", err)
+	}
+
+	// We'll tamper with the first node announcement, invalidating its
+	// signature.
+	batch.nodeAnn1.Timestamp++
+
+	ctx, cleanup, err := createTestCtx(0)
+	if err != nil {
+		t.Fatalf("can't create context: %v", err)
+	}
+	defer cleanup()
+
+	snapshot := writeTestSnapshot(
+		t, batch.remoteChanAnn, batch.nodeAnn1, batch.nodeAnn2,
+	)
+	stats, err := ctx.gossiper.ImportGraph(snapshot)
+	if err != nil {
+		t.Fatalf("unable to import graph: %v", err)
+	}
+	if stats.NumSkipped != 1 {
+		t.Fatalf("expected 1 skipped message, got %v",
+			stats.NumSkipped)
+	}
+	if len(ctx.router.nodes) != 1 {
+		t.Fatalf("expected 1 node, got %v", len(ctx.router.nodes))
+	}
+
+	// A channel that was confirmed beyond our chain tip should be skipped
+	// along with its updates, as we're unable to verify its funding
+	// output yet.
+	premature, err := createAnnouncements(10)
+	if err != nil {
+		t.Fatalf("can't generate announcements: %v", err)
+	}
+
+	ctx2, cleanup2, err := createTestCtx(0)
+	if err != nil {
+		t.Fatalf("can't create context: %v", err)
+	}
+	defer cleanup2()
+
+	snapshot = writeTestSnapshot(
+		t, premature.remoteChanAnn, premature.chanUpdAnn1,
+		premature.chanUpdAnn2,
+	)
+	stats, err = ctx2.gossiper.ImportGraph(snapshot)
+	if err != nil {
+		t.Fatalf("unable to import graph: %v", err)
+	}
+	if stats.NumSkipped != 3 {
+		t.Fatalf("expected 3 skipped messages, got %v",
+			stats.NumSkipped)
+	}
+	if len(ctx2.router.infos) != 0 || len(ctx2.router.edges) != 0 {
+		t.Fatalf("premature channel imported")
+	}
+
+	// Likewise, a channel update with an invalid signature shouldn't be
+	// applied to a channel we know of.
+	batch.chanUpdAnn1.BaseFee++
+	snapshot = writeTestSnapshot(
+		t, batch.remoteChanAnn, batch.chanUpdAnn1, batch.chanUpdAnn2,
+	)
+	stats, err = ctx2.gossiper.ImportGraph(snapshot)
+	if err != nil {
+		t.Fatalf("unable to import graph: %v", err)
+	}
+	if stats.NumSkipped != 1 {
+		t.Fatalf("expected 1 skipped message, got %v",
+			stats.NumSkipped)
+	}
+	chanID := batch.remoteChanAnn.ShortChannelID.ToUint64()
+	if len(ctx2.router.edges[chanID]) != 1 {
+		t.Fatalf("expected 1 policy, got %v",
+			len(ctx2.router.edges[chanID]))
+	}
+}
+
+// TestGraphSnapshotInvalid ensures that we refuse to import a file that isn't
+// a graph snapshot.
+func TestGraphSnapshotInvalid(t *testing.T) {
+	t.Parallel()
+
+	ctx, cleanup, err := createTestCtx(0)
+	if err != nil {
+		t.Fatalf("can't create context: %v", err)
+	}
+	defer cleanup()
+
+	_, err = ctx.gossiper.ImportGraph(bytes.NewReader([]byte("kek")))
+	if err != ErrInvalidGraphSnapshot {
+		t.Fatalf("expected ErrInvalidGraphSnapshot, got %v", err)
+	}
+}
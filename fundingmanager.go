@@ -69,6 +69,11 @@ type reservationWithCtx struct {
 
 	chanAmt btcutil.Amount
 
+	// announceDepth is the number of confirmations the funding
+	// transaction must reach before the channel is announced. If zero,
+	// the default of 6 confirmations is used.
+	announceDepth uint32
+
 	updates chan *lnrpc.OpenStatusUpdate
 	err     chan error
 }
@@ -221,6 +226,12 @@ type fundingConfig struct {
 	// initially announcing channels.
 	DefaultRoutingPolicy htlcswitch.ForwardingPolicy
 
+	// PeerRoutingPolicy returns the routing policy used when initially
+	// announcing channels with the passed peer, which may differ from the
+	// DefaultRoutingPolicy. If nil, then the DefaultRoutingPolicy is used
+	// for all peers.
+	PeerRoutingPolicy func(*btcec.PublicKey) htlcswitch.ForwardingPolicy
+
 	// NumRequiredConfs is a function closure that helps the funding
 	// manager decide how many confirmations it should require for a
 	// channel extended to it. The function is able to take into account
@@ -235,11 +246,53 @@ type fundingConfig struct {
 	// contract breach.
 	RequiredRemoteDelay func(btcutil.Amount) uint16
 
+	// RequiredRemoteChanReserve is a function closure that, given the
+	// capacity of an incoming channel and the reserve the initiator has
+	// proposed for our side of the channel, returns the reserve we'll
+	// require the initiator to maintain in turn. A zero value indicates
+	// that the default reserve should be used.
+	RequiredRemoteChanReserve func(btcutil.Amount, btcutil.Amount) btcutil.Amount
+
+	// RequiredRemoteMaxValue is a function closure that, given the
+	// capacity of a channel, returns the maximum total value of unresolved
+	// HTLCs we'll permit the remote party to offer us. A zero value
+	// indicates that the default should be used, which is the full
+	// capacity of the channel, less the remote party's reserve.
+	RequiredRemoteMaxValue func(btcutil.Amount) lnwire.MilliSatoshi
+
+	// AnnouncementDepth is the number of confirmations the funding
+	// transaction of a public channel must reach before we announce it,
+	// unless a depth is specified when the channel is opened. It's only
+	// used if it's beyond the minimum of 6 confirmations.
+	AnnouncementDepth uint32
+
+	// MinAcceptedMaxValue is a function closure that, given the capacity
+	// of a channel, returns the smallest maximum value in flight we'll
+	// accept being restricted to by the remote party. Channels in which
+	// the remote party proposes a lower value are rejected.
+	MinAcceptedMaxValue func(btcutil.Amount) lnwire.MilliSatoshi
+
+	// ChainHealthy reports whether the chain backend is currently deemed
+	// healthy. While it isn't, the funding manager won't initiate or
+	// accept any new channels, as it can't reliably estimate fees or watch
+	// for the confirmation of funding transactions.
+	ChainHealthy func() bool
+
+	// AcceptChannelFrom, if non-nil, is consulted before accepting a
+	// channel opened by a remote peer. If it returns an error, then the
+	// channel is rejected.
+	AcceptChannelFrom func(peer *btcec.PublicKey) error
+
 	// WatchNewChannel is to be called once a new channel enters the final
 	// funding stage: waiting for on-chain confirmation. This method sends
 	// the channel to the ChainArbitrator so it can watch for any on-chain
 	// events related to the channel.
 	WatchNewChannel func(*channeldb.OpenChannel) error
+
+	// NotifyOpenChannelEvent, if non-nil, is called once a channel's
+	// funding transaction has been confirmed, and the channel has been
+	// marked as open within the database.
+	NotifyOpenChannelEvent func(wire.OutPoint)
 }
 
 // fundingManager acts as an orchestrator/bridge between the wallet's
@@ -342,6 +395,12 @@ var (
 	// of being opened.
 	channelOpeningStateBucket = []byte("channelOpeningState")
 
+	// announcementDepthBucket is the database bucket used to store the
+	// number of confirmations to wait for before announcing a channel, for
+	// each channel in the process of being opened that requires more than
+	// the default.
+	announcementDepthBucket = []byte("announcementDepth")
+
 	// ErrChannelNotFound is returned when we are looking for a specific
 	// channel opening state in the FundingManager's internal database, but
 	// the channel in question is not considered being in an opening state.
@@ -686,6 +745,36 @@ func (f *fundingManager) failFundingFlow(peer *btcec.PublicKey,
 	return
 }
 
+// validateMaxValueInFlight returns an error if the maximum value in flight
+// the remote party has proposed for our side of a channel of the given
+// capacity is below the minimum we're willing to accept.
+func (f *fundingManager) validateMaxValueInFlight(capacity btcutil.Amount,
+	maxValue lnwire.MilliSatoshi) error {
+
+	minMaxValue := f.cfg.MinAcceptedMaxValue(capacity)
+	if maxValue < minMaxValue {
+		return fmt.Errorf("Max value in flight of %v is below "+
+			"minimum of %v", maxValue, minMaxValue)
+	}
+
+	return nil
+}
+
+// registerRemoteMaxValue consults our policy to determine the maximum value
+// in flight we'll permit the remote party within a channel of the given
+// capacity, registering it with the reservation if it differs from the
+// default.
+func (f *fundingManager) registerRemoteMaxValue(
+	reservation *lnwallet.ChannelReservation, capacity btcutil.Amount) error {
+
+	maxValue := f.cfg.RequiredRemoteMaxValue(capacity)
+	if maxValue == 0 {
+		return nil
+	}
+
+	return reservation.RegisterRemoteMaxValueInFlight(maxValue)
+}
+
 // reservationCoordinator is the primary goroutine tasked with progressing the
 // funding workflow between the wallet, and any outside peers or local callers.
 //
@@ -808,6 +897,18 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 		return
 	}
 
+	// Similarly, if our chain backend is currently unhealthy, then we
+	// can't rely on our view of the chain, so we'll reject the request.
+	if !f.cfg.ChainHealthy() {
+		fndgLog.Warnf("Rejecting funding request from %x, chain "+
+			"backend is unhealthy", peerIDKey[:])
+		f.failFundingFlow(
+			fmsg.peerAddress.IdentityKey, fmsg.msg.PendingChannelID,
+			lnwire.ErrorData{byte(lnwire.ErrSynchronizingChain)},
+		)
+		return
+	}
+
 	// We'll reject any request to create a channel that's above the
 	// current soft-limit for channel size.
 	if msg.FundingAmount > maxFundingAmount {
@@ -818,6 +919,21 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 		return
 	}
 
+	// Finally, we'll ensure we're willing to accept channels from the
+	// peer at all.
+	if f.cfg.AcceptChannelFrom != nil {
+		err := f.cfg.AcceptChannelFrom(fmsg.peerAddress.IdentityKey)
+		if err != nil {
+			fndgLog.Warnf("Rejecting funding request from %x: %v",
+				peerIDKey[:], err)
+			f.failFundingFlow(
+				fmsg.peerAddress.IdentityKey,
+				fmsg.msg.PendingChannelID, []byte(err.Error()),
+			)
+			return
+		}
+	}
+
 	// TODO(roasbeef): error if funding flow already ongoing
 	fndgLog.Infof("Recv'd fundingRequest(amt=%v, push=%v, delay=%v, "+
 		"pendingId=%x) from peer(%x)", amt, msg.PushAmount,
@@ -852,6 +968,29 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 	numConfsReq := f.cfg.NumRequiredConfs(msg.FundingAmount, msg.PushAmount)
 	reservation.SetNumConfsRequired(numConfsReq)
 
+	// The initiator may propose a lower reserve for our side of the
+	// channel than the default, but it must never be below the dust limit
+	// they've specified.
+	if msg.ChannelReserve < msg.DustLimit {
+		f.failFundingFlow(
+			fmsg.peerAddress.IdentityKey, fmsg.msg.PendingChannelID,
+			[]byte(fmt.Sprintf("Channel reserve of %v is below "+
+				"dust limit of %v", msg.ChannelReserve,
+				msg.DustLimit)),
+		)
+		return
+	}
+
+	// The initiator mustn't restrict the value of the HTLCs we may have in
+	// flight below our minimum.
+	if err := f.validateMaxValueInFlight(amt, msg.MaxValueInFlight); err != nil {
+		f.failFundingFlow(
+			fmsg.peerAddress.IdentityKey, fmsg.msg.PendingChannelID,
+			[]byte(err.Error()),
+		)
+		return
+	}
+
 	// We'll also validate and apply all the constraints the initiating
 	// party is attempting to dictate for our commitment transaction.
 	err = reservation.CommitConstraints(
@@ -868,6 +1007,32 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 	}
 	reservation.RegisterMinHTLC(f.cfg.DefaultRoutingPolicy.MinHTLC)
 
+	// Finally, we'll consult our policy to determine the reserve we'll
+	// require the initiator to maintain, given the reserve they've
+	// proposed for our side of the channel.
+	remoteReserve := f.cfg.RequiredRemoteChanReserve(amt, msg.ChannelReserve)
+	if remoteReserve != 0 {
+		err := reservation.RegisterRemoteChanReserve(remoteReserve)
+		if err != nil {
+			f.failFundingFlow(
+				fmsg.peerAddress.IdentityKey,
+				fmsg.msg.PendingChannelID,
+				[]byte(fmt.Sprintf("Unacceptable channel "+
+					"reserve: %v", err)),
+			)
+			return
+		}
+	}
+	err = f.registerRemoteMaxValue(reservation, amt)
+	if err != nil {
+		f.failFundingFlow(
+			fmsg.peerAddress.IdentityKey, fmsg.msg.PendingChannelID,
+			[]byte(fmt.Sprintf("Unacceptable max value in "+
+				"flight: %v", err)),
+		)
+		return
+	}
+
 	fndgLog.Infof("Requiring %v confirmations for pendingChan(%x): "+
 		"amt=%v, push_amt=%v", numConfsReq, fmsg.msg.PendingChannelID,
 		amt, msg.PushAmount)
@@ -880,10 +1045,11 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 		f.activeReservations[peerIDKey] = make(pendingChannels)
 	}
 	f.activeReservations[peerIDKey][msg.PendingChannelID] = &reservationWithCtx{
-		reservation: reservation,
-		chanAmt:     amt,
-		err:         make(chan error, 1),
-		peerAddress: fmsg.peerAddress,
+		reservation:   reservation,
+		chanAmt:       amt,
+		announceDepth: f.cfg.AnnouncementDepth,
+		err:           make(chan error, 1),
+		peerAddress:   fmsg.peerAddress,
 	}
 	f.resMtx.Unlock()
 
@@ -990,6 +1156,14 @@ func (f *fundingManager) handleFundingAccept(fmsg *fundingAcceptMsg) {
 	// required confirmations, and also the set of channel constraints
 	// they've specified for commitment states we can create.
 	resCtx.reservation.SetNumConfsRequired(uint16(msg.MinAcceptDepth))
+	err = f.validateMaxValueInFlight(resCtx.chanAmt, msg.MaxValueInFlight)
+	if err != nil {
+		f.failFundingFlow(
+			fmsg.peerAddress.IdentityKey, fmsg.msg.PendingChannelID,
+			[]byte(err.Error()),
+		)
+		return
+	}
 	err = resCtx.reservation.CommitConstraints(
 		uint16(msg.CsvDelay), msg.MaxAcceptedHTLCs,
 		msg.MaxValueInFlight, msg.ChannelReserve,
@@ -1147,6 +1321,8 @@ func (f *fundingManager) handleFundingCreated(fmsg *fundingCreatedMsg) {
 		return
 	}
 
+	f.saveAnnouncementDepth(&fundingOut, resCtx.announceDepth)
+
 	// If something goes wrong before the funding transaction is confirmed,
 	// we use this convenience method to delete the pending OpenChannel
 	// from the database.
@@ -1342,6 +1518,8 @@ func (f *fundingManager) handleFundingSigned(fmsg *fundingSignedMsg) {
 		return
 	}
 
+	f.saveAnnouncementDepth(fundingPoint, resCtx.announceDepth)
+
 	// Now that we have a finalized reservation for this funding flow,
 	// we'll send the to be active channel to the ChainArbitrator so it can
 	// watch for any on-chin actions before the channel has fully
@@ -1623,6 +1801,10 @@ func (f *fundingManager) waitForFundingConfirmation(completeChan *channeldb.Open
 		return
 	}
 
+	if f.cfg.NotifyOpenChannelEvent != nil {
+		f.cfg.NotifyOpenChannelEvent(completeChan.FundingOutpoint)
+	}
+
 	select {
 	case confChan <- &shortChanID:
 	case <-f.quit:
@@ -1837,6 +2019,19 @@ func (f *fundingManager) annAfterSixConfs(completeChan *channeldb.OpenChannel,
 		if numConfs < 6 {
 			numConfs = 6
 		}
+
+		// If a greater depth was chosen for this channel, then we'll
+		// defer the announcement until it's reached.
+		announceDepth, err := f.getAnnouncementDepth(
+			&completeChan.FundingOutpoint,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch announcement "+
+				"depth: %v", err)
+		}
+		if announceDepth > numConfs {
+			numConfs = announceDepth
+		}
 		txid := completeChan.FundingOutpoint.Hash
 		fndgLog.Debugf("Will announce channel %v after ChannelPoint"+
 			"(%v) has gotten %d confirmations",
@@ -2125,21 +2320,26 @@ func (f *fundingManager) newChanAnnouncement(localPubKey, remotePubKey *btcec.Pu
 		chanFlags = 1
 	}
 
-	// We announce the channel with the default values. Some of
-	// these values can later be changed by crafting a new ChannelUpdate.
+	// We announce the channel with the default values for the remote
+	// peer. Some of these values can later be changed by crafting a new
+	// ChannelUpdate.
+	routingPolicy := f.cfg.DefaultRoutingPolicy
+	if f.cfg.PeerRoutingPolicy != nil {
+		routingPolicy = f.cfg.PeerRoutingPolicy(remotePubKey)
+	}
 	chanUpdateAnn := &lnwire.ChannelUpdate{
 		ShortChannelID: shortChanID,
 		ChainHash:      chainHash,
 		Timestamp:      uint32(time.Now().Unix()),
 		Flags:          chanFlags,
-		TimeLockDelta:  uint16(f.cfg.DefaultRoutingPolicy.TimeLockDelta),
+		TimeLockDelta:  uint16(routingPolicy.TimeLockDelta),
 
 		// We use the *remote* party's HtlcMinimumMsat, as they'll be
 		// the ones carrying the HTLC routed *towards* us.
 		HtlcMinimumMsat: remoteMinHTLC,
 
-		BaseFee: uint32(f.cfg.DefaultRoutingPolicy.BaseFee),
-		FeeRate: uint32(f.cfg.DefaultRoutingPolicy.FeeRate),
+		BaseFee: uint32(routingPolicy.BaseFee),
+		FeeRate: uint32(routingPolicy.FeeRate),
 	}
 
 	// With the channel update announcement constructed, we'll generate a
@@ -2286,11 +2486,20 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		msg.pushAmt, capacity, msg.chainHash, msg.peerAddress.Address,
 		ourDustLimit)
 
+	// We won't initiate any new channels while the chain backend is
+	// unhealthy, as the fee estimate below may be stale.
+	if !f.cfg.ChainHealthy() {
+		msg.err <- errChainBackendUnhealthy
+		return
+	}
+
 	// First, we'll query the fee estimator for a fee that should get the
 	// commitment transaction confirmed by the next few blocks (conf target
 	// of 3). We target the near blocks here to ensure that we'll be able
 	// to execute a timely unilateral channel closure if needed.
-	feePerWeight, err := f.cfg.FeeEstimator.EstimateFeePerWeight(3)
+	feePerWeight, err := lnwallet.EstimateRelayableFeePerWeight(
+		f.cfg.FeeEstimator, 3,
+	)
 	if err != nil {
 		msg.err <- err
 		return
@@ -2335,12 +2544,17 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		f.activeReservations[peerIDKey] = make(pendingChannels)
 	}
 
+	announceDepth := msg.announceDepth
+	if announceDepth == 0 {
+		announceDepth = f.cfg.AnnouncementDepth
+	}
 	f.activeReservations[peerIDKey][chanID] = &reservationWithCtx{
-		chanAmt:     capacity,
-		reservation: reservation,
-		peerAddress: msg.peerAddress,
-		updates:     msg.updates,
-		err:         msg.err,
+		chanAmt:       capacity,
+		reservation:   reservation,
+		peerAddress:   msg.peerAddress,
+		announceDepth: announceDepth,
+		updates:       msg.updates,
+		err:           msg.err,
 	}
 	f.resMtx.Unlock()
 
@@ -2358,6 +2572,23 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 	reservation.RegisterMinHTLC(minHtlc)
 	ourContribution := reservation.OurContribution()
 
+	// If a custom reserve for the remote party was requested, then we'll
+	// propose it in place of the default. The remote party's policy will
+	// determine if it's acceptable.
+	if msg.remoteChanReserve != 0 {
+		err := reservation.RegisterRemoteChanReserve(msg.remoteChanReserve)
+		if err != nil {
+			f.cancelReservationCtx(peerKey, chanID)
+			msg.err <- err
+			return
+		}
+	}
+	if err := f.registerRemoteMaxValue(reservation, capacity); err != nil {
+		f.cancelReservationCtx(peerKey, chanID)
+		msg.err <- err
+		return
+	}
+
 	// Finally, we'll use the current value of the channels and our default
 	// policy to determine of required commitment constraints for the
 	// remote party.
@@ -2611,6 +2842,83 @@ func (f *fundingManager) deleteChannelOpeningState(chanPoint *wire.OutPoint) err
 			return err
 		}
 
+		// The announcement depth is no longer needed once the channel
+		// has been opened.
+		depthBucket := tx.Bucket(announcementDepthBucket)
+		if depthBucket != nil {
+			err := depthBucket.Delete(outpointBytes.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
 		return bucket.Delete(outpointBytes.Bytes())
 	})
 }
+
+// saveAnnouncementDepth saves the number of confirmations the funding
+// transaction of the channel must reach before the channel is announced. A
+// depth that doesn't exceed the default of 6 confirmations isn't stored.
+// Failures are only logged, as the channel will then be announced at the
+// default depth.
+func (f *fundingManager) saveAnnouncementDepth(chanPoint *wire.OutPoint,
+	depth uint32) {
+
+	if depth <= 6 {
+		return
+	}
+
+	err := f.cfg.Wallet.Cfg.Database.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(announcementDepthBucket)
+		if err != nil {
+			return err
+		}
+
+		var outpointBytes bytes.Buffer
+		if err := writeOutpoint(&outpointBytes, chanPoint); err != nil {
+			return err
+		}
+
+		var scratch [4]byte
+		byteOrder.PutUint32(scratch[:], depth)
+
+		return bucket.Put(outpointBytes.Bytes(), scratch[:])
+	})
+	if err != nil {
+		fndgLog.Errorf("Unable to save announcement depth of %v for "+
+			"ChannelPoint(%v): %v", depth, chanPoint, err)
+	}
+}
+
+// getAnnouncementDepth fetches the number of confirmations the funding
+// transaction of the channel must reach before the channel is announced. If
+// no depth was stored for the channel, then zero is returned.
+func (f *fundingManager) getAnnouncementDepth(
+	chanPoint *wire.OutPoint) (uint32, error) {
+
+	var depth uint32
+	err := f.cfg.Wallet.Cfg.Database.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(announcementDepthBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		var outpointBytes bytes.Buffer
+		if err := writeOutpoint(&outpointBytes, chanPoint); err != nil {
+			return err
+		}
+
+		value := bucket.Get(outpointBytes.Bytes())
+		if len(value) != 4 {
+			return nil
+		}
+		depth = byteOrder.Uint32(value)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return depth, nil
+}
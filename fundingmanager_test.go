@@ -269,7 +269,25 @@ func createTestFundingManager(t *testing.T, privKey *btcec.PrivateKey,
 		RequiredRemoteDelay: func(amt btcutil.Amount) uint16 {
 			return 4
 		},
+		RequiredRemoteChanReserve: func(chanAmt,
+			proposedReserve btcutil.Amount) btcutil.Amount {
+
+			return 0
+		},
+		RequiredRemoteMaxValue: func(
+			chanAmt btcutil.Amount) lnwire.MilliSatoshi {
+
+			return 0
+		},
+		MinAcceptedMaxValue: func(
+			chanAmt btcutil.Amount) lnwire.MilliSatoshi {
+
+			return 0
+		},
 		ArbiterChan: arbiterChan,
+		ChainHealthy: func() bool {
+			return true
+		},
 		WatchNewChannel: func(*channeldb.OpenChannel) error {
 			return nil
 		},
@@ -345,6 +363,7 @@ func recreateAliceFundingManager(t *testing.T, alice *testNode) {
 		TempChanIDSeed: oldCfg.TempChanIDSeed,
 		ArbiterChan:    alice.arbiterChan,
 		FindChannel:    oldCfg.FindChannel,
+		ChainHealthy:   oldCfg.ChainHealthy,
 	})
 	if err != nil {
 		t.Fatalf("failed recreating aliceFundingManager: %v", err)
@@ -1544,3 +1563,142 @@ func TestFundingManagerPrivateRestart(t *testing.T) {
 	// from the database, as the channel is announced.
 	assertNoChannelState(t, alice, bob, fundingOutPoint)
 }
+
+// TestFundingManagerCustomChanReserve checks that a custom reserve for the
+// remote party is proposed by the initiator, and that the responder's policy
+// determines the reserve it requires in turn.
+func TestFundingManagerCustomChanReserve(t *testing.T) {
+	alice, bob := setupFundingManagers(t)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	// Bob will match any lower reserve proposed by the initiator.
+	bob.fundingMgr.cfg.RequiredRemoteChanReserve = func(chanAmt,
+		proposedReserve btcutil.Amount) btcutil.Amount {
+
+		return proposedReserve
+	}
+
+	// Alice will propose a reserve that's below the default of 1% of the
+	// channel capacity.
+	const reserve = btcutil.Amount(1000)
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	errChan := make(chan error, 1)
+	initReq := &openChanReq{
+		targetPeerID:      int32(1),
+		targetPubkey:      bob.privKey.PubKey(),
+		chainHash:         *activeNetParams.GenesisHash,
+		localFundingAmt:   500000,
+		remoteChanReserve: reserve,
+		updates:           updateChan,
+		err:               errChan,
+	}
+	alice.fundingMgr.initFundingWorkflow(bobAddr, initReq)
+
+	var aliceMsg lnwire.Message
+	select {
+	case aliceMsg = <-alice.msgChan:
+	case err := <-initReq.err:
+		t.Fatalf("error init funding workflow: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenChannel message")
+	}
+	openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel)
+	if !ok {
+		t.Fatalf("expected OpenChannel to be sent from alice, "+
+			"instead got %T", aliceMsg)
+	}
+	if openChannelReq.ChannelReserve != reserve {
+		t.Fatalf("expected alice to propose reserve of %v, got %v",
+			reserve, openChannelReq.ChannelReserve)
+	}
+
+	// As Bob's policy is to match the proposed reserve, he should require
+	// the same reserve from Alice.
+	bob.fundingMgr.processFundingOpen(openChannelReq, aliceAddr)
+
+	var bobMsg lnwire.Message
+	select {
+	case bobMsg = <-bob.msgChan:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("bob did not send AcceptChannel message")
+	}
+	acceptChannelResponse, ok := bobMsg.(*lnwire.AcceptChannel)
+	if !ok {
+		t.Fatalf("expected AcceptChannel to be sent from bob, "+
+			"instead got %T", bobMsg)
+	}
+	if acceptChannelResponse.ChannelReserve != reserve {
+		t.Fatalf("expected bob to require reserve of %v, got %v",
+			reserve, acceptChannelResponse.ChannelReserve)
+	}
+
+	// Finally, a reserve below the dust limit should be rejected before
+	// any message is sent to the remote peer.
+	initReq = &openChanReq{
+		targetPeerID:      int32(1),
+		targetPubkey:      bob.privKey.PubKey(),
+		chainHash:         *activeNetParams.GenesisHash,
+		localFundingAmt:   500000,
+		remoteChanReserve: lnwallet.DefaultDustLimit() - 1,
+		updates:           updateChan,
+		err:               make(chan error, 1),
+	}
+	alice.fundingMgr.initFundingWorkflow(bobAddr, initReq)
+
+	select {
+	case <-initReq.err:
+	case msg := <-alice.msgChan:
+		t.Fatalf("expected reserve to be rejected, instead alice "+
+			"sent %T", msg)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("expected reserve below dust limit to be rejected")
+	}
+}
+
+// TestFundingManagerAnnouncementDepth tests that the announcement depth of a
+// channel is persisted only if it exceeds the default depth, and that it's
+// removed along with the channel's opening state.
+func TestFundingManagerAnnouncementDepth(t *testing.T) {
+	alice, bob := setupFundingManagers(t)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	chanPoint := &wire.OutPoint{Index: 1}
+
+	// A depth that doesn't exceed the default shouldn't be stored.
+	alice.fundingMgr.saveAnnouncementDepth(chanPoint, 6)
+	depth, err := alice.fundingMgr.getAnnouncementDepth(chanPoint)
+	if err != nil {
+		t.Fatalf("unable to fetch announcement depth: %v", err)
+	}
+	if depth != 0 {
+		t.Fatalf("expected no announcement depth, got %v", depth)
+	}
+
+	alice.fundingMgr.saveAnnouncementDepth(chanPoint, 144)
+	depth, err = alice.fundingMgr.getAnnouncementDepth(chanPoint)
+	if err != nil {
+		t.Fatalf("unable to fetch announcement depth: %v", err)
+	}
+	if depth != 144 {
+		t.Fatalf("expected announcement depth of 144, got %v", depth)
+	}
+
+	// Once the channel's opening state is deleted, the announcement
+	// depth should be removed as well.
+	err = alice.fundingMgr.saveChannelOpeningState(
+		chanPoint, markedOpen, &lnwire.ShortChannelID{},
+	)
+	if err != nil {
+		t.Fatalf("unable to save channel opening state: %v", err)
+	}
+	if err := alice.fundingMgr.deleteChannelOpeningState(chanPoint); err != nil {
+		t.Fatalf("unable to delete channel opening state: %v", err)
+	}
+	depth, err = alice.fundingMgr.getAnnouncementDepth(chanPoint)
+	if err != nil {
+		t.Fatalf("unable to fetch announcement depth: %v", err)
+	}
+	if depth != 0 {
+		t.Fatalf("expected no announcement depth, got %v", depth)
+	}
+}
@@ -0,0 +1,235 @@
+package htlcswitch
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultMaxOverflowQueueLen is the default number of HTLCs a link
+	// may hold within its overflow queue. It matches the number of HTLCs
+	// we may offer within a single commitment transaction, as HTLCs
+	// beyond those would have to wait for more than a full commitment's
+	// worth of HTLCs to be resolved.
+	DefaultMaxOverflowQueueLen = lnwallet.MaxHTLCNumber / 2
+
+	// DefaultMaxOverflowResidency is the default amount of time the HTLC
+	// at the head of a link's overflow queue may wait for a commitment
+	// slot before new HTLCs are no longer admitted to the queue.
+	DefaultMaxOverflowResidency = time.Minute
+)
+
+// ErrInsufficientCapacity is returned when an outgoing HTLC isn't admitted
+// to the overflow queue of a link, as the channel's commitment slots have
+// remained exhausted beyond the queue's budget.
+var ErrInsufficientCapacity = errors.New("insufficient capacity: overflow " +
+	"queue budget exhausted")
+
+// AdmissionStats describes the overflow queue of a link, and the admission of
+// outgoing HTLCs to it once the commitment slots of the channel have been
+// exhausted.
+type AdmissionStats struct {
+	// FreeSlots is the number of additional HTLCs we may currently offer
+	// within the channel's commitment transaction.
+	FreeSlots int
+
+	// QueueLen is the number of HTLCs within the overflow queue.
+	QueueLen int32
+
+	// PeakQueueLen is the largest number of HTLCs the overflow queue has
+	// held at once.
+	PeakQueueLen int32
+
+	// QueuedAmount is the total value of the HTLCs within the overflow
+	// queue.
+	QueuedAmount lnwire.MilliSatoshi
+
+	// OldestResidency is the amount of time the HTLC at the head of the
+	// overflow queue has been waiting for a commitment slot.
+	OldestResidency time.Duration
+
+	// AvgResidency is the average amount of time the HTLCs which have left
+	// the overflow queue waited for a commitment slot.
+	AvgResidency time.Duration
+
+	// NumAdmitted is the number of HTLCs admitted to the overflow queue.
+	NumAdmitted uint64
+
+	// NumRejected is the number of HTLCs failed with
+	// ErrInsufficientCapacity, rather than admitted to the overflow
+	// queue.
+	NumRejected uint64
+
+	// LastRejection is the time the last HTLC was failed with
+	// ErrInsufficientCapacity. It's zero if none have been.
+	LastRejection time.Time
+
+	// Saturated is true if the budget of the overflow queue is currently
+	// exhausted, such that new HTLCs would be failed with
+	// ErrInsufficientCapacity.
+	Saturated bool
+}
+
+// admissionController guards the overflow queue of a link. It admits HTLCs
+// which overflow the channel's commitment slots to the queue, until either the
+// queue holds maxQueueLen HTLCs, or the HTLC at its head has been waiting for
+// a slot for longer than maxResidency. The latter ensures that HTLCs aren't
+// held indefinitely should the channel remain saturated, as would otherwise
+// be the case with a queue of modest length.
+type admissionController struct {
+	// The following fields are only meant to be used *atomically*.
+	numAdmitted   uint64
+	numRejected   uint64
+	lastRejection int64
+
+	queue *packetQueue
+
+	// maxQueueLen is the number of HTLCs the queue may hold. A value of
+	// zero disables the limit.
+	maxQueueLen int32
+
+	// maxResidency is the amount of time the HTLC at the head of the queue
+	// may wait for a commitment slot before no further HTLCs are
+	// admitted. A value of zero disables the limit.
+	maxResidency time.Duration
+}
+
+// newAdmissionController returns an admissionController which guards the
+// passed overflow queue with the given budget.
+func newAdmissionController(queue *packetQueue, maxQueueLen int32,
+	maxResidency time.Duration) *admissionController {
+
+	return &admissionController{
+		queue:        queue,
+		maxQueueLen:  maxQueueLen,
+		maxResidency: maxResidency,
+	}
+}
+
+// residencyExceeded returns true if the HTLC at the head of the queue has
+// been waiting for a commitment slot for longer than the budget permits.
+func (a *admissionController) residencyExceeded(now time.Time) bool {
+	if a.maxResidency == 0 {
+		return false
+	}
+
+	queuedAt, ok := a.queue.OldestQueuedAt()
+	return ok && now.Sub(queuedAt) >= a.maxResidency
+}
+
+// queueFull returns true if the queue holds as many HTLCs as the budget
+// permits.
+func (a *admissionController) queueFull() bool {
+	return a.maxQueueLen != 0 && a.queue.Length() >= a.maxQueueLen
+}
+
+// replaceQueueLen returns the length at which a link using the
+// OverflowReplace policy starts to evict HTLCs from the queue, rather than
+// adding to it.
+func (a *admissionController) replaceQueueLen() int32 {
+	if a.maxQueueLen != 0 && a.maxQueueLen < maxReplaceQueueLen {
+		return a.maxQueueLen
+	}
+
+	return maxReplaceQueueLen
+}
+
+// admit determines whether a new HTLC may be added to the queue.
+// ErrInsufficientCapacity is returned if the budget of the queue is
+// exhausted.
+func (a *admissionController) admit(now time.Time) error {
+	if a.residencyExceeded(now) || a.queueFull() {
+		return a.reject(now)
+	}
+
+	a.recordAdmission()
+	return nil
+}
+
+// admitReplacement determines whether a new HTLC may take the place of one
+// within the queue. As this doesn't grow the queue, only the residency of the
+// queue is considered. Should the HTLC take the place of another, then the
+// caller is to record its admission with recordAdmission.
+func (a *admissionController) admitReplacement(now time.Time) error {
+	if a.residencyExceeded(now) {
+		return a.reject(now)
+	}
+
+	return nil
+}
+
+// recordAdmission records the admission of an HTLC to the queue.
+func (a *admissionController) recordAdmission() {
+	atomic.AddUint64(&a.numAdmitted, 1)
+}
+
+// reject records the rejection of an HTLC, and returns
+// ErrInsufficientCapacity.
+func (a *admissionController) reject(now time.Time) error {
+	atomic.AddUint64(&a.numRejected, 1)
+	atomic.StoreInt64(&a.lastRejection, now.UnixNano())
+
+	return ErrInsufficientCapacity
+}
+
+// stats returns the current statistics of the queue and its admission
+// control. The number of free commitment slots is left to the caller.
+func (a *admissionController) stats(now time.Time) AdmissionStats {
+	stats := AdmissionStats{
+		QueueLen:     a.queue.Length(),
+		PeakQueueLen: a.queue.PeakLength(),
+		QueuedAmount: a.queue.TotalHtlcAmount(),
+		AvgResidency: a.queue.AvgResidency(),
+		NumAdmitted:  atomic.LoadUint64(&a.numAdmitted),
+		NumRejected:  atomic.LoadUint64(&a.numRejected),
+		Saturated:    a.residencyExceeded(now) || a.queueFull(),
+	}
+
+	if queuedAt, ok := a.queue.OldestQueuedAt(); ok {
+		stats.OldestResidency = now.Sub(queuedAt)
+	}
+	lastRejection := atomic.LoadInt64(&a.lastRejection)
+	if lastRejection != 0 {
+		stats.LastRejection = time.Unix(0, lastRejection)
+	}
+
+	return stats
+}
+
+// AdmissionStats returns the statistics of the link's overflow queue, and the
+// admission of outgoing HTLCs to it.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) AdmissionStats() AdmissionStats {
+	stats := l.admission.stats(time.Now())
+
+	numOutgoing, _ := l.channel.NumInFlightHtlcs()
+	if numOutgoing < lnwallet.MaxHTLCNumber/2 {
+		stats.FreeSlots = lnwallet.MaxHTLCNumber/2 - numOutgoing
+	}
+
+	return stats
+}
+
+// rejectOverflow fails an outgoing HTLC which wasn't admitted to the overflow
+// queue. If it's being forwarded, then the switch is first given the chance
+// to retry it on another link to the same peer.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) rejectOverflow(pkt *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC, err error) {
+
+	log.Debugf("ChannelPoint(%v): rejecting overflowing htlc with "+
+		"payment_hash=%x: %v", l.channel.ChannelPoint(),
+		htlc.PaymentHash[:], err)
+
+	if l.retryForward(pkt, err) {
+		return
+	}
+
+	l.failAddPacketWith(pkt, htlc, l.temporaryChannelFailure())
+}
@@ -0,0 +1,92 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestAdmissionController tests that HTLCs are only admitted to the overflow
+// queue while it's within its budget, both in terms of its length and of the
+// residency of the HTLC at its head.
+func TestAdmissionController(t *testing.T) {
+	t.Parallel()
+
+	const maxResidency = time.Minute
+
+	queue := newPacketQueue(10)
+	admission := newAdmissionController(queue, 2, maxResidency)
+
+	addPkt := func() {
+		queue.AddPkt(&htlcPacket{
+			amount: 1000,
+			htlc:   &lnwire.UpdateAddHTLC{},
+		})
+	}
+
+	// The queue is empty, so the first two HTLCs should be admitted.
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := admission.admit(now); err != nil {
+			t.Fatalf("htlc %v not admitted: %v", i, err)
+		}
+		addPkt()
+	}
+
+	// As the queue is now full, further HTLCs should be rejected, though
+	// they may still take the place of queued HTLCs.
+	if err := admission.admit(now); err != ErrInsufficientCapacity {
+		t.Fatalf("expected ErrInsufficientCapacity, got %v", err)
+	}
+	if err := admission.admitReplacement(now); err != nil {
+		t.Fatalf("replacement not admitted: %v", err)
+	}
+
+	stats := admission.stats(now)
+	if !stats.Saturated {
+		t.Fatalf("full queue not reported as saturated")
+	}
+	if stats.QueueLen != 2 || stats.PeakQueueLen != 2 {
+		t.Fatalf("expected queue length and peak of 2, got %v and %v",
+			stats.QueueLen, stats.PeakQueueLen)
+	}
+	if stats.QueuedAmount != 2000 {
+		t.Fatalf("expected queued amount of 2000, got %v",
+			stats.QueuedAmount)
+	}
+	if stats.NumAdmitted != 2 || stats.NumRejected != 1 {
+		t.Fatalf("expected 2 admitted and 1 rejected htlcs, got %v "+
+			"and %v", stats.NumAdmitted, stats.NumRejected)
+	}
+	if !stats.LastRejection.Equal(now) {
+		t.Fatalf("expected last rejection at %v, got %v", now,
+			stats.LastRejection)
+	}
+
+	// With the length limit lifted, HTLCs should be admitted until the HTLC
+	// at the head of the queue has been waiting for too long, at which
+	// point even replacements are rejected.
+	admission.maxQueueLen = 0
+	if err := admission.admit(now); err != nil {
+		t.Fatalf("htlc not admitted: %v", err)
+	}
+
+	later := time.Now().Add(maxResidency)
+	if err := admission.admit(later); err != ErrInsufficientCapacity {
+		t.Fatalf("expected ErrInsufficientCapacity, got %v", err)
+	}
+	err := admission.admitReplacement(later)
+	if err != ErrInsufficientCapacity {
+		t.Fatalf("expected ErrInsufficientCapacity, got %v", err)
+	}
+
+	stats = admission.stats(later)
+	if !stats.Saturated {
+		t.Fatalf("stale queue not reported as saturated")
+	}
+	if stats.OldestResidency < maxResidency {
+		t.Fatalf("expected residency of at least %v, got %v",
+			maxResidency, stats.OldestResidency)
+	}
+}
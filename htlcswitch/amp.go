@@ -0,0 +1,56 @@
+package htlcswitch
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// AmpRecord is carried within the onion of each part of an AMP payment, as
+// part of its FinalHopRecords. The payment is made without an invoice: the
+// sender picks a random root seed, and splits it into one share per part,
+// such that the root seed is the XOR of all shares. The preimage of each part
+// is derived from the root seed and the child index of the part, so the
+// receiver is only able to settle the parts once all of them have arrived,
+// making the payment atomic.
+type AmpRecord struct {
+	// SetID identifies the payment the part belongs to. It's shared by all
+	// of its parts.
+	SetID [32]byte
+
+	// Share is the share of the root seed carried by the part.
+	Share [32]byte
+
+	// ChildIndex is the index of the part within the payment, from which
+	// the preimage of the part is derived.
+	ChildIndex uint32
+
+	// TotalAmt is the total amount of the payment. Once the parts received
+	// add up to it, all parts are expected to have arrived. It's carried
+	// within the MPP record accompanying the AMP record.
+	TotalAmt lnwire.MilliSatoshi
+}
+
+// AmpRootSeed reconstructs the root seed of an AMP payment from the shares
+// carried by all of its parts.
+func AmpRootSeed(shares [][32]byte) [32]byte {
+	var root [32]byte
+	for _, share := range shares {
+		for i := range root {
+			root[i] ^= share[i]
+		}
+	}
+
+	return root
+}
+
+// AmpChildPreimage derives the preimage of the part of an AMP payment with
+// the passed child index from the root seed of the payment.
+func AmpChildPreimage(root [32]byte, childIndex uint32) [32]byte {
+	var b [36]byte
+	copy(b[:32], root[:])
+	binary.BigEndian.PutUint32(b[32:], childIndex)
+
+	return sha256.Sum256(b[:])
+}
@@ -0,0 +1,286 @@
+package htlcswitch
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// TestSphinxAmpRecord ensures that the AMP record of a part of an AMP payment
+// is only extracted from the onion if all of the record payloads following
+// ours are destined to us as well.
+func TestSphinxAmpRecord(t *testing.T) {
+	t.Parallel()
+
+	ourKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	otherKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	processor := NewSphinxOnionProcessor(
+		sphinx.NewRouter(ourKey, &chaincfg.MainNetParams), nil,
+	)
+
+	record := &AmpRecord{
+		SetID:      [32]byte{1},
+		ChildIndex: 3,
+		TotalAmt:   5000,
+	}
+	if _, err := rand.Read(record.Share[:]); err != nil {
+		t.Fatalf("unable to generate share: %v", err)
+	}
+	paymentHash := sha256.Sum256([]byte("amp"))
+
+	ampHop := sphinx.HopData{
+		ForwardAmount: 1000,
+		OutgoingCltv:  100,
+	}
+	binary.BigEndian.PutUint64(
+		ampHop.NextAddress[:], FinalRecordsHop.ToUint64(),
+	)
+	recordPayloads, err := NewFinalHopData(&FinalHopRecords{Amp: record})
+	if err != nil {
+		t.Fatalf("unable to create record payloads: %v", err)
+	}
+	ampPayloads := append([]sphinx.HopData{ampHop}, recordPayloads...)
+
+	ourKeys := func(n int) []*btcec.PublicKey {
+		keys := make([]*btcec.PublicKey, n)
+		for i := range keys {
+			keys[i] = ourKey.PubKey()
+		}
+		return keys
+	}
+	otherPath := append(ourKeys(len(ampPayloads)-1), otherKey.PubKey())
+
+	tests := []struct {
+		name            string
+		nodes           []*btcec.PublicKey
+		payloads        []sphinx.HopData
+		expectedNextHop lnwire.ShortChannelID
+		expectedRecord  bool
+	}{
+		{
+			name:            "amp",
+			nodes:           ourKeys(len(ampPayloads)),
+			payloads:        ampPayloads,
+			expectedNextHop: exitHop,
+			expectedRecord:  true,
+		},
+		{
+			name:            "last amp payload to other node",
+			nodes:           otherPath,
+			payloads:        ampPayloads,
+			expectedNextHop: FinalRecordsHop,
+		},
+		{
+			name:            "missing amp payload",
+			nodes:           ourKeys(len(ampPayloads) - 1),
+			payloads:        ampPayloads[:len(ampPayloads)-1],
+			expectedNextHop: FinalRecordsHop,
+		},
+	}
+
+	for _, test := range tests {
+		sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		onionPkt, err := sphinx.NewOnionPacket(
+			test.nodes, sessionKey, test.payloads, paymentHash[:],
+		)
+		if err != nil {
+			t.Fatalf("%v: unable to create onion: %v", test.name,
+				err)
+		}
+
+		iterator, failCode := processor.processOnionPacket(
+			onionPkt, paymentHash[:],
+		)
+		if failCode != lnwire.CodeNone {
+			t.Fatalf("%v: unable to process onion: %v", test.name,
+				failCode)
+		}
+
+		fwdInfo := iterator.ForwardingInstructions()
+		if fwdInfo.NextHop != test.expectedNextHop {
+			t.Fatalf("%v: expected next hop %v, got %v", test.name,
+				test.expectedNextHop, fwdInfo.NextHop)
+		}
+
+		switch {
+		case !test.expectedRecord && fwdInfo.Amp != nil:
+			t.Fatalf("%v: unexpected amp record", test.name)
+
+		case test.expectedRecord &&
+			!reflect.DeepEqual(fwdInfo.Amp, record):
+
+			t.Fatalf("%v: expected amp record %v, got %v",
+				test.name, record, fwdInfo.Amp)
+		}
+	}
+}
+
+// TestAmpRecordEncoding ensures that an AMP record survives being encoded as
+// the AMP and MPP records of the onion, and that an AMP record isn't accepted
+// without the MPP record carrying its total amount.
+func TestAmpRecordEncoding(t *testing.T) {
+	t.Parallel()
+
+	record := &AmpRecord{
+		SetID:      [32]byte{1},
+		Share:      [32]byte{2},
+		ChildIndex: 300,
+		TotalAmt:   5000,
+	}
+	records := &FinalHopRecords{Amp: record}
+
+	decoded, err := decodeFinalHopRecords(records.encode())
+	if err != nil {
+		t.Fatalf("unable to decode records: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Amp, record) {
+		t.Fatalf("expected amp record %v, got %v", record, decoded.Amp)
+	}
+
+	amp := append(append(record.Share[:], record.SetID[:]...), 1, 44)
+	stream := lnwire.EncodeTLVStream(map[uint64][]byte{
+		AmpRecordType: amp,
+	})
+	b := make([]byte, 2+len(stream))
+	binary.BigEndian.PutUint16(b, uint16(len(stream)))
+	copy(b[2:], stream)
+	if _, err := decodeFinalHopRecords(b); err == nil {
+		t.Fatalf("expected AMP record without MPP record to be " +
+			"rejected")
+	}
+}
+
+// TestAmpChildPreimages ensures that the root seed of an AMP payment is
+// reconstructed from the shares of its parts, regardless of their order, and
+// that each part derives a distinct preimage from it.
+func TestAmpChildPreimages(t *testing.T) {
+	t.Parallel()
+
+	var root [32]byte
+	if _, err := rand.Read(root[:]); err != nil {
+		t.Fatalf("unable to generate root seed: %v", err)
+	}
+
+	// The last share is chosen such that the XOR of all shares is the
+	// root seed.
+	shares := make([][32]byte, 3)
+	last := root
+	for i := range shares[:len(shares)-1] {
+		if _, err := rand.Read(shares[i][:]); err != nil {
+			t.Fatalf("unable to generate share: %v", err)
+		}
+		for j := range last {
+			last[j] ^= shares[i][j]
+		}
+	}
+	shares[len(shares)-1] = last
+
+	if AmpRootSeed(shares) != root {
+		t.Fatalf("root seed not reconstructed from shares")
+	}
+	reversed := [][32]byte{shares[2], shares[1], shares[0]}
+	if AmpRootSeed(reversed) != root {
+		t.Fatalf("root seed depends on order of shares")
+	}
+	if AmpRootSeed(shares[:2]) == root {
+		t.Fatalf("root seed reconstructed from partial shares")
+	}
+
+	preimages := make(map[[32]byte]struct{})
+	for i := uint32(0); i < uint32(len(shares)); i++ {
+		preimage := AmpChildPreimage(root, i)
+		if AmpChildPreimage(root, i) != preimage {
+			t.Fatalf("child preimage derivation not deterministic")
+		}
+		preimages[preimage] = struct{}{}
+	}
+	if len(preimages) != len(shares) {
+		t.Fatalf("expected %v distinct child preimages, got %v",
+			len(shares), len(preimages))
+	}
+}
+
+// TestChannelLinkAcceptAmp ensures that the parts of AMP payments are only
+// accepted if enabled, in which case a hold invoice is recorded for each, and
+// the part is registered with the registry.
+func TestChannelLinkAcceptAmp(t *testing.T) {
+	t.Parallel()
+
+	pd := &lnwallet.PaymentDescriptor{
+		RHash:  sha256.Sum256([]byte("amp")),
+		Amount: 5000,
+	}
+	record := &AmpRecord{
+		SetID:    [32]byte{1},
+		Share:    [32]byte{2},
+		TotalAmt: 10000,
+	}
+	invoiceHash := chainhash.Hash(pd.RHash)
+
+	for _, acceptAmp := range []bool{false, true} {
+		registry := newMockRegistry()
+		link := &channelLink{
+			cfg: ChannelLinkConfig{
+				Registry:  registry,
+				AcceptAmp: acceptAmp,
+			},
+		}
+
+		failure := link.acceptAmp(pd, record)
+		if (failure == nil) != acceptAmp {
+			t.Fatalf("acceptamp=%v: unexpected failure: %v",
+				acceptAmp, failure)
+		}
+
+		invoice, err := registry.LookupInvoice(invoiceHash)
+		if !acceptAmp {
+			if err == nil {
+				t.Fatalf("unexpected invoice added")
+			}
+			if len(registry.ampParts) != 0 {
+				t.Fatalf("unexpected amp part registered")
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("invoice not added: %v", err)
+		}
+		if !invoice.Terms.Hold || invoice.Terms.Value != pd.Amount ||
+			invoice.Terms.PaymentHash != pd.RHash {
+
+			t.Fatalf("unexpected invoice terms: %v", invoice.Terms)
+		}
+		if registry.ampParts[invoiceHash] != record {
+			t.Fatalf("amp part not registered")
+		}
+
+		// Accepting the part again, as would happen once it's
+		// replayed after a restart, should register it once more.
+		delete(registry.ampParts, invoiceHash)
+		if failure := link.acceptAmp(pd, record); failure != nil {
+			t.Fatalf("unable to accept replayed part: %v", failure)
+		}
+		if registry.ampParts[invoiceHash] != record {
+			t.Fatalf("replayed amp part not registered")
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package htlcswitch
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+const (
+	// DefaultBatchSize is the default number of updates the link batches
+	// before initiating a commitment update.
+	DefaultBatchSize uint32 = 10
+
+	// DefaultBatchTicker is the default interval at which the link
+	// commits any updates it has batched.
+	DefaultBatchTicker = 50 * time.Millisecond
+
+	// DefaultPendingCommitTicker is the default amount of time the link
+	// waits after receiving a commitment signature, before checking
+	// whether it owes the remote party one in return.
+	DefaultPendingCommitTicker = 300 * time.Millisecond
+
+	// maxBatchSize is the largest batch size the link accepts, as no more
+	// updates than this can be added by either party to a commitment.
+	maxBatchSize = lnwallet.MaxHTLCNumber / 2
+
+	// minLinkTicker is the shortest interval the link accepts for either
+	// of its commit tickers, which prevents it from busy looping.
+	minLinkTicker = 10 * time.Millisecond
+)
+
+// sanitizeBatchConfig replaces the batch size and the commit tickers of the
+// passed config with their defaults if they're unset, and clamps them to the
+// range the link accepts otherwise. The maximum pending commit ticker is
+// raised to the pending commit ticker if it's below it.
+func sanitizeBatchConfig(cfg *ChannelLinkConfig) {
+	switch {
+	case cfg.BatchSize == 0:
+		cfg.BatchSize = DefaultBatchSize
+
+	case cfg.BatchSize > maxBatchSize:
+		log.Warnf("Batch size of %v exceeds the maximum of %v, using "+
+			"the maximum instead", cfg.BatchSize, maxBatchSize)
+		cfg.BatchSize = maxBatchSize
+	}
+
+	sanitizeTicker := func(name string, ticker *time.Duration,
+		defaultTicker time.Duration) {
+
+		switch {
+		case *ticker == 0:
+			*ticker = defaultTicker
+
+		case *ticker < minLinkTicker:
+			log.Warnf("%v of %v is below the minimum of %v, using "+
+				"the minimum instead", name, *ticker,
+				minLinkTicker)
+			*ticker = minLinkTicker
+		}
+	}
+	sanitizeTicker("Batch ticker", &cfg.BatchTicker, DefaultBatchTicker)
+	sanitizeTicker(
+		"Pending commit ticker", &cfg.PendingCommitTicker,
+		DefaultPendingCommitTicker,
+	)
+	sanitizeTicker(
+		"Max pending commit ticker", &cfg.MaxPendingCommitTicker,
+		DefaultMaxPendingCommitTicker,
+	)
+
+	if cfg.MaxPendingCommitTicker < cfg.PendingCommitTicker {
+		log.Warnf("Max pending commit ticker of %v is below the "+
+			"pending commit ticker of %v, using the latter instead",
+			cfg.MaxPendingCommitTicker, cfg.PendingCommitTicker)
+		cfg.MaxPendingCommitTicker = cfg.PendingCommitTicker
+	}
+}
@@ -0,0 +1,84 @@
+package htlcswitch
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultChanSyncTimeout is the default amount of time the link will wait
+// for the remote party's ChannelReestablish message when synchronizing
+// channel states.
+const DefaultChanSyncTimeout = 30 * time.Second
+
+// ChanSyncErrorKind describes why the channel states couldn't be
+// synchronized with the remote party.
+type ChanSyncErrorKind uint8
+
+const (
+	// ChanSyncTimeout indicates that the remote party's
+	// ChannelReestablish message didn't arrive in time. This is typical
+	// of slow or flaky connections, such as those over Tor, rather than
+	// of a misbehaving peer.
+	ChanSyncTimeout ChanSyncErrorKind = iota
+
+	// ChanSyncProtocolError indicates that the remote party sent an
+	// unexpected message, or a ChannelReestablish message that we were
+	// unable to process.
+	ChanSyncProtocolError
+
+	// ChanSyncInternalError indicates that we were unable to generate or
+	// send our own messages.
+	ChanSyncInternalError
+)
+
+// String returns a human readable description of the ChanSyncErrorKind.
+func (k ChanSyncErrorKind) String() string {
+	switch k {
+	case ChanSyncTimeout:
+		return "timeout"
+	case ChanSyncProtocolError:
+		return "protocol error"
+	case ChanSyncInternalError:
+		return "internal error"
+	default:
+		return "unknown"
+	}
+}
+
+// ChanSyncError is returned when the channel states couldn't be synchronized
+// with the remote party.
+type ChanSyncError struct {
+	// Kind describes why the synchronization failed.
+	Kind ChanSyncErrorKind
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns a human readable description of the error.
+//
+// NOTE: Part of the error interface.
+func (e *ChanSyncError) Error() string {
+	return fmt.Sprintf("unable to synchronize channel states (%v): %v",
+		e.Kind, e.Err)
+}
+
+// newChanSyncError creates a new ChanSyncError of the passed kind.
+func newChanSyncError(kind ChanSyncErrorKind, format string,
+	a ...interface{}) *ChanSyncError {
+
+	return &ChanSyncError{
+		Kind: kind,
+		Err:  fmt.Errorf(format, a...),
+	}
+}
+
+// chanSyncTimeout returns the amount of time the link will wait for the
+// remote party's ChannelReestablish message.
+func (l *channelLink) chanSyncTimeout() time.Duration {
+	if l.cfg.ChanSyncTimeout == 0 {
+		return DefaultChanSyncTimeout
+	}
+
+	return l.cfg.ChanSyncTimeout
+}
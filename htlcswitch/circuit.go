@@ -1,17 +1,38 @@
 package htlcswitch
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"io"
 	"sync"
 
+	"github.com/boltdb/bolt"
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
+var (
+	// circuitMapKey is the name of the top-level bucket within the
+	// channel database which houses the circuits of forwarded HTLCs. Each
+	// circuit is keyed by the short channel ID and HTLC ID of its incoming
+	// HTLC.
+	circuitMapKey = []byte("circuit-map")
+
+	// ErrDuplicateCircuit is returned when attempting to open a circuit
+	// for an incoming HTLC which already has an open circuit.
+	ErrDuplicateCircuit = errors.New("circuit already open for incoming htlc")
+
+	// ErrCircuitDecoding is returned when a persisted circuit holds an
+	// unknown type of error encrypter.
+	ErrCircuitDecoding = errors.New("unable to decode circuit")
+)
+
 // PaymentCircuit is used by the HTLC switch subsystem to determine the
 // backwards path for the settle/fail HTLC messages. A payment circuit
-// will be created once a channel link forwards the HTLC add request and
-// removed when we receive a settle/fail HTLC message.
+// will be opened once a channel link forwards the HTLC add request and
+// closed when we receive a settle/fail HTLC message.
 type PaymentCircuit struct {
 	// PaymentHash used as unique identifier of payment.
 	PaymentHash [32]byte
@@ -36,9 +57,134 @@ type PaymentCircuit struct {
 	// outgoing channel.
 	OutgoingHTLCID uint64
 
+	// IncomingAmount is the value of the incoming HTLC. It's zero for
+	// locally initiated payments.
+	IncomingAmount lnwire.MilliSatoshi
+
+	// OutgoingAmount is the value of the outgoing HTLC.
+	OutgoingAmount lnwire.MilliSatoshi
+
 	// ErrorEncrypter is used to re-encrypt the onion failure before
 	// sending it back to the originator of the payment.
 	ErrorEncrypter ErrorEncrypter
+
+	// TraceID is the trace ID of the HTLC, which is carried over to the
+	// settle or fail that resolves it.
+	TraceID TraceID
+}
+
+// isForwarded returns true if the circuit's incoming HTLC was received from
+// another channel, rather than initiated locally.
+func (c *PaymentCircuit) isForwarded() bool {
+	return c.IncomingChanID != (lnwire.ShortChannelID{})
+}
+
+// incomingKey returns the key of the circuit's incoming HTLC.
+func (c *PaymentCircuit) incomingKey() circuitKey {
+	return circuitKey{
+		chanID: c.IncomingChanID,
+		htlcID: c.IncomingHTLCID,
+	}
+}
+
+// outgoingKey returns the key of the circuit's outgoing HTLC.
+func (c *PaymentCircuit) outgoingKey() circuitKey {
+	return circuitKey{
+		chanID: c.OutgoingChanID,
+		htlcID: c.OutgoingHTLCID,
+	}
+}
+
+// Encode writes the circuit to the passed io.Writer.
+func (c *PaymentCircuit) Encode(w io.Writer) error {
+	if _, err := w.Write(c.PaymentHash[:]); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	for _, v := range []uint64{
+		c.IncomingChanID.ToUint64(), c.IncomingHTLCID,
+		c.OutgoingChanID.ToUint64(), c.OutgoingHTLCID,
+		uint64(c.IncomingAmount), uint64(c.OutgoingAmount),
+	} {
+		binary.BigEndian.PutUint64(scratch[:], v)
+		if _, err := w.Write(scratch[:]); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.Write(c.TraceID[:]); err != nil {
+		return err
+	}
+
+	// Finally, we'll write the type of the error encrypter, followed by
+	// its own encoding, so that it can be restored.
+	encrypterType := EncrypterTypeNone
+	if c.ErrorEncrypter != nil {
+		encrypterType = c.ErrorEncrypter.Type()
+	}
+	if _, err := w.Write([]byte{byte(encrypterType)}); err != nil {
+		return err
+	}
+	if c.ErrorEncrypter == nil {
+		return nil
+	}
+
+	return c.ErrorEncrypter.Encode(w)
+}
+
+// Decode reads a circuit from the passed io.Reader. The error encrypter of
+// the circuit is decoded, but its secrets must still be restored via
+// Reextract before it's used.
+func (c *PaymentCircuit) Decode(r io.Reader) error {
+	if _, err := io.ReadFull(r, c.PaymentHash[:]); err != nil {
+		return err
+	}
+
+	var scratch [48]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return err
+	}
+	c.IncomingChanID = lnwire.NewShortChanIDFromInt(
+		binary.BigEndian.Uint64(scratch[:8]),
+	)
+	c.IncomingHTLCID = binary.BigEndian.Uint64(scratch[8:16])
+	c.OutgoingChanID = lnwire.NewShortChanIDFromInt(
+		binary.BigEndian.Uint64(scratch[16:24]),
+	)
+	c.OutgoingHTLCID = binary.BigEndian.Uint64(scratch[24:32])
+	c.IncomingAmount = lnwire.MilliSatoshi(
+		binary.BigEndian.Uint64(scratch[32:40]),
+	)
+	c.OutgoingAmount = lnwire.MilliSatoshi(
+		binary.BigEndian.Uint64(scratch[40:]),
+	)
+
+	if _, err := io.ReadFull(r, c.TraceID[:]); err != nil {
+		return err
+	}
+
+	var encrypterType [1]byte
+	if _, err := io.ReadFull(r, encrypterType[:]); err != nil {
+		return err
+	}
+
+	switch EncrypterType(encrypterType[0]) {
+	case EncrypterTypeNone:
+		c.ErrorEncrypter = nil
+		return nil
+
+	case EncrypterTypeSphinx:
+		c.ErrorEncrypter = &SphinxErrorEncrypter{}
+
+	case EncrypterTypeMock:
+		c.ErrorEncrypter = newMockObfuscator()
+
+	default:
+		return ErrCircuitDecoding
+	}
+
+	return c.ErrorEncrypter.Decode(r)
 }
 
 // circuitKey is a channel ID, HTLC ID tuple used as an identifying key for a
@@ -54,27 +200,112 @@ func (k *circuitKey) String() string {
 	return fmt.Sprintf("(Chan ID=%s, HTLC ID=%d)", k.chanID, k.htlcID)
 }
 
+// bytes returns the on-disk encoding of the circuitKey.
+func (k *circuitKey) bytes() []byte {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], k.chanID.ToUint64())
+	binary.BigEndian.PutUint64(b[8:], k.htlcID)
+	return b[:]
+}
+
+// CircuitMapConfig houses the dependencies of the CircuitMap.
+type CircuitMapConfig struct {
+	// DB is the channel database within which the circuits of forwarded
+	// HTLCs are persisted. If nil, circuits are only held in memory.
+	DB *channeldb.DB
+
+	// ExtractErrorEncrypter restores the error encrypters of the circuits
+	// read from disk.
+	ExtractErrorEncrypter ErrorEncrypterExtracter
+}
+
 // CircuitMap is a data structure that implements thread safe storage of
 // circuit routing information. The switch consults a circuit map to determine
 // where to forward HTLC update messages. Each circuit is stored with it's
 // outgoing HTLC as the primary key because, each offered HTLC has at most one
 // received HTLC, but there may be multiple offered or received HTLCs with the
 // same payment hash. Circuits are also indexed to provide fast lookups by
-// payment hash.
+// their incoming HTLC, and by payment hash.
 //
-// TODO(andrew.shvv) make it persistent
+// The circuits of forwarded HTLCs are persisted within the channel database,
+// keyed by their incoming HTLC, so that a settle or fail received after a
+// restart can still be propagated back to the incoming channel. The circuits
+// of locally initiated payments are only held in memory, as the payments
+// they'd be delivered to don't survive a restart.
 type CircuitMap struct {
-	mtx       sync.RWMutex
-	circuits  map[circuitKey]*PaymentCircuit
-	hashIndex map[[32]byte]map[PaymentCircuit]struct{}
+	cfg *CircuitMapConfig
+
+	mtx           sync.RWMutex
+	circuits      map[circuitKey]*PaymentCircuit
+	incomingIndex map[circuitKey]*PaymentCircuit
+	hashIndex     map[[32]byte]map[PaymentCircuit]struct{}
 }
 
-// NewCircuitMap creates a new instance of the CircuitMap.
-func NewCircuitMap() *CircuitMap {
+// NewCircuitMap creates a new instance of the CircuitMap. Any circuits
+// persisted by a prior instance aren't available until Restore is called.
+func NewCircuitMap(cfg *CircuitMapConfig) *CircuitMap {
 	return &CircuitMap{
-		circuits:  make(map[circuitKey]*PaymentCircuit),
-		hashIndex: make(map[[32]byte]map[PaymentCircuit]struct{}),
+		cfg:           cfg,
+		circuits:      make(map[circuitKey]*PaymentCircuit),
+		incomingIndex: make(map[circuitKey]*PaymentCircuit),
+		hashIndex:     make(map[[32]byte]map[PaymentCircuit]struct{}),
+	}
+}
+
+// Restore reads the circuits persisted within the channel database into
+// memory, restoring their error encrypters. This should be called once, before
+// any circuits are opened.
+func (cm *CircuitMap) Restore() error {
+	if cm.cfg.DB == nil {
+		return nil
+	}
+
+	var circuits []*PaymentCircuit
+	err := cm.cfg.DB.View(func(tx *bolt.Tx) error {
+		circuitBkt := tx.Bucket(circuitMapKey)
+		if circuitBkt == nil {
+			return nil
+		}
+
+		return circuitBkt.ForEach(func(k, v []byte) error {
+			circuit := &PaymentCircuit{}
+			if err := circuit.Decode(bytes.NewReader(v)); err != nil {
+				return err
+			}
+
+			circuits = append(circuits, circuit)
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, circuit := range circuits {
+		if circuit.ErrorEncrypter != nil {
+			if cm.cfg.ExtractErrorEncrypter == nil {
+				return fmt.Errorf("unable to restore circuit " +
+					"without an error encrypter extracter")
+			}
+
+			err := circuit.ErrorEncrypter.Reextract(
+				cm.cfg.ExtractErrorEncrypter,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	cm.mtx.Lock()
+	for _, circuit := range circuits {
+		cm.index(circuit)
 	}
+	cm.mtx.Unlock()
+
+	log.Infof("Restored %d payment circuits", len(circuits))
+
+	return nil
 }
 
 // LookupByHTLC looks up the payment circuit by the outgoing channel and HTLC
@@ -92,6 +323,23 @@ func (cm *CircuitMap) LookupByHTLC(chanID lnwire.ShortChannelID, htlcID uint64)
 	return circuit
 }
 
+// LookupByIncoming looks up the payment circuit by the incoming channel and
+// HTLC IDs. Returns nil if there is no such circuit.
+func (cm *CircuitMap) LookupByIncoming(chanID lnwire.ShortChannelID,
+	htlcID uint64) *PaymentCircuit {
+
+	cm.mtx.RLock()
+
+	key := circuitKey{
+		chanID: chanID,
+		htlcID: htlcID,
+	}
+	circuit := cm.incomingIndex[key]
+
+	cm.mtx.RUnlock()
+	return circuit
+}
+
 // LookupByPaymentHash looks up and returns any payment circuits with a given
 // payment hash.
 func (cm *CircuitMap) LookupByPaymentHash(hash [32]byte) []*PaymentCircuit {
@@ -101,6 +349,7 @@ func (cm *CircuitMap) LookupByPaymentHash(hash [32]byte) []*PaymentCircuit {
 	if circuitSet, ok := cm.hashIndex[hash]; ok {
 		circuits = make([]*PaymentCircuit, 0, len(circuitSet))
 		for circuit := range circuitSet {
+			circuit := circuit
 			circuits = append(circuits, &circuit)
 		}
 	}
@@ -109,32 +358,58 @@ func (cm *CircuitMap) LookupByPaymentHash(hash [32]byte) []*PaymentCircuit {
 	return circuits
 }
 
-// Add adds a new active payment circuit to the CircuitMap.
-func (cm *CircuitMap) Add(circuit *PaymentCircuit) error {
-	cm.mtx.Lock()
+// Circuits returns a copy of each of the active payment circuits.
+func (cm *CircuitMap) Circuits() []*PaymentCircuit {
+	cm.mtx.RLock()
 
-	key := circuitKey{
-		chanID: circuit.OutgoingChanID,
-		htlcID: circuit.OutgoingHTLCID,
+	circuits := make([]*PaymentCircuit, 0, len(cm.circuits))
+	for _, circuit := range cm.circuits {
+		c := *circuit
+		circuits = append(circuits, &c)
 	}
-	cm.circuits[key] = circuit
 
-	// Add circuit to the hash index.
-	if _, ok := cm.hashIndex[circuit.PaymentHash]; !ok {
-		cm.hashIndex[circuit.PaymentHash] = make(map[PaymentCircuit]struct{})
+	cm.mtx.RUnlock()
+	return circuits
+}
+
+// Open adds a new active payment circuit to the CircuitMap, once its outgoing
+// HTLC has been added to the outgoing channel. The circuit of a forwarded
+// HTLC is persisted before it's added. If a circuit is already open for the
+// same incoming HTLC, then ErrDuplicateCircuit is returned.
+func (cm *CircuitMap) Open(circuit *PaymentCircuit) error {
+	cm.mtx.Lock()
+	defer cm.mtx.Unlock()
+
+	if circuit.isForwarded() {
+		if _, ok := cm.incomingIndex[circuit.incomingKey()]; ok {
+			return ErrDuplicateCircuit
+		}
+
+		err := cm.persist(func(circuitBkt *bolt.Bucket) error {
+			var b bytes.Buffer
+			if err := circuit.Encode(&b); err != nil {
+				return err
+			}
+
+			key := circuit.incomingKey()
+			return circuitBkt.Put(key.bytes(), b.Bytes())
+		})
+		if err != nil {
+			return err
+		}
 	}
-	cm.hashIndex[circuit.PaymentHash][*circuit] = struct{}{}
 
-	cm.mtx.Unlock()
+	cm.index(circuit)
+
 	return nil
 }
 
-// Remove destroys the target circuit by removing it from the circuit map.
-func (cm *CircuitMap) Remove(chanID lnwire.ShortChannelID, htlcID uint64) error {
+// Close destroys the target circuit by removing it from the circuit map, and
+// from disk.
+func (cm *CircuitMap) Close(chanID lnwire.ShortChannelID, htlcID uint64) error {
 	cm.mtx.Lock()
 	defer cm.mtx.Unlock()
 
-	// Look up circuit so that pointer can be matched in the hash index.
 	key := circuitKey{
 		chanID: chanID,
 		htlcID: htlcID,
@@ -143,7 +418,76 @@ func (cm *CircuitMap) Remove(chanID lnwire.ShortChannelID, htlcID uint64) error
 	if !found {
 		return errors.Errorf("Can't find circuit for HTLC %v", key)
 	}
+
+	if err := cm.unpersist([]*PaymentCircuit{circuit}); err != nil {
+		return err
+	}
+
+	return cm.unindex(circuit)
+}
+
+// TrimOpenCircuits removes the circuits whose outgoing HTLC was offered over
+// the passed channel with an HTLC ID of at least start. Upon restoring a
+// channel, any HTLCs which were added, but not yet signed for, are forgotten,
+// and their HTLC IDs are re-used. Their circuits are thus stale, and must be
+// trimmed before the channel offers any new HTLCs. The trimmed circuits are
+// returned.
+func (cm *CircuitMap) TrimOpenCircuits(chanID lnwire.ShortChannelID,
+	start uint64) ([]*PaymentCircuit, error) {
+
+	cm.mtx.Lock()
+	defer cm.mtx.Unlock()
+
+	var trimmed []*PaymentCircuit
+	for key, circuit := range cm.circuits {
+		if key.chanID != chanID || key.htlcID < start {
+			continue
+		}
+
+		trimmed = append(trimmed, circuit)
+	}
+
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	if err := cm.unpersist(trimmed); err != nil {
+		return nil, err
+	}
+	for _, circuit := range trimmed {
+		if err := cm.unindex(circuit); err != nil {
+			return nil, err
+		}
+	}
+
+	return trimmed, nil
+}
+
+// index adds the circuit to each of the in-memory indexes.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (cm *CircuitMap) index(circuit *PaymentCircuit) {
+	cm.circuits[circuit.outgoingKey()] = circuit
+	if circuit.isForwarded() {
+		cm.incomingIndex[circuit.incomingKey()] = circuit
+	}
+
+	// Add circuit to the hash index.
+	if _, ok := cm.hashIndex[circuit.PaymentHash]; !ok {
+		cm.hashIndex[circuit.PaymentHash] = make(map[PaymentCircuit]struct{})
+	}
+	cm.hashIndex[circuit.PaymentHash][*circuit] = struct{}{}
+}
+
+// unindex removes the circuit from each of the in-memory indexes.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (cm *CircuitMap) unindex(circuit *PaymentCircuit) error {
+	key := circuit.outgoingKey()
 	delete(cm.circuits, key)
+	if circuit.isForwarded() {
+		delete(cm.incomingIndex, circuit.incomingKey())
+	}
 
 	// Remove circuit from hash index.
 	circuitsWithHash, ok := cm.hashIndex[circuit.PaymentHash]
@@ -164,6 +508,42 @@ func (cm *CircuitMap) Remove(chanID lnwire.ShortChannelID, htlcID uint64) error
 	return nil
 }
 
+// unpersist removes the passed circuits from disk. Circuits which were never
+// persisted are ignored.
+func (cm *CircuitMap) unpersist(circuits []*PaymentCircuit) error {
+	return cm.persist(func(circuitBkt *bolt.Bucket) error {
+		for _, circuit := range circuits {
+			if !circuit.isForwarded() {
+				continue
+			}
+
+			key := circuit.incomingKey()
+			if err := circuitBkt.Delete(key.bytes()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// persist applies the passed modification to the bucket of circuits within
+// the channel database, if the circuit map is backed by one.
+func (cm *CircuitMap) persist(f func(*bolt.Bucket) error) error {
+	if cm.cfg.DB == nil {
+		return nil
+	}
+
+	return cm.cfg.DB.Update(func(tx *bolt.Tx) error {
+		circuitBkt, err := tx.CreateBucketIfNotExists(circuitMapKey)
+		if err != nil {
+			return err
+		}
+
+		return f(circuitBkt)
+	})
+}
+
 // pending returns number of circuits which are waiting for to be completed
 // (settle/fail responses to be received).
 func (cm *CircuitMap) pending() int {
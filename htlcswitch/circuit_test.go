@@ -1,8 +1,13 @@
 package htlcswitch_test
 
 import (
+	"io/ioutil"
+	"os"
+	"reflect"
 	"testing"
 
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
@@ -20,7 +25,7 @@ func TestCircuitMap(t *testing.T) {
 		chan2 = lnwire.NewShortChanIDFromInt(2)
 	)
 
-	circuitMap := htlcswitch.NewCircuitMap()
+	circuitMap := htlcswitch.NewCircuitMap(&htlcswitch.CircuitMapConfig{})
 
 	circuit := circuitMap.LookupByHTLC(chan1, 0)
 	if circuit != nil {
@@ -30,7 +35,7 @@ func TestCircuitMap(t *testing.T) {
 
 	// Add multiple circuits with same destination channel but different HTLC
 	// IDs and payment hashes.
-	circuitMap.Add(&htlcswitch.PaymentCircuit{
+	circuitMap.Open(&htlcswitch.PaymentCircuit{
 		PaymentHash:    hash1,
 		IncomingChanID: chan2,
 		IncomingHTLCID: 1,
@@ -38,7 +43,7 @@ func TestCircuitMap(t *testing.T) {
 		OutgoingHTLCID: 0,
 	})
 
-	circuitMap.Add(&htlcswitch.PaymentCircuit{
+	circuitMap.Open(&htlcswitch.PaymentCircuit{
 		PaymentHash:    hash2,
 		IncomingChanID: chan2,
 		IncomingHTLCID: 2,
@@ -48,7 +53,7 @@ func TestCircuitMap(t *testing.T) {
 
 	// Add another circuit with an already-used HTLC ID but different
 	// destination channel.
-	circuitMap.Add(&htlcswitch.PaymentCircuit{
+	circuitMap.Open(&htlcswitch.PaymentCircuit{
 		PaymentHash:    hash3,
 		IncomingChanID: chan1,
 		IncomingHTLCID: 2,
@@ -90,7 +95,7 @@ func TestCircuitMap(t *testing.T) {
 
 	// Add a circuit with a destination channel and payment hash that are
 	// already added but a different HTLC ID.
-	circuitMap.Add(&htlcswitch.PaymentCircuit{
+	circuitMap.Open(&htlcswitch.PaymentCircuit{
 		PaymentHash:    hash1,
 		IncomingChanID: chan2,
 		IncomingHTLCID: 3,
@@ -112,6 +117,10 @@ func TestCircuitMap(t *testing.T) {
 		t.Fatalf("LookupByPaymentHash returned wrong number of circuits for "+
 			"hash1: expected %d, got %d", 2, len(circuits))
 	}
+	if circuits[0].OutgoingHTLCID == circuits[1].OutgoingHTLCID {
+		t.Fatalf("LookupByPaymentHash returned the same circuit twice "+
+			"for hash1: %v", circuits[0])
+	}
 
 	circuits = circuitMap.LookupByPaymentHash(hash2)
 	if len(circuits) != 1 {
@@ -119,10 +128,10 @@ func TestCircuitMap(t *testing.T) {
 			"hash2: expected %d, got %d", 1, len(circuits))
 	}
 
-	// Test removing circuits and the subsequent lookups.
-	err := circuitMap.Remove(chan1, 0)
+	// Test closing circuits and the subsequent lookups.
+	err := circuitMap.Close(chan1, 0)
 	if err != nil {
-		t.Fatalf("Remove returned unexpected error: %v", err)
+		t.Fatalf("Close returned unexpected error: %v", err)
 	}
 
 	circuits = circuitMap.LookupByPaymentHash(hash1)
@@ -135,16 +144,16 @@ func TestCircuitMap(t *testing.T) {
 			circuits[0])
 	}
 
-	// Removing already-removed circuit should return an error.
-	err = circuitMap.Remove(chan1, 0)
+	// Closing an already-closed circuit should return an error.
+	err = circuitMap.Close(chan1, 0)
 	if err == nil {
-		t.Fatal("Remove did not return expected not found error")
+		t.Fatal("Close did not return expected not found error")
 	}
 
-	// Remove last remaining circuit with payment hash hash1.
-	err = circuitMap.Remove(chan1, 3)
+	// Close last remaining circuit with payment hash hash1.
+	err = circuitMap.Close(chan1, 3)
 	if err != nil {
-		t.Fatalf("Remove returned unexpected error: %v", err)
+		t.Fatalf("Close returned unexpected error: %v", err)
 	}
 
 	circuits = circuitMap.LookupByPaymentHash(hash1)
@@ -153,3 +162,125 @@ func TestCircuitMap(t *testing.T) {
 			"hash1: expecected %d, got %d", 0, len(circuits))
 	}
 }
+
+// TestCircuitMapPersistence tests that the circuits of forwarded HTLCs are
+// restored from disk, unlike those of locally initiated payments, that closed
+// and trimmed circuits aren't, and that a second circuit can't be opened for
+// the same incoming HTLC.
+func TestCircuitMapPersistence(t *testing.T) {
+	t.Parallel()
+
+	tempDir, err := ioutil.TempDir("", "circuitdb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	db, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+	defer db.Close()
+
+	var (
+		hash1, hash2, hash3, hash4 [32]byte
+
+		chan1 = lnwire.NewShortChanIDFromInt(1)
+		chan2 = lnwire.NewShortChanIDFromInt(2)
+	)
+	hash1[0] = 1
+	hash2[0] = 2
+	hash3[0] = 3
+	hash4[0] = 4
+
+	cfg := &htlcswitch.CircuitMapConfig{DB: db}
+	circuitMap := htlcswitch.NewCircuitMap(cfg)
+	if err := circuitMap.Restore(); err != nil {
+		t.Fatalf("unable to restore empty circuit map: %v", err)
+	}
+
+	circuits := []*htlcswitch.PaymentCircuit{
+		{
+			PaymentHash:    hash1,
+			IncomingChanID: chan2,
+			IncomingHTLCID: 1,
+			OutgoingChanID: chan1,
+			OutgoingHTLCID: 0,
+		},
+		{
+			PaymentHash:    hash2,
+			IncomingChanID: chan2,
+			IncomingHTLCID: 2,
+			OutgoingChanID: chan1,
+			OutgoingHTLCID: 1,
+		},
+		{
+			PaymentHash:    hash3,
+			IncomingChanID: chan2,
+			IncomingHTLCID: 3,
+			OutgoingChanID: chan1,
+			OutgoingHTLCID: 2,
+		},
+		{
+			// A locally initiated payment.
+			PaymentHash:    hash4,
+			IncomingHTLCID: 0,
+			OutgoingChanID: chan1,
+			OutgoingHTLCID: 3,
+		},
+	}
+	for _, circuit := range circuits {
+		if err := circuitMap.Open(circuit); err != nil {
+			t.Fatalf("unable to open circuit: %v", err)
+		}
+	}
+
+	// A second circuit for an incoming HTLC that's already been forwarded
+	// should be rejected.
+	err = circuitMap.Open(&htlcswitch.PaymentCircuit{
+		PaymentHash:    hash1,
+		IncomingChanID: chan2,
+		IncomingHTLCID: 1,
+		OutgoingChanID: chan1,
+		OutgoingHTLCID: 4,
+	})
+	if err != htlcswitch.ErrDuplicateCircuit {
+		t.Fatalf("expected ErrDuplicateCircuit, got %v", err)
+	}
+
+	// Close the first circuit, and trim the third.
+	if err := circuitMap.Close(chan1, 0); err != nil {
+		t.Fatalf("unable to close circuit: %v", err)
+	}
+	trimmed, err := circuitMap.TrimOpenCircuits(chan1, 2)
+	if err != nil {
+		t.Fatalf("unable to trim circuits: %v", err)
+	}
+	if len(trimmed) != 2 {
+		t.Fatalf("expected 2 trimmed circuits, got %v", len(trimmed))
+	}
+
+	// Only the second circuit should be restored by a new circuit map.
+	circuitMap = htlcswitch.NewCircuitMap(cfg)
+	if err := circuitMap.Restore(); err != nil {
+		t.Fatalf("unable to restore circuit map: %v", err)
+	}
+
+	restored := circuitMap.Circuits()
+	if len(restored) != 1 {
+		t.Fatalf("expected 1 restored circuit, got %v", len(restored))
+	}
+	if !reflect.DeepEqual(restored[0], circuits[1]) {
+		t.Fatalf("restored circuit doesn't match: expected %v, got %v",
+			spew.Sdump(circuits[1]), spew.Sdump(restored[0]))
+	}
+
+	circuit := circuitMap.LookupByIncoming(chan2, 2)
+	if circuit == nil || circuit.OutgoingHTLCID != 1 {
+		t.Fatalf("LookupByIncoming found unexpected circuit: %v",
+			circuit)
+	}
+	if circuit := circuitMap.LookupByIncoming(chan2, 1); circuit != nil {
+		t.Fatalf("LookupByIncoming found closed circuit: %v", circuit)
+	}
+}
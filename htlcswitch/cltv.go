@@ -0,0 +1,36 @@
+package htlcswitch
+
+const (
+	// DefaultFinalCltvGrace is the default grace period, expressed in
+	// blocks, that the timeout of incoming HTLCs which pay directly to us
+	// (i.e we're the "exit node") must uphold. We'll reject any such HTLC
+	// whose timeout is within this many blocks of the current height, as
+	// we must be able to claim it on-chain should the extending party go
+	// to the chain.
+	DefaultFinalCltvGrace uint32 = 2
+)
+
+// sanitizeCltvConfig replaces the final CLTV grace period of the passed config
+// with its default if it's unset.
+func sanitizeCltvConfig(cfg *ChannelLinkConfig) {
+	if cfg.FinalCltvGrace == 0 {
+		cfg.FinalCltvGrace = DefaultFinalCltvGrace
+	}
+}
+
+// effectiveTimeLockDelta returns the time lock delta that's enforced for
+// HTLCs forwarded under the passed policy. This is the delta of the policy,
+// unless it falls below the passed floor.
+func effectiveTimeLockDelta(policy ForwardingPolicy, minDelta uint32) uint32 {
+	if policy.TimeLockDelta < minDelta {
+		return minDelta
+	}
+
+	return policy.TimeLockDelta
+}
+
+// expiryTooSoon returns true if an HTLC with the passed timeout leaves no more
+// than the passed delta of blocks to spare at the given height.
+func expiryTooSoon(timeout, delta, height uint32) bool {
+	return timeout <= height+delta
+}
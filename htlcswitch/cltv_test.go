@@ -0,0 +1,94 @@
+package htlcswitch
+
+import "testing"
+
+// TestEffectiveTimeLockDelta tests that the time lock delta of a forwarding
+// policy is enforced unless it falls below the floor of the link.
+func TestEffectiveTimeLockDelta(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		delta    uint32
+		minDelta uint32
+		expected uint32
+	}{
+		{
+			name:     "no floor",
+			delta:    6,
+			expected: 6,
+		},
+		{
+			name:     "delta above floor",
+			delta:    144,
+			minDelta: 10,
+			expected: 144,
+		},
+		{
+			name:     "delta below floor",
+			delta:    6,
+			minDelta: 10,
+			expected: 10,
+		},
+	}
+
+	for _, test := range tests {
+		policy := ForwardingPolicy{TimeLockDelta: test.delta}
+		delta := effectiveTimeLockDelta(policy, test.minDelta)
+		if delta != test.expected {
+			t.Fatalf("%v: expected delta of %v, got %v", test.name,
+				test.expected, delta)
+		}
+	}
+}
+
+// TestExpiryTooSoon tests that an HTLC is considered to expire too soon once
+// it leaves no more than the required delta of blocks to spare, including
+// when its timeout is lower than the delta itself.
+func TestExpiryTooSoon(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		timeout uint32
+		delta   uint32
+		height  uint32
+		tooSoon bool
+	}{
+		{
+			name:    "enough blocks to spare",
+			timeout: 113,
+			delta:   10,
+			height:  102,
+		},
+		{
+			name:    "exactly delta blocks to spare",
+			timeout: 112,
+			delta:   10,
+			height:  102,
+			tooSoon: true,
+		},
+		{
+			name:    "already expired",
+			timeout: 100,
+			delta:   2,
+			height:  102,
+			tooSoon: true,
+		},
+		{
+			name:    "timeout below delta",
+			timeout: 5,
+			delta:   10,
+			height:  2,
+			tooSoon: true,
+		},
+	}
+
+	for _, test := range tests {
+		tooSoon := expiryTooSoon(test.timeout, test.delta, test.height)
+		if tooSoon != test.tooSoon {
+			t.Fatalf("%v: expected too soon=%v, got %v", test.name,
+				test.tooSoon, tooSoon)
+		}
+	}
+}
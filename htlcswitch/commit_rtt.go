@@ -0,0 +1,82 @@
+package htlcswitch
+
+import "time"
+
+const (
+	// DefaultMaxPendingCommitTicker is the default upper bound of the
+	// amount of time the link waits after receiving a commitment
+	// signature, before checking whether it owes the remote party one in
+	// return.
+	DefaultMaxPendingCommitTicker = 5 * time.Second
+
+	// pendingCommitRTTMultiple is the multiple of the estimated round trip
+	// time to the remote party the link waits after receiving a commitment
+	// signature. It ensures the remote party has ample time to follow up
+	// with a signature of its own, before we send a redundant one.
+	pendingCommitRTTMultiple = 4
+
+	// rttSmoothing is the inverse of the weight given to each new round
+	// trip time sample within the smoothed estimate.
+	rttSmoothing = 8
+)
+
+// rttEstimator estimates the round trip time to the remote party of a link,
+// by timing how long it takes the remote party to revoke its prior commitment
+// once we've sent it a new one. Much like TCP's estimate, samples are
+// combined into an exponentially weighted moving average.
+type rttEstimator struct {
+	// srtt is the smoothed round trip time. It's zero until the first
+	// sample is taken.
+	srtt time.Duration
+
+	// pendingCommits holds the times at which the commitments we've sent,
+	// which are yet to be revoked, were sent. As revocations arrive in
+	// the order the commitments were sent, each one is matched with the
+	// oldest.
+	pendingCommits []time.Time
+}
+
+// commitSent records that a new commitment was sent to the remote party at
+// the passed time.
+func (e *rttEstimator) commitSent(now time.Time) {
+	e.pendingCommits = append(e.pendingCommits, now)
+}
+
+// revocationReceived samples the round trip time of the oldest commitment
+// sent to the remote party, which the revocation received at the passed time
+// concludes. Revocations for commitments sent before the estimator was
+// created, such as those re-sent upon reconnection, aren't sampled.
+func (e *rttEstimator) revocationReceived(now time.Time) {
+	if len(e.pendingCommits) == 0 {
+		return
+	}
+
+	sample := now.Sub(e.pendingCommits[0])
+	e.pendingCommits = e.pendingCommits[1:]
+
+	if e.srtt == 0 {
+		e.srtt = sample
+		return
+	}
+	e.srtt += (sample - e.srtt) / rttSmoothing
+}
+
+// pendingCommitTimeout returns the amount of time to wait after receiving a
+// commitment signature, before checking whether we owe the remote party one
+// in return. It's a multiple of the estimated round trip time, bounded by the
+// passed minimum and maximum, and the minimum until a sample has been taken.
+func (e *rttEstimator) pendingCommitTimeout(minTimeout,
+	maxTimeout time.Duration) time.Duration {
+
+	timeout := e.srtt * pendingCommitRTTMultiple
+	switch {
+	case timeout < minTimeout:
+		return minTimeout
+
+	case timeout > maxTimeout:
+		return maxTimeout
+
+	default:
+		return timeout
+	}
+}
@@ -0,0 +1,69 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRTTEstimator tests that the round trip time is estimated from the time
+// between sending a commitment and receiving its revocation, and that the
+// resulting pending commit timeout is bounded.
+func TestRTTEstimator(t *testing.T) {
+	t.Parallel()
+
+	const (
+		minTimeout = 300 * time.Millisecond
+		maxTimeout = 5 * time.Second
+	)
+
+	var e rttEstimator
+	now := time.Now()
+
+	// Until a sample has been taken, the minimum timeout should be used.
+	// A revocation for a commitment we haven't timed isn't sampled.
+	e.revocationReceived(now)
+	timeout := e.pendingCommitTimeout(minTimeout, maxTimeout)
+	if timeout != minTimeout {
+		t.Fatalf("expected timeout of %v, got %v", minTimeout, timeout)
+	}
+
+	// The first sample should be taken as the estimate. As it's short,
+	// the minimum timeout should still be used.
+	e.commitSent(now)
+	e.revocationReceived(now.Add(40 * time.Millisecond))
+	if e.srtt != 40*time.Millisecond {
+		t.Fatalf("expected rtt of 40ms, got %v", e.srtt)
+	}
+	timeout = e.pendingCommitTimeout(minTimeout, maxTimeout)
+	if timeout != minTimeout {
+		t.Fatalf("expected timeout of %v, got %v", minTimeout, timeout)
+	}
+
+	// Revocations should be matched with commitments in the order they
+	// were sent, and their samples smoothed into the estimate.
+	e.commitSent(now)
+	e.commitSent(now.Add(800 * time.Millisecond))
+	e.revocationReceived(now.Add(840 * time.Millisecond))
+	e.revocationReceived(now.Add(1640 * time.Millisecond))
+
+	// The estimate is first moved by an eighth of the way to 840ms, then
+	// by an eighth of the way to 840ms again.
+	expected := 40*time.Millisecond + 100*time.Millisecond
+	expected += (840*time.Millisecond - expected) / rttSmoothing
+	if e.srtt != expected {
+		t.Fatalf("expected rtt of %v, got %v", expected, e.srtt)
+	}
+	timeout = e.pendingCommitTimeout(minTimeout, maxTimeout)
+	if timeout != expected*pendingCommitRTTMultiple {
+		t.Fatalf("expected timeout of %v, got %v",
+			expected*pendingCommitRTTMultiple, timeout)
+	}
+
+	// A slow enough peer should be capped at the maximum timeout.
+	e.commitSent(now)
+	e.revocationReceived(now.Add(time.Minute))
+	timeout = e.pendingCommitTimeout(minTimeout, maxTimeout)
+	if timeout != maxTimeout {
+		t.Fatalf("expected timeout of %v, got %v", maxTimeout, timeout)
+	}
+}
@@ -0,0 +1,127 @@
+package htlcswitch
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// unendorsedSlotDivisor determines the portion of our HTLC slots
+	// within a channel that unendorsed HTLCs may occupy. When the
+	// endorsement experiment is active, at most 1/unendorsedSlotDivisor of
+	// the slots are available to unendorsed HTLCs.
+	unendorsedSlotDivisor = 2
+
+	// unendorsedLiquidityDivisor determines the portion of a channel's
+	// capacity that unendorsed HTLCs may lock up. When the endorsement
+	// experiment is active, at most 1/unendorsedLiquidityDivisor of the
+	// capacity is available to unendorsed HTLCs.
+	unendorsedLiquidityDivisor = 2
+)
+
+// ErrUnendorsedBucketFull is returned when an unendorsed HTLC can't be added
+// to a channel, as the slots or liquidity reserved for unendorsed HTLCs have
+// been exhausted.
+var ErrUnendorsedBucketFull = errors.New("unendorsed htlc bucket exhausted")
+
+// recordIncomingEndorsement records whether an incoming HTLC, identified by
+// its index within the remote party's update log, has been endorsed by the
+// remote party. Endorsements are only recorded if the endorsement experiment
+// is active.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) recordIncomingEndorsement(index uint64,
+	htlc *lnwire.UpdateAddHTLC) {
+
+	if !l.cfg.EndorsementExperiment || !htlc.Endorsed {
+		return
+	}
+
+	l.endorsedHtlcs[index] = struct{}{}
+}
+
+// consumeIncomingEndorsement returns true if the incoming HTLC with the
+// given index was endorsed by the remote party. As the endorsement is only
+// needed once, when the HTLC is forwarded, it's forgotten afterwards.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) consumeIncomingEndorsement(index uint64) bool {
+	_, ok := l.endorsedHtlcs[index]
+	delete(l.endorsedHtlcs, index)
+
+	return ok
+}
+
+// addHTLC adds a new outgoing HTLC to the channel's state machine. The HTLC
+// is rejected if it would exceed the maximum value in flight the remote party
+// permits. If the endorsement experiment is active, then locally initiated
+// HTLCs are endorsed, while unendorsed HTLCs are restricted to a portion of
+// the channel's slots and liquidity.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) addHTLC(pkt *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC) (uint64, error) {
+
+	if err := l.checkOutgoingInFlight(htlc); err != nil {
+		return 0, err
+	}
+
+	if l.cfg.EndorsementExperiment {
+		// We always endorse our own payments. A blank incoming
+		// channel ID indicates that the HTLC was initiated locally.
+		if pkt.incomingChanID == (lnwire.ShortChannelID{}) {
+			htlc.Endorsed = true
+		}
+
+		if !htlc.Endorsed {
+			if err := l.checkUnendorsedBucket(htlc); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return l.channel.AddHTLC(htlc)
+}
+
+// checkUnendorsedBucket returns ErrUnendorsedBucketFull if adding the passed
+// unendorsed HTLC would exceed the slots or liquidity available to unendorsed
+// HTLCs within the channel.
+func (l *channelLink) checkUnendorsedBucket(htlc *lnwire.UpdateAddHTLC) error {
+	maxSlots := lnwallet.MaxHTLCNumber / 2 / unendorsedSlotDivisor
+	maxValue := lnwire.NewMSatFromSatoshis(
+		l.channel.Capacity / unendorsedLiquidityDivisor,
+	)
+
+	// We'll tally the unendorsed HTLCs that we've offered and that are
+	// still unresolved.
+	var (
+		numUnendorsed   int
+		unendorsedValue lnwire.MilliSatoshi
+	)
+	l.htlcAgeMtx.Lock()
+	for _, h := range l.outgoingHtlcs {
+		if h.htlc.Endorsed {
+			continue
+		}
+
+		numUnendorsed++
+		unendorsedValue += h.htlc.Amount
+	}
+	l.htlcAgeMtx.Unlock()
+
+	if numUnendorsed+1 > maxSlots ||
+		unendorsedValue+htlc.Amount > maxValue {
+
+		log.Debugf("ChannelPoint(%v): rejecting unendorsed htlc with "+
+			"payment_hash=%x, amt=%v, %v unendorsed htlcs worth "+
+			"%v are in flight", l.channel.ChannelPoint(),
+			htlc.PaymentHash[:], htlc.Amount, numUnendorsed,
+			unendorsedValue)
+
+		return ErrUnendorsedBucketFull
+	}
+
+	return nil
+}
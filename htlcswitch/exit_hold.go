@@ -0,0 +1,356 @@
+package htlcswitch
+
+import (
+	"bytes"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// ExitHTLC describes an incoming HTLC paying to one of our invoices, for
+// which we're the exit hop.
+type ExitHTLC struct {
+	// ChanID is the short channel ID of the channel the HTLC arrived on.
+	ChanID lnwire.ShortChannelID
+
+	// HtlcIndex is the index of the HTLC within the channel.
+	HtlcIndex uint64
+
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// Amount is the value of the HTLC.
+	Amount lnwire.MilliSatoshi
+
+	// Expiry is the absolute height at which the HTLC expires.
+	Expiry uint32
+}
+
+// invoiceHTLC returns the HTLC as it's recorded within the HTLC set of the
+// invoice it pays to.
+func (h *ExitHTLC) invoiceHTLC() channeldb.InvoiceHTLC {
+	return channeldb.InvoiceHTLC{
+		ChanID:    h.ChanID,
+		HtlcIndex: h.HtlcIndex,
+		Amt:       h.Amount,
+		Expiry:    h.Expiry,
+	}
+}
+
+// ExitHTLCAcceptor decides whether an HTLC for which we're the exit hop may
+// be settled, such as an external service which is to be consulted before
+// the payment is accepted.
+type ExitHTLCAcceptor interface {
+	// AcceptExitHTLC blocks until a decision about the passed HTLC has
+	// been made, returning true if the HTLC may be settled, and false if
+	// it should be failed back to the sender. The passed quit channel is
+	// closed if the link shuts down in the meantime, after which the
+	// return value is ignored.
+	AcceptExitHTLC(htlc *ExitHTLC, quit <-chan struct{}) bool
+}
+
+// heldExitHtlc is an HTLC for which we're the exit hop, and whose settlement
+// is being held back until it's safe to reveal its preimage.
+type heldExitHtlc struct {
+	htlc     ExitHTLC
+	preimage [32]byte
+
+	// hold is true if the HTLC pays to a hold invoice. Such HTLCs are only
+	// settled once the preimage of the invoice has been released through
+	// the registry, and are failed if the invoice is canceled instead.
+	hold bool
+
+	// backpressure, if non-nil, is closed once the registry is able to
+	// accept further invoice settles. Until then, the HTLC is held, even
+	// if it's otherwise ready to be settled.
+	backpressure <-chan struct{}
+
+	// obfuscator is used to encrypt the failure sent back to the sender
+	// if the HTLC is rejected. If nil, then it's decoded from the onion
+	// blob of the HTLC on demand.
+	obfuscator ErrorEncrypter
+	onionBlob  []byte
+
+	// stateless is the invoice the HTLC pays to if it's a stateless
+	// invoice, which was reconstructed from the onion of the HTLC.
+	stateless *channeldb.Invoice
+
+	// decided is true once the acceptor has decided upon the HTLC, with
+	// accepted holding its decision.
+	decided  bool
+	accepted bool
+}
+
+// exitDecision is the decision of the ExitHTLCAcceptor, or of the registry in
+// the case of a hold invoice, about a held HTLC.
+type exitDecision struct {
+	htlcIndex uint64
+	accepted  bool
+
+	// preimage is the released preimage of the hold invoice the HTLC
+	// pays to, if any.
+	preimage *[32]byte
+}
+
+// holdExitHtlc holds back the settlement of the passed HTLC, for which we're
+// the exit hop, until it's irrevocably committed to both commitment
+// transactions, and the ExitHTLCAcceptor, if any, has accepted it. This
+// ensures we never reveal the preimage of an HTLC that the remote party is
+// still able to remove from the channel, or that the acceptor may yet reject.
+// If the HTLC pays to a hold invoice, then it's additionally held until the
+// invoice is either settled or canceled through the registry. If the registry
+// is saturated with settles, then it's held until the registry catches up.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) holdExitHtlc(held *heldExitHtlc) {
+	if _, ok := l.heldExitHtlcs[held.htlc.HtlcIndex]; ok {
+		return
+	}
+
+	log.Debugf("ChannelPoint(%v): holding settlement of exit htlc=%v "+
+		"with payment_hash=%x", l.channel.ChannelPoint(),
+		held.htlc.HtlcIndex, held.htlc.PaymentHash[:])
+
+	l.heldExitHtlcs[held.htlc.HtlcIndex] = held
+
+	// Without an acceptor, the HTLC only needs to be irrevocably
+	// committed to be settled, unless it pays to a hold invoice.
+	acceptor := l.cfg.ExitHTLCAcceptor
+	if !l.cfg.SafeExitSettle {
+		acceptor = nil
+	}
+	if acceptor == nil && !held.hold && held.backpressure == nil {
+		held.decided = true
+		held.accepted = true
+		return
+	}
+
+	htlc, hold, backpressure := held.htlc, held.hold, held.backpressure
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+
+		decision := exitDecision{
+			htlcIndex: htlc.HtlcIndex,
+			accepted:  true,
+		}
+		if acceptor != nil {
+			decision.accepted = acceptor.AcceptExitHTLC(
+				&htlc, l.quit,
+			)
+		}
+
+		// If the HTLC pays to a hold invoice, then we'll record it as
+		// accepted, and wait for the invoice to be either settled or
+		// canceled.
+		if decision.accepted && hold {
+			err := l.cfg.Registry.AcceptInvoice(
+				htlc.PaymentHash, htlc.invoiceHTLC(),
+			)
+			if err != nil {
+				log.Errorf("ChannelPoint(%v): unable to accept "+
+					"htlc=%v for hold invoice %x: %v",
+					l.channel.ChannelPoint(),
+					htlc.HtlcIndex, htlc.PaymentHash[:],
+					err)
+			}
+
+			invoice, err := l.cfg.Registry.AwaitHoldInvoice(
+				htlc.PaymentHash, l.quit,
+			)
+			select {
+			case <-l.quit:
+				return
+			default:
+			}
+
+			switch {
+			case err != nil:
+				log.Errorf("ChannelPoint(%v): unable to await "+
+					"hold invoice %x: %v",
+					l.channel.ChannelPoint(),
+					htlc.PaymentHash[:], err)
+				decision.accepted = false
+
+			case invoice.Terms.HoldState == channeldb.HoldSettling:
+				preimage := invoice.Terms.PaymentPreimage
+				decision.preimage = &preimage
+
+			default:
+				decision.accepted = false
+			}
+		}
+
+		// If the registry is saturated with settles, then we'll wait
+		// until it catches up before settling the HTLC.
+		if decision.accepted && backpressure != nil {
+			select {
+			case <-backpressure:
+			case <-l.quit:
+				return
+			}
+		}
+
+		select {
+		case l.exitDecisions <- decision:
+		case <-l.quit:
+		}
+	}()
+}
+
+// awaitSettleCapacity holds back the settlement of the passed HTLC, which has
+// already been accepted, until the passed backpressure channel is closed,
+// signalling that the registry is able to accept further invoice settles.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) awaitSettleCapacity(held *heldExitHtlc,
+	backpressure <-chan struct{}) {
+
+	log.Debugf("ChannelPoint(%v): invoice registry saturated, holding "+
+		"settle of exit htlc=%v", l.channel.ChannelPoint(),
+		held.htlc.HtlcIndex)
+
+	held.decided = false
+
+	htlcIndex := held.htlc.HtlcIndex
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+
+		select {
+		case <-backpressure:
+		case <-l.quit:
+			return
+		}
+
+		decision := exitDecision{
+			htlcIndex: htlcIndex,
+			accepted:  true,
+		}
+		select {
+		case l.exitDecisions <- decision:
+		case <-l.quit:
+		}
+	}()
+}
+
+// handleExitDecision records the acceptor's decision about a held HTLC, then
+// resolves the held HTLCs which are now able to be resolved. It returns true
+// if any updates were added to the channel.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) handleExitDecision(decision exitDecision) (bool, error) {
+	held, ok := l.heldExitHtlcs[decision.htlcIndex]
+	if !ok {
+		return false, nil
+	}
+
+	held.decided = true
+	held.accepted = decision.accepted
+	if decision.preimage != nil {
+		held.preimage = *decision.preimage
+	}
+
+	return l.resolveHeldExitHtlcs()
+}
+
+// resolveHeldExitHtlcs settles each held HTLC which has been accepted, and
+// which is irrevocably committed to both commitment transactions, and fails
+// each held HTLC which has been rejected. It returns true if any updates were
+// added to the channel.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) resolveHeldExitHtlcs() (bool, error) {
+	if len(l.heldExitHtlcs) == 0 {
+		return false, nil
+	}
+
+	// The active HTLCs are those present on the current commitment of
+	// both parties, meaning that all prior states which didn't include
+	// them have been revoked.
+	committed := make(map[uint64]struct{})
+	for _, htlc := range l.channel.ActiveHtlcs() {
+		if htlc.Incoming {
+			committed[htlc.HtlcIndex] = struct{}{}
+		}
+	}
+
+	var updated bool
+	for htlcIndex, held := range l.heldExitHtlcs {
+		if !held.decided {
+			continue
+		}
+
+		if !held.accepted {
+			log.Infof("ChannelPoint(%v): exit htlc=%v with "+
+				"payment_hash=%x rejected",
+				l.channel.ChannelPoint(), htlcIndex,
+				held.htlc.PaymentHash[:])
+
+			obfuscator := held.obfuscator
+			if obfuscator == nil {
+				var failureCode lnwire.FailCode
+				obfuscator, failureCode = l.cfg.OnionProcessor.
+					ExtractErrorEncrypter(
+						bytes.NewReader(held.onionBlob),
+					)
+				if failureCode != lnwire.CodeNone {
+					l.sendMalformedHTLCError(
+						htlcIndex, failureCode,
+						held.onionBlob,
+					)
+					delete(l.heldExitHtlcs, htlcIndex)
+					l.batchCounter++
+					updated = true
+					continue
+				}
+			}
+
+			failure := lnwire.FailUnknownPaymentHash{}
+			l.sendHTLCError(htlcIndex, failure, obfuscator)
+			delete(l.heldExitHtlcs, htlcIndex)
+			l.batchCounter++
+			updated = true
+			continue
+		}
+
+		if _, ok := committed[htlcIndex]; !ok {
+			continue
+		}
+
+		// Rather than blocking on the registry while it's saturated
+		// with settles, we'll hold the HTLC a while longer.
+		backpressure := l.cfg.Registry.SettleBackpressure()
+		if backpressure != nil {
+			l.awaitSettleCapacity(held, backpressure)
+			continue
+		}
+
+		err := l.channel.SettleHTLC(held.preimage, htlcIndex)
+		if err != nil {
+			return updated, err
+		}
+
+		// Notify the invoiceRegistry of the invoice we just settled
+		// with this latest commitment update.
+		invoiceHash := chainhash.Hash(held.htlc.PaymentHash)
+		err = l.settleExitInvoice(
+			invoiceHash, held.htlc.invoiceHTLC(), held.stateless,
+		)
+		if err != nil {
+			return updated, err
+		}
+
+		l.sendUpdate(&lnwire.UpdateFufillHTLC{
+			ChanID:          l.ChanID(),
+			ID:              htlcIndex,
+			PaymentPreimage: held.preimage,
+		})
+		delete(l.heldExitHtlcs, htlcIndex)
+		l.batchCounter++
+		updated = true
+	}
+
+	return updated, nil
+}
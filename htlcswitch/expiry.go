@@ -0,0 +1,62 @@
+package htlcswitch
+
+import (
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// auditExpiries examines the HTLCs active within the channel as of the passed
+// height, and acts upon those which the remote party has left unresolved for
+// too long:
+//
+//   - An expired outgoing HTLC remains live off-chain until the remote party
+//     removes it, so its incoming counterpart can't be cancelled back yet.
+//     Instead, the channel is handed off to the contract court, which
+//     cancels back the HTLC once the channel is closed if it's dust, and
+//     once it's been timed out on-chain otherwise.
+//   - An incoming HTLC we know the preimage for, which is within
+//     ExpiryGraceDelta blocks of expiring, must be claimed on-chain before
+//     the remote party is able to time it out, so the channel is handed off
+//     to the contract court as well.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) auditExpiries(height uint32) {
+	if l.cfg.ExpiryGraceDelta == 0 || l.forceCloseRequested {
+		return
+	}
+
+	var onChainHtlc *channeldb.HTLC
+	for _, htlc := range l.channel.ActiveHtlcs() {
+		htlc := htlc
+
+		switch {
+		case !htlc.Incoming && height >= htlc.RefundTimeout:
+			onChainHtlc = &htlc
+
+		case htlc.Incoming &&
+			height+l.cfg.ExpiryGraceDelta >= htlc.RefundTimeout:
+
+			_, ok := l.cfg.PreimageCache.LookupPreimage(htlc.RHash[:])
+			if ok {
+				onChainHtlc = &htlc
+			}
+		}
+	}
+
+	if onChainHtlc == nil || l.cfg.ForceCloseChan == nil {
+		return
+	}
+
+	log.Warnf("ChannelPoint(%v): htlc(index=%v, incoming=%v, hash=%x) "+
+		"expires at height %v, current height is %v, going on-chain",
+		l.channel.ChannelPoint(), onChainHtlc.HtlcIndex,
+		onChainHtlc.Incoming, onChainHtlc.RHash[:],
+		onChainHtlc.RefundTimeout, height)
+
+	l.forceCloseRequested = true
+	go func() {
+		if err := l.cfg.ForceCloseChan(); err != nil {
+			log.Errorf("ChannelPoint(%v): unable to force close "+
+				"channel: %v", l.channel.ChannelPoint(), err)
+		}
+	}()
+}
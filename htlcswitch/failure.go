@@ -3,6 +3,7 @@ package htlcswitch
 import (
 	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -59,14 +60,55 @@ type ErrorEncrypter interface {
 	// in an additional layer of onion encryption. This process repeats
 	// until the error arrives at the source of the payment.
 	IntermediateEncrypt(lnwire.OpaqueReason) lnwire.OpaqueReason
+
+	// Type returns the type of the encrypter, which is persisted along
+	// with it so that the proper implementation can be decoded.
+	Type() EncrypterType
+
+	// Encode writes the state of the encrypter required to restore it to
+	// the passed io.Writer.
+	Encode(io.Writer) error
+
+	// Decode reads the state of the encrypter from the passed io.Reader.
+	Decode(io.Reader) error
+
+	// Reextract restores any secrets of the encrypter which aren't
+	// persisted, using the passed extracter. It must be called after the
+	// encrypter has been decoded, and before it's used.
+	Reextract(ErrorEncrypterExtracter) error
 }
 
+// EncrypterType identifies an implementation of the ErrorEncrypter interface.
+type EncrypterType byte
+
+const (
+	// EncrypterTypeNone signals that no encrypter is present.
+	EncrypterTypeNone EncrypterType = 0
+
+	// EncrypterTypeSphinx identifies the SphinxErrorEncrypter.
+	EncrypterTypeSphinx EncrypterType = 1
+
+	// EncrypterTypeMock identifies the mock encrypter used within tests.
+	EncrypterTypeMock EncrypterType = 2
+)
+
+// ErrorEncrypterExtracter derives an ErrorEncrypter from the ephemeral key of
+// an onion packet. In the case that an error occurs, a lnwire failure code
+// detailing the failure will be returned.
+type ErrorEncrypterExtracter func(*btcec.PublicKey) (ErrorEncrypter,
+	lnwire.FailCode)
+
 // SphinxErrorEncrypter is a concrete implementation of both the ErrorEncrypter
 // interface backed by an implementation of the Sphinx packet format. As a
 // result, all errors handled are themselves wrapped in layers of onion
 // encryption and must be treated as such accordingly.
 type SphinxErrorEncrypter struct {
 	*sphinx.OnionErrorEncrypter
+
+	// EphemeralKey is the ephemeral key of the onion packet the encrypter
+	// was derived from. It's persisted in place of the shared secret,
+	// which is re-derived from it upon restoring the encrypter.
+	EphemeralKey *btcec.PublicKey
 }
 
 // EncryptFirstHop transforms a concrete failure message into an encrypted
@@ -97,6 +139,63 @@ func (s *SphinxErrorEncrypter) IntermediateEncrypt(reason lnwire.OpaqueReason) l
 	return s.EncryptError(false, reason)
 }
 
+// Type returns the type of the encrypter.
+//
+// NOTE: Part of the ErrorEncrypter interface.
+func (s *SphinxErrorEncrypter) Type() EncrypterType {
+	return EncrypterTypeSphinx
+}
+
+// Encode writes the compressed ephemeral key of the encrypter to the passed
+// io.Writer.
+//
+// NOTE: Part of the ErrorEncrypter interface.
+func (s *SphinxErrorEncrypter) Encode(w io.Writer) error {
+	_, err := w.Write(s.EphemeralKey.SerializeCompressed())
+	return err
+}
+
+// Decode reads the compressed ephemeral key of the encrypter from the passed
+// io.Reader.
+//
+// NOTE: Part of the ErrorEncrypter interface.
+func (s *SphinxErrorEncrypter) Decode(r io.Reader) error {
+	var keyBytes [btcec.PubKeyBytesLenCompressed]byte
+	if _, err := io.ReadFull(r, keyBytes[:]); err != nil {
+		return err
+	}
+
+	ephemeralKey, err := btcec.ParsePubKey(keyBytes[:], btcec.S256())
+	if err != nil {
+		return err
+	}
+	s.EphemeralKey = ephemeralKey
+
+	return nil
+}
+
+// Reextract re-derives the shared secret of the encrypter from its ephemeral
+// key using the passed extracter.
+//
+// NOTE: Part of the ErrorEncrypter interface.
+func (s *SphinxErrorEncrypter) Reextract(
+	extract ErrorEncrypterExtracter) error {
+
+	obfuscator, failcode := extract(s.EphemeralKey)
+	if failcode != lnwire.CodeNone {
+		return fmt.Errorf("unable to reextract error encrypter: %v",
+			failcode)
+	}
+
+	sphinxEncrypter, ok := obfuscator.(*SphinxErrorEncrypter)
+	if !ok {
+		return fmt.Errorf("expected %T, instead got %T", s, obfuscator)
+	}
+	s.OnionErrorEncrypter = sphinxEncrypter.OnionErrorEncrypter
+
+	return nil
+}
+
 // A compile time check to ensure SphinxErrorEncrypter implements the
 // ErrorEncrypter interface.
 var _ ErrorEncrypter = (*SphinxErrorEncrypter)(nil)
@@ -0,0 +1,176 @@
+package htlcswitch
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// FaultAction is the action the switch takes upon a packet matched by a
+// FaultRule.
+type FaultAction uint8
+
+const (
+	// FaultDrop causes the packet to be discarded, while reporting to the
+	// sender that it was forwarded successfully.
+	FaultDrop FaultAction = iota
+
+	// FaultDelay causes the packet to be held for the delay of the rule
+	// before it's forwarded.
+	FaultDelay
+
+	// FaultDuplicate causes a copy of the packet to be forwarded ahead of
+	// the packet itself.
+	FaultDuplicate
+)
+
+// String returns a human readable version of the FaultAction.
+func (a FaultAction) String() string {
+	switch a {
+	case FaultDrop:
+		return "drop"
+	case FaultDelay:
+		return "delay"
+	case FaultDuplicate:
+		return "duplicate"
+	default:
+		return "unknown"
+	}
+}
+
+// FaultRule describes a fault to be injected into the forwarding path of the
+// switch.
+type FaultRule struct {
+	// Action is the action taken upon each matching packet.
+	Action FaultAction
+
+	// MsgType restricts the rule to the packets carrying an update of the
+	// given type, either MsgUpdateAddHTLC, MsgUpdateFufillHTLC or
+	// MsgUpdateFailHTLC. If zero, the rule matches packets of any type.
+	MsgType lnwire.MessageType
+
+	// Count is the number of matching packets the rule applies to, after
+	// which it's discarded.
+	Count uint32
+
+	// Delay is the time for which matching packets are held, if the
+	// action is FaultDelay.
+	Delay time.Duration
+}
+
+// String returns a human readable version of the FaultRule.
+func (r *FaultRule) String() string {
+	msgType := "any"
+	if r.MsgType != 0 {
+		msgType = r.MsgType.String()
+	}
+
+	return fmt.Sprintf("FaultRule(action=%v, type=%v, count=%v, delay=%v)",
+		r.Action, msgType, r.Count, r.Delay)
+}
+
+// FaultInjector is a debugging aid which injects faults into the forwarding
+// path of the switch, allowing the circuit map and the retransmission logic
+// of the links to be exercised under adversarial scheduling by integration
+// tests. Rules are matched in the order they were injected, so the packets
+// affected are deterministic for a given sequence of forwards.
+type FaultInjector struct {
+	mtx   sync.Mutex
+	rules []*FaultRule
+}
+
+// NewFaultInjector creates a new FaultInjector without any rules.
+func NewFaultInjector() *FaultInjector {
+	return &FaultInjector{}
+}
+
+// Inject adds the passed rule, to be applied after all rules injected prior.
+func (f *FaultInjector) Inject(rule FaultRule) error {
+	switch rule.MsgType {
+	case 0, lnwire.MsgUpdateAddHTLC, lnwire.MsgUpdateFufillHTLC,
+		lnwire.MsgUpdateFailHTLC:
+	default:
+		return fmt.Errorf("faults can't be injected for %v",
+			rule.MsgType)
+	}
+
+	if rule.Action > FaultDuplicate {
+		return fmt.Errorf("unknown fault action: %v", rule.Action)
+	}
+	if rule.Count == 0 {
+		return fmt.Errorf("fault rule must apply to at least one packet")
+	}
+
+	f.mtx.Lock()
+	f.rules = append(f.rules, &rule)
+	f.mtx.Unlock()
+
+	log.Infof("Injected %v", &rule)
+
+	return nil
+}
+
+// Clear discards all pending rules.
+func (f *FaultInjector) Clear() {
+	f.mtx.Lock()
+	f.rules = nil
+	f.mtx.Unlock()
+}
+
+// Pending returns the number of rules that have yet to be fully applied.
+func (f *FaultInjector) Pending() int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	return len(f.rules)
+}
+
+// match returns the first rule matching the passed packet, if any, counting
+// the packet against it.
+func (f *FaultInjector) match(packet *htlcPacket) (FaultRule, bool) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	msgType := packet.htlc.MsgType()
+	for i, rule := range f.rules {
+		if rule.MsgType != 0 && rule.MsgType != msgType {
+			continue
+		}
+
+		matched := *rule
+		rule.Count--
+		if rule.Count == 0 {
+			f.rules = append(f.rules[:i], f.rules[i+1:]...)
+		}
+
+		return matched, true
+	}
+
+	return FaultRule{}, false
+}
+
+// copyPacket returns a copy of the passed packet, which can be forwarded
+// alongside it without either being affected by modifications the switch
+// makes to the other.
+func copyPacket(packet *htlcPacket) *htlcPacket {
+	dup := *packet
+
+	switch htlc := packet.htlc.(type) {
+	case *lnwire.UpdateAddHTLC:
+		msg := *htlc
+		dup.htlc = &msg
+
+	case *lnwire.UpdateFufillHTLC:
+		msg := *htlc
+		dup.htlc = &msg
+
+	case *lnwire.UpdateFailHTLC:
+		msg := *htlc
+		msg.Reason = append(lnwire.OpaqueReason(nil), htlc.Reason...)
+		dup.htlc = &msg
+	}
+
+	return &dup
+}
@@ -0,0 +1,70 @@
+package htlcswitch
+
+import (
+	"fmt"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// remoteFeeBounds returns the range of commitment fee rates we'll accept from
+// the remote party, given our own estimate of the network fee rate. A fee
+// rate is accepted if it's within the passed multiple of our estimate, as
+// well as within the absolute bounds of minFee and maxFee. A tolerance or
+// estimate of zero disables the former, while a minFee or maxFee of zero
+// leaves the respective absolute bound unset. An upper bound of zero
+// indicates the range is unbounded above.
+func remoteFeeBounds(estimate btcutil.Amount, tolerance float64, minFee,
+	maxFee btcutil.Amount) (btcutil.Amount, btcutil.Amount) {
+
+	lower, upper := minFee, maxFee
+	if tolerance == 0 || estimate == 0 {
+		return lower, upper
+	}
+
+	estimateLower := btcutil.Amount(float64(estimate) / tolerance)
+	if estimateLower > lower {
+		lower = estimateLower
+	}
+
+	estimateUpper := btcutil.Amount(float64(estimate) * tolerance)
+	if upper == 0 || estimateUpper < upper {
+		upper = estimateUpper
+	}
+
+	return lower, upper
+}
+
+// checkRemoteFee ensures the commitment fee rate proposed by the remote
+// party, as the initiator of the channel, is within the bounds of the link.
+// If our own estimate of the network fee rate can't be sampled, then only the
+// absolute bounds are enforced.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) checkRemoteFee(feePerKw btcutil.Amount) error {
+	var estimate btcutil.Amount
+	if l.cfg.FeeRateTolerance != 0 {
+		var err error
+		estimate, err = l.sampleNetworkFee()
+		if err != nil {
+			log.Warnf("ChannelPoint(%v): unable to sample network "+
+				"fee to check fee update against: %v",
+				l.channel.ChannelPoint(), err)
+		}
+	}
+
+	lower, upper := remoteFeeBounds(
+		estimate, l.cfg.FeeRateTolerance, l.cfg.MinFeeRate,
+		l.cfg.MaxFeeRate,
+	)
+	switch {
+	case feePerKw < lower:
+		return fmt.Errorf("fee rate of %v sat/kw is below the minimum "+
+			"of %v sat/kw", int64(feePerKw), int64(lower))
+
+	case upper != 0 && feePerKw > upper:
+		return fmt.Errorf("fee rate of %v sat/kw is above the maximum "+
+			"of %v sat/kw", int64(feePerKw), int64(upper))
+	}
+
+	return nil
+}
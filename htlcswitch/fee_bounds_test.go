@@ -0,0 +1,79 @@
+package htlcswitch
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// TestRemoteFeeBounds tests that the range of commitment fee rates accepted
+// from the remote party is the intersection of the absolute bounds and the
+// tolerated multiple of our estimate, with either being optional.
+func TestRemoteFeeBounds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		estimate  btcutil.Amount
+		tolerance float64
+		minFee    btcutil.Amount
+		maxFee    btcutil.Amount
+		lower     btcutil.Amount
+		upper     btcutil.Amount
+	}{
+		{
+			name:     "no bounds",
+			estimate: 1000,
+		},
+		{
+			name:     "absolute bounds only",
+			estimate: 1000,
+			minFee:   253,
+			maxFee:   50000,
+			lower:    253,
+			upper:    50000,
+		},
+		{
+			name:      "tolerance only",
+			estimate:  1000,
+			tolerance: 10,
+			lower:     100,
+			upper:     10000,
+		},
+		{
+			name:      "tolerance without estimate",
+			tolerance: 10,
+			minFee:    253,
+			lower:     253,
+		},
+		{
+			name:      "tighter absolute bounds",
+			estimate:  1000,
+			tolerance: 10,
+			minFee:    253,
+			maxFee:    5000,
+			lower:     253,
+			upper:     5000,
+		},
+		{
+			name:      "tighter tolerance",
+			estimate:  1000,
+			tolerance: 2,
+			minFee:    253,
+			maxFee:    50000,
+			lower:     500,
+			upper:     2000,
+		},
+	}
+
+	for _, test := range tests {
+		lower, upper := remoteFeeBounds(
+			test.estimate, test.tolerance, test.minFee, test.maxFee,
+		)
+		if lower != test.lower || upper != test.upper {
+			t.Fatalf("%v: expected bounds of [%v, %v], got "+
+				"[%v, %v]", test.name, test.lower, test.upper,
+				lower, upper)
+		}
+	}
+}
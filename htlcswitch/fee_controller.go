@@ -0,0 +1,219 @@
+package htlcswitch
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// feeOutcomeWindow is the number of the most recently resolved outgoing HTLCs
+// of a link over which its failure rate is computed.
+const feeOutcomeWindow = 100
+
+// FeeContext describes an HTLC to be forwarded, along with the state of the
+// link it was received over, when a FeeController is consulted.
+type FeeContext struct {
+	// ShortChanID is the short channel ID of the link.
+	ShortChanID lnwire.ShortChannelID
+
+	// Policy is the current forwarding policy of the link.
+	Policy ForwardingPolicy
+
+	// AmountToForward is the value of the HTLC to be forwarded.
+	AmountToForward lnwire.MilliSatoshi
+
+	// Bandwidth is the current available bandwidth of the link.
+	Bandwidth lnwire.MilliSatoshi
+
+	// Capacity is the capacity of the link's channel.
+	Capacity lnwire.MilliSatoshi
+
+	// FailureRate is the fraction of the most recently resolved HTLCs
+	// offered over the link that were failed, rather than settled. It's
+	// zero if no HTLCs have been resolved yet.
+	FailureRate float64
+}
+
+// FeeController is an interface which allows the fees charged for forwarding
+// HTLCs to be computed dynamically. If a link has a FeeController, then it's
+// consulted for each HTLC the link forwards, in place of the fees of its
+// static forwarding policy.
+//
+// NOTE: The fees returned aren't advertised to the rest of the network.
+// HTLCs which pay less than them are failed with FeeInsufficient, which
+// carries our latest channel update, so a controller should only rarely
+// charge more than the advertised fees.
+type FeeController interface {
+	// ForwardingFee returns the base fee and fee rate to be charged for
+	// forwarding the HTLC described by the passed context.
+	ForwardingFee(ctx *FeeContext) (lnwire.MilliSatoshi,
+		lnwire.MilliSatoshi)
+}
+
+// feeOutcomes tracks whether each of the most recently resolved outgoing
+// HTLCs of a link was failed, allowing its failure rate to be computed.
+type feeOutcomes struct {
+	failed [feeOutcomeWindow]bool
+	next   int
+	count  int
+}
+
+// record records the outcome of a resolved outgoing HTLC, evicting the oldest
+// outcome once the window is full.
+func (o *feeOutcomes) record(failed bool) {
+	o.failed[o.next] = failed
+	o.next = (o.next + 1) % feeOutcomeWindow
+	if o.count < feeOutcomeWindow {
+		o.count++
+	}
+}
+
+// failureRate returns the fraction of the recorded outcomes that were
+// failures.
+func (o *feeOutcomes) failureRate() float64 {
+	if o.count == 0 {
+		return 0
+	}
+
+	var numFailed int
+	for i := 0; i < o.count; i++ {
+		if o.failed[i] {
+			numFailed++
+		}
+	}
+
+	return float64(numFailed) / float64(o.count)
+}
+
+// expectedFee returns the fee the passed amount must pay to be forwarded by
+// the link, consulting the link's FeeController if it has one.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) expectedFee(
+	amtToForward lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	policy := l.cfg.FwrdingPolicy
+	if policy.FeeController == nil {
+		return ExpectedFee(policy, amtToForward)
+	}
+
+	ctx := &FeeContext{
+		ShortChanID:     l.ShortChanID(),
+		Policy:          policy,
+		AmountToForward: amtToForward,
+		Bandwidth:       l.Bandwidth(),
+		Capacity:        lnwire.NewMSatFromSatoshis(l.channel.Capacity),
+		FailureRate:     l.feeOutcomes.failureRate(),
+	}
+	policy.BaseFee, policy.FeeRate = policy.FeeController.ForwardingFee(ctx)
+
+	return ExpectedFee(policy, amtToForward)
+}
+
+// ScheduledFee is a base fee and fee rate which takes effect at a scheduled
+// time.
+type ScheduledFee struct {
+	// Start is the time at which the fees take effect.
+	Start time.Time
+
+	// BaseFee is the base fee to be charged.
+	BaseFee lnwire.MilliSatoshi
+
+	// FeeRate is the fee rate to be charged.
+	FeeRate lnwire.MilliSatoshi
+}
+
+// ScheduledFeeController is a FeeController which charges fees according to a
+// schedule. Before the first scheduled fees take effect, the fees of the
+// link's forwarding policy are charged.
+type ScheduledFeeController struct {
+	mtx      sync.RWMutex
+	schedule []ScheduledFee
+}
+
+// NewScheduledFeeController returns a ScheduledFeeController following the
+// passed schedule.
+func NewScheduledFeeController(
+	schedule ...ScheduledFee) *ScheduledFeeController {
+
+	c := &ScheduledFeeController{}
+	c.Schedule(schedule...)
+
+	return c
+}
+
+// Schedule adds the passed fees to the schedule of the controller.
+func (c *ScheduledFeeController) Schedule(fees ...ScheduledFee) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.schedule = append(c.schedule, fees...)
+	sort.Slice(c.schedule, func(i, j int) bool {
+		return c.schedule[i].Start.Before(c.schedule[j].Start)
+	})
+}
+
+// ForwardingFee returns the most recently scheduled fees to have taken
+// effect.
+//
+// NOTE: Part of the FeeController interface.
+func (c *ScheduledFeeController) ForwardingFee(
+	ctx *FeeContext) (lnwire.MilliSatoshi, lnwire.MilliSatoshi) {
+
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	baseFee, feeRate := ctx.Policy.BaseFee, ctx.Policy.FeeRate
+
+	now := time.Now()
+	for _, fee := range c.schedule {
+		if fee.Start.After(now) {
+			break
+		}
+
+		baseFee, feeRate = fee.BaseFee, fee.FeeRate
+	}
+
+	return baseFee, feeRate
+}
+
+// CongestionFeeController is a FeeController which bumps the fees of the
+// link's forwarding policy as the link becomes congested. The fees are
+// scaled linearly from the policy's fees, when the link's bandwidth is
+// untouched and none of its HTLCs fail, up to MaxMultiplier times the
+// policy's fees, when either the link has no bandwidth left, or all of its
+// HTLCs fail.
+type CongestionFeeController struct {
+	// MaxMultiplier is the factor by which the policy's fees are
+	// multiplied once the link is fully congested. Values below one are
+	// treated as one, leaving the fees unchanged.
+	MaxMultiplier float64
+}
+
+// ForwardingFee returns the policy's fees scaled by the congestion of the
+// link.
+//
+// NOTE: Part of the FeeController interface.
+func (c *CongestionFeeController) ForwardingFee(
+	ctx *FeeContext) (lnwire.MilliSatoshi, lnwire.MilliSatoshi) {
+
+	congestion := ctx.FailureRate
+	if ctx.Capacity != 0 && ctx.Bandwidth < ctx.Capacity {
+		utilization := 1 - float64(ctx.Bandwidth)/float64(ctx.Capacity)
+		if utilization > congestion {
+			congestion = utilization
+		}
+	}
+
+	multiplier := 1.0
+	if c.MaxMultiplier > 1 {
+		multiplier += (c.MaxMultiplier - 1) * congestion
+	}
+
+	baseFee := lnwire.MilliSatoshi(float64(ctx.Policy.BaseFee) * multiplier)
+	feeRate := lnwire.MilliSatoshi(float64(ctx.Policy.FeeRate) * multiplier)
+
+	return baseFee, feeRate
+}
@@ -0,0 +1,129 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestFeeOutcomesFailureRate tests that the failure rate is computed over only
+// the most recently resolved HTLCs.
+func TestFeeOutcomesFailureRate(t *testing.T) {
+	t.Parallel()
+
+	var outcomes feeOutcomes
+	if rate := outcomes.failureRate(); rate != 0 {
+		t.Fatalf("expected failure rate of 0, got %v", rate)
+	}
+
+	// Fail a quarter of a full window of HTLCs.
+	for i := 0; i < feeOutcomeWindow; i++ {
+		outcomes.record(i%4 == 0)
+	}
+	if rate := outcomes.failureRate(); rate != 0.25 {
+		t.Fatalf("expected failure rate of 0.25, got %v", rate)
+	}
+
+	// Once a full window of HTLCs has failed, the earlier successes
+	// should no longer be counted.
+	for i := 0; i < feeOutcomeWindow; i++ {
+		outcomes.record(true)
+	}
+	if rate := outcomes.failureRate(); rate != 1 {
+		t.Fatalf("expected failure rate of 1, got %v", rate)
+	}
+}
+
+// TestScheduledFeeController tests that the fees of a ScheduledFeeController
+// are those most recently scheduled to take effect, falling back to those of
+// the link's policy.
+func TestScheduledFeeController(t *testing.T) {
+	t.Parallel()
+
+	ctx := &FeeContext{
+		Policy: ForwardingPolicy{BaseFee: 1, FeeRate: 2},
+	}
+	now := time.Now()
+
+	// Until the scheduled fees take effect, the policy's fees apply.
+	c := NewScheduledFeeController(ScheduledFee{
+		Start:   now.Add(time.Hour),
+		BaseFee: 10,
+		FeeRate: 20,
+	})
+	baseFee, feeRate := c.ForwardingFee(ctx)
+	if baseFee != 1 || feeRate != 2 {
+		t.Fatalf("expected policy fees, got base fee %v, fee rate %v",
+			baseFee, feeRate)
+	}
+
+	// Scheduled fees are applied in order of their start time, regardless
+	// of the order in which they were scheduled.
+	c.Schedule(
+		ScheduledFee{Start: now.Add(-time.Minute), BaseFee: 5, FeeRate: 6},
+		ScheduledFee{Start: now.Add(-time.Hour), BaseFee: 3, FeeRate: 4},
+	)
+	baseFee, feeRate = c.ForwardingFee(ctx)
+	if baseFee != 5 || feeRate != 6 {
+		t.Fatalf("expected base fee 5, fee rate 6, got base fee %v, "+
+			"fee rate %v", baseFee, feeRate)
+	}
+}
+
+// TestCongestionFeeController tests that a CongestionFeeController scales the
+// policy's fees by the greater of the link's utilization and failure rate.
+func TestCongestionFeeController(t *testing.T) {
+	t.Parallel()
+
+	policy := ForwardingPolicy{BaseFee: 1000, FeeRate: 100}
+	c := &CongestionFeeController{MaxMultiplier: 3}
+
+	tests := []struct {
+		name        string
+		bandwidth   lnwire.MilliSatoshi
+		failureRate float64
+		baseFee     lnwire.MilliSatoshi
+		feeRate     lnwire.MilliSatoshi
+	}{
+		{
+			name:      "idle",
+			bandwidth: 1000000,
+			baseFee:   1000,
+			feeRate:   100,
+		},
+		{
+			name:      "half utilized",
+			bandwidth: 500000,
+			baseFee:   2000,
+			feeRate:   200,
+		},
+		{
+			name:        "failing",
+			bandwidth:   500000,
+			failureRate: 1,
+			baseFee:     3000,
+			feeRate:     300,
+		},
+		{
+			name:      "exhausted",
+			bandwidth: 0,
+			baseFee:   3000,
+			feeRate:   300,
+		},
+	}
+
+	for _, test := range tests {
+		baseFee, feeRate := c.ForwardingFee(&FeeContext{
+			Policy:      policy,
+			Bandwidth:   test.bandwidth,
+			Capacity:    1000000,
+			FailureRate: test.failureRate,
+		})
+		if baseFee != test.baseFee || feeRate != test.feeRate {
+			t.Fatalf("%v: expected base fee %v, fee rate %v, got "+
+				"base fee %v, fee rate %v", test.name,
+				test.baseFee, test.feeRate, baseFee, feeRate)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package htlcswitch
+
+import (
+	"time"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// DefaultMinFeeUpdateInterval is the default minimum amount of time between
+// two commitment fee updates sent by a link, which spares the remote party a
+// flurry of updates while the fee estimate is volatile.
+const DefaultMinFeeUpdateInterval = 10 * time.Minute
+
+// limitFeeStep limits the change of the commitment fee rate from chanFee
+// towards netFee to the fraction maxStep of chanFee. Larger changes are
+// spread across several updates instead. A maxStep of zero leaves the change
+// unlimited.
+func limitFeeStep(netFee, chanFee btcutil.Amount,
+	maxStep float64) btcutil.Amount {
+
+	if maxStep == 0 {
+		return netFee
+	}
+
+	step := btcutil.Amount(float64(chanFee) * maxStep)
+	switch {
+	case netFee > chanFee+step:
+		return chanFee + step
+
+	case netFee < chanFee-step:
+		return chanFee - step
+
+	default:
+		return netFee
+	}
+}
+
+// feeUpdateDue returns true if the minimum interval between two commitment
+// fee updates has passed since the link's last one, as of the passed time.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) feeUpdateDue(now time.Time) bool {
+	return now.Sub(l.lastFeeUpdate) >= l.cfg.MinFeeUpdateInterval
+}
@@ -0,0 +1,57 @@
+package htlcswitch
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// TestLimitFeeStep tests that the change of the commitment fee rate is
+// limited to the maximum step in either direction, unless no step is set.
+func TestLimitFeeStep(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		netFee   btcutil.Amount
+		chanFee  btcutil.Amount
+		maxStep  float64
+		expected btcutil.Amount
+	}{
+		{
+			name:     "no maximum step",
+			netFee:   5000,
+			chanFee:  1000,
+			expected: 5000,
+		},
+		{
+			name:     "increase within step",
+			netFee:   1200,
+			chanFee:  1000,
+			maxStep:  0.5,
+			expected: 1200,
+		},
+		{
+			name:     "increase beyond step",
+			netFee:   5000,
+			chanFee:  1000,
+			maxStep:  0.5,
+			expected: 1500,
+		},
+		{
+			name:     "decrease beyond step",
+			netFee:   253,
+			chanFee:  1000,
+			maxStep:  0.5,
+			expected: 500,
+		},
+	}
+
+	for _, test := range tests {
+		fee := limitFeeStep(test.netFee, test.chanFee, test.maxStep)
+		if fee != test.expected {
+			t.Fatalf("%v: expected fee of %v, got %v", test.name,
+				test.expected, fee)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package htlcswitch
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcutil"
+)
+
+// FeeSpikeStats describes the forwarded HTLCs a link has declined to offer, as
+// claiming them on-chain at the prevailing fee rate would cost more than
+// they're worth.
+type FeeSpikeStats struct {
+	// FeePerKw is the fee rate the outgoing HTLCs are currently assessed
+	// at. It's zero if no fee rate has been sampled yet.
+	FeePerKw btcutil.Amount
+
+	// ClaimFee is the fee we'd currently pay to claim an outgoing HTLC
+	// on-chain.
+	ClaimFee btcutil.Amount
+
+	// NumDeclined is the number of HTLCs declined as uneconomical.
+	NumDeclined uint64
+
+	// DeclinedAmount is the total value of the HTLCs declined as
+	// uneconomical.
+	DeclinedAmount lnwire.MilliSatoshi
+
+	// LastDecline is the time the last HTLC was declined as uneconomical.
+	// It's zero if none have been.
+	LastDecline time.Time
+}
+
+// feeSpikeBreaker decides whether an outgoing HTLC is uneconomical, which is
+// the case when the fee to claim it on-chain, scaled by a multiplier, exceeds
+// its value. During fee spikes, such HTLCs are a liability rather than an
+// asset, as they'd be lost should the channel be force closed while they're
+// in flight.
+type feeSpikeBreaker struct {
+	// The following fields are only meant to be used *atomically*.
+	feePerKw       int64
+	numDeclined    uint64
+	declinedAmount uint64
+	lastDecline    int64
+
+	// multiplier is the factor the claim fee of an HTLC is scaled by
+	// before being compared to its value. A value of zero disables the
+	// breaker.
+	multiplier float64
+}
+
+// newFeeSpikeBreaker returns a feeSpikeBreaker which considers HTLCs whose
+// claim fee, scaled by the passed multiplier, exceeds their value to be
+// uneconomical.
+func newFeeSpikeBreaker(multiplier float64) *feeSpikeBreaker {
+	return &feeSpikeBreaker{
+		multiplier: multiplier,
+	}
+}
+
+// enabled returns true if the breaker declines uneconomical HTLCs.
+func (b *feeSpikeBreaker) enabled() bool {
+	return b.multiplier != 0
+}
+
+// setFeeRate sets the fee rate, expressed in sat/kw, at which outgoing HTLCs
+// are assessed.
+func (b *feeSpikeBreaker) setFeeRate(feePerKw btcutil.Amount) {
+	atomic.StoreInt64(&b.feePerKw, int64(feePerKw))
+}
+
+// claimFee returns the fee we'd currently pay to claim an outgoing HTLC
+// on-chain, through the HTLC timeout transaction.
+func (b *feeSpikeBreaker) claimFee() btcutil.Amount {
+	feePerKw := btcutil.Amount(atomic.LoadInt64(&b.feePerKw))
+	return feePerKw * lnwallet.HtlcTimeoutWeight / 1000
+}
+
+// uneconomical returns true if an outgoing HTLC of the passed amount would
+// cost more to claim on-chain, scaled by the multiplier, than it's worth.
+func (b *feeSpikeBreaker) uneconomical(amt lnwire.MilliSatoshi) bool {
+	if !b.enabled() {
+		return false
+	}
+
+	claimFee := float64(b.claimFee()) * b.multiplier
+	return claimFee > float64(amt.ToSatoshis())
+}
+
+// recordDecline records that an HTLC of the passed amount has been declined
+// as uneconomical.
+func (b *feeSpikeBreaker) recordDecline(amt lnwire.MilliSatoshi,
+	now time.Time) {
+
+	atomic.AddUint64(&b.numDeclined, 1)
+	atomic.AddUint64(&b.declinedAmount, uint64(amt))
+	atomic.StoreInt64(&b.lastDecline, now.UnixNano())
+}
+
+// stats returns the statistics of the HTLCs declined by the breaker.
+func (b *feeSpikeBreaker) stats() FeeSpikeStats {
+	stats := FeeSpikeStats{
+		FeePerKw:    btcutil.Amount(atomic.LoadInt64(&b.feePerKw)),
+		ClaimFee:    b.claimFee(),
+		NumDeclined: atomic.LoadUint64(&b.numDeclined),
+		DeclinedAmount: lnwire.MilliSatoshi(
+			atomic.LoadUint64(&b.declinedAmount),
+		),
+	}
+	if lastDecline := atomic.LoadInt64(&b.lastDecline); lastDecline != 0 {
+		stats.LastDecline = time.Unix(0, lastDecline)
+	}
+
+	return stats
+}
+
+// FeeSpikeStats returns the statistics of the forwarded HTLCs the link has
+// declined to offer, as they were uneconomical to claim on-chain.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) FeeSpikeStats() FeeSpikeStats {
+	return l.feeSpike.stats()
+}
+
+// refreshFeeSpikeRate samples the current fee rate on the network, and
+// updates the rate at which the fee spike breaker assesses outgoing HTLCs. If
+// the rate can't be trusted, then the last one sampled is retained.
+func (l *channelLink) refreshFeeSpikeRate() {
+	if !l.feeSpike.enabled() {
+		return
+	}
+
+	if l.cfg.ChainHealthy != nil && !l.cfg.ChainHealthy() {
+		return
+	}
+
+	feePerKw, err := l.sampleNetworkFee()
+	if err != nil {
+		log.Errorf("ChannelPoint(%v): unable to sample network fee "+
+			"for fee spike breaker: %v", l.channel.ChannelPoint(),
+			err)
+		return
+	}
+
+	l.feeSpike.setFeeRate(feePerKw)
+}
@@ -0,0 +1,101 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestFeeSpikeBreaker tests that the feeSpikeBreaker considers HTLCs
+// uneconomical once their claim fee, scaled by the multiplier, exceeds their
+// value, and that it tracks the HTLCs it has declined.
+func TestFeeSpikeBreaker(t *testing.T) {
+	t.Parallel()
+
+	// At 10,000 sat/kw, claiming an HTLC through the HTLC timeout
+	// transaction costs 6,630 satoshis.
+	const feePerKw = 10000
+	const claimFee = 6630
+
+	tests := []struct {
+		name         string
+		multiplier   float64
+		feePerKw     int64
+		amt          lnwire.MilliSatoshi
+		uneconomical bool
+	}{
+		{
+			name:       "disabled",
+			multiplier: 0,
+			feePerKw:   feePerKw,
+			amt:        lnwire.NewMSatFromSatoshis(1),
+		},
+		{
+			name:       "no fee rate sampled",
+			multiplier: 1,
+			amt:        lnwire.NewMSatFromSatoshis(1),
+		},
+		{
+			name:       "value equals claim fee",
+			multiplier: 1,
+			feePerKw:   feePerKw,
+			amt:        lnwire.NewMSatFromSatoshis(claimFee),
+		},
+		{
+			name:         "value below claim fee",
+			multiplier:   1,
+			feePerKw:     feePerKw,
+			amt:          lnwire.NewMSatFromSatoshis(claimFee - 1),
+			uneconomical: true,
+		},
+		{
+			name:         "value below scaled claim fee",
+			multiplier:   2,
+			feePerKw:     feePerKw,
+			amt:          lnwire.NewMSatFromSatoshis(10000),
+			uneconomical: true,
+		},
+		{
+			name:       "value above scaled claim fee",
+			multiplier: 0.5,
+			feePerKw:   feePerKw,
+			amt:        lnwire.NewMSatFromSatoshis(4000),
+		},
+	}
+
+	for _, test := range tests {
+		breaker := newFeeSpikeBreaker(test.multiplier)
+		breaker.setFeeRate(btcutil.Amount(test.feePerKw))
+
+		uneconomical := breaker.uneconomical(test.amt)
+		if uneconomical != test.uneconomical {
+			t.Fatalf("%v: expected uneconomical=%v, got %v",
+				test.name, test.uneconomical, uneconomical)
+		}
+	}
+
+	// Declined HTLCs should be reflected within the breaker's statistics.
+	breaker := newFeeSpikeBreaker(1)
+	breaker.setFeeRate(feePerKw)
+
+	now := time.Unix(1000, 0)
+	breaker.recordDecline(1000, now.Add(-time.Minute))
+	breaker.recordDecline(2000, now)
+
+	stats := breaker.stats()
+	if stats.FeePerKw != feePerKw || stats.ClaimFee != claimFee {
+		t.Fatalf("expected fee rate of %v and claim fee of %v, got "+
+			"%v and %v", feePerKw, claimFee, stats.FeePerKw,
+			stats.ClaimFee)
+	}
+	if stats.NumDeclined != 2 || stats.DeclinedAmount != 3000 {
+		t.Fatalf("expected 2 declines worth 3000 msat, got %v "+
+			"worth %v", stats.NumDeclined, stats.DeclinedAmount)
+	}
+	if !stats.LastDecline.Equal(now) {
+		t.Fatalf("expected last decline at %v, got %v", now,
+			stats.LastDecline)
+	}
+}
@@ -0,0 +1,335 @@
+package htlcswitch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// FinalRecordsHop is the next hop set within the per-hop payload of the final
+// hop of a payment whose onion carries records destined to it, such as the
+// preimage of a keysend payment. It signals that all payloads following its
+// own are destined to it as well, and hold a TLV stream of such records
+// rather than forwarding instructions. Nodes which don't support these
+// records treat it as an unknown channel, failing the payment.
+//
+// NOTE: The fixed-size per-hop payloads of the onion have no room for
+// records, which is why they're carried within additional payloads. This
+// encoding is experimental and specific to lnd, so it isn't understood by
+// other implementations, and each additional payload counts towards the 20
+// hops the onion is able to encode.
+var FinalRecordsHop = lnwire.NewShortChanIDFromInt(math.MaxUint64)
+
+const (
+	// MppRecordType is the type of the record carrying the payment address
+	// and total amount of a payment split across multiple HTLCs, as
+	// assigned to it by BOLT #4.
+	MppRecordType uint64 = 8
+
+	// AmpRecordType is the type of the record carrying the share, set ID
+	// and child index of a part of an AMP payment, as assigned to AMP
+	// records by other implementations.
+	AmpRecordType uint64 = 14
+
+	// KeysendRecordType is the type of the record carrying the preimage
+	// of a keysend payment, as assigned to keysend records by other
+	// implementations.
+	KeysendRecordType uint64 = 5482373484
+
+	// StatelessRecordType is the type of the record carrying the
+	// StatelessRecord of a payment to a stateless invoice. It's an odd
+	// type within the range reserved for custom records, as stateless
+	// invoices are specific to lnd.
+	StatelessRecordType uint64 = 65537
+
+	// finalRecordsChunk is the number of bytes of the TLV stream of
+	// records held by each additional payload.
+	finalRecordsChunk = 32
+
+	// maxFinalPayloads is the maximum number of additional payloads
+	// carrying records, as the onion encodes at most 20 payloads, one of
+	// which is that of the final hop itself.
+	maxFinalPayloads = 19
+)
+
+// ErrFinalRecordsTooLarge is returned when the records destined to the final
+// hop of a payment don't fit within the onion.
+var ErrFinalRecordsTooLarge = errors.New("final hop records too large")
+
+// ErrAmpPaymentAddr is returned when records carrying a payment address are
+// also those of an AMP payment, whose set ID takes the place of the payment
+// address within the MPP record.
+var ErrAmpPaymentAddr = errors.New("AMP payments can't carry a payment " +
+	"address")
+
+// FinalHopRecords are the records carried within the onion of a payment which
+// are destined to its final hop.
+type FinalHopRecords struct {
+	// KeysendPreimage, if non-nil, is the preimage of a keysend payment,
+	// allowing the final hop to settle it without an invoice.
+	KeysendPreimage *[32]byte
+
+	// Amp, if non-nil, is the record of a part of an AMP payment. It's
+	// carried within an AMP record, while its total amount is carried
+	// within an accompanying MPP record.
+	Amp *AmpRecord
+
+	// Stateless, if non-nil, is the record of the stateless invoice being
+	// paid, from which the final hop reconstructs the invoice.
+	Stateless *StatelessRecord
+
+	// PaymentAddr, if non-nil, is the payment secret of the invoice being
+	// paid, proving to the final hop that the sender knows the invoice.
+	// It's carried within an MPP record, along with TotalAmt.
+	PaymentAddr *[32]byte
+
+	// TotalAmt is the total amount of the payment whose payment address is
+	// carried, as included within the MPP record. It's only set along
+	// with PaymentAddr.
+	TotalAmt lnwire.MilliSatoshi
+}
+
+// knownFinalRecords are the types of the records we understand within the
+// onion of a payment to us.
+var knownFinalRecords = map[uint64]struct{}{
+	MppRecordType:       {},
+	AmpRecordType:       {},
+	KeysendRecordType:   {},
+	StatelessRecordType: {},
+}
+
+// encode serializes the records into a TLV stream, prefixed by its length.
+func (r *FinalHopRecords) encode() []byte {
+	records := make(map[uint64][]byte)
+	if r.KeysendPreimage != nil {
+		records[KeysendRecordType] = r.KeysendPreimage[:]
+	}
+	if r.Amp != nil {
+		amp := make([]byte, 0, 68)
+		amp = append(amp, r.Amp.Share[:]...)
+		amp = append(amp, r.Amp.SetID[:]...)
+		amp = append(
+			amp, encodeTruncated(uint64(r.Amp.ChildIndex))...,
+		)
+		records[AmpRecordType] = amp
+
+		// As AMP payments aren't made to an invoice, there's no
+		// payment address to include within the MPP record, so the set
+		// ID of the payment takes its place.
+		mpp := make([]byte, 0, 40)
+		mpp = append(mpp, r.Amp.SetID[:]...)
+		mpp = append(mpp, encodeTruncated(uint64(r.Amp.TotalAmt))...)
+		records[MppRecordType] = mpp
+	}
+	if r.PaymentAddr != nil && r.Amp == nil {
+		mpp := make([]byte, 0, 40)
+		mpp = append(mpp, r.PaymentAddr[:]...)
+		mpp = append(mpp, encodeTruncated(uint64(r.TotalAmt))...)
+		records[MppRecordType] = mpp
+	}
+	if r.Stateless != nil {
+		stateless := r.Stateless.encode()
+		records[StatelessRecordType] = stateless[:]
+	}
+	stream := lnwire.EncodeTLVStream(records)
+
+	b := make([]byte, 2+len(stream))
+	binary.BigEndian.PutUint16(b[:2], uint16(len(stream)))
+	copy(b[2:], stream)
+
+	return b
+}
+
+// decodeFinalHopRecords deserializes the records from a length prefixed TLV
+// stream created by encode, followed by any amount of padding.
+func decodeFinalHopRecords(b []byte) (*FinalHopRecords, error) {
+	if len(b) < 2 {
+		return nil, fmt.Errorf("final hop records missing length")
+	}
+	length := int(binary.BigEndian.Uint16(b[:2]))
+	if length > len(b)-2 {
+		return nil, fmt.Errorf("final hop records of length %v "+
+			"exceed their payloads", length)
+	}
+
+	records, err := lnwire.DecodeTLVStream(
+		b[2:2+length], knownFinalRecords,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	finalRecords := &FinalHopRecords{}
+	if preimage, ok := records[KeysendRecordType]; ok {
+		if len(preimage) != 32 {
+			return nil, fmt.Errorf("invalid keysend record "+
+				"length: %v", len(preimage))
+		}
+		finalRecords.KeysendPreimage = &[32]byte{}
+		copy(finalRecords.KeysendPreimage[:], preimage)
+	}
+
+	if amp, ok := records[AmpRecordType]; ok {
+		if len(amp) < 64 {
+			return nil, fmt.Errorf("invalid AMP record length: %v",
+				len(amp))
+		}
+		childIndex, err := decodeTruncated(amp[64:], 4)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AMP child index: %v",
+				err)
+		}
+
+		// The total amount of the payment is carried within the MPP
+		// record, which must accompany the AMP record.
+		mpp, ok := records[MppRecordType]
+		if !ok {
+			return nil, fmt.Errorf("AMP record without MPP record")
+		}
+		if len(mpp) < 32 {
+			return nil, fmt.Errorf("invalid MPP record length: %v",
+				len(mpp))
+		}
+		totalAmt, err := decodeTruncated(mpp[32:], 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MPP total amount: %v",
+				err)
+		}
+
+		finalRecords.Amp = &AmpRecord{
+			ChildIndex: uint32(childIndex),
+			TotalAmt:   lnwire.MilliSatoshi(totalAmt),
+		}
+		copy(finalRecords.Amp.Share[:], amp[:32])
+		copy(finalRecords.Amp.SetID[:], amp[32:64])
+	}
+
+	// Otherwise, an MPP record carries the payment address of the invoice
+	// being paid.
+	if mpp, ok := records[MppRecordType]; ok && finalRecords.Amp == nil {
+		if len(mpp) < 32 {
+			return nil, fmt.Errorf("invalid MPP record length: %v",
+				len(mpp))
+		}
+		totalAmt, err := decodeTruncated(mpp[32:], 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MPP total amount: %v",
+				err)
+		}
+
+		finalRecords.PaymentAddr = &[32]byte{}
+		copy(finalRecords.PaymentAddr[:], mpp[:32])
+		finalRecords.TotalAmt = lnwire.MilliSatoshi(totalAmt)
+	}
+
+	if stateless, ok := records[StatelessRecordType]; ok {
+		if len(stateless) != statelessRecordLen {
+			return nil, fmt.Errorf("invalid stateless record "+
+				"length: %v", len(stateless))
+		}
+		var b [statelessRecordLen]byte
+		copy(b[:], stateless)
+		finalRecords.Stateless = decodeStatelessRecord(b)
+	}
+
+	return finalRecords, nil
+}
+
+// encodeTruncated encodes the passed integer in big-endian, omitting its
+// leading zero bytes, as for the truncated integers of BOLT #1.
+func encodeTruncated(v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+
+	i := 0
+	for i < len(b) && b[i] == 0 {
+		i++
+	}
+
+	return b[i:]
+}
+
+// decodeTruncated decodes a truncated integer of at most maxLen bytes created
+// by encodeTruncated. Encodings with leading zero bytes are rejected.
+func decodeTruncated(b []byte, maxLen int) (uint64, error) {
+	switch {
+	case len(b) > maxLen:
+		return 0, fmt.Errorf("truncated integer of %v bytes exceeds "+
+			"%v bytes", len(b), maxLen)
+	case len(b) > 0 && b[0] == 0:
+		return 0, fmt.Errorf("non-minimal truncated integer")
+	}
+
+	var v uint64
+	for _, byt := range b {
+		v = v<<8 | uint64(byt)
+	}
+
+	return v, nil
+}
+
+// NewFinalHopData returns the additional per-hop payloads carrying the passed
+// records, which follow the payload of the final hop of a payment, whose next
+// hop is to be set to FinalRecordsHop.
+func NewFinalHopData(records *FinalHopRecords) ([]sphinx.HopData, error) {
+	if records.Amp != nil && records.PaymentAddr != nil {
+		return nil, ErrAmpPaymentAddr
+	}
+
+	b := records.encode()
+
+	numPayloads := (len(b) + finalRecordsChunk - 1) / finalRecordsChunk
+	if numPayloads > maxFinalPayloads {
+		return nil, ErrFinalRecordsTooLarge
+	}
+
+	hopData := make([]sphinx.HopData, numPayloads)
+	for i := range hopData {
+		var chunk [finalRecordsChunk]byte
+		copy(chunk[:], b[i*finalRecordsChunk:])
+		hopData[i] = hopDataFromBytes(chunk)
+	}
+
+	return hopData, nil
+}
+
+// finalHopRecords extracts the records from the per-hop payloads created by
+// NewFinalHopData.
+func finalHopRecords(hopData []sphinx.HopData) (*FinalHopRecords, error) {
+	b := make([]byte, 0, len(hopData)*finalRecordsChunk)
+	for i := range hopData {
+		chunk := hopDataBytes(hopData[i])
+		b = append(b, chunk[:]...)
+	}
+
+	return decodeFinalHopRecords(b)
+}
+
+// hopDataFromBytes returns a per-hop payload whose next address, amount,
+// time-lock, and padding hold the passed 32 bytes, for payloads which carry
+// data destined to the final hop rather than forwarding instructions.
+func hopDataFromBytes(b [32]byte) sphinx.HopData {
+	var hopData sphinx.HopData
+	copy(hopData.NextAddress[:], b[:8])
+	hopData.ForwardAmount = binary.BigEndian.Uint64(b[8:16])
+	hopData.OutgoingCltv = binary.BigEndian.Uint32(b[16:20])
+	copy(hopData.ExtraBytes[:], b[20:])
+
+	return hopData
+}
+
+// hopDataBytes extracts the 32 bytes held by a per-hop payload created by
+// hopDataFromBytes.
+func hopDataBytes(hopData sphinx.HopData) [32]byte {
+	var b [32]byte
+	copy(b[:8], hopData.NextAddress[:])
+	binary.BigEndian.PutUint64(b[8:16], hopData.ForwardAmount)
+	binary.BigEndian.PutUint32(b[16:20], hopData.OutgoingCltv)
+	copy(b[20:], hopData.ExtraBytes[:])
+
+	return b
+}
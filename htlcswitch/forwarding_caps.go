@@ -0,0 +1,273 @@
+package htlcswitch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ForwardingCaps bounds the total value of the HTLCs the switch forwards
+// within a sliding window of an hour and of a day, both across the entire
+// node and for each peer. A value of zero disables the respective cap.
+type ForwardingCaps struct {
+	// Hourly is the maximum value forwarded by the node within an hour.
+	Hourly lnwire.MilliSatoshi
+
+	// Daily is the maximum value forwarded by the node within a day.
+	Daily lnwire.MilliSatoshi
+
+	// PeerHourly is the maximum value forwarded to or from a single peer
+	// within an hour.
+	PeerHourly lnwire.MilliSatoshi
+
+	// PeerDaily is the maximum value forwarded to or from a single peer
+	// within a day.
+	PeerDaily lnwire.MilliSatoshi
+}
+
+// enabled returns true if any of the caps are enabled.
+func (c ForwardingCaps) enabled() bool {
+	return c.Hourly != 0 || c.Daily != 0 || c.PeerHourly != 0 ||
+		c.PeerDaily != 0
+}
+
+// valueWindow tracks the value forwarded within the last day, aggregated by
+// the minute.
+type valueWindow struct {
+	// minutes maps the unix minute to the value forwarded within it.
+	minutes map[int64]lnwire.MilliSatoshi
+}
+
+// newValueWindow creates a new, empty valueWindow.
+func newValueWindow() *valueWindow {
+	return &valueWindow{
+		minutes: make(map[int64]lnwire.MilliSatoshi),
+	}
+}
+
+// sum returns the value forwarded within the passed number of minutes up to,
+// and including, the passed minute.
+func (w *valueWindow) sum(now int64, numMinutes int64) lnwire.MilliSatoshi {
+	var total lnwire.MilliSatoshi
+	for minute, amt := range w.minutes {
+		if minute > now-numMinutes {
+			total += amt
+		}
+	}
+
+	return total
+}
+
+// prune removes the value forwarded prior to the last day, returning true if
+// the window is now empty.
+func (w *valueWindow) prune(now int64) bool {
+	for minute := range w.minutes {
+		if minute <= now-minutesPerDay {
+			delete(w.minutes, minute)
+		}
+	}
+
+	return len(w.minutes) == 0
+}
+
+const (
+	// minutesPerHour is the length of the hourly window in minutes.
+	minutesPerHour = 60
+
+	// minutesPerDay is the length of the daily window in minutes.
+	minutesPerDay = 24 * minutesPerHour
+)
+
+// cappedForward is a forward that has been counted towards the caps, and
+// which will be refunded if the HTLC is failed.
+type cappedForward struct {
+	minute      int64
+	amt         lnwire.MilliSatoshi
+	source      [33]byte
+	destination [33]byte
+}
+
+// forwardingCapTracker enforces the ForwardingCaps. The value of each forward
+// is counted towards the caps of the node, and of both the source and
+// destination peers, once it has been handed to the outgoing link. If the
+// HTLC is later failed, then its value is refunded, so that failed forwards
+// don't eat into the caps. The windows aren't persisted, so they start afresh
+// on restart.
+//
+// NOTE: This isn't safe for concurrent access, it's only to be used from
+// within the switch's htlcForwarder goroutine.
+type forwardingCapTracker struct {
+	caps ForwardingCaps
+
+	node  *valueWindow
+	peers map[[33]byte]*valueWindow
+
+	// pending holds the forwards which have been counted towards the
+	// caps, but not yet resolved, keyed by their incoming HTLC.
+	pending map[incomingHTLC]cappedForward
+
+	// now returns the current time, and is overridden within tests.
+	now func() time.Time
+}
+
+// incomingHTLC identifies an HTLC by the channel it arrived on, and its index
+// within that channel.
+type incomingHTLC struct {
+	chanID lnwire.ShortChannelID
+	htlcID uint64
+}
+
+// newForwardingCapTracker creates a new tracker enforcing the passed caps.
+func newForwardingCapTracker(caps ForwardingCaps) *forwardingCapTracker {
+	return &forwardingCapTracker{
+		caps:    caps,
+		node:    newValueWindow(),
+		peers:   make(map[[33]byte]*valueWindow),
+		pending: make(map[incomingHTLC]cappedForward),
+		now:     time.Now,
+	}
+}
+
+// addForward counts the forward of the HTLC with the passed incoming channel
+// and index from the source peer to the destination peer towards the caps. If
+// the forward would exceed any of the caps, then a non-nil error describing
+// the cap is returned, and the forward isn't counted. A forward which is
+// retried over another link is only counted once.
+func (f *forwardingCapTracker) addForward(chanID lnwire.ShortChannelID,
+	htlcID uint64, amt lnwire.MilliSatoshi, source,
+	destination [33]byte) error {
+
+	if !f.caps.enabled() {
+		return nil
+	}
+
+	key := incomingHTLC{chanID, htlcID}
+	if _, ok := f.pending[key]; ok {
+		return nil
+	}
+
+	minute := f.now().Unix() / 60
+	f.prune(minute)
+
+	err := checkCaps(
+		f.node, minute, amt, f.caps.Hourly, f.caps.Daily, "node",
+	)
+	if err != nil {
+		return err
+	}
+	for _, peer := range uniquePeers(source, destination) {
+		window, ok := f.peers[peer]
+		if !ok {
+			window = newValueWindow()
+		}
+
+		err := checkCaps(
+			window, minute, amt, f.caps.PeerHourly,
+			f.caps.PeerDaily, fmt.Sprintf("peer %x", peer[:]),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	f.node.minutes[minute] += amt
+	for _, peer := range uniquePeers(source, destination) {
+		window, ok := f.peers[peer]
+		if !ok {
+			window = newValueWindow()
+			f.peers[peer] = window
+		}
+		window.minutes[minute] += amt
+	}
+
+	f.pending[key] = cappedForward{
+		minute:      minute,
+		amt:         amt,
+		source:      source,
+		destination: destination,
+	}
+
+	return nil
+}
+
+// resolveForward marks the forward of the HTLC with the passed incoming
+// channel and index as resolved. If the HTLC was failed, then its value is
+// refunded.
+func (f *forwardingCapTracker) resolveForward(chanID lnwire.ShortChannelID,
+	htlcID uint64, failed bool) {
+
+	key := incomingHTLC{chanID, htlcID}
+	fwd, ok := f.pending[key]
+	if !ok {
+		return
+	}
+	delete(f.pending, key)
+
+	if !failed {
+		return
+	}
+
+	refund := func(window *valueWindow) {
+		amt, ok := window.minutes[fwd.minute]
+		if !ok {
+			return
+		}
+		if amt <= fwd.amt {
+			delete(window.minutes, fwd.minute)
+			return
+		}
+		window.minutes[fwd.minute] = amt - fwd.amt
+	}
+
+	refund(f.node)
+	for _, peer := range uniquePeers(fwd.source, fwd.destination) {
+		if window, ok := f.peers[peer]; ok {
+			refund(window)
+		}
+	}
+}
+
+// prune removes the value forwarded prior to the last day from each window.
+func (f *forwardingCapTracker) prune(minute int64) {
+	f.node.prune(minute)
+	for peer, window := range f.peers {
+		if window.prune(minute) {
+			delete(f.peers, peer)
+		}
+	}
+}
+
+// checkCaps returns a non-nil error if forwarding the passed amount would
+// exceed either the hourly or the daily cap of the window.
+func checkCaps(window *valueWindow, minute int64, amt, hourly,
+	daily lnwire.MilliSatoshi, name string) error {
+
+	if hourly != 0 {
+		if used := window.sum(minute, minutesPerHour); used+amt > hourly {
+			return fmt.Errorf("forwarding %v would exceed the "+
+				"hourly cap of %v for %v, %v already forwarded",
+				amt, hourly, name, used)
+		}
+	}
+	if daily != 0 {
+		if used := window.sum(minute, minutesPerDay); used+amt > daily {
+			return fmt.Errorf("forwarding %v would exceed the "+
+				"daily cap of %v for %v, %v already forwarded",
+				amt, daily, name, used)
+		}
+	}
+
+	return nil
+}
+
+// uniquePeers returns the passed source and destination peers, without
+// duplicates, as a forward may leave via another channel with the peer it
+// arrived from.
+func uniquePeers(source, destination [33]byte) [][33]byte {
+	if source == destination {
+		return [][33]byte{source}
+	}
+
+	return [][33]byte{source, destination}
+}
@@ -0,0 +1,75 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestForwardingCapTracker ensures that forwards are rejected once they'd
+// exceed either the node-wide or the per-peer caps, that failed forwards are
+// refunded, and that the windows slide over time.
+func TestForwardingCapTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := newForwardingCapTracker(ForwardingCaps{
+		Hourly:     3000,
+		Daily:      5000,
+		PeerHourly: 2000,
+	})
+
+	now := time.Unix(1000000, 0)
+	tracker.now = func() time.Time {
+		return now
+	}
+
+	alice := [33]byte{1}
+	bob := [33]byte{2}
+	carol := [33]byte{3}
+	dave := [33]byte{4}
+	chanID := lnwire.NewShortChanIDFromInt(1)
+
+	assertForward := func(htlcID uint64, amt lnwire.MilliSatoshi,
+		source, destination [33]byte, allowed bool) {
+
+		err := tracker.addForward(chanID, htlcID, amt, source, destination)
+		if allowed && err != nil {
+			t.Fatalf("expected forward %v to be allowed: %v",
+				htlcID, err)
+		}
+		if !allowed && err == nil {
+			t.Fatalf("expected forward %v to be rejected", htlcID)
+		}
+	}
+
+	// Alice may forward up to her hourly cap to Bob.
+	assertForward(0, 1500, alice, bob, true)
+	assertForward(1, 1000, alice, bob, false)
+
+	// Retrying the same forward shouldn't count it twice.
+	assertForward(0, 1500, alice, bob, true)
+
+	// Carol and Dave aren't restricted by Alice's usage, but they are by
+	// the node's hourly cap.
+	assertForward(2, 1500, carol, dave, true)
+	assertForward(3, 100, carol, dave, false)
+
+	// Once Alice's forward fails, its value should be refunded.
+	tracker.resolveForward(chanID, 0, true)
+	assertForward(4, 1000, alice, bob, true)
+
+	// A settled forward isn't refunded.
+	tracker.resolveForward(chanID, 4, false)
+	assertForward(5, 600, carol, dave, false)
+
+	// After an hour, the hourly caps should have reset, but the daily cap
+	// still applies.
+	now = now.Add(time.Hour)
+	assertForward(6, 2000, alice, bob, true)
+	assertForward(7, 1000, carol, dave, false)
+
+	// After a day, all usage should have expired.
+	now = now.Add(24 * time.Hour)
+	assertForward(8, 2000, alice, bob, true)
+}
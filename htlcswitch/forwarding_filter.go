@@ -0,0 +1,113 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ForwardingFilter is a set of allow and deny lists which govern the peers
+// that the switch will forward HTLCs between. A forward is rejected if either
+// the peer it arrived from, or the peer it's destined to, is within the deny
+// list. Additionally, if the allow list is non-empty, then both peers must be
+// within it. Locally initiated payments aren't subject to the filter. The
+// filter may be updated at runtime, and is safe for concurrent access.
+type ForwardingFilter struct {
+	mu      sync.RWMutex
+	allowed map[[33]byte]struct{}
+	denied  map[[33]byte]struct{}
+}
+
+// NewForwardingFilter creates a new ForwardingFilter populated with the passed
+// allow and deny lists.
+func NewForwardingFilter(allowed, denied [][33]byte) *ForwardingFilter {
+	f := &ForwardingFilter{
+		allowed: make(map[[33]byte]struct{}),
+		denied:  make(map[[33]byte]struct{}),
+	}
+	for _, peer := range allowed {
+		f.allowed[peer] = struct{}{}
+	}
+	for _, peer := range denied {
+		f.denied[peer] = struct{}{}
+	}
+
+	return f
+}
+
+// Allow adds the peer to the allow list, removing it from the deny list if
+// present.
+func (f *ForwardingFilter) Allow(peer [33]byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.denied, peer)
+	f.allowed[peer] = struct{}{}
+}
+
+// Deny adds the peer to the deny list, removing it from the allow list if
+// present.
+func (f *ForwardingFilter) Deny(peer [33]byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.allowed, peer)
+	f.denied[peer] = struct{}{}
+}
+
+// Remove removes the peer from both the allow and deny lists.
+func (f *ForwardingFilter) Remove(peer [33]byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.allowed, peer)
+	delete(f.denied, peer)
+}
+
+// Lists returns the current allow and deny lists, each sorted
+// lexicographically.
+func (f *ForwardingFilter) Lists() ([][33]byte, [][33]byte) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return sortedPeers(f.allowed), sortedPeers(f.denied)
+}
+
+// sortedPeers returns the keys of the passed set in lexicographical order.
+func sortedPeers(set map[[33]byte]struct{}) [][33]byte {
+	peers := make([][33]byte, 0, len(set))
+	for peer := range set {
+		peers = append(peers, peer)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return bytes.Compare(peers[i][:], peers[j][:]) < 0
+	})
+
+	return peers
+}
+
+// checkForward returns a non-nil error describing the reason for the
+// rejection if an HTLC isn't permitted to be forwarded from the source peer
+// to the destination peer.
+func (f *ForwardingFilter) checkForward(source, destination [33]byte) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, peer := range [][33]byte{source, destination} {
+		if _, ok := f.denied[peer]; ok {
+			return fmt.Errorf("peer %x is within the forwarding "+
+				"deny list", peer[:])
+		}
+
+		if len(f.allowed) == 0 {
+			continue
+		}
+		if _, ok := f.allowed[peer]; !ok {
+			return fmt.Errorf("peer %x isn't within the "+
+				"forwarding allow list", peer[:])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package htlcswitch
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// fwdEventFlushInterval is the interval at which the forwarding events
+// buffered by the switch are written to its ForwardingLog.
+const fwdEventFlushInterval = 15 * time.Second
+
+// ForwardingLog is an interface which represents a persistent log to which
+// the switch records every HTLC it successfully forwards.
+type ForwardingLog interface {
+	// AddForwardingEvents adds the passed events to the log.
+	AddForwardingEvents([]channeldb.ForwardingEvent) error
+}
+
+// recordForward buffers a forwarding event for the settled HTLC forwarded
+// across the passed circuit, if a ForwardingLog is configured. Buffered
+// events are periodically flushed to the log, so that the forwarding path
+// isn't blocked on a database write.
+//
+// NOTE: This MUST only be called from within the htlcForwarder goroutine.
+func (s *Switch) recordForward(circuit *PaymentCircuit) {
+	if s.cfg.FwdingLog == nil || !circuit.isForwarded() {
+		return
+	}
+
+	event := channeldb.ForwardingEvent{
+		Timestamp:      time.Now(),
+		IncomingChanID: circuit.IncomingChanID,
+		OutgoingChanID: circuit.OutgoingChanID,
+		AmtIn:          circuit.IncomingAmount,
+		AmtOut:         circuit.OutgoingAmount,
+	}
+	s.pendingFwdEvents = append(s.pendingFwdEvents, event)
+}
+
+// flushForwardingEvents writes all buffered forwarding events to the
+// ForwardingLog. If the write fails, the events are kept, and retried with
+// the next flush.
+//
+// NOTE: This MUST only be called from within the htlcForwarder goroutine.
+func (s *Switch) flushForwardingEvents() {
+	if len(s.pendingFwdEvents) == 0 {
+		return
+	}
+
+	err := s.cfg.FwdingLog.AddForwardingEvents(s.pendingFwdEvents)
+	if err != nil {
+		log.Errorf("Unable to flush %v forwarding events: %v",
+			len(s.pendingFwdEvents), err)
+		return
+	}
+
+	log.Debugf("Flushed %v forwarding events", len(s.pendingFwdEvents))
+	s.pendingFwdEvents = nil
+}
@@ -0,0 +1,142 @@
+package htlcswitch
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// maxStuckHtlcCheckInterval is the maximum interval between two consecutive
+// checks for stuck outgoing HTLCs.
+const maxStuckHtlcCheckInterval = time.Minute
+
+// StuckHTLC describes an outgoing HTLC which has remained unresolved for
+// longer than the link's configured threshold.
+type StuckHTLC struct {
+	// ChanPoint is the channel point of the channel the HTLC was offered
+	// over.
+	ChanPoint wire.OutPoint
+
+	// HtlcIndex is the index of the HTLC within our local update log.
+	HtlcIndex uint64
+
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// Amount is the value of the HTLC.
+	Amount lnwire.MilliSatoshi
+
+	// Expiry is the absolute timeout of the HTLC.
+	Expiry uint32
+
+	// Age is the amount of time the HTLC has been unresolved for.
+	Age time.Duration
+}
+
+// outgoingHtlc tracks an outgoing HTLC that hasn't yet been resolved by the
+// remote party.
+type outgoingHtlc struct {
+	htlc *lnwire.UpdateAddHTLC
+
+	// addedAt is the time the HTLC was offered to the remote party, or
+	// the time the link was started if the HTLC predates the link.
+	addedAt time.Time
+
+	// reported is true if the HTLC has already been reported as stuck.
+	reported bool
+}
+
+// stuckHtlcCheckInterval returns the interval at which the link should check
+// for stuck outgoing HTLCs.
+func (l *channelLink) stuckHtlcCheckInterval() time.Duration {
+	if l.cfg.StuckHTLCThreshold < maxStuckHtlcCheckInterval {
+		return l.cfg.StuckHTLCThreshold
+	}
+
+	return maxStuckHtlcCheckInterval
+}
+
+// trackOutgoingHtlc starts tracking the age of an outgoing HTLC with the
+// given index.
+func (l *channelLink) trackOutgoingHtlc(index uint64, htlc *lnwire.UpdateAddHTLC,
+	addedAt time.Time) {
+
+	l.htlcAgeMtx.Lock()
+	l.outgoingHtlcs[index] = &outgoingHtlc{
+		htlc:    htlc,
+		addedAt: addedAt,
+	}
+	l.htlcAgeMtx.Unlock()
+}
+
+// resolveOutgoingHtlc stops tracking the age of the outgoing HTLC with the
+// given index, as it has been settled or failed by the remote party, and
+// accounts for its resolution within the HTLC statistics of the channel.
+func (l *channelLink) resolveOutgoingHtlc(index uint64, settled bool) {
+	l.htlcAgeMtx.Lock()
+	h, ok := l.outgoingHtlcs[index]
+	delete(l.outgoingHtlcs, index)
+	l.htlcAgeMtx.Unlock()
+
+	if ok {
+		l.recordHtlcResolution(h, settled, time.Now())
+	}
+}
+
+// checkStuckHtlcs reports all outgoing HTLCs that have been unresolved for
+// longer than the configured threshold, and haven't yet been reported.
+func (l *channelLink) checkStuckHtlcs() {
+	now := time.Now()
+
+	var stuck []StuckHTLC
+	l.htlcAgeMtx.Lock()
+	for index, h := range l.outgoingHtlcs {
+		age := now.Sub(h.addedAt)
+		if h.reported || age < l.cfg.StuckHTLCThreshold {
+			continue
+		}
+
+		h.reported = true
+		stuck = append(stuck, StuckHTLC{
+			ChanPoint:   *l.channel.ChannelPoint(),
+			HtlcIndex:   index,
+			PaymentHash: h.htlc.PaymentHash,
+			Amount:      h.htlc.Amount,
+			Expiry:      h.htlc.Expiry,
+			Age:         age,
+		})
+	}
+	l.htlcAgeMtx.Unlock()
+
+	for _, htlc := range stuck {
+		log.Warnf("ChannelPoint(%v): outgoing HTLC with index=%v, "+
+			"payment_hash=%x, amt=%v, expiry=%v has been "+
+			"unresolved for %v", htlc.ChanPoint, htlc.HtlcIndex,
+			htlc.PaymentHash[:], htlc.Amount, htlc.Expiry,
+			htlc.Age)
+
+		if l.cfg.NotifyStuckHTLC != nil {
+			l.cfg.NotifyStuckHTLC(htlc)
+		}
+	}
+}
+
+// OutgoingHtlcAges returns the amount of time each of the unresolved outgoing
+// HTLCs of the link has been pending for, keyed by the index of the HTLC within
+// our local update log.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) OutgoingHtlcAges() map[uint64]time.Duration {
+	now := time.Now()
+
+	l.htlcAgeMtx.Lock()
+	defer l.htlcAgeMtx.Unlock()
+
+	ages := make(map[uint64]time.Duration, len(l.outgoingHtlcs))
+	for index, h := range l.outgoingHtlcs {
+		ages[index] = now.Sub(h.addedAt)
+	}
+
+	return ages
+}
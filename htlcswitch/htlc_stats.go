@@ -0,0 +1,55 @@
+package htlcswitch
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// htlcStatsInterval is the interval at which the link records the HTLC
+// statistics of its channel.
+const htlcStatsInterval = 10 * time.Minute
+
+// recordHtlcResolution accounts for the resolution of an outgoing HTLC by the
+// remote party within the HTLC statistics of the channel. As the age of an
+// HTLC which predates the link is tracked from the time the link was started,
+// its settle latency is only a lower bound.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) recordHtlcResolution(h *outgoingHtlc, settled bool,
+	now time.Time) {
+
+	if !settled {
+		l.htlcStats.NumFailed++
+		return
+	}
+
+	latency := now.Sub(h.addedAt)
+	l.htlcStats.NumSettled++
+	l.htlcStats.TotalSettleLatency += latency
+	l.cfg.Switch.metrics.settledHTLC(latency)
+}
+
+// recordHTLCStats records the HTLC statistics accumulated since they were
+// last recorded, accounting for the time elapsed since then as uptime of the
+// channel. If they can't be recorded, then they're retained until the next
+// attempt.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) recordHTLCStats(now time.Time) {
+	if l.cfg.RecordHTLCStats == nil {
+		return
+	}
+
+	stats := l.htlcStats
+	stats.Uptime = now.Sub(l.htlcStatsSince)
+
+	if err := l.cfg.RecordHTLCStats(&stats); err != nil {
+		log.Errorf("ChannelPoint(%v): unable to record htlc stats: %v",
+			l.channel.ChannelPoint(), err)
+		return
+	}
+
+	l.htlcStats = channeldb.ChannelHTLCStats{}
+	l.htlcStatsSince = now
+}
@@ -0,0 +1,154 @@
+package htlcswitch
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrMaxValueInFlight is returned when an HTLC can't be added to a channel,
+// as it would exceed the maximum total value of unresolved HTLCs the receiving
+// party permits.
+var ErrMaxValueInFlight = errors.New("max value in flight exceeded")
+
+// inFlightHeadroom returns the additional value of outgoing HTLCs that the
+// remote party permits us to add to the channel, along with the additional
+// value of incoming HTLCs we permit the remote party to add. A maximum value
+// in flight of zero is treated as unlimited, in which case the headroom is
+// bounded only by the channel's capacity.
+func inFlightHeadroom(maxValue, inFlight,
+	capacity lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	if maxValue == 0 || maxValue > capacity {
+		maxValue = capacity
+	}
+	if inFlight >= maxValue {
+		return 0
+	}
+
+	return maxValue - inFlight
+}
+
+// InFlightHeadroom returns the additional value of outgoing HTLCs that the
+// remote party permits us to add to the link's channel, along with the
+// additional value of incoming HTLCs we permit the remote party to add.
+func (l *channelLink) InFlightHeadroom() (lnwire.MilliSatoshi,
+	lnwire.MilliSatoshi) {
+
+	localMax, remoteMax := l.channel.MaxValueInFlight()
+	outgoing, incoming := l.channel.InFlightValues()
+	capacity := lnwire.NewMSatFromSatoshis(l.channel.Capacity)
+
+	return inFlightHeadroom(localMax, outgoing, capacity),
+		inFlightHeadroom(remoteMax, incoming, capacity)
+}
+
+// checkOutgoingInFlight returns ErrMaxValueInFlight if offering the passed
+// HTLC would exceed the maximum value in flight the remote party permits us.
+func (l *channelLink) checkOutgoingInFlight(htlc *lnwire.UpdateAddHTLC) error {
+	headroom, _ := l.InFlightHeadroom()
+	if htlc.Amount > headroom {
+		log.Debugf("ChannelPoint(%v): rejecting htlc with "+
+			"payment_hash=%x, amt=%v exceeds in-flight headroom "+
+			"of %v", l.channel.ChannelPoint(), htlc.PaymentHash[:],
+			htlc.Amount, headroom)
+
+		return ErrMaxValueInFlight
+	}
+
+	return nil
+}
+
+// checkIncomingInFlight returns ErrMaxValueInFlight if the incoming HTLCs
+// within the channel, including any the remote party has just added, exceed
+// the maximum value in flight we permit the remote party.
+func (l *channelLink) checkIncomingInFlight() error {
+	_, remoteMax := l.channel.MaxValueInFlight()
+	if remoteMax == 0 {
+		return nil
+	}
+
+	_, incoming := l.channel.InFlightValues()
+	if incoming > remoteMax {
+		return ErrMaxValueInFlight
+	}
+
+	return nil
+}
+
+// ErrLinkHtlcLimit is returned when an HTLC can't be offered over a link, as
+// it would exceed the MaxAcceptedHtlcs or MaxPendingAmount of the link.
+var ErrLinkHtlcLimit = errors.New("link htlc limit exceeded")
+
+// exceedsHtlcLimits returns true if the passed number and total value of
+// unresolved HTLCs exceed the MaxAcceptedHtlcs or MaxPendingAmount of the
+// link.
+func (l *channelLink) exceedsHtlcLimits(numHtlcs int,
+	value lnwire.MilliSatoshi) bool {
+
+	maxHtlcs := l.cfg.MaxAcceptedHtlcs
+	if maxHtlcs != 0 && numHtlcs > int(maxHtlcs) {
+		return true
+	}
+
+	maxAmt := l.cfg.MaxPendingAmount
+	return maxAmt != 0 && value > maxAmt
+}
+
+// checkOutgoingLimits returns ErrLinkHtlcLimit if offering the passed HTLC
+// would exceed the MaxAcceptedHtlcs or MaxPendingAmount of the link.
+func (l *channelLink) checkOutgoingLimits(htlc *lnwire.UpdateAddHTLC) error {
+	numHtlcs, _ := l.channel.NumInFlightHtlcs()
+	value, _ := l.channel.InFlightValues()
+	if l.exceedsHtlcLimits(numHtlcs+1, value+htlc.Amount) {
+		log.Debugf("ChannelPoint(%v): rejecting htlc with "+
+			"payment_hash=%x, amt=%v exceeds link limits: "+
+			"num_htlcs=%v, value_in_flight=%v",
+			l.channel.ChannelPoint(), htlc.PaymentHash[:],
+			htlc.Amount, numHtlcs, value)
+
+		return ErrLinkHtlcLimit
+	}
+
+	return nil
+}
+
+// checkIncomingLimits marks the incoming HTLC with the given index to be
+// failed back once locked in, if the incoming HTLCs within the channel,
+// including it, exceed the MaxAcceptedHtlcs or MaxPendingAmount of the link.
+// As the remote party may add the HTLC regardless of our limits, it can't be
+// rejected outright without failing the link.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) checkIncomingLimits(index uint64,
+	htlc *lnwire.UpdateAddHTLC) {
+
+	if l.cfg.MaxAcceptedHtlcs == 0 && l.cfg.MaxPendingAmount == 0 {
+		return
+	}
+
+	_, numHtlcs := l.channel.NumInFlightHtlcs()
+	_, value := l.channel.InFlightValues()
+	if !l.exceedsHtlcLimits(numHtlcs, value) {
+		return
+	}
+
+	log.Debugf("ChannelPoint(%v): incoming htlc with payment_hash=%x, "+
+		"amt=%v exceeds link limits, will fail back: num_htlcs=%v, "+
+		"value_in_flight=%v", l.channel.ChannelPoint(),
+		htlc.PaymentHash[:], htlc.Amount, numHtlcs, value)
+
+	l.overLimitHtlcs[index] = struct{}{}
+}
+
+// consumeOverLimitHtlc returns true if the incoming HTLC with the given index
+// exceeded the HTLC limits of the link when it was received, forgetting it
+// afterwards.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) consumeOverLimitHtlc(index uint64) bool {
+	_, ok := l.overLimitHtlcs[index]
+	delete(l.overLimitHtlcs, index)
+
+	return ok
+}
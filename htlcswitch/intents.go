@@ -0,0 +1,121 @@
+package htlcswitch
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// sendUpdate persists an intent to send the passed HTLC update before sending
+// it to the remote peer. If we crash after the update has been sent, but
+// before a commitment covering it has been signed, then the intent allows us
+// to re-apply and retransmit the update once the channel has been
+// re-established, rather than relying solely on the commitment heights
+// reported by the remote party.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) sendUpdate(msg lnwire.Message) {
+	if err := l.channel.State().AppendOutgoingIntent(msg); err != nil {
+		log.Errorf("ChannelPoint(%v): unable to persist intent to "+
+			"send %T: %v", l.channel.ChannelPoint(), msg, err)
+	}
+
+	l.recordResolution(msg)
+	l.cfg.Peer.SendMessage(msg)
+}
+
+// clearOutgoingIntents removes the persisted intents to send updates, as
+// they've been covered by a signed commitment, or acted upon after
+// re-establishing the channel.
+func (l *channelLink) clearOutgoingIntents() {
+	if err := l.channel.State().ClearOutgoingIntents(); err != nil {
+		log.Errorf("ChannelPoint(%v): unable to clear outgoing "+
+			"intents: %v", l.channel.ChannelPoint(), err)
+	}
+}
+
+// replayOutgoingIntents acts upon any intents to send updates that weren't
+// covered by a signed commitment before we last went down. As neither party
+// retains updates not covered by a commitment across a reconnection, such
+// updates must be re-applied in order for them not to be lost. The settles
+// and fails of incoming HTLCs which are still active within the channel are
+// re-applied and retransmitted. HTLCs the channel's update log already holds
+// a resolution for, such as those retransmitted as part of a signed
+// commitment, are skipped. Adds aren't re-applied, as their circuits were
+// trimmed when the link started, failing back the HTLCs they were forwarded
+// for.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine, after the
+// channel states have been synchronized.
+func (l *channelLink) replayOutgoingIntents() error {
+	intents, err := l.channel.State().OutgoingIntents()
+	if err != nil {
+		return err
+	}
+	if len(intents) == 0 {
+		return nil
+	}
+
+	// We'll only re-apply resolutions of incoming HTLCs that are still
+	// active, as those that aren't have either already been resolved
+	// within a signed commitment, or have been removed otherwise.
+	activeHtlcs := make(map[uint64]struct{})
+	for _, htlc := range l.channel.ActiveHtlcs() {
+		if !htlc.Incoming {
+			continue
+		}
+		if l.channel.IncomingHtlcResolved(htlc.HtlcIndex) {
+			continue
+		}
+
+		activeHtlcs[htlc.HtlcIndex] = struct{}{}
+	}
+
+	for _, intent := range intents {
+		var updateErr error
+		switch msg := intent.(type) {
+		case *lnwire.UpdateAddHTLC:
+			log.Warnf("ChannelPoint(%v): dropping unsigned htlc add "+
+				"with payment_hash=%x sent before restart",
+				l.channel.ChannelPoint(), msg.PaymentHash[:])
+			continue
+
+		case *lnwire.UpdateFufillHTLC:
+			if _, ok := activeHtlcs[msg.ID]; !ok {
+				continue
+			}
+
+			updateErr = l.channel.SettleHTLC(msg.PaymentPreimage, msg.ID)
+
+		case *lnwire.UpdateFailHTLC:
+			if _, ok := activeHtlcs[msg.ID]; !ok {
+				continue
+			}
+
+			updateErr = l.channel.FailHTLC(msg.ID, msg.Reason)
+
+		case *lnwire.UpdateFailMalformedHTLC:
+			if _, ok := activeHtlcs[msg.ID]; !ok {
+				continue
+			}
+
+			updateErr = l.channel.MalformedFailHTLC(
+				msg.ID, msg.FailureCode, msg.ShaOnionBlob,
+			)
+
+		default:
+			continue
+		}
+		if updateErr != nil {
+			return updateErr
+		}
+
+		log.Infof("ChannelPoint(%v): retransmitting %T for htlc "+
+			"recorded as an outgoing intent before restart",
+			l.channel.ChannelPoint(), intent)
+
+		l.batchCounter++
+		l.recordResolution(intent)
+		l.cfg.Peer.SendMessage(intent)
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package htlcswitch
 
 import (
+	"time"
+
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
@@ -14,9 +16,44 @@ type InvoiceDatabase interface {
 	// byte payment hash.
 	LookupInvoice(chainhash.Hash) (channeldb.Invoice, error)
 
+	// AcceptInvoice records the passed HTLC as accepted, but held, within
+	// the HTLC set of the invoice corresponding to the passed payment
+	// hash.
+	AcceptInvoice(chainhash.Hash, channeldb.InvoiceHTLC) error
+
 	// SettleInvoice attempts to mark an invoice corresponding to the
-	// passed payment hash as fully settled.
-	SettleInvoice(chainhash.Hash) error
+	// passed payment hash as fully settled by the passed HTLC.
+	SettleInvoice(chainhash.Hash, channeldb.InvoiceHTLC) error
+
+	// SettleStatelessInvoice marks the passed stateless invoice, which
+	// was reconstructed from the onion of the passed HTLC rather than
+	// looked up, as settled by the HTLC. As such invoices aren't stored,
+	// the settlement is only relayed to invoice subscribers.
+	SettleStatelessInvoice(*channeldb.Invoice, channeldb.InvoiceHTLC) error
+
+	// AddInvoice adds the passed invoice to the database. It's used to
+	// record keysend payments, which are made without an invoice.
+	AddInvoice(*channeldb.Invoice) error
+
+	// AddAmpPart registers the passed HTLC amount, paying to the hold
+	// invoice corresponding to the passed payment hash, as a part of the
+	// AMP payment described by the passed record. Once the parts of the
+	// payment add up to its total amount, the invoices of its parts are
+	// settled with the preimages derived from their shares.
+	AddAmpPart(chainhash.Hash, lnwire.MilliSatoshi, *AmpRecord) error
+
+	// AwaitHoldInvoice blocks until the hold invoice corresponding to the
+	// passed payment hash is either settled or canceled, then returns the
+	// invoice. An error is returned if the passed quit channel is closed
+	// in the meantime.
+	AwaitHoldInvoice(chainhash.Hash,
+		<-chan struct{}) (channeldb.Invoice, error)
+
+	// SettleBackpressure returns a non-nil channel if the database is
+	// saturated with invoice settles. The channel is closed once further
+	// settles may be made, and until then, HTLCs paying to our invoices
+	// should be held rather than settled.
+	SettleBackpressure() <-chan struct{}
 }
 
 // ChannelLink is an interface which represents the subsystem for managing the
@@ -38,7 +75,11 @@ type InvoiceDatabase interface {
 //       |
 //
 type ChannelLink interface {
-	// TODO(roasbeef): modify interface to embed mail boxes?
+	// AttachMailBox sets the mailbox through which the link receives the
+	// messages of the remote peer, and the packets of the switch. The
+	// mailbox is owned by the switch, which attaches it before starting
+	// the link, and retains it across restarts of the link.
+	AttachMailBox(*memoryMailBox)
 
 	// HandleSwitchPacket handles the switch packets. This packets might be
 	// forwarded to us from another channel link in case the htlc update
@@ -60,6 +101,10 @@ type ChannelLink interface {
 	// is a more compact representation of a channel's full outpoint.
 	ChanID() lnwire.ChannelID
 
+	// ChannelPoint returns the funding outpoint of the channel the link
+	// operates over.
+	ChannelPoint() *wire.OutPoint
+
 	// ShortChanID returns the short channel ID for the channel link. The
 	// short channel ID encodes the exact location in the main chain that
 	// the original funding output can be found.
@@ -70,6 +115,9 @@ type ChannelLink interface {
 	// policy to govern if it an incoming HTLC should be forwarded or not.
 	UpdateForwardingPolicy(ForwardingPolicy)
 
+	// ForwardingPolicy returns the current forwarding policy of the link.
+	ForwardingPolicy() ForwardingPolicy
+
 	// Bandwidth returns the amount of milli-satoshis which current link
 	// might pass through channel link. The value returned from this method
 	// represents the up to date available flow through the channel. This
@@ -77,6 +125,12 @@ type ChannelLink interface {
 	// HTLC's which have been set to the over flow queue.
 	Bandwidth() lnwire.MilliSatoshi
 
+	// ReceivableBandwidth returns the amount of milli-satoshis which the
+	// remote party might send to us through the channel link. Like
+	// Bandwidth, it takes into account any un-cleared HTLC's, and the
+	// reserve the remote party must maintain.
+	ReceivableBandwidth() lnwire.MilliSatoshi
+
 	// Stats return the statistics of channel link. Number of updates,
 	// total sent/received milli-satoshis.
 	Stats() (uint64, lnwire.MilliSatoshi, lnwire.MilliSatoshi)
@@ -92,6 +146,44 @@ type ChannelLink interface {
 	// will use this function in forwarding decisions accordingly.
 	EligibleToForward() bool
 
+	// OutgoingHtlcAges returns the amount of time each of the unresolved
+	// outgoing HTLCs of the link has been pending for, keyed by the index
+	// of the HTLC within our local update log.
+	OutgoingHtlcAges() map[uint64]time.Duration
+
+	// LinkSnapshot returns a consistent snapshot of the current state of
+	// the link, assembled with a single request to the link. Callers that
+	// need more than a single attribute of the link should prefer this
+	// method over querying each attribute individually.
+	LinkSnapshot() (*LinkSnapshot, error)
+
+	// AdmissionStats returns the statistics of the link's overflow queue,
+	// which holds the outgoing HTLCs that overflow the channel's
+	// commitment transaction, and of the admission of HTLCs to it. Once
+	// its budget is exhausted, new HTLCs are failed with
+	// ErrInsufficientCapacity.
+	AdmissionStats() AdmissionStats
+
+	// FeeSpikeStats returns the statistics of the forwarded HTLCs the
+	// link has declined to offer, as claiming them on-chain at the
+	// prevailing fee rate would cost more than they're worth.
+	FeeSpikeStats() FeeSpikeStats
+
+	// ShutdownIfChannelClean places the link into flush mode, in which it
+	// no longer accepts new HTLCs, while it continues to process the
+	// settles and fails of the HTLCs already in flight. It blocks until
+	// the channel has no HTLCs or pending updates remaining, then stops
+	// the link, such that a cooperative close of the channel can be
+	// negotiated without racing any in-flight HTLCs.
+	ShutdownIfChannelClean() error
+
+	// InitStfu requests that the channel be brought into a quiescent
+	// state, in which neither party initiates any further updates, as
+	// required by operations such as splicing. The result is delivered
+	// over the returned channel once both parties have exchanged Stfu
+	// messages, or the link exits.
+	InitStfu() <-chan error
+
 	// Start/Stop are used to initiate the start/stop of the channel link
 	// functioning.
 	Start() error
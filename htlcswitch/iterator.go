@@ -1,11 +1,13 @@
 package htlcswitch
 
 import (
+	"crypto/sha256"
 	"encoding/binary"
 	"io"
 
 	"github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
 )
 
 // NetworkHop indicates the blockchain network that is intended to be the next
@@ -65,6 +67,28 @@ type ForwardingInfo struct {
 	// in the outgoing HTLC.
 	OutgoingCTLV uint32
 
+	// KeysendPreimage is the preimage included within the onion by the
+	// sender of a keysend payment, allowing us to settle the HTLC without
+	// an invoice. It's only set for the final hop of such a payment.
+	KeysendPreimage *[32]byte
+
+	// Amp is the record included within the onion by the sender of an AMP
+	// payment, from which the preimage of the HTLC is derived once all
+	// parts of the payment have arrived. It's only set for the final hop
+	// of such a payment.
+	Amp *AmpRecord
+
+	// Stateless is the record included within the onion by the sender of
+	// a payment to a stateless invoice, from which the invoice is
+	// reconstructed. It's only set for the final hop of such a payment.
+	Stateless *StatelessRecord
+
+	// PaymentAddr is the payment secret of the invoice being paid, which
+	// the sender included within the onion, proving that it knows the
+	// invoice. It's only set for the final hop of a payment, if the
+	// sender included it.
+	PaymentAddr *[32]byte
+
 	// TODO(roasbeef): modify sphinx logic to not just discard the
 	// remaining bytes, instead should include the rest as excess
 }
@@ -98,6 +122,11 @@ type sphinxHopIterator struct {
 	// includes the information required to properly forward the packet to
 	// the next hop.
 	processedPacket *sphinx.ProcessedPacket
+
+	// finalRecords are the records carried by the payloads following
+	// ours, if the packet is that of a payment to us carrying records
+	// such as the preimage of a keysend payment.
+	finalRecords *FinalHopRecords
 }
 
 // A compile time check to ensure sphinxHopIterator implements the HopIterator
@@ -129,38 +158,140 @@ func (r *sphinxHopIterator) ForwardingInstructions() ForwardingInfo {
 		nextHop = lnwire.NewShortChanIDFromInt(s)
 	}
 
-	return ForwardingInfo{
+	// The record payloads following ours are destined to us as well, so
+	// we're the final hop of the payment.
+	if r.finalRecords != nil {
+		nextHop = exitHop
+	}
+
+	fwdInfo := ForwardingInfo{
 		Network:         BitcoinHop,
 		NextHop:         nextHop,
 		AmountToForward: lnwire.MilliSatoshi(fwdInst.ForwardAmount),
 		OutgoingCTLV:    fwdInst.OutgoingCltv,
 	}
+	if r.finalRecords != nil {
+		fwdInfo.KeysendPreimage = r.finalRecords.KeysendPreimage
+		fwdInfo.Amp = r.finalRecords.Amp
+		fwdInfo.Stateless = r.finalRecords.Stateless
+		fwdInfo.PaymentAddr = r.finalRecords.PaymentAddr
+	}
+
+	return fwdInfo
+}
+
+// DecodeHopIteratorRequest describes an onion packet to be decoded as part of
+// a batch.
+type DecodeHopIteratorRequest struct {
+	// OnionReader is the reader from which the onion packet is read.
+	OnionReader io.Reader
+
+	// RHash is the payment hash of the HTLC carrying the packet, which is
+	// authenticated by the packet as associated data.
+	RHash []byte
+
+	// IncomingCltv is the expiry height of the HTLC carrying the packet.
+	IncomingCltv uint32
+}
+
+// DecodeHopIteratorResponse is the outcome of decoding an onion packet as
+// part of a batch. If the packet couldn't be decoded, then FailCode details
+// why, and HopIterator is nil.
+type DecodeHopIteratorResponse struct {
+	// HopIterator is the hop iterator of the decoded packet.
+	HopIterator HopIterator
+
+	// FailCode is the failure code to fail the HTLC back with, or
+	// CodeNone if the packet was decoded successfully.
+	FailCode lnwire.FailCode
+}
+
+// OnionProcessor is an interface which abstracts away the onion routing
+// construction used to route HTLCs, allowing alternative constructions to be
+// used by the links, e.g. for testing or research purposes. A processor
+// decodes the onion packets carried by incoming HTLCs into hop iterators,
+// detects packets which are being replayed, and derives the error encrypters
+// used to fail HTLCs back to their sender.
+type OnionProcessor interface {
+	// SupportedVersions returns the versions of the onion packets the
+	// processor is able to decode. The version of a packet is given by its
+	// first byte.
+	SupportedVersions() []byte
+
+	// DecodeHopIterator decodes the onion packet read from the passed
+	// reader, authenticating the passed payment hash as associated data.
+	// As the packet isn't part of a batch, it isn't checked for replays.
+	DecodeHopIterator(r io.Reader, rHash []byte) (HopIterator,
+		lnwire.FailCode)
+
+	// DecodeHopIterators decodes the onion packets of a batch of HTLCs,
+	// which have been locked in at the passed height. Each packet that was
+	// already processed within a different batch is failed as a replay,
+	// with CodeTemporaryChannelFailure.
+	// Decoding a batch with the same ID again, e.g. after a restart,
+	// yields the same responses as when it was first decoded. The
+	// responses are returned in the order of the requests.
+	DecodeHopIterators(batchID []byte, height uint32,
+		reqs []DecodeHopIteratorRequest) ([]DecodeHopIteratorResponse,
+		error)
+
+	// ExtractErrorEncrypter derives the ErrorEncrypter used to fail back
+	// the HTLC carrying the onion packet read from the passed reader.
+	ExtractErrorEncrypter(r io.Reader) (ErrorEncrypter, lnwire.FailCode)
+
+	// ReextractErrorEncrypter restores the ErrorEncrypter of a persisted
+	// circuit from the ephemeral key of its onion packet.
+	ReextractErrorEncrypter(ephemeralKey *btcec.PublicKey) (ErrorEncrypter,
+		lnwire.FailCode)
 }
 
-// OnionProcessor is responsible for keeping all sphinx dependent parts inside
-// and expose only decoding function. With such approach we give freedom for
-// subsystems which wants to decode sphinx path to not be dependable from
-// sphinx at all.
+// sphinxOnionVersion is the version of the onion packets constructed
+// according to the Sphinx construction of BOLT #4.
+const sphinxOnionVersion byte = 0
+
+// SphinxOnionProcessor is responsible for keeping all sphinx dependent parts
+// inside and expose only decoding function. With such approach we give
+// freedom for subsystems which wants to decode sphinx path to not be
+// dependable from sphinx at all.
 //
 // NOTE: The reason for keeping decoder separated from hop iterator is too
 // maintain the hop iterator abstraction. Without it the structures which using
 // the hop iterator should contain sphinx router which makes their creations in
 // tests dependent from the sphinx internal parts.
-type OnionProcessor struct {
+type SphinxOnionProcessor struct {
 	router *sphinx.Router
+
+	// replayLog records the packets which have been processed within a
+	// batch. If nil, then packets aren't checked for replays.
+	replayLog ReplayLog
 }
 
-// NewOnionProcessor creates new instance of decoder.
-func NewOnionProcessor(router *sphinx.Router) *OnionProcessor {
-	return &OnionProcessor{router}
+// A compile time check to ensure SphinxOnionProcessor implements the
+// OnionProcessor interface.
+var _ OnionProcessor = (*SphinxOnionProcessor)(nil)
+
+// NewSphinxOnionProcessor creates new instance of decoder. The passed replay
+// log is used to detect onion packets being replayed.
+func NewSphinxOnionProcessor(router *sphinx.Router,
+	replayLog ReplayLog) *SphinxOnionProcessor {
+
+	return &SphinxOnionProcessor{
+		router:    router,
+		replayLog: replayLog,
+	}
 }
 
-// DecodeHopIterator attempts to decode a valid sphinx packet from the passed io.Reader
-// instance using the rHash as the associated data when checking the relevant
-// MACs during the decoding process.
-func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte) (HopIterator,
-	lnwire.FailCode) {
+// SupportedVersions returns the versions of the onion packets the processor
+// is able to decode.
+//
+// NOTE: Part of the OnionProcessor interface.
+func (p *SphinxOnionProcessor) SupportedVersions() []byte {
+	return []byte{sphinxOnionVersion}
+}
 
+// decodeOnionPacket attempts to decode a sphinx packet from the passed
+// io.Reader instance.
+func decodeOnionPacket(r io.Reader) (*sphinx.OnionPacket, lnwire.FailCode) {
 	onionPkt := &sphinx.OnionPacket{}
 	if err := onionPkt.Decode(r); err != nil {
 		switch err {
@@ -174,6 +305,14 @@ func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte) (HopIterat
 		}
 	}
 
+	return onionPkt, lnwire.CodeNone
+}
+
+// processOnionPacket attempts to process the passed sphinx packet, using the
+// rHash as the associated data when checking the relevant MACs.
+func (p *SphinxOnionProcessor) processOnionPacket(onionPkt *sphinx.OnionPacket,
+	rHash []byte) (HopIterator, lnwire.FailCode) {
+
 	// Attempt to process the Sphinx packet. We include the payment hash of
 	// the HTLC as it's authenticated within the Sphinx packet itself as
 	// associated data in order to thwart attempts a replay attacks. In the
@@ -197,31 +336,177 @@ func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte) (HopIterat
 	return &sphinxHopIterator{
 		nextPacket:      sphinxPacket.NextPacket,
 		processedPacket: sphinxPacket,
+		finalRecords:    p.extractFinalRecords(sphinxPacket, rHash),
 	}, lnwire.CodeNone
 }
 
+// extractFinalRecords returns the records carried within the onion of a
+// payment to us if the passed packet, which was destined to us, is followed by
+// the payloads holding them, which are destined to us as well. Otherwise, nil
+// is returned, and the packet is handled as any other, such that a payment
+// with malformed records is failed as one to an unknown channel.
+func (p *SphinxOnionProcessor) extractFinalRecords(
+	packet *sphinx.ProcessedPacket, rHash []byte) *FinalHopRecords {
+
+	if packet.Action != sphinx.MoreHops {
+		return nil
+	}
+	nextHop := binary.BigEndian.Uint64(
+		packet.ForwardingInstructions.NextAddress[:],
+	)
+	if nextHop != FinalRecordsHop.ToUint64() {
+		return nil
+	}
+
+	// The records span all remaining payloads within the onion, so we'll
+	// process it until we reach the final one.
+	var hopData []sphinx.HopData
+	for {
+		if len(hopData) == maxFinalPayloads {
+			log.Debugf("Too many final hop record payloads for "+
+				"payment_hash=%x", rHash)
+			return nil
+		}
+
+		next, err := p.router.ProcessOnionPacket(
+			packet.NextPacket, rHash,
+		)
+		if err != nil {
+			log.Debugf("Invalid final hop records for "+
+				"payment_hash=%x: %v", rHash, err)
+			return nil
+		}
+
+		hopData = append(hopData, next.ForwardingInstructions)
+		if next.Action == sphinx.ExitNode {
+			break
+		}
+		packet = next
+	}
+
+	records, err := finalHopRecords(hopData)
+	if err != nil {
+		log.Debugf("Unable to decode final hop records for "+
+			"payment_hash=%x: %v", rHash, err)
+		return nil
+	}
+
+	return records
+}
+
+// DecodeHopIterator attempts to decode a valid sphinx packet from the passed
+// io.Reader instance using the rHash as the associated data when checking the
+// relevant MACs during the decoding process.
+//
+// NOTE: Part of the OnionProcessor interface.
+func (p *SphinxOnionProcessor) DecodeHopIterator(r io.Reader,
+	rHash []byte) (HopIterator, lnwire.FailCode) {
+
+	onionPkt, failCode := decodeOnionPacket(r)
+	if failCode != lnwire.CodeNone {
+		return nil, failCode
+	}
+
+	return p.processOnionPacket(onionPkt, rHash)
+}
+
+// DecodeHopIterators decodes the sphinx packets of a batch of HTLCs. Each
+// packet that was successfully processed is recorded within the replay log,
+// identified by the hash of its ephemeral key, as no two distinct packets
+// destined to us may share the same key.
+//
+// NOTE: Part of the OnionProcessor interface.
+func (p *SphinxOnionProcessor) DecodeHopIterators(batchID []byte,
+	height uint32, reqs []DecodeHopIteratorRequest) (
+	[]DecodeHopIteratorResponse, error) {
+
+	var (
+		resps   = make([]DecodeHopIteratorResponse, len(reqs))
+		entries []ReplayEntry
+
+		// entryIndexes maps the index of each replay log entry to the
+		// index of the request it was recorded for.
+		entryIndexes []int
+	)
+	for i, req := range reqs {
+		onionPkt, failCode := decodeOnionPacket(req.OnionReader)
+		if failCode != lnwire.CodeNone {
+			resps[i].FailCode = failCode
+			continue
+		}
+
+		iterator, failCode := p.processOnionPacket(onionPkt, req.RHash)
+		if failCode != lnwire.CodeNone {
+			resps[i].FailCode = failCode
+			continue
+		}
+		resps[i].HopIterator = iterator
+
+		entries = append(entries, ReplayEntry{
+			Hash: sha256.Sum256(
+				onionPkt.EphemeralKey.SerializeCompressed(),
+			),
+			Expiry: req.IncomingCltv,
+		})
+		entryIndexes = append(entryIndexes, i)
+	}
+
+	if p.replayLog == nil || len(entries) == 0 {
+		return resps, nil
+	}
+
+	replays, err := p.replayLog.PutBatch(batchID, height, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	// Any packet that was already processed within a different batch is
+	// being replayed. As the packet itself is valid, the HTLC is failed
+	// back with a temporary channel failure, rather than as malformed.
+	for entryIndex := range replays {
+		i := entryIndexes[entryIndex]
+
+		log.Warnf("Replayed onion packet detected for payment_hash=%x",
+			reqs[i].RHash)
+
+		resps[i] = DecodeHopIteratorResponse{
+			FailCode: lnwire.CodeTemporaryChannelFailure,
+		}
+	}
+
+	return resps, nil
+}
+
 // ExtractErrorEncrypter takes an io.Reader which should contain the onion
 // packet as original received by a forwarding node and creates an
 // ErrorEncrypter instance using the derived shared secret. In the case that en
 // error occurs, a lnwire failure code detailing the parsing failure will be
 // returned.
-func (p *OnionProcessor) ExtractErrorEncrypter(r io.Reader) (ErrorEncrypter, lnwire.FailCode) {
+//
+// NOTE: Part of the OnionProcessor interface.
+func (p *SphinxOnionProcessor) ExtractErrorEncrypter(
+	r io.Reader) (ErrorEncrypter, lnwire.FailCode) {
 
-	onionPkt := &sphinx.OnionPacket{}
-	if err := onionPkt.Decode(r); err != nil {
-		switch err {
-		case sphinx.ErrInvalidOnionVersion:
-			return nil, lnwire.CodeInvalidOnionVersion
-		case sphinx.ErrInvalidOnionKey:
-			return nil, lnwire.CodeInvalidOnionKey
-		default:
-			log.Errorf("unable to decode onion packet: %v", err)
-			return nil, lnwire.CodeInvalidOnionKey
-		}
+	onionPkt, failCode := decodeOnionPacket(r)
+	if failCode != lnwire.CodeNone {
+		return nil, failCode
 	}
 
+	return p.ReextractErrorEncrypter(onionPkt.EphemeralKey)
+}
+
+// ReextractErrorEncrypter creates an ErrorEncrypter instance using the shared
+// secret derived from the passed ephemeral key of an onion packet. This
+// allows the encrypter of a persisted circuit, which only retains the key, to
+// be restored. In the case that an error occurs, a lnwire failure code
+// detailing the failure will be returned.
+//
+// NOTE: Part of the OnionProcessor interface.
+func (p *SphinxOnionProcessor) ReextractErrorEncrypter(
+	ephemeralKey *btcec.PublicKey) (ErrorEncrypter, lnwire.FailCode) {
+
 	onionObfuscator, err := sphinx.NewOnionErrorEncrypter(p.router,
-		onionPkt.EphemeralKey)
+		ephemeralKey)
 	if err != nil {
 		switch err {
 		case sphinx.ErrInvalidOnionVersion:
@@ -238,5 +523,6 @@ func (p *OnionProcessor) ExtractErrorEncrypter(r io.Reader) (ErrorEncrypter, lnw
 
 	return &SphinxErrorEncrypter{
 		OnionErrorEncrypter: onionObfuscator,
+		EphemeralKey:        ephemeralKey,
 	}, lnwire.CodeNone
 }
@@ -0,0 +1,305 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// TestSphinxKeysendPreimage ensures that the preimage of a keysend payment is
+// only extracted from the onion if the record payloads following ours are
+// destined to us as well, and that the packet is otherwise handled as any
+// other.
+func TestSphinxKeysendPreimage(t *testing.T) {
+	t.Parallel()
+
+	ourKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	otherKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	processor := NewSphinxOnionProcessor(
+		sphinx.NewRouter(ourKey, &chaincfg.MainNetParams), nil,
+	)
+
+	var preimage [32]byte
+	if _, err := rand.Read(preimage[:]); err != nil {
+		t.Fatalf("unable to generate preimage: %v", err)
+	}
+	paymentHash := sha256.Sum256(preimage[:])
+
+	keysendHop := sphinx.HopData{
+		ForwardAmount: 1000,
+		OutgoingCltv:  100,
+	}
+	binary.BigEndian.PutUint64(
+		keysendHop.NextAddress[:], FinalRecordsHop.ToUint64(),
+	)
+	forwardHop := keysendHop
+	forwardChan := lnwire.NewShortChanIDFromInt(5)
+	binary.BigEndian.PutUint64(
+		forwardHop.NextAddress[:], forwardChan.ToUint64(),
+	)
+
+	keysendData, err := NewFinalHopData(&FinalHopRecords{
+		KeysendPreimage: &preimage,
+	})
+	if err != nil {
+		t.Fatalf("unable to create record payloads: %v", err)
+	}
+
+	var (
+		ourPath = []*btcec.PublicKey{
+			ourKey.PubKey(), ourKey.PubKey(), ourKey.PubKey(),
+		}
+		otherPath = []*btcec.PublicKey{
+			ourKey.PubKey(), otherKey.PubKey(), otherKey.PubKey(),
+		}
+		keysendPayloads = append(
+			[]sphinx.HopData{keysendHop}, keysendData...,
+		)
+		forwardPayloads = append(
+			[]sphinx.HopData{forwardHop}, keysendData...,
+		)
+	)
+
+	tests := []struct {
+		name             string
+		nodes            []*btcec.PublicKey
+		payloads         []sphinx.HopData
+		expectedNextHop  lnwire.ShortChannelID
+		expectedPreimage bool
+	}{
+		{
+			name:             "keysend",
+			nodes:            ourPath,
+			payloads:         keysendPayloads,
+			expectedNextHop:  exitHop,
+			expectedPreimage: true,
+		},
+		{
+			name:            "forward",
+			nodes:           otherPath,
+			payloads:        forwardPayloads,
+			expectedNextHop: forwardChan,
+		},
+		{
+			name:            "keysend payload to other node",
+			nodes:           otherPath,
+			payloads:        keysendPayloads,
+			expectedNextHop: FinalRecordsHop,
+		},
+	}
+
+	for _, test := range tests {
+		sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		onionPkt, err := sphinx.NewOnionPacket(
+			test.nodes, sessionKey, test.payloads, paymentHash[:],
+		)
+		if err != nil {
+			t.Fatalf("%v: unable to create onion: %v", test.name,
+				err)
+		}
+
+		iterator, failCode := processor.processOnionPacket(
+			onionPkt, paymentHash[:],
+		)
+		if failCode != lnwire.CodeNone {
+			t.Fatalf("%v: unable to process onion: %v", test.name,
+				failCode)
+		}
+
+		fwdInfo := iterator.ForwardingInstructions()
+		if fwdInfo.NextHop != test.expectedNextHop {
+			t.Fatalf("%v: expected next hop %v, got %v", test.name,
+				test.expectedNextHop, fwdInfo.NextHop)
+		}
+		if fwdInfo.AmountToForward != 1000 ||
+			fwdInfo.OutgoingCTLV != 100 {
+
+			t.Fatalf("%v: unexpected forwarding info: %v",
+				test.name, fwdInfo)
+		}
+
+		switch {
+		case !test.expectedPreimage && fwdInfo.KeysendPreimage != nil:
+			t.Fatalf("%v: unexpected keysend preimage", test.name)
+
+		case test.expectedPreimage && (fwdInfo.KeysendPreimage == nil ||
+			*fwdInfo.KeysendPreimage != preimage):
+
+			t.Fatalf("%v: expected keysend preimage %x, got %v",
+				test.name, preimage, fwdInfo.KeysendPreimage)
+		}
+	}
+}
+
+// TestFinalHopRecordsEncoding ensures that the records destined to the final
+// hop survive being carried within per-hop payloads, and that unknown records
+// are handled as per the "it's ok to be odd" rule.
+func TestFinalHopRecordsEncoding(t *testing.T) {
+	t.Parallel()
+
+	var preimage [32]byte
+	if _, err := rand.Read(preimage[:]); err != nil {
+		t.Fatalf("unable to generate preimage: %v", err)
+	}
+
+	hopData, err := NewFinalHopData(&FinalHopRecords{
+		KeysendPreimage: &preimage,
+	})
+	if err != nil {
+		t.Fatalf("unable to create record payloads: %v", err)
+	}
+
+	// The preimage record spans 38 bytes, which along with the length
+	// prefix requires two payloads.
+	if len(hopData) != 2 {
+		t.Fatalf("expected 2 payloads, got %v", len(hopData))
+	}
+
+	// Each payload should survive its wire encoding.
+	decodedData := make([]sphinx.HopData, len(hopData))
+	for i := range hopData {
+		var b bytes.Buffer
+		if err := hopData[i].Encode(&b); err != nil {
+			t.Fatalf("unable to encode hop data: %v", err)
+		}
+		if err := decodedData[i].Decode(&b); err != nil {
+			t.Fatalf("unable to decode hop data: %v", err)
+		}
+	}
+
+	records, err := finalHopRecords(decodedData)
+	if err != nil {
+		t.Fatalf("unable to decode records: %v", err)
+	}
+	if records.KeysendPreimage == nil ||
+		*records.KeysendPreimage != preimage {
+
+		t.Fatalf("expected preimage %x, got %v", preimage,
+			records.KeysendPreimage)
+	}
+
+	// Unknown odd records should be skipped, while unknown even ones
+	// should be rejected.
+	encode := func(records map[uint64][]byte) []byte {
+		stream := lnwire.EncodeTLVStream(records)
+		b := make([]byte, 2+len(stream))
+		binary.BigEndian.PutUint16(b, uint16(len(stream)))
+		copy(b[2:], stream)
+		return b
+	}
+	decoded, err := decodeFinalHopRecords(encode(map[uint64][]byte{
+		KeysendRecordType:     preimage[:],
+		KeysendRecordType + 1: {1},
+	}))
+	if err != nil {
+		t.Fatalf("unable to decode records: %v", err)
+	}
+	if decoded.KeysendPreimage == nil {
+		t.Fatalf("expected keysend preimage")
+	}
+	_, err = decodeFinalHopRecords(encode(map[uint64][]byte{
+		KeysendRecordType: preimage[:],
+		1000:              {1},
+	}))
+	if err == nil {
+		t.Fatalf("expected unknown even record to be rejected")
+	}
+}
+
+// TestChannelLinkAcceptKeysend ensures that keysend payments are only
+// accepted if enabled and their preimage matches the payment hash, in which
+// case an invoice is recorded for them.
+func TestChannelLinkAcceptKeysend(t *testing.T) {
+	t.Parallel()
+
+	preimage := [32]byte{1}
+	pd := &lnwallet.PaymentDescriptor{
+		RHash:  sha256.Sum256(preimage[:]),
+		Amount: 5000,
+	}
+
+	tests := []struct {
+		name          string
+		acceptKeysend bool
+		preimage      [32]byte
+		expectInvoice bool
+	}{
+		{
+			name:          "disabled",
+			acceptKeysend: false,
+			preimage:      preimage,
+		},
+		{
+			name:          "mismatched preimage",
+			acceptKeysend: true,
+			preimage:      [32]byte{2},
+		},
+		{
+			name:          "accepted",
+			acceptKeysend: true,
+			preimage:      preimage,
+			expectInvoice: true,
+		},
+	}
+
+	for _, test := range tests {
+		registry := newMockRegistry()
+		link := &channelLink{
+			cfg: ChannelLinkConfig{
+				Registry:      registry,
+				AcceptKeysend: test.acceptKeysend,
+			},
+		}
+
+		failure := link.acceptKeysend(pd, test.preimage)
+		if (failure == nil) != test.expectInvoice {
+			t.Fatalf("%v: unexpected failure: %v", test.name,
+				failure)
+		}
+
+		invoiceHash := chainhash.Hash(pd.RHash)
+		invoice, err := registry.LookupInvoice(invoiceHash)
+		switch {
+		case !test.expectInvoice && err == nil:
+			t.Fatalf("%v: unexpected invoice added", test.name)
+
+		case test.expectInvoice && err != nil:
+			t.Fatalf("%v: invoice not added: %v", test.name, err)
+
+		case test.expectInvoice:
+			if invoice.Terms.PaymentPreimage != preimage ||
+				invoice.Terms.Value != pd.Amount {
+
+				t.Fatalf("%v: unexpected invoice terms: %v",
+					test.name, invoice.Terms)
+			}
+
+			// Accepting the payment again, as would happen once
+			// it's replayed after a restart, should leave the
+			// invoice in place.
+			failure := link.acceptKeysend(pd, preimage)
+			if failure != nil {
+				t.Fatalf("%v: unable to accept replayed "+
+					"payment: %v", test.name, failure)
+			}
+		}
+	}
+}
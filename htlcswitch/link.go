@@ -2,13 +2,12 @@ package htlcswitch
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"io"
-
 	"crypto/sha256"
 
 	"github.com/go-errors/errors"
@@ -18,21 +17,10 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
 )
 
-const (
-	// expiryGraceDelta is a grace period that the timeout of incoming
-	// HTLC's that pay directly to us (i.e we're the "exit node") must up
-	// hold. We'll reject any HTLC's who's timeout minus this value is less
-	// that or equal to the current block height. We require this in order
-	// to ensure that if the extending party goes to the chain, then we'll
-	// be able to claim the HTLC still.
-	//
-	// TODO(roasbeef): must be < default delta
-	expiryGraceDelta = 2
-)
-
 // ForwardingPolicy describes the set of constraints that a given ChannelLink
 // is to adhere to when forwarding HTLC's. For each incoming HTLC, this set of
 // constraints will be consulted in order to ensure that adequate fees are
@@ -67,7 +55,17 @@ type ForwardingPolicy struct {
 	//    per-hop payload of the incoming HTLC's onion packet.
 	TimeLockDelta uint32
 
-	// TODO(roasbeef): add fee module inside of switch
+	// MaxHTLC is the largest HTLC that is to be forwarded over, or
+	// accepted from, the channel. As it isn't part of the ChannelUpdate
+	// we advertise, senders only learn of it once an HTLC is rejected. If
+	// zero, then HTLCs of any value are permitted.
+	MaxHTLC lnwire.MilliSatoshi
+
+	// FeeController, if non-nil, is consulted for each HTLC forwarded by
+	// the link to determine the fees it must pay, in place of BaseFee and
+	// FeeRate. This allows fees to be scheduled, or adjusted to the
+	// congestion of the link, without restarting it.
+	FeeController FeeController
 }
 
 // ExpectedFee computes the expected fee for a given htlc amount. The value
@@ -100,14 +98,11 @@ type ChannelLinkConfig struct {
 	// TODO(roasbeef): remove in favor of simple ForwardPacket closure func
 	Switch *Switch
 
-	// DecodeHopIterator function is responsible for decoding HTLC Sphinx
-	// onion blob, and creating hop iterator which will give us next
-	// destination of HTLC.
-	DecodeHopIterator func(r io.Reader, rHash []byte) (HopIterator, lnwire.FailCode)
-
-	// DecodeOnionObfuscator function is responsible for decoding HTLC
-	// Sphinx onion blob, and creating onion failure obfuscator.
-	DecodeOnionObfuscator func(r io.Reader) (ErrorEncrypter, lnwire.FailCode)
+	// OnionProcessor is responsible for decoding the onion blobs of the
+	// incoming HTLCs into hop iterators, which give us the next
+	// destination of each HTLC, and for deriving the onion failure
+	// obfuscators used to fail them back.
+	OnionProcessor OnionProcessor
 
 	// GetLastChannelUpdate retrieves the latest routing policy for this
 	// particular channel. This will be used to provide payment senders our
@@ -144,6 +139,12 @@ type ChannelLinkConfig struct {
 	// transaction to ensure timely confirmation.
 	FeeEstimator lnwallet.FeeEstimator
 
+	// ChainHealthy reports whether the chain backend is currently deemed
+	// healthy. While it isn't, the link won't attempt to adjust the
+	// commitment fee, as the fee estimate it'd be based on may be stale.
+	// If nil, the backend is always assumed to be healthy.
+	ChainHealthy func() bool
+
 	// BlockEpochs is an active block epoch event stream backed by an
 	// active ChainNotifier instance. The ChannelLink will use new block
 	// notifications sent over this channel to decide when a _new_ HTLC is
@@ -167,6 +168,207 @@ type ChannelLinkConfig struct {
 	// reestablishment message to the remote peer. It should be done if our
 	// clients have been restarted, or remote peer have been reconnected.
 	SyncStates bool
+
+	// ChanSyncTimeout is the amount of time we'll wait for the remote
+	// party's ChannelReestablish message when synchronizing channel
+	// states. If zero, DefaultChanSyncTimeout is used.
+	ChanSyncTimeout time.Duration
+
+	// ChanSyncRetries is the number of times we'll re-send our
+	// ChannelReestablish message, and wait for the remote party's once
+	// again, before failing the link if it doesn't arrive in time.
+	ChanSyncRetries int
+
+	// StuckHTLCThreshold is the amount of time an outgoing HTLC may remain
+	// unresolved before it's reported as stuck. A value of zero disables
+	// the reporting of stuck HTLCs.
+	StuckHTLCThreshold time.Duration
+
+	// NotifyStuckHTLC, if non-nil, is called once for each outgoing HTLC
+	// that has remained unresolved for longer than StuckHTLCThreshold.
+	NotifyStuckHTLC func(StuckHTLC)
+
+	// RecordHTLCStats, if non-nil, is used to periodically persist the
+	// HTLC statistics of the channel accumulated since they were last
+	// recorded. The statistics are also recorded once the link exits.
+	RecordHTLCStats func(*channeldb.ChannelHTLCStats) error
+
+	// EndorsementExperiment, if true, enables the experimental HTLC
+	// endorsement signal. Endorsements of incoming HTLCs are relayed when
+	// they're forwarded, our own payments are endorsed, and unendorsed
+	// HTLCs are restricted to a portion of the channel's slots and
+	// liquidity.
+	EndorsementExperiment bool
+
+	// StrictUnknownMsgs, if true, causes the link to fail upon receiving a
+	// message of an unknown even type, as per the "it's ok to be odd" rule
+	// of BOLT #1. Unknown messages of an odd type are always ignored.
+	StrictUnknownMsgs bool
+
+	// MaxAcceptedHtlcs is the maximum number of unresolved HTLCs we permit
+	// in either direction of the channel. Unlike the protocol limit, it's
+	// enforced by failing the excess HTLCs back, rather than failing the
+	// link. A value of zero disables the limit.
+	MaxAcceptedHtlcs uint16
+
+	// MaxPendingAmount is the maximum total value of unresolved HTLCs we
+	// permit in either direction of the channel. As with
+	// MaxAcceptedHtlcs, the excess HTLCs are failed back. A value of zero
+	// disables the limit.
+	MaxPendingAmount lnwire.MilliSatoshi
+
+	// OverflowPolicy determines how outgoing HTLCs are handled once the
+	// maximum number of HTLCs within the channel's commitment transaction
+	// has been reached. If blank, OverflowQueue is used.
+	OverflowPolicy OverflowPolicy
+
+	// SafeExitSettle, if true, holds back the settlement of HTLCs for
+	// which we're the exit hop until they're irrevocably committed to
+	// both commitment transactions, and the ExitHTLCAcceptor, if any, has
+	// accepted them.
+	SafeExitSettle bool
+
+	// ExitHTLCAcceptor, if non-nil and SafeExitSettle is set, is consulted
+	// before settling each HTLC for which we're the exit hop.
+	ExitHTLCAcceptor ExitHTLCAcceptor
+
+	// AcceptKeysend, if true, allows HTLCs carrying their preimage within
+	// the onion to be settled without a prior invoice. An invoice is
+	// recorded for each such payment as it arrives.
+	AcceptKeysend bool
+
+	// AcceptAmp, if true, allows the parts of AMP payments, whose
+	// preimages are derived from the shares carried within their onions,
+	// to be settled without a prior invoice. A hold invoice is recorded
+	// for each part as it arrives, which is settled once all parts of the
+	// payment have arrived.
+	AcceptAmp bool
+
+	// StatelessInvoiceKey, if non-nil, is the secret key from which the
+	// preimages of our stateless invoices are derived, allowing HTLCs
+	// paying to them to be settled without any invoice being stored. The
+	// invoice is reconstructed from the record carried within the onion
+	// of the HTLC instead. If nil, then such HTLCs are rejected.
+	StatelessInvoiceKey *[32]byte
+
+	// RejectLegacyPayments, if true, rejects HTLCs paying to invoices
+	// which have a payment secret if their onion doesn't carry it. HTLCs
+	// carrying a payment secret which doesn't match that of the invoice
+	// are always rejected.
+	RejectLegacyPayments bool
+
+	// MaxOverpaymentPct is the percentage of the value of an invoice by
+	// which the amount paid to it may exceed the value, as instructed by
+	// the onion of the HTLC paying to it. If zero, the amount must match
+	// the value of the invoice exactly.
+	MaxOverpaymentPct uint32
+
+	// FinalCltvGrace is the number of blocks an incoming HTLC for which
+	// we're the exit hop must have left until its expiry to be accepted.
+	// If zero, DefaultFinalCltvGrace is used.
+	FinalCltvGrace uint32
+
+	// MinCltvDelta is the floor of the time lock delta enforced for
+	// forwarded HTLCs. Should the forwarding policy of the link specify a
+	// lower delta, then this one is enforced instead.
+	MinCltvDelta uint32
+
+	// ExpiryGraceDelta is the number of blocks prior to the expiry of an
+	// incoming HTLC we know the preimage for, at which the channel is
+	// handed off for on-chain resolution if the HTLC is still active. A
+	// value of zero disables the expiry auditor of the link.
+	ExpiryGraceDelta uint32
+
+	// ForceCloseChan hands the channel off to the contract court for
+	// on-chain resolution, by force closing it. It's called by the expiry
+	// auditor within a distinct goroutine, as the link is expected to be
+	// torn down in the process.
+	ForceCloseChan func() error
+
+	// QuiescenceSupported indicates whether both parties have negotiated
+	// the quiescence feature, which allows either party to request the
+	// channel to be brought into a quiescent state through an Stfu
+	// message. If false, then receiving one is a protocol violation, and
+	// InitStfu fails with ErrQuiescenceUnsupported.
+	QuiescenceSupported bool
+
+	// QuiescenceTimeout is the amount of time the link remains quiescent,
+	// including the time it takes for the channel to become quiescent,
+	// before the peer is disconnected in order to end the quiescence. If
+	// zero, DefaultQuiescenceTimeout is used.
+	QuiescenceTimeout time.Duration
+
+	// OnForceCloseRecommended is called once the link fails in a manner
+	// which leaves the channel unusable until it's force closed, such as
+	// the remote party sending an invalid commitment or revocation. The
+	// channel isn't closed automatically, as the operator may wish to
+	// investigate first. If nil, then the failure is only logged.
+	OnForceCloseRecommended func(reason error)
+
+	// BatchSize is the number of updates the link batches before
+	// initiating a commitment update. If zero, DefaultBatchSize is used.
+	BatchSize uint32
+
+	// BatchTicker is the interval at which the link commits any updates
+	// it has batched, should the batch not fill up in the meantime. If
+	// zero, DefaultBatchTicker is used.
+	BatchTicker time.Duration
+
+	// PendingCommitTicker is the minimum amount of time the link waits
+	// after receiving a commitment signature, before checking whether it
+	// owes the remote party one in return. The wait is extended to a
+	// multiple of the round trip time to the remote party, as measured by
+	// the link. If zero, DefaultPendingCommitTicker is used.
+	PendingCommitTicker time.Duration
+
+	// MaxPendingCommitTicker is the maximum amount of time the link waits
+	// after receiving a commitment signature, however long the round
+	// trip time to the remote party. If zero,
+	// DefaultMaxPendingCommitTicker is used.
+	MaxPendingCommitTicker time.Duration
+
+	// MaxOverflowQueueLen is the maximum number of HTLCs that may be held
+	// within the link's overflow queue, waiting for a slot within the
+	// channel's commitment transaction. Once reached, new HTLCs are failed
+	// back, or retried over another link to the same peer, with
+	// ErrInsufficientCapacity. Zero indicates no limit.
+	MaxOverflowQueueLen int32
+
+	// MaxOverflowResidency is the maximum amount of time the HTLC at the
+	// head of the link's overflow queue may wait for a commitment slot.
+	// Once exceeded, new HTLCs are handled as if the queue were full,
+	// until it drains. Zero indicates no limit.
+	MaxOverflowResidency time.Duration
+
+	// FeeSpikeMultiplier is the factor the fee to claim an outgoing HTLC
+	// on-chain, at the prevailing fee rate, is scaled by before being
+	// compared to the HTLC's value. Forwarded HTLCs whose scaled claim
+	// fee exceeds their value are declined as uneconomical. Zero disables
+	// the check.
+	FeeSpikeMultiplier float64
+
+	// MinFeeUpdateInterval is the minimum amount of time between two
+	// commitment fee updates sent by the link. Zero indicates no minimum.
+	MinFeeUpdateInterval time.Duration
+
+	// MaxFeeUpdateStep is the maximum change of the commitment fee rate
+	// within a single update, as a fraction of the current fee rate.
+	// Larger changes are spread across several updates. Zero indicates no
+	// limit.
+	MaxFeeUpdateStep float64
+
+	// MinFeeRate and MaxFeeRate are the lowest and highest commitment fee
+	// rates, in sat/kw, we'll accept from the remote party as the
+	// initiator of the channel. A proposed fee rate outside of them fails
+	// the channel. Zero leaves the respective bound unset.
+	MinFeeRate btcutil.Amount
+	MaxFeeRate btcutil.Amount
+
+	// FeeRateTolerance is the multiple of our own estimate of the network
+	// fee rate the commitment fee rate proposed by the remote party may
+	// deviate by, in either direction, before the channel is failed. Zero
+	// disables the check.
+	FeeRateTolerance float64
 }
 
 // channelLink is the service which drives a channel's commitment update
@@ -200,14 +402,30 @@ type channelLink struct {
 	// which may affect behaviour of the service.
 	cfg ChannelLinkConfig
 
+	// policyMtx guards the forwarding policy within cfg. It's only updated
+	// by the htlcManager goroutine, which holds the lock while doing so,
+	// and may be read concurrently by ForwardingPolicy.
+	policyMtx sync.RWMutex
+
 	// overflowQueue is used to store the htlc add updates which haven't
 	// been processed because of the commitment transaction overflow.
 	overflowQueue *packetQueue
 
+	// admission decides which of the htlc adds overflowing the commitment
+	// transaction are admitted to the overflow queue, according to the
+	// queue's budget.
+	admission *admissionController
+
+	// feeSpike declines forwarded HTLCs which would cost more to claim
+	// on-chain than they're worth.
+	feeSpike *feeSpikeBreaker
+
 	// mailBox is the main interface between the outside world and the
 	// link. All incoming messages will be sent over this mailBox. Messages
 	// include new updates from our connected peer, and new packets to be
-	// forwarded sent by the switch.
+	// forwarded sent by the switch. The mailbox is owned by the switch,
+	// which attaches it before starting the link, and retains it once the
+	// link is stopped, so packets aren't lost while the link restarts.
 	mailBox *memoryMailBox
 
 	// upstream is a channel that new messages sent from the remote peer to
@@ -231,10 +449,103 @@ type channelLink struct {
 	// logCommitTimer is a timer which is sent upon if we go an interval
 	// without receiving/sending a commitment update. It's role is to
 	// ensure both chains converge to identical state in a timely manner.
-	//
-	// TODO(roasbeef): timer should be >> then RTT
+	// The interval is derived from the round trip time to the remote
+	// party, as estimated by rtt, which MUST only be accessed from the
+	// htlcManager goroutine.
 	logCommitTimer *time.Timer
 	logCommitTick  <-chan time.Time
+	rtt            rttEstimator
+
+	// outgoingHtlcs tracks the age of all outgoing HTLCs which haven't yet
+	// been resolved by the remote party, keyed by their index within our
+	// local update log.
+	outgoingHtlcs map[uint64]*outgoingHtlc
+	htlcAgeMtx    sync.Mutex
+
+	// htlcStats accumulates the HTLC statistics of the channel since they
+	// were last recorded at htlcStatsSince. They MUST only be accessed
+	// from the htlcManager goroutine.
+	htlcStats      channeldb.ChannelHTLCStats
+	htlcStatsSince time.Time
+
+	// endorsedHtlcs is the set of incoming HTLCs, keyed by their index
+	// within the remote party's update log, which have been endorsed by
+	// the remote party and are yet to be processed.
+	endorsedHtlcs map[uint64]struct{}
+
+	// overLimitHtlcs is the set of incoming HTLCs, keyed by their index
+	// within the remote party's update log, which exceeded the
+	// MaxAcceptedHtlcs or MaxPendingAmount of the link when they were
+	// received, and are to be failed back once locked in.
+	overLimitHtlcs map[uint64]struct{}
+
+	// flushing is set to 1 once the link has been placed into flush mode
+	// by ShutdownIfChannelClean, after which it no longer accepts new
+	// HTLCs.
+	flushing int32 // To be used atomically.
+
+	// flushWaiters are closed once the channel is clean while the link is
+	// flushing.
+	flushWaiters []chan struct{}
+
+	// lastFeeUpdate is the time the link last sent a commitment fee
+	// update. It MUST only be accessed from the htlcManager goroutine.
+	lastFeeUpdate time.Time
+
+	// quiescing is set to 1 once either party has requested the channel
+	// to be brought into a quiescent state, after which the link no
+	// longer initiates any updates.
+	quiescing int32 // To be used atomically.
+
+	// stfuSent and stfuReceived are set once we've sent, and received,
+	// an Stfu message respectively. The channel is quiescent once both
+	// are set.
+	stfuSent     bool
+	stfuReceived bool
+
+	// stfuWaiters are notified once the channel is quiescent, or the link
+	// exits before it is.
+	stfuWaiters []chan error
+
+	// quiescenceTimer fires once the link has been quiescing for longer
+	// than the QuiescenceTimeout. It's created once the link starts
+	// quiescing.
+	quiescenceTimer *time.Timer
+
+	// feeOutcomes tracks the outcomes of the most recently resolved HTLCs
+	// offered over the link, from which the failure rate passed to the
+	// FeeController of its forwarding policy is computed.
+	feeOutcomes feeOutcomes
+
+	// resolvedHtlcs is the set of incoming HTLCs, keyed by their index
+	// within the remote party's update log, which we've added a settle or
+	// fail for. It ensures that an HTLC isn't resolved a second time using
+	// a preimage discovered by the witness beacon.
+	resolvedHtlcs map[uint64]struct{}
+
+	// witnessSettled is the set of incoming HTLCs, keyed by their index
+	// within the remote party's update log, which were settled using a
+	// preimage discovered by the witness beacon rather than one delivered
+	// by the switch. Any resolution the switch later delivers for these
+	// HTLCs is ignored.
+	witnessSettled map[uint64]struct{}
+
+	// heldExitHtlcs is the set of HTLCs for which we're the exit hop, and
+	// whose settlement is being held back, keyed by their index within the
+	// remote party's update log. It's only used if SafeExitSettle is set.
+	heldExitHtlcs map[uint64]*heldExitHtlc
+
+	// forceCloseRequested is true once the expiry auditor has handed the
+	// channel off for on-chain resolution.
+	forceCloseRequested bool
+
+	// exitDecisions delivers the decisions of the ExitHTLCAcceptor about
+	// held HTLCs to the htlcManager.
+	exitDecisions chan exitDecision
+
+	// lastCommitUpdate is the time we last sent or received a new
+	// commitment signature.
+	lastCommitUpdate time.Time
 
 	wg   sync.WaitGroup
 	quit chan struct{}
@@ -245,21 +556,36 @@ type channelLink struct {
 func NewChannelLink(cfg ChannelLinkConfig, channel *lnwallet.LightningChannel,
 	currentHeight uint32) ChannelLink {
 
+	sanitizeBatchConfig(&cfg)
+	sanitizeCltvConfig(&cfg)
+	if cfg.QuiescenceTimeout == 0 {
+		cfg.QuiescenceTimeout = DefaultQuiescenceTimeout
+	}
+
 	link := &channelLink{
 		cfg:         cfg,
 		channel:     channel,
-		mailBox:     newMemoryMailBox(),
 		linkControl: make(chan interface{}),
 		// TODO(roasbeef): just do reserve here?
-		logCommitTimer: time.NewTimer(300 * time.Millisecond),
+		logCommitTimer: time.NewTimer(cfg.PendingCommitTicker),
 		overflowQueue:  newPacketQueue(lnwallet.MaxHTLCNumber / 2),
 		bestHeight:     currentHeight,
 		htlcUpdates:    make(chan []channeldb.HTLC),
+		outgoingHtlcs:  make(map[uint64]*outgoingHtlc),
+		endorsedHtlcs:  make(map[uint64]struct{}),
+		overLimitHtlcs: make(map[uint64]struct{}),
+		resolvedHtlcs:  make(map[uint64]struct{}),
+		witnessSettled: make(map[uint64]struct{}),
+		heldExitHtlcs:  make(map[uint64]*heldExitHtlc),
+		exitDecisions:  make(chan exitDecision),
 		quit:           make(chan struct{}),
 	}
 
-	link.upstream = link.mailBox.MessageOutBox()
-	link.downstream = link.mailBox.PacketOutBox()
+	link.admission = newAdmissionController(
+		link.overflowQueue, cfg.MaxOverflowQueueLen,
+		cfg.MaxOverflowResidency,
+	)
+	link.feeSpike = newFeeSpikeBreaker(cfg.FeeSpikeMultiplier)
 
 	return link
 }
@@ -268,11 +594,28 @@ func NewChannelLink(cfg ChannelLinkConfig, channel *lnwallet.LightningChannel,
 // interface.
 var _ ChannelLink = (*channelLink)(nil)
 
+// AttachMailBox sets the mailbox through which the link receives the messages
+// of the remote peer, and the packets of the switch. It MUST be called before
+// the link is started.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) AttachMailBox(mailBox *memoryMailBox) {
+	l.mailBox = mailBox
+	l.upstream = mailBox.MessageOutBox()
+	l.downstream = mailBox.PacketOutBox()
+}
+
 // Start starts all helper goroutines required for the operation of the channel
 // link.
 //
 // NOTE: Part of the ChannelLink interface.
 func (l *channelLink) Start() error {
+	if l.mailBox == nil {
+		err := errors.Errorf("channel link(%v): no mailbox attached", l)
+		log.Error(err)
+		return err
+	}
+
 	if !atomic.CompareAndSwapInt32(&l.started, 0, 1) {
 		err := errors.Errorf("channel link(%v): already started", l)
 		log.Warn(err)
@@ -286,6 +629,14 @@ func (l *channelLink) Start() error {
 	//
 	// TODO(roasbeef): split goroutines within channel arb to avoid
 	go func() {
+		// If the link has already been stopped, as its peer flapped,
+		// then there's no need to hand its signals over.
+		select {
+		case <-l.quit:
+			return
+		default:
+		}
+
 		err := l.cfg.UpdateContractSignals(&contractcourt.ContractSignals{
 			HtlcUpdates: l.htlcUpdates,
 			ShortChanID: l.channel.ShortChanID(),
@@ -296,7 +647,19 @@ func (l *channelLink) Start() error {
 		}
 	}()
 
-	l.mailBox.Start()
+	// Any HTLCs we offered, but didn't sign for, before going down have
+	// been forgotten by the channel, and their IDs will be re-used. We'll
+	// trim their circuits, so that the settles and fails of new HTLCs
+	// aren't mistaken for theirs.
+	err := l.cfg.Switch.trimOpenCircuits(
+		l.ShortChanID(), l.channel.LocalHtlcIndex(),
+	)
+	if err != nil {
+		log.Errorf("ChannelPoint(%v): unable to trim open circuits: %v",
+			l.channel.ChannelPoint(), err)
+		return err
+	}
+
 	l.overflowQueue.Start()
 
 	l.wg.Add(1)
@@ -323,7 +686,6 @@ func (l *channelLink) Stop() {
 
 	l.channel.Stop()
 
-	l.mailBox.Stop()
 	l.overflowQueue.Stop()
 
 	close(l.quit)
@@ -335,7 +697,8 @@ func (l *channelLink) Stop() {
 // we know the remote party's next revocation point. Otherwise, we can't
 // initiate new channel state.
 func (l *channelLink) EligibleToForward() bool {
-	return l.channel.RemoteNextRevocation() != nil
+	return l.channel.RemoteNextRevocation() != nil && !l.isFlushing() &&
+		!l.isQuiescing()
 }
 
 // sampleNetworkFee samples the current fee rate on the network to get into the
@@ -344,8 +707,11 @@ func (l *channelLink) EligibleToForward() bool {
 // transactions, and the second-level HTLC transactions.
 func (l *channelLink) sampleNetworkFee() (btcutil.Amount, error) {
 	// We'll first query for the sat/weight recommended to be confirmed
-	// within 3blocks.
-	feePerWeight, err := l.cfg.FeeEstimator.EstimateFeePerWeight(3)
+	// within 3blocks. The backend's mempool minimum fee is enforced as a
+	// floor, as a commitment paying less wouldn't propagate.
+	feePerWeight, err := lnwallet.EstimateRelayableFeePerWeight(
+		l.cfg.FeeEstimator, 3,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -393,117 +759,154 @@ func (l *channelLink) syncChanStates() error {
 	// need to retransmit any data or not.
 	localChanSyncMsg, err := l.channel.ChanSyncMsg()
 	if err != nil {
-		return fmt.Errorf("unable to generate chan sync message for "+
-			"ChannelPoint(%v)", l.channel.ChannelPoint())
+		return newChanSyncError(ChanSyncInternalError, "unable to "+
+			"generate chan sync message for ChannelPoint(%v)",
+			l.channel.ChannelPoint())
 	}
 	if err := l.cfg.Peer.SendMessage(localChanSyncMsg); err != nil {
-		return fmt.Errorf("Unable to send chan sync message for "+
-			"ChannelPoint(%v)", l.channel.ChannelPoint())
+		return newChanSyncError(ChanSyncInternalError, "unable to "+
+			"send chan sync message for ChannelPoint(%v)",
+			l.channel.ChannelPoint())
 	}
 
-	var msgsToReSend []lnwire.Message
-
 	// Next, we'll wait to receive the ChanSync message with a timeout
 	// period. The first message sent MUST be the ChanSync message,
-	// otherwise, we'll terminate the connection.
-	chanSyncDeadline := time.After(time.Second * 30)
-	select {
-	case msg := <-l.upstream:
-		remoteChanSyncMsg, ok := msg.(*lnwire.ChannelReestablish)
-		if !ok {
-			return fmt.Errorf("first message sent to sync "+
-				"should be ChannelReestablish, instead "+
-				"received: %T", msg)
-		}
-
-		// If the remote party indicates that they think we haven't
-		// done any state updates yet, then we'll retransmit the
-		// funding locked message first. We do this, as at this point
-		// we can't be sure if they've really received the
-		// FundingLocked message.
-		if remoteChanSyncMsg.NextLocalCommitHeight == 1 &&
-			localChanSyncMsg.NextLocalCommitHeight == 1 &&
-			!l.channel.IsPending() {
-
-			log.Infof("ChannelPoint(%v): resending "+
-				"FundingLocked message to peer",
-				l.channel.ChannelPoint())
+	// otherwise, we'll terminate the connection. If it doesn't arrive in
+	// time, then we'll re-send our own ChanSync message and wait once
+	// again, up to the configured number of retries, as on slow
+	// connections the message may merely be delayed. The peer is still
+	// connected at this point, as the link is stopped otherwise.
+	var remoteChanSyncMsg *lnwire.ChannelReestablish
+	for attempt := 0; remoteChanSyncMsg == nil; attempt++ {
+		chanSyncDeadline := time.After(l.chanSyncTimeout())
 
-			nextRevocation, err := l.channel.NextRevocationKey()
-			if err != nil {
-				return fmt.Errorf("unable to create next "+
-					"revocation: %v", err)
+		select {
+		case msg := <-l.upstream:
+			chanSyncMsg, ok := msg.(*lnwire.ChannelReestablish)
+			if !ok {
+				return newChanSyncError(ChanSyncProtocolError,
+					"first message sent to sync should be "+
+						"ChannelReestablish, instead "+
+						"received: %T", msg)
 			}
+			remoteChanSyncMsg = chanSyncMsg
 
-			fundingLockedMsg := lnwire.NewFundingLocked(
-				l.ChanID(), nextRevocation,
-			)
-			err = l.cfg.Peer.SendMessage(fundingLockedMsg)
+		case <-l.quit:
+			return fmt.Errorf("shutting down")
+
+		case <-chanSyncDeadline:
+			if attempt >= l.cfg.ChanSyncRetries {
+				return newChanSyncError(ChanSyncTimeout,
+					"didn't receive ChannelReestablish "+
+						"within %v after %v attempt(s)",
+					l.chanSyncTimeout(), attempt+1)
+			}
+
+			log.Warnf("ChannelPoint(%v): didn't receive "+
+				"ChannelReestablish within %v, re-sending ours "+
+				"(retry %v of %v)", l.channel.ChannelPoint(),
+				l.chanSyncTimeout(), attempt+1,
+				l.cfg.ChanSyncRetries)
+
+			err := l.cfg.Peer.SendMessage(localChanSyncMsg)
 			if err != nil {
-				return fmt.Errorf("unable to re-send "+
-					"FundingLocked: %v", err)
+				return newChanSyncError(ChanSyncInternalError,
+					"unable to re-send chan sync message "+
+						"for ChannelPoint(%v)",
+					l.channel.ChannelPoint())
 			}
 		}
+	}
+
+	// We'll record the message before processing it, so it can be
+	// inspected should we fail to re-synchronize the channel.
+	err = l.channel.State().PutLastChanSync(remoteChanSyncMsg)
+	if err != nil {
+		log.Errorf("ChannelPoint(%v): unable to record chan sync "+
+			"message: %v", l.channel.ChannelPoint(), err)
+	}
 
-		// In any case, we'll then process their ChanSync message.
-		log.Infof("Received re-establishment message from remote side "+
-			"for channel(%v)", l.channel.ChannelPoint())
+	// If the remote party indicates that they think we haven't done any
+	// state updates yet, then we'll retransmit the funding locked message
+	// first. We do this, as at this point we can't be sure if they've
+	// really received the FundingLocked message.
+	if remoteChanSyncMsg.NextLocalCommitHeight == 1 &&
+		localChanSyncMsg.NextLocalCommitHeight == 1 &&
+		!l.channel.IsPending() {
 
-		// We've just received a ChnSync message from the remote party,
-		// so we'll process the message  in order to determine if we
-		// need to re-transmit any messages to the remote party.
-		msgsToReSend, err = l.channel.ProcessChanSyncMsg(remoteChanSyncMsg)
+		log.Infof("ChannelPoint(%v): resending FundingLocked message "+
+			"to peer", l.channel.ChannelPoint())
+
+		nextRevocation, err := l.channel.NextRevocationKey()
 		if err != nil {
-			// TODO(roasbeef): check concrete type of error, act
-			// accordingly
-			return fmt.Errorf("unable to handle upstream reestablish "+
-				"message: %v", err)
+			return newChanSyncError(ChanSyncInternalError, "unable "+
+				"to create next revocation: %v", err)
 		}
 
-		if len(msgsToReSend) > 0 {
-			log.Infof("Sending %v updates to synchronize the "+
-				"state for ChannelPoint(%v)", len(msgsToReSend),
-				l.channel.ChannelPoint())
+		fundingLockedMsg := lnwire.NewFundingLocked(
+			l.ChanID(), nextRevocation,
+		)
+		err = l.cfg.Peer.SendMessage(fundingLockedMsg)
+		if err != nil {
+			return newChanSyncError(ChanSyncInternalError, "unable "+
+				"to re-send FundingLocked: %v", err)
 		}
+	}
 
-		// If we have any messages to retransmit, we'll do so
-		// immediately so we return to a synchronized state as soon as
-		// possible.
-		for _, msg := range msgsToReSend {
-			l.cfg.Peer.SendMessage(msg)
-		}
+	// In any case, we'll then process their ChanSync message.
+	log.Infof("Received re-establishment message from remote side "+
+		"for channel(%v)", l.channel.ChannelPoint())
 
-	case <-l.quit:
-		return fmt.Errorf("shutting down")
+	// We've just received a ChnSync message from the remote party, so
+	// we'll process the message  in order to determine if we need to
+	// re-transmit any messages to the remote party.
+	msgsToReSend, err := l.channel.ProcessChanSyncMsg(remoteChanSyncMsg)
+	if err != nil {
+		// TODO(roasbeef): check concrete type of error, act
+		// accordingly
+		return newChanSyncError(ChanSyncProtocolError, "unable to "+
+			"handle upstream reestablish message: %v", err)
+	}
 
-	case <-chanSyncDeadline:
-		return fmt.Errorf("didn't receive ChannelReestablish before " +
-			"deadline")
+	if len(msgsToReSend) > 0 {
+		log.Infof("Sending %v updates to synchronize the state for "+
+			"ChannelPoint(%v)", len(msgsToReSend),
+			l.channel.ChannelPoint())
 	}
 
-	// In order to prep for the fragment below, we'll note if we
-	// retransmitted any HTLC's settles earlier. We'll track them by the
-	// HTLC index of the remote party in order to avoid erroneously sending
-	// a duplicate settle.
-	htlcsSettled := make(map[uint64]struct{})
+	// If we have any messages to retransmit, we'll do so immediately so we
+	// return to a synchronized state as soon as possible.
 	for _, msg := range msgsToReSend {
-		settleMsg, ok := msg.(*lnwire.UpdateFufillHTLC)
-		if !ok {
-			// If this isn't a settle message, then we'll skip it.
-			continue
-		}
+		l.cfg.Peer.SendMessage(msg)
+	}
 
-		// Otherwise, we'll note the ID of the HTLC we're settling so we
-		// don't duplicate it below.
-		htlcsSettled[settleMsg.ID] = struct{}{}
+	// Any updates we intended to send, but which weren't covered by a
+	// commitment we signed, have been forgotten by both parties. So we'll
+	// re-apply and retransmit those that resolve HTLCs which are still
+	// active. Resolutions we just retransmitted as part of a signed
+	// commitment are already within the channel's update log, so they
+	// won't be re-applied.
+	if err := l.replayOutgoingIntents(); err != nil {
+		l.fail("unable to replay outgoing intents: %v", err)
+		return err
+	}
+	l.clearOutgoingIntents()
+
+	// Next, we'll resolve any forwarding packages whose updates we may
+	// not have finished handling before going down. The HTLCs those
+	// packages process again are excluded from being settled below, while
+	// the unprocessed Adds we know the preimage for are acknowledged, and
+	// left to be settled below along with their invoices.
+	replayed, acked, err := l.resolveFwdPkgs()
+	if err != nil {
+		l.fail("unable to resolve forwarding packages: %v", err)
+		return err
 	}
 
 	// Now that we've synchronized our state, we'll check to see if
 	// there're any HTLC's that we received, but weren't able to settle
 	// directly the last time we were active. If we find any, then we'll
 	// send the settle message, then being to initiate a state transition.
-	//
-	// TODO(roasbeef): can later just inspect forwarding package
 	activeHTLCs := l.channel.ActiveHtlcs()
 	for _, htlc := range activeHTLCs {
 		if !htlc.Incoming {
@@ -511,9 +914,43 @@ func (l *channelLink) syncChanStates() error {
 		}
 
 		// Before we attempt to settle this HTLC, we'll check to see if
-		// we just re-sent it as part of the channel sync. If so, then
-		// we'll skip it.
-		if _, ok := htlcsSettled[htlc.HtlcIndex]; ok {
+		// the channel's update log already holds a resolution for it,
+		// which is the case if we just re-sent it as part of the
+		// channel sync, or re-applied it from our outgoing intents. If
+		// so, then we'll skip it, as settling it again would result in
+		// a duplicate settle.
+		if l.channel.IncomingHtlcResolved(htlc.HtlcIndex) {
+			continue
+		}
+		if _, ok := replayed[htlc.HtlcIndex]; ok {
+			continue
+		}
+
+		// HTLCs paying to a hold invoice are held once again until
+		// the invoice is settled or canceled, or settled straight
+		// away if it already has been.
+		invoice, lookupErr := l.cfg.Registry.LookupInvoice(htlc.RHash)
+		if lookupErr == nil && invoice.Terms.Hold {
+			// If the HTLC is a part of an AMP payment that's yet
+			// to complete, then it's registered with the registry
+			// once again, as the parts received are only tracked
+			// in memory.
+			if invoice.Terms.HoldState == channeldb.HoldAccepting {
+				l.reregisterAmpPart(htlc)
+			}
+
+			l.holdExitHtlc(&heldExitHtlc{
+				htlc: ExitHTLC{
+					ChanID:      l.ShortChanID(),
+					HtlcIndex:   htlc.HtlcIndex,
+					PaymentHash: htlc.RHash,
+					Amount:      htlc.Amt,
+					Expiry:      htlc.RefundTimeout,
+				},
+				preimage:  invoice.Terms.PaymentPreimage,
+				hold:      true,
+				onionBlob: htlc.OnionBlob,
+			})
 			continue
 		}
 
@@ -521,6 +958,13 @@ func (l *channelLink) syncChanStates() error {
 		// don't then we'll skip it.
 		preimage, ok := l.cfg.PreimageCache.LookupPreimage(htlc.RHash[:])
 		if !ok {
+			// HTLCs paying to a stateless invoice only have their
+			// preimage recorded once settled, so those which were
+			// held are held once again, with their invoice
+			// reconstructed from their onion.
+			if lookupErr != nil && l.cfg.StatelessInvoiceKey != nil {
+				l.holdStatelessHtlc(htlc)
+			}
 			continue
 		}
 
@@ -529,21 +973,61 @@ func (l *channelLink) syncChanStates() error {
 		// remote party.
 		var p [32]byte
 		copy(p[:], preimage)
+
+		// If we're the exit hop of the HTLC, and are settling such
+		// HTLCs safely, then we'll hold it until the acceptor, if
+		// any, has accepted it.
+		if l.cfg.SafeExitSettle {
+			_, err := l.cfg.Registry.LookupInvoice(htlc.RHash)
+			if err == nil {
+				l.holdExitHtlc(&heldExitHtlc{
+					htlc: ExitHTLC{
+						ChanID:      l.ShortChanID(),
+						HtlcIndex:   htlc.HtlcIndex,
+						PaymentHash: htlc.RHash,
+						Amount:      htlc.Amt,
+						Expiry:      htlc.RefundTimeout,
+					},
+					preimage:  p,
+					onionBlob: htlc.OnionBlob,
+				})
+				continue
+			}
+		}
+
 		err := l.channel.SettleHTLC(p, htlc.HtlcIndex)
 		if err != nil {
 			l.fail("unable to settle htlc: %v", err)
 			return err
 		}
 
-		// We'll now mark the HTLC as settled in the invoice database,
-		// then send the settle message to the remote party.
-		err = l.cfg.Registry.SettleInvoice(htlc.RHash)
-		if err != nil {
-			l.fail("unable to settle invoice: %v", err)
-			return err
+		// If the Add of the HTLC was only just acknowledged within its
+		// forwarding package, then we'll mark the invoice it pays to,
+		// if any, as settled. The Adds acknowledged before going down
+		// were either forwarded, or had their invoices settled as they
+		// were processed, so their invoices mustn't be settled again.
+		if _, ok := acked[htlc.HtlcIndex]; ok {
+			_, err := l.cfg.Registry.LookupInvoice(htlc.RHash)
+			if err == nil {
+				err = l.cfg.Registry.SettleInvoice(
+					htlc.RHash, channeldb.InvoiceHTLC{
+						ChanID:    l.ShortChanID(),
+						HtlcIndex: htlc.HtlcIndex,
+						Amt:       htlc.Amt,
+						Expiry:    htlc.RefundTimeout,
+					},
+				)
+				if err != nil {
+					l.fail("unable to settle "+
+						"invoice: %v", err)
+					return err
+				}
+			}
 		}
+
+		// Finally, we'll send the settle message to the remote party.
 		l.batchCounter++
-		l.cfg.Peer.SendMessage(&lnwire.UpdateFufillHTLC{
+		l.sendUpdate(&lnwire.UpdateFufillHTLC{
 			ChanID:          l.ChanID(),
 			ID:              htlc.HtlcIndex,
 			PaymentPreimage: p,
@@ -551,6 +1035,13 @@ func (l *channelLink) syncChanStates() error {
 
 	}
 
+	// Any held exit hop HTLCs which don't await the acceptor's decision
+	// can be settled straight away.
+	if _, err := l.resolveHeldExitHtlcs(); err != nil {
+		l.fail("unable to resolve held exit htlcs: %v", err)
+		return err
+	}
+
 	return nil
 }
 
@@ -578,22 +1069,112 @@ func (l *channelLink) htlcManager() {
 
 	// If this isn't the first time that this channel link has been
 	// created, then we'll need to check to see if we need to
-	// re-synchronize state with the remote peer. settledHtlcs is a map of
-	// HTLC's that we re-settled as part of the channel state sync.
+	// re-synchronize state with the remote peer.
 	if l.cfg.SyncStates {
-		// TODO(roasbeef): need to ensure haven't already settled?
 		if err := l.syncChanStates(); err != nil {
 			l.fail(err.Error())
 			return
 		}
 	}
 
-	batchTimer := time.NewTicker(50 * time.Millisecond)
+	// If uneconomical HTLCs are to be declined, then we'll sample the fee
+	// rate to assess them at now, rather than waiting for the next block.
+	l.refreshFeeSpikeRate()
+
+	batchTimer := time.NewTicker(l.cfg.BatchTicker)
 	defer batchTimer.Stop()
 
+	// Any outgoing HTLCs that are already active on the channel predate
+	// this link, so we'll track their age from now on. If reporting of
+	// stuck HTLCs is enabled, then we'll also periodically check for any
+	// outgoing HTLCs that have been unresolved for too long.
+	now := time.Now()
+	for _, htlc := range l.channel.ActiveHtlcs() {
+		if htlc.Incoming {
+			continue
+		}
+
+		l.trackOutgoingHtlc(htlc.HtlcIndex, &lnwire.UpdateAddHTLC{
+			ID:          htlc.HtlcIndex,
+			Amount:      htlc.Amt,
+			PaymentHash: htlc.RHash,
+			Expiry:      htlc.RefundTimeout,
+		}, now)
+	}
+
+	var stuckHtlcTick <-chan time.Time
+	if l.cfg.StuckHTLCThreshold != 0 {
+		stuckHtlcTicker := time.NewTicker(l.stuckHtlcCheckInterval())
+		defer stuckHtlcTicker.Stop()
+
+		stuckHtlcTick = stuckHtlcTicker.C
+	}
+
+	// If the HTLC statistics of the channel are to be recorded, then
+	// we'll do so periodically, as well as once the link exits.
+	l.htlcStatsSince = time.Now()
+	var htlcStatsTick <-chan time.Time
+	if l.cfg.RecordHTLCStats != nil {
+		htlcStatsTicker := time.NewTicker(htlcStatsInterval)
+		defer htlcStatsTicker.Stop()
+		defer func() {
+			l.recordHTLCStats(time.Now())
+		}()
+
+		htlcStatsTick = htlcStatsTicker.C
+	}
+
+	// We'll subscribe to the preimages discovered by the witness beacon,
+	// such as those used by the chain arbitrators to claim HTLCs on-chain,
+	// so that any of our incoming HTLCs paying to the same hash can be
+	// settled off-chain immediately. This is skipped if we've been
+	// instructed to hold onto HTLCs.
+	var witnessUpdates <-chan []byte
+	if !l.cfg.HodlHTLC {
+		witnessSub := l.cfg.PreimageCache.SubcribeUpdates()
+		if witnessSub != nil {
+			defer witnessSub.CancelSubcription()
+			witnessUpdates = witnessSub.WitnessUpdates
+		}
+	}
+
+	// Those awaiting quiescence are notified if the link exits before the
+	// channel is quiescent.
+	defer l.cancelStfu()
+
+	// The quiescence timer is only created once the link starts
+	// quiescing, so we'll stop it if it has been.
+	defer func() {
+		if l.quiescenceTimer != nil {
+			l.quiescenceTimer.Stop()
+		}
+	}()
+
 	// TODO(roasbeef): fail chan in case of protocol violation
 out:
 	for {
+		// If the link is being flushed, then we'll notify those
+		// awaiting the flush once the channel is clean.
+		l.notifyIfFlushed()
+
+		// If the link is quiescing, then we'll send our Stfu message
+		// once none of our updates remain pending. In the meantime,
+		// we'll no longer receive any packets from the switch, nor
+		// resolve any HTLCs, as either would have us initiate a new
+		// update.
+		l.sendStfuIfReady()
+
+		downstream := l.downstream
+		overflowPkts := l.overflowQueue.outgoingPkts
+		preimages := witnessUpdates
+		exitDecisions := l.exitDecisions
+		if l.isQuiescing() {
+			downstream = nil
+			overflowPkts = nil
+			preimages = nil
+			exitDecisions = nil
+		}
+
 		select {
 
 		// A new block has arrived, we'll check the network fee to see
@@ -606,9 +1187,29 @@ out:
 
 			l.bestHeight = uint32(blockEpoch.Height)
 
+			// Act upon any HTLCs which are about to expire, before
+			// considering a fee update.
+			l.auditExpiries(l.bestHeight)
+
+			// The fee rate at which we assess outgoing HTLCs is
+			// refreshed with each block, regardless of whether we
+			// control the commitment fee.
+			l.refreshFeeSpikeRate()
+
 			// If we're not the initiator of the channel, don't we
-			// don't control the fees, so we can ignore this.
-			if !l.channel.IsInitiator() {
+			// don't control the fees, so we can ignore this. Nor
+			// may we update the fee while the link is quiescing.
+			if !l.channel.IsInitiator() || l.isQuiescing() {
+				continue
+			}
+
+			// If the chain backend is currently unhealthy, then we
+			// can't trust the fee estimator, so we'll hold off on
+			// any fee updates until it recovers.
+			if l.cfg.ChainHealthy != nil && !l.cfg.ChainHealthy() {
+				log.Debugf("ChannelPoint(%v): skipping fee update "+
+					"as chain backend is unhealthy",
+					l.channel.ChannelPoint())
 				continue
 			}
 
@@ -628,6 +1229,19 @@ out:
 				continue
 			}
 
+			// So as not to flood the remote party with updates
+			// while the fee estimate is volatile, we'll wait out
+			// the minimum interval since our last update, and
+			// limit the step of each update.
+			if !l.feeUpdateDue(time.Now()) {
+				log.Debugf("ChannelPoint(%v): deferring fee "+
+					"update to %v sat/kw", l, feePerKw)
+				continue
+			}
+			feePerKw = limitFeeStep(
+				feePerKw, commitFee, l.cfg.MaxFeeUpdateStep,
+			)
+
 			// If we do, then we'll send a new UpdateFee message to
 			// the remote party, to be locked in with a new update.
 			if err := l.updateChannelFee(feePerKw); err != nil {
@@ -684,11 +1298,62 @@ out:
 				break out
 			}
 
+		// It's time to check whether any of our outgoing HTLCs have
+		// been unresolved for too long.
+		case <-stuckHtlcTick:
+			l.checkStuckHtlcs()
+
+		case now := <-htlcStatsTick:
+			l.recordHTLCStats(now)
+
+		// A new preimage has been discovered, so we'll settle any
+		// incoming HTLCs paying to its hash, committing the settles
+		// straight away.
+		case preimage, ok := <-preimages:
+			if !ok {
+				witnessUpdates = nil
+				continue
+			}
+
+			numSettled, err := l.settleWithWitness(preimage)
+			if err != nil {
+				l.fail("unable to settle incoming HTLC with "+
+					"witness: %v", err)
+				break out
+			}
+			if numSettled == 0 {
+				continue
+			}
+
+			if err := l.updateCommitTx(); err != nil {
+				l.fail("unable to update commitment: %v", err)
+				break out
+			}
+
+		// The acceptor has decided upon a held HTLC for which we're
+		// the exit hop, so we'll settle or fail it if we're now able
+		// to.
+		case decision := <-exitDecisions:
+			updated, err := l.handleExitDecision(decision)
+			if err != nil {
+				l.fail("unable to resolve held exit htlc: %v",
+					err)
+				break out
+			}
+			if !updated {
+				continue
+			}
+
+			if err := l.updateCommitTx(); err != nil {
+				l.fail("unable to update commitment: %v", err)
+				break out
+			}
+
 		// A packet that previously overflowed the commitment
 		// transaction is now eligible for processing once again. So
 		// we'll attempt to re-process the packet in order to allow it
 		// to continue propagating within the network.
-		case packet := <-l.overflowQueue.outgoingPkts:
+		case packet := <-overflowPkts:
 			msg := packet.htlc.(*lnwire.UpdateAddHTLC)
 			log.Tracef("Reprocessing downstream add update "+
 				"with payment hash(%x)", msg.PaymentHash[:])
@@ -698,20 +1363,14 @@ out:
 		// A message from the switch was just received. This indicates
 		// that the link is an intermediate hop in a multi-hop HTLC
 		// circuit.
-		case pkt := <-l.downstream:
+		case pkt := <-downstream:
 			// If we have non empty processing queue then we'll add
 			// this to the overflow rather than processing it
 			// directly. Once an active HTLC is either settled or
 			// failed, then we'll free up a new slot.
 			htlc, ok := pkt.htlc.(*lnwire.UpdateAddHTLC)
-			if ok && l.overflowQueue.Length() != 0 {
-				log.Infof("Downstream htlc add update with "+
-					"payment hash(%x) have been added to "+
-					"reprocessing queue, batch_size=%v",
-					htlc.PaymentHash[:],
-					l.batchCounter)
-
-				l.overflowQueue.AddPkt(pkt)
+			if ok && l.overflowQueue.Length() != 0 && !l.isFlushing() {
+				l.handleOverflow(pkt, htlc)
 				continue
 			}
 			l.handleDownStreamPkt(pkt, false)
@@ -731,6 +1390,7 @@ out:
 				// with a "null" field in the new policy, we'll
 				// only update to the set sub policy if the new
 				// value isn't uninitialized.
+				l.policyMtx.Lock()
 				if req.policy.BaseFee != 0 {
 					l.cfg.FwrdingPolicy.BaseFee = req.policy.BaseFee
 				}
@@ -740,18 +1400,82 @@ out:
 				if req.policy.TimeLockDelta != 0 {
 					l.cfg.FwrdingPolicy.TimeLockDelta = req.policy.TimeLockDelta
 				}
+				if req.policy.MaxHTLC != 0 {
+					l.cfg.FwrdingPolicy.MaxHTLC = req.policy.MaxHTLC
+				}
+				if req.policy.FeeController != nil {
+					l.cfg.FwrdingPolicy.FeeController = req.policy.FeeController
+				}
+				l.policyMtx.Unlock()
 
 				if req.done != nil {
 					close(req.done)
 				}
+
+			case *linkSnapshotReq:
+				req.resp <- l.snapshot()
+
+			case *flushReq:
+				l.startFlush(req)
+
+			case *stfuReq:
+				l.startStfu(req)
 			}
 
+		// The link has been quiescing for too long. As quiescence only
+		// ends once the peer reconnects, we'll disconnect it rather
+		// than leave the channel unable to resolve any HTLCs.
+		case <-l.quiescenceTimeout():
+			l.fail("channel still quiescent after %v",
+				l.cfg.QuiescenceTimeout)
+			break out
+
 		case <-l.quit:
 			break out
 		}
 	}
 }
 
+// failAddPacket fails the HTLC add carried by the passed packet back to the
+// switch with a TemporaryChannelFailure, as it couldn't be added to the
+// channel.
+func (l *channelLink) failAddPacket(pkt *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC) {
+
+	l.failAddPacketWith(pkt, htlc, lnwire.NewTemporaryChannelFailure(nil))
+}
+
+// failAddPacketWith fails the HTLC add carried by the passed packet back to
+// the switch with the given failure.
+func (l *channelLink) failAddPacketWith(pkt *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC, failure lnwire.FailureMessage) {
+
+	failPkt, err := newAddFailPacket(pkt, htlc, failure)
+	if err != nil {
+		log.Errorf("unable to fail htlc(%x): %v", htlc.PaymentHash[:],
+			err)
+		return
+	}
+	l.cfg.Switch.traceAdd(pkt, TraceFailed, l.ShortChanID())
+
+	// TODO(roasbeef): need to identify if sent from switch so don't need
+	// to obfuscate
+	go l.cfg.Switch.forward(failPkt)
+}
+
+// temporaryChannelFailure returns the failure with which HTLCs exceeding the
+// MaxHTLC of our forwarding policy, or the HTLC limits of the link, are
+// rejected. The failure is a TemporaryChannelFailure carrying our latest
+// channel update, so the sender obtains the most up to date data.
+func (l *channelLink) temporaryChannelFailure() lnwire.FailureMessage {
+	update, err := l.cfg.GetLastChannelUpdate()
+	if err != nil {
+		return lnwire.NewTemporaryChannelFailure(nil)
+	}
+
+	return lnwire.NewTemporaryChannelFailure(update)
+}
+
 // handleDownStreamPkt processes an HTLC packet sent from the downstream HTLC
 // Switch. Possible messages sent by the switch include requests to forward new
 // HTLCs, timeout previously cleared HTLCs, and finally to settle currently
@@ -766,99 +1490,166 @@ func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket, isReProcess bool) {
 		// so we add the new HTLC to our local log, then update the
 		// commitment chains.
 		htlc.ChanID = l.ChanID()
-		index, err := l.channel.AddHTLC(htlc)
+
+		// If the link is being flushed, then no new HTLCs may be added
+		// to the channel.
+		if l.isFlushing() {
+			l.failFlushedAdd(pkt, htlc)
+			return
+		}
+
+		// Before adding the HTLC, we'll ensure that it doesn't exceed
+		// the largest HTLC we're willing to offer over this channel.
+		// If it does, then we'll fail it back along with our latest
+		// channel update.
+		maxHTLC := l.cfg.FwrdingPolicy.MaxHTLC
+		if maxHTLC != 0 && htlc.Amount > maxHTLC {
+			log.Warnf("ChannelPoint(%v): outgoing htlc(%x) is too "+
+				"large: max_htlc=%v, htlc_value=%v",
+				l.channel.ChannelPoint(), htlc.PaymentHash[:],
+				maxHTLC, htlc.Amount)
+
+			l.failAddPacketWith(pkt, htlc, l.temporaryChannelFailure())
+			return
+		}
+
+		// If the HTLC is being forwarded, and claiming it on-chain at
+		// the prevailing fee rate would cost more than it's worth,
+		// then we'll decline to offer it, as it would be lost should
+		// the channel be force closed while it's in flight.
+		isForward := pkt.incomingChanID != (lnwire.ShortChannelID{})
+		if isForward && l.feeSpike.uneconomical(htlc.Amount) {
+			log.Warnf("ChannelPoint(%v): declining uneconomical "+
+				"htlc(%x): htlc_value=%v, claim_fee=%v",
+				l.channel.ChannelPoint(), htlc.PaymentHash[:],
+				htlc.Amount, l.feeSpike.claimFee())
+
+			l.feeSpike.recordDecline(htlc.Amount, time.Now())
+			l.failAddPacketWith(pkt, htlc, l.temporaryChannelFailure())
+			return
+		}
+
+		// Similarly, we'll ensure that offering the HTLC won't exceed
+		// the HTLC limits of the link. If it does, then we'll let the
+		// switch retry it on another link to the same peer, before
+		// failing it back.
+		if err := l.checkOutgoingLimits(htlc); err != nil {
+			if l.retryForward(pkt, err) {
+				return
+			}
+
+			l.failAddPacketWith(pkt, htlc, l.temporaryChannelFailure())
+			return
+		}
+
+		// Before the HTLC is offered, we'll open and persist its
+		// circuit, keyed by the ID the channel is about to assign it,
+		// so that its settle or fail can be propagated back even after
+		// a restart. If the circuit can't be opened, then the HTLC
+		// isn't offered at all.
+		circuit := &PaymentCircuit{
+			PaymentHash:    htlc.PaymentHash,
+			IncomingChanID: pkt.incomingChanID,
+			IncomingHTLCID: pkt.incomingHTLCID,
+			OutgoingChanID: l.ShortChanID(),
+			OutgoingHTLCID: l.channel.LocalHtlcIndex(),
+			IncomingAmount: pkt.incomingAmount,
+			OutgoingAmount: htlc.Amount,
+			ErrorEncrypter: pkt.obfuscator,
+			TraceID:        pkt.traceID,
+		}
+		err := l.cfg.Switch.openCircuit(circuit)
+		switch {
+		// The incoming HTLC has already been forwarded, so it's
+		// resolved through its existing circuit.
+		case err == ErrDuplicateCircuit:
+			log.Warnf("ChannelPoint(%v): dropping duplicate "+
+				"forward of htlc (%s, %d)",
+				l.channel.ChannelPoint(), pkt.incomingChanID,
+				pkt.incomingHTLCID)
+			return
+
+		case err != nil:
+			log.Errorf("ChannelPoint(%v): unable to open "+
+				"circuit for htlc (%s, %d): %v",
+				l.channel.ChannelPoint(), pkt.incomingChanID,
+				pkt.incomingHTLCID, err)
+			l.failAddPacketWith(
+				pkt, htlc, l.temporaryChannelFailure(),
+			)
+			return
+		}
+
+		index, err := l.addHTLC(pkt, htlc)
 		if err != nil {
+			// As the HTLC wasn't added, its circuit must be
+			// removed before it's retried or failed back.
+			rmErr := l.cfg.Switch.removeCircuit(circuit)
+			if rmErr != nil {
+				log.Errorf("ChannelPoint(%v): unable to remove "+
+					"circuit of htlc (%s, %d): %v",
+					l.channel.ChannelPoint(),
+					pkt.incomingChanID, pkt.incomingHTLCID,
+					rmErr)
+			}
+
 			switch err {
 
 			// The channels spare bandwidth is fully allocated, so
-			// we'll put this HTLC into the overflow queue.
+			// we'll handle this HTLC according to our overflow
+			// policy.
 			case lnwallet.ErrMaxHTLCNumber:
-				log.Infof("Downstream htlc add update with "+
-					"payment hash(%x) have been added to "+
-					"reprocessing queue, batch: %v",
-					htlc.PaymentHash[:],
-					l.batchCounter)
-
-				l.overflowQueue.AddPkt(pkt)
+				l.handleOverflow(pkt, htlc)
 				return
 
 			// The HTLC was unable to be added to the state
-			// machine, as a result, we'll signal the switch to
-			// cancel the pending payment.
+			// machine. If it's being forwarded, then we'll let the
+			// switch retry it on another link to the same peer.
+			// Otherwise, we'll signal the switch to cancel the
+			// pending payment.
 			default:
-				log.Warnf("Unable to handle downstream add HTLC: %v", err)
-
-				var (
-					localFailure = false
-					reason       lnwire.OpaqueReason
-				)
-
-				failure := lnwire.NewTemporaryChannelFailure(nil)
-
-				// Encrypt the error back to the source unless the payment was
-				// generated locally.
-				if pkt.obfuscator == nil {
-					var b bytes.Buffer
-					err := lnwire.EncodeFailure(&b, failure, 0)
-					if err != nil {
-						log.Errorf("unable to encode failure: %v", err)
-						return
-					}
-					reason = lnwire.OpaqueReason(b.Bytes())
-					localFailure = true
-				} else {
-					var err error
-					reason, err = pkt.obfuscator.EncryptFirstHop(failure)
-					if err != nil {
-						log.Errorf("unable to obfuscate error: %v", err)
-						return
-					}
+				if l.retryForward(pkt, err) {
+					return
 				}
 
-				failPkt := &htlcPacket{
-					incomingChanID: pkt.incomingChanID,
-					incomingHTLCID: pkt.incomingHTLCID,
-					amount:         htlc.Amount,
-					isRouted:       true,
-					localFailure:   localFailure,
-					htlc: &lnwire.UpdateFailHTLC{
-						Reason: reason,
-					},
-				}
+				log.Warnf("Unable to handle downstream add HTLC: %v", err)
 
-				// TODO(roasbeef): need to identify if sent
-				// from switch so don't need to obfuscate
-				go l.cfg.Switch.forward(failPkt)
+				l.failAddPacket(pkt, htlc)
 				return
 			}
 		}
 
 		log.Tracef("Received downstream htlc: payment_hash=%x, "+
-			"local_log_index=%v, batch_size=%v",
-			htlc.PaymentHash[:], index, l.batchCounter+1)
+			"local_log_index=%v, batch_size=%v, trace=%v",
+			htlc.PaymentHash[:], index, l.batchCounter+1,
+			pkt.traceID)
 
-		// Create circuit (remember the path) in order to forward settle/fail
-		// packet back.
-		l.cfg.Switch.addCircuit(&PaymentCircuit{
-			PaymentHash:    htlc.PaymentHash,
-			IncomingChanID: pkt.incomingChanID,
-			IncomingHTLCID: pkt.incomingHTLCID,
-			OutgoingChanID: l.ShortChanID(),
-			OutgoingHTLCID: index,
-			ErrorEncrypter: pkt.obfuscator,
-		})
+		l.cfg.Switch.traceAdd(pkt, TraceAdded, l.ShortChanID())
 
 		htlc.ID = index
-		l.cfg.Peer.SendMessage(htlc)
+		l.trackOutgoingHtlc(index, htlc, time.Now())
+		l.htlcStats.NumForwarded++
+		l.sendUpdate(htlc)
 
 	case *lnwire.UpdateFufillHTLC:
+		// If we've already settled the HTLC using a preimage
+		// discovered by the witness beacon, then there's nothing left
+		// to do.
+		if l.consumeWitnessSettle(pkt.incomingHTLCID) {
+			log.Debugf("ChannelPoint(%v): ignoring settle of htlc=%v "+
+				"already settled by witness",
+				l.channel.ChannelPoint(), pkt.incomingHTLCID)
+			return
+		}
+
 		// An HTLC we forward to the switch has just settled somewhere
 		// upstream. Therefore we settle the HTLC within the our local
 		// state machine.
 		err := l.channel.SettleHTLC(htlc.PaymentPreimage, pkt.incomingHTLCID)
 		if err != nil {
 			// TODO(roasbeef): broadcast on-chain
-			l.fail("unable to settle incoming HTLC: %v", err)
+			l.failForceClose("unable to settle incoming HTLC: %v",
+				err)
 			return
 		}
 
@@ -870,10 +1661,20 @@ func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket, isReProcess bool) {
 
 		// Then we send the HTLC settle message to the connected peer
 		// so we can continue the propagation of the settle message.
-		l.cfg.Peer.SendMessage(htlc)
+		l.sendUpdate(htlc)
 		isSettle = true
 
 	case *lnwire.UpdateFailHTLC:
+		// If we've already settled the HTLC using a preimage
+		// discovered by the witness beacon, then it can no longer be
+		// cancelled.
+		if l.consumeWitnessSettle(pkt.incomingHTLCID) {
+			log.Debugf("ChannelPoint(%v): ignoring cancel of htlc=%v "+
+				"already settled by witness",
+				l.channel.ChannelPoint(), pkt.incomingHTLCID)
+			return
+		}
+
 		// An HTLC cancellation has been triggered somewhere upstream,
 		// we'll remove then HTLC from our local state machine.
 		err := l.channel.FailHTLC(pkt.incomingHTLCID, htlc.Reason)
@@ -891,7 +1692,7 @@ func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket, isReProcess bool) {
 
 		// Finally, we send the HTLC message to the peer which
 		// initially created the HTLC.
-		l.cfg.Peer.SendMessage(htlc)
+		l.sendUpdate(htlc)
 		isSettle = true
 	}
 
@@ -899,7 +1700,7 @@ func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket, isReProcess bool) {
 
 	// If this newly added update exceeds the min batch size for adds, or
 	// this is a settle request, then initiate an update.
-	if l.batchCounter >= 10 || isSettle {
+	if l.batchCounter >= l.cfg.BatchSize || isSettle {
 		if err := l.updateCommitTx(); err != nil {
 			l.fail("unable to update commitment: %v", err)
 			return
@@ -907,10 +1708,41 @@ func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket, isReProcess bool) {
 	}
 }
 
+// retryForward hands a forwarded HTLC that couldn't be added to the channel
+// back to the switch, so that the forward may be retried on another link to
+// the same peer. The HTLC is only failed back once all such links have been
+// exhausted. If the HTLC was initiated locally, then it isn't retried, and
+// false is returned.
+func (l *channelLink) retryForward(pkt *htlcPacket, addErr error) bool {
+	if pkt.incomingChanID == (lnwire.ShortChannelID{}) {
+		return false
+	}
+
+	htlc := pkt.htlc.(*lnwire.UpdateAddHTLC)
+	log.Debugf("ChannelPoint(%v): unable to add forwarded htlc with "+
+		"payment_hash=%x, retrying on another link: %v",
+		l.channel.ChannelPoint(), htlc.PaymentHash[:], addErr)
+
+	if pkt.attemptedLinks == nil {
+		pkt.attemptedLinks = make(map[lnwire.ShortChannelID]struct{})
+	}
+	pkt.attemptedLinks[l.ShortChanID()] = struct{}{}
+
+	go l.cfg.Switch.forward(pkt)
+	return true
+}
+
 // handleUpstreamMsg processes wire messages related to commitment state
 // updates from the upstream peer. The upstream peer is the peer whom we have a
 // direct channel with, updating our respective commitment chains.
 func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
+	// Once the remote party has sent its Stfu message, it may no longer
+	// send us any updates.
+	if err := l.checkQuiescentUpdate(msg); err != nil {
+		l.fail("protocol violation: %v", err)
+		return
+	}
+
 	switch msg := msg.(type) {
 
 	case *lnwire.UpdateAddHTLC:
@@ -923,6 +1755,19 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			return
 		}
 
+		// The remote party must respect the maximum value in flight
+		// we set for it when the channel was opened.
+		if err := l.checkIncomingInFlight(); err != nil {
+			l.fail("unable to handle upstream add HTLC: %v", err)
+			return
+		}
+
+		l.recordIncomingEndorsement(index, msg)
+
+		// If the HTLC exceeds the HTLC limits of the link, then it'll
+		// be failed back once locked in, rather than failing the link.
+		l.checkIncomingLimits(index, msg)
+
 		log.Tracef("Receive upstream htlc with payment hash(%x), "+
 			"assigning index: %v", msg.PaymentHash[:], index)
 
@@ -931,11 +1776,17 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		idx := msg.ID
 		if err := l.channel.ReceiveHTLCSettle(pre, idx); err != nil {
 			// TODO(roasbeef): broadcast on-chain
-			l.fail("unable to handle upstream settle HTLC: %v", err)
+			l.failForceClose("unable to handle upstream settle "+
+				"HTLC: %v", err)
 			return
 		}
+		l.resolveOutgoingHtlc(idx, true)
+		l.feeOutcomes.record(false)
 
-		// TODO(roasbeef): pipeline to switch
+		// With the preimage validated, we'll relay the settle to the
+		// switch right away, rather than waiting for it to be locked
+		// in.
+		l.pipelineSettle(idx, pre)
 
 		// As we've learned of a new preimage for the first time, we'll
 		// add it to to our preimage cache. By doing this, we ensure
@@ -988,6 +1839,8 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			l.fail("unable to handle upstream fail HTLC: %v", err)
 			return
 		}
+		l.resolveOutgoingHtlc(msg.ID, false)
+		l.feeOutcomes.record(true)
 
 	case *lnwire.UpdateFailHTLC:
 		idx := msg.ID
@@ -996,6 +1849,8 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			l.fail("unable to handle upstream fail HTLC: %v", err)
 			return
 		}
+		l.resolveOutgoingHtlc(idx, false)
+		l.feeOutcomes.record(true)
 
 	case *lnwire.CommitSig:
 		// We just received a new updates to our local commitment
@@ -1016,11 +1871,14 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 				})
 			}
 
-			l.fail("ChannelPoint(%v): unable to accept new "+
-				"commitment: %v", l.channel.ChannelPoint(), err)
+			l.failForceClose("ChannelPoint(%v): unable to accept "+
+				"new commitment: %v", l.channel.ChannelPoint(),
+				err)
 			return
 		}
 
+		l.lastCommitUpdate = time.Now()
+
 		// As we've just just accepted a new state, we'll now
 		// immediately send the remote peer a revocation for our prior
 		// state.
@@ -1043,7 +1901,8 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// As we've just received a commitment signature, we'll
 		// re-start the log commit timer to wake up the main processing
 		// loop to check if we need to send a commitment signature as
-		// we owe one.
+		// we owe one. The remote party is given a few round trips to
+		// follow up with its own signature in the meantime.
 		//
 		// TODO(roasbeef): instead after revocation?
 		if !l.logCommitTimer.Stop() {
@@ -1052,7 +1911,9 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			default:
 			}
 		}
-		l.logCommitTimer.Reset(300 * time.Millisecond)
+		l.logCommitTimer.Reset(l.rtt.pendingCommitTimeout(
+			l.cfg.PendingCommitTicker, l.cfg.MaxPendingCommitTicker,
+		))
 		l.logCommitTick = l.logCommitTimer.C
 
 		// If both commitment chains are fully synced from our PoV,
@@ -1074,9 +1935,20 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// We've received a revocation from the remote chain, if valid,
 		// this moves the remote chain forward, and expands our
 		// revocation window.
-		htlcs, err := l.channel.ReceiveRevocation(msg)
+		fwdPkg, htlcs, err := l.channel.ReceiveRevocation(msg)
 		if err != nil {
-			l.fail("unable to accept revocation: %v", err)
+			l.failForceClose("unable to accept revocation: %v", err)
+			return
+		}
+		l.rtt.revocationReceived(time.Now())
+
+		// While the link is quiescing, the HTLCs that were locked in
+		// are left within their forwarding package, as resolving them
+		// may have us initiate a new update. They'll be processed once
+		// the link is restarted.
+		if l.isQuiescing() {
+			log.Debugf("ChannelPoint(%v): deferring %v as link is "+
+				"quiescing", l.channel.ChannelPoint(), fwdPkg)
 			return
 		}
 
@@ -1084,28 +1956,41 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// commitment transactions they might be safely propagated over
 		// htlc switch or settled if our node was last node in htlc
 		// path.
-		htlcsToForward := l.processLockedInHtlcs(htlcs)
-		go func() {
-			log.Debugf("ChannelPoint(%v) forwarding %v HTLC's",
-				l.channel.ChannelPoint(), len(htlcsToForward))
-			for _, packet := range htlcsToForward {
-				if err := l.cfg.Switch.forward(packet); err != nil {
-					log.Errorf("channel link(%v): "+
-						"unhandled error while forwarding "+
-						"htlc packet over htlc  "+
-						"switch: %v", l, err)
-				}
-			}
-		}()
+		htlcsToForward := l.processLockedInHtlcs(fwdPkg, htlcs)
+		l.forwardBatch(htlcsToForward)
 
 	case *lnwire.UpdateFee:
 		// We received fee update from peer. If we are the initiator we
-		// will fail the channel, if not we will apply the update.
+		// will fail the channel, if not we will apply the update, as
+		// long as it's within the bounds we accept.
 		fee := btcutil.Amount(msg.FeePerKw)
+		if err := l.checkRemoteFee(fee); err != nil {
+			l.fail("rejecting fee update: %v", err)
+			return
+		}
 		if err := l.channel.ReceiveUpdateFee(fee); err != nil {
 			l.fail("error receiving fee update: %v", err)
 			return
 		}
+
+	case *lnwire.OpaqueMessage:
+		// We received a message of a type we don't understand. Unless
+		// it's of an even type and we're strictly enforcing BOLT #1,
+		// we'll ignore it.
+		if l.cfg.StrictUnknownMsgs && !msg.IsOdd() {
+			l.fail("received message of unknown even type %v",
+				uint16(msg.Type))
+			return
+		}
+
+		log.Debugf("ChannelPoint(%v): ignoring message of unknown "+
+			"type %v", l.channel.ChannelPoint(), uint16(msg.Type))
+
+	case *lnwire.Stfu:
+		if err := l.handleStfu(msg); err != nil {
+			l.fail("protocol violation: %v", err)
+			return
+		}
 	}
 }
 
@@ -1122,12 +2007,19 @@ func (l *channelLink) updateCommitTx() error {
 		return err
 	}
 
+	// The new commitment, along with the updates it covers, has been
+	// persisted, so our intents to send those updates are no longer
+	// needed.
+	l.clearOutgoingIntents()
+
 	commitSig := &lnwire.CommitSig{
 		ChanID:    l.ChanID(),
 		CommitSig: theirCommitSig,
 		HtlcSigs:  htlcSigs,
 	}
 	l.cfg.Peer.SendMessage(commitSig)
+	l.lastCommitUpdate = time.Now()
+	l.rtt.commitSent(l.lastCommitUpdate)
 
 	// We've just initiated a state transition, attempt to stop the
 	// logCommitTimer. If the timer already ticked, then we'll consume the
@@ -1172,6 +2064,14 @@ func (l *channelLink) ChanID() lnwire.ChannelID {
 	return lnwire.NewChanIDFromOutPoint(l.channel.ChannelPoint())
 }
 
+// ChannelPoint returns the funding outpoint of the channel the link operates
+// over.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) ChannelPoint() *wire.OutPoint {
+	return l.channel.ChannelPoint()
+}
+
 // getBandwidthCmd is a wrapper for get bandwidth handler.
 type getBandwidthCmd struct {
 	resp chan lnwire.MilliSatoshi
@@ -1184,11 +2084,55 @@ type getBandwidthCmd struct {
 //
 // NOTE: Part of the ChannelLink interface.
 func (l *channelLink) Bandwidth() lnwire.MilliSatoshi {
-	// TODO(roasbeef): subtract reserve
 	channelBandwidth := l.channel.AvailableBalance()
 	overflowBandwidth := l.overflowQueue.TotalHtlcAmount()
 
-	return channelBandwidth - overflowBandwidth
+	// We're required to keep our settled balance above the reserve the
+	// remote party has set for us, so that portion of our balance can't
+	// be used to forward HTLCs.
+	reserve := lnwire.NewMSatFromSatoshis(l.channel.LocalChanReserve())
+	if channelBandwidth < overflowBandwidth+reserve {
+		return 0
+	}
+	bandwidth := channelBandwidth - overflowBandwidth - reserve
+
+	// Additionally, we can't offer HTLCs beyond the maximum value in
+	// flight the remote party permits us.
+	headroom, _ := l.InFlightHeadroom()
+	if bandwidth > headroom {
+		return headroom
+	}
+
+	return bandwidth
+}
+
+// ReceivableBandwidth returns the total amount that the remote party is able
+// to send to us through the channel link at this given instance. It's the
+// counterpart of Bandwidth, accounting for the remote party's reserve, the
+// HTLCs it has yet to have locked in, and the maximum value in flight we
+// permit it.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) ReceivableBandwidth() lnwire.MilliSatoshi {
+	channelBandwidth := l.channel.RemoteAvailableBalance()
+
+	// The remote party is required to keep its settled balance above the
+	// reserve we've set for it, so that portion of its balance can't be
+	// sent to us.
+	reserve := lnwire.NewMSatFromSatoshis(l.channel.RemoteChanReserve())
+	if channelBandwidth < reserve {
+		return 0
+	}
+	bandwidth := channelBandwidth - reserve
+
+	// Additionally, the remote party can't offer HTLCs beyond the maximum
+	// value in flight we permit it.
+	_, headroom := l.InFlightHeadroom()
+	if bandwidth > headroom {
+		return headroom
+	}
+
+	return bandwidth
 }
 
 // policyUpdate is a message sent to a channel link when an outside sub-system
@@ -1204,7 +2148,8 @@ type policyUpdate struct {
 // govern if it an incoming HTLC should be forwarded or not. Note that this
 // processing of the new policy will ensure that uninitialized fields in the
 // passed policy won't override already initialized fields in the current
-// policy.
+// policy. If the new policy carries a FeeController, then it replaces the
+// link's current one, and is consulted from the next HTLC forwarded onwards.
 //
 // NOTE: Part of the ChannelLink interface.
 func (l *channelLink) UpdateForwardingPolicy(newPolicy ForwardingPolicy) {
@@ -1224,6 +2169,16 @@ func (l *channelLink) UpdateForwardingPolicy(newPolicy ForwardingPolicy) {
 	}
 }
 
+// ForwardingPolicy returns the current forwarding policy of the link.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) ForwardingPolicy() ForwardingPolicy {
+	l.policyMtx.RLock()
+	defer l.policyMtx.RUnlock()
+
+	return l.cfg.FwrdingPolicy
+}
+
 // Stats returns the statistics of channel link.
 //
 // NOTE: Part of the ChannelLink interface.
@@ -1248,7 +2203,26 @@ func (l *channelLink) String() string {
 //
 // NOTE: Part of the ChannelLink interface.
 func (l *channelLink) HandleSwitchPacket(packet *htlcPacket) {
-	l.mailBox.AddPacket(packet)
+	l.cfg.Switch.traceAdd(packet, TraceMailbox, l.ShortChanID())
+
+	err := l.mailBox.AddPacket(packet)
+	if err == nil {
+		return
+	}
+
+	// Only htlc adds are rejected by the mailbox, which happens once it's
+	// full. Rather than have the HTLC wait behind the backlog of the link,
+	// we'll let the switch retry it over another link to the same peer,
+	// or fail it back.
+	htlc := packet.htlc.(*lnwire.UpdateAddHTLC)
+	log.Warnf("ChannelPoint(%v): rejecting htlc(%x): %v",
+		l.channel.ChannelPoint(), htlc.PaymentHash[:], err)
+
+	if l.retryForward(packet, err) {
+		return
+	}
+
+	l.failAddPacketWith(packet, htlc, l.temporaryChannelFailure())
 }
 
 // HandleChannelUpdate handles the htlc requests as settle/add/fail which sent
@@ -1256,7 +2230,12 @@ func (l *channelLink) HandleSwitchPacket(packet *htlcPacket) {
 //
 // NOTE: Part of the ChannelLink interface.
 func (l *channelLink) HandleChannelUpdate(message lnwire.Message) {
-	l.mailBox.AddMessage(message)
+	// If the mailbox is full, then this blocks until the link has caught
+	// up with the remote peer's messages.
+	if err := l.mailBox.AddMessage(message); err != nil {
+		log.Debugf("ChannelPoint(%v): dropping %T: %v",
+			l.channel.ChannelPoint(), message, err)
+	}
 }
 
 // updateChannelFee updates the commitment fee-per-kw on this channel by
@@ -1279,36 +2258,140 @@ func (l *channelLink) updateChannelFee(feePerKw btcutil.Amount) error {
 		return err
 	}
 
-	// We'll then attempt to send a new UpdateFee message, and also lock it
-	// in immediately by triggering a commitment update.
+	// We'll then attempt to send a new UpdateFee message. Rather than
+	// forcing a commitment update, it's batched along with any other
+	// pending updates, and locked in by the next commitment.
 	msg := lnwire.NewUpdateFee(l.ChanID(), uint32(feePerKw))
 	if err := l.cfg.Peer.SendMessage(msg); err != nil {
 		return err
 	}
-	return l.updateCommitTx()
+	l.lastFeeUpdate = time.Now()
+	l.batchCounter++
+
+	return nil
+}
+
+// decodeHopIterators decodes the onion packets of the Adds among the passed
+// payment descriptors, which are those of the passed forwarding package, as a
+// single batch identified by the package. The responses are indexed by the
+// index of each Add within the package.
+func (l *channelLink) decodeHopIterators(fwdPkg *channeldb.FwdPkg,
+	paymentDescriptors []*lnwallet.PaymentDescriptor) (
+	[]DecodeHopIteratorResponse, error) {
+
+	var reqs []DecodeHopIteratorRequest
+	for _, pd := range paymentDescriptors {
+		if pd.EntryType != lnwallet.Add {
+			continue
+		}
+
+		var onionBlob [lnwire.OnionPacketSize]byte
+		copy(onionBlob[:], pd.OnionBlob)
+
+		// We include the payment hash of the htlc as it's
+		// authenticated within the Sphinx packet itself as associated
+		// data in order to thwart attempts a replay attacks. In the
+		// case of a replay, an attacker is *forced* to use the same
+		// payment hash twice, thereby losing their money entirely.
+		reqs = append(reqs, DecodeHopIteratorRequest{
+			OnionReader:  bytes.NewReader(onionBlob[:]),
+			RHash:        pd.RHash[:],
+			IncomingCltv: pd.Timeout,
+		})
+	}
+
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	var batchID [16]byte
+	binary.BigEndian.PutUint64(batchID[:8], fwdPkg.Source.ToUint64())
+	binary.BigEndian.PutUint64(batchID[8:], fwdPkg.Height)
+
+	return l.cfg.OnionProcessor.DecodeHopIterators(
+		batchID[:], l.bestHeight, reqs,
+	)
 }
 
 // processLockedInHtlcs serially processes each of the log updates which have
 // been "locked-in". An HTLC is considered locked-in once it has been fully
 // committed to in both the remote and local commitment state. Once a channel
 // updates is locked-in, then it can be acted upon, meaning: settling HTLCs,
-// cancelling them, or forwarding new HTLCs to the next hop.
-func (l *channelLink) processLockedInHtlcs(
+// cancelling them, or forwarding new HTLCs to the next hop. The updates are
+// those of the passed forwarding package, in which the Settles and Fails, and
+// the Adds, each appear in the same order as within the payment descriptors.
+// Any updates that the package marks as acknowledged are skipped.
+func (l *channelLink) processLockedInHtlcs(fwdPkg *channeldb.FwdPkg,
 	paymentDescriptors []*lnwallet.PaymentDescriptor) []*htlcPacket {
 
 	var (
 		needUpdate       bool
 		packetsToForward []*htlcPacket
+
+		// addIndex and settleFailIndex are the indexes within the
+		// forwarding package of the next Add, and of the next Settle
+		// or Fail, respectively.
+		addIndex        uint16
+		settleFailIndex uint16
+
+		// fwdFilter marks the Adds which are forwarded to the switch,
+		// and processedAdds references each Add that was processed.
+		fwdFilter     = channeldb.NewPkgFilter(uint16(len(fwdPkg.Adds)))
+		processedAdds []channeldb.AddRef
 	)
 
+	// Before processing the updates, we'll decode the onion packets of
+	// all the Adds within the forwarding package as a single batch. Should
+	// the package be processed again after a restart, the onion processor
+	// is then able to tell that its packets aren't being replayed.
+	decodeResps, err := l.decodeHopIterators(fwdPkg, paymentDescriptors)
+	if err != nil {
+		l.fail("unable to decode hop iterators: %v", err)
+		return nil
+	}
+
 	for _, pd := range paymentDescriptors {
+		// We'll first determine the reference of the update within
+		// the forwarding package. Any update that has already been
+		// acknowledged was handled prior to a restart, so it mustn't
+		// be processed again.
+		var (
+			addRef        *channeldb.AddRef
+			settleFailRef *channeldb.SettleFailRef
+		)
+		if pd.EntryType == lnwallet.Add {
+			addRef = &channeldb.AddRef{
+				Height: fwdPkg.Height,
+				Index:  addIndex,
+			}
+			addIndex++
+
+			if fwdPkg.AckFilter.Contains(addRef.Index) {
+				continue
+			}
+			processedAdds = append(processedAdds, *addRef)
+		} else {
+			settleFailRef = &channeldb.SettleFailRef{
+				Source: fwdPkg.Source,
+				Height: fwdPkg.Height,
+				Index:  settleFailIndex,
+			}
+			settleFailIndex++
+
+			if fwdPkg.SettleFailFilter.Contains(settleFailRef.Index) {
+				continue
+			}
+		}
+
 		// TODO(roasbeef): rework log entries to a shared
 		// interface.
 		switch pd.EntryType {
 
 		// A settle for an HTLC we previously forwarded HTLC has been
 		// received. So we'll forward the HTLC to the switch which
-		// will handle propagating the settle to the prior hop.
+		// will handle propagating the settle to the prior hop. If the
+		// settle was already pipelined, then the switch only closes
+		// the circuit of the HTLC.
 		case lnwallet.Settle:
 			settlePacket := &htlcPacket{
 				outgoingChanID: l.ShortChanID(),
@@ -1317,6 +2400,7 @@ func (l *channelLink) processLockedInHtlcs(
 				htlc: &lnwire.UpdateFufillHTLC{
 					PaymentPreimage: pd.RPreimage,
 				},
+				destRef: settleFailRef,
 			}
 
 			// Add the packet to the batch to be forwarded, and
@@ -1339,6 +2423,7 @@ func (l *channelLink) processLockedInHtlcs(
 				htlc: &lnwire.UpdateFailHTLC{
 					Reason: lnwire.OpaqueReason(pd.FailReason),
 				},
+				destRef: settleFailRef,
 			}
 
 			// Add the packet to the batch to be forwarded, and
@@ -1353,6 +2438,15 @@ func (l *channelLink) processLockedInHtlcs(
 		// or are able to settle it (and it adheres to our fee related
 		// constraints).
 		case lnwallet.Add:
+			// If the endorsement experiment is active, we'll
+			// determine if the remote party endorsed this HTLC, so
+			// we're able to relay the signal if we forward it.
+			endorsed := l.consumeIncomingEndorsement(pd.HtlcIndex)
+
+			// We'll also determine if the HTLC exceeded the HTLC
+			// limits of the link when it was received.
+			overLimit := l.consumeOverLimitHtlc(pd.HtlcIndex)
+
 			// Fetch the onion blob that was included within this
 			// processed payment descriptor.
 			var onionBlob [lnwire.OnionPacketSize]byte
@@ -1362,9 +2456,8 @@ func (l *channelLink) processLockedInHtlcs(
 			// to produce initial obfuscation of the onion
 			// failureCode.
 			onionReader := bytes.NewReader(onionBlob[:])
-			obfuscator, failureCode := l.cfg.DecodeOnionObfuscator(
-				onionReader,
-			)
+			obfuscator, failureCode := l.cfg.OnionProcessor.
+				ExtractErrorEncrypter(onionReader)
 			if failureCode != lnwire.CodeNone {
 				// If we're unable to process the onion blob
 				// than we should send the malformed htlc error
@@ -1378,21 +2471,40 @@ func (l *channelLink) processLockedInHtlcs(
 				continue
 			}
 
+			// If the HTLC exceeded the HTLC limits of the link,
+			// or arrived while the link is being flushed, then
+			// we'll fail it back along with our latest channel
+			// update.
+			if overLimit || l.isFlushing() {
+				l.sendHTLCError(
+					pd.HtlcIndex, l.temporaryChannelFailure(),
+					obfuscator,
+				)
+				needUpdate = true
+				continue
+			}
+
 			// Before adding the new htlc to the state machine,
-			// parse the onion object in order to obtain the
-			// routing information with DecodeHopIterator function
-			// which process the Sphinx packet.
-			//
-			// We include the payment hash of the htlc as it's
-			// authenticated within the Sphinx packet itself as
-			// associated data in order to thwart attempts a replay
-			// attacks. In the case of a replay, an attacker is
-			// *forced* to use the same payment hash twice, thereby
-			// losing their money entirely.
-			onionReader = bytes.NewReader(onionBlob[:])
-			chanIterator, failureCode := l.cfg.DecodeHopIterator(
-				onionReader, pd.RHash[:],
-			)
+			// we'll obtain the routing information from the hop
+			// iterator decoded from its onion object.
+			chanIterator := decodeResps[addRef.Index].HopIterator
+			failureCode = decodeResps[addRef.Index].FailCode
+
+			// If the onion packet is a replay of one we already
+			// processed, then we'll fail the HTLC back with a
+			// temporary channel failure, as the packet itself is
+			// valid.
+			if failureCode == lnwire.CodeTemporaryChannelFailure {
+				l.sendHTLCError(
+					pd.HtlcIndex, l.temporaryChannelFailure(),
+					obfuscator,
+				)
+				needUpdate = true
+
+				log.Errorf("replayed onion packet for "+
+					"htlc(index=%v)", pd.HtlcIndex)
+				continue
+			}
 			if failureCode != lnwire.CodeNone {
 				// If we're unable to process the onion blob
 				// than we should send the malformed htlc error
@@ -1422,7 +2534,8 @@ func (l *channelLink) processLockedInHtlcs(
 				// itself against, the current block height. If
 				// the timeout is too soon, then we'll reject
 				// the HTLC.
-				if pd.Timeout-expiryGraceDelta <= heightNow {
+				grace := l.cfg.FinalCltvGrace
+				if expiryTooSoon(pd.Timeout, grace, heightNow) {
 					log.Errorf("htlc(%x) has an expiry "+
 						"that's too soon: expiry=%v, "+
 						"best_height=%v", pd.RHash[:],
@@ -1434,12 +2547,77 @@ func (l *channelLink) processLockedInHtlcs(
 					continue
 				}
 
+				// If the sender included the preimage within
+				// the onion, then this is a keysend payment,
+				// for which we'll add an invoice on the fly,
+				// such that it's settled like any other.
+				if fwdInfo.KeysendPreimage != nil {
+					failure := l.acceptKeysend(
+						pd, *fwdInfo.KeysendPreimage,
+					)
+					if failure != nil {
+						l.sendHTLCError(
+							pd.HtlcIndex, failure,
+							obfuscator,
+						)
+						needUpdate = true
+						continue
+					}
+				}
+
+				// Similarly, if the sender included an AMP
+				// record within the onion, then this is a part
+				// of an AMP payment, for which we'll add a
+				// hold invoice on the fly, which is settled
+				// once all of its parts have arrived.
+				if fwdInfo.Amp != nil {
+					failure := l.acceptAmp(pd, fwdInfo.Amp)
+					if failure != nil {
+						l.sendHTLCError(
+							pd.HtlcIndex, failure,
+							obfuscator,
+						)
+						needUpdate = true
+						continue
+					}
+				}
+
+				// If the sender included a stateless record
+				// within the onion, then the HTLC pays to one
+				// of our stateless invoices, which we'll
+				// reconstruct from the record, as it was
+				// never stored.
+				var stateless *channeldb.Invoice
+				if fwdInfo.Stateless != nil {
+					var failure lnwire.FailureMessage
+					stateless, failure = l.acceptStateless(
+						pd, fwdInfo.Stateless,
+					)
+					if failure != nil {
+						l.sendHTLCError(
+							pd.HtlcIndex, failure,
+							obfuscator,
+						)
+						needUpdate = true
+						continue
+					}
+				}
+
 				// We're the designated payment destination.
 				// Therefore we attempt to see if we have an
 				// invoice locally which'll allow us to settle
 				// this htlc.
-				invoiceHash := chainhash.Hash(pd.RHash)
-				invoice, err := l.cfg.Registry.LookupInvoice(invoiceHash)
+				var (
+					invoiceHash = chainhash.Hash(pd.RHash)
+					invoice     channeldb.Invoice
+					err         error
+				)
+				if stateless != nil {
+					invoice = *stateless
+				} else {
+					invoice, err = l.cfg.Registry.
+						LookupInvoice(invoiceHash)
+				}
 				if err != nil {
 					log.Errorf("unable to query invoice registry: "+
 						" %v", err)
@@ -1452,7 +2630,9 @@ func (l *channelLink) processLockedInHtlcs(
 				// If this invoice has already been settled,
 				// then we'll reject it as we don't allow an
 				// invoice to be paid twice.
-				if invoice.Terms.Settled == true {
+				if invoice.Terms.State ==
+					channeldb.ContractSettled {
+
 					log.Warnf("Rejecting duplicate "+
 						"payment for hash=%x", pd.RHash[:])
 					failure := lnwire.FailUnknownPaymentHash{}
@@ -1463,6 +2643,38 @@ func (l *channelLink) processLockedInHtlcs(
 					continue
 				}
 
+				// Similarly, we'll reject any payments to an
+				// invoice that has been canceled.
+				if invoice.Terms.State ==
+					channeldb.ContractCanceled {
+
+					log.Warnf("Rejecting payment for "+
+						"canceled invoice hash=%x",
+						pd.RHash[:])
+					failure := lnwire.FailUnknownPaymentHash{}
+					l.sendHTLCError(
+						pd.HtlcIndex, failure, obfuscator,
+					)
+					needUpdate = true
+					continue
+				}
+
+				// Payments to an invoice with a payment secret
+				// must carry it, unless legacy payments are
+				// still accepted, such that intermediate nodes
+				// can't probe for the invoice.
+				failure := l.checkPaymentAddr(
+					pd, &invoice, fwdInfo.PaymentAddr,
+				)
+				if failure != nil {
+					l.sendHTLCError(
+						pd.HtlcIndex, failure,
+						obfuscator,
+					)
+					needUpdate = true
+					continue
+				}
+
 				// If we're not currently in debug mode, and
 				// the extended htlc doesn't meet the value
 				// requested, then we'll fail the htlc.
@@ -1490,17 +2702,13 @@ func (l *channelLink) processLockedInHtlcs(
 				// As we're the exit hop, we'll double check
 				// the hop-payload included in the HTLC to
 				// ensure that it was crafted correctly by the
-				// sender and matches the HTLC we were
-				// extended.
-				//
-				// NOTE: We make an exception when the value
-				// requested by the invoice is zero. This means
-				// the invoice allows the payee to specify the
-				// amount of satoshis they wish to send.
-				// So since we expect the htlc to have a
-				// different amount, we should not fail.
-				if !l.cfg.DebugHTLC && invoice.Terms.Value > 0 &&
-					fwdInfo.AmountToForward != invoice.Terms.Value {
+				// sender and pays the value of the invoice,
+				// or overpays it within our bounds.
+				amtOk := l.acceptablePaymentAmount(
+					invoice.Terms.Value,
+					fwdInfo.AmountToForward,
+				)
+				if !l.cfg.DebugHTLC && !amtOk {
 
 					log.Errorf("Onion payload of incoming "+
 						"htlc(%x) has incorrect value: "+
@@ -1550,6 +2758,37 @@ func (l *channelLink) processLockedInHtlcs(
 				}
 
 				preimage := invoice.Terms.PaymentPreimage
+
+				// If settling exit hop HTLCs safely, then
+				// we'll hold the HTLC until we're able to
+				// reveal its preimage without risk. HTLCs
+				// paying to a hold invoice are always held
+				// until the invoice is settled or canceled.
+				// Likewise, rather than blocking on the
+				// registry while it's saturated with settles,
+				// we'll hold the HTLC until it catches up.
+				backpressure := l.cfg.Registry.SettleBackpressure()
+				if l.cfg.SafeExitSettle || invoice.Terms.Hold ||
+					backpressure != nil {
+
+					l.holdExitHtlc(&heldExitHtlc{
+						htlc: ExitHTLC{
+							ChanID:      l.ShortChanID(),
+							HtlcIndex:   pd.HtlcIndex,
+							PaymentHash: pd.RHash,
+							Amount:      pd.Amount,
+							Expiry:      pd.Timeout,
+						},
+						preimage:     preimage,
+						hold:         invoice.Terms.Hold,
+						backpressure: backpressure,
+						obfuscator:   obfuscator,
+						onionBlob:    onionBlob[:],
+						stateless:    stateless,
+					})
+					continue
+				}
+
 				err = l.channel.SettleHTLC(preimage, pd.HtlcIndex)
 				if err != nil {
 					l.fail("unable to settle htlc: %v", err)
@@ -1559,15 +2798,23 @@ func (l *channelLink) processLockedInHtlcs(
 				// Notify the invoiceRegistry of the invoices
 				// we just settled with this latest commitment
 				// update.
-				err = l.cfg.Registry.SettleInvoice(invoiceHash)
+				err = l.settleExitInvoice(
+					invoiceHash, channeldb.InvoiceHTLC{
+						ChanID:    l.ShortChanID(),
+						HtlcIndex: pd.HtlcIndex,
+						Amt:       pd.Amount,
+						Expiry:    pd.Timeout,
+					}, stateless,
+				)
 				if err != nil {
-					l.fail("unable to settle invoice: %v", err)
+					l.fail("unable to settle "+
+						"invoice: %v", err)
 					return nil
 				}
 
 				// HTLC was successfully settled locally send
 				// notification about it remote peer.
-				l.cfg.Peer.SendMessage(&lnwire.UpdateFufillHTLC{
+				l.sendUpdate(&lnwire.UpdateFufillHTLC{
 					ChanID:          l.ChanID(),
 					ID:              pd.HtlcIndex,
 					PaymentPreimage: preimage,
@@ -1583,8 +2830,13 @@ func (l *channelLink) processLockedInHtlcs(
 				// will expire in the near future, so we'll
 				// reject an HTLC if its expiration time is too
 				// close to the current height.
-				timeDelta := l.cfg.FwrdingPolicy.TimeLockDelta
-				if pd.Timeout-timeDelta <= heightNow {
+				timeDelta := effectiveTimeLockDelta(
+					l.cfg.FwrdingPolicy, l.cfg.MinCltvDelta,
+				)
+				tooSoon := expiryTooSoon(
+					pd.Timeout, timeDelta, heightNow,
+				)
+				if tooSoon {
 					log.Errorf("htlc(%x) has an expiry "+
 						"that's too soon: outgoing_expiry=%v, "+
 						"best_height=%v", pd.RHash[:],
@@ -1630,12 +2882,27 @@ func (l *channelLink) processLockedInHtlcs(
 					continue
 				}
 
+				// Similarly, we'll ensure that the HTLC isn't
+				// too large, if we've set a maximum.
+				maxHTLC := l.cfg.FwrdingPolicy.MaxHTLC
+				if maxHTLC != 0 && pd.Amount > maxHTLC {
+					log.Errorf("Incoming htlc(%x) is too "+
+						"large: max_htlc=%v, htlc_value=%v",
+						pd.RHash[:], maxHTLC, pd.Amount)
+
+					l.sendHTLCError(
+						pd.HtlcIndex, l.temporaryChannelFailure(),
+						obfuscator,
+					)
+					needUpdate = true
+					continue
+				}
+
 				// Next, using the amount of the incoming HTLC,
 				// we'll calculate the expected fee this
 				// incoming HTLC must carry in order to be
 				// accepted.
-				expectedFee := ExpectedFee(
-					l.cfg.FwrdingPolicy,
+				expectedFee := l.expectedFee(
 					fwdInfo.AmountToForward,
 				)
 
@@ -1714,6 +2981,7 @@ func (l *channelLink) processLockedInHtlcs(
 					Expiry:      fwdInfo.OutgoingCTLV,
 					Amount:      fwdInfo.AmountToForward,
 					PaymentHash: pd.RHash,
+					Endorsed:    endorsed,
 				}
 
 				// Finally, we'll encode the onion packet for
@@ -1736,12 +3004,53 @@ func (l *channelLink) processLockedInHtlcs(
 					incomingHTLCID: pd.HtlcIndex,
 					outgoingChanID: fwdInfo.NextHop,
 					amount:         addMsg.Amount,
+					incomingAmount: pd.Amount,
 					htlc:           addMsg,
 					obfuscator:     obfuscator,
+					sourceRef:      addRef,
+					traceID:        newTraceID(),
 				}
+				l.cfg.Switch.traceAdd(
+					updatePacket, TraceReceived,
+					l.ShortChanID(),
+				)
 				packetsToForward = append(packetsToForward, updatePacket)
+				fwdFilter.Set(addRef.Index)
+			}
+		}
+	}
+
+	// Any held exit hop HTLCs that have now been irrevocably committed
+	// may be settled.
+	updated, err := l.resolveHeldExitHtlcs()
+	if err != nil {
+		l.fail("unable to resolve held exit htlcs: %v", err)
+		return nil
+	}
+	needUpdate = needUpdate || updated
+
+	// Now that the package has been processed, we'll record which of its
+	// Adds are to be forwarded, and acknowledge all others, as they've
+	// been resolved within the link. This must happen before any of the
+	// resolutions are committed to, as otherwise, the package would be
+	// processed afresh after a restart.
+	if fwdPkg.State == channeldb.FwdStateLockedIn {
+		err := l.channel.SetFwdFilter(fwdPkg.Height, fwdFilter)
+		if err != nil {
+			l.fail("unable to set forwarding filter: %v", err)
+			return nil
+		}
+	} else {
+		var resolvedAdds []channeldb.AddRef
+		for _, ref := range processedAdds {
+			if !fwdFilter.Contains(ref.Index) {
+				resolvedAdds = append(resolvedAdds, ref)
 			}
 		}
+		if err := l.channel.AckAddHtlcs(resolvedAdds...); err != nil {
+			l.fail("unable to ack resolved adds: %v", err)
+			return nil
+		}
 	}
 
 	if needUpdate {
@@ -1757,6 +3066,436 @@ func (l *channelLink) processLockedInHtlcs(
 	return packetsToForward
 }
 
+// acceptablePaymentAmount returns whether the passed amount, as instructed by
+// the onion of an HTLC, is acceptable in payment of an invoice of the passed
+// value. The amount may not fall short of the value, though it may exceed it
+// by up to MaxOverpaymentPct percent of the value, in milli-satoshis rounded
+// down. Invoices with a value of zero allow the payer to specify the amount,
+// so any amount is accepted.
+func (l *channelLink) acceptablePaymentAmount(value,
+	amt lnwire.MilliSatoshi) bool {
+
+	if value == 0 {
+		return true
+	}
+	if amt < value {
+		return false
+	}
+
+	// As the value of invoices is bounded by the maximum payment amount,
+	// this can't overflow.
+	pct := lnwire.MilliSatoshi(l.cfg.MaxOverpaymentPct)
+	maxOverpayment := value * pct / 100
+
+	return amt-value <= maxOverpayment
+}
+
+// acceptKeysend verifies the preimage carried within the onion of a keysend
+// payment against the payment hash of the passed HTLC, then adds an invoice
+// for the payment unless one already exists, such that the HTLC is settled as
+// any other paying to one of our invoices. If the payment is to be rejected,
+// then the failure to send back is returned.
+func (l *channelLink) acceptKeysend(pd *lnwallet.PaymentDescriptor,
+	preimage [32]byte) lnwire.FailureMessage {
+
+	if !l.cfg.AcceptKeysend {
+		log.Warnf("Rejecting keysend payment for hash=%x as keysend "+
+			"payments aren't accepted", pd.RHash[:])
+		return lnwire.FailUnknownPaymentHash{}
+	}
+
+	if sha256.Sum256(preimage[:]) != pd.RHash {
+		log.Warnf("Rejecting keysend payment for hash=%x with "+
+			"mismatched preimage", pd.RHash[:])
+		return lnwire.FailUnknownPaymentHash{}
+	}
+
+	// If an invoice already exists for this payment hash, then it's
+	// either the same payment being replayed after a restart, or a
+	// duplicate, which is rejected once the invoice is found to be
+	// settled.
+	invoiceHash := chainhash.Hash(pd.RHash)
+	if _, err := l.cfg.Registry.LookupInvoice(invoiceHash); err == nil {
+		return nil
+	}
+
+	invoice := &channeldb.Invoice{
+		CreationDate: time.Now(),
+		Memo:         []byte("keysend"),
+		Terms: channeldb.ContractTerm{
+			Value:           pd.Amount,
+			PaymentPreimage: preimage,
+			PaymentHash:     pd.RHash,
+		},
+	}
+	if err := l.cfg.Registry.AddInvoice(invoice); err != nil {
+		log.Errorf("unable to add invoice for keysend payment "+
+			"hash=%x: %v", pd.RHash[:], err)
+		return lnwire.FailTemporaryNodeFailure{}
+	}
+
+	log.Infof("Accepted keysend payment of %v for hash=%x", pd.Amount,
+		pd.RHash[:])
+
+	return nil
+}
+
+// acceptAmp adds a hold invoice for the passed HTLC, which is a part of the
+// AMP payment described by the passed record, unless one already exists, then
+// registers the part with the registry. Once all parts of the payment have
+// arrived, the registry settles their invoices with the preimages derived
+// from their shares, such that the HTLC is settled as any other paying to a
+// hold invoice. If the part is to be rejected, then the failure to send back
+// is returned.
+func (l *channelLink) acceptAmp(pd *lnwallet.PaymentDescriptor,
+	record *AmpRecord) lnwire.FailureMessage {
+
+	if !l.cfg.AcceptAmp {
+		log.Warnf("Rejecting AMP payment part for hash=%x as AMP "+
+			"payments aren't accepted", pd.RHash[:])
+		return lnwire.FailUnknownPaymentHash{}
+	}
+
+	// If an invoice already exists for this payment hash, then it's
+	// either the same part being replayed after a restart, or a
+	// duplicate, which is rejected once the invoice is found to be
+	// settled or canceled.
+	invoiceHash := chainhash.Hash(pd.RHash)
+	invoice, err := l.cfg.Registry.LookupInvoice(invoiceHash)
+	switch {
+	case err == nil && (!invoice.Terms.Hold ||
+		invoice.Terms.HoldState != channeldb.HoldAccepting):
+
+		return nil
+
+	case err != nil:
+		invoice := &channeldb.Invoice{
+			CreationDate: time.Now(),
+			Memo:         []byte("amp"),
+			Terms: channeldb.ContractTerm{
+				Value:       pd.Amount,
+				PaymentHash: pd.RHash,
+				Hold:        true,
+			},
+		}
+		if err := l.cfg.Registry.AddInvoice(invoice); err != nil {
+			log.Errorf("unable to add invoice for AMP payment "+
+				"part hash=%x: %v", pd.RHash[:], err)
+			return lnwire.FailTemporaryNodeFailure{}
+		}
+	}
+
+	err = l.cfg.Registry.AddAmpPart(invoiceHash, pd.Amount, record)
+	if err != nil {
+		log.Warnf("Rejecting AMP payment part for hash=%x: %v",
+			pd.RHash[:], err)
+		return lnwire.FailUnknownPaymentHash{}
+	}
+
+	log.Infof("Accepted AMP payment part of %v for hash=%x with "+
+		"set_id=%x", pd.Amount, pd.RHash[:], record.SetID[:])
+
+	return nil
+}
+
+// reregisterAmpPart registers the passed HTLC with the registry once again if
+// it's a part of an AMP payment, as given by the AMP record within its onion.
+func (l *channelLink) reregisterAmpPart(htlc channeldb.HTLC) {
+	iterator, failCode := l.cfg.OnionProcessor.DecodeHopIterator(
+		bytes.NewReader(htlc.OnionBlob), htlc.RHash[:],
+	)
+	if failCode != lnwire.CodeNone {
+		return
+	}
+
+	record := iterator.ForwardingInstructions().Amp
+	if record == nil {
+		return
+	}
+
+	err := l.cfg.Registry.AddAmpPart(htlc.RHash, htlc.Amt, record)
+	if err != nil {
+		log.Errorf("ChannelPoint(%v): unable to register AMP "+
+			"payment part for hash=%x: %v",
+			l.channel.ChannelPoint(), htlc.RHash[:], err)
+	}
+}
+
+// acceptStateless reconstructs the stateless invoice paid by the passed HTLC
+// from the record carried within its onion, and verifies that the HTLC may
+// pay to it. If the payment is to be rejected, then the failure to send back
+// is returned instead.
+func (l *channelLink) acceptStateless(pd *lnwallet.PaymentDescriptor,
+	record *StatelessRecord) (*channeldb.Invoice, lnwire.FailureMessage) {
+
+	if l.cfg.StatelessInvoiceKey == nil {
+		log.Warnf("Rejecting payment for hash=%x as stateless "+
+			"invoices aren't accepted", pd.RHash[:])
+		return nil, lnwire.FailUnknownPaymentHash{}
+	}
+
+	invoice := l.statelessInvoice(pd.RHash, record)
+	if invoice == nil {
+		log.Warnf("Rejecting payment for hash=%x with mismatched "+
+			"stateless record", pd.RHash[:])
+		return nil, lnwire.FailUnknownPaymentHash{}
+	}
+
+	if record.Expired(time.Now()) {
+		log.Warnf("Rejecting payment for expired stateless invoice "+
+			"hash=%x", pd.RHash[:])
+		return nil, lnwire.FailUnknownPaymentHash{}
+	}
+
+	return invoice, nil
+}
+
+// checkPaymentAddr verifies the payment secret carried within the onion of
+// the passed HTLC, if any, against that of the invoice it pays to. Invoices
+// without a payment secret accept any payment. If the payment is to be
+// rejected, then the failure to send back is returned.
+func (l *channelLink) checkPaymentAddr(pd *lnwallet.PaymentDescriptor,
+	invoice *channeldb.Invoice,
+	paymentAddr *[32]byte) lnwire.FailureMessage {
+
+	if invoice.Terms.PaymentAddr == [32]byte{} {
+		return nil
+	}
+
+	// The sender's route reached us over this link, so we'll log our
+	// peer along with the channel, as that's the hop the payment took
+	// towards us.
+	peer := l.cfg.Peer.PubKey()
+	switch {
+	case paymentAddr == nil && !l.cfg.RejectLegacyPayments:
+		return nil
+
+	case paymentAddr == nil:
+		log.Warnf("Rejecting legacy payment without payment secret "+
+			"for hash=%x, received from peer %x over channel %v",
+			pd.RHash[:], peer[:], l.ShortChanID())
+		return lnwire.FailUnknownPaymentHash{}
+
+	case *paymentAddr != invoice.Terms.PaymentAddr:
+		log.Warnf("Rejecting payment with mismatched payment secret "+
+			"for hash=%x, received from peer %x over channel %v",
+			pd.RHash[:], peer[:], l.ShortChanID())
+		return lnwire.FailUnknownPaymentHash{}
+	}
+
+	return nil
+}
+
+// statelessInvoice reconstructs the stateless invoice described by the
+// passed record. If the record doesn't yield the passed payment hash, then
+// it wasn't created by us, or has been tampered with, and nil is returned.
+func (l *channelLink) statelessInvoice(rHash [32]byte,
+	record *StatelessRecord) *channeldb.Invoice {
+
+	invoice := record.Invoice(*l.cfg.StatelessInvoiceKey)
+	if invoice.Terms.PaymentHash != rHash {
+		return nil
+	}
+
+	// As stateless invoices aren't stored, the preimage cache is the only
+	// record of them having been settled, such that further payments to
+	// them are rejected as duplicates.
+	_, settled := l.cfg.PreimageCache.LookupPreimage(rHash[:])
+	if settled {
+		invoice.Terms.State = channeldb.ContractSettled
+	}
+
+	return invoice
+}
+
+// holdStatelessHtlc holds the passed HTLC once again if it pays to one of our
+// stateless invoices, as given by the stateless record within its onion,
+// until it can be settled.
+func (l *channelLink) holdStatelessHtlc(htlc channeldb.HTLC) {
+	iterator, failCode := l.cfg.OnionProcessor.DecodeHopIterator(
+		bytes.NewReader(htlc.OnionBlob), htlc.RHash[:],
+	)
+	if failCode != lnwire.CodeNone {
+		return
+	}
+
+	record := iterator.ForwardingInstructions().Stateless
+	if record == nil {
+		return
+	}
+	invoice := l.statelessInvoice(htlc.RHash, record)
+	if invoice == nil {
+		return
+	}
+
+	l.holdExitHtlc(&heldExitHtlc{
+		htlc: ExitHTLC{
+			ChanID:      l.ShortChanID(),
+			HtlcIndex:   htlc.HtlcIndex,
+			PaymentHash: htlc.RHash,
+			Amount:      htlc.Amt,
+			Expiry:      htlc.RefundTimeout,
+		},
+		preimage:  invoice.Terms.PaymentPreimage,
+		onionBlob: htlc.OnionBlob,
+		stateless: invoice,
+	})
+}
+
+// settleExitInvoice notifies the registry of the settlement of the invoice
+// corresponding to the passed payment hash by the passed HTLC. If the HTLC
+// pays to a stateless invoice, then its preimage is added to the preimage
+// cache beforehand, as that's the only record of it, ensuring the HTLC is
+// settled once again should we go down before it's committed.
+func (l *channelLink) settleExitInvoice(rHash chainhash.Hash,
+	htlc channeldb.InvoiceHTLC, stateless *channeldb.Invoice) error {
+
+	if stateless == nil {
+		return l.cfg.Registry.SettleInvoice(rHash, htlc)
+	}
+
+	preimage := stateless.Terms.PaymentPreimage
+	if err := l.cfg.PreimageCache.AddPreimage(preimage[:]); err != nil {
+		return err
+	}
+
+	return l.cfg.Registry.SettleStatelessInvoice(stateless, htlc)
+}
+
+// forwardBatch hands the passed packets off to the switch within a distinct
+// goroutine. Once the switch has handled a packet, its update is acknowledged
+// within the forwarding package it belongs to, so that it isn't forwarded
+// again after a restart.
+func (l *channelLink) forwardBatch(packets []*htlcPacket) {
+	go func() {
+		log.Debugf("ChannelPoint(%v) forwarding %v HTLC's",
+			l.channel.ChannelPoint(), len(packets))
+
+		var (
+			addRefs        []channeldb.AddRef
+			settleFailRefs []channeldb.SettleFailRef
+		)
+		for _, packet := range packets {
+			// If the switch is shutting down, then the packet
+			// hasn't been handled, so we'll leave it, and all
+			// those that follow it, to be replayed on restart.
+			err := l.cfg.Switch.forward(packet)
+			if err == ErrSwitchExiting {
+				break
+			} else if err != nil {
+				log.Errorf("channel link(%v): "+
+					"unhandled error while forwarding "+
+					"htlc packet over htlc  "+
+					"switch: %v", l, err)
+			}
+
+			if packet.sourceRef != nil {
+				addRefs = append(addRefs, *packet.sourceRef)
+			}
+			if packet.destRef != nil {
+				settleFailRefs = append(
+					settleFailRefs, *packet.destRef,
+				)
+			}
+		}
+
+		if err := l.channel.AckAddHtlcs(addRefs...); err != nil {
+			log.Errorf("ChannelPoint(%v): unable to ack forwarded "+
+				"adds: %v", l.channel.ChannelPoint(), err)
+		}
+		err := l.channel.AckSettleFails(settleFailRefs...)
+		if err != nil {
+			log.Errorf("ChannelPoint(%v): unable to ack forwarded "+
+				"settles and fails: %v", l.channel.ChannelPoint(),
+				err)
+		}
+	}()
+}
+
+// resolveFwdPkgs processes the forwarding packages of the channel which
+// haven't been completed, as we may have gone down before all of their
+// updates were handed off to the switch. Packages that were never processed
+// are processed in full, while of the others, only the updates which were to
+// be forwarded, but haven't been acknowledged, are forwarded again. Completed
+// packages are removed. Adds whose preimage we know, or which we've already
+// resolved, are acknowledged without being processed, leaving them to be
+// settled directly. The HTLC indexes of the Adds which were processed again
+// are returned, so they aren't settled directly as well, along with those of
+// the Adds acknowledged without being processed.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) resolveFwdPkgs() (map[uint64]struct{},
+	map[uint64]struct{}, error) {
+
+	fwdPkgs, err := l.channel.LoadFwdPkgs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replayed := make(map[uint64]struct{})
+	acked := make(map[uint64]struct{})
+	for _, fwdPkg := range fwdPkgs {
+		if fwdPkg.State == channeldb.FwdStateCompleted {
+			err := l.channel.RemoveFwdPkg(fwdPkg.Height)
+			if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		log.Infof("ChannelPoint(%v): resolving %v",
+			l.channel.ChannelPoint(), fwdPkg)
+
+		adds := lnwallet.PayDescsFromRemoteLogUpdates(fwdPkg.Adds)
+
+		var skipped []channeldb.AddRef
+		for i, pd := range adds {
+			index := uint16(i)
+			if fwdPkg.AckFilter.Contains(index) {
+				continue
+			}
+			if fwdPkg.FwdFilter != nil &&
+				!fwdPkg.FwdFilter.Contains(index) {
+
+				continue
+			}
+
+			isResolved := l.channel.IncomingHtlcResolved(pd.HtlcIndex)
+			_, hasPreimage := l.cfg.PreimageCache.LookupPreimage(
+				pd.RHash[:],
+			)
+			if !isResolved && !hasPreimage {
+				replayed[pd.HtlcIndex] = struct{}{}
+				continue
+			}
+
+			fwdPkg.AckFilter.Set(index)
+			skipped = append(skipped, channeldb.AddRef{
+				Height: fwdPkg.Height,
+				Index:  index,
+			})
+			acked[pd.HtlcIndex] = struct{}{}
+		}
+
+		// The skipped Adds are acknowledged before they're settled, so
+		// that their invoices are never settled more than once, even
+		// if we go down before the settles are committed to.
+		if err := l.channel.AckAddHtlcs(skipped...); err != nil {
+			return nil, nil, err
+		}
+
+		settleFails := lnwallet.PayDescsFromRemoteLogUpdates(
+			fwdPkg.SettleFails,
+		)
+		packets := l.processLockedInHtlcs(
+			fwdPkg, append(settleFails, adds...),
+		)
+		l.forwardBatch(packets)
+	}
+
+	return replayed, acked, nil
+}
+
 // sendHTLCError functions cancels HTLC and send cancel message back to the
 // peer from which HTLC was received.
 func (l *channelLink) sendHTLCError(htlcIndex uint64,
@@ -1773,8 +3512,9 @@ func (l *channelLink) sendHTLCError(htlcIndex uint64,
 		log.Errorf("unable cancel htlc: %v", err)
 		return
 	}
+	l.cfg.Switch.metrics.failedHTLC(failure.Code())
 
-	l.cfg.Peer.SendMessage(&lnwire.UpdateFailHTLC{
+	l.sendUpdate(&lnwire.UpdateFailHTLC{
 		ChanID: l.ChanID(),
 		ID:     htlcIndex,
 		Reason: reason,
@@ -1792,8 +3532,9 @@ func (l *channelLink) sendMalformedHTLCError(htlcIndex uint64,
 		log.Errorf("unable cancel htlc: %v", err)
 		return
 	}
+	l.cfg.Switch.metrics.failedHTLC(code)
 
-	l.cfg.Peer.SendMessage(&lnwire.UpdateFailMalformedHTLC{
+	l.sendUpdate(&lnwire.UpdateFailMalformedHTLC{
 		ChanID:       l.ChanID(),
 		ID:           htlcIndex,
 		ShaOnionBlob: shaOnionBlob,
@@ -1808,3 +3549,16 @@ func (l *channelLink) fail(format string, a ...interface{}) {
 	log.Error(reason)
 	l.cfg.Peer.Disconnect(reason)
 }
+
+// failForceClose is used to handle a failure which leaves the channel unusable
+// until it's force closed. The peer is disconnected, and the failure is
+// reported such that the operator can decide whether to close the channel.
+func (l *channelLink) failForceClose(format string, a ...interface{}) {
+	reason := errors.Errorf(format, a...)
+	if l.cfg.OnForceCloseRecommended != nil {
+		l.cfg.OnForceCloseRecommended(reason)
+	}
+
+	log.Error(reason)
+	l.cfg.Peer.Disconnect(reason)
+}
@@ -0,0 +1,113 @@
+package htlcswitch
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrLinkFlushing is returned when an HTLC can't be offered over a link, as
+// the link is being flushed ahead of a cooperative close of its channel.
+var ErrLinkFlushing = errors.New("link is flushing")
+
+// flushReq is a message sent to a channel link to place it into flush mode.
+// The done channel is closed once the link's channel is clean.
+type flushReq struct {
+	done chan struct{}
+}
+
+// ShutdownIfChannelClean places the link into flush mode, in which it no
+// longer accepts new HTLCs, failing back any that are offered to it, while it
+// continues to process the settles and fails of the HTLCs already in flight.
+// The call blocks until the channel is clean, with no HTLCs or pending updates
+// remaining, at which point the link is stopped, such that no further updates
+// can race a cooperative close of the channel. If the link exits before the
+// channel is clean, then ErrLinkShuttingDown is returned.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) ShutdownIfChannelClean() error {
+	req := &flushReq{
+		done: make(chan struct{}),
+	}
+
+	select {
+	case l.linkControl <- req:
+	case <-l.quit:
+		return ErrLinkShuttingDown
+	}
+
+	select {
+	case <-req.done:
+	case <-l.quit:
+		return ErrLinkShuttingDown
+	}
+
+	log.Infof("ChannelLink(%v): channel is clean, stopping link", l)
+
+	l.Stop()
+
+	return nil
+}
+
+// isFlushing returns true if the link has been placed into flush mode.
+func (l *channelLink) isFlushing() bool {
+	return atomic.LoadInt32(&l.flushing) == 1
+}
+
+// startFlush places the link into flush mode, registering the passed request
+// to be notified once the channel is clean.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) startFlush(req *flushReq) {
+	if atomic.CompareAndSwapInt32(&l.flushing, 0, 1) {
+		log.Infof("ChannelLink(%v): flushing channel, no longer "+
+			"accepting htlcs", l)
+	}
+
+	l.flushWaiters = append(l.flushWaiters, req.done)
+}
+
+// notifyIfFlushed notifies those awaiting the flush of the link once its
+// channel is clean. HTLCs that are waiting within the overflow queue are
+// released one at a time, so that they're failed back, before the flush is
+// considered complete.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) notifyIfFlushed() {
+	if len(l.flushWaiters) == 0 || !l.channel.IsChannelClean() {
+		return
+	}
+
+	// The overflow queue only releases a packet once a slot is freed. We
+	// free one without blocking, as the queue may itself be blocked on
+	// handing us a previously released packet.
+	if l.overflowQueue.Length() != 0 {
+		select {
+		case l.overflowQueue.freeSlots <- struct{}{}:
+		default:
+		}
+		return
+	}
+
+	for _, done := range l.flushWaiters {
+		close(done)
+	}
+	l.flushWaiters = nil
+}
+
+// failFlushedAdd fails an HTLC offered to the link while it's flushing. If
+// the HTLC is being forwarded, then the switch may retry it over another link
+// to the same peer.
+func (l *channelLink) failFlushedAdd(pkt *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC) {
+
+	log.Debugf("ChannelPoint(%v): rejecting htlc(%x) as link is "+
+		"flushing", l.channel.ChannelPoint(), htlc.PaymentHash[:])
+
+	if l.retryForward(pkt, ErrLinkFlushing) {
+		return
+	}
+
+	l.failAddPacketWith(pkt, htlc, l.temporaryChannelFailure())
+}
@@ -0,0 +1,198 @@
+package htlcswitch
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// DefaultQuiescenceTimeout is the default amount of time a link remains
+// quiescent, including the time it takes for the channel to become quiescent,
+// before the peer is disconnected in order to end the quiescence.
+const DefaultQuiescenceTimeout = time.Minute
+
+// ErrQuiescenceUnsupported is returned when quiescence is requested over a
+// link whose peer hasn't negotiated the quiescence feature.
+var ErrQuiescenceUnsupported = errors.New("quiescence not supported by peer")
+
+// stfuReq is a message sent to a channel link to request that its channel be
+// brought into a quiescent state. The result of the request is delivered over
+// the done channel.
+type stfuReq struct {
+	done chan error
+}
+
+// InitStfu requests that the link's channel be brought into a quiescent
+// state, in which neither party initiates any further updates. Once no
+// updates of our own remain pending, we'll send the remote party an Stfu
+// message, after which we no longer initiate any updates ourselves. The
+// channel is quiescent once the remote party has responded with an Stfu
+// message of its own, at which point nil is delivered over the returned
+// channel. If the peer hasn't negotiated the quiescence feature, then
+// ErrQuiescenceUnsupported is delivered instead. Quiescence lasts until the
+// peer reconnects, or the QuiescenceTimeout expires, which disconnects the
+// peer. If the link exits before the channel is quiescent, then
+// ErrLinkShuttingDown is delivered.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) InitStfu() <-chan error {
+	req := &stfuReq{
+		done: make(chan error, 1),
+	}
+
+	if !l.cfg.QuiescenceSupported {
+		req.done <- ErrQuiescenceUnsupported
+		return req.done
+	}
+
+	select {
+	case l.linkControl <- req:
+	case <-l.quit:
+		req.done <- ErrLinkShuttingDown
+	}
+
+	return req.done
+}
+
+// isQuiescing returns true if the link has either been requested to bring
+// its channel into a quiescent state, or the remote party has requested it
+// to, after which the link no longer initiates any updates.
+func (l *channelLink) isQuiescing() bool {
+	return atomic.LoadInt32(&l.quiescing) == 1
+}
+
+// startStfu registers the passed request to be notified once the channel is
+// quiescent. If the channel already is, then the request is notified right
+// away.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) startStfu(req *stfuReq) {
+	if atomic.CompareAndSwapInt32(&l.quiescing, 0, 1) {
+		log.Infof("ChannelLink(%v): quiescing channel, no longer "+
+			"initiating updates", l)
+	}
+
+	l.stfuWaiters = append(l.stfuWaiters, req.done)
+	l.notifyIfQuiescent()
+}
+
+// handleStfu processes an Stfu message received from the remote party. Once
+// the remote party has sent Stfu, it may no longer initiate any updates, and
+// neither may we, so we'll respond with an Stfu message of our own once our
+// pending updates have been committed to, unless we've already sent ours, in
+// which case the channel is now quiescent. Stfu may only be sent if both
+// parties have negotiated the quiescence feature, and a response may only be
+// sent once we've requested quiescence ourselves.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) handleStfu(msg *lnwire.Stfu) error {
+	if !l.cfg.QuiescenceSupported {
+		return fmt.Errorf("received stfu without negotiating " +
+			"quiescence")
+	}
+	if l.stfuReceived {
+		return fmt.Errorf("received duplicate stfu")
+	}
+	if !msg.Initiator && !l.stfuSent {
+		return fmt.Errorf("received stfu response without a request")
+	}
+	l.stfuReceived = true
+
+	if atomic.CompareAndSwapInt32(&l.quiescing, 0, 1) {
+		log.Infof("ChannelLink(%v): remote party requested "+
+			"quiescence, no longer initiating updates", l)
+	}
+
+	l.notifyIfQuiescent()
+
+	return nil
+}
+
+// sendStfuIfReady sends our Stfu message to the remote party once the link
+// is quiescing and the commitment chains of both parties are fully synced,
+// such that none of our updates remain pending.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) sendStfuIfReady() {
+	if !l.isQuiescing() || l.stfuSent || !l.channel.FullySynced() {
+		return
+	}
+
+	// If the remote party has already sent its Stfu message, then ours
+	// acknowledges its request rather than initiating one.
+	l.cfg.Peer.SendMessage(lnwire.NewStfu(l.ChanID(), !l.stfuReceived))
+	l.stfuSent = true
+
+	l.notifyIfQuiescent()
+}
+
+// notifyIfQuiescent notifies those awaiting quiescence once both parties
+// have sent their Stfu message.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) notifyIfQuiescent() {
+	if !l.stfuSent || !l.stfuReceived {
+		return
+	}
+
+	log.Infof("ChannelLink(%v): channel is quiescent", l)
+
+	for _, done := range l.stfuWaiters {
+		done <- nil
+	}
+	l.stfuWaiters = nil
+}
+
+// cancelStfu notifies those still awaiting quiescence that the link has
+// exited before the channel became quiescent.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) cancelStfu() {
+	for _, done := range l.stfuWaiters {
+		done <- ErrLinkShuttingDown
+	}
+	l.stfuWaiters = nil
+}
+
+// quiescenceTimeout returns a channel which is sent upon once the link has
+// been quiescing for longer than the QuiescenceTimeout, or nil if the link
+// isn't quiescing. Quiescence only ends once the peer reconnects, so the peer
+// is to be disconnected once the timeout fires, rather than leave the
+// channel unable to resolve any HTLCs indefinitely, whichever party requested
+// it.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) quiescenceTimeout() <-chan time.Time {
+	if !l.isQuiescing() {
+		return nil
+	}
+
+	if l.quiescenceTimer == nil {
+		l.quiescenceTimer = time.NewTimer(l.cfg.QuiescenceTimeout)
+	}
+
+	return l.quiescenceTimer.C
+}
+
+// checkQuiescentUpdate ensures that the remote party doesn't send us any
+// updates after it has sent its Stfu message.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) checkQuiescentUpdate(msg lnwire.Message) error {
+	if !l.stfuReceived {
+		return nil
+	}
+
+	switch msg.(type) {
+	case *lnwire.UpdateAddHTLC, *lnwire.UpdateFufillHTLC,
+		*lnwire.UpdateFailHTLC, *lnwire.UpdateFailMalformedHTLC,
+		*lnwire.UpdateFee:
+
+		return fmt.Errorf("received %T after stfu", msg)
+	}
+
+	return nil
+}
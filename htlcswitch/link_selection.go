@@ -0,0 +1,116 @@
+package htlcswitch
+
+import (
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// LinkCandidate describes a link over which an HTLC may be forwarded, as of
+// the time the switch selects the link to forward it over.
+type LinkCandidate struct {
+	// Link is the candidate link.
+	Link ChannelLink
+
+	// Bandwidth is the amount that can currently flow through the link.
+	// It's at least the value of the HTLC being forwarded.
+	Bandwidth lnwire.MilliSatoshi
+
+	// Policy is the current forwarding policy of the link.
+	Policy ForwardingPolicy
+
+	// PendingHtlcs is the number of outgoing HTLCs of the link which have
+	// yet to be resolved, including those waiting within its overflow
+	// queue for a slot within the commitment transaction.
+	PendingHtlcs int
+}
+
+// newLinkCandidate returns the LinkCandidate describing the passed link.
+func newLinkCandidate(link ChannelLink,
+	bandwidth lnwire.MilliSatoshi) *LinkCandidate {
+
+	stats := link.AdmissionStats()
+	pendingHtlcs := lnwallet.MaxHTLCNumber/2 - stats.FreeSlots +
+		int(stats.QueueLen)
+
+	return &LinkCandidate{
+		Link:         link,
+		Bandwidth:    bandwidth,
+		Policy:       link.ForwardingPolicy(),
+		PendingHtlcs: pendingHtlcs,
+	}
+}
+
+// LinkSelector is an interface which allows the strategy by which the switch
+// selects the outgoing link of a forwarded HTLC to be plugged in. When we have
+// several channels with the next hop of an HTLC, the switch may forward the
+// HTLC over any of them.
+type LinkSelector interface {
+	// SelectLink returns the link the passed HTLC is to be forwarded over,
+	// among the candidate links to its next hop. Each of the candidates
+	// is eligible to forward HTLCs, and has sufficient bandwidth to carry
+	// the HTLC. If none of them are suitable, then nil is to be returned,
+	// in which case the HTLC is failed back.
+	SelectLink(htlc *lnwire.UpdateAddHTLC,
+		candidates []*LinkCandidate) ChannelLink
+}
+
+// BestBandwidthSelector is the default LinkSelector. It selects the link with
+// the most bandwidth, among those whose forwarding policy permits the HTLC,
+// so that the HTLC is least likely to exhaust the link. Ties are broken in
+// favor of the link with the fewest pending HTLCs, and then of the link
+// charging the lowest fee for the HTLC.
+type BestBandwidthSelector struct{}
+
+// A compile time check to ensure BestBandwidthSelector implements the
+// LinkSelector interface.
+var _ LinkSelector = (*BestBandwidthSelector)(nil)
+
+// SelectLink returns the link the passed HTLC is to be forwarded over.
+//
+// NOTE: Part of the LinkSelector interface.
+func (s *BestBandwidthSelector) SelectLink(htlc *lnwire.UpdateAddHTLC,
+	candidates []*LinkCandidate) ChannelLink {
+
+	var best *LinkCandidate
+	for _, candidate := range candidates {
+		if !policyPermitsHtlc(&candidate.Policy, htlc.Amount) {
+			continue
+		}
+
+		if best == nil || betterBandwidth(candidate, best, htlc.Amount) {
+			best = candidate
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	return best.Link
+}
+
+// betterBandwidth returns true if the candidate a is preferable to the
+// candidate b for forwarding an HTLC of the passed amount, according to the
+// BestBandwidthSelector.
+func betterBandwidth(a, b *LinkCandidate, amt lnwire.MilliSatoshi) bool {
+	switch {
+	case a.Bandwidth != b.Bandwidth:
+		return a.Bandwidth > b.Bandwidth
+
+	case a.PendingHtlcs != b.PendingHtlcs:
+		return a.PendingHtlcs < b.PendingHtlcs
+
+	default:
+		return ExpectedFee(a.Policy, amt) < ExpectedFee(b.Policy, amt)
+	}
+}
+
+// policyPermitsHtlc returns true if an outgoing HTLC of the passed amount
+// satisfies the size limits of the passed forwarding policy.
+func policyPermitsHtlc(policy *ForwardingPolicy, amt lnwire.MilliSatoshi) bool {
+	if amt < policy.MinHTLC {
+		return false
+	}
+
+	return policy.MaxHTLC == 0 || amt <= policy.MaxHTLC
+}
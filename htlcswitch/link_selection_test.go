@@ -0,0 +1,102 @@
+package htlcswitch
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestBestBandwidthSelector tests that the BestBandwidthSelector selects the
+// link with the most bandwidth among those whose policy permits the HTLC,
+// breaking ties by the number of pending HTLCs, and then by fee.
+func TestBestBandwidthSelector(t *testing.T) {
+	t.Parallel()
+
+	newLink := func(id uint64) ChannelLink {
+		return newMockChannelLink(
+			nil, lnwire.ChannelID{byte(id)},
+			lnwire.NewShortChanIDFromInt(id), nil, true,
+		)
+	}
+	linkA, linkB, linkC := newLink(1), newLink(2), newLink(3)
+
+	htlc := &lnwire.UpdateAddHTLC{
+		Amount: 1000,
+	}
+
+	tests := []struct {
+		name       string
+		candidates []*LinkCandidate
+		expected   ChannelLink
+	}{
+		{
+			name:     "no candidates",
+			expected: nil,
+		},
+		{
+			name: "most bandwidth",
+			candidates: []*LinkCandidate{
+				{Link: linkA, Bandwidth: 2000},
+				{Link: linkB, Bandwidth: 5000, PendingHtlcs: 10},
+				{Link: linkC, Bandwidth: 3000},
+			},
+			expected: linkB,
+		},
+		{
+			name: "policy excludes link",
+			candidates: []*LinkCandidate{
+				{Link: linkA, Bandwidth: 2000},
+				{
+					Link:      linkB,
+					Bandwidth: 5000,
+					Policy:    ForwardingPolicy{MaxHTLC: 500},
+				},
+				{
+					Link:      linkC,
+					Bandwidth: 5000,
+					Policy:    ForwardingPolicy{MinHTLC: 2000},
+				},
+			},
+			expected: linkA,
+		},
+		{
+			name: "fewest pending htlcs",
+			candidates: []*LinkCandidate{
+				{Link: linkA, Bandwidth: 5000, PendingHtlcs: 3},
+				{Link: linkB, Bandwidth: 5000, PendingHtlcs: 1},
+				{Link: linkC, Bandwidth: 5000, PendingHtlcs: 2},
+			},
+			expected: linkB,
+		},
+		{
+			name: "lowest fee",
+			candidates: []*LinkCandidate{
+				{
+					Link:      linkA,
+					Bandwidth: 5000,
+					Policy:    ForwardingPolicy{BaseFee: 3},
+				},
+				{
+					Link:      linkB,
+					Bandwidth: 5000,
+					Policy:    ForwardingPolicy{BaseFee: 2},
+				},
+				{
+					Link:      linkC,
+					Bandwidth: 5000,
+					Policy:    ForwardingPolicy{BaseFee: 4},
+				},
+			},
+			expected: linkB,
+		},
+	}
+
+	selector := &BestBandwidthSelector{}
+	for _, test := range tests {
+		link := selector.SelectLink(htlc, test.candidates)
+		if link != test.expected {
+			t.Fatalf("%v: expected link %v, got %v", test.name,
+				test.expected, link)
+		}
+	}
+}
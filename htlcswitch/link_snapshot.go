@@ -0,0 +1,188 @@
+package htlcswitch
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// ErrLinkShuttingDown is returned when a request can't be serviced as the
+// link is shutting down.
+var ErrLinkShuttingDown = errors.New("link shutting down")
+
+// LinkSnapshot is a consistent, point-in-time view of the state of a channel
+// link. It's assembled by the link's main goroutine, so callers are able to
+// obtain the full status of a link with a single request.
+type LinkSnapshot struct {
+	// ChanID is the channel ID of the link's channel.
+	ChanID lnwire.ChannelID
+
+	// ShortChanID is the short channel ID of the link's channel.
+	ShortChanID lnwire.ShortChannelID
+
+	// ChannelPoint is the funding outpoint of the link's channel.
+	ChannelPoint wire.OutPoint
+
+	// EligibleToForward is true if the link is able to forward HTLCs.
+	EligibleToForward bool
+
+	// FullySynced is true if both commitment chains of the channel contain
+	// the same set of updates.
+	FullySynced bool
+
+	// Bandwidth is the amount that can currently flow through the link.
+	Bandwidth lnwire.MilliSatoshi
+
+	// ReceivableBandwidth is the amount that the remote party can
+	// currently send to us through the link.
+	ReceivableBandwidth lnwire.MilliSatoshi
+
+	// LocalInFlightHeadroom is the additional value of outgoing HTLCs
+	// that the remote party permits us to add to the channel.
+	LocalInFlightHeadroom lnwire.MilliSatoshi
+
+	// RemoteInFlightHeadroom is the additional value of incoming HTLCs
+	// that we permit the remote party to add to the channel.
+	RemoteInFlightHeadroom lnwire.MilliSatoshi
+
+	// NumPendingIncoming is the number of incoming HTLCs that are locked
+	// into both commitment transactions.
+	NumPendingIncoming int
+
+	// NumPendingOutgoing is the number of outgoing HTLCs that are locked
+	// into both commitment transactions.
+	NumPendingOutgoing int
+
+	// OutgoingHtlcAges is the amount of time each of the unresolved
+	// outgoing HTLCs of the link has been pending for, keyed by the index
+	// of the HTLC within our local update log.
+	OutgoingHtlcAges map[uint64]time.Duration
+
+	// CommitHeight is the height of our current local commitment.
+	CommitHeight uint64
+
+	// TotalMSatSent is the total amount sent over the channel.
+	TotalMSatSent lnwire.MilliSatoshi
+
+	// TotalMSatReceived is the total amount received over the channel.
+	TotalMSatReceived lnwire.MilliSatoshi
+
+	// LastCommitUpdate is the time the link last sent or received a new
+	// commitment signature. It's the zero time if no commitment has been
+	// exchanged since the link was started.
+	LastCommitUpdate time.Time
+
+	// Policy is the forwarding policy currently used by the link.
+	Policy ForwardingPolicy
+
+	// BatchCounter is the number of updates that have been received from
+	// or sent to the remote party, but not yet included within a
+	// commitment.
+	BatchCounter uint32
+
+	// PendingRemoteCommit is true if we've signed a new commitment for
+	// the remote party that they haven't yet revoked their prior
+	// commitment for.
+	PendingRemoteCommit bool
+
+	// MailboxMessages is the number of wire messages queued within the
+	// link's mailbox.
+	MailboxMessages int
+
+	// MailboxPackets is the number of htlc packets queued within the
+	// link's mailbox.
+	MailboxPackets int
+
+	// MailboxStats holds the full statistics of the link's mailbox,
+	// including the peak depths of its queues, and how often backpressure
+	// has been applied.
+	MailboxStats MailBoxStats
+
+	// OverflowQueueLen is the number of htlc packets held within the
+	// link's overflow queue, as the channel's HTLC slots are exhausted.
+	OverflowQueueLen int32
+
+	// AdmissionStats describes the link's overflow queue, and the
+	// admission of HTLCs to it.
+	AdmissionStats AdmissionStats
+
+	// FeeSpikeStats describes the forwarded HTLCs the link has declined
+	// as uneconomical to claim on-chain.
+	FeeSpikeStats FeeSpikeStats
+}
+
+// linkSnapshotReq is a message sent to a channel link in order to obtain a
+// snapshot of its state.
+type linkSnapshotReq struct {
+	resp chan *LinkSnapshot
+}
+
+// LinkSnapshot returns a consistent snapshot of the current state of the link.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) LinkSnapshot() (*LinkSnapshot, error) {
+	req := &linkSnapshotReq{
+		resp: make(chan *LinkSnapshot, 1),
+	}
+
+	select {
+	case l.linkControl <- req:
+	case <-l.quit:
+		return nil, ErrLinkShuttingDown
+	}
+
+	select {
+	case snapshot := <-req.resp:
+		return snapshot, nil
+	case <-l.quit:
+		return nil, ErrLinkShuttingDown
+	}
+}
+
+// snapshot assembles a snapshot of the current state of the link.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) snapshot() *LinkSnapshot {
+	chanState := l.channel.StateSnapshot()
+
+	snapshot := &LinkSnapshot{
+		ChanID:            l.ChanID(),
+		ShortChanID:       l.ShortChanID(),
+		ChannelPoint:      *l.channel.ChannelPoint(),
+		EligibleToForward: l.EligibleToForward(),
+		FullySynced:       l.channel.FullySynced(),
+		Bandwidth:         l.Bandwidth(),
+		OutgoingHtlcAges:  l.OutgoingHtlcAges(),
+		CommitHeight:      chanState.ChannelCommitment.CommitHeight,
+		TotalMSatSent:     chanState.TotalMSatSent,
+		TotalMSatReceived: chanState.TotalMSatReceived,
+		LastCommitUpdate:  l.lastCommitUpdate,
+		Policy:            l.cfg.FwrdingPolicy,
+		BatchCounter:      l.batchCounter,
+		OverflowQueueLen:  l.overflowQueue.Length(),
+
+		PendingRemoteCommit: l.channel.PendingRemoteCommitment(),
+		ReceivableBandwidth: l.ReceivableBandwidth(),
+	}
+
+	snapshot.AdmissionStats = l.AdmissionStats()
+	snapshot.FeeSpikeStats = l.FeeSpikeStats()
+	snapshot.MailboxStats = l.mailBox.Stats()
+	snapshot.MailboxMessages = snapshot.MailboxStats.Messages
+	snapshot.MailboxPackets = snapshot.MailboxStats.Packets
+
+	snapshot.LocalInFlightHeadroom, snapshot.RemoteInFlightHeadroom =
+		l.InFlightHeadroom()
+
+	for _, htlc := range l.channel.ActiveHtlcs() {
+		if htlc.Incoming {
+			snapshot.NumPendingIncoming++
+		} else {
+			snapshot.NumPendingOutgoing++
+		}
+	}
+
+	return snapshot
+}
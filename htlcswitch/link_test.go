@@ -2,6 +2,7 @@ package htlcswitch
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"runtime"
 	"strings"
@@ -9,8 +10,6 @@ import (
 	"testing"
 	"time"
 
-	"io"
-
 	"math"
 
 	"github.com/davecgh/go-spew/spew"
@@ -234,7 +233,7 @@ func TestChannelLinkSingleHopPayment(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to get invoice: %v", err)
 	}
-	if !invoice.Terms.Settled {
+	if invoice.Terms.State != channeldb.ContractSettled {
 		t.Fatal("alice invoice wasn't settled")
 	}
 
@@ -459,7 +458,7 @@ func TestChannelLinkMultiHopPayment(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to get invoice: %v", err)
 	}
-	if !invoice.Terms.Settled {
+	if invoice.Terms.State != channeldb.ContractSettled {
 		t.Fatal("carol invoice haven't been settled")
 	}
 
@@ -752,6 +751,150 @@ func TestLinkForwardMinHTLCPolicyMismatch(t *testing.T) {
 	}
 }
 
+// TestLinkForwardMaxHTLCPolicyMismatch tests that an HTLC which exceeds the
+// max HTLC policy of either the incoming or the outgoing link of an
+// intermediate node is rejected with a TemporaryChannelFailure carrying the
+// latest channel update.
+func TestLinkForwardMaxHTLCPolicyMismatch(t *testing.T) {
+	t.Parallel()
+
+	channels, cleanUp, _, err := createClusterChannels(
+		btcutil.SatoshiPerBitcoin*5,
+		btcutil.SatoshiPerBitcoin*5)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	n := newThreeHopNetwork(t, channels.aliceToBob, channels.bobToAlice,
+		channels.bobToCarol, channels.carolToBob, testStartingHeight)
+	if err := n.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer n.stop()
+
+	amountNoFee := lnwire.NewMSatFromSatoshis(10000)
+	maxHTLC := amountNoFee - 1
+
+	assertMaxHTLCFailure := func() {
+		htlcAmt, htlcExpiry, hops := generateHops(amountNoFee,
+			testStartingHeight, n.firstBobChannelLink,
+			n.carolChannelLink)
+
+		_, err := n.makePayment(n.aliceServer, n.carolServer,
+			n.bobServer.PubKey(), hops, amountNoFee, htlcAmt,
+			htlcExpiry).Wait(30 * time.Second)
+		if err == nil {
+			t.Fatalf("payment should have failed but didn't")
+		}
+
+		ferr, ok := err.(*ForwardingError)
+		if !ok {
+			t.Fatalf("expected a ForwardingError, instead got: %T",
+				err)
+		}
+
+		failure, ok := ferr.FailureMessage.(*lnwire.FailTemporaryChannelFailure)
+		if !ok {
+			t.Fatalf("incorrect error, expected temporary channel "+
+				"failure, instead have: %v", err)
+		}
+		if failure.Update == nil {
+			t.Fatalf("expected failure to carry a channel update")
+		}
+	}
+
+	// First, we'll cap the value of the HTLCs Bob is willing to forward
+	// to Carol, just below the value of our payment. The HTLC should be
+	// rejected when Bob attempts to add it to the outgoing channel.
+	n.secondBobChannelLink.UpdateForwardingPolicy(ForwardingPolicy{
+		MaxHTLC: maxHTLC,
+	})
+	assertMaxHTLCFailure()
+
+	// Lifting the cap on the outgoing link, and instead capping the value
+	// of the HTLCs Bob accepts from Alice, should also cause the HTLC to
+	// be rejected, this time as soon as it's locked in.
+	n.secondBobChannelLink.UpdateForwardingPolicy(ForwardingPolicy{
+		MaxHTLC: lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin),
+	})
+	n.firstBobChannelLink.UpdateForwardingPolicy(ForwardingPolicy{
+		MaxHTLC: maxHTLC,
+	})
+	assertMaxHTLCFailure()
+}
+
+// TestLinkForwardHtlcLimits tests that HTLCs exceeding the MaxAcceptedHtlcs
+// or MaxPendingAmount of a link are failed back with a temporary channel
+// failure, whether they're offered over the link or received over it, rather
+// than causing the link to fail.
+func TestLinkForwardHtlcLimits(t *testing.T) {
+	t.Parallel()
+
+	amountNoFee := lnwire.NewMSatFromSatoshis(10000)
+
+	assertLimitFailure := func(setLimits func(n *threeHopNetwork)) {
+		channels, cleanUp, _, err := createClusterChannels(
+			btcutil.SatoshiPerBitcoin*5,
+			btcutil.SatoshiPerBitcoin*5)
+		if err != nil {
+			t.Fatalf("unable to create channel: %v", err)
+		}
+		defer cleanUp()
+
+		n := newThreeHopNetwork(t, channels.aliceToBob,
+			channels.bobToAlice, channels.bobToCarol,
+			channels.carolToBob, testStartingHeight)
+		setLimits(n)
+		if err := n.start(); err != nil {
+			t.Fatal(err)
+		}
+		defer n.stop()
+
+		htlcAmt, htlcExpiry, hops := generateHops(amountNoFee,
+			testStartingHeight, n.firstBobChannelLink,
+			n.carolChannelLink)
+
+		_, err = n.makePayment(n.aliceServer, n.carolServer,
+			n.bobServer.PubKey(), hops, amountNoFee, htlcAmt,
+			htlcExpiry).Wait(30 * time.Second)
+		if err == nil {
+			t.Fatalf("payment should have failed but didn't")
+		}
+
+		ferr, ok := err.(*ForwardingError)
+		if !ok {
+			t.Fatalf("expected a ForwardingError, instead got: %T",
+				err)
+		}
+		_, ok = ferr.FailureMessage.(*lnwire.FailTemporaryChannelFailure)
+		if !ok {
+			t.Fatalf("incorrect error, expected temporary channel "+
+				"failure, instead have: %v", err)
+		}
+
+		// The links should remain active, as the HTLC was failed
+		// back rather than the link.
+		if !n.firstBobChannelLink.EligibleToForward() {
+			t.Fatalf("link between alice and bob should still " +
+				"be active")
+		}
+	}
+
+	// Bounding the value of the HTLCs Bob may offer to Carol below the
+	// value of our payment should cause it to be rejected when Bob
+	// attempts to forward it.
+	assertLimitFailure(func(n *threeHopNetwork) {
+		n.secondBobChannelLink.cfg.MaxPendingAmount = amountNoFee - 1
+	})
+
+	// Bounding the value of the HTLCs Bob accepts from Alice should cause
+	// it to be failed back as soon as it's locked in.
+	assertLimitFailure(func(n *threeHopNetwork) {
+		n.firstBobChannelLink.cfg.MaxPendingAmount = amountNoFee - 1
+	})
+}
+
 // TestUpdateForwardingPolicy tests that the forwarding policy for a link is
 // able to be updated properly. We'll first create an HTLC that meets the
 // specified policy, assert that it succeeds, update the policy (to invalidate
@@ -799,7 +942,7 @@ func TestUpdateForwardingPolicy(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to get invoice: %v", err)
 	}
-	if !invoice.Terms.Settled {
+	if invoice.Terms.State != channeldb.ContractSettled {
 		t.Fatal("carol invoice haven't been settled")
 	}
 
@@ -854,6 +997,75 @@ func TestUpdateForwardingPolicy(t *testing.T) {
 	}
 }
 
+// TestUpdateForwardingPolicyFeeController tests that a FeeController can be
+// installed on a running link, and that the fees it computes are enforced for
+// each HTLC forwarded from then on.
+func TestUpdateForwardingPolicyFeeController(t *testing.T) {
+	t.Parallel()
+
+	channels, cleanUp, _, err := createClusterChannels(
+		btcutil.SatoshiPerBitcoin*5,
+		btcutil.SatoshiPerBitcoin*5)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	n := newThreeHopNetwork(t, channels.aliceToBob, channels.bobToAlice,
+		channels.bobToCarol, channels.carolToBob, testStartingHeight)
+	if err := n.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer n.stop()
+
+	amountNoFee := lnwire.NewMSatFromSatoshis(10)
+	htlcAmt, htlcExpiry, hops := generateHops(amountNoFee,
+		testStartingHeight,
+		n.firstBobChannelLink, n.carolChannelLink)
+
+	// We'll install a controller on Bob's link whose only scheduled fees
+	// don't take effect for another hour, so the payment should succeed
+	// under Bob's current policy.
+	controller := NewScheduledFeeController(ScheduledFee{
+		Start:   time.Now().Add(time.Hour),
+		BaseFee: lnwire.NewMSatFromSatoshis(1000),
+	})
+	n.firstBobChannelLink.UpdateForwardingPolicy(ForwardingPolicy{
+		FeeController: controller,
+	})
+
+	_, err = n.makePayment(n.aliceServer, n.carolServer,
+		n.bobServer.PubKey(), hops, amountNoFee, htlcAmt,
+		htlcExpiry).Wait(30 * time.Second)
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	// Once fees which have already taken effect are scheduled, the same
+	// payment should be rejected, without the link being restarted.
+	controller.Schedule(ScheduledFee{
+		Start:   time.Now().Add(-time.Minute),
+		BaseFee: lnwire.NewMSatFromSatoshis(1000),
+	})
+
+	_, err = n.makePayment(n.aliceServer, n.carolServer,
+		n.bobServer.PubKey(), hops, amountNoFee, htlcAmt,
+		htlcExpiry).Wait(30 * time.Second)
+	if err == nil {
+		t.Fatalf("payment should've been rejected")
+	}
+
+	ferr, ok := err.(*ForwardingError)
+	if !ok {
+		t.Fatalf("expected a ForwardingError, instead got: %T", err)
+	}
+	switch ferr.FailureMessage.(type) {
+	case *lnwire.FailFeeInsufficient:
+	default:
+		t.Fatalf("expected FailFeeInsufficient instead got: %v", err)
+	}
+}
+
 // TestChannelLinkMultiHopInsufficientPayment checks that we receive error if
 // bob<->alice channel has insufficient BTC capacity/bandwidth. In this test we
 // send the payment from Carol to Alice over Bob peer. (Carol -> Bob -> Alice)
@@ -915,7 +1127,7 @@ func TestChannelLinkMultiHopInsufficientPayment(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to get invoice: %v", err)
 	}
-	if invoice.Terms.Settled {
+	if invoice.Terms.State == channeldb.ContractSettled {
 		t.Fatal("carol invoice have been settled")
 	}
 
@@ -986,7 +1198,7 @@ func TestChannelLinkMultiHopUnknownPaymentHash(t *testing.T) {
 	invoice.Terms.PaymentPreimage[0] ^= byte(255)
 
 	// Check who is last in the route and add invoice to server registry.
-	if err := n.carolServer.registry.AddInvoice(*invoice); err != nil {
+	if err := n.carolServer.registry.AddInvoice(invoice); err != nil {
 		t.Fatalf("unable to add invoice in carol registry: %v", err)
 	}
 
@@ -1002,7 +1214,7 @@ func TestChannelLinkMultiHopUnknownPaymentHash(t *testing.T) {
 
 	// Check that alice invoice wasn't settled and bandwidth of htlc
 	// links hasn't been changed.
-	if invoice.Terms.Settled {
+	if invoice.Terms.State == channeldb.ContractSettled {
 		t.Fatal("alice invoice was settled")
 	}
 
@@ -1079,7 +1291,7 @@ func TestChannelLinkMultiHopUnknownNextHop(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to get invoice: %v", err)
 	}
-	if invoice.Terms.Settled {
+	if invoice.Terms.State == channeldb.ContractSettled {
 		t.Fatal("carol invoice have been settled")
 	}
 
@@ -1124,10 +1336,10 @@ func TestChannelLinkMultiHopDecodeError(t *testing.T) {
 	}
 	defer n.stop()
 
-	// Replace decode function with another which throws an error.
-	n.carolChannelLink.cfg.DecodeOnionObfuscator = func(
-		r io.Reader) (ErrorEncrypter, lnwire.FailCode) {
-		return nil, lnwire.CodeInvalidOnionVersion
+	// Replace onion processor with another which throws an error when
+	// extracting the error encrypter.
+	n.carolChannelLink.cfg.OnionProcessor = &mockIteratorDecoder{
+		extractFailCode: lnwire.CodeInvalidOnionVersion,
 	}
 
 	carolBandwidthBefore := n.carolChannelLink.Bandwidth()
@@ -1167,7 +1379,7 @@ func TestChannelLinkMultiHopDecodeError(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to get invoice: %v", err)
 	}
-	if invoice.Terms.Settled {
+	if invoice.Terms.State == channeldb.ContractSettled {
 		t.Fatal("carol invoice have been settled")
 	}
 
@@ -1435,8 +1647,8 @@ func newSingleLinkTestHarness(chanAmt btcutil.Amount) (ChannelLink, func(), erro
 
 	var (
 		invoiveRegistry = newMockRegistry()
-		decoder         = &mockIteratorDecoder{}
 		obfuscator      = newMockObfuscator()
+		decoder         = &mockIteratorDecoder{obfuscator: obfuscator}
 		alicePeer       mockPeer
 
 		globalPolicy = ForwardingPolicy{
@@ -1452,13 +1664,10 @@ func newSingleLinkTestHarness(chanAmt btcutil.Amount) (ChannelLink, func(), erro
 	}
 
 	aliceCfg := ChannelLinkConfig{
-		FwrdingPolicy:     globalPolicy,
-		Peer:              &alicePeer,
-		Switch:            New(Config{}),
-		DecodeHopIterator: decoder.DecodeHopIterator,
-		DecodeOnionObfuscator: func(io.Reader) (ErrorEncrypter, lnwire.FailCode) {
-			return obfuscator, lnwire.CodeNone
-		},
+		FwrdingPolicy:        globalPolicy,
+		Peer:                 &alicePeer,
+		Switch:               New(Config{}),
+		OnionProcessor:       decoder,
 		GetLastChannelUpdate: mockGetChanUpdateMessage,
 		PreimageCache:        pCache,
 		UpdateContractSignals: func(*contractcourt.ContractSignals) error {
@@ -1471,12 +1680,17 @@ func newSingleLinkTestHarness(chanAmt btcutil.Amount) (ChannelLink, func(), erro
 
 	const startingHeight = 100
 	aliceLink := NewChannelLink(aliceCfg, aliceChannel, startingHeight)
+	mailBox := newMemoryMailBox()
+	mailBox.Start()
+	aliceLink.AttachMailBox(mailBox)
 	if err := aliceLink.Start(); err != nil {
+		mailBox.Stop()
 		return nil, nil, err
 	}
 
 	cleanUp := func() {
 		defer fCleanUp()
+		defer mailBox.Stop()
 		defer aliceLink.Stop()
 	}
 
@@ -1995,7 +2209,7 @@ func TestChannelRetransmission(t *testing.T) {
 				err = errors.Errorf("unable to get invoice: %v", err)
 				continue
 			}
-			if !invoice.Terms.Settled {
+			if invoice.Terms.State != channeldb.ContractSettled {
 				err = errors.Errorf("alice invoice haven't been settled")
 				continue
 			}
@@ -2317,7 +2531,7 @@ func TestChannelLinkRejectDuplicatePayment(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := n.carolServer.registry.AddInvoice(*invoice); err != nil {
+	if err := n.carolServer.registry.AddInvoice(invoice); err != nil {
 		t.Fatalf("unable to add invoice in carol registry: %v", err)
 	}
 
@@ -2340,3 +2554,1792 @@ func TestChannelLinkRejectDuplicatePayment(t *testing.T) {
 }
 
 // TODO(roasbeef): add test for re-sending after hodl mode, to settle any lingering
+
+// TestChannelLinkOutgoingHtlcAge ensures that the link tracks the age of its
+// outgoing HTLCs until they're resolved by the remote party.
+func TestChannelLinkOutgoingHtlcAge(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	aliceLink, cleanUp, err := newSingleLinkTestHarness(chanAmt)
+	if err != nil {
+		t.Fatalf("unable to create link: %v", err)
+	}
+	defer cleanUp()
+
+	// We'll send a single HTLC into the link, which should then be
+	// tracked as an unresolved outgoing HTLC.
+	var mockBlob [lnwire.OnionPacketSize]byte
+	htlcAmt := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	invoice, htlc, err := generatePayment(htlcAmt, htlcAmt, 5, mockBlob)
+	if err != nil {
+		t.Fatalf("unable to create payment: %v", err)
+	}
+	aliceLink.HandleSwitchPacket(&htlcPacket{
+		htlc: htlc,
+	})
+	time.Sleep(time.Millisecond * 500)
+
+	ages := aliceLink.OutgoingHtlcAges()
+	if _, ok := ages[0]; !ok || len(ages) != 1 {
+		t.Fatalf("expected a single outgoing htlc, got %v", ages)
+	}
+
+	// Once the remote party settles the HTLC, it should no longer be
+	// tracked.
+	aliceLink.HandleChannelUpdate(&lnwire.UpdateFufillHTLC{
+		ID:              0,
+		PaymentPreimage: invoice.Terms.PaymentPreimage,
+	})
+	time.Sleep(time.Millisecond * 500)
+
+	ages = aliceLink.OutgoingHtlcAges()
+	if len(ages) != 0 {
+		t.Fatalf("expected no outgoing htlcs, got %v", ages)
+	}
+}
+
+// TestChannelLinkStuckHtlcReport ensures that outgoing HTLCs that have been
+// unresolved for longer than the configured threshold are reported exactly
+// once.
+func TestChannelLinkStuckHtlcReport(t *testing.T) {
+	t.Parallel()
+
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, _, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, btcutil.SatoshiPerBitcoin,
+		btcutil.SatoshiPerBitcoin, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	var reported []StuckHTLC
+	link := NewChannelLink(ChannelLinkConfig{
+		StuckHTLCThreshold: time.Hour,
+		NotifyStuckHTLC: func(htlc StuckHTLC) {
+			reported = append(reported, htlc)
+		},
+	}, aliceChannel, testStartingHeight).(*channelLink)
+
+	// We'll track two HTLCs: one that has been pending for longer than
+	// the threshold, and one that was just added.
+	now := time.Now()
+	link.trackOutgoingHtlc(0, &lnwire.UpdateAddHTLC{
+		PaymentHash: [32]byte{1},
+		Amount:      1000,
+	}, now.Add(-2*time.Hour))
+	link.trackOutgoingHtlc(1, &lnwire.UpdateAddHTLC{
+		PaymentHash: [32]byte{2},
+		Amount:      1000,
+	}, now)
+
+	// Only the first HTLC should be reported, and it should only be
+	// reported once.
+	link.checkStuckHtlcs()
+	link.checkStuckHtlcs()
+	if len(reported) != 1 {
+		t.Fatalf("expected 1 stuck htlc, got %v", len(reported))
+	}
+	if reported[0].HtlcIndex != 0 ||
+		reported[0].PaymentHash != [32]byte{1} ||
+		reported[0].Age < 2*time.Hour {
+
+		t.Fatalf("unexpected stuck htlc: %v", spew.Sdump(reported[0]))
+	}
+	if reported[0].ChanPoint != *aliceChannel.ChannelPoint() {
+		t.Fatalf("wrong channel point: expected %v, got %v",
+			aliceChannel.ChannelPoint(), reported[0].ChanPoint)
+	}
+}
+
+// TestChannelLinkHTLCStats ensures that the link accounts for the resolution
+// of its outgoing HTLCs within the HTLC statistics of the channel, and resets
+// them once they've been recorded.
+func TestChannelLinkHTLCStats(t *testing.T) {
+	t.Parallel()
+
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, _, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, btcutil.SatoshiPerBitcoin,
+		btcutil.SatoshiPerBitcoin, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	var recorded []channeldb.ChannelHTLCStats
+	link := NewChannelLink(ChannelLinkConfig{
+		RecordHTLCStats: func(stats *channeldb.ChannelHTLCStats) error {
+			recorded = append(recorded, *stats)
+			return nil
+		},
+	}, aliceChannel, testStartingHeight).(*channelLink)
+
+	// We'll track two HTLCs offered a second apart, the first of which is
+	// then settled by the remote party, and the second failed.
+	start := time.Now()
+	link.htlcStatsSince = start
+	link.trackOutgoingHtlc(0, &lnwire.UpdateAddHTLC{}, start)
+	link.trackOutgoingHtlc(1, &lnwire.UpdateAddHTLC{}, start)
+	link.htlcStats.NumForwarded = 2
+
+	link.resolveOutgoingHtlc(0, true)
+	link.resolveOutgoingHtlc(1, false)
+
+	// Resolving an HTLC that isn't tracked shouldn't affect the stats.
+	link.resolveOutgoingHtlc(2, true)
+
+	link.recordHTLCStats(start.Add(time.Hour))
+	if len(recorded) != 1 {
+		t.Fatalf("expected stats to be recorded once, got %v",
+			len(recorded))
+	}
+
+	stats := recorded[0]
+	if stats.NumForwarded != 2 || stats.NumSettled != 1 ||
+		stats.NumFailed != 1 || stats.Uptime != time.Hour {
+
+		t.Fatalf("unexpected stats: %v", spew.Sdump(stats))
+	}
+	if stats.TotalSettleLatency < 0 ||
+		stats.TotalSettleLatency > time.Since(start) {
+
+		t.Fatalf("unexpected settle latency: %v",
+			stats.TotalSettleLatency)
+	}
+
+	// Once recorded, the stats should be reset, with the uptime accounted
+	// for from the time of recording.
+	link.recordHTLCStats(start.Add(2 * time.Hour))
+	expected := channeldb.ChannelHTLCStats{Uptime: time.Hour}
+	if recorded[1] != expected {
+		t.Fatalf("unexpected stats: %v", spew.Sdump(recorded[1]))
+	}
+}
+
+// TestChannelLinkSnapshot ensures that the snapshot returned by a link
+// reflects its current state.
+func TestChannelLinkSnapshot(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	aliceLink, cleanUp, err := newSingleLinkTestHarness(chanAmt)
+	if err != nil {
+		t.Fatalf("unable to create link: %v", err)
+	}
+	defer cleanUp()
+
+	snapshot, err := aliceLink.LinkSnapshot()
+	if err != nil {
+		t.Fatalf("unable to obtain link snapshot: %v", err)
+	}
+	if snapshot.ChanID != aliceLink.ChanID() ||
+		snapshot.ShortChanID != aliceLink.ShortChanID() {
+
+		t.Fatalf("snapshot has wrong channel id")
+	}
+	if !snapshot.FullySynced {
+		t.Fatalf("fresh channel should be fully synced")
+	}
+	if !snapshot.LastCommitUpdate.IsZero() {
+		t.Fatalf("no commitment should have been exchanged yet")
+	}
+	if snapshot.Bandwidth != aliceLink.Bandwidth() {
+		t.Fatalf("wrong bandwidth: expected %v, got %v",
+			aliceLink.Bandwidth(), snapshot.Bandwidth)
+	}
+	if snapshot.Policy.TimeLockDelta != 6 {
+		t.Fatalf("wrong policy: %v", spew.Sdump(snapshot.Policy))
+	}
+
+	// After sending an HTLC into the link, it should be reflected within
+	// the snapshot, along with the commitment that was sent to cover it.
+	var mockBlob [lnwire.OnionPacketSize]byte
+	htlcAmt := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	_, htlc, err := generatePayment(htlcAmt, htlcAmt, 5, mockBlob)
+	if err != nil {
+		t.Fatalf("unable to create payment: %v", err)
+	}
+	aliceLink.HandleSwitchPacket(&htlcPacket{
+		htlc: htlc,
+	})
+	time.Sleep(time.Millisecond * 500)
+
+	snapshot, err = aliceLink.LinkSnapshot()
+	if err != nil {
+		t.Fatalf("unable to obtain link snapshot: %v", err)
+	}
+	if len(snapshot.OutgoingHtlcAges) != 1 {
+		t.Fatalf("expected 1 outgoing htlc, got %v",
+			len(snapshot.OutgoingHtlcAges))
+	}
+	if snapshot.LastCommitUpdate.IsZero() {
+		t.Fatalf("commitment update not recorded")
+	}
+	if snapshot.FullySynced {
+		t.Fatalf("channel shouldn't be fully synced")
+	}
+	if snapshot.Bandwidth != aliceLink.Bandwidth() {
+		t.Fatalf("wrong bandwidth: expected %v, got %v",
+			aliceLink.Bandwidth(), snapshot.Bandwidth)
+	}
+}
+
+// TestChannelLinkShutdownIfChannelClean ensures that a flushing link no
+// longer accepts HTLCs, only stopping once its channel is clean.
+func TestChannelLinkShutdownIfChannelClean(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	aliceLink, cleanUp, err := newSingleLinkTestHarness(chanAmt)
+	if err != nil {
+		t.Fatalf("unable to create link: %v", err)
+	}
+	defer cleanUp()
+
+	// We'll send a single HTLC into the link, which the remote party
+	// never resolves, so the channel won't become clean.
+	var mockBlob [lnwire.OnionPacketSize]byte
+	htlcAmt := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	_, htlc, err := generatePayment(htlcAmt, htlcAmt, 5, mockBlob)
+	if err != nil {
+		t.Fatalf("unable to create payment: %v", err)
+	}
+	aliceLink.HandleSwitchPacket(&htlcPacket{
+		htlc: htlc,
+	})
+	time.Sleep(time.Millisecond * 500)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- aliceLink.ShutdownIfChannelClean()
+	}()
+
+	// As the HTLC is still in flight, the link should remain active,
+	// though it should no longer be eligible to forward HTLCs.
+	select {
+	case err := <-errChan:
+		t.Fatalf("link shouldn't have been flushed: %v", err)
+	case <-time.After(time.Millisecond * 500):
+	}
+	if aliceLink.EligibleToForward() {
+		t.Fatalf("flushing link shouldn't be eligible to forward")
+	}
+
+	// Once the link is stopped, the flush should be abandoned.
+	aliceLink.Stop()
+	select {
+	case err := <-errChan:
+		if err != ErrLinkShuttingDown {
+			t.Fatalf("expected ErrLinkShuttingDown, got %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("flush wasn't abandoned")
+	}
+
+	// A link over a clean channel should be stopped right away.
+	cleanLink, cleanUp2, err := newSingleLinkTestHarness(chanAmt)
+	if err != nil {
+		t.Fatalf("unable to create link: %v", err)
+	}
+	defer cleanUp2()
+
+	go func() {
+		errChan <- cleanLink.ShutdownIfChannelClean()
+	}()
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Fatalf("unable to flush link: %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("clean link wasn't flushed")
+	}
+}
+
+// TestChannelLinkUnendorsedBucket ensures that when the endorsement
+// experiment is active, local payments are endorsed, and unendorsed HTLCs are
+// rejected once they'd exceed the liquidity available to them.
+func TestChannelLinkUnendorsedBucket(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, _, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, chanAmt, chanAmt, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	link := NewChannelLink(ChannelLinkConfig{
+		EndorsementExperiment: true,
+	}, aliceChannel, testStartingHeight).(*channelLink)
+
+	// An incoming HTLC's endorsement should be recorded, and consumed
+	// exactly once.
+	link.recordIncomingEndorsement(0, &lnwire.UpdateAddHTLC{Endorsed: true})
+	if !link.consumeIncomingEndorsement(0) {
+		t.Fatalf("expected incoming htlc to be endorsed")
+	}
+	if link.consumeIncomingEndorsement(0) {
+		t.Fatalf("endorsement should only be consumed once")
+	}
+
+	// We'll now track unendorsed outgoing HTLCs that lock up all of the
+	// liquidity available to unendorsed HTLCs.
+	maxValue := lnwire.NewMSatFromSatoshis(
+		chanAmt / unendorsedLiquidityDivisor,
+	)
+	link.trackOutgoingHtlc(0, &lnwire.UpdateAddHTLC{
+		PaymentHash: [32]byte{1},
+		Amount:      maxValue,
+	}, time.Now())
+
+	// Any further unendorsed HTLC should be rejected.
+	forwarded := &htlcPacket{
+		incomingChanID: lnwire.NewShortChanIDFromInt(5),
+	}
+	_, err = link.addHTLC(forwarded, &lnwire.UpdateAddHTLC{
+		PaymentHash: [32]byte{2},
+		Amount:      1000,
+	})
+	if err != ErrUnendorsedBucketFull {
+		t.Fatalf("expected ErrUnendorsedBucketFull, got %v", err)
+	}
+
+	// An endorsed HTLC shouldn't be restricted by the bucket.
+	_, err = link.addHTLC(forwarded, &lnwire.UpdateAddHTLC{
+		PaymentHash: [32]byte{3},
+		Amount:      1000,
+		Endorsed:    true,
+	})
+	if err != nil {
+		t.Fatalf("unable to add endorsed htlc: %v", err)
+	}
+
+	// Finally, our own payments should always be endorsed.
+	local := &lnwire.UpdateAddHTLC{
+		PaymentHash: [32]byte{4},
+		Amount:      1000,
+	}
+	if _, err := link.addHTLC(&htlcPacket{}, local); err != nil {
+		t.Fatalf("unable to add local htlc: %v", err)
+	}
+	if !local.Endorsed {
+		t.Fatalf("expected local htlc to be endorsed")
+	}
+}
+
+// TestChannelLinkMaxValueInFlight tests that the link enforces the maximum
+// value in flight of both outgoing and incoming HTLCs.
+func TestChannelLinkMaxValueInFlight(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, bobChannel, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, chanAmt, chanAmt, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	// We'll restrict the value of the HTLCs each party may have in flight
+	// within Alice's channel.
+	const maxValue = lnwire.MilliSatoshi(100000)
+	aliceChannel.State().LocalChanCfg.MaxPendingAmount = maxValue
+	aliceChannel.State().RemoteChanCfg.MaxPendingAmount = maxValue
+
+	link := NewChannelLink(
+		ChannelLinkConfig{}, aliceChannel, testStartingHeight,
+	).(*channelLink)
+
+	// The link's bandwidth should be limited by the max value in flight.
+	if link.Bandwidth() != maxValue {
+		t.Fatalf("expected bandwidth of %v, got %v", maxValue,
+			link.Bandwidth())
+	}
+
+	// Alice should be able to offer HTLCs up to the max value in flight,
+	// but no further.
+	_, err = link.addHTLC(&htlcPacket{}, &lnwire.UpdateAddHTLC{
+		PaymentHash: [32]byte{1},
+		Amount:      maxValue - 1000,
+	})
+	if err != nil {
+		t.Fatalf("unable to add htlc: %v", err)
+	}
+	_, err = link.addHTLC(&htlcPacket{}, &lnwire.UpdateAddHTLC{
+		PaymentHash: [32]byte{2},
+		Amount:      1001,
+	})
+	if err != ErrMaxValueInFlight {
+		t.Fatalf("expected ErrMaxValueInFlight, got %v", err)
+	}
+
+	localHeadroom, remoteHeadroom := link.InFlightHeadroom()
+	if localHeadroom != 1000 {
+		t.Fatalf("expected local headroom of 1000, got %v",
+			localHeadroom)
+	}
+	if remoteHeadroom != maxValue {
+		t.Fatalf("expected remote headroom of %v, got %v", maxValue,
+			remoteHeadroom)
+	}
+
+	// Finally, if Bob offers an HTLC that exceeds the max value in flight
+	// Alice permits, then the link should detect the violation.
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: [32]byte{3},
+		Amount:      maxValue + 1,
+	}
+	if _, err := bobChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("unable to add htlc: %v", err)
+	}
+	if _, err := aliceChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("unable to receive htlc: %v", err)
+	}
+	if err := link.checkIncomingInFlight(); err != ErrMaxValueInFlight {
+		t.Fatalf("expected ErrMaxValueInFlight, got %v", err)
+	}
+}
+
+// TestChannelLinkSettleWithWitness tests that the link settles incoming HTLCs
+// using preimages discovered by the witness beacon exactly once, and that any
+// resolution later delivered by the switch for such HTLCs is ignored.
+func TestChannelLinkSettleWithWitness(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, bobChannel, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, chanAmt, chanAmt, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	// We'll lock in an HTLC offered by Bob to Alice.
+	preimage := [32]byte{1, 2, 3}
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(preimage[:]),
+		Amount:      lnwire.NewMSatFromSatoshis(10000),
+	}
+	if _, err := bobChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("unable to add htlc: %v", err)
+	}
+	htlcIndex, err := aliceChannel.ReceiveHTLC(htlc)
+	if err != nil {
+		t.Fatalf("unable to receive htlc: %v", err)
+	}
+	bobSig, bobHtlcSigs, err := bobChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("unable to sign commitment: %v", err)
+	}
+	err = aliceChannel.ReceiveNewCommitment(bobSig, bobHtlcSigs)
+	if err != nil {
+		t.Fatalf("unable to receive commitment: %v", err)
+	}
+	aliceRevocation, _, err := aliceChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("unable to revoke commitment: %v", err)
+	}
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+		t.Fatalf("unable to receive revocation: %v", err)
+	}
+	aliceSig, aliceHtlcSigs, err := aliceChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("unable to sign commitment: %v", err)
+	}
+	err = bobChannel.ReceiveNewCommitment(aliceSig, aliceHtlcSigs)
+	if err != nil {
+		t.Fatalf("unable to receive commitment: %v", err)
+	}
+	bobRevocation, _, err := bobChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("unable to revoke commitment: %v", err)
+	}
+	if _, _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
+		t.Fatalf("unable to receive revocation: %v", err)
+	}
+
+	peer := &mockPeer{}
+	link := NewChannelLink(ChannelLinkConfig{
+		Peer: peer,
+	}, aliceChannel, testStartingHeight).(*channelLink)
+
+	// A preimage for an unrelated hash shouldn't settle anything.
+	numSettled, err := link.settleWithWitness(bytes.Repeat([]byte{9}, 32))
+	if err != nil {
+		t.Fatalf("unable to settle with witness: %v", err)
+	}
+	if numSettled != 0 {
+		t.Fatalf("expected no htlcs to be settled, got %v", numSettled)
+	}
+
+	// The HTLC should be settled once its preimage is discovered, with
+	// the settle being sent to Bob.
+	numSettled, err = link.settleWithWitness(preimage[:])
+	if err != nil {
+		t.Fatalf("unable to settle with witness: %v", err)
+	}
+	if numSettled != 1 {
+		t.Fatalf("expected one htlc to be settled, got %v", numSettled)
+	}
+	settle, ok := peer.popSentMsg().(*lnwire.UpdateFufillHTLC)
+	if !ok {
+		t.Fatalf("expected settle to be sent")
+	}
+	if settle.ID != htlcIndex || settle.PaymentPreimage != preimage {
+		t.Fatalf("settle doesn't match htlc: %v", spew.Sdump(settle))
+	}
+
+	// Learning of the same preimage again shouldn't settle the HTLC a
+	// second time.
+	numSettled, err = link.settleWithWitness(preimage[:])
+	if err != nil {
+		t.Fatalf("unable to settle with witness: %v", err)
+	}
+	if numSettled != 0 {
+		t.Fatalf("expected no htlcs to be settled, got %v", numSettled)
+	}
+
+	// Finally, the settle the switch delivers for the HTLC should be
+	// ignored exactly once.
+	if !link.consumeWitnessSettle(htlcIndex) {
+		t.Fatalf("expected htlc to be settled by witness")
+	}
+	if link.consumeWitnessSettle(htlcIndex) {
+		t.Fatalf("witness settle should only be consumed once")
+	}
+}
+
+// TestChannelLinkChanSyncRetry ensures that the link re-sends its
+// ChannelReestablish message the configured number of times if the remote
+// party's doesn't arrive in time, and that a timeout is reported distinctly
+// from a protocol error.
+func TestChannelLinkChanSyncRetry(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, _, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, chanAmt, chanAmt, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	var alicePeer mockPeer
+	link := NewChannelLink(ChannelLinkConfig{
+		Peer:            &alicePeer,
+		ChanSyncTimeout: 10 * time.Millisecond,
+		ChanSyncRetries: 2,
+	}, aliceChannel, 100).(*channelLink)
+	upstream := make(chan lnwire.Message, 1)
+	link.upstream = upstream
+
+	// As the remote party never responds, the sync should time out after
+	// the initial attempt and both retries.
+	err = link.syncChanStates()
+	syncErr, ok := err.(*ChanSyncError)
+	if !ok {
+		t.Fatalf("expected ChanSyncError, got %v", err)
+	}
+	if syncErr.Kind != ChanSyncTimeout {
+		t.Fatalf("expected timeout, got %v", syncErr.Kind)
+	}
+
+	if len(alicePeer.sentMsgs) != 3 {
+		t.Fatalf("expected 3 messages to be sent, got %v",
+			len(alicePeer.sentMsgs))
+	}
+	for _, msg := range alicePeer.sentMsgs {
+		if _, ok := msg.(*lnwire.ChannelReestablish); !ok {
+			t.Fatalf("expected ChannelReestablish, got %T", msg)
+		}
+	}
+
+	// If the remote party responds with any other message, then a protocol
+	// error should be reported instead.
+	upstream <- &lnwire.CommitSig{}
+	err = link.syncChanStates()
+	syncErr, ok = err.(*ChanSyncError)
+	if !ok {
+		t.Fatalf("expected ChanSyncError, got %v", err)
+	}
+	if syncErr.Kind != ChanSyncProtocolError {
+		t.Fatalf("expected protocol error, got %v", syncErr.Kind)
+	}
+}
+
+// mockExitAcceptor accepts the exit hop HTLCs paying to the payment hashes it
+// maps to true.
+type mockExitAcceptor map[[32]byte]bool
+
+func (m mockExitAcceptor) AcceptExitHTLC(htlc *ExitHTLC,
+	quit <-chan struct{}) bool {
+
+	return m[htlc.PaymentHash]
+}
+
+// TestChannelLinkSafeExitSettle ensures that, when settling exit hop HTLCs
+// safely, an HTLC is only settled once it's irrevocably committed to both
+// commitment transactions and it has been accepted by the acceptor, and that
+// an HTLC rejected by the acceptor is failed.
+func TestChannelLinkSafeExitSettle(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, bobChannel, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, chanAmt, chanAmt, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	// Bob will offer Alice two HTLCs, paying to two of her invoices.
+	registry := newMockRegistry()
+	preimages := [][32]byte{{1}, {2}}
+	var htlcIndexes []uint64
+	for _, preimage := range preimages {
+		invoice := channeldb.Invoice{}
+		invoice.Terms.PaymentPreimage = preimage
+		if err := registry.AddInvoice(&invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		htlc := &lnwire.UpdateAddHTLC{
+			PaymentHash: sha256.Sum256(preimage[:]),
+			Amount:      lnwire.NewMSatFromSatoshis(10000),
+		}
+		if _, err := bobChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("unable to add htlc: %v", err)
+		}
+		htlcIndex, err := aliceChannel.ReceiveHTLC(htlc)
+		if err != nil {
+			t.Fatalf("unable to receive htlc: %v", err)
+		}
+		htlcIndexes = append(htlcIndexes, htlcIndex)
+	}
+
+	// Bob signs a commitment including the HTLCs, which Alice revokes her
+	// prior commitment for. The HTLCs aren't yet irrevocably committed to
+	// Bob's commitment though.
+	bobSig, bobHtlcSigs, err := bobChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("unable to sign commitment: %v", err)
+	}
+	err = aliceChannel.ReceiveNewCommitment(bobSig, bobHtlcSigs)
+	if err != nil {
+		t.Fatalf("unable to receive commitment: %v", err)
+	}
+	aliceRevocation, _, err := aliceChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("unable to revoke commitment: %v", err)
+	}
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+		t.Fatalf("unable to receive revocation: %v", err)
+	}
+
+	// The acceptor will accept the first HTLC, and reject the second.
+	peer := &mockPeer{}
+	link := NewChannelLink(ChannelLinkConfig{
+		Peer:           peer,
+		Registry:       registry,
+		SafeExitSettle: true,
+		ExitHTLCAcceptor: mockExitAcceptor{
+			sha256.Sum256(preimages[0][:]): true,
+		},
+	}, aliceChannel, testStartingHeight).(*channelLink)
+
+	for i, preimage := range preimages {
+		link.holdExitHtlc(&heldExitHtlc{
+			htlc: ExitHTLC{
+				HtlcIndex:   htlcIndexes[i],
+				PaymentHash: sha256.Sum256(preimage[:]),
+			},
+			preimage:   preimage,
+			obfuscator: newMockObfuscator(),
+		})
+	}
+	for range preimages {
+		var decision exitDecision
+		select {
+		case decision = <-link.exitDecisions:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("acceptor didn't decide")
+		}
+		if _, err := link.handleExitDecision(decision); err != nil {
+			t.Fatalf("unable to handle decision: %v", err)
+		}
+	}
+
+	// Only the rejected HTLC should have been failed, as the accepted
+	// HTLC isn't yet irrevocably committed.
+	if len(peer.sentMsgs) != 1 {
+		t.Fatalf("expected one message to be sent, got %v",
+			len(peer.sentMsgs))
+	}
+	fail, ok := peer.popSentMsg().(*lnwire.UpdateFailHTLC)
+	if !ok || fail.ID != htlcIndexes[1] {
+		t.Fatalf("expected fail of htlc %v", htlcIndexes[1])
+	}
+	err = bobChannel.ReceiveFailHTLC(fail.ID, fail.Reason)
+	if err != nil {
+		t.Fatalf("unable to receive fail: %v", err)
+	}
+
+	// Once Alice signs a commitment including the HTLCs for Bob, and Bob
+	// revokes his prior commitment, the accepted HTLC should be settled.
+	aliceSig, aliceHtlcSigs, err := aliceChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("unable to sign commitment: %v", err)
+	}
+	err = bobChannel.ReceiveNewCommitment(aliceSig, aliceHtlcSigs)
+	if err != nil {
+		t.Fatalf("unable to receive commitment: %v", err)
+	}
+	bobRevocation, _, err := bobChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("unable to revoke commitment: %v", err)
+	}
+	if _, _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
+		t.Fatalf("unable to receive revocation: %v", err)
+	}
+
+	updated, err := link.resolveHeldExitHtlcs()
+	if err != nil {
+		t.Fatalf("unable to resolve held htlcs: %v", err)
+	}
+	if !updated {
+		t.Fatalf("expected held htlc to be settled")
+	}
+	settle, ok := peer.popSentMsg().(*lnwire.UpdateFufillHTLC)
+	if !ok || settle.ID != htlcIndexes[0] ||
+		settle.PaymentPreimage != preimages[0] {
+
+		t.Fatalf("expected settle of htlc %v", htlcIndexes[0])
+	}
+	if len(link.heldExitHtlcs) != 0 {
+		t.Fatalf("expected no held htlcs, got %v",
+			len(link.heldExitHtlcs))
+	}
+}
+
+// TestChannelLinkHoldInvoice ensures that an HTLC paying to a hold invoice is
+// held until the invoice is either settled or canceled through the registry,
+// after which it's settled or failed respectively, even if the preimage of
+// the invoice was unknown when the HTLC arrived.
+func TestChannelLinkHoldInvoice(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, bobChannel, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, chanAmt, chanAmt, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	// Bob will offer Alice two HTLCs, paying to two of her hold invoices,
+	// for which she only knows the payment hashes.
+	registry := newMockRegistry()
+	preimages := [][32]byte{{1}, {2}}
+	var htlcIndexes []uint64
+	for _, preimage := range preimages {
+		invoice := channeldb.Invoice{}
+		invoice.Terms.PaymentHash = sha256.Sum256(preimage[:])
+		invoice.Terms.Hold = true
+		if err := registry.AddInvoice(&invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		htlc := &lnwire.UpdateAddHTLC{
+			PaymentHash: sha256.Sum256(preimage[:]),
+			Amount:      lnwire.NewMSatFromSatoshis(10000),
+		}
+		if _, err := bobChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("unable to add htlc: %v", err)
+		}
+		htlcIndex, err := aliceChannel.ReceiveHTLC(htlc)
+		if err != nil {
+			t.Fatalf("unable to receive htlc: %v", err)
+		}
+		htlcIndexes = append(htlcIndexes, htlcIndex)
+	}
+
+	// The HTLCs are then irrevocably committed to both commitments.
+	bobSig, bobHtlcSigs, err := bobChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("unable to sign commitment: %v", err)
+	}
+	err = aliceChannel.ReceiveNewCommitment(bobSig, bobHtlcSigs)
+	if err != nil {
+		t.Fatalf("unable to receive commitment: %v", err)
+	}
+	aliceRevocation, _, err := aliceChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("unable to revoke commitment: %v", err)
+	}
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+		t.Fatalf("unable to receive revocation: %v", err)
+	}
+	aliceSig, aliceHtlcSigs, err := aliceChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("unable to sign commitment: %v", err)
+	}
+	err = bobChannel.ReceiveNewCommitment(aliceSig, aliceHtlcSigs)
+	if err != nil {
+		t.Fatalf("unable to receive commitment: %v", err)
+	}
+	bobRevocation, _, err := bobChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("unable to revoke commitment: %v", err)
+	}
+	if _, _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
+		t.Fatalf("unable to receive revocation: %v", err)
+	}
+
+	peer := &mockPeer{}
+	link := NewChannelLink(ChannelLinkConfig{
+		Peer:     peer,
+		Registry: registry,
+	}, aliceChannel, testStartingHeight).(*channelLink)
+	defer close(link.quit)
+
+	for i, preimage := range preimages {
+		link.holdExitHtlc(&heldExitHtlc{
+			htlc: ExitHTLC{
+				HtlcIndex:   htlcIndexes[i],
+				PaymentHash: sha256.Sum256(preimage[:]),
+			},
+			hold:       true,
+			obfuscator: newMockObfuscator(),
+		})
+	}
+
+	// Although the HTLCs are irrevocably committed, they should be held
+	// until their invoices are resolved.
+	updated, err := link.resolveHeldExitHtlcs()
+	if err != nil {
+		t.Fatalf("unable to resolve held htlcs: %v", err)
+	}
+	if updated || len(peer.sentMsgs) != 0 {
+		t.Fatalf("hold invoice htlcs resolved prematurely")
+	}
+	select {
+	case <-link.exitDecisions:
+		t.Fatalf("hold invoice htlc decided prematurely")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// We'll now settle the first invoice, which should settle its HTLC
+	// with the released preimage, and cancel the second, which should
+	// fail its HTLC.
+	hash1 := chainhash.Hash(sha256.Sum256(preimages[0][:]))
+	if err := registry.SettleHodlInvoice(hash1, preimages[0]); err != nil {
+		t.Fatalf("unable to settle hold invoice: %v", err)
+	}
+	hash2 := chainhash.Hash(sha256.Sum256(preimages[1][:]))
+	if err := registry.CancelHodlInvoice(hash2); err != nil {
+		t.Fatalf("unable to cancel hold invoice: %v", err)
+	}
+	for range preimages {
+		var decision exitDecision
+		select {
+		case decision = <-link.exitDecisions:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("hold invoice htlc wasn't decided")
+		}
+		if _, err := link.handleExitDecision(decision); err != nil {
+			t.Fatalf("unable to handle decision: %v", err)
+		}
+	}
+
+	if len(peer.sentMsgs) != 2 {
+		t.Fatalf("expected two messages to be sent, got %v",
+			len(peer.sentMsgs))
+	}
+	for _, msg := range peer.sentMsgs {
+		switch msg := msg.(type) {
+		case *lnwire.UpdateFufillHTLC:
+			if msg.ID != htlcIndexes[0] ||
+				msg.PaymentPreimage != preimages[0] {
+
+				t.Fatalf("unexpected settle of htlc %v", msg.ID)
+			}
+		case *lnwire.UpdateFailHTLC:
+			if msg.ID != htlcIndexes[1] {
+				t.Fatalf("unexpected fail of htlc %v", msg.ID)
+			}
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+	}
+
+	invoice, err := registry.LookupInvoice(hash1)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if invoice.Terms.State != channeldb.ContractSettled {
+		t.Fatalf("settled hold invoice wasn't marked as settled")
+	}
+	if len(link.heldExitHtlcs) != 0 {
+		t.Fatalf("expected no held htlcs, got %v",
+			len(link.heldExitHtlcs))
+	}
+}
+
+// TestChannelLinkSettleBackpressure tests that the settles of HTLCs paying to
+// our invoices are held, rather than made, while the invoice registry is
+// saturated with settles, and that they're made once it catches up.
+func TestChannelLinkSettleBackpressure(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, bobChannel, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, chanAmt, chanAmt, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	// Bob will offer Alice two HTLCs, paying to two of her invoices.
+	registry := newMockRegistry()
+	preimages := [][32]byte{{1}, {2}}
+	var htlcIndexes []uint64
+	for _, preimage := range preimages {
+		invoice := channeldb.Invoice{}
+		invoice.Terms.PaymentPreimage = preimage
+		if err := registry.AddInvoice(&invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		htlc := &lnwire.UpdateAddHTLC{
+			PaymentHash: sha256.Sum256(preimage[:]),
+			Amount:      lnwire.NewMSatFromSatoshis(10000),
+		}
+		if _, err := bobChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("unable to add htlc: %v", err)
+		}
+		htlcIndex, err := aliceChannel.ReceiveHTLC(htlc)
+		if err != nil {
+			t.Fatalf("unable to receive htlc: %v", err)
+		}
+		htlcIndexes = append(htlcIndexes, htlcIndex)
+	}
+
+	// The HTLCs are then irrevocably committed to both commitments.
+	bobSig, bobHtlcSigs, err := bobChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("unable to sign commitment: %v", err)
+	}
+	err = aliceChannel.ReceiveNewCommitment(bobSig, bobHtlcSigs)
+	if err != nil {
+		t.Fatalf("unable to receive commitment: %v", err)
+	}
+	aliceRevocation, _, err := aliceChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("unable to revoke commitment: %v", err)
+	}
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+		t.Fatalf("unable to receive revocation: %v", err)
+	}
+	aliceSig, aliceHtlcSigs, err := aliceChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("unable to sign commitment: %v", err)
+	}
+	err = bobChannel.ReceiveNewCommitment(aliceSig, aliceHtlcSigs)
+	if err != nil {
+		t.Fatalf("unable to receive commitment: %v", err)
+	}
+	bobRevocation, _, err := bobChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("unable to revoke commitment: %v", err)
+	}
+	if _, _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
+		t.Fatalf("unable to receive revocation: %v", err)
+	}
+
+	peer := &mockPeer{}
+	link := NewChannelLink(ChannelLinkConfig{
+		Peer:     peer,
+		Registry: registry,
+	}, aliceChannel, testStartingHeight).(*channelLink)
+	defer close(link.quit)
+
+	// The first HTLC is held as the registry is already saturated once
+	// it's processed, while the second is only held once the registry
+	// becomes saturated before it's settled.
+	registry.setSaturated(true)
+	link.holdExitHtlc(&heldExitHtlc{
+		htlc: ExitHTLC{
+			HtlcIndex:   htlcIndexes[0],
+			PaymentHash: sha256.Sum256(preimages[0][:]),
+		},
+		preimage:     preimages[0],
+		backpressure: registry.SettleBackpressure(),
+		obfuscator:   newMockObfuscator(),
+	})
+	link.holdExitHtlc(&heldExitHtlc{
+		htlc: ExitHTLC{
+			HtlcIndex:   htlcIndexes[1],
+			PaymentHash: sha256.Sum256(preimages[1][:]),
+		},
+		preimage:   preimages[1],
+		obfuscator: newMockObfuscator(),
+	})
+
+	updated, err := link.resolveHeldExitHtlcs()
+	if err != nil {
+		t.Fatalf("unable to resolve held htlcs: %v", err)
+	}
+	if updated || len(peer.sentMsgs) != 0 {
+		t.Fatalf("htlcs settled while registry saturated")
+	}
+	select {
+	case <-link.exitDecisions:
+		t.Fatalf("htlc decided while registry saturated")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Once the registry catches up, both HTLCs should be settled, and
+	// their invoices marked as such.
+	registry.setSaturated(false)
+	for range preimages {
+		var decision exitDecision
+		select {
+		case decision = <-link.exitDecisions:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("held htlc wasn't released")
+		}
+		if _, err := link.handleExitDecision(decision); err != nil {
+			t.Fatalf("unable to handle decision: %v", err)
+		}
+	}
+
+	if len(peer.sentMsgs) != 2 {
+		t.Fatalf("expected two messages to be sent, got %v",
+			len(peer.sentMsgs))
+	}
+	for i, msg := range peer.sentMsgs {
+		settle, ok := msg.(*lnwire.UpdateFufillHTLC)
+		if !ok {
+			t.Fatalf("unexpected message %T", msg)
+		}
+		if settle.PaymentPreimage != preimages[settle.ID] {
+			t.Fatalf("unexpected settle %v of htlc %v", i,
+				settle.ID)
+		}
+
+		hash := chainhash.Hash(sha256.Sum256(preimages[settle.ID][:]))
+		invoice, err := registry.LookupInvoice(hash)
+		if err != nil {
+			t.Fatalf("unable to find invoice: %v", err)
+		}
+		if invoice.Terms.State != channeldb.ContractSettled {
+			t.Fatalf("invoice of htlc %v wasn't settled", settle.ID)
+		}
+	}
+	if len(link.heldExitHtlcs) != 0 {
+		t.Fatalf("expected no held htlcs, got %v",
+			len(link.heldExitHtlcs))
+	}
+}
+
+// TestChannelLinkPipelineSettle tests that the settle of a forwarded HTLC is
+// relayed to the incoming link as soon as the preimage is received, before
+// the settle is locked in, while the circuit of the HTLC is left open. Should
+// we go down before the settle is locked in, the restored circuit allows the
+// settle to be replayed from its forwarding package.
+func TestChannelLinkPipelineSettle(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, _, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, chanAmt, chanAmt, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	db := aliceChannel.State().Db
+	s := New(Config{DB: db})
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+	defer s.Stop()
+
+	incomingLink := newMockChannelLink(
+		s, chanID1, aliceChanID, &mockPeer{}, true,
+	)
+	if err := s.AddLink(incomingLink); err != nil {
+		t.Fatalf("unable to add incoming link: %v", err)
+	}
+
+	// Alice offers Bob an HTLC, which is the outgoing leg of a circuit
+	// opened by the switch.
+	preimage := [32]byte{1}
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(preimage[:]),
+		Amount:      lnwire.NewMSatFromSatoshis(10000),
+	}
+	htlcIndex, err := aliceChannel.AddHTLC(htlc)
+	if err != nil {
+		t.Fatalf("unable to add htlc: %v", err)
+	}
+	err = s.circuits.Open(&PaymentCircuit{
+		PaymentHash:    htlc.PaymentHash,
+		IncomingChanID: aliceChanID,
+		OutgoingChanID: chanID,
+		OutgoingHTLCID: htlcIndex,
+		ErrorEncrypter: newMockObfuscator(),
+	})
+	if err != nil {
+		t.Fatalf("unable to open circuit: %v", err)
+	}
+
+	link := NewChannelLink(ChannelLinkConfig{
+		Peer:   &mockPeer{},
+		Switch: s,
+		PreimageCache: &mockPreimageCache{
+			preimageMap: make(map[[32]byte][]byte),
+		},
+	}, aliceChannel, testStartingHeight).(*channelLink)
+
+	// As soon as Bob settles the HTLC, the settle should reach the
+	// incoming link, without any commitment update taking place.
+	link.handleUpstreamMsg(&lnwire.UpdateFufillHTLC{
+		ID:              htlcIndex,
+		PaymentPreimage: preimage,
+	})
+
+	var packet *htlcPacket
+	select {
+	case packet = <-incomingLink.packets:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("settle wasn't pipelined to the incoming link")
+	}
+	settle, ok := packet.htlc.(*lnwire.UpdateFufillHTLC)
+	if !ok || settle.PaymentPreimage != preimage {
+		t.Fatalf("expected settle with preimage %x, got %v",
+			preimage, packet.htlc)
+	}
+	if s.circuits.pending() != 1 {
+		t.Fatalf("expected circuit to remain open")
+	}
+
+	// Should Bob retransmit the settle, it mustn't be forwarded again.
+	link.pipelineSettle(htlcIndex, preimage)
+	select {
+	case <-incomingLink.packets:
+		t.Fatalf("settle forwarded twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// We now go down before the settle is locked in. Upon restart, the
+	// circuit should be restored, so the settle replayed from its
+	// forwarding package once locked in still reaches the incoming link,
+	// and closes the circuit.
+	if err := s.Stop(); err != nil {
+		t.Fatalf("unable to stop switch: %v", err)
+	}
+	restarted := New(Config{DB: db})
+	if err := restarted.Start(); err != nil {
+		t.Fatalf("unable to restart switch: %v", err)
+	}
+	defer restarted.Stop()
+
+	incomingLink = newMockChannelLink(
+		restarted, chanID1, aliceChanID, &mockPeer{}, true,
+	)
+	if err := restarted.AddLink(incomingLink); err != nil {
+		t.Fatalf("unable to add incoming link: %v", err)
+	}
+	if restarted.circuits.pending() != 1 {
+		t.Fatalf("expected circuit to be restored")
+	}
+
+	err = restarted.forward(&htlcPacket{
+		outgoingChanID: chanID,
+		outgoingHTLCID: htlcIndex,
+		htlc: &lnwire.UpdateFufillHTLC{
+			PaymentPreimage: preimage,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to forward locked in settle: %v", err)
+	}
+	select {
+	case <-incomingLink.packets:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("replayed settle wasn't forwarded")
+	}
+	if restarted.circuits.pending() != 0 {
+		t.Fatalf("expected circuit to be closed")
+	}
+}
+
+// TestChannelLinkExpiryAudit tests that the link hands its channel off for
+// on-chain resolution once an outgoing HTLC has expired, dust or not, without
+// cancelling back its incoming counterpart while it's still live off-chain,
+// and that it only does so once, and not at all if the auditor is disabled.
+func TestChannelLinkExpiryAudit(t *testing.T) {
+	t.Parallel()
+
+	for _, amt := range []btcutil.Amount{10000, 100} {
+		testChannelLinkExpiryAudit(t, amt)
+	}
+}
+
+func testChannelLinkExpiryAudit(t *testing.T, amt btcutil.Amount) {
+	const chanAmt = btcutil.SatoshiPerBitcoin
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, bobChannel, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, chanAmt, chanAmt, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	// Alice will offer Bob an HTLC which expires ten blocks from now, and
+	// lock it into both commitments.
+	const expiry = testStartingHeight + 10
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256([]byte{1}),
+		Amount:      lnwire.NewMSatFromSatoshis(amt),
+		Expiry:      expiry,
+	}
+	htlcIndex, err := aliceChannel.AddHTLC(htlc)
+	if err != nil {
+		t.Fatalf("unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("unable to receive htlc: %v", err)
+	}
+	transition := func(sender, receiver *lnwallet.LightningChannel) {
+		sig, htlcSigs, err := sender.SignNextCommitment()
+		if err != nil {
+			t.Fatalf("unable to sign commitment: %v", err)
+		}
+		err = receiver.ReceiveNewCommitment(sig, htlcSigs)
+		if err != nil {
+			t.Fatalf("unable to receive commitment: %v", err)
+		}
+		revocation, _, err := receiver.RevokeCurrentCommitment()
+		if err != nil {
+			t.Fatalf("unable to revoke commitment: %v", err)
+		}
+		_, _, err = sender.ReceiveRevocation(revocation)
+		if err != nil {
+			t.Fatalf("unable to receive revocation: %v", err)
+		}
+	}
+	transition(aliceChannel, bobChannel)
+	transition(bobChannel, aliceChannel)
+
+	// The HTLC is the outgoing leg of a circuit, whose incoming link
+	// mustn't have the HTLC cancelled back by the auditor.
+	s := New(Config{})
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+	defer s.Stop()
+
+	incomingLink := newMockChannelLink(
+		s, chanID1, aliceChanID, &mockPeer{}, true,
+	)
+	if err := s.AddLink(incomingLink); err != nil {
+		t.Fatalf("unable to add incoming link: %v", err)
+	}
+	err = s.openCircuit(&PaymentCircuit{
+		PaymentHash:    htlc.PaymentHash,
+		IncomingChanID: aliceChanID,
+		OutgoingChanID: chanID,
+		OutgoingHTLCID: htlcIndex,
+		ErrorEncrypter: newMockObfuscator(),
+	})
+	if err != nil {
+		t.Fatalf("unable to open circuit: %v", err)
+	}
+
+	newLink := func(grace uint32) (*channelLink, chan struct{}) {
+		forceClosed := make(chan struct{}, 2)
+		link := NewChannelLink(ChannelLinkConfig{
+			Peer:             &mockPeer{},
+			Switch:           s,
+			ExpiryGraceDelta: grace,
+			ForceCloseChan: func() error {
+				forceClosed <- struct{}{}
+				return nil
+			},
+		}, aliceChannel, testStartingHeight).(*channelLink)
+
+		return link, forceClosed
+	}
+	assertForceClosed := func(forceClosed chan struct{}, expected bool) {
+		select {
+		case <-forceClosed:
+			if !expected {
+				t.Fatalf("channel with htlc of %v unexpectedly "+
+					"force closed", amt)
+			}
+		case <-time.After(100 * time.Millisecond):
+			if expected {
+				t.Fatalf("channel with htlc of %v wasn't force "+
+					"closed", amt)
+			}
+		}
+	}
+
+	// With the auditor disabled, the channel shouldn't be force closed,
+	// even though the HTLC has expired.
+	link, forceClosed := newLink(0)
+	link.auditExpiries(expiry)
+	assertForceClosed(forceClosed, false)
+
+	// Otherwise, the channel shouldn't be force closed prior to the expiry
+	// of the HTLC, but should be once it has expired.
+	link, forceClosed = newLink(2)
+	link.auditExpiries(expiry - 1)
+	assertForceClosed(forceClosed, false)
+	link.auditExpiries(expiry)
+	assertForceClosed(forceClosed, true)
+
+	// Subsequent blocks shouldn't cause a second force close.
+	link.auditExpiries(expiry + 1)
+	assertForceClosed(forceClosed, false)
+
+	// The incoming HTLC is only cancelled back by the contract court once
+	// the channel is closed.
+	select {
+	case pkt := <-incomingLink.packets:
+		t.Fatalf("htlc of %v unexpectedly resolved: %v", amt,
+			pkt.htlc)
+	default:
+	}
+	if s.circuits.pending() != 1 {
+		t.Fatalf("expected circuit of htlc of %v to remain open", amt)
+	}
+}
+
+// TestChannelLinkBatchConfig tests that the link falls back to the default
+// batch size and commit tickers if they're unset, and clamps them to the range
+// it accepts otherwise.
+func TestChannelLinkBatchConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  ChannelLinkConfig
+
+		batchSize              uint32
+		batchTicker            time.Duration
+		pendingCommitTicker    time.Duration
+		maxPendingCommitTicker time.Duration
+	}{
+		{
+			name:                   "defaults",
+			batchSize:              DefaultBatchSize,
+			batchTicker:            DefaultBatchTicker,
+			pendingCommitTicker:    DefaultPendingCommitTicker,
+			maxPendingCommitTicker: DefaultMaxPendingCommitTicker,
+		},
+		{
+			name: "custom",
+			cfg: ChannelLinkConfig{
+				BatchSize:              1,
+				BatchTicker:            time.Second,
+				PendingCommitTicker:    20 * time.Millisecond,
+				MaxPendingCommitTicker: time.Second,
+			},
+			batchSize:              1,
+			batchTicker:            time.Second,
+			pendingCommitTicker:    20 * time.Millisecond,
+			maxPendingCommitTicker: time.Second,
+		},
+		{
+			name: "clamped",
+			cfg: ChannelLinkConfig{
+				BatchSize:              maxBatchSize + 1,
+				BatchTicker:            time.Nanosecond,
+				PendingCommitTicker:    time.Minute,
+				MaxPendingCommitTicker: time.Second,
+			},
+			batchSize:              maxBatchSize,
+			batchTicker:            minLinkTicker,
+			pendingCommitTicker:    time.Minute,
+			maxPendingCommitTicker: time.Minute,
+		},
+		{
+			name: "below minimum",
+			cfg: ChannelLinkConfig{
+				PendingCommitTicker:    time.Millisecond,
+				MaxPendingCommitTicker: time.Millisecond,
+			},
+			batchSize:              DefaultBatchSize,
+			batchTicker:            DefaultBatchTicker,
+			pendingCommitTicker:    minLinkTicker,
+			maxPendingCommitTicker: minLinkTicker,
+		},
+	}
+
+	for _, test := range tests {
+		link := NewChannelLink(
+			test.cfg, nil, testStartingHeight,
+		).(*channelLink)
+
+		if link.cfg.BatchSize != test.batchSize {
+			t.Fatalf("%v: expected batch size %v, got %v",
+				test.name, test.batchSize, link.cfg.BatchSize)
+		}
+		if link.cfg.BatchTicker != test.batchTicker {
+			t.Fatalf("%v: expected batch ticker %v, got %v",
+				test.name, test.batchTicker,
+				link.cfg.BatchTicker)
+		}
+		if link.cfg.PendingCommitTicker != test.pendingCommitTicker {
+			t.Fatalf("%v: expected pending commit ticker %v, "+
+				"got %v", test.name, test.pendingCommitTicker,
+				link.cfg.PendingCommitTicker)
+		}
+		if link.cfg.MaxPendingCommitTicker !=
+			test.maxPendingCommitTicker {
+
+			t.Fatalf("%v: expected max pending commit ticker %v, "+
+				"got %v", test.name, test.maxPendingCommitTicker,
+				link.cfg.MaxPendingCommitTicker)
+		}
+	}
+}
+
+// TestChannelLinkStfu tests that a link only accepts an Stfu message if both
+// parties have negotiated quiescence, responds to it with its own once the
+// channel is clean, and rejects any updates the remote party sends after its
+// Stfu message. It also tests that a link requesting quiescence only accepts
+// a response once its own request has been sent, notifying the requester once
+// the channel is quiescent, and that the link times out once it has been
+// quiescing for too long.
+func TestChannelLinkStfu(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, _, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, chanAmt, chanAmt, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	// Without negotiating quiescence, the remote party may not request
+	// it.
+	peer := &mockPeer{}
+	link := NewChannelLink(ChannelLinkConfig{
+		Peer: peer,
+	}, aliceChannel, testStartingHeight).(*channelLink)
+
+	request := lnwire.NewStfu(link.ChanID(), true)
+	if err := link.handleStfu(request); err == nil {
+		t.Fatalf("stfu without negotiating quiescence should be " +
+			"rejected")
+	}
+	if link.isQuiescing() {
+		t.Fatalf("link shouldn't be quiescing")
+	}
+
+	// Nor may we request it.
+	select {
+	case err := <-link.InitStfu():
+		if err != ErrQuiescenceUnsupported {
+			t.Fatalf("expected ErrQuiescenceUnsupported, got %v",
+				err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("stfu request not answered")
+	}
+	if link.isQuiescing() {
+		t.Fatalf("link shouldn't be quiescing")
+	}
+
+	link = NewChannelLink(ChannelLinkConfig{
+		Peer:                peer,
+		QuiescenceSupported: true,
+		QuiescenceTimeout:   time.Millisecond * 100,
+	}, aliceChannel, testStartingHeight).(*channelLink)
+
+	// Until the remote party requests quiescence, the link doesn't time
+	// out.
+	if link.quiescenceTimeout() != nil {
+		t.Fatalf("link shouldn't time out before quiescing")
+	}
+
+	// As we haven't requested quiescence, a response is rejected.
+	response := lnwire.NewStfu(link.ChanID(), false)
+	if err := link.handleStfu(response); err == nil {
+		t.Fatalf("unrequested stfu response should be rejected")
+	}
+
+	// Until the remote party requests quiescence, it may still send us
+	// updates.
+	if err := link.checkQuiescentUpdate(&lnwire.UpdateFee{}); err != nil {
+		t.Fatalf("update before stfu rejected: %v", err)
+	}
+
+	// Once it does, the link should no longer be eligible to forward
+	// HTLCs, and respond with its own Stfu message as the channel is
+	// clean.
+	if err := link.handleStfu(request); err != nil {
+		t.Fatalf("unable to handle stfu: %v", err)
+	}
+	if link.EligibleToForward() {
+		t.Fatalf("quiescing link shouldn't be eligible to forward")
+	}
+	link.sendStfuIfReady()
+
+	stfu, ok := peer.popSentMsg().(*lnwire.Stfu)
+	if !ok {
+		t.Fatalf("expected stfu to be sent")
+	}
+	if stfu.Initiator || stfu.ChanID != link.ChanID() {
+		t.Fatalf("unexpected stfu: %v", spew.Sdump(stfu))
+	}
+
+	// Any further updates or Stfu messages should be rejected.
+	if err := link.checkQuiescentUpdate(&lnwire.UpdateFee{}); err == nil {
+		t.Fatalf("update after stfu should be rejected")
+	}
+	if err := link.handleStfu(request); err == nil {
+		t.Fatalf("duplicate stfu should be rejected")
+	}
+
+	// Finally, the link should time out once it has been quiescing for
+	// longer than its timeout.
+	select {
+	case <-link.quiescenceTimeout():
+	case <-time.After(time.Second * 5):
+		t.Fatalf("quiescence didn't time out")
+	}
+
+	// Next, we'll request quiescence ourselves over a new link. As the
+	// channel is clean, our request is sent straight away.
+	link = NewChannelLink(ChannelLinkConfig{
+		Peer:                peer,
+		QuiescenceSupported: true,
+	}, aliceChannel, testStartingHeight).(*channelLink)
+
+	req := &stfuReq{
+		done: make(chan error, 1),
+	}
+	link.startStfu(req)
+	if link.EligibleToForward() {
+		t.Fatalf("quiescing link shouldn't be eligible to forward")
+	}
+	link.sendStfuIfReady()
+
+	stfu, ok = peer.popSentMsg().(*lnwire.Stfu)
+	if !ok {
+		t.Fatalf("expected stfu to be sent")
+	}
+	if !stfu.Initiator || stfu.ChanID != link.ChanID() {
+		t.Fatalf("unexpected stfu: %v", spew.Sdump(stfu))
+	}
+
+	// Until the remote party responds, the channel isn't quiescent.
+	select {
+	case err := <-req.done:
+		t.Fatalf("stfu request answered before response: %v", err)
+	default:
+	}
+
+	// Once it has, our request is answered.
+	if err := link.handleStfu(response); err != nil {
+		t.Fatalf("unable to handle stfu response: %v", err)
+	}
+	select {
+	case err := <-req.done:
+		if err != nil {
+			t.Fatalf("unable to quiesce channel: %v", err)
+		}
+	default:
+		t.Fatalf("stfu request not answered")
+	}
+
+	// A request still pending once the link exits is cancelled.
+	link = NewChannelLink(ChannelLinkConfig{
+		Peer:                peer,
+		QuiescenceSupported: true,
+	}, aliceChannel, testStartingHeight).(*channelLink)
+
+	req = &stfuReq{
+		done: make(chan error, 1),
+	}
+	link.startStfu(req)
+	link.cancelStfu()
+	select {
+	case err := <-req.done:
+		if err != ErrLinkShuttingDown {
+			t.Fatalf("expected ErrLinkShuttingDown, got %v", err)
+		}
+	default:
+		t.Fatalf("stfu request not cancelled")
+	}
+}
+
+// TestChannelLinkForceCloseRecommended tests that the link reports failures
+// which leave the channel unusable until it's force closed, while other
+// failures only disconnect the peer.
+func TestChannelLinkForceCloseRecommended(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, _, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, chanAmt, chanAmt, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	reasons := make(chan error, 1)
+	link := NewChannelLink(ChannelLinkConfig{
+		Peer: &mockPeer{},
+		OnForceCloseRecommended: func(reason error) {
+			reasons <- reason
+		},
+	}, aliceChannel, testStartingHeight).(*channelLink)
+
+	assertRecommended := func(expected bool) {
+		select {
+		case reason := <-reasons:
+			if !expected {
+				t.Fatalf("unexpected force close "+
+					"recommendation: %v", reason)
+			}
+		default:
+			if expected {
+				t.Fatalf("force close wasn't recommended")
+			}
+		}
+	}
+
+	// The remote party settling an HTLC we never offered, or sending a
+	// revocation which doesn't match its commitment, leaves the channel
+	// unusable.
+	link.handleUpstreamMsg(&lnwire.UpdateFufillHTLC{
+		ChanID: link.ChanID(),
+		ID:     100,
+	})
+	assertRecommended(true)
+
+	link.handleUpstreamMsg(&lnwire.RevokeAndAck{
+		ChanID: link.ChanID(),
+	})
+	assertRecommended(true)
+
+	// A protocol violation which doesn't call the channel state into
+	// question should only disconnect the peer.
+	link.stfuReceived = true
+	link.handleUpstreamMsg(&lnwire.UpdateAddHTLC{
+		ChanID: link.ChanID(),
+	})
+	assertRecommended(false)
+}
+
+// TestChannelLinkAcceptablePaymentAmount ensures that the exit hop only
+// accepts payments to an invoice which don't fall short of its value, and
+// overpay it by no more than the configured percentage.
+func TestChannelLinkAcceptablePaymentAmount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		overpayPct    uint32
+		value         lnwire.MilliSatoshi
+		amt           lnwire.MilliSatoshi
+		expectedValid bool
+	}{
+		{
+			name:          "exact amount",
+			value:         10000,
+			amt:           10000,
+			expectedValid: true,
+		},
+		{
+			name:  "underpayment",
+			value: 10000,
+			amt:   9999,
+		},
+		{
+			name:  "overpayment not accepted",
+			value: 10000,
+			amt:   10001,
+		},
+		{
+			name:          "overpayment within bound",
+			overpayPct:    10,
+			value:         10000,
+			amt:           11000,
+			expectedValid: true,
+		},
+		{
+			name:       "overpayment exceeding bound",
+			overpayPct: 10,
+			value:      10000,
+			amt:        11001,
+		},
+		{
+			name:          "bound rounded down",
+			overpayPct:    10,
+			value:         10009,
+			amt:           11009,
+			expectedValid: true,
+		},
+		{
+			name:       "bound rounded down exceeded",
+			overpayPct: 10,
+			value:      10009,
+			amt:        11010,
+		},
+		{
+			name:          "zero-value invoice",
+			value:         0,
+			amt:           123456,
+			expectedValid: true,
+		},
+	}
+
+	for _, test := range tests {
+		link := &channelLink{
+			cfg: ChannelLinkConfig{
+				MaxOverpaymentPct: test.overpayPct,
+			},
+		}
+
+		valid := link.acceptablePaymentAmount(test.value, test.amt)
+		if valid != test.expectedValid {
+			t.Fatalf("%v: expected valid=%v, got %v", test.name,
+				test.expectedValid, valid)
+		}
+	}
+}
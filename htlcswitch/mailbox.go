@@ -1,11 +1,39 @@
 package htlcswitch
 
 import (
+	"errors"
 	"sync"
 
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
+const (
+	// DefaultMailBoxMaxMessages is the default maximum number of wire
+	// messages queued within the mailbox of a link. It comfortably holds
+	// a full commitment update adding the maximum number of HTLCs.
+	DefaultMailBoxMaxMessages = 1000
+
+	// DefaultMailBoxMaxPackets is the default maximum number of htlc
+	// packets queued within the mailbox of a link before new HTLCs are
+	// rejected.
+	DefaultMailBoxMaxPackets = 1000
+)
+
+var (
+	// ErrMailBoxFull is returned when an htlc add packet can't be queued
+	// within a mailbox, as its packet queue has reached its capacity.
+	ErrMailBoxFull = errors.New("mailbox is full")
+
+	// ErrMailBoxShuttingDown is returned when a message can't be queued
+	// within a mailbox, as the mailbox is shutting down.
+	ErrMailBoxShuttingDown = errors.New("mailbox shutting down")
+
+	// ErrMailBoxReset is returned when a message can't be queued within a
+	// mailbox, as its message queue has been reset while waiting for
+	// space.
+	ErrMailBoxReset = errors.New("mailbox reset")
+)
+
 // mailBox is an interface which represents a concurrent-safe, in-order
 // delivery queue for messages from the network and also from the main switch.
 // This struct servers as a buffer between incoming messages, and messages to
@@ -13,9 +41,13 @@ import (
 // should be implemented in a non-blocking manner.
 type mailBox interface {
 	// AddMessage appends a new message to the end of the message queue.
+	// If the queue is full, then this method blocks until space is
+	// available.
 	AddMessage(msg lnwire.Message) error
 
-	// AddPacket appends a new message to the end of the packet queue.
+	// AddPacket appends a new message to the end of the packet queue. If
+	// the queue is full, then htlc add packets are rejected with
+	// ErrMailBoxFull.
 	AddPacket(pkt *htlcPacket) error
 
 	// MessageOutBox returns a channel that any new messages ready for
@@ -34,12 +66,59 @@ type mailBox interface {
 	Stop() error
 }
 
+// MailBoxStats describes the current and peak depths of the queues of a
+// mailbox, along with the number of times backpressure has been applied.
+type MailBoxStats struct {
+	// Messages is the number of wire messages currently queued.
+	Messages int
+
+	// Packets is the number of htlc packets currently queued.
+	Packets int
+
+	// PeakMessages is the largest number of wire messages that have been
+	// queued at once.
+	PeakMessages int
+
+	// PeakPackets is the largest number of htlc packets that have been
+	// queued at once.
+	PeakPackets int
+
+	// MessageStalls is the number of times a wire message had to wait for
+	// space within the full message queue.
+	MessageStalls uint64
+
+	// RejectedAdds is the number of htlc add packets which have been
+	// rejected, as the packet queue was full.
+	RejectedAdds uint64
+}
+
 // memoryMailBox is an implementation of the mailBox struct backed by purely
 // in-memory queues.
 type memoryMailBox struct {
+	// maxMessages is the maximum number of wire messages that may be
+	// queued. Once reached, AddMessage blocks until a message has been
+	// delivered, applying backpressure to the remote peer. A value of zero
+	// indicates no limit.
+	maxMessages int
+
+	// maxPackets is the maximum number of htlc packets that may be queued
+	// before htlc add packets are rejected. Settle and fail packets are
+	// always queued, as they free up resources rather than consume them.
+	// A value of zero indicates no limit.
+	maxPackets int
+
 	wireMessages []lnwire.Message
 	wireMtx      sync.Mutex
 	wireCond     *sync.Cond
+	wireSpace    *sync.Cond
+
+	// wireReset is closed to stop the current wire courier once the
+	// message queue is reset, while wireGen is incremented with each
+	// reset, so callers waiting for space can tell their message is
+	// stale. Both are guarded by the wireMtx.
+	wireReset chan struct{}
+	wireGen   uint64
+	wireWg    sync.WaitGroup
 
 	messageOutbox chan lnwire.Message
 
@@ -49,18 +128,38 @@ type memoryMailBox struct {
 
 	pktOutbox chan *htlcPacket
 
+	// The following fields track the statistics of the mailbox. They're
+	// guarded by the mutex of the respective queue.
+	peakMessages  int
+	messageStalls uint64
+	peakPackets   int
+	rejectedAdds  uint64
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
 
-// newMemoryMailBox creates a new instance of the memoryMailBox.
+// newMemoryMailBox creates a new instance of the memoryMailBox, whose queues
+// are unbounded.
 func newMemoryMailBox() *memoryMailBox {
+	return newBoundedMailBox(0, 0)
+}
+
+// newBoundedMailBox creates a new instance of the memoryMailBox which queues
+// at most maxMessages wire messages, and rejects htlc add packets once
+// maxPackets htlc packets are queued. A limit of zero leaves the respective
+// queue unbounded.
+func newBoundedMailBox(maxMessages, maxPackets int) *memoryMailBox {
 	box := &memoryMailBox{
+		maxMessages:   maxMessages,
+		maxPackets:    maxPackets,
 		quit:          make(chan struct{}),
+		wireReset:     make(chan struct{}),
 		messageOutbox: make(chan lnwire.Message),
 		pktOutbox:     make(chan *htlcPacket),
 	}
 	box.wireCond = sync.NewCond(&box.wireMtx)
+	box.wireSpace = sync.NewCond(&box.wireMtx)
 	box.pktCond = sync.NewCond(&box.pktMtx)
 
 	return box
@@ -87,31 +186,102 @@ const (
 //
 // NOTE: This method is part of the mailBox interface.
 func (m *memoryMailBox) Start() error {
-	m.wg.Add(2)
-	go m.mailCourier(wireCourier)
-	go m.mailCourier(pktCourier)
+	m.wireWg.Add(1)
+	go m.mailCourier(wireCourier, m.wireReset)
+
+	m.wg.Add(1)
+	go m.mailCourier(pktCourier, nil)
 
 	return nil
 }
 
-// Stop signals the mailbox and its goroutines for a graceful shutdown.
+// Stop signals the mailbox and its goroutines for a graceful shutdown, then
+// waits until they've exited.
 //
 // NOTE: This method is part of the mailBox interface.
 func (m *memoryMailBox) Stop() error {
 	close(m.quit)
 
+	// We'll wake the couriers, along with any callers blocked on a full
+	// message queue. The locks are held so the wake ups can't be missed
+	// by a goroutine that's about to wait.
+	m.wireMtx.Lock()
 	m.wireCond.Signal()
+	m.wireSpace.Broadcast()
+	m.wireMtx.Unlock()
+
+	m.pktMtx.Lock()
 	m.pktCond.Signal()
+	m.pktMtx.Unlock()
+
+	m.wireWg.Wait()
+	m.wg.Wait()
 
 	return nil
 }
 
+// ResetMessages discards all queued wire messages, including any message the
+// wire courier is in the process of delivering. It's called once the mailbox
+// is attached to a new link, as the messages were meant for the link it
+// replaces, and are retransmitted by the remote peer as the channel is
+// reestablished. Callers waiting for space within the message queue are
+// released with ErrMailBoxReset. Queued packets are left untouched.
+//
+// NOTE: This method MUST only be called once the mailbox has been started.
+func (m *memoryMailBox) ResetMessages() {
+	// First, we'll stop the current wire courier, waking it up in case
+	// it's waiting for new messages, and wait for it to exit.
+	m.wireMtx.Lock()
+	close(m.wireReset)
+	m.wireCond.Broadcast()
+	m.wireMtx.Unlock()
+
+	m.wireWg.Wait()
+
+	// With the courier gone, no message is in flight, so we can safely
+	// clear the queue and start a new courier in its place.
+	m.wireMtx.Lock()
+	for i := range m.wireMessages {
+		m.wireMessages[i] = nil // Set to nil to prevent GC leak.
+	}
+	m.wireMessages = nil
+	m.wireGen++
+	m.wireReset = make(chan struct{})
+	m.wireSpace.Broadcast()
+
+	m.wireWg.Add(1)
+	go m.mailCourier(wireCourier, m.wireReset)
+	m.wireMtx.Unlock()
+}
+
+// DrainPackets removes and returns all queued htlc packets, including any
+// packet the courier was in the process of delivering. It's called once the
+// mailbox has been stopped, so the packets which are yet to be delivered can
+// be failed back or discarded.
+func (m *memoryMailBox) DrainPackets() []*htlcPacket {
+	m.pktMtx.Lock()
+	defer m.pktMtx.Unlock()
+
+	pkts := m.htlcPkts
+	m.htlcPkts = nil
+
+	return pkts
+}
+
 // mailCourier is a dedicated goroutine whose job is to reliably deliver
 // messages of a particular type. There are two types of couriers: wire
 // couriers, and mail couriers. Depending on the passed courierType, this
-// goroutine will assume one of two roles.
-func (m *memoryMailBox) mailCourier(cType courierType) {
-	defer m.wg.Done()
+// goroutine will assume one of two roles. Wire couriers additionally exit
+// once the passed reset channel is closed, as the message queue is reset.
+func (m *memoryMailBox) mailCourier(cType courierType,
+	reset <-chan struct{}) {
+
+	switch cType {
+	case wireCourier:
+		defer m.wireWg.Done()
+	case pktCourier:
+		defer m.wg.Done()
+	}
 
 	// TODO(roasbeef): refactor...
 
@@ -122,27 +292,30 @@ func (m *memoryMailBox) mailCourier(cType courierType) {
 		case wireCourier:
 			m.wireCond.L.Lock()
 			for len(m.wireMessages) == 0 {
-				m.wireCond.Wait()
-
 				select {
 				case <-m.quit:
 					m.wireCond.L.Unlock()
 					return
+				case <-reset:
+					m.wireCond.L.Unlock()
+					return
 				default:
 				}
+
+				m.wireCond.Wait()
 			}
 
 		case pktCourier:
 			m.pktCond.L.Lock()
 			for len(m.htlcPkts) == 0 {
-				m.pktCond.Wait()
-
 				select {
 				case <-m.quit:
 					m.pktCond.L.Unlock()
 					return
 				default:
 				}
+
+				m.pktCond.Wait()
 			}
 		}
 
@@ -158,6 +331,10 @@ func (m *memoryMailBox) mailCourier(cType courierType) {
 			nextMsg = m.wireMessages[0]
 			m.wireMessages[0] = nil // Set to nil to prevent GC leak.
 			m.wireMessages = m.wireMessages[1:]
+
+			// As space has been freed within the queue, we'll
+			// wake any caller waiting to add a message.
+			m.wireSpace.Signal()
 		case pktCourier:
 			nextPkt = m.htlcPkts[0]
 			m.htlcPkts[0] = nil // Set to nil to prevent GC leak.
@@ -182,12 +359,22 @@ func (m *memoryMailBox) mailCourier(cType courierType) {
 			case m.messageOutbox <- nextMsg:
 			case <-m.quit:
 				return
+			case <-reset:
+				return
 			}
 
 		case pktCourier:
 			select {
 			case m.pktOutbox <- nextPkt:
 			case <-m.quit:
+				// The packet is returned to the front of the
+				// queue, so it isn't lost once the remaining
+				// packets are drained.
+				m.pktCond.L.Lock()
+				m.htlcPkts = append(
+					[]*htlcPacket{nextPkt}, m.htlcPkts...,
+				)
+				m.pktCond.L.Unlock()
 				return
 			}
 		}
@@ -200,10 +387,39 @@ func (m *memoryMailBox) mailCourier(cType courierType) {
 // NOTE: This method is safe for concrete use and part of the mailBox
 // interface.
 func (m *memoryMailBox) AddMessage(msg lnwire.Message) error {
-	// First, we'll lock the condition, and add the message to the end of
-	// the wire message inbox.
+	// First, we'll lock the condition. If the wire message inbox is full,
+	// then we'll wait until the courier has made space, so the remote
+	// peer can't make us buffer an unbounded number of messages.
 	m.wireCond.L.Lock()
+	if m.maxMessages != 0 && len(m.wireMessages) >= m.maxMessages {
+		m.messageStalls++
+	}
+	gen := m.wireGen
+	for m.maxMessages != 0 && len(m.wireMessages) >= m.maxMessages {
+		select {
+		case <-m.quit:
+			m.wireCond.L.Unlock()
+			return ErrMailBoxShuttingDown
+		default:
+		}
+
+		m.wireSpace.Wait()
+
+		// If the queue was reset while we were waiting, then the
+		// message was meant for the link the mailbox was attached to
+		// beforehand, so we'll drop it.
+		if m.wireGen != gen {
+			m.wireCond.L.Unlock()
+			return ErrMailBoxReset
+		}
+	}
+
+	// With space available, we'll add the message to the end of the
+	// inbox.
 	m.wireMessages = append(m.wireMessages, msg)
+	if len(m.wireMessages) > m.peakMessages {
+		m.peakMessages = len(m.wireMessages)
+	}
 	m.wireCond.L.Unlock()
 
 	// With the message added, we signal to the mailCourier that there are
@@ -218,10 +434,22 @@ func (m *memoryMailBox) AddMessage(msg lnwire.Message) error {
 // NOTE: This method is safe for concrete use and part of the mailBox
 // interface.
 func (m *memoryMailBox) AddPacket(pkt *htlcPacket) error {
-	// First, we'll lock the condition, and add the packet to the end of
-	// the htlc packet inbox.
+	// First, we'll lock the condition. If the htlc packet inbox is full,
+	// then we'll reject any new htlcs, so the switch can fail them back
+	// straight away, rather than have them wait behind a slow link.
 	m.pktCond.L.Lock()
+	_, isAdd := pkt.htlc.(*lnwire.UpdateAddHTLC)
+	if isAdd && m.maxPackets != 0 && len(m.htlcPkts) >= m.maxPackets {
+		m.rejectedAdds++
+		m.pktCond.L.Unlock()
+		return ErrMailBoxFull
+	}
+
+	// Otherwise, we'll add the packet to the end of the inbox.
 	m.htlcPkts = append(m.htlcPkts, pkt)
+	if len(m.htlcPkts) > m.peakPackets {
+		m.peakPackets = len(m.htlcPkts)
+	}
 	m.pktCond.L.Unlock()
 
 	// With the packet added, we signal to the mailCourier that there are
@@ -246,3 +474,22 @@ func (m *memoryMailBox) MessageOutBox() chan lnwire.Message {
 func (m *memoryMailBox) PacketOutBox() chan *htlcPacket {
 	return m.pktOutbox
 }
+
+// Stats returns the current statistics of the mailbox.
+func (m *memoryMailBox) Stats() MailBoxStats {
+	var stats MailBoxStats
+
+	m.wireMtx.Lock()
+	stats.Messages = len(m.wireMessages)
+	stats.PeakMessages = m.peakMessages
+	stats.MessageStalls = m.messageStalls
+	m.wireMtx.Unlock()
+
+	m.pktMtx.Lock()
+	stats.Packets = len(m.htlcPkts)
+	stats.PeakPackets = m.peakPackets
+	stats.RejectedAdds = m.rejectedAdds
+	m.pktMtx.Unlock()
+
+	return stats
+}
@@ -97,3 +97,172 @@ func TestMailBoxCouriers(t *testing.T) {
 			spew.Sdump(sentMessages), spew.Sdump(recvdMessages))
 	}
 }
+
+// TestMailBoxBounded tests that a bounded mailbox rejects htlc adds once its
+// packet queue is full, while still accepting settles and fails, and that it
+// holds back wire messages once its message queue is full until space is made.
+func TestMailBoxBounded(t *testing.T) {
+	t.Parallel()
+
+	mailBox := newBoundedMailBox(2, 2)
+
+	// The first two adds should be queued, while the third should be
+	// rejected.
+	for i := 0; i < 2; i++ {
+		err := mailBox.AddPacket(&htlcPacket{
+			htlc: &lnwire.UpdateAddHTLC{},
+		})
+		if err != nil {
+			t.Fatalf("unable to add packet: %v", err)
+		}
+	}
+	err := mailBox.AddPacket(&htlcPacket{htlc: &lnwire.UpdateAddHTLC{}})
+	if err != ErrMailBoxFull {
+		t.Fatalf("expected ErrMailBoxFull, got %v", err)
+	}
+
+	// A settle should still be queued, despite the queue being full.
+	err = mailBox.AddPacket(&htlcPacket{htlc: &lnwire.UpdateFufillHTLC{}})
+	if err != nil {
+		t.Fatalf("unable to add settle packet: %v", err)
+	}
+
+	// Next, we'll fill the message queue, then attempt to add another
+	// message, which should block as the mailbox hasn't been started.
+	for i := 0; i < 2; i++ {
+		err := mailBox.AddMessage(&lnwire.UpdateAddHTLC{ID: uint64(i)})
+		if err != nil {
+			t.Fatalf("unable to add message: %v", err)
+		}
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- mailBox.AddMessage(&lnwire.UpdateAddHTLC{ID: 2})
+	}()
+	select {
+	case err := <-errChan:
+		t.Fatalf("message shouldn't have been added: %v", err)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	// Once the mailbox is started, the courier makes space for the
+	// blocked message, and all messages are delivered in order.
+	mailBox.Start()
+	defer mailBox.Stop()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case msg := <-mailBox.MessageOutBox():
+			id := msg.(*lnwire.UpdateAddHTLC).ID
+			if id != uint64(i) {
+				t.Fatalf("expected message %v, got %v", i, id)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("message %v wasn't delivered", i)
+		}
+	}
+	if err := <-errChan; err != nil {
+		t.Fatalf("unable to add message: %v", err)
+	}
+
+	stats := mailBox.Stats()
+	if stats.PeakMessages != 2 || stats.MessageStalls != 1 ||
+		stats.PeakPackets != 3 || stats.RejectedAdds != 1 {
+
+		t.Fatalf("unexpected stats: %v", spew.Sdump(stats))
+	}
+}
+
+// TestMailBoxStopUnblocksMessages tests that a caller blocked on a full
+// message queue is released once the mailbox is stopped.
+func TestMailBoxStopUnblocksMessages(t *testing.T) {
+	t.Parallel()
+
+	mailBox := newBoundedMailBox(1, 0)
+	if err := mailBox.AddMessage(&lnwire.UpdateAddHTLC{}); err != nil {
+		t.Fatalf("unable to add message: %v", err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- mailBox.AddMessage(&lnwire.UpdateAddHTLC{})
+	}()
+	time.Sleep(time.Millisecond * 100)
+
+	mailBox.Stop()
+
+	select {
+	case err := <-errChan:
+		if err != ErrMailBoxShuttingDown {
+			t.Fatalf("expected ErrMailBoxShuttingDown, got %v",
+				err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("blocked message wasn't released")
+	}
+}
+
+// TestMailBoxResetMessages tests that resetting a mailbox discards its queued
+// wire messages, including the one being delivered, and releases callers
+// waiting for space, while its packets are retained.
+func TestMailBoxResetMessages(t *testing.T) {
+	t.Parallel()
+
+	mailBox := newBoundedMailBox(1, 0)
+	mailBox.Start()
+	defer mailBox.Stop()
+
+	// The courier will pick up the first message, while the second fills
+	// the queue, so the third is blocked.
+	for i := 0; i < 2; i++ {
+		err := mailBox.AddMessage(&lnwire.UpdateAddHTLC{ID: uint64(i)})
+		if err != nil {
+			t.Fatalf("unable to add message: %v", err)
+		}
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- mailBox.AddMessage(&lnwire.UpdateAddHTLC{ID: 2})
+	}()
+	time.Sleep(time.Millisecond * 100)
+
+	pkt := &htlcPacket{htlc: &lnwire.UpdateFufillHTLC{}}
+	if err := mailBox.AddPacket(pkt); err != nil {
+		t.Fatalf("unable to add packet: %v", err)
+	}
+
+	mailBox.ResetMessages()
+
+	select {
+	case err := <-errChan:
+		if err != ErrMailBoxReset {
+			t.Fatalf("expected ErrMailBoxReset, got %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("blocked message wasn't released")
+	}
+
+	// Only messages added after the reset should be delivered.
+	err := mailBox.AddMessage(&lnwire.UpdateAddHTLC{ID: 3})
+	if err != nil {
+		t.Fatalf("unable to add message: %v", err)
+	}
+	select {
+	case msg := <-mailBox.MessageOutBox():
+		id := msg.(*lnwire.UpdateAddHTLC).ID
+		if id != 3 {
+			t.Fatalf("expected message 3, got %v", id)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("message wasn't delivered")
+	}
+
+	select {
+	case p := <-mailBox.PacketOutBox():
+		if p != pkt {
+			t.Fatalf("unexpected packet delivered")
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("packet wasn't delivered")
+	}
+}
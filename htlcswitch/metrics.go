@@ -0,0 +1,151 @@
+package htlcswitch
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// settleLatencyBuckets are the upper bounds of the buckets of the histogram
+// of the time taken for outgoing HTLCs to be settled by the remote party.
+var settleLatencyBuckets = []time.Duration{
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+}
+
+// switchMetrics accumulates the counters describing the HTLCs handled by the
+// switch and its links since the switch was created. It's safe for
+// concurrent use.
+type switchMetrics struct {
+	// forwarded is the number of HTLCs the switch has handed to an
+	// outgoing link. It MUST be accessed atomically.
+	forwarded uint64
+
+	mtx sync.Mutex
+
+	// failures is the number of HTLCs we've failed ourselves, keyed by the
+	// code of the failure returned to the sender.
+	failures map[lnwire.FailCode]uint64
+
+	// settleCounts holds the number of outgoing HTLCs settled within each
+	// of the settleLatencyBuckets, with the final count holding those
+	// that took longer than the largest bucket.
+	settleCounts []uint64
+
+	// settleSum is the total time taken for outgoing HTLCs to be settled.
+	settleSum time.Duration
+}
+
+// newSwitchMetrics creates a new switchMetrics instance with all counters
+// set to zero.
+func newSwitchMetrics() *switchMetrics {
+	return &switchMetrics{
+		failures:     make(map[lnwire.FailCode]uint64),
+		settleCounts: make([]uint64, len(settleLatencyBuckets)+1),
+	}
+}
+
+// forwardedHTLC accounts for an HTLC handed to an outgoing link.
+func (m *switchMetrics) forwardedHTLC() {
+	atomic.AddUint64(&m.forwarded, 1)
+}
+
+// failedHTLC accounts for an HTLC we've failed with the passed code.
+func (m *switchMetrics) failedHTLC(code lnwire.FailCode) {
+	m.mtx.Lock()
+	m.failures[code]++
+	m.mtx.Unlock()
+}
+
+// settledHTLC accounts for an outgoing HTLC that the remote party settled
+// after the passed latency.
+func (m *switchMetrics) settledHTLC(latency time.Duration) {
+	bucket := len(settleLatencyBuckets)
+	for i, bound := range settleLatencyBuckets {
+		if latency <= bound {
+			bucket = i
+			break
+		}
+	}
+
+	m.mtx.Lock()
+	m.settleCounts[bucket]++
+	m.settleSum += latency
+	m.mtx.Unlock()
+}
+
+// SwitchMetrics is a snapshot of the counters describing the HTLCs handled by
+// the switch and its links since the switch was created.
+type SwitchMetrics struct {
+	// ForwardedHTLCs is the number of HTLCs the switch has handed to an
+	// outgoing link.
+	ForwardedHTLCs uint64
+
+	// FailuresByCode is the number of HTLCs we've failed ourselves, keyed
+	// by the code of the failure returned to the sender. Failures
+	// originating further downstream are encrypted, and as such aren't
+	// included.
+	FailuresByCode map[lnwire.FailCode]uint64
+
+	// SettleLatencyBuckets are the upper bounds of the buckets of the
+	// settle latency histogram.
+	SettleLatencyBuckets []time.Duration
+
+	// SettleLatencyCounts holds the cumulative number of outgoing HTLCs
+	// settled within each of the SettleLatencyBuckets.
+	SettleLatencyCounts []uint64
+
+	// SettledHTLCs is the number of outgoing HTLCs settled by the remote
+	// party.
+	SettledHTLCs uint64
+
+	// SettleLatencySum is the total time taken for outgoing HTLCs to be
+	// settled by the remote party.
+	SettleLatencySum time.Duration
+}
+
+// snapshot returns a consistent snapshot of the metrics.
+func (m *switchMetrics) snapshot() *SwitchMetrics {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	snapshot := &SwitchMetrics{
+		ForwardedHTLCs: atomic.LoadUint64(&m.forwarded),
+		FailuresByCode: make(map[lnwire.FailCode]uint64),
+		SettleLatencyBuckets: append(
+			[]time.Duration(nil), settleLatencyBuckets...,
+		),
+		SettleLatencyCounts: make(
+			[]uint64, len(settleLatencyBuckets),
+		),
+		SettleLatencySum: m.settleSum,
+	}
+	for code, count := range m.failures {
+		snapshot.FailuresByCode[code] = count
+	}
+
+	for i, count := range m.settleCounts {
+		snapshot.SettledHTLCs += count
+		if i < len(snapshot.SettleLatencyCounts) {
+			snapshot.SettleLatencyCounts[i] = snapshot.SettledHTLCs
+		}
+	}
+
+	return snapshot
+}
+
+// Metrics returns a snapshot of the counters describing the HTLCs handled by
+// the switch and its links since the switch was created.
+func (s *Switch) Metrics() *SwitchMetrics {
+	return s.metrics.snapshot()
+}
@@ -0,0 +1,71 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestSwitchMetrics tests that the metrics snapshot reports the recorded
+// forwards and failures, and that settles are accounted for within the
+// cumulative buckets of the latency histogram.
+func TestSwitchMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := newSwitchMetrics()
+	m.forwardedHTLC()
+	m.forwardedHTLC()
+	m.failedHTLC(lnwire.CodeTemporaryChannelFailure)
+	m.failedHTLC(lnwire.CodeTemporaryChannelFailure)
+	m.failedHTLC(lnwire.CodeUnknownNextPeer)
+
+	// We'll settle one HTLC within the first bucket, one on the bound of
+	// the second, and one beyond the largest bucket.
+	m.settledHTLC(50 * time.Millisecond)
+	m.settledHTLC(250 * time.Millisecond)
+	m.settledHTLC(time.Hour)
+
+	snapshot := m.snapshot()
+	if snapshot.ForwardedHTLCs != 2 {
+		t.Fatalf("expected 2 forwarded htlcs, got %v",
+			snapshot.ForwardedHTLCs)
+	}
+
+	failures := snapshot.FailuresByCode
+	if len(failures) != 2 ||
+		failures[lnwire.CodeTemporaryChannelFailure] != 2 ||
+		failures[lnwire.CodeUnknownNextPeer] != 1 {
+
+		t.Fatalf("unexpected failures: %v", failures)
+	}
+
+	if snapshot.SettledHTLCs != 3 {
+		t.Fatalf("expected 3 settled htlcs, got %v",
+			snapshot.SettledHTLCs)
+	}
+	expectedSum := 300*time.Millisecond + time.Hour
+	if snapshot.SettleLatencySum != expectedSum {
+		t.Fatalf("expected latency sum %v, got %v", expectedSum,
+			snapshot.SettleLatencySum)
+	}
+
+	if len(snapshot.SettleLatencyCounts) !=
+		len(snapshot.SettleLatencyBuckets) {
+
+		t.Fatalf("expected %v bucket counts, got %v",
+			len(snapshot.SettleLatencyBuckets),
+			len(snapshot.SettleLatencyCounts))
+	}
+	for i, count := range snapshot.SettleLatencyCounts {
+		expected := uint64(2)
+		if i == 0 {
+			expected = 1
+		}
+		if count != expected {
+			t.Fatalf("expected %v htlcs within bucket %v, got %v",
+				expected, snapshot.SettleLatencyBuckets[i],
+				count)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"io"
 	"sync/atomic"
@@ -228,7 +229,42 @@ func (f *ForwardingInfo) encode(w io.Writer) error {
 		return err
 	}
 
-	return nil
+	var keysend [33]byte
+	if f.KeysendPreimage != nil {
+		keysend[0] = 1
+		copy(keysend[1:], f.KeysendPreimage[:])
+	}
+	if _, err := w.Write(keysend[:]); err != nil {
+		return err
+	}
+
+	hasAmp := f.Amp != nil
+	if err := binary.Write(w, binary.BigEndian, hasAmp); err != nil {
+		return err
+	}
+	if hasAmp {
+		if err := binary.Write(w, binary.BigEndian, f.Amp); err != nil {
+			return err
+		}
+	}
+
+	var stateless [1 + statelessRecordLen]byte
+	if f.Stateless != nil {
+		stateless[0] = 1
+		record := f.Stateless.encode()
+		copy(stateless[1:], record[:])
+	}
+	if _, err := w.Write(stateless[:]); err != nil {
+		return err
+	}
+
+	var paymentAddr [33]byte
+	if f.PaymentAddr != nil {
+		paymentAddr[0] = 1
+		copy(paymentAddr[1:], f.PaymentAddr[:])
+	}
+	_, err := w.Write(paymentAddr[:])
+	return err
 }
 
 var _ HopIterator = (*mockHopIterator)(nil)
@@ -256,6 +292,22 @@ func (o *mockObfuscator) IntermediateEncrypt(reason lnwire.OpaqueReason) lnwire.
 
 }
 
+func (o *mockObfuscator) Type() EncrypterType {
+	return EncrypterTypeMock
+}
+
+func (o *mockObfuscator) Encode(w io.Writer) error {
+	return nil
+}
+
+func (o *mockObfuscator) Decode(r io.Reader) error {
+	return nil
+}
+
+func (o *mockObfuscator) Reextract(extract ErrorEncrypterExtracter) error {
+	return nil
+}
+
 // mockDeobfuscator mock implementation of the failure deobfuscator which
 // only decodes the failure do not makes any onion obfuscation.
 type mockDeobfuscator struct{}
@@ -279,9 +331,22 @@ func (o *mockDeobfuscator) DecryptError(reason lnwire.OpaqueReason) (*Forwarding
 
 var _ ErrorDecrypter = (*mockDeobfuscator)(nil)
 
-// mockIteratorDecoder test version of hop iterator decoder which decodes the
+// mockIteratorDecoder test version of onion processor which decodes the
 // encoded array of hops.
-type mockIteratorDecoder struct{}
+type mockIteratorDecoder struct {
+	// obfuscator is the error encrypter returned for each onion packet.
+	obfuscator ErrorEncrypter
+
+	// extractFailCode, if set, is returned instead of the obfuscator when
+	// an error encrypter is extracted.
+	extractFailCode lnwire.FailCode
+}
+
+var _ OnionProcessor = (*mockIteratorDecoder)(nil)
+
+func (p *mockIteratorDecoder) SupportedVersions() []byte {
+	return []byte{0}
+}
 
 func (p *mockIteratorDecoder) DecodeHopIterator(r io.Reader, meta []byte) (
 	HopIterator, lnwire.FailCode) {
@@ -306,6 +371,36 @@ func (p *mockIteratorDecoder) DecodeHopIterator(r io.Reader, meta []byte) (
 	return newMockHopIterator(hops...), lnwire.CodeNone
 }
 
+func (p *mockIteratorDecoder) DecodeHopIterators(batchID []byte,
+	height uint32, reqs []DecodeHopIteratorRequest) (
+	[]DecodeHopIteratorResponse, error) {
+
+	resps := make([]DecodeHopIteratorResponse, len(reqs))
+	for i, req := range reqs {
+		resps[i].HopIterator, resps[i].FailCode = p.DecodeHopIterator(
+			req.OnionReader, req.RHash,
+		)
+	}
+
+	return resps, nil
+}
+
+func (p *mockIteratorDecoder) ExtractErrorEncrypter(
+	r io.Reader) (ErrorEncrypter, lnwire.FailCode) {
+
+	if p.extractFailCode != lnwire.CodeNone {
+		return nil, p.extractFailCode
+	}
+
+	return p.obfuscator, lnwire.CodeNone
+}
+
+func (p *mockIteratorDecoder) ReextractErrorEncrypter(
+	*btcec.PublicKey) (ErrorEncrypter, lnwire.FailCode) {
+
+	return p.obfuscator, lnwire.CodeNone
+}
+
 func (f *ForwardingInfo) decode(r io.Reader) error {
 	var net [1]byte
 	if _, err := r.Read(net[:]); err != nil {
@@ -325,6 +420,46 @@ func (f *ForwardingInfo) decode(r io.Reader) error {
 		return err
 	}
 
+	var keysend [33]byte
+	if _, err := io.ReadFull(r, keysend[:]); err != nil {
+		return err
+	}
+	if keysend[0] == 1 {
+		var preimage [32]byte
+		copy(preimage[:], keysend[1:])
+		f.KeysendPreimage = &preimage
+	}
+
+	var hasAmp bool
+	if err := binary.Read(r, binary.BigEndian, &hasAmp); err != nil {
+		return err
+	}
+	if hasAmp {
+		f.Amp = &AmpRecord{}
+		if err := binary.Read(r, binary.BigEndian, f.Amp); err != nil {
+			return err
+		}
+	}
+
+	var stateless [1 + statelessRecordLen]byte
+	if _, err := io.ReadFull(r, stateless[:]); err != nil {
+		return err
+	}
+	if stateless[0] == 1 {
+		var record [statelessRecordLen]byte
+		copy(record[:], stateless[1:])
+		f.Stateless = decodeStatelessRecord(record)
+	}
+
+	var paymentAddr [33]byte
+	if _, err := io.ReadFull(r, paymentAddr[:]); err != nil {
+		return err
+	}
+	if paymentAddr[0] == 1 {
+		f.PaymentAddr = &[32]byte{}
+		copy(f.PaymentAddr[:], paymentAddr[1:])
+	}
+
 	return nil
 }
 
@@ -428,6 +563,8 @@ type mockChannelLink struct {
 
 	peer Peer
 
+	mailBox *memoryMailBox
+
 	packets chan *htlcPacket
 
 	eligible bool
@@ -449,16 +586,23 @@ func newMockChannelLink(htlcSwitch *Switch, chanID lnwire.ChannelID,
 	}
 }
 
+func (f *mockChannelLink) AttachMailBox(mailBox *memoryMailBox) {
+	f.mailBox = mailBox
+}
+
 func (f *mockChannelLink) HandleSwitchPacket(packet *htlcPacket) {
 	switch htlc := packet.htlc.(type) {
 	case *lnwire.UpdateAddHTLC:
-		f.htlcSwitch.addCircuit(&PaymentCircuit{
+		f.htlcSwitch.openCircuit(&PaymentCircuit{
 			PaymentHash:    htlc.PaymentHash,
 			IncomingChanID: packet.incomingChanID,
 			IncomingHTLCID: packet.incomingHTLCID,
 			OutgoingChanID: f.shortChanID,
 			OutgoingHTLCID: f.htlcID,
+			IncomingAmount: packet.incomingAmount,
+			OutgoingAmount: htlc.Amount,
 			ErrorEncrypter: packet.obfuscator,
+			TraceID:        packet.traceID,
 		})
 		f.htlcID++
 	}
@@ -472,6 +616,10 @@ func (f *mockChannelLink) HandleChannelUpdate(lnwire.Message) {
 func (f *mockChannelLink) UpdateForwardingPolicy(_ ForwardingPolicy) {
 }
 
+func (f *mockChannelLink) ForwardingPolicy() ForwardingPolicy {
+	return ForwardingPolicy{}
+}
+
 func (f *mockChannelLink) Stats() (uint64, lnwire.MilliSatoshi, lnwire.MilliSatoshi) {
 	return 0, 0, 0
 }
@@ -484,16 +632,75 @@ func (f *mockChannelLink) Start() error                       { return nil }
 func (f *mockChannelLink) Stop()                              {}
 func (f *mockChannelLink) EligibleToForward() bool            { return f.eligible }
 
+func (f *mockChannelLink) ReceivableBandwidth() lnwire.MilliSatoshi {
+	return 99999999
+}
+
+func (f *mockChannelLink) ChannelPoint() *wire.OutPoint {
+	return &wire.OutPoint{}
+}
+
+func (f *mockChannelLink) OutgoingHtlcAges() map[uint64]time.Duration {
+	return nil
+}
+
+func (f *mockChannelLink) LinkSnapshot() (*LinkSnapshot, error) {
+	return &LinkSnapshot{
+		ChanID:            f.chanID,
+		ShortChanID:       f.shortChanID,
+		EligibleToForward: f.eligible,
+		Bandwidth:         f.Bandwidth(),
+
+		ReceivableBandwidth: f.ReceivableBandwidth(),
+	}, nil
+}
+
+func (f *mockChannelLink) ShutdownIfChannelClean() error {
+	return nil
+}
+
+func (f *mockChannelLink) InitStfu() <-chan error {
+	done := make(chan error, 1)
+	done <- nil
+	return done
+}
+
+func (f *mockChannelLink) AdmissionStats() AdmissionStats {
+	return AdmissionStats{}
+}
+
+func (f *mockChannelLink) FeeSpikeStats() FeeSpikeStats {
+	return FeeSpikeStats{}
+}
+
 var _ ChannelLink = (*mockChannelLink)(nil)
 
 type mockInvoiceRegistry struct {
 	sync.Mutex
-	invoices map[chainhash.Hash]channeldb.Invoice
+	invoices    map[chainhash.Hash]channeldb.Invoice
+	holdWaiters map[chainhash.Hash][]chan struct{}
+
+	// ampParts records the AMP payment parts registered for each payment
+	// hash.
+	ampParts map[chainhash.Hash]*AmpRecord
+
+	// statelessInvoices records the stateless invoices settled for each
+	// payment hash.
+	statelessInvoices map[chainhash.Hash]channeldb.Invoice
+
+	// settleCapacity is non-nil while the registry is marked as
+	// saturated with settles.
+	settleCapacity chan struct{}
 }
 
 func newMockRegistry() *mockInvoiceRegistry {
 	return &mockInvoiceRegistry{
-		invoices: make(map[chainhash.Hash]channeldb.Invoice),
+		invoices:    make(map[chainhash.Hash]channeldb.Invoice),
+		holdWaiters: make(map[chainhash.Hash][]chan struct{}),
+		ampParts:    make(map[chainhash.Hash]*AmpRecord),
+		statelessInvoices: make(
+			map[chainhash.Hash]channeldb.Invoice,
+		),
 	}
 }
 
@@ -509,7 +716,9 @@ func (i *mockInvoiceRegistry) LookupInvoice(rHash chainhash.Hash) (channeldb.Inv
 	return invoice, nil
 }
 
-func (i *mockInvoiceRegistry) SettleInvoice(rhash chainhash.Hash) error {
+func (i *mockInvoiceRegistry) AcceptInvoice(rhash chainhash.Hash,
+	htlc channeldb.InvoiceHTLC) error {
+
 	i.Lock()
 	defer i.Unlock()
 
@@ -518,22 +727,171 @@ func (i *mockInvoiceRegistry) SettleInvoice(rhash chainhash.Hash) error {
 		return fmt.Errorf("can't find mock invoice: %x", rhash[:])
 	}
 
-	invoice.Terms.Settled = true
+	htlc.State = channeldb.HTLCAccepted
+	invoice.Htlcs = append(invoice.Htlcs, htlc)
+	invoice.Terms.State = channeldb.ContractAccepted
 	i.invoices[rhash] = invoice
 
 	return nil
 }
 
-func (i *mockInvoiceRegistry) AddInvoice(invoice channeldb.Invoice) error {
+func (i *mockInvoiceRegistry) SettleInvoice(rhash chainhash.Hash,
+	htlc channeldb.InvoiceHTLC) error {
+
+	i.Lock()
+	defer i.Unlock()
+
+	invoice, ok := i.invoices[rhash]
+	if !ok {
+		return fmt.Errorf("can't find mock invoice: %x", rhash[:])
+	}
+
+	htlcs := make([]channeldb.InvoiceHTLC, 0, len(invoice.Htlcs)+1)
+	for _, h := range invoice.Htlcs {
+		if h.ChanID != htlc.ChanID || h.HtlcIndex != htlc.HtlcIndex {
+			htlcs = append(htlcs, h)
+		}
+	}
+	htlcs = append(htlcs, htlc)
+
+	invoice.AmtPaid = 0
+	for idx := range htlcs {
+		htlcs[idx].State = channeldb.HTLCSettled
+		invoice.AmtPaid += htlcs[idx].Amt
+	}
+	invoice.Htlcs = htlcs
+	invoice.Terms.State = channeldb.ContractSettled
+	i.invoices[rhash] = invoice
+
+	return nil
+}
+
+func (i *mockInvoiceRegistry) SettleStatelessInvoice(
+	invoice *channeldb.Invoice, htlc channeldb.InvoiceHTLC) error {
+
+	i.Lock()
+	defer i.Unlock()
+
+	htlc.State = channeldb.HTLCSettled
+	settled := *invoice
+	settled.Htlcs = []channeldb.InvoiceHTLC{htlc}
+	settled.AmtPaid = htlc.Amt
+	settled.Terms.State = channeldb.ContractSettled
+	i.statelessInvoices[settled.Terms.PaymentHash] = settled
+
+	return nil
+}
+
+func (i *mockInvoiceRegistry) AddInvoice(invoice *channeldb.Invoice) error {
+	i.Lock()
+	defer i.Unlock()
+
+	rhash := invoice.Terms.PaymentHash
+	if rhash == [32]byte{} {
+		rhash = fastsha256.Sum256(invoice.Terms.PaymentPreimage[:])
+	}
+	i.invoices[chainhash.Hash(rhash)] = *invoice
+
+	return nil
+}
+
+func (i *mockInvoiceRegistry) AddAmpPart(rhash chainhash.Hash,
+	amt lnwire.MilliSatoshi, record *AmpRecord) error {
+
+	i.Lock()
+	defer i.Unlock()
+
+	i.ampParts[rhash] = record
+
+	return nil
+}
+
+func (i *mockInvoiceRegistry) AwaitHoldInvoice(rhash chainhash.Hash,
+	quit <-chan struct{}) (channeldb.Invoice, error) {
+
+	i.Lock()
+	invoice, ok := i.invoices[rhash]
+	if !ok {
+		i.Unlock()
+		return channeldb.Invoice{}, fmt.Errorf("can't find mock "+
+			"invoice: %x", rhash[:])
+	}
+	if invoice.Terms.HoldState != channeldb.HoldAccepting {
+		i.Unlock()
+		return invoice, nil
+	}
+	released := make(chan struct{})
+	i.holdWaiters[rhash] = append(i.holdWaiters[rhash], released)
+	i.Unlock()
+
+	select {
+	case <-released:
+		return i.LookupInvoice(rhash)
+	case <-quit:
+		return channeldb.Invoice{}, fmt.Errorf("mock registry exiting")
+	}
+}
+
+func (i *mockInvoiceRegistry) SettleBackpressure() <-chan struct{} {
 	i.Lock()
 	defer i.Unlock()
 
-	rhash := fastsha256.Sum256(invoice.Terms.PaymentPreimage[:])
-	i.invoices[chainhash.Hash(rhash)] = invoice
+	return i.settleCapacity
+}
+
+// setSaturated marks the registry as saturated with settles, or releases the
+// links holding settles if it was previously saturated.
+func (i *mockInvoiceRegistry) setSaturated(saturated bool) {
+	i.Lock()
+	defer i.Unlock()
+
+	switch {
+	case saturated && i.settleCapacity == nil:
+		i.settleCapacity = make(chan struct{})
+	case !saturated && i.settleCapacity != nil:
+		close(i.settleCapacity)
+		i.settleCapacity = nil
+	}
+}
+
+func (i *mockInvoiceRegistry) resolveHoldInvoice(rhash chainhash.Hash,
+	state channeldb.HoldState, preimage [32]byte) error {
+
+	i.Lock()
+	defer i.Unlock()
+
+	invoice, ok := i.invoices[rhash]
+	if !ok {
+		return fmt.Errorf("can't find mock invoice: %x", rhash[:])
+	}
+
+	invoice.Terms.HoldState = state
+	switch state {
+	case channeldb.HoldSettling:
+		invoice.Terms.PaymentPreimage = preimage
+	case channeldb.HoldCanceled:
+		invoice.Terms.State = channeldb.ContractCanceled
+	}
+	i.invoices[rhash] = invoice
+
+	for _, released := range i.holdWaiters[rhash] {
+		close(released)
+	}
+	delete(i.holdWaiters, rhash)
 
 	return nil
 }
 
+func (i *mockInvoiceRegistry) SettleHodlInvoice(rhash chainhash.Hash,
+	preimage [32]byte) error {
+
+	return i.resolveHoldInvoice(rhash, channeldb.HoldSettling, preimage)
+}
+
+func (i *mockInvoiceRegistry) CancelHodlInvoice(rhash chainhash.Hash) error {
+	return i.resolveHoldInvoice(rhash, channeldb.HoldCanceled, [32]byte{})
+}
+
 var _ InvoiceDatabase = (*mockInvoiceRegistry)(nil)
 
 type mockSigner struct {
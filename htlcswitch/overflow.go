@@ -0,0 +1,123 @@
+package htlcswitch
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// OverflowPolicy determines how a link handles an outgoing HTLC which can't
+// be added to its channel, as the maximum number of HTLCs permitted within
+// the commitment transaction has been reached.
+type OverflowPolicy string
+
+const (
+	// OverflowQueue places overflowing HTLCs within the link's overflow
+	// queue, from which they're added to the channel as slots become
+	// available. This is the default policy.
+	OverflowQueue OverflowPolicy = "queue"
+
+	// OverflowReject immediately fails overflowing HTLCs back with a
+	// TemporaryChannelFailure.
+	OverflowReject OverflowPolicy = "reject"
+
+	// OverflowReplace places overflowing HTLCs within the link's overflow
+	// queue until it holds maxReplaceQueueLen HTLCs, or the maximum
+	// length of the queue if lower. Once it's full, the
+	// queued HTLC paying the lowest fee is evicted and failed back in
+	// favor of a newcomer paying a higher fee. Otherwise, the newcomer is
+	// failed back.
+	OverflowReplace OverflowPolicy = "replace"
+)
+
+// maxReplaceQueueLen is the number of HTLCs the overflow queue of a link
+// using the OverflowReplace policy may hold before HTLCs are evicted from
+// it.
+const maxReplaceQueueLen = lnwallet.MaxHTLCNumber / 2
+
+// fee returns the fee we'll earn by forwarding the HTLC add carried by the
+// packet. Locally initiated HTLCs don't pay us a fee.
+func (p *htlcPacket) fee() lnwire.MilliSatoshi {
+	if p.incomingAmount < p.amount {
+		return 0
+	}
+
+	return p.incomingAmount - p.amount
+}
+
+// handleOverflow handles an outgoing HTLC which couldn't be added to the
+// channel as the maximum number of HTLCs within the commitment transaction
+// has been reached, according to the link's OverflowPolicy.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) handleOverflow(pkt *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC) {
+
+	switch l.cfg.OverflowPolicy {
+	case OverflowReject:
+		log.Debugf("ChannelPoint(%v): rejecting overflowing htlc with "+
+			"payment_hash=%x", l.channel.ChannelPoint(),
+			htlc.PaymentHash[:])
+
+		l.failAddPacket(pkt, htlc)
+		return
+
+	case OverflowReplace:
+		if l.overflowQueue.Length() >= l.admission.replaceQueueLen() {
+			l.replaceOverflow(pkt, htlc)
+			return
+		}
+	}
+
+	// Before placing the HTLC within the overflow queue, we'll ensure that
+	// the queue's budget permits it, so that HTLCs aren't held
+	// indefinitely should the channel remain saturated.
+	if err := l.admission.admit(time.Now()); err != nil {
+		l.rejectOverflow(pkt, htlc, err)
+		return
+	}
+
+	log.Infof("Downstream htlc add update with payment hash(%x) have "+
+		"been added to reprocessing queue, batch_size=%v",
+		htlc.PaymentHash[:], l.batchCounter)
+
+	l.overflowQueue.AddPkt(pkt)
+	l.cfg.Switch.traceAdd(pkt, TraceOverflowQueue, l.ShortChanID())
+}
+
+// replaceOverflow handles an overflowing HTLC once the overflow queue of a
+// link using the OverflowReplace policy is full, by evicting the queued HTLC
+// paying the lowest fee in its favor if it pays a higher fee.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) replaceOverflow(pkt *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC) {
+
+	if err := l.admission.admitReplacement(time.Now()); err != nil {
+		l.rejectOverflow(pkt, htlc, err)
+		return
+	}
+
+	evicted, ok := l.overflowQueue.ReplaceLowestFee(pkt)
+	if !ok {
+		log.Debugf("ChannelPoint(%v): rejecting overflowing htlc "+
+			"with payment_hash=%x, fee=%v doesn't exceed that of "+
+			"any queued htlc", l.channel.ChannelPoint(),
+			htlc.PaymentHash[:], pkt.fee())
+
+		l.failAddPacket(pkt, htlc)
+		return
+	}
+
+	evictedHtlc := evicted.htlc.(*lnwire.UpdateAddHTLC)
+	log.Debugf("ChannelPoint(%v): evicted queued htlc with "+
+		"payment_hash=%x, fee=%v in favor of htlc with "+
+		"payment_hash=%x, fee=%v", l.channel.ChannelPoint(),
+		evictedHtlc.PaymentHash[:], evicted.fee(),
+		htlc.PaymentHash[:], pkt.fee())
+
+	l.admission.recordAdmission()
+	l.failAddPacket(evicted, evictedHtlc)
+	l.cfg.Switch.traceAdd(pkt, TraceOverflowQueue, l.ShortChanID())
+}
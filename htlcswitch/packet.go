@@ -1,6 +1,10 @@
 package htlcswitch
 
 import (
+	"bytes"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
@@ -30,6 +34,11 @@ type htlcPacket struct {
 	// amount is the value of the HTLC that is being created or modified.
 	amount lnwire.MilliSatoshi
 
+	// incomingAmount is the value of the incoming HTLC of a forwarded
+	// HTLC add. The difference between it and amount is the fee we'll
+	// earn by forwarding the HTLC.
+	incomingAmount lnwire.MilliSatoshi
+
 	// htlc lnwire message type of which depends on switch request type.
 	htlc lnwire.Message
 
@@ -53,4 +62,74 @@ type htlcPacket struct {
 	// encrypt all errors related to this packet as if we were the first
 	// hop.
 	isResolution bool
+
+	// isPipelined is set to true if this packet is a settle relayed to the
+	// switch as soon as its preimage was received, ahead of the settle
+	// being locked in. The circuit of the HTLC is only closed once the
+	// settle is locked in, and forwarded to the switch a second time.
+	isPipelined bool
+
+	// attemptedLinks is the set of outgoing links on which adding a
+	// forwarded HTLC has already failed. The switch won't select any of
+	// these links when retrying the forward.
+	attemptedLinks map[lnwire.ShortChannelID]struct{}
+
+	// queuedAt is the time at which the packet was placed within the
+	// overflow queue of the outgoing link, if it overflowed the channel.
+	queuedAt time.Time
+
+	// sourceRef references the Add within the forwarding package of the
+	// incoming channel that this packet forwards. It's acknowledged once
+	// the packet has been handed off to the switch.
+	sourceRef *channeldb.AddRef
+
+	// destRef references the Settle or Fail within the forwarding package
+	// of the outgoing channel that this packet forwards. It's
+	// acknowledged once the packet has been handed off to the switch.
+	destRef *channeldb.SettleFailRef
+
+	// traceID is the trace ID of the HTLC this packet relates to. It's
+	// assigned once the HTLC enters the switch, and restored from the
+	// payment circuit for the settle or fail which resolves the HTLC.
+	traceID TraceID
+}
+
+// newAddFailPacket creates the packet failing the HTLC add carried by the
+// passed packet back to its source with the given failure, before it has been
+// offered over the outgoing channel. The failure is encrypted back to the
+// source, unless the payment was generated locally.
+func newAddFailPacket(pkt *htlcPacket, htlc *lnwire.UpdateAddHTLC,
+	failure lnwire.FailureMessage) (*htlcPacket, error) {
+
+	var (
+		localFailure = false
+		reason       lnwire.OpaqueReason
+	)
+	if pkt.obfuscator == nil {
+		var b bytes.Buffer
+		err := lnwire.EncodeFailure(&b, failure, 0)
+		if err != nil {
+			return nil, err
+		}
+		reason = lnwire.OpaqueReason(b.Bytes())
+		localFailure = true
+	} else {
+		var err error
+		reason, err = pkt.obfuscator.EncryptFirstHop(failure)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &htlcPacket{
+		incomingChanID: pkt.incomingChanID,
+		incomingHTLCID: pkt.incomingHTLCID,
+		amount:         htlc.Amount,
+		isRouted:       true,
+		localFailure:   localFailure,
+		htlc: &lnwire.UpdateFailHTLC{
+			Reason: reason,
+		},
+		traceID: pkt.traceID,
+	}, nil
 }
@@ -0,0 +1,130 @@
+package htlcswitch
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestPaymentAddrRecordEncoding ensures that a payment address survives being
+// carried within an MPP record, and that it can't be combined with an AMP
+// record, whose set ID takes its place.
+func TestPaymentAddrRecordEncoding(t *testing.T) {
+	t.Parallel()
+
+	records := &FinalHopRecords{
+		PaymentAddr: &[32]byte{1, 2, 3},
+		TotalAmt:    5000,
+	}
+	hopData, err := NewFinalHopData(records)
+	if err != nil {
+		t.Fatalf("unable to create record payloads: %v", err)
+	}
+	decoded, err := finalHopRecords(hopData)
+	if err != nil {
+		t.Fatalf("unable to decode records: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, records) {
+		t.Fatalf("expected records %v, got %v", records, decoded)
+	}
+
+	records.Amp = &AmpRecord{TotalAmt: 5000}
+	if _, err := NewFinalHopData(records); err != ErrAmpPaymentAddr {
+		t.Fatalf("expected ErrAmpPaymentAddr, got %v", err)
+	}
+}
+
+// TestChannelLinkCheckPaymentAddr ensures that HTLCs paying to an invoice
+// with a payment secret are rejected if they carry a mismatched secret, and
+// if they carry none only once legacy payments are rejected.
+func TestChannelLinkCheckPaymentAddr(t *testing.T) {
+	t.Parallel()
+
+	chanID := lnwire.NewShortChanIDFromInt(4)
+	aliceChannel, _, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, btcutil.SatoshiPerBitcoin,
+		btcutil.SatoshiPerBitcoin, chanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	link := NewChannelLink(ChannelLinkConfig{
+		Peer: newMockServer(t, "bob"),
+	}, aliceChannel, testStartingHeight).(*channelLink)
+	pd := &lnwallet.PaymentDescriptor{
+		RHash:  [32]byte{1},
+		Amount: 5000,
+	}
+
+	addr := [32]byte{2}
+	wrongAddr := [32]byte{3}
+	invoice := &channeldb.Invoice{
+		Terms: channeldb.ContractTerm{
+			Value:       5000,
+			PaymentAddr: addr,
+		},
+	}
+	legacyInvoice := &channeldb.Invoice{
+		Terms: channeldb.ContractTerm{
+			Value: 5000,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		rejectLegacy  bool
+		invoice       *channeldb.Invoice
+		paymentAddr   *[32]byte
+		expectFailure bool
+	}{
+		{
+			name:        "matching secret",
+			invoice:     invoice,
+			paymentAddr: &addr,
+		},
+		{
+			name:          "mismatched secret",
+			invoice:       invoice,
+			paymentAddr:   &wrongAddr,
+			expectFailure: true,
+		},
+		{
+			name:    "legacy payment accepted",
+			invoice: invoice,
+		},
+		{
+			name:          "legacy payment rejected",
+			rejectLegacy:  true,
+			invoice:       invoice,
+			expectFailure: true,
+		},
+		{
+			name:         "invoice without secret",
+			rejectLegacy: true,
+			invoice:      legacyInvoice,
+		},
+		{
+			name:         "matching secret while rejecting legacy",
+			rejectLegacy: true,
+			invoice:      invoice,
+			paymentAddr:  &addr,
+		},
+	}
+
+	for _, test := range tests {
+		link.cfg.RejectLegacyPayments = test.rejectLegacy
+		failure := link.checkPaymentAddr(
+			pd, test.invoice, test.paymentAddr,
+		)
+		if test.expectFailure != (failure != nil) {
+			t.Fatalf("%v: expected failure %v, got %v", test.name,
+				test.expectFailure, failure)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package htlcswitch
+
+import (
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrPaymentInFlight is returned when a payment can't be sent, as an attempt
+// for the same payment hash is recorded as in flight, but isn't tracked by
+// the switch.
+var ErrPaymentInFlight = errors.New("payment is already in flight")
+
+// PaymentStore is an interface which represents the persistent store within
+// which the switch records the payments it initiates, keyed by their payment
+// hash.
+type PaymentStore interface {
+	// PutPaymentAttempt records the passed attempt, replacing any prior
+	// attempt for the same payment hash.
+	PutPaymentAttempt(*channeldb.PaymentAttempt) error
+
+	// FetchPaymentAttempt returns the attempt recorded for the passed
+	// payment hash, or channeldb.ErrPaymentAttemptNotFound if there is
+	// none.
+	FetchPaymentAttempt([32]byte) (*channeldb.PaymentAttempt, error)
+
+	// FetchInFlightPaymentAttempts returns each of the recorded attempts
+	// that are still in flight.
+	FetchInFlightPaymentAttempts() ([]*channeldb.PaymentAttempt, error)
+}
+
+// paymentAttempt tracks a payment attempt which is in flight, allowing any
+// duplicate sends of the payment to await its result, rather than
+// dispatching another HTLC.
+type paymentAttempt struct {
+	attempt channeldb.PaymentAttempt
+
+	// done is closed once the attempt has completed, after which preimage
+	// and err hold its result.
+	done     chan struct{}
+	preimage [sha256.Size]byte
+	err      error
+}
+
+// newPaymentAttempt returns a new tracked attempt for the passed record.
+func newPaymentAttempt(attempt *channeldb.PaymentAttempt) *paymentAttempt {
+	return &paymentAttempt{
+		attempt: *attempt,
+		done:    make(chan struct{}),
+	}
+}
+
+// restoredAttemptFailure returns the error with which attempts that were in
+// flight when we last went down are failed, as the onion failure of their
+// HTLC can no longer be decrypted.
+func (s *Switch) restoredAttemptFailure() error {
+	return &ForwardingError{
+		ErrorSource:    s.cfg.SelfKey,
+		ExtraMsg:       "payment attempt failed across restart",
+		FailureMessage: lnwire.NewTemporaryChannelFailure(nil),
+	}
+}
+
+// restorePaymentAttempts loads the payment attempts that were in flight when
+// we last went down, so that their result is recorded once their HTLC is
+// resolved. Attempts whose HTLC was never added to a channel are failed, as
+// the HTLC was never signed for, and has therefore been forgotten.
+func (s *Switch) restorePaymentAttempts() error {
+	if s.cfg.PaymentStore == nil {
+		return nil
+	}
+
+	attempts, err := s.cfg.PaymentStore.FetchInFlightPaymentAttempts()
+	if err != nil {
+		return err
+	}
+
+	for _, attempt := range attempts {
+		a := newPaymentAttempt(attempt)
+		if attempt.OutgoingChanID == (lnwire.ShortChannelID{}) {
+			s.completePaymentAttempt(
+				a, zeroPreimage, s.restoredAttemptFailure(),
+			)
+			continue
+		}
+
+		key := circuitKey{
+			chanID: attempt.OutgoingChanID,
+			htlcID: attempt.OutgoingHTLCID,
+		}
+
+		s.pendingMutex.Lock()
+		s.paymentAttempts[attempt.PaymentHash] = a
+		s.restoredAttempts[key] = a
+		s.pendingMutex.Unlock()
+	}
+
+	log.Infof("Restored %d in-flight payment attempts", len(attempts))
+
+	return nil
+}
+
+// beginPaymentAttempt records an attempt to send the passed HTLC. If an
+// attempt for the same payment hash is already in flight, or has succeeded,
+// then it's returned instead, along with a value of true, in which case no
+// HTLC should be dispatched, and the result of the existing attempt should be
+// awaited instead.
+func (s *Switch) beginPaymentAttempt(
+	htlc *lnwire.UpdateAddHTLC) (*paymentAttempt, bool, error) {
+
+	s.pendingMutex.Lock()
+	defer s.pendingMutex.Unlock()
+
+	if a, ok := s.paymentAttempts[htlc.PaymentHash]; ok {
+		return a, true, nil
+	}
+
+	prior, err := s.cfg.PaymentStore.FetchPaymentAttempt(htlc.PaymentHash)
+	switch {
+	case err == channeldb.ErrPaymentAttemptNotFound:
+
+	case err != nil:
+		return nil, false, err
+
+	// If the payment has already succeeded, then we'll return the result
+	// of the successful attempt.
+	case prior.Status == channeldb.AttemptSucceeded:
+		a := newPaymentAttempt(prior)
+		a.preimage = prior.Preimage
+		close(a.done)
+		return a, true, nil
+
+	case prior.Status == channeldb.AttemptInFlight:
+		return nil, false, ErrPaymentInFlight
+	}
+
+	// Otherwise, either the payment hasn't been attempted before, or all
+	// prior attempts have failed, so a new attempt may be dispatched.
+	a := newPaymentAttempt(&channeldb.PaymentAttempt{
+		PaymentHash:  htlc.PaymentHash,
+		Amount:       htlc.Amount,
+		Status:       channeldb.AttemptInFlight,
+		CreationTime: time.Now(),
+	})
+	if err := s.cfg.PaymentStore.PutPaymentAttempt(&a.attempt); err != nil {
+		return nil, false, err
+	}
+
+	s.paymentAttempts[htlc.PaymentHash] = a
+
+	return a, false, nil
+}
+
+// recordAttemptHTLC records the outgoing HTLC of the passed attempt as its
+// circuit is opened, before it's offered over a channel, so that the attempt
+// can be resolved should the HTLC be settled or failed after a restart.
+func (s *Switch) recordAttemptHTLC(a *paymentAttempt,
+	circuit *PaymentCircuit) error {
+
+	s.pendingMutex.Lock()
+	defer s.pendingMutex.Unlock()
+
+	a.attempt.OutgoingChanID = circuit.OutgoingChanID
+	a.attempt.OutgoingHTLCID = circuit.OutgoingHTLCID
+
+	return s.cfg.PaymentStore.PutPaymentAttempt(&a.attempt)
+}
+
+// completePaymentAttempt records the result of the passed attempt, then
+// delivers it to any duplicate sends awaiting it. Attempts which have already
+// completed are left untouched.
+func (s *Switch) completePaymentAttempt(a *paymentAttempt,
+	preimage [sha256.Size]byte, err error) {
+
+	s.pendingMutex.Lock()
+	defer s.pendingMutex.Unlock()
+
+	select {
+	case <-a.done:
+		return
+	default:
+	}
+
+	if err != nil {
+		a.attempt.Status = channeldb.AttemptFailed
+	} else {
+		a.attempt.Status = channeldb.AttemptSucceeded
+		a.attempt.Preimage = preimage
+	}
+
+	if err := s.cfg.PaymentStore.PutPaymentAttempt(&a.attempt); err != nil {
+		log.Errorf("Unable to record result of payment attempt for "+
+			"%x: %v", a.attempt.PaymentHash[:], err)
+	}
+
+	if s.paymentAttempts[a.attempt.PaymentHash] == a {
+		delete(s.paymentAttempts, a.attempt.PaymentHash)
+	}
+	delete(s.restoredAttempts, circuitKey{
+		chanID: a.attempt.OutgoingChanID,
+		htlcID: a.attempt.OutgoingHTLCID,
+	})
+
+	a.preimage = preimage
+	a.err = err
+	close(a.done)
+}
+
+// resolveRestoredAttempt completes the restored payment attempt whose HTLC is
+// settled or failed by the passed packet, if any. It returns true if the
+// packet resolved an attempt.
+func (s *Switch) resolveRestoredAttempt(packet *htlcPacket) bool {
+	s.pendingMutex.RLock()
+	a, ok := s.restoredAttempts[circuitKey{
+		chanID: packet.outgoingChanID,
+		htlcID: packet.outgoingHTLCID,
+	}]
+	s.pendingMutex.RUnlock()
+	if !ok {
+		return false
+	}
+
+	switch htlc := packet.htlc.(type) {
+	case *lnwire.UpdateFufillHTLC:
+		s.completePaymentAttempt(a, htlc.PaymentPreimage, nil)
+
+	case *lnwire.UpdateFailHTLC:
+		s.completePaymentAttempt(
+			a, zeroPreimage, s.restoredAttemptFailure(),
+		)
+
+	default:
+		return false
+	}
+
+	log.Infof("Resolved payment attempt for %x restored across restart",
+		a.attempt.PaymentHash[:])
+
+	return true
+}
+
+// trimRestoredAttempts fails the restored payment attempts whose HTLC was
+// offered over the passed channel, but never signed for, and was therefore
+// forgotten when the channel was restored. As with circuits, the HTLCs with
+// an ID of at least start are considered stale.
+func (s *Switch) trimRestoredAttempts(chanID lnwire.ShortChannelID,
+	start uint64) {
+
+	var trimmed []*paymentAttempt
+	s.pendingMutex.RLock()
+	for key, a := range s.restoredAttempts {
+		if key.chanID == chanID && key.htlcID >= start {
+			trimmed = append(trimmed, a)
+		}
+	}
+	s.pendingMutex.RUnlock()
+
+	for _, a := range trimmed {
+		s.completePaymentAttempt(
+			a, zeroPreimage, s.restoredAttemptFailure(),
+		)
+	}
+}
+
+// LookupPayment returns the latest attempt of the payment with the passed
+// hash. If the payment has never been attempted, then
+// channeldb.ErrPaymentAttemptNotFound is returned.
+func (s *Switch) LookupPayment(hash [32]byte) (*channeldb.PaymentAttempt,
+	error) {
+
+	if s.cfg.PaymentStore == nil {
+		return nil, channeldb.ErrPaymentAttemptNotFound
+	}
+
+	s.pendingMutex.RLock()
+	if a, ok := s.paymentAttempts[hash]; ok {
+		attempt := a.attempt
+		s.pendingMutex.RUnlock()
+		return &attempt, nil
+	}
+	s.pendingMutex.RUnlock()
+
+	return s.cfg.PaymentStore.FetchPaymentAttempt(hash)
+}
+
+// InFlightPayments returns the attempts of each of the payments initiated by
+// the switch which are currently in flight.
+func (s *Switch) InFlightPayments() []*channeldb.PaymentAttempt {
+	s.pendingMutex.RLock()
+	defer s.pendingMutex.RUnlock()
+
+	attempts := make([]*channeldb.PaymentAttempt, 0, len(s.paymentAttempts))
+	for _, a := range s.paymentAttempts {
+		attempt := a.attempt
+		attempts = append(attempts, &attempt)
+	}
+
+	return attempts
+}
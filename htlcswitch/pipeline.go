@@ -0,0 +1,38 @@
+package htlcswitch
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// pipelineSettle relays the settle of the outgoing HTLC with the passed index
+// to the switch immediately, so the preimage is propagated backwards without
+// waiting for the settle to be committed. This is safe, as the preimage
+// alone allows us to claim the HTLC on-chain should the remote party fail to
+// commit the settle, while it halves the latency of settling multi-hop
+// payments.
+//
+// The switch leaves the circuit of the HTLC open, so the settle is still
+// forwarded within its forwarding package once locked in, which closes the
+// circuit. The switch only forwards the first of the two settles it
+// receives, while a settle lost to a restart is replayed from the forwarding
+// package as usual.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) pipelineSettle(htlcIndex uint64, preimage [32]byte) {
+	packet := &htlcPacket{
+		outgoingChanID: l.ShortChanID(),
+		outgoingHTLCID: htlcIndex,
+		isPipelined:    true,
+		htlc: &lnwire.UpdateFufillHTLC{
+			PaymentPreimage: preimage,
+		},
+	}
+
+	go func() {
+		if err := l.cfg.Switch.forward(packet); err != nil {
+			log.Errorf("ChannelPoint(%v): unable to pipeline "+
+				"settle of htlc %v: %v",
+				l.channel.ChannelPoint(), htlcIndex, err)
+		}
+	}()
+}
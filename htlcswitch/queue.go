@@ -3,6 +3,7 @@ package htlcswitch
 import (
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/lightningnetwork/lnd/lnwire"
 )
@@ -47,6 +48,17 @@ type packetQueue struct {
 	// deadlock situation where the main goroutine is attempting a send
 	// with the lock held.
 	queueLen int32
+
+	// peakLen is the largest number of packets the queue has held at
+	// once. This value should only be read or modified *atomically*.
+	peakLen int32
+
+	// numDequeued is the number of packets that have been handed to the
+	// channelLink, and totalResidency is the sum of the time each of them
+	// spent within the queue. These values should only be read or
+	// modified *atomically*.
+	numDequeued    uint64
+	totalResidency int64
 }
 
 // newPacketQueue returns a new instance of the packetQueue. The maxFreeSlots
@@ -136,6 +148,10 @@ func (p *packetQueue) packetCoordinator() {
 				atomic.AddInt32(&p.queueLen, -1)
 				atomic.AddInt64(&p.totalHtlcAmt, int64(-nextPkt.amount))
 				p.queueCond.L.Unlock()
+
+				residency := time.Since(nextPkt.queuedAt)
+				atomic.AddInt64(&p.totalResidency, int64(residency))
+				atomic.AddUint64(&p.numDequeued, 1)
 			case <-p.quit:
 				return
 			}
@@ -155,8 +171,12 @@ func (p *packetQueue) AddPkt(pkt *htlcPacket) {
 	// the message queue, and increment the internal atomic for tracking
 	// the queue's length.
 	p.queueCond.L.Lock()
+	pkt.queuedAt = time.Now()
 	p.queue = append(p.queue, pkt)
-	atomic.AddInt32(&p.queueLen, 1)
+	queueLen := atomic.AddInt32(&p.queueLen, 1)
+	if queueLen > atomic.LoadInt32(&p.peakLen) {
+		atomic.StoreInt32(&p.peakLen, queueLen)
+	}
 	atomic.AddInt64(&p.totalHtlcAmt, int64(pkt.amount))
 	p.queueCond.L.Unlock()
 
@@ -165,6 +185,37 @@ func (p *packetQueue) AddPkt(pkt *htlcPacket) {
 	p.queueCond.Signal()
 }
 
+// ReplaceLowestFee evicts the queued packet paying the lowest fee, and
+// appends the passed packet to the end of the queue, if the passed packet
+// pays a higher fee. The evicted packet is returned, along with a boolean
+// indicating whether a packet was evicted. The packet at the head of the
+// queue is never evicted, as it may be in the process of being handed to the
+// channelLink.
+func (p *packetQueue) ReplaceLowestFee(pkt *htlcPacket) (*htlcPacket, bool) {
+	p.queueCond.L.Lock()
+
+	lowest := -1
+	for i := 1; i < len(p.queue); i++ {
+		if lowest == -1 || p.queue[i].fee() < p.queue[lowest].fee() {
+			lowest = i
+		}
+	}
+	if lowest == -1 || p.queue[lowest].fee() >= pkt.fee() {
+		p.queueCond.L.Unlock()
+		return nil, false
+	}
+
+	evicted := p.queue[lowest]
+	pkt.queuedAt = time.Now()
+	copy(p.queue[lowest:], p.queue[lowest+1:])
+	p.queue[len(p.queue)-1] = pkt
+	atomic.AddInt64(&p.totalHtlcAmt, int64(pkt.amount)-int64(evicted.amount))
+
+	p.queueCond.L.Unlock()
+
+	return evicted, true
+}
+
 // SignalFreeSlot signals to the queue that a new slot has opened up within the
 // commitment transaction. The max amount of free slots has been defined when
 // initially creating the packetQueue itself. This method, combined with AddPkt
@@ -198,3 +249,35 @@ func (p *packetQueue) TotalHtlcAmount() lnwire.MilliSatoshi {
 	// TODO(roasbeef): also factor in fee rate?
 	return lnwire.MilliSatoshi(atomic.LoadInt64(&p.totalHtlcAmt))
 }
+
+// OldestQueuedAt returns the time at which the packet at the head of the
+// queue was added to it. False is returned if the queue is empty.
+func (p *packetQueue) OldestQueuedAt() (time.Time, bool) {
+	p.queueCond.L.Lock()
+	defer p.queueCond.L.Unlock()
+
+	if len(p.queue) == 0 {
+		return time.Time{}, false
+	}
+
+	return p.queue[0].queuedAt, true
+}
+
+// PeakLength returns the largest number of packets the queue has held at
+// once.
+func (p *packetQueue) PeakLength() int32 {
+	return atomic.LoadInt32(&p.peakLen)
+}
+
+// AvgResidency returns the average amount of time the packets handed to the
+// channelLink spent within the queue. Zero is returned if no packets have
+// left the queue yet.
+func (p *packetQueue) AvgResidency() time.Duration {
+	numDequeued := atomic.LoadUint64(&p.numDequeued)
+	if numDequeued == 0 {
+		return 0
+	}
+
+	totalResidency := atomic.LoadInt64(&p.totalResidency)
+	return time.Duration(totalResidency) / time.Duration(numDequeued)
+}
@@ -62,3 +62,68 @@ func TestWaitingQueueThreadSafety(t *testing.T) {
 		t.Fatal("wrong order of the objects")
 	}
 }
+
+// TestPacketQueueReplaceLowestFee tests that the queued packet paying the
+// lowest fee is only evicted in favor of a packet paying a higher fee, and
+// that the packet at the head of the queue is never evicted.
+func TestPacketQueueReplaceLowestFee(t *testing.T) {
+	t.Parallel()
+
+	q := newPacketQueue(10)
+
+	// We'll queue packets paying fees of 1, 5, and 3 mSAT. As the packet
+	// at the head of the queue may be in the process of being handed to
+	// the link, the packet paying a fee of 3 mSAT is the lowest paying
+	// packet eligible for eviction.
+	fees := []lnwire.MilliSatoshi{1, 5, 3}
+	for i, fee := range fees {
+		q.AddPkt(&htlcPacket{
+			incomingHTLCID: uint64(i),
+			amount:         1000,
+			incomingAmount: 1000 + fee,
+			htlc:           &lnwire.UpdateAddHTLC{},
+		})
+	}
+
+	// A packet paying the same fee as the lowest eligible packet
+	// shouldn't evict it.
+	if _, ok := q.ReplaceLowestFee(&htlcPacket{
+		incomingHTLCID: 3,
+		amount:         1000,
+		incomingAmount: 1003,
+	}); ok {
+		t.Fatalf("packet evicted in favor of one paying an equal fee")
+	}
+
+	// A packet paying a higher fee should evict it, and be placed at the
+	// end of the queue.
+	evicted, ok := q.ReplaceLowestFee(&htlcPacket{
+		incomingHTLCID: 4,
+		amount:         2000,
+		incomingAmount: 2004,
+	})
+	if !ok {
+		t.Fatalf("packet not evicted in favor of one paying a higher fee")
+	}
+	if evicted.incomingHTLCID != 2 {
+		t.Fatalf("wrong packet evicted: expected %v, got %v", 2,
+			evicted.incomingHTLCID)
+	}
+
+	var ids []uint64
+	for _, pkt := range q.queue {
+		ids = append(ids, pkt.incomingHTLCID)
+	}
+	if !reflect.DeepEqual(ids, []uint64{0, 1, 4}) {
+		t.Fatalf("wrong queue order: %v", ids)
+	}
+
+	if q.Length() != 3 {
+		t.Fatalf("queue has wrong length: expected %v, got %v", 3,
+			q.Length())
+	}
+	if q.TotalHtlcAmount() != 4000 {
+		t.Fatalf("wrong total htlc amount: expected %v, got %v",
+			lnwire.MilliSatoshi(4000), q.TotalHtlcAmount())
+	}
+}
@@ -0,0 +1,125 @@
+package htlcswitch
+
+import "sync"
+
+// ReplayEntry identifies an onion packet which has been processed.
+type ReplayEntry struct {
+	// Hash uniquely identifies the packet.
+	Hash [32]byte
+
+	// Expiry is the expiry height of the HTLC which carried the packet.
+	// Once it has passed, the entry may be forgotten, as any HTLC
+	// replaying the packet would be rejected as expired.
+	Expiry uint32
+}
+
+// ReplayLog records the onion packets which have been processed by an
+// OnionProcessor, allowing packets which are replayed to be detected. The
+// packets are recorded in batches, each identified by the forwarding package
+// they were locked in by, so that a batch which is processed again after a
+// restart isn't mistaken for a replay of itself.
+type ReplayLog interface {
+	// PutBatch records the passed entries as processed within the batch
+	// with the given ID, and returns the indexes of the entries whose
+	// packet had already been processed, either within a different batch,
+	// or earlier within the same batch. If a batch with the same ID was
+	// recorded before, then nothing is recorded, and the indexes returned
+	// for it at the time are returned again. The height is the current
+	// best height, at which expired entries may be forgotten.
+	PutBatch(batchID []byte, height uint32,
+		entries []ReplayEntry) (map[int]struct{}, error)
+}
+
+// replayBatch is a batch recorded within the memoryReplayLog.
+type replayBatch struct {
+	// replays is the set of indexes returned for the batch.
+	replays map[int]struct{}
+
+	// expiry is the highest expiry of the batch's entries. Once it has
+	// passed, the batch may be forgotten.
+	expiry uint32
+}
+
+// memoryReplayLog is a ReplayLog which retains its entries in memory. As a
+// result, packets processed before a restart can't be detected as replays
+// after it.
+type memoryReplayLog struct {
+	entries map[[32]byte]uint32
+	batches map[string]*replayBatch
+
+	// prunedHeight is the height at which expired entries were last
+	// pruned.
+	prunedHeight uint32
+
+	mtx sync.Mutex
+}
+
+// A compile time check to ensure memoryReplayLog implements the ReplayLog
+// interface.
+var _ ReplayLog = (*memoryReplayLog)(nil)
+
+// NewMemoryReplayLog returns a new ReplayLog which retains its entries in
+// memory.
+func NewMemoryReplayLog() ReplayLog {
+	return &memoryReplayLog{
+		entries: make(map[[32]byte]uint32),
+		batches: make(map[string]*replayBatch),
+	}
+}
+
+// PutBatch records the passed entries as processed within the batch with the
+// given ID, and returns the indexes of the entries which are replays.
+//
+// NOTE: Part of the ReplayLog interface.
+func (r *memoryReplayLog) PutBatch(batchID []byte, height uint32,
+	entries []ReplayEntry) (map[int]struct{}, error) {
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.prune(height)
+
+	if batch, ok := r.batches[string(batchID)]; ok {
+		return batch.replays, nil
+	}
+
+	batch := &replayBatch{
+		replays: make(map[int]struct{}),
+	}
+	for i, entry := range entries {
+		if _, ok := r.entries[entry.Hash]; ok {
+			batch.replays[i] = struct{}{}
+			continue
+		}
+
+		r.entries[entry.Hash] = entry.Expiry
+		if entry.Expiry > batch.expiry {
+			batch.expiry = entry.Expiry
+		}
+	}
+	r.batches[string(batchID)] = batch
+
+	return batch.replays, nil
+}
+
+// prune forgets the entries and batches which have expired at the passed
+// height.
+//
+// NOTE: This method MUST be called with the mutex held.
+func (r *memoryReplayLog) prune(height uint32) {
+	if height <= r.prunedHeight {
+		return
+	}
+	r.prunedHeight = height
+
+	for hash, expiry := range r.entries {
+		if expiry < height {
+			delete(r.entries, hash)
+		}
+	}
+	for id, batch := range r.batches {
+		if batch.expiry < height {
+			delete(r.batches, id)
+		}
+	}
+}
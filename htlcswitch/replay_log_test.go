@@ -0,0 +1,79 @@
+package htlcswitch
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestMemoryReplayLog tests that the memoryReplayLog detects entries which
+// are replayed across and within batches, that recording a batch again
+// yields the same replays, and that expired entries are forgotten.
+func TestMemoryReplayLog(t *testing.T) {
+	t.Parallel()
+
+	entry := func(b byte, expiry uint32) ReplayEntry {
+		return ReplayEntry{Hash: [32]byte{b}, Expiry: expiry}
+	}
+
+	tests := []struct {
+		name     string
+		batchID  string
+		height   uint32
+		entries  []ReplayEntry
+		expected map[int]struct{}
+	}{
+		{
+			name:     "new batch",
+			batchID:  "a",
+			height:   100,
+			entries:  []ReplayEntry{entry(1, 110), entry(2, 120)},
+			expected: map[int]struct{}{},
+		},
+		{
+			name:    "replays across and within batches",
+			batchID: "b",
+			height:  100,
+			entries: []ReplayEntry{
+				entry(2, 120), entry(3, 120), entry(3, 120),
+			},
+			expected: map[int]struct{}{0: {}, 2: {}},
+		},
+		{
+			name:     "first batch recorded again",
+			batchID:  "a",
+			height:   100,
+			entries:  []ReplayEntry{entry(1, 110), entry(2, 120)},
+			expected: map[int]struct{}{},
+		},
+		{
+			name:    "second batch recorded again",
+			batchID: "b",
+			height:  100,
+			entries: []ReplayEntry{
+				entry(2, 120), entry(3, 120), entry(3, 120),
+			},
+			expected: map[int]struct{}{0: {}, 2: {}},
+		},
+		{
+			name:     "expired entry forgotten",
+			batchID:  "c",
+			height:   115,
+			entries:  []ReplayEntry{entry(1, 130), entry(2, 130)},
+			expected: map[int]struct{}{1: {}},
+		},
+	}
+
+	replayLog := NewMemoryReplayLog()
+	for _, test := range tests {
+		replays, err := replayLog.PutBatch(
+			[]byte(test.batchID), test.height, test.entries,
+		)
+		if err != nil {
+			t.Fatalf("%v: unable to put batch: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(replays, test.expected) {
+			t.Fatalf("%v: expected replays %v, got %v", test.name,
+				test.expected, replays)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package htlcswitch
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// statelessRecordLen is the length of an encoded StatelessRecord.
+const statelessRecordLen = 52
+
+// StatelessRecord is carried within the onion of a payment to a stateless
+// invoice, as part of its FinalHopRecords. Such invoices aren't stored by
+// their creator: the preimage of the invoice is an HMAC of the record, keyed
+// by a secret only known to the creator, and its payment hash is that of the
+// preimage. As the record is handed back by the payer, the creator is able to
+// reconstruct the invoice once it's paid, while a record which has been
+// tampered with yields a preimage that doesn't match the payment hash.
+type StatelessRecord struct {
+	// Nonce is the random nonce of the invoice, which sets apart invoices
+	// which are otherwise the same.
+	Nonce [32]byte
+
+	// Value is the amount requested by the invoice. If zero, then the
+	// payer is able to pay any amount.
+	Value lnwire.MilliSatoshi
+
+	// CreationDate is the time the invoice was created, with a precision
+	// of one second.
+	CreationDate time.Time
+
+	// Expiry is the timespan after its creation the invoice is valid for,
+	// with a precision of one second.
+	Expiry time.Duration
+}
+
+// encode serializes the record into the value of its record within the onion,
+// which is also the message its preimage is derived from.
+func (r *StatelessRecord) encode() [statelessRecordLen]byte {
+	var b [statelessRecordLen]byte
+	copy(b[:32], r.Nonce[:])
+	binary.BigEndian.PutUint64(b[32:40], uint64(r.Value))
+	binary.BigEndian.PutUint64(b[40:48], uint64(r.CreationDate.Unix()))
+	binary.BigEndian.PutUint32(b[48:52], uint32(r.Expiry/time.Second))
+
+	return b
+}
+
+// decodeStatelessRecord deserializes a record from the value of its record
+// within the onion.
+func decodeStatelessRecord(b [statelessRecordLen]byte) *StatelessRecord {
+	record := &StatelessRecord{
+		Value: lnwire.MilliSatoshi(binary.BigEndian.Uint64(b[32:40])),
+		CreationDate: time.Unix(
+			int64(binary.BigEndian.Uint64(b[40:48])), 0,
+		),
+		Expiry: time.Duration(
+			binary.BigEndian.Uint32(b[48:52]),
+		) * time.Second,
+	}
+	copy(record.Nonce[:], b[:32])
+
+	return record
+}
+
+// Preimage derives the preimage of the stateless invoice described by the
+// record, using the passed secret key of its creator.
+func (r *StatelessRecord) Preimage(key [32]byte) [32]byte {
+	b := r.encode()
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write(b[:])
+
+	var preimage [32]byte
+	copy(preimage[:], mac.Sum(nil))
+
+	return preimage
+}
+
+// Invoice reconstructs the stateless invoice described by the record, using
+// the passed secret key of its creator.
+func (r *StatelessRecord) Invoice(key [32]byte) *channeldb.Invoice {
+	preimage := r.Preimage(key)
+
+	return &channeldb.Invoice{
+		CreationDate: r.CreationDate,
+		Memo:         []byte("stateless"),
+		Terms: channeldb.ContractTerm{
+			Value:           r.Value,
+			PaymentPreimage: preimage,
+			PaymentHash:     sha256.Sum256(preimage[:]),
+		},
+	}
+}
+
+// Expired returns true if the stateless invoice described by the record is
+// no longer valid at the passed time.
+func (r *StatelessRecord) Expired(now time.Time) bool {
+	return now.After(r.CreationDate.Add(r.Expiry))
+}
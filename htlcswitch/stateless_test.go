@@ -0,0 +1,146 @@
+package htlcswitch
+
+import (
+	"crypto/sha256"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// TestStatelessRecordPreimage ensures that a stateless record survives being
+// carried within the final hop records of an onion, and that the preimage
+// derived from it changes if either the record or the key it's derived with
+// changes.
+func TestStatelessRecordPreimage(t *testing.T) {
+	t.Parallel()
+
+	record := &StatelessRecord{
+		Nonce:        [32]byte{1, 2, 3},
+		Value:        5000,
+		CreationDate: time.Unix(1500000000, 0),
+		Expiry:       time.Hour,
+	}
+	decoded, err := decodeFinalHopRecords(
+		(&FinalHopRecords{Stateless: record}).encode(),
+	)
+	if err != nil {
+		t.Fatalf("unable to decode stateless record: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Stateless, record) {
+		t.Fatalf("expected stateless record %v, got %v", record,
+			decoded.Stateless)
+	}
+
+	key := [32]byte{9}
+	preimage := record.Preimage(key)
+	if record.Preimage(key) != preimage {
+		t.Fatalf("preimage derivation not deterministic")
+	}
+	if record.Preimage([32]byte{8}) == preimage {
+		t.Fatalf("preimage derived with another key matches")
+	}
+
+	tampered := *record
+	tampered.Value = 1
+	if tampered.Preimage(key) == preimage {
+		t.Fatalf("preimage of tampered record matches")
+	}
+
+	invoice := record.Invoice(key)
+	if invoice.Terms.PaymentPreimage != preimage ||
+		invoice.Terms.PaymentHash != sha256.Sum256(preimage[:]) ||
+		invoice.Terms.Value != record.Value {
+
+		t.Fatalf("unexpected invoice terms: %v", invoice.Terms)
+	}
+}
+
+// TestChannelLinkAcceptStateless ensures that HTLCs paying to stateless
+// invoices are only accepted if enabled, and if the record within their onion
+// matches their payment hash and hasn't expired, and that the invoice is
+// deemed settled once an HTLC paying to it has been settled.
+func TestChannelLinkAcceptStateless(t *testing.T) {
+	t.Parallel()
+
+	key := [32]byte{9}
+	record := &StatelessRecord{
+		Nonce:        [32]byte{1},
+		Value:        5000,
+		CreationDate: time.Unix(time.Now().Unix(), 0),
+		Expiry:       time.Hour,
+	}
+	expected := record.Invoice(key)
+	pd := &lnwallet.PaymentDescriptor{
+		RHash:     expected.Terms.PaymentHash,
+		Amount:    5000,
+		HtlcIndex: 3,
+	}
+
+	registry := newMockRegistry()
+	link := &channelLink{
+		cfg: ChannelLinkConfig{
+			Registry: registry,
+			PreimageCache: &mockPreimageCache{
+				preimageMap: make(map[[32]byte][]byte),
+			},
+		},
+	}
+
+	if _, failure := link.acceptStateless(pd, record); failure == nil {
+		t.Fatalf("stateless payment accepted while disabled")
+	}
+
+	link.cfg.StatelessInvoiceKey = &key
+	invoice, failure := link.acceptStateless(pd, record)
+	if failure != nil {
+		t.Fatalf("unable to accept stateless payment: %v", failure)
+	}
+	if !reflect.DeepEqual(invoice, expected) {
+		t.Fatalf("expected invoice %v, got %v", expected, invoice)
+	}
+
+	tampered := *record
+	tampered.Value = 1
+	if _, failure := link.acceptStateless(pd, &tampered); failure == nil {
+		t.Fatalf("tampered stateless record accepted")
+	}
+
+	expired := *record
+	expired.CreationDate = record.CreationDate.Add(-2 * time.Hour)
+	expiredPd := *pd
+	expiredPd.RHash = expired.Invoice(key).Terms.PaymentHash
+	_, failure = link.acceptStateless(&expiredPd, &expired)
+	if failure == nil {
+		t.Fatalf("expired stateless invoice accepted")
+	}
+
+	// Once settled, the invoice should be relayed to the registry, and
+	// further payments to it should find it settled.
+	invoiceHash := chainhash.Hash(pd.RHash)
+	err := link.settleExitInvoice(
+		invoiceHash, channeldb.InvoiceHTLC{
+			HtlcIndex: pd.HtlcIndex,
+			Amt:       pd.Amount,
+		}, invoice,
+	)
+	if err != nil {
+		t.Fatalf("unable to settle stateless invoice: %v", err)
+	}
+	settled, ok := registry.statelessInvoices[invoiceHash]
+	if !ok || settled.AmtPaid != pd.Amount {
+		t.Fatalf("stateless invoice not settled: %v", settled)
+	}
+
+	invoice, failure = link.acceptStateless(pd, record)
+	if failure != nil {
+		t.Fatalf("unable to accept stateless payment: %v", failure)
+	}
+	if invoice.Terms.State != channeldb.ContractSettled {
+		t.Fatalf("expected settled invoice, got state %v",
+			invoice.Terms.State)
+	}
+}
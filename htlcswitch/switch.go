@@ -13,6 +13,7 @@ import (
 	"github.com/roasbeef/btcd/btcec"
 
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -25,6 +26,10 @@ var (
 	// ErrChannelLinkNotFound is used when channel link hasn't been found.
 	ErrChannelLinkNotFound = errors.New("channel link not found")
 
+	// ErrSwitchExiting is returned when a packet couldn't be handed off
+	// to the switch, as it's shutting down.
+	ErrSwitchExiting = errors.New("htlc switch was stopped")
+
 	// zeroPreimage is the empty preimage which is returned when we have
 	// some errors.
 	zeroPreimage [sha256.Size]byte
@@ -44,6 +49,10 @@ type pendingPayment struct {
 	// an error, it deobfuscates the onion failure blob, and extracts the
 	// exact error from it.
 	deobfuscator ErrorDecrypter
+
+	// attempt is the attempt recorded for the payment within the
+	// PaymentStore, if one is configured.
+	attempt *paymentAttempt
 }
 
 // plexPacket encapsulates switch packet and adds error channel to receive
@@ -83,6 +92,12 @@ type ChanClose struct {
 	// process for the cooperative closure transaction kicks off.
 	TargetFeePerKw btcutil.Amount
 
+	// DeliveryScript is an optional script that our funds should be paid
+	// out to within the cooperative closure transaction. If nil, a fresh
+	// address will be generated by the wallet. This value is only
+	// utilized if the closure type is CloseRegular.
+	DeliveryScript []byte
+
 	// Updates is used by request creator to receive the notifications about
 	// execution of the close channel request.
 	Updates chan *lnrpc.CloseStatusUpdate
@@ -103,6 +118,74 @@ type Config struct {
 	// forced unilateral closure of the channel initiated by a local
 	// subsystem.
 	LocalChannelClose func(pubKey []byte, request *ChanClose)
+
+	// RetargetForward is an optional policy which is consulted if the
+	// outgoing channel of a forwarded HTLC, as specified within its onion,
+	// is unknown to the switch, and no forwarding alias has been
+	// registered for it. If it returns true along with an alternate
+	// channel to the same peer, then the HTLC will be re-targeted to that
+	// channel rather than being failed with UnknownNextPeer.
+	RetargetForward func(lnwire.ShortChannelID) (lnwire.ShortChannelID, bool)
+
+	// ForwardingFilter is the set of allow and deny lists that govern the
+	// peers the switch will forward HTLCs between. If nil, then an empty
+	// filter which permits all forwards is used.
+	ForwardingFilter *ForwardingFilter
+
+	// ForwardingCaps bounds the total value of the HTLCs forwarded by the
+	// switch within an hour and a day, both across the node and per peer.
+	// Forwards which would exceed a cap are failed with a
+	// TemporaryChannelFailure.
+	ForwardingCaps ForwardingCaps
+
+	// LinkSelector selects the outgoing link of a forwarded HTLC when we
+	// have several channels with its next hop. If nil, then a
+	// BestBandwidthSelector is used.
+	LinkSelector LinkSelector
+
+	// TraceExporter is an optional exporter to which the trace events of
+	// HTLCs passing through the switch and links are handed. Regardless
+	// of whether it's set, trace events are logged at the debug level.
+	TraceExporter TraceExporter
+
+	// DB is the channel database within which the circuits of forwarded
+	// HTLCs are persisted, allowing their settles and fails to be
+	// propagated back across restarts. If nil, circuits are only held in
+	// memory.
+	DB *channeldb.DB
+
+	// ExtractErrorEncrypter re-derives the error encrypters of the
+	// circuits restored from disk.
+	ExtractErrorEncrypter ErrorEncrypterExtracter
+
+	// FwdingLog is an optional log to which the switch records every HTLC
+	// it successfully forwards. Events are buffered, and written to the
+	// log periodically, as well as on shutdown.
+	FwdingLog ForwardingLog
+
+	// PaymentStore is an optional store within which the switch records
+	// the payments it initiates, keyed by payment hash. If set, sending a
+	// payment whose hash is already in flight, or has succeeded, awaits
+	// the result of the existing attempt rather than dispatching another
+	// HTLC, including across restarts.
+	PaymentStore PaymentStore
+
+	// FaultInjector is an optional debugging aid which injects faults
+	// into the forwarding path of the switch. It should only be set within
+	// integration tests.
+	FaultInjector *FaultInjector
+
+	// MailBoxMaxMessages is the maximum number of wire messages from the
+	// remote peer that may be queued within the mailbox of a link. Once
+	// reached, further messages are held back until the link catches up,
+	// applying backpressure to the peer. Zero indicates no limit.
+	MailBoxMaxMessages int
+
+	// MailBoxMaxPackets is the maximum number of packets from the switch
+	// that may be queued within the mailbox of a link. Once reached, new
+	// HTLCs are failed back, or retried over another link to the same
+	// peer, right away. Zero indicates no limit.
+	MailBoxMaxPackets int
 }
 
 // Switch is the central messaging bus for all incoming/outgoing HTLCs.
@@ -123,6 +206,18 @@ type Switch struct {
 	// service was initialized with.
 	cfg *Config
 
+	// fwdCaps enforces the caps on the value of the HTLCs forwarded by the
+	// switch.
+	fwdCaps *forwardingCapTracker
+
+	// metrics accumulates the counters describing the HTLCs handled by
+	// the switch and its links.
+	metrics *switchMetrics
+
+	// pendingFwdEvents holds the forwarding events which have yet to be
+	// flushed to the configured ForwardingLog.
+	pendingFwdEvents []channeldb.ForwardingEvent
+
 	// pendingPayments stores payments initiated by the user that are not yet
 	// settled. The map is used to later look up the payments and notify the
 	// user of the result when they are complete. Each payment is given a unique
@@ -131,10 +226,36 @@ type Switch struct {
 	pendingMutex    sync.RWMutex
 	nextPendingID   uint64
 
+	// paymentAttempts indexes the payment attempts in flight by their
+	// payment hash, while restoredAttempts indexes those that were in
+	// flight when we last went down by their outgoing HTLC. Both are only
+	// populated if a PaymentStore is configured, and are guarded by the
+	// pendingMutex.
+	paymentAttempts  map[[32]byte]*paymentAttempt
+	restoredAttempts map[circuitKey]*paymentAttempt
+
 	// circuits is storage for payment circuits which are used to
 	// forward the settle/fail htlc updates back to the add htlc initiator.
 	circuits *CircuitMap
 
+	// replayedAdds holds the incoming HTLCs whose Add was replayed after a
+	// restart and ignored, as their circuit was still open. If such a
+	// circuit is then trimmed, its incoming HTLC must be failed back, as
+	// the Add won't be replayed again. It's guarded by the replayMtx,
+	// which is held while circuits are trimmed, so that an Add can't be
+	// replayed while the trimmed circuits are resolved.
+	replayedAdds map[circuitKey]struct{}
+	replayMtx    sync.Mutex
+
+	// pipelinedSettles holds the outgoing HTLCs whose settle was relayed
+	// by their link ahead of being locked in, and has been forwarded,
+	// until the locked-in settle closes their circuit. It's guarded by
+	// the pipelineMtx, which is held while the circuit of a settle or
+	// fail is looked up and closed, so that each settle is forwarded only
+	// once.
+	pipelinedSettles map[circuitKey]struct{}
+	pipelineMtx      sync.Mutex
+
 	// links is a map of channel id and channel link which manages
 	// this channel.
 	linkIndex map[lnwire.ChannelID]ChannelLink
@@ -151,6 +272,23 @@ type Switch struct {
 	// channels that the switch maintains iwht that peer.
 	interfaceIndex map[[33]byte]map[ChannelLink]struct{}
 
+	// mailBoxes holds the mailbox of each channel which has had a link
+	// added. Mailboxes are retained once the link is removed, so packets
+	// destined to the channel are buffered while its link restarts, until
+	// the channel is closed. mailBoxIndex maps the short channel ID of
+	// each of these channels to its channel ID, so settles and fails can
+	// be buffered for links which aren't active.
+	mailBoxes    map[lnwire.ChannelID]*memoryMailBox
+	mailBoxIndex map[lnwire.ShortChannelID]lnwire.ChannelID
+
+	// forwardingAliases maps alternate short channel IDs which may be
+	// specified within the onion of a forwarded HTLC, to the short channel
+	// ID of the link that should carry the HTLC. This allows HTLCs
+	// destined for an alias, or for the prior short channel ID of a
+	// channel, to still be forwarded.
+	forwardingAliases map[lnwire.ShortChannelID]lnwire.ShortChannelID
+	aliasMtx          sync.RWMutex
+
 	// htlcPlex is the channel which all connected links use to coordinate
 	// the setup/teardown of Sphinx (onion routing) payment circuits.
 	// Active links forward any add/settle messages over this channel each
@@ -173,13 +311,34 @@ type Switch struct {
 
 // New creates the new instance of htlc switch.
 func New(cfg Config) *Switch {
+	if cfg.ForwardingFilter == nil {
+		cfg.ForwardingFilter = NewForwardingFilter(nil, nil)
+	}
+	if cfg.LinkSelector == nil {
+		cfg.LinkSelector = &BestBandwidthSelector{}
+	}
+
+	circuitCfg := &CircuitMapConfig{
+		DB:                    cfg.DB,
+		ExtractErrorEncrypter: cfg.ExtractErrorEncrypter,
+	}
+
 	return &Switch{
 		cfg:               &cfg,
-		circuits:          NewCircuitMap(),
+		circuits:          NewCircuitMap(circuitCfg),
+		fwdCaps:           newForwardingCapTracker(cfg.ForwardingCaps),
+		metrics:           newSwitchMetrics(),
 		linkIndex:         make(map[lnwire.ChannelID]ChannelLink),
 		forwardingIndex:   make(map[lnwire.ShortChannelID]ChannelLink),
 		interfaceIndex:    make(map[[33]byte]map[ChannelLink]struct{}),
+		mailBoxes:         make(map[lnwire.ChannelID]*memoryMailBox),
+		mailBoxIndex:      make(map[lnwire.ShortChannelID]lnwire.ChannelID),
+		forwardingAliases: make(map[lnwire.ShortChannelID]lnwire.ShortChannelID),
 		pendingPayments:   make(map[uint64]*pendingPayment),
+		paymentAttempts:   make(map[[32]byte]*paymentAttempt),
+		restoredAttempts:  make(map[circuitKey]*paymentAttempt),
+		replayedAdds:      make(map[circuitKey]struct{}),
+		pipelinedSettles:  make(map[circuitKey]struct{}),
 		htlcPlex:          make(chan *plexPacket),
 		chanCloseRequests: make(chan *ChanClose),
 		resolutionMsgs:    make(chan *resolutionMsg),
@@ -229,6 +388,56 @@ func (s *Switch) ProcessContractResolution(msg contractcourt.ResolutionMsg) erro
 func (s *Switch) SendHTLC(nextNode [33]byte, htlc *lnwire.UpdateAddHTLC,
 	deobfuscator ErrorDecrypter) ([sha256.Size]byte, error) {
 
+	return s.sendHTLC(nextNode, lnwire.ShortChannelID{}, htlc, deobfuscator)
+}
+
+// SendHTLCOverLink is used by other subsystems in order to send the htlc
+// update over the link of the passed channel, rather than over whichever link
+// to the next node has sufficient bandwidth. This allows payments that must
+// traverse a particular channel, such as circular payments, to be sent.
+func (s *Switch) SendHTLCOverLink(chanID lnwire.ShortChannelID,
+	htlc *lnwire.UpdateAddHTLC,
+	deobfuscator ErrorDecrypter) ([sha256.Size]byte, error) {
+
+	return s.sendHTLC([33]byte{}, chanID, htlc, deobfuscator)
+}
+
+// sendHTLC sends the htlc update to the next node, over the link of the
+// outgoing channel if one is specified, and awaits its result.
+func (s *Switch) sendHTLC(nextNode [33]byte,
+	outgoingChan lnwire.ShortChannelID, htlc *lnwire.UpdateAddHTLC,
+	deobfuscator ErrorDecrypter) ([sha256.Size]byte, error) {
+
+	// If a PaymentStore is configured, then we'll first ensure that the
+	// payment isn't already in flight, or has succeeded. If it is, then
+	// we'll await the result of the existing attempt, rather than
+	// dispatching another HTLC.
+	var attempt *paymentAttempt
+	if s.cfg.PaymentStore != nil {
+		var (
+			isDuplicate bool
+			err         error
+		)
+		attempt, isDuplicate, err = s.beginPaymentAttempt(htlc)
+		if err != nil {
+			return zeroPreimage, err
+		}
+
+		if isDuplicate {
+			log.Infof("Payment to %x already attempted, awaiting "+
+				"its result", htlc.PaymentHash[:])
+
+			select {
+			case <-attempt.done:
+				return attempt.preimage, attempt.err
+			case <-s.quit:
+				return zeroPreimage, errors.New("htlc switch " +
+					"have been stopped while waiting for " +
+					"payment result")
+			}
+		}
+	}
+
 	// Create payment and add to the map of payment in order later to be
 	// able to retrieve it and return response to the user.
 	payment := &pendingPayment{
@@ -237,6 +446,7 @@ func (s *Switch) SendHTLC(nextNode [33]byte, htlc *lnwire.UpdateAddHTLC,
 		paymentHash:  htlc.PaymentHash,
 		amount:       htlc.Amount,
 		deobfuscator: deobfuscator,
+		attempt:      attempt,
 	}
 
 	s.pendingMutex.Lock()
@@ -250,11 +460,18 @@ func (s *Switch) SendHTLC(nextNode [33]byte, htlc *lnwire.UpdateAddHTLC,
 	// system and something wrong happened.
 	packet := &htlcPacket{
 		incomingHTLCID: paymentID,
+		outgoingChanID: outgoingChan,
 		destNode:       nextNode,
 		htlc:           htlc,
+		traceID:        newTraceID(),
 	}
+	s.traceAdd(packet, TraceInitiated, lnwire.ShortChannelID{})
+
 	if err := s.forward(packet); err != nil {
 		s.removePendingPayment(paymentID)
+		if attempt != nil {
+			s.completePaymentAttempt(attempt, zeroPreimage, err)
+		}
 		return zeroPreimage, err
 	}
 
@@ -285,7 +502,8 @@ func (s *Switch) SendHTLC(nextNode [33]byte, htlc *lnwire.UpdateAddHTLC,
 // UpdateForwardingPolicies sends a message to the switch to update the
 // forwarding policies for the set of target channels. If the set of targeted
 // channels is nil, then the forwarding policies for all active channels with
-// be updated.
+// be updated. Targeted channels without an active link are skipped, as their
+// policy is loaded from the channel graph once their link is started.
 //
 // NOTE: This function is synchronous and will block until either the
 // forwarding policies for all links have been updated, or the switch shuts
@@ -341,12 +559,13 @@ func (s *Switch) updateLinkPolicies(c *updatePoliciesCmd) error {
 	for _, targetLink := range c.targetChans {
 		cid := lnwire.NewChanIDFromOutPoint(&targetLink)
 
-		// If we can't locate a link by its converted channel ID, then we'll
-		// return an error back to the caller.
+		// If we can't locate a link by its converted channel ID, then
+		// the channel isn't active, so there's nothing to update.
 		link, ok := s.linkIndex[cid]
 		if !ok {
-			return fmt.Errorf("unable to find ChannelPoint(%v) to "+
-				"update link policy", targetLink)
+			log.Debugf("Skipping policy update of inactive "+
+				"ChannelPoint(%v)", targetLink)
+			continue
 		}
 
 		link.UpdateForwardingPolicy(c.newPolicy)
@@ -359,6 +578,47 @@ func (s *Switch) updateLinkPolicies(c *updatePoliciesCmd) error {
 // update. Also this function is used by channel links itself in order to
 // forward the update after it has been included in the channel.
 func (s *Switch) forward(packet *htlcPacket) error {
+	if s.cfg.FaultInjector != nil {
+		rule, ok := s.cfg.FaultInjector.match(packet)
+		if ok {
+			return s.forwardFaulty(packet, rule)
+		}
+	}
+
+	return s.route(packet)
+}
+
+// forwardFaulty forwards the packet, subject to the fault described by the
+// passed rule.
+func (s *Switch) forwardFaulty(packet *htlcPacket, rule FaultRule) error {
+	log.Infof("Applying %v to %T packet with trace=%v", &rule,
+		packet.htlc, packet.traceID)
+
+	switch rule.Action {
+	case FaultDrop:
+		return nil
+
+	case FaultDelay:
+		select {
+		case <-time.After(rule.Delay):
+		case <-s.quit:
+			return ErrSwitchExiting
+		}
+
+	case FaultDuplicate:
+		err := s.route(copyPacket(packet))
+		if err != nil {
+			log.Infof("Duplicate of %T packet with trace=%v "+
+				"failed: %v", packet.htlc, packet.traceID, err)
+		}
+	}
+
+	return s.route(packet)
+}
+
+// route hands the packet to the htlcForwarder goroutine, which will find the
+// next channel link and apply the htlc update.
+func (s *Switch) route(packet *htlcPacket) error {
 	command := &plexPacket{
 		pkt: packet,
 		err: make(chan error, 1),
@@ -367,15 +627,14 @@ func (s *Switch) forward(packet *htlcPacket) error {
 	select {
 	case s.htlcPlex <- command:
 	case <-s.quit:
-		return errors.New("Htlc Switch was stopped")
+		return ErrSwitchExiting
 	}
 
 	select {
 	case err := <-command.err:
 		return err
 	case <-s.quit:
-		return errors.New("unable to forward htlc packet htlc switch was " +
-			"stopped")
+		return ErrSwitchExiting
 	}
 }
 
@@ -407,8 +666,17 @@ func (s *Switch) handleLocalDispatch(packet *htlcPacket) error {
 	// User have created the htlc update therefore we should find the
 	// appropriate channel link and send the payment over this link.
 	case *lnwire.UpdateAddHTLC:
-		// Try to find links by node destination.
-		links, err := s.getLinks(packet.destNode)
+		// If the payment must be sent over a particular channel, then
+		// its link is the only candidate, otherwise we'll try to find
+		// links by node destination.
+		var links []ChannelLink
+		if packet.outgoingChanID != (lnwire.ShortChannelID{}) {
+			var link ChannelLink
+			link, err = s.getLinkByShortID(packet.outgoingChanID)
+			links = []ChannelLink{link}
+		} else {
+			links, err = s.getLinks(packet.destNode)
+		}
 		if err != nil {
 			log.Errorf("unable to find links by destination %v", err)
 			return &ForwardingError{
@@ -450,6 +718,7 @@ func (s *Switch) handleLocalDispatch(packet *htlcPacket) error {
 				"outgoing links: need %v, max available is %v",
 				htlc.Amount, largestBandwidth)
 			log.Error(err)
+			s.traceAdd(packet, TraceFailed, lnwire.ShortChannelID{})
 
 			htlcErr := lnwire.NewTemporaryChannelFailure(nil)
 			return &ForwardingError{
@@ -471,6 +740,11 @@ func (s *Switch) handleLocalDispatch(packet *htlcPacket) error {
 	// user payment and return successful response.
 	case *lnwire.UpdateFufillHTLC:
 		// Notify the user that his payment was successfully proceed.
+		if payment.attempt != nil {
+			s.completePaymentAttempt(
+				payment.attempt, htlc.PaymentPreimage, nil,
+			)
+		}
 		payment.err <- nil
 		payment.preimage <- htlc.PaymentPreimage
 		s.removePendingPayment(packet.incomingHTLCID)
@@ -531,6 +805,11 @@ func (s *Switch) handleLocalDispatch(packet *htlcPacket) error {
 			}
 		}
 
+		if payment.attempt != nil {
+			s.completePaymentAttempt(
+				payment.attempt, zeroPreimage, failure,
+			)
+		}
 		payment.err <- failure
 		payment.preimage <- zeroPreimage
 		s.removePendingPayment(packet.incomingHTLCID)
@@ -558,6 +837,25 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			return s.handleLocalDispatch(packet)
 		}
 
+		// If a circuit is already open for the incoming HTLC, then
+		// it's a replay of an HTLC we forwarded prior to a restart, so
+		// it mustn't be forwarded again.
+		s.replayMtx.Lock()
+		circuit := s.circuits.LookupByIncoming(
+			packet.incomingChanID, packet.incomingHTLCID,
+		)
+		if circuit != nil {
+			s.replayedAdds[circuit.incomingKey()] = struct{}{}
+		}
+		s.replayMtx.Unlock()
+		if circuit != nil {
+			log.Debugf("Ignoring duplicate forward of htlc "+
+				"(%s, %d), already forwarded as (%s, %d)",
+				circuit.IncomingChanID, circuit.IncomingHTLCID,
+				circuit.OutgoingChanID, circuit.OutgoingHTLCID)
+			return nil
+		}
+
 		source, err := s.getLinkByShortID(packet.incomingChanID)
 		if err != nil {
 			err := errors.Errorf("unable to find channel link "+
@@ -567,11 +865,18 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 		}
 
 		targetLink, err := s.getLinkByShortID(packet.outgoingChanID)
+		if err != nil {
+			// The outgoing channel isn't known to us, however it
+			// may be an alias of one of our channels, in which
+			// case we'll re-target the HTLC.
+			targetLink, err = s.retargetForward(packet.outgoingChanID)
+		}
 		if err != nil {
 			// If packet was forwarded from another channel link
 			// than we should notify this link that some error
 			// occurred.
 			failure := lnwire.FailUnknownNextPeer{}
+			s.metrics.failedHTLC(failure.Code())
 			reason, err := packet.obfuscator.EncryptFirstHop(failure)
 			if err != nil {
 				err := errors.Errorf("unable to obfuscate "+
@@ -580,6 +885,7 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 				return err
 			}
 
+			s.traceAdd(packet, TraceFailed, packet.incomingChanID)
 			source.HandleSwitchPacket(&htlcPacket{
 				incomingChanID: packet.incomingChanID,
 				incomingHTLCID: packet.incomingHTLCID,
@@ -587,17 +893,53 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 				htlc: &lnwire.UpdateFailHTLC{
 					Reason: reason,
 				},
+				traceID: packet.traceID,
 			})
 			err = errors.Errorf("unable to find link with "+
 				"destination %v", packet.outgoingChanID)
 			log.Error(err)
 			return err
 		}
+
+		// Before selecting a link, we'll ensure that we're permitted to
+		// forward HTLCs between the source and destination peers.
+		fwdErr := s.cfg.ForwardingFilter.checkForward(
+			source.Peer().PubKey(), targetLink.Peer().PubKey(),
+		)
+		if fwdErr != nil {
+			failure := lnwire.FailUnknownNextPeer{}
+			s.metrics.failedHTLC(failure.Code())
+			reason, err := packet.obfuscator.EncryptFirstHop(failure)
+			if err != nil {
+				err := errors.Errorf("unable to obfuscate "+
+					"error: %v", err)
+				log.Error(err)
+				return err
+			}
+
+			s.traceAdd(packet, TraceFailed, packet.incomingChanID)
+			source.HandleSwitchPacket(&htlcPacket{
+				incomingChanID: packet.incomingChanID,
+				incomingHTLCID: packet.incomingHTLCID,
+				isRouted:       true,
+				htlc: &lnwire.UpdateFailHTLC{
+					Reason: reason,
+				},
+				traceID: packet.traceID,
+			})
+
+			err = errors.Errorf("rejecting forward from %v to %v: "+
+				"%v", packet.incomingChanID,
+				packet.outgoingChanID, fwdErr)
+			log.Error(err)
+			return err
+		}
+
 		interfaceLinks, _ := s.getLinks(targetLink.Peer().PubKey())
 
-		// Try to find destination channel link with appropriate
-		// bandwidth.
-		var destination ChannelLink
+		// Gather the links to the next hop with sufficient bandwidth to
+		// carry the HTLC, from which the destination link is selected.
+		candidates := make([]*LinkCandidate, 0, len(interfaceLinks))
 		for _, link := range interfaceLinks {
 			// We'll skip any links that aren't yet eligible for
 			// forwarding.
@@ -605,12 +947,23 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 				continue
 			}
 
-			if link.Bandwidth() >= htlc.Amount {
+			// If this is a retry of a forward that a link failed
+			// to add, then we'll skip the links that have already
+			// been attempted.
+			if _, ok := packet.attemptedLinks[link.ShortChanID()]; ok {
+				continue
+			}
 
-				destination = link
-				break
+			bandwidth := link.Bandwidth()
+			if bandwidth < htlc.Amount {
+				continue
 			}
+
+			candidates = append(
+				candidates, newLinkCandidate(link, bandwidth),
+			)
 		}
+		destination := s.cfg.LinkSelector.SelectLink(htlc, candidates)
 
 		// If the channel link we're attempting to forward the update
 		// over has insufficient capacity, then we'll cancel the htlc
@@ -620,6 +973,7 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			// channel link than we should notify this
 			// link that some error occurred.
 			failure := lnwire.NewTemporaryChannelFailure(nil)
+			s.metrics.failedHTLC(failure.Code())
 			reason, err := packet.obfuscator.EncryptFirstHop(failure)
 			if err != nil {
 				err := errors.Errorf("unable to obfuscate "+
@@ -628,6 +982,7 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 				return err
 			}
 
+			s.traceAdd(packet, TraceFailed, packet.incomingChanID)
 			source.HandleSwitchPacket(&htlcPacket{
 				incomingChanID: packet.incomingChanID,
 				incomingHTLCID: packet.incomingHTLCID,
@@ -635,17 +990,57 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 				htlc: &lnwire.UpdateFailHTLC{
 					Reason: reason,
 				},
+				traceID: packet.traceID,
 			})
 
 			err = errors.Errorf("unable to find appropriate "+
 				"channel link insufficient capacity, need "+
-				"%v", htlc.Amount)
+				"%v, %v links already attempted", htlc.Amount,
+				len(packet.attemptedLinks))
+			log.Error(err)
+			return err
+		}
+
+		// Before handing the packet to the destination link, we'll
+		// ensure that the forward doesn't exceed any of our caps on the
+		// value forwarded.
+		capErr := s.fwdCaps.addForward(
+			packet.incomingChanID, packet.incomingHTLCID,
+			htlc.Amount, source.Peer().PubKey(),
+			destination.Peer().PubKey(),
+		)
+		if capErr != nil {
+			failure := lnwire.NewTemporaryChannelFailure(nil)
+			s.metrics.failedHTLC(failure.Code())
+			reason, err := packet.obfuscator.EncryptFirstHop(failure)
+			if err != nil {
+				err := errors.Errorf("unable to obfuscate "+
+					"error: %v", err)
+				log.Error(err)
+				return err
+			}
+
+			s.traceAdd(packet, TraceFailed, packet.incomingChanID)
+			source.HandleSwitchPacket(&htlcPacket{
+				incomingChanID: packet.incomingChanID,
+				incomingHTLCID: packet.incomingHTLCID,
+				isRouted:       true,
+				htlc: &lnwire.UpdateFailHTLC{
+					Reason: reason,
+				},
+				traceID: packet.traceID,
+			})
+
+			err = errors.Errorf("rejecting forward from %v to %v: "+
+				"%v", packet.incomingChanID,
+				destination.ShortChanID(), capErr)
 			log.Error(err)
 			return err
 		}
 
 		// Send the packet to the destination channel link which
 		// manages the channel.
+		s.metrics.forwardedHTLC()
 		destination.HandleSwitchPacket(packet)
 		return nil
 
@@ -655,33 +1050,54 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 	case *lnwire.UpdateFufillHTLC, *lnwire.UpdateFailHTLC:
 		if !packet.isRouted {
 			// Use circuit map to find the link to forward settle/fail to.
-			circuit := s.circuits.LookupByHTLC(packet.outgoingChanID,
-				packet.outgoingHTLCID)
-			if circuit == nil {
+			circuit, forward := s.resolveCircuit(packet)
+			switch {
+			// The settle was pipelined after the locked-in settle
+			// already closed the circuit.
+			case circuit == nil && packet.isPipelined:
+				log.Debugf("Ignoring pipelined settle of "+
+					"closed circuit (%s, %d)",
+					packet.outgoingChanID,
+					packet.outgoingHTLCID)
+				return nil
+
+			case circuit == nil:
+				// The HTLC may belong to a payment we
+				// initiated before going down, in which case
+				// we'll record its result.
+				if s.resolveRestoredAttempt(packet) {
+					return nil
+				}
+
 				err := errors.Errorf("Unable to find target channel for HTLC "+
 					"settle/fail: channel ID = %s, HTLC ID = %d",
 					packet.outgoingChanID, packet.outgoingHTLCID)
 				log.Error(err)
 				return err
-			}
 
-			// Remove circuit since we are about to complete the HTLC.
-			err := s.circuits.Remove(packet.outgoingChanID,
-				packet.outgoingHTLCID)
-			if err != nil {
-				log.Warnf("Failed to close completed onion circuit for %x: "+
-					"(%s, %d) <-> (%s, %d)", circuit.PaymentHash,
-					circuit.IncomingChanID, circuit.IncomingHTLCID,
-					circuit.OutgoingChanID, circuit.OutgoingHTLCID)
-			} else {
-				log.Debugf("Closed completed onion circuit for %x: "+
-					"(%s, %d) <-> (%s, %d)", circuit.PaymentHash,
-					circuit.IncomingChanID, circuit.IncomingHTLCID,
-					circuit.OutgoingChanID, circuit.OutgoingHTLCID)
+			// The settle was already forwarded when it was
+			// pipelined.
+			case !forward:
+				log.Debugf("Ignoring duplicate settle of htlc "+
+					"(%s, %d)", packet.outgoingChanID,
+					packet.outgoingHTLCID)
+				return nil
 			}
 
 			packet.incomingChanID = circuit.IncomingChanID
 			packet.incomingHTLCID = circuit.IncomingHTLCID
+			packet.traceID = circuit.TraceID
+
+			stage := TraceSettled
+			if _, ok := htlc.(*lnwire.UpdateFailHTLC); ok {
+				stage = TraceFailed
+			} else {
+				s.recordForward(circuit)
+			}
+			s.trace(
+				circuit.TraceID, circuit.PaymentHash, stage,
+				circuit.IncomingChanID,
+			)
 
 			// Obfuscate the error message for fail updates before
 			// sending back through the circuit unless the payment
@@ -694,6 +1110,7 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 					if packet.isResolution {
 						// TODO(roasbeef): don't need to pass actually?
 						failure := &lnwire.FailPermanentChannelFailure{}
+						var err error
 						htlc.Reason, err = circuit.ErrorEncrypter.EncryptFirstHop(
 							failure,
 						)
@@ -721,29 +1138,118 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			return s.handleLocalDispatch(packet)
 		}
 
+		// With the forward resolved, it no longer counts towards our
+		// caps if it was failed.
+		_, failed := htlc.(*lnwire.UpdateFailHTLC)
+		s.fwdCaps.resolveForward(
+			packet.incomingChanID, packet.incomingHTLCID, failed,
+		)
+
 		source, err := s.getLinkByShortID(packet.incomingChanID)
-		if err != nil {
-			err := errors.Errorf("Unable to get source channel "+
-				"link to forward HTLC settle/fail: %v", err)
-			log.Error(err)
-			return err
+		if err == nil {
+			source.HandleSwitchPacket(packet)
+			return nil
 		}
 
-		source.HandleSwitchPacket(packet)
-		return nil
+		// If the source link is restarting, then we'll buffer the
+		// packet within its mailbox, so it's delivered once the link
+		// is back up, as the circuit has already been closed.
+		mailBox, ok := s.getMailBoxByShortID(packet.incomingChanID)
+		if ok {
+			log.Debugf("Buffering HTLC settle/fail for inactive "+
+				"link %v", packet.incomingChanID)
+			return mailBox.AddPacket(packet)
+		}
+
+		err = errors.Errorf("Unable to get source channel link to "+
+			"forward HTLC settle/fail: %v", err)
+		log.Error(err)
+		return err
 
 	default:
 		return errors.New("wrong update type")
 	}
 }
 
+// retargetForward attempts to find an alternate link for an HTLC whose
+// outgoing channel is unknown to the switch. The registered forwarding aliases
+// are consulted first, followed by the RetargetForward policy, if any.
+func (s *Switch) retargetForward(
+	chanID lnwire.ShortChannelID) (ChannelLink, error) {
+
+	s.aliasMtx.RLock()
+	target, ok := s.forwardingAliases[chanID]
+	s.aliasMtx.RUnlock()
+
+	if !ok && s.cfg.RetargetForward != nil {
+		target, ok = s.cfg.RetargetForward(chanID)
+	}
+	if !ok {
+		return nil, ErrChannelLinkNotFound
+	}
+
+	link, err := s.getLinkByShortID(target)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Re-targeting forward for ChannelID(%v) to "+
+		"ChannelID(%v)", chanID, target)
+
+	return link, nil
+}
+
+// AddForwardingAlias registers an alternate short channel ID for the channel
+// identified by chanID. Any HTLCs which specify the alias as their outgoing
+// channel will be forwarded over the link of the channel.
+func (s *Switch) AddForwardingAlias(alias, chanID lnwire.ShortChannelID) {
+	s.aliasMtx.Lock()
+	s.forwardingAliases[alias] = chanID
+	s.aliasMtx.Unlock()
+}
+
+// RemoveForwardingAlias removes a forwarding alias previously registered via
+// AddForwardingAlias.
+func (s *Switch) RemoveForwardingAlias(alias lnwire.ShortChannelID) {
+	s.aliasMtx.Lock()
+	delete(s.forwardingAliases, alias)
+	s.aliasMtx.Unlock()
+}
+
+// ForwardingAliases returns a copy of the registered forwarding aliases,
+// mapping each alias to the short channel ID of the channel it forwards over.
+func (s *Switch) ForwardingAliases() (
+	aliases map[lnwire.ShortChannelID]lnwire.ShortChannelID) {
+
+	s.aliasMtx.RLock()
+	defer s.aliasMtx.RUnlock()
+
+	aliases = make(
+		map[lnwire.ShortChannelID]lnwire.ShortChannelID,
+		len(s.forwardingAliases),
+	)
+	for alias, chanID := range s.forwardingAliases {
+		aliases[alias] = chanID
+	}
+
+	return aliases
+}
+
+// ForwardingFilter returns the allow and deny lists which govern the peers the
+// switch will forward HTLCs between. The returned filter may be updated at
+// runtime.
+func (s *Switch) ForwardingFilter() *ForwardingFilter {
+	return s.cfg.ForwardingFilter
+}
+
 // CloseLink creates and sends the close channel command to the target link
 // directing the specified closure type. If the closure type if CloseRegular,
-// then the last parameter should be the ideal fee-per-kw that will be used as
-// a starting point for close negotiation.
+// then targetFeePerKw should be the ideal fee-per-kw that will be used as a
+// starting point for close negotiation, and deliveryScript may optionally
+// specify the script our funds will be paid out to.
 func (s *Switch) CloseLink(chanPoint *wire.OutPoint,
-	closeType ChannelCloseType,
-	targetFeePerKw btcutil.Amount) (chan *lnrpc.CloseStatusUpdate, chan error) {
+	closeType ChannelCloseType, targetFeePerKw btcutil.Amount,
+	deliveryScript []byte) (chan *lnrpc.CloseStatusUpdate, chan error) {
 
 	// TODO(roasbeef) abstract out the close updates.
 	updateChan := make(chan *lnrpc.CloseStatusUpdate, 2)
@@ -754,6 +1260,7 @@ func (s *Switch) CloseLink(chanPoint *wire.OutPoint,
 		ChanPoint:      chanPoint,
 		Updates:        updateChan,
 		TargetFeePerKw: targetFeePerKw,
+		DeliveryScript: deliveryScript,
 		Err:            errChan,
 	}
 
@@ -782,7 +1289,8 @@ func (s *Switch) CloseLink(chanPoint *wire.OutPoint,
 func (s *Switch) htlcForwarder() {
 	defer s.wg.Done()
 
-	// Remove all links once we've been signalled for shutdown.
+	// Remove all links once we've been signalled for shutdown, and stop
+	// their mailboxes thereafter.
 	defer func() {
 		for _, link := range s.linkIndex {
 			if err := s.removeLink(link.ChanID()); err != nil {
@@ -790,6 +1298,9 @@ func (s *Switch) htlcForwarder() {
 					"channel link on stop: %v", err)
 			}
 		}
+		for _, mailBox := range s.mailBoxes {
+			mailBox.Stop()
+		}
 	}()
 
 	// TODO(roasbeef): cleared vs settled distinction
@@ -801,6 +1312,14 @@ func (s *Switch) htlcForwarder() {
 	logTicker := time.NewTicker(10 * time.Second)
 	defer logTicker.Stop()
 
+	// Any forwarding events still buffered once we've been signalled for
+	// shutdown are flushed before we exit.
+	fwdEventTicker := time.NewTicker(fwdEventFlushInterval)
+	defer fwdEventTicker.Stop()
+	if s.cfg.FwdingLog != nil {
+		defer s.flushForwardingEvents()
+	}
+
 	for {
 		select {
 		// A local close request has arrived, we'll forward this to the
@@ -861,6 +1380,13 @@ func (s *Switch) htlcForwarder() {
 		case cmd := <-s.htlcPlex:
 			cmd.err <- s.handlePacketForward(cmd.pkt)
 
+		// The forwarding event ticker has fired, so we'll write the
+		// events buffered since the last tick to the forwarding log.
+		case <-fwdEventTicker.C:
+			if s.cfg.FwdingLog != nil {
+				s.flushForwardingEvents()
+			}
+
 		// The log ticker has fired, so we'll calculate some forwarding
 		// stats for the last 10 seconds to display within the logs to
 		// users.
@@ -933,6 +1459,8 @@ func (s *Switch) htlcForwarder() {
 				cmd.err <- s.addLink(cmd.link)
 			case *removeLinkCmd:
 				cmd.err <- s.removeLink(cmd.chanID)
+			case *discardMailBoxCmd:
+				cmd.err <- s.discardMailBox(cmd.chanID)
 			case *getLinkCmd:
 				link, err := s.getLink(cmd.chanID)
 				cmd.done <- link
@@ -941,6 +1469,8 @@ func (s *Switch) htlcForwarder() {
 				links, err := s.getLinks(cmd.peer)
 				cmd.done <- links
 				cmd.err <- err
+			case *getAllLinksCmd:
+				cmd.done <- s.getAllLinks()
 			}
 
 		case <-s.quit:
@@ -958,6 +1488,20 @@ func (s *Switch) Start() error {
 
 	log.Infof("Starting HTLC Switch")
 
+	// Before any links are added, we'll restore the circuits of the HTLCs
+	// that were in flight when we last went down, so that their settles
+	// and fails can be propagated back.
+	if err := s.circuits.Restore(); err != nil {
+		return err
+	}
+
+	// Similarly, we'll restore the payments we initiated that were in
+	// flight, so that their results are recorded once their HTLCs are
+	// resolved.
+	if err := s.restorePaymentAttempts(); err != nil {
+		return err
+	}
+
 	s.wg.Add(1)
 	go s.htlcForwarder()
 
@@ -1021,6 +1565,11 @@ func (s *Switch) addLink(link ChannelLink) error {
 	}
 	s.interfaceIndex[peerPub][link] = struct{}{}
 
+	// Before starting the link, we'll attach the mailbox of its channel,
+	// so any packets buffered while a prior link was restarting are
+	// delivered to it.
+	link.AttachMailBox(s.getOrCreateMailBox(link))
+
 	if err := link.Start(); err != nil {
 		s.removeLink(link.ChanID())
 		return err
@@ -1123,6 +1672,116 @@ func (s *Switch) removeLink(chanID lnwire.ChannelID) error {
 	return nil
 }
 
+// getOrCreateMailBox returns the mailbox of the channel of the passed link,
+// creating it if the channel hasn't had a link added before. An existing
+// mailbox has its wire messages discarded, as they were meant for the prior
+// link of the channel, while its packets are retained.
+func (s *Switch) getOrCreateMailBox(link ChannelLink) *memoryMailBox {
+	chanID := link.ChanID()
+	mailBox, ok := s.mailBoxes[chanID]
+	if ok {
+		mailBox.ResetMessages()
+	} else {
+		mailBox = newBoundedMailBox(
+			s.cfg.MailBoxMaxMessages, s.cfg.MailBoxMaxPackets,
+		)
+		mailBox.Start()
+		s.mailBoxes[chanID] = mailBox
+	}
+
+	// The short channel ID may have changed since the mailbox was
+	// created, so we'll always index it anew.
+	s.mailBoxIndex[link.ShortChanID()] = chanID
+
+	return mailBox
+}
+
+// getMailBoxByShortID returns the mailbox of the channel with the passed short
+// channel ID, if any.
+func (s *Switch) getMailBoxByShortID(
+	chanID lnwire.ShortChannelID) (*memoryMailBox, bool) {
+
+	fullChanID, ok := s.mailBoxIndex[chanID]
+	if !ok {
+		return nil, false
+	}
+	mailBox, ok := s.mailBoxes[fullChanID]
+
+	return mailBox, ok
+}
+
+// discardMailBoxCmd is a discard mailbox command wrapper, it is used to
+// propagate handler parameters and return handler error.
+type discardMailBoxCmd struct {
+	chanID lnwire.ChannelID
+	err    chan error
+}
+
+// DiscardMailBox stops and discards the mailbox of the target channel, once
+// the channel has been closed and its link removed. Any HTLC adds still
+// queued within the mailbox are failed back, while settles and fails are
+// dropped, as the HTLCs they resolve are resolved on-chain instead. The
+// request will be propagated and handled in the main goroutine.
+func (s *Switch) DiscardMailBox(chanID lnwire.ChannelID) error {
+	command := &discardMailBoxCmd{
+		chanID: chanID,
+		err:    make(chan error, 1),
+	}
+
+	select {
+	case s.linkControl <- command:
+		return <-command.err
+	case <-s.quit:
+		return errors.New("unable to discard mailbox htlc switch " +
+			"was stopped")
+	}
+}
+
+// discardMailBox stops and discards the mailbox of the target channel,
+// failing back any HTLC adds queued within it.
+func (s *Switch) discardMailBox(chanID lnwire.ChannelID) error {
+	if _, ok := s.linkIndex[chanID]; ok {
+		return errors.Errorf("unable to discard mailbox of active "+
+			"channel %v", chanID)
+	}
+
+	mailBox, ok := s.mailBoxes[chanID]
+	if !ok {
+		return nil
+	}
+
+	log.Infof("Discarding mailbox of ChannelID(%v)", chanID)
+
+	delete(s.mailBoxes, chanID)
+	for shortChanID, id := range s.mailBoxIndex {
+		if id == chanID {
+			delete(s.mailBoxIndex, shortChanID)
+		}
+	}
+
+	mailBox.Stop()
+	for _, pkt := range mailBox.DrainPackets() {
+		htlc, ok := pkt.htlc.(*lnwire.UpdateAddHTLC)
+		if !ok {
+			continue
+		}
+
+		failPkt, err := newAddFailPacket(
+			pkt, htlc, &lnwire.FailPermanentChannelFailure{},
+		)
+		if err != nil {
+			log.Errorf("unable to fail htlc(%x): %v",
+				htlc.PaymentHash[:], err)
+			continue
+		}
+
+		s.traceAdd(pkt, TraceFailed, pkt.outgoingChanID)
+		go s.forward(failPkt)
+	}
+
+	return nil
+}
+
 // getLinksCmd is a get links command wrapper, it is used to propagate handler
 // parameters and return handler error.
 type getLinksCmd struct {
@@ -1165,6 +1824,67 @@ func (s *Switch) getLinks(destination [33]byte) ([]ChannelLink, error) {
 	return channelLinks, nil
 }
 
+// getAllLinksCmd is a command wrapper used to fetch all the links managed by
+// the switch.
+type getAllLinksCmd struct {
+	done chan []ChannelLink
+}
+
+// Links returns all of the channel links currently managed by the switch.
+func (s *Switch) Links() ([]ChannelLink, error) {
+	command := &getAllLinksCmd{
+		done: make(chan []ChannelLink, 1),
+	}
+
+	select {
+	case s.linkControl <- command:
+		return <-command.done, nil
+	case <-s.quit:
+		return nil, errors.New("unable to get links htlc switch was stopped")
+	}
+}
+
+// getAllLinks returns all of the channel links currently managed by the
+// switch.
+func (s *Switch) getAllLinks() []ChannelLink {
+	links := make([]ChannelLink, 0, len(s.linkIndex))
+	for _, link := range s.linkIndex {
+		links = append(links, link)
+	}
+
+	return links
+}
+
+// Circuits returns a copy of each of the payment circuits currently held by
+// the switch.
+func (s *Switch) Circuits() []*PaymentCircuit {
+	return s.circuits.Circuits()
+}
+
+// LookupCircuitsByHash returns a copy of each of the payment circuits held by
+// the switch for the HTLCs paying to the passed payment hash.
+func (s *Switch) LookupCircuitsByHash(hash [32]byte) []*PaymentCircuit {
+	return s.circuits.LookupByPaymentHash(hash)
+}
+
+// LookupCircuit returns a copy of the payment circuit held by the switch whose
+// outgoing, or otherwise incoming, HTLC is identified by the passed channel
+// and HTLC IDs. Nil is returned if there's no such circuit.
+func (s *Switch) LookupCircuit(chanID lnwire.ShortChannelID,
+	htlcID uint64) *PaymentCircuit {
+
+	circuit := s.circuits.LookupByHTLC(chanID, htlcID)
+	if circuit == nil {
+		circuit = s.circuits.LookupByIncoming(chanID, htlcID)
+	}
+	if circuit == nil {
+		return nil
+	}
+
+	c := *circuit
+	return &c
+}
+
 // removePendingPayment is the helper function which removes the pending user
 // payment.
 func (s *Switch) removePendingPayment(paymentID uint64) error {
@@ -1199,7 +1919,179 @@ func (s *Switch) numPendingPayments() int {
 	return len(s.pendingPayments)
 }
 
-// addCircuit adds a circuit to the switch's in-memory mapping.
-func (s *Switch) addCircuit(circuit *PaymentCircuit) {
-	s.circuits.Add(circuit)
+// openCircuit opens a circuit within the switch's circuit map, persisting it
+// if its HTLC was forwarded. If the HTLC belongs to a payment we initiated,
+// then its outgoing HTLC is recorded within the payment's attempt.
+func (s *Switch) openCircuit(circuit *PaymentCircuit) error {
+	if err := s.circuits.Open(circuit); err != nil {
+		return err
+	}
+
+	if circuit.isForwarded() || s.cfg.PaymentStore == nil {
+		return nil
+	}
+
+	payment, err := s.findPayment(circuit.IncomingHTLCID)
+	if err != nil || payment.attempt == nil {
+		return nil
+	}
+
+	return s.recordAttemptHTLC(payment.attempt, circuit)
+}
+
+// resolveCircuit looks up the circuit of the outgoing HTLC settled or failed
+// by the passed packet, and closes it, as the HTLC is about to be completed.
+// Pipelined settles leave the circuit open, so that the HTLC can still be
+// resolved should we go down before the settle is locked in. As such a settle
+// reaches the switch a second time once locked in, forward is false if the
+// settle of the HTLC has already been forwarded.
+func (s *Switch) resolveCircuit(packet *htlcPacket) (*PaymentCircuit, bool) {
+	key := circuitKey{
+		chanID: packet.outgoingChanID,
+		htlcID: packet.outgoingHTLCID,
+	}
+
+	s.pipelineMtx.Lock()
+	defer s.pipelineMtx.Unlock()
+
+	circuit := s.circuits.LookupByHTLC(key.chanID, key.htlcID)
+	if circuit == nil {
+		return nil, true
+	}
+
+	_, forwarded := s.pipelinedSettles[key]
+	if packet.isPipelined {
+		s.pipelinedSettles[key] = struct{}{}
+		return circuit, !forwarded
+	}
+	delete(s.pipelinedSettles, key)
+
+	s.forgetReplayedAdd(circuit)
+	err := s.circuits.Close(key.chanID, key.htlcID)
+	if err != nil {
+		log.Warnf("Failed to close completed onion circuit for %x: "+
+			"(%s, %d) <-> (%s, %d)", circuit.PaymentHash,
+			circuit.IncomingChanID, circuit.IncomingHTLCID,
+			circuit.OutgoingChanID, circuit.OutgoingHTLCID)
+	} else {
+		log.Debugf("Closed completed onion circuit for %x: "+
+			"(%s, %d) <-> (%s, %d)", circuit.PaymentHash,
+			circuit.IncomingChanID, circuit.IncomingHTLCID,
+			circuit.OutgoingChanID, circuit.OutgoingHTLCID)
+	}
+
+	return circuit, !forwarded
+}
+
+// removeCircuit removes the circuit of an HTLC which couldn't be offered
+// after its circuit was opened.
+func (s *Switch) removeCircuit(circuit *PaymentCircuit) error {
+	s.forgetReplayedAdd(circuit)
+
+	return s.circuits.Close(circuit.OutgoingChanID, circuit.OutgoingHTLCID)
+}
+
+// forgetReplayedAdd removes the passed circuit's incoming HTLC from the set
+// of those whose Add was replayed, as its circuit is being closed.
+func (s *Switch) forgetReplayedAdd(circuit *PaymentCircuit) {
+	s.replayMtx.Lock()
+	delete(s.replayedAdds, circuit.incomingKey())
+	s.replayMtx.Unlock()
+}
+
+// addReplayed returns whether the Add of the passed trimmed circuit's incoming
+// HTLC has already been replayed since we came back up, or will never be, as
+// it was acknowledged within the forwarding package of the incoming channel.
+// The replayMtx must be held.
+func (s *Switch) addReplayed(circuit *PaymentCircuit) (bool, error) {
+	key := circuit.incomingKey()
+	if _, ok := s.replayedAdds[key]; ok {
+		delete(s.replayedAdds, key)
+		return true, nil
+	}
+
+	if s.cfg.DB == nil {
+		return true, nil
+	}
+
+	return s.cfg.DB.AddAcked(circuit.IncomingChanID, circuit.IncomingHTLCID)
+}
+
+// trimOpenCircuits removes the stale circuits of the HTLCs offered over the
+// passed channel which were never signed for, and were therefore forgotten
+// when the channel was restored. The HTLCs with an ID of at least start are
+// considered stale. Their incoming HTLCs are failed back, if the incoming
+// link is active, but only once their Add won't be replayed by the incoming
+// link. Otherwise, the replayed Add is forwarded anew.
+func (s *Switch) trimOpenCircuits(chanID lnwire.ShortChannelID,
+	start uint64) error {
+
+	s.replayMtx.Lock()
+	defer s.replayMtx.Unlock()
+
+	trimmed, err := s.circuits.TrimOpenCircuits(chanID, start)
+	if err != nil {
+		return err
+	}
+
+	// The payments we initiated before going down, whose HTLCs were
+	// forgotten, have failed.
+	s.trimRestoredAttempts(chanID, start)
+
+	for _, circuit := range trimmed {
+		log.Infof("Trimmed stale circuit for %x: (%s, %d) <-> (%s, %d)",
+			circuit.PaymentHash, circuit.IncomingChanID,
+			circuit.IncomingHTLCID, circuit.OutgoingChanID,
+			circuit.OutgoingHTLCID)
+
+		if !circuit.isForwarded() || circuit.ErrorEncrypter == nil {
+			continue
+		}
+
+		replayed, err := s.addReplayed(circuit)
+		if err != nil {
+			log.Errorf("Unable to determine whether the add of "+
+				"trimmed htlc (%s, %d) will be replayed: %v",
+				circuit.IncomingChanID, circuit.IncomingHTLCID,
+				err)
+			continue
+		}
+		if !replayed {
+			log.Infof("Leaving trimmed htlc (%s, %d) to be "+
+				"forwarded again once its add is replayed",
+				circuit.IncomingChanID, circuit.IncomingHTLCID)
+			continue
+		}
+
+		source, err := s.getLinkByShortID(circuit.IncomingChanID)
+		if err != nil {
+			log.Warnf("Unable to fail back trimmed htlc (%s, %d): %v",
+				circuit.IncomingChanID, circuit.IncomingHTLCID,
+				err)
+			continue
+		}
+
+		failure := lnwire.NewTemporaryChannelFailure(nil)
+		reason, err := circuit.ErrorEncrypter.EncryptFirstHop(failure)
+		if err != nil {
+			log.Errorf("Unable to obfuscate error: %v", err)
+			continue
+		}
+
+		s.trace(
+			circuit.TraceID, circuit.PaymentHash, TraceFailed,
+			circuit.IncomingChanID,
+		)
+		source.HandleSwitchPacket(&htlcPacket{
+			incomingChanID: circuit.IncomingChanID,
+			incomingHTLCID: circuit.IncomingHTLCID,
+			isRouted:       true,
+			htlc: &lnwire.UpdateFailHTLC{
+				Reason: reason,
+			},
+			traceID: circuit.TraceID,
+		})
+	}
+
+	return nil
 }
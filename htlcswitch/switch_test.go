@@ -3,14 +3,18 @@ package htlcswitch
 import (
 	"bytes"
 	"crypto/sha256"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/btcsuite/fastsha256"
+	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
 )
 
 var (
@@ -111,6 +115,735 @@ func TestSwitchForward(t *testing.T) {
 	}
 }
 
+// TestSwitchMailBoxRetained checks that settles destined to a link which is
+// restarting are buffered within its mailbox, and delivered to the link that
+// replaces it, and that HTLC adds still queued within the mailbox of a closed
+// channel are failed back once it's discarded.
+func TestSwitchMailBoxRetained(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	s := New(Config{})
+	s.Start()
+	defer s.Stop()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	preimage := [sha256.Size]byte{1}
+	rhash := fastsha256.Sum256(preimage[:])
+	packet := &htlcPacket{
+		incomingChanID: aliceChannelLink.ShortChanID(),
+		incomingHTLCID: 0,
+		outgoingChanID: bobChannelLink.ShortChanID(),
+		obfuscator:     newMockObfuscator(),
+		htlc: &lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      1,
+		},
+	}
+	if err := s.forward(packet); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-bobChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+
+	// We'll now remove Alice's link, as would happen when it's restarted,
+	// before Bob settles the HTLC. The settle should be buffered rather
+	// than dropped.
+	aliceMailBox := aliceChannelLink.mailBox
+	if err := s.RemoveLink(chanID1); err != nil {
+		t.Fatalf("unable to remove alice link: %v", err)
+	}
+	packet = &htlcPacket{
+		outgoingChanID: bobChannelLink.ShortChanID(),
+		outgoingHTLCID: 0,
+		amount:         1,
+		htlc: &lnwire.UpdateFufillHTLC{
+			PaymentPreimage: preimage,
+		},
+	}
+	if err := s.forward(packet); err != nil {
+		t.Fatalf("unable to buffer settle: %v", err)
+	}
+
+	// Once Alice's link has been restarted, the new link should be
+	// attached to the same mailbox, and receive the settle.
+	newAliceLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	if err := s.AddLink(newAliceLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if newAliceLink.mailBox != aliceMailBox {
+		t.Fatalf("mailbox of alice link wasn't retained")
+	}
+	select {
+	case pkt := <-aliceMailBox.PacketOutBox():
+		if _, ok := pkt.htlc.(*lnwire.UpdateFufillHTLC); !ok {
+			t.Fatalf("expected settle, got %T", pkt.htlc)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("settle was not delivered to restarted link")
+	}
+
+	// The mailbox of an active link can't be discarded.
+	if err := s.DiscardMailBox(chanID2); err == nil {
+		t.Fatalf("mailbox of active link discarded")
+	}
+
+	// Finally, we'll close Bob's channel with an HTLC add still queued
+	// within its mailbox, which should be failed back to Alice once the
+	// mailbox is discarded.
+	bobMailBox := bobChannelLink.mailBox
+	if err := s.RemoveLink(chanID2); err != nil {
+		t.Fatalf("unable to remove bob link: %v", err)
+	}
+	err := bobMailBox.AddPacket(&htlcPacket{
+		incomingChanID: newAliceLink.ShortChanID(),
+		incomingHTLCID: 1,
+		outgoingChanID: bobChannelLink.ShortChanID(),
+		obfuscator:     newMockObfuscator(),
+		htlc: &lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      1,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add packet: %v", err)
+	}
+	if err := s.DiscardMailBox(chanID2); err != nil {
+		t.Fatalf("unable to discard bob mailbox: %v", err)
+	}
+	select {
+	case pkt := <-newAliceLink.packets:
+		if _, ok := pkt.htlc.(*lnwire.UpdateFailHTLC); !ok {
+			t.Fatalf("expected fail, got %T", pkt.htlc)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("add was not failed back")
+	}
+}
+
+// TestSwitchFaultInjection checks that the faults injected into the
+// forwarding path of the switch are applied to the matching packets, in the
+// order they were injected, and that a duplicated add isn't forwarded twice.
+func TestSwitchFaultInjection(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	faults := NewFaultInjector()
+	s := New(Config{FaultInjector: faults})
+	s.Start()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	rules := []FaultRule{
+		{
+			Action:  FaultDuplicate,
+			MsgType: lnwire.MsgUpdateAddHTLC,
+			Count:   1,
+		},
+		{
+			Action:  FaultDrop,
+			MsgType: lnwire.MsgUpdateFufillHTLC,
+			Count:   1,
+		},
+		{
+			Action:  FaultDelay,
+			MsgType: lnwire.MsgUpdateFufillHTLC,
+			Count:   1,
+			Delay:   100 * time.Millisecond,
+		},
+	}
+	for _, rule := range rules {
+		if err := faults.Inject(rule); err != nil {
+			t.Fatalf("unable to inject fault: %v", err)
+		}
+	}
+	err := faults.Inject(FaultRule{
+		Action:  FaultDrop,
+		MsgType: lnwire.MsgUpdateFee,
+		Count:   1,
+	})
+	if err == nil {
+		t.Fatalf("expected fault for update_fee to be rejected")
+	}
+
+	assertReceived := func(link *mockChannelLink, received bool) {
+		select {
+		case <-link.packets:
+			if !received {
+				t.Fatalf("unexpected packet received")
+			}
+		case <-time.After(time.Second):
+			if received {
+				t.Fatalf("packet wasn't received")
+			}
+		}
+	}
+
+	// The add should be forwarded to Bob, but its duplicate should be
+	// ignored, as a circuit is already open for the incoming HTLC.
+	preimage := [sha256.Size]byte{1}
+	rhash := fastsha256.Sum256(preimage[:])
+	packet := &htlcPacket{
+		incomingChanID: aliceChannelLink.ShortChanID(),
+		incomingHTLCID: 0,
+		outgoingChanID: bobChannelLink.ShortChanID(),
+		obfuscator:     newMockObfuscator(),
+		htlc: &lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      1,
+		},
+	}
+	if err := s.forward(packet); err != nil {
+		t.Fatal(err)
+	}
+	assertReceived(bobChannelLink, true)
+	assertReceived(bobChannelLink, false)
+	if s.circuits.pending() != 1 {
+		t.Fatal("wrong amount of circuits")
+	}
+
+	// The first settle should be dropped, leaving the circuit open.
+	settle := func() *htlcPacket {
+		return &htlcPacket{
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			outgoingHTLCID: 0,
+			amount:         1,
+			htlc: &lnwire.UpdateFufillHTLC{
+				PaymentPreimage: preimage,
+			},
+		}
+	}
+	if err := s.forward(settle()); err != nil {
+		t.Fatal(err)
+	}
+	assertReceived(aliceChannelLink, false)
+	if s.circuits.pending() != 1 {
+		t.Fatal("wrong amount of circuits")
+	}
+
+	// The second should be delayed, but still reach Alice.
+	start := time.Now()
+	if err := s.forward(settle()); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < rules[2].Delay {
+		t.Fatalf("settle wasn't delayed")
+	}
+	assertReceived(aliceChannelLink, true)
+	if s.circuits.pending() != 0 {
+		t.Fatal("wrong amount of circuits")
+	}
+
+	if faults.Pending() != 0 {
+		t.Fatalf("expected all faults to be applied, %v remain",
+			faults.Pending())
+	}
+}
+
+// mockTraceExporter is a TraceExporter which records the exported events.
+type mockTraceExporter struct {
+	events chan TraceEvent
+}
+
+func (m *mockTraceExporter) ExportTraceEvent(event TraceEvent) {
+	m.events <- event
+}
+
+// TestSwitchTrace checks that a forwarded HTLC is traced through the switch
+// under a single trace ID, which is carried over to the settle that resolves
+// it.
+func TestSwitchTrace(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	exporter := &mockTraceExporter{events: make(chan TraceEvent, 10)}
+	s := New(Config{TraceExporter: exporter})
+	s.Start()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	preimage := [sha256.Size]byte{1}
+	rhash := fastsha256.Sum256(preimage[:])
+	traceID := newTraceID()
+	packet := &htlcPacket{
+		incomingChanID: aliceChannelLink.ShortChanID(),
+		incomingHTLCID: 0,
+		outgoingChanID: bobChannelLink.ShortChanID(),
+		obfuscator:     newMockObfuscator(),
+		htlc: &lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      1,
+		},
+		traceID: traceID,
+	}
+	if err := s.forward(packet); err != nil {
+		t.Fatalf("unable to forward htlc: %v", err)
+	}
+	select {
+	case <-bobChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatalf("request was not propagated to destination")
+	}
+
+	// Once Bob settles the HTLC, the settle should be traced under the
+	// trace ID of the original HTLC.
+	packet = &htlcPacket{
+		outgoingChanID: bobChannelLink.ShortChanID(),
+		outgoingHTLCID: 0,
+		amount:         1,
+		htlc: &lnwire.UpdateFufillHTLC{
+			PaymentPreimage: preimage,
+		},
+	}
+	if err := s.forward(packet); err != nil {
+		t.Fatalf("unable to forward settle: %v", err)
+	}
+
+	var pkt *htlcPacket
+	select {
+	case pkt = <-aliceChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatalf("settle was not propagated to alice")
+	}
+	if pkt.traceID != traceID {
+		t.Fatalf("expected settle to carry trace id %v, got %v",
+			traceID, pkt.traceID)
+	}
+
+	select {
+	case event := <-exporter.events:
+		if event.TraceID != traceID || event.Stage != TraceSettled ||
+			event.PaymentHash != rhash ||
+			event.ChanID != aliceChannelLink.ShortChanID() {
+
+			t.Fatalf("unexpected trace event: %v", spew.Sdump(event))
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("settle trace event wasn't exported")
+	}
+}
+
+// mockForwardingLog is a ForwardingLog which hands the events written to it
+// over a channel.
+type mockForwardingLog struct {
+	events chan []channeldb.ForwardingEvent
+}
+
+func (m *mockForwardingLog) AddForwardingEvents(
+	events []channeldb.ForwardingEvent) error {
+
+	m.events <- events
+	return nil
+}
+
+// TestSwitchForwardingLog checks that a successfully forwarded HTLC is
+// recorded to the forwarding log along with its amounts, while a failed
+// forward isn't, and that buffered events are flushed on shutdown.
+func TestSwitchForwardingLog(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	fwdLog := &mockForwardingLog{
+		events: make(chan []channeldb.ForwardingEvent, 1),
+	}
+	s := New(Config{FwdingLog: fwdLog})
+	s.Start()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	// Forward two HTLCs from Alice to Bob, the first of which Bob will
+	// fail, and the second of which he'll settle.
+	for i := uint64(0); i < 2; i++ {
+		preimage := [sha256.Size]byte{byte(i)}
+		packet := &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: i,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			incomingAmount: 1010,
+			amount:         1000,
+			obfuscator:     newMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: fastsha256.Sum256(preimage[:]),
+				Amount:      1000,
+			},
+		}
+		if err := s.forward(packet); err != nil {
+			t.Fatalf("unable to forward htlc: %v", err)
+		}
+		select {
+		case <-bobChannelLink.packets:
+		case <-time.After(time.Second):
+			t.Fatalf("request was not propagated to destination")
+		}
+	}
+
+	resolutions := []lnwire.Message{
+		&lnwire.UpdateFailHTLC{},
+		&lnwire.UpdateFufillHTLC{PaymentPreimage: [sha256.Size]byte{1}},
+	}
+	for i, htlc := range resolutions {
+		packet := &htlcPacket{
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			outgoingHTLCID: uint64(i),
+			amount:         1000,
+			htlc:           htlc,
+		}
+		if err := s.forward(packet); err != nil {
+			t.Fatalf("unable to forward resolution: %v", err)
+		}
+		select {
+		case <-aliceChannelLink.packets:
+		case <-time.After(time.Second):
+			t.Fatalf("resolution was not propagated to alice")
+		}
+	}
+
+	// The event of the settled HTLC should be buffered until the switch
+	// is stopped.
+	select {
+	case events := <-fwdLog.events:
+		t.Fatalf("events flushed early: %v", spew.Sdump(events))
+	default:
+	}
+	if err := s.Stop(); err != nil {
+		t.Fatalf("unable to stop switch: %v", err)
+	}
+
+	var events []channeldb.ForwardingEvent
+	select {
+	case events = <-fwdLog.events:
+	case <-time.After(time.Second):
+		t.Fatalf("forwarding events weren't flushed")
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 forwarding event, got %v", len(events))
+	}
+	event := events[0]
+	if event.IncomingChanID != aliceChannelLink.ShortChanID() ||
+		event.OutgoingChanID != bobChannelLink.ShortChanID() ||
+		event.AmtIn != 1010 || event.AmtOut != 1000 {
+
+		t.Fatalf("unexpected forwarding event: %v", spew.Sdump(event))
+	}
+	if event.Fee() != 10 {
+		t.Fatalf("expected fee of 10, got %v", event.Fee())
+	}
+}
+
+// TestSwitchForwardAlias checks that an HTLC which specifies an unknown
+// outgoing channel is re-targeted to the link of the channel that the unknown
+// channel is an alias of, rather than being failed.
+func TestSwitchForwardAlias(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	s := New(Config{})
+	s.Start()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	// We'll create a request which should be forwarded from Alice to a
+	// channel that the switch isn't aware of.
+	bobAliasChanID := lnwire.NewShortChanIDFromInt(3)
+	preimage := [sha256.Size]byte{1}
+	rhash := fastsha256.Sum256(preimage[:])
+	newPacket := func() *htlcPacket {
+		return &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: 0,
+			outgoingChanID: bobAliasChanID,
+			obfuscator:     newMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			},
+		}
+	}
+
+	// Without an alias for the channel, the forward should fail.
+	if err := s.forward(newPacket()); err == nil {
+		t.Fatalf("forward to unknown channel should have failed")
+	}
+	select {
+	case <-aliceChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatal("failure was not sent back to alice")
+	}
+
+	// Once the alias has been registered, the HTLC should be re-targeted
+	// to Bob's link.
+	s.AddForwardingAlias(bobAliasChanID, bobChanID)
+	aliases := s.ForwardingAliases()
+	if len(aliases) != 1 || aliases[bobAliasChanID] != bobChanID {
+		t.Fatalf("unexpected forwarding aliases: %v", aliases)
+	}
+	if err := s.forward(newPacket()); err != nil {
+		t.Fatalf("unable to forward to alias: %v", err)
+	}
+	select {
+	case <-bobChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+
+	// Finally, after removing the alias, the same outcome should be
+	// achieved by consulting the RetargetForward policy.
+	s.RemoveForwardingAlias(bobAliasChanID)
+	if aliases := s.ForwardingAliases(); len(aliases) != 0 {
+		t.Fatalf("unexpected forwarding aliases: %v", aliases)
+	}
+	s.cfg.RetargetForward = func(
+		chanID lnwire.ShortChannelID) (lnwire.ShortChannelID, bool) {
+
+		return bobChanID, chanID == bobAliasChanID
+	}
+	if err := s.forward(newPacket()); err != nil {
+		t.Fatalf("unable to forward with retarget policy: %v", err)
+	}
+	select {
+	case <-bobChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+}
+
+// TestSwitchForwardingFilter checks that forwards arriving from, or destined
+// to, a peer that isn't permitted by the forwarding filter are failed back to
+// the source link.
+func TestSwitchForwardingFilter(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	s := New(Config{})
+	s.Start()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	preimage := [sha256.Size]byte{1}
+	rhash := fastsha256.Sum256(preimage[:])
+	newPacket := func() *htlcPacket {
+		return &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: 0,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			obfuscator:     newMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			},
+		}
+	}
+
+	// assertForward attempts a forward from Alice to Bob, and asserts that
+	// it's either propagated to Bob, or failed back to Alice.
+	assertForward := func(permitted bool) {
+		err := s.forward(newPacket())
+		if permitted && err != nil {
+			t.Fatalf("unable to forward htlc: %v", err)
+		}
+		if !permitted && err == nil {
+			t.Fatalf("forward should have been rejected")
+		}
+
+		target := bobChannelLink.packets
+		if !permitted {
+			target = aliceChannelLink.packets
+		}
+		select {
+		case <-target:
+		case <-time.After(time.Second):
+			t.Fatalf("packet wasn't sent to expected link")
+		}
+	}
+
+	// With an empty filter, the forward should succeed.
+	assertForward(true)
+
+	// Once Bob is denied, forwards destined to him should fail.
+	filter := s.ForwardingFilter()
+	filter.Deny(bobPeer.PubKey())
+	assertForward(false)
+
+	// Adding Bob to the allow list removes him from the deny list,
+	// however as Alice isn't allowed, forwards from her should fail.
+	filter.Allow(bobPeer.PubKey())
+	assertForward(false)
+
+	// Once Alice is allowed as well, the forward should succeed again.
+	filter.Allow(alicePeer.PubKey())
+	assertForward(true)
+
+	allowed, denied := filter.Lists()
+	if len(allowed) != 2 || len(denied) != 0 {
+		t.Fatalf("expected 2 allowed and 0 denied peers, got %v and %v",
+			len(allowed), len(denied))
+	}
+}
+
+// TestSwitchForwardRetry tests that a forward which a link failed to add is
+// retried on the remaining links to the same peer, and is only failed back
+// once all of them have been attempted.
+func TestSwitchForwardRetry(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	s := New(Config{})
+	s.Start()
+
+	chanPoint3 := wire.NewOutPoint(hash2, 1)
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink1 := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	bobChannelLink2 := newMockChannelLink(
+		s, lnwire.NewChanIDFromOutPoint(chanPoint3),
+		lnwire.NewShortChanIDFromInt(3), bobPeer, true,
+	)
+	for _, link := range []*mockChannelLink{
+		aliceChannelLink, bobChannelLink1, bobChannelLink2,
+	} {
+		if err := s.AddLink(link); err != nil {
+			t.Fatalf("unable to add link: %v", err)
+		}
+	}
+
+	preimage := [sha256.Size]byte{1}
+	rhash := fastsha256.Sum256(preimage[:])
+	newPacket := func(attempted ...*mockChannelLink) *htlcPacket {
+		pkt := &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: 0,
+			outgoingChanID: bobChannelLink1.ShortChanID(),
+			obfuscator:     newMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			},
+			attemptedLinks: make(map[lnwire.ShortChannelID]struct{}),
+		}
+		for _, link := range attempted {
+			pkt.attemptedLinks[link.ShortChanID()] = struct{}{}
+		}
+
+		return pkt
+	}
+
+	// If the add failed on Bob's first link, then the forward should be
+	// retried on his second link.
+	if err := s.forward(newPacket(bobChannelLink1)); err != nil {
+		t.Fatalf("unable to forward htlc: %v", err)
+	}
+	select {
+	case <-bobChannelLink2.packets:
+	case <-time.After(time.Second):
+		t.Fatalf("packet wasn't retried on bob's second link")
+	}
+
+	// Once the add has failed on both of Bob's links, the HTLC should be
+	// failed back to Alice.
+	err := s.forward(newPacket(bobChannelLink1, bobChannelLink2))
+	if err == nil {
+		t.Fatalf("forward should have failed once all links were " +
+			"attempted")
+	}
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		if _, ok := pkt.htlc.(*lnwire.UpdateFailHTLC); !ok {
+			t.Fatalf("expected fail packet, got %T", pkt.htlc)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("htlc wasn't failed back to alice")
+	}
+}
+
 // TestSkipIneligibleLinksMultiHopForward tests that if a multi-hop HTLC comes
 // along, then we won't attempt to froward it down al ink that isn't yet able
 // to forward any HTLC's.
@@ -542,3 +1275,492 @@ func TestSwitchSendPayment(t *testing.T) {
 		t.Fatal("wrong amount of pending payments")
 	}
 }
+
+// mockPaymentStore is an in-memory PaymentStore.
+type mockPaymentStore struct {
+	mtx      sync.Mutex
+	attempts map[[32]byte]channeldb.PaymentAttempt
+}
+
+func newMockPaymentStore() *mockPaymentStore {
+	return &mockPaymentStore{
+		attempts: make(map[[32]byte]channeldb.PaymentAttempt),
+	}
+}
+
+func (m *mockPaymentStore) PutPaymentAttempt(
+	attempt *channeldb.PaymentAttempt) error {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.attempts[attempt.PaymentHash] = *attempt
+	return nil
+}
+
+func (m *mockPaymentStore) FetchPaymentAttempt(
+	hash [32]byte) (*channeldb.PaymentAttempt, error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	attempt, ok := m.attempts[hash]
+	if !ok {
+		return nil, channeldb.ErrPaymentAttemptNotFound
+	}
+	return &attempt, nil
+}
+
+func (m *mockPaymentStore) FetchInFlightPaymentAttempts() (
+	[]*channeldb.PaymentAttempt, error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	var inFlight []*channeldb.PaymentAttempt
+	for _, attempt := range m.attempts {
+		if attempt.Status == channeldb.AttemptInFlight {
+			a := attempt
+			inFlight = append(inFlight, &a)
+		}
+	}
+	return inFlight, nil
+}
+
+// TestSwitchPaymentDeduplication checks that sending a payment whose hash is
+// already in flight awaits the result of the existing attempt rather than
+// dispatching another HTLC, that sending a payment which has succeeded
+// returns its preimage, and that attempts in flight across a restart are
+// resolved once their HTLC is settled.
+func TestSwitchPaymentDeduplication(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	store := newMockPaymentStore()
+
+	s := New(Config{PaymentStore: store})
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add link: %v", err)
+	}
+
+	preimage := [sha256.Size]byte{1}
+	rhash := fastsha256.Sum256(preimage[:])
+	update := &lnwire.UpdateAddHTLC{
+		PaymentHash: rhash,
+		Amount:      1,
+	}
+
+	type result struct {
+		preimage [sha256.Size]byte
+		err      error
+	}
+	results := make(chan result, 2)
+	sendPayment := func(s *Switch) {
+		go func() {
+			preimage, err := s.SendHTLC(
+				alicePeer.PubKey(), update,
+				newMockDeobfuscator(),
+			)
+			results <- result{preimage, err}
+		}()
+	}
+	assertResult := func() {
+		select {
+		case r := <-results:
+			if r.err != nil {
+				t.Fatalf("unable to send payment: %v", r.err)
+			}
+			if r.preimage != preimage {
+				t.Fatalf("expected preimage %x, got %x",
+					preimage[:], r.preimage[:])
+			}
+		case <-time.After(time.Second):
+			t.Fatal("payment result wasn't received")
+		}
+	}
+
+	// Sending the payment should dispatch its HTLC, while sending it again
+	// while it's in flight should not.
+	sendPayment(s)
+	select {
+	case <-aliceChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+
+	sendPayment(s)
+	select {
+	case <-aliceChannelLink.packets:
+		t.Fatal("duplicate payment was dispatched")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The attempt should be in flight, along with the HTLC it was
+	// dispatched as.
+	inFlight := s.InFlightPayments()
+	if len(inFlight) != 1 {
+		t.Fatalf("expected 1 payment in flight, got %v", len(inFlight))
+	}
+	if inFlight[0].OutgoingChanID != aliceChanID ||
+		inFlight[0].OutgoingHTLCID != 0 {
+
+		t.Fatalf("wrong outgoing htlc recorded: %v",
+			spew.Sdump(inFlight[0]))
+	}
+
+	// Once the HTLC is settled, both sends should receive the preimage.
+	settle := &htlcPacket{
+		outgoingChanID: aliceChanID,
+		outgoingHTLCID: 0,
+		htlc: &lnwire.UpdateFufillHTLC{
+			PaymentPreimage: preimage,
+		},
+	}
+	if err := s.forward(settle); err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	assertResult()
+	assertResult()
+
+	attempt, err := s.LookupPayment(rhash)
+	if err != nil {
+		t.Fatalf("unable to lookup payment: %v", err)
+	}
+	if attempt.Status != channeldb.AttemptSucceeded {
+		t.Fatalf("expected attempt to have succeeded, instead: %v",
+			attempt.Status)
+	}
+
+	// Sending the payment once more should return its preimage, without
+	// dispatching another HTLC.
+	sendPayment(s)
+	assertResult()
+	select {
+	case <-aliceChannelLink.packets:
+		t.Fatal("succeeded payment was dispatched")
+	default:
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Fatalf("unable to stop switch: %v", err)
+	}
+
+	// Now, we'll record an attempt as in flight across a restart of the
+	// switch. Sending the payment after the restart should await the
+	// result of the restored attempt, which is resolved by the settle of
+	// its HTLC, despite the HTLC having no circuit.
+	attempt.Status = channeldb.AttemptInFlight
+	attempt.OutgoingHTLCID = 5
+	if err := store.PutPaymentAttempt(attempt); err != nil {
+		t.Fatalf("unable to record attempt: %v", err)
+	}
+
+	s = New(Config{PaymentStore: store})
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+	defer s.Stop()
+
+	aliceChannelLink = newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add link: %v", err)
+	}
+
+	sendPayment(s)
+	select {
+	case <-aliceChannelLink.packets:
+		t.Fatal("restored payment was dispatched")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	settle.outgoingHTLCID = 5
+	if err := s.forward(settle); err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	assertResult()
+
+	if len(s.InFlightPayments()) != 0 {
+		t.Fatalf("expected no payments in flight")
+	}
+}
+
+// TestSwitchTrimOpenCircuits checks that upon trimming the stale circuits of a
+// restored channel, only the incoming HTLCs whose Add won't be replayed are
+// failed back, namely those whose Add was acknowledged, or was replayed and
+// ignored as its circuit was still open. The others are left to be forwarded
+// again once their Add is replayed.
+func TestSwitchTrimOpenCircuits(t *testing.T) {
+	t.Parallel()
+
+	// We'll lock in three HTLCs offered by Bob to Alice, so that they're
+	// held within the forwarding package of Alice's channel.
+	aliceChannel, bobChannel, cleanUp, _, err := createTestChannel(
+		alicePrivKey, bobPrivKey, btcutil.SatoshiPerBitcoin,
+		btcutil.SatoshiPerBitcoin, aliceChanID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	defer cleanUp()
+
+	htlcs := make([]*lnwire.UpdateAddHTLC, 3)
+	for i := range htlcs {
+		htlcs[i] = &lnwire.UpdateAddHTLC{
+			PaymentHash: sha256.Sum256([]byte{byte(i)}),
+			Amount:      lnwire.NewMSatFromSatoshis(10000),
+		}
+		if _, err := bobChannel.AddHTLC(htlcs[i]); err != nil {
+			t.Fatalf("unable to add htlc: %v", err)
+		}
+		if _, err := aliceChannel.ReceiveHTLC(htlcs[i]); err != nil {
+			t.Fatalf("unable to receive htlc: %v", err)
+		}
+	}
+	bobSig, bobHtlcSigs, err := bobChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("unable to sign commitment: %v", err)
+	}
+	err = aliceChannel.ReceiveNewCommitment(bobSig, bobHtlcSigs)
+	if err != nil {
+		t.Fatalf("unable to receive commitment: %v", err)
+	}
+	aliceRevocation, _, err := aliceChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("unable to revoke commitment: %v", err)
+	}
+	_, _, err = bobChannel.ReceiveRevocation(aliceRevocation)
+	if err != nil {
+		t.Fatalf("unable to receive revocation: %v", err)
+	}
+	aliceSig, aliceHtlcSigs, err := aliceChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("unable to sign commitment: %v", err)
+	}
+	err = bobChannel.ReceiveNewCommitment(aliceSig, aliceHtlcSigs)
+	if err != nil {
+		t.Fatalf("unable to receive commitment: %v", err)
+	}
+	bobRevocation, _, err := bobChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("unable to revoke commitment: %v", err)
+	}
+	fwdPkg, _, err := aliceChannel.ReceiveRevocation(bobRevocation)
+	if err != nil {
+		t.Fatalf("unable to receive revocation: %v", err)
+	}
+
+	// Only the first Add has been acknowledged, as if it was forwarded
+	// before we went down.
+	err = aliceChannel.AckAddHtlcs(channeldb.AddRef{
+		Height: fwdPkg.Height,
+		Index:  0,
+	})
+	if err != nil {
+		t.Fatalf("unable to ack add: %v", err)
+	}
+
+	s := New(Config{
+		DB: aliceChannel.State().Db,
+	})
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+	defer s.Stop()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, newMockServer(t, "alice"), true,
+	)
+	aliceChannelLink.packets = make(chan *htlcPacket, len(htlcs))
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, newMockServer(t, "bob"), true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	// Each of the HTLCs was forwarded to Bob's link, whose outgoing HTLCs
+	// were never signed for.
+	for i, htlc := range htlcs {
+		err := s.openCircuit(&PaymentCircuit{
+			PaymentHash:    htlc.PaymentHash,
+			IncomingChanID: aliceChanID,
+			IncomingHTLCID: uint64(i),
+			OutgoingChanID: bobChanID,
+			OutgoingHTLCID: uint64(i),
+			ErrorEncrypter: newMockObfuscator(),
+		})
+		if err != nil {
+			t.Fatalf("unable to open circuit: %v", err)
+		}
+	}
+
+	// The third Add is replayed by Alice's link before Bob's link trims
+	// its circuits, and is ignored as its circuit is still open.
+	err = s.handlePacketForward(&htlcPacket{
+		incomingChanID: aliceChanID,
+		incomingHTLCID: 2,
+		outgoingChanID: bobChanID,
+		obfuscator:     newMockObfuscator(),
+		htlc:           htlcs[2],
+	})
+	if err != nil {
+		t.Fatalf("unable to forward replayed add: %v", err)
+	}
+	select {
+	case <-bobChannelLink.packets:
+		t.Fatalf("replayed add was forwarded")
+	default:
+	}
+
+	if err := s.trimOpenCircuits(bobChanID, 0); err != nil {
+		t.Fatalf("unable to trim circuits: %v", err)
+	}
+	if s.circuits.pending() != 0 {
+		t.Fatalf("expected all circuits to be trimmed")
+	}
+
+	// Only the first and third HTLCs should be failed back, as the Add of
+	// the second will still be replayed.
+	failed := make(map[uint64]struct{})
+	for i := 0; i < 2; i++ {
+		select {
+		case pkt := <-aliceChannelLink.packets:
+			if _, ok := pkt.htlc.(*lnwire.UpdateFailHTLC); !ok {
+				t.Fatalf("expected fail, got %T", pkt.htlc)
+			}
+			failed[pkt.incomingHTLCID] = struct{}{}
+		case <-time.After(time.Second):
+			t.Fatalf("trimmed htlc wasn't failed back")
+		}
+	}
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		t.Fatalf("unexpected packet for htlc %v",
+			pkt.incomingHTLCID)
+	default:
+	}
+	for _, htlcID := range []uint64{0, 2} {
+		if _, ok := failed[htlcID]; !ok {
+			t.Fatalf("htlc %v wasn't failed back", htlcID)
+		}
+	}
+
+	// Once the second Add is replayed, it should be forwarded anew.
+	err = s.handlePacketForward(&htlcPacket{
+		incomingChanID: aliceChanID,
+		incomingHTLCID: 1,
+		outgoingChanID: bobChanID,
+		obfuscator:     newMockObfuscator(),
+		htlc:           htlcs[1],
+	})
+	if err != nil {
+		t.Fatalf("unable to forward replayed add: %v", err)
+	}
+	select {
+	case <-bobChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatalf("replayed add wasn't forwarded")
+	}
+}
+
+// TestSwitchPipelinedSettle checks that a settle pipelined by the outgoing
+// link is forwarded without closing the circuit of the HTLC, and that once
+// the settle is locked in, it only closes the circuit, so the incoming link
+// receives the settle exactly once.
+func TestSwitchPipelinedSettle(t *testing.T) {
+	t.Parallel()
+
+	s := New(Config{})
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+	defer s.Stop()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, newMockServer(t, "alice"), true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, newMockServer(t, "bob"), true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	preimage := [sha256.Size]byte{1}
+	err := s.openCircuit(&PaymentCircuit{
+		PaymentHash:    sha256.Sum256(preimage[:]),
+		IncomingChanID: aliceChanID,
+		IncomingHTLCID: 0,
+		OutgoingChanID: bobChanID,
+		OutgoingHTLCID: 0,
+		ErrorEncrypter: newMockObfuscator(),
+	})
+	if err != nil {
+		t.Fatalf("unable to open circuit: %v", err)
+	}
+
+	settle := func(pipelined bool) {
+		err := s.forward(&htlcPacket{
+			outgoingChanID: bobChanID,
+			outgoingHTLCID: 0,
+			isPipelined:    pipelined,
+			htlc: &lnwire.UpdateFufillHTLC{
+				PaymentPreimage: preimage,
+			},
+		})
+		if err != nil {
+			t.Fatalf("unable to forward settle: %v", err)
+		}
+	}
+	assertForwarded := func(forwarded bool) {
+		select {
+		case <-aliceChannelLink.packets:
+			if !forwarded {
+				t.Fatalf("settle forwarded twice")
+			}
+		case <-time.After(100 * time.Millisecond):
+			if forwarded {
+				t.Fatalf("settle wasn't forwarded")
+			}
+		}
+	}
+
+	// The pipelined settle should be forwarded, while leaving the circuit
+	// open.
+	settle(true)
+	assertForwarded(true)
+	if s.circuits.pending() != 1 {
+		t.Fatalf("expected circuit to remain open")
+	}
+
+	// A retransmission of the settle is pipelined a second time, which
+	// should be ignored.
+	settle(true)
+	assertForwarded(false)
+
+	// Once locked in, the settle should only close the circuit.
+	settle(false)
+	assertForwarded(false)
+	if s.circuits.pending() != 0 {
+		t.Fatalf("expected circuit to be closed")
+	}
+
+	// A settle pipelined after the circuit was closed should be ignored.
+	settle(true)
+	assertForwarded(false)
+}
@@ -11,8 +11,6 @@ import (
 	"io/ioutil"
 	"os"
 
-	"io"
-
 	"math/big"
 
 	"net"
@@ -571,7 +569,7 @@ func (n *threeHopNetwork) makePayment(sendingPeer, receivingPeer Peer,
 	rhash = fastsha256.Sum256(invoice.Terms.PaymentPreimage[:])
 
 	// Check who is last in the route and add invoice to server registry.
-	if err := receiver.registry.AddInvoice(*invoice); err != nil {
+	if err := receiver.registry.AddInvoice(invoice); err != nil {
 		paymentErr <- err
 		return &paymentResponse{
 			rhash: rhash,
@@ -715,10 +713,6 @@ func newThreeHopNetwork(t testing.TB, aliceChannel, firstBobChannel,
 	bobServer := newMockServer(t, "bob")
 	carolServer := newMockServer(t, "carol")
 
-	// Create mock decoder instead of sphinx one in order to mock the route
-	// which htlc should follow.
-	decoder := &mockIteratorDecoder{}
-
 	feeEstimator := &mockFeeEstimator{
 		byteFeeIn:   make(chan btcutil.Amount),
 		weightFeeIn: make(chan btcutil.Amount),
@@ -737,6 +731,10 @@ func newThreeHopNetwork(t testing.TB, aliceChannel, firstBobChannel,
 	}
 	obfuscator := newMockObfuscator()
 
+	// Create mock decoder instead of sphinx one in order to mock the route
+	// which htlc should follow.
+	decoder := &mockIteratorDecoder{obfuscator: obfuscator}
+
 	aliceEpochChan := make(chan *chainntnfs.BlockEpoch)
 	aliceEpoch := &chainntnfs.BlockEpochEvent{
 		Epochs: aliceEpochChan,
@@ -745,14 +743,10 @@ func newThreeHopNetwork(t testing.TB, aliceChannel, firstBobChannel,
 	}
 	aliceChannelLink := NewChannelLink(
 		ChannelLinkConfig{
-			FwrdingPolicy:     globalPolicy,
-			Peer:              bobServer,
-			Switch:            aliceServer.htlcSwitch,
-			DecodeHopIterator: decoder.DecodeHopIterator,
-			DecodeOnionObfuscator: func(io.Reader) (ErrorEncrypter,
-				lnwire.FailCode) {
-				return obfuscator, lnwire.CodeNone
-			},
+			FwrdingPolicy:        globalPolicy,
+			Peer:                 bobServer,
+			Switch:               aliceServer.htlcSwitch,
+			OnionProcessor:       decoder,
 			GetLastChannelUpdate: mockGetChanUpdateMessage,
 			Registry:             aliceServer.registry,
 			BlockEpochs:          aliceEpoch,
@@ -784,14 +778,10 @@ func newThreeHopNetwork(t testing.TB, aliceChannel, firstBobChannel,
 	}
 	firstBobChannelLink := NewChannelLink(
 		ChannelLinkConfig{
-			FwrdingPolicy:     globalPolicy,
-			Peer:              aliceServer,
-			Switch:            bobServer.htlcSwitch,
-			DecodeHopIterator: decoder.DecodeHopIterator,
-			DecodeOnionObfuscator: func(io.Reader) (ErrorEncrypter,
-				lnwire.FailCode) {
-				return obfuscator, lnwire.CodeNone
-			},
+			FwrdingPolicy:        globalPolicy,
+			Peer:                 aliceServer,
+			Switch:               bobServer.htlcSwitch,
+			OnionProcessor:       decoder,
 			GetLastChannelUpdate: mockGetChanUpdateMessage,
 			Registry:             bobServer.registry,
 			BlockEpochs:          bobFirstEpoch,
@@ -823,14 +813,10 @@ func newThreeHopNetwork(t testing.TB, aliceChannel, firstBobChannel,
 	}
 	secondBobChannelLink := NewChannelLink(
 		ChannelLinkConfig{
-			FwrdingPolicy:     globalPolicy,
-			Peer:              carolServer,
-			Switch:            bobServer.htlcSwitch,
-			DecodeHopIterator: decoder.DecodeHopIterator,
-			DecodeOnionObfuscator: func(io.Reader) (ErrorEncrypter,
-				lnwire.FailCode) {
-				return obfuscator, lnwire.CodeNone
-			},
+			FwrdingPolicy:        globalPolicy,
+			Peer:                 carolServer,
+			Switch:               bobServer.htlcSwitch,
+			OnionProcessor:       decoder,
 			GetLastChannelUpdate: mockGetChanUpdateMessage,
 			Registry:             bobServer.registry,
 			BlockEpochs:          bobSecondEpoch,
@@ -862,14 +848,10 @@ func newThreeHopNetwork(t testing.TB, aliceChannel, firstBobChannel,
 	}
 	carolChannelLink := NewChannelLink(
 		ChannelLinkConfig{
-			FwrdingPolicy:     globalPolicy,
-			Peer:              bobServer,
-			Switch:            carolServer.htlcSwitch,
-			DecodeHopIterator: decoder.DecodeHopIterator,
-			DecodeOnionObfuscator: func(io.Reader) (ErrorEncrypter,
-				lnwire.FailCode) {
-				return obfuscator, lnwire.CodeNone
-			},
+			FwrdingPolicy:        globalPolicy,
+			Peer:                 bobServer,
+			Switch:               carolServer.htlcSwitch,
+			OnionProcessor:       decoder,
 			GetLastChannelUpdate: mockGetChanUpdateMessage,
 			Registry:             carolServer.registry,
 			BlockEpochs:          carolEpoch,
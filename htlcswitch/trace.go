@@ -0,0 +1,154 @@
+package htlcswitch
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TraceID is a random identifier assigned to an HTLC once it enters the
+// switch, either as a locally initiated payment or as an incoming HTLC to be
+// forwarded. It's carried along with the HTLC's packets, and with the settle
+// or fail that resolves it, allowing the HTLC's journey through the switch
+// and links to be reconstructed.
+type TraceID [8]byte
+
+// newTraceID returns a new random TraceID.
+func newTraceID() TraceID {
+	var id TraceID
+	if _, err := rand.Read(id[:]); err != nil {
+		log.Errorf("unable to generate trace id: %v", err)
+	}
+
+	return id
+}
+
+// String returns the hex encoding of the TraceID.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// TraceStage denotes a point within an HTLC's journey through the switch and
+// links.
+type TraceStage uint8
+
+const (
+	// TraceInitiated indicates that a locally initiated payment has been
+	// handed to the switch.
+	TraceInitiated TraceStage = iota
+
+	// TraceReceived indicates that an incoming HTLC has been locked in
+	// and handed to the switch in order to be forwarded.
+	TraceReceived
+
+	// TraceMailbox indicates that the HTLC has been delivered to the
+	// mailbox of the outgoing link.
+	TraceMailbox
+
+	// TraceOverflowQueue indicates that the HTLC has been placed within
+	// the overflow queue of the outgoing link, as the channel's HTLC
+	// slots are exhausted.
+	TraceOverflowQueue
+
+	// TraceAdded indicates that the HTLC has been added to the update log
+	// of the outgoing channel, and offered to the remote party.
+	TraceAdded
+
+	// TraceSettled indicates that the HTLC has been settled, and the
+	// settle is being propagated back to the incoming link.
+	TraceSettled
+
+	// TraceFailed indicates that the HTLC has been failed, and the
+	// failure is being propagated back to the incoming link.
+	TraceFailed
+)
+
+// String returns a human readable name for the TraceStage.
+func (s TraceStage) String() string {
+	switch s {
+	case TraceInitiated:
+		return "Initiated"
+	case TraceReceived:
+		return "Received"
+	case TraceMailbox:
+		return "Mailbox"
+	case TraceOverflowQueue:
+		return "OverflowQueue"
+	case TraceAdded:
+		return "Added"
+	case TraceSettled:
+		return "Settled"
+	case TraceFailed:
+		return "Failed"
+	default:
+		return "<unknown>"
+	}
+}
+
+// TraceEvent records an HTLC reaching a stage of its journey through the
+// switch and links.
+type TraceEvent struct {
+	// TraceID is the trace ID of the HTLC.
+	TraceID TraceID
+
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// Stage is the stage the HTLC has reached.
+	Stage TraceStage
+
+	// ChanID is the short channel ID of the link the event occurred on.
+	// It's blank for events that occur within the switch itself.
+	ChanID lnwire.ShortChannelID
+
+	// Timestamp is the time the event occurred.
+	Timestamp time.Time
+}
+
+// TraceExporter is an interface which allows trace events to be exported to
+// an external tracing system, such as an OpenTelemetry collector, where the
+// events sharing a trace ID may be assembled into spans.
+type TraceExporter interface {
+	// ExportTraceEvent exports the passed event. It MUST NOT block.
+	ExportTraceEvent(TraceEvent)
+}
+
+// trace records that the HTLC with the passed trace ID and payment hash has
+// reached the given stage on the link with the passed short channel ID. The
+// event is logged, and handed to the configured TraceExporter, if any. HTLCs
+// without a trace ID aren't traced.
+func (s *Switch) trace(traceID TraceID, paymentHash [32]byte,
+	stage TraceStage, chanID lnwire.ShortChannelID) {
+
+	if traceID == (TraceID{}) {
+		return
+	}
+
+	log.Debugf("trace=%v stage=%v chan_id=%v payment_hash=%x", traceID,
+		stage, chanID, paymentHash[:])
+
+	if s.cfg.TraceExporter != nil {
+		s.cfg.TraceExporter.ExportTraceEvent(TraceEvent{
+			TraceID:     traceID,
+			PaymentHash: paymentHash,
+			Stage:       stage,
+			ChanID:      chanID,
+			Timestamp:   time.Now(),
+		})
+	}
+}
+
+// traceAdd records that the HTLC add carried by the passed packet has reached
+// the given stage on the link with the passed short channel ID.
+func (s *Switch) traceAdd(pkt *htlcPacket, stage TraceStage,
+	chanID lnwire.ShortChannelID) {
+
+	htlc, ok := pkt.htlc.(*lnwire.UpdateAddHTLC)
+	if !ok {
+		return
+	}
+
+	s.trace(pkt.traceID, htlc.PaymentHash, stage, chanID)
+}
@@ -0,0 +1,189 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// VersionedOnionProcessor is an OnionProcessor which dispatches each onion
+// packet to one of several underlying processors, according to the version
+// of the packet. This allows an alternative onion construction to be deployed
+// alongside the Sphinx construction, with senders selecting the construction
+// used for each payment through the version of its packets.
+type VersionedOnionProcessor struct {
+	// processors maps each supported packet version to the processor
+	// decoding it.
+	processors map[byte]OnionProcessor
+
+	// versions is the set of supported versions, in the order they were
+	// registered.
+	versions []byte
+
+	// primary is the first of the underlying processors.
+	primary OnionProcessor
+}
+
+// A compile time check to ensure VersionedOnionProcessor implements the
+// OnionProcessor interface.
+var _ OnionProcessor = (*VersionedOnionProcessor)(nil)
+
+// NewVersionedOnionProcessor creates a new VersionedOnionProcessor
+// dispatching packets to the passed processors. As the error encrypters of
+// persisted circuits can't be attributed to a version, they're restored by
+// the first processor. An error is returned if several processors support the
+// same version.
+func NewVersionedOnionProcessor(
+	processors ...OnionProcessor) (*VersionedOnionProcessor, error) {
+
+	if len(processors) == 0 {
+		return nil, fmt.Errorf("at least one onion processor is " +
+			"required")
+	}
+
+	p := &VersionedOnionProcessor{
+		processors: make(map[byte]OnionProcessor),
+		primary:    processors[0],
+	}
+	for _, processor := range processors {
+		for _, version := range processor.SupportedVersions() {
+			if _, ok := p.processors[version]; ok {
+				return nil, fmt.Errorf("onion version %v is "+
+					"supported by several processors",
+					version)
+			}
+
+			p.processors[version] = processor
+			p.versions = append(p.versions, version)
+		}
+	}
+
+	return p, nil
+}
+
+// processorFor reads the version of the onion packet from the passed reader,
+// and returns the processor supporting it, along with a reader from which the
+// whole packet, version included, can be read.
+func (p *VersionedOnionProcessor) processorFor(r io.Reader) (OnionProcessor,
+	io.Reader, lnwire.FailCode) {
+
+	var version [1]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return nil, nil, lnwire.CodeInvalidOnionVersion
+	}
+
+	processor, ok := p.processors[version[0]]
+	if !ok {
+		log.Debugf("Unsupported onion version %v", version[0])
+		return nil, nil, lnwire.CodeInvalidOnionVersion
+	}
+
+	return processor, io.MultiReader(bytes.NewReader(version[:]), r),
+		lnwire.CodeNone
+}
+
+// SupportedVersions returns the versions supported by any of the underlying
+// processors.
+//
+// NOTE: Part of the OnionProcessor interface.
+func (p *VersionedOnionProcessor) SupportedVersions() []byte {
+	return p.versions
+}
+
+// DecodeHopIterator decodes the onion packet read from the passed reader,
+// using the processor supporting its version.
+//
+// NOTE: Part of the OnionProcessor interface.
+func (p *VersionedOnionProcessor) DecodeHopIterator(r io.Reader,
+	rHash []byte) (HopIterator, lnwire.FailCode) {
+
+	processor, r, failCode := p.processorFor(r)
+	if failCode != lnwire.CodeNone {
+		return nil, failCode
+	}
+
+	return processor.DecodeHopIterator(r, rHash)
+}
+
+// DecodeHopIterators decodes the onion packets of a batch of HTLCs. The
+// packets are split into a sub-batch for each version, each decoded by the
+// processor supporting it.
+//
+// NOTE: Part of the OnionProcessor interface.
+func (p *VersionedOnionProcessor) DecodeHopIterators(batchID []byte,
+	height uint32, reqs []DecodeHopIteratorRequest) (
+	[]DecodeHopIteratorResponse, error) {
+
+	resps := make([]DecodeHopIteratorResponse, len(reqs))
+
+	// We'll first group the requests by version, remembering the index of
+	// each within the batch.
+	subReqs := make(map[byte][]DecodeHopIteratorRequest)
+	subIndexes := make(map[byte][]int)
+	for i, req := range reqs {
+		var version [1]byte
+		_, err := io.ReadFull(req.OnionReader, version[:])
+		if err != nil {
+			resps[i].FailCode = lnwire.CodeInvalidOnionVersion
+			continue
+		}
+		if _, ok := p.processors[version[0]]; !ok {
+			resps[i].FailCode = lnwire.CodeInvalidOnionVersion
+			continue
+		}
+
+		req.OnionReader = io.MultiReader(
+			bytes.NewReader(version[:]), req.OnionReader,
+		)
+		subReqs[version[0]] = append(subReqs[version[0]], req)
+		subIndexes[version[0]] = append(subIndexes[version[0]], i)
+	}
+
+	// Each sub-batch is identified by the ID of the batch along with its
+	// version, so that the sub-batches remain distinct should several
+	// processors share a replay log.
+	for version, versionReqs := range subReqs {
+		subBatchID := append(append([]byte{}, batchID...), version)
+
+		subResps, err := p.processors[version].DecodeHopIterators(
+			subBatchID, height, versionReqs,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, resp := range subResps {
+			resps[subIndexes[version][j]] = resp
+		}
+	}
+
+	return resps, nil
+}
+
+// ExtractErrorEncrypter derives the ErrorEncrypter of the onion packet read
+// from the passed reader, using the processor supporting its version.
+//
+// NOTE: Part of the OnionProcessor interface.
+func (p *VersionedOnionProcessor) ExtractErrorEncrypter(
+	r io.Reader) (ErrorEncrypter, lnwire.FailCode) {
+
+	processor, r, failCode := p.processorFor(r)
+	if failCode != lnwire.CodeNone {
+		return nil, failCode
+	}
+
+	return processor.ExtractErrorEncrypter(r)
+}
+
+// ReextractErrorEncrypter restores the ErrorEncrypter of a persisted circuit
+// using the primary processor.
+//
+// NOTE: Part of the OnionProcessor interface.
+func (p *VersionedOnionProcessor) ReextractErrorEncrypter(
+	ephemeralKey *btcec.PublicKey) (ErrorEncrypter, lnwire.FailCode) {
+
+	return p.primary.ReextractErrorEncrypter(ephemeralKey)
+}
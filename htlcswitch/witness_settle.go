@@ -0,0 +1,109 @@
+package htlcswitch
+
+import (
+	"crypto/sha256"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// recordResolution notes that we've added a settle or fail of an incoming
+// HTLC to the channel, so that the HTLC won't be resolved once again using a
+// preimage discovered by the witness beacon.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) recordResolution(msg lnwire.Message) {
+	switch msg := msg.(type) {
+	case *lnwire.UpdateFufillHTLC:
+		l.resolvedHtlcs[msg.ID] = struct{}{}
+	case *lnwire.UpdateFailHTLC:
+		l.resolvedHtlcs[msg.ID] = struct{}{}
+	case *lnwire.UpdateFailMalformedHTLC:
+		l.resolvedHtlcs[msg.ID] = struct{}{}
+	}
+}
+
+// consumeWitnessSettle returns true if the incoming HTLC with the passed index
+// has already been settled using a preimage discovered by the witness beacon.
+// In that case, the resolution delivered by the switch is redundant, and must
+// be ignored rather than applied to the channel a second time.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) consumeWitnessSettle(htlcIndex uint64) bool {
+	if _, ok := l.witnessSettled[htlcIndex]; !ok {
+		return false
+	}
+
+	delete(l.witnessSettled, htlcIndex)
+	return true
+}
+
+// settleWithWitness settles any of the channel's incoming HTLCs which pay to
+// the hash of the passed preimage, and which haven't already been resolved.
+// Preimages are delivered by the witness beacon as soon as they're
+// discovered, such as when the chain arbitrator of another channel broadcasts
+// a transaction claiming an HTLC using the preimage. Settling the incoming
+// HTLCs off-chain straight away, rather than waiting for the settle to
+// propagate through the switch, reduces the chance of the upstream HTLCs
+// timing out and the remote party force closing the channel as well. The
+// number of HTLCs settled is returned.
+//
+// NOTE: This MUST only be called from the htlcManager goroutine.
+func (l *channelLink) settleWithWitness(preimage []byte) (int, error) {
+	if len(preimage) != 32 {
+		return 0, nil
+	}
+
+	var p [32]byte
+	copy(p[:], preimage)
+	paymentHash := sha256.Sum256(p[:])
+
+	// Any recorded resolution of an HTLC that's no longer active on the
+	// channel can be dropped, as it can no longer be resolved again.
+	activeHtlcs := make(map[uint64]struct{})
+	for _, htlc := range l.channel.ActiveHtlcs() {
+		if htlc.Incoming {
+			activeHtlcs[htlc.HtlcIndex] = struct{}{}
+		}
+	}
+	for htlcIndex := range l.resolvedHtlcs {
+		if _, ok := activeHtlcs[htlcIndex]; !ok {
+			delete(l.resolvedHtlcs, htlcIndex)
+		}
+	}
+
+	var numSettled int
+	for _, htlc := range l.channel.ActiveHtlcs() {
+		if !htlc.Incoming || htlc.RHash != paymentHash {
+			continue
+		}
+		if _, ok := l.resolvedHtlcs[htlc.HtlcIndex]; ok {
+			continue
+		}
+
+		// HTLCs for which we're the exit hop, and whose settlement is
+		// being held, are only settled once it's safe to do so.
+		if _, ok := l.heldExitHtlcs[htlc.HtlcIndex]; ok {
+			continue
+		}
+
+		if err := l.channel.SettleHTLC(p, htlc.HtlcIndex); err != nil {
+			return numSettled, err
+		}
+
+		log.Infof("ChannelPoint(%v): settling incoming htlc=%v with "+
+			"payment_hash=%x using preimage discovered by witness "+
+			"beacon", l.channel.ChannelPoint(), htlc.HtlcIndex,
+			paymentHash[:])
+
+		l.witnessSettled[htlc.HtlcIndex] = struct{}{}
+		l.sendUpdate(&lnwire.UpdateFufillHTLC{
+			ChanID:          l.ChanID(),
+			ID:              htlc.HtlcIndex,
+			PaymentPreimage: p,
+		})
+		l.batchCounter++
+		numSettled++
+	}
+
+	return numSettled, nil
+}
@@ -3,10 +3,12 @@ package main
 import (
 	"bytes"
 	"crypto/sha256"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
@@ -36,21 +38,62 @@ type invoiceRegistry struct {
 	nextClientID        uint32
 	notificationClients map[uint32]*invoiceSubscription
 
+	// singleInvoiceClients are the clients subscribed to the updates of a
+	// single invoice, guarded by the clientMtx.
+	singleInvoiceClients map[uint32]*singleInvoiceSubscription
+
 	// debugInvoices is a map which stores special "debug" invoices which
 	// should be only created/used when manual tests require an invoice
 	// that *all* nodes are able to fully settle.
 	debugInvoices map[chainhash.Hash]*channeldb.Invoice
+
+	// holdWaiters maps the payment hash of each hold invoice that's
+	// awaited by AwaitHoldInvoice to the channels which are closed once
+	// the invoice is either settled or canceled.
+	holdMtx     sync.Mutex
+	holdWaiters map[chainhash.Hash][]chan struct{}
+
+	// maxPendingSettles is the number of invoice settles that may be
+	// written to the database concurrently before the registry is
+	// considered saturated. If zero, the registry is never saturated.
+	maxPendingSettles int
+
+	// pendingSettles is the number of invoice settles currently being
+	// written to the database. While the registry is saturated,
+	// settleCapacity is non-nil, and is closed once it's no longer
+	// saturated.
+	settleMtx      sync.Mutex
+	pendingSettles int
+	settleCapacity chan struct{}
+
+	// ampSets maps the set ID of each AMP payment whose parts are being
+	// received to the parts received so far. Sets which aren't complete
+	// within ampTimeout have their parts canceled.
+	ampMtx     sync.Mutex
+	ampSets    map[[32]byte]*ampSet
+	ampTimeout time.Duration
 }
 
 // newInvoiceRegistry creates a new invoice registry. The invoice registry
 // wraps the persistent on-disk invoice storage with an additional in-memory
 // layer. The in-memory layer is in place such that debug invoices can be added
-// which are volatile yet available system wide within the daemon.
-func newInvoiceRegistry(cdb *channeldb.DB) *invoiceRegistry {
+// which are volatile yet available system wide within the daemon. Once
+// maxPendingSettles invoice settles are being written concurrently, the
+// registry signals backpressure to the links settling them.
+func newInvoiceRegistry(cdb *channeldb.DB,
+	maxPendingSettles int) *invoiceRegistry {
+
 	return &invoiceRegistry{
 		cdb:                 cdb,
 		debugInvoices:       make(map[chainhash.Hash]*channeldb.Invoice),
 		notificationClients: make(map[uint32]*invoiceSubscription),
+		singleInvoiceClients: make(
+			map[uint32]*singleInvoiceSubscription,
+		),
+		holdWaiters:       make(map[chainhash.Hash][]chan struct{}),
+		maxPendingSettles: maxPendingSettles,
+		ampSets:           make(map[[32]byte]*ampSet),
+		ampTimeout:        defaultAmpTimeout,
 	}
 }
 
@@ -89,10 +132,29 @@ func (i *invoiceRegistry) AddInvoice(invoice *channeldb.Invoice) error {
 	}))
 
 	// TODO(roasbeef): also check in memory for quick lookups/settles?
-	return i.cdb.AddInvoice(invoice)
+	if err := i.cdb.AddInvoice(invoice); err != nil {
+		return err
+	}
 
-	// TODO(roasbeef): re-enable?
-	//go i.notifyClients(invoice, false)
+	i.notifyClients(invoice, false)
+
+	return nil
+}
+
+// AddInvoices adds a batch of invoices to the invoice database within a
+// single transaction. Either all of the invoices are added, or none are.
+func (i *invoiceRegistry) AddInvoices(invoices []*channeldb.Invoice) error {
+	ltndLog.Debugf("Adding batch of %v invoices", len(invoices))
+
+	if err := i.cdb.AddInvoices(invoices); err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		i.notifyClients(invoice, false)
+	}
+
+	return nil
 }
 
 // lookupInvoice looks up an invoice by its payment hash (R-Hash), if found
@@ -120,10 +182,38 @@ func (i *invoiceRegistry) LookupInvoice(rHash chainhash.Hash) (channeldb.Invoice
 	return *invoice, nil
 }
 
-// SettleInvoice attempts to mark an invoice as settled. If the invoice is a
-// debug invoice, then this method is a noop as debug invoices are never fully
-// settled.
-func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash) error {
+// AcceptInvoice records the passed HTLC as accepted, but held, within the HTLC
+// set of the invoice identified by the passed payment hash. If the invoice is
+// a debug invoice, then this method is a noop.
+func (i *invoiceRegistry) AcceptInvoice(rHash chainhash.Hash,
+	htlc channeldb.InvoiceHTLC) error {
+
+	ltndLog.Debugf("Accepting htlc=%v from chan_id=%v for invoice %x",
+		htlc.HtlcIndex, htlc.ChanID, rHash[:])
+
+	i.RLock()
+	_, ok := i.debugInvoices[rHash]
+	i.RUnlock()
+	if ok {
+		return nil
+	}
+
+	invoice, err := i.cdb.AcceptInvoice(rHash, htlc)
+	if err != nil {
+		return err
+	}
+
+	i.notifySingleInvoiceClients(rHash, invoice)
+
+	return nil
+}
+
+// SettleInvoice attempts to mark an invoice as settled by the passed HTLC. If
+// the invoice is a debug invoice, then this method is a noop as debug
+// invoices are never fully settled.
+func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash,
+	htlc channeldb.InvoiceHTLC) error {
+
 	ltndLog.Debugf("Settling invoice %x", rHash[:])
 
 	// First check the in-memory debug invoice index to see if this is an
@@ -140,27 +230,212 @@ func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash) error {
 
 	// If this isn't a debug invoice, then we'll attempt to settle an
 	// invoice matching this rHash on disk (if one exists).
-	if err := i.cdb.SettleInvoice(rHash); err != nil {
+	i.beginSettle()
+	invoice, err := i.cdb.SettleInvoice(rHash, htlc)
+	i.endSettle()
+	if err != nil {
 		return err
 	}
 
-	// Launch a new goroutine to notify any/all registered invoice
-	// notification clients.
-	go func() {
-		invoice, err := i.cdb.LookupInvoice(rHash)
-		if err != nil {
-			ltndLog.Errorf("unable to find invoice: %v", err)
-			return
-		}
+	ltndLog.Infof("Payment received: %v", newLogClosure(func() string {
+		return spew.Sdump(invoice)
+	}))
 
-		ltndLog.Infof("Payment received: %v", spew.Sdump(invoice))
+	// Notify any/all registered invoice notification clients.
+	i.notifyClients(invoice, true)
+	i.notifySingleInvoiceClients(rHash, invoice)
 
-		i.notifyClients(invoice, true)
-	}()
+	return nil
+}
+
+// SettleStatelessInvoice marks the passed stateless invoice as settled by the
+// passed HTLC. As stateless invoices aren't stored, the settlement is only
+// relayed to the invoice notification clients.
+func (i *invoiceRegistry) SettleStatelessInvoice(invoice *channeldb.Invoice,
+	htlc channeldb.InvoiceHTLC) error {
+
+	rHash := chainhash.Hash(invoice.Terms.PaymentHash)
+	ltndLog.Debugf("Settling stateless invoice %x", rHash[:])
+
+	if err := invoice.SettleStateless(htlc); err != nil {
+		return err
+	}
+
+	ltndLog.Infof("Payment received: %v", newLogClosure(func() string {
+		return spew.Sdump(invoice)
+	}))
+
+	i.notifyClients(invoice, true)
+	i.notifySingleInvoiceClients(rHash, invoice)
+
+	return nil
+}
+
+// SettleBackpressure returns a non-nil channel if the registry is saturated
+// with invoice settles, such as when the database is slow to write them. The
+// channel is closed once the registry is able to accept further settles. In
+// the meantime, links should hold the HTLCs paying to our invoices, rather
+// than blocking on their settles.
+func (i *invoiceRegistry) SettleBackpressure() <-chan struct{} {
+	i.settleMtx.Lock()
+	defer i.settleMtx.Unlock()
+
+	return i.settleCapacity
+}
+
+// beginSettle records the start of an invoice settle, marking the registry
+// as saturated if it's reached its maximum number of pending settles.
+func (i *invoiceRegistry) beginSettle() {
+	i.settleMtx.Lock()
+	defer i.settleMtx.Unlock()
+
+	i.pendingSettles++
+	if i.maxPendingSettles == 0 || i.settleCapacity != nil ||
+		i.pendingSettles < i.maxPendingSettles {
+
+		return
+	}
+
+	ltndLog.Warnf("Invoice registry saturated with %v pending settles, "+
+		"holding further exit hop settles", i.pendingSettles)
+
+	i.settleCapacity = make(chan struct{})
+}
+
+// endSettle records the completion of an invoice settle, signalling any links
+// holding settles if the registry is no longer saturated.
+func (i *invoiceRegistry) endSettle() {
+	i.settleMtx.Lock()
+	defer i.settleMtx.Unlock()
+
+	i.pendingSettles--
+	if i.settleCapacity == nil ||
+		i.pendingSettles >= i.maxPendingSettles {
+
+		return
+	}
+
+	ltndLog.Infof("Invoice registry no longer saturated, releasing " +
+		"held exit hop settles")
+
+	close(i.settleCapacity)
+	i.settleCapacity = nil
+}
+
+// SettleHodlInvoice releases the preimage of the hold invoice identified by
+// the passed payment hash. Any HTLCs paying to the invoice which are held by
+// their links will then be settled, after which the invoice itself is marked
+// as settled.
+func (i *invoiceRegistry) SettleHodlInvoice(rHash chainhash.Hash,
+	preimage [32]byte) error {
+
+	ltndLog.Debugf("Releasing preimage of hold invoice %x", rHash[:])
+
+	if err := i.cdb.SettleHoldInvoice(rHash, preimage); err != nil {
+		return err
+	}
+
+	i.releaseHoldWaiters(rHash)
 
 	return nil
 }
 
+// CancelHodlInvoice cancels the hold invoice identified by the passed payment
+// hash. Any HTLCs paying to the invoice which are held by their links will
+// then be failed back to the sender, as will any HTLCs paying to it in the
+// future.
+func (i *invoiceRegistry) CancelHodlInvoice(rHash chainhash.Hash) error {
+	ltndLog.Debugf("Canceling hold invoice %x", rHash[:])
+
+	if err := i.cdb.CancelHoldInvoice(rHash); err != nil {
+		return err
+	}
+
+	i.releaseHoldWaiters(rHash)
+
+	invoice, err := i.cdb.LookupInvoice(rHash)
+	if err != nil {
+		ltndLog.Errorf("unable to find invoice: %v", err)
+		return nil
+	}
+	i.notifySingleInvoiceClients(rHash, invoice)
+
+	return nil
+}
+
+// AwaitHoldInvoice blocks until the hold invoice identified by the passed
+// payment hash is either settled or canceled, then returns the invoice. If
+// the invoice has already left the accepting state, then it's returned
+// straight away. An error is returned if the passed quit channel is closed
+// in the meantime.
+func (i *invoiceRegistry) AwaitHoldInvoice(rHash chainhash.Hash,
+	quit <-chan struct{}) (channeldb.Invoice, error) {
+
+	// We'll register ourselves as a waiter before looking up the
+	// invoice, so we won't miss the invoice being resolved in between.
+	released := make(chan struct{})
+	i.holdMtx.Lock()
+	i.holdWaiters[rHash] = append(i.holdWaiters[rHash], released)
+	i.holdMtx.Unlock()
+
+	defer i.removeHoldWaiter(rHash, released)
+
+	invoice, err := i.LookupInvoice(rHash)
+	if err != nil {
+		return channeldb.Invoice{}, err
+	}
+	if !invoice.Terms.Hold {
+		return channeldb.Invoice{}, channeldb.ErrNotHoldInvoice
+	}
+	if invoice.Terms.HoldState != channeldb.HoldAccepting {
+		return invoice, nil
+	}
+
+	select {
+	case <-released:
+		return i.LookupInvoice(rHash)
+	case <-quit:
+		return channeldb.Invoice{}, fmt.Errorf("wait for hold invoice "+
+			"%x aborted", rHash[:])
+	}
+}
+
+// releaseHoldWaiters wakes all callers of AwaitHoldInvoice which are waiting
+// upon the hold invoice identified by the passed payment hash.
+func (i *invoiceRegistry) releaseHoldWaiters(rHash chainhash.Hash) {
+	i.holdMtx.Lock()
+	defer i.holdMtx.Unlock()
+
+	for _, released := range i.holdWaiters[rHash] {
+		close(released)
+	}
+	delete(i.holdWaiters, rHash)
+}
+
+// removeHoldWaiter removes the passed waiter upon the hold invoice identified
+// by the passed payment hash, if it hasn't been released already.
+func (i *invoiceRegistry) removeHoldWaiter(rHash chainhash.Hash,
+	released chan struct{}) {
+
+	i.holdMtx.Lock()
+	defer i.holdMtx.Unlock()
+
+	waiters := i.holdWaiters[rHash]
+	for idx, waiter := range waiters {
+		if waiter != released {
+			continue
+		}
+
+		waiters = append(waiters[:idx], waiters[idx+1:]...)
+		break
+	}
+	if len(waiters) == 0 {
+		delete(i.holdWaiters, rHash)
+		return
+	}
+	i.holdWaiters[rHash] = waiters
+}
+
 // notifyClients notifies all currently registered invoice notification clients
 // of a newly added/settled invoice.
 func (i *invoiceRegistry) notifyClients(invoice *channeldb.Invoice, settle bool) {
@@ -220,3 +495,96 @@ func (i *invoiceRegistry) SubscribeNotifications() *invoiceSubscription {
 
 	return client
 }
+
+// notifySingleInvoiceClients delivers the passed update of the invoice
+// identified by the passed payment hash to the clients subscribed to it.
+// Updates are delivered to each client in the order they were made.
+func (i *invoiceRegistry) notifySingleInvoiceClients(rHash chainhash.Hash,
+	invoice *channeldb.Invoice) {
+
+	i.clientMtx.Lock()
+	defer i.clientMtx.Unlock()
+
+	for _, client := range i.singleInvoiceClients {
+		if client.hash != rHash {
+			continue
+		}
+
+		client.queue.ChanIn() <- invoice
+	}
+}
+
+// singleInvoiceSubscription represents an intent to receive the updates of a
+// single invoice. The current state of the invoice is sent over the Updates
+// channel upon subscribing, followed by a copy of the invoice each time an
+// HTLC paying to it is accepted, or it's settled or canceled.
+type singleInvoiceSubscription struct {
+	Updates chan *channeldb.Invoice
+
+	hash  chainhash.Hash
+	queue *chainntnfs.ConcurrentQueue
+
+	inv  *invoiceRegistry
+	id   uint32
+	quit chan struct{}
+}
+
+// Cancel unregisters the singleInvoiceSubscription, freeing any previously
+// allocated resources.
+func (i *singleInvoiceSubscription) Cancel() {
+	i.inv.clientMtx.Lock()
+	delete(i.inv.singleInvoiceClients, i.id)
+	i.inv.clientMtx.Unlock()
+
+	close(i.quit)
+	i.queue.Stop()
+}
+
+// SubscribeSingleInvoice returns a singleInvoiceSubscription which allows the
+// caller to receive async notifications of the updates of the invoice
+// identified by the passed payment hash, starting with its current state.
+func (i *invoiceRegistry) SubscribeSingleInvoice(
+	rHash chainhash.Hash) (*singleInvoiceSubscription, error) {
+
+	client := &singleInvoiceSubscription{
+		Updates: make(chan *channeldb.Invoice),
+		hash:    rHash,
+		queue:   chainntnfs.NewConcurrentQueue(20),
+		inv:     i,
+		quit:    make(chan struct{}),
+	}
+	client.queue.Start()
+
+	// We'll look up the invoice while holding the client mutex, such that
+	// no update is delivered before its current state.
+	i.clientMtx.Lock()
+	invoice, err := i.cdb.LookupInvoice(rHash)
+	if err != nil {
+		i.clientMtx.Unlock()
+		client.queue.Stop()
+		return nil, err
+	}
+	client.queue.ChanIn() <- invoice
+
+	i.singleInvoiceClients[i.nextClientID] = client
+	client.id = i.nextClientID
+	i.nextClientID++
+	i.clientMtx.Unlock()
+
+	go func() {
+		for {
+			select {
+			case item := <-client.queue.ChanOut():
+				select {
+				case client.Updates <- item.(*channeldb.Invoice):
+				case <-client.quit:
+					return
+				}
+			case <-client.quit:
+				return
+			}
+		}
+	}()
+
+	return client, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// TestSubscribeSingleInvoice tests that a client subscribed to a single
+// invoice receives its current state, followed by each of its updates in
+// order, and none of the updates of other invoices.
+func TestSubscribeSingleInvoice(t *testing.T) {
+	t.Parallel()
+
+	tempDir, err := ioutil.TempDir("", "invoiceregistry")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cdb, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open db: %v", err)
+	}
+	defer cdb.Close()
+
+	registry := newInvoiceRegistry(cdb, 0)
+
+	newInvoice := func(preimage [32]byte) chainhash.Hash {
+		invoice := &channeldb.Invoice{
+			CreationDate: time.Now(),
+			Terms: channeldb.ContractTerm{
+				PaymentPreimage: preimage,
+				Value:           lnwire.MilliSatoshi(100000),
+			},
+		}
+		if err := registry.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		return chainhash.Hash(sha256.Sum256(preimage[:]))
+	}
+	rHash := newInvoice([32]byte{1})
+	otherHash := newInvoice([32]byte{2})
+
+	sub, err := registry.SubscribeSingleInvoice(rHash)
+	if err != nil {
+		t.Fatalf("unable to subscribe to invoice: %v", err)
+	}
+	defer sub.Cancel()
+
+	htlc := channeldb.InvoiceHTLC{
+		ChanID:    lnwire.NewShortChanIDFromInt(1),
+		HtlcIndex: 5,
+		Amt:       100000,
+		Expiry:    200,
+	}
+	if err := registry.AcceptInvoice(otherHash, htlc); err != nil {
+		t.Fatalf("unable to accept htlc: %v", err)
+	}
+	if err := registry.AcceptInvoice(rHash, htlc); err != nil {
+		t.Fatalf("unable to accept htlc: %v", err)
+	}
+	if err := registry.SettleInvoice(rHash, htlc); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+
+	expected := []channeldb.ContractState{
+		channeldb.ContractOpen,
+		channeldb.ContractAccepted,
+		channeldb.ContractSettled,
+	}
+	for _, state := range expected {
+		select {
+		case invoice := <-sub.Updates:
+			if invoice.Terms.State != state {
+				t.Fatalf("expected state %v, got %v", state,
+					invoice.Terms.State)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("invoice update with state %v not received",
+				state)
+		}
+	}
+
+	select {
+	case invoice := <-sub.Updates:
+		t.Fatalf("unexpected invoice update: %v", invoice.Terms.State)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
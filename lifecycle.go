@@ -0,0 +1,319 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultSubsystemTimeout is the default amount of time a subsystem
+	// may take to either start or stop before it's considered to be hung.
+	defaultSubsystemTimeout = 30 * time.Second
+)
+
+// errSubsystemTimeout is returned when a subsystem fails to start or stop
+// within its timeout.
+var errSubsystemTimeout = errors.New("subsystem timed out")
+
+// subsystemState describes the current state of a subsystem managed by the
+// lifecycleManager.
+type subsystemState uint8
+
+const (
+	// subsystemStopped indicates the subsystem hasn't yet been started, or
+	// has been cleanly stopped.
+	subsystemStopped subsystemState = iota
+
+	// subsystemRunning indicates the subsystem has been started
+	// successfully.
+	subsystemRunning
+
+	// subsystemFailed indicates the subsystem returned an error when being
+	// started or stopped.
+	subsystemFailed
+
+	// subsystemTimedOut indicates the subsystem didn't finish starting or
+	// stopping within its timeout.
+	subsystemTimedOut
+)
+
+// String returns a human readable version of the subsystemState.
+func (s subsystemState) String() string {
+	switch s {
+	case subsystemStopped:
+		return "stopped"
+	case subsystemRunning:
+		return "running"
+	case subsystemFailed:
+		return "failed"
+	case subsystemTimedOut:
+		return "timed out"
+	default:
+		return "unknown"
+	}
+}
+
+// subsystem is a single component of the daemon whose lifecycle is governed
+// by the lifecycleManager.
+type subsystem struct {
+	// name uniquely identifies the subsystem.
+	name string
+
+	// deps is the set of subsystems which must be running before this
+	// subsystem is started. This subsystem will be stopped before any of
+	// its dependencies are.
+	deps []string
+
+	// start starts the subsystem. If nil, then the subsystem is started
+	// externally, and is only stopped by the manager.
+	start func() error
+
+	// stop stops the subsystem.
+	stop func() error
+
+	// timeout is the amount of time start and stop may each take before
+	// the subsystem is considered to be hung. If zero, then
+	// defaultSubsystemTimeout is used.
+	timeout time.Duration
+
+	state subsystemState
+	err   error
+}
+
+// subsystemStatus is a snapshot of the health of a single subsystem.
+type subsystemStatus struct {
+	Name  string
+	State subsystemState
+	Err   error
+}
+
+// lifecycleManager starts and stops the subsystems of the daemon in
+// dependency order. Subsystems are started only once all of their
+// dependencies are running, and are stopped before any of their dependencies.
+// This ensures that, for example, channel links are torn down while the chain
+// notifier they're waiting on is still alive. Each start and stop is bounded
+// by a timeout, so a single hung subsystem can't block the shutdown of the
+// entire daemon.
+type lifecycleManager struct {
+	mu         sync.Mutex
+	subsystems map[string]*subsystem
+
+	// order is the list of subsystems in the order they were started in.
+	// It's populated by Start.
+	order []*subsystem
+}
+
+// newLifecycleManager creates a new lifecycleManager with no registered
+// subsystems.
+func newLifecycleManager() *lifecycleManager {
+	return &lifecycleManager{
+		subsystems: make(map[string]*subsystem),
+	}
+}
+
+// Register adds a new subsystem to the manager. All subsystems must be
+// registered before Start is called.
+func (m *lifecycleManager) Register(sub *subsystem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.subsystems[sub.name]; ok {
+		return fmt.Errorf("subsystem %v already registered", sub.name)
+	}
+	if sub.timeout == 0 {
+		sub.timeout = defaultSubsystemTimeout
+	}
+
+	m.subsystems[sub.name] = sub
+	return nil
+}
+
+// sortSubsystems returns the registered subsystems in an order such that each
+// subsystem comes after all of its dependencies. An error is returned if a
+// dependency is unknown, or if the dependencies form a cycle.
+func (m *lifecycleManager) sortSubsystems() ([]*subsystem, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	var (
+		order []*subsystem
+		marks = make(map[string]int)
+		visit func(name, parent string) error
+	)
+	visit = func(name, parent string) error {
+		sub, ok := m.subsystems[name]
+		if !ok {
+			return fmt.Errorf("subsystem %v depends on unknown "+
+				"subsystem %v", parent, name)
+		}
+
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle detected at "+
+				"subsystem %v", name)
+		}
+
+		marks[name] = visiting
+		for _, dep := range sub.deps {
+			if err := visit(dep, name); err != nil {
+				return err
+			}
+		}
+		marks[name] = visited
+
+		order = append(order, sub)
+		return nil
+	}
+
+	// We'll visit the subsystems in lexicographical order, so the
+	// resulting order is deterministic.
+	names := make([]string, 0, len(m.subsystems))
+	for name := range m.subsystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := visit(name, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// runWithTimeout executes f, returning errSubsystemTimeout if it doesn't
+// return within the passed timeout.
+func runWithTimeout(f func() error, timeout time.Duration) error {
+	// The function is executed within a distinct goroutine, as it may
+	// never return. The channel is buffered so the goroutine is able to
+	// exit if it eventually does.
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- f()
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-time.After(timeout):
+		return errSubsystemTimeout
+	}
+}
+
+// Start starts all registered subsystems in dependency order. If any of the
+// subsystems fails to start, then all subsystems that were already started
+// are stopped again, and the error is returned.
+func (m *lifecycleManager) Start() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	order, err := m.sortSubsystems()
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range order {
+		// Subsystems without a start function have been started
+		// externally, so they only need to be tracked.
+		if sub.start == nil {
+			sub.state = subsystemRunning
+			m.order = append(m.order, sub)
+			continue
+		}
+
+		ltndLog.Debugf("Starting subsystem %v", sub.name)
+
+		err := runWithTimeout(sub.start, sub.timeout)
+		switch {
+		case err == errSubsystemTimeout:
+			sub.state = subsystemTimedOut
+			sub.err = err
+
+		case err != nil:
+			sub.state = subsystemFailed
+			sub.err = err
+		}
+		if err != nil {
+			// As we're unable to proceed, we'll stop all the
+			// subsystems that were already started.
+			m.stop()
+			return fmt.Errorf("unable to start subsystem %v: %v",
+				sub.name, err)
+		}
+
+		sub.state = subsystemRunning
+		m.order = append(m.order, sub)
+	}
+
+	return nil
+}
+
+// Stop stops all running subsystems in the reverse order they were started
+// in. Subsystems that fail to stop in time are skipped, so the remaining
+// subsystems can still be stopped.
+func (m *lifecycleManager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stop()
+}
+
+// stop stops all running subsystems in reverse start order.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *lifecycleManager) stop() {
+	for i := len(m.order) - 1; i >= 0; i-- {
+		sub := m.order[i]
+
+		ltndLog.Debugf("Stopping subsystem %v", sub.name)
+
+		err := runWithTimeout(sub.stop, sub.timeout)
+		switch {
+		case err == errSubsystemTimeout:
+			ltndLog.Errorf("Subsystem %v failed to stop within %v, "+
+				"continuing shutdown", sub.name, sub.timeout)
+			sub.state = subsystemTimedOut
+			sub.err = err
+
+		case err != nil:
+			ltndLog.Errorf("Unable to stop subsystem %v: %v",
+				sub.name, err)
+			sub.state = subsystemFailed
+			sub.err = err
+
+		default:
+			sub.state = subsystemStopped
+		}
+	}
+
+	m.order = nil
+}
+
+// Status returns a snapshot of the state of each registered subsystem, sorted
+// by name.
+func (m *lifecycleManager) Status() []subsystemStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]subsystemStatus, 0, len(m.subsystems))
+	for _, sub := range m.subsystems {
+		statuses = append(statuses, subsystemStatus{
+			Name:  sub.name,
+			State: sub.state,
+			Err:   sub.err,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
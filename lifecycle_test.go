@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lifecycleRecorder records the order in which subsystems are started and
+// stopped.
+type lifecycleRecorder struct {
+	sync.Mutex
+	events []string
+}
+
+func (r *lifecycleRecorder) record(event string) {
+	r.Lock()
+	r.events = append(r.events, event)
+	r.Unlock()
+}
+
+// newTestSubsystem creates a subsystem that records its start and stop within
+// the passed recorder.
+func (r *lifecycleRecorder) newTestSubsystem(name string,
+	deps ...string) *subsystem {
+
+	return &subsystem{
+		name: name,
+		deps: deps,
+		start: func() error {
+			r.record("start " + name)
+			return nil
+		},
+		stop: func() error {
+			r.record("stop " + name)
+			return nil
+		},
+	}
+}
+
+// TestLifecycleManagerOrdering ensures that subsystems are started after all
+// of their dependencies, and stopped before them.
+func TestLifecycleManagerOrdering(t *testing.T) {
+	t.Parallel()
+
+	recorder := &lifecycleRecorder{}
+	manager := newLifecycleManager()
+
+	subsystems := []*subsystem{
+		recorder.newTestSubsystem("links", "switch", "notifier"),
+		recorder.newTestSubsystem("switch", "notifier"),
+		recorder.newTestSubsystem("notifier"),
+	}
+	for _, sub := range subsystems {
+		if err := manager.Register(sub); err != nil {
+			t.Fatalf("unable to register subsystem: %v", err)
+		}
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatalf("unable to start subsystems: %v", err)
+	}
+	for _, status := range manager.Status() {
+		if status.State != subsystemRunning {
+			t.Fatalf("subsystem %v not running: %v", status.Name,
+				status.State)
+		}
+	}
+
+	manager.Stop()
+
+	expected := []string{
+		"start notifier", "start switch", "start links",
+		"stop links", "stop switch", "stop notifier",
+	}
+	if !reflect.DeepEqual(recorder.events, expected) {
+		t.Fatalf("unexpected order: expected %v, got %v", expected,
+			recorder.events)
+	}
+}
+
+// TestLifecycleManagerStartFailure ensures that if a subsystem fails to start,
+// then all subsystems that were already started are stopped again.
+func TestLifecycleManagerStartFailure(t *testing.T) {
+	t.Parallel()
+
+	recorder := &lifecycleRecorder{}
+	manager := newLifecycleManager()
+
+	failing := recorder.newTestSubsystem("switch", "notifier")
+	failing.start = func() error {
+		return errors.New("unable to start")
+	}
+	subsystems := []*subsystem{
+		recorder.newTestSubsystem("notifier"),
+		failing,
+		recorder.newTestSubsystem("links", "switch"),
+	}
+	for _, sub := range subsystems {
+		if err := manager.Register(sub); err != nil {
+			t.Fatalf("unable to register subsystem: %v", err)
+		}
+	}
+
+	if err := manager.Start(); err == nil {
+		t.Fatalf("expected start to fail")
+	}
+
+	expected := []string{"start notifier", "stop notifier"}
+	if !reflect.DeepEqual(recorder.events, expected) {
+		t.Fatalf("unexpected order: expected %v, got %v", expected,
+			recorder.events)
+	}
+
+	for _, status := range manager.Status() {
+		if status.Name == "switch" && status.State != subsystemFailed {
+			t.Fatalf("switch should have failed, is %v",
+				status.State)
+		}
+	}
+}
+
+// TestLifecycleManagerStopTimeout ensures that a subsystem that hangs while
+// stopping doesn't prevent its dependencies from being stopped.
+func TestLifecycleManagerStopTimeout(t *testing.T) {
+	t.Parallel()
+
+	recorder := &lifecycleRecorder{}
+	manager := newLifecycleManager()
+
+	hung := recorder.newTestSubsystem("links", "notifier")
+	hung.timeout = 100 * time.Millisecond
+	block := make(chan struct{})
+	defer close(block)
+	hung.stop = func() error {
+		<-block
+		return nil
+	}
+
+	for _, sub := range []*subsystem{
+		recorder.newTestSubsystem("notifier"), hung,
+	} {
+		if err := manager.Register(sub); err != nil {
+			t.Fatalf("unable to register subsystem: %v", err)
+		}
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatalf("unable to start subsystems: %v", err)
+	}
+	manager.Stop()
+
+	expected := []string{"start notifier", "start links", "stop notifier"}
+	if !reflect.DeepEqual(recorder.events, expected) {
+		t.Fatalf("unexpected order: expected %v, got %v", expected,
+			recorder.events)
+	}
+
+	for _, status := range manager.Status() {
+		if status.Name == "links" && status.State != subsystemTimedOut {
+			t.Fatalf("links should have timed out, is %v",
+				status.State)
+		}
+	}
+}
+
+// TestLifecycleManagerInvalidDeps ensures that unknown and cyclic
+// dependencies are rejected.
+func TestLifecycleManagerInvalidDeps(t *testing.T) {
+	t.Parallel()
+
+	recorder := &lifecycleRecorder{}
+
+	manager := newLifecycleManager()
+	manager.Register(recorder.newTestSubsystem("switch", "notifier"))
+	if err := manager.Start(); err == nil {
+		t.Fatalf("expected unknown dependency to be rejected")
+	}
+
+	manager = newLifecycleManager()
+	manager.Register(recorder.newTestSubsystem("switch", "links"))
+	manager.Register(recorder.newTestSubsystem("links", "switch"))
+	if err := manager.Start(); err == nil {
+		t.Fatalf("expected dependency cycle to be rejected")
+	}
+
+	if len(recorder.events) != 0 {
+		t.Fatalf("no subsystems should have been started: %v",
+			recorder.events)
+	}
+}
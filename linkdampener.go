@@ -0,0 +1,109 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// linkStablePeriod is the amount of time the link of a channel must have been
+// up for before it's restarted, for the restart not to be considered a flap.
+const linkStablePeriod = time.Minute
+
+// linkFlapState tracks the restarts of the link of a single channel.
+type linkFlapState struct {
+	// flaps is the number of consecutive restarts of the link which
+	// occurred within linkStablePeriod of the previous one.
+	flaps uint32
+
+	// lastStart is the time at which the link was last started, or is
+	// scheduled to be started should its restart have been delayed.
+	lastStart time.Time
+}
+
+// linkDampener dampens the restarts of the links of channels whose peer
+// rapidly connects and disconnects. Each reconnection tears down the links of
+// the peer's channels and starts them anew, which re-registers the channels
+// with the chain arbitrator and replays the channel reestablishment. A single
+// restart of a link within linkStablePeriod of its previous start is
+// permitted immediately, while each further one is delayed by an
+// exponentially increasing backoff, up to a ceiling.
+type linkDampener struct {
+	// baseBackoff is the delay imposed on the first dampened restart of a
+	// link, which doubles with each further one. If zero, then restarts
+	// aren't dampened.
+	baseBackoff time.Duration
+
+	// maxBackoff is the ceiling of the delay imposed on a restart.
+	maxBackoff time.Duration
+
+	// now returns the current time. It's replaced within tests.
+	now func() time.Time
+
+	channels map[wire.OutPoint]*linkFlapState
+	mu       sync.Mutex
+}
+
+// newLinkDampener creates a new linkDampener which delays the restarts of
+// flapping links by the passed base backoff, doubled with each further flap
+// up to the maximum backoff.
+func newLinkDampener(baseBackoff, maxBackoff time.Duration) *linkDampener {
+	return &linkDampener{
+		baseBackoff: baseBackoff,
+		maxBackoff:  maxBackoff,
+		now:         time.Now,
+		channels:    make(map[wire.OutPoint]*linkFlapState),
+	}
+}
+
+// restartDelay records that the link of the target channel is about to be
+// started, and returns the amount of time its start should be delayed by. The
+// link is expected to be started once the delay has elapsed.
+func (d *linkDampener) restartDelay(chanPoint wire.OutPoint) time.Duration {
+	if d.baseBackoff == 0 {
+		return 0
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+
+	// We'll first forget the channels whose link has been stable, as their
+	// next restart won't be dampened anyway.
+	for op, state := range d.channels {
+		if now.Sub(state.lastStart) >= linkStablePeriod {
+			delete(d.channels, op)
+		}
+	}
+
+	state, ok := d.channels[chanPoint]
+	if !ok {
+		d.channels[chanPoint] = &linkFlapState{lastStart: now}
+		return 0
+	}
+
+	// The link was started recently, so this restart is a flap. The
+	// first one is tolerated, as a peer may legitimately reconnect once
+	// shortly after connecting, e.g. when both sides dial each other.
+	state.flaps++
+	if state.flaps == 1 {
+		state.lastStart = now
+		return 0
+	}
+
+	backoff := d.baseBackoff
+	for i := uint32(2); i < state.flaps && backoff < d.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > d.maxBackoff {
+		backoff = d.maxBackoff
+	}
+
+	// As the link won't be started until the backoff has elapsed, the
+	// period it must remain stable for is measured from then.
+	state.lastStart = now.Add(backoff)
+
+	return backoff
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestLinkDampener tests that the linkDampener tolerates a single quick
+// restart of a link, delays further ones by an exponentially increasing
+// backoff up to its ceiling, and forgets a link once it has been stable.
+func TestLinkDampener(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	dampener := newLinkDampener(time.Second*5, time.Second*30)
+	dampener.now = func() time.Time {
+		return now
+	}
+
+	chanA := wire.OutPoint{Index: 1}
+	chanB := wire.OutPoint{Index: 2}
+
+	assertDelay := func(chanPoint wire.OutPoint, expected time.Duration) {
+		delay := dampener.restartDelay(chanPoint)
+		if delay != expected {
+			t.Fatalf("expected delay of %v for ChannelPoint(%v), "+
+				"got %v", expected, chanPoint, delay)
+		}
+
+		// We'll advance the clock past the delay, as the link would
+		// only be started once it has elapsed.
+		now = now.Add(delay + time.Second)
+	}
+
+	// The initial start and the first restart of a link shouldn't be
+	// delayed.
+	assertDelay(chanA, 0)
+	assertDelay(chanA, 0)
+
+	// Each further restart should be delayed by twice as long as the
+	// last, up to the ceiling.
+	assertDelay(chanA, time.Second*5)
+	assertDelay(chanA, time.Second*10)
+	assertDelay(chanA, time.Second*20)
+	assertDelay(chanA, time.Second*30)
+	assertDelay(chanA, time.Second*30)
+
+	// The restarts of another channel's link should be dampened
+	// independently.
+	assertDelay(chanB, 0)
+	assertDelay(chanB, 0)
+	assertDelay(chanB, time.Second*5)
+
+	// Once the link has been up for long enough, its next restart
+	// shouldn't be dampened.
+	now = now.Add(linkStablePeriod)
+	assertDelay(chanA, 0)
+	assertDelay(chanA, 0)
+	assertDelay(chanA, time.Second*5)
+
+	// A dampener with no base backoff shouldn't dampen restarts at all.
+	dampener = newLinkDampener(0, 0)
+	for i := 0; i < 5; i++ {
+		assertDelay(chanA, 0)
+	}
+}
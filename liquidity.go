@@ -0,0 +1,170 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+const (
+	// defaultLiquidityHistoryInterval is the default interval between two
+	// consecutive snapshots of the liquidity of our channels.
+	defaultLiquidityHistoryInterval = time.Hour
+
+	// defaultLiquidityHistoryRetention is the default amount of time for
+	// which liquidity snapshots are retained.
+	defaultLiquidityHistoryRetention = 90 * 24 * time.Hour
+)
+
+// liquidityRecorderConfig houses the dependencies and parameters of the
+// liquidityRecorder.
+type liquidityRecorderConfig struct {
+	// FetchChannels returns all of our open channels.
+	FetchChannels func() ([]*channeldb.OpenChannel, error)
+
+	// RecordSnapshots persists the passed snapshots, keyed by the channel
+	// point of the channel they were taken of.
+	RecordSnapshots func(map[wire.OutPoint]*channeldb.LiquiditySnapshot) error
+
+	// PruneSnapshots removes all snapshots taken prior to the passed
+	// time.
+	PruneSnapshots func(time.Time) error
+
+	// Interval is the time between two consecutive snapshots.
+	Interval time.Duration
+
+	// Retention is the amount of time for which snapshots are retained.
+	// A value of zero retains them indefinitely.
+	Retention time.Duration
+}
+
+// liquidityRecorder periodically records a snapshot of the balances and
+// pending HTLCs of each of our channels within the channel database, building
+// up a time series of their liquidity. The series can be queried over RPC, in
+// order to visualize how the liquidity of a channel evolves, or to inform
+// decisions on rebalancing and fees.
+type liquidityRecorder struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg liquidityRecorderConfig
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newLiquidityRecorder creates a new liquidityRecorder from the passed
+// config.
+func newLiquidityRecorder(cfg liquidityRecorderConfig) *liquidityRecorder {
+	return &liquidityRecorder{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine that periodically records the liquidity of
+// our channels.
+func (l *liquidityRecorder) Start() error {
+	if !atomic.CompareAndSwapUint32(&l.started, 0, 1) {
+		return nil
+	}
+
+	srvrLog.Infof("Starting channel liquidity history, interval=%v, "+
+		"retention=%v", l.cfg.Interval, l.cfg.Retention)
+
+	l.wg.Add(1)
+	go l.recorder()
+
+	return nil
+}
+
+// Stop signals the recorder to exit, and waits for it to do so.
+func (l *liquidityRecorder) Stop() error {
+	if !atomic.CompareAndSwapUint32(&l.stopped, 0, 1) {
+		return nil
+	}
+
+	close(l.quit)
+	l.wg.Wait()
+
+	return nil
+}
+
+// recorder is the main goroutine of the liquidityRecorder. It records a
+// snapshot of our channels on each tick of the configured interval.
+//
+// NOTE: This MUST be run as a goroutine.
+func (l *liquidityRecorder) recorder() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(l.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := l.record(time.Now()); err != nil {
+				srvrLog.Errorf("Unable to record channel "+
+					"liquidity: %v", err)
+			}
+
+		case <-l.quit:
+			return
+		}
+	}
+}
+
+// record records a snapshot of each of our channels as of the passed time,
+// then prunes the snapshots that have exceeded the retention period.
+func (l *liquidityRecorder) record(now time.Time) error {
+	channels, err := l.cfg.FetchChannels()
+	if err != nil {
+		return err
+	}
+
+	snapshots := make(
+		map[wire.OutPoint]*channeldb.LiquiditySnapshot, len(channels),
+	)
+	for _, channel := range channels {
+		snapshots[channel.FundingOutpoint] = liquiditySnapshot(
+			channel, now,
+		)
+	}
+
+	if len(snapshots) != 0 {
+		if err := l.cfg.RecordSnapshots(snapshots); err != nil {
+			return err
+		}
+	}
+
+	if l.cfg.Retention == 0 {
+		return nil
+	}
+
+	return l.cfg.PruneSnapshots(now.Add(-l.cfg.Retention))
+}
+
+// liquiditySnapshot returns a snapshot of the liquidity of the passed channel
+// as of the passed time, as reflected by our latest commitment.
+func liquiditySnapshot(channel *channeldb.OpenChannel,
+	now time.Time) *channeldb.LiquiditySnapshot {
+
+	commit := channel.LocalCommitment
+
+	var pending lnwire.MilliSatoshi
+	for _, htlc := range commit.Htlcs {
+		pending += htlc.Amt
+	}
+
+	return &channeldb.LiquiditySnapshot{
+		Timestamp:        now,
+		LocalBalance:     commit.LocalBalance,
+		RemoteBalance:    commit.RemoteBalance,
+		PendingHTLCValue: pending,
+		NumPendingHTLCs:  uint16(len(commit.Htlcs)),
+	}
+}
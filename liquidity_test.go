@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestLiquidityRecorder ensures that the liquidity recorder snapshots the
+// balances and pending HTLCs of each channel, and prunes the snapshots which
+// have exceeded the retention period.
+func TestLiquidityRecorder(t *testing.T) {
+	t.Parallel()
+
+	channel := &channeldb.OpenChannel{
+		FundingOutpoint: wire.OutPoint{Index: 1},
+		LocalCommitment: channeldb.ChannelCommitment{
+			LocalBalance:  5000,
+			RemoteBalance: 3000,
+			Htlcs: []channeldb.HTLC{
+				{Amt: 1000},
+				{Amt: 500, Incoming: true},
+			},
+		},
+	}
+
+	var (
+		recorded map[wire.OutPoint]*channeldb.LiquiditySnapshot
+		prunedAt time.Time
+	)
+	recorder := newLiquidityRecorder(liquidityRecorderConfig{
+		FetchChannels: func() ([]*channeldb.OpenChannel, error) {
+			return []*channeldb.OpenChannel{channel}, nil
+		},
+		RecordSnapshots: func(s map[wire.OutPoint]*channeldb.LiquiditySnapshot) error {
+			recorded = s
+			return nil
+		},
+		PruneSnapshots: func(before time.Time) error {
+			prunedAt = before
+			return nil
+		},
+		Interval:  time.Hour,
+		Retention: 24 * time.Hour,
+	})
+
+	now := time.Now()
+	if err := recorder.record(now); err != nil {
+		t.Fatalf("unable to record liquidity: %v", err)
+	}
+
+	snapshot, ok := recorded[channel.FundingOutpoint]
+	if !ok || len(recorded) != 1 {
+		t.Fatalf("expected a single snapshot of the channel, got %v",
+			recorded)
+	}
+	expected := channeldb.LiquiditySnapshot{
+		Timestamp:        now,
+		LocalBalance:     5000,
+		RemoteBalance:    3000,
+		PendingHTLCValue: lnwire.MilliSatoshi(1500),
+		NumPendingHTLCs:  2,
+	}
+	if *snapshot != expected {
+		t.Fatalf("expected snapshot %v, got %v", expected, *snapshot)
+	}
+
+	if !prunedAt.Equal(now.Add(-24 * time.Hour)) {
+		t.Fatalf("expected snapshots prior to %v to be pruned, got %v",
+			now.Add(-24*time.Hour), prunedAt)
+	}
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/walletunlocker"
 	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
 )
 
@@ -135,13 +136,28 @@ func lndMain() error {
 
 	// Open the channeldb, which is dedicated to storing channel, and
 	// network related metadata.
-	chanDB, err := channeldb.Open(cfg.DataDir)
+	var dbOptions []channeldb.OptionModifier
+	if cfg.ForceUnlockDB {
+		dbOptions = append(dbOptions, channeldb.OptionForceLock())
+	}
+	chanDB, err := channeldb.Open(cfg.DataDir, dbOptions...)
 	if err != nil {
 		ltndLog.Errorf("unable to open channeldb: %v", err)
+		if _, ok := err.(*channeldb.ErrDBLocked); ok {
+			ltndLog.Errorf("Another instance of lnd appears to be " +
+				"using the same data directory. If certain " +
+				"that it isn't, restart with --forceunlockdb")
+		}
 		return err
 	}
 	defer chanDB.Close()
 
+	// If the user has configured a commit hook command, then we'll have it
+	// executed each time the commitment state of a channel is persisted.
+	if cfg.CommitHookCmd != "" {
+		chanDB.RegisterCommitHook(&commitHookCmd{cmd: cfg.CommitHookCmd})
+	}
+
 	// Only process macaroons if --no-macaroons isn't set.
 	var macaroonService *bakery.Service
 	if !cfg.NoMacaroons {
@@ -292,6 +308,7 @@ func lndMain() error {
 			return nil, fmt.Errorf("unable to find channel")
 		},
 		DefaultRoutingPolicy: activeChainControl.routingPolicy,
+		PeerRoutingPolicy:    activeChainControl.routingPolicyForPeer,
 		NumRequiredConfs: func(chanAmt btcutil.Amount,
 			pushAmt lnwire.MilliSatoshi) uint16 {
 			// For large channels we increase the number
@@ -358,7 +375,40 @@ func lndMain() error {
 			}
 			return delay
 		},
-		WatchNewChannel: server.chainArb.WatchNewChannel,
+		RequiredRemoteChanReserve: func(chanAmt,
+			proposedReserve btcutil.Amount) btcutil.Amount {
+
+			// By default, we'll require the initiator to maintain
+			// the default reserve of 1% of the channel capacity.
+			// If configured to do so, we'll instead match a lower
+			// reserve proposed by the initiator.
+			if cfg.MatchChanReserve && proposedReserve < chanAmt/100 {
+				return proposedReserve
+			}
+
+			return 0
+		},
+		RequiredRemoteMaxValue: func(
+			chanAmt btcutil.Amount) lnwire.MilliSatoshi {
+
+			// A zero value will cause the default of the full
+			// channel capacity, less the reserve, to be used.
+			pct := btcutil.Amount(cfg.MaxValueInFlightPct)
+			return lnwire.NewMSatFromSatoshis(chanAmt * pct / 100)
+		},
+		AnnouncementDepth: cfg.AnnouncementDepth,
+		MinAcceptedMaxValue: func(
+			chanAmt btcutil.Amount) lnwire.MilliSatoshi {
+
+			pct := btcutil.Amount(cfg.MinAcceptedValueInFlightPct)
+			return lnwire.NewMSatFromSatoshis(chanAmt * pct / 100)
+		},
+		ChainHealthy:      server.chainHealth.IsHealthy,
+		AcceptChannelFrom: server.checkPeerScore,
+		WatchNewChannel:   server.chainArb.WatchNewChannel,
+		NotifyOpenChannelEvent: func(chanPoint wire.OutPoint) {
+			server.channelNotifier.NotifyOpenChannelEvent(chanPoint)
+		},
 	})
 	if err != nil {
 		return err
@@ -368,15 +418,56 @@ func lndMain() error {
 	}
 	server.fundingMgr = fundingMgr
 
+	err = server.lifecycle.Register(&subsystem{
+		name: "fundingmanager",
+		deps: []string{"chainnotifier", "wallet", "gossiper"},
+		stop: fundingMgr.Stop,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Before we create the RPC server, we'll gather the configuration of
+	// each of the optional sub-servers that have been compiled in, so
+	// they can be initialized alongside the main RPC server.
+	subServerCgs, err := newSubRPCServerConfigs(
+		server, activeChainControl, macaroonService,
+	)
+	if err != nil {
+		return err
+	}
+
 	// Initialize, and register our implementation of the gRPC interface
 	// exported by the rpcServer.
-	rpcServer := newRPCServer(server, macaroonService)
+	rpcServer, err := newRPCServer(server, macaroonService, subServerCgs)
+	if err != nil {
+		return err
+	}
 	if err := rpcServer.Start(); err != nil {
 		return err
 	}
 
+	err = server.lifecycle.Register(&subsystem{
+		name: "rpcserver",
+		deps: []string{
+			"fundingmanager", "htlcswitch", "router", "gossiper",
+			"chainarb", "wallet",
+		},
+		stop: rpcServer.Stop,
+	})
+	if err != nil {
+		return err
+	}
+
+	if subServers := lnrpc.SupportedServers(); len(subServers) != 0 {
+		ltndLog.Infof("Active sub RPC servers: %v", subServers)
+	}
+
+	serverOpts = append(serverOpts, rpcServer.serverOpts()...)
 	grpcServer := grpc.NewServer(serverOpts...)
-	lnrpc.RegisterLightningServer(grpcServer, rpcServer)
+	if err := rpcServer.RegisterWithGrpcServer(grpcServer); err != nil {
+		return err
+	}
 
 	// Next, Start the gRPC server listening for HTTP/2 connections.
 	for _, listener := range cfg.RPCListeners {
@@ -477,8 +568,6 @@ func lndMain() error {
 
 	addInterruptHandler(func() {
 		ltndLog.Infof("Gracefully shutting down the server...")
-		rpcServer.Stop()
-		fundingMgr.Stop()
 		server.Stop()
 
 		if pilot != nil {
@@ -664,7 +753,7 @@ func genMacaroons(svc *bakery.Service, admFile, roFile string) error {
 
 	// Generate the read-only macaroon and write it to a file.
 	roMacaroon, err := macaroons.AddConstraints(admMacaroon,
-		macaroons.AllowConstraint(roPermissions...))
+		macaroons.AllowConstraint(readOnlyPermissions()...))
 	if err != nil {
 		return err
 	}
@@ -2474,6 +2474,146 @@ func testMultiHopPayments(net *lntest.NetworkHarness, t *harnessTest) {
 	}
 }
 
+// testSwitchFaultInjection tests that a payment forwarded through a node
+// whose switch delays the add, and duplicates the settle, still completes,
+// with the funds within each channel shifted exactly once.
+func testSwitchFaultInjection(net *lntest.NetworkHarness, t *harnessTest) {
+	const (
+		chanAmt    = btcutil.Amount(100000)
+		paymentAmt = 1000
+		baseFee    = 1
+	)
+	ctxb := context.Background()
+	timeout := time.Duration(time.Second * 15)
+
+	// We'll create Carol, who'll forward a payment from Alice to Bob
+	// while faults are injected into her switch, such that the topology
+	// looks like:
+	//     Alice -> Carol -> Bob
+	carol, err := net.NewNode([]string{"--debugswitchfaults"})
+	if err != nil {
+		t.Fatalf("unable to create new node: %v", err)
+	}
+	if err := net.ConnectNodes(ctxb, net.Alice, carol); err != nil {
+		t.Fatalf("unable to connect alice to carol: %v", err)
+	}
+	if err := net.ConnectNodes(ctxb, carol, net.Bob); err != nil {
+		t.Fatalf("unable to connect carol to bob: %v", err)
+	}
+	err = net.SendCoins(ctxb, btcutil.SatoshiPerBitcoin, carol)
+	if err != nil {
+		t.Fatalf("unable to send coins to carol: %v", err)
+	}
+
+	ctxt, _ := context.WithTimeout(ctxb, timeout)
+	chanPointAlice := openChannelAndAssert(ctxt, t, net, net.Alice,
+		carol, chanAmt, 0)
+	ctxt, _ = context.WithTimeout(ctxb, timeout)
+	chanPointCarol := openChannelAndAssert(ctxt, t, net, carol,
+		net.Bob, chanAmt, 0)
+
+	nodes := []*lntest.HarnessNode{net.Alice, net.Bob, carol}
+	for _, chanPoint := range []*lnrpc.ChannelPoint{
+		chanPointAlice, chanPointCarol,
+	} {
+		for _, node := range nodes {
+			ctxt, _ = context.WithTimeout(ctxb, timeout)
+			err = node.WaitForNetworkChannelOpen(ctxt, chanPoint)
+			if err != nil {
+				t.Fatalf("node %d: timeout waiting for channel "+
+					"open: %v", node.NodeID, err)
+			}
+		}
+	}
+
+	// Carol will hold the add for a second before forwarding it, and
+	// will propagate the settle back to Alice twice.
+	faults := []*lnrpc.InjectSwitchFaultRequest{
+		{
+			Action:     "delay",
+			UpdateType: "add",
+			DelayMs:    1000,
+		},
+		{
+			Action:     "duplicate",
+			UpdateType: "settle",
+		},
+	}
+	for _, req := range faults {
+		_, err := carol.InjectSwitchFault(ctxb, req)
+		if err != nil {
+			t.Fatalf("unable to inject switch fault: %v", err)
+		}
+	}
+
+	invoice := &lnrpc.Invoice{
+		Memo:  "faulty",
+		Value: paymentAmt,
+	}
+	resp, err := net.Bob.AddInvoice(ctxb, invoice)
+	if err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	ctxt, _ = context.WithTimeout(ctxb, timeout)
+	err = completePaymentRequests(
+		ctxt, net.Alice, []string{resp.PaymentRequest}, true,
+	)
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	// Both faults should have been applied by now.
+	clearResp, err := carol.InjectSwitchFault(
+		ctxb, &lnrpc.InjectSwitchFaultRequest{Action: "drop", Count: 0},
+	)
+	if err != nil {
+		t.Fatalf("unable to inject switch fault: %v", err)
+	}
+	if clearResp.NumPending != 1 {
+		t.Fatalf("expected only the new fault to be pending, "+
+			"instead %v are", clearResp.NumPending)
+	}
+	_, err = carol.InjectSwitchFault(
+		ctxb, &lnrpc.InjectSwitchFaultRequest{Clear: true},
+	)
+	if err != nil {
+		t.Fatalf("unable to clear switch faults: %v", err)
+	}
+
+	// The payment, and Carol's fee, should have been transferred exactly
+	// once within each channel, despite the duplicated settle.
+	toOutPoint := func(chanPoint *lnrpc.ChannelPoint) wire.OutPoint {
+		txid, err := chainhash.NewHash(chanPoint.FundingTxid)
+		if err != nil {
+			t.Fatalf("unable to create sha hash: %v", err)
+		}
+		return wire.OutPoint{
+			Hash:  *txid,
+			Index: chanPoint.OutputIndex,
+		}
+	}
+	aliceFundPoint := toOutPoint(chanPointAlice)
+	carolFundPoint := toOutPoint(chanPointCarol)
+	assertAmountPaid(t, ctxb, "Carol(local) => Bob(remote)", net.Bob,
+		carolFundPoint, int64(0), paymentAmt)
+	assertAmountPaid(t, ctxb, "Carol(local) => Bob(remote)", carol,
+		carolFundPoint, paymentAmt, int64(0))
+	assertAmountPaid(t, ctxb, "Alice(local) => Carol(remote)", carol,
+		aliceFundPoint, int64(0), paymentAmt+baseFee)
+	assertAmountPaid(t, ctxb, "Alice(local) => Carol(remote)", net.Alice,
+		aliceFundPoint, paymentAmt+baseFee, int64(0))
+
+	ctxt, _ = context.WithTimeout(ctxb, timeout)
+	closeChannelAndAssert(ctxt, t, net, net.Alice, chanPointAlice, false)
+	ctxt, _ = context.WithTimeout(ctxb, timeout)
+	closeChannelAndAssert(ctxt, t, net, carol, chanPointCarol, false)
+
+	if err := net.ShutdownNode(carol); err != nil {
+		t.Fatalf("unable to shutdown carol: %v", err)
+	}
+}
+
 // testPrivateChannels tests that a private channel can be used for
 // routing by the two endpoints of the channel, but is not known by
 // the rest of the nodes in the graph.
@@ -6431,6 +6571,10 @@ var testsCases = []*testCase{
 		name: "test multi-hop htlc remote chain claim",
 		test: testMultiHopHtlcRemoteChainClaim,
 	},
+	{
+		name: "switch fault injection",
+		test: testSwitchFaultInjection,
+	},
 	{
 		// TODO(roasbeef): test always needs to be last as Bob's state
 		// is borked since we trick him into attempting to cheat Alice?
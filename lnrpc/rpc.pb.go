@@ -49,6 +49,7 @@ It has these top-level messages:
 	CloseChannelRequest
 	CloseStatusUpdate
 	PendingUpdate
+	ForceClosePreview
 	OpenChannelRequest
 	OpenStatusUpdate
 	PendingHTLC
@@ -81,6 +82,7 @@ It has these top-level messages:
 	ChannelEdgeUpdate
 	ClosedChannelUpdate
 	Invoice
+	InvoiceHTLC
 	AddInvoiceResponse
 	PaymentHash
 	ListInvoiceRequest
@@ -100,6 +102,58 @@ It has these top-level messages:
 	FeeReportResponse
 	PolicyUpdateRequest
 	PolicyUpdateResponse
+	ExportGraphRequest
+	ExportGraphResponse
+	ImportGraphRequest
+	ImportGraphResponse
+	ForwardingFilterRequest
+	UpdateForwardingFilterRequest
+	ForwardingFilterResponse
+	PeerMessageSubscription
+	PeerMessage
+	LiquidityHistoryRequest
+	LiquiditySnapshot
+	LiquidityHistoryResponse
+	InjectSwitchFaultRequest
+	InjectSwitchFaultResponse
+	UpdateChanStatusRequest
+	UpdateChanStatusResponse
+	SettleHoldInvoiceRequest
+	SettleHoldInvoiceResponse
+	CancelHoldInvoiceResponse
+	ForwardingHistoryRequest
+	ForwardingEvent
+	ForwardingHistoryResponse
+	BatchAddInvoiceRequest
+	BatchAddInvoiceResponse
+	ChannelPointList
+	FailedUpdate
+	TimeLockedBalanceRequest
+	TimeLockedBucket
+	TimeLockedBalanceResponse
+	ExportDebugPackageRequest
+	ExportDebugPackageResponse
+	ChannelEventSubscription
+	ChannelCloseSummary
+	ChannelEventUpdate
+	DebugProfileRequest
+	DebugProfileResponse
+	LookupCircuitRequest
+	PaymentCircuit
+	LookupCircuitResponse
+	PeerCompatibilityRequest
+	ChannelCompatibility
+	PeerCompatibilityResponse
+	ListAliasesRequest
+	ChannelAliases
+	AliasMapping
+	ListAliasesResponse
+	InspectCommitmentsRequest
+	CommitmentState
+	UnackedUpdate
+	ChanSyncFields
+	InspectCommitmentsResponse
+	SendToRouteRequest
 */
 package lnrpc
 
@@ -150,6 +204,145 @@ func (NewAddressRequest_AddressType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor0, []int{15, 0}
 }
 
+type ChannelCloseSummary_ClosureType int32
+
+const (
+	ChannelCloseSummary_COOPERATIVE_CLOSE ChannelCloseSummary_ClosureType = 0
+	ChannelCloseSummary_FORCE_CLOSE       ChannelCloseSummary_ClosureType = 1
+	ChannelCloseSummary_BREACH_CLOSE      ChannelCloseSummary_ClosureType = 2
+	ChannelCloseSummary_FUNDING_CANCELED  ChannelCloseSummary_ClosureType = 3
+)
+
+var ChannelCloseSummary_ClosureType_name = map[int32]string{
+	0: "COOPERATIVE_CLOSE",
+	1: "FORCE_CLOSE",
+	2: "BREACH_CLOSE",
+	3: "FUNDING_CANCELED",
+}
+var ChannelCloseSummary_ClosureType_value = map[string]int32{
+	"COOPERATIVE_CLOSE": 0,
+	"FORCE_CLOSE":       1,
+	"BREACH_CLOSE":      2,
+	"FUNDING_CANCELED":  3,
+}
+
+func (x ChannelCloseSummary_ClosureType) String() string {
+	return proto.EnumName(ChannelCloseSummary_ClosureType_name, int32(x))
+}
+func (ChannelCloseSummary_ClosureType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{126, 0}
+}
+
+type ChannelEventUpdate_UpdateType int32
+
+const (
+	ChannelEventUpdate_OPEN_CHANNEL     ChannelEventUpdate_UpdateType = 0
+	ChannelEventUpdate_CLOSED_CHANNEL   ChannelEventUpdate_UpdateType = 1
+	ChannelEventUpdate_ACTIVE_CHANNEL   ChannelEventUpdate_UpdateType = 2
+	ChannelEventUpdate_INACTIVE_CHANNEL ChannelEventUpdate_UpdateType = 3
+)
+
+var ChannelEventUpdate_UpdateType_name = map[int32]string{
+	0: "OPEN_CHANNEL",
+	1: "CLOSED_CHANNEL",
+	2: "ACTIVE_CHANNEL",
+	3: "INACTIVE_CHANNEL",
+}
+var ChannelEventUpdate_UpdateType_value = map[string]int32{
+	"OPEN_CHANNEL":     0,
+	"CLOSED_CHANNEL":   1,
+	"ACTIVE_CHANNEL":   2,
+	"INACTIVE_CHANNEL": 3,
+}
+
+func (x ChannelEventUpdate_UpdateType) String() string {
+	return proto.EnumName(ChannelEventUpdate_UpdateType_name, int32(x))
+}
+func (ChannelEventUpdate_UpdateType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{127, 0}
+}
+
+type DebugProfileRequest_ProfileType int32
+
+const (
+	DebugProfileRequest_GOROUTINE DebugProfileRequest_ProfileType = 0
+	DebugProfileRequest_HEAP      DebugProfileRequest_ProfileType = 1
+	DebugProfileRequest_CPU       DebugProfileRequest_ProfileType = 2
+)
+
+var DebugProfileRequest_ProfileType_name = map[int32]string{
+	0: "GOROUTINE",
+	1: "HEAP",
+	2: "CPU",
+}
+var DebugProfileRequest_ProfileType_value = map[string]int32{
+	"GOROUTINE": 0,
+	"HEAP":      1,
+	"CPU":       2,
+}
+
+func (x DebugProfileRequest_ProfileType) String() string {
+	return proto.EnumName(DebugProfileRequest_ProfileType_name, int32(x))
+}
+func (DebugProfileRequest_ProfileType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{128, 0}
+}
+
+type Invoice_InvoiceState int32
+
+const (
+	Invoice_OPEN     Invoice_InvoiceState = 0
+	Invoice_SETTLED  Invoice_InvoiceState = 1
+	Invoice_CANCELED Invoice_InvoiceState = 2
+	Invoice_ACCEPTED Invoice_InvoiceState = 3
+)
+
+var Invoice_InvoiceState_name = map[int32]string{
+	0: "OPEN",
+	1: "SETTLED",
+	2: "CANCELED",
+	3: "ACCEPTED",
+}
+var Invoice_InvoiceState_value = map[string]int32{
+	"OPEN":     0,
+	"SETTLED":  1,
+	"CANCELED": 2,
+	"ACCEPTED": 3,
+}
+
+func (x Invoice_InvoiceState) String() string {
+	return proto.EnumName(Invoice_InvoiceState_name, int32(x))
+}
+func (Invoice_InvoiceState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{73, 0}
+}
+
+type InvoiceHTLC_HTLCState int32
+
+const (
+	InvoiceHTLC_ACCEPTED InvoiceHTLC_HTLCState = 0
+	InvoiceHTLC_SETTLED  InvoiceHTLC_HTLCState = 1
+	InvoiceHTLC_CANCELED InvoiceHTLC_HTLCState = 2
+)
+
+var InvoiceHTLC_HTLCState_name = map[int32]string{
+	0: "ACCEPTED",
+	1: "SETTLED",
+	2: "CANCELED",
+}
+var InvoiceHTLC_HTLCState_value = map[string]int32{
+	"ACCEPTED": 0,
+	"SETTLED":  1,
+	"CANCELED": 2,
+}
+
+func (x InvoiceHTLC_HTLCState) String() string {
+	return proto.EnumName(InvoiceHTLC_HTLCState_name, int32(x))
+}
+func (InvoiceHTLC_HTLCState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{74, 0}
+}
+
 type CreateWalletRequest struct {
 	Password []byte `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
 }
@@ -321,6 +514,10 @@ type SendRequest struct {
 	PaymentRequest string `protobuf:"bytes,6,opt,name=payment_request,json=paymentRequest" json:"payment_request,omitempty"`
 	// / The CLTV delta from the current height that should be used to set the timelock for the final hop.
 	FinalCltvDelta int32 `protobuf:"varint,7,opt,name=final_cltv_delta,json=finalCltvDelta" json:"final_cltv_delta,omitempty"`
+	// / If set, the route for the payment is found and its onion constructed, but the payment isn't sent. The route is returned along with an estimate of its success probability.
+	DryRun bool `protobuf:"varint,8,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
+	// / If set, a random preimage is generated and included within the onion, allowing the recipient to settle the payment without an invoice. This is an experimental encoding specific to lnd, which requires both the sender and the recipient to enable experimentalkeysend. It may not be combined with payment_hash or payment_request.
+	Keysend bool `protobuf:"varint,9,opt,name=keysend" json:"keysend,omitempty"`
 }
 
 func (m *SendRequest) Reset()                    { *m = SendRequest{} }
@@ -377,10 +574,30 @@ func (m *SendRequest) GetFinalCltvDelta() int32 {
 	return 0
 }
 
+func (m *SendRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *SendRequest) GetKeysend() bool {
+	if m != nil {
+		return m.Keysend
+	}
+	return false
+}
+
 type SendResponse struct {
 	PaymentError    string `protobuf:"bytes,1,opt,name=payment_error" json:"payment_error,omitempty"`
 	PaymentPreimage []byte `protobuf:"bytes,2,opt,name=payment_preimage,proto3" json:"payment_preimage,omitempty"`
 	PaymentRoute    *Route `protobuf:"bytes,3,opt,name=payment_route" json:"payment_route,omitempty"`
+	// / For dry runs, a rough estimate of the probability that the payment would succeed over payment_route, between 0 and 1.
+	SuccessProbability float64 `protobuf:"fixed64,4,opt,name=success_probability" json:"success_probability,omitempty"`
+	// / For payments sent to a route, the hex-encoded public key of the node that reported the failure, if any.
+	FailureSourcePubkey string `protobuf:"bytes,5,opt,name=failure_source_pubkey" json:"failure_source_pubkey,omitempty"`
+	// / For payments sent to a route, the decoded failure code reported by failure_source_pubkey.
+	FailureCode string `protobuf:"bytes,6,opt,name=failure_code" json:"failure_code,omitempty"`
 }
 
 func (m *SendResponse) Reset()                    { *m = SendResponse{} }
@@ -409,6 +626,27 @@ func (m *SendResponse) GetPaymentRoute() *Route {
 	return nil
 }
 
+func (m *SendResponse) GetSuccessProbability() float64 {
+	if m != nil {
+		return m.SuccessProbability
+	}
+	return 0
+}
+
+func (m *SendResponse) GetFailureSourcePubkey() string {
+	if m != nil {
+		return m.FailureSourcePubkey
+	}
+	return ""
+}
+
+func (m *SendResponse) GetFailureCode() string {
+	if m != nil {
+		return m.FailureCode
+	}
+	return ""
+}
+
 type ChannelPoint struct {
 	// / Txid of the funding transaction
 	FundingTxid []byte `protobuf:"bytes,1,opt,name=funding_txid,proto3" json:"funding_txid,omitempty"`
@@ -791,6 +1029,8 @@ type HTLC struct {
 	Amount           int64  `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
 	HashLock         []byte `protobuf:"bytes,3,opt,name=hash_lock,proto3" json:"hash_lock,omitempty"`
 	ExpirationHeight uint32 `protobuf:"varint,4,opt,name=expiration_height" json:"expiration_height,omitempty"`
+	// / For outgoing HTLCs, the number of seconds the HTLC has been unresolved for, as observed by the channel's link
+	Age int64 `protobuf:"varint,5,opt,name=age" json:"age,omitempty"`
 }
 
 func (m *HTLC) Reset()                    { *m = HTLC{} }
@@ -826,6 +1066,13 @@ func (m *HTLC) GetExpirationHeight() uint32 {
 	return 0
 }
 
+func (m *HTLC) GetAge() int64 {
+	if m != nil {
+		return m.Age
+	}
+	return 0
+}
+
 type ActiveChannel struct {
 	// / Whether this channel is active or not
 	Active bool `protobuf:"varint,1,opt,name=active" json:"active,omitempty"`
@@ -879,6 +1126,16 @@ type ActiveChannel struct {
 	// closed, we'll need to wait for this many blocks before we can regain our
 	// funds.
 	CsvDelay uint32 `protobuf:"varint,16,opt,name=csv_delay" json:"csv_delay,omitempty"`
+	// / The additional value of outgoing HTLCs, in millisatoshis, the remote party permits us to add before reaching the channel's max in-flight value
+	LocalInflightHeadroomMsat int64 `protobuf:"varint,17,opt,name=local_inflight_headroom_msat" json:"local_inflight_headroom_msat,omitempty"`
+	// / The additional value of incoming HTLCs, in millisatoshis, we permit the remote party to add before reaching the channel's max in-flight value
+	RemoteInflightHeadroomMsat int64 `protobuf:"varint,18,opt,name=remote_inflight_headroom_msat" json:"remote_inflight_headroom_msat,omitempty"`
+	// / Whether this channel is private, not announced to the greater network.
+	Private bool `protobuf:"varint,19,opt,name=private" json:"private,omitempty"`
+	// / The amount, in millisatoshis, we're currently able to send over this channel, accounting for our reserve and any pending HTLCs
+	SendableMsat int64 `protobuf:"varint,20,opt,name=sendable_msat" json:"sendable_msat,omitempty"`
+	// / The amount, in millisatoshis, the remote party is currently able to send to us over this channel, accounting for its reserve and any pending HTLCs
+	ReceivableMsat int64 `protobuf:"varint,21,opt,name=receivable_msat" json:"receivable_msat,omitempty"`
 }
 
 func (m *ActiveChannel) Reset()                    { *m = ActiveChannel{} }
@@ -998,6 +1255,41 @@ func (m *ActiveChannel) GetCsvDelay() uint32 {
 	return 0
 }
 
+func (m *ActiveChannel) GetLocalInflightHeadroomMsat() int64 {
+	if m != nil {
+		return m.LocalInflightHeadroomMsat
+	}
+	return 0
+}
+
+func (m *ActiveChannel) GetRemoteInflightHeadroomMsat() int64 {
+	if m != nil {
+		return m.RemoteInflightHeadroomMsat
+	}
+	return 0
+}
+
+func (m *ActiveChannel) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
+func (m *ActiveChannel) GetSendableMsat() int64 {
+	if m != nil {
+		return m.SendableMsat
+	}
+	return 0
+}
+
+func (m *ActiveChannel) GetReceivableMsat() int64 {
+	if m != nil {
+		return m.ReceivableMsat
+	}
+	return 0
+}
+
 type ListChannelsRequest struct {
 }
 
@@ -1042,6 +1334,8 @@ type Peer struct {
 	Inbound bool `protobuf:"varint,8,opt,name=inbound" json:"inbound,omitempty"`
 	// / Ping time to this peer
 	PingTime int64 `protobuf:"varint,9,opt,name=ping_time" json:"ping_time,omitempty"`
+	// / The number of messages of an unknown type received from this peer
+	UnknownMsgs uint64 `protobuf:"varint,10,opt,name=unknown_msgs" json:"unknown_msgs,omitempty"`
 }
 
 func (m *Peer) Reset()                    { *m = Peer{} }
@@ -1112,6 +1406,13 @@ func (m *Peer) GetPingTime() int64 {
 	return 0
 }
 
+func (m *Peer) GetUnknownMsgs() uint64 {
+	if m != nil {
+		return m.UnknownMsgs
+	}
+	return 0
+}
+
 type ListPeersRequest struct {
 }
 
@@ -1168,6 +1469,16 @@ type GetInfoResponse struct {
 	Chains []string `protobuf:"bytes,11,rep,name=chains" json:"chains,omitempty"`
 	// / The URIs of the current node.
 	Uris []string `protobuf:"bytes,12,rep,name=uris" json:"uris,omitempty"`
+	// / Whether the chain backend and fee estimator are passing the node's periodic health checks
+	ChainBackendHealthy bool `protobuf:"varint,13,opt,name=chain_backend_healthy" json:"chain_backend_healthy,omitempty"`
+	// / If the chain backend is unhealthy, the error of the most recent failed health check
+	ChainBackendError string `protobuf:"bytes,14,opt,name=chain_backend_error" json:"chain_backend_error,omitempty"`
+	// / The outcome of the startup self-check: disabled, pending, passed or failed
+	SelfCheckStatus string `protobuf:"bytes,15,opt,name=self_check_status" json:"self_check_status,omitempty"`
+	// / The time taken for the self-check payment to be settled, or to fail, in milliseconds
+	SelfCheckLatencyMs int64 `protobuf:"varint,16,opt,name=self_check_latency_ms" json:"self_check_latency_ms,omitempty"`
+	// / If the self-check failed, the reason it did
+	SelfCheckError string `protobuf:"bytes,17,opt,name=self_check_error" json:"self_check_error,omitempty"`
 }
 
 func (m *GetInfoResponse) Reset()                    { *m = GetInfoResponse{} }
@@ -1252,6 +1563,41 @@ func (m *GetInfoResponse) GetUris() []string {
 	return nil
 }
 
+func (m *GetInfoResponse) GetChainBackendHealthy() bool {
+	if m != nil {
+		return m.ChainBackendHealthy
+	}
+	return false
+}
+
+func (m *GetInfoResponse) GetChainBackendError() string {
+	if m != nil {
+		return m.ChainBackendError
+	}
+	return ""
+}
+
+func (m *GetInfoResponse) GetSelfCheckStatus() string {
+	if m != nil {
+		return m.SelfCheckStatus
+	}
+	return ""
+}
+
+func (m *GetInfoResponse) GetSelfCheckLatencyMs() int64 {
+	if m != nil {
+		return m.SelfCheckLatencyMs
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetSelfCheckError() string {
+	if m != nil {
+		return m.SelfCheckError
+	}
+	return ""
+}
+
 type ConfirmationUpdate struct {
 	BlockSha     []byte `protobuf:"bytes,1,opt,name=block_sha,json=blockSha,proto3" json:"block_sha,omitempty"`
 	BlockHeight  int32  `protobuf:"varint,2,opt,name=block_height,json=blockHeight" json:"block_height,omitempty"`
@@ -1336,6 +1682,12 @@ type CloseChannelRequest struct {
 	TargetConf int32 `protobuf:"varint,3,opt,name=target_conf,json=targetConf" json:"target_conf,omitempty"`
 	// / A manual fee rate set in sat/byte that should be used when crafting the closure transaction.
 	SatPerByte int64 `protobuf:"varint,5,opt,name=sat_per_byte,json=satPerByte" json:"sat_per_byte,omitempty"`
+	// / If force is set, then this must also be set in order for the commitment transaction to be broadcast. Otherwise, a preview of the HTLCs that will be resolved on chain, and of the estimated cost of the closure, is returned instead. As the fee of the commitment transaction is fixed, target_conf and sat_per_byte only determine the fee rate used to estimate the cost of sweeping our outputs.
+	ConfirmForce bool `protobuf:"varint,6,opt,name=confirm_force,json=confirmForce" json:"confirm_force,omitempty"`
+	// / An optional raw script that the funds of a cooperative closure should be paid out to. This may be any standard output script, or a segwit output script of any witness version (such as P2TR). May not be set if force is set, or along with delivery_address.
+	DeliveryScript []byte `protobuf:"bytes,7,opt,name=delivery_script,json=deliveryScript,proto3" json:"delivery_script,omitempty"`
+	// / An optional address that the funds of a cooperative closure should be paid out to. May not be set if force is set, or along with delivery_script.
+	DeliveryAddress string `protobuf:"bytes,8,opt,name=delivery_address,json=deliveryAddress" json:"delivery_address,omitempty"`
 }
 
 func (m *CloseChannelRequest) Reset()                    { *m = CloseChannelRequest{} }
@@ -1371,11 +1723,33 @@ func (m *CloseChannelRequest) GetSatPerByte() int64 {
 	return 0
 }
 
+func (m *CloseChannelRequest) GetConfirmForce() bool {
+	if m != nil {
+		return m.ConfirmForce
+	}
+	return false
+}
+
+func (m *CloseChannelRequest) GetDeliveryScript() []byte {
+	if m != nil {
+		return m.DeliveryScript
+	}
+	return nil
+}
+
+func (m *CloseChannelRequest) GetDeliveryAddress() string {
+	if m != nil {
+		return m.DeliveryAddress
+	}
+	return ""
+}
+
 type CloseStatusUpdate struct {
 	// Types that are valid to be assigned to Update:
 	//	*CloseStatusUpdate_ClosePending
 	//	*CloseStatusUpdate_Confirmation
 	//	*CloseStatusUpdate_ChanClose
+	//	*CloseStatusUpdate_ForceClosePreview
 	Update isCloseStatusUpdate_Update `protobuf_oneof:"update"`
 }
 
@@ -1397,10 +1771,14 @@ type CloseStatusUpdate_Confirmation struct {
 type CloseStatusUpdate_ChanClose struct {
 	ChanClose *ChannelCloseUpdate `protobuf:"bytes,3,opt,name=chan_close,oneof"`
 }
+type CloseStatusUpdate_ForceClosePreview struct {
+	ForceClosePreview *ForceClosePreview `protobuf:"bytes,4,opt,name=force_close_preview,oneof"`
+}
 
-func (*CloseStatusUpdate_ClosePending) isCloseStatusUpdate_Update() {}
-func (*CloseStatusUpdate_Confirmation) isCloseStatusUpdate_Update() {}
-func (*CloseStatusUpdate_ChanClose) isCloseStatusUpdate_Update()    {}
+func (*CloseStatusUpdate_ClosePending) isCloseStatusUpdate_Update()      {}
+func (*CloseStatusUpdate_Confirmation) isCloseStatusUpdate_Update()      {}
+func (*CloseStatusUpdate_ChanClose) isCloseStatusUpdate_Update()         {}
+func (*CloseStatusUpdate_ForceClosePreview) isCloseStatusUpdate_Update() {}
 
 func (m *CloseStatusUpdate) GetUpdate() isCloseStatusUpdate_Update {
 	if m != nil {
@@ -1430,12 +1808,20 @@ func (m *CloseStatusUpdate) GetChanClose() *ChannelCloseUpdate {
 	return nil
 }
 
+func (m *CloseStatusUpdate) GetForceClosePreview() *ForceClosePreview {
+	if x, ok := m.GetUpdate().(*CloseStatusUpdate_ForceClosePreview); ok {
+		return x.ForceClosePreview
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*CloseStatusUpdate) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _CloseStatusUpdate_OneofMarshaler, _CloseStatusUpdate_OneofUnmarshaler, _CloseStatusUpdate_OneofSizer, []interface{}{
 		(*CloseStatusUpdate_ClosePending)(nil),
 		(*CloseStatusUpdate_Confirmation)(nil),
 		(*CloseStatusUpdate_ChanClose)(nil),
+		(*CloseStatusUpdate_ForceClosePreview)(nil),
 	}
 }
 
@@ -1458,6 +1844,11 @@ func _CloseStatusUpdate_OneofMarshaler(msg proto.Message, b *proto.Buffer) error
 		if err := b.EncodeMessage(x.ChanClose); err != nil {
 			return err
 		}
+	case *CloseStatusUpdate_ForceClosePreview:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ForceClosePreview); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("CloseStatusUpdate.Update has unexpected type %T", x)
@@ -1492,6 +1883,14 @@ func _CloseStatusUpdate_OneofUnmarshaler(msg proto.Message, tag, wire int, b *pr
 		err := b.DecodeMessage(msg)
 		m.Update = &CloseStatusUpdate_ChanClose{msg}
 		return true, err
+	case 4: // update.force_close_preview
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ForceClosePreview)
+		err := b.DecodeMessage(msg)
+		m.Update = &CloseStatusUpdate_ForceClosePreview{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -1516,6 +1915,11 @@ func _CloseStatusUpdate_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(3<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *CloseStatusUpdate_ForceClosePreview:
+		s := proto.Size(x.ForceClosePreview)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -1547,6 +1951,77 @@ func (m *PendingUpdate) GetOutputIndex() uint32 {
 	return 0
 }
 
+type ForceClosePreview struct {
+	// / The HTLCs within our latest commitment transaction, which will be resolved on chain once it's broadcast.
+	PendingHtlcs []*HTLC `protobuf:"bytes,1,rep,name=pending_htlcs" json:"pending_htlcs,omitempty"`
+	// / The fee paid by our latest commitment transaction, which was fixed when the commitment was signed.
+	CommitFee int64 `protobuf:"varint,2,opt,name=commit_fee" json:"commit_fee,omitempty"`
+	// / The estimated fee of the second-level HTLC transactions and the transactions sweeping our outputs back into the wallet.
+	EstimatedSweepFee int64 `protobuf:"varint,3,opt,name=estimated_sweep_fee" json:"estimated_sweep_fee,omitempty"`
+	// / The fee rate in sat/byte used to estimate the cost of sweeping our outputs.
+	SweepSatPerByte int64 `protobuf:"varint,4,opt,name=sweep_sat_per_byte" json:"sweep_sat_per_byte,omitempty"`
+	// / The number of blocks our outputs will be time-locked for once the commitment transaction confirms.
+	CsvDelay uint32 `protobuf:"varint,5,opt,name=csv_delay" json:"csv_delay,omitempty"`
+	// / The largest absolute height at which one of the pending HTLCs expires.
+	MaxHtlcExpiry uint32 `protobuf:"varint,6,opt,name=max_htlc_expiry" json:"max_htlc_expiry,omitempty"`
+	// / Our balance within the commitment transaction that will be time-locked, excluding HTLCs.
+	LimboBalance int64 `protobuf:"varint,7,opt,name=limbo_balance" json:"limbo_balance,omitempty"`
+}
+
+func (m *ForceClosePreview) Reset()                    { *m = ForceClosePreview{} }
+func (m *ForceClosePreview) String() string            { return proto.CompactTextString(m) }
+func (*ForceClosePreview) ProtoMessage()               {}
+func (*ForceClosePreview) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *ForceClosePreview) GetPendingHtlcs() []*HTLC {
+	if m != nil {
+		return m.PendingHtlcs
+	}
+	return nil
+}
+
+func (m *ForceClosePreview) GetCommitFee() int64 {
+	if m != nil {
+		return m.CommitFee
+	}
+	return 0
+}
+
+func (m *ForceClosePreview) GetEstimatedSweepFee() int64 {
+	if m != nil {
+		return m.EstimatedSweepFee
+	}
+	return 0
+}
+
+func (m *ForceClosePreview) GetSweepSatPerByte() int64 {
+	if m != nil {
+		return m.SweepSatPerByte
+	}
+	return 0
+}
+
+func (m *ForceClosePreview) GetCsvDelay() uint32 {
+	if m != nil {
+		return m.CsvDelay
+	}
+	return 0
+}
+
+func (m *ForceClosePreview) GetMaxHtlcExpiry() uint32 {
+	if m != nil {
+		return m.MaxHtlcExpiry
+	}
+	return 0
+}
+
+func (m *ForceClosePreview) GetLimboBalance() int64 {
+	if m != nil {
+		return m.LimboBalance
+	}
+	return 0
+}
+
 type OpenChannelRequest struct {
 	// / The peer_id of the node to open a channel with
 	TargetPeerId int32 `protobuf:"varint,1,opt,name=target_peer_id" json:"target_peer_id,omitempty"`
@@ -1566,12 +2041,16 @@ type OpenChannelRequest struct {
 	Private bool `protobuf:"varint,8,opt,name=private" json:"private,omitempty"`
 	// / The minimum value in millisatoshi we will require for incoming HTLCs on the channel.
 	MinHtlcMsat int64 `protobuf:"varint,9,opt,name=min_htlc_msat" json:"min_htlc_msat,omitempty"`
+	// / The reserve in satoshis the remote node is required to maintain within the channel. If zero, the default of 1% of the channel capacity is used.
+	RemoteChanReserveSat int64 `protobuf:"varint,10,opt,name=remote_chan_reserve_sat" json:"remote_chan_reserve_sat,omitempty"`
+	// / The number of confirmations the funding transaction must reach before the channel is announced. Values of 6 or less use the default.
+	AnnouncementDepth uint32 `protobuf:"varint,11,opt,name=announcement_depth" json:"announcement_depth,omitempty"`
 }
 
 func (m *OpenChannelRequest) Reset()                    { *m = OpenChannelRequest{} }
 func (m *OpenChannelRequest) String() string            { return proto.CompactTextString(m) }
 func (*OpenChannelRequest) ProtoMessage()               {}
-func (*OpenChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+func (*OpenChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
 
 func (m *OpenChannelRequest) GetTargetPeerId() int32 {
 	if m != nil {
@@ -1636,6 +2115,20 @@ func (m *OpenChannelRequest) GetMinHtlcMsat() int64 {
 	return 0
 }
 
+func (m *OpenChannelRequest) GetRemoteChanReserveSat() int64 {
+	if m != nil {
+		return m.RemoteChanReserveSat
+	}
+	return 0
+}
+
+func (m *OpenChannelRequest) GetAnnouncementDepth() uint32 {
+	if m != nil {
+		return m.AnnouncementDepth
+	}
+	return 0
+}
+
 type OpenStatusUpdate struct {
 	// Types that are valid to be assigned to Update:
 	//	*OpenStatusUpdate_ChanPending
@@ -1647,7 +2140,7 @@ type OpenStatusUpdate struct {
 func (m *OpenStatusUpdate) Reset()                    { *m = OpenStatusUpdate{} }
 func (m *OpenStatusUpdate) String() string            { return proto.CompactTextString(m) }
 func (*OpenStatusUpdate) ProtoMessage()               {}
-func (*OpenStatusUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+func (*OpenStatusUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
 
 type isOpenStatusUpdate_Update interface {
 	isOpenStatusUpdate_Update()
@@ -1809,7 +2302,7 @@ type PendingHTLC struct {
 func (m *PendingHTLC) Reset()                    { *m = PendingHTLC{} }
 func (m *PendingHTLC) String() string            { return proto.CompactTextString(m) }
 func (*PendingHTLC) ProtoMessage()               {}
-func (*PendingHTLC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+func (*PendingHTLC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
 
 func (m *PendingHTLC) GetIncoming() bool {
 	if m != nil {
@@ -1859,7 +2352,7 @@ type PendingChannelsRequest struct {
 func (m *PendingChannelsRequest) Reset()                    { *m = PendingChannelsRequest{} }
 func (m *PendingChannelsRequest) String() string            { return proto.CompactTextString(m) }
 func (*PendingChannelsRequest) ProtoMessage()               {}
-func (*PendingChannelsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+func (*PendingChannelsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
 
 type PendingChannelsResponse struct {
 	// / The balance in satoshis encumbered in pending channels
@@ -1875,7 +2368,7 @@ type PendingChannelsResponse struct {
 func (m *PendingChannelsResponse) Reset()                    { *m = PendingChannelsResponse{} }
 func (m *PendingChannelsResponse) String() string            { return proto.CompactTextString(m) }
 func (*PendingChannelsResponse) ProtoMessage()               {}
-func (*PendingChannelsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+func (*PendingChannelsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
 
 func (m *PendingChannelsResponse) GetTotalLimboBalance() int64 {
 	if m != nil {
@@ -1919,7 +2412,7 @@ func (m *PendingChannelsResponse_PendingChannel) Reset() {
 func (m *PendingChannelsResponse_PendingChannel) String() string { return proto.CompactTextString(m) }
 func (*PendingChannelsResponse_PendingChannel) ProtoMessage()    {}
 func (*PendingChannelsResponse_PendingChannel) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{45, 0}
+	return fileDescriptor0, []int{46, 0}
 }
 
 func (m *PendingChannelsResponse_PendingChannel) GetRemoteNodePub() string {
@@ -1986,7 +2479,7 @@ func (m *PendingChannelsResponse_PendingOpenChannel) String() string {
 }
 func (*PendingChannelsResponse_PendingOpenChannel) ProtoMessage() {}
 func (*PendingChannelsResponse_PendingOpenChannel) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{45, 1}
+	return fileDescriptor0, []int{46, 1}
 }
 
 func (m *PendingChannelsResponse_PendingOpenChannel) GetChannel() *PendingChannelsResponse_PendingChannel {
@@ -2035,7 +2528,7 @@ func (m *PendingChannelsResponse_ClosedChannel) Reset()         { *m = PendingCh
 func (m *PendingChannelsResponse_ClosedChannel) String() string { return proto.CompactTextString(m) }
 func (*PendingChannelsResponse_ClosedChannel) ProtoMessage()    {}
 func (*PendingChannelsResponse_ClosedChannel) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{45, 2}
+	return fileDescriptor0, []int{46, 2}
 }
 
 func (m *PendingChannelsResponse_ClosedChannel) GetChannel() *PendingChannelsResponse_PendingChannel {
@@ -2079,7 +2572,7 @@ func (m *PendingChannelsResponse_ForceClosedChannel) String() string {
 }
 func (*PendingChannelsResponse_ForceClosedChannel) ProtoMessage() {}
 func (*PendingChannelsResponse_ForceClosedChannel) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{45, 3}
+	return fileDescriptor0, []int{46, 3}
 }
 
 func (m *PendingChannelsResponse_ForceClosedChannel) GetChannel() *PendingChannelsResponse_PendingChannel {
@@ -2139,7 +2632,7 @@ type WalletBalanceRequest struct {
 func (m *WalletBalanceRequest) Reset()                    { *m = WalletBalanceRequest{} }
 func (m *WalletBalanceRequest) String() string            { return proto.CompactTextString(m) }
 func (*WalletBalanceRequest) ProtoMessage()               {}
-func (*WalletBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+func (*WalletBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
 
 func (m *WalletBalanceRequest) GetWitnessOnly() bool {
 	if m != nil {
@@ -2155,12 +2648,14 @@ type WalletBalanceResponse struct {
 	ConfirmedBalance int64 `protobuf:"varint,2,opt,name=confirmed_balance" json:"confirmed_balance,omitempty"`
 	// / The unconfirmed balance of a wallet(with 0 confirmations)
 	UnconfirmedBalance int64 `protobuf:"varint,3,opt,name=unconfirmed_balance" json:"unconfirmed_balance,omitempty"`
+	// / The portion of the confirmed balance set aside for the fees of resolving our channels on-chain, which may not be spent
+	ReservedBalance int64 `protobuf:"varint,4,opt,name=reserved_balance" json:"reserved_balance,omitempty"`
 }
 
 func (m *WalletBalanceResponse) Reset()                    { *m = WalletBalanceResponse{} }
 func (m *WalletBalanceResponse) String() string            { return proto.CompactTextString(m) }
 func (*WalletBalanceResponse) ProtoMessage()               {}
-func (*WalletBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+func (*WalletBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
 
 func (m *WalletBalanceResponse) GetTotalBalance() int64 {
 	if m != nil {
@@ -2183,13 +2678,20 @@ func (m *WalletBalanceResponse) GetUnconfirmedBalance() int64 {
 	return 0
 }
 
+func (m *WalletBalanceResponse) GetReservedBalance() int64 {
+	if m != nil {
+		return m.ReservedBalance
+	}
+	return 0
+}
+
 type ChannelBalanceRequest struct {
 }
 
 func (m *ChannelBalanceRequest) Reset()                    { *m = ChannelBalanceRequest{} }
 func (m *ChannelBalanceRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChannelBalanceRequest) ProtoMessage()               {}
-func (*ChannelBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+func (*ChannelBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
 
 type ChannelBalanceResponse struct {
 	// / Sum of channels balances denominated in satoshis
@@ -2199,7 +2701,7 @@ type ChannelBalanceResponse struct {
 func (m *ChannelBalanceResponse) Reset()                    { *m = ChannelBalanceResponse{} }
 func (m *ChannelBalanceResponse) String() string            { return proto.CompactTextString(m) }
 func (*ChannelBalanceResponse) ProtoMessage()               {}
-func (*ChannelBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+func (*ChannelBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
 
 func (m *ChannelBalanceResponse) GetBalance() int64 {
 	if m != nil {
@@ -2218,7 +2720,7 @@ type QueryRoutesRequest struct {
 func (m *QueryRoutesRequest) Reset()                    { *m = QueryRoutesRequest{} }
 func (m *QueryRoutesRequest) String() string            { return proto.CompactTextString(m) }
 func (*QueryRoutesRequest) ProtoMessage()               {}
-func (*QueryRoutesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+func (*QueryRoutesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
 
 func (m *QueryRoutesRequest) GetPubKey() string {
 	if m != nil {
@@ -2241,7 +2743,7 @@ type QueryRoutesResponse struct {
 func (m *QueryRoutesResponse) Reset()                    { *m = QueryRoutesResponse{} }
 func (m *QueryRoutesResponse) String() string            { return proto.CompactTextString(m) }
 func (*QueryRoutesResponse) ProtoMessage()               {}
-func (*QueryRoutesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+func (*QueryRoutesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
 
 func (m *QueryRoutesResponse) GetRoutes() []*Route {
 	if m != nil {
@@ -2260,12 +2762,18 @@ type Hop struct {
 	AmtToForward int64  `protobuf:"varint,3,opt,name=amt_to_forward" json:"amt_to_forward,omitempty"`
 	Fee          int64  `protobuf:"varint,4,opt,name=fee" json:"fee,omitempty"`
 	Expiry       uint32 `protobuf:"varint,5,opt,name=expiry" json:"expiry,omitempty"`
+	// / The hex-encoded public key of the node this hop leads to.
+	PubKey string `protobuf:"bytes,6,opt,name=pub_key" json:"pub_key,omitempty"`
+	// / The amount to forward in milli-satoshis. If set, it takes precedence over amt_to_forward.
+	AmtToForwardMsat int64 `protobuf:"varint,7,opt,name=amt_to_forward_msat" json:"amt_to_forward_msat,omitempty"`
+	// / The fee in milli-satoshis. If set, it takes precedence over fee.
+	FeeMsat int64 `protobuf:"varint,8,opt,name=fee_msat" json:"fee_msat,omitempty"`
 }
 
 func (m *Hop) Reset()                    { *m = Hop{} }
 func (m *Hop) String() string            { return proto.CompactTextString(m) }
 func (*Hop) ProtoMessage()               {}
-func (*Hop) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+func (*Hop) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
 
 func (m *Hop) GetChanId() uint64 {
 	if m != nil {
@@ -2302,6 +2810,27 @@ func (m *Hop) GetExpiry() uint32 {
 	return 0
 }
 
+func (m *Hop) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *Hop) GetAmtToForwardMsat() int64 {
+	if m != nil {
+		return m.AmtToForwardMsat
+	}
+	return 0
+}
+
+func (m *Hop) GetFeeMsat() int64 {
+	if m != nil {
+		return m.FeeMsat
+	}
+	return 0
+}
+
 // *
 // A path through the channel graph which runs over one or more channels in
 // succession. This struct carries all the information required to craft the
@@ -2330,12 +2859,16 @@ type Route struct {
 	// *
 	// Contains details concerning the specific forwarding details at each hop.
 	Hops []*Hop `protobuf:"bytes,4,rep,name=hops" json:"hops,omitempty"`
+	// / The sum of the fees paid at each hop, in milli-satoshis.
+	TotalFeesMsat int64 `protobuf:"varint,5,opt,name=total_fees_msat" json:"total_fees_msat,omitempty"`
+	// / The total amount of funds required to complete the payment, in milli-satoshis.
+	TotalAmtMsat int64 `protobuf:"varint,6,opt,name=total_amt_msat" json:"total_amt_msat,omitempty"`
 }
 
 func (m *Route) Reset()                    { *m = Route{} }
 func (m *Route) String() string            { return proto.CompactTextString(m) }
 func (*Route) ProtoMessage()               {}
-func (*Route) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+func (*Route) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
 
 func (m *Route) GetTotalTimeLock() uint32 {
 	if m != nil {
@@ -2365,6 +2898,20 @@ func (m *Route) GetHops() []*Hop {
 	return nil
 }
 
+func (m *Route) GetTotalFeesMsat() int64 {
+	if m != nil {
+		return m.TotalFeesMsat
+	}
+	return 0
+}
+
+func (m *Route) GetTotalAmtMsat() int64 {
+	if m != nil {
+		return m.TotalAmtMsat
+	}
+	return 0
+}
+
 type NodeInfoRequest struct {
 	// / The 33-byte hex-encoded compressed public of the target node
 	PubKey string `protobuf:"bytes,1,opt,name=pub_key,json=pubKey" json:"pub_key,omitempty"`
@@ -2373,7 +2920,7 @@ type NodeInfoRequest struct {
 func (m *NodeInfoRequest) Reset()                    { *m = NodeInfoRequest{} }
 func (m *NodeInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*NodeInfoRequest) ProtoMessage()               {}
-func (*NodeInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+func (*NodeInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
 
 func (m *NodeInfoRequest) GetPubKey() string {
 	if m != nil {
@@ -2396,7 +2943,7 @@ type NodeInfo struct {
 func (m *NodeInfo) Reset()                    { *m = NodeInfo{} }
 func (m *NodeInfo) String() string            { return proto.CompactTextString(m) }
 func (*NodeInfo) ProtoMessage()               {}
-func (*NodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+func (*NodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
 
 func (m *NodeInfo) GetNode() *LightningNode {
 	if m != nil {
@@ -2435,7 +2982,7 @@ type LightningNode struct {
 func (m *LightningNode) Reset()                    { *m = LightningNode{} }
 func (m *LightningNode) String() string            { return proto.CompactTextString(m) }
 func (*LightningNode) ProtoMessage()               {}
-func (*LightningNode) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+func (*LightningNode) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
 
 func (m *LightningNode) GetLastUpdate() uint32 {
 	if m != nil {
@@ -2480,7 +3027,7 @@ type NodeAddress struct {
 func (m *NodeAddress) Reset()                    { *m = NodeAddress{} }
 func (m *NodeAddress) String() string            { return proto.CompactTextString(m) }
 func (*NodeAddress) ProtoMessage()               {}
-func (*NodeAddress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+func (*NodeAddress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
 
 func (m *NodeAddress) GetNetwork() string {
 	if m != nil {
@@ -2506,7 +3053,7 @@ type RoutingPolicy struct {
 func (m *RoutingPolicy) Reset()                    { *m = RoutingPolicy{} }
 func (m *RoutingPolicy) String() string            { return proto.CompactTextString(m) }
 func (*RoutingPolicy) ProtoMessage()               {}
-func (*RoutingPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+func (*RoutingPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
 
 func (m *RoutingPolicy) GetTimeLockDelta() uint32 {
 	if m != nil {
@@ -2560,7 +3107,7 @@ type ChannelEdge struct {
 func (m *ChannelEdge) Reset()                    { *m = ChannelEdge{} }
 func (m *ChannelEdge) String() string            { return proto.CompactTextString(m) }
 func (*ChannelEdge) ProtoMessage()               {}
-func (*ChannelEdge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+func (*ChannelEdge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
 
 func (m *ChannelEdge) GetChannelId() uint64 {
 	if m != nil {
@@ -2624,7 +3171,7 @@ type ChannelGraphRequest struct {
 func (m *ChannelGraphRequest) Reset()                    { *m = ChannelGraphRequest{} }
 func (m *ChannelGraphRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChannelGraphRequest) ProtoMessage()               {}
-func (*ChannelGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+func (*ChannelGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
 
 // / Returns a new instance of the directed channel graph.
 type ChannelGraph struct {
@@ -2637,7 +3184,7 @@ type ChannelGraph struct {
 func (m *ChannelGraph) Reset()                    { *m = ChannelGraph{} }
 func (m *ChannelGraph) String() string            { return proto.CompactTextString(m) }
 func (*ChannelGraph) ProtoMessage()               {}
-func (*ChannelGraph) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+func (*ChannelGraph) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
 
 func (m *ChannelGraph) GetNodes() []*LightningNode {
 	if m != nil {
@@ -2664,7 +3211,7 @@ type ChanInfoRequest struct {
 func (m *ChanInfoRequest) Reset()                    { *m = ChanInfoRequest{} }
 func (m *ChanInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChanInfoRequest) ProtoMessage()               {}
-func (*ChanInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+func (*ChanInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
 
 func (m *ChanInfoRequest) GetChanId() uint64 {
 	if m != nil {
@@ -2679,7 +3226,7 @@ type NetworkInfoRequest struct {
 func (m *NetworkInfoRequest) Reset()                    { *m = NetworkInfoRequest{} }
 func (m *NetworkInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*NetworkInfoRequest) ProtoMessage()               {}
-func (*NetworkInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+func (*NetworkInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
 
 type NetworkInfo struct {
 	GraphDiameter        uint32  `protobuf:"varint,1,opt,name=graph_diameter" json:"graph_diameter,omitempty"`
@@ -2696,7 +3243,7 @@ type NetworkInfo struct {
 func (m *NetworkInfo) Reset()                    { *m = NetworkInfo{} }
 func (m *NetworkInfo) String() string            { return proto.CompactTextString(m) }
 func (*NetworkInfo) ProtoMessage()               {}
-func (*NetworkInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+func (*NetworkInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
 
 func (m *NetworkInfo) GetGraphDiameter() uint32 {
 	if m != nil {
@@ -2767,7 +3314,7 @@ type StopRequest struct {
 func (m *StopRequest) Reset()                    { *m = StopRequest{} }
 func (m *StopRequest) String() string            { return proto.CompactTextString(m) }
 func (*StopRequest) ProtoMessage()               {}
-func (*StopRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+func (*StopRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
 
 type StopResponse struct {
 }
@@ -2775,7 +3322,7 @@ type StopResponse struct {
 func (m *StopResponse) Reset()                    { *m = StopResponse{} }
 func (m *StopResponse) String() string            { return proto.CompactTextString(m) }
 func (*StopResponse) ProtoMessage()               {}
-func (*StopResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+func (*StopResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
 
 type GraphTopologySubscription struct {
 }
@@ -2783,7 +3330,7 @@ type GraphTopologySubscription struct {
 func (m *GraphTopologySubscription) Reset()                    { *m = GraphTopologySubscription{} }
 func (m *GraphTopologySubscription) String() string            { return proto.CompactTextString(m) }
 func (*GraphTopologySubscription) ProtoMessage()               {}
-func (*GraphTopologySubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+func (*GraphTopologySubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
 
 type GraphTopologyUpdate struct {
 	NodeUpdates    []*NodeUpdate          `protobuf:"bytes,1,rep,name=node_updates,json=nodeUpdates" json:"node_updates,omitempty"`
@@ -2794,7 +3341,7 @@ type GraphTopologyUpdate struct {
 func (m *GraphTopologyUpdate) Reset()                    { *m = GraphTopologyUpdate{} }
 func (m *GraphTopologyUpdate) String() string            { return proto.CompactTextString(m) }
 func (*GraphTopologyUpdate) ProtoMessage()               {}
-func (*GraphTopologyUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+func (*GraphTopologyUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
 
 func (m *GraphTopologyUpdate) GetNodeUpdates() []*NodeUpdate {
 	if m != nil {
@@ -2827,7 +3374,7 @@ type NodeUpdate struct {
 func (m *NodeUpdate) Reset()                    { *m = NodeUpdate{} }
 func (m *NodeUpdate) String() string            { return proto.CompactTextString(m) }
 func (*NodeUpdate) ProtoMessage()               {}
-func (*NodeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+func (*NodeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
 
 func (m *NodeUpdate) GetAddresses() []string {
 	if m != nil {
@@ -2873,7 +3420,7 @@ type ChannelEdgeUpdate struct {
 func (m *ChannelEdgeUpdate) Reset()                    { *m = ChannelEdgeUpdate{} }
 func (m *ChannelEdgeUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ChannelEdgeUpdate) ProtoMessage()               {}
-func (*ChannelEdgeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
+func (*ChannelEdgeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
 
 func (m *ChannelEdgeUpdate) GetChanId() uint64 {
 	if m != nil {
@@ -2931,7 +3478,7 @@ type ClosedChannelUpdate struct {
 func (m *ClosedChannelUpdate) Reset()                    { *m = ClosedChannelUpdate{} }
 func (m *ClosedChannelUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ClosedChannelUpdate) ProtoMessage()               {}
-func (*ClosedChannelUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
+func (*ClosedChannelUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
 
 func (m *ClosedChannelUpdate) GetChanId() uint64 {
 	if m != nil {
@@ -3000,12 +3547,28 @@ type Invoice struct {
 	FallbackAddr string `protobuf:"bytes,12,opt,name=fallback_addr" json:"fallback_addr,omitempty"`
 	// / Delta to use for the time-lock of the CLTV extended to the final hop.
 	CltvExpiry uint64 `protobuf:"varint,13,opt,name=cltv_expiry" json:"cltv_expiry,omitempty"`
+	// / Whether this invoice should include a routing hint for one of our private channels, so that it can be paid through it.
+	Private bool `protobuf:"varint,14,opt,name=private" json:"private,omitempty"`
+	// / Whether this is a hold invoice. HTLCs paying to a hold invoice are accepted, but held until the invoice is either settled via SettleHoldInvoice, or canceled via CancelHoldInvoice. When adding a hold invoice, r_hash may be set in place of r_preimage, in which case the preimage is only revealed once the invoice is settled.
+	Hold bool `protobuf:"varint,15,opt,name=hold" json:"hold,omitempty"`
+	// / The state of a hold invoice, either "accepting", "settling" or "canceled".
+	HoldState string `protobuf:"bytes,16,opt,name=hold_state" json:"hold_state,omitempty"`
+	// / The amount in milli-satoshis that was actually paid to the invoice once settled, which may exceed its value.
+	AmtPaidMsat int64 `protobuf:"varint,17,opt,name=amt_paid_msat" json:"amt_paid_msat,omitempty"`
+	// / The state of the invoice. An invoice is accepted once HTLCs paying to it are held, but not yet settled.
+	State Invoice_InvoiceState `protobuf:"varint,18,opt,name=state,enum=lnrpc.Invoice.InvoiceState" json:"state,omitempty"`
+	// / The HTLCs paying to the invoice, in the order they were accepted.
+	Htlcs []*InvoiceHTLC `protobuf:"bytes,19,rep,name=htlcs" json:"htlcs,omitempty"`
+	// / Whether this is a stateless invoice, which isn't stored. Its preimage is derived from the terms of the invoice, which the payer hands back within the payment, so it may be paid without any invoice on disk. Such invoices can't be looked up, nor be hold invoices. Requires statelessinvoices to be enabled.
+	Stateless bool `protobuf:"varint,20,opt,name=stateless" json:"stateless,omitempty"`
+	// / The payment secret of the invoice, which its payment request hands to the payer, and which the payer includes within the payment, proving that it knows the invoice. Stateless invoices don't have one.
+	PaymentAddr []byte `protobuf:"bytes,21,opt,name=payment_addr,proto3" json:"payment_addr,omitempty"`
 }
 
 func (m *Invoice) Reset()                    { *m = Invoice{} }
 func (m *Invoice) String() string            { return proto.CompactTextString(m) }
 func (*Invoice) ProtoMessage()               {}
-func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
+func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
 
 func (m *Invoice) GetMemo() string {
 	if m != nil {
@@ -3098,19 +3661,137 @@ func (m *Invoice) GetCltvExpiry() uint64 {
 	return 0
 }
 
-type AddInvoiceResponse struct {
-	RHash []byte `protobuf:"bytes,1,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
-	// *
-	// A bare-bones invoice for a payment within the Lightning Network.  With the
-	// details of the invoice, the sender has all the data necessary to send a
-	// payment to the recipient.
-	PaymentRequest string `protobuf:"bytes,2,opt,name=payment_request" json:"payment_request,omitempty"`
-}
+func (m *Invoice) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
+func (m *Invoice) GetHold() bool {
+	if m != nil {
+		return m.Hold
+	}
+	return false
+}
+
+func (m *Invoice) GetHoldState() string {
+	if m != nil {
+		return m.HoldState
+	}
+	return ""
+}
+
+func (m *Invoice) GetAmtPaidMsat() int64 {
+	if m != nil {
+		return m.AmtPaidMsat
+	}
+	return 0
+}
+
+func (m *Invoice) GetState() Invoice_InvoiceState {
+	if m != nil {
+		return m.State
+	}
+	return Invoice_OPEN
+}
+
+func (m *Invoice) GetHtlcs() []*InvoiceHTLC {
+	if m != nil {
+		return m.Htlcs
+	}
+	return nil
+}
+
+func (m *Invoice) GetStateless() bool {
+	if m != nil {
+		return m.Stateless
+	}
+	return false
+}
+
+func (m *Invoice) GetPaymentAddr() []byte {
+	if m != nil {
+		return m.PaymentAddr
+	}
+	return nil
+}
+
+type InvoiceHTLC struct {
+	// / The short channel ID of the channel the HTLC arrived on.
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id" json:"chan_id,omitempty"`
+	// / The index of the HTLC within the channel.
+	HtlcIndex uint64 `protobuf:"varint,2,opt,name=htlc_index" json:"htlc_index,omitempty"`
+	// / The value of the HTLC in milli-satoshis.
+	AmtMsat uint64 `protobuf:"varint,3,opt,name=amt_msat" json:"amt_msat,omitempty"`
+	// / The absolute height at which the HTLC expires.
+	ExpiryHeight uint32 `protobuf:"varint,4,opt,name=expiry_height" json:"expiry_height,omitempty"`
+	// / The time the HTLC was accepted, in seconds since the unix epoch.
+	AcceptTime int64 `protobuf:"varint,5,opt,name=accept_time" json:"accept_time,omitempty"`
+	// / The state of the HTLC.
+	State InvoiceHTLC_HTLCState `protobuf:"varint,6,opt,name=state,enum=lnrpc.InvoiceHTLC.HTLCState" json:"state,omitempty"`
+}
+
+func (m *InvoiceHTLC) Reset()                    { *m = InvoiceHTLC{} }
+func (m *InvoiceHTLC) String() string            { return proto.CompactTextString(m) }
+func (*InvoiceHTLC) ProtoMessage()               {}
+func (*InvoiceHTLC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
+
+func (m *InvoiceHTLC) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *InvoiceHTLC) GetHtlcIndex() uint64 {
+	if m != nil {
+		return m.HtlcIndex
+	}
+	return 0
+}
+
+func (m *InvoiceHTLC) GetAmtMsat() uint64 {
+	if m != nil {
+		return m.AmtMsat
+	}
+	return 0
+}
+
+func (m *InvoiceHTLC) GetExpiryHeight() uint32 {
+	if m != nil {
+		return m.ExpiryHeight
+	}
+	return 0
+}
+
+func (m *InvoiceHTLC) GetAcceptTime() int64 {
+	if m != nil {
+		return m.AcceptTime
+	}
+	return 0
+}
+
+func (m *InvoiceHTLC) GetState() InvoiceHTLC_HTLCState {
+	if m != nil {
+		return m.State
+	}
+	return InvoiceHTLC_ACCEPTED
+}
+
+type AddInvoiceResponse struct {
+	RHash []byte `protobuf:"bytes,1,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
+	// *
+	// A bare-bones invoice for a payment within the Lightning Network.  With the
+	// details of the invoice, the sender has all the data necessary to send a
+	// payment to the recipient.
+	PaymentRequest string `protobuf:"bytes,2,opt,name=payment_request" json:"payment_request,omitempty"`
+}
 
 func (m *AddInvoiceResponse) Reset()                    { *m = AddInvoiceResponse{} }
 func (m *AddInvoiceResponse) String() string            { return proto.CompactTextString(m) }
 func (*AddInvoiceResponse) ProtoMessage()               {}
-func (*AddInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
+func (*AddInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
 
 func (m *AddInvoiceResponse) GetRHash() []byte {
 	if m != nil {
@@ -3138,7 +3819,7 @@ type PaymentHash struct {
 func (m *PaymentHash) Reset()                    { *m = PaymentHash{} }
 func (m *PaymentHash) String() string            { return proto.CompactTextString(m) }
 func (*PaymentHash) ProtoMessage()               {}
-func (*PaymentHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
+func (*PaymentHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
 
 func (m *PaymentHash) GetRHashStr() string {
 	if m != nil {
@@ -3162,7 +3843,7 @@ type ListInvoiceRequest struct {
 func (m *ListInvoiceRequest) Reset()                    { *m = ListInvoiceRequest{} }
 func (m *ListInvoiceRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListInvoiceRequest) ProtoMessage()               {}
-func (*ListInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
+func (*ListInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
 
 func (m *ListInvoiceRequest) GetPendingOnly() bool {
 	if m != nil {
@@ -3178,7 +3859,7 @@ type ListInvoiceResponse struct {
 func (m *ListInvoiceResponse) Reset()                    { *m = ListInvoiceResponse{} }
 func (m *ListInvoiceResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListInvoiceResponse) ProtoMessage()               {}
-func (*ListInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
+func (*ListInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
 
 func (m *ListInvoiceResponse) GetInvoices() []*Invoice {
 	if m != nil {
@@ -3193,7 +3874,7 @@ type InvoiceSubscription struct {
 func (m *InvoiceSubscription) Reset()                    { *m = InvoiceSubscription{} }
 func (m *InvoiceSubscription) String() string            { return proto.CompactTextString(m) }
 func (*InvoiceSubscription) ProtoMessage()               {}
-func (*InvoiceSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
+func (*InvoiceSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
 
 type Payment struct {
 	// / The payment hash
@@ -3213,7 +3894,7 @@ type Payment struct {
 func (m *Payment) Reset()                    { *m = Payment{} }
 func (m *Payment) String() string            { return proto.CompactTextString(m) }
 func (*Payment) ProtoMessage()               {}
-func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
+func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{80} }
 
 func (m *Payment) GetPaymentHash() string {
 	if m != nil {
@@ -3263,7 +3944,7 @@ type ListPaymentsRequest struct {
 func (m *ListPaymentsRequest) Reset()                    { *m = ListPaymentsRequest{} }
 func (m *ListPaymentsRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListPaymentsRequest) ProtoMessage()               {}
-func (*ListPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
+func (*ListPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{81} }
 
 type ListPaymentsResponse struct {
 	// / The list of payments
@@ -3273,7 +3954,7 @@ type ListPaymentsResponse struct {
 func (m *ListPaymentsResponse) Reset()                    { *m = ListPaymentsResponse{} }
 func (m *ListPaymentsResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListPaymentsResponse) ProtoMessage()               {}
-func (*ListPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{80} }
+func (*ListPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
 
 func (m *ListPaymentsResponse) GetPayments() []*Payment {
 	if m != nil {
@@ -3288,7 +3969,7 @@ type DeleteAllPaymentsRequest struct {
 func (m *DeleteAllPaymentsRequest) Reset()                    { *m = DeleteAllPaymentsRequest{} }
 func (m *DeleteAllPaymentsRequest) String() string            { return proto.CompactTextString(m) }
 func (*DeleteAllPaymentsRequest) ProtoMessage()               {}
-func (*DeleteAllPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{81} }
+func (*DeleteAllPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{83} }
 
 type DeleteAllPaymentsResponse struct {
 }
@@ -3296,7 +3977,7 @@ type DeleteAllPaymentsResponse struct {
 func (m *DeleteAllPaymentsResponse) Reset()                    { *m = DeleteAllPaymentsResponse{} }
 func (m *DeleteAllPaymentsResponse) String() string            { return proto.CompactTextString(m) }
 func (*DeleteAllPaymentsResponse) ProtoMessage()               {}
-func (*DeleteAllPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
+func (*DeleteAllPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
 
 type DebugLevelRequest struct {
 	Show      bool   `protobuf:"varint,1,opt,name=show" json:"show,omitempty"`
@@ -3306,7 +3987,7 @@ type DebugLevelRequest struct {
 func (m *DebugLevelRequest) Reset()                    { *m = DebugLevelRequest{} }
 func (m *DebugLevelRequest) String() string            { return proto.CompactTextString(m) }
 func (*DebugLevelRequest) ProtoMessage()               {}
-func (*DebugLevelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{83} }
+func (*DebugLevelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
 
 func (m *DebugLevelRequest) GetShow() bool {
 	if m != nil {
@@ -3329,7 +4010,7 @@ type DebugLevelResponse struct {
 func (m *DebugLevelResponse) Reset()                    { *m = DebugLevelResponse{} }
 func (m *DebugLevelResponse) String() string            { return proto.CompactTextString(m) }
 func (*DebugLevelResponse) ProtoMessage()               {}
-func (*DebugLevelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
+func (*DebugLevelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
 
 func (m *DebugLevelResponse) GetSubSystems() string {
 	if m != nil {
@@ -3346,7 +4027,7 @@ type PayReqString struct {
 func (m *PayReqString) Reset()                    { *m = PayReqString{} }
 func (m *PayReqString) String() string            { return proto.CompactTextString(m) }
 func (*PayReqString) ProtoMessage()               {}
-func (*PayReqString) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
+func (*PayReqString) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{87} }
 
 func (m *PayReqString) GetPayReq() string {
 	if m != nil {
@@ -3365,12 +4046,13 @@ type PayReq struct {
 	DescriptionHash string `protobuf:"bytes,7,opt,name=description_hash" json:"description_hash,omitempty"`
 	FallbackAddr    string `protobuf:"bytes,8,opt,name=fallback_addr" json:"fallback_addr,omitempty"`
 	CltvExpiry      int64  `protobuf:"varint,9,opt,name=cltv_expiry" json:"cltv_expiry,omitempty"`
+	PaymentAddr     string `protobuf:"bytes,10,opt,name=payment_addr" json:"payment_addr,omitempty"`
 }
 
 func (m *PayReq) Reset()                    { *m = PayReq{} }
 func (m *PayReq) String() string            { return proto.CompactTextString(m) }
 func (*PayReq) ProtoMessage()               {}
-func (*PayReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
+func (*PayReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{88} }
 
 func (m *PayReq) GetDestination() string {
 	if m != nil {
@@ -3435,13 +4117,20 @@ func (m *PayReq) GetCltvExpiry() int64 {
 	return 0
 }
 
+func (m *PayReq) GetPaymentAddr() string {
+	if m != nil {
+		return m.PaymentAddr
+	}
+	return ""
+}
+
 type FeeReportRequest struct {
 }
 
 func (m *FeeReportRequest) Reset()                    { *m = FeeReportRequest{} }
 func (m *FeeReportRequest) String() string            { return proto.CompactTextString(m) }
 func (*FeeReportRequest) ProtoMessage()               {}
-func (*FeeReportRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{87} }
+func (*FeeReportRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{89} }
 
 type ChannelFeeReport struct {
 	// / The channel that this fee report belongs to.
@@ -3457,7 +4146,7 @@ type ChannelFeeReport struct {
 func (m *ChannelFeeReport) Reset()                    { *m = ChannelFeeReport{} }
 func (m *ChannelFeeReport) String() string            { return proto.CompactTextString(m) }
 func (*ChannelFeeReport) ProtoMessage()               {}
-func (*ChannelFeeReport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{88} }
+func (*ChannelFeeReport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{90} }
 
 func (m *ChannelFeeReport) GetChanPoint() string {
 	if m != nil {
@@ -3495,7 +4184,7 @@ type FeeReportResponse struct {
 func (m *FeeReportResponse) Reset()                    { *m = FeeReportResponse{} }
 func (m *FeeReportResponse) String() string            { return proto.CompactTextString(m) }
 func (*FeeReportResponse) ProtoMessage()               {}
-func (*FeeReportResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{89} }
+func (*FeeReportResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{91} }
 
 func (m *FeeReportResponse) GetChannelFees() []*ChannelFeeReport {
 	if m != nil {
@@ -3508,6 +4197,8 @@ type PolicyUpdateRequest struct {
 	// Types that are valid to be assigned to Scope:
 	//	*PolicyUpdateRequest_Global
 	//	*PolicyUpdateRequest_ChanPoint
+	//	*PolicyUpdateRequest_ChanPoints
+	//	*PolicyUpdateRequest_PeerPubKey
 	Scope isPolicyUpdateRequest_Scope `protobuf_oneof:"scope"`
 	// / The base fee charged regardless of the number of milli-satoshis sent.
 	BaseFeeMsat int64 `protobuf:"varint,3,opt,name=base_fee_msat" json:"base_fee_msat,omitempty"`
@@ -3515,12 +4206,14 @@ type PolicyUpdateRequest struct {
 	FeeRate float64 `protobuf:"fixed64,4,opt,name=fee_rate" json:"fee_rate,omitempty"`
 	// / The required timelock delta for HTLCs forwarded over the channel.
 	TimeLockDelta uint32 `protobuf:"varint,5,opt,name=time_lock_delta" json:"time_lock_delta,omitempty"`
+	// / The largest HTLC in milli-satoshis that will be forwarded over, or accepted from, the channel. If 0, then the current limit is kept.
+	MaxHtlcMsat uint64 `protobuf:"varint,6,opt,name=max_htlc_msat" json:"max_htlc_msat,omitempty"`
 }
 
 func (m *PolicyUpdateRequest) Reset()                    { *m = PolicyUpdateRequest{} }
 func (m *PolicyUpdateRequest) String() string            { return proto.CompactTextString(m) }
 func (*PolicyUpdateRequest) ProtoMessage()               {}
-func (*PolicyUpdateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{90} }
+func (*PolicyUpdateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{92} }
 
 type isPolicyUpdateRequest_Scope interface {
 	isPolicyUpdateRequest_Scope()
@@ -3532,9 +4225,17 @@ type PolicyUpdateRequest_Global struct {
 type PolicyUpdateRequest_ChanPoint struct {
 	ChanPoint *ChannelPoint `protobuf:"bytes,2,opt,name=chan_point,oneof"`
 }
+type PolicyUpdateRequest_ChanPoints struct {
+	ChanPoints *ChannelPointList `protobuf:"bytes,7,opt,name=chan_points,oneof"`
+}
+type PolicyUpdateRequest_PeerPubKey struct {
+	PeerPubKey string `protobuf:"bytes,8,opt,name=peer_pub_key,oneof"`
+}
 
-func (*PolicyUpdateRequest_Global) isPolicyUpdateRequest_Scope()    {}
-func (*PolicyUpdateRequest_ChanPoint) isPolicyUpdateRequest_Scope() {}
+func (*PolicyUpdateRequest_Global) isPolicyUpdateRequest_Scope()     {}
+func (*PolicyUpdateRequest_ChanPoint) isPolicyUpdateRequest_Scope()  {}
+func (*PolicyUpdateRequest_ChanPoints) isPolicyUpdateRequest_Scope() {}
+func (*PolicyUpdateRequest_PeerPubKey) isPolicyUpdateRequest_Scope() {}
 
 func (m *PolicyUpdateRequest) GetScope() isPolicyUpdateRequest_Scope {
 	if m != nil {
@@ -3557,6 +4258,20 @@ func (m *PolicyUpdateRequest) GetChanPoint() *ChannelPoint {
 	return nil
 }
 
+func (m *PolicyUpdateRequest) GetChanPoints() *ChannelPointList {
+	if x, ok := m.GetScope().(*PolicyUpdateRequest_ChanPoints); ok {
+		return x.ChanPoints
+	}
+	return nil
+}
+
+func (m *PolicyUpdateRequest) GetPeerPubKey() string {
+	if x, ok := m.GetScope().(*PolicyUpdateRequest_PeerPubKey); ok {
+		return x.PeerPubKey
+	}
+	return ""
+}
+
 func (m *PolicyUpdateRequest) GetBaseFeeMsat() int64 {
 	if m != nil {
 		return m.BaseFeeMsat
@@ -3578,11 +4293,20 @@ func (m *PolicyUpdateRequest) GetTimeLockDelta() uint32 {
 	return 0
 }
 
+func (m *PolicyUpdateRequest) GetMaxHtlcMsat() uint64 {
+	if m != nil {
+		return m.MaxHtlcMsat
+	}
+	return 0
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*PolicyUpdateRequest) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _PolicyUpdateRequest_OneofMarshaler, _PolicyUpdateRequest_OneofUnmarshaler, _PolicyUpdateRequest_OneofSizer, []interface{}{
 		(*PolicyUpdateRequest_Global)(nil),
 		(*PolicyUpdateRequest_ChanPoint)(nil),
+		(*PolicyUpdateRequest_ChanPoints)(nil),
+		(*PolicyUpdateRequest_PeerPubKey)(nil),
 	}
 }
 
@@ -3602,6 +4326,14 @@ func _PolicyUpdateRequest_OneofMarshaler(msg proto.Message, b *proto.Buffer) err
 		if err := b.EncodeMessage(x.ChanPoint); err != nil {
 			return err
 		}
+	case *PolicyUpdateRequest_ChanPoints:
+		b.EncodeVarint(7<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ChanPoints); err != nil {
+			return err
+		}
+	case *PolicyUpdateRequest_PeerPubKey:
+		b.EncodeVarint(8<<3 | proto.WireBytes)
+		b.EncodeStringBytes(x.PeerPubKey)
 	case nil:
 	default:
 		return fmt.Errorf("PolicyUpdateRequest.Scope has unexpected type %T", x)
@@ -3627,6 +4359,21 @@ func _PolicyUpdateRequest_OneofUnmarshaler(msg proto.Message, tag, wire int, b *
 		err := b.DecodeMessage(msg)
 		m.Scope = &PolicyUpdateRequest_ChanPoint{msg}
 		return true, err
+	case 7: // scope.chan_points
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ChannelPointList)
+		err := b.DecodeMessage(msg)
+		m.Scope = &PolicyUpdateRequest_ChanPoints{msg}
+		return true, err
+	case 8: // scope.peer_pub_key
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeStringBytes()
+		m.Scope = &PolicyUpdateRequest_PeerPubKey{x}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -3644,6 +4391,15 @@ func _PolicyUpdateRequest_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(2<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *PolicyUpdateRequest_ChanPoints:
+		s := proto.Size(x.ChanPoints)
+		n += proto.SizeVarint(7<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *PolicyUpdateRequest_PeerPubKey:
+		n += proto.SizeVarint(8<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(len(x.PeerPubKey)))
+		n += len(x.PeerPubKey)
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -3652,919 +4408,3201 @@ func _PolicyUpdateRequest_OneofSizer(msg proto.Message) (n int) {
 }
 
 type PolicyUpdateResponse struct {
+	// / The targeted channels that the update couldn't be applied to, along with the reason why. The update is applied to all other targeted channels.
+	FailedUpdates []*FailedUpdate `protobuf:"bytes,1,rep,name=failed_updates" json:"failed_updates,omitempty"`
 }
 
 func (m *PolicyUpdateResponse) Reset()                    { *m = PolicyUpdateResponse{} }
 func (m *PolicyUpdateResponse) String() string            { return proto.CompactTextString(m) }
 func (*PolicyUpdateResponse) ProtoMessage()               {}
-func (*PolicyUpdateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{91} }
+func (*PolicyUpdateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{93} }
 
-func init() {
-	proto.RegisterType((*CreateWalletRequest)(nil), "lnrpc.CreateWalletRequest")
-	proto.RegisterType((*CreateWalletResponse)(nil), "lnrpc.CreateWalletResponse")
-	proto.RegisterType((*UnlockWalletRequest)(nil), "lnrpc.UnlockWalletRequest")
-	proto.RegisterType((*UnlockWalletResponse)(nil), "lnrpc.UnlockWalletResponse")
-	proto.RegisterType((*Transaction)(nil), "lnrpc.Transaction")
-	proto.RegisterType((*GetTransactionsRequest)(nil), "lnrpc.GetTransactionsRequest")
-	proto.RegisterType((*TransactionDetails)(nil), "lnrpc.TransactionDetails")
-	proto.RegisterType((*SendRequest)(nil), "lnrpc.SendRequest")
-	proto.RegisterType((*SendResponse)(nil), "lnrpc.SendResponse")
-	proto.RegisterType((*ChannelPoint)(nil), "lnrpc.ChannelPoint")
-	proto.RegisterType((*LightningAddress)(nil), "lnrpc.LightningAddress")
-	proto.RegisterType((*SendManyRequest)(nil), "lnrpc.SendManyRequest")
-	proto.RegisterType((*SendManyResponse)(nil), "lnrpc.SendManyResponse")
-	proto.RegisterType((*SendCoinsRequest)(nil), "lnrpc.SendCoinsRequest")
-	proto.RegisterType((*SendCoinsResponse)(nil), "lnrpc.SendCoinsResponse")
-	proto.RegisterType((*NewAddressRequest)(nil), "lnrpc.NewAddressRequest")
-	proto.RegisterType((*NewWitnessAddressRequest)(nil), "lnrpc.NewWitnessAddressRequest")
-	proto.RegisterType((*NewAddressResponse)(nil), "lnrpc.NewAddressResponse")
-	proto.RegisterType((*SignMessageRequest)(nil), "lnrpc.SignMessageRequest")
-	proto.RegisterType((*SignMessageResponse)(nil), "lnrpc.SignMessageResponse")
-	proto.RegisterType((*VerifyMessageRequest)(nil), "lnrpc.VerifyMessageRequest")
-	proto.RegisterType((*VerifyMessageResponse)(nil), "lnrpc.VerifyMessageResponse")
-	proto.RegisterType((*ConnectPeerRequest)(nil), "lnrpc.ConnectPeerRequest")
-	proto.RegisterType((*ConnectPeerResponse)(nil), "lnrpc.ConnectPeerResponse")
-	proto.RegisterType((*DisconnectPeerRequest)(nil), "lnrpc.DisconnectPeerRequest")
-	proto.RegisterType((*DisconnectPeerResponse)(nil), "lnrpc.DisconnectPeerResponse")
-	proto.RegisterType((*HTLC)(nil), "lnrpc.HTLC")
-	proto.RegisterType((*ActiveChannel)(nil), "lnrpc.ActiveChannel")
-	proto.RegisterType((*ListChannelsRequest)(nil), "lnrpc.ListChannelsRequest")
-	proto.RegisterType((*ListChannelsResponse)(nil), "lnrpc.ListChannelsResponse")
-	proto.RegisterType((*Peer)(nil), "lnrpc.Peer")
-	proto.RegisterType((*ListPeersRequest)(nil), "lnrpc.ListPeersRequest")
-	proto.RegisterType((*ListPeersResponse)(nil), "lnrpc.ListPeersResponse")
-	proto.RegisterType((*GetInfoRequest)(nil), "lnrpc.GetInfoRequest")
-	proto.RegisterType((*GetInfoResponse)(nil), "lnrpc.GetInfoResponse")
-	proto.RegisterType((*ConfirmationUpdate)(nil), "lnrpc.ConfirmationUpdate")
-	proto.RegisterType((*ChannelOpenUpdate)(nil), "lnrpc.ChannelOpenUpdate")
-	proto.RegisterType((*ChannelCloseUpdate)(nil), "lnrpc.ChannelCloseUpdate")
-	proto.RegisterType((*CloseChannelRequest)(nil), "lnrpc.CloseChannelRequest")
-	proto.RegisterType((*CloseStatusUpdate)(nil), "lnrpc.CloseStatusUpdate")
-	proto.RegisterType((*PendingUpdate)(nil), "lnrpc.PendingUpdate")
-	proto.RegisterType((*OpenChannelRequest)(nil), "lnrpc.OpenChannelRequest")
-	proto.RegisterType((*OpenStatusUpdate)(nil), "lnrpc.OpenStatusUpdate")
-	proto.RegisterType((*PendingHTLC)(nil), "lnrpc.PendingHTLC")
-	proto.RegisterType((*PendingChannelsRequest)(nil), "lnrpc.PendingChannelsRequest")
-	proto.RegisterType((*PendingChannelsResponse)(nil), "lnrpc.PendingChannelsResponse")
-	proto.RegisterType((*PendingChannelsResponse_PendingChannel)(nil), "lnrpc.PendingChannelsResponse.PendingChannel")
-	proto.RegisterType((*PendingChannelsResponse_PendingOpenChannel)(nil), "lnrpc.PendingChannelsResponse.PendingOpenChannel")
-	proto.RegisterType((*PendingChannelsResponse_ClosedChannel)(nil), "lnrpc.PendingChannelsResponse.ClosedChannel")
-	proto.RegisterType((*PendingChannelsResponse_ForceClosedChannel)(nil), "lnrpc.PendingChannelsResponse.ForceClosedChannel")
-	proto.RegisterType((*WalletBalanceRequest)(nil), "lnrpc.WalletBalanceRequest")
-	proto.RegisterType((*WalletBalanceResponse)(nil), "lnrpc.WalletBalanceResponse")
-	proto.RegisterType((*ChannelBalanceRequest)(nil), "lnrpc.ChannelBalanceRequest")
-	proto.RegisterType((*ChannelBalanceResponse)(nil), "lnrpc.ChannelBalanceResponse")
-	proto.RegisterType((*QueryRoutesRequest)(nil), "lnrpc.QueryRoutesRequest")
-	proto.RegisterType((*QueryRoutesResponse)(nil), "lnrpc.QueryRoutesResponse")
-	proto.RegisterType((*Hop)(nil), "lnrpc.Hop")
-	proto.RegisterType((*Route)(nil), "lnrpc.Route")
-	proto.RegisterType((*NodeInfoRequest)(nil), "lnrpc.NodeInfoRequest")
-	proto.RegisterType((*NodeInfo)(nil), "lnrpc.NodeInfo")
-	proto.RegisterType((*LightningNode)(nil), "lnrpc.LightningNode")
-	proto.RegisterType((*NodeAddress)(nil), "lnrpc.NodeAddress")
-	proto.RegisterType((*RoutingPolicy)(nil), "lnrpc.RoutingPolicy")
-	proto.RegisterType((*ChannelEdge)(nil), "lnrpc.ChannelEdge")
-	proto.RegisterType((*ChannelGraphRequest)(nil), "lnrpc.ChannelGraphRequest")
-	proto.RegisterType((*ChannelGraph)(nil), "lnrpc.ChannelGraph")
-	proto.RegisterType((*ChanInfoRequest)(nil), "lnrpc.ChanInfoRequest")
-	proto.RegisterType((*NetworkInfoRequest)(nil), "lnrpc.NetworkInfoRequest")
-	proto.RegisterType((*NetworkInfo)(nil), "lnrpc.NetworkInfo")
-	proto.RegisterType((*StopRequest)(nil), "lnrpc.StopRequest")
-	proto.RegisterType((*StopResponse)(nil), "lnrpc.StopResponse")
-	proto.RegisterType((*GraphTopologySubscription)(nil), "lnrpc.GraphTopologySubscription")
-	proto.RegisterType((*GraphTopologyUpdate)(nil), "lnrpc.GraphTopologyUpdate")
-	proto.RegisterType((*NodeUpdate)(nil), "lnrpc.NodeUpdate")
-	proto.RegisterType((*ChannelEdgeUpdate)(nil), "lnrpc.ChannelEdgeUpdate")
-	proto.RegisterType((*ClosedChannelUpdate)(nil), "lnrpc.ClosedChannelUpdate")
-	proto.RegisterType((*Invoice)(nil), "lnrpc.Invoice")
-	proto.RegisterType((*AddInvoiceResponse)(nil), "lnrpc.AddInvoiceResponse")
-	proto.RegisterType((*PaymentHash)(nil), "lnrpc.PaymentHash")
-	proto.RegisterType((*ListInvoiceRequest)(nil), "lnrpc.ListInvoiceRequest")
-	proto.RegisterType((*ListInvoiceResponse)(nil), "lnrpc.ListInvoiceResponse")
-	proto.RegisterType((*InvoiceSubscription)(nil), "lnrpc.InvoiceSubscription")
-	proto.RegisterType((*Payment)(nil), "lnrpc.Payment")
-	proto.RegisterType((*ListPaymentsRequest)(nil), "lnrpc.ListPaymentsRequest")
-	proto.RegisterType((*ListPaymentsResponse)(nil), "lnrpc.ListPaymentsResponse")
-	proto.RegisterType((*DeleteAllPaymentsRequest)(nil), "lnrpc.DeleteAllPaymentsRequest")
-	proto.RegisterType((*DeleteAllPaymentsResponse)(nil), "lnrpc.DeleteAllPaymentsResponse")
-	proto.RegisterType((*DebugLevelRequest)(nil), "lnrpc.DebugLevelRequest")
-	proto.RegisterType((*DebugLevelResponse)(nil), "lnrpc.DebugLevelResponse")
-	proto.RegisterType((*PayReqString)(nil), "lnrpc.PayReqString")
-	proto.RegisterType((*PayReq)(nil), "lnrpc.PayReq")
-	proto.RegisterType((*FeeReportRequest)(nil), "lnrpc.FeeReportRequest")
-	proto.RegisterType((*ChannelFeeReport)(nil), "lnrpc.ChannelFeeReport")
-	proto.RegisterType((*FeeReportResponse)(nil), "lnrpc.FeeReportResponse")
-	proto.RegisterType((*PolicyUpdateRequest)(nil), "lnrpc.PolicyUpdateRequest")
-	proto.RegisterType((*PolicyUpdateResponse)(nil), "lnrpc.PolicyUpdateResponse")
-	proto.RegisterEnum("lnrpc.NewAddressRequest_AddressType", NewAddressRequest_AddressType_name, NewAddressRequest_AddressType_value)
+func (m *PolicyUpdateResponse) GetFailedUpdates() []*FailedUpdate {
+	if m != nil {
+		return m.FailedUpdates
+	}
+	return nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+type ExportGraphRequest struct {
+}
 
-// Client API for WalletUnlocker service
+func (m *ExportGraphRequest) Reset()                    { *m = ExportGraphRequest{} }
+func (m *ExportGraphRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportGraphRequest) ProtoMessage()               {}
+func (*ExportGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{94} }
 
-type WalletUnlockerClient interface {
-	// * lncli: `create`
-	// CreateWallet is used at lnd startup to set the encryption password for
-	// the wallet database.
-	CreateWallet(ctx context.Context, in *CreateWalletRequest, opts ...grpc.CallOption) (*CreateWalletResponse, error)
-	// * lncli: `unlock`
-	// UnlockWallet is used at startup of lnd to provide a password to unlock
-	// the wallet database.
-	UnlockWallet(ctx context.Context, in *UnlockWalletRequest, opts ...grpc.CallOption) (*UnlockWalletResponse, error)
+type ExportGraphResponse struct {
+	// / The number of channels written to the snapshot.
+	NumChannels uint32 `protobuf:"varint,1,opt,name=num_channels" json:"num_channels,omitempty"`
+	// / The number of channel updates written to the snapshot.
+	NumUpdates uint32 `protobuf:"varint,2,opt,name=num_updates" json:"num_updates,omitempty"`
+	// / The number of node announcements written to the snapshot.
+	NumNodes uint32 `protobuf:"varint,3,opt,name=num_nodes" json:"num_nodes,omitempty"`
+	// / The serialized graph snapshot.
+	Snapshot []byte `protobuf:"bytes,4,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
 }
 
-type walletUnlockerClient struct {
-	cc *grpc.ClientConn
+func (m *ExportGraphResponse) Reset()                    { *m = ExportGraphResponse{} }
+func (m *ExportGraphResponse) String() string            { return proto.CompactTextString(m) }
+func (*ExportGraphResponse) ProtoMessage()               {}
+func (*ExportGraphResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{95} }
+
+func (m *ExportGraphResponse) GetNumChannels() uint32 {
+	if m != nil {
+		return m.NumChannels
+	}
+	return 0
 }
 
-func NewWalletUnlockerClient(cc *grpc.ClientConn) WalletUnlockerClient {
-	return &walletUnlockerClient{cc}
+func (m *ExportGraphResponse) GetNumUpdates() uint32 {
+	if m != nil {
+		return m.NumUpdates
+	}
+	return 0
 }
 
-func (c *walletUnlockerClient) CreateWallet(ctx context.Context, in *CreateWalletRequest, opts ...grpc.CallOption) (*CreateWalletResponse, error) {
-	out := new(CreateWalletResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.WalletUnlocker/CreateWallet", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ExportGraphResponse) GetNumNodes() uint32 {
+	if m != nil {
+		return m.NumNodes
 	}
-	return out, nil
+	return 0
 }
 
-func (c *walletUnlockerClient) UnlockWallet(ctx context.Context, in *UnlockWalletRequest, opts ...grpc.CallOption) (*UnlockWalletResponse, error) {
-	out := new(UnlockWalletResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.WalletUnlocker/UnlockWallet", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ExportGraphResponse) GetSnapshot() []byte {
+	if m != nil {
+		return m.Snapshot
 	}
-	return out, nil
+	return nil
 }
 
-// Server API for WalletUnlocker service
+type ImportGraphRequest struct {
+	// / The serialized graph snapshot to import, as returned by ExportGraph.
+	Snapshot []byte `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+}
 
-type WalletUnlockerServer interface {
-	// * lncli: `create`
-	// CreateWallet is used at lnd startup to set the encryption password for
-	// the wallet database.
-	CreateWallet(context.Context, *CreateWalletRequest) (*CreateWalletResponse, error)
-	// * lncli: `unlock`
-	// UnlockWallet is used at startup of lnd to provide a password to unlock
-	// the wallet database.
-	UnlockWallet(context.Context, *UnlockWalletRequest) (*UnlockWalletResponse, error)
+func (m *ImportGraphRequest) Reset()                    { *m = ImportGraphRequest{} }
+func (m *ImportGraphRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportGraphRequest) ProtoMessage()               {}
+func (*ImportGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{96} }
+
+func (m *ImportGraphRequest) GetSnapshot() []byte {
+	if m != nil {
+		return m.Snapshot
+	}
+	return nil
 }
 
-func RegisterWalletUnlockerServer(s *grpc.Server, srv WalletUnlockerServer) {
-	s.RegisterService(&_WalletUnlocker_serviceDesc, srv)
+type ImportGraphResponse struct {
+	// / The number of channels imported from the snapshot.
+	NumChannels uint32 `protobuf:"varint,1,opt,name=num_channels" json:"num_channels,omitempty"`
+	// / The number of channel updates imported from the snapshot.
+	NumUpdates uint32 `protobuf:"varint,2,opt,name=num_updates" json:"num_updates,omitempty"`
+	// / The number of node announcements imported from the snapshot.
+	NumNodes uint32 `protobuf:"varint,3,opt,name=num_nodes" json:"num_nodes,omitempty"`
+	// / The number of messages within the snapshot that were invalid or outdated, and have been skipped.
+	NumSkipped uint32 `protobuf:"varint,4,opt,name=num_skipped" json:"num_skipped,omitempty"`
 }
 
-func _WalletUnlocker_CreateWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateWalletRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *ImportGraphResponse) Reset()                    { *m = ImportGraphResponse{} }
+func (m *ImportGraphResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportGraphResponse) ProtoMessage()               {}
+func (*ImportGraphResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{97} }
+
+func (m *ImportGraphResponse) GetNumChannels() uint32 {
+	if m != nil {
+		return m.NumChannels
 	}
-	if interceptor == nil {
-		return srv.(WalletUnlockerServer).CreateWallet(ctx, in)
+	return 0
+}
+
+func (m *ImportGraphResponse) GetNumUpdates() uint32 {
+	if m != nil {
+		return m.NumUpdates
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/lnrpc.WalletUnlocker/CreateWallet",
+	return 0
+}
+
+func (m *ImportGraphResponse) GetNumNodes() uint32 {
+	if m != nil {
+		return m.NumNodes
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletUnlockerServer).CreateWallet(ctx, req.(*CreateWalletRequest))
+	return 0
+}
+
+func (m *ImportGraphResponse) GetNumSkipped() uint32 {
+	if m != nil {
+		return m.NumSkipped
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _WalletUnlocker_UnlockWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UnlockWalletRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+type ForwardingFilterRequest struct {
+}
+
+func (m *ForwardingFilterRequest) Reset()                    { *m = ForwardingFilterRequest{} }
+func (m *ForwardingFilterRequest) String() string            { return proto.CompactTextString(m) }
+func (*ForwardingFilterRequest) ProtoMessage()               {}
+func (*ForwardingFilterRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{98} }
+
+type UpdateForwardingFilterRequest struct {
+	// / The hex-encoded public keys of the peers to add to the allow list.
+	Allow []string `protobuf:"bytes,1,rep,name=allow" json:"allow,omitempty"`
+	// / The hex-encoded public keys of the peers to add to the deny list.
+	Deny []string `protobuf:"bytes,2,rep,name=deny" json:"deny,omitempty"`
+	// / The hex-encoded public keys of the peers to remove from both lists.
+	Remove []string `protobuf:"bytes,3,rep,name=remove" json:"remove,omitempty"`
+}
+
+func (m *UpdateForwardingFilterRequest) Reset()                    { *m = UpdateForwardingFilterRequest{} }
+func (m *UpdateForwardingFilterRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateForwardingFilterRequest) ProtoMessage()               {}
+func (*UpdateForwardingFilterRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{99} }
+
+func (m *UpdateForwardingFilterRequest) GetAllow() []string {
+	if m != nil {
+		return m.Allow
 	}
-	if interceptor == nil {
-		return srv.(WalletUnlockerServer).UnlockWallet(ctx, in)
+	return nil
+}
+
+func (m *UpdateForwardingFilterRequest) GetDeny() []string {
+	if m != nil {
+		return m.Deny
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/lnrpc.WalletUnlocker/UnlockWallet",
+	return nil
+}
+
+func (m *UpdateForwardingFilterRequest) GetRemove() []string {
+	if m != nil {
+		return m.Remove
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WalletUnlockerServer).UnlockWallet(ctx, req.(*UnlockWalletRequest))
+	return nil
+}
+
+type ForwardingFilterResponse struct {
+	// / The hex-encoded public keys of the peers within the allow list. If non-empty, then forwards involving any other peer are rejected.
+	Allowed []string `protobuf:"bytes,1,rep,name=allowed" json:"allowed,omitempty"`
+	// / The hex-encoded public keys of the peers within the deny list.
+	Denied []string `protobuf:"bytes,2,rep,name=denied" json:"denied,omitempty"`
+}
+
+func (m *ForwardingFilterResponse) Reset()                    { *m = ForwardingFilterResponse{} }
+func (m *ForwardingFilterResponse) String() string            { return proto.CompactTextString(m) }
+func (*ForwardingFilterResponse) ProtoMessage()               {}
+func (*ForwardingFilterResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{100} }
+
+func (m *ForwardingFilterResponse) GetAllowed() []string {
+	if m != nil {
+		return m.Allowed
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-var _WalletUnlocker_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "lnrpc.WalletUnlocker",
-	HandlerType: (*WalletUnlockerServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreateWallet",
-			Handler:    _WalletUnlocker_CreateWallet_Handler,
-		},
-		{
-			MethodName: "UnlockWallet",
-			Handler:    _WalletUnlocker_UnlockWallet_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "rpc.proto",
+func (m *ForwardingFilterResponse) GetDenied() []string {
+	if m != nil {
+		return m.Denied
+	}
+	return nil
 }
 
-// Client API for Lightning service
+type PeerMessageSubscription struct {
+	// / The hex-encoded public key of the peer whose messages should be streamed.
+	PubKey string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+}
 
-type LightningClient interface {
-	// * lncli: `walletbalance`
-	// WalletBalance returns total unspent outputs(confirmed and unconfirmed), all confirmed unspent outputs and all unconfirmed unspent outputs under control
-	// by the wallet. This method can be modified by having the request specify
-	// only witness outputs should be factored into the final output sum.
-	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
-	// * lncli: `channelbalance`
-	// ChannelBalance returns the total funds available across all open channels
-	// in satoshis.
-	ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error)
-	// * lncli: `listchaintxns`
-	// GetTransactions returns a list describing all the known transactions
-	// relevant to the wallet.
-	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error)
-	// * lncli: `sendcoins`
-	// SendCoins executes a request to send coins to a particular address. Unlike
-	// SendMany, this RPC call only allows creating a single output at a time. If
-	// neither target_conf, or sat_per_byte are set, then the internal wallet will
-	// consult its fee model to determine a fee for the default confirmation
-	// target.
-	SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error)
-	// *
-	// SubscribeTransactions creates a uni-directional stream from the server to
-	// the client in which any newly discovered transactions relevant to the
-	// wallet are sent over.
-	SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error)
-	// * lncli: `sendmany`
-	// SendMany handles a request for a transaction that creates multiple specified
-	// outputs in parallel. If neither target_conf, or sat_per_byte are set, then
-	// the internal wallet will consult its fee model to determine a fee for the
-	// default confirmation target.
-	SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error)
-	// * lncli: `newaddress`
-	// NewAddress creates a new address under control of the local wallet.
-	NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
-	// *
-	// NewWitnessAddress creates a new witness address under control of the local wallet.
-	NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
-	// * lncli: `signmessage`
-	// SignMessage signs a message with this node's private key. The returned
-	// signature string is `zbase32` encoded and pubkey recoverable, meaning that
-	// only the message digest and signature are needed for verification.
-	SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error)
-	// * lncli: `verifymessage`
-	// VerifyMessage verifies a signature over a msg. The signature must be
-	// zbase32 encoded and signed by an active node in the resident node's
-	// channel database. In addition to returning the validity of the signature,
-	// VerifyMessage also returns the recovered pubkey from the signature.
-	VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error)
-	// * lncli: `connect`
-	// ConnectPeer attempts to establish a connection to a remote peer. This is at
-	// the networking level, and is used for communication between nodes. This is
-	// distinct from establishing a channel with a peer.
-	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
-	// * lncli: `disconnect`
-	// DisconnectPeer attempts to disconnect one peer from another identified by a
-	// given pubKey. In the case that we currently have a pending or active channel
-	// with the target peer, then this action will be not be allowed.
-	DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error)
-	// * lncli: `listpeers`
-	// ListPeers returns a verbose listing of all currently active peers.
-	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
-	// * lncli: `getinfo`
-	// GetInfo returns general information concerning the lightning node including
-	// it's identity pubkey, alias, the chains it is connected to, and information
-	// concerning the number of open+pending channels.
-	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
-	// * lncli: `pendingchannels`
-	// PendingChannels returns a list of all the channels that are currently
-	// considered "pending". A channel is pending if it has finished the funding
-	// workflow and is waiting for confirmations for the funding txn, or is in the
-	// process of closure, either initiated cooperatively or non-cooperatively.
-	PendingChannels(ctx context.Context, in *PendingChannelsRequest, opts ...grpc.CallOption) (*PendingChannelsResponse, error)
-	// * lncli: `listchannels`
-	// ListChannels returns a description of all the open channels that this node
-	// is a participant in.
-	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
-	// *
-	// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
-	// call is meant to be consumed by clients to the REST proxy. As with all
-	// other sync calls, all byte slices are intended to be populated as hex
-	// encoded strings.
-	OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error)
-	// * lncli: `openchannel`
-	// OpenChannel attempts to open a singly funded channel specified in the
-	// request to a remote peer. Users are able to specify a target number of
-	// blocks that the funding transaction should be confirmed in, or a manual fee
-	// rate to us for the funding transaction. If neither are specified, then a
-	// lax block confirmation target is used.
-	OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error)
-	// * lncli: `closechannel`
-	// CloseChannel attempts to close an active channel identified by its channel
-	// outpoint (ChannelPoint). The actions of this method can additionally be
-	// augmented to attempt a force close after a timeout period in the case of an
-	// inactive peer. If a non-force close (cooperative closure) is requested,
-	// then the user can specify either a target number of blocks until the
-	// closure transaction is confirmed, or a manual fee rate. If neither are
-	// specified, then a default lax, block confirmation target is used.
-	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
-	// * lncli: `sendpayment`
-	// SendPayment dispatches a bi-directional streaming RPC for sending payments
-	// through the Lightning Network. A single RPC invocation creates a persistent
-	// bi-directional stream allowing clients to rapidly send payments through the
-	// Lightning Network with a single persistent connection.
-	SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error)
-	// *
-	// SendPaymentSync is the synchronous non-streaming version of SendPayment.
-	// This RPC is intended to be consumed by clients of the REST proxy.
-	// Additionally, this RPC expects the destination's public key and the payment
-	// hash (if any) to be encoded as hex strings.
-	SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
-	// * lncli: `addinvoice`
-	// AddInvoice attempts to add a new invoice to the invoice database. Any
-	// duplicated invoices are rejected, therefore all invoices *must* have a
-	// unique payment preimage.
-	AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
-	// * lncli: `listinvoices`
-	// ListInvoices returns a list of all the invoices currently stored within the
-	// database. Any active debug invoices are ignored.
-	ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error)
-	// * lncli: `lookupinvoice`
-	// LookupInvoice attemps to look up an invoice according to its payment hash.
-	// The passed payment hash *must* be exactly 32 bytes, if not, an error is
-	// returned.
-	LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error)
-	// *
-	// SubscribeInvoices returns a uni-directional stream (sever -> client) for
-	// notifying the client of newly added/settled invoices.
-	SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error)
-	// * lncli: `decodepayreq`
-	// DecodePayReq takes an encoded payment request string and attempts to decode
-	// it, returning a full description of the conditions encoded within the
-	// payment request.
-	DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error)
-	// * lncli: `listpayments`
-	// ListPayments returns a list of all outgoing payments.
-	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
-	// *
-	// DeleteAllPayments deletes all outgoing payments from DB.
-	DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error)
-	// * lncli: `describegraph`
-	// DescribeGraph returns a description of the latest graph state from the
-	// point of view of the node. The graph information is partitioned into two
-	// components: all the nodes/vertexes, and all the edges that connect the
-	// vertexes themselves.  As this is a directed graph, the edges also contain
-	// the node directional specific routing policy which includes: the time lock
-	// delta, fee information, etc.
-	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
-	// * lncli: `getchaninfo`
-	// GetChanInfo returns the latest authenticated network announcement for the
-	// given channel identified by its channel ID: an 8-byte integer which
-	// uniquely identifies the location of transaction's funding output within the
-	// blockchain.
-	GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error)
-	// * lncli: `getnodeinfo`
-	// GetNodeInfo returns the latest advertised, aggregated, and authenticated
-	// channel information for the specified node identified by its public key.
-	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
-	// * lncli: `queryroutes`
-	// QueryRoutes attempts to query the daemon's Channel Router for a possible
-	// route to a target destination capable of carrying a specific amount of
-	// satoshis. The retuned route contains the full details required to craft and
-	// send an HTLC, also including the necessary information that should be
-	// present within the Sphinx packet encapsualted within the HTLC.
-	QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error)
-	// * lncli: `getnetworkinfo`
-	// GetNetworkInfo returns some basic stats about the known channel graph from
-	// the point of view of the node.
-	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
-	// * lncli: `stop`
-	// StopDaemon will send a shutdown request to the interrupt handler, triggering
-	// a graceful shutdown of the daemon.
-	StopDaemon(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
-	// *
-	// SubscribeChannelGraph launches a streaming RPC that allows the caller to
-	// receive notifications upon any changes to the channel graph topology from
-	// the point of view of the responding node. Events notified include: new
-	// nodes coming online, nodes updating their authenticated attributes, new
-	// channels being advertised, updates in the routing policy for a directional
-	// channel edge, and when channels are closed on-chain.
-	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
-	// * lncli: `debuglevel`
-	// DebugLevel allows a caller to programmatically set the logging verbosity of
-	// lnd. The logging can be targeted according to a coarse daemon-wide logging
-	// level, or in a granular fashion to specify the logging for a target
-	// sub-system.
-	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
-	// * lncli: `feereport`
-	// FeeReport allows the caller to obtain a report detailing the current fee
-	// schedule enforced by the node globally for each channel.
-	FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error)
-	// * lncli: `updatechanpolicy`
-	// UpdateChannelPolicy allows the caller to update the fee schedule and
-	// channel policies for all channels globally, or a particular channel.
-	UpdateChannelPolicy(ctx context.Context, in *PolicyUpdateRequest, opts ...grpc.CallOption) (*PolicyUpdateResponse, error)
-}
+func (m *PeerMessageSubscription) Reset()                    { *m = PeerMessageSubscription{} }
+func (m *PeerMessageSubscription) String() string            { return proto.CompactTextString(m) }
+func (*PeerMessageSubscription) ProtoMessage()               {}
+func (*PeerMessageSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{101} }
 
-type lightningClient struct {
-	cc *grpc.ClientConn
+func (m *PeerMessageSubscription) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
 }
 
-func NewLightningClient(cc *grpc.ClientConn) LightningClient {
-	return &lightningClient{cc}
+type PeerMessage struct {
+	// / The hex-encoded public key of the peer.
+	PubKey string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+	// / Whether the message was received from, rather than sent to, the peer.
+	Inbound bool `protobuf:"varint,2,opt,name=inbound" json:"inbound,omitempty"`
+	// / The type of the message.
+	MsgType string `protobuf:"bytes,3,opt,name=msg_type" json:"msg_type,omitempty"`
+	// / A brief summary of the message's contents.
+	Summary string `protobuf:"bytes,4,opt,name=summary" json:"summary,omitempty"`
+	// / A full dump of the decoded message.
+	Dump string `protobuf:"bytes,5,opt,name=dump" json:"dump,omitempty"`
+	// / The unix timestamp in nanoseconds at which the message was sent or received.
+	TimestampNs int64 `protobuf:"varint,6,opt,name=timestamp_ns" json:"timestamp_ns,omitempty"`
 }
 
-func (c *lightningClient) WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error) {
-	out := new(WalletBalanceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/WalletBalance", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *PeerMessage) Reset()                    { *m = PeerMessage{} }
+func (m *PeerMessage) String() string            { return proto.CompactTextString(m) }
+func (*PeerMessage) ProtoMessage()               {}
+func (*PeerMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{102} }
+
+func (m *PeerMessage) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
 	}
-	return out, nil
+	return ""
 }
 
-func (c *lightningClient) ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error) {
-	out := new(ChannelBalanceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ChannelBalance", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *PeerMessage) GetInbound() bool {
+	if m != nil {
+		return m.Inbound
 	}
-	return out, nil
+	return false
 }
 
-func (c *lightningClient) GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error) {
-	out := new(TransactionDetails)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetTransactions", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *PeerMessage) GetMsgType() string {
+	if m != nil {
+		return m.MsgType
 	}
-	return out, nil
+	return ""
 }
 
-func (c *lightningClient) SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error) {
-	out := new(SendCoinsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendCoins", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *PeerMessage) GetSummary() string {
+	if m != nil {
+		return m.Summary
 	}
-	return out, nil
+	return ""
 }
 
-func (c *lightningClient) SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[0], c.cc, "/lnrpc.Lightning/SubscribeTransactions", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &lightningSubscribeTransactionsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *PeerMessage) GetDump() string {
+	if m != nil {
+		return m.Dump
 	}
-	return x, nil
+	return ""
 }
 
-type Lightning_SubscribeTransactionsClient interface {
-	Recv() (*Transaction, error)
-	grpc.ClientStream
+func (m *PeerMessage) GetTimestampNs() int64 {
+	if m != nil {
+		return m.TimestampNs
+	}
+	return 0
 }
 
-type lightningSubscribeTransactionsClient struct {
-	grpc.ClientStream
+type LiquidityHistoryRequest struct {
+	// / The channel point of the channel whose history should be returned.
+	ChanPoint *ChannelPoint `protobuf:"bytes,1,opt,name=chan_point" json:"chan_point,omitempty"`
+	// / The unix timestamp in seconds from which snapshots should be returned.
+	StartTime uint64 `protobuf:"varint,2,opt,name=start_time" json:"start_time,omitempty"`
+	// / The unix timestamp in seconds up to which snapshots should be returned. If 0, then snapshots up to the current time are returned.
+	EndTime uint64 `protobuf:"varint,3,opt,name=end_time" json:"end_time,omitempty"`
 }
 
-func (x *lightningSubscribeTransactionsClient) Recv() (*Transaction, error) {
-	m := new(Transaction)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *LiquidityHistoryRequest) Reset()                    { *m = LiquidityHistoryRequest{} }
+func (m *LiquidityHistoryRequest) String() string            { return proto.CompactTextString(m) }
+func (*LiquidityHistoryRequest) ProtoMessage()               {}
+func (*LiquidityHistoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{103} }
+
+func (m *LiquidityHistoryRequest) GetChanPoint() *ChannelPoint {
+	if m != nil {
+		return m.ChanPoint
 	}
-	return m, nil
+	return nil
 }
 
-func (c *lightningClient) SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error) {
-	out := new(SendManyResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendMany", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *LiquidityHistoryRequest) GetStartTime() uint64 {
+	if m != nil {
+		return m.StartTime
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
-	out := new(NewAddressResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewAddress", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *LiquidityHistoryRequest) GetEndTime() uint64 {
+	if m != nil {
+		return m.EndTime
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
-	out := new(NewAddressResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewWitnessAddress", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type LiquiditySnapshot struct {
+	// / The unix timestamp in seconds at which the snapshot was taken.
+	Timestamp uint64 `protobuf:"varint,1,opt,name=timestamp" json:"timestamp,omitempty"`
+	// / Our settled balance within the channel in millisatoshis.
+	LocalBalanceMsat uint64 `protobuf:"varint,2,opt,name=local_balance_msat" json:"local_balance_msat,omitempty"`
+	// / The remote party's settled balance within the channel in millisatoshis.
+	RemoteBalanceMsat uint64 `protobuf:"varint,3,opt,name=remote_balance_msat" json:"remote_balance_msat,omitempty"`
+	// / The total value of the HTLCs pending within the channel in millisatoshis.
+	PendingHtlcMsat uint64 `protobuf:"varint,4,opt,name=pending_htlc_msat" json:"pending_htlc_msat,omitempty"`
+	// / The number of HTLCs pending within the channel.
+	NumPendingHtlcs uint32 `protobuf:"varint,5,opt,name=num_pending_htlcs" json:"num_pending_htlcs,omitempty"`
 }
 
-func (c *lightningClient) SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error) {
-	out := new(SignMessageResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SignMessage", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *LiquiditySnapshot) Reset()                    { *m = LiquiditySnapshot{} }
+func (m *LiquiditySnapshot) String() string            { return proto.CompactTextString(m) }
+func (*LiquiditySnapshot) ProtoMessage()               {}
+func (*LiquiditySnapshot) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{104} }
+
+func (m *LiquiditySnapshot) GetTimestamp() uint64 {
+	if m != nil {
+		return m.Timestamp
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error) {
-	out := new(VerifyMessageResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/VerifyMessage", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *LiquiditySnapshot) GetLocalBalanceMsat() uint64 {
+	if m != nil {
+		return m.LocalBalanceMsat
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error) {
-	out := new(ConnectPeerResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ConnectPeer", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *LiquiditySnapshot) GetRemoteBalanceMsat() uint64 {
+	if m != nil {
+		return m.RemoteBalanceMsat
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error) {
-	out := new(DisconnectPeerResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DisconnectPeer", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *LiquiditySnapshot) GetPendingHtlcMsat() uint64 {
+	if m != nil {
+		return m.PendingHtlcMsat
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error) {
-	out := new(ListPeersResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPeers", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *LiquiditySnapshot) GetNumPendingHtlcs() uint32 {
+	if m != nil {
+		return m.NumPendingHtlcs
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
-	out := new(GetInfoResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetInfo", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type LiquidityHistoryResponse struct {
+	// / The snapshots taken within the requested time range, in chronological order.
+	Snapshots []*LiquiditySnapshot `protobuf:"bytes,1,rep,name=snapshots" json:"snapshots,omitempty"`
 }
 
-func (c *lightningClient) PendingChannels(ctx context.Context, in *PendingChannelsRequest, opts ...grpc.CallOption) (*PendingChannelsResponse, error) {
-	out := new(PendingChannelsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PendingChannels", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *LiquidityHistoryResponse) Reset()                    { *m = LiquidityHistoryResponse{} }
+func (m *LiquidityHistoryResponse) String() string            { return proto.CompactTextString(m) }
+func (*LiquidityHistoryResponse) ProtoMessage()               {}
+func (*LiquidityHistoryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{105} }
+
+func (m *LiquidityHistoryResponse) GetSnapshots() []*LiquiditySnapshot {
+	if m != nil {
+		return m.Snapshots
 	}
-	return out, nil
+	return nil
 }
 
-func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error) {
-	out := new(ListChannelsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListChannels", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type InjectSwitchFaultRequest struct {
+	// / The action to take upon each matching packet, either "drop", "delay" or "duplicate".
+	Action string `protobuf:"bytes,1,opt,name=action" json:"action,omitempty"`
+	// / The type of update the fault applies to, either "add", "settle" or "fail". If empty, the fault applies to packets of any type.
+	UpdateType string `protobuf:"bytes,2,opt,name=update_type" json:"update_type,omitempty"`
+	// / The number of matching packets the fault applies to. If 0, it applies to a single packet.
+	Count uint32 `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+	// / The time in milliseconds for which matching packets are held, if the action is "delay".
+	DelayMs uint32 `protobuf:"varint,4,opt,name=delay_ms" json:"delay_ms,omitempty"`
+	// / If set, all pending faults are discarded, and no new fault is injected.
+	Clear bool `protobuf:"varint,5,opt,name=clear" json:"clear,omitempty"`
 }
 
-func (c *lightningClient) OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error) {
-	out := new(ChannelPoint)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/OpenChannelSync", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *InjectSwitchFaultRequest) Reset()                    { *m = InjectSwitchFaultRequest{} }
+func (m *InjectSwitchFaultRequest) String() string            { return proto.CompactTextString(m) }
+func (*InjectSwitchFaultRequest) ProtoMessage()               {}
+func (*InjectSwitchFaultRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{106} }
+
+func (m *InjectSwitchFaultRequest) GetAction() string {
+	if m != nil {
+		return m.Action
 	}
-	return out, nil
+	return ""
 }
 
-func (c *lightningClient) OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[1], c.cc, "/lnrpc.Lightning/OpenChannel", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &lightningOpenChannelClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *InjectSwitchFaultRequest) GetUpdateType() string {
+	if m != nil {
+		return m.UpdateType
 	}
-	return x, nil
+	return ""
 }
 
-type Lightning_OpenChannelClient interface {
-	Recv() (*OpenStatusUpdate, error)
-	grpc.ClientStream
+func (m *InjectSwitchFaultRequest) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
 }
 
-type lightningOpenChannelClient struct {
-	grpc.ClientStream
+func (m *InjectSwitchFaultRequest) GetDelayMs() uint32 {
+	if m != nil {
+		return m.DelayMs
+	}
+	return 0
 }
 
-func (x *lightningOpenChannelClient) Recv() (*OpenStatusUpdate, error) {
-	m := new(OpenStatusUpdate)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *InjectSwitchFaultRequest) GetClear() bool {
+	if m != nil {
+		return m.Clear
 	}
-	return m, nil
+	return false
 }
 
-func (c *lightningClient) CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[2], c.cc, "/lnrpc.Lightning/CloseChannel", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &lightningCloseChannelClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
+type InjectSwitchFaultResponse struct {
+	// / The number of faults that have yet to be fully applied.
+	NumPending uint32 `protobuf:"varint,1,opt,name=num_pending" json:"num_pending,omitempty"`
 }
 
-type Lightning_CloseChannelClient interface {
-	Recv() (*CloseStatusUpdate, error)
-	grpc.ClientStream
+func (m *InjectSwitchFaultResponse) Reset()                    { *m = InjectSwitchFaultResponse{} }
+func (m *InjectSwitchFaultResponse) String() string            { return proto.CompactTextString(m) }
+func (*InjectSwitchFaultResponse) ProtoMessage()               {}
+func (*InjectSwitchFaultResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{107} }
+
+func (m *InjectSwitchFaultResponse) GetNumPending() uint32 {
+	if m != nil {
+		return m.NumPending
+	}
+	return 0
 }
 
-type lightningCloseChannelClient struct {
-	grpc.ClientStream
+type UpdateChanStatusRequest struct {
+	// / The target channel.
+	ChanPoint *ChannelPoint `protobuf:"bytes,1,opt,name=chan_point" json:"chan_point,omitempty"`
+	// / The status to pin the channel to, either "disable" or "enable", or "auto" to release a prior pin.
+	Action string `protobuf:"bytes,2,opt,name=action" json:"action,omitempty"`
 }
 
-func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
-	m := new(CloseStatusUpdate)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *UpdateChanStatusRequest) Reset()                    { *m = UpdateChanStatusRequest{} }
+func (m *UpdateChanStatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateChanStatusRequest) ProtoMessage()               {}
+func (*UpdateChanStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{108} }
+
+func (m *UpdateChanStatusRequest) GetChanPoint() *ChannelPoint {
+	if m != nil {
+		return m.ChanPoint
 	}
-	return m, nil
+	return nil
 }
 
-func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[3], c.cc, "/lnrpc.Lightning/SendPayment", opts...)
-	if err != nil {
-		return nil, err
+func (m *UpdateChanStatusRequest) GetAction() string {
+	if m != nil {
+		return m.Action
 	}
-	x := &lightningSendPaymentClient{stream}
-	return x, nil
+	return ""
 }
 
-type Lightning_SendPaymentClient interface {
-	Send(*SendRequest) error
-	Recv() (*SendResponse, error)
-	grpc.ClientStream
+type UpdateChanStatusResponse struct {
 }
 
-type lightningSendPaymentClient struct {
-	grpc.ClientStream
-}
+func (m *UpdateChanStatusResponse) Reset()                    { *m = UpdateChanStatusResponse{} }
+func (m *UpdateChanStatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdateChanStatusResponse) ProtoMessage()               {}
+func (*UpdateChanStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{109} }
 
-func (x *lightningSendPaymentClient) Send(m *SendRequest) error {
-	return x.ClientStream.SendMsg(m)
+type SettleHoldInvoiceRequest struct {
+	// / The preimage of the hold invoice to be settled, which must match its payment hash.
+	RPreimage []byte `protobuf:"bytes,1,opt,name=r_preimage,proto3" json:"r_preimage,omitempty"`
 }
 
-func (x *lightningSendPaymentClient) Recv() (*SendResponse, error) {
-	m := new(SendResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *SettleHoldInvoiceRequest) Reset()                    { *m = SettleHoldInvoiceRequest{} }
+func (m *SettleHoldInvoiceRequest) String() string            { return proto.CompactTextString(m) }
+func (*SettleHoldInvoiceRequest) ProtoMessage()               {}
+func (*SettleHoldInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{110} }
+
+func (m *SettleHoldInvoiceRequest) GetRPreimage() []byte {
+	if m != nil {
+		return m.RPreimage
 	}
-	return m, nil
+	return nil
 }
 
-func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
-	out := new(SendResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendPaymentSync", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type SettleHoldInvoiceResponse struct {
 }
 
-func (c *lightningClient) AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error) {
-	out := new(AddInvoiceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AddInvoice", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *SettleHoldInvoiceResponse) Reset()                    { *m = SettleHoldInvoiceResponse{} }
+func (m *SettleHoldInvoiceResponse) String() string            { return proto.CompactTextString(m) }
+func (*SettleHoldInvoiceResponse) ProtoMessage()               {}
+func (*SettleHoldInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{111} }
+
+type CancelHoldInvoiceResponse struct {
 }
 
-func (c *lightningClient) ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error) {
-	out := new(ListInvoiceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListInvoices", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *CancelHoldInvoiceResponse) Reset()                    { *m = CancelHoldInvoiceResponse{} }
+func (m *CancelHoldInvoiceResponse) String() string            { return proto.CompactTextString(m) }
+func (*CancelHoldInvoiceResponse) ProtoMessage()               {}
+func (*CancelHoldInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{112} }
+
+type ForwardingHistoryRequest struct {
+	// / The unix timestamp in seconds from which forwarding events should be returned.
+	StartTime uint64 `protobuf:"varint,1,opt,name=start_time" json:"start_time,omitempty"`
+	// / The unix timestamp in seconds up to which forwarding events should be returned. If 0, then events up to the current time are returned.
+	EndTime uint64 `protobuf:"varint,2,opt,name=end_time" json:"end_time,omitempty"`
+	// / The number of events within the time range to skip, used to paginate the results.
+	IndexOffset uint32 `protobuf:"varint,3,opt,name=index_offset" json:"index_offset,omitempty"`
+	// / The maximum number of events to return. If 0, then at most 100 events are returned.
+	NumMaxEvents uint32 `protobuf:"varint,4,opt,name=num_max_events" json:"num_max_events,omitempty"`
 }
 
-func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error) {
-	out := new(Invoice)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/LookupInvoice", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ForwardingHistoryRequest) Reset()                    { *m = ForwardingHistoryRequest{} }
+func (m *ForwardingHistoryRequest) String() string            { return proto.CompactTextString(m) }
+func (*ForwardingHistoryRequest) ProtoMessage()               {}
+func (*ForwardingHistoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{113} }
+
+func (m *ForwardingHistoryRequest) GetStartTime() uint64 {
+	if m != nil {
+		return m.StartTime
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[4], c.cc, "/lnrpc.Lightning/SubscribeInvoices", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &lightningSubscribeInvoicesClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+func (m *ForwardingHistoryRequest) GetEndTime() uint64 {
+	if m != nil {
+		return m.EndTime
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	return 0
+}
+
+func (m *ForwardingHistoryRequest) GetIndexOffset() uint32 {
+	if m != nil {
+		return m.IndexOffset
 	}
-	return x, nil
+	return 0
 }
 
-type Lightning_SubscribeInvoicesClient interface {
-	Recv() (*Invoice, error)
-	grpc.ClientStream
+func (m *ForwardingHistoryRequest) GetNumMaxEvents() uint32 {
+	if m != nil {
+		return m.NumMaxEvents
+	}
+	return 0
 }
 
-type lightningSubscribeInvoicesClient struct {
-	grpc.ClientStream
+type ForwardingEvent struct {
+	// / The unix timestamp in seconds at which the forwarded HTLC was settled.
+	Timestamp uint64 `protobuf:"varint,1,opt,name=timestamp" json:"timestamp,omitempty"`
+	// / The short channel ID of the channel over which the HTLC arrived.
+	ChanIdIn uint64 `protobuf:"varint,2,opt,name=chan_id_in" json:"chan_id_in,omitempty"`
+	// / The short channel ID of the channel over which the HTLC was forwarded.
+	ChanIdOut uint64 `protobuf:"varint,3,opt,name=chan_id_out" json:"chan_id_out,omitempty"`
+	// / The value of the incoming HTLC in millisatoshis.
+	AmtInMsat uint64 `protobuf:"varint,4,opt,name=amt_in_msat" json:"amt_in_msat,omitempty"`
+	// / The value of the outgoing HTLC in millisatoshis.
+	AmtOutMsat uint64 `protobuf:"varint,5,opt,name=amt_out_msat" json:"amt_out_msat,omitempty"`
+	// / The fee earned by forwarding the HTLC in millisatoshis.
+	FeeMsat uint64 `protobuf:"varint,6,opt,name=fee_msat" json:"fee_msat,omitempty"`
 }
 
-func (x *lightningSubscribeInvoicesClient) Recv() (*Invoice, error) {
-	m := new(Invoice)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *ForwardingEvent) Reset()                    { *m = ForwardingEvent{} }
+func (m *ForwardingEvent) String() string            { return proto.CompactTextString(m) }
+func (*ForwardingEvent) ProtoMessage()               {}
+func (*ForwardingEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{114} }
+
+func (m *ForwardingEvent) GetTimestamp() uint64 {
+	if m != nil {
+		return m.Timestamp
 	}
-	return m, nil
+	return 0
 }
 
-func (c *lightningClient) DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error) {
-	out := new(PayReq)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DecodePayReq", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ForwardingEvent) GetChanIdIn() uint64 {
+	if m != nil {
+		return m.ChanIdIn
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error) {
-	out := new(ListPaymentsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPayments", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ForwardingEvent) GetChanIdOut() uint64 {
+	if m != nil {
+		return m.ChanIdOut
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error) {
-	out := new(DeleteAllPaymentsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DeleteAllPayments", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ForwardingEvent) GetAmtInMsat() uint64 {
+	if m != nil {
+		return m.AmtInMsat
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error) {
-	out := new(ChannelGraph)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DescribeGraph", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ForwardingEvent) GetAmtOutMsat() uint64 {
+	if m != nil {
+		return m.AmtOutMsat
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error) {
-	out := new(ChannelEdge)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetChanInfo", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ForwardingEvent) GetFeeMsat() uint64 {
+	if m != nil {
+		return m.FeeMsat
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error) {
-	out := new(NodeInfo)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNodeInfo", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type ForwardingHistoryResponse struct {
+	// / The forwarding events within the requested time range, in chronological order.
+	ForwardingEvents []*ForwardingEvent `protobuf:"bytes,1,rep,name=forwarding_events" json:"forwarding_events,omitempty"`
+	// / The index offset of the event following the last one returned, to be used as the index_offset of the request for the next page.
+	LastOffsetIndex uint32 `protobuf:"varint,2,opt,name=last_offset_index" json:"last_offset_index,omitempty"`
 }
 
-func (c *lightningClient) QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error) {
-	out := new(QueryRoutesResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryRoutes", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ForwardingHistoryResponse) Reset()                    { *m = ForwardingHistoryResponse{} }
+func (m *ForwardingHistoryResponse) String() string            { return proto.CompactTextString(m) }
+func (*ForwardingHistoryResponse) ProtoMessage()               {}
+func (*ForwardingHistoryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{115} }
+
+func (m *ForwardingHistoryResponse) GetForwardingEvents() []*ForwardingEvent {
+	if m != nil {
+		return m.ForwardingEvents
 	}
-	return out, nil
+	return nil
 }
 
-func (c *lightningClient) GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error) {
-	out := new(NetworkInfo)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNetworkInfo", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ForwardingHistoryResponse) GetLastOffsetIndex() uint32 {
+	if m != nil {
+		return m.LastOffsetIndex
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
-	out := new(StopResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/StopDaemon", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type BatchAddInvoiceRequest struct {
+	// / The invoice whose parameters are shared by each invoice of the batch. Neither r_preimage nor r_hash may be set.
+	Invoice *Invoice `protobuf:"bytes,1,opt,name=invoice" json:"invoice,omitempty"`
+	// / The number of invoices to create, which must not exceed 1000.
+	NumInvoices uint32 `protobuf:"varint,2,opt,name=num_invoices" json:"num_invoices,omitempty"`
 }
 
-func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[5], c.cc, "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &lightningSubscribeChannelGraphClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *BatchAddInvoiceRequest) Reset()                    { *m = BatchAddInvoiceRequest{} }
+func (m *BatchAddInvoiceRequest) String() string            { return proto.CompactTextString(m) }
+func (*BatchAddInvoiceRequest) ProtoMessage()               {}
+func (*BatchAddInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{116} }
+
+func (m *BatchAddInvoiceRequest) GetInvoice() *Invoice {
+	if m != nil {
+		return m.Invoice
 	}
-	return x, nil
+	return nil
 }
 
-type Lightning_SubscribeChannelGraphClient interface {
-	Recv() (*GraphTopologyUpdate, error)
-	grpc.ClientStream
+func (m *BatchAddInvoiceRequest) GetNumInvoices() uint32 {
+	if m != nil {
+		return m.NumInvoices
+	}
+	return 0
 }
 
-type lightningSubscribeChannelGraphClient struct {
-	grpc.ClientStream
+type BatchAddInvoiceResponse struct {
+	// / The invoices that were created, in the order they were added.
+	Invoices []*AddInvoiceResponse `protobuf:"bytes,1,rep,name=invoices" json:"invoices,omitempty"`
 }
 
-func (x *lightningSubscribeChannelGraphClient) Recv() (*GraphTopologyUpdate, error) {
-	m := new(GraphTopologyUpdate)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *BatchAddInvoiceResponse) Reset()                    { *m = BatchAddInvoiceResponse{} }
+func (m *BatchAddInvoiceResponse) String() string            { return proto.CompactTextString(m) }
+func (*BatchAddInvoiceResponse) ProtoMessage()               {}
+func (*BatchAddInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{117} }
+
+func (m *BatchAddInvoiceResponse) GetInvoices() []*AddInvoiceResponse {
+	if m != nil {
+		return m.Invoices
 	}
-	return m, nil
+	return nil
 }
 
-func (c *lightningClient) DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error) {
-	out := new(DebugLevelResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DebugLevel", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+type ChannelPointList struct {
+	// / The listed channel points.
+	ChanPoints []*ChannelPoint `protobuf:"bytes,1,rep,name=chan_points" json:"chan_points,omitempty"`
+}
+
+func (m *ChannelPointList) Reset()                    { *m = ChannelPointList{} }
+func (m *ChannelPointList) String() string            { return proto.CompactTextString(m) }
+func (*ChannelPointList) ProtoMessage()               {}
+func (*ChannelPointList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{118} }
+
+func (m *ChannelPointList) GetChanPoints() []*ChannelPoint {
+	if m != nil {
+		return m.ChanPoints
 	}
-	return out, nil
+	return nil
 }
 
-func (c *lightningClient) FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error) {
-	out := new(FeeReportResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/FeeReport", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+type FailedUpdate struct {
+	// / The channel point of the channel the update couldn't be applied to.
+	Outpoint *ChannelPoint `protobuf:"bytes,1,opt,name=outpoint" json:"outpoint,omitempty"`
+	// / The reason the update couldn't be applied to the channel.
+	UpdateError string `protobuf:"bytes,2,opt,name=update_error" json:"update_error,omitempty"`
+}
+
+func (m *FailedUpdate) Reset()                    { *m = FailedUpdate{} }
+func (m *FailedUpdate) String() string            { return proto.CompactTextString(m) }
+func (*FailedUpdate) ProtoMessage()               {}
+func (*FailedUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{119} }
+
+func (m *FailedUpdate) GetOutpoint() *ChannelPoint {
+	if m != nil {
+		return m.Outpoint
 	}
-	return out, nil
+	return nil
 }
 
-func (c *lightningClient) UpdateChannelPolicy(ctx context.Context, in *PolicyUpdateRequest, opts ...grpc.CallOption) (*PolicyUpdateResponse, error) {
-	out := new(PolicyUpdateResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateChannelPolicy", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *FailedUpdate) GetUpdateError() string {
+	if m != nil {
+		return m.UpdateError
 	}
-	return out, nil
+	return ""
 }
 
-// Server API for Lightning service
+type TimeLockedBalanceRequest struct {
+}
 
-type LightningServer interface {
+func (m *TimeLockedBalanceRequest) Reset()                    { *m = TimeLockedBalanceRequest{} }
+func (m *TimeLockedBalanceRequest) String() string            { return proto.CompactTextString(m) }
+func (*TimeLockedBalanceRequest) ProtoMessage()               {}
+func (*TimeLockedBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{120} }
+
+type TimeLockedBucket struct {
+	// / The block height at which the funds become spendable. If 0, then the height isn't known yet, as the output locking the funds hasn't confirmed.
+	MaturityHeight uint32 `protobuf:"varint,1,opt,name=maturity_height" json:"maturity_height,omitempty"`
+	// / The number of blocks until the funds become spendable. Negative values indicate how many blocks have passed since the funds became spendable.
+	BlocksTilMaturity int32 `protobuf:"varint,2,opt,name=blocks_til_maturity" json:"blocks_til_maturity,omitempty"`
+	// / The total value in satoshis of the funds which become spendable at this height.
+	Amount int64 `protobuf:"varint,3,opt,name=amount" json:"amount,omitempty"`
+	// / The value in satoshis of the funds locked behind CSV delays.
+	CsvAmount int64 `protobuf:"varint,4,opt,name=csv_amount" json:"csv_amount,omitempty"`
+	// / The value in satoshis of the funds locked behind CLTV timeouts.
+	CltvAmount int64 `protobuf:"varint,5,opt,name=cltv_amount" json:"cltv_amount,omitempty"`
+	// / The number of outputs, or HTLCs, locking the funds.
+	NumOutputs uint32 `protobuf:"varint,6,opt,name=num_outputs" json:"num_outputs,omitempty"`
+}
+
+func (m *TimeLockedBucket) Reset()                    { *m = TimeLockedBucket{} }
+func (m *TimeLockedBucket) String() string            { return proto.CompactTextString(m) }
+func (*TimeLockedBucket) ProtoMessage()               {}
+func (*TimeLockedBucket) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{121} }
+
+func (m *TimeLockedBucket) GetMaturityHeight() uint32 {
+	if m != nil {
+		return m.MaturityHeight
+	}
+	return 0
+}
+
+func (m *TimeLockedBucket) GetBlocksTilMaturity() int32 {
+	if m != nil {
+		return m.BlocksTilMaturity
+	}
+	return 0
+}
+
+func (m *TimeLockedBucket) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *TimeLockedBucket) GetCsvAmount() int64 {
+	if m != nil {
+		return m.CsvAmount
+	}
+	return 0
+}
+
+func (m *TimeLockedBucket) GetCltvAmount() int64 {
+	if m != nil {
+		return m.CltvAmount
+	}
+	return 0
+}
+
+func (m *TimeLockedBucket) GetNumOutputs() uint32 {
+	if m != nil {
+		return m.NumOutputs
+	}
+	return 0
+}
+
+type TimeLockedBalanceResponse struct {
+	// / The total value in satoshis of all time-locked funds.
+	TotalTimeLockedBalance int64 `protobuf:"varint,1,opt,name=total_time_locked_balance" json:"total_time_locked_balance,omitempty"`
+	// / The current block height, relative to which blocks_til_maturity is computed.
+	BlockHeight uint32 `protobuf:"varint,2,opt,name=block_height" json:"block_height,omitempty"`
+	// / The time-locked funds bucketed by their maturity height, in ascending order. The bucket of funds whose maturity height isn't known yet, if any, is last.
+	Buckets []*TimeLockedBucket `protobuf:"bytes,3,rep,name=buckets" json:"buckets,omitempty"`
+}
+
+func (m *TimeLockedBalanceResponse) Reset()                    { *m = TimeLockedBalanceResponse{} }
+func (m *TimeLockedBalanceResponse) String() string            { return proto.CompactTextString(m) }
+func (*TimeLockedBalanceResponse) ProtoMessage()               {}
+func (*TimeLockedBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{122} }
+
+func (m *TimeLockedBalanceResponse) GetTotalTimeLockedBalance() int64 {
+	if m != nil {
+		return m.TotalTimeLockedBalance
+	}
+	return 0
+}
+
+func (m *TimeLockedBalanceResponse) GetBlockHeight() uint32 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *TimeLockedBalanceResponse) GetBuckets() []*TimeLockedBucket {
+	if m != nil {
+		return m.Buckets
+	}
+	return nil
+}
+
+type ExportDebugPackageRequest struct {
+	// / The hex-encoded public key the debug package is encrypted to.
+	RecipientPubkey string `protobuf:"bytes,1,opt,name=recipient_pubkey" json:"recipient_pubkey,omitempty"`
+	// / The maximum number of recent log entries to include. If 0, then all retained log entries are included.
+	MaxLogEntries uint32 `protobuf:"varint,2,opt,name=max_log_entries" json:"max_log_entries,omitempty"`
+}
+
+func (m *ExportDebugPackageRequest) Reset()                    { *m = ExportDebugPackageRequest{} }
+func (m *ExportDebugPackageRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportDebugPackageRequest) ProtoMessage()               {}
+func (*ExportDebugPackageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{123} }
+
+func (m *ExportDebugPackageRequest) GetRecipientPubkey() string {
+	if m != nil {
+		return m.RecipientPubkey
+	}
+	return ""
+}
+
+func (m *ExportDebugPackageRequest) GetMaxLogEntries() uint32 {
+	if m != nil {
+		return m.MaxLogEntries
+	}
+	return 0
+}
+
+type ExportDebugPackageResponse struct {
+	// / The debug package, encrypted to the recipient public key using ECIES.
+	EncryptedPackage []byte `protobuf:"bytes,1,opt,name=encrypted_package,proto3" json:"encrypted_package,omitempty"`
+}
+
+func (m *ExportDebugPackageResponse) Reset()                    { *m = ExportDebugPackageResponse{} }
+func (m *ExportDebugPackageResponse) String() string            { return proto.CompactTextString(m) }
+func (*ExportDebugPackageResponse) ProtoMessage()               {}
+func (*ExportDebugPackageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{124} }
+
+func (m *ExportDebugPackageResponse) GetEncryptedPackage() []byte {
+	if m != nil {
+		return m.EncryptedPackage
+	}
+	return nil
+}
+
+type ChannelEventSubscription struct {
+}
+
+func (m *ChannelEventSubscription) Reset()                    { *m = ChannelEventSubscription{} }
+func (m *ChannelEventSubscription) String() string            { return proto.CompactTextString(m) }
+func (*ChannelEventSubscription) ProtoMessage()               {}
+func (*ChannelEventSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{125} }
+
+type ChannelCloseSummary struct {
+	// / The outpoint (txid:index) of the funding transaction.
+	ChannelPoint string `protobuf:"bytes,1,opt,name=channel_point" json:"channel_point,omitempty"`
+	// / The unique channel ID for the channel.
+	ChanId uint64 `protobuf:"varint,2,opt,name=chan_id" json:"chan_id,omitempty"`
+	// / The hash of the genesis block that this channel resides within.
+	ChainHash string `protobuf:"bytes,3,opt,name=chain_hash" json:"chain_hash,omitempty"`
+	// / The txid of the transaction which ultimately closed this channel.
+	ClosingTxHash string `protobuf:"bytes,4,opt,name=closing_tx_hash" json:"closing_tx_hash,omitempty"`
+	// / Public key of the remote peer that we formerly had a channel with.
+	RemotePubkey string `protobuf:"bytes,5,opt,name=remote_pubkey" json:"remote_pubkey,omitempty"`
+	// / Total capacity of the channel.
+	Capacity int64 `protobuf:"varint,6,opt,name=capacity" json:"capacity,omitempty"`
+	// / Height at which the funding transaction was spent.
+	CloseHeight uint32 `protobuf:"varint,7,opt,name=close_height" json:"close_height,omitempty"`
+	// / Settled balance at the time of channel closure.
+	SettledBalance int64 `protobuf:"varint,8,opt,name=settled_balance" json:"settled_balance,omitempty"`
+	// / The sum of all the time-locked outputs at the time of channel closure.
+	TimeLockedBalance int64 `protobuf:"varint,9,opt,name=time_locked_balance" json:"time_locked_balance,omitempty"`
+	// / Details on how the channel was closed.
+	CloseType ChannelCloseSummary_ClosureType `protobuf:"varint,10,opt,name=close_type,enum=lnrpc.ChannelCloseSummary_ClosureType" json:"close_type,omitempty"`
+}
+
+func (m *ChannelCloseSummary) Reset()                    { *m = ChannelCloseSummary{} }
+func (m *ChannelCloseSummary) String() string            { return proto.CompactTextString(m) }
+func (*ChannelCloseSummary) ProtoMessage()               {}
+func (*ChannelCloseSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{126} }
+
+func (m *ChannelCloseSummary) GetChannelPoint() string {
+	if m != nil {
+		return m.ChannelPoint
+	}
+	return ""
+}
+
+func (m *ChannelCloseSummary) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *ChannelCloseSummary) GetChainHash() string {
+	if m != nil {
+		return m.ChainHash
+	}
+	return ""
+}
+
+func (m *ChannelCloseSummary) GetClosingTxHash() string {
+	if m != nil {
+		return m.ClosingTxHash
+	}
+	return ""
+}
+
+func (m *ChannelCloseSummary) GetRemotePubkey() string {
+	if m != nil {
+		return m.RemotePubkey
+	}
+	return ""
+}
+
+func (m *ChannelCloseSummary) GetCapacity() int64 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *ChannelCloseSummary) GetCloseHeight() uint32 {
+	if m != nil {
+		return m.CloseHeight
+	}
+	return 0
+}
+
+func (m *ChannelCloseSummary) GetSettledBalance() int64 {
+	if m != nil {
+		return m.SettledBalance
+	}
+	return 0
+}
+
+func (m *ChannelCloseSummary) GetTimeLockedBalance() int64 {
+	if m != nil {
+		return m.TimeLockedBalance
+	}
+	return 0
+}
+
+func (m *ChannelCloseSummary) GetCloseType() ChannelCloseSummary_ClosureType {
+	if m != nil {
+		return m.CloseType
+	}
+	return ChannelCloseSummary_COOPERATIVE_CLOSE
+}
+
+type ChannelEventUpdate struct {
+	// / The channel which has been opened, set for OPEN_CHANNEL updates.
+	OpenChannel *ActiveChannel `protobuf:"bytes,1,opt,name=open_channel" json:"open_channel,omitempty"`
+	// / The summary of the channel's closure, set for CLOSED_CHANNEL updates.
+	ClosedChannel *ChannelCloseSummary `protobuf:"bytes,2,opt,name=closed_channel" json:"closed_channel,omitempty"`
+	// / The channel which has become active, set for ACTIVE_CHANNEL updates.
+	ActiveChannel *ChannelPoint `protobuf:"bytes,3,opt,name=active_channel" json:"active_channel,omitempty"`
+	// / The channel which has become inactive, set for INACTIVE_CHANNEL updates.
+	InactiveChannel *ChannelPoint `protobuf:"bytes,4,opt,name=inactive_channel" json:"inactive_channel,omitempty"`
+	// / The type of the update.
+	Type ChannelEventUpdate_UpdateType `protobuf:"varint,5,opt,name=type,enum=lnrpc.ChannelEventUpdate_UpdateType" json:"type,omitempty"`
+}
+
+func (m *ChannelEventUpdate) Reset()                    { *m = ChannelEventUpdate{} }
+func (m *ChannelEventUpdate) String() string            { return proto.CompactTextString(m) }
+func (*ChannelEventUpdate) ProtoMessage()               {}
+func (*ChannelEventUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{127} }
+
+func (m *ChannelEventUpdate) GetOpenChannel() *ActiveChannel {
+	if m != nil {
+		return m.OpenChannel
+	}
+	return nil
+}
+
+func (m *ChannelEventUpdate) GetClosedChannel() *ChannelCloseSummary {
+	if m != nil {
+		return m.ClosedChannel
+	}
+	return nil
+}
+
+func (m *ChannelEventUpdate) GetActiveChannel() *ChannelPoint {
+	if m != nil {
+		return m.ActiveChannel
+	}
+	return nil
+}
+
+func (m *ChannelEventUpdate) GetInactiveChannel() *ChannelPoint {
+	if m != nil {
+		return m.InactiveChannel
+	}
+	return nil
+}
+
+func (m *ChannelEventUpdate) GetType() ChannelEventUpdate_UpdateType {
+	if m != nil {
+		return m.Type
+	}
+	return ChannelEventUpdate_OPEN_CHANNEL
+}
+
+type DebugProfileRequest struct {
+	// / The type of the profile to collect.
+	Type DebugProfileRequest_ProfileType `protobuf:"varint,1,opt,name=type,enum=lnrpc.DebugProfileRequest.ProfileType" json:"type,omitempty"`
+	// / The number of seconds a CPU profile is sampled for. If 0, then the profile is sampled for 10 seconds. It's ignored for other types of profiles.
+	DurationSecs uint32 `protobuf:"varint,2,opt,name=duration_secs" json:"duration_secs,omitempty"`
+}
+
+func (m *DebugProfileRequest) Reset()                    { *m = DebugProfileRequest{} }
+func (m *DebugProfileRequest) String() string            { return proto.CompactTextString(m) }
+func (*DebugProfileRequest) ProtoMessage()               {}
+func (*DebugProfileRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{128} }
+
+func (m *DebugProfileRequest) GetType() DebugProfileRequest_ProfileType {
+	if m != nil {
+		return m.Type
+	}
+	return DebugProfileRequest_GOROUTINE
+}
+
+func (m *DebugProfileRequest) GetDurationSecs() uint32 {
+	if m != nil {
+		return m.DurationSecs
+	}
+	return 0
+}
+
+type DebugProfileResponse struct {
+	// / The profile, in the pprof format, or as plain text for goroutine dumps.
+	Profile []byte `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	// / Whether the profile has been truncated, as it exceeded the maximum size.
+	Truncated bool `protobuf:"varint,2,opt,name=truncated" json:"truncated,omitempty"`
+}
+
+func (m *DebugProfileResponse) Reset()                    { *m = DebugProfileResponse{} }
+func (m *DebugProfileResponse) String() string            { return proto.CompactTextString(m) }
+func (*DebugProfileResponse) ProtoMessage()               {}
+func (*DebugProfileResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{129} }
+
+func (m *DebugProfileResponse) GetProfile() []byte {
+	if m != nil {
+		return m.Profile
+	}
+	return nil
+}
+
+func (m *DebugProfileResponse) GetTruncated() bool {
+	if m != nil {
+		return m.Truncated
+	}
+	return false
+}
+
+type LookupCircuitRequest struct {
+	// / The payment hash of the circuits to look up. If set, then the channel and HTLC ID are ignored.
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+	// / The hex-encoded payment hash of the circuits to look up, which may be set instead of payment_hash.
+	PaymentHashStr string `protobuf:"bytes,2,opt,name=payment_hash_str" json:"payment_hash_str,omitempty"`
+	// / The short channel ID of either the incoming or the outgoing channel of the circuit to look up.
+	ChanId uint64 `protobuf:"varint,3,opt,name=chan_id" json:"chan_id,omitempty"`
+	// / The ID of the HTLC within the channel.
+	HtlcId uint64 `protobuf:"varint,4,opt,name=htlc_id" json:"htlc_id,omitempty"`
+}
+
+func (m *LookupCircuitRequest) Reset()                    { *m = LookupCircuitRequest{} }
+func (m *LookupCircuitRequest) String() string            { return proto.CompactTextString(m) }
+func (*LookupCircuitRequest) ProtoMessage()               {}
+func (*LookupCircuitRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{130} }
+
+func (m *LookupCircuitRequest) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+func (m *LookupCircuitRequest) GetPaymentHashStr() string {
+	if m != nil {
+		return m.PaymentHashStr
+	}
+	return ""
+}
+
+func (m *LookupCircuitRequest) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *LookupCircuitRequest) GetHtlcId() uint64 {
+	if m != nil {
+		return m.HtlcId
+	}
+	return 0
+}
+
+type PaymentCircuit struct {
+	// / The payment hash of the HTLC.
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+	// / The short channel ID of the channel over which the HTLC arrived. It's 0 if the HTLC was sent by the node.
+	IncomingChanId uint64 `protobuf:"varint,2,opt,name=incoming_chan_id" json:"incoming_chan_id,omitempty"`
+	// / The ID of the incoming HTLC within its channel.
+	IncomingHtlcId uint64 `protobuf:"varint,3,opt,name=incoming_htlc_id" json:"incoming_htlc_id,omitempty"`
+	// / The value of the incoming HTLC in millisatoshis. It's 0 if the HTLC was sent by the node.
+	IncomingAmtMsat uint64 `protobuf:"varint,4,opt,name=incoming_amt_msat" json:"incoming_amt_msat,omitempty"`
+	// / The short channel ID of the channel over which the HTLC was offered.
+	OutgoingChanId uint64 `protobuf:"varint,5,opt,name=outgoing_chan_id" json:"outgoing_chan_id,omitempty"`
+	// / The ID of the outgoing HTLC within its channel.
+	OutgoingHtlcId uint64 `protobuf:"varint,6,opt,name=outgoing_htlc_id" json:"outgoing_htlc_id,omitempty"`
+	// / The value of the outgoing HTLC in millisatoshis.
+	OutgoingAmtMsat uint64 `protobuf:"varint,7,opt,name=outgoing_amt_msat" json:"outgoing_amt_msat,omitempty"`
+	// / Whether the circuit holds an obfuscator, used to encrypt the failure of the HTLC before it's returned to the sender.
+	HasObfuscator bool `protobuf:"varint,8,opt,name=has_obfuscator" json:"has_obfuscator,omitempty"`
+}
+
+func (m *PaymentCircuit) Reset()                    { *m = PaymentCircuit{} }
+func (m *PaymentCircuit) String() string            { return proto.CompactTextString(m) }
+func (*PaymentCircuit) ProtoMessage()               {}
+func (*PaymentCircuit) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{131} }
+
+func (m *PaymentCircuit) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+func (m *PaymentCircuit) GetIncomingChanId() uint64 {
+	if m != nil {
+		return m.IncomingChanId
+	}
+	return 0
+}
+
+func (m *PaymentCircuit) GetIncomingHtlcId() uint64 {
+	if m != nil {
+		return m.IncomingHtlcId
+	}
+	return 0
+}
+
+func (m *PaymentCircuit) GetIncomingAmtMsat() uint64 {
+	if m != nil {
+		return m.IncomingAmtMsat
+	}
+	return 0
+}
+
+func (m *PaymentCircuit) GetOutgoingChanId() uint64 {
+	if m != nil {
+		return m.OutgoingChanId
+	}
+	return 0
+}
+
+func (m *PaymentCircuit) GetOutgoingHtlcId() uint64 {
+	if m != nil {
+		return m.OutgoingHtlcId
+	}
+	return 0
+}
+
+func (m *PaymentCircuit) GetOutgoingAmtMsat() uint64 {
+	if m != nil {
+		return m.OutgoingAmtMsat
+	}
+	return 0
+}
+
+func (m *PaymentCircuit) GetHasObfuscator() bool {
+	if m != nil {
+		return m.HasObfuscator
+	}
+	return false
+}
+
+type LookupCircuitResponse struct {
+	// / The circuits matching the request.
+	Circuits []*PaymentCircuit `protobuf:"bytes,1,rep,name=circuits" json:"circuits,omitempty"`
+}
+
+func (m *LookupCircuitResponse) Reset()                    { *m = LookupCircuitResponse{} }
+func (m *LookupCircuitResponse) String() string            { return proto.CompactTextString(m) }
+func (*LookupCircuitResponse) ProtoMessage()               {}
+func (*LookupCircuitResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{132} }
+
+func (m *LookupCircuitResponse) GetCircuits() []*PaymentCircuit {
+	if m != nil {
+		return m.Circuits
+	}
+	return nil
+}
+
+type PeerCompatibilityRequest struct {
+}
+
+func (m *PeerCompatibilityRequest) Reset()                    { *m = PeerCompatibilityRequest{} }
+func (m *PeerCompatibilityRequest) String() string            { return proto.CompactTextString(m) }
+func (*PeerCompatibilityRequest) ProtoMessage()               {}
+func (*PeerCompatibilityRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{133} }
+
+type ChannelCompatibility struct {
+	// / The identity pubkey of the channel peer.
+	RemotePubkey string `protobuf:"bytes,1,opt,name=remote_pubkey" json:"remote_pubkey,omitempty"`
+	// / The outpoint of the channel's funding transaction.
+	ChannelPoint string `protobuf:"bytes,2,opt,name=channel_point" json:"channel_point,omitempty"`
+	// / The short channel ID of the channel.
+	ChanId uint64 `protobuf:"varint,3,opt,name=chan_id" json:"chan_id,omitempty"`
+	// / Whether the features of the peer are known. If not, then the blocked upgrades are unknown as well.
+	FeaturesKnown bool `protobuf:"varint,4,opt,name=features_known" json:"features_known,omitempty"`
+	// / A short identifier of the exact set of features announced by the peer. Peers sharing a fingerprint likely run the same implementation and version.
+	Fingerprint string `protobuf:"bytes,5,opt,name=fingerprint" json:"fingerprint,omitempty"`
+	// / The unix timestamp at which the peer last announced its features.
+	FeaturesUpdated int64 `protobuf:"varint,6,opt,name=features_updated" json:"features_updated,omitempty"`
+	// / The optional protocol upgrades which are blocked as the peer doesn't support them.
+	BlockedUpgrades []string `protobuf:"bytes,7,rep,name=blocked_upgrades" json:"blocked_upgrades,omitempty"`
+}
+
+func (m *ChannelCompatibility) Reset()                    { *m = ChannelCompatibility{} }
+func (m *ChannelCompatibility) String() string            { return proto.CompactTextString(m) }
+func (*ChannelCompatibility) ProtoMessage()               {}
+func (*ChannelCompatibility) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{134} }
+
+func (m *ChannelCompatibility) GetRemotePubkey() string {
+	if m != nil {
+		return m.RemotePubkey
+	}
+	return ""
+}
+
+func (m *ChannelCompatibility) GetChannelPoint() string {
+	if m != nil {
+		return m.ChannelPoint
+	}
+	return ""
+}
+
+func (m *ChannelCompatibility) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *ChannelCompatibility) GetFeaturesKnown() bool {
+	if m != nil {
+		return m.FeaturesKnown
+	}
+	return false
+}
+
+func (m *ChannelCompatibility) GetFingerprint() string {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return ""
+}
+
+func (m *ChannelCompatibility) GetFeaturesUpdated() int64 {
+	if m != nil {
+		return m.FeaturesUpdated
+	}
+	return 0
+}
+
+func (m *ChannelCompatibility) GetBlockedUpgrades() []string {
+	if m != nil {
+		return m.BlockedUpgrades
+	}
+	return nil
+}
+
+type PeerCompatibilityResponse struct {
+	// / The compatibility report of each open channel.
+	Channels []*ChannelCompatibility `protobuf:"bytes,1,rep,name=channels" json:"channels,omitempty"`
+}
+
+func (m *PeerCompatibilityResponse) Reset()                    { *m = PeerCompatibilityResponse{} }
+func (m *PeerCompatibilityResponse) String() string            { return proto.CompactTextString(m) }
+func (*PeerCompatibilityResponse) ProtoMessage()               {}
+func (*PeerCompatibilityResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{135} }
+
+func (m *PeerCompatibilityResponse) GetChannels() []*ChannelCompatibility {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+type ListAliasesRequest struct {
+}
+
+func (m *ListAliasesRequest) Reset()                    { *m = ListAliasesRequest{} }
+func (m *ListAliasesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListAliasesRequest) ProtoMessage()               {}
+func (*ListAliasesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{136} }
+
+type ChannelAliases struct {
+	// / The identity pubkey of the channel peer.
+	RemotePubkey string `protobuf:"bytes,1,opt,name=remote_pubkey" json:"remote_pubkey,omitempty"`
+	// / The outpoint of the channel's funding transaction.
+	ChannelPoint string `protobuf:"bytes,2,opt,name=channel_point" json:"channel_point,omitempty"`
+	// / The confirmed short channel ID of the channel, derived from the location of its funding transaction within the chain.
+	ConfirmedChanId uint64 `protobuf:"varint,3,opt,name=confirmed_chan_id" json:"confirmed_chan_id,omitempty"`
+	// / The short channel ID used for the channel within the route hints of our invoices. It's 0 if the channel is public, as route hints are only included for private channels.
+	InvoiceChanId uint64 `protobuf:"varint,4,opt,name=invoice_chan_id" json:"invoice_chan_id,omitempty"`
+	// / The alias short channel IDs which are accepted for forwards over the channel, in addition to its confirmed short channel ID.
+	ForwardingAliases []uint64 `protobuf:"varint,5,rep,packed,name=forwarding_aliases" json:"forwarding_aliases,omitempty"`
+	// / Whether the link of the channel is active within the switch. Forwards over inactive channels are failed with UnknownNextPeer, regardless of the short channel ID used.
+	Active bool `protobuf:"varint,6,opt,name=active" json:"active,omitempty"`
+}
+
+func (m *ChannelAliases) Reset()                    { *m = ChannelAliases{} }
+func (m *ChannelAliases) String() string            { return proto.CompactTextString(m) }
+func (*ChannelAliases) ProtoMessage()               {}
+func (*ChannelAliases) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{137} }
+
+func (m *ChannelAliases) GetRemotePubkey() string {
+	if m != nil {
+		return m.RemotePubkey
+	}
+	return ""
+}
+
+func (m *ChannelAliases) GetChannelPoint() string {
+	if m != nil {
+		return m.ChannelPoint
+	}
+	return ""
+}
+
+func (m *ChannelAliases) GetConfirmedChanId() uint64 {
+	if m != nil {
+		return m.ConfirmedChanId
+	}
+	return 0
+}
+
+func (m *ChannelAliases) GetInvoiceChanId() uint64 {
+	if m != nil {
+		return m.InvoiceChanId
+	}
+	return 0
+}
+
+func (m *ChannelAliases) GetForwardingAliases() []uint64 {
+	if m != nil {
+		return m.ForwardingAliases
+	}
+	return nil
+}
+
+func (m *ChannelAliases) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+type AliasMapping struct {
+	// / The alias short channel ID.
+	Alias uint64 `protobuf:"varint,1,opt,name=alias" json:"alias,omitempty"`
+	// / The short channel ID of the channel the alias maps to.
+	ChanId uint64 `protobuf:"varint,2,opt,name=chan_id" json:"chan_id,omitempty"`
+}
+
+func (m *AliasMapping) Reset()                    { *m = AliasMapping{} }
+func (m *AliasMapping) String() string            { return proto.CompactTextString(m) }
+func (*AliasMapping) ProtoMessage()               {}
+func (*AliasMapping) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{138} }
+
+func (m *AliasMapping) GetAlias() uint64 {
+	if m != nil {
+		return m.Alias
+	}
+	return 0
+}
+
+func (m *AliasMapping) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+type ListAliasesResponse struct {
+	// / The short channel IDs of each open channel.
+	Channels []*ChannelAliases `protobuf:"bytes,1,rep,name=channels" json:"channels,omitempty"`
+	// / The aliases registered with the switch which map to a channel that isn't open.
+	UnknownAliases []*AliasMapping `protobuf:"bytes,2,rep,name=unknown_aliases" json:"unknown_aliases,omitempty"`
+}
+
+func (m *ListAliasesResponse) Reset()                    { *m = ListAliasesResponse{} }
+func (m *ListAliasesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListAliasesResponse) ProtoMessage()               {}
+func (*ListAliasesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{139} }
+
+func (m *ListAliasesResponse) GetChannels() []*ChannelAliases {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *ListAliasesResponse) GetUnknownAliases() []*AliasMapping {
+	if m != nil {
+		return m.UnknownAliases
+	}
+	return nil
+}
+
+type InspectCommitmentsRequest struct {
+	// / The channel point of the channel to inspect.
+	ChanPoint *ChannelPoint `protobuf:"bytes,1,opt,name=chan_point" json:"chan_point,omitempty"`
+}
+
+func (m *InspectCommitmentsRequest) Reset()                    { *m = InspectCommitmentsRequest{} }
+func (m *InspectCommitmentsRequest) String() string            { return proto.CompactTextString(m) }
+func (*InspectCommitmentsRequest) ProtoMessage()               {}
+func (*InspectCommitmentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{140} }
+
+func (m *InspectCommitmentsRequest) GetChanPoint() *ChannelPoint {
+	if m != nil {
+		return m.ChanPoint
+	}
+	return nil
+}
+
+type CommitmentState struct {
+	// / The height of the commitment.
+	CommitHeight uint64 `protobuf:"varint,1,opt,name=commit_height" json:"commit_height,omitempty"`
+	// / Our balance within the commitment in millisatoshis.
+	LocalBalanceMsat int64 `protobuf:"varint,2,opt,name=local_balance_msat" json:"local_balance_msat,omitempty"`
+	// / The balance of the remote party within the commitment in millisatoshis.
+	RemoteBalanceMsat int64 `protobuf:"varint,3,opt,name=remote_balance_msat" json:"remote_balance_msat,omitempty"`
+	// / The fee paid by the commitment transaction.
+	CommitFee int64 `protobuf:"varint,4,opt,name=commit_fee" json:"commit_fee,omitempty"`
+	// / The fee rate of the commitment transaction in sat/kw.
+	FeePerKw int64 `protobuf:"varint,5,opt,name=fee_per_kw" json:"fee_per_kw,omitempty"`
+	// / The number of HTLCs within the commitment.
+	NumHtlcs uint32 `protobuf:"varint,6,opt,name=num_htlcs" json:"num_htlcs,omitempty"`
+	// / The index of our update log covered by the commitment.
+	LocalLogIndex uint64 `protobuf:"varint,7,opt,name=local_log_index" json:"local_log_index,omitempty"`
+	// / The index of our HTLCs covered by the commitment.
+	LocalHtlcIndex uint64 `protobuf:"varint,8,opt,name=local_htlc_index" json:"local_htlc_index,omitempty"`
+	// / The index of the update log of the remote party covered by the commitment.
+	RemoteLogIndex uint64 `protobuf:"varint,9,opt,name=remote_log_index" json:"remote_log_index,omitempty"`
+	// / The index of the HTLCs of the remote party covered by the commitment.
+	RemoteHtlcIndex uint64 `protobuf:"varint,10,opt,name=remote_htlc_index" json:"remote_htlc_index,omitempty"`
+}
+
+func (m *CommitmentState) Reset()                    { *m = CommitmentState{} }
+func (m *CommitmentState) String() string            { return proto.CompactTextString(m) }
+func (*CommitmentState) ProtoMessage()               {}
+func (*CommitmentState) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{141} }
+
+func (m *CommitmentState) GetCommitHeight() uint64 {
+	if m != nil {
+		return m.CommitHeight
+	}
+	return 0
+}
+
+func (m *CommitmentState) GetLocalBalanceMsat() int64 {
+	if m != nil {
+		return m.LocalBalanceMsat
+	}
+	return 0
+}
+
+func (m *CommitmentState) GetRemoteBalanceMsat() int64 {
+	if m != nil {
+		return m.RemoteBalanceMsat
+	}
+	return 0
+}
+
+func (m *CommitmentState) GetCommitFee() int64 {
+	if m != nil {
+		return m.CommitFee
+	}
+	return 0
+}
+
+func (m *CommitmentState) GetFeePerKw() int64 {
+	if m != nil {
+		return m.FeePerKw
+	}
+	return 0
+}
+
+func (m *CommitmentState) GetNumHtlcs() uint32 {
+	if m != nil {
+		return m.NumHtlcs
+	}
+	return 0
+}
+
+func (m *CommitmentState) GetLocalLogIndex() uint64 {
+	if m != nil {
+		return m.LocalLogIndex
+	}
+	return 0
+}
+
+func (m *CommitmentState) GetLocalHtlcIndex() uint64 {
+	if m != nil {
+		return m.LocalHtlcIndex
+	}
+	return 0
+}
+
+func (m *CommitmentState) GetRemoteLogIndex() uint64 {
+	if m != nil {
+		return m.RemoteLogIndex
+	}
+	return 0
+}
+
+func (m *CommitmentState) GetRemoteHtlcIndex() uint64 {
+	if m != nil {
+		return m.RemoteHtlcIndex
+	}
+	return 0
+}
+
+type UnackedUpdate struct {
+	// / The type of the update message.
+	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	// / A summary of the update message.
+	Summary string `protobuf:"bytes,2,opt,name=summary" json:"summary,omitempty"`
+	// / Whether the update is covered by the pending commitment we've signed for the remote party. If not, then it has been sent, but not yet signed for.
+	Signed bool `protobuf:"varint,3,opt,name=signed" json:"signed,omitempty"`
+}
+
+func (m *UnackedUpdate) Reset()                    { *m = UnackedUpdate{} }
+func (m *UnackedUpdate) String() string            { return proto.CompactTextString(m) }
+func (*UnackedUpdate) ProtoMessage()               {}
+func (*UnackedUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{142} }
+
+func (m *UnackedUpdate) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *UnackedUpdate) GetSummary() string {
+	if m != nil {
+		return m.Summary
+	}
+	return ""
+}
+
+func (m *UnackedUpdate) GetSigned() bool {
+	if m != nil {
+		return m.Signed
+	}
+	return false
+}
+
+type ChanSyncFields struct {
+	// / The height of the next commitment the sender expects to receive.
+	NextLocalCommitHeight uint64 `protobuf:"varint,1,opt,name=next_local_commit_height" json:"next_local_commit_height,omitempty"`
+	// / The height of the current, unrevoked commitment of the recipient, as known to the sender.
+	RemoteCommitTailHeight uint64 `protobuf:"varint,2,opt,name=remote_commit_tail_height" json:"remote_commit_tail_height,omitempty"`
+	// / The hex-encoded last commitment secret the sender has received from the recipient.
+	LastRemoteCommitSecret string `protobuf:"bytes,3,opt,name=last_remote_commit_secret" json:"last_remote_commit_secret,omitempty"`
+	// / The hex-encoded commitment point of the current commitment of the sender, if included.
+	LocalUnrevokedCommitPoint string `protobuf:"bytes,4,opt,name=local_unrevoked_commit_point" json:"local_unrevoked_commit_point,omitempty"`
+	// / The unix timestamp at which the message was received. It's 0 for the message we'd send.
+	Received int64 `protobuf:"varint,5,opt,name=received" json:"received,omitempty"`
+}
+
+func (m *ChanSyncFields) Reset()                    { *m = ChanSyncFields{} }
+func (m *ChanSyncFields) String() string            { return proto.CompactTextString(m) }
+func (*ChanSyncFields) ProtoMessage()               {}
+func (*ChanSyncFields) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{143} }
+
+func (m *ChanSyncFields) GetNextLocalCommitHeight() uint64 {
+	if m != nil {
+		return m.NextLocalCommitHeight
+	}
+	return 0
+}
+
+func (m *ChanSyncFields) GetRemoteCommitTailHeight() uint64 {
+	if m != nil {
+		return m.RemoteCommitTailHeight
+	}
+	return 0
+}
+
+func (m *ChanSyncFields) GetLastRemoteCommitSecret() string {
+	if m != nil {
+		return m.LastRemoteCommitSecret
+	}
+	return ""
+}
+
+func (m *ChanSyncFields) GetLocalUnrevokedCommitPoint() string {
+	if m != nil {
+		return m.LocalUnrevokedCommitPoint
+	}
+	return ""
+}
+
+func (m *ChanSyncFields) GetReceived() int64 {
+	if m != nil {
+		return m.Received
+	}
+	return 0
+}
+
+type InspectCommitmentsResponse struct {
+	// / The short channel ID of the channel.
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id" json:"chan_id,omitempty"`
+	// / Whether the channel has been marked as unable to re-synchronize its commitment chains.
+	Borked bool `protobuf:"varint,2,opt,name=borked" json:"borked,omitempty"`
+	// / Our current commitment.
+	LocalCommitment *CommitmentState `protobuf:"bytes,3,opt,name=local_commitment" json:"local_commitment,omitempty"`
+	// / The current commitment of the remote party, which it hasn't revoked yet.
+	RemoteCommitment *CommitmentState `protobuf:"bytes,4,opt,name=remote_commitment" json:"remote_commitment,omitempty"`
+	// / The commitment we've signed for the remote party, but haven't received a revocation for yet, if any.
+	PendingRemoteCommitment *CommitmentState `protobuf:"bytes,5,opt,name=pending_remote_commitment" json:"pending_remote_commitment,omitempty"`
+	// / The height of our next commitment.
+	NextLocalCommitHeight uint64 `protobuf:"varint,6,opt,name=next_local_commit_height" json:"next_local_commit_height,omitempty"`
+	// / The height of the next commitment of the remote party.
+	NextRemoteCommitHeight uint64 `protobuf:"varint,7,opt,name=next_remote_commit_height" json:"next_remote_commit_height,omitempty"`
+	// / The updates we've sent which the remote party hasn't acknowledged yet by revoking its commitment.
+	UnackedUpdates []*UnackedUpdate `protobuf:"bytes,8,rep,name=unacked_updates" json:"unacked_updates,omitempty"`
+	// / The ChannelReestablish fields we'd send to the remote party upon reconnection.
+	LocalChanSync *ChanSyncFields `protobuf:"bytes,9,opt,name=local_chan_sync" json:"local_chan_sync,omitempty"`
+	// / The ChannelReestablish fields last received from the remote party, if any.
+	RemoteChanSync *ChanSyncFields `protobuf:"bytes,10,opt,name=remote_chan_sync" json:"remote_chan_sync,omitempty"`
+	// / Whether the channel can be re-synchronized given the ChannelReestablish fields last received from the remote party. It's false if none have been received.
+	Recoverable bool `protobuf:"varint,11,opt,name=recoverable" json:"recoverable,omitempty"`
+	// / A description of the outcome of re-synchronizing the channel given the ChannelReestablish fields last received from the remote party.
+	SyncStatus string `protobuf:"bytes,12,opt,name=sync_status" json:"sync_status,omitempty"`
+}
+
+func (m *InspectCommitmentsResponse) Reset()                    { *m = InspectCommitmentsResponse{} }
+func (m *InspectCommitmentsResponse) String() string            { return proto.CompactTextString(m) }
+func (*InspectCommitmentsResponse) ProtoMessage()               {}
+func (*InspectCommitmentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{144} }
+
+func (m *InspectCommitmentsResponse) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *InspectCommitmentsResponse) GetBorked() bool {
+	if m != nil {
+		return m.Borked
+	}
+	return false
+}
+
+func (m *InspectCommitmentsResponse) GetLocalCommitment() *CommitmentState {
+	if m != nil {
+		return m.LocalCommitment
+	}
+	return nil
+}
+
+func (m *InspectCommitmentsResponse) GetRemoteCommitment() *CommitmentState {
+	if m != nil {
+		return m.RemoteCommitment
+	}
+	return nil
+}
+
+func (m *InspectCommitmentsResponse) GetPendingRemoteCommitment() *CommitmentState {
+	if m != nil {
+		return m.PendingRemoteCommitment
+	}
+	return nil
+}
+
+func (m *InspectCommitmentsResponse) GetNextLocalCommitHeight() uint64 {
+	if m != nil {
+		return m.NextLocalCommitHeight
+	}
+	return 0
+}
+
+func (m *InspectCommitmentsResponse) GetNextRemoteCommitHeight() uint64 {
+	if m != nil {
+		return m.NextRemoteCommitHeight
+	}
+	return 0
+}
+
+func (m *InspectCommitmentsResponse) GetUnackedUpdates() []*UnackedUpdate {
+	if m != nil {
+		return m.UnackedUpdates
+	}
+	return nil
+}
+
+func (m *InspectCommitmentsResponse) GetLocalChanSync() *ChanSyncFields {
+	if m != nil {
+		return m.LocalChanSync
+	}
+	return nil
+}
+
+func (m *InspectCommitmentsResponse) GetRemoteChanSync() *ChanSyncFields {
+	if m != nil {
+		return m.RemoteChanSync
+	}
+	return nil
+}
+
+func (m *InspectCommitmentsResponse) GetRecoverable() bool {
+	if m != nil {
+		return m.Recoverable
+	}
+	return false
+}
+
+func (m *InspectCommitmentsResponse) GetSyncStatus() string {
+	if m != nil {
+		return m.SyncStatus
+	}
+	return ""
+}
+
+type SendToRouteRequest struct {
+	// / The hash to use within the payment's HTLC
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	// / The hex-encoded hash to use within the payment's HTLC
+	PaymentHashString string `protobuf:"bytes,2,opt,name=payment_hash_string,json=paymentHashString" json:"payment_hash_string,omitempty"`
+	// / The route to send the payment over. The HTLC extended to the first hop carries total_time_lock as its expiry.
+	Route *Route `protobuf:"bytes,3,opt,name=route" json:"route,omitempty"`
+}
+
+func (m *SendToRouteRequest) Reset()                    { *m = SendToRouteRequest{} }
+func (m *SendToRouteRequest) String() string            { return proto.CompactTextString(m) }
+func (*SendToRouteRequest) ProtoMessage()               {}
+func (*SendToRouteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{145} }
+
+func (m *SendToRouteRequest) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+func (m *SendToRouteRequest) GetPaymentHashString() string {
+	if m != nil {
+		return m.PaymentHashString
+	}
+	return ""
+}
+
+func (m *SendToRouteRequest) GetRoute() *Route {
+	if m != nil {
+		return m.Route
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*CreateWalletRequest)(nil), "lnrpc.CreateWalletRequest")
+	proto.RegisterType((*CreateWalletResponse)(nil), "lnrpc.CreateWalletResponse")
+	proto.RegisterType((*UnlockWalletRequest)(nil), "lnrpc.UnlockWalletRequest")
+	proto.RegisterType((*UnlockWalletResponse)(nil), "lnrpc.UnlockWalletResponse")
+	proto.RegisterType((*Transaction)(nil), "lnrpc.Transaction")
+	proto.RegisterType((*GetTransactionsRequest)(nil), "lnrpc.GetTransactionsRequest")
+	proto.RegisterType((*TransactionDetails)(nil), "lnrpc.TransactionDetails")
+	proto.RegisterType((*SendRequest)(nil), "lnrpc.SendRequest")
+	proto.RegisterType((*SendResponse)(nil), "lnrpc.SendResponse")
+	proto.RegisterType((*ChannelPoint)(nil), "lnrpc.ChannelPoint")
+	proto.RegisterType((*LightningAddress)(nil), "lnrpc.LightningAddress")
+	proto.RegisterType((*SendManyRequest)(nil), "lnrpc.SendManyRequest")
+	proto.RegisterType((*SendManyResponse)(nil), "lnrpc.SendManyResponse")
+	proto.RegisterType((*SendCoinsRequest)(nil), "lnrpc.SendCoinsRequest")
+	proto.RegisterType((*SendCoinsResponse)(nil), "lnrpc.SendCoinsResponse")
+	proto.RegisterType((*NewAddressRequest)(nil), "lnrpc.NewAddressRequest")
+	proto.RegisterType((*NewWitnessAddressRequest)(nil), "lnrpc.NewWitnessAddressRequest")
+	proto.RegisterType((*NewAddressResponse)(nil), "lnrpc.NewAddressResponse")
+	proto.RegisterType((*SignMessageRequest)(nil), "lnrpc.SignMessageRequest")
+	proto.RegisterType((*SignMessageResponse)(nil), "lnrpc.SignMessageResponse")
+	proto.RegisterType((*VerifyMessageRequest)(nil), "lnrpc.VerifyMessageRequest")
+	proto.RegisterType((*VerifyMessageResponse)(nil), "lnrpc.VerifyMessageResponse")
+	proto.RegisterType((*ConnectPeerRequest)(nil), "lnrpc.ConnectPeerRequest")
+	proto.RegisterType((*ConnectPeerResponse)(nil), "lnrpc.ConnectPeerResponse")
+	proto.RegisterType((*DisconnectPeerRequest)(nil), "lnrpc.DisconnectPeerRequest")
+	proto.RegisterType((*DisconnectPeerResponse)(nil), "lnrpc.DisconnectPeerResponse")
+	proto.RegisterType((*HTLC)(nil), "lnrpc.HTLC")
+	proto.RegisterType((*ActiveChannel)(nil), "lnrpc.ActiveChannel")
+	proto.RegisterType((*ListChannelsRequest)(nil), "lnrpc.ListChannelsRequest")
+	proto.RegisterType((*ListChannelsResponse)(nil), "lnrpc.ListChannelsResponse")
+	proto.RegisterType((*Peer)(nil), "lnrpc.Peer")
+	proto.RegisterType((*ListPeersRequest)(nil), "lnrpc.ListPeersRequest")
+	proto.RegisterType((*ListPeersResponse)(nil), "lnrpc.ListPeersResponse")
+	proto.RegisterType((*GetInfoRequest)(nil), "lnrpc.GetInfoRequest")
+	proto.RegisterType((*GetInfoResponse)(nil), "lnrpc.GetInfoResponse")
+	proto.RegisterType((*ConfirmationUpdate)(nil), "lnrpc.ConfirmationUpdate")
+	proto.RegisterType((*ChannelOpenUpdate)(nil), "lnrpc.ChannelOpenUpdate")
+	proto.RegisterType((*ChannelCloseUpdate)(nil), "lnrpc.ChannelCloseUpdate")
+	proto.RegisterType((*CloseChannelRequest)(nil), "lnrpc.CloseChannelRequest")
+	proto.RegisterType((*CloseStatusUpdate)(nil), "lnrpc.CloseStatusUpdate")
+	proto.RegisterType((*PendingUpdate)(nil), "lnrpc.PendingUpdate")
+	proto.RegisterType((*ForceClosePreview)(nil), "lnrpc.ForceClosePreview")
+	proto.RegisterType((*OpenChannelRequest)(nil), "lnrpc.OpenChannelRequest")
+	proto.RegisterType((*OpenStatusUpdate)(nil), "lnrpc.OpenStatusUpdate")
+	proto.RegisterType((*PendingHTLC)(nil), "lnrpc.PendingHTLC")
+	proto.RegisterType((*PendingChannelsRequest)(nil), "lnrpc.PendingChannelsRequest")
+	proto.RegisterType((*PendingChannelsResponse)(nil), "lnrpc.PendingChannelsResponse")
+	proto.RegisterType((*PendingChannelsResponse_PendingChannel)(nil), "lnrpc.PendingChannelsResponse.PendingChannel")
+	proto.RegisterType((*PendingChannelsResponse_PendingOpenChannel)(nil), "lnrpc.PendingChannelsResponse.PendingOpenChannel")
+	proto.RegisterType((*PendingChannelsResponse_ClosedChannel)(nil), "lnrpc.PendingChannelsResponse.ClosedChannel")
+	proto.RegisterType((*PendingChannelsResponse_ForceClosedChannel)(nil), "lnrpc.PendingChannelsResponse.ForceClosedChannel")
+	proto.RegisterType((*WalletBalanceRequest)(nil), "lnrpc.WalletBalanceRequest")
+	proto.RegisterType((*WalletBalanceResponse)(nil), "lnrpc.WalletBalanceResponse")
+	proto.RegisterType((*ChannelBalanceRequest)(nil), "lnrpc.ChannelBalanceRequest")
+	proto.RegisterType((*ChannelBalanceResponse)(nil), "lnrpc.ChannelBalanceResponse")
+	proto.RegisterType((*QueryRoutesRequest)(nil), "lnrpc.QueryRoutesRequest")
+	proto.RegisterType((*QueryRoutesResponse)(nil), "lnrpc.QueryRoutesResponse")
+	proto.RegisterType((*Hop)(nil), "lnrpc.Hop")
+	proto.RegisterType((*Route)(nil), "lnrpc.Route")
+	proto.RegisterType((*NodeInfoRequest)(nil), "lnrpc.NodeInfoRequest")
+	proto.RegisterType((*NodeInfo)(nil), "lnrpc.NodeInfo")
+	proto.RegisterType((*LightningNode)(nil), "lnrpc.LightningNode")
+	proto.RegisterType((*NodeAddress)(nil), "lnrpc.NodeAddress")
+	proto.RegisterType((*RoutingPolicy)(nil), "lnrpc.RoutingPolicy")
+	proto.RegisterType((*ChannelEdge)(nil), "lnrpc.ChannelEdge")
+	proto.RegisterType((*ChannelGraphRequest)(nil), "lnrpc.ChannelGraphRequest")
+	proto.RegisterType((*ChannelGraph)(nil), "lnrpc.ChannelGraph")
+	proto.RegisterType((*ChanInfoRequest)(nil), "lnrpc.ChanInfoRequest")
+	proto.RegisterType((*NetworkInfoRequest)(nil), "lnrpc.NetworkInfoRequest")
+	proto.RegisterType((*NetworkInfo)(nil), "lnrpc.NetworkInfo")
+	proto.RegisterType((*StopRequest)(nil), "lnrpc.StopRequest")
+	proto.RegisterType((*StopResponse)(nil), "lnrpc.StopResponse")
+	proto.RegisterType((*GraphTopologySubscription)(nil), "lnrpc.GraphTopologySubscription")
+	proto.RegisterType((*GraphTopologyUpdate)(nil), "lnrpc.GraphTopologyUpdate")
+	proto.RegisterType((*NodeUpdate)(nil), "lnrpc.NodeUpdate")
+	proto.RegisterType((*ChannelEdgeUpdate)(nil), "lnrpc.ChannelEdgeUpdate")
+	proto.RegisterType((*ClosedChannelUpdate)(nil), "lnrpc.ClosedChannelUpdate")
+	proto.RegisterType((*Invoice)(nil), "lnrpc.Invoice")
+	proto.RegisterType((*InvoiceHTLC)(nil), "lnrpc.InvoiceHTLC")
+	proto.RegisterType((*AddInvoiceResponse)(nil), "lnrpc.AddInvoiceResponse")
+	proto.RegisterType((*PaymentHash)(nil), "lnrpc.PaymentHash")
+	proto.RegisterType((*ListInvoiceRequest)(nil), "lnrpc.ListInvoiceRequest")
+	proto.RegisterType((*ListInvoiceResponse)(nil), "lnrpc.ListInvoiceResponse")
+	proto.RegisterType((*InvoiceSubscription)(nil), "lnrpc.InvoiceSubscription")
+	proto.RegisterType((*Payment)(nil), "lnrpc.Payment")
+	proto.RegisterType((*ListPaymentsRequest)(nil), "lnrpc.ListPaymentsRequest")
+	proto.RegisterType((*ListPaymentsResponse)(nil), "lnrpc.ListPaymentsResponse")
+	proto.RegisterType((*DeleteAllPaymentsRequest)(nil), "lnrpc.DeleteAllPaymentsRequest")
+	proto.RegisterType((*DeleteAllPaymentsResponse)(nil), "lnrpc.DeleteAllPaymentsResponse")
+	proto.RegisterType((*DebugLevelRequest)(nil), "lnrpc.DebugLevelRequest")
+	proto.RegisterType((*DebugLevelResponse)(nil), "lnrpc.DebugLevelResponse")
+	proto.RegisterType((*PayReqString)(nil), "lnrpc.PayReqString")
+	proto.RegisterType((*PayReq)(nil), "lnrpc.PayReq")
+	proto.RegisterType((*FeeReportRequest)(nil), "lnrpc.FeeReportRequest")
+	proto.RegisterType((*ChannelFeeReport)(nil), "lnrpc.ChannelFeeReport")
+	proto.RegisterType((*FeeReportResponse)(nil), "lnrpc.FeeReportResponse")
+	proto.RegisterType((*PolicyUpdateRequest)(nil), "lnrpc.PolicyUpdateRequest")
+	proto.RegisterType((*PolicyUpdateResponse)(nil), "lnrpc.PolicyUpdateResponse")
+	proto.RegisterType((*ExportGraphRequest)(nil), "lnrpc.ExportGraphRequest")
+	proto.RegisterType((*ExportGraphResponse)(nil), "lnrpc.ExportGraphResponse")
+	proto.RegisterType((*ImportGraphRequest)(nil), "lnrpc.ImportGraphRequest")
+	proto.RegisterType((*ImportGraphResponse)(nil), "lnrpc.ImportGraphResponse")
+	proto.RegisterType((*ForwardingFilterRequest)(nil), "lnrpc.ForwardingFilterRequest")
+	proto.RegisterType((*UpdateForwardingFilterRequest)(nil), "lnrpc.UpdateForwardingFilterRequest")
+	proto.RegisterType((*ForwardingFilterResponse)(nil), "lnrpc.ForwardingFilterResponse")
+	proto.RegisterType((*PeerMessageSubscription)(nil), "lnrpc.PeerMessageSubscription")
+	proto.RegisterType((*PeerMessage)(nil), "lnrpc.PeerMessage")
+	proto.RegisterType((*LiquidityHistoryRequest)(nil), "lnrpc.LiquidityHistoryRequest")
+	proto.RegisterType((*LiquiditySnapshot)(nil), "lnrpc.LiquiditySnapshot")
+	proto.RegisterType((*LiquidityHistoryResponse)(nil), "lnrpc.LiquidityHistoryResponse")
+	proto.RegisterType((*InjectSwitchFaultRequest)(nil), "lnrpc.InjectSwitchFaultRequest")
+	proto.RegisterType((*InjectSwitchFaultResponse)(nil), "lnrpc.InjectSwitchFaultResponse")
+	proto.RegisterType((*UpdateChanStatusRequest)(nil), "lnrpc.UpdateChanStatusRequest")
+	proto.RegisterType((*UpdateChanStatusResponse)(nil), "lnrpc.UpdateChanStatusResponse")
+	proto.RegisterType((*SettleHoldInvoiceRequest)(nil), "lnrpc.SettleHoldInvoiceRequest")
+	proto.RegisterType((*SettleHoldInvoiceResponse)(nil), "lnrpc.SettleHoldInvoiceResponse")
+	proto.RegisterType((*CancelHoldInvoiceResponse)(nil), "lnrpc.CancelHoldInvoiceResponse")
+	proto.RegisterType((*ForwardingHistoryRequest)(nil), "lnrpc.ForwardingHistoryRequest")
+	proto.RegisterType((*ForwardingEvent)(nil), "lnrpc.ForwardingEvent")
+	proto.RegisterType((*ForwardingHistoryResponse)(nil), "lnrpc.ForwardingHistoryResponse")
+	proto.RegisterType((*BatchAddInvoiceRequest)(nil), "lnrpc.BatchAddInvoiceRequest")
+	proto.RegisterType((*BatchAddInvoiceResponse)(nil), "lnrpc.BatchAddInvoiceResponse")
+	proto.RegisterType((*ChannelPointList)(nil), "lnrpc.ChannelPointList")
+	proto.RegisterType((*FailedUpdate)(nil), "lnrpc.FailedUpdate")
+	proto.RegisterType((*TimeLockedBalanceRequest)(nil), "lnrpc.TimeLockedBalanceRequest")
+	proto.RegisterType((*TimeLockedBucket)(nil), "lnrpc.TimeLockedBucket")
+	proto.RegisterType((*TimeLockedBalanceResponse)(nil), "lnrpc.TimeLockedBalanceResponse")
+	proto.RegisterType((*ExportDebugPackageRequest)(nil), "lnrpc.ExportDebugPackageRequest")
+	proto.RegisterType((*ExportDebugPackageResponse)(nil), "lnrpc.ExportDebugPackageResponse")
+	proto.RegisterType((*ChannelEventSubscription)(nil), "lnrpc.ChannelEventSubscription")
+	proto.RegisterType((*ChannelCloseSummary)(nil), "lnrpc.ChannelCloseSummary")
+	proto.RegisterType((*ChannelEventUpdate)(nil), "lnrpc.ChannelEventUpdate")
+	proto.RegisterType((*DebugProfileRequest)(nil), "lnrpc.DebugProfileRequest")
+	proto.RegisterType((*DebugProfileResponse)(nil), "lnrpc.DebugProfileResponse")
+	proto.RegisterType((*LookupCircuitRequest)(nil), "lnrpc.LookupCircuitRequest")
+	proto.RegisterType((*PaymentCircuit)(nil), "lnrpc.PaymentCircuit")
+	proto.RegisterType((*LookupCircuitResponse)(nil), "lnrpc.LookupCircuitResponse")
+	proto.RegisterType((*PeerCompatibilityRequest)(nil), "lnrpc.PeerCompatibilityRequest")
+	proto.RegisterType((*ChannelCompatibility)(nil), "lnrpc.ChannelCompatibility")
+	proto.RegisterType((*PeerCompatibilityResponse)(nil), "lnrpc.PeerCompatibilityResponse")
+	proto.RegisterType((*ListAliasesRequest)(nil), "lnrpc.ListAliasesRequest")
+	proto.RegisterType((*ChannelAliases)(nil), "lnrpc.ChannelAliases")
+	proto.RegisterType((*AliasMapping)(nil), "lnrpc.AliasMapping")
+	proto.RegisterType((*ListAliasesResponse)(nil), "lnrpc.ListAliasesResponse")
+	proto.RegisterType((*InspectCommitmentsRequest)(nil), "lnrpc.InspectCommitmentsRequest")
+	proto.RegisterType((*CommitmentState)(nil), "lnrpc.CommitmentState")
+	proto.RegisterType((*UnackedUpdate)(nil), "lnrpc.UnackedUpdate")
+	proto.RegisterType((*ChanSyncFields)(nil), "lnrpc.ChanSyncFields")
+	proto.RegisterType((*InspectCommitmentsResponse)(nil), "lnrpc.InspectCommitmentsResponse")
+	proto.RegisterType((*SendToRouteRequest)(nil), "lnrpc.SendToRouteRequest")
+	proto.RegisterEnum("lnrpc.NewAddressRequest_AddressType", NewAddressRequest_AddressType_name, NewAddressRequest_AddressType_value)
+	proto.RegisterEnum("lnrpc.ChannelCloseSummary_ClosureType", ChannelCloseSummary_ClosureType_name, ChannelCloseSummary_ClosureType_value)
+	proto.RegisterEnum("lnrpc.ChannelEventUpdate_UpdateType", ChannelEventUpdate_UpdateType_name, ChannelEventUpdate_UpdateType_value)
+	proto.RegisterEnum("lnrpc.DebugProfileRequest_ProfileType", DebugProfileRequest_ProfileType_name, DebugProfileRequest_ProfileType_value)
+	proto.RegisterEnum("lnrpc.Invoice_InvoiceState", Invoice_InvoiceState_name, Invoice_InvoiceState_value)
+	proto.RegisterEnum("lnrpc.InvoiceHTLC_HTLCState", InvoiceHTLC_HTLCState_name, InvoiceHTLC_HTLCState_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for WalletUnlocker service
+
+type WalletUnlockerClient interface {
+	// * lncli: `create`
+	// CreateWallet is used at lnd startup to set the encryption password for
+	// the wallet database.
+	CreateWallet(ctx context.Context, in *CreateWalletRequest, opts ...grpc.CallOption) (*CreateWalletResponse, error)
+	// * lncli: `unlock`
+	// UnlockWallet is used at startup of lnd to provide a password to unlock
+	// the wallet database.
+	UnlockWallet(ctx context.Context, in *UnlockWalletRequest, opts ...grpc.CallOption) (*UnlockWalletResponse, error)
+}
+
+type walletUnlockerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWalletUnlockerClient(cc *grpc.ClientConn) WalletUnlockerClient {
+	return &walletUnlockerClient{cc}
+}
+
+func (c *walletUnlockerClient) CreateWallet(ctx context.Context, in *CreateWalletRequest, opts ...grpc.CallOption) (*CreateWalletResponse, error) {
+	out := new(CreateWalletResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.WalletUnlocker/CreateWallet", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletUnlockerClient) UnlockWallet(ctx context.Context, in *UnlockWalletRequest, opts ...grpc.CallOption) (*UnlockWalletResponse, error) {
+	out := new(UnlockWalletResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.WalletUnlocker/UnlockWallet", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for WalletUnlocker service
+
+type WalletUnlockerServer interface {
+	// * lncli: `create`
+	// CreateWallet is used at lnd startup to set the encryption password for
+	// the wallet database.
+	CreateWallet(context.Context, *CreateWalletRequest) (*CreateWalletResponse, error)
+	// * lncli: `unlock`
+	// UnlockWallet is used at startup of lnd to provide a password to unlock
+	// the wallet database.
+	UnlockWallet(context.Context, *UnlockWalletRequest) (*UnlockWalletResponse, error)
+}
+
+func RegisterWalletUnlockerServer(s *grpc.Server, srv WalletUnlockerServer) {
+	s.RegisterService(&_WalletUnlocker_serviceDesc, srv)
+}
+
+func _WalletUnlocker_CreateWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletUnlockerServer).CreateWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.WalletUnlocker/CreateWallet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletUnlockerServer).CreateWallet(ctx, req.(*CreateWalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletUnlocker_UnlockWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockWalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletUnlockerServer).UnlockWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.WalletUnlocker/UnlockWallet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletUnlockerServer).UnlockWallet(ctx, req.(*UnlockWalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _WalletUnlocker_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "lnrpc.WalletUnlocker",
+	HandlerType: (*WalletUnlockerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateWallet",
+			Handler:    _WalletUnlocker_CreateWallet_Handler,
+		},
+		{
+			MethodName: "UnlockWallet",
+			Handler:    _WalletUnlocker_UnlockWallet_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "rpc.proto",
+}
+
+// Client API for Lightning service
+
+type LightningClient interface {
+	// * lncli: `walletbalance`
+	// WalletBalance returns total unspent outputs(confirmed and unconfirmed), all confirmed unspent outputs and all unconfirmed unspent outputs under control
+	// by the wallet. This method can be modified by having the request specify
+	// only witness outputs should be factored into the final output sum.
+	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
+	// * lncli: `channelbalance`
+	// ChannelBalance returns the total funds available across all open channels
+	// in satoshis.
+	ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error)
+	// * lncli: `listchaintxns`
+	// GetTransactions returns a list describing all the known transactions
+	// relevant to the wallet.
+	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error)
+	// * lncli: `sendcoins`
+	// SendCoins executes a request to send coins to a particular address. Unlike
+	// SendMany, this RPC call only allows creating a single output at a time. If
+	// neither target_conf, or sat_per_byte are set, then the internal wallet will
+	// consult its fee model to determine a fee for the default confirmation
+	// target.
+	SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error)
+	// *
+	// SubscribeTransactions creates a uni-directional stream from the server to
+	// the client in which any newly discovered transactions relevant to the
+	// wallet are sent over.
+	SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error)
+	// * lncli: `sendmany`
+	// SendMany handles a request for a transaction that creates multiple specified
+	// outputs in parallel. If neither target_conf, or sat_per_byte are set, then
+	// the internal wallet will consult its fee model to determine a fee for the
+	// default confirmation target.
+	SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error)
+	// * lncli: `newaddress`
+	// NewAddress creates a new address under control of the local wallet.
+	NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	// *
+	// NewWitnessAddress creates a new witness address under control of the local wallet.
+	NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	// * lncli: `signmessage`
+	// SignMessage signs a message with this node's private key. The returned
+	// signature string is `zbase32` encoded and pubkey recoverable, meaning that
+	// only the message digest and signature are needed for verification.
+	SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error)
+	// * lncli: `verifymessage`
+	// VerifyMessage verifies a signature over a msg. The signature must be
+	// zbase32 encoded and signed by an active node in the resident node's
+	// channel database. In addition to returning the validity of the signature,
+	// VerifyMessage also returns the recovered pubkey from the signature.
+	VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error)
+	// * lncli: `connect`
+	// ConnectPeer attempts to establish a connection to a remote peer. This is at
+	// the networking level, and is used for communication between nodes. This is
+	// distinct from establishing a channel with a peer.
+	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
+	// * lncli: `disconnect`
+	// DisconnectPeer attempts to disconnect one peer from another identified by a
+	// given pubKey. In the case that we currently have a pending or active channel
+	// with the target peer, then this action will be not be allowed.
+	DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error)
+	// * lncli: `listpeers`
+	// ListPeers returns a verbose listing of all currently active peers.
+	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
+	// * lncli: `getinfo`
+	// GetInfo returns general information concerning the lightning node including
+	// it's identity pubkey, alias, the chains it is connected to, and information
+	// concerning the number of open+pending channels.
+	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+	// * lncli: `pendingchannels`
+	// PendingChannels returns a list of all the channels that are currently
+	// considered "pending". A channel is pending if it has finished the funding
+	// workflow and is waiting for confirmations for the funding txn, or is in the
+	// process of closure, either initiated cooperatively or non-cooperatively.
+	PendingChannels(ctx context.Context, in *PendingChannelsRequest, opts ...grpc.CallOption) (*PendingChannelsResponse, error)
+	// * lncli: `listchannels`
+	// ListChannels returns a description of all the open channels that this node
+	// is a participant in.
+	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
+	// *
+	// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
+	// call is meant to be consumed by clients to the REST proxy. As with all
+	// other sync calls, all byte slices are intended to be populated as hex
+	// encoded strings.
+	OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error)
+	// * lncli: `openchannel`
+	// OpenChannel attempts to open a singly funded channel specified in the
+	// request to a remote peer. Users are able to specify a target number of
+	// blocks that the funding transaction should be confirmed in, or a manual fee
+	// rate to us for the funding transaction. If neither are specified, then a
+	// lax block confirmation target is used.
+	OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error)
+	// * lncli: `closechannel`
+	// CloseChannel attempts to close an active channel identified by its channel
+	// outpoint (ChannelPoint). The actions of this method can additionally be
+	// augmented to attempt a force close after a timeout period in the case of an
+	// inactive peer. If a non-force close (cooperative closure) is requested,
+	// then the user can specify either a target number of blocks until the
+	// closure transaction is confirmed, or a manual fee rate. If neither are
+	// specified, then a default lax, block confirmation target is used.
+	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
+	// * lncli: `sendpayment`
+	// SendPayment dispatches a bi-directional streaming RPC for sending payments
+	// through the Lightning Network. A single RPC invocation creates a persistent
+	// bi-directional stream allowing clients to rapidly send payments through the
+	// Lightning Network with a single persistent connection.
+	SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error)
+	// *
+	// SendPaymentSync is the synchronous non-streaming version of SendPayment.
+	// This RPC is intended to be consumed by clients of the REST proxy.
+	// Additionally, this RPC expects the destination's public key and the payment
+	// hash (if any) to be encoded as hex strings.
+	SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	// * lncli: `addinvoice`
+	// AddInvoice attempts to add a new invoice to the invoice database. Any
+	// duplicated invoices are rejected, therefore all invoices *must* have a
+	// unique payment preimage.
+	AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
+	// * lncli: `listinvoices`
+	// ListInvoices returns a list of all the invoices currently stored within the
+	// database. Any active debug invoices are ignored.
+	ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error)
+	// * lncli: `lookupinvoice`
+	// LookupInvoice attemps to look up an invoice according to its payment hash.
+	// The passed payment hash *must* be exactly 32 bytes, if not, an error is
+	// returned.
+	LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error)
+	// *
+	// SubscribeInvoices returns a uni-directional stream (sever -> client) for
+	// notifying the client of newly added/settled invoices.
+	SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error)
+	// * lncli: `subscribeinvoice`
+	// SubscribeSingleInvoice returns a uni-directional stream (server -> client)
+	// for notifying the client of the updates of a single invoice. The current
+	// state of the invoice is sent first, followed by the invoice each time an
+	// HTLC paying to it is accepted, or it's settled or canceled.
+	SubscribeSingleInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (Lightning_SubscribeSingleInvoiceClient, error)
+	// * lncli: `decodepayreq`
+	// DecodePayReq takes an encoded payment request string and attempts to decode
+	// it, returning a full description of the conditions encoded within the
+	// payment request.
+	DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error)
+	// * lncli: `listpayments`
+	// ListPayments returns a list of all outgoing payments.
+	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
+	// *
+	// DeleteAllPayments deletes all outgoing payments from DB.
+	DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error)
+	// * lncli: `describegraph`
+	// DescribeGraph returns a description of the latest graph state from the
+	// point of view of the node. The graph information is partitioned into two
+	// components: all the nodes/vertexes, and all the edges that connect the
+	// vertexes themselves.  As this is a directed graph, the edges also contain
+	// the node directional specific routing policy which includes: the time lock
+	// delta, fee information, etc.
+	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
+	// * lncli: `getchaninfo`
+	// GetChanInfo returns the latest authenticated network announcement for the
+	// given channel identified by its channel ID: an 8-byte integer which
+	// uniquely identifies the location of transaction's funding output within the
+	// blockchain.
+	GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error)
+	// * lncli: `getnodeinfo`
+	// GetNodeInfo returns the latest advertised, aggregated, and authenticated
+	// channel information for the specified node identified by its public key.
+	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
+	// * lncli: `queryroutes`
+	// QueryRoutes attempts to query the daemon's Channel Router for a possible
+	// route to a target destination capable of carrying a specific amount of
+	// satoshis. The retuned route contains the full details required to craft and
+	// send an HTLC, also including the necessary information that should be
+	// present within the Sphinx packet encapsualted within the HTLC.
+	QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error)
+	// * lncli: `getnetworkinfo`
+	// GetNetworkInfo returns some basic stats about the known channel graph from
+	// the point of view of the node.
+	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
+	// * lncli: `stop`
+	// StopDaemon will send a shutdown request to the interrupt handler, triggering
+	// a graceful shutdown of the daemon.
+	StopDaemon(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
+	// *
+	// SubscribeChannelGraph launches a streaming RPC that allows the caller to
+	// receive notifications upon any changes to the channel graph topology from
+	// the point of view of the responding node. Events notified include: new
+	// nodes coming online, nodes updating their authenticated attributes, new
+	// channels being advertised, updates in the routing policy for a directional
+	// channel edge, and when channels are closed on-chain.
+	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
+	// * lncli: `debuglevel`
+	// DebugLevel allows a caller to programmatically set the logging verbosity of
+	// lnd. The logging can be targeted according to a coarse daemon-wide logging
+	// level, or in a granular fashion to specify the logging for a target
+	// sub-system.
+	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
+	// * lncli: `feereport`
+	// FeeReport allows the caller to obtain a report detailing the current fee
+	// schedule enforced by the node globally for each channel.
+	FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error)
+	// * lncli: `updatechanpolicy`
+	// UpdateChannelPolicy allows the caller to update the fee schedule and
+	// channel policies for all channels globally, a particular channel, a set
+	// of channels, or all channels with a peer. The update is validated
+	// before being applied, and the targeted channels it couldn't be applied
+	// to are returned along with the reason why.
+	UpdateChannelPolicy(ctx context.Context, in *PolicyUpdateRequest, opts ...grpc.CallOption) (*PolicyUpdateResponse, error)
+	// * lncli: `exportgraph`
+	// ExportGraph returns a compact snapshot of the node's validated channel
+	// graph. The snapshot contains the original signed channel announcements,
+	// channel updates and node announcements, and can be loaded into a fresh
+	// node via the ImportGraph call.
+	ExportGraph(ctx context.Context, in *ExportGraphRequest, opts ...grpc.CallOption) (*ExportGraphResponse, error)
+	// * lncli: `importgraph`
+	// ImportGraph loads a channel graph snapshot created by ExportGraph into the
+	// node's channel graph, allowing a new node to be useful for routing without
+	// waiting to receive the entire graph via gossip. Each announcement within
+	// the snapshot is validated exactly as if it had been received via gossip:
+	// its signatures are verified, and the funding outputs of all imported
+	// channels are verified against the chain.
+	ImportGraph(ctx context.Context, in *ImportGraphRequest, opts ...grpc.CallOption) (*ImportGraphResponse, error)
+	// * lncli: `fwdfilter`
+	// ForwardingFilter returns the current forwarding allow and deny lists, which
+	// determine the peers the node will forward HTLCs from or to.
+	ForwardingFilter(ctx context.Context, in *ForwardingFilterRequest, opts ...grpc.CallOption) (*ForwardingFilterResponse, error)
+	// * lncli: `updatefwdfilter`
+	// UpdateForwardingFilter adds peers to, or removes peers from, the forwarding
+	// allow and deny lists. The changes take effect immediately, but aren't
+	// persisted across restarts. Forwards arriving from, or destined to, a peer
+	// that isn't permitted by the lists are failed back to the sender.
+	UpdateForwardingFilter(ctx context.Context, in *UpdateForwardingFilterRequest, opts ...grpc.CallOption) (*ForwardingFilterResponse, error)
+	// * lncli: `tappeer`
+	// SubscribePeerMessages returns a uni-directional stream (server -> client)
+	// of the decoded wire messages sent to, and received from, the target peer.
+	// Payment preimages are redacted from the streamed messages. This is a
+	// debugging aid, and is only available if lnd was started with the
+	// --debugmessagetap flag.
+	SubscribePeerMessages(ctx context.Context, in *PeerMessageSubscription, opts ...grpc.CallOption) (Lightning_SubscribePeerMessagesClient, error)
+	// * lncli: `liquidityhistory`
+	// LiquidityHistory returns the recorded snapshots of the balances and
+	// pending HTLCs of the target channel within the given time range, in
+	// chronological order. Snapshots are only recorded if the liquidity history
+	// is enabled.
+	LiquidityHistory(ctx context.Context, in *LiquidityHistoryRequest, opts ...grpc.CallOption) (*LiquidityHistoryResponse, error)
+	// * lncli: `injectswitchfault`
+	// InjectSwitchFault injects a fault into the forwarding path of the htlc
+	// switch, which drops, delays or duplicates the next packets forwarded. Faults
+	// are applied in the order they were injected. This is intended for
+	// integration tests, and is only available if lnd was started with the
+	// --debugswitchfaults flag.
+	InjectSwitchFault(ctx context.Context, in *InjectSwitchFaultRequest, opts ...grpc.CallOption) (*InjectSwitchFaultResponse, error)
+	// * lncli: `updatechanstatus`
+	// UpdateChanStatus sets the disabled bit of the ChannelUpdate announced for
+	// the target channel, and pins it, so that it's kept regardless of the
+	// automatic management of the status of our channels. This allows an operator
+	// to drain a channel of routing traffic ahead of maintenance. The pin can be
+	// released with the "auto" action, returning the channel to the status of all
+	// other channels. Pins don't survive a restart.
+	UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error)
+	// * lncli: `settleholdinvoice`
+	// SettleHoldInvoice releases the preimage of a hold invoice, settling the
+	// HTLCs paying to it which are held by their links. The invoice itself is
+	// marked as settled once its HTLCs have been settled.
+	SettleHoldInvoice(ctx context.Context, in *SettleHoldInvoiceRequest, opts ...grpc.CallOption) (*SettleHoldInvoiceResponse, error)
+	// * lncli: `cancelholdinvoice`
+	// CancelHoldInvoice cancels a hold invoice whose preimage hasn't yet been
+	// released, failing the HTLCs paying to it back to their senders. Any HTLCs
+	// paying to the invoice in the future are failed as well.
+	CancelHoldInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*CancelHoldInvoiceResponse, error)
+	// * lncli: `fwdinghistory`
+	// ForwardingHistory returns the HTLCs successfully forwarded by the node
+	// within the given time range, in chronological order, along with the fee
+	// earned by each. The results are paginated by an index offset, allowing
+	// operators to audit their routing revenue over large time ranges.
+	ForwardingHistory(ctx context.Context, in *ForwardingHistoryRequest, opts ...grpc.CallOption) (*ForwardingHistoryResponse, error)
+	// * lncli: `batchaddinvoice`
+	// BatchAddInvoice creates a batch of invoices sharing the parameters of the
+	// given template, each with a freshly generated preimage, and adds them to
+	// the invoice database within a single transaction. Either all of the
+	// invoices are added, or none are. The invoices are returned in the order
+	// they were added. At most 1000 invoices may be created per request.
+	BatchAddInvoice(ctx context.Context, in *BatchAddInvoiceRequest, opts ...grpc.CallOption) (*BatchAddInvoiceResponse, error)
+	// * lncli: `timelockedbalance`
+	// TimeLockedBalance returns all of the node's funds which are currently
+	// time-locked, bucketed by the block height at which they become spendable.
+	// This covers the outputs of force closed channels awaiting their CSV or CLTV
+	// maturity, as well as the outgoing HTLCs of open channels, whose value can
+	// only be reclaimed once their CLTV timeout has expired.
+	TimeLockedBalance(ctx context.Context, in *TimeLockedBalanceRequest, opts ...grpc.CallOption) (*TimeLockedBalanceResponse, error)
+	// * lncli: `exportdebugpackage`
+	// ExportDebugPackage bundles the sanitized metadata of all channels, a
+	// summary of their pending HTLCs, the state of their links, the graph
+	// excerpts describing them and the most recent log entries into a gzipped
+	// tarball, which is encrypted to the given public key. No preimages, keys,
+	// revocation secrets or signatures are included, and any 32-byte hex value
+	// is redacted from the log entries. The package is intended to be shared
+	// with maintainers when reporting stuck channels.
+	ExportDebugPackage(ctx context.Context, in *ExportDebugPackageRequest, opts ...grpc.CallOption) (*ExportDebugPackageResponse, error)
+	// * lncli: `debugprofile`
+	// DebugProfile collects a runtime profile of the daemon: a dump of the stack
+	// of each running goroutine, a heap profile, or a CPU profile sampled over
+	// the requested duration, which is capped at 60 seconds. Only a single
+	// profile is collected at a time, and profiles exceeding 3 MiB are
+	// truncated. The goroutine dump allows wedged subsystems, such as a stuck
+	// link, to be diagnosed without access to the host running the daemon.
+	DebugProfile(ctx context.Context, in *DebugProfileRequest, opts ...grpc.CallOption) (*DebugProfileResponse, error)
+	// * lncli: `lookupcircuit`
+	// LookupCircuit returns the payment circuits held by the switch for the
+	// HTLCs paying to the given payment hash, or the circuit whose incoming or
+	// outgoing HTLC is identified by the given channel and HTLC ID. A circuit
+	// links the incoming and outgoing legs of an HTLC forwarded by the node, or
+	// sent by it, until the HTLC is settled or failed, which allows stuck
+	// multi-hop payments to be debugged.
+	LookupCircuit(ctx context.Context, in *LookupCircuitRequest, opts ...grpc.CallOption) (*LookupCircuitResponse, error)
+	// * lncli: `peercompatibility`
+	// PeerCompatibility reports, for each of our open channels, the optional
+	// protocol upgrades which are blocked as the channel peer didn't announce
+	// support for them. The report is based on the features last announced by
+	// each peer, which are cached while we have channels with it, so peers that
+	// are currently offline are included as well.
+	PeerCompatibility(ctx context.Context, in *PeerCompatibilityRequest, opts ...grpc.CallOption) (*PeerCompatibilityResponse, error)
+	// * lncli: `listaliases`
+	// ListAliases lists, for each of our open channels, its confirmed short
+	// channel ID, the short channel ID used for it within the route hints of
+	// our invoices, and the alias short channel IDs which are accepted for
+	// forwards over it. Aliases registered with the switch whose channel isn't
+	// open are listed separately, as HTLCs specifying them are failed with
+	// UnknownNextPeer.
+	ListAliases(ctx context.Context, in *ListAliasesRequest, opts ...grpc.CallOption) (*ListAliasesResponse, error)
+	// * lncli: `inspectcommitments`
+	// InspectCommitments dumps the commitment state of a channel, as persisted
+	// by the node, in order to allow a failure to re-synchronize the channel
+	// with its peer to be diagnosed. This includes our view of the current and
+	// next commitments of both parties, the updates not yet acknowledged by the
+	// remote party, the ChannelReestablish fields we'd send, and those last
+	// received from the remote party, along with whether they allow the
+	// channel to be re-synchronized.
+	InspectCommitments(ctx context.Context, in *InspectCommitmentsRequest, opts ...grpc.CallOption) (*InspectCommitmentsResponse, error)
+	// * lncli: `sendtoroute`
+	// SendToRoute sends a payment over a route which has been fully specified by
+	// the caller, such as by an external path-finder, bypassing the path finding
+	// of the internal router. Each hop of the route must set the channel, the
+	// public key of the node it leads to, the amount to forward and the expiry.
+	// A single attempt is made, after which either the preimage or the decoded
+	// failure is returned.
+	SendToRoute(ctx context.Context, in *SendToRouteRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	// * lncli: `subscribechannelevents`
+	// SubscribeChannelEvents creates a uni-directional stream from the server to
+	// the client in which updates relevant to the state of our channels are
+	// sent. An update is sent whenever a channel is opened, becomes active and
+	// able to forward payments, becomes inactive, or is fully closed.
+	SubscribeChannelEvents(ctx context.Context, in *ChannelEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelEventsClient, error)
+}
+
+type lightningClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLightningClient(cc *grpc.ClientConn) LightningClient {
+	return &lightningClient{cc}
+}
+
+func (c *lightningClient) WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error) {
+	out := new(WalletBalanceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/WalletBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error) {
+	out := new(ChannelBalanceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ChannelBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error) {
+	out := new(TransactionDetails)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetTransactions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error) {
+	out := new(SendCoinsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendCoins", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[0], c.cc, "/lnrpc.Lightning/SubscribeTransactions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeTransactionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeTransactionsClient interface {
+	Recv() (*Transaction, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeTransactionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeTransactionsClient) Recv() (*Transaction, error) {
+	m := new(Transaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error) {
+	out := new(SendManyResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendMany", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
+	out := new(NewAddressResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
+	out := new(NewAddressResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewWitnessAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error) {
+	out := new(SignMessageResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SignMessage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error) {
+	out := new(VerifyMessageResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/VerifyMessage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error) {
+	out := new(ConnectPeerResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ConnectPeer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error) {
+	out := new(DisconnectPeerResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DisconnectPeer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error) {
+	out := new(ListPeersResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPeers", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
+	out := new(GetInfoResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) PendingChannels(ctx context.Context, in *PendingChannelsRequest, opts ...grpc.CallOption) (*PendingChannelsResponse, error) {
+	out := new(PendingChannelsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PendingChannels", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error) {
+	out := new(ListChannelsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListChannels", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error) {
+	out := new(ChannelPoint)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/OpenChannelSync", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[1], c.cc, "/lnrpc.Lightning/OpenChannel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningOpenChannelClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_OpenChannelClient interface {
+	Recv() (*OpenStatusUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningOpenChannelClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningOpenChannelClient) Recv() (*OpenStatusUpdate, error) {
+	m := new(OpenStatusUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[2], c.cc, "/lnrpc.Lightning/CloseChannel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningCloseChannelClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_CloseChannelClient interface {
+	Recv() (*CloseStatusUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningCloseChannelClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
+	m := new(CloseStatusUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[3], c.cc, "/lnrpc.Lightning/SendPayment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSendPaymentClient{stream}
+	return x, nil
+}
+
+type Lightning_SendPaymentClient interface {
+	Send(*SendRequest) error
+	Recv() (*SendResponse, error)
+	grpc.ClientStream
+}
+
+type lightningSendPaymentClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSendPaymentClient) Send(m *SendRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *lightningSendPaymentClient) Recv() (*SendResponse, error) {
+	m := new(SendResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	out := new(SendResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendPaymentSync", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error) {
+	out := new(AddInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AddInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error) {
+	out := new(ListInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListInvoices", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error) {
+	out := new(Invoice)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/LookupInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[4], c.cc, "/lnrpc.Lightning/SubscribeInvoices", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeInvoicesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeInvoicesClient interface {
+	Recv() (*Invoice, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeInvoicesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeInvoicesClient) Recv() (*Invoice, error) {
+	m := new(Invoice)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SubscribeSingleInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (Lightning_SubscribeSingleInvoiceClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[5], c.cc, "/lnrpc.Lightning/SubscribeSingleInvoice", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeSingleInvoiceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeSingleInvoiceClient interface {
+	Recv() (*Invoice, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeSingleInvoiceClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeSingleInvoiceClient) Recv() (*Invoice, error) {
+	m := new(Invoice)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error) {
+	out := new(PayReq)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DecodePayReq", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error) {
+	out := new(ListPaymentsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPayments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error) {
+	out := new(DeleteAllPaymentsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DeleteAllPayments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error) {
+	out := new(ChannelGraph)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DescribeGraph", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error) {
+	out := new(ChannelEdge)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetChanInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error) {
+	out := new(NodeInfo)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNodeInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error) {
+	out := new(QueryRoutesResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryRoutes", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error) {
+	out := new(NetworkInfo)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNetworkInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
+	out := new(StopResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/StopDaemon", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[6], c.cc, "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeChannelGraphClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeChannelGraphClient interface {
+	Recv() (*GraphTopologyUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeChannelGraphClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeChannelGraphClient) Recv() (*GraphTopologyUpdate, error) {
+	m := new(GraphTopologyUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error) {
+	out := new(DebugLevelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DebugLevel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error) {
+	out := new(FeeReportResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/FeeReport", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateChannelPolicy(ctx context.Context, in *PolicyUpdateRequest, opts ...grpc.CallOption) (*PolicyUpdateResponse, error) {
+	out := new(PolicyUpdateResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateChannelPolicy", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportGraph(ctx context.Context, in *ExportGraphRequest, opts ...grpc.CallOption) (*ExportGraphResponse, error) {
+	out := new(ExportGraphResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ExportGraph", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ImportGraph(ctx context.Context, in *ImportGraphRequest, opts ...grpc.CallOption) (*ImportGraphResponse, error) {
+	out := new(ImportGraphResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ImportGraph", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ForwardingFilter(ctx context.Context, in *ForwardingFilterRequest, opts ...grpc.CallOption) (*ForwardingFilterResponse, error) {
+	out := new(ForwardingFilterResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ForwardingFilter", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateForwardingFilter(ctx context.Context, in *UpdateForwardingFilterRequest, opts ...grpc.CallOption) (*ForwardingFilterResponse, error) {
+	out := new(ForwardingFilterResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateForwardingFilter", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribePeerMessages(ctx context.Context, in *PeerMessageSubscription, opts ...grpc.CallOption) (Lightning_SubscribePeerMessagesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[7], c.cc, "/lnrpc.Lightning/SubscribePeerMessages", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribePeerMessagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribePeerMessagesClient interface {
+	Recv() (*PeerMessage, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribePeerMessagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribePeerMessagesClient) Recv() (*PeerMessage, error) {
+	m := new(PeerMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) LiquidityHistory(ctx context.Context, in *LiquidityHistoryRequest, opts ...grpc.CallOption) (*LiquidityHistoryResponse, error) {
+	out := new(LiquidityHistoryResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/LiquidityHistory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) InjectSwitchFault(ctx context.Context, in *InjectSwitchFaultRequest, opts ...grpc.CallOption) (*InjectSwitchFaultResponse, error) {
+	out := new(InjectSwitchFaultResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/InjectSwitchFault", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error) {
+	out := new(UpdateChanStatusResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateChanStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SettleHoldInvoice(ctx context.Context, in *SettleHoldInvoiceRequest, opts ...grpc.CallOption) (*SettleHoldInvoiceResponse, error) {
+	out := new(SettleHoldInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SettleHoldInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) CancelHoldInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*CancelHoldInvoiceResponse, error) {
+	out := new(CancelHoldInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/CancelHoldInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ForwardingHistory(ctx context.Context, in *ForwardingHistoryRequest, opts ...grpc.CallOption) (*ForwardingHistoryResponse, error) {
+	out := new(ForwardingHistoryResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ForwardingHistory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) BatchAddInvoice(ctx context.Context, in *BatchAddInvoiceRequest, opts ...grpc.CallOption) (*BatchAddInvoiceResponse, error) {
+	out := new(BatchAddInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/BatchAddInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) TimeLockedBalance(ctx context.Context, in *TimeLockedBalanceRequest, opts ...grpc.CallOption) (*TimeLockedBalanceResponse, error) {
+	out := new(TimeLockedBalanceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/TimeLockedBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportDebugPackage(ctx context.Context, in *ExportDebugPackageRequest, opts ...grpc.CallOption) (*ExportDebugPackageResponse, error) {
+	out := new(ExportDebugPackageResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ExportDebugPackage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DebugProfile(ctx context.Context, in *DebugProfileRequest, opts ...grpc.CallOption) (*DebugProfileResponse, error) {
+	out := new(DebugProfileResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DebugProfile", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) LookupCircuit(ctx context.Context, in *LookupCircuitRequest, opts ...grpc.CallOption) (*LookupCircuitResponse, error) {
+	out := new(LookupCircuitResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/LookupCircuit", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) PeerCompatibility(ctx context.Context, in *PeerCompatibilityRequest, opts ...grpc.CallOption) (*PeerCompatibilityResponse, error) {
+	out := new(PeerCompatibilityResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PeerCompatibility", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListAliases(ctx context.Context, in *ListAliasesRequest, opts ...grpc.CallOption) (*ListAliasesResponse, error) {
+	out := new(ListAliasesResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListAliases", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) InspectCommitments(ctx context.Context, in *InspectCommitmentsRequest, opts ...grpc.CallOption) (*InspectCommitmentsResponse, error) {
+	out := new(InspectCommitmentsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/InspectCommitments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SendToRoute(ctx context.Context, in *SendToRouteRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	out := new(SendResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendToRoute", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeChannelEvents(ctx context.Context, in *ChannelEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelEventsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[8], c.cc, "/lnrpc.Lightning/SubscribeChannelEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeChannelEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeChannelEventsClient interface {
+	Recv() (*ChannelEventUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeChannelEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeChannelEventsClient) Recv() (*ChannelEventUpdate, error) {
+	m := new(ChannelEventUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Lightning service
+
+type LightningServer interface {
 	// * lncli: `walletbalance`
 	// WalletBalance returns total unspent outputs(confirmed and unconfirmed), all confirmed unspent outputs and all unconfirmed unspent outputs under control
 	// by the wallet. This method can be modified by having the request specify
@@ -4693,6 +7731,12 @@ type LightningServer interface {
 	// SubscribeInvoices returns a uni-directional stream (sever -> client) for
 	// notifying the client of newly added/settled invoices.
 	SubscribeInvoices(*InvoiceSubscription, Lightning_SubscribeInvoicesServer) error
+	// * lncli: `subscribeinvoice`
+	// SubscribeSingleInvoice returns a uni-directional stream (server -> client)
+	// for notifying the client of the updates of a single invoice. The current
+	// state of the invoice is sent first, followed by the invoice each time an
+	// HTLC paying to it is accepted, or it's settled or canceled.
+	SubscribeSingleInvoice(*PaymentHash, Lightning_SubscribeSingleInvoiceServer) error
 	// * lncli: `decodepayreq`
 	// DecodePayReq takes an encoded payment request string and attempts to decode
 	// it, returning a full description of the conditions encoded within the
@@ -4757,719 +7801,1272 @@ type LightningServer interface {
 	FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error)
 	// * lncli: `updatechanpolicy`
 	// UpdateChannelPolicy allows the caller to update the fee schedule and
-	// channel policies for all channels globally, or a particular channel.
+	// channel policies for all channels globally, a particular channel, a set
+	// of channels, or all channels with a peer. The update is validated
+	// before being applied, and the targeted channels it couldn't be applied
+	// to are returned along with the reason why.
 	UpdateChannelPolicy(context.Context, *PolicyUpdateRequest) (*PolicyUpdateResponse, error)
+	// * lncli: `exportgraph`
+	// ExportGraph returns a compact snapshot of the node's validated channel
+	// graph. The snapshot contains the original signed channel announcements,
+	// channel updates and node announcements, and can be loaded into a fresh
+	// node via the ImportGraph call.
+	ExportGraph(context.Context, *ExportGraphRequest) (*ExportGraphResponse, error)
+	// * lncli: `importgraph`
+	// ImportGraph loads a channel graph snapshot created by ExportGraph into the
+	// node's channel graph, allowing a new node to be useful for routing without
+	// waiting to receive the entire graph via gossip. Each announcement within
+	// the snapshot is validated exactly as if it had been received via gossip:
+	// its signatures are verified, and the funding outputs of all imported
+	// channels are verified against the chain.
+	ImportGraph(context.Context, *ImportGraphRequest) (*ImportGraphResponse, error)
+	// * lncli: `fwdfilter`
+	// ForwardingFilter returns the current forwarding allow and deny lists, which
+	// determine the peers the node will forward HTLCs from or to.
+	ForwardingFilter(context.Context, *ForwardingFilterRequest) (*ForwardingFilterResponse, error)
+	// * lncli: `updatefwdfilter`
+	// UpdateForwardingFilter adds peers to, or removes peers from, the forwarding
+	// allow and deny lists. The changes take effect immediately, but aren't
+	// persisted across restarts. Forwards arriving from, or destined to, a peer
+	// that isn't permitted by the lists are failed back to the sender.
+	UpdateForwardingFilter(context.Context, *UpdateForwardingFilterRequest) (*ForwardingFilterResponse, error)
+	// * lncli: `tappeer`
+	// SubscribePeerMessages returns a uni-directional stream (server -> client)
+	// of the decoded wire messages sent to, and received from, the target peer.
+	// Payment preimages are redacted from the streamed messages. This is a
+	// debugging aid, and is only available if lnd was started with the
+	// --debugmessagetap flag.
+	SubscribePeerMessages(*PeerMessageSubscription, Lightning_SubscribePeerMessagesServer) error
+	// * lncli: `liquidityhistory`
+	// LiquidityHistory returns the recorded snapshots of the balances and
+	// pending HTLCs of the target channel within the given time range, in
+	// chronological order. Snapshots are only recorded if the liquidity history
+	// is enabled.
+	LiquidityHistory(context.Context, *LiquidityHistoryRequest) (*LiquidityHistoryResponse, error)
+	// * lncli: `injectswitchfault`
+	// InjectSwitchFault injects a fault into the forwarding path of the htlc
+	// switch, which drops, delays or duplicates the next packets forwarded. Faults
+	// are applied in the order they were injected. This is intended for
+	// integration tests, and is only available if lnd was started with the
+	// --debugswitchfaults flag.
+	InjectSwitchFault(context.Context, *InjectSwitchFaultRequest) (*InjectSwitchFaultResponse, error)
+	// * lncli: `updatechanstatus`
+	// UpdateChanStatus sets the disabled bit of the ChannelUpdate announced for
+	// the target channel, and pins it, so that it's kept regardless of the
+	// automatic management of the status of our channels. This allows an operator
+	// to drain a channel of routing traffic ahead of maintenance. The pin can be
+	// released with the "auto" action, returning the channel to the status of all
+	// other channels. Pins don't survive a restart.
+	UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error)
+	// * lncli: `settleholdinvoice`
+	// SettleHoldInvoice releases the preimage of a hold invoice, settling the
+	// HTLCs paying to it which are held by their links. The invoice itself is
+	// marked as settled once its HTLCs have been settled.
+	SettleHoldInvoice(context.Context, *SettleHoldInvoiceRequest) (*SettleHoldInvoiceResponse, error)
+	// * lncli: `cancelholdinvoice`
+	// CancelHoldInvoice cancels a hold invoice whose preimage hasn't yet been
+	// released, failing the HTLCs paying to it back to their senders. Any HTLCs
+	// paying to the invoice in the future are failed as well.
+	CancelHoldInvoice(context.Context, *PaymentHash) (*CancelHoldInvoiceResponse, error)
+	// * lncli: `fwdinghistory`
+	// ForwardingHistory returns the HTLCs successfully forwarded by the node
+	// within the given time range, in chronological order, along with the fee
+	// earned by each. The results are paginated by an index offset, allowing
+	// operators to audit their routing revenue over large time ranges.
+	ForwardingHistory(context.Context, *ForwardingHistoryRequest) (*ForwardingHistoryResponse, error)
+	// * lncli: `batchaddinvoice`
+	// BatchAddInvoice creates a batch of invoices sharing the parameters of the
+	// given template, each with a freshly generated preimage, and adds them to
+	// the invoice database within a single transaction. Either all of the
+	// invoices are added, or none are. The invoices are returned in the order
+	// they were added. At most 1000 invoices may be created per request.
+	BatchAddInvoice(context.Context, *BatchAddInvoiceRequest) (*BatchAddInvoiceResponse, error)
+	// * lncli: `timelockedbalance`
+	// TimeLockedBalance returns all of the node's funds which are currently
+	// time-locked, bucketed by the block height at which they become spendable.
+	// This covers the outputs of force closed channels awaiting their CSV or CLTV
+	// maturity, as well as the outgoing HTLCs of open channels, whose value can
+	// only be reclaimed once their CLTV timeout has expired.
+	TimeLockedBalance(context.Context, *TimeLockedBalanceRequest) (*TimeLockedBalanceResponse, error)
+	// * lncli: `exportdebugpackage`
+	// ExportDebugPackage bundles the sanitized metadata of all channels, a
+	// summary of their pending HTLCs, the state of their links, the graph
+	// excerpts describing them and the most recent log entries into a gzipped
+	// tarball, which is encrypted to the given public key. No preimages, keys,
+	// revocation secrets or signatures are included, and any 32-byte hex value
+	// is redacted from the log entries. The package is intended to be shared
+	// with maintainers when reporting stuck channels.
+	ExportDebugPackage(context.Context, *ExportDebugPackageRequest) (*ExportDebugPackageResponse, error)
+	// * lncli: `debugprofile`
+	// DebugProfile collects a runtime profile of the daemon: a dump of the stack
+	// of each running goroutine, a heap profile, or a CPU profile sampled over
+	// the requested duration, which is capped at 60 seconds. Only a single
+	// profile is collected at a time, and profiles exceeding 3 MiB are
+	// truncated. The goroutine dump allows wedged subsystems, such as a stuck
+	// link, to be diagnosed without access to the host running the daemon.
+	DebugProfile(context.Context, *DebugProfileRequest) (*DebugProfileResponse, error)
+	// * lncli: `lookupcircuit`
+	// LookupCircuit returns the payment circuits held by the switch for the
+	// HTLCs paying to the given payment hash, or the circuit whose incoming or
+	// outgoing HTLC is identified by the given channel and HTLC ID. A circuit
+	// links the incoming and outgoing legs of an HTLC forwarded by the node, or
+	// sent by it, until the HTLC is settled or failed, which allows stuck
+	// multi-hop payments to be debugged.
+	LookupCircuit(context.Context, *LookupCircuitRequest) (*LookupCircuitResponse, error)
+	// * lncli: `peercompatibility`
+	// PeerCompatibility reports, for each of our open channels, the optional
+	// protocol upgrades which are blocked as the channel peer didn't announce
+	// support for them. The report is based on the features last announced by
+	// each peer, which are cached while we have channels with it, so peers that
+	// are currently offline are included as well.
+	PeerCompatibility(context.Context, *PeerCompatibilityRequest) (*PeerCompatibilityResponse, error)
+	// * lncli: `listaliases`
+	// ListAliases lists, for each of our open channels, its confirmed short
+	// channel ID, the short channel ID used for it within the route hints of
+	// our invoices, and the alias short channel IDs which are accepted for
+	// forwards over it. Aliases registered with the switch whose channel isn't
+	// open are listed separately, as HTLCs specifying them are failed with
+	// UnknownNextPeer.
+	ListAliases(context.Context, *ListAliasesRequest) (*ListAliasesResponse, error)
+	// * lncli: `inspectcommitments`
+	// InspectCommitments dumps the commitment state of a channel, as persisted
+	// by the node, in order to allow a failure to re-synchronize the channel
+	// with its peer to be diagnosed. This includes our view of the current and
+	// next commitments of both parties, the updates not yet acknowledged by the
+	// remote party, the ChannelReestablish fields we'd send, and those last
+	// received from the remote party, along with whether they allow the
+	// channel to be re-synchronized.
+	InspectCommitments(context.Context, *InspectCommitmentsRequest) (*InspectCommitmentsResponse, error)
+	// * lncli: `sendtoroute`
+	// SendToRoute sends a payment over a route which has been fully specified by
+	// the caller, such as by an external path-finder, bypassing the path finding
+	// of the internal router. Each hop of the route must set the channel, the
+	// public key of the node it leads to, the amount to forward and the expiry.
+	// A single attempt is made, after which either the preimage or the decoded
+	// failure is returned.
+	SendToRoute(context.Context, *SendToRouteRequest) (*SendResponse, error)
+	// * lncli: `subscribechannelevents`
+	// SubscribeChannelEvents creates a uni-directional stream from the server to
+	// the client in which updates relevant to the state of our channels are
+	// sent. An update is sent whenever a channel is opened, becomes active and
+	// able to forward payments, becomes inactive, or is fully closed.
+	SubscribeChannelEvents(*ChannelEventSubscription, Lightning_SubscribeChannelEventsServer) error
+}
+
+func RegisterLightningServer(s *grpc.Server, srv LightningServer) {
+	s.RegisterService(&_Lightning_serviceDesc, srv)
+}
+
+func _Lightning_WalletBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).WalletBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/WalletBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).WalletBalance(ctx, req.(*WalletBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ChannelBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ChannelBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ChannelBalance(ctx, req.(*ChannelBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetTransactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SendCoins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendCoinsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendCoins(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendCoins",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendCoins(ctx, req.(*SendCoinsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SubscribeTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTransactionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeTransactions(m, &lightningSubscribeTransactionsServer{stream})
+}
+
+type Lightning_SubscribeTransactionsServer interface {
+	Send(*Transaction) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeTransactionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeTransactionsServer) Send(m *Transaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_SendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendManyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendMany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendMany",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendMany(ctx, req.(*SendManyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).NewAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/NewAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).NewAddress(ctx, req.(*NewAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterLightningServer(s *grpc.Server, srv LightningServer) {
-	s.RegisterService(&_Lightning_serviceDesc, srv)
+func _Lightning_NewWitnessAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewWitnessAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).NewWitnessAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/NewWitnessAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).NewWitnessAddress(ctx, req.(*NewWitnessAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SignMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SignMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SignMessage(ctx, req.(*SignMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_VerifyMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).VerifyMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/VerifyMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).VerifyMessage(ctx, req.(*VerifyMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ConnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ConnectPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ConnectPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ConnectPeer(ctx, req.(*ConnectPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_DisconnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisconnectPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DisconnectPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DisconnectPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DisconnectPeer(ctx, req.(*DisconnectPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListPeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListPeers(ctx, req.(*ListPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetInfo(ctx, req.(*GetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).PendingChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/PendingChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).PendingChannels(ctx, req.(*PendingChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListChannels(ctx, req.(*ListChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_OpenChannelSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).OpenChannelSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/OpenChannelSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).OpenChannelSync(ctx, req.(*OpenChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_OpenChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OpenChannelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).OpenChannel(m, &lightningOpenChannelServer{stream})
+}
+
+type Lightning_OpenChannelServer interface {
+	Send(*OpenStatusUpdate) error
+	grpc.ServerStream
+}
+
+type lightningOpenChannelServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningOpenChannelServer) Send(m *OpenStatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_CloseChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CloseChannelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).CloseChannel(m, &lightningCloseChannelServer{stream})
+}
+
+type Lightning_CloseChannelServer interface {
+	Send(*CloseStatusUpdate) error
+	grpc.ServerStream
+}
+
+type lightningCloseChannelServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_SendPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).SendPayment(&lightningSendPaymentServer{stream})
+}
+
+type Lightning_SendPaymentServer interface {
+	Send(*SendResponse) error
+	Recv() (*SendRequest, error)
+	grpc.ServerStream
+}
+
+type lightningSendPaymentServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSendPaymentServer) Send(m *SendResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *lightningSendPaymentServer) Recv() (*SendRequest, error) {
+	m := new(SendRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
-func _Lightning_WalletBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(WalletBalanceRequest)
+func _Lightning_SendPaymentSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).WalletBalance(ctx, in)
+		return srv.(LightningServer).SendPaymentSync(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/WalletBalance",
+		FullMethod: "/lnrpc.Lightning/SendPaymentSync",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).WalletBalance(ctx, req.(*WalletBalanceRequest))
+		return srv.(LightningServer).SendPaymentSync(ctx, req.(*SendRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChannelBalanceRequest)
+func _Lightning_AddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Invoice)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ChannelBalance(ctx, in)
+		return srv.(LightningServer).AddInvoice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ChannelBalance",
+		FullMethod: "/lnrpc.Lightning/AddInvoice",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ChannelBalance(ctx, req.(*ChannelBalanceRequest))
+		return srv.(LightningServer).AddInvoice(ctx, req.(*Invoice))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetTransactionsRequest)
+func _Lightning_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvoiceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetTransactions(ctx, in)
+		return srv.(LightningServer).ListInvoices(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetTransactions",
+		FullMethod: "/lnrpc.Lightning/ListInvoices",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
+		return srv.(LightningServer).ListInvoices(ctx, req.(*ListInvoiceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SendCoins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendCoinsRequest)
+func _Lightning_LookupInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentHash)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendCoins(ctx, in)
+		return srv.(LightningServer).LookupInvoice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendCoins",
+		FullMethod: "/lnrpc.Lightning/LookupInvoice",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendCoins(ctx, req.(*SendCoinsRequest))
+		return srv.(LightningServer).LookupInvoice(ctx, req.(*PaymentHash))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GetTransactionsRequest)
+func _Lightning_SubscribeInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InvoiceSubscription)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).SubscribeTransactions(m, &lightningSubscribeTransactionsServer{stream})
+	return srv.(LightningServer).SubscribeInvoices(m, &lightningSubscribeInvoicesServer{stream})
 }
 
-type Lightning_SubscribeTransactionsServer interface {
-	Send(*Transaction) error
+type Lightning_SubscribeInvoicesServer interface {
+	Send(*Invoice) error
 	grpc.ServerStream
 }
 
-type lightningSubscribeTransactionsServer struct {
+type lightningSubscribeInvoicesServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningSubscribeTransactionsServer) Send(m *Transaction) error {
+func (x *lightningSubscribeInvoicesServer) Send(m *Invoice) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_SendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendManyRequest)
+func _Lightning_SubscribeSingleInvoice_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PaymentHash)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeSingleInvoice(m, &lightningSubscribeSingleInvoiceServer{stream})
+}
+
+type Lightning_SubscribeSingleInvoiceServer interface {
+	Send(*Invoice) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeSingleInvoiceServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeSingleInvoiceServer) Send(m *Invoice) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_DecodePayReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayReqString)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendMany(ctx, in)
+		return srv.(LightningServer).DecodePayReq(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendMany",
+		FullMethod: "/lnrpc.Lightning/DecodePayReq",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendMany(ctx, req.(*SendManyRequest))
+		return srv.(LightningServer).DecodePayReq(ctx, req.(*PayReqString))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewAddressRequest)
+func _Lightning_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).NewAddress(ctx, in)
+		return srv.(LightningServer).ListPayments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/NewAddress",
+		FullMethod: "/lnrpc.Lightning/ListPayments",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).NewAddress(ctx, req.(*NewAddressRequest))
+		return srv.(LightningServer).ListPayments(ctx, req.(*ListPaymentsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_NewWitnessAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewWitnessAddressRequest)
+func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAllPaymentsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).NewWitnessAddress(ctx, in)
+		return srv.(LightningServer).DeleteAllPayments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/NewWitnessAddress",
+		FullMethod: "/lnrpc.Lightning/DeleteAllPayments",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).NewWitnessAddress(ctx, req.(*NewWitnessAddressRequest))
+		return srv.(LightningServer).DeleteAllPayments(ctx, req.(*DeleteAllPaymentsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SignMessageRequest)
+func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelGraphRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SignMessage(ctx, in)
+		return srv.(LightningServer).DescribeGraph(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SignMessage",
+		FullMethod: "/lnrpc.Lightning/DescribeGraph",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SignMessage(ctx, req.(*SignMessageRequest))
+		return srv.(LightningServer).DescribeGraph(ctx, req.(*ChannelGraphRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_VerifyMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(VerifyMessageRequest)
+func _Lightning_GetChanInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChanInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).VerifyMessage(ctx, in)
+		return srv.(LightningServer).GetChanInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/VerifyMessage",
+		FullMethod: "/lnrpc.Lightning/GetChanInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).VerifyMessage(ctx, req.(*VerifyMessageRequest))
+		return srv.(LightningServer).GetChanInfo(ctx, req.(*ChanInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ConnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ConnectPeerRequest)
+func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ConnectPeer(ctx, in)
+		return srv.(LightningServer).GetNodeInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ConnectPeer",
+		FullMethod: "/lnrpc.Lightning/GetNodeInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ConnectPeer(ctx, req.(*ConnectPeerRequest))
+		return srv.(LightningServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DisconnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DisconnectPeerRequest)
+func _Lightning_QueryRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRoutesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DisconnectPeer(ctx, in)
+		return srv.(LightningServer).QueryRoutes(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DisconnectPeer",
+		FullMethod: "/lnrpc.Lightning/QueryRoutes",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DisconnectPeer(ctx, req.(*DisconnectPeerRequest))
+		return srv.(LightningServer).QueryRoutes(ctx, req.(*QueryRoutesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPeersRequest)
+func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListPeers(ctx, in)
+		return srv.(LightningServer).GetNetworkInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListPeers",
+		FullMethod: "/lnrpc.Lightning/GetNetworkInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListPeers(ctx, req.(*ListPeersRequest))
+		return srv.(LightningServer).GetNetworkInfo(ctx, req.(*NetworkInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetInfoRequest)
+func _Lightning_StopDaemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetInfo(ctx, in)
+		return srv.(LightningServer).StopDaemon(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetInfo",
+		FullMethod: "/lnrpc.Lightning/StopDaemon",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetInfo(ctx, req.(*GetInfoRequest))
+		return srv.(LightningServer).StopDaemon(ctx, req.(*StopRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PendingChannelsRequest)
+func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GraphTopologySubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
+}
+
+type Lightning_SubscribeChannelGraphServer interface {
+	Send(*GraphTopologyUpdate) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeChannelGraphServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebugLevelRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).PendingChannels(ctx, in)
+		return srv.(LightningServer).DebugLevel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/PendingChannels",
+		FullMethod: "/lnrpc.Lightning/DebugLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DebugLevel(ctx, req.(*DebugLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_FeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeeReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).FeeReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/FeeReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).FeeReport(ctx, req.(*FeeReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_UpdateChannelPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).UpdateChannelPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/UpdateChannelPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).UpdateChannelPolicy(ctx, req.(*PolicyUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ExportGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ExportGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExportGraph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExportGraph(ctx, req.(*ExportGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ImportGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ImportGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ImportGraph",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).PendingChannels(ctx, req.(*PendingChannelsRequest))
+		return srv.(LightningServer).ImportGraph(ctx, req.(*ImportGraphRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListChannelsRequest)
+func _Lightning_ForwardingFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardingFilterRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListChannels(ctx, in)
+		return srv.(LightningServer).ForwardingFilter(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListChannels",
+		FullMethod: "/lnrpc.Lightning/ForwardingFilter",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListChannels(ctx, req.(*ListChannelsRequest))
+		return srv.(LightningServer).ForwardingFilter(ctx, req.(*ForwardingFilterRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_OpenChannelSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OpenChannelRequest)
+func _Lightning_UpdateForwardingFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateForwardingFilterRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).OpenChannelSync(ctx, in)
+		return srv.(LightningServer).UpdateForwardingFilter(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/OpenChannelSync",
+		FullMethod: "/lnrpc.Lightning/UpdateForwardingFilter",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).OpenChannelSync(ctx, req.(*OpenChannelRequest))
+		return srv.(LightningServer).UpdateForwardingFilter(ctx, req.(*UpdateForwardingFilterRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_OpenChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(OpenChannelRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(LightningServer).OpenChannel(m, &lightningOpenChannelServer{stream})
-}
-
-type Lightning_OpenChannelServer interface {
-	Send(*OpenStatusUpdate) error
-	grpc.ServerStream
-}
-
-type lightningOpenChannelServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningOpenChannelServer) Send(m *OpenStatusUpdate) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Lightning_CloseChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(CloseChannelRequest)
+func _Lightning_SubscribePeerMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PeerMessageSubscription)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).CloseChannel(m, &lightningCloseChannelServer{stream})
-}
-
-type Lightning_CloseChannelServer interface {
-	Send(*CloseStatusUpdate) error
-	grpc.ServerStream
-}
-
-type lightningCloseChannelServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
-	return x.ServerStream.SendMsg(m)
+	return srv.(LightningServer).SubscribePeerMessages(m, &lightningSubscribePeerMessagesServer{stream})
 }
 
-func _Lightning_SendPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(LightningServer).SendPayment(&lightningSendPaymentServer{stream})
-}
-
-type Lightning_SendPaymentServer interface {
-	Send(*SendResponse) error
-	Recv() (*SendRequest, error)
+type Lightning_SubscribePeerMessagesServer interface {
+	Send(*PeerMessage) error
 	grpc.ServerStream
 }
 
-type lightningSendPaymentServer struct {
+type lightningSubscribePeerMessagesServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningSendPaymentServer) Send(m *SendResponse) error {
+func (x *lightningSubscribePeerMessagesServer) Send(m *PeerMessage) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func (x *lightningSendPaymentServer) Recv() (*SendRequest, error) {
-	m := new(SendRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-func _Lightning_SendPaymentSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendRequest)
+func _Lightning_LiquidityHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LiquidityHistoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendPaymentSync(ctx, in)
+		return srv.(LightningServer).LiquidityHistory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendPaymentSync",
+		FullMethod: "/lnrpc.Lightning/LiquidityHistory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendPaymentSync(ctx, req.(*SendRequest))
+		return srv.(LightningServer).LiquidityHistory(ctx, req.(*LiquidityHistoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_AddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Invoice)
+func _Lightning_InjectSwitchFault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InjectSwitchFaultRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).AddInvoice(ctx, in)
+		return srv.(LightningServer).InjectSwitchFault(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/AddInvoice",
+		FullMethod: "/lnrpc.Lightning/InjectSwitchFault",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).AddInvoice(ctx, req.(*Invoice))
+		return srv.(LightningServer).InjectSwitchFault(ctx, req.(*InjectSwitchFaultRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListInvoiceRequest)
+func _Lightning_UpdateChanStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateChanStatusRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListInvoices(ctx, in)
+		return srv.(LightningServer).UpdateChanStatus(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListInvoices",
+		FullMethod: "/lnrpc.Lightning/UpdateChanStatus",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListInvoices(ctx, req.(*ListInvoiceRequest))
+		return srv.(LightningServer).UpdateChanStatus(ctx, req.(*UpdateChanStatusRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_LookupInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PaymentHash)
+func _Lightning_SettleHoldInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettleHoldInvoiceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).LookupInvoice(ctx, in)
+		return srv.(LightningServer).SettleHoldInvoice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/LookupInvoice",
+		FullMethod: "/lnrpc.Lightning/SettleHoldInvoice",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).LookupInvoice(ctx, req.(*PaymentHash))
+		return srv.(LightningServer).SettleHoldInvoice(ctx, req.(*SettleHoldInvoiceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(InvoiceSubscription)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(LightningServer).SubscribeInvoices(m, &lightningSubscribeInvoicesServer{stream})
-}
-
-type Lightning_SubscribeInvoicesServer interface {
-	Send(*Invoice) error
-	grpc.ServerStream
-}
-
-type lightningSubscribeInvoicesServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningSubscribeInvoicesServer) Send(m *Invoice) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Lightning_DecodePayReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PayReqString)
+func _Lightning_CancelHoldInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentHash)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DecodePayReq(ctx, in)
+		return srv.(LightningServer).CancelHoldInvoice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DecodePayReq",
+		FullMethod: "/lnrpc.Lightning/CancelHoldInvoice",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DecodePayReq(ctx, req.(*PayReqString))
+		return srv.(LightningServer).CancelHoldInvoice(ctx, req.(*PaymentHash))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPaymentsRequest)
+func _Lightning_ForwardingHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardingHistoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListPayments(ctx, in)
+		return srv.(LightningServer).ForwardingHistory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListPayments",
+		FullMethod: "/lnrpc.Lightning/ForwardingHistory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListPayments(ctx, req.(*ListPaymentsRequest))
+		return srv.(LightningServer).ForwardingHistory(ctx, req.(*ForwardingHistoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteAllPaymentsRequest)
+func _Lightning_BatchAddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchAddInvoiceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DeleteAllPayments(ctx, in)
+		return srv.(LightningServer).BatchAddInvoice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DeleteAllPayments",
+		FullMethod: "/lnrpc.Lightning/BatchAddInvoice",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DeleteAllPayments(ctx, req.(*DeleteAllPaymentsRequest))
+		return srv.(LightningServer).BatchAddInvoice(ctx, req.(*BatchAddInvoiceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChannelGraphRequest)
+func _Lightning_TimeLockedBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeLockedBalanceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DescribeGraph(ctx, in)
+		return srv.(LightningServer).TimeLockedBalance(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DescribeGraph",
+		FullMethod: "/lnrpc.Lightning/TimeLockedBalance",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DescribeGraph(ctx, req.(*ChannelGraphRequest))
+		return srv.(LightningServer).TimeLockedBalance(ctx, req.(*TimeLockedBalanceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetChanInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChanInfoRequest)
+func _Lightning_ExportDebugPackage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportDebugPackageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetChanInfo(ctx, in)
+		return srv.(LightningServer).ExportDebugPackage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetChanInfo",
+		FullMethod: "/lnrpc.Lightning/ExportDebugPackage",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetChanInfo(ctx, req.(*ChanInfoRequest))
+		return srv.(LightningServer).ExportDebugPackage(ctx, req.(*ExportDebugPackageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NodeInfoRequest)
+func _Lightning_DebugProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebugProfileRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetNodeInfo(ctx, in)
+		return srv.(LightningServer).DebugProfile(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetNodeInfo",
+		FullMethod: "/lnrpc.Lightning/DebugProfile",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
+		return srv.(LightningServer).DebugProfile(ctx, req.(*DebugProfileRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_QueryRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(QueryRoutesRequest)
+func _Lightning_LookupCircuit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupCircuitRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).QueryRoutes(ctx, in)
+		return srv.(LightningServer).LookupCircuit(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/QueryRoutes",
+		FullMethod: "/lnrpc.Lightning/LookupCircuit",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).QueryRoutes(ctx, req.(*QueryRoutesRequest))
+		return srv.(LightningServer).LookupCircuit(ctx, req.(*LookupCircuitRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NetworkInfoRequest)
+func _Lightning_PeerCompatibility_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerCompatibilityRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetNetworkInfo(ctx, in)
+		return srv.(LightningServer).PeerCompatibility(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetNetworkInfo",
+		FullMethod: "/lnrpc.Lightning/PeerCompatibility",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetNetworkInfo(ctx, req.(*NetworkInfoRequest))
+		return srv.(LightningServer).PeerCompatibility(ctx, req.(*PeerCompatibilityRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_StopDaemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StopRequest)
+func _Lightning_ListAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAliasesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).StopDaemon(ctx, in)
+		return srv.(LightningServer).ListAliases(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/StopDaemon",
+		FullMethod: "/lnrpc.Lightning/ListAliases",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).StopDaemon(ctx, req.(*StopRequest))
+		return srv.(LightningServer).ListAliases(ctx, req.(*ListAliasesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GraphTopologySubscription)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
-}
-
-type Lightning_SubscribeChannelGraphServer interface {
-	Send(*GraphTopologyUpdate) error
-	grpc.ServerStream
-}
-
-type lightningSubscribeChannelGraphServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DebugLevelRequest)
+func _Lightning_InspectCommitments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectCommitmentsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DebugLevel(ctx, in)
+		return srv.(LightningServer).InspectCommitments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DebugLevel",
+		FullMethod: "/lnrpc.Lightning/InspectCommitments",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DebugLevel(ctx, req.(*DebugLevelRequest))
+		return srv.(LightningServer).InspectCommitments(ctx, req.(*InspectCommitmentsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_FeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FeeReportRequest)
+func _Lightning_SendToRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendToRouteRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).FeeReport(ctx, in)
+		return srv.(LightningServer).SendToRoute(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/FeeReport",
+		FullMethod: "/lnrpc.Lightning/SendToRoute",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).FeeReport(ctx, req.(*FeeReportRequest))
+		return srv.(LightningServer).SendToRoute(ctx, req.(*SendToRouteRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_UpdateChannelPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PolicyUpdateRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(LightningServer).UpdateChannelPolicy(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/UpdateChannelPolicy",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).UpdateChannelPolicy(ctx, req.(*PolicyUpdateRequest))
+func _Lightning_SubscribeChannelEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChannelEventSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return interceptor(ctx, in, info, handler)
+	return srv.(LightningServer).SubscribeChannelEvents(m, &lightningSubscribeChannelEventsServer{stream})
+}
+
+type Lightning_SubscribeChannelEventsServer interface {
+	Send(*ChannelEventUpdate) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeChannelEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeChannelEventsServer) Send(m *ChannelEventUpdate) error {
+	return x.ServerStream.SendMsg(m)
 }
 
 var _Lightning_serviceDesc = grpc.ServiceDesc{
@@ -5604,6 +9201,82 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateChannelPolicy",
 			Handler:    _Lightning_UpdateChannelPolicy_Handler,
 		},
+		{
+			MethodName: "ExportGraph",
+			Handler:    _Lightning_ExportGraph_Handler,
+		},
+		{
+			MethodName: "ImportGraph",
+			Handler:    _Lightning_ImportGraph_Handler,
+		},
+		{
+			MethodName: "ForwardingFilter",
+			Handler:    _Lightning_ForwardingFilter_Handler,
+		},
+		{
+			MethodName: "UpdateForwardingFilter",
+			Handler:    _Lightning_UpdateForwardingFilter_Handler,
+		},
+		{
+			MethodName: "LiquidityHistory",
+			Handler:    _Lightning_LiquidityHistory_Handler,
+		},
+		{
+			MethodName: "InjectSwitchFault",
+			Handler:    _Lightning_InjectSwitchFault_Handler,
+		},
+		{
+			MethodName: "UpdateChanStatus",
+			Handler:    _Lightning_UpdateChanStatus_Handler,
+		},
+		{
+			MethodName: "SettleHoldInvoice",
+			Handler:    _Lightning_SettleHoldInvoice_Handler,
+		},
+		{
+			MethodName: "CancelHoldInvoice",
+			Handler:    _Lightning_CancelHoldInvoice_Handler,
+		},
+		{
+			MethodName: "ForwardingHistory",
+			Handler:    _Lightning_ForwardingHistory_Handler,
+		},
+		{
+			MethodName: "BatchAddInvoice",
+			Handler:    _Lightning_BatchAddInvoice_Handler,
+		},
+		{
+			MethodName: "TimeLockedBalance",
+			Handler:    _Lightning_TimeLockedBalance_Handler,
+		},
+		{
+			MethodName: "ExportDebugPackage",
+			Handler:    _Lightning_ExportDebugPackage_Handler,
+		},
+		{
+			MethodName: "DebugProfile",
+			Handler:    _Lightning_DebugProfile_Handler,
+		},
+		{
+			MethodName: "LookupCircuit",
+			Handler:    _Lightning_LookupCircuit_Handler,
+		},
+		{
+			MethodName: "PeerCompatibility",
+			Handler:    _Lightning_PeerCompatibility_Handler,
+		},
+		{
+			MethodName: "ListAliases",
+			Handler:    _Lightning_ListAliases_Handler,
+		},
+		{
+			MethodName: "InspectCommitments",
+			Handler:    _Lightning_InspectCommitments_Handler,
+		},
+		{
+			MethodName: "SendToRoute",
+			Handler:    _Lightning_SendToRoute_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -5632,11 +9305,26 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeInvoices_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeSingleInvoice",
+			Handler:       _Lightning_SubscribeSingleInvoice_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SubscribeChannelGraph",
 			Handler:       _Lightning_SubscribeChannelGraph_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribePeerMessages",
+			Handler:       _Lightning_SubscribePeerMessages_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeChannelEvents",
+			Handler:       _Lightning_SubscribeChannelEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "rpc.proto",
 }
@@ -5644,312 +9332,517 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 4904 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x3b, 0x4d, 0x8f, 0x1c, 0x49,
-	0x56, 0xce, 0xea, 0xaa, 0xee, 0xae, 0x57, 0x55, 0xfd, 0x11, 0xdd, 0xee, 0x2e, 0xd7, 0x78, 0xbd,
-	0x9e, 0x60, 0x34, 0xd3, 0x98, 0xc1, 0x6d, 0xf7, 0xb2, 0xc3, 0xec, 0x18, 0x18, 0xf9, 0xbb, 0x87,
-	0xf5, 0x78, 0x7a, 0xb3, 0xed, 0x1d, 0xd8, 0x11, 0x2a, 0xb2, 0xab, 0xa2, 0xab, 0x73, 0x9d, 0x95,
-	0x99, 0x93, 0x19, 0xd5, 0xed, 0xda, 0xc1, 0x12, 0x2c, 0x08, 0x09, 0x09, 0xc4, 0x01, 0x04, 0xda,
-	0xc3, 0x72, 0xe1, 0x02, 0x07, 0x7e, 0xc1, 0x4a, 0xfc, 0x80, 0x95, 0x10, 0x87, 0x3d, 0x21, 0xb8,
-	0x20, 0x38, 0xc1, 0x81, 0x13, 0x17, 0x4e, 0x28, 0x5e, 0x7c, 0x64, 0x44, 0x66, 0xb6, 0xed, 0x65,
-	0x97, 0xbd, 0x55, 0xbc, 0x78, 0xf9, 0x22, 0xe2, 0xc5, 0x8b, 0xf7, 0x5d, 0xd0, 0xce, 0xd2, 0xd1,
-	0xf5, 0x34, 0x4b, 0x78, 0x42, 0x5a, 0x51, 0x9c, 0xa5, 0xa3, 0xc1, 0xe5, 0x49, 0x92, 0x4c, 0x22,
-	0xb6, 0x1b, 0xa4, 0xe1, 0x6e, 0x10, 0xc7, 0x09, 0x0f, 0x78, 0x98, 0xc4, 0xb9, 0x44, 0xa2, 0x37,
-	0x61, 0xe3, 0x6e, 0xc6, 0x02, 0xce, 0x3e, 0x0d, 0xa2, 0x88, 0x71, 0x9f, 0x7d, 0x3e, 0x63, 0x39,
-	0x27, 0x03, 0x58, 0x4e, 0x83, 0x3c, 0x3f, 0x4b, 0xb2, 0x71, 0xdf, 0xbb, 0xea, 0xed, 0x74, 0x7d,
-	0x33, 0xa6, 0x5b, 0xb0, 0xe9, 0x7e, 0x92, 0xa7, 0x49, 0x9c, 0x33, 0x41, 0xea, 0x69, 0x1c, 0x25,
-	0xa3, 0x67, 0x3f, 0x16, 0x29, 0xf7, 0x13, 0x45, 0xea, 0x7b, 0x0d, 0xe8, 0x3c, 0xc9, 0x82, 0x38,
-	0x0f, 0x46, 0x62, 0xb3, 0xa4, 0x0f, 0x4b, 0xfc, 0xf9, 0xf0, 0x24, 0xc8, 0x4f, 0x90, 0x44, 0xdb,
-	0xd7, 0x43, 0xb2, 0x05, 0x8b, 0xc1, 0x34, 0x99, 0xc5, 0xbc, 0xdf, 0xb8, 0xea, 0xed, 0x2c, 0xf8,
-	0x6a, 0x44, 0xde, 0x85, 0xf5, 0x78, 0x36, 0x1d, 0x8e, 0x92, 0xf8, 0x38, 0xcc, 0xa6, 0xf2, 0xc8,
-	0xfd, 0x85, 0xab, 0xde, 0x4e, 0xcb, 0xaf, 0x4e, 0x90, 0x2b, 0x00, 0x47, 0x62, 0x1b, 0x72, 0x89,
-	0x26, 0x2e, 0x61, 0x41, 0x08, 0x85, 0xae, 0x1a, 0xb1, 0x70, 0x72, 0xc2, 0xfb, 0x2d, 0x24, 0xe4,
-	0xc0, 0x04, 0x0d, 0x1e, 0x4e, 0xd9, 0x30, 0xe7, 0xc1, 0x34, 0xed, 0x2f, 0xe2, 0x6e, 0x2c, 0x08,
-	0xce, 0x27, 0x3c, 0x88, 0x86, 0xc7, 0x8c, 0xe5, 0xfd, 0x25, 0x35, 0x6f, 0x20, 0xe4, 0x6d, 0x58,
-	0x19, 0xb3, 0x9c, 0x0f, 0x83, 0xf1, 0x38, 0x63, 0x79, 0xce, 0xf2, 0xfe, 0xf2, 0xd5, 0x85, 0x9d,
-	0xb6, 0x5f, 0x82, 0xd2, 0x3e, 0x6c, 0x3d, 0x64, 0xdc, 0xe2, 0x4e, 0xae, 0x38, 0x4d, 0x1f, 0x01,
-	0xb1, 0xc0, 0xf7, 0x18, 0x0f, 0xc2, 0x28, 0x27, 0xef, 0x41, 0x97, 0x5b, 0xc8, 0x7d, 0xef, 0xea,
-	0xc2, 0x4e, 0x67, 0x8f, 0x5c, 0x47, 0xe9, 0xb8, 0x6e, 0x7d, 0xe0, 0x3b, 0x78, 0xf4, 0x7f, 0x3c,
-	0xe8, 0x1c, 0xb2, 0x78, 0xac, 0xef, 0x91, 0x40, 0x53, 0xec, 0x44, 0xdd, 0x21, 0xfe, 0x26, 0x5f,
-	0x86, 0x0e, 0xee, 0x2e, 0xe7, 0x59, 0x18, 0x4f, 0xf0, 0x0a, 0xda, 0x3e, 0x08, 0xd0, 0x21, 0x42,
-	0xc8, 0x1a, 0x2c, 0x04, 0x53, 0x8e, 0x8c, 0x5f, 0xf0, 0xc5, 0x4f, 0xf2, 0x26, 0x74, 0xd3, 0x60,
-	0x3e, 0x65, 0x31, 0x2f, 0x98, 0xdd, 0xf5, 0x3b, 0x0a, 0xb6, 0x2f, 0xb8, 0x7d, 0x1d, 0x36, 0x6c,
-	0x14, 0x4d, 0xbd, 0x85, 0xd4, 0xd7, 0x2d, 0x4c, 0xb5, 0xc8, 0x3b, 0xb0, 0xaa, 0xf1, 0x33, 0xb9,
-	0x59, 0x64, 0x7f, 0xdb, 0x5f, 0x51, 0x60, 0x7d, 0x84, 0x1d, 0x58, 0x3b, 0x0e, 0xe3, 0x20, 0x1a,
-	0x8e, 0x22, 0x7e, 0x3a, 0x1c, 0xb3, 0x88, 0x07, 0x78, 0x11, 0x2d, 0x7f, 0x05, 0xe1, 0x77, 0x23,
-	0x7e, 0x7a, 0x4f, 0x40, 0xe9, 0x9f, 0x7b, 0xd0, 0x95, 0x87, 0x97, 0x12, 0x49, 0xde, 0x82, 0x9e,
-	0x5e, 0x83, 0x65, 0x59, 0x92, 0x29, 0x39, 0x74, 0x81, 0xe4, 0x1a, 0xac, 0x69, 0x40, 0x9a, 0xb1,
-	0x70, 0x1a, 0x4c, 0x18, 0x32, 0xa5, 0xeb, 0x57, 0xe0, 0x64, 0xaf, 0xa0, 0x98, 0x25, 0x33, 0xce,
-	0x90, 0x49, 0x9d, 0xbd, 0xae, 0xba, 0x18, 0x5f, 0xc0, 0x7c, 0x17, 0x85, 0x7e, 0xd7, 0x83, 0xee,
-	0xdd, 0x93, 0x20, 0x8e, 0x59, 0x74, 0x90, 0x84, 0x31, 0x17, 0x82, 0x79, 0x3c, 0x8b, 0xc7, 0x61,
-	0x3c, 0x19, 0xf2, 0xe7, 0xa1, 0x7e, 0x60, 0x0e, 0x4c, 0x6c, 0xca, 0x1e, 0x0b, 0x76, 0xaa, 0x9b,
-	0xaa, 0xc0, 0x05, 0xbd, 0x64, 0xc6, 0xd3, 0x19, 0x1f, 0x86, 0xf1, 0x98, 0x3d, 0xc7, 0x3d, 0xf5,
-	0x7c, 0x07, 0x46, 0x7f, 0x0d, 0xd6, 0x1e, 0x09, 0x89, 0x8f, 0xc3, 0x78, 0x72, 0x5b, 0x8a, 0xa5,
-	0x78, 0x86, 0xe9, 0xec, 0xe8, 0x19, 0x9b, 0x2b, 0xbe, 0xa8, 0x91, 0x10, 0x9a, 0x93, 0x24, 0xe7,
-	0x6a, 0x3d, 0xfc, 0x4d, 0xff, 0xcd, 0x83, 0x55, 0xc1, 0xdb, 0x8f, 0x83, 0x78, 0xae, 0x6f, 0xe6,
-	0x11, 0x74, 0x05, 0xa9, 0x27, 0xc9, 0x6d, 0xf9, 0x98, 0xa5, 0x90, 0xee, 0x28, 0x5e, 0x94, 0xb0,
-	0xaf, 0xdb, 0xa8, 0xf7, 0x63, 0x9e, 0xcd, 0x7d, 0xe7, 0x6b, 0x21, 0x96, 0x3c, 0xc8, 0x26, 0x8c,
-	0xe3, 0x33, 0x57, 0xcf, 0x1e, 0x24, 0xe8, 0x6e, 0x12, 0x1f, 0x93, 0xab, 0xd0, 0xcd, 0x03, 0x3e,
-	0x4c, 0x59, 0x36, 0x3c, 0x9a, 0x73, 0x86, 0xa2, 0xb5, 0xe0, 0x43, 0x1e, 0xf0, 0x03, 0x96, 0xdd,
-	0x99, 0x73, 0x36, 0xf8, 0x10, 0xd6, 0x2b, 0xab, 0x08, 0x69, 0x2e, 0x8e, 0x28, 0x7e, 0x92, 0x4d,
-	0x68, 0x9d, 0x06, 0xd1, 0x8c, 0x29, 0xed, 0x23, 0x07, 0x1f, 0x34, 0xde, 0xf7, 0xe8, 0xdb, 0xb0,
-	0x56, 0x6c, 0x5b, 0x09, 0x11, 0x81, 0xa6, 0xb9, 0xa5, 0xb6, 0x8f, 0xbf, 0xe9, 0xef, 0x79, 0x12,
-	0xf1, 0x6e, 0x12, 0x9a, 0x97, 0x2c, 0x10, 0xc5, 0x83, 0xd7, 0x88, 0xe2, 0xf7, 0xb9, 0x9a, 0xee,
-	0x27, 0x3f, 0x2c, 0x7d, 0x07, 0xd6, 0xad, 0x2d, 0xbc, 0x64, 0xb3, 0x7f, 0xe5, 0xc1, 0xfa, 0x63,
-	0x76, 0xa6, 0x6e, 0x5d, 0xef, 0xf6, 0x7d, 0x68, 0xf2, 0x79, 0xca, 0x10, 0x73, 0x65, 0xef, 0x2d,
-	0x75, 0x69, 0x15, 0xbc, 0xeb, 0x6a, 0xf8, 0x64, 0x9e, 0x32, 0x1f, 0xbf, 0xa0, 0x9f, 0x40, 0xc7,
-	0x02, 0x92, 0x6d, 0xd8, 0xf8, 0xf4, 0xa3, 0x27, 0x8f, 0xef, 0x1f, 0x1e, 0x0e, 0x0f, 0x9e, 0xde,
-	0xf9, 0xfa, 0xfd, 0xdf, 0x1c, 0xee, 0xdf, 0x3e, 0xdc, 0x5f, 0xbb, 0x40, 0xb6, 0x80, 0x3c, 0xbe,
-	0x7f, 0xf8, 0xe4, 0xfe, 0x3d, 0x07, 0xee, 0x91, 0x55, 0xe8, 0xd8, 0x80, 0x06, 0x1d, 0x40, 0xff,
-	0x31, 0x3b, 0xfb, 0x34, 0xe4, 0x31, 0xcb, 0x73, 0x77, 0x79, 0x7a, 0x1d, 0x88, 0xbd, 0x27, 0x75,
-	0xcc, 0x3e, 0x2c, 0x29, 0xdd, 0xaa, 0x4d, 0x8b, 0x1a, 0xd2, 0xb7, 0x81, 0x1c, 0x86, 0x93, 0xf8,
-	0x63, 0x96, 0xe7, 0xc1, 0x84, 0xe9, 0xc3, 0xae, 0xc1, 0xc2, 0x34, 0x9f, 0xa8, 0x87, 0x26, 0x7e,
-	0xd2, 0xaf, 0xc0, 0x86, 0x83, 0xa7, 0x08, 0x5f, 0x86, 0x76, 0x1e, 0x4e, 0xe2, 0x80, 0xcf, 0x32,
-	0xa6, 0x48, 0x17, 0x00, 0xfa, 0x00, 0x36, 0xbf, 0xc9, 0xb2, 0xf0, 0x78, 0xfe, 0x2a, 0xf2, 0x2e,
-	0x9d, 0x46, 0x99, 0xce, 0x7d, 0xb8, 0x58, 0xa2, 0xa3, 0x96, 0x97, 0x92, 0xa9, 0xee, 0x6f, 0xd9,
-	0x97, 0x03, 0xeb, 0x9d, 0x36, 0xec, 0x77, 0x4a, 0x9f, 0x02, 0xb9, 0x9b, 0xc4, 0x31, 0x1b, 0xf1,
-	0x03, 0xc6, 0x32, 0xbd, 0x99, 0x5f, 0xb0, 0xc4, 0xb0, 0xb3, 0xb7, 0xad, 0x2e, 0xb6, 0xfc, 0xf8,
-	0x95, 0x7c, 0x12, 0x68, 0xa6, 0x2c, 0x9b, 0x22, 0xe1, 0x65, 0x1f, 0x7f, 0xd3, 0x5d, 0xd8, 0x70,
-	0xc8, 0x16, 0x3c, 0x4f, 0x19, 0xcb, 0x86, 0x6a, 0x77, 0x2d, 0x5f, 0x0f, 0xe9, 0x4d, 0xb8, 0x78,
-	0x2f, 0xcc, 0x47, 0xd5, 0xad, 0x88, 0x4f, 0x66, 0x47, 0xc3, 0xe2, 0xf9, 0xe9, 0xa1, 0xb0, 0x87,
-	0xe5, 0x4f, 0x94, 0x17, 0xf1, 0x87, 0x1e, 0x34, 0xf7, 0x9f, 0x3c, 0xba, 0x2b, 0x5c, 0x90, 0x30,
-	0x1e, 0x25, 0x53, 0x61, 0x45, 0x24, 0x3b, 0xcc, 0xf8, 0xdc, 0x67, 0x75, 0x19, 0xda, 0x68, 0x7c,
-	0x84, 0x89, 0xc7, 0x47, 0xd5, 0xf5, 0x0b, 0x80, 0x70, 0x2f, 0xd8, 0xf3, 0x34, 0xcc, 0xd0, 0x7f,
-	0xd0, 0x5e, 0x41, 0x13, 0x95, 0x65, 0x75, 0x82, 0xfe, 0x47, 0x13, 0x7a, 0xb7, 0x47, 0x3c, 0x3c,
-	0x65, 0x4a, 0x79, 0xe3, 0xaa, 0x08, 0x50, 0xfb, 0x51, 0x23, 0x61, 0x66, 0x32, 0x36, 0x4d, 0x38,
-	0x1b, 0x3a, 0xd7, 0xe4, 0x02, 0x05, 0xd6, 0x48, 0x12, 0x1a, 0xa6, 0xc2, 0x0c, 0xe0, 0xfe, 0xda,
-	0xbe, 0x0b, 0x14, 0x2c, 0x13, 0x00, 0xc1, 0x65, 0xb1, 0xb3, 0xa6, 0xaf, 0x87, 0x82, 0x1f, 0xa3,
-	0x20, 0x0d, 0x46, 0x21, 0x9f, 0x2b, 0x6d, 0x60, 0xc6, 0x82, 0x76, 0x94, 0x8c, 0x82, 0x68, 0x78,
-	0x14, 0x44, 0x41, 0x3c, 0x62, 0xca, 0x93, 0x71, 0x81, 0xc2, 0x59, 0x51, 0x5b, 0xd2, 0x68, 0xd2,
-	0xa1, 0x29, 0x41, 0x85, 0xd3, 0x33, 0x4a, 0xa6, 0xd3, 0x90, 0x0b, 0x1f, 0xa7, 0xbf, 0x2c, 0x35,
-	0x4f, 0x01, 0xc1, 0x93, 0xc8, 0xd1, 0x99, 0xe4, 0x61, 0x5b, 0xae, 0xe6, 0x00, 0x05, 0x95, 0x63,
-	0xc6, 0x50, 0x83, 0x3d, 0x3b, 0xeb, 0x83, 0xa4, 0x52, 0x40, 0xc4, 0x6d, 0xcc, 0xe2, 0x9c, 0x71,
-	0x1e, 0xb1, 0xb1, 0xd9, 0x50, 0x07, 0xd1, 0xaa, 0x13, 0xe4, 0x06, 0x6c, 0x48, 0xb7, 0x2b, 0x0f,
-	0x78, 0x92, 0x9f, 0x84, 0xf9, 0x30, 0x67, 0x31, 0xef, 0x77, 0x11, 0xbf, 0x6e, 0x8a, 0xbc, 0x0f,
-	0xdb, 0x25, 0x70, 0xc6, 0x46, 0x2c, 0x3c, 0x65, 0xe3, 0x7e, 0x0f, 0xbf, 0x3a, 0x6f, 0x9a, 0x5c,
-	0x85, 0x8e, 0xf0, 0x36, 0x67, 0xe9, 0x38, 0xe0, 0x2c, 0xef, 0xaf, 0xe0, 0x3d, 0xd8, 0x20, 0x72,
-	0x13, 0x7a, 0x29, 0x93, 0x56, 0xf8, 0x84, 0x47, 0xa3, 0xbc, 0xbf, 0x8a, 0xa6, 0xaf, 0xa3, 0x1e,
-	0x9b, 0x90, 0x5f, 0xdf, 0xc5, 0x10, 0xa2, 0x39, 0xca, 0xd1, 0x7f, 0x09, 0xe6, 0xfd, 0x35, 0x14,
-	0xba, 0x02, 0x40, 0x2f, 0xc2, 0xc6, 0xa3, 0x30, 0xe7, 0x4a, 0xd2, 0x8c, 0xf6, 0xdb, 0x87, 0x4d,
-	0x17, 0xac, 0xde, 0xe2, 0x0d, 0x58, 0x56, 0x62, 0x93, 0xf7, 0x3b, 0xb8, 0xf4, 0xa6, 0x5a, 0xda,
-	0x91, 0x58, 0xdf, 0x60, 0xd1, 0x3f, 0x68, 0x40, 0x53, 0xbc, 0xb3, 0xf3, 0xdf, 0xa4, 0xfd, 0xc0,
-	0x1b, 0xce, 0x03, 0xb7, 0xd5, 0xed, 0x82, 0xa3, 0x6e, 0xd1, 0x07, 0x9f, 0x73, 0xa6, 0x6e, 0x43,
-	0x4a, 0xac, 0x05, 0x29, 0xe6, 0x33, 0x36, 0x3a, 0x45, 0xb1, 0x35, 0xf3, 0x02, 0x22, 0x84, 0x5a,
-	0x98, 0x39, 0xfc, 0x5a, 0xca, 0xac, 0x19, 0xeb, 0x39, 0xfc, 0x72, 0xa9, 0x98, 0xc3, 0xef, 0xfa,
-	0xb0, 0x14, 0xc6, 0x47, 0xc9, 0x2c, 0x1e, 0xa3, 0x7c, 0x2e, 0xfb, 0x7a, 0x28, 0xf8, 0x9c, 0xa2,
-	0x77, 0x14, 0x4e, 0x99, 0x12, 0xcc, 0x02, 0x40, 0x89, 0x70, 0x83, 0x72, 0xd4, 0x38, 0x86, 0xc9,
-	0xef, 0xc1, 0xba, 0x05, 0x53, 0x1c, 0x7e, 0x13, 0x5a, 0xe2, 0xf4, 0xda, 0xf3, 0xd6, 0x37, 0x8b,
-	0xaa, 0x4a, 0xce, 0xd0, 0x35, 0x58, 0x79, 0xc8, 0xf8, 0x47, 0xf1, 0x71, 0xa2, 0x29, 0xfd, 0xd1,
-	0x02, 0xac, 0x1a, 0x90, 0x22, 0xb4, 0x03, 0xab, 0xe1, 0x98, 0xc5, 0x3c, 0xe4, 0xf3, 0xa1, 0xe3,
-	0x6d, 0x95, 0xc1, 0x42, 0xf9, 0x07, 0x51, 0x18, 0xe4, 0x4a, 0x7d, 0xc8, 0x01, 0xd9, 0x83, 0x4d,
-	0x21, 0x79, 0x5a, 0x98, 0xcc, 0xb5, 0x4b, 0x27, 0xaf, 0x76, 0x4e, 0x3c, 0x16, 0x01, 0x97, 0xea,
-	0xa9, 0xf8, 0x44, 0xaa, 0xba, 0xba, 0x29, 0xc1, 0x35, 0x49, 0x49, 0x1c, 0xb9, 0x25, 0xa5, 0xd3,
-	0x00, 0x2a, 0x91, 0xd4, 0xa2, 0x74, 0x30, 0xcb, 0x91, 0x94, 0x15, 0x8d, 0x2d, 0x57, 0xa2, 0xb1,
-	0x1d, 0x58, 0xcd, 0xe7, 0xf1, 0x88, 0x8d, 0x87, 0x3c, 0x11, 0xeb, 0x86, 0x31, 0xde, 0xce, 0xb2,
-	0x5f, 0x06, 0x63, 0xdc, 0xc8, 0x72, 0x1e, 0x33, 0x8e, 0x5a, 0x63, 0xd9, 0xd7, 0x43, 0xa1, 0x80,
-	0x11, 0x45, 0x0a, 0x7d, 0xdb, 0x57, 0x23, 0x61, 0xc5, 0x66, 0x59, 0x98, 0xf7, 0xbb, 0x08, 0xc5,
-	0xdf, 0xf4, 0x3b, 0x68, 0x1c, 0x4d, 0xb8, 0xf8, 0x14, 0x5f, 0x2e, 0x79, 0x03, 0xda, 0x72, 0x4f,
-	0xf9, 0x49, 0xa0, 0x03, 0x5b, 0x04, 0x1c, 0x9e, 0x04, 0x22, 0xca, 0x71, 0x8e, 0x29, 0x5f, 0x41,
-	0x07, 0x61, 0xfb, 0xf2, 0x94, 0x6f, 0xc1, 0x8a, 0x0e, 0x44, 0xf3, 0x61, 0xc4, 0x8e, 0xb9, 0x76,
-	0xb6, 0xe3, 0xd9, 0x54, 0x2c, 0x97, 0x3f, 0x62, 0xc7, 0x9c, 0x3e, 0x86, 0x75, 0xf5, 0x02, 0x3f,
-	0x49, 0x99, 0x5e, 0xfa, 0x6b, 0x65, 0xfd, 0x2f, 0x0d, 0xf4, 0x86, 0x92, 0x2c, 0x3b, 0x42, 0x28,
-	0x19, 0x05, 0xea, 0x03, 0x51, 0xd3, 0x77, 0xa3, 0x24, 0x67, 0x8a, 0x20, 0x85, 0xee, 0x28, 0x4a,
-	0xf2, 0x72, 0x18, 0x61, 0xc3, 0x04, 0x2f, 0xf3, 0xd9, 0x68, 0x24, 0x5e, 0xae, 0x34, 0xf1, 0x7a,
-	0x48, 0xff, 0xc6, 0x83, 0x0d, 0xa4, 0xa6, 0x75, 0x85, 0xf1, 0x0b, 0x5f, 0x7f, 0x9b, 0xdd, 0x91,
-	0x1d, 0xd6, 0x6c, 0x42, 0xeb, 0x38, 0xc9, 0x46, 0x4c, 0xad, 0x24, 0x07, 0x3f, 0x0d, 0x4f, 0xf7,
-	0x9f, 0x3c, 0x58, 0xc7, 0xad, 0x1e, 0xf2, 0x80, 0xcf, 0x72, 0x75, 0xfc, 0x5f, 0x81, 0x9e, 0x38,
-	0x2a, 0xd3, 0xe2, 0xaf, 0x36, 0xba, 0x69, 0x5e, 0x2a, 0x42, 0x25, 0xf2, 0xfe, 0x05, 0xdf, 0x45,
-	0x26, 0x1f, 0x42, 0xd7, 0xce, 0x26, 0xe0, 0x9e, 0x3b, 0x7b, 0x97, 0xf4, 0x29, 0x2b, 0x92, 0xb3,
-	0x7f, 0xc1, 0x77, 0x3e, 0x20, 0xb7, 0x00, 0xd0, 0x32, 0x23, 0x59, 0x15, 0x06, 0x5e, 0x72, 0x99,
-	0x64, 0x5d, 0xd6, 0xfe, 0x05, 0xdf, 0x42, 0xbf, 0xb3, 0x0c, 0x8b, 0xd2, 0x94, 0xd0, 0x87, 0xd0,
-	0x73, 0x76, 0xea, 0x78, 0xf0, 0x5d, 0xe9, 0xc1, 0x57, 0x02, 0xbc, 0x46, 0x4d, 0x80, 0xf7, 0xaf,
-	0x0d, 0x20, 0x42, 0xda, 0x4a, 0xd7, 0xf9, 0x36, 0xac, 0x28, 0xf6, 0xbb, 0xce, 0x5b, 0x09, 0x8a,
-	0x36, 0x2f, 0x19, 0x3b, 0x1e, 0x4c, 0xd7, 0xb7, 0x41, 0xe4, 0x3a, 0x10, 0x6b, 0xa8, 0xe3, 0x7b,
-	0x69, 0x0f, 0x6a, 0x66, 0x84, 0xe2, 0x92, 0xee, 0x87, 0x8e, 0x57, 0x95, 0xc7, 0xd6, 0xc4, 0xfb,
-	0xad, 0x9d, 0xc3, 0xb4, 0xd3, 0x2c, 0x3f, 0x11, 0x36, 0x59, 0xfb, 0x38, 0x7a, 0x5c, 0x16, 0xa4,
-	0xc5, 0x57, 0x0a, 0xd2, 0x52, 0x59, 0x90, 0xd0, 0xc2, 0x65, 0xe1, 0x69, 0xc0, 0x99, 0xb6, 0x1a,
-	0x6a, 0x28, 0x5c, 0x9a, 0x69, 0x18, 0xa3, 0xa9, 0x1e, 0x4e, 0xc5, 0xea, 0xca, 0xa5, 0x71, 0x80,
-	0xf4, 0x47, 0x1e, 0xac, 0x09, 0x1e, 0x3b, 0x72, 0xf8, 0x01, 0xe0, 0x33, 0x78, 0x4d, 0x31, 0x74,
-	0x70, 0x7f, 0x72, 0x29, 0x7c, 0x1f, 0xda, 0x48, 0x30, 0x49, 0x59, 0xac, 0x84, 0xb0, 0xef, 0x0a,
-	0x61, 0xa1, 0x81, 0xf6, 0x2f, 0xf8, 0x05, 0xb2, 0x25, 0x82, 0xff, 0xe8, 0x41, 0x47, 0x6d, 0xf3,
-	0xff, 0xec, 0x78, 0x0f, 0x60, 0x59, 0x48, 0xa3, 0xe5, 0xd7, 0x9a, 0xb1, 0xd0, 0xfc, 0x53, 0x11,
-	0xf7, 0x08, 0x53, 0xe7, 0x38, 0xdd, 0x65, 0xb0, 0xb0, 0x5b, 0xa8, 0x6c, 0xf3, 0x21, 0x0f, 0xa3,
-	0xa1, 0x9e, 0x55, 0x89, 0xbb, 0xba, 0x29, 0xa1, 0x73, 0x72, 0x1e, 0x4c, 0x98, 0x32, 0x49, 0x72,
-	0x20, 0xa2, 0x0b, 0x75, 0xa0, 0xb2, 0x43, 0xf5, 0x43, 0x80, 0xed, 0xca, 0x94, 0x71, 0xaa, 0x94,
-	0x1f, 0x19, 0x85, 0xd3, 0xa3, 0xc4, 0xb8, 0xa4, 0x9e, 0xed, 0x62, 0x3a, 0x53, 0x64, 0x02, 0x17,
-	0xb5, 0xed, 0x15, 0x3c, 0x2d, 0x2c, 0x6d, 0x03, 0x9d, 0x86, 0x9b, 0xae, 0x0c, 0x94, 0x17, 0xd4,
-	0x70, 0xfb, 0xd5, 0xd6, 0xd3, 0x23, 0x27, 0xd0, 0x37, 0x46, 0x5e, 0xa9, 0x77, 0xcb, 0x11, 0x10,
-	0x6b, 0xbd, 0xfb, 0x8a, 0xb5, 0x50, 0x17, 0x8d, 0xf5, 0x32, 0xe7, 0x52, 0x23, 0x73, 0xb8, 0xa2,
-	0xe7, 0x50, 0x7f, 0x57, 0xd7, 0x6b, 0xbe, 0xd6, 0xd9, 0x1e, 0x88, 0x8f, 0xdd, 0x45, 0x5f, 0x41,
-	0x78, 0xf0, 0x43, 0x0f, 0x56, 0x5c, 0x72, 0x42, 0x74, 0x54, 0x6c, 0xa2, 0x15, 0x8c, 0x76, 0x9e,
-	0x4a, 0xe0, 0x6a, 0x74, 0xd5, 0xa8, 0x8b, 0xae, 0xec, 0x18, 0x6a, 0xe1, 0x55, 0x31, 0x54, 0xf3,
-	0xf5, 0x62, 0xa8, 0x56, 0x5d, 0x0c, 0x35, 0xf8, 0x6f, 0x0f, 0x48, 0xf5, 0x7e, 0xc9, 0x43, 0x19,
-	0xde, 0xc5, 0x2c, 0x52, 0x7a, 0xe2, 0x17, 0x5f, 0x4f, 0x46, 0x34, 0x0f, 0xf5, 0xd7, 0x42, 0x58,
-	0x6d, 0x45, 0x60, 0xbb, 0x2c, 0x3d, 0xbf, 0x6e, 0xaa, 0x14, 0xd5, 0x35, 0x5f, 0x1d, 0xd5, 0xb5,
-	0x5e, 0x1d, 0xd5, 0x2d, 0x96, 0xa3, 0xba, 0xc1, 0xef, 0x40, 0xcf, 0xb9, 0xf5, 0x9f, 0xde, 0x89,
-	0xcb, 0xee, 0x8e, 0xbc, 0x60, 0x07, 0x36, 0xf8, 0xcf, 0x06, 0x90, 0xaa, 0xe4, 0xfd, 0x4c, 0xf7,
-	0x80, 0x72, 0xe4, 0x28, 0x90, 0x05, 0x25, 0x47, 0x8e, 0xea, 0xf8, 0xff, 0x54, 0x8a, 0xef, 0xc2,
-	0x7a, 0xc6, 0x46, 0xc9, 0x29, 0xcb, 0xac, 0xc8, 0x5a, 0x5e, 0x55, 0x75, 0x42, 0x38, 0x7c, 0x6e,
-	0x2c, 0xbb, 0xec, 0xd4, 0x1a, 0x2c, 0xcb, 0x50, 0x0a, 0x69, 0xe9, 0xd7, 0x60, 0x53, 0x96, 0x80,
-	0xee, 0x48, 0x52, 0xda, 0xe7, 0x78, 0x13, 0xba, 0x67, 0x32, 0x99, 0x37, 0x4c, 0xe2, 0x68, 0xae,
-	0x8c, 0x48, 0x47, 0xc1, 0x3e, 0x89, 0xa3, 0x39, 0xfd, 0xbe, 0x07, 0x17, 0x4b, 0xdf, 0x16, 0x39,
-	0x7b, 0xa9, 0x6a, 0x5d, 0xfd, 0xeb, 0x02, 0xc5, 0x11, 0x95, 0x8c, 0x5b, 0x47, 0x94, 0x26, 0xa9,
-	0x3a, 0x21, 0x58, 0x38, 0x8b, 0xab, 0xf8, 0xf2, 0x62, 0xea, 0xa6, 0xe8, 0x36, 0x5c, 0x54, 0x97,
-	0xef, 0x9e, 0x8d, 0xee, 0xc1, 0x56, 0x79, 0xa2, 0xc8, 0x8f, 0xb9, 0x5b, 0xd6, 0x43, 0xfa, 0x21,
-	0x90, 0x6f, 0xcc, 0x58, 0x36, 0xc7, 0xea, 0x80, 0x49, 0xc0, 0x6e, 0x97, 0x03, 0xf1, 0xc5, 0x74,
-	0x76, 0xf4, 0x75, 0x36, 0xd7, 0xe5, 0x97, 0x86, 0x29, 0xbf, 0xd0, 0x5b, 0xb0, 0xe1, 0x10, 0x30,
-	0xac, 0x5a, 0xc4, 0x0a, 0x83, 0x0e, 0x52, 0xdd, 0x2a, 0x84, 0x9a, 0xa3, 0x7f, 0xe9, 0xc1, 0xc2,
-	0x7e, 0x92, 0xda, 0x99, 0x25, 0xcf, 0xcd, 0x2c, 0x29, 0xdd, 0x39, 0x34, 0xaa, 0xb1, 0xa1, 0x5e,
-	0xbe, 0x0d, 0x14, 0x9a, 0x2f, 0x98, 0x72, 0x11, 0xa6, 0x1d, 0x27, 0xd9, 0x59, 0x90, 0x8d, 0x15,
-	0xff, 0x4a, 0x50, 0xb1, 0xfd, 0x42, 0xc1, 0x88, 0x9f, 0xc2, 0x69, 0xc0, 0xf4, 0xda, 0x5c, 0x45,
-	0x96, 0x6a, 0x44, 0xff, 0xd4, 0x83, 0x16, 0xee, 0x55, 0xbc, 0x06, 0x79, 0xbf, 0x58, 0x7a, 0xc3,
-	0xec, 0x9d, 0x27, 0x5f, 0x43, 0x09, 0x5c, 0x2a, 0xc8, 0x35, 0x2a, 0x05, 0xb9, 0xcb, 0xd0, 0x96,
-	0xa3, 0xa2, 0x82, 0x55, 0x00, 0xc8, 0x15, 0x68, 0x9e, 0x24, 0xa9, 0xb6, 0x61, 0xa0, 0xd3, 0x35,
-	0x49, 0xea, 0x23, 0x9c, 0x5e, 0x83, 0xd5, 0xc7, 0xc9, 0x98, 0x59, 0x31, 0xfd, 0xb9, 0xd7, 0x44,
-	0x7f, 0xd7, 0x83, 0x65, 0x8d, 0x4c, 0x76, 0xa0, 0x29, 0x4c, 0x51, 0xc9, 0xf9, 0x33, 0x49, 0x57,
-	0x81, 0xe7, 0x23, 0x86, 0x50, 0x21, 0x18, 0x41, 0x16, 0xae, 0x82, 0x8e, 0x1f, 0x0b, 0x23, 0x2c,
-	0x9c, 0x76, 0xdc, 0x73, 0xc9, 0x58, 0x95, 0xa0, 0xf4, 0x6f, 0x3d, 0xe8, 0x39, 0x6b, 0x08, 0x37,
-	0x3e, 0x0a, 0x72, 0xae, 0x12, 0x55, 0x8a, 0x89, 0x36, 0xc8, 0xce, 0xff, 0x34, 0xdc, 0xfc, 0x8f,
-	0xc9, 0x3f, 0x2c, 0xd8, 0xf9, 0x87, 0x1b, 0xd0, 0x2e, 0x8a, 0x9b, 0x4d, 0x47, 0x35, 0x88, 0x15,
-	0x75, 0x3a, 0xb9, 0x40, 0x12, 0x74, 0x46, 0x49, 0x94, 0x64, 0xaa, 0xf6, 0x27, 0x07, 0xf4, 0x16,
-	0x74, 0x2c, 0x7c, 0xb1, 0x8d, 0x98, 0xf1, 0xb3, 0x24, 0x7b, 0xa6, 0xd3, 0x50, 0x6a, 0x68, 0xca,
-	0x28, 0x8d, 0xa2, 0x8c, 0x42, 0xff, 0xce, 0x83, 0x9e, 0x90, 0x94, 0x30, 0x9e, 0x1c, 0x24, 0x51,
-	0x38, 0x9a, 0xa3, 0xc4, 0x68, 0xa1, 0x50, 0x45, 0x41, 0x2d, 0x31, 0x2e, 0x58, 0xd8, 0x7c, 0xed,
-	0xc5, 0x2b, 0x79, 0x31, 0x63, 0x21, 0xf9, 0xc2, 0x76, 0x1d, 0x05, 0x39, 0x93, 0x6e, 0xbf, 0xd2,
-	0xd5, 0x0e, 0x50, 0xa8, 0x0f, 0x01, 0xc8, 0x02, 0xce, 0x86, 0xd3, 0x30, 0x8a, 0x42, 0x89, 0x2b,
-	0x25, 0xbc, 0x6e, 0x8a, 0xfe, 0xa0, 0x01, 0x1d, 0xa5, 0x26, 0xee, 0x8f, 0x27, 0x32, 0xa3, 0xaa,
-	0x1c, 0x11, 0xf3, 0xfc, 0x2c, 0x88, 0x9e, 0x77, 0x5c, 0x17, 0x0b, 0x52, 0xbe, 0xd6, 0x85, 0xea,
-	0xb5, 0x5e, 0x86, 0xb6, 0x10, 0xaf, 0x9b, 0xe8, 0x23, 0xc9, 0x5a, 0x78, 0x01, 0xd0, 0xb3, 0x7b,
-	0x38, 0xdb, 0x2a, 0x66, 0x11, 0xe0, 0x78, 0x45, 0x8b, 0x25, 0xaf, 0xe8, 0x7d, 0xe8, 0x2a, 0x32,
-	0xc8, 0x77, 0x0c, 0xaa, 0x0a, 0x01, 0x77, 0xee, 0xc4, 0x77, 0x30, 0xf5, 0x97, 0x7b, 0xfa, 0xcb,
-	0xe5, 0x57, 0x7d, 0xa9, 0x31, 0xe9, 0x45, 0xd8, 0x50, 0xcc, 0x7b, 0x98, 0x05, 0xe9, 0x89, 0x56,
-	0xbd, 0x63, 0x53, 0x46, 0x45, 0x30, 0xb9, 0x06, 0x2d, 0xf1, 0x99, 0xd6, 0x7e, 0xf5, 0x8f, 0x4e,
-	0xa2, 0x90, 0x1d, 0x68, 0xb1, 0xf1, 0x84, 0x69, 0xcf, 0x9c, 0xb8, 0x31, 0x92, 0xb8, 0x23, 0x5f,
-	0x22, 0x08, 0x15, 0x20, 0xa0, 0x25, 0x15, 0xe0, 0x6a, 0xce, 0x45, 0x31, 0xfc, 0x68, 0x4c, 0x37,
-	0x81, 0x3c, 0x96, 0x52, 0x6b, 0x67, 0x01, 0x7f, 0x7f, 0x01, 0x3a, 0x16, 0x58, 0xbc, 0xe6, 0x89,
-	0xd8, 0xf0, 0x70, 0x1c, 0x06, 0x53, 0xc6, 0x59, 0xa6, 0x24, 0xb5, 0x04, 0x45, 0x05, 0x7b, 0x3a,
-	0x19, 0x26, 0x33, 0x3e, 0x1c, 0xb3, 0x49, 0xc6, 0xa4, 0x41, 0xf3, 0xfc, 0x12, 0x54, 0xe0, 0x4d,
-	0x83, 0xe7, 0x36, 0x9e, 0x94, 0x87, 0x12, 0x54, 0xe7, 0xf4, 0x24, 0x8f, 0x9a, 0x45, 0x4e, 0x4f,
-	0x72, 0xa4, 0xac, 0x87, 0x5a, 0x35, 0x7a, 0xe8, 0x3d, 0xd8, 0x92, 0x1a, 0x47, 0xbd, 0xcd, 0x61,
-	0x49, 0x4c, 0xce, 0x99, 0x25, 0xd7, 0x60, 0x4d, 0xec, 0x59, 0x0b, 0x78, 0x1e, 0x7e, 0x47, 0x46,
-	0xe3, 0x9e, 0x5f, 0x81, 0x0b, 0x5c, 0xf1, 0x1c, 0x1d, 0x5c, 0x59, 0x72, 0xa8, 0xc0, 0x11, 0x37,
-	0x78, 0xee, 0xe2, 0xb6, 0x15, 0x6e, 0x09, 0x4e, 0x7b, 0xd0, 0x39, 0xe4, 0x49, 0xaa, 0x2f, 0x65,
-	0x05, 0xba, 0x72, 0xa8, 0xca, 0x4c, 0x6f, 0xc0, 0x25, 0x94, 0xa2, 0x27, 0x49, 0x9a, 0x44, 0xc9,
-	0x64, 0x7e, 0x38, 0x3b, 0xca, 0x47, 0x59, 0x98, 0x0a, 0x8f, 0x99, 0xfe, 0x83, 0x07, 0x1b, 0xce,
-	0xac, 0x0a, 0xf5, 0x7f, 0x49, 0x8a, 0xb4, 0xa9, 0x0c, 0x48, 0xc1, 0x5b, 0xb7, 0xd4, 0xa1, 0x44,
-	0x94, 0x89, 0x93, 0xa7, 0xaa, 0x58, 0x70, 0x1b, 0x56, 0xf5, 0xce, 0xf4, 0x87, 0x52, 0x0a, 0xfb,
-	0x55, 0x29, 0x54, 0xdf, 0xaf, 0xa8, 0x0f, 0x34, 0x89, 0x5f, 0x95, 0x7e, 0x27, 0x1b, 0xe3, 0x19,
-	0x75, 0xcc, 0x37, 0xd0, 0xdf, 0xdb, 0xce, 0xae, 0xde, 0xc1, 0xc8, 0x00, 0x73, 0xfa, 0xc7, 0x1e,
-	0x40, 0xb1, 0x3b, 0x21, 0x18, 0x85, 0x4a, 0xf7, 0x30, 0x67, 0x6a, 0xa9, 0xef, 0x37, 0xa1, 0x6b,
-	0x32, 0xd3, 0x85, 0x95, 0xe8, 0x68, 0x98, 0xf0, 0x50, 0xde, 0x81, 0xd5, 0x49, 0x94, 0x1c, 0xa1,
-	0xcd, 0xc5, 0x8a, 0x66, 0xae, 0x8a, 0x6d, 0x2b, 0x12, 0xfc, 0x40, 0x41, 0x0b, 0x93, 0xd2, 0xb4,
-	0x4c, 0x0a, 0xfd, 0x93, 0x86, 0xc9, 0x8f, 0x16, 0x67, 0x3e, 0xf7, 0x95, 0x91, 0xbd, 0x8a, 0x72,
-	0x3c, 0x27, 0x1d, 0x89, 0xd9, 0x8d, 0x83, 0x57, 0x06, 0x7a, 0xb7, 0x60, 0x25, 0x93, 0xda, 0x47,
-	0xab, 0xa6, 0xe6, 0x4b, 0x54, 0x53, 0x2f, 0x73, 0xec, 0xce, 0xcf, 0xc3, 0x5a, 0x30, 0x3e, 0x65,
-	0x19, 0x0f, 0xd1, 0xe3, 0x47, 0xa3, 0x2f, 0x15, 0xea, 0xaa, 0x05, 0x47, 0x5b, 0xfc, 0x0e, 0xac,
-	0xaa, 0x02, 0xa7, 0xc1, 0x54, 0x1d, 0x2e, 0x05, 0x58, 0x20, 0xd2, 0xbf, 0xd6, 0xa9, 0x58, 0xf7,
-	0x0e, 0xcf, 0xe7, 0x88, 0x7d, 0xba, 0x46, 0xe9, 0x74, 0x3f, 0xa7, 0xd2, 0xa2, 0x63, 0x1d, 0x56,
-	0xa8, 0x04, 0xb5, 0x04, 0xaa, 0x34, 0xb6, 0xcb, 0xd2, 0xe6, 0xeb, 0xb0, 0x94, 0x7e, 0x7f, 0x01,
-	0x96, 0x3e, 0x8a, 0x4f, 0x93, 0x70, 0x84, 0x49, 0xca, 0x29, 0x9b, 0x26, 0xba, 0xcd, 0x40, 0xfc,
-	0x16, 0x16, 0x1d, 0x2b, 0x68, 0x29, 0x57, 0xd9, 0x43, 0x3d, 0x14, 0xd6, 0x2d, 0x2b, 0x5a, 0x6b,
-	0xa4, 0xa4, 0x58, 0x10, 0xe1, 0x1f, 0x66, 0x76, 0x5f, 0x91, 0x1a, 0x15, 0x7d, 0x1a, 0x2d, 0xab,
-	0x4f, 0x03, 0x53, 0xda, 0xb2, 0x38, 0x88, 0xec, 0x5c, 0xf6, 0xf5, 0x10, 0xfd, 0xd8, 0x8c, 0xc9,
-	0xa0, 0x17, 0xed, 0xe4, 0x92, 0xf2, 0x63, 0x6d, 0xa0, 0xb0, 0xa5, 0xf2, 0x03, 0x89, 0x23, 0x75,
-	0x8d, 0x0d, 0x12, 0xbe, 0x45, 0xb9, 0x35, 0xa9, 0x2d, 0xaf, 0xb8, 0x04, 0x16, 0x0a, 0x69, 0xcc,
-	0x8c, 0xde, 0x90, 0x67, 0x00, 0xd9, 0x3a, 0x54, 0x86, 0x5b, 0x5e, 0xb0, 0x2c, 0x72, 0xaa, 0x11,
-	0xfa, 0x20, 0x41, 0x14, 0x1d, 0x05, 0xa3, 0x67, 0xd8, 0x30, 0x86, 0x35, 0xcd, 0xb6, 0xef, 0x02,
-	0xc5, 0xae, 0xb1, 0xff, 0x49, 0x91, 0xe8, 0xc9, 0x9a, 0xa4, 0x05, 0xa2, 0xdf, 0x04, 0x72, 0x7b,
-	0x3c, 0x56, 0x37, 0x64, 0x62, 0x84, 0x82, 0xb7, 0x9e, 0xc3, 0xdb, 0x9a, 0x33, 0x36, 0x6a, 0xcf,
-	0x48, 0xef, 0x43, 0xe7, 0xc0, 0xea, 0xf3, 0xc2, 0xcb, 0xd4, 0x1d, 0x5e, 0x4a, 0x00, 0x2c, 0x88,
-	0xb5, 0x60, 0xc3, 0x5e, 0x90, 0xfe, 0x32, 0x90, 0x47, 0x61, 0xce, 0xcd, 0xfe, 0x4c, 0xa8, 0x68,
-	0x32, 0x5e, 0x56, 0xa8, 0xa8, 0x60, 0x18, 0x2a, 0xde, 0x96, 0xa5, 0xd1, 0xf2, 0xc1, 0xae, 0xc1,
-	0x72, 0x28, 0x41, 0x5a, 0x0f, 0xaf, 0x28, 0x01, 0xd6, 0x98, 0x66, 0x5e, 0x38, 0x14, 0x0a, 0xe8,
-	0xa8, 0xf9, 0x1f, 0x78, 0xb0, 0xa4, 0x8e, 0x26, 0xcc, 0xa1, 0xd3, 0xe1, 0x26, 0x0f, 0xe6, 0xc0,
-	0xea, 0xfb, 0x86, 0xaa, 0x52, 0xb7, 0x50, 0x27, 0x75, 0x04, 0x9a, 0x69, 0xc0, 0x4f, 0xd0, 0x83,
-	0x6e, 0xfb, 0xf8, 0x5b, 0x47, 0x4a, 0xad, 0x22, 0x52, 0xaa, 0x6b, 0x45, 0x93, 0x3a, 0xa3, 0x02,
-	0xd7, 0x25, 0x63, 0x75, 0x00, 0x93, 0xe1, 0xbc, 0x23, 0x4b, 0xc6, 0x05, 0xb8, 0xe0, 0x97, 0x22,
-	0x51, 0xe6, 0x97, 0x42, 0xf5, 0xcd, 0x3c, 0x1d, 0x40, 0xff, 0x1e, 0x8b, 0x18, 0x67, 0xb7, 0xa3,
-	0xa8, 0x4c, 0xff, 0x0d, 0xb8, 0x54, 0x33, 0xa7, 0xac, 0xea, 0x03, 0x58, 0xbf, 0xc7, 0x8e, 0x66,
-	0x93, 0x47, 0xec, 0xb4, 0x28, 0x41, 0x10, 0x68, 0xe6, 0x27, 0xc9, 0x99, 0xba, 0x5b, 0xfc, 0x4d,
-	0xbe, 0x04, 0x10, 0x09, 0x9c, 0x61, 0x9e, 0xb2, 0x91, 0x6e, 0x90, 0x41, 0xc8, 0x61, 0xca, 0x46,
-	0xf4, 0x3d, 0x20, 0x36, 0x1d, 0x75, 0x04, 0xf1, 0x72, 0x67, 0x47, 0xc3, 0x7c, 0x9e, 0x73, 0x36,
-	0xd5, 0x9d, 0x3f, 0x36, 0x88, 0xbe, 0x03, 0xdd, 0x83, 0x60, 0xee, 0xb3, 0xcf, 0x55, 0x93, 0xa1,
-	0x08, 0xde, 0x82, 0xb9, 0x10, 0x65, 0x13, 0xbc, 0xe1, 0x34, 0xfd, 0xfb, 0x06, 0x2c, 0x4a, 0x4c,
-	0x41, 0x75, 0xcc, 0x72, 0x1e, 0xc6, 0x32, 0x05, 0xaf, 0xa8, 0x5a, 0xa0, 0x8a, 0x6c, 0x34, 0x6a,
-	0x64, 0x43, 0xb9, 0x53, 0xba, 0x99, 0x40, 0x09, 0x81, 0x03, 0xc3, 0xd8, 0x34, 0x9c, 0x32, 0xd9,
-	0x6b, 0xda, 0x54, 0xb1, 0xa9, 0x06, 0x94, 0xa2, 0xe4, 0x42, 0x3f, 0xc8, 0xfd, 0x69, 0xa1, 0x55,
-	0xe2, 0x60, 0x83, 0x6a, 0xb5, 0xd0, 0x92, 0x94, 0x9a, 0x8a, 0x16, 0xaa, 0x68, 0x9b, 0xe5, 0xd7,
-	0xd0, 0x36, 0xd2, 0xc7, 0x72, 0xb4, 0x0d, 0x81, 0xb5, 0x07, 0x8c, 0xf9, 0x2c, 0x4d, 0x32, 0xdd,
-	0xa9, 0x49, 0xbf, 0xe7, 0xc1, 0x9a, 0xb2, 0x1e, 0x66, 0x8e, 0xbc, 0xe9, 0x98, 0x1a, 0xaf, 0x2e,
-	0x2b, 0xfb, 0x16, 0xf4, 0x30, 0xd8, 0x12, 0x91, 0x14, 0x46, 0x56, 0x2a, 0xff, 0xe0, 0x00, 0xc5,
-	0x9e, 0x74, 0x9e, 0x71, 0x1a, 0x46, 0x8a, 0xc1, 0x36, 0x48, 0x98, 0x45, 0x1d, 0x8c, 0x21, 0x7b,
-	0x3d, 0xdf, 0x8c, 0xe9, 0x01, 0xac, 0x5b, 0xfb, 0x55, 0x02, 0x75, 0x0b, 0x74, 0x05, 0x53, 0xa6,
-	0x13, 0xe4, 0xbb, 0xd8, 0x76, 0x0d, 0x61, 0xf1, 0x99, 0x83, 0x4c, 0xff, 0xd9, 0x83, 0x0d, 0xe9,
-	0x14, 0x28, 0x97, 0xcb, 0x34, 0x3d, 0x2d, 0x4a, 0x2f, 0x48, 0x0a, 0xfc, 0xfe, 0x05, 0x5f, 0x8d,
-	0xc9, 0x57, 0x5f, 0xd3, 0x91, 0x31, 0xc5, 0xc2, 0x73, 0xd8, 0xb3, 0x50, 0xc7, 0x9e, 0x97, 0x1c,
-	0xbe, 0x2e, 0x58, 0x6e, 0xd5, 0x06, 0xcb, 0x77, 0x96, 0xa0, 0x95, 0x8f, 0x92, 0x94, 0xd1, 0x2d,
-	0xd8, 0x74, 0x0f, 0x27, 0x59, 0xb6, 0xf7, 0x2f, 0x1e, 0xac, 0xc8, 0xc4, 0x9d, 0xec, 0x01, 0x67,
-	0x19, 0x11, 0x71, 0x99, 0xd5, 0x5a, 0x4e, 0x8c, 0x5b, 0x5a, 0x6d, 0x51, 0x1f, 0xbc, 0x51, 0x3b,
-	0xa7, 0x7d, 0xf2, 0xef, 0xfe, 0xe8, 0xdf, 0xff, 0xac, 0x71, 0x91, 0xae, 0xed, 0x9e, 0xde, 0xdc,
-	0x45, 0xf5, 0xc9, 0xce, 0x10, 0xe3, 0x03, 0xef, 0x9a, 0x58, 0xc5, 0xee, 0x3a, 0x37, 0xab, 0xd4,
-	0x74, 0xaf, 0x9b, 0x55, 0x6a, 0xdb, 0xd4, 0x9d, 0x55, 0x66, 0x88, 0x61, 0x56, 0xd9, 0xfb, 0xaf,
-	0x01, 0xb4, 0x4d, 0x00, 0x49, 0xbe, 0x0d, 0x3d, 0x27, 0x49, 0x49, 0x34, 0xe1, 0xba, 0xb4, 0xe7,
-	0xe0, 0x72, 0xfd, 0xa4, 0x5a, 0xf6, 0x0a, 0x2e, 0xdb, 0x27, 0x5b, 0x62, 0x59, 0x95, 0x19, 0xdc,
-	0xc5, 0xec, 0xad, 0xec, 0x7a, 0x78, 0x06, 0x2b, 0x6e, 0x62, 0x91, 0x5c, 0x76, 0x45, 0xa3, 0xb4,
-	0xda, 0x97, 0xce, 0x99, 0x55, 0xcb, 0x5d, 0xc6, 0xe5, 0xb6, 0xc8, 0xa6, 0xbd, 0x9c, 0x09, 0xec,
-	0x18, 0xf6, 0xa9, 0xd8, 0xed, 0xe8, 0x44, 0xd3, 0xab, 0x6f, 0x53, 0x1f, 0x5c, 0xaa, 0xb6, 0x9e,
-	0xab, 0x5e, 0x75, 0xda, 0xc7, 0xa5, 0x08, 0x41, 0x86, 0xda, 0xdd, 0xe8, 0xe4, 0x33, 0x68, 0x9b,
-	0x16, 0x55, 0xb2, 0x6d, 0xf5, 0x05, 0xdb, 0x7d, 0xb3, 0x83, 0x7e, 0x75, 0xa2, 0xee, 0xaa, 0x6c,
-	0xca, 0x42, 0x20, 0x1e, 0xc1, 0x45, 0x65, 0xcd, 0x8f, 0xd8, 0x8f, 0x73, 0x92, 0x9a, 0x26, 0xfa,
-	0x1b, 0x1e, 0xb9, 0x05, 0xcb, 0xba, 0xf3, 0x97, 0x6c, 0xd5, 0x77, 0x30, 0x0f, 0xb6, 0x2b, 0x70,
-	0xa5, 0x47, 0x6e, 0x03, 0x14, 0x4d, 0xaa, 0xa4, 0x7f, 0x5e, 0x2f, 0xad, 0x61, 0x62, 0x4d, 0x47,
-	0xeb, 0x04, 0x7b, 0x74, 0xdd, 0x1e, 0x58, 0xf2, 0xe5, 0x02, 0xbf, 0xb6, 0x3b, 0xf6, 0x25, 0x04,
-	0xe9, 0x16, 0xf2, 0x6e, 0x8d, 0xac, 0x08, 0xde, 0xc5, 0xec, 0x4c, 0x77, 0x6c, 0xdd, 0x83, 0x8e,
-	0xd5, 0xf8, 0x4a, 0x34, 0x85, 0x6a, 0xd3, 0xec, 0x60, 0x50, 0x37, 0xa5, 0xb6, 0xfb, 0xeb, 0xd0,
-	0x73, 0x3a, 0x58, 0xcd, 0xcb, 0xa8, 0xeb, 0x8f, 0x35, 0x2f, 0xa3, 0xbe, 0xe9, 0xf5, 0x5b, 0xd0,
-	0xb1, 0xfa, 0x4d, 0x89, 0x55, 0xfd, 0x2e, 0xf5, 0x93, 0x9a, 0x1d, 0xd5, 0xb4, 0xa7, 0xd2, 0x4d,
-	0x3c, 0xef, 0x0a, 0x6d, 0x8b, 0xf3, 0x62, 0xdb, 0x92, 0x10, 0x92, 0x6f, 0xc3, 0x8a, 0xdb, 0x67,
-	0x6a, 0x5e, 0x55, 0x6d, 0xc7, 0xaa, 0x79, 0x55, 0xe7, 0x34, 0xa7, 0x2a, 0x81, 0xbc, 0xb6, 0x61,
-	0x16, 0xd9, 0xfd, 0x42, 0xa5, 0x4f, 0x5f, 0x90, 0x6f, 0x08, 0xd5, 0xa1, 0xfa, 0xc8, 0x48, 0xd1,
-	0x77, 0xeb, 0x76, 0x9b, 0x19, 0x69, 0xaf, 0xb4, 0x9c, 0xd1, 0x75, 0x24, 0xde, 0x21, 0xc5, 0x09,
-	0xc8, 0xc7, 0xb0, 0xa4, 0xfa, 0xc9, 0xc8, 0xc5, 0x42, 0xaa, 0xad, 0x64, 0xd3, 0x60, 0xab, 0x0c,
-	0x56, 0xc4, 0x36, 0x90, 0x58, 0x8f, 0x74, 0x04, 0xb1, 0x09, 0xe3, 0xa1, 0xa0, 0x11, 0xc3, 0x6a,
-	0xa9, 0xe2, 0x65, 0x1e, 0x4b, 0x7d, 0xbd, 0x7c, 0x70, 0xe5, 0xe5, 0x85, 0x32, 0x57, 0xcd, 0x68,
-	0xf5, 0xb2, 0xab, 0xdb, 0x1b, 0x7e, 0x0b, 0xba, 0x76, 0xfb, 0xa2, 0xd1, 0xd9, 0x35, 0xad, 0x8e,
-	0x46, 0x67, 0xd7, 0xf5, 0x3b, 0xea, 0xcb, 0x25, 0x5d, 0x7b, 0x19, 0xf2, 0x2d, 0x58, 0xb5, 0x6a,
-	0xab, 0x87, 0xf3, 0x78, 0x64, 0x84, 0xa7, 0xda, 0x09, 0x33, 0xa8, 0xb3, 0xb4, 0x74, 0x1b, 0x09,
-	0xaf, 0x53, 0x87, 0xb0, 0x10, 0x9c, 0xbb, 0xd0, 0xb1, 0xeb, 0xb6, 0x2f, 0xa1, 0xbb, 0x6d, 0x4d,
-	0xd9, 0x8d, 0x21, 0x37, 0x3c, 0xf2, 0x17, 0x1e, 0x74, 0xed, 0x1e, 0x2b, 0xe2, 0x64, 0x6c, 0x4a,
-	0x74, 0xfa, 0xf6, 0x9c, 0x4d, 0x88, 0x3e, 0xc6, 0x4d, 0xee, 0x5f, 0x7b, 0xe0, 0x30, 0xf9, 0x0b,
-	0xc7, 0x89, 0xba, 0x6e, 0xff, 0x2b, 0xe4, 0x45, 0x79, 0xd2, 0xee, 0x14, 0x7a, 0x71, 0xc3, 0x23,
-	0x1f, 0xc8, 0x7f, 0x09, 0xe9, 0xe0, 0x87, 0x58, 0x8a, 0xad, 0xcc, 0x2e, 0xfb, 0x0f, 0x35, 0x3b,
-	0xde, 0x0d, 0x8f, 0xfc, 0xb6, 0xfc, 0x23, 0x88, 0xfa, 0x16, 0xb9, 0xfe, 0xba, 0xdf, 0xd3, 0xb7,
-	0xf0, 0x24, 0x57, 0xe8, 0x25, 0xe7, 0x24, 0x65, 0xcd, 0x7e, 0x00, 0x50, 0x44, 0xb2, 0xa4, 0x14,
-	0xd6, 0x19, 0x9d, 0x57, 0x0d, 0x76, 0xdd, 0xdb, 0xd4, 0xd1, 0x9f, 0xa0, 0xf8, 0x99, 0x14, 0x44,
-	0x85, 0x9f, 0x9b, 0xeb, 0xac, 0x46, 0xa4, 0x83, 0x41, 0xdd, 0x54, 0x9d, 0x18, 0x6a, 0xfa, 0xe4,
-	0x29, 0xf4, 0x1e, 0x25, 0xc9, 0xb3, 0x59, 0x6a, 0xb2, 0x23, 0x6e, 0x60, 0x25, 0xc2, 0xe6, 0x41,
-	0xe9, 0x14, 0xf4, 0x2a, 0x92, 0x1a, 0x90, 0xbe, 0x45, 0x6a, 0xf7, 0x8b, 0x22, 0x8e, 0x7e, 0x41,
-	0x02, 0x58, 0x37, 0xf6, 0xcd, 0x6c, 0x7c, 0xe0, 0x92, 0xb1, 0xc3, 0xd9, 0xca, 0x12, 0x8e, 0xc7,
-	0xa1, 0x77, 0xbb, 0x9b, 0x6b, 0x9a, 0x37, 0x3c, 0x72, 0x00, 0xdd, 0x7b, 0x6c, 0x94, 0x8c, 0x99,
-	0x0a, 0x85, 0x36, 0x8a, 0x8d, 0x9b, 0x18, 0x6a, 0xd0, 0x73, 0x80, 0xee, 0x8b, 0x4f, 0x83, 0x79,
-	0xc6, 0x3e, 0xdf, 0xfd, 0x42, 0x05, 0x59, 0x2f, 0xf4, 0x8b, 0xd7, 0x81, 0xa1, 0xf3, 0xe2, 0x4b,
-	0x91, 0xa4, 0xf3, 0xe2, 0x2b, 0x91, 0xa4, 0xc3, 0x6a, 0x1d, 0x98, 0x92, 0x48, 0xc4, 0x97, 0xa5,
-	0xe0, 0xd3, 0x58, 0xc9, 0xf3, 0x42, 0xd6, 0xc1, 0xd5, 0xf3, 0x11, 0xdc, 0xd5, 0xae, 0xb9, 0xab,
-	0x1d, 0x42, 0xef, 0x1e, 0x93, 0xcc, 0x92, 0x15, 0x87, 0x81, 0xab, 0x42, 0xec, 0xea, 0x44, 0x59,
-	0xbd, 0xe0, 0x9c, 0xab, 0xd2, 0x31, 0xdd, 0x4f, 0x3e, 0x83, 0xce, 0x43, 0xc6, 0x75, 0x89, 0xc1,
-	0xf8, 0x1a, 0xa5, 0x9a, 0xc3, 0xa0, 0xa6, 0x42, 0xe1, 0xca, 0x0c, 0x52, 0xdb, 0x65, 0xe3, 0x09,
-	0x93, 0x8f, 0x7d, 0x18, 0x8e, 0x5f, 0x90, 0xdf, 0x40, 0xe2, 0xa6, 0x2a, 0xb9, 0x65, 0x65, 0xa6,
-	0x6d, 0xe2, 0xab, 0x25, 0x78, 0x1d, 0xe5, 0x38, 0x19, 0x33, 0xcb, 0xb8, 0xc5, 0xd0, 0xb1, 0x4a,
-	0xd0, 0xe6, 0x01, 0x55, 0xeb, 0xda, 0xe6, 0x01, 0xd5, 0x54, 0xac, 0xe9, 0x0e, 0xae, 0x43, 0xc9,
-	0xd5, 0x62, 0x1d, 0x59, 0xa5, 0x2e, 0x56, 0xda, 0xfd, 0x22, 0x98, 0xf2, 0x17, 0xe4, 0x53, 0x6c,
-	0xae, 0xb6, 0xcb, 0x28, 0x85, 0xaf, 0x53, 0xae, 0xb8, 0x18, 0x66, 0x59, 0x53, 0xae, 0xff, 0x23,
-	0x97, 0x42, 0x1b, 0xf8, 0x55, 0x80, 0x43, 0x9e, 0xa4, 0xf7, 0x02, 0x36, 0x4d, 0xe2, 0x42, 0x73,
-	0x15, 0xa5, 0x82, 0x42, 0x73, 0x59, 0xf5, 0x02, 0xf2, 0xa9, 0xe5, 0x6d, 0x3a, 0x55, 0x28, 0x2d,
-	0x5c, 0xe7, 0x56, 0x13, 0x0c, 0x43, 0x6a, 0x2a, 0x0a, 0x37, 0x3c, 0xe1, 0x3b, 0x16, 0xa9, 0x0e,
-	0xe3, 0x3b, 0x56, 0xb2, 0x28, 0x46, 0xed, 0xd5, 0xe4, 0x45, 0x0e, 0xa0, 0x5d, 0xc4, 0xdb, 0xda,
-	0x1c, 0x95, 0xa3, 0x73, 0x63, 0x5f, 0x2a, 0x61, 0x30, 0x5d, 0x43, 0x56, 0x01, 0x59, 0x16, 0xac,
-	0xc2, 0x2a, 0x7a, 0x08, 0x1b, 0x72, 0x83, 0xc6, 0x58, 0x62, 0xf2, 0x5b, 0x9f, 0xa4, 0x26, 0xec,
-	0x35, 0xaf, 0xb9, 0x2e, 0x6a, 0xa4, 0x97, 0x70, 0x85, 0x0d, 0xba, 0xa2, 0xf5, 0xbe, 0x4c, 0xbc,
-	0x7f, 0xe0, 0x5d, 0x3b, 0x5a, 0xc4, 0xbf, 0x34, 0x7f, 0xe5, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff,
-	0x74, 0x60, 0x2b, 0x2e, 0x04, 0x3d, 0x00, 0x00,
+	// 8191 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x7d, 0x4d, 0x6f, 0x24, 0xc9,
+	0x72, 0xd8, 0x54, 0x7f, 0x90, 0xec, 0xe8, 0xe6, 0x57, 0xf2, 0xab, 0x59, 0x33, 0x3b, 0xcb, 0x2d,
+	0x2d, 0x76, 0xe9, 0xf1, 0xf3, 0x7c, 0xf0, 0xbd, 0x5d, 0xad, 0x76, 0xb5, 0xbb, 0xe0, 0x90, 0x9c,
+	0xe1, 0x3c, 0x71, 0x39, 0x54, 0x91, 0xb3, 0x6b, 0xe9, 0x59, 0x28, 0x17, 0xbb, 0x93, 0xcd, 0x7a,
+	0x53, 0x5d, 0xd5, 0x5b, 0x55, 0x4d, 0x4e, 0x6b, 0xbd, 0x80, 0x25, 0x1f, 0x6c, 0xf8, 0x03, 0x3e,
+	0x18, 0x32, 0x2c, 0xdb, 0x10, 0xfc, 0x71, 0xd0, 0xf3, 0x41, 0xb0, 0x4e, 0xbe, 0xe8, 0x17, 0x58,
+	0x86, 0xe1, 0x83, 0xae, 0x86, 0x01, 0xdb, 0x02, 0x6c, 0xd8, 0x07, 0x1f, 0x0c, 0xdf, 0x0c, 0xd8,
+	0x88, 0xfc, 0xaa, 0xcc, 0xaa, 0x6a, 0xce, 0xec, 0x7b, 0xcf, 0xd6, 0x89, 0x9d, 0x11, 0x91, 0x91,
+	0x59, 0x99, 0x91, 0x91, 0x11, 0x91, 0x91, 0x49, 0x68, 0x25, 0xa3, 0xde, 0xfd, 0x51, 0x12, 0x67,
+	0x31, 0x69, 0x86, 0x51, 0x32, 0xea, 0xd9, 0x77, 0x06, 0x71, 0x3c, 0x08, 0xe9, 0x03, 0x7f, 0x14,
+	0x3c, 0xf0, 0xa3, 0x28, 0xce, 0xfc, 0x2c, 0x88, 0xa3, 0x94, 0x13, 0x39, 0x8f, 0x60, 0x65, 0x2f,
+	0xa1, 0x7e, 0x46, 0xbf, 0xf2, 0xc3, 0x90, 0x66, 0x2e, 0xfd, 0x7a, 0x4c, 0xd3, 0x8c, 0xd8, 0x30,
+	0x37, 0xf2, 0xd3, 0xf4, 0x3a, 0x4e, 0xfa, 0x5d, 0x6b, 0xcb, 0xda, 0xee, 0xb8, 0xaa, 0xec, 0xac,
+	0xc3, 0xaa, 0x59, 0x25, 0x1d, 0xc5, 0x51, 0x4a, 0x91, 0xd5, 0x8b, 0x28, 0x8c, 0x7b, 0x2f, 0xbf,
+	0x13, 0x2b, 0xb3, 0x8a, 0x60, 0xf5, 0xbb, 0x35, 0x68, 0x9f, 0x25, 0x7e, 0x94, 0xfa, 0x3d, 0xec,
+	0x2c, 0xe9, 0xc2, 0x6c, 0xf6, 0xca, 0xbb, 0xf4, 0xd3, 0x4b, 0xc6, 0xa2, 0xe5, 0xca, 0x22, 0x59,
+	0x87, 0x19, 0x7f, 0x18, 0x8f, 0xa3, 0xac, 0x5b, 0xdb, 0xb2, 0xb6, 0xeb, 0xae, 0x28, 0x91, 0xef,
+	0xc1, 0x72, 0x34, 0x1e, 0x7a, 0xbd, 0x38, 0xba, 0x08, 0x92, 0x21, 0xff, 0xe4, 0x6e, 0x7d, 0xcb,
+	0xda, 0x6e, 0xba, 0x65, 0x04, 0xb9, 0x0b, 0x70, 0x8e, 0xdd, 0xe0, 0x4d, 0x34, 0x58, 0x13, 0x1a,
+	0x84, 0x38, 0xd0, 0x11, 0x25, 0x1a, 0x0c, 0x2e, 0xb3, 0x6e, 0x93, 0x31, 0x32, 0x60, 0xc8, 0x23,
+	0x0b, 0x86, 0xd4, 0x4b, 0x33, 0x7f, 0x38, 0xea, 0xce, 0xb0, 0xde, 0x68, 0x10, 0x86, 0x8f, 0x33,
+	0x3f, 0xf4, 0x2e, 0x28, 0x4d, 0xbb, 0xb3, 0x02, 0xaf, 0x20, 0xe4, 0x3d, 0x58, 0xe8, 0xd3, 0x34,
+	0xf3, 0xfc, 0x7e, 0x3f, 0xa1, 0x69, 0x4a, 0xd3, 0xee, 0xdc, 0x56, 0x7d, 0xbb, 0xe5, 0x16, 0xa0,
+	0x4e, 0x17, 0xd6, 0x9f, 0xd2, 0x4c, 0x1b, 0x9d, 0x54, 0x8c, 0xb4, 0x73, 0x04, 0x44, 0x03, 0xef,
+	0xd3, 0xcc, 0x0f, 0xc2, 0x94, 0x7c, 0x08, 0x9d, 0x4c, 0x23, 0xee, 0x5a, 0x5b, 0xf5, 0xed, 0xf6,
+	0x0e, 0xb9, 0xcf, 0xa4, 0xe3, 0xbe, 0x56, 0xc1, 0x35, 0xe8, 0x9c, 0x3f, 0xa8, 0x41, 0xfb, 0x94,
+	0x46, 0x7d, 0x39, 0x8f, 0x04, 0x1a, 0xd8, 0x13, 0x31, 0x87, 0xec, 0x37, 0x79, 0x1b, 0xda, 0xac,
+	0x77, 0x69, 0x96, 0x04, 0xd1, 0x80, 0x4d, 0x41, 0xcb, 0x05, 0x04, 0x9d, 0x32, 0x08, 0x59, 0x82,
+	0xba, 0x3f, 0xcc, 0xd8, 0xc0, 0xd7, 0x5d, 0xfc, 0x49, 0xde, 0x81, 0xce, 0xc8, 0x9f, 0x0c, 0x69,
+	0x94, 0xe5, 0x83, 0xdd, 0x71, 0xdb, 0x02, 0x76, 0x88, 0xa3, 0x7d, 0x1f, 0x56, 0x74, 0x12, 0xc9,
+	0xbd, 0xc9, 0xb8, 0x2f, 0x6b, 0x94, 0xa2, 0x91, 0xf7, 0x61, 0x51, 0xd2, 0x27, 0xbc, 0xb3, 0x6c,
+	0xf8, 0x5b, 0xee, 0x82, 0x00, 0xcb, 0x4f, 0xd8, 0x86, 0xa5, 0x8b, 0x20, 0xf2, 0x43, 0xaf, 0x17,
+	0x66, 0x57, 0x5e, 0x9f, 0x86, 0x99, 0xcf, 0x26, 0xa2, 0xe9, 0x2e, 0x30, 0xf8, 0x5e, 0x98, 0x5d,
+	0xed, 0x23, 0x94, 0x6c, 0xc0, 0x6c, 0x3f, 0x99, 0x78, 0xc9, 0x38, 0xea, 0xce, 0x6d, 0x59, 0xdb,
+	0x73, 0xee, 0x4c, 0x3f, 0x99, 0xb8, 0x63, 0x26, 0x89, 0x2f, 0xe9, 0x24, 0xa5, 0x51, 0xbf, 0xdb,
+	0x62, 0x08, 0x59, 0x74, 0xfe, 0x49, 0x0d, 0x3a, 0x7c, 0xbc, 0xb8, 0x10, 0x93, 0x77, 0x61, 0x5e,
+	0x76, 0x8b, 0x26, 0x49, 0x9c, 0x08, 0xd1, 0x35, 0x81, 0xe4, 0x1e, 0x2c, 0x49, 0xc0, 0x28, 0xa1,
+	0xc1, 0xd0, 0x1f, 0x50, 0x36, 0x8e, 0x1d, 0xb7, 0x04, 0x27, 0x3b, 0x39, 0xc7, 0x24, 0x1e, 0x67,
+	0x94, 0x8d, 0x6b, 0x7b, 0xa7, 0x23, 0xe6, 0xd2, 0x45, 0x98, 0x6b, 0x92, 0x90, 0x87, 0xb0, 0x92,
+	0x8e, 0x7b, 0x3d, 0x9a, 0xa6, 0xde, 0x28, 0x89, 0xcf, 0xfd, 0xf3, 0x20, 0x0c, 0xb2, 0x09, 0x1b,
+	0x76, 0xcb, 0xad, 0x42, 0x91, 0x1f, 0xc0, 0xda, 0x85, 0x1f, 0x84, 0xe3, 0x84, 0x7a, 0x69, 0x3c,
+	0x4e, 0x7a, 0xd4, 0x1b, 0x8d, 0xcf, 0x5f, 0xd2, 0x89, 0x98, 0x80, 0x6a, 0x24, 0x2e, 0x11, 0x89,
+	0xe8, 0xc5, 0x7d, 0x2a, 0x66, 0xc0, 0x80, 0x39, 0xbf, 0x6d, 0x41, 0x67, 0xef, 0xd2, 0x8f, 0x22,
+	0x1a, 0x9e, 0xc4, 0x41, 0x94, 0xb1, 0x4a, 0xe3, 0xa8, 0x1f, 0x44, 0x03, 0x2f, 0x7b, 0x15, 0x48,
+	0xfd, 0x60, 0xc0, 0x70, 0x80, 0xf4, 0x32, 0x4a, 0x83, 0x10, 0xb4, 0x12, 0x1c, 0xf9, 0xc5, 0xe3,
+	0x6c, 0x34, 0xce, 0xbc, 0x20, 0xea, 0xd3, 0x57, 0x6c, 0x7c, 0xe6, 0x5d, 0x03, 0xe6, 0x7c, 0x06,
+	0x4b, 0x47, 0xb8, 0x60, 0xa3, 0x20, 0x1a, 0xec, 0xf2, 0x55, 0x85, 0x5a, 0x44, 0x7c, 0x23, 0x9f,
+	0x23, 0x51, 0x42, 0x99, 0xbf, 0x8c, 0xd3, 0x4c, 0xb4, 0xc7, 0x7e, 0x3b, 0xff, 0xd9, 0x82, 0x45,
+	0x9c, 0xe7, 0x2f, 0xfc, 0x68, 0x22, 0x05, 0xeb, 0x08, 0x3a, 0xc8, 0xea, 0x2c, 0xde, 0xe5, 0xba,
+	0x88, 0xaf, 0xb1, 0x6d, 0x31, 0x2f, 0x05, 0xea, 0xfb, 0x3a, 0xe9, 0x41, 0x94, 0x25, 0x13, 0xd7,
+	0xa8, 0x8d, 0xab, 0x2a, 0xf3, 0x93, 0x01, 0xcd, 0x98, 0x96, 0x12, 0x5a, 0x0b, 0x38, 0x68, 0x2f,
+	0x8e, 0x2e, 0xc8, 0x16, 0x74, 0x52, 0x3f, 0xf3, 0x46, 0x34, 0xf1, 0xce, 0x27, 0x19, 0x65, 0x13,
+	0x53, 0x77, 0x21, 0xf5, 0xb3, 0x13, 0x9a, 0x3c, 0x9e, 0x64, 0xd4, 0xfe, 0x1c, 0x96, 0x4b, 0xad,
+	0xe0, 0x62, 0xcc, 0x3f, 0x11, 0x7f, 0x92, 0x55, 0x68, 0x5e, 0xf9, 0xe1, 0x98, 0x0a, 0xe5, 0xc9,
+	0x0b, 0x1f, 0xd7, 0x3e, 0xb2, 0x9c, 0xf7, 0x60, 0x29, 0xef, 0xb6, 0x10, 0x68, 0x02, 0x0d, 0x35,
+	0x4b, 0x2d, 0x97, 0xfd, 0x76, 0x7e, 0xcb, 0xe2, 0x84, 0x7b, 0x71, 0xa0, 0x14, 0x11, 0x12, 0xa2,
+	0xbe, 0x92, 0x84, 0xf8, 0x7b, 0xaa, 0xa2, 0xfe, 0xd9, 0x3f, 0xd6, 0x79, 0x1f, 0x96, 0xb5, 0x2e,
+	0xdc, 0xd0, 0xd9, 0xdf, 0xb3, 0x60, 0xf9, 0x98, 0x5e, 0x8b, 0x59, 0x97, 0xbd, 0xfd, 0x08, 0x1a,
+	0xd9, 0x64, 0x44, 0x19, 0xe5, 0xc2, 0xce, 0xbb, 0x62, 0xd2, 0x4a, 0x74, 0xf7, 0x45, 0xf1, 0x6c,
+	0x32, 0xa2, 0x2e, 0xab, 0xe1, 0x3c, 0x87, 0xb6, 0x06, 0x24, 0x1b, 0xb0, 0xf2, 0xd5, 0xb3, 0xb3,
+	0xe3, 0x83, 0xd3, 0x53, 0xef, 0xe4, 0xc5, 0xe3, 0x5f, 0x39, 0xf8, 0x35, 0xef, 0x70, 0xf7, 0xf4,
+	0x70, 0xe9, 0x16, 0x59, 0x07, 0x72, 0x7c, 0x70, 0x7a, 0x76, 0xb0, 0x6f, 0xc0, 0x2d, 0xb2, 0x08,
+	0x6d, 0x1d, 0x50, 0x73, 0x6c, 0xe8, 0x1e, 0xd3, 0xeb, 0xaf, 0x82, 0x2c, 0xa2, 0x69, 0x6a, 0x36,
+	0xef, 0xdc, 0x07, 0xa2, 0xf7, 0x49, 0x7c, 0x66, 0x17, 0x66, 0xc5, 0xd6, 0x20, 0x77, 0x46, 0x51,
+	0x74, 0xde, 0x03, 0x72, 0x1a, 0x0c, 0xa2, 0x2f, 0x68, 0x9a, 0xfa, 0x03, 0x2a, 0x3f, 0x76, 0x09,
+	0xea, 0xc3, 0x74, 0x20, 0x16, 0x1a, 0xfe, 0x74, 0xbe, 0x0f, 0x2b, 0x06, 0x9d, 0x60, 0x7c, 0x07,
+	0x5a, 0x69, 0x30, 0x88, 0xfc, 0x6c, 0x9c, 0x50, 0xc1, 0x3a, 0x07, 0x38, 0x4f, 0x60, 0xf5, 0x4b,
+	0x9a, 0x04, 0x17, 0x93, 0xd7, 0xb1, 0x37, 0xf9, 0xd4, 0x8a, 0x7c, 0x0e, 0x60, 0xad, 0xc0, 0x47,
+	0x34, 0xcf, 0x25, 0x53, 0xcc, 0xdf, 0x9c, 0xcb, 0x0b, 0xda, 0x3a, 0xad, 0xe9, 0xeb, 0xd4, 0x79,
+	0x01, 0x64, 0x2f, 0x8e, 0x22, 0xda, 0xcb, 0x4e, 0x28, 0x4d, 0x64, 0x67, 0xfe, 0xbc, 0x26, 0x86,
+	0xed, 0x9d, 0x0d, 0x31, 0xb1, 0xc5, 0xc5, 0x2f, 0xe4, 0x93, 0x40, 0x63, 0x44, 0x93, 0x21, 0x63,
+	0x3c, 0xe7, 0xb2, 0xdf, 0xce, 0x03, 0x58, 0x31, 0xd8, 0xe6, 0x63, 0x3e, 0xa2, 0x34, 0xf1, 0x44,
+	0xef, 0x9a, 0xae, 0x2c, 0x3a, 0x8f, 0x60, 0x6d, 0x3f, 0x48, 0x7b, 0xe5, 0xae, 0x60, 0x95, 0xf1,
+	0xb9, 0x97, 0x2f, 0x3f, 0x59, 0xc4, 0xed, 0xbc, 0x58, 0x45, 0x18, 0x41, 0xff, 0xc0, 0x82, 0xc6,
+	0xe1, 0xd9, 0xd1, 0x1e, 0x5a, 0x50, 0x41, 0xd4, 0x8b, 0x87, 0xb8, 0x09, 0xf2, 0xe1, 0x50, 0xe5,
+	0xa9, 0xcb, 0xea, 0x0e, 0xb4, 0xd8, 0xde, 0x89, 0x16, 0x0a, 0x5b, 0x54, 0x1d, 0x37, 0x07, 0xa0,
+	0x75, 0x44, 0x5f, 0x8d, 0x82, 0x84, 0x99, 0x3f, 0xd2, 0xa8, 0x69, 0x30, 0x65, 0x59, 0x46, 0xb0,
+	0x4d, 0x7c, 0x20, 0x17, 0x1e, 0xfe, 0x74, 0xfe, 0xc3, 0x0c, 0xcc, 0xef, 0xf6, 0xb2, 0xe0, 0x8a,
+	0x0a, 0x75, 0xce, 0xfa, 0xc1, 0x00, 0xa2, 0x87, 0xa2, 0x84, 0x9b, 0x60, 0x42, 0x87, 0x71, 0xa6,
+	0x36, 0x11, 0x3e, 0x71, 0x26, 0x10, 0xa9, 0x7a, 0x9c, 0x91, 0x37, 0xc2, 0x8d, 0x81, 0xf5, 0xb8,
+	0xe5, 0x9a, 0x40, 0x1c, 0x44, 0x04, 0xe0, 0xb8, 0x63, 0x5f, 0x1b, 0xae, 0x2c, 0xe2, 0x08, 0xf5,
+	0xfc, 0x91, 0xdf, 0xc3, 0x9d, 0x8d, 0x77, 0x53, 0x95, 0x91, 0x77, 0x18, 0xf7, 0xfc, 0xd0, 0x3b,
+	0xf7, 0x43, 0x3f, 0xea, 0x51, 0x61, 0x9a, 0x99, 0x40, 0xb4, 0xbe, 0x44, 0x97, 0x24, 0x19, 0xb7,
+	0xd0, 0x0a, 0x50, 0xb4, 0xe2, 0x7a, 0xf1, 0x70, 0x18, 0x64, 0x68, 0xb4, 0x31, 0xdb, 0xa0, 0xee,
+	0x6a, 0x10, 0xf6, 0x25, 0xbc, 0x74, 0xcd, 0x47, 0xb5, 0xc5, 0x5b, 0x33, 0x80, 0xc8, 0xe5, 0x82,
+	0x52, 0xa6, 0xd3, 0x5e, 0x5e, 0x77, 0x81, 0x73, 0xc9, 0x21, 0x38, 0x3f, 0xe3, 0x28, 0xa5, 0x59,
+	0x16, 0xd2, 0xbe, 0xea, 0x50, 0x9b, 0x91, 0x95, 0x11, 0xb8, 0xc5, 0x73, 0x3b, 0x32, 0xf5, 0xb3,
+	0x38, 0xbd, 0x0c, 0x52, 0x2f, 0xa5, 0x51, 0xd6, 0xed, 0x30, 0xfa, 0x2a, 0x14, 0xf9, 0x08, 0x36,
+	0x0a, 0xe0, 0x84, 0xf6, 0x68, 0x70, 0x45, 0xfb, 0xdd, 0x79, 0x56, 0x6b, 0x1a, 0x9a, 0x6c, 0x41,
+	0x1b, 0xcd, 0xe7, 0xf1, 0xa8, 0xef, 0x67, 0x34, 0xed, 0x2e, 0xb0, 0x79, 0xd0, 0x41, 0xe4, 0x11,
+	0xcc, 0x8f, 0x28, 0xdf, 0x97, 0x2f, 0xb3, 0xb0, 0x97, 0x76, 0x17, 0xd9, 0x66, 0xd8, 0x16, 0xcb,
+	0x0f, 0x25, 0xda, 0x35, 0x29, 0x50, 0x58, 0x7b, 0x29, 0x33, 0xc8, 0xfc, 0x49, 0x77, 0x89, 0x89,
+	0x61, 0x0e, 0x20, 0x8f, 0xe1, 0x0e, 0x9f, 0xab, 0x20, 0xba, 0x08, 0x71, 0xf8, 0xbc, 0x4b, 0xea,
+	0xf7, 0x93, 0x38, 0x1e, 0x7a, 0xc3, 0xd4, 0xcf, 0xba, 0xcb, 0xac, 0xc7, 0x37, 0xd2, 0x90, 0x7d,
+	0x78, 0x4b, 0x4c, 0xe4, 0x14, 0x26, 0x84, 0x31, 0xb9, 0x99, 0x88, 0xad, 0xe2, 0x24, 0xb8, 0xf2,
+	0x33, 0xda, 0x5d, 0xe1, 0xc6, 0x9f, 0x28, 0xe2, 0xb4, 0xa3, 0x11, 0xe8, 0x9f, 0x87, 0x94, 0xf3,
+	0x5b, 0xe5, 0xd3, 0x6e, 0x00, 0xc9, 0x36, 0x2c, 0xf2, 0x81, 0xcc, 0xe9, 0xd6, 0x18, 0x5d, 0x11,
+	0xec, 0xac, 0xc1, 0xca, 0x51, 0x90, 0x66, 0x62, 0x75, 0xa9, 0x3d, 0xe0, 0x10, 0x56, 0x4d, 0xb0,
+	0xd0, 0x48, 0x0f, 0x61, 0x4e, 0x2c, 0x95, 0xb4, 0xdb, 0x66, 0xc3, 0xbd, 0x2a, 0x86, 0xdb, 0x58,
+	0xa5, 0xae, 0xa2, 0x72, 0x7e, 0x52, 0x83, 0x06, 0x6a, 0x9b, 0xe9, 0x9a, 0x49, 0x57, 0x73, 0x35,
+	0x43, 0xcd, 0xe9, 0x9b, 0x4e, 0xdd, 0xd8, 0x74, 0x98, 0x23, 0x35, 0xc9, 0xa8, 0x90, 0x40, 0xbe,
+	0x4a, 0x35, 0x48, 0x8e, 0x4f, 0x68, 0xef, 0xaa, 0xdb, 0xd4, 0xf1, 0x08, 0xc1, 0x85, 0x8c, 0x9b,
+	0x3d, 0xab, 0xcd, 0xd7, 0xa9, 0x2a, 0x4b, 0x1c, 0xab, 0x39, 0x9b, 0xe3, 0x58, 0xbd, 0x2e, 0xcc,
+	0x06, 0xd1, 0x79, 0x3c, 0x8e, 0xfa, 0xc2, 0x5e, 0x97, 0x45, 0x94, 0xad, 0x11, 0xb3, 0x11, 0x83,
+	0x21, 0x15, 0x8b, 0x31, 0x07, 0xa0, 0xc1, 0x38, 0x8e, 0x5e, 0x46, 0xf1, 0x75, 0xe4, 0x0d, 0xd3,
+	0x41, 0xca, 0x96, 0x62, 0xc3, 0x35, 0x60, 0x0e, 0x41, 0x83, 0x31, 0x65, 0xba, 0x59, 0x4d, 0xc4,
+	0x87, 0xb0, 0xac, 0xc1, 0xc4, 0x2c, 0xbc, 0x03, 0x4d, 0x1c, 0x21, 0xe9, 0x62, 0x49, 0x89, 0x47,
+	0x22, 0x97, 0x63, 0x9c, 0x25, 0x58, 0x78, 0x4a, 0xb3, 0x67, 0xd1, 0x45, 0x2c, 0x39, 0xfd, 0x56,
+	0x13, 0x16, 0x15, 0x48, 0x30, 0xda, 0x86, 0xc5, 0xa0, 0x4f, 0xa3, 0x2c, 0xc8, 0x26, 0x9e, 0x61,
+	0x97, 0x16, 0xc1, 0xb8, 0x4d, 0xfa, 0x61, 0xe0, 0xa7, 0x42, 0xad, 0xf2, 0x02, 0xd9, 0x81, 0x55,
+	0x5c, 0x91, 0x72, 0x91, 0x29, 0xd1, 0xe0, 0xe6, 0x70, 0x25, 0x0e, 0x95, 0x08, 0xc2, 0xb9, 0xda,
+	0xce, 0xab, 0xf0, 0x4d, 0xa1, 0x0a, 0x85, 0x23, 0xcb, 0x39, 0xe1, 0x27, 0x37, 0xf9, 0xaa, 0x55,
+	0x80, 0x92, 0xcb, 0x3c, 0xc3, 0x4d, 0xf1, 0xa2, 0xcb, 0xac, 0xb9, 0xdd, 0x73, 0x25, 0xb7, 0x7b,
+	0x1b, 0x16, 0xd3, 0x49, 0xd4, 0xa3, 0x7d, 0x2f, 0x8b, 0xb1, 0xdd, 0x20, 0x12, 0x4e, 0x57, 0x11,
+	0xcc, 0x02, 0x04, 0x34, 0xcd, 0x22, 0x9a, 0xb1, 0x29, 0x9c, 0x73, 0x65, 0x11, 0x37, 0x26, 0x46,
+	0xc2, 0x17, 0x46, 0xcb, 0x15, 0x25, 0xdc, 0xef, 0xc7, 0x49, 0x90, 0x76, 0x3b, 0x0c, 0xca, 0x7e,
+	0xa3, 0xe7, 0xc3, 0xb0, 0xde, 0xb9, 0xdf, 0x7b, 0x49, 0xa3, 0x3e, 0x2e, 0xff, 0x30, 0xbb, 0x9c,
+	0x30, 0xa5, 0x38, 0xe7, 0x56, 0x23, 0x71, 0xe4, 0x4c, 0x04, 0xf7, 0xf6, 0x16, 0xd8, 0xe7, 0x54,
+	0xa1, 0x50, 0xbd, 0xa7, 0x34, 0xbc, 0xf0, 0x7a, 0x97, 0xb4, 0xf7, 0xd2, 0x4b, 0x33, 0x3f, 0x1b,
+	0xa3, 0x9a, 0x64, 0xee, 0x6d, 0x09, 0x81, 0xbd, 0xd2, 0x80, 0xa1, 0x9f, 0xd1, 0xa8, 0x37, 0xf1,
+	0x86, 0x29, 0xd3, 0x94, 0x75, 0xb7, 0x1a, 0x89, 0x6e, 0x93, 0x86, 0xe0, 0x5d, 0x5a, 0xe6, 0x6e,
+	0x53, 0x11, 0xee, 0xfc, 0x26, 0x33, 0x9f, 0x54, 0x3c, 0xe4, 0x05, 0xd3, 0xe4, 0xe4, 0x36, 0xb4,
+	0xf8, 0x5c, 0xa4, 0x97, 0xbe, 0x8c, 0xdc, 0x30, 0xc0, 0xe9, 0xa5, 0x8f, 0x6e, 0xbc, 0x31, 0xbd,
+	0x5c, 0x43, 0xb4, 0x19, 0xec, 0x90, 0xcf, 0xee, 0xbb, 0xb0, 0x20, 0x23, 0x2d, 0xa9, 0x17, 0xd2,
+	0x8b, 0x4c, 0xba, 0x63, 0xd1, 0x78, 0x88, 0xcd, 0xa5, 0x47, 0xf4, 0x22, 0x73, 0x8e, 0x61, 0x59,
+	0x68, 0xa7, 0xe7, 0x23, 0x2a, 0x9b, 0xfe, 0xa5, 0xa2, 0x3d, 0xc0, 0x4d, 0xb8, 0x15, 0xb1, 0xa2,
+	0x74, 0x1f, 0xb2, 0x60, 0x24, 0x38, 0x2e, 0x10, 0x81, 0xde, 0x0b, 0xe3, 0x94, 0x0a, 0x86, 0x0e,
+	0x74, 0x7a, 0x61, 0x9c, 0x16, 0x1d, 0x4d, 0x1d, 0x86, 0x32, 0x24, 0xdc, 0x61, 0x61, 0x04, 0xca,
+	0xa2, 0xf3, 0x4f, 0x6b, 0xb0, 0xc2, 0xb8, 0x49, 0x3d, 0xaa, 0x3c, 0x87, 0x37, 0xef, 0x66, 0xa7,
+	0xa7, 0x95, 0x70, 0xdd, 0x5e, 0xc4, 0x49, 0x8f, 0x8a, 0x96, 0x78, 0xe1, 0xe7, 0xe0, 0x0b, 0x91,
+	0x5f, 0x40, 0xfb, 0x83, 0x4d, 0xa5, 0xc7, 0x1b, 0x98, 0x61, 0x0d, 0x74, 0x04, 0xf0, 0x09, 0x6b,
+	0xe7, 0x7d, 0x58, 0xec, 0xd3, 0x30, 0xb8, 0xa2, 0xc9, 0xc4, 0x4b, 0x7b, 0x49, 0x30, 0xca, 0x98,
+	0x42, 0xed, 0xb8, 0x0b, 0x12, 0x7c, 0xca, 0xa0, 0xe4, 0xcf, 0xc1, 0x92, 0x22, 0x94, 0x1a, 0x9f,
+	0x2f, 0x53, 0xc5, 0x40, 0x58, 0xd1, 0xce, 0xef, 0xd7, 0x60, 0x99, 0x8d, 0xd1, 0x29, 0x93, 0x5a,
+	0x31, 0xee, 0xbf, 0x0c, 0xf3, 0x38, 0xc6, 0x54, 0xea, 0x1b, 0x31, 0x42, 0xab, 0x4a, 0x35, 0x32,
+	0x28, 0x27, 0x3e, 0xbc, 0xe5, 0x9a, 0xc4, 0xe4, 0x73, 0xe8, 0xe8, 0x71, 0x3a, 0x36, 0x58, 0xed,
+	0x9d, 0x4d, 0x39, 0xbc, 0x25, 0x91, 0x3d, 0xbc, 0xe5, 0x1a, 0x15, 0xc8, 0x27, 0x00, 0xcc, 0x44,
+	0x64, 0x6c, 0xbb, 0x75, 0xb3, 0x7a, 0x49, 0x4a, 0x0e, 0x6f, 0xb9, 0x1a, 0x39, 0x39, 0x82, 0x15,
+	0x36, 0x84, 0x9e, 0xe8, 0x54, 0x42, 0xaf, 0x02, 0x7a, 0xcd, 0x34, 0x62, 0x7b, 0xa7, 0x2b, 0xb8,
+	0xb0, 0x01, 0x65, 0x3c, 0x4e, 0x38, 0xfe, 0xf0, 0x96, 0x5b, 0x55, 0xed, 0xf1, 0x1c, 0xcc, 0x70,
+	0x0b, 0xc9, 0x79, 0x0a, 0xf3, 0xc6, 0x77, 0x1b, 0xae, 0x6a, 0x87, 0xbb, 0xaa, 0xa5, 0x48, 0x46,
+	0xad, 0x22, 0x92, 0xf1, 0xaf, 0x6a, 0xb0, 0x5c, 0x6a, 0xbf, 0x6c, 0x7f, 0x59, 0xaf, 0xb5, 0xbf,
+	0x4c, 0xa3, 0xb6, 0x56, 0x32, 0x6a, 0x1f, 0xc2, 0x0a, 0x4d, 0xb3, 0x60, 0xe8, 0x67, 0xb4, 0xef,
+	0xa5, 0xd7, 0x94, 0x8e, 0x18, 0x21, 0x8f, 0xea, 0x55, 0xa1, 0xc8, 0x7d, 0x20, 0xbc, 0x60, 0x88,
+	0x6b, 0x83, 0x55, 0xa8, 0xc0, 0x98, 0x16, 0x60, 0xb3, 0x68, 0x01, 0x6e, 0xc3, 0xe2, 0xd0, 0x7f,
+	0xc5, 0x3a, 0xeb, 0x31, 0xf7, 0x64, 0x22, 0xb6, 0x93, 0x22, 0x98, 0x19, 0xfb, 0xc1, 0xf0, 0x3c,
+	0x2e, 0x58, 0xf1, 0x26, 0xd0, 0xf9, 0x37, 0x75, 0x20, 0xa8, 0x6d, 0x0a, 0xcb, 0xf9, 0x3d, 0x58,
+	0x10, 0xcb, 0xcf, 0x74, 0xef, 0x0a, 0x50, 0x66, 0x03, 0xc7, 0x7d, 0xc3, 0xa3, 0xe9, 0xb8, 0x3a,
+	0x08, 0x3f, 0x5f, 0x2b, 0xca, 0x00, 0x26, 0xb7, 0x95, 0x2a, 0x30, 0xb8, 0x61, 0x73, 0xf3, 0x55,
+	0x46, 0xb4, 0x84, 0x4f, 0xc7, 0x07, 0xac, 0x12, 0xc7, 0xe2, 0xea, 0x63, 0x8c, 0x8e, 0xfa, 0x99,
+	0xf4, 0x79, 0x64, 0xb9, 0xa8, 0x48, 0x66, 0x5e, 0xab, 0x48, 0x66, 0x4b, 0x8a, 0x44, 0xb3, 0x75,
+	0xe7, 0x4a, 0xb6, 0xee, 0x30, 0x88, 0xf8, 0xb0, 0x33, 0x1b, 0x56, 0xb8, 0x38, 0x06, 0x10, 0x5d,
+	0x0c, 0x61, 0x4c, 0xb3, 0x25, 0x95, 0xd0, 0x94, 0x26, 0x57, 0x94, 0xf5, 0x96, 0xfb, 0x3b, 0xd3,
+	0xd0, 0x38, 0x78, 0x7e, 0x14, 0xc5, 0xe3, 0xa8, 0x47, 0x59, 0x1c, 0xb3, 0x4f, 0x47, 0xd9, 0x25,
+	0xf3, 0x7e, 0xe6, 0xdd, 0x0a, 0x8c, 0xf3, 0x27, 0x16, 0x2c, 0xe1, 0x6c, 0x1a, 0x8a, 0xe7, 0x63,
+	0x60, 0x0a, 0xf7, 0x0d, 0xf5, 0x8e, 0x41, 0xfb, 0xb3, 0xab, 0x9d, 0x8f, 0xa0, 0xc5, 0x18, 0xc6,
+	0x23, 0x1a, 0x75, 0xeb, 0x86, 0xbe, 0x28, 0xed, 0x75, 0x87, 0xb7, 0xdc, 0x9c, 0x58, 0xd3, 0x12,
+	0xff, 0xce, 0x82, 0xb6, 0xe8, 0xe6, 0x4f, 0x1d, 0x04, 0xb0, 0x61, 0x0e, 0x15, 0x86, 0xe6, 0x51,
+	0xab, 0x32, 0x5f, 0x53, 0xd9, 0x38, 0x41, 0x63, 0xd2, 0x08, 0x00, 0x14, 0xc1, 0xb8, 0xfa, 0xd9,
+	0xb6, 0x9e, 0x7a, 0x59, 0x10, 0x7a, 0x12, 0x2b, 0xce, 0x40, 0xaa, 0x50, 0xb8, 0xbb, 0xa5, 0x19,
+	0x86, 0x0c, 0xf8, 0x2a, 0xe5, 0x05, 0x8c, 0x74, 0x88, 0x0f, 0x2a, 0xba, 0x35, 0x7f, 0x0c, 0xb0,
+	0x51, 0x42, 0x29, 0xd7, 0x46, 0x78, 0xb0, 0xe6, 0xba, 0xb6, 0x74, 0xe7, 0xd6, 0x40, 0x91, 0x01,
+	0xac, 0x49, 0xf5, 0x86, 0x63, 0x9a, 0xdb, 0xb2, 0x35, 0xa6, 0x08, 0x1f, 0x99, 0x32, 0x50, 0x6c,
+	0x50, 0xc2, 0x75, 0xfd, 0x50, 0xcd, 0x8f, 0x5c, 0x42, 0x57, 0x22, 0xa4, 0x21, 0xa1, 0x99, 0xda,
+	0xd8, 0xd6, 0xf7, 0x5e, 0xd3, 0x16, 0x53, 0xdc, 0x7d, 0xd9, 0xcc, 0x54, 0x6e, 0x64, 0x02, 0x77,
+	0x25, 0x2e, 0xdf, 0x5b, 0x8c, 0xf6, 0x1a, 0x6f, 0xf4, 0x6d, 0xf9, 0x6e, 0xa1, 0x1a, 0x7d, 0x0d,
+	0x63, 0xfb, 0x8f, 0x2d, 0x58, 0x30, 0xd9, 0x71, 0x37, 0x96, 0xad, 0x5d, 0xa9, 0xca, 0xa4, 0x7b,
+	0x52, 0x00, 0x97, 0xe3, 0x3a, 0xb5, 0xaa, 0xb8, 0x8e, 0x1e, 0xbd, 0xa9, 0xbf, 0x2e, 0x7a, 0xd3,
+	0x78, 0xb3, 0xe8, 0x4d, 0xb3, 0x2a, 0x7a, 0x63, 0xff, 0x2f, 0x0b, 0x48, 0x79, 0x7e, 0xc9, 0x53,
+	0x1e, 0x58, 0x8a, 0x68, 0x28, 0xf4, 0xc4, 0x5f, 0x78, 0x33, 0x19, 0x91, 0x63, 0x28, 0x6b, 0x33,
+	0x57, 0x40, 0x53, 0x04, 0xba, 0x71, 0x3c, 0xef, 0x56, 0xa1, 0x0a, 0x5b, 0x6f, 0xe3, 0xf5, 0xf1,
+	0xa4, 0xe6, 0xeb, 0xe3, 0x49, 0x33, 0xc5, 0x78, 0x92, 0xfd, 0x57, 0x60, 0xde, 0x98, 0xf5, 0x9f,
+	0xdf, 0x17, 0x17, 0x0d, 0x6b, 0x3e, 0xc1, 0x06, 0xcc, 0xfe, 0xef, 0x35, 0x20, 0x65, 0xc9, 0xfb,
+	0xff, 0xda, 0x87, 0xb2, 0x61, 0x50, 0xaf, 0x30, 0x0c, 0xfe, 0x9f, 0x2a, 0xc5, 0xef, 0xc1, 0x72,
+	0x42, 0x7b, 0xf1, 0x15, 0x4d, 0xb4, 0x98, 0x1e, 0x9f, 0xaa, 0x32, 0x02, 0x5d, 0x0b, 0xd3, 0x8a,
+	0x9b, 0x33, 0x8e, 0x6d, 0xb5, 0x9d, 0xa1, 0x60, 0xcc, 0x39, 0xbf, 0x04, 0xab, 0xfc, 0x34, 0xfd,
+	0x31, 0x67, 0x25, 0xad, 0x9b, 0x77, 0xa0, 0x73, 0xcd, 0x0f, 0x16, 0xbc, 0x38, 0x0a, 0x27, 0x62,
+	0x13, 0x69, 0x0b, 0xd8, 0xf3, 0x28, 0x9c, 0x38, 0xff, 0xda, 0x82, 0xb5, 0x42, 0xdd, 0xfc, 0x2c,
+	0x93, 0xab, 0x5a, 0x53, 0xff, 0x9a, 0x40, 0xfc, 0x44, 0x21, 0xe3, 0xda, 0x27, 0xf2, 0x2d, 0xa9,
+	0x8c, 0xc0, 0x21, 0x1c, 0x47, 0x65, 0x7a, 0x61, 0x55, 0x56, 0xa0, 0xd0, 0xa7, 0x15, 0x86, 0x42,
+	0xbf, 0xa0, 0x0f, 0x4a, 0x70, 0x67, 0x03, 0xd6, 0x84, 0xa0, 0x98, 0xe3, 0xe0, 0xec, 0xc0, 0x7a,
+	0x11, 0x91, 0xc7, 0xf5, 0xcd, 0xcf, 0x93, 0x45, 0xe7, 0x73, 0x20, 0xbf, 0x3a, 0xa6, 0xc9, 0x84,
+	0x9d, 0xb0, 0xaa, 0x83, 0xa3, 0x8d, 0x62, 0xe8, 0x0c, 0x8f, 0x23, 0x7e, 0x85, 0x4e, 0xe4, 0xa9,
+	0x77, 0x4d, 0x9d, 0x7a, 0x3b, 0x9f, 0xc0, 0x8a, 0xc1, 0x40, 0x0d, 0xeb, 0x0c, 0x3b, 0xa5, 0x95,
+	0x46, 0xba, 0x79, 0x92, 0x2b, 0x70, 0xce, 0xff, 0xb1, 0xa0, 0x7e, 0x18, 0x8f, 0xf4, 0xf8, 0xb7,
+	0x65, 0xc6, 0xbf, 0x85, 0x9e, 0xf5, 0x94, 0x1a, 0xad, 0x09, 0x2d, 0xa1, 0x03, 0x51, 0x4b, 0xfa,
+	0xc3, 0x0c, 0x83, 0x26, 0x17, 0x71, 0x72, 0xed, 0x27, 0x7d, 0x31, 0xd6, 0x05, 0x28, 0x76, 0x3f,
+	0x57, 0x46, 0xf8, 0x13, 0x0d, 0x0c, 0x61, 0x77, 0x73, 0xdb, 0x5c, 0x94, 0xf4, 0xe0, 0xe1, 0x8c,
+	0x19, 0x3c, 0x7c, 0x08, 0x2b, 0x26, 0x57, 0x6e, 0x2a, 0x72, 0x3b, 0xb3, 0x0a, 0x85, 0xbb, 0x00,
+	0x6a, 0x2c, 0x46, 0xc6, 0xe3, 0xea, 0xaa, 0xec, 0xfc, 0x47, 0x0b, 0x9a, 0x6c, 0x4c, 0x70, 0x85,
+	0x72, 0x99, 0x63, 0x99, 0x15, 0xec, 0x74, 0xc3, 0xe2, 0x2b, 0xb4, 0x00, 0x2e, 0xe4, 0x5b, 0xd4,
+	0x4a, 0xf9, 0x16, 0x77, 0xa0, 0xc5, 0x4b, 0x79, 0x82, 0x42, 0x0e, 0x20, 0x77, 0xf1, 0xe4, 0x77,
+	0x24, 0xf7, 0x55, 0x90, 0xce, 0x53, 0x3c, 0x72, 0x19, 0x3c, 0xef, 0x07, 0xf2, 0xe2, 0x9d, 0xe6,
+	0x9a, 0xb9, 0x08, 0x66, 0x5e, 0x85, 0x64, 0xcb, 0x09, 0xf9, 0xa2, 0x2f, 0x40, 0x9d, 0x7b, 0xb0,
+	0x78, 0x1c, 0xf7, 0xa9, 0x16, 0x1b, 0x9c, 0x2a, 0x60, 0xce, 0x5f, 0xb5, 0x60, 0x4e, 0x12, 0x93,
+	0x6d, 0x68, 0xe0, 0x86, 0x5b, 0x30, 0x71, 0xd5, 0x31, 0x17, 0xd2, 0xb9, 0x8c, 0x02, 0x15, 0x25,
+	0x8b, 0xc8, 0xe4, 0x06, 0x91, 0x8c, 0xc7, 0x28, 0x58, 0xde, 0xdd, 0xc2, 0x96, 0x5c, 0x80, 0x3a,
+	0xff, 0xc2, 0x82, 0x79, 0xa3, 0x0d, 0x74, 0x8b, 0x42, 0x3f, 0xcd, 0xc4, 0x41, 0x80, 0x98, 0x16,
+	0x1d, 0xa4, 0x8b, 0x4b, 0xcd, 0x14, 0x17, 0x15, 0xc7, 0xac, 0xeb, 0x71, 0xcc, 0x87, 0xd0, 0xca,
+	0xb3, 0x61, 0x1a, 0x86, 0x02, 0xc4, 0x16, 0xe5, 0x01, 0x5e, 0x4e, 0x84, 0x7c, 0x7a, 0x71, 0x18,
+	0x27, 0x22, 0x57, 0x81, 0x17, 0x9c, 0x4f, 0xa0, 0xad, 0xd1, 0x63, 0x37, 0x22, 0x9a, 0x5d, 0xc7,
+	0xc9, 0x4b, 0x19, 0xf2, 0x16, 0x45, 0x75, 0x70, 0x5d, 0xcb, 0x0f, 0xae, 0x9d, 0x3f, 0xb0, 0x60,
+	0x1e, 0x65, 0x2f, 0x88, 0x06, 0x27, 0x71, 0x18, 0xf4, 0x98, 0x3b, 0xaa, 0xc4, 0x4c, 0x64, 0x91,
+	0x48, 0x19, 0x34, 0xc1, 0x28, 0xd3, 0xd2, 0x2b, 0x12, 0x12, 0xa8, 0xca, 0xb8, 0x66, 0x51, 0xbe,
+	0xcf, 0xfd, 0x54, 0x08, 0xbd, 0xd8, 0x91, 0x0c, 0x20, 0xae, 0x23, 0x04, 0x24, 0x7e, 0x46, 0xbd,
+	0x61, 0x10, 0x86, 0x01, 0xa7, 0xe5, 0x6b, 0xb3, 0x0a, 0xe5, 0xfc, 0x51, 0x0d, 0xda, 0x42, 0xc1,
+	0x1d, 0xf4, 0x07, 0xfc, 0xc4, 0x8a, 0x17, 0x73, 0xc5, 0xa1, 0x41, 0x24, 0xde, 0x30, 0xd0, 0x34,
+	0x48, 0x71, 0x5a, 0xeb, 0xe5, 0x69, 0xc5, 0x40, 0x70, 0xdc, 0xa7, 0x8f, 0x98, 0x25, 0xc8, 0x93,
+	0xa7, 0x72, 0x80, 0xc4, 0xee, 0x30, 0x6c, 0x33, 0xc7, 0x32, 0x80, 0x61, 0xfb, 0xcd, 0x14, 0x6c,
+	0xbf, 0x8f, 0xa0, 0x23, 0xd8, 0xb0, 0x71, 0xef, 0xce, 0x1a, 0x02, 0x6e, 0xcc, 0x89, 0x6b, 0x50,
+	0xca, 0x9a, 0x3b, 0xb2, 0xe6, 0xdc, 0xeb, 0x6a, 0x4a, 0x4a, 0x3c, 0x78, 0x11, 0x83, 0xf7, 0x34,
+	0xf1, 0x47, 0x97, 0x72, 0xd3, 0xe8, 0x43, 0x47, 0x07, 0x93, 0x7b, 0xd0, 0xc4, 0x6a, 0x52, 0x6f,
+	0x57, 0x2f, 0x3a, 0x4e, 0x42, 0xb6, 0xa1, 0x49, 0xfb, 0x03, 0x2a, 0xfd, 0x0f, 0x62, 0x7a, 0x82,
+	0x38, 0x47, 0x2e, 0x27, 0x40, 0x15, 0x80, 0xd0, 0x82, 0x0a, 0x30, 0x75, 0x3e, 0xc6, 0xaf, 0xa3,
+	0x67, 0x7d, 0x67, 0x15, 0xd3, 0x01, 0x98, 0xd4, 0x6a, 0xe4, 0xce, 0x5f, 0xab, 0x43, 0x5b, 0x03,
+	0xe3, 0x6a, 0x1e, 0x60, 0x87, 0xbd, 0x7e, 0xe0, 0x0f, 0x69, 0x46, 0x13, 0x21, 0xa9, 0x05, 0x28,
+	0xd2, 0xf9, 0x57, 0x03, 0x2f, 0x1e, 0xa3, 0x53, 0x3d, 0x48, 0x44, 0x14, 0xc8, 0x72, 0x0b, 0x50,
+	0xa4, 0xc3, 0x90, 0x8b, 0x46, 0xc7, 0xe5, 0xa1, 0x00, 0x95, 0x67, 0x03, 0x7c, 0x8c, 0x1a, 0xf9,
+	0xd9, 0x00, 0x1f, 0x91, 0xa2, 0x1e, 0x6a, 0x56, 0xe8, 0xa1, 0x0f, 0x61, 0x9d, 0x6b, 0x1c, 0xb1,
+	0x36, 0xbd, 0x82, 0x98, 0x4c, 0xc1, 0xa2, 0x8d, 0x80, 0x7d, 0x96, 0x02, 0x9e, 0x06, 0xbf, 0xc9,
+	0xa3, 0x1b, 0x96, 0x5b, 0x82, 0x23, 0x2d, 0x2e, 0x47, 0x83, 0x96, 0x6f, 0x3d, 0x25, 0x38, 0xa3,
+	0xf5, 0x5f, 0x99, 0xb4, 0x2d, 0x41, 0x5b, 0x80, 0x3b, 0xf3, 0xd0, 0x3e, 0xcd, 0xe2, 0x91, 0x9c,
+	0x94, 0x05, 0xe8, 0xf0, 0xa2, 0x38, 0xd8, 0xbf, 0x0d, 0x9b, 0x4c, 0x8a, 0xce, 0xe2, 0x51, 0x1c,
+	0xc6, 0x83, 0xc9, 0xe9, 0xf8, 0x9c, 0x47, 0x61, 0x83, 0x38, 0x72, 0xfe, 0xad, 0x05, 0x2b, 0x06,
+	0x56, 0x04, 0x34, 0x7e, 0xc0, 0x45, 0x5a, 0x9d, 0xbc, 0x72, 0xc1, 0x5b, 0xd6, 0xd4, 0x21, 0x27,
+	0xe4, 0x81, 0x28, 0xfe, 0x3b, 0x25, 0xbb, 0xb0, 0x28, 0x7b, 0x26, 0x2b, 0x72, 0x29, 0xec, 0x96,
+	0xa5, 0x50, 0xd4, 0x5f, 0x10, 0x15, 0x24, 0x8b, 0x4f, 0xb9, 0x75, 0x4d, 0xfb, 0xec, 0x1b, 0xa5,
+	0x67, 0x6b, 0xcb, 0xfa, 0xba, 0x49, 0x2f, 0x7b, 0xd0, 0x53, 0xc0, 0xd4, 0xf9, 0xdb, 0x16, 0x40,
+	0xde, 0x3b, 0x14, 0x8c, 0x5c, 0xa5, 0x5b, 0xec, 0xec, 0x25, 0x07, 0xa0, 0x8d, 0xaa, 0x4e, 0xb8,
+	0xf2, 0x5d, 0xa2, 0x2d, 0x61, 0x68, 0x5b, 0xbd, 0x0f, 0x8b, 0x83, 0x30, 0x3e, 0x67, 0x5b, 0x2c,
+	0xcb, 0x21, 0x49, 0x45, 0x7a, 0xc3, 0x02, 0x07, 0x3f, 0x11, 0xd0, 0x7c, 0x4b, 0x69, 0x68, 0x5b,
+	0x8a, 0xf3, 0x77, 0x6a, 0xb0, 0x5c, 0xfa, 0xe6, 0xa9, 0xab, 0x8c, 0xec, 0x94, 0x94, 0xe3, 0x94,
+	0xf0, 0x3e, 0x8b, 0xe1, 0x9c, 0xbc, 0xd6, 0x9d, 0xfd, 0x04, 0x16, 0x12, 0xae, 0x7d, 0xa4, 0x6a,
+	0x6a, 0xdc, 0xa0, 0x9a, 0xe6, 0x13, 0xbd, 0x88, 0xd1, 0x78, 0xbf, 0x7f, 0x45, 0x93, 0x2c, 0x60,
+	0x7e, 0x0d, 0xdb, 0xf4, 0xb9, 0x42, 0x5d, 0xd4, 0xe0, 0x6c, 0x2f, 0x7e, 0x1f, 0x16, 0x45, 0x4a,
+	0x89, 0xa2, 0x14, 0x29, 0x91, 0x39, 0x18, 0x09, 0x9d, 0x7f, 0x6e, 0x89, 0xa3, 0x0d, 0x73, 0x0e,
+	0xa7, 0x8f, 0x88, 0xfe, 0x75, 0xb5, 0xc2, 0xd7, 0xfd, 0x82, 0x88, 0xf6, 0xf7, 0xa5, 0xf3, 0x24,
+	0x0e, 0x7c, 0x38, 0x50, 0x1c, 0x0b, 0x99, 0x43, 0xda, 0x78, 0x93, 0x21, 0x75, 0xfe, 0x67, 0x13,
+	0x66, 0x9f, 0x45, 0x57, 0x71, 0xd0, 0x63, 0xd1, 0xf2, 0x21, 0x1d, 0xc6, 0x32, 0xb1, 0x0b, 0x7f,
+	0xe3, 0x8e, 0xce, 0x4e, 0xd0, 0x47, 0x99, 0x88, 0xc6, 0xca, 0x22, 0xee, 0x6e, 0x49, 0x9e, 0x58,
+	0xc9, 0x25, 0x45, 0x83, 0xa0, 0x65, 0x9b, 0xe8, 0x89, 0xa8, 0xa2, 0x94, 0x67, 0xc6, 0x35, 0xb5,
+	0xcc, 0x38, 0x6c, 0x47, 0x24, 0x5f, 0x88, 0x73, 0x15, 0x59, 0x64, 0x16, 0x78, 0x42, 0xb9, 0x6b,
+	0xcf, 0xf6, 0x49, 0x11, 0x78, 0x36, 0x80, 0xb8, 0x97, 0xf2, 0x0a, 0x9c, 0x86, 0xeb, 0x1a, 0x1d,
+	0x84, 0xb6, 0x45, 0x31, 0x97, 0xb5, 0xc5, 0xa7, 0xb8, 0x00, 0x46, 0x85, 0xd4, 0xa7, 0x4a, 0x6f,
+	0xf0, 0x6f, 0x00, 0x9e, 0x38, 0x5a, 0x84, 0x6b, 0xf6, 0x3b, 0x4f, 0x22, 0x99, 0xc9, 0xc3, 0xe5,
+	0x17, 0x7e, 0x18, 0xe2, 0xe9, 0x24, 0x3b, 0xdf, 0x61, 0x39, 0x23, 0x2d, 0xd7, 0x04, 0x62, 0xaf,
+	0x59, 0xc2, 0xac, 0x60, 0x31, 0xcf, 0x73, 0x3e, 0x34, 0x90, 0x1e, 0x2c, 0x5e, 0x30, 0x83, 0xc5,
+	0x2c, 0x83, 0x32, 0xec, 0xb3, 0xd3, 0xcd, 0x39, 0x97, 0xfd, 0xc6, 0x39, 0xc1, 0xbf, 0xec, 0x7c,
+	0x93, 0xb2, 0x53, 0xcc, 0x96, 0xab, 0x41, 0xb0, 0x57, 0x68, 0x15, 0x8f, 0xfc, 0xa0, 0xaf, 0x67,
+	0x78, 0x98, 0x40, 0xf2, 0x88, 0x05, 0x19, 0x33, 0xca, 0x52, 0x37, 0x16, 0x76, 0x6e, 0x0b, 0x11,
+	0x12, 0x62, 0x22, 0xff, 0x62, 0x50, 0x98, 0xba, 0x9c, 0x12, 0x77, 0x62, 0xee, 0x4c, 0xaf, 0x18,
+	0x3b, 0xb1, 0x20, 0x65, 0xce, 0x74, 0x53, 0x65, 0xa4, 0xb0, 0x2a, 0x21, 0x9e, 0x78, 0xad, 0xb2,
+	0xbe, 0xe7, 0x00, 0xdc, 0xbf, 0xe4, 0x6c, 0xb0, 0x51, 0x5b, 0xe3, 0xa7, 0x89, 0x3a, 0xcc, 0xd9,
+	0x85, 0x8e, 0xde, 0x05, 0x32, 0x07, 0x8d, 0xe7, 0x27, 0x07, 0xc7, 0x4b, 0xb7, 0x48, 0x1b, 0x66,
+	0x4f, 0x0f, 0xce, 0xce, 0x8e, 0x0e, 0xf6, 0x97, 0x2c, 0xd2, 0x81, 0xb9, 0xbd, 0xdd, 0xe3, 0xbd,
+	0x03, 0x2c, 0xd5, 0xb0, 0xb4, 0xbb, 0xb7, 0x77, 0x70, 0x72, 0x76, 0xb0, 0xbf, 0x54, 0x77, 0x7e,
+	0xa7, 0x06, 0x6d, 0xad, 0x6f, 0x37, 0xf8, 0x7f, 0x38, 0xa2, 0x18, 0x79, 0xcf, 0xcf, 0x8a, 0x1a,
+	0xae, 0x06, 0xc1, 0x45, 0xab, 0xbc, 0x8f, 0x3a, 0xc3, 0xaa, 0x32, 0x8e, 0x36, 0x9f, 0x45, 0x33,
+	0xe2, 0x61, 0x02, 0x51, 0x06, 0xfc, 0x5e, 0x8f, 0x8e, 0x32, 0x9e, 0x48, 0xc1, 0x57, 0x85, 0x0e,
+	0x22, 0x3b, 0x72, 0x3e, 0x66, 0xd8, 0x7c, 0xdc, 0x29, 0x0f, 0x2e, 0x3b, 0x7b, 0xd2, 0x27, 0xc4,
+	0xf9, 0x01, 0xb4, 0x14, 0xcc, 0xf8, 0xf8, 0x9b, 0x46, 0xc9, 0xf9, 0x12, 0xc8, 0x6e, 0xbf, 0x2f,
+	0x18, 0x2b, 0x5f, 0x3a, 0x5f, 0xc9, 0x96, 0xb1, 0x92, 0x2b, 0x56, 0x54, 0xad, 0x72, 0x45, 0x39,
+	0x07, 0xd0, 0x3e, 0xd1, 0xd2, 0xd0, 0x99, 0xea, 0x90, 0x09, 0xe8, 0x42, 0xdd, 0x68, 0x10, 0xad,
+	0xc1, 0x9a, 0xde, 0xa0, 0xf3, 0x8b, 0x40, 0x30, 0x37, 0x44, 0xf5, 0x4f, 0x85, 0x5f, 0x54, 0x14,
+	0x59, 0x0b, 0xbf, 0x08, 0x18, 0x0b, 0xbf, 0xec, 0xc2, 0x8a, 0x51, 0x51, 0x7c, 0xd8, 0x3d, 0x8c,
+	0xfc, 0x33, 0x90, 0xdc, 0xf5, 0x17, 0xcc, 0xb1, 0x75, 0x15, 0x1e, 0xcd, 0x57, 0x29, 0x75, 0xba,
+	0x51, 0xf1, 0x47, 0x16, 0xcc, 0x8a, 0x4f, 0xd3, 0x85, 0x57, 0xbb, 0x50, 0x61, 0xc0, 0xaa, 0xf3,
+	0x82, 0xcb, 0x3a, 0xae, 0x5e, 0xa5, 0xe3, 0x30, 0x91, 0xd2, 0xcf, 0x2e, 0x99, 0xbf, 0xd6, 0x72,
+	0xd9, 0x6f, 0x19, 0x51, 0x68, 0xe6, 0x11, 0x85, 0xaa, 0xb4, 0x77, 0xbe, 0x43, 0x95, 0xe0, 0x32,
+	0x19, 0x4a, 0x7c, 0x80, 0x3a, 0x35, 0x78, 0x0c, 0xab, 0x26, 0x38, 0x1f, 0x2f, 0xc1, 0xa2, 0x38,
+	0x5e, 0x82, 0xd4, 0x55, 0x78, 0x4c, 0xb8, 0xdd, 0xa7, 0x21, 0xcd, 0xe8, 0x6e, 0x18, 0x16, 0xf9,
+	0xdf, 0x86, 0xcd, 0x0a, 0x9c, 0xb0, 0xe1, 0x9e, 0xc0, 0xf2, 0x3e, 0x3d, 0x1f, 0x0f, 0x8e, 0xe8,
+	0x55, 0x7e, 0x80, 0x48, 0xa0, 0x91, 0x5e, 0xc6, 0xd7, 0x62, 0x6e, 0xd9, 0x6f, 0xf2, 0x16, 0x40,
+	0x88, 0x34, 0x5e, 0x3a, 0xa2, 0x3d, 0x99, 0x00, 0xcb, 0x20, 0xa7, 0x23, 0xda, 0x73, 0x3e, 0x04,
+	0xa2, 0xf3, 0x11, 0x9f, 0x80, 0xfb, 0xc4, 0xf8, 0xdc, 0x4b, 0x27, 0x69, 0x46, 0x87, 0x32, 0xb3,
+	0x57, 0x07, 0x39, 0xef, 0x43, 0xe7, 0xc4, 0xc7, 0x8c, 0x72, 0x71, 0x07, 0x02, 0x43, 0x05, 0xfe,
+	0x04, 0x45, 0x59, 0x85, 0x0a, 0x18, 0xda, 0xf9, 0x4f, 0x35, 0x98, 0xe1, 0x94, 0xc8, 0xb5, 0x4f,
+	0xd3, 0x2c, 0x88, 0xf8, 0xb1, 0x96, 0xe0, 0xaa, 0x81, 0x4a, 0xb2, 0x51, 0xab, 0x90, 0x0d, 0x61,
+	0xbc, 0xcb, 0xd4, 0x40, 0x21, 0x04, 0x06, 0x8c, 0xc5, 0x56, 0x82, 0x21, 0xe5, 0x57, 0x61, 0x1a,
+	0x22, 0xb6, 0x22, 0x01, 0x85, 0x68, 0x52, 0xbe, 0x1b, 0xf1, 0xfe, 0x49, 0xa1, 0x15, 0xe2, 0xa0,
+	0x83, 0x2a, 0xf7, 0xbc, 0x59, 0x2e, 0x35, 0x45, 0x78, 0x79, 0x6f, 0x9b, 0x7b, 0x83, 0xbd, 0x8d,
+	0x5b, 0xf4, 0x3a, 0xa8, 0xa4, 0xec, 0xc1, 0x1c, 0x13, 0xa6, 0xec, 0x09, 0x2c, 0x3d, 0xa1, 0xd4,
+	0xa5, 0xa3, 0x38, 0x91, 0x97, 0x4d, 0x9c, 0xdf, 0xb5, 0x60, 0x49, 0xd8, 0x33, 0x0a, 0x47, 0xde,
+	0x31, 0x8c, 0x1f, 0xab, 0xea, 0x34, 0xe4, 0x5d, 0x98, 0x67, 0xee, 0xbf, 0x0a, 0x86, 0x89, 0x58,
+	0x9e, 0x01, 0xc4, 0x7e, 0xcb, 0xf8, 0xfe, 0x30, 0x08, 0xc5, 0x24, 0xe8, 0x20, 0x19, 0x4f, 0x4b,
+	0x7c, 0x71, 0xf0, 0x6e, 0xb9, 0xaa, 0xec, 0x9c, 0xc0, 0xb2, 0xd6, 0x5f, 0x21, 0x74, 0x9f, 0x80,
+	0xcc, 0x51, 0xe1, 0x21, 0x33, 0xbe, 0x76, 0x36, 0x4c, 0xd3, 0x2c, 0xaf, 0x66, 0x10, 0x3b, 0x7f,
+	0x5a, 0x83, 0x15, 0x6e, 0xa6, 0x0a, 0x27, 0x40, 0x25, 0x3e, 0xcf, 0x70, 0xbb, 0x9c, 0x2f, 0x8a,
+	0xc3, 0x5b, 0xae, 0x28, 0x93, 0x0f, 0xde, 0xd0, 0xb4, 0x56, 0x59, 0x19, 0x7c, 0x78, 0x3e, 0x81,
+	0x76, 0x5e, 0x4a, 0x45, 0x4c, 0x60, 0xa3, 0xa2, 0x1e, 0xea, 0x86, 0xc3, 0x5b, 0xae, 0x4e, 0x4d,
+	0xde, 0x45, 0x25, 0x4c, 0x13, 0x4f, 0x46, 0xa1, 0x98, 0x48, 0xe0, 0xf1, 0xad, 0x0e, 0x2d, 0xcf,
+	0x40, 0xbd, 0x6a, 0x06, 0x6e, 0x18, 0xdf, 0xaa, 0x08, 0x51, 0xb3, 0x3a, 0x42, 0x84, 0x87, 0xe9,
+	0x32, 0x87, 0x41, 0x05, 0x07, 0x1b, 0xae, 0x09, 0x7c, 0x3c, 0x0b, 0xcd, 0xb4, 0x17, 0x8f, 0xa8,
+	0x73, 0x0a, 0xab, 0xe6, 0x28, 0xab, 0xb9, 0x5b, 0xc0, 0x9b, 0x36, 0xb4, 0x5f, 0xf0, 0x0f, 0xe5,
+	0x80, 0x3e, 0x61, 0x48, 0xe9, 0xe1, 0x99, 0xa4, 0x18, 0x4a, 0x38, 0x78, 0x85, 0x73, 0x6a, 0x84,
+	0x3c, 0x7e, 0xc7, 0x82, 0x15, 0x03, 0x2c, 0x9a, 0x2a, 0x3a, 0xef, 0x56, 0x85, 0xf3, 0x5e, 0xc8,
+	0x12, 0xe6, 0x71, 0x46, 0x1d, 0x64, 0x06, 0x08, 0xea, 0xc5, 0x00, 0x01, 0xa6, 0x7a, 0x46, 0xfe,
+	0x28, 0xbd, 0x8c, 0x33, 0x61, 0x97, 0xab, 0xb2, 0xf3, 0x10, 0xc8, 0xb3, 0x61, 0xb1, 0xb7, 0x46,
+	0x0d, 0xab, 0x50, 0xe3, 0x1f, 0x59, 0xb0, 0xf2, 0x6c, 0xf8, 0x67, 0xf3, 0x25, 0xa2, 0x7e, 0xfa,
+	0x32, 0x18, 0x8d, 0x68, 0x5f, 0xd8, 0x56, 0x3a, 0xc8, 0xd9, 0x84, 0x8d, 0x27, 0x3c, 0x0e, 0x1e,
+	0x44, 0x83, 0x27, 0x41, 0x98, 0xa9, 0x5b, 0x03, 0x8e, 0x0f, 0x6f, 0xf1, 0x29, 0x9b, 0x42, 0xc0,
+	0x7d, 0xdc, 0x90, 0xed, 0x38, 0x75, 0xee, 0xe3, 0x86, 0xf1, 0x35, 0xbf, 0xa9, 0x17, 0x4d, 0x98,
+	0xa7, 0xdf, 0x72, 0xd9, 0x6f, 0x66, 0xac, 0xd0, 0x61, 0x7c, 0x45, 0x99, 0xff, 0xde, 0x72, 0x45,
+	0xc9, 0x39, 0x82, 0x6e, 0x99, 0xb9, 0x76, 0xb7, 0x04, 0x19, 0xd2, 0xbe, 0xe0, 0x2f, 0x8b, 0xc8,
+	0xad, 0x4f, 0xa3, 0x80, 0xf6, 0x45, 0x1b, 0xa2, 0xe4, 0x7c, 0x1f, 0xcf, 0xf1, 0x69, 0x22, 0x2e,
+	0x73, 0xe8, 0x26, 0xc8, 0x0d, 0x37, 0x20, 0xfe, 0x25, 0xcb, 0x74, 0x50, 0xb5, 0x6e, 0xce, 0x48,
+	0x96, 0x59, 0xbe, 0x35, 0x33, 0xcb, 0x17, 0x03, 0xad, 0xe9, 0xc0, 0x63, 0xf7, 0x78, 0x44, 0xa6,
+	0x83, 0x2c, 0xf3, 0xbc, 0xbe, 0xe1, 0xd0, 0x4f, 0x26, 0x22, 0x14, 0x20, 0x8b, 0x6c, 0xa0, 0xc6,
+	0xc3, 0x91, 0x70, 0xa2, 0xd9, 0x6f, 0x14, 0x0a, 0xb5, 0x53, 0x79, 0x51, 0x2a, 0xa2, 0x4d, 0x06,
+	0xcc, 0xf9, 0x9b, 0x16, 0x6c, 0x1c, 0x05, 0x5f, 0x8f, 0x83, 0x7e, 0x90, 0x4d, 0x0e, 0x83, 0x34,
+	0x8b, 0x13, 0x75, 0x15, 0xec, 0xfb, 0x25, 0x0d, 0x3f, 0xc5, 0xbd, 0xd5, 0xc8, 0xd0, 0xd4, 0x4c,
+	0x33, 0x3f, 0x11, 0xc6, 0xb5, 0xb0, 0xdf, 0x73, 0x08, 0x7e, 0x1e, 0x8d, 0xfa, 0x1c, 0x2b, 0xec,
+	0x77, 0x59, 0x76, 0xfe, 0x87, 0x05, 0xcb, 0xaa, 0x33, 0xa7, 0x42, 0xe6, 0xcd, 0x1d, 0x98, 0x7b,
+	0x0b, 0x39, 0x00, 0x53, 0x6c, 0x8c, 0x03, 0xf4, 0x7c, 0xa3, 0x69, 0xb8, 0x15, 0x18, 0x8c, 0x42,
+	0x9b, 0x27, 0xe9, 0xba, 0x2b, 0x51, 0x85, 0xc2, 0xa3, 0x40, 0xfd, 0x58, 0x32, 0x8f, 0x5a, 0x37,
+	0xdc, 0x32, 0x42, 0xde, 0xd6, 0x35, 0x4f, 0x3c, 0xb9, 0xc6, 0x2c, 0x23, 0x1c, 0x17, 0xba, 0xe5,
+	0xd1, 0x17, 0x32, 0xfb, 0x21, 0xb4, 0xe4, 0xba, 0x97, 0x3a, 0xb0, 0xab, 0x82, 0xb3, 0x85, 0x41,
+	0x72, 0x73, 0x52, 0xe7, 0x1f, 0x5b, 0xd0, 0x7d, 0x16, 0xfd, 0x98, 0xf6, 0xb2, 0xd3, 0xeb, 0x20,
+	0xeb, 0x5d, 0x3e, 0xf1, 0xc7, 0xa1, 0xba, 0x37, 0x2a, 0x2e, 0xb7, 0x28, 0x9b, 0x49, 0x94, 0x70,
+	0x71, 0x73, 0x2d, 0xc0, 0x05, 0x4f, 0x44, 0xab, 0x34, 0x10, 0x3f, 0x8f, 0x18, 0x47, 0x32, 0x12,
+	0xc2, 0x0b, 0x38, 0x9d, 0x2c, 0xb1, 0x0d, 0x93, 0x78, 0xb9, 0x46, 0x50, 0x65, 0x56, 0x23, 0xa4,
+	0x3e, 0x3f, 0xc1, 0x98, 0x73, 0x79, 0xc1, 0xf9, 0x14, 0x36, 0x2b, 0x7a, 0x97, 0x5b, 0x8b, 0xda,
+	0x20, 0xc9, 0x83, 0x17, 0x0d, 0xe4, 0x5c, 0xc0, 0x06, 0x57, 0x24, 0x28, 0x81, 0x3c, 0x4f, 0xea,
+	0x67, 0x92, 0xd7, 0x7c, 0x40, 0x6a, 0xfa, 0x80, 0xa0, 0x39, 0x5d, 0x6e, 0x47, 0x58, 0xcc, 0x1f,
+	0x43, 0xf7, 0x94, 0x05, 0x3a, 0x0e, 0xe3, 0xb0, 0x5f, 0x70, 0x8e, 0xcc, 0x28, 0x8d, 0x55, 0x8c,
+	0xd2, 0xa0, 0x29, 0x5e, 0x51, 0x37, 0x0f, 0xa7, 0xee, 0xa1, 0xe0, 0x85, 0x55, 0xc8, 0x7f, 0x66,
+	0xe9, 0x0a, 0xae, 0xb0, 0x56, 0xcd, 0x65, 0x67, 0xdd, 0xb8, 0xec, 0x6a, 0xe6, 0xb2, 0x43, 0x3d,
+	0xc1, 0x7c, 0x6b, 0x2f, 0xbe, 0xb8, 0x48, 0xa9, 0x0a, 0x75, 0xe9, 0x30, 0x8c, 0x96, 0xe3, 0x2c,
+	0xe0, 0x5e, 0x4e, 0xaf, 0x98, 0x3f, 0xc2, 0x67, 0xbb, 0x00, 0xc5, 0x0c, 0xb6, 0xc5, 0xbc, 0x93,
+	0x07, 0x08, 0x7c, 0xcd, 0x02, 0x96, 0x87, 0x36, 0x41, 0xdf, 0x0b, 0x22, 0xa9, 0x30, 0x72, 0x08,
+	0x33, 0x6b, 0x45, 0x29, 0x1e, 0xcb, 0x85, 0xaa, 0x83, 0x90, 0x02, 0x43, 0x00, 0x41, 0xa4, 0x2f,
+	0x4d, 0x1d, 0x84, 0x5f, 0x88, 0x45, 0x8c, 0xea, 0xab, 0xf3, 0xcd, 0x86, 0x6b, 0xc0, 0x8c, 0x43,
+	0x5b, 0x6e, 0xb9, 0xa8, 0xb2, 0xf3, 0x77, 0x2d, 0xd8, 0xac, 0x18, 0x7a, 0x21, 0xb4, 0xfb, 0xb0,
+	0x7c, 0xa1, 0x90, 0x72, 0x78, 0xf8, 0x82, 0x5d, 0xcf, 0x73, 0x6b, 0xf5, 0x21, 0x71, 0xcb, 0x15,
+	0x50, 0x71, 0xb0, 0x93, 0x28, 0x3e, 0xe0, 0x46, 0xae, 0x6c, 0x19, 0xe1, 0x5c, 0xc0, 0xfa, 0x63,
+	0x3f, 0xeb, 0x5d, 0xea, 0xd1, 0x03, 0x79, 0x33, 0x7c, 0x56, 0xf8, 0xd0, 0x62, 0x09, 0x14, 0x5d,
+	0x6c, 0x89, 0x96, 0x46, 0x83, 0xf2, 0xc8, 0xb5, 0x33, 0x54, 0x09, 0x73, 0x4e, 0x60, 0xa3, 0xd4,
+	0x8e, 0xf8, 0xec, 0x0f, 0x4a, 0xce, 0xbc, 0xcc, 0x2b, 0x2c, 0x13, 0x6b, 0x7e, 0xfd, 0x33, 0x58,
+	0xd2, 0x17, 0x23, 0xda, 0xb6, 0xe4, 0x03, 0xd3, 0x12, 0x36, 0x0d, 0x3e, 0x63, 0xe9, 0xea, 0x74,
+	0x4e, 0x0f, 0x3a, 0xba, 0x35, 0x48, 0x1e, 0x68, 0x49, 0x82, 0x37, 0x2c, 0x7f, 0x45, 0xc4, 0xee,
+	0xcc, 0xb0, 0xaa, 0xe2, 0x56, 0x81, 0x70, 0x12, 0x75, 0x18, 0x2a, 0x82, 0xb3, 0x60, 0x48, 0x8f,
+	0xe2, 0xde, 0x4b, 0xda, 0x2f, 0x24, 0x60, 0xfc, 0x37, 0x0b, 0x96, 0x34, 0xe4, 0xb8, 0xf7, 0x92,
+	0x56, 0xa6, 0x23, 0x5a, 0xdf, 0x29, 0xf3, 0xa6, 0x36, 0x3d, 0xf3, 0x26, 0x4f, 0x8f, 0xac, 0x1b,
+	0xe9, 0x91, 0xb8, 0x88, 0xd2, 0x2b, 0x33, 0xd7, 0x56, 0x83, 0x28, 0xdf, 0x50, 0x10, 0x34, 0x35,
+	0xdf, 0x30, 0xa7, 0xc0, 0x89, 0xe7, 0x59, 0xd9, 0xa9, 0x48, 0x77, 0xd4, 0x41, 0xce, 0x1f, 0x5a,
+	0xb0, 0x59, 0x31, 0x12, 0x42, 0x1a, 0x7e, 0x19, 0x36, 0x0b, 0x69, 0x0b, 0x5a, 0x66, 0x0b, 0xcf,
+	0x41, 0x99, 0x4e, 0x50, 0xba, 0x62, 0x53, 0xab, 0xb8, 0x62, 0xf3, 0x08, 0x66, 0xcf, 0xd9, 0x08,
+	0xcb, 0x83, 0x1b, 0xe9, 0x2a, 0x15, 0x67, 0xc0, 0x95, 0x74, 0xce, 0xd7, 0xb0, 0xc9, 0xed, 0x7e,
+	0x16, 0x98, 0x38, 0xf1, 0x7b, 0x2f, 0xb5, 0x0b, 0xbe, 0x2c, 0x05, 0xa7, 0x17, 0x8c, 0x02, 0x16,
+	0xa1, 0xd1, 0xef, 0x26, 0x95, 0xe0, 0x32, 0x6d, 0x3b, 0x8c, 0x07, 0x1e, 0x8d, 0xb2, 0x24, 0x50,
+	0xab, 0xa5, 0x08, 0x76, 0x7e, 0x08, 0x76, 0x55, 0x93, 0x62, 0x94, 0xf0, 0xb6, 0x6a, 0xd4, 0x4b,
+	0x26, 0xa3, 0x8c, 0xf6, 0xbd, 0x11, 0x47, 0x8a, 0x4d, 0xa2, 0x8c, 0x40, 0xd1, 0x93, 0x07, 0x3c,
+	0xa8, 0x23, 0x8c, 0x38, 0xd8, 0xdf, 0x68, 0xa8, 0xe3, 0x5d, 0x7e, 0x57, 0x41, 0x18, 0x82, 0xef,
+	0x56, 0x5d, 0xe4, 0xb8, 0xe9, 0xfe, 0x69, 0xad, 0x14, 0x7f, 0xe5, 0xf7, 0x7c, 0x58, 0x44, 0xa2,
+	0xae, 0xce, 0xd0, 0x05, 0x04, 0x47, 0x22, 0xcf, 0x46, 0xd3, 0x1f, 0x19, 0x29, 0x82, 0xcb, 0xf7,
+	0x65, 0x9b, 0x55, 0xf7, 0x65, 0x6f, 0x3a, 0x35, 0x17, 0xd9, 0x70, 0x54, 0x4a, 0xc5, 0xac, 0x76,
+	0x06, 0x23, 0x60, 0xd8, 0x9f, 0xe2, 0xed, 0x52, 0x7e, 0x16, 0xb1, 0x58, 0x75, 0xb7, 0xb4, 0x42,
+	0x36, 0x5b, 0x22, 0xfd, 0xb6, 0x8c, 0x22, 0x4f, 0x00, 0x78, 0x5b, 0xcc, 0x26, 0x02, 0x16, 0x0c,
+	0x7e, 0xaf, 0xe2, 0xce, 0x85, 0x18, 0x7b, 0x76, 0x82, 0x38, 0x4e, 0x28, 0xbb, 0x56, 0xaf, 0xd5,
+	0x74, 0x7e, 0x03, 0xda, 0x1a, 0x8a, 0xac, 0xc1, 0xf2, 0xde, 0xf3, 0xe7, 0x27, 0x07, 0xee, 0xee,
+	0xd9, 0xb3, 0x2f, 0x0f, 0xbc, 0xbd, 0xa3, 0xe7, 0xa7, 0x07, 0x4b, 0xb7, 0xf0, 0x0a, 0xfd, 0x93,
+	0xe7, 0xee, 0x9e, 0x04, 0x58, 0x64, 0x09, 0x3a, 0x8f, 0xdd, 0x83, 0xdd, 0xbd, 0x43, 0x01, 0xa9,
+	0x91, 0x55, 0x58, 0x7a, 0xf2, 0xe2, 0x78, 0xff, 0xd9, 0xf1, 0x53, 0x4f, 0x05, 0x91, 0xeb, 0xce,
+	0x4f, 0xea, 0x40, 0x74, 0x39, 0x11, 0xda, 0xf0, 0x23, 0xe8, 0xe8, 0x49, 0xbe, 0x85, 0xa4, 0x1a,
+	0xf3, 0x36, 0xa5, 0x41, 0x49, 0x1e, 0xc3, 0x82, 0x76, 0x4e, 0x8a, 0x75, 0x79, 0x4c, 0xc3, 0x9e,
+	0xfe, 0xed, 0x6e, 0xa1, 0x06, 0xba, 0xf1, 0xe6, 0x2d, 0xbb, 0x6e, 0x7d, 0xba, 0x46, 0x2e, 0x90,
+	0x92, 0xcf, 0x61, 0x29, 0x88, 0x0a, 0xd5, 0x6f, 0x38, 0x5e, 0x2b, 0x11, 0xab, 0x87, 0x10, 0x9a,
+	0xc6, 0x43, 0x08, 0xe5, 0x41, 0xba, 0xcf, 0xff, 0x68, 0x0f, 0x21, 0xfc, 0x25, 0x80, 0x1c, 0x86,
+	0x53, 0x80, 0x47, 0x1d, 0xde, 0xde, 0xe1, 0xee, 0xf1, 0xf1, 0xc1, 0xd1, 0xd2, 0x2d, 0x42, 0x60,
+	0x81, 0xcd, 0xc6, 0xbe, 0x82, 0x59, 0x08, 0xdb, 0xdd, 0xe3, 0x73, 0x29, 0x60, 0x6c, 0xaa, 0x9e,
+	0x1d, 0x17, 0xa0, 0x75, 0xe7, 0x27, 0x16, 0xac, 0x70, 0xc5, 0x90, 0xc4, 0x17, 0x41, 0xa8, 0x74,
+	0xd1, 0xc7, 0xc6, 0xc3, 0x0d, 0x52, 0xc6, 0x2a, 0x28, 0xef, 0x8b, 0x62, 0xde, 0x63, 0x5c, 0x67,
+	0xfd, 0xb1, 0xb8, 0xe6, 0x9e, 0xd2, 0x9e, 0xd4, 0x4c, 0x26, 0xd0, 0x79, 0x00, 0x6d, 0xad, 0x2a,
+	0x99, 0x87, 0xd6, 0xd3, 0xe7, 0xee, 0xf3, 0x17, 0x67, 0xcf, 0x8e, 0x51, 0xf6, 0xe6, 0xa0, 0x71,
+	0x78, 0xb0, 0x7b, 0xb2, 0x64, 0x91, 0x59, 0xa8, 0xef, 0x9d, 0xbc, 0x58, 0xaa, 0x39, 0xc7, 0xb0,
+	0x6a, 0xb6, 0xaf, 0x3d, 0x19, 0xc0, 0x41, 0x42, 0x71, 0xc9, 0x22, 0xb3, 0xf3, 0x92, 0x71, 0xd4,
+	0xf3, 0x33, 0x2a, 0xbd, 0xda, 0x1c, 0xe0, 0xfc, 0x43, 0x0b, 0x56, 0x8f, 0xe2, 0xf8, 0xe5, 0x78,
+	0xb4, 0x17, 0x24, 0xbd, 0x71, 0xa0, 0x5c, 0x92, 0xaa, 0x28, 0x7e, 0xa7, 0x10, 0xa9, 0xd5, 0x62,
+	0xec, 0xea, 0x18, 0xa3, 0x66, 0xc6, 0xd8, 0x25, 0x5c, 0xd7, 0x6d, 0x75, 0x53, 0xb7, 0x75, 0x61,
+	0x96, 0x9f, 0x24, 0xa9, 0x5b, 0xf7, 0xa2, 0xe8, 0xfc, 0xd7, 0x1a, 0x2c, 0x88, 0xc0, 0xb8, 0xe8,
+	0xdd, 0x9b, 0x76, 0x4b, 0xde, 0x5c, 0xf0, 0x4c, 0x7d, 0x5a, 0x82, 0x1b, 0xb4, 0xb2, 0x17, 0xf5,
+	0x02, 0xad, 0x80, 0xe3, 0x36, 0xa1, 0x60, 0xea, 0xb4, 0x4b, 0xb8, 0x9c, 0x25, 0x04, 0x72, 0x8e,
+	0xc7, 0xd9, 0x20, 0xd6, 0x7b, 0xc1, 0x2d, 0xdc, 0x12, 0xdc, 0xa0, 0x95, 0xbd, 0x98, 0x29, 0xd0,
+	0x6a, 0xbd, 0x50, 0x30, 0xd5, 0x8b, 0x59, 0xde, 0x8b, 0x12, 0x02, 0x3d, 0x84, 0x4b, 0x3f, 0xf5,
+	0xe2, 0xf3, 0x8b, 0x71, 0xda, 0xf3, 0xb3, 0x38, 0x11, 0x97, 0x6d, 0x0a, 0x50, 0xe7, 0x87, 0xb0,
+	0x56, 0x10, 0x03, 0x21, 0x58, 0x8f, 0x60, 0xae, 0xc7, 0x41, 0xd2, 0x02, 0x5c, 0x33, 0x0f, 0x3b,
+	0x64, 0x05, 0x45, 0x86, 0x1b, 0x24, 0x86, 0x5b, 0xf6, 0xe2, 0xe1, 0xc8, 0xcf, 0x02, 0xfe, 0xe8,
+	0x8f, 0xb4, 0xcd, 0x7e, 0xaf, 0x06, 0xab, 0x52, 0x51, 0xe9, 0xf8, 0xf2, 0xbe, 0x64, 0xbd, 0xd1,
+	0x3b, 0x0e, 0xb5, 0xd7, 0xec, 0xa3, 0x05, 0x59, 0x7b, 0x0f, 0x16, 0x64, 0x56, 0x87, 0xc7, 0x6e,
+	0x60, 0xb3, 0xf9, 0x9b, 0x73, 0x0b, 0x50, 0x16, 0xfd, 0x0e, 0xa2, 0x01, 0x4d, 0x46, 0x49, 0x20,
+	0x2c, 0xb3, 0x96, 0xab, 0x83, 0xd8, 0xab, 0x41, 0xb2, 0x0e, 0xb7, 0x4c, 0xfb, 0x62, 0xa7, 0x2c,
+	0xc1, 0x91, 0xf6, 0x5c, 0x6c, 0x62, 0xe3, 0xd1, 0x20, 0xf1, 0xfb, 0xec, 0x7d, 0x2e, 0x8c, 0x6b,
+	0x95, 0xe0, 0xce, 0x19, 0x6c, 0x56, 0x0c, 0x9e, 0x98, 0x8c, 0x5f, 0xd4, 0xae, 0xe1, 0xf3, 0xc9,
+	0xb8, 0x5d, 0x50, 0xfe, 0x46, 0x35, 0x45, 0x8c, 0x11, 0x58, 0x34, 0xe9, 0x77, 0xc3, 0xc0, 0x4f,
+	0x55, 0x7e, 0xb1, 0xf3, 0xbf, 0x2d, 0x58, 0x10, 0x15, 0x05, 0xe6, 0xe7, 0x3a, 0x0d, 0x46, 0xb6,
+	0xb6, 0x39, 0x21, 0x65, 0x04, 0xbb, 0x93, 0xce, 0xbd, 0x11, 0xcf, 0x7c, 0x84, 0xa3, 0x08, 0xc6,
+	0xe0, 0x92, 0xe6, 0xa8, 0xf9, 0xbc, 0xe7, 0xdd, 0xe6, 0x56, 0x1d, 0x83, 0x4b, 0x65, 0x8c, 0xf6,
+	0x74, 0xc8, 0x8c, 0xfe, 0x74, 0x88, 0xf3, 0x19, 0x74, 0xd8, 0x67, 0x7f, 0xe1, 0x8f, 0xf0, 0xc2,
+	0x7e, 0x9e, 0xd0, 0xc3, 0xbd, 0x61, 0x5e, 0x98, 0x6e, 0x94, 0x39, 0x7f, 0xdd, 0xe2, 0xe7, 0x86,
+	0x6a, 0x54, 0xb5, 0x25, 0x63, 0xce, 0xd2, 0x9a, 0x39, 0x4b, 0xb2, 0x82, 0x22, 0x23, 0x9f, 0xc2,
+	0xa2, 0x7c, 0x12, 0x40, 0x7e, 0x4f, 0xcd, 0x70, 0xb7, 0xf4, 0x8e, 0xba, 0x45, 0x5a, 0xe7, 0x04,
+	0xa3, 0x37, 0x78, 0xfe, 0x97, 0xed, 0xb1, 0x6b, 0x1b, 0xfa, 0x31, 0xe3, 0x4f, 0x15, 0x80, 0x71,
+	0x7e, 0xbf, 0x0e, 0x8b, 0x39, 0xaf, 0x53, 0x99, 0x36, 0x21, 0x6e, 0x85, 0x68, 0x0e, 0x54, 0xc3,
+	0x35, 0x81, 0x37, 0x84, 0xfe, 0xea, 0xdf, 0x35, 0xf4, 0x57, 0xaf, 0x0e, 0xfd, 0xbd, 0xee, 0x4a,
+	0x8b, 0x79, 0x59, 0xa5, 0x59, 0x7a, 0xfc, 0x44, 0x04, 0xd4, 0x79, 0x10, 0x70, 0x26, 0x0f, 0xa8,
+	0x33, 0x00, 0xca, 0x21, 0xef, 0x25, 0xfa, 0x0f, 0xdc, 0xdf, 0xe7, 0xda, 0xb5, 0x08, 0xc6, 0x65,
+	0xcd, 0x41, 0x5a, 0x6a, 0xc4, 0x1c, 0xd7, 0xda, 0x45, 0x38, 0x77, 0x6b, 0xd8, 0xa7, 0xe4, 0x6c,
+	0x5b, 0x9c, 0xb6, 0x08, 0xe7, 0x17, 0x39, 0x18, 0x4c, 0x63, 0xcc, 0x1f, 0x8e, 0x28, 0x23, 0x9c,
+	0x17, 0x30, 0xff, 0x22, 0xc2, 0xdb, 0xff, 0x7d, 0xed, 0xb6, 0xaf, 0xb4, 0x5a, 0x5a, 0x6e, 0xa3,
+	0x18, 0xa2, 0xae, 0x99, 0x21, 0xea, 0x75, 0x98, 0x49, 0x83, 0x41, 0x44, 0xf9, 0xca, 0x9c, 0x73,
+	0x45, 0x09, 0xdf, 0xef, 0x60, 0xba, 0xe1, 0x74, 0x12, 0xf5, 0x9e, 0x04, 0x34, 0xec, 0xa7, 0xe4,
+	0x63, 0xe8, 0x46, 0xf4, 0x55, 0xe6, 0xf1, 0x8f, 0xab, 0x12, 0x85, 0xa9, 0x78, 0x74, 0x44, 0x45,
+	0xd7, 0x05, 0x3c, 0xf3, 0x83, 0x50, 0xf7, 0x2b, 0x1b, 0xee, 0x74, 0x02, 0xac, 0xcd, 0x82, 0x2d,
+	0x26, 0x45, 0x4a, 0x7b, 0x09, 0x95, 0x17, 0x0f, 0xa7, 0x13, 0xe4, 0xef, 0xbb, 0x8c, 0xa3, 0x84,
+	0x5e, 0xc5, 0xa8, 0x6e, 0x05, 0x41, 0x9e, 0x22, 0xd6, 0x72, 0x6f, 0xa4, 0x41, 0x87, 0x48, 0xbd,
+	0x60, 0x23, 0x2e, 0xc3, 0xca, 0xb2, 0xf3, 0x87, 0x4d, 0xb0, 0xab, 0x96, 0x5f, 0x6e, 0x9a, 0x4d,
+	0xc9, 0xaa, 0x59, 0x87, 0x99, 0xf3, 0x38, 0x79, 0xa9, 0xec, 0x32, 0x51, 0x22, 0x8f, 0xa5, 0x60,
+	0xf5, 0x14, 0x3b, 0x61, 0xa7, 0xaf, 0xab, 0x3b, 0xa2, 0xc6, 0xd2, 0x74, 0x4b, 0xf4, 0x18, 0xfe,
+	0x32, 0x06, 0x63, 0x48, 0x55, 0x32, 0xdc, 0x34, 0x26, 0xe5, 0x0a, 0xe4, 0x0c, 0x36, 0x65, 0x68,
+	0xbc, 0xcc, 0xad, 0x79, 0x23, 0xb7, 0xe9, 0x15, 0x6f, 0x14, 0xa4, 0x99, 0xd7, 0x0b, 0x12, 0xc3,
+	0x99, 0x33, 0xad, 0xb9, 0xa2, 0x0d, 0x77, 0x3a, 0x01, 0xf9, 0x0c, 0xf5, 0xac, 0x2f, 0x76, 0x5c,
+	0x7e, 0xe2, 0x36, 0x67, 0x24, 0x58, 0x1b, 0x4b, 0xc9, 0x2d, 0x12, 0x93, 0xcf, 0xa5, 0x72, 0x60,
+	0x53, 0x98, 0x4e, 0xa2, 0x1e, 0x5b, 0xc5, 0xa6, 0x86, 0xcf, 0x97, 0x8c, 0x5b, 0xa4, 0x26, 0xbb,
+	0x4a, 0x0f, 0xe4, 0x1c, 0xe0, 0x26, 0x0e, 0x25, 0x72, 0xb4, 0x4d, 0xc4, 0x75, 0x2e, 0xff, 0x3c,
+	0xe4, 0xaf, 0x36, 0xcd, 0xb9, 0x3a, 0x08, 0x29, 0x90, 0x52, 0x3e, 0xfc, 0xd1, 0x11, 0xd9, 0x1d,
+	0x39, 0xc8, 0xf9, 0x5b, 0x16, 0x10, 0x7c, 0xd2, 0xee, 0x2c, 0xe6, 0x37, 0x81, 0xb4, 0x1c, 0xa2,
+	0xb2, 0x75, 0xfd, 0x26, 0x6f, 0x67, 0xd6, 0xa6, 0xbd, 0x9d, 0xe9, 0x40, 0x73, 0xfa, 0x53, 0x92,
+	0x1c, 0xb5, 0xf3, 0xef, 0x2d, 0x58, 0xe0, 0xd7, 0xc2, 0xf8, 0x63, 0xad, 0x34, 0x21, 0x98, 0x0f,
+	0xaf, 0xbd, 0x01, 0x4b, 0x94, 0x93, 0x5b, 0x7e, 0x4b, 0xd6, 0xbe, 0x5d, 0x89, 0x93, 0xc1, 0xfb,
+	0xdf, 0xfe, 0x93, 0x3f, 0xfd, 0x7b, 0xb5, 0x35, 0x67, 0xe9, 0xc1, 0xd5, 0xa3, 0x07, 0x2c, 0x91,
+	0x88, 0x5e, 0x33, 0x8a, 0x8f, 0xad, 0x7b, 0xd8, 0x8a, 0xfe, 0x3c, 0xac, 0x6a, 0xa5, 0xe2, 0x99,
+	0x59, 0xfb, 0x76, 0x25, 0xae, 0xaa, 0x95, 0x31, 0xa3, 0x50, 0xad, 0xec, 0xfc, 0x97, 0x6d, 0x68,
+	0xa9, 0xc4, 0x7d, 0xf2, 0x63, 0x98, 0x37, 0xae, 0xc0, 0x11, 0xc9, 0xb8, 0xea, 0x52, 0x9d, 0x7d,
+	0xa7, 0x1a, 0x29, 0x9a, 0xbd, 0xcb, 0x9a, 0xed, 0x92, 0x75, 0x6c, 0x56, 0xec, 0x91, 0x0f, 0x98,
+	0x49, 0xc9, 0x5f, 0xad, 0x79, 0xa9, 0xec, 0x3b, 0xd9, 0xd8, 0x1d, 0x73, 0xe3, 0x2f, 0xb4, 0xf6,
+	0xd6, 0x14, 0xac, 0x68, 0xee, 0x0e, 0x6b, 0x6e, 0x9d, 0xac, 0xea, 0xcd, 0x29, 0x1b, 0x86, 0xb2,
+	0x77, 0x86, 0xf4, 0x77, 0x63, 0x89, 0xe4, 0x57, 0xfd, 0x9e, 0xac, 0xbd, 0x59, 0x7e, 0x23, 0x56,
+	0x3c, 0x2a, 0xeb, 0x74, 0x59, 0x53, 0x84, 0xb0, 0x01, 0xd5, 0x9f, 0x8d, 0x25, 0x3f, 0x82, 0x96,
+	0x7a, 0x8c, 0x91, 0x6c, 0x68, 0x2f, 0x60, 0xea, 0x2f, 0x44, 0xda, 0xdd, 0x32, 0xa2, 0x6a, 0xaa,
+	0x74, 0xce, 0x28, 0x10, 0x47, 0xb0, 0x26, 0xe2, 0x79, 0xe7, 0xf4, 0xbb, 0x7c, 0x49, 0xc5, 0x6b,
+	0xb7, 0x0f, 0x2d, 0xf2, 0x09, 0xcc, 0xc9, 0x37, 0x2e, 0xc9, 0x7a, 0xf5, 0x5b, 0x9d, 0xf6, 0x46,
+	0x09, 0x2e, 0xb6, 0x8d, 0x5d, 0x80, 0xfc, 0x39, 0x46, 0xd2, 0x9d, 0xf6, 0x6a, 0xa4, 0xbd, 0x59,
+	0x81, 0x11, 0x2c, 0x06, 0xb0, 0x5c, 0x7a, 0xed, 0x91, 0xbc, 0x9d, 0xd3, 0x57, 0xbe, 0x03, 0x79,
+	0x03, 0x43, 0x67, 0x9d, 0x8d, 0xdd, 0x12, 0x59, 0xc0, 0xb1, 0x8b, 0xe8, 0xb5, 0x7c, 0x95, 0x6b,
+	0x1f, 0xda, 0xda, 0x13, 0x8f, 0x44, 0x72, 0x28, 0x3f, 0x0f, 0x69, 0xdb, 0x55, 0x28, 0xd1, 0xdd,
+	0x1f, 0xc2, 0xbc, 0xf1, 0x56, 0xa3, 0x5a, 0x19, 0x55, 0x2f, 0x41, 0xda, 0x77, 0xaa, 0x91, 0x82,
+	0xd7, 0xaf, 0x43, 0x5b, 0x7b, 0x59, 0x91, 0x68, 0x6f, 0x2b, 0x14, 0x5e, 0x4e, 0xb4, 0xed, 0x2a,
+	0x94, 0xf8, 0xde, 0x55, 0xf6, 0xbd, 0x0b, 0x4e, 0x0b, 0xbf, 0x97, 0x3d, 0x3b, 0x85, 0x42, 0xf2,
+	0x63, 0x58, 0x30, 0x5f, 0x54, 0x54, 0xab, 0xaa, 0xf2, 0x6d, 0x46, 0xfb, 0xad, 0x29, 0x58, 0x53,
+	0x20, 0xef, 0xad, 0xa8, 0x46, 0x1e, 0x7c, 0x23, 0x12, 0x12, 0xbe, 0x25, 0xbf, 0x0a, 0x2d, 0xf5,
+	0x0e, 0x18, 0xc9, 0x5f, 0x98, 0x34, 0x5f, 0x0b, 0xb3, 0xbb, 0x65, 0x84, 0x60, 0xbe, 0xcc, 0x98,
+	0xb7, 0x49, 0xfe, 0x05, 0xe4, 0x0b, 0x98, 0x15, 0xef, 0x81, 0x91, 0xb5, 0x5c, 0xaa, 0xb5, 0x4b,
+	0x3e, 0xf6, 0x7a, 0x11, 0x2c, 0x98, 0xad, 0x30, 0x66, 0xf3, 0xa4, 0x8d, 0xcc, 0x06, 0x34, 0x0b,
+	0x90, 0x47, 0x04, 0x8b, 0x85, 0xfb, 0xd4, 0x6a, 0xb1, 0x54, 0xbf, 0xc6, 0x60, 0xdf, 0xbd, 0xf9,
+	0x1a, 0xb6, 0xa9, 0x66, 0xa4, 0x7a, 0x79, 0x20, 0x1f, 0xcf, 0xf8, 0x0d, 0xe8, 0xe8, 0x4f, 0xd4,
+	0x29, 0x9d, 0x5d, 0xf1, 0x9c, 0x9d, 0x7d, 0xbb, 0x12, 0x67, 0x4e, 0x2e, 0xe9, 0xe8, 0xcd, 0x90,
+	0x5f, 0x87, 0x45, 0xed, 0xe6, 0x3e, 0x6e, 0xc4, 0x4a, 0x78, 0xca, 0x2f, 0xba, 0xd8, 0x55, 0x7e,
+	0x94, 0xb3, 0xc1, 0x18, 0x2f, 0x3b, 0x06, 0x63, 0x14, 0x9c, 0x3d, 0x68, 0x6b, 0x3c, 0x6e, 0xe2,
+	0xbb, 0xa1, 0xa1, 0xf4, 0x67, 0x47, 0x1e, 0x5a, 0xe4, 0xef, 0xe3, 0x1b, 0xc7, 0xda, 0x5b, 0x51,
+	0xc4, 0xb8, 0x29, 0x53, 0xe0, 0xd3, 0xd5, 0x71, 0x3a, 0x23, 0xe7, 0x98, 0x75, 0xf2, 0xf0, 0xde,
+	0x13, 0x63, 0x90, 0xbf, 0x31, 0x3c, 0xf8, 0xfb, 0xfa, 0xfb, 0xc7, 0xdf, 0x16, 0x91, 0xfa, 0x53,
+	0x41, 0xdf, 0x3e, 0xb4, 0xc8, 0xc7, 0xfc, 0x39, 0x6f, 0x99, 0x06, 0x4c, 0x34, 0xc5, 0x56, 0x1c,
+	0x2e, 0xfd, 0x19, 0xeb, 0x6d, 0xeb, 0xa1, 0x45, 0xfe, 0x32, 0x2c, 0x6a, 0x75, 0xd9, 0xa8, 0xbf,
+	0x69, 0x7d, 0xe7, 0x5d, 0xf6, 0x25, 0x77, 0x9d, 0x4d, 0xe3, 0x4b, 0x8a, 0x9a, 0xfd, 0x04, 0x20,
+	0x3f, 0x00, 0x25, 0x85, 0xd3, 0x57, 0x7b, 0xfa, 0x19, 0xa9, 0x39, 0x9b, 0xf2, 0xbc, 0x14, 0x39,
+	0xfe, 0x88, 0x0b, 0xa2, 0xa0, 0x4f, 0xd5, 0x74, 0x96, 0x73, 0xb3, 0x6d, 0xbb, 0x0a, 0x55, 0x25,
+	0x86, 0x92, 0x3f, 0x79, 0x01, 0xf3, 0x3c, 0x1e, 0x27, 0x7b, 0x4c, 0xcc, 0xa8, 0x1b, 0x5a, 0x58,
+	0x76, 0xe1, 0x2b, 0x9c, 0x2d, 0xc6, 0xca, 0x26, 0x5d, 0x8d, 0xd5, 0x83, 0x6f, 0xf2, 0x8c, 0xf2,
+	0x6f, 0x89, 0x0f, 0xcb, 0x6a, 0x7f, 0x53, 0x1d, 0xb7, 0x4d, 0x36, 0xfa, 0x81, 0x56, 0xa9, 0x09,
+	0xc3, 0xe2, 0x90, 0xbd, 0x7d, 0x90, 0x4a, 0x9e, 0x0f, 0x2d, 0xf2, 0x19, 0xac, 0xab, 0x26, 0x4e,
+	0x83, 0x68, 0x10, 0xd2, 0xef, 0xf0, 0x09, 0x0f, 0x2d, 0x72, 0x02, 0x9d, 0x7d, 0xda, 0x8b, 0xfb,
+	0x54, 0x24, 0x15, 0xaf, 0xe4, 0xb5, 0x54, 0x36, 0xb2, 0x3d, 0x6f, 0x00, 0x4d, 0x8d, 0x31, 0xf2,
+	0x27, 0x09, 0xfd, 0xfa, 0xc1, 0x37, 0x22, 0x5d, 0xf9, 0x5b, 0xa9, 0x31, 0x44, 0xb3, 0xa6, 0xc6,
+	0x28, 0xe4, 0x64, 0xdb, 0xb7, 0x2b, 0x71, 0x55, 0x53, 0x25, 0x53, 0xbc, 0x49, 0x08, 0xcb, 0xa5,
+	0x34, 0x6e, 0xb5, 0xcb, 0x4e, 0x4b, 0xfe, 0xb6, 0xb7, 0xa6, 0x13, 0x98, 0xad, 0xdd, 0x33, 0x5b,
+	0x3b, 0x85, 0xf9, 0x7d, 0xca, 0x47, 0x97, 0xdf, 0x14, 0x2d, 0x1c, 0xff, 0xe8, 0x49, 0x8b, 0xf6,
+	0x4a, 0x05, 0xce, 0xdc, 0x12, 0xd8, 0x35, 0x4d, 0xf2, 0x23, 0x68, 0x3f, 0xa5, 0x99, 0xbc, 0x1a,
+	0xaa, 0x6c, 0x95, 0xc2, 0x5d, 0x51, 0xbb, 0xe2, 0x66, 0xa9, 0x29, 0x73, 0x8c, 0xdb, 0x03, 0xbc,
+	0x6b, 0xca, 0x95, 0x85, 0x17, 0xf4, 0xbf, 0x25, 0x7f, 0x91, 0x31, 0x57, 0xb7, 0xc9, 0xd7, 0xb5,
+	0x1b, 0x85, 0x3a, 0xf3, 0xc5, 0x02, 0xbc, 0x8a, 0x73, 0x14, 0xf7, 0xa9, 0xb6, 0x39, 0x46, 0xd0,
+	0xd6, 0x1e, 0x3d, 0x50, 0x0b, 0xb0, 0xfc, 0x92, 0x82, 0x6d, 0x57, 0xa1, 0xc4, 0x38, 0x6f, 0xb3,
+	0x76, 0x1c, 0xb2, 0x95, 0xb7, 0xc3, 0xdf, 0x45, 0xc8, 0x5b, 0x7a, 0xf0, 0x8d, 0x3f, 0xcc, 0xbe,
+	0x25, 0x5f, 0xb1, 0xc7, 0x35, 0xf5, 0xeb, 0xaf, 0xb9, 0xad, 0x54, 0xbc, 0x29, 0x6b, 0x93, 0x32,
+	0xca, 0xb4, 0x9f, 0x78, 0x53, 0x6c, 0x0f, 0xfd, 0x00, 0x00, 0x2f, 0x70, 0xee, 0xfb, 0x74, 0x18,
+	0x47, 0xb9, 0xe6, 0xcb, 0xaf, 0x78, 0xda, 0x2b, 0x06, 0x4c, 0x18, 0x39, 0x5f, 0x69, 0xd6, 0xaa,
+	0x3e, 0xc5, 0x44, 0x0a, 0xd7, 0xd4, 0x5b, 0xa0, 0xb6, 0x5d, 0x45, 0xa1, 0xf6, 0x98, 0x5d, 0x80,
+	0xfc, 0xd2, 0x80, 0xb2, 0x3d, 0x4b, 0xf7, 0x11, 0xec, 0xcd, 0x0a, 0x8c, 0xe8, 0xdb, 0x09, 0xb4,
+	0xf2, 0xac, 0x74, 0xb9, 0x9d, 0x15, 0x73, 0xd8, 0xed, 0x6e, 0x19, 0x21, 0x66, 0x65, 0x89, 0x0d,
+	0x15, 0x90, 0x39, 0x1c, 0x2a, 0xf6, 0x9e, 0x42, 0x00, 0x2b, 0x79, 0xee, 0x17, 0xdb, 0x6c, 0xd9,
+	0xa5, 0x45, 0xf9, 0x25, 0x15, 0xc9, 0xe1, 0xf6, 0xed, 0x4a, 0x9c, 0x68, 0x61, 0x93, 0xb5, 0xb0,
+	0xe2, 0x2c, 0xc8, 0x7d, 0x83, 0x5f, 0x98, 0x44, 0xd5, 0xbe, 0x0f, 0x6d, 0x2d, 0x33, 0x59, 0xcd,
+	0x72, 0x39, 0x89, 0xd9, 0xb6, 0xab, 0x50, 0x2a, 0x03, 0xa9, 0xfd, 0x6c, 0x58, 0xe6, 0xf2, 0x6c,
+	0x38, 0x95, 0x4b, 0x55, 0x12, 0xf1, 0x29, 0x2c, 0x15, 0x13, 0x68, 0xc9, 0xdd, 0x52, 0x02, 0x93,
+	0x91, 0xb6, 0x6b, 0xbf, 0x3d, 0x15, 0x2f, 0x98, 0x7a, 0xb0, 0x5e, 0x9d, 0xf8, 0x4b, 0xe4, 0xa9,
+	0xec, 0x8d, 0x79, 0xc1, 0xaf, 0x6f, 0xe0, 0x0b, 0x4d, 0x34, 0xb5, 0xdc, 0xdb, 0x94, 0xdc, 0xd5,
+	0x1e, 0xad, 0xad, 0x48, 0xe3, 0xb5, 0x49, 0x19, 0xff, 0xd0, 0xc2, 0x41, 0x28, 0x66, 0x64, 0x2a,
+	0x4e, 0x53, 0x12, 0x65, 0xed, 0xb7, 0xa7, 0xe2, 0x45, 0x1f, 0xbf, 0x84, 0xe5, 0x52, 0xce, 0xa3,
+	0x52, 0xdc, 0xd3, 0x72, 0x35, 0xed, 0xad, 0xe9, 0x04, 0xf9, 0x8c, 0x15, 0x93, 0x14, 0x55, 0x67,
+	0xa7, 0x64, 0x49, 0xda, 0x6f, 0x4f, 0xc5, 0xe7, 0x9d, 0x2d, 0x65, 0x28, 0xaa, 0xce, 0x4e, 0xcb,
+	0x7b, 0xb4, 0xb7, 0xa6, 0x13, 0x08, 0xbe, 0xcf, 0x60, 0xb9, 0x94, 0xdc, 0x58, 0xb9, 0x53, 0x4b,
+	0x56, 0x53, 0x53, 0x21, 0xb1, 0x8b, 0xa5, 0x74, 0x3c, 0x52, 0x96, 0x94, 0xc2, 0x34, 0x6d, 0x4d,
+	0x27, 0x50, 0xaa, 0x64, 0xb1, 0x90, 0xed, 0xa6, 0x3c, 0x8c, 0xea, 0x6c, 0x3b, 0xfb, 0xee, 0x34,
+	0x74, 0xde, 0xd3, 0x52, 0xce, 0x94, 0xea, 0xe9, 0xb4, 0xbc, 0x32, 0x7b, 0x6b, 0x3a, 0x81, 0xe0,
+	0xfb, 0x6b, 0xf2, 0xa2, 0x83, 0x9e, 0x66, 0xa4, 0xb4, 0xf1, 0xd4, 0xa4, 0x27, 0xfb, 0x9d, 0x1b,
+	0x28, 0x04, 0xeb, 0xa7, 0xd0, 0xe1, 0x70, 0x71, 0xac, 0x6f, 0x4f, 0xcf, 0x46, 0xb0, 0x6f, 0x57,
+	0xe2, 0x72, 0x2f, 0xdb, 0x38, 0xe9, 0x55, 0x5e, 0x76, 0x55, 0x1a, 0x80, 0x7d, 0xa7, 0x1a, 0x99,
+	0x8f, 0x63, 0xe9, 0xb0, 0x52, 0x8d, 0xe3, 0xb4, 0x33, 0x60, 0x7b, 0x6b, 0x3a, 0x41, 0xae, 0x39,
+	0xb5, 0x83, 0x35, 0xc3, 0xb2, 0x36, 0x8f, 0x30, 0x6d, 0xbb, 0x0a, 0x95, 0xcf, 0x46, 0x39, 0x2c,
+	0x4f, 0xf2, 0xf5, 0x3b, 0xe5, 0xc0, 0xcc, 0x7e, 0xe7, 0x06, 0x0a, 0xc1, 0xfa, 0x53, 0x68, 0x6b,
+	0xe1, 0xd3, 0x3c, 0xe0, 0x51, 0x0a, 0xa9, 0x56, 0xba, 0x2c, 0xe4, 0x4b, 0xcd, 0x46, 0xd6, 0xd3,
+	0x5f, 0x72, 0xbb, 0x71, 0x5a, 0x86, 0x99, 0xbd, 0x39, 0x35, 0x6b, 0xe6, 0xa1, 0x75, 0x3e, 0xc3,
+	0xfe, 0xe7, 0xd6, 0xf7, 0xff, 0xef, 0x00, 0xdb, 0x05, 0xb9, 0x0f, 0xa5, 0x6b, 0x00, 0x00,
 }
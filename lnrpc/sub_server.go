@@ -0,0 +1,148 @@
+package lnrpc
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// SubServer is a child server of the main lnd RPC server. Sub-servers allow
+// lnd to expose discrete services that can be used with or independent of the
+// main RPC server. The main rpcServer will create, start, stop, and register
+// all sub-servers that have been compiled into the daemon.
+//
+// Each sub-server lives within its own package, and its driver file is gated
+// behind a build tag named after the package (e.g. `examplerpc` for a
+// sub-server within lnrpc/examplerpc). This allows a user to pick and choose
+// the set of optional services that are compiled into their binary, as only
+// the sub-servers whose tags are active will register themselves from their
+// package's init function.
+type SubServer interface {
+	// Start starts the sub-server and all goroutines it needs to operate.
+	Start() error
+
+	// Stop signals that the sub-server should wrap up any lingering
+	// requests, and begin a graceful shutdown.
+	Stop() error
+
+	// Name returns a unique string representation of the sub-server. This
+	// can be used to identify the sub-server and also de-duplicate them.
+	Name() string
+
+	// RegisterWithRootServer will be called by the root gRPC server to
+	// direct a sub RPC server to register itself with the main gRPC root
+	// server. Until this is called, each sub-server won't be able to have
+	// requests routed towards it.
+	RegisterWithRootServer(*grpc.Server) error
+}
+
+// SubServerConfigDispatcher is an interface that all sub-servers will use to
+// dynamically locate their configuration files. This abstraction will allow
+// the primary RPC server to initialize all sub-servers in a generic manner
+// without knowing of each individual sub-server.
+type SubServerConfigDispatcher interface {
+	// FetchConfig attempts to locate an existing configuration file mapped
+	// to the target sub-server. If we're unable to find a config file
+	// matching the subServerName name, then false will be returned for the
+	// second parameter.
+	FetchConfig(subServerName string) (interface{}, bool)
+}
+
+// MacaroonPerms is a map from the full gRPC method URI of each of the calls
+// exposed by a sub-server (e.g. "/examplerpc.Example/Call") to the name
+// of the macaroon permission, all lowercase, that a caller must hold in order
+// to invoke it.
+type MacaroonPerms map[string]string
+
+// SubServerDriver is a template struct that allows the root server to create
+// a sub-server with minimal knowledge. The root server only needs a fully
+// populated SubServerConfigDispatcher and with the aide of the New method, it
+// will be able to create and initialize the sub-server.
+type SubServerDriver struct {
+	// SubServerName is the full name of a sub-sever.
+	//
+	// NOTE: This MUST be unique.
+	SubServerName string
+
+	// Permissions is the set of macaroon permissions required to invoke
+	// each of the sub-server's RPC calls. Calls that aren't present
+	// within this map will be rejected by the root server.
+	Permissions MacaroonPerms
+
+	// ReadOnlyPermissions is the subset of the permission names within
+	// Permissions that should be granted to the read-only macaroon.
+	ReadOnlyPermissions []string
+
+	// New creates, and fully initializes a new sub-server instance with
+	// the aide of the SubServerConfigDispatcher. This closure should
+	// return the SubServer, ready for action.
+	New func(subCfgs SubServerConfigDispatcher) (SubServer, error)
+}
+
+var (
+	// subServers is a package level global variable that houses all the
+	// registered sub-servers.
+	subServers = make(map[string]*SubServerDriver)
+
+	// registerMtx is a mutex that protects access to the above subServer
+	// map.
+	registerMtx sync.Mutex
+)
+
+// RegisteredSubServers returns all registered sub-servers, sorted by name.
+//
+// NOTE: This function is safe for concurrent access.
+func RegisteredSubServers() []*SubServerDriver {
+	registerMtx.Lock()
+	defer registerMtx.Unlock()
+
+	drivers := make([]*SubServerDriver, 0, len(subServers))
+	for _, driver := range subServers {
+		drivers = append(drivers, driver)
+	}
+
+	sort.Slice(drivers, func(i, j int) bool {
+		return drivers[i].SubServerName < drivers[j].SubServerName
+	})
+
+	return drivers
+}
+
+// RegisterSubServer should be called by a sub-server within its package's
+// init() method to register its existence with the main sub-server map. Each
+// sub-server, if active, is meant to register via this method in their init()
+// method. This allows callers to easily initialize and register all
+// sub-servers without knowing any details beyond that the fact that they
+// satisfy the necessary interfaces.
+//
+// NOTE: This function is safe for concurrent access.
+func RegisterSubServer(driver *SubServerDriver) error {
+	registerMtx.Lock()
+	defer registerMtx.Unlock()
+
+	if _, ok := subServers[driver.SubServerName]; ok {
+		return fmt.Errorf("subserver already registered")
+	}
+
+	subServers[driver.SubServerName] = driver
+
+	return nil
+}
+
+// SupportedServers returns slice of the names of all registered sub-servers.
+//
+// NOTE: This function is safe for concurrent access.
+func SupportedServers() []string {
+	registerMtx.Lock()
+	defer registerMtx.Unlock()
+
+	supportedSubServers := make([]string, 0, len(subServers))
+	for driverName := range subServers {
+		supportedSubServers = append(supportedSubServers, driverName)
+	}
+	sort.Strings(supportedSubServers)
+
+	return supportedSubServers
+}
@@ -0,0 +1,91 @@
+package lnrpc
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+// mockSubServer is a SubServer which does nothing, used to exercise the
+// registration of sub-servers.
+type mockSubServer struct {
+	name string
+}
+
+func (m *mockSubServer) Start() error { return nil }
+func (m *mockSubServer) Stop() error  { return nil }
+func (m *mockSubServer) Name() string { return m.name }
+
+func (m *mockSubServer) RegisterWithRootServer(*grpc.Server) error {
+	return nil
+}
+
+// newMockDriver returns a driver for a mockSubServer of the passed name.
+func newMockDriver(name string) *SubServerDriver {
+	return &SubServerDriver{
+		SubServerName: name,
+		Permissions: MacaroonPerms{
+			"/" + name + ".Mock/Call": "read" + name,
+		},
+		ReadOnlyPermissions: []string{"read" + name},
+		New: func(SubServerConfigDispatcher) (SubServer, error) {
+			return &mockSubServer{name: name}, nil
+		},
+	}
+}
+
+// TestRegisterSubServer ensures that registered sub-servers are reported
+// sorted by name, and that a sub-server can't be registered twice.
+func TestRegisterSubServer(t *testing.T) {
+	// The registered sub-servers are held globally, so we'll restore them
+	// once we're done.
+	registerMtx.Lock()
+	prior := subServers
+	subServers = make(map[string]*SubServerDriver)
+	registerMtx.Unlock()
+	defer func() {
+		registerMtx.Lock()
+		subServers = prior
+		registerMtx.Unlock()
+	}()
+
+	if len(RegisteredSubServers()) != 0 || len(SupportedServers()) != 0 {
+		t.Fatalf("expected no registered sub-servers")
+	}
+
+	for _, name := range []string{"mockb", "mocka"} {
+		if err := RegisterSubServer(newMockDriver(name)); err != nil {
+			t.Fatalf("unable to register %v: %v", name, err)
+		}
+	}
+	if err := RegisterSubServer(newMockDriver("mocka")); err == nil {
+		t.Fatalf("expected duplicate registration to fail")
+	}
+
+	expected := []string{"mocka", "mockb"}
+	if names := SupportedServers(); !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected sub-servers %v, got %v", expected, names)
+	}
+
+	drivers := RegisteredSubServers()
+	if len(drivers) != len(expected) {
+		t.Fatalf("expected %v drivers, got %v", len(expected),
+			len(drivers))
+	}
+	for i, driver := range drivers {
+		if driver.SubServerName != expected[i] {
+			t.Fatalf("expected driver %v at index %v, got %v",
+				expected[i], i, driver.SubServerName)
+		}
+
+		subServer, err := driver.New(nil)
+		if err != nil {
+			t.Fatalf("unable to create %v: %v", expected[i], err)
+		}
+		if subServer.Name() != expected[i] {
+			t.Fatalf("expected sub-server %v, got %v",
+				expected[i], subServer.Name())
+		}
+	}
+}
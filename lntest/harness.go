@@ -704,6 +704,7 @@ func (n *NetworkHarness) CloseChannel(ctx context.Context,
 	closeReq := &lnrpc.CloseChannelRequest{
 		ChannelPoint: cp,
 		Force:        force,
+		ConfirmForce: force,
 	}
 	closeRespStream, err := lnNode.CloseChannel(ctx, closeReq)
 	if err != nil {
@@ -0,0 +1,148 @@
+package interop
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// bitcoindRPCPort is the port the bitcoind node listens on for RPC
+	// connections.
+	bitcoindRPCPort = 18450
+
+	// bitcoindP2PPort is the port the bitcoind node listens on for p2p
+	// connections.
+	bitcoindP2PPort = 18451
+
+	// bitcoindZMQPort is the port on which the bitcoind node publishes raw
+	// blocks and transactions over ZMQ.
+	bitcoindZMQPort = 28350
+
+	// bitcoindRPCUser and bitcoindRPCPass are the RPC credentials of the
+	// bitcoind node.
+	bitcoindRPCUser = "interop"
+	bitcoindRPCPass = "interop"
+)
+
+var (
+	// bitcoindImage is the docker image the bitcoind node is launched
+	// from.
+	bitcoindImage = flag.String("interop.bitcoind",
+		"ruimarinho/bitcoin-core:0.16", "docker image of bitcoind")
+)
+
+// Bitcoind is a bitcoind node in regtest mode running within a docker
+// container, which serves as the chain backend of both lnd and the remote
+// implementations under test.
+type Bitcoind struct {
+	*container
+}
+
+// NewBitcoind launches a new bitcoind node, and waits for it to be ready to
+// serve RPC requests.
+func NewBitcoind() (*Bitcoind, error) {
+	zmqAddr := fmt.Sprintf("tcp://127.0.0.1:%d", bitcoindZMQPort)
+	c, err := startContainer("interop-bitcoind", *bitcoindImage, nil,
+		"-regtest",
+		"-server",
+		"-txindex",
+		"-listen",
+		"-printtoconsole",
+		"-fallbackfee=0.0002",
+		fmt.Sprintf("-port=%d", bitcoindP2PPort),
+		fmt.Sprintf("-rpcport=%d", bitcoindRPCPort),
+		fmt.Sprintf("-rpcuser=%s", bitcoindRPCUser),
+		fmt.Sprintf("-rpcpassword=%s", bitcoindRPCPass),
+		"-rpcallowip=127.0.0.1",
+		fmt.Sprintf("-zmqpubrawblock=%s", zmqAddr),
+		fmt.Sprintf("-zmqpubrawtx=%s", zmqAddr),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bitcoind{container: c}
+	err = waitFor(func() error {
+		_, err := b.rpc("getblockchaininfo")
+		return err
+	}, 30*time.Second)
+	if err != nil {
+		b.remove()
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// rpc executes the passed RPC command through bitcoin-cli, returning its
+// output stripped of any surrounding whitespace.
+func (b *Bitcoind) rpc(args ...string) ([]byte, error) {
+	cliArgs := []string{
+		"bitcoin-cli",
+		"-regtest",
+		fmt.Sprintf("-rpcport=%d", bitcoindRPCPort),
+		fmt.Sprintf("-rpcuser=%s", bitcoindRPCUser),
+		fmt.Sprintf("-rpcpassword=%s", bitcoindRPCPass),
+	}
+
+	out, err := b.exec(append(cliArgs, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSpace(out), nil
+}
+
+// LndArgs returns the arguments with which an lnd node connects to the
+// bitcoind node as its chain backend.
+func (b *Bitcoind) LndArgs() []string {
+	return []string{
+		"--bitcoin.node=bitcoind",
+		fmt.Sprintf("--bitcoind.rpchost=127.0.0.1:%d", bitcoindRPCPort),
+		fmt.Sprintf("--bitcoind.rpcuser=%s", bitcoindRPCUser),
+		fmt.Sprintf("--bitcoind.rpcpass=%s", bitcoindRPCPass),
+		fmt.Sprintf("--bitcoind.zmqpath=tcp://127.0.0.1:%d",
+			bitcoindZMQPort),
+	}
+}
+
+// Generate mines the given number of blocks, paying their rewards to the
+// wallet of the bitcoind node.
+func (b *Bitcoind) Generate(numBlocks uint32) error {
+	_, err := b.rpc("generate", strconv.FormatUint(uint64(numBlocks), 10))
+	return err
+}
+
+// NewAddress returns a new address of the wallet of the bitcoind node.
+func (b *Bitcoind) NewAddress() (string, error) {
+	addr, err := b.rpc("getnewaddress")
+	if err != nil {
+		return "", err
+	}
+
+	return string(addr), nil
+}
+
+// SendToAddress sends the given amount from the wallet of the bitcoind node
+// to the passed address, returning the txid of the transaction.
+func (b *Bitcoind) SendToAddress(addr string, amt btcutil.Amount) (string,
+	error) {
+
+	txid, err := b.rpc("sendtoaddress", addr,
+		strconv.FormatFloat(amt.ToBTC(), 'f', 8, 64))
+	if err != nil {
+		return "", err
+	}
+
+	return string(txid), nil
+}
+
+// Stop stops the bitcoind node and removes its container.
+func (b *Bitcoind) Stop() error {
+	return b.remove()
+}
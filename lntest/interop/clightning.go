@@ -0,0 +1,242 @@
+package interop
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// clightningP2PPort is the port the c-lightning node listens on for
+	// p2p connections.
+	clightningP2PPort = 19735
+
+	// clightningDir is the lightning directory of the c-lightning node
+	// within its container.
+	clightningDir = "/root/.lightning"
+)
+
+var (
+	// clightningImage is the docker image the c-lightning node is
+	// launched from.
+	clightningImage = flag.String("interop.clightning",
+		"elementsproject/lightningd:latest", "docker image of c-lightning")
+)
+
+// CLightning is a c-lightning node running within a docker container, driven
+// through lightning-cli.
+type CLightning struct {
+	*container
+
+	pubKey string
+}
+
+// A compile time check to ensure CLightning meets the RemoteNode interface.
+var _ RemoteNode = (*CLightning)(nil)
+
+// NewCLightning launches a new c-lightning node backed by the passed bitcoind
+// node, and waits for it to be ready to serve RPC requests.
+func NewCLightning(b *Bitcoind) (*CLightning, error) {
+	c, err := startContainer("interop-clightning", *clightningImage, nil,
+		"--network=regtest",
+		"--log-level=debug",
+		fmt.Sprintf("--lightning-dir=%s", clightningDir),
+		fmt.Sprintf("--addr=127.0.0.1:%d", clightningP2PPort),
+		"--bitcoin-rpcconnect=127.0.0.1",
+		fmt.Sprintf("--bitcoin-rpcport=%d", bitcoindRPCPort),
+		fmt.Sprintf("--bitcoin-rpcuser=%s", bitcoindRPCUser),
+		fmt.Sprintf("--bitcoin-rpcpassword=%s", bitcoindRPCPass),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &CLightning{container: c}
+	if err := node.waitReady(); err != nil {
+		node.remove()
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// waitReady waits for the node to serve RPC requests, and records its public
+// key.
+func (n *CLightning) waitReady() error {
+	return waitFor(func() error {
+		var info struct {
+			ID string `json:"id"`
+		}
+		if err := n.rpc(&info, "getinfo"); err != nil {
+			return err
+		}
+
+		n.pubKey = info.ID
+		return nil
+	}, 30*time.Second)
+}
+
+// rpc executes the passed command through lightning-cli, decoding its JSON
+// output into resp if it's non-nil.
+func (n *CLightning) rpc(resp interface{}, args ...string) error {
+	cliArgs := []string{
+		"lightning-cli",
+		fmt.Sprintf("--lightning-dir=%s", clightningDir),
+	}
+
+	out, err := n.exec(append(cliArgs, args...)...)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return nil
+	}
+
+	return json.Unmarshal(out, resp)
+}
+
+// Name returns the name of the implementation.
+func (n *CLightning) Name() string {
+	return "c-lightning"
+}
+
+// PubKey returns the hex encoded identity public key of the node.
+func (n *CLightning) PubKey() string {
+	return n.pubKey
+}
+
+// P2PAddr returns the host:port on which the node accepts p2p connections.
+func (n *CLightning) P2PAddr() string {
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(clightningP2PPort))
+}
+
+// NewAddress returns a new address of the wallet of the node.
+func (n *CLightning) NewAddress() (string, error) {
+	// Depending on the version, the address is returned under either of
+	// these fields.
+	var resp struct {
+		Address string `json:"address"`
+		Bech32  string `json:"bech32"`
+	}
+	if err := n.rpc(&resp, "newaddr"); err != nil {
+		return "", err
+	}
+
+	if resp.Bech32 != "" {
+		return resp.Bech32, nil
+	}
+	return resp.Address, nil
+}
+
+// ConnectPeer connects the node to the peer listening on the passed
+// host:port.
+func (n *CLightning) ConnectPeer(pubKey, addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	return n.rpc(nil, "connect", pubKey, host, port)
+}
+
+// OpenChannel opens a channel of the given capacity with the peer.
+func (n *CLightning) OpenChannel(pubKey string, amt btcutil.Amount) error {
+	return n.rpc(nil, "fundchannel", pubKey,
+		strconv.FormatInt(int64(amt), 10))
+}
+
+// ChannelActive returns true if the channel with the peer is open, and the
+// peer is online.
+func (n *CLightning) ChannelActive(pubKey string) (bool, error) {
+	var resp struct {
+		Peers []struct {
+			Connected bool `json:"connected"`
+			Channels  []struct {
+				State string `json:"state"`
+			} `json:"channels"`
+		} `json:"peers"`
+	}
+	if err := n.rpc(&resp, "listpeers", pubKey); err != nil {
+		return false, err
+	}
+
+	for _, peer := range resp.Peers {
+		if !peer.Connected {
+			continue
+		}
+		for _, channel := range peer.Channels {
+			if channel.State == "CHANNELD_NORMAL" {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// AddInvoice returns a BOLT 11 payment request for the given amount.
+func (n *CLightning) AddInvoice(amt lnwire.MilliSatoshi,
+	label string) (string, error) {
+
+	var resp struct {
+		Bolt11 string `json:"bolt11"`
+	}
+	err := n.rpc(&resp, "invoice", strconv.FormatUint(uint64(amt), 10),
+		label, label)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Bolt11, nil
+}
+
+// PayInvoice pays the passed BOLT 11 payment request, blocking until the
+// payment either succeeds or fails.
+func (n *CLightning) PayInvoice(payReq string) error {
+	return n.rpc(nil, "pay", payReq)
+}
+
+// UpdateFee makes the node propose the given commitment fee rate on the
+// channels it has opened. This requires a developer build of c-lightning,
+// ErrUnsupported is returned otherwise.
+func (n *CLightning) UpdateFee(feePerKw btcutil.Amount) error {
+	err := n.rpc(nil, "dev-setfees",
+		strconv.FormatInt(int64(feePerKw), 10))
+	if err != nil && strings.Contains(err.Error(), "Unknown command") {
+		return ErrUnsupported
+	}
+
+	return err
+}
+
+// CloseChannel cooperatively closes the channel with the peer.
+func (n *CLightning) CloseChannel(pubKey string) error {
+	return n.rpc(nil, "close", pubKey)
+}
+
+// Restart restarts the node, and waits for it to be ready to serve RPC
+// requests.
+func (n *CLightning) Restart() error {
+	if err := n.restart(); err != nil {
+		return err
+	}
+
+	return n.waitReady()
+}
+
+// Logs returns the logs of the node.
+func (n *CLightning) Logs() ([]byte, error) {
+	return n.logs()
+}
+
+// Stop stops the node and removes its container.
+func (n *CLightning) Stop() error {
+	return n.remove()
+}
@@ -0,0 +1,14 @@
+/*
+Package interop provides the infrastructure for testing lnd against other
+implementations of the Lightning Network protocol, in order to catch any
+divergence in how the BOLTs are interpreted, most notably in the ordering of
+retransmitted updates upon channel reestablishment.
+
+Each implementation runs within a docker container, attached to the network of
+the host, and shares a bitcoind node in regtest mode with the lnd node it's
+tested against. The containers are driven through the RemoteNode interface,
+while the lnd node itself is launched with lntest.NewStandaloneNode. The images
+used can be chosen with the -interop.bitcoind, -interop.clightning and
+-interop.eclair flags.
+*/
+package interop
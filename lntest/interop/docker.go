@@ -0,0 +1,102 @@
+package interop
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// docker executes the docker CLI with the passed arguments, returning its
+// output.
+func docker(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("docker", args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker %v failed: %v: %s", args[0], err,
+			bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return out, nil
+}
+
+// DockerAvailable returns true if the docker CLI is installed, and able to
+// reach the docker daemon.
+func DockerAvailable() bool {
+	_, err := docker("version")
+	return err == nil
+}
+
+// container is a docker container launched by the interop harness. All
+// containers are attached to the network of the host, so the ports they
+// listen on are reachable through the loopback interface, as are those of
+// the lnd nodes under test.
+type container struct {
+	name string
+}
+
+// startContainer launches a new detached container with the given name from
+// the passed image. Any container of the same name left behind by a prior
+// run is removed beforehand.
+func startContainer(name, image string, env []string,
+	args ...string) (*container, error) {
+
+	docker("rm", "--force", name)
+
+	runArgs := []string{"run", "--detach", "--network=host", "--name", name}
+	for _, e := range env {
+		runArgs = append(runArgs, "--env", e)
+	}
+	runArgs = append(runArgs, image)
+	runArgs = append(runArgs, args...)
+
+	if _, err := docker(runArgs...); err != nil {
+		return nil, err
+	}
+
+	return &container{name: name}, nil
+}
+
+// exec executes the passed command within the container, returning its
+// output.
+func (c *container) exec(args ...string) ([]byte, error) {
+	return docker(append([]string{"exec", c.name}, args...)...)
+}
+
+// restart stops the container and starts it again, preserving its
+// filesystem.
+func (c *container) restart() error {
+	_, err := docker("restart", c.name)
+	return err
+}
+
+// logs returns the output of the container so far.
+func (c *container) logs() ([]byte, error) {
+	return docker("logs", c.name)
+}
+
+// remove forcibly stops the container and removes it.
+func (c *container) remove() error {
+	_, err := docker("rm", "--force", "--volumes", c.name)
+	return err
+}
+
+// waitFor polls the passed function until it succeeds, returning the last
+// error encountered if it didn't within the timeout.
+func waitFor(f func() error, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout after %v: %v", timeout, err)
+		}
+
+		time.Sleep(250 * time.Millisecond)
+	}
+}
@@ -0,0 +1,292 @@
+package interop
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// eclairP2PPort is the port the eclair node listens on for p2p
+	// connections.
+	eclairP2PPort = 19736
+
+	// eclairAPIPort is the port the eclair node serves its JSON-RPC API
+	// on.
+	eclairAPIPort = 18090
+
+	// eclairAPIPass is the password of the JSON-RPC API of the eclair
+	// node.
+	eclairAPIPass = "interop"
+)
+
+var (
+	// eclairImage is the docker image the eclair node is launched from.
+	eclairImage = flag.String("interop.eclair", "acinq/eclair:latest",
+		"docker image of eclair")
+)
+
+// Eclair is an eclair node running within a docker container, driven through
+// its JSON-RPC API. Eclair has no wallet of its own, so channels are funded
+// from the wallet of the bitcoind node it's backed by.
+type Eclair struct {
+	*container
+
+	bitcoind *Bitcoind
+	pubKey   string
+	client   http.Client
+}
+
+// A compile time check to ensure Eclair meets the RemoteNode interface.
+var _ RemoteNode = (*Eclair)(nil)
+
+// NewEclair launches a new eclair node backed by the passed bitcoind node,
+// and waits for it to be ready to serve API requests.
+func NewEclair(b *Bitcoind) (*Eclair, error) {
+	opts := []string{
+		"-Declair.chain=regtest",
+		"-Declair.printToConsole",
+		"-Declair.server.binding-ip=127.0.0.1",
+		fmt.Sprintf("-Declair.server.port=%d", eclairP2PPort),
+		"-Declair.api.enabled=true",
+		fmt.Sprintf("-Declair.api.port=%d", eclairAPIPort),
+		fmt.Sprintf("-Declair.api.password=%s", eclairAPIPass),
+		"-Declair.bitcoind.host=127.0.0.1",
+		fmt.Sprintf("-Declair.bitcoind.rpcport=%d", bitcoindRPCPort),
+		fmt.Sprintf("-Declair.bitcoind.rpcuser=%s", bitcoindRPCUser),
+		fmt.Sprintf("-Declair.bitcoind.rpcpassword=%s", bitcoindRPCPass),
+		fmt.Sprintf("-Declair.bitcoind.zmq=tcp://127.0.0.1:%d",
+			bitcoindZMQPort),
+	}
+	env := []string{"JAVA_OPTS=" + strings.Join(opts, " ")}
+
+	c, err := startContainer("interop-eclair", *eclairImage, env)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &Eclair{
+		container: c,
+		bitcoind:  b,
+		client:    http.Client{Timeout: 2 * time.Minute},
+	}
+	if err := node.waitReady(); err != nil {
+		node.remove()
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// waitReady waits for the node to serve API requests, and records its public
+// key.
+func (n *Eclair) waitReady() error {
+	return waitFor(func() error {
+		var info struct {
+			NodeID string `json:"nodeId"`
+		}
+		if err := n.rpc(&info, "getinfo"); err != nil {
+			return err
+		}
+
+		n.pubKey = info.NodeID
+		return nil
+	}, time.Minute)
+}
+
+// rpc calls the passed method of the JSON-RPC API, decoding its result into
+// resp if it's non-nil.
+func (n *Eclair) rpc(resp interface{}, method string,
+	params ...interface{}) error {
+
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"method": method,
+		"params": params,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("http://127.0.0.1:%d", eclairAPIPort)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth("", eclairAPIPass)
+
+	httpResp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(httpResp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("unable to decode response to %v (%v): %v",
+			method, httpResp.Status, err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("%v failed: %v (code %v)", method,
+			rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+	if resp == nil {
+		return nil
+	}
+
+	return json.Unmarshal(rpcResp.Result, resp)
+}
+
+// eclairChannel describes a channel as returned by the channels method of the
+// API.
+type eclairChannel struct {
+	NodeID    string `json:"nodeId"`
+	ChannelID string `json:"channelId"`
+	State     string `json:"state"`
+}
+
+// channelWith returns the channel the node has with the passed peer, or nil
+// if there's none.
+func (n *Eclair) channelWith(pubKey string) (*eclairChannel, error) {
+	var channels []eclairChannel
+	if err := n.rpc(&channels, "channels"); err != nil {
+		return nil, err
+	}
+
+	for i := range channels {
+		if channels[i].NodeID == pubKey {
+			return &channels[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Name returns the name of the implementation.
+func (n *Eclair) Name() string {
+	return "eclair"
+}
+
+// PubKey returns the hex encoded identity public key of the node.
+func (n *Eclair) PubKey() string {
+	return n.pubKey
+}
+
+// P2PAddr returns the host:port on which the node accepts p2p connections.
+func (n *Eclair) P2PAddr() string {
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(eclairP2PPort))
+}
+
+// NewAddress returns a new address of the wallet of the bitcoind node the
+// node is backed by, as it funds its channels from it.
+func (n *Eclair) NewAddress() (string, error) {
+	return n.bitcoind.NewAddress()
+}
+
+// ConnectPeer connects the node to the peer listening on the passed
+// host:port.
+func (n *Eclair) ConnectPeer(pubKey, addr string) error {
+	return n.rpc(nil, "connect", fmt.Sprintf("%s@%s", pubKey, addr))
+}
+
+// OpenChannel opens a channel of the given capacity with the peer.
+func (n *Eclair) OpenChannel(pubKey string, amt btcutil.Amount) error {
+	return n.rpc(nil, "open", pubKey, int64(amt))
+}
+
+// ChannelActive returns true if the channel with the peer is open, and the
+// peer is online.
+func (n *Eclair) ChannelActive(pubKey string) (bool, error) {
+	channel, err := n.channelWith(pubKey)
+	if err != nil || channel == nil {
+		return false, err
+	}
+
+	return channel.State == "NORMAL", nil
+}
+
+// AddInvoice returns a BOLT 11 payment request for the given amount.
+func (n *Eclair) AddInvoice(amt lnwire.MilliSatoshi,
+	label string) (string, error) {
+
+	var payReq string
+	if err := n.rpc(&payReq, "receive", uint64(amt), label); err != nil {
+		return "", err
+	}
+
+	return payReq, nil
+}
+
+// PayInvoice pays the passed BOLT 11 payment request, blocking until the
+// payment either succeeds or fails.
+func (n *Eclair) PayInvoice(payReq string) error {
+	var resp struct {
+		PaymentPreimage string `json:"paymentPreimage"`
+	}
+	if err := n.rpc(&resp, "send", payReq); err != nil {
+		return err
+	}
+
+	if resp.PaymentPreimage == "" {
+		return fmt.Errorf("payment of %v failed", payReq)
+	}
+
+	return nil
+}
+
+// UpdateFee isn't supported by eclair, as it only updates the commitment fee
+// rate as the fee estimates of its bitcoind node change.
+func (n *Eclair) UpdateFee(feePerKw btcutil.Amount) error {
+	return ErrUnsupported
+}
+
+// CloseChannel cooperatively closes the channel with the peer.
+func (n *Eclair) CloseChannel(pubKey string) error {
+	channel, err := n.channelWith(pubKey)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return fmt.Errorf("no channel with %v", pubKey)
+	}
+
+	return n.rpc(nil, "close", channel.ChannelID)
+}
+
+// Restart restarts the node, and waits for it to be ready to serve API
+// requests.
+func (n *Eclair) Restart() error {
+	if err := n.restart(); err != nil {
+		return err
+	}
+
+	return n.waitReady()
+}
+
+// Logs returns the logs of the node.
+func (n *Eclair) Logs() ([]byte, error) {
+	return n.logs()
+}
+
+// Stop stops the node and removes its container.
+func (n *Eclair) Stop() error {
+	return n.remove()
+}
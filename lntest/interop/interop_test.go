@@ -0,0 +1,521 @@
+// +build interop
+
+package interop
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// chanAmt is the capacity of the channels opened by the remote nodes
+	// towards lnd.
+	chanAmt = btcutil.Amount(1000000)
+
+	// paymentAmt is the amount of each payment sent over the channels.
+	paymentAmt = btcutil.Amount(10000)
+
+	// numPayments is the number of payments sent in each direction per
+	// batch. The payments of a batch are sent concurrently, so that the
+	// updates of both parties are interleaved within the commitments.
+	numPayments = 5
+
+	// feeRate is the commitment fee rate the remote nodes are made to
+	// propose, in sat/kw.
+	feeRate = btcutil.Amount(5000)
+
+	// defaultTimeout is the time within which the conditions waited upon
+	// by the tests must be met.
+	defaultTimeout = time.Minute
+)
+
+// interopTest drives an lnd node and a remote node of another implementation
+// through the lifecycle of a channel between them.
+type interopTest struct {
+	t *testing.T
+
+	bitcoind  *Bitcoind
+	lnd       *lntest.HarnessNode
+	lndErrors <-chan error
+	remote    RemoteNode
+
+	// sent and received are the amounts lnd has sent and received over
+	// the channel so far.
+	mtx      sync.Mutex
+	sent     btcutil.Amount
+	received btcutil.Amount
+}
+
+// fatalf fails the test, logging the output of the remote node and any fatal
+// error of the lnd process to help diagnose the failure.
+func (h *interopTest) fatalf(format string, args ...interface{}) {
+	if logs, err := h.remote.Logs(); err == nil {
+		h.t.Logf("%v logs:\n%s", h.remote.Name(), logs)
+	}
+
+	select {
+	case err := <-h.lndErrors:
+		h.t.Logf("lnd exited: %v", err)
+	default:
+	}
+
+	h.t.Fatalf(format, args...)
+}
+
+// lndChannel returns lnd's channel with the remote node, or nil if there's
+// none.
+func (h *interopTest) lndChannel() (*lnrpc.ActiveChannel, error) {
+	ctxb := context.Background()
+	resp, err := h.lnd.ListChannels(ctxb, &lnrpc.ListChannelsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, channel := range resp.Channels {
+		if channel.RemotePubkey == h.remote.PubKey() {
+			return channel, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// waitChannelActive waits for the channel to be active from the point of
+// view of both lnd and the remote node. lnd is made to reconnect to the
+// remote node as long as that's not the case.
+func (h *interopTest) waitChannelActive() {
+	ctxb := context.Background()
+	err := waitFor(func() error {
+		channel, err := h.lndChannel()
+		if err != nil {
+			return err
+		}
+		active, err := h.remote.ChannelActive(h.lnd.PubKeyStr)
+		if err != nil {
+			return err
+		}
+		if channel != nil && channel.Active && active {
+			return nil
+		}
+
+		h.lnd.ConnectPeer(ctxb, &lnrpc.ConnectPeerRequest{
+			Addr: &lnrpc.LightningAddress{
+				Pubkey: h.remote.PubKey(),
+				Host:   h.remote.P2PAddr(),
+			},
+		})
+
+		return fmt.Errorf("channel not active, lnd: %v, %v: %v",
+			channel != nil && channel.Active, h.remote.Name(),
+			active)
+	}, defaultTimeout)
+	if err != nil {
+		h.fatalf("channel with %v didn't become active: %v",
+			h.remote.Name(), err)
+	}
+}
+
+// payRemote pays an invoice of the remote node from lnd.
+func (h *interopTest) payRemote(label string) error {
+	payReq, err := h.remote.AddInvoice(
+		lnwire.NewMSatFromSatoshis(paymentAmt), label,
+	)
+	if err != nil {
+		return err
+	}
+
+	ctxt, cancel := context.WithTimeout(
+		context.Background(), defaultTimeout,
+	)
+	defer cancel()
+	resp, err := h.lnd.SendPaymentSync(ctxt, &lnrpc.SendRequest{
+		PaymentRequest: payReq,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.PaymentError != "" {
+		return errors.New(resp.PaymentError)
+	}
+
+	h.mtx.Lock()
+	h.sent += paymentAmt
+	h.mtx.Unlock()
+
+	return nil
+}
+
+// payLnd pays an invoice of lnd from the remote node, and checks that lnd
+// considers the invoice settled.
+func (h *interopTest) payLnd(label string) error {
+	ctxb := context.Background()
+	invoice, err := h.lnd.AddInvoice(ctxb, &lnrpc.Invoice{
+		Memo:  label,
+		Value: int64(paymentAmt),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := h.remote.PayInvoice(invoice.PaymentRequest); err != nil {
+		return err
+	}
+
+	// The remote node may consider the payment complete as soon as it
+	// learns the preimage, so we wait for lnd to lock in the settle.
+	return waitFor(func() error {
+		resp, err := h.lnd.LookupInvoice(ctxb, &lnrpc.PaymentHash{
+			RHash: invoice.RHash,
+		})
+		if err != nil {
+			return err
+		}
+		if !resp.Settled {
+			return fmt.Errorf("invoice %v not settled", label)
+		}
+
+		h.mtx.Lock()
+		h.received += paymentAmt
+		h.mtx.Unlock()
+
+		return nil
+	}, defaultTimeout)
+}
+
+// sendPayments concurrently sends numPayments payments in each of the
+// directions requested, returning the errors encountered.
+func (h *interopTest) sendPayments(batch string, toRemote,
+	toLnd bool) []error {
+
+	var (
+		wg   sync.WaitGroup
+		mtx  sync.Mutex
+		errs []error
+	)
+	send := func(pay func(string) error, label string) {
+		defer wg.Done()
+
+		if err := pay(label); err != nil {
+			mtx.Lock()
+			errs = append(errs, fmt.Errorf("%v: %v", label, err))
+			mtx.Unlock()
+		}
+	}
+
+	for i := 0; i < numPayments; i++ {
+		if toRemote {
+			wg.Add(1)
+			label := fmt.Sprintf("%v-to-remote-%d", batch, i)
+			go send(h.payRemote, label)
+		}
+		if toLnd {
+			wg.Add(1)
+			label := fmt.Sprintf("%v-to-lnd-%d", batch, i)
+			go send(h.payLnd, label)
+		}
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// assertPayments sends a batch of payments, failing the test if any of them
+// fails.
+func (h *interopTest) assertPayments(batch string, toRemote, toLnd bool) {
+	if errs := h.sendPayments(batch, toRemote, toLnd); len(errs) != 0 {
+		h.fatalf("payments of batch %v failed: %v", batch, errs)
+	}
+}
+
+// assertBalance checks that lnd's balance within the channel reflects the
+// payments sent and received. As lnd isn't the initiator of the channel, it
+// doesn't pay the commitment fee, so the balance is exact.
+func (h *interopTest) assertBalance() {
+	err := waitFor(func() error {
+		channel, err := h.lndChannel()
+		if err != nil {
+			return err
+		}
+		if channel == nil {
+			return fmt.Errorf("no channel with %v", h.remote.Name())
+		}
+
+		h.mtx.Lock()
+		expected := h.received - h.sent
+		h.mtx.Unlock()
+
+		if channel.LocalBalance != int64(expected) {
+			return fmt.Errorf("expected local balance %v, got %v",
+				int64(expected), channel.LocalBalance)
+		}
+		if len(channel.PendingHtlcs) != 0 {
+			return fmt.Errorf("%v HTLCs still pending",
+				len(channel.PendingHtlcs))
+		}
+
+		return nil
+	}, defaultTimeout)
+	if err != nil {
+		h.fatalf("channel balance mismatch: %v", err)
+	}
+}
+
+// run exercises the lifecycle of a channel opened by the remote node
+// towards lnd: the channel is opened, payments are sent in both directions,
+// the channel is reestablished both while idle and with HTLCs in flight, the
+// commitment fee rate is updated, and the channel is finally cooperatively
+// closed by lnd.
+func (h *interopTest) run() {
+	ctxb := context.Background()
+
+	// Fund the wallet of the remote node, so it can open a channel
+	// towards lnd.
+	addr, err := h.remote.NewAddress()
+	if err != nil {
+		h.fatalf("unable to get address: %v", err)
+	}
+	if _, err := h.bitcoind.SendToAddress(addr, 2*chanAmt); err != nil {
+		h.fatalf("unable to fund %v: %v", h.remote.Name(), err)
+	}
+	if err := h.bitcoind.Generate(6); err != nil {
+		h.fatalf("unable to generate blocks: %v", err)
+	}
+
+	// lnd may still be syncing to the chain tip, so we retry connecting
+	// until it's ready to.
+	err = waitFor(func() error {
+		_, err := h.lnd.ConnectPeer(ctxb, &lnrpc.ConnectPeerRequest{
+			Addr: &lnrpc.LightningAddress{
+				Pubkey: h.remote.PubKey(),
+				Host:   h.remote.P2PAddr(),
+			},
+		})
+		return err
+	}, defaultTimeout)
+	if err != nil {
+		h.fatalf("unable to connect to %v: %v", h.remote.Name(), err)
+	}
+
+	// The remote node may only see the funds once its wallet has caught
+	// up with the blocks just mined, so we retry opening the channel.
+	err = waitFor(func() error {
+		return h.remote.OpenChannel(h.lnd.PubKeyStr, chanAmt)
+	}, defaultTimeout)
+	if err != nil {
+		h.fatalf("unable to open channel: %v", err)
+	}
+	if err := h.bitcoind.Generate(6); err != nil {
+		h.fatalf("unable to generate blocks: %v", err)
+	}
+	h.waitChannelActive()
+
+	// lnd starts without any balance within the channel, so the remote
+	// node pays first, then payments flow in both directions at once.
+	h.assertPayments("initial", false, true)
+	h.assertPayments("bidirectional", true, true)
+	h.assertBalance()
+
+	// Restart the remote node while the channel is idle, and ensure the
+	// channel is reestablished and remains usable.
+	if err := h.remote.Restart(); err != nil {
+		h.fatalf("unable to restart %v: %v", h.remote.Name(), err)
+	}
+	h.waitChannelActive()
+	h.assertPayments("idle-reestablish", true, true)
+	h.assertBalance()
+
+	// Restart the remote node with payments in flight. Some of them may
+	// fail, but those which do must be failed back cleanly, and the
+	// updates retransmitted upon reestablishment must leave both
+	// parties with the same view of the channel.
+	var (
+		wg   sync.WaitGroup
+		errs []error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs = h.sendPayments("inflight-reestablish", true, false)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if err := h.remote.Restart(); err != nil {
+		h.fatalf("unable to restart %v: %v", h.remote.Name(), err)
+	}
+	wg.Wait()
+	h.t.Logf("%d payments failed across restart of %v: %v", len(errs),
+		h.remote.Name(), errs)
+
+	h.waitChannelActive()
+	h.assertBalance()
+	h.assertPayments("post-reestablish", true, true)
+	h.assertBalance()
+
+	// Have the remote node, as the initiator of the channel, update the
+	// commitment fee rate, and ensure lnd accepts the new fee rate.
+	switch err := h.remote.UpdateFee(feeRate); {
+	case err == ErrUnsupported:
+		h.t.Logf("%v doesn't support updating the fee rate at will, "+
+			"skipping fee update", h.remote.Name())
+
+	case err != nil:
+		h.fatalf("unable to update fee rate: %v", err)
+
+	default:
+		err := waitFor(func() error {
+			channel, err := h.lndChannel()
+			if err != nil {
+				return err
+			}
+			if channel == nil || channel.FeePerKw != int64(feeRate) {
+				return fmt.Errorf("fee rate not updated: %v",
+					channel)
+			}
+			return nil
+		}, defaultTimeout)
+		if err != nil {
+			h.fatalf("fee update not applied: %v", err)
+		}
+
+		h.assertPayments("post-fee-update", true, true)
+		h.assertBalance()
+	}
+
+	// Finally, cooperatively close the channel from lnd's side.
+	channel, err := h.lndChannel()
+	if err != nil || channel == nil {
+		h.fatalf("unable to find channel: %v", err)
+	}
+	split := strings.Split(channel.ChannelPoint, ":")
+	if len(split) != 2 {
+		h.fatalf("invalid channel point: %v", channel.ChannelPoint)
+	}
+	index, err := strconv.ParseUint(split[1], 10, 32)
+	if err != nil {
+		h.fatalf("invalid channel point: %v", channel.ChannelPoint)
+	}
+	chanPoint := &lnrpc.ChannelPoint{
+		FundingTxidStr: split[0],
+		OutputIndex:    uint32(index),
+	}
+	closeUpdates, err := h.lnd.CloseChannel(ctxb, &lnrpc.CloseChannelRequest{
+		ChannelPoint: chanPoint,
+	})
+	if err != nil {
+		h.fatalf("unable to close channel: %v", err)
+	}
+	if _, err := closeUpdates.Recv(); err != nil {
+		h.fatalf("unable to close channel: %v", err)
+	}
+	if err := h.bitcoind.Generate(6); err != nil {
+		h.fatalf("unable to generate blocks: %v", err)
+	}
+
+	err = waitFor(func() error {
+		channel, err := h.lndChannel()
+		if err != nil {
+			return err
+		}
+		if channel != nil {
+			return fmt.Errorf("channel still open")
+		}
+		return nil
+	}, defaultTimeout)
+	if err != nil {
+		h.fatalf("channel wasn't closed: %v", err)
+	}
+}
+
+// TestInterop tests lnd against each of the other implementations, as
+// described by interopTest.run.
+func TestInterop(t *testing.T) {
+	if !DockerAvailable() {
+		t.Skip("docker isn't available")
+	}
+
+	bitcoind, err := NewBitcoind()
+	if err != nil {
+		t.Fatalf("unable to start bitcoind: %v", err)
+	}
+	defer bitcoind.Stop()
+
+	// Mature enough coinbase outputs to fund the nodes under test.
+	if err := bitcoind.Generate(101); err != nil {
+		t.Fatalf("unable to generate blocks: %v", err)
+	}
+
+	lndErrors := make(chan error, 1)
+	lnd, err := lntest.NewStandaloneNode(
+		&chaincfg.RegressionNetParams, bitcoind.LndArgs(), nil,
+		lndErrors,
+	)
+	if err != nil {
+		if lnd != nil {
+			lnd.Shutdown()
+		}
+		t.Fatalf("unable to start lnd: %v", err)
+	}
+	defer lnd.Shutdown()
+
+	remotes := []struct {
+		name    string
+		newNode func(*Bitcoind) (RemoteNode, error)
+	}{
+		{
+			name: "c-lightning",
+			newNode: func(b *Bitcoind) (RemoteNode, error) {
+				node, err := NewCLightning(b)
+				if err != nil {
+					return nil, err
+				}
+				return node, nil
+			},
+		},
+		{
+			name: "eclair",
+			newNode: func(b *Bitcoind) (RemoteNode, error) {
+				node, err := NewEclair(b)
+				if err != nil {
+					return nil, err
+				}
+				return node, nil
+			},
+		},
+	}
+
+	for _, remote := range remotes {
+		remote := remote
+		success := t.Run(remote.name, func(t *testing.T) {
+			node, err := remote.newNode(bitcoind)
+			if err != nil {
+				t.Fatalf("unable to start %v: %v", remote.name,
+					err)
+			}
+			defer node.Stop()
+
+			ht := &interopTest{
+				t:         t,
+				bitcoind:  bitcoind,
+				lnd:       lnd,
+				lndErrors: lndErrors,
+				remote:    node,
+			}
+			ht.run()
+		})
+		if !success {
+			break
+		}
+	}
+}
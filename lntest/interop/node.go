@@ -0,0 +1,69 @@
+package interop
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcutil"
+)
+
+// ErrUnsupported is returned by a RemoteNode for an operation its
+// implementation doesn't expose a way to trigger.
+var ErrUnsupported = errors.New("operation not supported by implementation")
+
+// RemoteNode is a node of another implementation of the Lightning Network
+// protocol, which lnd is tested against. Peers and channels are identified
+// by the hex encoded identity public key of the remote party.
+type RemoteNode interface {
+	// Name returns the name of the implementation.
+	Name() string
+
+	// PubKey returns the hex encoded identity public key of the node.
+	PubKey() string
+
+	// P2PAddr returns the host:port on which the node accepts p2p
+	// connections.
+	P2PAddr() string
+
+	// NewAddress returns a new address, which funds the on-chain wallet
+	// the node draws from to open channels.
+	NewAddress() (string, error)
+
+	// ConnectPeer connects the node to the peer listening on the passed
+	// host:port.
+	ConnectPeer(pubKey, addr string) error
+
+	// OpenChannel opens a channel of the given capacity with the peer,
+	// funded entirely by the node. The funding transaction is broadcast,
+	// but the call returns before it confirms.
+	OpenChannel(pubKey string, amt btcutil.Amount) error
+
+	// ChannelActive returns true if the channel with the peer is open, and
+	// the peer is online.
+	ChannelActive(pubKey string) (bool, error)
+
+	// AddInvoice returns a BOLT 11 payment request for the given amount.
+	AddInvoice(amt lnwire.MilliSatoshi, label string) (string, error)
+
+	// PayInvoice pays the passed BOLT 11 payment request, blocking until
+	// the payment either succeeds or fails.
+	PayInvoice(payReq string) error
+
+	// UpdateFee makes the node propose the given commitment fee rate on
+	// the channels it has opened. ErrUnsupported is returned if the fee
+	// rate can't be set at will.
+	UpdateFee(feePerKw btcutil.Amount) error
+
+	// CloseChannel cooperatively closes the channel with the peer.
+	CloseChannel(pubKey string) error
+
+	// Restart restarts the node, which must reestablish its channels
+	// once it reconnects to its peers.
+	Restart() error
+
+	// Logs returns the logs of the node, to help diagnose test failures.
+	Logs() ([]byte, error)
+
+	// Stop stops the node and removes its container.
+	Stop() error
+}
@@ -90,6 +90,11 @@ type nodeConfig struct {
 	BaseDir   string
 	ExtraArgs []string
 
+	// BackendArgs, if set, are the arguments used to connect the node to
+	// its chain backend, in place of those of the btcd node described by
+	// RPCConfig.
+	BackendArgs []string
+
 	DataDir      string
 	LogDir       string
 	TLSCertPath  string
@@ -131,17 +136,21 @@ func (cfg nodeConfig) genArgs() []string {
 		args = append(args, "--bitcoin.regtest")
 	}
 
-	encodedCert := hex.EncodeToString(cfg.RPCConfig.Certificates)
 	args = append(args, "--bitcoin.active")
 	args = append(args, "--nobootstrap")
 	args = append(args, "--noencryptwallet")
 	args = append(args, "--debuglevel=debug")
 	args = append(args, "--bitcoin.defaultchanconfs=1")
 	args = append(args, "--bitcoin.defaultremotedelay=4")
-	args = append(args, fmt.Sprintf("--btcd.rpchost=%v", cfg.RPCConfig.Host))
-	args = append(args, fmt.Sprintf("--btcd.rpcuser=%v", cfg.RPCConfig.User))
-	args = append(args, fmt.Sprintf("--btcd.rpcpass=%v", cfg.RPCConfig.Pass))
-	args = append(args, fmt.Sprintf("--btcd.rawrpccert=%v", encodedCert))
+	if cfg.BackendArgs != nil {
+		args = append(args, cfg.BackendArgs...)
+	} else {
+		encodedCert := hex.EncodeToString(cfg.RPCConfig.Certificates)
+		args = append(args, fmt.Sprintf("--btcd.rpchost=%v", cfg.RPCConfig.Host))
+		args = append(args, fmt.Sprintf("--btcd.rpcuser=%v", cfg.RPCConfig.User))
+		args = append(args, fmt.Sprintf("--btcd.rpcpass=%v", cfg.RPCConfig.Pass))
+		args = append(args, fmt.Sprintf("--btcd.rawrpccert=%v", encodedCert))
+	}
 	args = append(args, fmt.Sprintf("--rpclisten=%v", cfg.RPCAddr()))
 	args = append(args, fmt.Sprintf("--restlisten=%v", cfg.RESTAddr()))
 	args = append(args, fmt.Sprintf("--listen=%v", cfg.P2PAddr()))
@@ -224,6 +233,26 @@ func newNode(cfg nodeConfig) (*HarnessNode, error) {
 	}, nil
 }
 
+// NewStandaloneNode creates and launches an lnd node which isn't part of a
+// NetworkHarness, connected to the chain backend described by backendArgs,
+// e.g. a bitcoind node. Any fatal error of the lnd process is sent over the
+// passed channel. The caller is responsible for calling Shutdown on the
+// returned node, even if an error is returned alongside it.
+func NewStandaloneNode(netParams *chaincfg.Params, backendArgs,
+	extraArgs []string, lndError chan<- error) (*HarnessNode, error) {
+
+	node, err := newNode(nodeConfig{
+		NetParams:   netParams,
+		BackendArgs: backendArgs,
+		ExtraArgs:   extraArgs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return node, node.start(lndError)
+}
+
 // DBPath returns the filepath to the channeldb database file for this node.
 func (hn *HarnessNode) DBPath() string {
 	return hn.cfg.DBPath()
@@ -439,6 +468,12 @@ func (hn *HarnessNode) shutdown() error {
 	return nil
 }
 
+// Shutdown stops the lnd process of a node created with NewStandaloneNode,
+// and removes its temporary files.
+func (hn *HarnessNode) Shutdown() error {
+	return hn.shutdown()
+}
+
 // closeChanWatchRequest is a request to the lightningNetworkWatcher to be
 // notified once it's detected within the test Lightning Network, that a
 // channel has either been added or closed.
@@ -5,7 +5,6 @@ import (
 	"container/list"
 	"crypto/sha256"
 	"fmt"
-	"runtime"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -1075,11 +1074,13 @@ func compactLogs(ourLog, theirLog *updateLog,
 // commitment transactions allowing for a high degree of non-blocking
 // bi-directional payment throughput.
 //
-// In order to allow updates to be fully non-blocking, either side is able to
-// create multiple new commitment states up to a pre-determined window size.
-// This window size is encoded within InitialRevocationWindow. Before the start
-// of a session, both side should send out revocation messages with nil
-// preimages in order to populate their revocation window for the remote party.
+// Either side is able to create a new commitment state for the remote party
+// as long as the remote party has revoked all of its prior states. The
+// revocation window is therefore fixed at a single unrevoked commitment: each
+// RevokeAndAck only carries the single next commitment point of the remote
+// party, so we're unable to sign for states any further ahead. Updates remain
+// non-blocking though, as any number of updates can be batched into a single
+// commitment, and both parties can sign new commitments concurrently.
 //
 // The state machine has for main methods:
 //  * .SignNextCommitment()
@@ -1239,8 +1240,7 @@ func NewLightningChannel(signer Signer, pCache PreimageCache,
 	)
 
 	lc := &LightningChannel{
-		// TODO(roasbeef): tune num sig workers?
-		sigPool:              newSigPool(runtime.NumCPU(), signer),
+		sigPool:              newSigPool(numSigWorkers(), signer),
 		signer:               signer,
 		pCache:               pCache,
 		stateHintObfuscator:  stateHint,
@@ -1429,6 +1429,109 @@ func (lc *LightningChannel) logUpdateToPayDesc(logUpdate *channeldb.LogUpdate,
 	return pd, nil
 }
 
+// toLogUpdateMsg maps the PaymentDescriptor to the one of the four wire
+// messages that it corresponds to, for the channel with the passed ID.
+func (pd *PaymentDescriptor) toLogUpdateMsg(
+	chanID lnwire.ChannelID) lnwire.Message {
+
+	switch pd.EntryType {
+	case Add:
+		htlc := &lnwire.UpdateAddHTLC{
+			ChanID:      chanID,
+			ID:          pd.HtlcIndex,
+			Amount:      pd.Amount,
+			Expiry:      pd.Timeout,
+			PaymentHash: pd.RHash,
+		}
+		copy(htlc.OnionBlob[:], pd.OnionBlob)
+		return htlc
+
+	case Settle:
+		return &lnwire.UpdateFufillHTLC{
+			ChanID:          chanID,
+			ID:              pd.ParentIndex,
+			PaymentPreimage: pd.RPreimage,
+		}
+
+	case Fail:
+		return &lnwire.UpdateFailHTLC{
+			ChanID: chanID,
+			ID:     pd.ParentIndex,
+			Reason: pd.FailReason,
+		}
+
+	case MalformedFail:
+		return &lnwire.UpdateFailMalformedHTLC{
+			ChanID:       chanID,
+			ID:           pd.ParentIndex,
+			ShaOnionBlob: pd.ShaOnionBlob,
+			FailureCode:  pd.FailCode,
+		}
+	}
+
+	return nil
+}
+
+// PayDescsFromRemoteLogUpdates converts the updates of the remote party
+// within a forwarding package back into PaymentDescriptors, so that the
+// updates can be processed again after a restart. As the HTLCs which the
+// Settles and Fails remove may no longer be within the update log, their
+// amounts aren't restored.
+func PayDescsFromRemoteLogUpdates(
+	logUpdates []channeldb.LogUpdate) []*PaymentDescriptor {
+
+	payDescs := make([]*PaymentDescriptor, 0, len(logUpdates))
+	for _, logUpdate := range logUpdates {
+		var pd *PaymentDescriptor
+
+		switch wireMsg := logUpdate.UpdateMsg.(type) {
+		case *lnwire.UpdateAddHTLC:
+			pd = &PaymentDescriptor{
+				RHash:     wireMsg.PaymentHash,
+				Timeout:   wireMsg.Expiry,
+				Amount:    wireMsg.Amount,
+				EntryType: Add,
+				HtlcIndex: wireMsg.ID,
+				LogIndex:  logUpdate.LogIndex,
+			}
+			pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
+			copy(pd.OnionBlob[:], wireMsg.OnionBlob[:])
+
+		case *lnwire.UpdateFufillHTLC:
+			pd = &PaymentDescriptor{
+				RPreimage:   wireMsg.PaymentPreimage,
+				ParentIndex: wireMsg.ID,
+				LogIndex:    logUpdate.LogIndex,
+				EntryType:   Settle,
+			}
+
+		case *lnwire.UpdateFailHTLC:
+			pd = &PaymentDescriptor{
+				ParentIndex: wireMsg.ID,
+				LogIndex:    logUpdate.LogIndex,
+				EntryType:   Fail,
+				FailReason:  wireMsg.Reason[:],
+			}
+
+		case *lnwire.UpdateFailMalformedHTLC:
+			pd = &PaymentDescriptor{
+				ParentIndex:  wireMsg.ID,
+				LogIndex:     logUpdate.LogIndex,
+				EntryType:    MalformedFail,
+				FailCode:     wireMsg.FailureCode,
+				ShaOnionBlob: wireMsg.ShaOnionBlob,
+			}
+
+		default:
+			continue
+		}
+
+		payDescs = append(payDescs, pd)
+	}
+
+	return payDescs
+}
+
 // restoreCommitState will restore the local commitment chain and updateLog
 // state to a consistent in-memory representation of the passed dis commitment.
 // This method is to be used upon reconnection to our channel counter party.
@@ -2586,48 +2689,12 @@ func (lc *LightningChannel) createCommitDiff(
 
 		// Knowing that this update is a part of this new commitment,
 		// we'll create a log update and not it's index in the log so
-		// we can later restore it properly if a restart occurs.
+		// we can later restore it properly if a restart occurs. With
+		// this set of messages obtained, we can simply read from disk
+		// and re-send them in the case of a needed channel sync.
 		logUpdate := channeldb.LogUpdate{
-			LogIndex: pd.LogIndex,
-		}
-
-		// We'll map the type of the PaymentDescriptor to one of the
-		// four messages that it corresponds to. With this set of
-		// messages obtained, we can simply read from disk and re-send
-		// them in the case of a needed channel sync.
-		switch pd.EntryType {
-		case Add:
-			htlc := &lnwire.UpdateAddHTLC{
-				ChanID:      chanID,
-				ID:          pd.HtlcIndex,
-				Amount:      pd.Amount,
-				Expiry:      pd.Timeout,
-				PaymentHash: pd.RHash,
-			}
-			copy(htlc.OnionBlob[:], pd.OnionBlob)
-			logUpdate.UpdateMsg = htlc
-
-		case Settle:
-			logUpdate.UpdateMsg = &lnwire.UpdateFufillHTLC{
-				ChanID:          chanID,
-				ID:              pd.ParentIndex,
-				PaymentPreimage: pd.RPreimage,
-			}
-
-		case Fail:
-			logUpdate.UpdateMsg = &lnwire.UpdateFailHTLC{
-				ChanID: chanID,
-				ID:     pd.ParentIndex,
-				Reason: pd.FailReason,
-			}
-
-		case MalformedFail:
-			logUpdate.UpdateMsg = &lnwire.UpdateFailMalformedHTLC{
-				ChanID:       chanID,
-				ID:           pd.ParentIndex,
-				ShaOnionBlob: pd.ShaOnionBlob,
-				FailureCode:  pd.FailCode,
-			}
+			LogIndex:  pd.LogIndex,
+			UpdateMsg: pd.toLogUpdateMsg(chanID),
 		}
 
 		logUpdates = append(logUpdates, logUpdate)
@@ -2664,6 +2731,7 @@ func (lc *LightningChannel) createCommitDiff(
 func (lc *LightningChannel) SignNextCommitment() (*btcec.Signature, []*btcec.Signature, error) {
 	lc.Lock()
 	defer lc.Unlock()
+	defer lc.assertInvariants("SignNextCommitment")
 
 	// If we're awaiting for an ACK to a commitment signature, or if we
 	// don't yet have the initial next revocation point of the remote
@@ -3285,6 +3353,7 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig *btcec.Signature,
 
 	lc.Lock()
 	defer lc.Unlock()
+	defer lc.assertInvariants("ReceiveNewCommitment")
 
 	// Determine the last update on the local log that has been locked in.
 	localACKedIndex := lc.remoteCommitChain.tail().ourMessageIndex
@@ -3440,6 +3509,46 @@ func (lc *LightningChannel) FullySynced() bool {
 	return !oweCommitment && localUpdatesSynced && remoteUpdatesSynced
 }
 
+// IsChannelClean returns true if the channel has no HTLCs within either
+// commitment, no updates which have yet to be locked into both commitments,
+// no pending fee update, and no commitment awaiting a revocation. A clean
+// channel can be cooperatively closed without racing any in-flight updates.
+func (lc *LightningChannel) IsChannelClean() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	if lc.localUpdateLog.Len() != 0 || lc.remoteUpdateLog.Len() != 0 {
+		return false
+	}
+
+	if lc.localCommitChain.hasUnackedCommitment() ||
+		lc.remoteCommitChain.hasUnackedCommitment() {
+
+		return false
+	}
+
+	if lc.pendingFeeUpdate != nil {
+		return false
+	}
+
+	localTip := lc.localCommitChain.tip()
+	remoteTip := lc.remoteCommitChain.tip()
+	return len(localTip.incomingHTLCs) == 0 &&
+		len(localTip.outgoingHTLCs) == 0 &&
+		len(remoteTip.incomingHTLCs) == 0 &&
+		len(remoteTip.outgoingHTLCs) == 0
+}
+
+// PendingRemoteCommitment returns true if we've signed a new commitment for
+// the remote party, which they haven't yet ACKed by revoking their prior
+// commitment.
+func (lc *LightningChannel) PendingRemoteCommitment() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.remoteCommitChain.hasUnackedCommitment()
+}
+
 // RevokeCurrentCommitment revokes the next lowest unrevoked commitment
 // transaction in the local commitment chain. As a result the edge of our
 // revocation window is extended by one, and the tail of our local commitment
@@ -3451,6 +3560,7 @@ func (lc *LightningChannel) FullySynced() bool {
 func (lc *LightningChannel) RevokeCurrentCommitment() (*lnwire.RevokeAndAck, []channeldb.HTLC, error) {
 	lc.Lock()
 	defer lc.Unlock()
+	defer lc.assertInvariants("RevokeCurrentCommitment")
 
 	revocationMsg, err := lc.generateRevocation(lc.currentHeight)
 	if err != nil {
@@ -3492,19 +3602,24 @@ func (lc *LightningChannel) RevokeCurrentCommitment() (*lnwire.RevokeAndAck, []c
 // windows are extended, or in response to a state update that we initiate. If
 // successful, then the remote commitment chain is advanced by a single
 // commitment, and a log compaction is attempted. In addition, a slice of
-// HTLC's which can be forwarded upstream are returned.
-func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) ([]*PaymentDescriptor, error) {
+// HTLC's which can be forwarded upstream are returned, along with the
+// forwarding package holding them, which is persisted atomically with the
+// revocation.
+func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) (
+	*channeldb.FwdPkg, []*PaymentDescriptor, error) {
+
 	lc.Lock()
 	defer lc.Unlock()
+	defer lc.assertInvariants("ReceiveRevocation")
 
 	// Ensure that the new pre-image can be placed in preimage store.
 	store := lc.channelState.RevocationStore
 	revocation, err := chainhash.NewHash(revMsg.Revocation[:])
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if err := store.AddNextEntry(revocation); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Verify that if we use the commitment point computed based off of the
@@ -3513,7 +3628,7 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) ([]*P
 	currentCommitPoint := lc.channelState.RemoteCurrentRevocation
 	derivedCommitPoint := ComputeCommitmentPoint(revMsg.Revocation[:])
 	if !derivedCommitPoint.IsEqual(currentCommitPoint) {
-		return nil, fmt.Errorf("revocation key mismatch")
+		return nil, nil, fmt.Errorf("revocation key mismatch")
 	}
 
 	// Now that we've verified that the prior commitment has been properly
@@ -3529,26 +3644,21 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) ([]*P
 		lc.remoteCommitChain.tail().height,
 		lc.remoteCommitChain.tail().height+1)
 
-	// At this point, the revocation has been accepted, and we've rotated
-	// the current revocation key+hash for the remote party. Therefore we
-	// sync now to ensure the revocation producer state is consistent with
-	// the current commitment height and also to advance the on-disk
-	// commitment chain.
-	if err := lc.channelState.AdvanceCommitChainTail(); err != nil {
-		return nil, err
-	}
-
 	// Since they revoked the current lowest height in their commitment
-	// chain, we can advance their chain by a single commitment.
-	lc.remoteCommitChain.advanceTail()
-
-	remoteChainTail := lc.remoteCommitChain.tail().height
+	// chain, the commitment following it will become the new tail of
+	// their chain.
+	remoteChainTail := lc.remoteCommitChain.tail().height + 1
 	localChainTail := lc.localCommitChain.tail().height
 
-	// Now that we've verified the revocation update the state of the HTLC
-	// log as we may be able to prune portions of it now, and update their
-	// balance.
-	var htlcsToForward []*PaymentDescriptor
+	// Now that we've verified the revocation, we'll determine which of
+	// the remote party's updates have been locked in by it, so they can
+	// be forwarded.
+	var (
+		htlcsToForward    []*PaymentDescriptor
+		addUpdates        []channeldb.LogUpdate
+		settleFailUpdates []channeldb.LogUpdate
+	)
+	chanID := lnwire.NewChanIDFromOutPoint(&lc.channelState.FundingOutpoint)
 	for e := lc.remoteUpdateLog.Front(); e != nil; e = e.Next() {
 		htlc := e.Value.(*PaymentDescriptor)
 
@@ -3571,8 +3681,11 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) ([]*P
 			remoteChainTail == htlc.addCommitHeightRemote &&
 			localChainTail >= htlc.addCommitHeightLocal {
 
-			htlc.isForwarded = true
 			htlcsToForward = append(htlcsToForward, htlc)
+			addUpdates = append(addUpdates, channeldb.LogUpdate{
+				LogIndex:  htlc.LogIndex,
+				UpdateMsg: htlc.toLogUpdateMsg(chanID),
+			})
 			continue
 		}
 
@@ -3580,19 +3693,49 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) ([]*P
 			remoteChainTail >= htlc.removeCommitHeightRemote &&
 			localChainTail >= htlc.removeCommitHeightLocal {
 
-			htlc.isForwarded = true
 			htlcsToForward = append(htlcsToForward, htlc)
+			settleFailUpdates = append(
+				settleFailUpdates, channeldb.LogUpdate{
+					LogIndex:  htlc.LogIndex,
+					UpdateMsg: htlc.toLogUpdateMsg(chanID),
+				},
+			)
 			continue
 		}
 	}
 
+	// At this point, the revocation has been accepted, and we've rotated
+	// the current revocation key+hash for the remote party. Therefore we
+	// sync now to ensure the revocation producer state is consistent with
+	// the current commitment height and also to advance the on-disk
+	// commitment chain. The updates locked in by the revocation are
+	// persisted within the same transaction as a forwarding package, so
+	// they're never lost, even if we go down before forwarding them.
+	fwdPkg := channeldb.NewFwdPkg(
+		lc.channelState.ShortChanID, remoteChainTail, addUpdates,
+		settleFailUpdates,
+	)
+	if err := lc.channelState.AdvanceCommitChainTail(fwdPkg); err != nil {
+		return nil, nil, err
+	}
+
+	// Since they revoked the current lowest height in their commitment
+	// chain, we can advance their chain by a single commitment.
+	lc.remoteCommitChain.advanceTail()
+
+	// With the forwarding package written, the updates can now be marked
+	// as forwarded.
+	for _, htlc := range htlcsToForward {
+		htlc.isForwarded = true
+	}
+
 	// As we've just completed a new state transition, attempt to see if we
 	// can remove any entries from the update log which have been removed
 	// from the PoV of both commitment chains.
 	compactLogs(lc.localUpdateLog, lc.remoteUpdateLog,
 		localChainTail, remoteChainTail)
 
-	return htlcsToForward, nil
+	return fwdPkg, htlcsToForward, nil
 }
 
 // NextRevocationKey returns the commitment point for the _next_ commitment
@@ -3890,6 +4033,34 @@ func (lc *LightningChannel) ShortChanID() lnwire.ShortChannelID {
 	return lc.channelState.ShortChanID
 }
 
+// LocalHtlcIndex returns the HTLC index that will be assigned to the next HTLC
+// we offer to the remote party.
+func (lc *LightningChannel) LocalHtlcIndex() uint64 {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.localUpdateLog.htlcCounter
+}
+
+// IncomingHtlcResolved returns true if our update log holds a settle or fail
+// of the HTLC offered by the remote party with the passed index. This covers
+// both the resolutions applied since the channel was loaded, and those
+// restored from a commitment we signed before a restart, which the remote
+// party has yet to revoke their prior commitment for.
+func (lc *LightningChannel) IncomingHtlcResolved(htlcIndex uint64) bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	for e := lc.localUpdateLog.Front(); e != nil; e = e.Next() {
+		pd := e.Value.(*PaymentDescriptor)
+		if pd.EntryType != Add && pd.ParentIndex == htlcIndex {
+			return true
+		}
+	}
+
+	return false
+}
+
 // genHtlcScript generates the proper P2WSH public key scripts for the HTLC
 // output modified by two-bits denoting if this is an incoming HTLC, and if the
 // HTLC is being applied to their commitment transaction or ours.
@@ -5043,6 +5214,133 @@ func (lc *LightningChannel) availableBalance() (lnwire.MilliSatoshi, int64) {
 	return settledBalance, totalCommitWeight
 }
 
+// RemoteAvailableBalance returns the current available balance of the remote
+// party within the channel, which bounds the amount it's able to send to us.
+// It mirrors AvailableBalance: it's the balance the remote party would have if
+// at this very instance a new commitment were to be created for it which
+// evals all the log entries, net of the commitment fee should the remote
+// party be the initiator.
+func (lc *LightningChannel) RemoteAvailableBalance() lnwire.MilliSatoshi {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.remoteAvailableBalance()
+}
+
+// remoteAvailableBalance is the private, non mutexed version of
+// RemoteAvailableBalance.
+func (lc *LightningChannel) remoteAvailableBalance() lnwire.MilliSatoshi {
+	// First, we'll grab the remote party's balance within its latest
+	// commitment. If it's the initiator of the channel then it paid the
+	// fees on that commitment, so we'll re-apply those.
+	remoteCommit := lc.remoteCommitChain.tip()
+	settledBalance := remoteCommit.theirBalance
+	if !lc.channelState.IsInitiator {
+		settledBalance += lnwire.NewMSatFromSatoshis(remoteCommit.fee)
+	}
+
+	htlcView := lc.fetchHTLCView(lc.remoteUpdateLog.logIndex,
+		lc.localUpdateLog.logIndex)
+	feePerKw := remoteCommit.feePerKw
+	dustLimit := remoteCommit.dustLimit
+
+	// We'll now re-compute the current weight of all the active HTLC's
+	// on the remote commitment, skipping any that are dust.
+	var totalHtlcWeight int64
+	for _, htlc := range remoteCommit.incomingHTLCs {
+		if htlcIsDust(true, false, feePerKw, htlc.Amount.ToSatoshis(),
+			dustLimit) {
+			continue
+		}
+
+		totalHtlcWeight += HtlcWeight
+	}
+	for _, htlc := range remoteCommit.outgoingHTLCs {
+		if htlcIsDust(false, false, feePerKw, htlc.Amount.ToSatoshis(),
+			dustLimit) {
+			continue
+		}
+
+		totalHtlcWeight += HtlcWeight
+	}
+
+	// Next we'll run through the set of updates not yet included within
+	// the remote commitment, mirroring availableBalance with the roles of
+	// the two parties reversed.
+	for _, entry := range htlcView.theirUpdates {
+		switch {
+		case entry.EntryType == Add && entry.addCommitHeightRemote == 0:
+			settledBalance -= entry.Amount
+
+			if htlcIsDust(true, false, feePerKw,
+				entry.Amount.ToSatoshis(), dustLimit) {
+				continue
+			}
+
+			totalHtlcWeight += HtlcWeight
+
+		case entry.EntryType == Settle &&
+			entry.removeCommitHeightRemote == 0:
+
+			totalHtlcWeight -= HtlcWeight
+			settledBalance += entry.Amount
+
+		case entry.EntryType == Fail &&
+			entry.removeCommitHeightRemote == 0:
+			fallthrough
+		case entry.EntryType == MalformedFail &&
+			entry.removeCommitHeightRemote == 0:
+
+			totalHtlcWeight -= HtlcWeight
+		}
+	}
+	for _, entry := range htlcView.ourUpdates {
+		switch {
+		case entry.EntryType == Add && entry.addCommitHeightRemote == 0:
+			if htlcIsDust(false, false, feePerKw,
+				entry.Amount.ToSatoshis(), dustLimit) {
+				continue
+			}
+
+			totalHtlcWeight += HtlcWeight
+
+		case entry.EntryType == Settle &&
+			entry.removeCommitHeightRemote == 0:
+
+			totalHtlcWeight -= HtlcWeight
+
+		case entry.EntryType == Fail &&
+			entry.removeCommitHeightRemote == 0:
+			fallthrough
+		case entry.EntryType == MalformedFail &&
+			entry.removeCommitHeightRemote == 0:
+
+			totalHtlcWeight -= HtlcWeight
+			settledBalance += entry.Amount
+		}
+	}
+
+	if totalHtlcWeight < 0 {
+		totalHtlcWeight = 0
+	}
+
+	// If the remote party is the initiator then it needs to pay the fees
+	// for its next commitment, so we'll deduct them from its balance.
+	if !lc.channelState.IsInitiator {
+		fee := lnwire.NewMSatFromSatoshis(btcutil.Amount(
+			(int64(feePerKw) * (CommitWeight + totalHtlcWeight)) /
+				1000,
+		))
+		if fee > settledBalance {
+			return 0
+		}
+
+		settledBalance -= fee
+	}
+
+	return settledBalance
+}
+
 // StateSnapshot returns a snapshot of the current fully committed state within
 // the channel.
 func (lc *LightningChannel) StateSnapshot() *channeldb.ChannelSnapshot {
@@ -5283,6 +5581,100 @@ func (lc *LightningChannel) IsInitiator() bool {
 	return lc.channelState.IsInitiator
 }
 
+// LocalChanReserve returns the reserve the remote party requires us to
+// maintain within the channel. Our settled balance may never drop below this
+// amount.
+func (lc *LightningChannel) LocalChanReserve() btcutil.Amount {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.channelState.LocalChanCfg.ChanReserve
+}
+
+// RemoteChanReserve returns the reserve we require the remote party to
+// maintain within the channel. Its settled balance may never drop below this
+// amount.
+func (lc *LightningChannel) RemoteChanReserve() btcutil.Amount {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.channelState.RemoteChanCfg.ChanReserve
+}
+
+// MaxValueInFlight returns the maximum total value of unresolved outgoing
+// HTLCs the remote party permits us to have within the channel, along with the
+// maximum total value of unresolved incoming HTLCs we permit the remote party
+// to have.
+func (lc *LightningChannel) MaxValueInFlight() (lnwire.MilliSatoshi,
+	lnwire.MilliSatoshi) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.channelState.LocalChanCfg.MaxPendingAmount,
+		lc.channelState.RemoteChanCfg.MaxPendingAmount
+}
+
+// InFlightValues returns the total value of all outgoing and incoming HTLCs
+// within the channel that haven't yet been settled or failed. This includes
+// HTLCs that have been added to either update log, but not yet locked in.
+func (lc *LightningChannel) InFlightValues() (lnwire.MilliSatoshi,
+	lnwire.MilliSatoshi) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	_, outgoing := inFlightHtlcs(lc.localUpdateLog, lc.remoteUpdateLog)
+	_, incoming := inFlightHtlcs(lc.remoteUpdateLog, lc.localUpdateLog)
+
+	return outgoing, incoming
+}
+
+// NumInFlightHtlcs returns the number of outgoing and incoming HTLCs within
+// the channel that haven't yet been settled or failed. As with
+// InFlightValues, this includes HTLCs that haven't yet been locked in.
+func (lc *LightningChannel) NumInFlightHtlcs() (int, int) {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	outgoing, _ := inFlightHtlcs(lc.localUpdateLog, lc.remoteUpdateLog)
+	incoming, _ := inFlightHtlcs(lc.remoteUpdateLog, lc.localUpdateLog)
+
+	return outgoing, incoming
+}
+
+// inFlightHtlcs returns the number and total value of the HTLCs added within
+// the offer log that haven't been removed by an entry within the
+// counterparty's log.
+func inFlightHtlcs(offerLog, removeLog *updateLog) (int, lnwire.MilliSatoshi) {
+	removed := make(map[uint64]struct{})
+	for e := removeLog.Front(); e != nil; e = e.Next() {
+		pd := e.Value.(*PaymentDescriptor)
+		if pd.EntryType != Add {
+			removed[pd.ParentIndex] = struct{}{}
+		}
+	}
+
+	var (
+		num   int
+		total lnwire.MilliSatoshi
+	)
+	for e := offerLog.Front(); e != nil; e = e.Next() {
+		pd := e.Value.(*PaymentDescriptor)
+		if pd.EntryType != Add {
+			continue
+		}
+		if _, ok := removed[pd.HtlcIndex]; ok {
+			continue
+		}
+
+		num++
+		total += pd.Amount
+	}
+
+	return num, total
+}
+
 // CommitFeeRate returns the current fee rate of the commitment transaction in
 // units of sat-per-kw.
 func (lc *LightningChannel) CommitFeeRate() btcutil.Amount {
@@ -5301,6 +5693,39 @@ func (lc *LightningChannel) IsPending() bool {
 	return lc.channelState.IsPending
 }
 
+// LoadFwdPkgs returns the forwarding packages of the channel, in order of
+// ascending remote commitment height.
+func (lc *LightningChannel) LoadFwdPkgs() ([]*channeldb.FwdPkg, error) {
+	return lc.channelState.LoadFwdPkgs()
+}
+
+// SetFwdFilter records which of the Adds of the forwarding package at the
+// passed height are to be forwarded, marking the package as processed.
+func (lc *LightningChannel) SetFwdFilter(height uint64,
+	fwdFilter *channeldb.PkgFilter) error {
+
+	return lc.channelState.SetFwdFilter(height, fwdFilter)
+}
+
+// AckAddHtlcs marks the referenced Adds within the channel's forwarding
+// packages as acknowledged.
+func (lc *LightningChannel) AckAddHtlcs(addRefs ...channeldb.AddRef) error {
+	return lc.channelState.AckAddHtlcs(addRefs...)
+}
+
+// AckSettleFails marks the referenced Settles and Fails within the
+// forwarding packages of any channel as acknowledged.
+func (lc *LightningChannel) AckSettleFails(
+	settleFailRefs ...channeldb.SettleFailRef) error {
+
+	return lc.channelState.Db.AckSettleFails(settleFailRefs...)
+}
+
+// RemoveFwdPkg removes the channel's forwarding package at the passed height.
+func (lc *LightningChannel) RemoveFwdPkg(height uint64) error {
+	return lc.channelState.RemoveFwdPkg(height)
+}
+
 // State provides access to the channel's internal state for testing.
 func (lc *LightningChannel) State() *channeldb.OpenChannel {
 	return lc.channelState
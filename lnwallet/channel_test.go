@@ -102,7 +102,7 @@ func forceStateTransition(chanA, chanB *LightningChannel) error {
 		return err
 	}
 
-	if _, err := chanA.ReceiveRevocation(bobRevocation); err != nil {
+	if _, _, err := chanA.ReceiveRevocation(bobRevocation); err != nil {
 		return err
 	}
 	if err := chanA.ReceiveNewCommitment(bobSig, bobHtlcSigs); err != nil {
@@ -113,7 +113,7 @@ func forceStateTransition(chanA, chanB *LightningChannel) error {
 	if err != nil {
 		return err
 	}
-	if _, err := chanB.ReceiveRevocation(aliceRevocation); err != nil {
+	if _, _, err := chanB.ReceiveRevocation(aliceRevocation); err != nil {
 		return err
 	}
 
@@ -460,7 +460,7 @@ func TestSimpleAddSettleWorkflow(t *testing.T) {
 	// Alice then processes this revocation, sending her own revocation for
 	// her prior commitment transaction. Alice shouldn't have any HTLCs to
 	// forward since she's sending an outgoing HTLC.
-	if htlcs, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
+	if _, htlcs, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
 		t.Fatalf("alice unable to process bob's revocation: %v", err)
 	} else if len(htlcs) != 0 {
 		t.Fatalf("alice forwards %v htlcs, should forward none: ", len(htlcs))
@@ -484,7 +484,7 @@ func TestSimpleAddSettleWorkflow(t *testing.T) {
 	// is fully locked in within both commitment transactions. Bob should
 	// also be able to forward an HTLC now that the HTLC has been locked
 	// into both commitment transactions.
-	if htlcs, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+	if _, htlcs, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
 		t.Fatalf("bob unable to process alice's revocation: %v", err)
 	} else if len(htlcs) != 1 {
 		t.Fatalf("bob should be able to forward an HTLC, instead can "+
@@ -573,7 +573,7 @@ func TestSimpleAddSettleWorkflow(t *testing.T) {
 		t.Fatalf("alice unable to sign new commitment: %v", err)
 	}
 
-	if htlcs, err := bobChannel.ReceiveRevocation(aliceRevocation2); err != nil {
+	if _, htlcs, err := bobChannel.ReceiveRevocation(aliceRevocation2); err != nil {
 		t.Fatalf("bob unable to process alice's revocation: %v", err)
 	} else if len(htlcs) != 0 {
 		t.Fatalf("bob shouldn't forward any HTLCs after outgoing settle, "+
@@ -589,7 +589,7 @@ func TestSimpleAddSettleWorkflow(t *testing.T) {
 		t.Fatalf("bob unable to revoke commitment: %v", err)
 	}
 
-	if htlcs, err := aliceChannel.ReceiveRevocation(bobRevocation2); err != nil {
+	if _, htlcs, err := aliceChannel.ReceiveRevocation(bobRevocation2); err != nil {
 		t.Fatalf("alice unable to process bob's revocation: %v", err)
 	} else if len(htlcs) != 1 {
 		// Alice should now be able to forward the settlement HTLC to
@@ -795,6 +795,68 @@ func TestCooperativeChannelClosure(t *testing.T) {
 	}
 }
 
+// TestChannelIsClean tests that a channel is only reported as clean once all
+// of its HTLCs have been removed, and the removals have been locked into both
+// commitments.
+func TestChannelIsClean(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	assertClean := func(clean bool) {
+		if aliceChannel.IsChannelClean() != clean {
+			t.Fatalf("expected alice's channel clean=%v", clean)
+		}
+		if bobChannel.IsChannelClean() != clean {
+			t.Fatalf("expected bob's channel clean=%v", clean)
+		}
+	}
+
+	// A freshly opened channel is clean.
+	assertClean(true)
+
+	// Once Alice offers an HTLC, neither channel is clean, whether or not
+	// the HTLC has been locked in.
+	htlc, preimage := createHTLC(0, lnwire.NewMSatFromSatoshis(20000))
+	aliceHtlcIndex, err := aliceChannel.AddHTLC(htlc)
+	if err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	bobHtlcIndex, err := bobChannel.ReceiveHTLC(htlc)
+	if err != nil {
+		t.Fatalf("bob unable to recv add htlc: %v", err)
+	}
+	assertClean(false)
+
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state transition: %v", err)
+	}
+	assertClean(false)
+
+	// Bob then settles the HTLC. The channels aren't clean until the
+	// settle has been locked into both commitments of both parties.
+	if err := bobChannel.SettleHTLC(preimage, bobHtlcIndex); err != nil {
+		t.Fatalf("bob unable to settle htlc: %v", err)
+	}
+	err = aliceChannel.ReceiveHTLCSettle(preimage, aliceHtlcIndex)
+	if err != nil {
+		t.Fatalf("alice unable to accept settle: %v", err)
+	}
+	assertClean(false)
+
+	if err := forceStateTransition(bobChannel, aliceChannel); err != nil {
+		t.Fatalf("unable to complete state transition: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state transition: %v", err)
+	}
+	assertClean(true)
+}
+
 // TestForceClose checks that the resulting ForceCloseSummary is correct when a
 // peer is ForceClosing the channel. Will check outputs both above and below
 // the dust limit. Additionally, we'll ensure that the node which executed the
@@ -2102,7 +2164,7 @@ func TestUpdateFeeSenderCommits(t *testing.T) {
 	// Alice receives the revocation of the old one, and can now assume
 	// that Bob's received everything up to the signature she sent,
 	// including the HTLC and fee update.
-	if _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
+	if _, _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
 		t.Fatalf("alice unable to rocess bob's revocation: %v", err)
 	}
 
@@ -2129,7 +2191,7 @@ func TestUpdateFeeSenderCommits(t *testing.T) {
 	}
 
 	// Bob receives revocation from Alice.
-	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
 		t.Fatalf("bob unable to process alice's revocation: %v", err)
 	}
 
@@ -2197,7 +2259,7 @@ func TestUpdateFeeReceiverCommits(t *testing.T) {
 	}
 
 	// Bob receives the revocation of the old commitment
-	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
 		t.Fatalf("alice unable to rocess bob's revocation: %v", err)
 	}
 
@@ -2241,7 +2303,7 @@ func TestUpdateFeeReceiverCommits(t *testing.T) {
 
 	// Alice receives revokation from Bob, and can now be sure that Bob
 	// received the two updates, and they are considered locked in.
-	if _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
+	if _, _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
 		t.Fatalf("bob unable to process alice's revocation: %v", err)
 	}
 
@@ -2268,7 +2330,7 @@ func TestUpdateFeeReceiverCommits(t *testing.T) {
 	}
 
 	// Bob receives revocation from Alice.
-	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
 		t.Fatalf("bob unable to process alice's revocation: %v", err)
 	}
 }
@@ -2382,7 +2444,7 @@ func TestUpdateFeeMultipleUpdates(t *testing.T) {
 	// Alice receives the revocation of the old one, and can now assume that
 	// Bob's received everything up to the signature she sent, including the
 	// HTLC and fee update.
-	if _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
+	if _, _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
 		t.Fatalf("alice unable to rocess bob's revocation: %v", err)
 	}
 
@@ -2408,7 +2470,7 @@ func TestUpdateFeeMultipleUpdates(t *testing.T) {
 	}
 
 	// Bob receives revocation from Alice.
-	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
 		t.Fatalf("bob unable to process alice's revocation: %v", err)
 	}
 }
@@ -2828,7 +2890,7 @@ func TestChanSyncOweCommitment(t *testing.T) {
 	if err != nil {
 		t.Fatalf("bob unable to sign commitment: %v", err)
 	}
-	_, err = aliceChannel.ReceiveRevocation(bobRevocation)
+	_, _, err = aliceChannel.ReceiveRevocation(bobRevocation)
 	if err != nil {
 		t.Fatalf("alice unable to recv revocation: %v", err)
 	}
@@ -2840,7 +2902,7 @@ func TestChanSyncOweCommitment(t *testing.T) {
 	if err != nil {
 		t.Fatalf("alice unable to revoke commitment: %v", err)
 	}
-	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
 		t.Fatalf("bob unable to recv revocation: %v", err)
 	}
 
@@ -2998,7 +3060,7 @@ func TestChanSyncOweRevocation(t *testing.T) {
 		t.Fatalf("bob unable to sign commitment: %v", err)
 	}
 
-	_, err = aliceChannel.ReceiveRevocation(bobRevocation)
+	_, _, err = aliceChannel.ReceiveRevocation(bobRevocation)
 	if err != nil {
 		t.Fatalf("alice unable to recv revocation: %v", err)
 	}
@@ -3081,7 +3143,7 @@ func TestChanSyncOweRevocation(t *testing.T) {
 	// TODO(roasbeef): restart bob too???
 
 	// We'll continue by then allowing bob to process Alice's revocation message.
-	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
 		t.Fatalf("bob unable to recv revocation: %v", err)
 	}
 
@@ -3261,7 +3323,7 @@ func TestChanSyncOweRevocationAndCommit(t *testing.T) {
 
 	// We'll now finish the state transition by having Alice process both
 	// messages, and send her final revocation.
-	_, err = aliceChannel.ReceiveRevocation(bobRevocation)
+	_, _, err = aliceChannel.ReceiveRevocation(bobRevocation)
 	if err != nil {
 		t.Fatalf("alice unable to recv revocation: %v", err)
 	}
@@ -3273,7 +3335,7 @@ func TestChanSyncOweRevocationAndCommit(t *testing.T) {
 	if err != nil {
 		t.Fatalf("alice unable to revoke commitment: %v", err)
 	}
-	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
 		t.Fatalf("bob unable to recv revocation: %v", err)
 	}
 }
@@ -3447,7 +3509,7 @@ func TestChanSyncOweRevocationAndCommitForceTransition(t *testing.T) {
 	// Now, we'll continue the exchange, sending Bob's revocation and
 	// signature message to Alice, ending with Alice sending her revocation
 	// message to Bob.
-	_, err = aliceChannel.ReceiveRevocation(bobRevocation)
+	_, _, err = aliceChannel.ReceiveRevocation(bobRevocation)
 	if err != nil {
 		t.Fatalf("alice unable to recv revocation: %v", err)
 	}
@@ -3461,7 +3523,7 @@ func TestChanSyncOweRevocationAndCommitForceTransition(t *testing.T) {
 	if err != nil {
 		t.Fatalf("alice unable to revoke commitment: %v", err)
 	}
-	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
 		t.Fatalf("bob unable to recv revocation: %v", err)
 	}
 }
@@ -3633,7 +3695,7 @@ func TestChannelRetransmissionFeeUpdate(t *testing.T) {
 	if err != nil {
 		t.Fatalf("bob unable to sign commitment: %v", err)
 	}
-	_, err = aliceChannel.ReceiveRevocation(bobRevocation)
+	_, _, err = aliceChannel.ReceiveRevocation(bobRevocation)
 	if err != nil {
 		t.Fatalf("alice unable to recv revocation: %v", err)
 	}
@@ -3645,7 +3707,7 @@ func TestChannelRetransmissionFeeUpdate(t *testing.T) {
 	if err != nil {
 		t.Fatalf("alice unable to revoke commitment: %v", err)
 	}
-	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+	if _, _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
 		t.Fatalf("bob unable to recv revocation: %v", err)
 	}
 
@@ -3921,6 +3983,87 @@ func TestChanAvailableBandwidth(t *testing.T) {
 	// TODO(roasbeef): additional tests from diff starting conditions
 }
 
+// TestChanRemoteAvailableBandwidth tests that the RemoteAvailableBalance()
+// method reflects the balance the remote party has available within the
+// channel, as computed by the remote party itself through AvailableBalance(),
+// both before and after a state transition.
+func TestChanRemoteAvailableBandwidth(t *testing.T) {
+	t.Parallel()
+
+	// Create a test channel which will be used for the duration of this
+	// unittest. The channel will be funded evenly with Alice having 5 BTC,
+	// and Bob having 5 BTC.
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	assertRemoteBandwidth := func() {
+		remoteBalance := aliceChannel.RemoteAvailableBalance()
+		bobBalance := bobChannel.AvailableBalance()
+		if remoteBalance != bobBalance {
+			_, _, line, _ := runtime.Caller(1)
+			t.Fatalf("line: %v, incorrect remote balance: "+
+				"expected %v, got %v", line, bobBalance,
+				remoteBalance)
+		}
+	}
+
+	assertRemoteBandwidth()
+
+	// First, we'll have Bob add a set of HTLC's to Alice, which should
+	// reduce his balance even before they're locked in.
+	const numHtlcs = 3
+	htlcAmt := lnwire.NewMSatFromSatoshis(30000)
+	bobPreimages := make([][32]byte, numHtlcs)
+	for i := 0; i < numHtlcs; i++ {
+		htlc, preImage := createHTLC(i, htlcAmt)
+		if _, err := bobChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("unable to add htlc: %v", err)
+		}
+		if _, err := aliceChannel.ReceiveHTLC(htlc); err != nil {
+			t.Fatalf("unable to recv htlc: %v", err)
+		}
+
+		bobPreimages[i] = preImage
+	}
+
+	assertRemoteBandwidth()
+
+	if err := forceStateTransition(bobChannel, aliceChannel); err != nil {
+		t.Fatalf("unable to complete bob's state transition: %v", err)
+	}
+
+	assertRemoteBandwidth()
+
+	// Next, we'll have Alice settle one of Bob's HTLC's, and fail
+	// another, the latter of which should credit Bob's balance.
+	err = aliceChannel.SettleHTLC(bobPreimages[0], 0)
+	if err != nil {
+		t.Fatalf("unable to settle htlc: %v", err)
+	}
+	err = bobChannel.ReceiveHTLCSettle(bobPreimages[0], 0)
+	if err != nil {
+		t.Fatalf("unable to settle htlc: %v", err)
+	}
+	if err := aliceChannel.FailHTLC(1, []byte("f")); err != nil {
+		t.Fatalf("unable to cancel HTLC: %v", err)
+	}
+	if err := bobChannel.ReceiveFailHTLC(1, []byte("f")); err != nil {
+		t.Fatalf("unable to recv htlc cancel: %v", err)
+	}
+
+	assertRemoteBandwidth()
+
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete alice's state transition: %v",
+			err)
+	}
+
+	assertRemoteBandwidth()
+}
+
 // TestSignCommitmentFailNotLockedIn tests that a channel will not attempt to
 // create a new state if it doesn't yet know of the next revocation point for
 // the remote party.
@@ -3999,7 +4142,7 @@ func TestLockedInHtlcForwardingSkipAfterRestart(t *testing.T) {
 	}
 
 	// Alice should detect that she doesn't need to forward any HTLC's.
-	aliceHtlcsToForward, err := aliceChannel.ReceiveRevocation(
+	_, aliceHtlcsToForward, err := aliceChannel.ReceiveRevocation(
 		bobRevocation,
 	)
 	if err != nil {
@@ -4021,7 +4164,7 @@ func TestLockedInHtlcForwardingSkipAfterRestart(t *testing.T) {
 
 	// Bob on the other hand, should detect that he now has 2 incoming
 	// HTLC's that he can forward along.
-	bobHtlcsToForward, err := bobChannel.ReceiveRevocation(aliceRevocation)
+	_, bobHtlcsToForward, err := bobChannel.ReceiveRevocation(aliceRevocation)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -4074,7 +4217,7 @@ func TestLockedInHtlcForwardingSkipAfterRestart(t *testing.T) {
 	// At this point, Bob receives the revocation from Alice, which is now
 	// his signal to examine all the HTLC's that have been locked in to
 	// process.
-	bobHtlcsToForward, err = bobChannel.ReceiveRevocation(aliceRevocation)
+	_, bobHtlcsToForward, err = bobChannel.ReceiveRevocation(aliceRevocation)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -4294,3 +4437,177 @@ func TestChannelUnilateralCloseHtlcResolution(t *testing.T) {
 }
 
 // TODO(roasbeef): testing.Quick test case for retrans!!!
+
+// TestChannelInvariants tests that the invariants of the commitment state
+// machine hold across state transitions, and that a commitment which doesn't
+// conserve the channel's capacity is detected.
+func TestChannelInvariants(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// We'll add a regular and a dust HTLC, both of which must be
+	// accounted for within the commitments of both parties.
+	for i, amt := range []lnwire.MilliSatoshi{
+		lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin), 1000,
+	} {
+		htlc, _ := createHTLC(i, amt)
+		if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("unable to add htlc: %v", err)
+		}
+		if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+			t.Fatalf("unable to recv htlc: %v", err)
+		}
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state transition: %v", err)
+	}
+
+	for _, channel := range []*LightningChannel{aliceChannel, bobChannel} {
+		if err := channel.checkInvariants(); err != nil {
+			t.Fatalf("invariants violated: %v", err)
+		}
+	}
+
+	// If a commitment's balance is tampered with, then it no longer
+	// conserves the channel's capacity, which should be detected.
+	aliceChannel.localCommitChain.tip().ourBalance++
+	if err := aliceChannel.checkInvariants(); err == nil {
+		t.Fatalf("expected capacity conservation to be violated")
+	}
+	aliceChannel.localCommitChain.tip().ourBalance--
+
+	// Similarly, duplicate HTLC indexes within an update log should be
+	// detected.
+	for e := aliceChannel.localUpdateLog.Front(); e != nil; e = e.Next() {
+		e.Value.(*PaymentDescriptor).HtlcIndex = 0
+	}
+	if err := aliceChannel.checkInvariants(); err == nil {
+		t.Fatalf("expected htlc index uniqueness to be violated")
+	}
+}
+
+// TestIncomingHtlcResolved tests that an incoming HTLC is reported as resolved
+// once it's been settled, and that this survives a restart if the settle was
+// included within a commitment which the remote party hasn't yet revoked
+// their prior commitment for.
+func TestIncomingHtlcResolved(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Alice will offer Bob an HTLC, which is locked into both
+	// commitments.
+	htlc, preimage := createHTLC(0, lnwire.MilliSatoshi(1e7))
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("unable to add htlc: %v", err)
+	}
+	bobHtlcIndex, err := bobChannel.ReceiveHTLC(htlc)
+	if err != nil {
+		t.Fatalf("unable to receive htlc: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	if bobChannel.IncomingHtlcResolved(bobHtlcIndex) {
+		t.Fatalf("htlc shouldn't be resolved yet")
+	}
+
+	// Once Bob settles the HTLC, it should be reported as resolved.
+	if err := bobChannel.SettleHTLC(preimage, bobHtlcIndex); err != nil {
+		t.Fatalf("unable to settle htlc: %v", err)
+	}
+	if !bobChannel.IncomingHtlcResolved(bobHtlcIndex) {
+		t.Fatalf("settled htlc should be resolved")
+	}
+
+	// Bob signs a commitment including the settle, which Alice doesn't
+	// receive. After restarting, the settle should be restored from the
+	// dangling commitment, so the HTLC should still be reported as
+	// resolved.
+	if _, _, err := bobChannel.SignNextCommitment(); err != nil {
+		t.Fatalf("unable to sign commitment: %v", err)
+	}
+	bobChannel, err = restartChannel(bobChannel)
+	if err != nil {
+		t.Fatalf("unable to restart channel: %v", err)
+	}
+	defer bobChannel.Stop()
+
+	if !bobChannel.IncomingHtlcResolved(bobHtlcIndex) {
+		t.Fatalf("settled htlc should be resolved after restart")
+	}
+}
+
+// benchmarkReceiveNewCommitment benchmarks the verification of a commitment
+// carrying the maximum number of HTLCs a single party may add, with the
+// receiver's HTLC signatures verified by the given number of workers.
+func benchmarkReceiveNewCommitment(b *testing.B, numWorkers int) {
+	numHtlcs := MaxHTLCNumber / 2
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+
+		aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+		if err != nil {
+			b.Fatalf("unable to create test channels: %v", err)
+		}
+
+		// Replace Bob's sigPool with one using the desired number of
+		// workers.
+		bobChannel.sigPool.Stop()
+		bobChannel.sigPool = newSigPool(numWorkers, bobChannel.signer)
+		if err := bobChannel.sigPool.Start(); err != nil {
+			b.Fatalf("unable to start sig pool: %v", err)
+		}
+
+		for j := 0; j < numHtlcs; j++ {
+			htlc, _ := createHTLC(j, lnwire.MilliSatoshi(1e7))
+			if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+				b.Fatalf("alice unable to add htlc: %v", err)
+			}
+			if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+				b.Fatalf("bob unable to receive htlc: %v", err)
+			}
+		}
+
+		aliceSig, aliceHtlcSigs, err := aliceChannel.SignNextCommitment()
+		if err != nil {
+			b.Fatalf("alice unable to sign commitment: %v", err)
+		}
+		if len(aliceHtlcSigs) != numHtlcs {
+			b.Fatalf("expected %v htlc sigs, got %v", numHtlcs,
+				len(aliceHtlcSigs))
+		}
+
+		b.StartTimer()
+		err = bobChannel.ReceiveNewCommitment(aliceSig, aliceHtlcSigs)
+		b.StopTimer()
+		if err != nil {
+			b.Fatalf("bob unable to receive commitment: %v", err)
+		}
+
+		cleanUp()
+	}
+}
+
+// BenchmarkReceiveNewCommitmentSerial benchmarks the verification of a
+// commitment with the maximum number of HTLCs by a single worker.
+func BenchmarkReceiveNewCommitmentSerial(b *testing.B) {
+	benchmarkReceiveNewCommitment(b, 1)
+}
+
+// BenchmarkReceiveNewCommitmentParallel benchmarks the verification of a
+// commitment with the maximum number of HTLCs by the default number of
+// workers.
+func BenchmarkReceiveNewCommitmentParallel(b *testing.B) {
+	benchmarkReceiveNewCommitment(b, numSigWorkers())
+}
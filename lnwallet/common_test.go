@@ -13,6 +13,12 @@ import (
 	"github.com/roasbeef/btcutil"
 )
 
+func init() {
+	// Any violation of the commitment state machine's invariants within
+	// tests built with the debug build tag should fail loudly.
+	PanicOnInvariantViolation = true
+}
+
 // mockSigner is a simple implementation of the Signer interface. Each one has
 // a set of private keys in a slice and can sign messages using the appropriate
 // one.
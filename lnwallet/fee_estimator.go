@@ -31,6 +31,71 @@ type FeeEstimator interface {
 	Stop() error
 }
 
+// RelayFeeEstimator is a FeeEstimator which is also able to report the
+// minimum fee rate a transaction must pay in order to be accepted into the
+// mempool of the chain backend, and relayed.
+type RelayFeeEstimator interface {
+	FeeEstimator
+
+	// RelayFeePerWeight returns the minimum fee rate, expressed in
+	// satoshis/weight, a transaction must pay in order to be accepted into
+	// the backend's mempool. During mempool congestion, this may exceed
+	// the backend's static minimum relay fee.
+	RelayFeePerWeight() (btcutil.Amount, error)
+}
+
+// EstimateRelayableFeePerWeight queries the passed estimator for the fee rate,
+// expressed in satoshis/weight, required for a transaction to confirm within
+// numBlocks blocks. If the estimator is a RelayFeeEstimator, then the backend's
+// minimum mempool fee rate is enforced as a floor, ensuring the transaction
+// will propagate. If the floor can't be queried, the estimate is returned
+// as is.
+func EstimateRelayableFeePerWeight(estimator FeeEstimator,
+	numBlocks uint32) (btcutil.Amount, error) {
+
+	feePerWeight, err := estimator.EstimateFeePerWeight(numBlocks)
+	if err != nil {
+		return 0, err
+	}
+
+	relayEstimator, ok := estimator.(RelayFeeEstimator)
+	if !ok {
+		return feePerWeight, nil
+	}
+
+	relayFeePerWeight, err := relayEstimator.RelayFeePerWeight()
+	if err != nil {
+		walletLog.Errorf("unable to query relay fee: %v", err)
+		return feePerWeight, nil
+	}
+
+	if feePerWeight < relayFeePerWeight {
+		walletLog.Debugf("Raising fee rate of %v sat/weight to "+
+			"mempool minimum of %v sat/weight", int64(feePerWeight),
+			int64(relayFeePerWeight))
+
+		return relayFeePerWeight, nil
+	}
+
+	return feePerWeight, nil
+}
+
+// relayFeePerWeight converts a fee rate expressed in BTC/kB, as returned by
+// the RPC interface of the chain backends, to satoshis/weight. The result is
+// rounded up, so it never falls below the passed fee rate.
+func relayFeePerWeight(btcPerKB float64) (btcutil.Amount, error) {
+	satPerKB, err := btcutil.NewAmount(btcPerKB)
+	if err != nil {
+		return 0, err
+	}
+
+	// There are 1000 bytes within a kB, and each byte maps to
+	// WitnessScaleFactor units of weight.
+	const weightPerKB = 1000 * blockchain.WitnessScaleFactor
+
+	return (satPerKB + weightPerKB - 1) / weightPerKB, nil
+}
+
 // StaticFeeEstimator will return a static value for all fee calculation
 // requests. It is designed to be replaced by a proper fee calculation
 // implementation.
@@ -199,9 +264,22 @@ func (b *BtcdFeeEstimator) fetchEstimatePerByte(confTarget uint32) (btcutil.Amou
 	return satPerByte, nil
 }
 
+// RelayFeePerWeight returns the minimum fee rate, expressed in
+// satoshis/weight, a transaction must pay in order to be relayed by btcd.
+//
+// NOTE: This method is part of the RelayFeeEstimator interface.
+func (b *BtcdFeeEstimator) RelayFeePerWeight() (btcutil.Amount, error) {
+	info, err := b.btcdConn.GetInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	return relayFeePerWeight(info.RelayFee)
+}
+
 // A compile-time assertion to ensure that BtcdFeeEstimator implements the
-// FeeEstimator interface.
-var _ FeeEstimator = (*BtcdFeeEstimator)(nil)
+// RelayFeeEstimator interface.
+var _ RelayFeeEstimator = (*BtcdFeeEstimator)(nil)
 
 // BitcoindFeeEstimator is an implementation of the FeeEstimator interface
 // backed by the RPC interface of an active bitcoind node. This implementation
@@ -341,6 +419,35 @@ func (b *BitcoindFeeEstimator) fetchEstimatePerByte(confTarget uint32) (btcutil.
 	return satPerByte, nil
 }
 
+// RelayFeePerWeight returns the minimum fee rate, expressed in
+// satoshis/weight, a transaction must pay in order to be accepted into
+// bitcoind's mempool. Once the mempool is full, bitcoind raises its minimum
+// fee above the static minimum relay fee, so we'll use the greater of the
+// two.
+//
+// NOTE: This method is part of the RelayFeeEstimator interface.
+func (b *BitcoindFeeEstimator) RelayFeePerWeight() (btcutil.Amount, error) {
+	resp, err := b.bitcoindConn.RawRequest("getmempoolinfo", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	mempoolInfo := struct {
+		MempoolMinFee float64 `json:"mempoolminfee"`
+		MinRelayTxFee float64 `json:"minrelaytxfee"`
+	}{}
+	if err := json.Unmarshal(resp, &mempoolInfo); err != nil {
+		return 0, err
+	}
+
+	minFee := mempoolInfo.MempoolMinFee
+	if mempoolInfo.MinRelayTxFee > minFee {
+		minFee = mempoolInfo.MinRelayTxFee
+	}
+
+	return relayFeePerWeight(minFee)
+}
+
 // A compile-time assertion to ensure that BitcoindFeeEstimator implements the
-// FeeEstimator interface.
-var _ FeeEstimator = (*BitcoindFeeEstimator)(nil)
+// RelayFeeEstimator interface.
+var _ RelayFeeEstimator = (*BitcoindFeeEstimator)(nil)
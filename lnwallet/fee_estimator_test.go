@@ -0,0 +1,108 @@
+package lnwallet
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// mockRelayFeeEstimator is a RelayFeeEstimator which returns a static fee
+// estimate along with a static relay fee.
+type mockRelayFeeEstimator struct {
+	StaticFeeEstimator
+
+	relayFeePerWeight btcutil.Amount
+	relayFeeErr       error
+}
+
+func (m *mockRelayFeeEstimator) RelayFeePerWeight() (btcutil.Amount, error) {
+	return m.relayFeePerWeight, m.relayFeeErr
+}
+
+// TestEstimateRelayableFeePerWeight tests that the relay fee of a
+// RelayFeeEstimator is enforced as a floor for fee estimates.
+func TestEstimateRelayableFeePerWeight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		estimator FeeEstimator
+		expected  btcutil.Amount
+	}{
+		{
+			name:      "no relay fee",
+			estimator: StaticFeeEstimator{FeeRate: 40},
+			expected:  10,
+		},
+		{
+			name: "estimate above relay fee",
+			estimator: &mockRelayFeeEstimator{
+				StaticFeeEstimator: StaticFeeEstimator{FeeRate: 40},
+				relayFeePerWeight:  5,
+			},
+			expected: 10,
+		},
+		{
+			name: "estimate below relay fee",
+			estimator: &mockRelayFeeEstimator{
+				StaticFeeEstimator: StaticFeeEstimator{FeeRate: 40},
+				relayFeePerWeight:  25,
+			},
+			expected: 25,
+		},
+		{
+			name: "relay fee unavailable",
+			estimator: &mockRelayFeeEstimator{
+				StaticFeeEstimator: StaticFeeEstimator{FeeRate: 40},
+				relayFeeErr:        fmt.Errorf("backend unavailable"),
+			},
+			expected: 10,
+		},
+	}
+
+	for _, test := range tests {
+		feePerWeight, err := EstimateRelayableFeePerWeight(
+			test.estimator, 6,
+		)
+		if err != nil {
+			t.Fatalf("%v: unable to estimate fee: %v", test.name,
+				err)
+		}
+		if feePerWeight != test.expected {
+			t.Fatalf("%v: expected fee rate of %v sat/weight, got "+
+				"%v", test.name, int64(test.expected),
+				int64(feePerWeight))
+		}
+	}
+}
+
+// TestRelayFeePerWeight tests the conversion of relay fees expressed in
+// BTC/kB to satoshis/weight, ensuring the result is never rounded down.
+func TestRelayFeePerWeight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		btcPerKB float64
+		expected btcutil.Amount
+	}{
+		{btcPerKB: 0, expected: 0},
+		{btcPerKB: 0.00001, expected: 1},
+		{btcPerKB: 0.00004, expected: 1},
+		{btcPerKB: 0.00004001, expected: 2},
+		{btcPerKB: 0.0002, expected: 5},
+	}
+
+	for _, test := range tests {
+		feePerWeight, err := relayFeePerWeight(test.btcPerKB)
+		if err != nil {
+			t.Fatalf("unable to convert %v BTC/kB: %v",
+				test.btcPerKB, err)
+		}
+		if feePerWeight != test.expected {
+			t.Fatalf("%v BTC/kB: expected %v sat/weight, got %v",
+				test.btcPerKB, int64(test.expected),
+				int64(feePerWeight))
+		}
+	}
+}
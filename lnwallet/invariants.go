@@ -0,0 +1,148 @@
+package lnwallet
+
+import (
+	"container/list"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// PanicOnInvariantViolation, if true, causes a violation of the commitment
+// state machine's invariants to result in a panic, rather than being logged.
+// It should be set within tests, so violations can't go unnoticed. The
+// invariants are only checked within builds using the debug build tag.
+var PanicOnInvariantViolation = false
+
+// assertInvariants checks the invariants of the channel's commitment state
+// machine following the named state transition, if invariant checking is
+// enabled. A violation results in a panic if PanicOnInvariantViolation is
+// set, and is logged otherwise.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) assertInvariants(transition string) {
+	if !invariantsEnabled {
+		return
+	}
+
+	err := lc.checkInvariants()
+	if err == nil {
+		return
+	}
+
+	err = fmt.Errorf("ChannelPoint(%v): invariant violated after %v: %v",
+		lc.channelState.FundingOutpoint, transition, err)
+	if PanicOnInvariantViolation {
+		panic(err)
+	}
+
+	walletLog.Error(err)
+}
+
+// checkInvariants validates the invariants of the channel's commitment state
+// machine. Every commitment within both commitment chains must conserve the
+// channel's capacity, and pay a sane fee, while the HTLC indexes within the
+// commitments and update logs must be unique.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) checkInvariants() error {
+	chains := []*commitmentChain{lc.localCommitChain, lc.remoteCommitChain}
+	for _, chain := range chains {
+		for e := chain.commitments.Front(); e != nil; e = e.Next() {
+			commit := e.Value.(*commitment)
+			if err := lc.checkCommitInvariants(commit); err != nil {
+				return fmt.Errorf("commitment at height %v of "+
+					"the %v chain: %v", commit.height,
+					commitChainName(commit.isOurs), err)
+			}
+		}
+	}
+
+	logs := []*updateLog{lc.localUpdateLog, lc.remoteUpdateLog}
+	for i, log := range logs {
+		if err := checkUniqueHtlcIndexes(log.List); err != nil {
+			return fmt.Errorf("%v update log: %v",
+				commitChainName(i == 0), err)
+		}
+	}
+
+	return nil
+}
+
+// commitChainName returns a human readable name for the local or remote
+// commitment chain or update log.
+func commitChainName(isOurs bool) string {
+	if isOurs {
+		return "local"
+	}
+
+	return "remote"
+}
+
+// checkCommitInvariants validates that the passed commitment conserves the
+// channel's capacity, pays a sane fee, and doesn't contain duplicate HTLCs.
+func (lc *LightningChannel) checkCommitInvariants(c *commitment) error {
+	capacity := lnwire.NewMSatFromSatoshis(lc.channelState.Capacity)
+	fee := lnwire.NewMSatFromSatoshis(c.fee)
+
+	// The fee rate of the commitment must be set, and the fee it pays
+	// can't exceed the capacity of the channel.
+	switch {
+	case c.feePerKw == 0:
+		return fmt.Errorf("fee rate of zero")
+	case fee > capacity:
+		return fmt.Errorf("fee of %v exceeds capacity of %v", fee,
+			capacity)
+	}
+
+	// As the balances are unsigned, a balance that underflowed, such as
+	// that of an initiator unable to pay the fee, would exceed the
+	// capacity.
+	if c.ourBalance > capacity || c.theirBalance > capacity {
+		return fmt.Errorf("balances of %v and %v exceed capacity of %v",
+			c.ourBalance, c.theirBalance, capacity)
+	}
+
+	// The balances of both parties, the value of all HTLCs, including
+	// dust HTLCs, and the fee must sum to the capacity of the channel.
+	total := c.ourBalance + c.theirBalance + fee
+	for _, htlcs := range [][]PaymentDescriptor{
+		c.outgoingHTLCs, c.incomingHTLCs,
+	} {
+		seen := make(map[uint64]struct{}, len(htlcs))
+		for _, htlc := range htlcs {
+			if _, ok := seen[htlc.HtlcIndex]; ok {
+				return fmt.Errorf("duplicate htlc with index "+
+					"%v", htlc.HtlcIndex)
+			}
+			seen[htlc.HtlcIndex] = struct{}{}
+
+			total += htlc.Amount
+		}
+	}
+	if total != capacity {
+		return fmt.Errorf("balances, htlcs and fee sum to %v, "+
+			"expected capacity of %v", total, capacity)
+	}
+
+	return nil
+}
+
+// checkUniqueHtlcIndexes validates that no two HTLCs added within the passed
+// update log share the same HTLC index.
+func checkUniqueHtlcIndexes(log *list.List) error {
+	seen := make(map[uint64]struct{})
+	for e := log.Front(); e != nil; e = e.Next() {
+		pd := e.Value.(*PaymentDescriptor)
+		if pd.EntryType != Add {
+			continue
+		}
+
+		if _, ok := seen[pd.HtlcIndex]; ok {
+			return fmt.Errorf("duplicate htlc with index %v",
+				pd.HtlcIndex)
+		}
+		seen[pd.HtlcIndex] = struct{}{}
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+// +build debug
+
+package lnwallet
+
+// invariantsEnabled indicates whether the commitment state machine's
+// invariants are checked after each state transition. They're only checked
+// within builds using the debug build tag.
+const invariantsEnabled = true
@@ -121,6 +121,14 @@ type ChannelReservation struct {
 	// commitment state.
 	pushMSat lnwire.MilliSatoshi
 
+	// remoteChanReserve, if non-zero, overrides the default reserve we
+	// require the remote party to maintain within the channel.
+	remoteChanReserve btcutil.Amount
+
+	// remoteMaxValue, if non-zero, overrides the default maximum value of
+	// unresolved HTLCs we permit the remote party to offer us.
+	remoteMaxValue lnwire.MilliSatoshi
+
 	// chanOpen houses a struct containing the channel and additional
 	// confirmation details will be sent on once the channel is considered
 	// 'open'. A channel is open once the funding transaction has reached a
@@ -270,6 +278,47 @@ func (r *ChannelReservation) RegisterMinHTLC(minHTLC lnwire.MilliSatoshi) {
 	r.ourContribution.MinHTLC = minHTLC
 }
 
+// RegisterRemoteChanReserve overrides the default reserve we require the
+// remote party to maintain within the channel. This allows a lower reserve to
+// be specified for parties that can't afford to lock up the default 1% of the
+// channel capacity. The reserve must be at least the default dust limit, as
+// the remote party's reserve output would otherwise be unable to propagate.
+func (r *ChannelReservation) RegisterRemoteChanReserve(reserve btcutil.Amount) error {
+	r.Lock()
+	defer r.Unlock()
+
+	dustLimit := DefaultDustLimit()
+	if reserve < dustLimit {
+		return fmt.Errorf("channel reserve of %v is below the dust "+
+			"limit of %v", reserve, dustLimit)
+	}
+	if reserve >= r.partialState.Capacity {
+		return fmt.Errorf("channel reserve of %v exceeds the channel "+
+			"capacity of %v", reserve, r.partialState.Capacity)
+	}
+
+	r.remoteChanReserve = reserve
+	return nil
+}
+
+// RegisterRemoteMaxValueInFlight overrides the default maximum total value of
+// unresolved HTLCs the remote party may offer us within the channel, which is
+// otherwise the full capacity of the channel, less the remote party's
+// reserve. A value beyond the default has no effect.
+func (r *ChannelReservation) RegisterRemoteMaxValueInFlight(
+	maxValue lnwire.MilliSatoshi) error {
+
+	r.Lock()
+	defer r.Unlock()
+
+	if maxValue == 0 {
+		return fmt.Errorf("max value in flight must be positive")
+	}
+
+	r.remoteMaxValue = maxValue
+	return nil
+}
+
 // CommitConstraints takes the constraints that the remote party specifies for
 // the type of commitments that we can generate for them. These constraints
 // include several parameters that serve as flow control restricting the amount
@@ -303,11 +352,24 @@ func (r *ChannelReservation) RemoteChanConstraints() (btcutil.Amount, lnwire.Mil
 	// By default, we'll require them to maintain at least 1% of the total
 	// channel capacity at all times. This is the absolute amount the
 	// settled balance of the remote party must be above at *all* times.
-	chanReserve := (chanCapacity) / 100
+	// If a custom reserve has been registered, then we'll use that
+	// instead.
+	r.RLock()
+	chanReserve := r.remoteChanReserve
+	r.RUnlock()
+	if chanReserve == 0 {
+		chanReserve = chanCapacity / 100
+	}
 
 	// We'll allow them to fully utilize the full bandwidth of the channel,
-	// minus our required reserve.
+	// minus our required reserve, unless a lower maximum value in flight
+	// has been registered.
 	maxValue := lnwire.NewMSatFromSatoshis(chanCapacity - chanReserve)
+	r.RLock()
+	if r.remoteMaxValue != 0 && r.remoteMaxValue < maxValue {
+		maxValue = r.remoteMaxValue
+	}
+	r.RUnlock()
 
 	// Finally, we'll permit them to utilize the full channel bandwidth
 	maxHTLCs := uint16(MaxHTLCNumber / 2)
@@ -1323,3 +1323,36 @@ func ComputeCommitmentPoint(commitSecret []byte) *btcec.PublicKey {
 		Curve: btcec.S256(),
 	}
 }
+
+// ValidateDeliveryScript returns an error if the passed script isn't one of
+// the forms a cooperative close may pay out to, as set out by BOLT #2: P2PKH,
+// P2SH, a version 0 witness program of 20 or 32 bytes, or a witness program
+// of version 1 through 16 of 2 to 40 bytes, such as a P2TR script. Closing
+// transactions paying to any other script may not be relayed, and could weigh
+// more than the fee negotiation accounts for.
+func ValidateDeliveryScript(script []byte) error {
+	switch txscript.GetScriptClass(script) {
+	case txscript.PubKeyHashTy, txscript.ScriptHashTy,
+		txscript.WitnessV0PubKeyHashTy, txscript.WitnessV0ScriptHashTy:
+
+		return nil
+	}
+
+	// Otherwise, the script must be a witness program of a future
+	// version, consisting of the version opcode followed by a single
+	// push of the program itself.
+	if len(script) < 4 || len(script) > 42 {
+		return fmt.Errorf("delivery script of %v bytes isn't a valid "+
+			"witness program", len(script))
+	}
+	if script[0] < txscript.OP_1 || script[0] > txscript.OP_16 {
+		return fmt.Errorf("delivery script %x isn't of a standard "+
+			"type", script)
+	}
+	if int(script[1]) != len(script)-2 {
+		return fmt.Errorf("delivery script %x has an invalid witness "+
+			"program push", script)
+	}
+
+	return nil
+}
@@ -1200,3 +1200,66 @@ func pubkeyToHex(key *btcec.PublicKey) string {
 func privkeyToHex(key *btcec.PrivateKey) string {
 	return hex.EncodeToString(key.Serialize())
 }
+
+// TestValidateDeliveryScript tests that only the script types BOLT #2 permits
+// a cooperative close to pay out to are accepted as delivery scripts.
+func TestValidateDeliveryScript(t *testing.T) {
+	t.Parallel()
+
+	hash20 := bytes.Repeat([]byte{1}, 20)
+	hash32 := bytes.Repeat([]byte{2}, 32)
+
+	p2pkh, err := txscript.NewScriptBuilder().AddOp(txscript.OP_DUP).
+		AddOp(txscript.OP_HASH160).AddData(hash20).
+		AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	p2sh, err := txscript.NewScriptBuilder().AddOp(txscript.OP_HASH160).
+		AddData(hash20).AddOp(txscript.OP_EQUAL).Script()
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	witnessProgram := func(version byte, program []byte) []byte {
+		return append([]byte{version, byte(len(program))}, program...)
+	}
+
+	tests := []struct {
+		name   string
+		script []byte
+		valid  bool
+	}{
+		{"p2pkh", p2pkh, true},
+		{"p2sh", p2sh, true},
+		{"p2wpkh", witnessProgram(txscript.OP_0, hash20), true},
+		{"p2wsh", witnessProgram(txscript.OP_0, hash32), true},
+		{"p2tr", witnessProgram(txscript.OP_1, hash32), true},
+		{"v16 2 bytes", witnessProgram(txscript.OP_16, hash20[:2]), true},
+		{"v1 40 bytes", witnessProgram(
+			txscript.OP_1, bytes.Repeat([]byte{3}, 40),
+		), true},
+		{"v0 31 bytes", witnessProgram(txscript.OP_0, hash32[:31]), false},
+		{"v1 41 bytes", witnessProgram(
+			txscript.OP_1, bytes.Repeat([]byte{3}, 41),
+		), false},
+		{"v1 1 byte", witnessProgram(txscript.OP_1, hash20[:1]), false},
+		{"bad push", append(
+			witnessProgram(txscript.OP_1, hash32), 0,
+		), false},
+		{"op_return", []byte{txscript.OP_RETURN, 1, 1}, false},
+		{"raw bytes", hash32, false},
+		{"empty", nil, false},
+	}
+
+	for _, test := range tests {
+		err := ValidateDeliveryScript(test.script)
+		if test.valid && err != nil {
+			t.Fatalf("%v: expected script to be valid: %v",
+				test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("%v: expected script to be invalid", test.name)
+		}
+	}
+}
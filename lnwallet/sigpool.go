@@ -2,6 +2,7 @@ package lnwallet
 
 import (
 	"fmt"
+	"runtime"
 	"sync"
 	"sync/atomic"
 
@@ -124,6 +125,15 @@ type sigPool struct {
 	numWorkers int
 }
 
+// numSigWorkers returns the number of workers each channel's sigPool should
+// be started with. As signing and verification are purely CPU bound, we'll
+// use one worker for each CPU the Go scheduler is permitted to use, so that
+// the HTLC signatures of a large commitment are verified across all of them,
+// rather than stalling the link serially.
+func numSigWorkers() int {
+	return runtime.GOMAXPROCS(0)
+}
+
 // newSigPool creates a new signature pool with the specified number of
 // workers. The recommended parameter for the number of works is the number of
 // physical CPU cores available on the target machine.
@@ -219,6 +229,8 @@ func (s *sigPool) poolWorker() {
 					continue
 				case <-verifyMsg.cancel:
 					continue
+				case <-s.quit:
+					return
 				}
 			}
 
@@ -0,0 +1,200 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+)
+
+const (
+	// ProtocolVersionLegacy is the protocol version under which messages
+	// are encoded without any trailing extension records. It's used when
+	// messages are persisted, as the stored messages aren't length
+	// prefixed.
+	ProtocolVersionLegacy uint32 = 0
+
+	// ProtocolVersionExtensions is the protocol version under which
+	// messages may carry a trailing stream of TLV extension records that
+	// runs to the end of the message. It MUST only be used when the length
+	// of the encoded message is known, such as for messages sent over the
+	// wire.
+	ProtocolVersionExtensions uint32 = 1
+)
+
+// extensionRecord is a single TLV record within the extension stream of a
+// message.
+type extensionRecord struct {
+	recordType uint64
+	value      []byte
+}
+
+// writeBigSize writes a BigSize integer, as defined in BOLT #1, to the passed
+// io.Writer.
+func writeBigSize(w io.Writer, v uint64) error {
+	var b []byte
+	switch {
+	case v < 0xfd:
+		b = []byte{byte(v)}
+	case v <= 0xffff:
+		b = make([]byte, 3)
+		b[0] = 0xfd
+		binary.BigEndian.PutUint16(b[1:], uint16(v))
+	case v <= 0xffffffff:
+		b = make([]byte, 5)
+		b[0] = 0xfe
+		binary.BigEndian.PutUint32(b[1:], uint32(v))
+	default:
+		b = make([]byte, 9)
+		b[0] = 0xff
+		binary.BigEndian.PutUint64(b[1:], v)
+	}
+
+	_, err := w.Write(b)
+	return err
+}
+
+// readBigSize reads a BigSize integer, as defined in BOLT #1, from the passed
+// io.Reader. Non-canonical encodings are rejected.
+func readBigSize(r io.Reader) (uint64, error) {
+	var discriminant [1]byte
+	if _, err := io.ReadFull(r, discriminant[:]); err != nil {
+		return 0, err
+	}
+
+	var (
+		b   []byte
+		min uint64
+	)
+	switch discriminant[0] {
+	case 0xfd:
+		b, min = make([]byte, 2), 0xfd
+	case 0xfe:
+		b, min = make([]byte, 4), 0x10000
+	case 0xff:
+		b, min = make([]byte, 8), 0x100000000
+	default:
+		return uint64(discriminant[0]), nil
+	}
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	var v uint64
+	for _, byt := range b {
+		v = v<<8 | uint64(byt)
+	}
+	if v < min {
+		return 0, fmt.Errorf("non-canonical BigSize encoding")
+	}
+
+	return v, nil
+}
+
+// writeExtension writes the passed records, which MUST be sorted by their
+// type, as a TLV stream to the passed io.Writer.
+func writeExtension(w io.Writer, records []extensionRecord) error {
+	for _, record := range records {
+		if err := writeBigSize(w, record.recordType); err != nil {
+			return err
+		}
+		err := writeBigSize(w, uint64(len(record.value)))
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(record.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readExtension reads the remainder of the passed io.Reader as a TLV stream.
+// The records are returned keyed by their type. An error is returned if the
+// stream is malformed, or contains an unknown even record, as per the "it's
+// ok to be odd" rule.
+func readExtension(r io.Reader,
+	knownTypes map[uint64]struct{}) (map[uint64][]byte, error) {
+
+	stream, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[uint64][]byte)
+	var (
+		prevType uint64
+		first    = true
+		reader   = bytes.NewReader(stream)
+	)
+	for reader.Len() > 0 {
+		recordType, err := readBigSize(reader)
+		if err != nil {
+			return nil, err
+		}
+		if !first && recordType <= prevType {
+			return nil, fmt.Errorf("extension records not in " +
+				"ascending order")
+		}
+		first, prevType = false, recordType
+
+		length, err := readBigSize(reader)
+		if err != nil {
+			return nil, err
+		}
+		if length > uint64(reader.Len()) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		value := make([]byte, length)
+		if _, err := io.ReadFull(reader, value); err != nil {
+			return nil, err
+		}
+
+		if _, ok := knownTypes[recordType]; ok {
+			records[recordType] = value
+			continue
+		}
+		if recordType%2 == 0 {
+			return nil, fmt.Errorf("unknown required extension "+
+				"record of type %v", recordType)
+		}
+	}
+
+	return records, nil
+}
+
+// EncodeTLVStream encodes the passed records, keyed by their type, as a TLV
+// stream sorted by type, using the same encoding as the extension records of
+// messages.
+func EncodeTLVStream(records map[uint64][]byte) []byte {
+	sorted := make([]extensionRecord, 0, len(records))
+	for recordType, value := range records {
+		sorted = append(sorted, extensionRecord{
+			recordType: recordType,
+			value:      value,
+		})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].recordType < sorted[j].recordType
+	})
+
+	// Writing to a bytes.Buffer never fails.
+	var b bytes.Buffer
+	_ = writeExtension(&b, sorted)
+
+	return b.Bytes()
+}
+
+// DecodeTLVStream decodes a TLV stream created by EncodeTLVStream. The records
+// of the known types are returned keyed by their type, while unknown odd
+// records are skipped. An error is returned if the stream is malformed, or
+// contains an unknown even record.
+func DecodeTLVStream(stream []byte,
+	knownTypes map[uint64]struct{}) (map[uint64][]byte, error) {
+
+	return readExtension(bytes.NewReader(stream), knownTypes)
+}
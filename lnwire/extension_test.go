@@ -0,0 +1,108 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestUpdateAddHTLCEndorsement checks that the experimental endorsement
+// signal is only encoded under the extension protocol version, and that
+// unknown extension records are handled as per the "it's ok to be odd" rule.
+func TestUpdateAddHTLCEndorsement(t *testing.T) {
+	t.Parallel()
+
+	htlc := &UpdateAddHTLC{
+		ID:       1,
+		Amount:   1000,
+		Expiry:   144,
+		Endorsed: true,
+	}
+
+	encode := func(pver uint32) []byte {
+		var b bytes.Buffer
+		if err := htlc.Encode(&b, pver); err != nil {
+			t.Fatalf("unable to encode htlc: %v", err)
+		}
+		return b.Bytes()
+	}
+
+	// Under the legacy protocol version, the endorsement shouldn't be
+	// encoded at all.
+	legacy := encode(ProtocolVersionLegacy)
+	if uint32(len(legacy)) != htlc.MaxPayloadLength(ProtocolVersionLegacy) {
+		t.Fatalf("legacy encoding should have fixed length %v, got %v",
+			htlc.MaxPayloadLength(ProtocolVersionLegacy), len(legacy))
+	}
+	var decoded UpdateAddHTLC
+	err := decoded.Decode(bytes.NewReader(legacy), ProtocolVersionLegacy)
+	if err != nil {
+		t.Fatalf("unable to decode htlc: %v", err)
+	}
+	if decoded.Endorsed {
+		t.Fatalf("endorsement shouldn't survive legacy encoding")
+	}
+
+	// Under the extension protocol version, the endorsement should
+	// survive a round trip.
+	extended := encode(ProtocolVersionExtensions)
+	decoded = UpdateAddHTLC{}
+	err = decoded.Decode(bytes.NewReader(extended), ProtocolVersionExtensions)
+	if err != nil {
+		t.Fatalf("unable to decode htlc: %v", err)
+	}
+	if !decoded.Endorsed {
+		t.Fatalf("endorsement should survive extension encoding")
+	}
+
+	// An unknown odd record should be ignored, while an unknown even
+	// record should cause decoding to fail.
+	var odd bytes.Buffer
+	odd.Write(legacy)
+	writeExtension(&odd, []extensionRecord{
+		{recordType: 1, value: []byte{0xaa}},
+	})
+	decoded = UpdateAddHTLC{}
+	err = decoded.Decode(&odd, ProtocolVersionExtensions)
+	if err != nil {
+		t.Fatalf("unknown odd record should be ignored: %v", err)
+	}
+
+	var even bytes.Buffer
+	even.Write(legacy)
+	writeExtension(&even, []extensionRecord{
+		{recordType: 2, value: []byte{0xaa}},
+	})
+	decoded = UpdateAddHTLC{}
+	err = decoded.Decode(&even, ProtocolVersionExtensions)
+	if err == nil {
+		t.Fatalf("unknown even record should be rejected")
+	}
+}
+
+// TestBigSizeEncoding checks that BigSize integers round trip, and that
+// non-canonical encodings are rejected.
+func TestBigSizeEncoding(t *testing.T) {
+	t.Parallel()
+
+	values := []uint64{
+		0, 0xfc, 0xfd, 0xffff, 0x10000, 0xffffffff, 0x100000000,
+	}
+	for _, v := range values {
+		var b bytes.Buffer
+		if err := writeBigSize(&b, v); err != nil {
+			t.Fatalf("unable to write %v: %v", v, err)
+		}
+		decoded, err := readBigSize(&b)
+		if err != nil {
+			t.Fatalf("unable to read %v: %v", v, err)
+		}
+		if decoded != v {
+			t.Fatalf("expected %v, got %v", v, decoded)
+		}
+	}
+
+	nonCanonical := []byte{0xfd, 0x00, 0xfc}
+	if _, err := readBigSize(bytes.NewReader(nonCanonical)); err == nil {
+		t.Fatalf("non-canonical encoding should be rejected")
+	}
+}
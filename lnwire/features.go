@@ -21,6 +21,56 @@ const (
 	// connection is established.
 	InitialRoutingSync FeatureBit = 3
 
+	// TLVOnionPayloadRequired is a local feature bit that indicates that
+	// the node requires HTLCs forwarded to it to carry variable length
+	// TLV onion payloads.
+	TLVOnionPayloadRequired FeatureBit = 8
+
+	// TLVOnionPayloadOptional is a local feature bit that indicates that
+	// the node is able to decode variable length TLV onion payloads.
+	TLVOnionPayloadOptional FeatureBit = 9
+
+	// StaticRemoteKeyRequired is a local feature bit that indicates that
+	// the node requires the to_remote output of the commitment
+	// transaction to pay to a static key, rather than one that is tweaked
+	// by each commitment point.
+	StaticRemoteKeyRequired FeatureBit = 12
+
+	// StaticRemoteKeyOptional is a local feature bit that indicates that
+	// the node is able to use commitment transactions whose to_remote
+	// output pays to a static key.
+	StaticRemoteKeyOptional FeatureBit = 13
+
+	// AnchorOutputsRequired is a local feature bit that indicates that
+	// the node requires commitment transactions to carry anchor outputs,
+	// which allow either party to bump their fee through CPFP.
+	AnchorOutputsRequired FeatureBit = 20
+
+	// AnchorOutputsOptional is a local feature bit that indicates that
+	// the node is able to use commitment transactions that carry anchor
+	// outputs.
+	AnchorOutputsOptional FeatureBit = 21
+
+	// QuiesceRequired is a local feature bit that indicates that the node
+	// requires its peers to support bringing channels into a quiescent
+	// state through the Stfu message.
+	QuiesceRequired FeatureBit = 34
+
+	// QuiesceOptional is a local feature bit that indicates that the node
+	// supports bringing channels into a quiescent state through the Stfu
+	// message.
+	QuiesceOptional FeatureBit = 35
+
+	// ProvideStorageRequired is a local feature bit that indicates that
+	// the node is required to store a small blob on behalf of its channel
+	// peers, returning it to them upon reconnection.
+	ProvideStorageRequired FeatureBit = 42
+
+	// ProvideStorageOptional is a local feature bit that indicates that
+	// the node is willing to store a small blob on behalf of its channel
+	// peers, returning it to them upon reconnection.
+	ProvideStorageOptional FeatureBit = 43
+
 	// maxAllowedSize is a maximum allowed size of feature vector.
 	//
 	// NOTE: Within the protocol, the maximum allowed message size is 65535
@@ -41,8 +91,16 @@ const (
 // this mapping. Local features are those which are only sent to the peer and
 // not advertised to the entire network. A full description of these feature
 // bits is provided in the BOLT-09 specification.
+//
+// NOTE: The TLV onion payload, static remote key and anchor outputs bits are
+// deliberately absent, as we don't yet implement those features, and must
+// therefore continue to reject peers that require them.
 var LocalFeatures = map[FeatureBit]string{
-	InitialRoutingSync: "initial-routing-sync",
+	InitialRoutingSync:     "initial-routing-sync",
+	QuiesceRequired:        "quiesce",
+	QuiesceOptional:        "quiesce",
+	ProvideStorageRequired: "provide-storage",
+	ProvideStorageOptional: "provide-storage",
 }
 
 // GlobalFeatures is a mapping of known global feature bits to a descriptive
@@ -134,12 +192,12 @@ func (fv *RawFeatureVector) Encode(w io.Writer) error {
 // bytes. Since the bit vector length is variable, the first two bytes of the
 // serialization represent the length.
 func (fv *RawFeatureVector) Decode(r io.Reader) error {
-	// Read the length of the feature vector.
-	var l [2]byte
-	if _, err := io.ReadFull(r, l[:]); err != nil {
+	// Read the length of the feature vector, ensuring it's not large
+	// enough for setting its bits to be costly.
+	length, err := readLength(r, "feature vector", MaxFeatureVectorLength)
+	if err != nil {
 		return err
 	}
-	length := binary.BigEndian.Uint16(l[:])
 
 	// Read the feature vector data.
 	data := make([]byte, length)
@@ -150,7 +208,7 @@ func (fv *RawFeatureVector) Decode(r io.Reader) error {
 	// Set feature bits from parsed data.
 	bitsNumber := len(data) * 8
 	for i := 0; i < bitsNumber; i++ {
-		byteIndex := uint16(i / 8)
+		byteIndex := i / 8
 		bitIndex := uint(i % 8)
 		if (data[length-byteIndex-1]>>bitIndex)&1 == 1 {
 			fv.Set(FeatureBit(i))
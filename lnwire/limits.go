@@ -0,0 +1,96 @@
+package lnwire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// MaxOpaqueReasonLength is the maximum length of the encrypted reason
+	// of an UpdateFailHTLC message. Our own failures are 292 bytes long,
+	// which leaves ample room for other implementations padding their
+	// failures further.
+	MaxOpaqueReasonLength = 1024
+
+	// MaxHtlcSigs is the maximum number of HTLC signatures a CommitSig
+	// message may carry. A signature is carried for each HTLC output on
+	// the commitment, so it matches lnwallet.MaxHTLCNumber: at most 483
+	// HTLCs offered by each party, or 966 in total.
+	MaxHtlcSigs = 966
+
+	// MaxFeatureVectorLength is the maximum length of an encoded feature
+	// vector, which allows for 2048 feature bits.
+	MaxFeatureVectorLength = 256
+)
+
+// ErrFieldTooLarge is returned when the length of a variable-size field
+// exceeds its maximum while the field is being decoded. The length is checked
+// before any memory is allocated for the field, so that a peer can't cause us
+// to allocate more memory than a well-formed message requires.
+type ErrFieldTooLarge struct {
+	// MsgType is the type of the message the field belongs to. It's only
+	// set when the field is decoded as part of a message by ReadMessage.
+	MsgType MessageType
+
+	// ChanID is the channel the message is destined for, if it's a
+	// channel update message.
+	ChanID *ChannelID
+
+	// Field is the name of the field.
+	Field string
+
+	// Length is the length the field was encoded with.
+	Length int
+
+	// MaxLength is the maximum length of the field.
+	MaxLength int
+}
+
+// Error returns a human readable string describing the error.
+//
+// This is part of the error interface.
+func (e *ErrFieldTooLarge) Error() string {
+	if e.MsgType != 0 {
+		return fmt.Sprintf("%v of %v message is too large: length "+
+			"is %v, maximum is %v", e.Field, e.MsgType, e.Length,
+			e.MaxLength)
+	}
+
+	return fmt.Sprintf("%v is too large: length is %v, maximum is %v",
+		e.Field, e.Length, e.MaxLength)
+}
+
+// readLength reads the 2-byte length prefix of a variable-size field, and
+// ensures it doesn't exceed the maximum length of the field.
+func readLength(r io.Reader, field string, maxLength int) (int, error) {
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return 0, err
+	}
+
+	length := int(binary.BigEndian.Uint16(l[:]))
+	if length > maxLength {
+		return 0, &ErrFieldTooLarge{
+			Field:     field,
+			Length:    length,
+			MaxLength: maxLength,
+		}
+	}
+
+	return length, nil
+}
+
+// updateTarget returns the channel a channel update message is destined for.
+// It's used to attribute a message which failed to decode to its channel, and
+// as such only covers the messages which carry variable-size fields.
+func updateTarget(msg Message) (ChannelID, bool) {
+	switch msg := msg.(type) {
+	case *UpdateFailHTLC:
+		return msg.ChanID, true
+	case *CommitSig:
+		return msg.ChanID, true
+	default:
+		return ChannelID{}, false
+	}
+}
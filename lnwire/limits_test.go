@@ -0,0 +1,157 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestReadMessageFieldTooLarge tests that messages whose variable-size fields
+// exceed their maximum length are rejected with an ErrFieldTooLarge, which
+// identifies the message, and the channel it's destined for if any.
+func TestReadMessageFieldTooLarge(t *testing.T) {
+	t.Parallel()
+
+	chanID := ChannelID{1, 2, 3}
+
+	// The commitment signature preceding the HTLC signatures must be
+	// valid, so we'll use one whose R and S values are both 1.
+	var commitSig [64]byte
+	commitSig[31] = 1
+	commitSig[63] = 1
+
+	// newMsg returns the raw encoding of a message of the passed type,
+	// beginning with the passed fields and followed by the length prefix
+	// of a variable-size field.
+	newMsg := func(msgType MessageType, length int,
+		fields ...[]byte) []byte {
+
+		var b bytes.Buffer
+		binary.Write(&b, binary.BigEndian, uint16(msgType))
+		for _, field := range fields {
+			b.Write(field)
+		}
+		binary.Write(&b, binary.BigEndian, uint16(length))
+
+		return b.Bytes()
+	}
+
+	tests := []struct {
+		name      string
+		msg       []byte
+		msgType   MessageType
+		maxLength int
+		chanID    *ChannelID
+	}{
+		{
+			name: "failure reason",
+			msg: newMsg(
+				MsgUpdateFailHTLC, MaxOpaqueReasonLength+1,
+				chanID[:], make([]byte, 8),
+			),
+			msgType:   MsgUpdateFailHTLC,
+			maxLength: MaxOpaqueReasonLength,
+			chanID:    &chanID,
+		},
+		{
+			name: "htlc signatures",
+			msg: newMsg(
+				MsgCommitSig, MaxHtlcSigs+1, chanID[:],
+				commitSig[:],
+			),
+			msgType:   MsgCommitSig,
+			maxLength: MaxHtlcSigs,
+			chanID:    &chanID,
+		},
+		{
+			name:      "feature vector",
+			msg:       newMsg(MsgInit, MaxFeatureVectorLength+1),
+			msgType:   MsgInit,
+			maxLength: MaxFeatureVectorLength,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := ReadMessage(bytes.NewReader(test.msg), 0)
+		tooLarge, ok := err.(*ErrFieldTooLarge)
+		if !ok {
+			t.Fatalf("%v: expected ErrFieldTooLarge, got %v",
+				test.name, err)
+		}
+
+		if tooLarge.MsgType != test.msgType {
+			t.Fatalf("%v: expected message type %v, got %v",
+				test.name, test.msgType, tooLarge.MsgType)
+		}
+		if tooLarge.MaxLength != test.maxLength ||
+			tooLarge.Length != test.maxLength+1 {
+
+			t.Fatalf("%v: expected length %v and maximum %v, got "+
+				"%v and %v", test.name, test.maxLength+1,
+				test.maxLength, tooLarge.Length,
+				tooLarge.MaxLength)
+		}
+
+		switch {
+		case test.chanID == nil && tooLarge.ChanID != nil:
+			t.Fatalf("%v: expected no channel, got %v", test.name,
+				tooLarge.ChanID)
+
+		case test.chanID != nil && (tooLarge.ChanID == nil ||
+			*tooLarge.ChanID != *test.chanID):
+
+			t.Fatalf("%v: expected channel %v, got %v", test.name,
+				test.chanID, tooLarge.ChanID)
+		}
+	}
+}
+
+// TestCommitSigMaxHtlcSigs tests that a CommitSig carrying a signature for
+// each of the maximum number of HTLCs on a commitment survives a round trip,
+// while one carrying a further signature is rejected.
+func TestCommitSigMaxHtlcSigs(t *testing.T) {
+	t.Parallel()
+
+	newCommitSig := func(numSigs int) *CommitSig {
+		htlcSigs := make([]*btcec.Signature, numSigs)
+		for i := range htlcSigs {
+			htlcSigs[i] = testSig
+		}
+
+		return &CommitSig{
+			ChanID:    ChannelID{1, 2, 3},
+			CommitSig: testSig,
+			HtlcSigs:  htlcSigs,
+		}
+	}
+
+	var b bytes.Buffer
+	_, err := WriteMessage(&b, newCommitSig(MaxHtlcSigs), 0)
+	if err != nil {
+		t.Fatalf("unable to encode commit sig: %v", err)
+	}
+	msg, err := ReadMessage(&b, 0)
+	if err != nil {
+		t.Fatalf("unable to decode commit sig: %v", err)
+	}
+	commitSig, ok := msg.(*CommitSig)
+	if !ok {
+		t.Fatalf("expected CommitSig, got %T", msg)
+	}
+	if len(commitSig.HtlcSigs) != MaxHtlcSigs {
+		t.Fatalf("expected %v htlc signatures, got %v", MaxHtlcSigs,
+			len(commitSig.HtlcSigs))
+	}
+
+	b.Reset()
+	_, err = WriteMessage(&b, newCommitSig(MaxHtlcSigs+1), 0)
+	if err != nil {
+		t.Fatalf("unable to encode commit sig: %v", err)
+	}
+	_, err = ReadMessage(&b, 0)
+	if _, ok := err.(*ErrFieldTooLarge); !ok {
+		t.Fatalf("expected ErrFieldTooLarge, got %v", err)
+	}
+}
@@ -190,6 +190,21 @@ func writeElement(w io.Writer, element interface{}) error {
 			return err
 		}
 
+		if _, err := w.Write(e[:]); err != nil {
+			return err
+		}
+	case PeerStorageBlob:
+		if len(e) > MaxPeerStorageBlobSize {
+			return fmt.Errorf("peer storage blob of size %v exceeds "+
+				"max size of %v", len(e), MaxPeerStorageBlobSize)
+		}
+
+		var l [2]byte
+		binary.BigEndian.PutUint16(l[:], uint16(len(e)))
+		if _, err := w.Write(l[:]); err != nil {
+			return err
+		}
+
 		if _, err := w.Write(e[:]); err != nil {
 			return err
 		}
@@ -470,16 +485,15 @@ func readElement(r io.Reader, element interface{}) error {
 		*e = f
 
 	case *[]*btcec.Signature:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+		numSigs, err := readLength(r, "htlc signatures", MaxHtlcSigs)
+		if err != nil {
 			return err
 		}
-		numSigs := binary.BigEndian.Uint16(l[:])
 
 		var sigs []*btcec.Signature
 		if numSigs > 0 {
 			sigs = make([]*btcec.Signature, numSigs)
-			for i := 0; i < int(numSigs); i++ {
+			for i := 0; i < numSigs; i++ {
 				if err := readElement(r, &sigs[i]); err != nil {
 					return err
 				}
@@ -498,11 +512,12 @@ func readElement(r io.Reader, element interface{}) error {
 			return err
 		}
 	case *OpaqueReason:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+		reasonLen, err := readLength(
+			r, "failure reason", MaxOpaqueReasonLength,
+		)
+		if err != nil {
 			return err
 		}
-		reasonLen := binary.BigEndian.Uint16(l[:])
 
 		*e = OpaqueReason(make([]byte, reasonLen))
 		if _, err := io.ReadFull(r, *e); err != nil {
@@ -541,6 +556,18 @@ func readElement(r io.Reader, element interface{}) error {
 		if _, err := io.ReadFull(r, *e); err != nil {
 			return err
 		}
+	case *PeerStorageBlob:
+		blobLen, err := readLength(
+			r, "peer storage blob", MaxPeerStorageBlobSize,
+		)
+		if err != nil {
+			return err
+		}
+
+		*e = PeerStorageBlob(make([]byte, blobLen))
+		if _, err := io.ReadFull(r, *e); err != nil {
+			return err
+		}
 	case []byte:
 		if _, err := io.ReadFull(r, e); err != nil {
 			return err
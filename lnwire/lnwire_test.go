@@ -54,7 +54,7 @@ func randPubKey() (*btcec.PublicKey, error) {
 
 func randRawFeatureVector(r *rand.Rand) *RawFeatureVector {
 	featureVec := NewRawFeatureVector()
-	for i := 0; i < 10000; i++ {
+	for i := 0; i < MaxFeatureVectorLength*8; i++ {
 		if r.Int31n(2) == 0 {
 			featureVec.Set(FeatureBit(i))
 		}
@@ -98,9 +98,11 @@ func TestLightningWireProtocol(t *testing.T) {
 	// violates our model of the system.
 	mainScenario := func(msg Message) bool {
 		// Give a new message, we'll serialize the message into a new
-		// bytes buffer.
+		// bytes buffer. We use the protocol version used for messages
+		// sent over the wire, so any extension records are included.
+		pver := ProtocolVersionExtensions
 		var b bytes.Buffer
-		if _, err := WriteMessage(&b, msg, 0); err != nil {
+		if _, err := WriteMessage(&b, msg, pver); err != nil {
 			t.Fatalf("unable to write msg: %v", err)
 			return false
 		}
@@ -109,15 +111,15 @@ func TestLightningWireProtocol(t *testing.T) {
 		// the 2 bytes for the message type) is _below_ the specified
 		// max payload size for this message.
 		payloadLen := uint32(b.Len()) - 2
-		if payloadLen > msg.MaxPayloadLength(0) {
+		if payloadLen > msg.MaxPayloadLength(pver) {
 			t.Fatalf("msg payload constraint violated: %v > %v",
-				payloadLen, msg.MaxPayloadLength(0))
+				payloadLen, msg.MaxPayloadLength(pver))
 			return false
 		}
 
 		// Finally, we'll deserialize the message from the written
 		// buffer, and finally assert that the messages are equal.
-		newMsg, err := ReadMessage(&b, 0)
+		newMsg, err := ReadMessage(&b, pver)
 		if err != nil {
 			t.Fatalf("unable to read msg: %v", err)
 			return false
@@ -325,8 +327,11 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			// Only create the slice if there will be any signatures
 			// in it to prevent false positive test failures due to
-			// an empty slice versus a nil slice.
-			numSigs := uint16(r.Int31n(1020))
+			// an empty slice versus a nil slice. The range spans up
+			// to the 966 HTLCs (483 per side) a commitment may hold,
+			// independently of MaxHtlcSigs, so a cap set too low
+			// fails here.
+			numSigs := uint16(r.Int31n(967))
 			if numSigs > 0 {
 				req.HtlcSigs = make([]*btcec.Signature, numSigs)
 			}
@@ -514,6 +519,24 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgPeerStorage,
+			scenario: func(m PeerStorage) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgPeerStorageRetrieval,
+			scenario: func(m PeerStorageRetrieval) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgStfu,
+			scenario: func(m Stfu) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgOpenChannel,
 			scenario: func(m OpenChannel) bool {
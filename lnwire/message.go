@@ -26,7 +26,10 @@ type MessageType uint16
 // The currently defined message types within this current version of the
 // Lightning protocol.
 const (
-	MsgInit                    MessageType = 16
+	MsgStfu                    MessageType = 2
+	MsgPeerStorage                         = 7
+	MsgPeerStorageRetrieval                = 9
+	MsgInit                                = 16
 	MsgError                               = 17
 	MsgPing                                = 18
 	MsgPong                                = 19
@@ -100,6 +103,12 @@ func (t MessageType) String() string {
 		return "Pong"
 	case MsgUpdateFee:
 		return "UpdateFee"
+	case MsgPeerStorage:
+		return "PeerStorage"
+	case MsgPeerStorageRetrieval:
+		return "PeerStorageRetrieval"
+	case MsgStfu:
+		return "Stfu"
 	default:
 		return "<unknown>"
 	}
@@ -111,6 +120,11 @@ type UnknownMessage struct {
 	messageType MessageType
 }
 
+// Type returns the unknown type of the message.
+func (u *UnknownMessage) Type() MessageType {
+	return u.messageType
+}
+
 // Error returns a human readable string describing the error.
 //
 // This is part of the error interface.
@@ -191,6 +205,12 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &AnnounceSignatures{}
 	case MsgPong:
 		msg = &Pong{}
+	case MsgPeerStorage:
+		msg = &PeerStorage{}
+	case MsgPeerStorageRetrieval:
+		msg = &PeerStorageRetrieval{}
+	case MsgStfu:
+		msg = &Stfu{}
 	default:
 		return nil, fmt.Errorf("unknown message type [%d]", msgType)
 	}
@@ -264,6 +284,16 @@ func ReadMessage(r io.Reader, pver uint32) (Message, error) {
 		return nil, err
 	}
 	if err := msg.Decode(r, pver); err != nil {
+		// If a variable-size field is too large, then we'll note the
+		// message it belongs to, and the channel it's destined for,
+		// so that the message can be rejected in a targeted manner.
+		if tooLarge, ok := err.(*ErrFieldTooLarge); ok {
+			tooLarge.MsgType = msgType
+			if chanID, ok := updateTarget(msg); ok {
+				tooLarge.ChanID = &chanID
+			}
+		}
+
 		return nil, err
 	}
 
@@ -995,14 +995,14 @@ func (f *FailFinalIncorrectHtlcAmount) Encode(w io.Writer, pver uint32) error {
 func DecodeFailure(r io.Reader, pver uint32) (FailureMessage, error) {
 	// First, we'll parse out the encapsulated failure message itself. This
 	// is a 2 byte length followed by the payload itself.
-	var failureLength uint16
-	if err := readElement(r, &failureLength); err != nil {
+	failureLength, err := readLength(
+		r, "failure message", failureMessageLength,
+	)
+	if _, ok := err.(*ErrFieldTooLarge); ok {
+		return nil, err
+	} else if err != nil {
 		return nil, fmt.Errorf("unable to read error len: %v", err)
 	}
-	if failureLength > failureMessageLength {
-		return nil, fmt.Errorf("failure message is too "+
-			"long: %v", failureLength)
-	}
 	failureData := make([]byte, failureLength)
 	if _, err := io.ReadFull(r, failureData); err != nil {
 		return nil, fmt.Errorf("unable to full read payload of "+
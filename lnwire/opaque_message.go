@@ -0,0 +1,87 @@
+package lnwire
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+// OpaqueMessage is a message of a type that we don't understand. As all
+// channel-scoped messages begin with the ID of the channel they target, the
+// first 32 bytes of the payload are interpreted as a channel ID, allowing the
+// message to be delivered to the channel it may be destined for.
+type OpaqueMessage struct {
+	// ChanID is the channel ID the message may be destined for.
+	ChanID ChannelID
+
+	// Type is the unknown type of the message.
+	Type MessageType
+
+	// Payload is the remainder of the message following the channel ID.
+	Payload []byte
+}
+
+// NewOpaqueMessage returns a new OpaqueMessage of the passed type.
+func NewOpaqueMessage(msgType MessageType) *OpaqueMessage {
+	return &OpaqueMessage{
+		Type: msgType,
+	}
+}
+
+// A compile time check to ensure OpaqueMessage implements the lnwire.Message
+// interface.
+var _ Message = (*OpaqueMessage)(nil)
+
+// IsOdd returns true if the type of the message is odd. As per the "it's ok
+// to be odd" rule of BOLT #1, unknown messages of an odd type may be ignored,
+// while unknown messages of an even type must not be.
+func (o *OpaqueMessage) IsOdd() bool {
+	return o.Type%2 == 1
+}
+
+// Decode deserializes a serialized OpaqueMessage stored in the passed
+// io.Reader observing the specified protocol version. The remainder of the
+// reader is consumed as the payload.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpaqueMessage) Decode(r io.Reader, pver uint32) error {
+	if err := readElements(r, &o.ChanID); err != nil {
+		return err
+	}
+
+	payload, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	o.Payload = payload
+
+	return nil
+}
+
+// Encode serializes the target OpaqueMessage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpaqueMessage) Encode(w io.Writer, pver uint32) error {
+	if err := writeElements(w, o.ChanID); err != nil {
+		return err
+	}
+
+	_, err := w.Write(o.Payload)
+	return err
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpaqueMessage) MsgType() MessageType {
+	return o.Type
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for an
+// OpaqueMessage complete message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpaqueMessage) MaxPayloadLength(uint32) uint32 {
+	return MaxMessagePayload
+}
@@ -0,0 +1,48 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// TestOpaqueMessageEncodeDecode tests that an OpaqueMessage retains the
+// channel ID and the remainder of the payload of a message of an unknown
+// type.
+func TestOpaqueMessageEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	msg := &OpaqueMessage{
+		ChanID:  ChannelID{1, 2, 3},
+		Type:    MessageType(32769),
+		Payload: []byte{4, 5, 6},
+	}
+	if !msg.IsOdd() {
+		t.Fatalf("message of type %v should be odd", msg.Type)
+	}
+
+	var b bytes.Buffer
+	if err := msg.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode message: %v", err)
+	}
+
+	decoded := NewOpaqueMessage(msg.Type)
+	if err := decoded.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode message: %v", err)
+	}
+	if !reflect.DeepEqual(msg, decoded) {
+		t.Fatalf("decoded message doesn't match: expected %v, got %v",
+			msg, decoded)
+	}
+
+	// A message of an unknown even type can't be decoded into a concrete
+	// message, but should be identified as even.
+	_, err := ReadMessage(bytes.NewReader([]byte{0x80, 0x00}), 0)
+	unknown, ok := err.(*UnknownMessage)
+	if !ok {
+		t.Fatalf("expected unknown message error, got %v", err)
+	}
+	if NewOpaqueMessage(unknown.Type()).IsOdd() {
+		t.Fatalf("message of type %v should be even", unknown.Type())
+	}
+}
@@ -0,0 +1,124 @@
+package lnwire
+
+import "io"
+
+// MaxPeerStorageBlobSize is the maximum size of a blob that can be carried
+// within a PeerStorage or PeerStorageRetrieval message. It accounts for the
+// 2-byte message type and the 2-byte length prefix of the blob itself.
+const MaxPeerStorageBlobSize = 65531
+
+// PeerStorageBlob is an opaque blob of data that a node stores on behalf of
+// one of its peers.
+type PeerStorageBlob []byte
+
+// PeerStorage is sent by a node to its peer in order to request that the peer
+// store the enclosed blob on its behalf. The blob should be encrypted by the
+// sender, and replaces any blob the peer previously stored for the sender.
+type PeerStorage struct {
+	// Blob is the opaque blob that the peer should store.
+	Blob PeerStorageBlob
+}
+
+// NewPeerStorage returns a new PeerStorage message carrying the passed blob.
+func NewPeerStorage(blob []byte) *PeerStorage {
+	return &PeerStorage{
+		Blob: blob,
+	}
+}
+
+// A compile time check to ensure PeerStorage implements the lnwire.Message
+// interface.
+var _ Message = (*PeerStorage)(nil)
+
+// Decode deserializes a serialized PeerStorage message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&p.Blob,
+	)
+}
+
+// Encode serializes the target PeerStorage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		p.Blob,
+	)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) MsgType() MessageType {
+	return MsgPeerStorage
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a PeerStorage
+// complete message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) MaxPayloadLength(uint32) uint32 {
+	return MaxPeerStorageBlobSize + 2
+}
+
+// PeerStorageRetrieval is sent by a node to its peer upon reconnection in
+// order to return the latest blob the peer requested that the node store on
+// its behalf.
+type PeerStorageRetrieval struct {
+	// Blob is the opaque blob that was stored on behalf of the peer.
+	Blob PeerStorageBlob
+}
+
+// NewPeerStorageRetrieval returns a new PeerStorageRetrieval message carrying
+// the passed blob.
+func NewPeerStorageRetrieval(blob []byte) *PeerStorageRetrieval {
+	return &PeerStorageRetrieval{
+		Blob: blob,
+	}
+}
+
+// A compile time check to ensure PeerStorageRetrieval implements the
+// lnwire.Message interface.
+var _ Message = (*PeerStorageRetrieval)(nil)
+
+// Decode deserializes a serialized PeerStorageRetrieval message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&p.Blob,
+	)
+}
+
+// Encode serializes the target PeerStorageRetrieval into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		p.Blob,
+	)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) MsgType() MessageType {
+	return MsgPeerStorageRetrieval
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// PeerStorageRetrieval complete message observing the specified protocol
+// version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) MaxPayloadLength(uint32) uint32 {
+	return MaxPeerStorageBlobSize + 2
+}
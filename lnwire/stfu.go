@@ -0,0 +1,75 @@
+package lnwire
+
+import "io"
+
+// Stfu is sent by a node to request that the channel be brought into a
+// quiescent state, or to acknowledge such a request. Once a node has sent
+// Stfu it may no longer initiate any updates to the channel. The channel is
+// quiescent once both nodes have sent Stfu, at which point operations that
+// require a static channel state, such as splicing, may be carried out. It
+// may only be sent once both nodes have signalled support for the quiesce
+// feature bits, as its message type is even.
+type Stfu struct {
+	// ChanID is the channel that is to be brought into a quiescent state.
+	ChanID ChannelID
+
+	// Initiator is true if the sender is requesting quiescence, and false
+	// if it's acknowledging the request of the remote party.
+	Initiator bool
+}
+
+// NewStfu creates a new Stfu message for the target channel.
+func NewStfu(chanID ChannelID, initiator bool) *Stfu {
+	return &Stfu{
+		ChanID:    chanID,
+		Initiator: initiator,
+	}
+}
+
+// A compile time check to ensure Stfu implements the lnwire.Message
+// interface.
+var _ Message = (*Stfu)(nil)
+
+// Decode deserializes a serialized Stfu message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) Decode(r io.Reader, pver uint32) error {
+	var initiator uint8
+	if err := readElements(r, &s.ChanID, &initiator); err != nil {
+		return err
+	}
+	s.Initiator = initiator != 0
+
+	return nil
+}
+
+// Encode serializes the target Stfu into the passed io.Writer observing the
+// protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) Encode(w io.Writer, pver uint32) error {
+	var initiator uint8
+	if s.Initiator {
+		initiator = 1
+	}
+
+	return writeElements(w, s.ChanID, initiator)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) MsgType() MessageType {
+	return MsgStfu
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a Stfu
+// complete message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) MaxPayloadLength(uint32) uint32 {
+	// 32 + 1
+	return 33
+}
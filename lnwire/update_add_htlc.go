@@ -1,6 +1,9 @@
 package lnwire
 
-import "io"
+import (
+	"fmt"
+	"io"
+)
 
 // OnionPacketSize is the size of the serialized Sphinx onion packet included
 // in each UpdateAddHTLC message. The breakdown of the onion packet is as
@@ -8,6 +11,21 @@ import "io"
 // of per-hop data, and a 32-byte HMAC over the entire packet.
 const OnionPacketSize = 1366
 
+// ExperimentalEndorsementType is the type of the extension record used to
+// signal that an HTLC has been endorsed by the sender, as part of the
+// experimental jamming mitigation proposal. The record is odd, so it's safely
+// ignored by nodes unaware of it.
+const ExperimentalEndorsementType uint64 = 106823
+
+// experimentalEndorsedValue is the value of the experimental endorsement
+// record which signals that the HTLC has been endorsed.
+const experimentalEndorsedValue = 1
+
+// maxUpdateAddExtensionLength is the maximum length of the extension records
+// we'll write for an UpdateAddHTLC: a 5-byte type, a 1-byte length and a
+// 1-byte value for the endorsement record.
+const maxUpdateAddExtensionLength = 7
+
 // UpdateAddHTLC is the message sent by Alice to Bob when she wishes to add an
 // HTLC to his remote commitment transaction. In addition to information
 // detailing the value, the ID, expiry, and the onion blob is also included
@@ -50,6 +68,13 @@ type UpdateAddHTLC struct {
 	// should strip off a layer of encryption, exposing the next hop to be
 	// used in the subsequent UpdateAddHTLC message.
 	OnionBlob [OnionPacketSize]byte
+
+	// Endorsed is true if the sender has endorsed this HTLC, as part of
+	// the experimental jamming mitigation proposal. It's only encoded
+	// within the extension records of the message when the extension
+	// protocol version is used, so it isn't persisted along with the
+	// HTLC.
+	Endorsed bool
 }
 
 // NewUpdateAddHTLC returns a new empty UpdateAddHTLC message.
@@ -66,7 +91,7 @@ var _ Message = (*UpdateAddHTLC)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *UpdateAddHTLC) Decode(r io.Reader, pver uint32) error {
-	return readElements(r,
+	err := readElements(r,
 		&c.ChanID,
 		&c.ID,
 		&c.Amount,
@@ -74,6 +99,29 @@ func (c *UpdateAddHTLC) Decode(r io.Reader, pver uint32) error {
 		&c.Expiry,
 		c.OnionBlob[:],
 	)
+	if err != nil {
+		return err
+	}
+
+	if pver < ProtocolVersionExtensions {
+		return nil
+	}
+
+	records, err := readExtension(r, map[uint64]struct{}{
+		ExperimentalEndorsementType: {},
+	})
+	if err != nil {
+		return err
+	}
+	if endorsement, ok := records[ExperimentalEndorsementType]; ok {
+		if len(endorsement) != 1 {
+			return fmt.Errorf("invalid endorsement record length: "+
+				"%v", len(endorsement))
+		}
+		c.Endorsed = endorsement[0] == experimentalEndorsedValue
+	}
+
+	return nil
 }
 
 // Encode serializes the target UpdateAddHTLC into the passed io.Writer observing
@@ -81,7 +129,7 @@ func (c *UpdateAddHTLC) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *UpdateAddHTLC) Encode(w io.Writer, pver uint32) error {
-	return writeElements(w,
+	err := writeElements(w,
 		c.ChanID,
 		c.ID,
 		c.Amount,
@@ -89,6 +137,20 @@ func (c *UpdateAddHTLC) Encode(w io.Writer, pver uint32) error {
 		c.Expiry,
 		c.OnionBlob[:],
 	)
+	if err != nil {
+		return err
+	}
+
+	if pver < ProtocolVersionExtensions || !c.Endorsed {
+		return nil
+	}
+
+	return writeExtension(w, []extensionRecord{
+		{
+			recordType: ExperimentalEndorsementType,
+			value:      []byte{experimentalEndorsedValue},
+		},
+	})
 }
 
 // MsgType returns the integer uniquely identifying this message type on the
@@ -103,7 +165,13 @@ func (c *UpdateAddHTLC) MsgType() MessageType {
 // complete message observing the specified protocol version.
 //
 // This is part of the lnwire.Message interface.
-func (c *UpdateAddHTLC) MaxPayloadLength(uint32) uint32 {
+func (c *UpdateAddHTLC) MaxPayloadLength(pver uint32) uint32 {
 	// 1450
-	return 32 + 8 + 4 + 8 + 32 + 1366
+	length := uint32(32 + 8 + 4 + 8 + 32 + 1366)
+
+	if pver >= ProtocolVersionExtensions {
+		length += maxUpdateAddExtensionLength
+	}
+
+	return length
 }
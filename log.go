@@ -14,6 +14,7 @@ import (
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/htlcswitch"
@@ -23,12 +24,14 @@ import (
 )
 
 // logWriter implements an io.Writer that outputs to both standard output and
-// the write-end pipe of an initialized log rotator.
+// the write-end pipe of an initialized log rotator. The most recent entries
+// are also retained in memory, for inclusion within debug packages.
 type logWriter struct{}
 
 func (logWriter) Write(p []byte) (n int, err error) {
 	os.Stdout.Write(p)
 	logRotatorPipe.Write(p)
+	recentLogs.Write(p)
 	return len(p), nil
 }
 
@@ -72,6 +75,7 @@ var (
 	btcnLog = backendLog.Logger("BTCN")
 	atplLog = backendLog.Logger("ATPL")
 	cnctLog = backendLog.Logger("CNCT")
+	chnfLog = backendLog.Logger("CHNF")
 )
 
 // Initialize package-global logger variables.
@@ -86,6 +90,7 @@ func init() {
 	neutrino.UseLogger(btcnLog)
 	autopilot.UseLogger(atplLog)
 	contractcourt.UseLogger(cnctLog)
+	channelnotifier.UseLogger(chnfLog)
 }
 
 // subsystemLoggers maps each subsystem identifier to its associated logger.
@@ -107,6 +112,7 @@ var subsystemLoggers = map[string]btclog.Logger{
 	"BTCN": btcnLog,
 	"ATPL": atplLog,
 	"CNCT": cnctLog,
+	"CHNF": chnfLog,
 }
 
 // initLogRotator initializes the logging rotator to write logs to logFile and
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// tapBufferSize is the number of messages buffered for each subscriber of the
+// message tap. Once a subscriber's buffer is full, further messages are
+// dropped until it catches up, so a slow subscriber never stalls a peer.
+const tapBufferSize = 100
+
+// tappedMessage is a decoded wire message sent to, or received from, a peer,
+// as delivered to the subscribers of the message tap.
+type tappedMessage struct {
+	// peer is the public key of the peer the message was exchanged with.
+	peer [33]byte
+
+	// inbound is true if the message was received from the peer.
+	inbound bool
+
+	// msgType is the type of the message.
+	msgType lnwire.MessageType
+
+	// summary is a brief summary of the message's contents.
+	summary string
+
+	// dump is a full dump of the message, with any preimages redacted.
+	dump string
+
+	// timestamp is the time at which the message was sent or received.
+	timestamp time.Time
+}
+
+// tapSubscription is a subscription to the messages exchanged with a single
+// peer.
+type tapSubscription struct {
+	id   uint64
+	peer [33]byte
+
+	// Messages is the channel over which the tapped messages are
+	// delivered.
+	Messages chan *tappedMessage
+
+	tap *messageTap
+}
+
+// Cancel cancels the subscription, after which no further messages will be
+// delivered.
+func (s *tapSubscription) Cancel() {
+	s.tap.mtx.Lock()
+	delete(s.tap.subscriptions, s.id)
+	s.tap.mtx.Unlock()
+}
+
+// messageTap is a debugging aid, which streams the decoded wire messages
+// exchanged with a particular peer to its subscribers, allowing protocol
+// interop problems to be diagnosed without attaching a debugger. As the
+// messages are only decoded for delivery if a subscription for the peer
+// exists, an idle tap adds little overhead.
+type messageTap struct {
+	subscriptions map[uint64]*tapSubscription
+	nextID        uint64
+	mtx           sync.Mutex
+}
+
+// newMessageTap creates a new message tap without any subscribers.
+func newMessageTap() *messageTap {
+	return &messageTap{
+		subscriptions: make(map[uint64]*tapSubscription),
+	}
+}
+
+// Subscribe returns a new subscription to the messages exchanged with the
+// passed peer.
+func (t *messageTap) Subscribe(peer [33]byte) *tapSubscription {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	sub := &tapSubscription{
+		id:       t.nextID,
+		peer:     peer,
+		Messages: make(chan *tappedMessage, tapBufferSize),
+		tap:      t,
+	}
+	t.subscriptions[sub.id] = sub
+	t.nextID++
+
+	return sub
+}
+
+// Tap delivers the passed message, sent to or received from the passed peer,
+// to each subscriber of the peer. This never blocks: if a subscriber's buffer
+// is full, the message is dropped for that subscriber.
+func (t *messageTap) Tap(peer [33]byte, msg lnwire.Message, inbound bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	var tapped *tappedMessage
+	for _, sub := range t.subscriptions {
+		if sub.peer != peer {
+			continue
+		}
+
+		// Only decode the message once we know there's a subscriber
+		// interested in it.
+		if tapped == nil {
+			redacted := redactMessage(msg)
+			tapped = &tappedMessage{
+				peer:      peer,
+				inbound:   inbound,
+				msgType:   msg.MsgType(),
+				summary:   messageSummary(redacted),
+				dump:      spew.Sdump(redacted),
+				timestamp: time.Now(),
+			}
+		}
+
+		select {
+		case sub.Messages <- tapped:
+		default:
+			peerLog.Debugf("Message tap subscriber for peer %x is "+
+				"lagging, dropping %v", peer[:], tapped.msgType)
+		}
+	}
+}
+
+// redactMessage returns a copy of the passed message with any payment
+// preimages removed, so that they aren't exposed to the tap's subscribers.
+// Messages which don't carry secrets are returned unmodified.
+func redactMessage(msg lnwire.Message) lnwire.Message {
+	switch m := msg.(type) {
+	case *lnwire.UpdateFufillHTLC:
+		redacted := *m
+		redacted.PaymentPreimage = [32]byte{}
+		return &redacted
+
+	default:
+		return msg
+	}
+}
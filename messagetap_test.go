@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestMessageTap ensures that the message tap only delivers the messages of
+// the subscribed peer, that it redacts payment preimages, and that it never
+// blocks on a lagging subscriber.
+func TestMessageTap(t *testing.T) {
+	t.Parallel()
+
+	var peer, otherPeer [33]byte
+	peer[0] = 1
+	otherPeer[0] = 2
+
+	tap := newMessageTap()
+	sub := tap.Subscribe(peer)
+
+	var preimage [32]byte
+	copy(preimage[:], bytes.Repeat([]byte{0xab}, 32))
+	fulfill := lnwire.NewUpdateFufillHTLC(lnwire.ChannelID{}, 5, preimage)
+
+	tap.Tap(otherPeer, fulfill, true)
+	tap.Tap(peer, fulfill, true)
+
+	var msg *tappedMessage
+	select {
+	case msg = <-sub.Messages:
+	default:
+		t.Fatalf("expected tapped message")
+	}
+	if msg.peer != peer || !msg.inbound ||
+		msg.msgType != lnwire.MsgUpdateFufillHTLC {
+
+		t.Fatalf("unexpected tapped message: %v", msg)
+	}
+
+	// Neither the summary nor the dump may contain the preimage, and the
+	// original message must not have been altered.
+	preimageHex := fmt.Sprintf("%x", preimage[:])
+	if bytes.Contains([]byte(msg.summary), []byte(preimageHex)) {
+		t.Fatalf("preimage not redacted from summary: %v", msg.summary)
+	}
+	if bytes.Contains([]byte(msg.dump), []byte("ab ab ab ab")) {
+		t.Fatalf("preimage not redacted from dump: %v", msg.dump)
+	}
+	if fulfill.PaymentPreimage != preimage {
+		t.Fatalf("original message was modified")
+	}
+
+	// The message of the other peer shouldn't have been delivered.
+	select {
+	case msg := <-sub.Messages:
+		t.Fatalf("unexpected tapped message: %v", msg)
+	default:
+	}
+
+	// Filling the subscriber's buffer shouldn't block the tap.
+	for i := 0; i < tapBufferSize+10; i++ {
+		tap.Tap(peer, fulfill, false)
+	}
+	if len(sub.Messages) != tapBufferSize {
+		t.Fatalf("expected %v buffered messages, got %v",
+			tapBufferSize, len(sub.Messages))
+	}
+
+	// Once cancelled, no further messages should be delivered.
+	sub.Cancel()
+	for len(sub.Messages) > 0 {
+		<-sub.Messages
+	}
+	tap.Tap(peer, fulfill, true)
+	if len(sub.Messages) != 0 {
+		t.Fatalf("message delivered after subscription was cancelled")
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// switchMetricsSource is the subset of the htlc switch's methods the metrics
+// exporter uses to gather its metrics.
+type switchMetricsSource interface {
+	// Links returns all of the channel links managed by the switch.
+	Links() ([]htlcswitch.ChannelLink, error)
+
+	// Metrics returns a snapshot of the counters describing the HTLCs
+	// handled by the switch and its links.
+	Metrics() *htlcswitch.SwitchMetrics
+}
+
+// metricsExporter is an optional HTTP server which exports the metrics of the
+// htlc switch and its links at /metrics, in the text format scraped by
+// Prometheus. This allows the operators of routing nodes to monitor the
+// throughput of their node, along with the failures it returns, without
+// polling the RPC server.
+type metricsExporter struct {
+	listenAddr string
+	htlcSwitch switchMetricsSource
+
+	listener net.Listener
+	server   *http.Server
+
+	wg sync.WaitGroup
+}
+
+// newMetricsExporter creates a new metrics exporter, which will listen on the
+// passed address once started.
+func newMetricsExporter(listenAddr string,
+	htlcSwitch switchMetricsSource) *metricsExporter {
+
+	return &metricsExporter{
+		listenAddr: listenAddr,
+		htlcSwitch: htlcSwitch,
+	}
+}
+
+// Start begins serving the metrics over HTTP.
+func (e *metricsExporter) Start() error {
+	listener, err := net.Listen("tcp", e.listenAddr)
+	if err != nil {
+		return err
+	}
+	e.listener = listener
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.serveMetrics)
+	e.server = &http.Server{Handler: mux}
+
+	srvrLog.Infof("Metrics exporter listening on %v", listener.Addr())
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		e.server.Serve(listener)
+	}()
+
+	return nil
+}
+
+// Stop closes the HTTP server along with any active connections, and waits
+// for it to exit.
+func (e *metricsExporter) Stop() error {
+	err := e.server.Close()
+	e.wg.Wait()
+
+	return err
+}
+
+// serveMetrics answers a scrape of the metrics.
+func (e *metricsExporter) serveMetrics(w http.ResponseWriter,
+	r *http.Request) {
+
+	var b bytes.Buffer
+	if err := e.writeMetrics(&b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write(b.Bytes())
+}
+
+// writeMetricHeader writes the help text and type of the named metric.
+func writeMetricHeader(w io.Writer, name, help, metricType string) {
+	fmt.Fprintf(w, "# HELP %v %v\n# TYPE %v %v\n", name, help, name,
+		metricType)
+}
+
+// writeMetrics writes the current metrics of the switch and its links to w.
+func (e *metricsExporter) writeMetrics(w io.Writer) error {
+	links, err := e.htlcSwitch.Links()
+	if err != nil {
+		return err
+	}
+	metrics := e.htlcSwitch.Metrics()
+
+	writeMetricHeader(w, "lnd_switch_forwarded_htlcs_total",
+		"Number of HTLCs handed to an outgoing link.", "counter")
+	fmt.Fprintf(w, "lnd_switch_forwarded_htlcs_total %v\n",
+		metrics.ForwardedHTLCs)
+
+	// The failures are sorted by their code, such that their order is
+	// stable across scrapes.
+	codes := make([]lnwire.FailCode, 0, len(metrics.FailuresByCode))
+	for code := range metrics.FailuresByCode {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+
+	writeMetricHeader(w, "lnd_switch_htlc_failures_total",
+		"Number of HTLCs failed by this node, by failure code.",
+		"counter")
+	for _, code := range codes {
+		fmt.Fprintf(w, "lnd_switch_htlc_failures_total{code=%q} %v\n",
+			code.String(), metrics.FailuresByCode[code])
+	}
+
+	writeMetricHeader(w, "lnd_switch_settle_latency_seconds",
+		"Time taken for outgoing HTLCs to be settled.", "histogram")
+	for i, bound := range metrics.SettleLatencyBuckets {
+		fmt.Fprintf(w, "lnd_switch_settle_latency_seconds_bucket"+
+			"{le=\"%v\"} %v\n", bound.Seconds(),
+			metrics.SettleLatencyCounts[i])
+	}
+	fmt.Fprintf(w, "lnd_switch_settle_latency_seconds_bucket"+
+		"{le=\"+Inf\"} %v\n", metrics.SettledHTLCs)
+	fmt.Fprintf(w, "lnd_switch_settle_latency_seconds_sum %v\n",
+		metrics.SettleLatencySum.Seconds())
+	fmt.Fprintf(w, "lnd_switch_settle_latency_seconds_count %v\n",
+		metrics.SettledHTLCs)
+
+	// Next, we'll gather the gauges of each link. A link that shuts down
+	// while we're gathering them is simply omitted.
+	snapshots := make([]*htlcswitch.LinkSnapshot, 0, len(links))
+	for _, link := range links {
+		snapshot, err := link.LinkSnapshot()
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	linkGauges := []struct {
+		name  string
+		help  string
+		value func(*htlcswitch.LinkSnapshot) interface{}
+	}{
+		{
+			name: "lnd_link_bandwidth_msat",
+			help: "Amount that can currently flow through the " +
+				"link.",
+			value: func(s *htlcswitch.LinkSnapshot) interface{} {
+				return uint64(s.Bandwidth)
+			},
+		},
+		{
+			name: "lnd_link_mailbox_messages",
+			help: "Number of wire messages queued within the " +
+				"link's mailbox.",
+			value: func(s *htlcswitch.LinkSnapshot) interface{} {
+				return s.MailboxMessages
+			},
+		},
+		{
+			name: "lnd_link_mailbox_packets",
+			help: "Number of htlc packets queued within the " +
+				"link's mailbox.",
+			value: func(s *htlcswitch.LinkSnapshot) interface{} {
+				return s.MailboxPackets
+			},
+		},
+		{
+			name: "lnd_link_overflow_queue_length",
+			help: "Number of htlc packets held within the link's " +
+				"overflow queue.",
+			value: func(s *htlcswitch.LinkSnapshot) interface{} {
+				return s.OverflowQueueLen
+			},
+		},
+	}
+	for _, gauge := range linkGauges {
+		writeMetricHeader(w, gauge.name, gauge.help, "gauge")
+		for _, snapshot := range snapshots {
+			fmt.Fprintf(w, "%v{chan_point=\"%v\",chan_id=\"%v\"} "+
+				"%v\n", gauge.name, snapshot.ChannelPoint,
+				snapshot.ShortChanID.ToUint64(),
+				gauge.value(snapshot))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// mockSwitchMetricsSource is a switchMetricsSource which returns a static
+// set of metrics, and no links.
+type mockSwitchMetricsSource struct {
+	metrics *htlcswitch.SwitchMetrics
+}
+
+func (m *mockSwitchMetricsSource) Links() ([]htlcswitch.ChannelLink, error) {
+	return nil, nil
+}
+
+func (m *mockSwitchMetricsSource) Metrics() *htlcswitch.SwitchMetrics {
+	return m.metrics
+}
+
+// TestMetricsExporter tests that a scrape of the metrics exporter returns the
+// metrics of the switch in the Prometheus text format.
+func TestMetricsExporter(t *testing.T) {
+	t.Parallel()
+
+	source := &mockSwitchMetricsSource{
+		metrics: &htlcswitch.SwitchMetrics{
+			ForwardedHTLCs: 5,
+			FailuresByCode: map[lnwire.FailCode]uint64{
+				lnwire.CodeUnknownNextPeer: 2,
+			},
+			SettleLatencyBuckets: []time.Duration{
+				time.Second, 10 * time.Second,
+			},
+			SettleLatencyCounts: []uint64{1, 3},
+			SettledHTLCs:        4,
+			SettleLatencySum:    30 * time.Second,
+		},
+	}
+	exporter := newMetricsExporter("127.0.0.1:0", source)
+	if err := exporter.Start(); err != nil {
+		t.Fatalf("unable to start metrics exporter: %v", err)
+	}
+	defer exporter.Stop()
+
+	url := "http://" + exporter.listener.Addr().String() + "/metrics"
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("unable to scrape metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status: %v", resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read metrics: %v", err)
+	}
+
+	expectedLines := []string{
+		"# TYPE lnd_switch_forwarded_htlcs_total counter",
+		"lnd_switch_forwarded_htlcs_total 5",
+		`lnd_switch_htlc_failures_total{code="UnknownNextPeer"} 2`,
+		"# TYPE lnd_switch_settle_latency_seconds histogram",
+		`lnd_switch_settle_latency_seconds_bucket{le="1"} 1`,
+		`lnd_switch_settle_latency_seconds_bucket{le="10"} 3`,
+		`lnd_switch_settle_latency_seconds_bucket{le="+Inf"} 4`,
+		"lnd_switch_settle_latency_seconds_sum 30",
+		"lnd_switch_settle_latency_seconds_count 4",
+		"# TYPE lnd_link_bandwidth_msat gauge",
+	}
+	lines := strings.Split(string(body), "\n")
+	for _, expected := range expectedLines {
+		found := false
+		for _, line := range lines {
+			if line == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("metrics don't include %q:\n%v", expected,
+				string(body))
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// nodeProfiles maps the name of each of the available node profiles to a
+// function which overwrites the defaults within the passed config with those
+// of the profile. A profile only replaces the defaults of the options it
+// covers, so any option set explicitly within the config file or on the
+// command line takes precedence over the profile.
+var nodeProfiles = map[string]func(cfg *config){
+	// A routing node forwards a large number of HTLCs, so it favors
+	// throughput over latency by batching more updates into each
+	// commitment, and evicts its least profitable queued HTLCs once a
+	// channel is full.
+	"routing-node": func(cfg *config) {
+		cfg.LinkBatchSize = 30
+		cfg.LinkBatchTicker = 100 * time.Millisecond
+		cfg.MaxPendingChannels = 5
+		cfg.OverflowPolicy = "replace"
+		cfg.HTLCSweepBudget = 0.5
+		cfg.CommitSweepBudget = 0.5
+	},
+
+	// A merchant receives payments to its invoices, so it favors latency
+	// over throughput, only reveals the preimages of its invoices once
+	// it's safe to do so, and prices its invoices for a short while.
+	"merchant": func(cfg *config) {
+		cfg.LinkBatchSize = 5
+		cfg.LinkBatchTicker = 20 * time.Millisecond
+		cfg.SafeExitSettle = true
+		cfg.InvoiceExpiry = 15 * time.Minute
+	},
+
+	// A mobile node is online intermittently, with few channels to a
+	// handful of peers, so it commits less eagerly to save on bandwidth
+	// and battery, caps the value peers may have in flight with it, and
+	// avoids spending a large share of its small outputs on fees.
+	"mobile": func(cfg *config) {
+		cfg.LinkBatchSize = 5
+		cfg.LinkBatchTicker = 200 * time.Millisecond
+		cfg.LinkPendingCommitTicker = time.Second
+		cfg.MaxPendingChannels = 1
+		cfg.MaxValueInFlightPct = 50
+		cfg.HTLCSweepBudget = 0.2
+		cfg.CommitSweepBudget = 0.2
+		cfg.InvoiceExpiry = 24 * time.Hour
+	},
+}
+
+// nodeProfileNames returns the sorted names of all available node profiles.
+func nodeProfileNames() []string {
+	names := make([]string, 0, len(nodeProfiles))
+	for name := range nodeProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// applyNodeProfile overwrites the defaults within the passed config with those
+// of the named node profile. If the name is empty, then the config is left
+// untouched.
+func applyNodeProfile(cfg *config, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	applyProfile, ok := nodeProfiles[name]
+	if !ok {
+		return fmt.Errorf("unknown node profile %q, must be one of %v",
+			name, nodeProfileNames())
+	}
+	applyProfile(cfg)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestApplyNodeProfile tests that a node profile overwrites the defaults it
+// covers while leaving all others untouched, and that an unknown profile is
+// rejected.
+func TestApplyNodeProfile(t *testing.T) {
+	t.Parallel()
+
+	defaultCfg := config{
+		LinkBatchSize:      defaultLinkBatchSize,
+		LinkBatchTicker:    defaultLinkBatchTicker,
+		MaxPendingChannels: defaultMaxPendingChannels,
+		OverflowPolicy:     "queue",
+		HtlcExpiryGrace:    defaultHtlcExpiryGrace,
+	}
+
+	// Without a profile, the config should be left untouched.
+	cfg := defaultCfg
+	if err := applyNodeProfile(&cfg, ""); err != nil {
+		t.Fatalf("unable to apply empty profile: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, defaultCfg) {
+		t.Fatalf("config modified without a profile")
+	}
+
+	cfg = defaultCfg
+	if err := applyNodeProfile(&cfg, "routing-node"); err != nil {
+		t.Fatalf("unable to apply profile: %v", err)
+	}
+	if cfg.LinkBatchSize != 30 || cfg.OverflowPolicy != "replace" {
+		t.Fatalf("routing-node profile not applied: batch size %v, "+
+			"overflow policy %v", cfg.LinkBatchSize,
+			cfg.OverflowPolicy)
+	}
+	if cfg.HtlcExpiryGrace != defaultHtlcExpiryGrace {
+		t.Fatalf("option not covered by profile was modified")
+	}
+
+	cfg = defaultCfg
+	if err := applyNodeProfile(&cfg, "merchant"); err != nil {
+		t.Fatalf("unable to apply profile: %v", err)
+	}
+	if !cfg.SafeExitSettle || cfg.InvoiceExpiry != 15*time.Minute {
+		t.Fatalf("merchant profile not applied")
+	}
+
+	cfg = defaultCfg
+	if err := applyNodeProfile(&cfg, "mobile"); err != nil {
+		t.Fatalf("unable to apply profile: %v", err)
+	}
+	if cfg.MaxValueInFlightPct != 50 || cfg.MaxPendingChannels != 1 {
+		t.Fatalf("mobile profile not applied")
+	}
+
+	cfg = defaultCfg
+	if err := applyNodeProfile(&cfg, "unknown"); err == nil {
+		t.Fatalf("unknown profile should be rejected")
+	}
+	if !reflect.DeepEqual(cfg, defaultCfg) {
+		t.Fatalf("config modified by unknown profile")
+	}
+}
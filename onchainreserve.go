@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcutil"
+)
+
+// onChainReserveConfig houses the dependencies and parameters of the
+// onChainReserve.
+type onChainReserveConfig struct {
+	// PerChannel is the on-chain balance set aside for each channel.
+	PerChannel btcutil.Amount
+
+	// FetchChannels returns all of our open and pending channels.
+	FetchChannels func() ([]*channeldb.OpenChannel, error)
+
+	// ConfirmedBalance returns our confirmed on-chain balance.
+	ConfirmedBalance func() (btcutil.Amount, error)
+}
+
+// onChainReserve tracks the portion of our confirmed on-chain balance which is
+// set aside to pay the fees of force closes, fee bumps, and sweeps, should any
+// of our channels have to be resolved on-chain. The reserve grows with the
+// number of channels, and coins may only be spent, or committed to new
+// channels, as long as the balance left covers it.
+type onChainReserve struct {
+	cfg onChainReserveConfig
+}
+
+// newOnChainReserve creates a new onChainReserve from the passed config.
+func newOnChainReserve(cfg onChainReserveConfig) *onChainReserve {
+	return &onChainReserve{
+		cfg: cfg,
+	}
+}
+
+// Required returns the on-chain balance reserved for our current channels.
+func (r *onChainReserve) Required() (btcutil.Amount, error) {
+	channels, err := r.cfg.FetchChannels()
+	if err != nil && err != channeldb.ErrNoActiveChannels {
+		return 0, err
+	}
+
+	return r.cfg.PerChannel * btcutil.Amount(len(channels)), nil
+}
+
+// CheckSpend returns an error if spending the passed amount from our
+// confirmed on-chain balance, while opening the passed number of new
+// channels, would leave less than the reserve required for our channels
+// thereafter. As the fee of the spending transaction isn't known yet, it isn't
+// accounted for.
+func (r *onChainReserve) CheckSpend(amt btcutil.Amount, newChans int) error {
+	required, err := r.Required()
+	if err != nil {
+		return fmt.Errorf("unable to determine on-chain reserve: %v",
+			err)
+	}
+	required += r.cfg.PerChannel * btcutil.Amount(newChans)
+
+	balance, err := r.cfg.ConfirmedBalance()
+	if err != nil {
+		return fmt.Errorf("unable to determine confirmed balance: %v",
+			err)
+	}
+
+	if balance-amt < required {
+		return fmt.Errorf("spending %v of the confirmed balance of %v "+
+			"would dip into the on-chain reserve of %v set aside "+
+			"for channel fees", amt, balance, required)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestOnChainReserve ensures that the on-chain reserve grows with the number
+// of channels, and that spends are only permitted if they leave enough of our
+// confirmed balance to cover the reserve of all channels, including new ones.
+func TestOnChainReserve(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numChannels int
+		balance     btcutil.Amount = 100000
+	)
+	reserve := newOnChainReserve(onChainReserveConfig{
+		PerChannel: 20000,
+		FetchChannels: func() ([]*channeldb.OpenChannel, error) {
+			if numChannels == 0 {
+				return nil, channeldb.ErrNoActiveChannels
+			}
+			return make([]*channeldb.OpenChannel, numChannels), nil
+		},
+		ConfirmedBalance: func() (btcutil.Amount, error) {
+			return balance, nil
+		},
+	})
+
+	tests := []struct {
+		name        string
+		numChannels int
+		amt         btcutil.Amount
+		newChans    int
+		required    btcutil.Amount
+		permitted   bool
+	}{
+		{
+			name:      "no channels",
+			amt:       100000,
+			permitted: true,
+		},
+		{
+			name:      "new channel",
+			amt:       80000,
+			newChans:  1,
+			permitted: true,
+		},
+		{
+			name:     "new channel dipping into reserve",
+			amt:      80001,
+			newChans: 1,
+		},
+		{
+			name:        "spend within reserve",
+			numChannels: 3,
+			amt:         40000,
+			required:    60000,
+			permitted:   true,
+		},
+		{
+			name:        "spend dipping into reserve",
+			numChannels: 3,
+			amt:         40001,
+			required:    60000,
+		},
+		{
+			name:        "reserve underfunded",
+			numChannels: 6,
+			amt:         1,
+			required:    120000,
+		},
+	}
+
+	for _, test := range tests {
+		numChannels = test.numChannels
+
+		required, err := reserve.Required()
+		if err != nil {
+			t.Fatalf("%v: unable to determine reserve: %v",
+				test.name, err)
+		}
+		if required != test.required {
+			t.Fatalf("%v: expected reserve of %v, got %v",
+				test.name, test.required, required)
+		}
+
+		err = reserve.CheckSpend(test.amt, test.newChans)
+		if (err == nil) != test.permitted {
+			t.Fatalf("%v: expected permitted=%v, got err=%v",
+				test.name, test.permitted, err)
+		}
+	}
+}
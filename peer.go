@@ -69,6 +69,17 @@ type closeMsg struct {
 	msg lnwire.Message
 }
 
+// linkFlushResult is sent to the channelManager once the link of a channel
+// that's about to be cooperatively closed has been flushed. It carries either
+// the local close request, or the remote close message, that triggered the
+// flush, such that it can be processed now that the channel is clean.
+type linkFlushResult struct {
+	chanID lnwire.ChannelID
+	req    *htlcswitch.ChanClose
+	msg    *closeMsg
+	err    error
+}
+
 // chanSnapshotReq is a message sent by outside subsystems to a peer in order
 // to gain a snapshot of the peer's currently active channels.
 type chanSnapshotReq struct {
@@ -86,6 +97,10 @@ type peer struct {
 	bytesReceived uint64
 	bytesSent     uint64
 
+	// unknownMsgs is the number of messages of a type we don't understand
+	// that we've received from the peer.
+	unknownMsgs uint64
+
 	// pingTime is a rough estimate of the RTT (round-trip-time) between us
 	// and the connected peer. This time is expressed in micro seconds.
 	// TODO(roasbeef): also use a WMA or EMA?
@@ -128,6 +143,12 @@ type peer struct {
 	activeChanMtx  sync.RWMutex
 	activeChannels map[lnwire.ChannelID]*lnwallet.LightningChannel
 
+	// delayedLinks tracks the channels whose link is yet to be started,
+	// as its restart is being dampened. Each channel is closed once the
+	// link has been added to the switch, or its start has been abandoned.
+	// The map is guarded by the activeChanMtx.
+	delayedLinks map[lnwire.ChannelID]chan struct{}
+
 	// newChannels is used by the fundingManager to send fully opened
 	// channels to the source peer which handled the funding workflow.
 	newChannels chan *newChannelMsg
@@ -148,6 +169,16 @@ type peer struct {
 	// well as lnwire.ClosingSigned messages.
 	chanCloseMsgs chan *closeMsg
 
+	// linkFlushes tracks the channels whose links have been placed into
+	// flush mode ahead of a cooperative close. An entry with a false value
+	// indicates the flush is still in progress. This map MUST only be
+	// accessed from the channelManager goroutine.
+	linkFlushes map[lnwire.ChannelID]bool
+
+	// flushedLinks is a channel over which the result of each link flush
+	// is delivered to the channelManager.
+	flushedLinks chan *linkFlushResult
+
 	server *server
 
 	// localFeatures is the set of local features that we advertised to the
@@ -191,11 +222,14 @@ func newPeer(conn net.Conn, connReq *connmgr.ConnReq, server *server,
 		outgoingQueue: make(chan outgoinMsg),
 
 		activeChannels: make(map[lnwire.ChannelID]*lnwallet.LightningChannel),
+		delayedLinks:   make(map[lnwire.ChannelID]chan struct{}),
 		newChannels:    make(chan *newChannelMsg, 1),
 
 		activeChanCloses:   make(map[lnwire.ChannelID]*channelCloser),
 		localCloseChanReqs: make(chan *htlcswitch.ChanClose),
 		chanCloseMsgs:      make(chan *closeMsg),
+		linkFlushes:        make(map[lnwire.ChannelID]bool),
+		flushedLinks:       make(chan *linkFlushResult),
 
 		queueQuit: make(chan struct{}),
 		quit:      make(chan struct{}),
@@ -277,6 +311,12 @@ func (p *peer) Start() error {
 	// goroutines required to operate them.
 	peerLog.Debugf("Loaded %v active channels from database with "+
 		"peerID(%v)", len(activeChans), p.id)
+
+	// As we have channels with this peer, we'll cache the features it
+	// just announced, so they can be reported while it's offline.
+	if len(activeChans) > 0 {
+		p.cacheRemoteFeatures()
+	}
 	if err := p.loadActiveChannels(activeChans); err != nil {
 		return fmt.Errorf("unable to load channels: %v", err)
 	}
@@ -288,6 +328,12 @@ func (p *peer) Start() error {
 	go p.channelManager()
 	go p.pingHandler()
 
+	// Now that the peer's goroutines are running, we'll exchange backup
+	// blobs with it if the peer storage feature is enabled.
+	if p.server.peerStorage != nil {
+		p.server.peerStorage.peerOnline(p)
+	}
+
 	return nil
 }
 
@@ -318,97 +364,261 @@ func (p *peer) loadActiveChannels(chans []*channeldb.OpenChannel) error {
 			continue
 		}
 
-		blockEpoch, err := p.server.cc.chainNotifier.RegisterBlockEpochNtfn()
-		if err != nil {
-			return err
-		}
-		_, currentHeight, err := p.server.cc.chainIO.GetBestBlock()
-		if err != nil {
-			return err
+		// If the link of this channel has been restarted repeatedly in
+		// quick succession, then we'll delay its start in order to
+		// dampen the churn caused by a flapping peer. Until then, the
+		// channel isn't registered with the switch or the chain
+		// arbitrator, so a peer disconnecting again in the meantime
+		// causes no further churn.
+		delay := p.server.linkDampener.restartDelay(*chanPoint)
+		if delay != 0 {
+			peerLog.Infof("peerID(%v) is flapping, delaying start "+
+				"of link for ChannelPoint(%v) by %v", p.id,
+				chanPoint, delay)
+
+			linkStarted := make(chan struct{})
+			p.activeChanMtx.Lock()
+			p.delayedLinks[chanID] = linkStarted
+			p.activeChanMtx.Unlock()
+
+			p.wg.Add(1)
+			go p.startDelayedLink(
+				chanPoint, lnChan, delay, linkStarted,
+			)
+			continue
 		}
 
-		// Before we register this new link with the HTLC Switch, we'll
-		// need to fetch its current link-layer forwarding policy from
-		// the database.
-		graph := p.server.chanDB.ChannelGraph()
-		info, p1, p2, err := graph.FetchChannelEdgesByOutpoint(chanPoint)
-		if err != nil && err != channeldb.ErrEdgeNotFound {
+		if err := p.addActiveLink(chanPoint, lnChan); err != nil {
 			return err
 		}
+	}
 
-		// We'll filter out our policy from the directional channel
-		// edges based whom the edge connects to. If it doesn't connect
-		// to us, then we know that we were the one that advertised the
-		// policy.
-		//
-		// TODO(roasbeef): can add helper method to get policy for
-		// particular channel.
-		var selfPolicy *channeldb.ChannelEdgePolicy
-		if info != nil && info.NodeKey1.IsEqual(p.server.identityPriv.PubKey()) {
-			selfPolicy = p1
-		} else {
-			selfPolicy = p2
-		}
+	return nil
+}
 
-		// If we don't yet have an advertised routing policy, then
-		// we'll use the current default, otherwise we'll translate the
-		// routing policy into a forwarding policy.
-		var forwardingPolicy *htlcswitch.ForwardingPolicy
-		if selfPolicy != nil {
-			forwardingPolicy = &htlcswitch.ForwardingPolicy{
-				MinHTLC:       selfPolicy.MinHTLC,
-				BaseFee:       selfPolicy.FeeBaseMSat,
-				FeeRate:       selfPolicy.FeeProportionalMillionths,
-				TimeLockDelta: uint32(selfPolicy.TimeLockDelta),
-			}
-		} else {
-			forwardingPolicy = &p.server.cc.routingPolicy
-		}
+// addActiveLink creates the link of an active channel, and registers it with
+// the htlcswitch.
+func (p *peer) addActiveLink(chanPoint *wire.OutPoint,
+	lnChan *lnwallet.LightningChannel) error {
 
-		peerLog.Tracef("Using link policy of: %v", spew.Sdump(forwardingPolicy))
+	blockEpoch, err := p.server.cc.chainNotifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+	_, currentHeight, err := p.server.cc.chainIO.GetBestBlock()
+	if err != nil {
+		return err
+	}
 
-		// Register this new channel link with the HTLC Switch. This is
-		// necessary to properly route multi-hop payments, and forward
-		// new payments triggered by RPC clients.
-		chainEvents, err := p.server.chainArb.SubscribeChannelEvents(
-			*chanPoint, false,
-		)
-		if err != nil {
-			return err
-		}
-		linkCfg := htlcswitch.ChannelLinkConfig{
-			Peer:                  p,
-			DecodeHopIterator:     p.server.sphinx.DecodeHopIterator,
-			DecodeOnionObfuscator: p.server.sphinx.ExtractErrorEncrypter,
-			GetLastChannelUpdate: createGetLastUpdate(p.server.chanRouter,
-				p.PubKey(), lnChan.ShortChanID()),
-			DebugHTLC:     cfg.DebugHTLC,
-			HodlHTLC:      cfg.HodlHTLC,
-			Registry:      p.server.invoices,
-			Switch:        p.server.htlcSwitch,
-			FwrdingPolicy: *forwardingPolicy,
-			FeeEstimator:  p.server.cc.feeEstimator,
-			BlockEpochs:   blockEpoch,
-			PreimageCache: p.server.witnessBeacon,
-			ChainEvents:   chainEvents,
-			UpdateContractSignals: func(signals *contractcourt.ContractSignals) error {
-				return p.server.chainArb.UpdateContractSignals(
-					*chanPoint, signals,
-				)
-			},
-			SyncStates: true,
-		}
-		link := htlcswitch.NewChannelLink(linkCfg, lnChan,
-			uint32(currentHeight))
+	// Before we register this new link with the HTLC Switch, we'll
+	// need to fetch its current link-layer forwarding policy from
+	// the database.
+	graph := p.server.chanDB.ChannelGraph()
+	info, p1, p2, err := graph.FetchChannelEdgesByOutpoint(chanPoint)
+	if err != nil && err != channeldb.ErrEdgeNotFound {
+		return err
+	}
 
-		if err := p.server.htlcSwitch.AddLink(link); err != nil {
-			return err
+	// We'll filter out our policy from the directional channel
+	// edges based whom the edge connects to. If it doesn't connect
+	// to us, then we know that we were the one that advertised the
+	// policy.
+	//
+	// TODO(roasbeef): can add helper method to get policy for
+	// particular channel.
+	var selfPolicy *channeldb.ChannelEdgePolicy
+	if info != nil && info.NodeKey1.IsEqual(p.server.identityPriv.PubKey()) {
+		selfPolicy = p1
+	} else {
+		selfPolicy = p2
+	}
+
+	// If we don't yet have an advertised routing policy, then
+	// we'll use the current default, otherwise we'll translate the
+	// routing policy into a forwarding policy. As the maximum HTLC
+	// isn't advertised, it's always taken from the default.
+	var forwardingPolicy *htlcswitch.ForwardingPolicy
+	if selfPolicy != nil {
+		forwardingPolicy = &htlcswitch.ForwardingPolicy{
+			MinHTLC:       selfPolicy.MinHTLC,
+			BaseFee:       selfPolicy.FeeBaseMSat,
+			FeeRate:       selfPolicy.FeeProportionalMillionths,
+			TimeLockDelta: uint32(selfPolicy.TimeLockDelta),
+			MaxHTLC:       p.server.cc.routingPolicy.MaxHTLC,
 		}
+	} else {
+		policy := p.server.cc.routingPolicyForPeer(
+			p.addr.IdentityKey,
+		)
+		forwardingPolicy = &policy
 	}
 
+	peerLog.Tracef("Using link policy of: %v", spew.Sdump(forwardingPolicy))
+
+	// Register this new channel link with the HTLC Switch. This is
+	// necessary to properly route multi-hop payments, and forward
+	// new payments triggered by RPC clients.
+	chainEvents, err := p.server.chainArb.SubscribeChannelEvents(
+		*chanPoint, false,
+	)
+	if err != nil {
+		return err
+	}
+	linkCfg := htlcswitch.ChannelLinkConfig{
+		Peer:           p,
+		OnionProcessor: p.server.sphinx,
+		GetLastChannelUpdate: createGetLastUpdate(p.server.chanRouter,
+			p.PubKey(), lnChan.ShortChanID()),
+		DebugHTLC:     cfg.DebugHTLC,
+		HodlHTLC:      cfg.HodlHTLC,
+		Registry:      p.server.invoices,
+		Switch:        p.server.htlcSwitch,
+		FwrdingPolicy: *forwardingPolicy,
+		FeeEstimator:  p.server.cc.feeEstimator,
+		ChainHealthy:  p.server.chainHealth.IsHealthy,
+		BlockEpochs:   blockEpoch,
+		PreimageCache: p.server.witnessBeacon,
+		ChainEvents:   chainEvents,
+		UpdateContractSignals: func(signals *contractcourt.ContractSignals) error {
+			return p.server.chainArb.UpdateContractSignals(
+				*chanPoint, signals,
+			)
+		},
+		SyncStates:            true,
+		StuckHTLCThreshold:    cfg.StuckHTLCThreshold,
+		ChanSyncTimeout:       cfg.ChanSyncTimeout,
+		ChanSyncRetries:       cfg.ChanSyncRetries,
+		EndorsementExperiment: cfg.ExperimentalEndorsement,
+		StrictUnknownMsgs:     cfg.StrictUnknownMsgs,
+		MaxAcceptedHtlcs:      cfg.MaxLinkHtlcs,
+		MaxPendingAmount:      cfg.MaxLinkPendingAmt,
+		RejectLegacyPayments:  cfg.RejectLegacyPayments,
+		OverflowPolicy: htlcswitch.OverflowPolicy(
+			cfg.OverflowPolicy,
+		),
+		SafeExitSettle:      cfg.SafeExitSettle,
+		AcceptKeysend:       cfg.ExperimentalKeysend,
+		AcceptAmp:           cfg.ExperimentalAmp,
+		StatelessInvoiceKey: p.server.statelessInvoiceKey,
+		MaxOverpaymentPct:   cfg.MaxOverpaymentPct,
+		ExpiryGraceDelta:    cfg.HtlcExpiryGrace,
+		FinalCltvGrace:      cfg.FinalCltvGrace,
+		MinCltvDelta:        cfg.MinCltvDelta,
+		ForceCloseChan: func() error {
+			return p.forceCloseChan(*chanPoint)
+		},
+		QuiescenceSupported:     p.supportsQuiescence(),
+		OnForceCloseRecommended: p.forceCloseAlerter(*chanPoint),
+		RecordHTLCStats: func(stats *channeldb.ChannelHTLCStats) error {
+			return p.server.chanDB.AddChannelHTLCStats(
+				p.pubKeyBytes, chanPoint, stats,
+			)
+		},
+		BatchSize:              cfg.LinkBatchSize,
+		BatchTicker:            cfg.LinkBatchTicker,
+		PendingCommitTicker:    cfg.LinkPendingCommitTicker,
+		MaxPendingCommitTicker: cfg.LinkMaxPendingCommitTicker,
+		MaxOverflowQueueLen:    cfg.MaxOverflowQueueLen,
+		MaxOverflowResidency:   cfg.MaxOverflowResidency,
+		FeeSpikeMultiplier:     cfg.FeeSpikeMultiplier,
+		MinFeeUpdateInterval:   cfg.MinFeeUpdateInterval,
+		MaxFeeUpdateStep:       cfg.MaxFeeUpdateStep,
+		MinFeeRate:             cfg.MinRemoteFeeRate,
+		MaxFeeRate:             cfg.MaxRemoteFeeRate,
+		FeeRateTolerance:       cfg.RemoteFeeTolerance,
+	}
+	link := htlcswitch.NewChannelLink(linkCfg, lnChan,
+		uint32(currentHeight))
+
+	if err := p.server.htlcSwitch.AddLink(link); err != nil {
+		return err
+	}
+
+	// If the peer disconnected while the link was being added, then it
+	// won't be removed along with the peer's other links, so we'll remove
+	// it ourselves.
+	select {
+	case <-p.quit:
+		return p.server.htlcSwitch.RemoveLink(link.ChanID())
+	default:
+	}
+
+	p.server.channelNotifier.NotifyActiveChannelEvent(*chanPoint)
+
 	return nil
 }
 
+// startDelayedLink adds the link of an active channel to the htlcswitch once
+// the delay imposed on its start has elapsed, unless the peer disconnects in
+// the meantime.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (p *peer) startDelayedLink(chanPoint *wire.OutPoint,
+	lnChan *lnwallet.LightningChannel, delay time.Duration,
+	linkStarted chan struct{}) {
+
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+
+	var err error
+	select {
+	case <-time.After(delay):
+		err = p.addActiveLink(chanPoint, lnChan)
+	case <-p.quit:
+	}
+
+	p.activeChanMtx.Lock()
+	delete(p.delayedLinks, chanID)
+	p.activeChanMtx.Unlock()
+	close(linkStarted)
+
+	p.wg.Done()
+
+	if err != nil {
+		peerLog.Errorf("unable to start link for ChannelPoint(%v): %v",
+			chanPoint, err)
+		p.Disconnect(err)
+	}
+}
+
+// waitForDelayedLink blocks until the link of the target channel has been
+// started, if its start is being delayed, or until the peer disconnects.
+func (p *peer) waitForDelayedLink(chanID lnwire.ChannelID) {
+	p.activeChanMtx.RLock()
+	linkStarted, ok := p.delayedLinks[chanID]
+	p.activeChanMtx.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	select {
+	case <-linkStarted:
+	case <-p.quit:
+	}
+}
+
+// forceCloseAlerter returns the callback through which the link of the target
+// channel reports that the channel should be force closed, or nil if alerting
+// is disabled.
+func (p *peer) forceCloseAlerter(chanPoint wire.OutPoint) func(error) {
+	if p.server.alerter == nil {
+		return nil
+	}
+
+	return func(reason error) {
+		p.server.alerter.NotifyForceCloseRecommended(
+			chanPoint, p.pubKeyBytes, reason,
+		)
+	}
+}
+
+// supportsQuiescence returns true if both we and the peer support bringing
+// channels into a quiescent state. As we always signal support for it, this
+// only depends on the features the peer has announced.
+func (p *peer) supportsQuiescence() bool {
+	return p.remoteLocalFeatures.HasFeature(lnwire.QuiesceOptional)
+}
+
 // WaitForDisconnect waits until the peer has disconnected. A peer may be
 // disconnected if the local or remote side terminating the connection, or an
 // irrecoverable protocol error has been encountered.
@@ -460,13 +670,32 @@ func (p *peer) readNextMessage() (lnwire.Message, error) {
 	// Next, create a new io.Reader implementation from the raw message,
 	// and use this to decode the message directly from.
 	msgReader := bytes.NewReader(rawMsg)
-	nextMsg, err := lnwire.ReadMessage(msgReader, 0)
-	if err != nil {
+	nextMsg, err := lnwire.ReadMessage(
+		msgReader, lnwire.ProtocolVersionExtensions,
+	)
+	if unknown, ok := err.(*lnwire.UnknownMessage); ok {
+		// If the message is of a type we don't understand, but is
+		// long enough to carry a channel ID, then we'll retain it as
+		// an opaque message, as it may be destined for one of our
+		// channels.
+		if msgReader.Len() < len(lnwire.ChannelID{}) {
+			return nil, err
+		}
+
+		opaqueMsg := lnwire.NewOpaqueMessage(unknown.Type())
+		if err := opaqueMsg.Decode(msgReader, 0); err != nil {
+			return nil, err
+		}
+		nextMsg = opaqueMsg
+	} else if err != nil {
 		return nil, err
 	}
 
 	// TODO(roasbeef): add message summaries
 	p.logWireMessage(nextMsg, true)
+	if p.server.messageTap != nil {
+		p.server.messageTap.Tap(p.pubKeyBytes, nextMsg, true)
+	}
 
 	return nextMsg, nil
 }
@@ -489,6 +718,12 @@ type msgStream struct {
 	msgCond *sync.Cond
 	msgs    []lnwire.Message
 
+	// producerSema holds a token for each message within the queue. As
+	// it's bounded, AddMsg blocks once the queue is full, which stops the
+	// peer's readHandler from reading any further messages off the wire
+	// until the consumer catches up. If nil, the queue is unbounded.
+	producerSema chan struct{}
+
 	mtx sync.Mutex
 
 	wg   sync.WaitGroup
@@ -496,8 +731,9 @@ type msgStream struct {
 }
 
 // newMsgStream creates a new instance of a chanMsgStream for a particular
-// channel identified by its channel ID.
-func newMsgStream(p *peer, startMsg, stopMsg string,
+// channel identified by its channel ID. At most bufSize messages are queued
+// at once, or an unbounded number if bufSize is zero.
+func newMsgStream(p *peer, startMsg, stopMsg string, bufSize int,
 	apply func(lnwire.Message)) *msgStream {
 
 	stream := &msgStream{
@@ -508,6 +744,9 @@ func newMsgStream(p *peer, startMsg, stopMsg string,
 		quit:     make(chan struct{}),
 	}
 	stream.msgCond = sync.NewCond(&stream.mtx)
+	if bufSize > 0 {
+		stream.producerSema = make(chan struct{}, bufSize)
+	}
 
 	return stream
 }
@@ -570,14 +809,34 @@ func (ms *msgStream) msgConsumer() {
 
 		ms.msgCond.L.Unlock()
 
+		// As the message has left the queue, we'll release its token,
+		// allowing a blocked producer to add another.
+		if ms.producerSema != nil {
+			<-ms.producerSema
+		}
+
 		ms.apply(msg)
 	}
 }
 
-// AddMsg adds a new message to the msgStream. This function is safe for
-// concurrent access.
+// AddMsg adds a new message to the msgStream. If the stream is bounded and
+// its queue is full, then this blocks until the consumer has made space, or
+// either the stream or its peer is shutting down, in which case the message
+// is dropped. This function is safe for concurrent access.
 func (ms *msgStream) AddMsg(msg lnwire.Message) {
-	// First, we'll lock the condition, and add the message to the end of
+	// First, we'll acquire a token for the message, waiting for space
+	// within the queue if it's full.
+	if ms.producerSema != nil {
+		select {
+		case ms.producerSema <- struct{}{}:
+		case <-ms.peer.quit:
+			return
+		case <-ms.quit:
+			return
+		}
+	}
+
+	// Next, we'll lock the condition, and add the message to the end of
 	// the message queue.
 	ms.msgCond.L.Lock()
 	ms.msgs = append(ms.msgs, msg)
@@ -601,6 +860,7 @@ func newChanMsgStream(p *peer, cid lnwire.ChannelID) *msgStream {
 	return newMsgStream(p,
 		fmt.Sprintf("Update stream for ChannelID(%x) created", cid[:]),
 		fmt.Sprintf("Update stream for ChannelID(%x) exiting", cid[:]),
+		cfg.MailBoxMaxMsgs,
 		func(msg lnwire.Message) {
 			_, isChanSycMsg := msg.(*lnwire.ChannelReestablish)
 
@@ -621,8 +881,12 @@ func newChanMsgStream(p *peer, cid lnwire.ChannelID) *msgStream {
 			// TODO(roasbeef): only wait if not chan sync
 
 			// Dispatch the commitment update message to the proper active
-			// goroutine dedicated to this channel.
+			// goroutine dedicated to this channel. If the start of
+			// its link is being delayed, then we'll hold the
+			// message until the link has started.
 			if chanLink == nil {
+				p.waitForDelayedLink(cid)
+
 				link, err := p.server.htlcSwitch.GetLink(cid)
 				if err != nil {
 					peerLog.Errorf("recv'd update for unknown "+
@@ -637,6 +901,10 @@ func newChanMsgStream(p *peer, cid lnwire.ChannelID) *msgStream {
 	)
 }
 
+// discStreamSize is the maximum number of messages queued for the gossiper by
+// the msgStream of each peer, before we stop reading from the peer.
+const discStreamSize = 1000
+
 // newDiscMsgStream is used to setup a msgStream between the peer and the
 // authenticated gossiper. This stream should be used to forward all remote
 // channel announcements.
@@ -644,6 +912,7 @@ func newDiscMsgStream(p *peer) *msgStream {
 	return newMsgStream(p,
 		"Update stream for gossiper created",
 		"Update stream for gossiper exited",
+		discStreamSize,
 		func(msg lnwire.Message) {
 			p.server.authGossiper.ProcessRemoteAnnouncement(msg,
 				p.addr.IdentityKey)
@@ -678,15 +947,28 @@ out:
 			peerLog.Infof("unable to read message from %v: %v",
 				p, err)
 
-			switch err.(type) {
+			switch err := err.(type) {
 			// If this is just a message we don't yet recognize,
 			// we'll continue processing as normal as this allows
 			// us to introduce new messages in a forwards
-			// compatible manner.
+			// compatible manner, unless it's of an even type and
+			// we're strictly enforcing BOLT #1.
 			case *lnwire.UnknownMessage:
+				if !p.handleUnknownMessage(err.Type()) {
+					break out
+				}
+
 				idleTimer.Reset(idleTimeout)
 				continue
 
+			// If a field of the message exceeded its maximum
+			// length, then the peer is either faulty or hostile.
+			// We'll let it know which channel the message was
+			// rejected for before disconnecting.
+			case *lnwire.ErrFieldTooLarge:
+				p.rejectOversizedMsg(err)
+				break out
+
 			// If the error we encountered wasn't just a message we
 			// didn't recognize, then we'll stop all processing s
 			// this is a fatal error.
@@ -714,6 +996,17 @@ out:
 			pongBytes := make([]byte, msg.NumPongBytes)
 			p.queueMsg(lnwire.NewPong(pongBytes), nil)
 
+		case *lnwire.PeerStorage:
+			if p.server.peerStorage != nil {
+				p.server.peerStorage.handlePeerStorage(p, msg)
+			}
+		case *lnwire.PeerStorageRetrieval:
+			if p.server.peerStorage != nil {
+				p.server.peerStorage.handlePeerStorageRetrieval(
+					p, msg,
+				)
+			}
+
 		case *lnwire.OpenChannel:
 			p.server.fundingMgr.processFundingOpen(msg, p.addr)
 		case *lnwire.AcceptChannel:
@@ -766,6 +1059,9 @@ out:
 		case *lnwire.ChannelReestablish:
 			isChanUpdate = true
 			targetChan = msg.ChanID
+		case *lnwire.Stfu:
+			isChanUpdate = true
+			targetChan = msg.ChanID
 
 		case *lnwire.ChannelUpdate,
 			*lnwire.ChannelAnnouncement,
@@ -774,6 +1070,25 @@ out:
 
 			discStream.AddMsg(msg)
 
+		// A message of a type we don't understand. If it's destined
+		// for one of our channels, then we'll let the channel's link
+		// decide how to handle it. Otherwise, it's handled at the
+		// connection level.
+		case *lnwire.OpaqueMessage:
+			link, err := p.server.htlcSwitch.GetLink(msg.ChanID)
+			switch {
+			case err == nil && link.Peer().PubKey() == p.PubKey():
+				if !p.recordUnknownMessage(msg.Type) {
+					break out
+				}
+
+				isChanUpdate = true
+				targetChan = msg.ChanID
+
+			case !p.handleUnknownMessage(msg.Type):
+				break out
+			}
+
 		default:
 			peerLog.Errorf("unknown message %v received from peer "+
 				"%v", uint16(msg.MsgType()), p)
@@ -813,6 +1128,75 @@ out:
 	peerLog.Tracef("readHandler for peer %v done", p)
 }
 
+// recordUnknownMessage records the receipt of a message of a type we don't
+// understand. If the number of such messages received from the peer exceeds
+// the configured limit, then false is returned, and the peer should be
+// disconnected.
+//
+// NOTE: This method MUST only be called from the readHandler.
+func (p *peer) recordUnknownMessage(msgType lnwire.MessageType) bool {
+	numUnknown := atomic.AddUint64(&p.unknownMsgs, 1)
+
+	peerLog.Debugf("Received message of unknown type %v from %v, %v "+
+		"unknown messages received in total", uint16(msgType), p,
+		numUnknown)
+
+	if cfg.MaxUnknownMsgs != 0 && numUnknown > uint64(cfg.MaxUnknownMsgs) {
+		peerLog.Warnf("Disconnecting %v, %v unknown messages received "+
+			"exceeds limit of %v", p, numUnknown, cfg.MaxUnknownMsgs)
+		return false
+	}
+
+	return true
+}
+
+// rejectOversizedMsg sends an Error to the peer for the channel a message
+// which exceeded the maximum length of one of its fields was destined for,
+// should it have been a channel update message. The Error informs the peer
+// that the channel has been failed, and we'll wait for it to be written before
+// returning, so that it's not lost as we disconnect.
+func (p *peer) rejectOversizedMsg(tooLarge *lnwire.ErrFieldTooLarge) {
+	if tooLarge.ChanID == nil {
+		return
+	}
+
+	peerLog.Warnf("Failing ChannelID(%v) with peer %v, received "+
+		"oversized message: %v", *tooLarge.ChanID, p, tooLarge)
+
+	errChan := make(chan error, 1)
+	p.queueMsg(&lnwire.Error{
+		ChanID: *tooLarge.ChanID,
+		Data:   lnwire.ErrorData(tooLarge.Error()),
+	}, errChan)
+
+	select {
+	case <-errChan:
+	case <-time.After(time.Second * 5):
+	case <-p.quit:
+	}
+}
+
+// handleUnknownMessage handles a message of a type we don't understand that
+// isn't destined for one of our channels. As per BOLT #1, if we're strictly
+// enforcing the "it's ok to be odd" rule, then we'll disconnect from the peer
+// upon receiving an unknown message of an even type. In that case, or if the
+// peer has sent too many unknown messages, false is returned.
+//
+// NOTE: This method MUST only be called from the readHandler.
+func (p *peer) handleUnknownMessage(msgType lnwire.MessageType) bool {
+	if !p.recordUnknownMessage(msgType) {
+		return false
+	}
+
+	if cfg.StrictUnknownMsgs && msgType%2 == 0 {
+		peerLog.Warnf("Disconnecting %v, received message of unknown "+
+			"even type %v", p, uint16(msgType))
+		return false
+	}
+
+	return true
+}
+
 // messageSummary returns a human-readable string that summarizes a
 // incoming/outgoing message. Not all messages will have a summary, only those
 // which have additional data that can be informative at a glance.
@@ -915,6 +1299,10 @@ func messageSummary(msg lnwire.Message) string {
 	case *lnwire.ChannelReestablish:
 		return fmt.Sprintf("next_local_height=%v, remote_tail_height=%v",
 			msg.NextLocalCommitHeight, msg.RemoteCommitTailHeight)
+
+	case *lnwire.Stfu:
+		return fmt.Sprintf("chan_id=%v, initiator=%v", msg.ChanID,
+			msg.Initiator)
 	}
 
 	return ""
@@ -998,6 +1386,9 @@ func (p *peer) writeMessage(msg lnwire.Message) error {
 
 	// TODO(roasbeef): add message summaries
 	p.logWireMessage(msg, false)
+	if p.server.messageTap != nil {
+		p.server.messageTap.Tap(p.pubKeyBytes, msg, false)
+	}
 
 	// As the Lightning wire protocol is fully message oriented, we only
 	// allows one wire message per outer encapsulated crypto message. So
@@ -1007,7 +1398,7 @@ func (p *peer) writeMessage(msg lnwire.Message) error {
 
 	// With the temp buffer created and sliced properly (length zero, full
 	// capacity), we'll now encode the message directly into this buffer.
-	n, err := lnwire.WriteMessage(b, msg, 0)
+	n, err := lnwire.WriteMessage(b, msg, lnwire.ProtocolVersionExtensions)
 	atomic.AddUint64(&p.bytesSent, uint64(n))
 
 	// TODO(roasbeef): add write deadline?
@@ -1246,6 +1637,20 @@ out:
 			peerLog.Infof("New channel active ChannelPoint(%v) "+
 				"with peerId(%v)", chanPoint, p.id)
 
+			// Now that we have a channel with this peer, its
+			// features are worth caching, if they weren't already.
+			p.cacheRemoteFeatures()
+
+			// As our set of channels has changed, we'll provide
+			// the peer with an updated backup blob.
+			if p.server.peerStorage != nil {
+				err := p.server.peerStorage.sendBlob(p)
+				if err != nil {
+					peerLog.Errorf("unable to send peer "+
+						"storage: %v", err)
+				}
+			}
+
 			// Next, we'll assemble a ChannelLink along with the
 			// necessary items it needs to function.
 			//
@@ -1268,18 +1673,24 @@ out:
 					"events: %v", err)
 				continue
 			}
+			// The new channel starts out with the default routing
+			// policy for this peer.
+			fwdingPolicy := p.server.cc.routingPolicyForPeer(
+				p.addr.IdentityKey,
+			)
+
 			linkConfig := htlcswitch.ChannelLinkConfig{
-				Peer:                  p,
-				DecodeHopIterator:     p.server.sphinx.DecodeHopIterator,
-				DecodeOnionObfuscator: p.server.sphinx.ExtractErrorEncrypter,
+				Peer:           p,
+				OnionProcessor: p.server.sphinx,
 				GetLastChannelUpdate: createGetLastUpdate(p.server.chanRouter,
 					p.PubKey(), newChanReq.channel.ShortChanID()),
 				DebugHTLC:     cfg.DebugHTLC,
 				HodlHTLC:      cfg.HodlHTLC,
 				Registry:      p.server.invoices,
 				Switch:        p.server.htlcSwitch,
-				FwrdingPolicy: p.server.cc.routingPolicy,
+				FwrdingPolicy: fwdingPolicy,
 				FeeEstimator:  p.server.cc.feeEstimator,
+				ChainHealthy:  p.server.chainHealth.IsHealthy,
 				BlockEpochs:   blockEpoch,
 				PreimageCache: p.server.witnessBeacon,
 				ChainEvents:   chainEvents,
@@ -1288,7 +1699,50 @@ out:
 						*chanPoint, signals,
 					)
 				},
-				SyncStates: false,
+				SyncStates:            false,
+				StuckHTLCThreshold:    cfg.StuckHTLCThreshold,
+				ChanSyncTimeout:       cfg.ChanSyncTimeout,
+				ChanSyncRetries:       cfg.ChanSyncRetries,
+				EndorsementExperiment: cfg.ExperimentalEndorsement,
+				StrictUnknownMsgs:     cfg.StrictUnknownMsgs,
+				MaxAcceptedHtlcs:      cfg.MaxLinkHtlcs,
+				MaxPendingAmount:      cfg.MaxLinkPendingAmt,
+				RejectLegacyPayments:  cfg.RejectLegacyPayments,
+				OverflowPolicy: htlcswitch.OverflowPolicy(
+					cfg.OverflowPolicy,
+				),
+				SafeExitSettle:      cfg.SafeExitSettle,
+				AcceptKeysend:       cfg.ExperimentalKeysend,
+				AcceptAmp:           cfg.ExperimentalAmp,
+				StatelessInvoiceKey: p.server.statelessInvoiceKey,
+				MaxOverpaymentPct:   cfg.MaxOverpaymentPct,
+				ExpiryGraceDelta:    cfg.HtlcExpiryGrace,
+				FinalCltvGrace:      cfg.FinalCltvGrace,
+				MinCltvDelta:        cfg.MinCltvDelta,
+				ForceCloseChan: func() error {
+					return p.forceCloseChan(*chanPoint)
+				},
+				QuiescenceSupported: p.supportsQuiescence(),
+				OnForceCloseRecommended: p.forceCloseAlerter(
+					*chanPoint,
+				),
+				RecordHTLCStats: func(stats *channeldb.ChannelHTLCStats) error {
+					return p.server.chanDB.AddChannelHTLCStats(
+						p.pubKeyBytes, chanPoint, stats,
+					)
+				},
+				BatchSize:              cfg.LinkBatchSize,
+				BatchTicker:            cfg.LinkBatchTicker,
+				PendingCommitTicker:    cfg.LinkPendingCommitTicker,
+				MaxPendingCommitTicker: cfg.LinkMaxPendingCommitTicker,
+				MaxOverflowQueueLen:    cfg.MaxOverflowQueueLen,
+				MaxOverflowResidency:   cfg.MaxOverflowResidency,
+				FeeSpikeMultiplier:     cfg.FeeSpikeMultiplier,
+				MinFeeUpdateInterval:   cfg.MinFeeUpdateInterval,
+				MaxFeeUpdateStep:       cfg.MaxFeeUpdateStep,
+				MinFeeRate:             cfg.MinRemoteFeeRate,
+				MaxFeeRate:             cfg.MaxRemoteFeeRate,
+				FeeRateTolerance:       cfg.RemoteFeeTolerance,
 			}
 			link := htlcswitch.NewChannelLink(linkConfig, newChan,
 				uint32(currentHeight))
@@ -1296,9 +1750,13 @@ out:
 			// With the channel link created, we'll now notify the
 			// htlc switch so this channel can be used to dispatch
 			// local payments and also passively forward payments.
-			if err := p.server.htlcSwitch.AddLink(link); err != nil {
+			err = p.server.htlcSwitch.AddLink(link)
+			if err != nil {
 				peerLog.Errorf("can't register new channel "+
 					"link(%v) with peerId(%v)", chanPoint, p.id)
+			} else {
+				notifier := p.server.channelNotifier
+				notifier.NotifyActiveChannelEvent(*chanPoint)
 			}
 
 			close(newChanReq.done)
@@ -1308,64 +1766,57 @@ out:
 		// closure negotiation, or be a notification of a breached
 		// contract that should be abandoned.
 		case req := <-p.localCloseChanReqs:
+			// Before we negotiate a cooperative close, the link of
+			// the channel must first be flushed of any HTLCs.
+			if req.CloseType == htlcswitch.CloseRegular {
+				chanID := lnwire.NewChanIDFromOutPoint(
+					req.ChanPoint,
+				)
+				if p.flushLink(chanID, req, nil) {
+					continue
+				}
+			}
+
 			p.handleLocalCloseReq(req)
 
 		// We've received a new cooperative channel closure related
 		// message from the remote peer, we'll use this message to
 		// advance the chan closer state machine.
 		case closeMsg := <-p.chanCloseMsgs:
-			// We'll now fetch the matching closing state machine
-			// in order to continue, or finalize the channel
-			// closure process.
-			chanCloser, err := p.fetchActiveChanCloser(closeMsg.cid)
-			if err != nil {
-				// TODO(roasbeef): send protocol error?
-				peerLog.Errorf("unable to respond to remote "+
-					"close msg: %v", err)
+			// If the remote peer is initiating the closure, then
+			// we'll flush the link of the channel before
+			// responding.
+			_, ok := p.activeChanCloses[closeMsg.cid]
+			if !ok && p.flushLink(closeMsg.cid, nil, closeMsg) {
 				continue
 			}
 
-			// Next, we'll process the next message using the
-			// target state machine. We'll either continue
-			// negotiation, or halt.
-			msgs, closeFin, err := chanCloser.ProcessCloseMsg(
-				closeMsg.msg,
-			)
-			if err != nil {
-				err := fmt.Errorf("unable to process close "+
-					"msg: %v", err)
-				peerLog.Error(err)
+			p.handleCloseMsg(closeMsg)
 
-				// As the negotiations failed, we'll reset the
-				// channel state to ensure we act to on-chain
-				// events as normal.
-				chanCloser.cfg.channel.ResetState()
+		// The link of a channel that's about to be cooperatively
+		// closed has been flushed, so we can now process the request
+		// or message that triggered the flush.
+		case res := <-p.flushedLinks:
+			if res.err != nil {
+				err := fmt.Errorf("unable to flush link of "+
+					"ChannelID(%v): %v", res.chanID, res.err)
+				peerLog.Error(err)
 
-				if chanCloser.CloseRequest() != nil {
-					chanCloser.CloseRequest().Err <- err
+				delete(p.linkFlushes, res.chanID)
+				if res.req != nil {
+					res.req.Err <- err
 				}
-				delete(p.activeChanCloses, closeMsg.cid)
 				continue
 			}
 
-			// Queue any messages to the remote peer that need to
-			// be sent as a part of this latest round of
-			// negotiations.
-			for _, msg := range msgs {
-				p.queueMsg(msg, nil)
-			}
+			p.linkFlushes[res.chanID] = true
 
-			// If we haven't finished close negotiations, then
-			// we'll continue as we can't yet finalize the closure.
-			if !closeFin {
-				continue
+			if res.req != nil {
+				p.handleLocalCloseReq(res.req)
+			} else {
+				p.handleCloseMsg(res.msg)
 			}
 
-			// Otherwise, we've agreed on a closing fee! In this
-			// case, we'll wrap up the channel closure by notifying
-			// relevant sub-systems and launching a goroutine to
-			// wait for close tx conf.
-			p.finalizeChanClosure(chanCloser)
 		case <-p.quit:
 
 			// As, we've been signalled to exit, we'll reset all
@@ -1381,6 +1832,105 @@ out:
 	}
 }
 
+// flushLink places the link of the target channel into flush mode ahead of a
+// cooperative close, if it hasn't been flushed already. The flush is carried
+// out within a goroutine, as the link must be allowed to settle or fail any
+// HTLCs still in flight. Once it completes, the passed close request or
+// message is delivered back to the channelManager over the flushedLinks
+// channel. True is returned if the request or message should be held until
+// then.
+//
+// NOTE: This MUST only be called from the channelManager goroutine.
+func (p *peer) flushLink(chanID lnwire.ChannelID, req *htlcswitch.ChanClose,
+	msg *closeMsg) bool {
+
+	if _, ok := p.linkFlushes[chanID]; ok {
+		return false
+	}
+
+	// If the channel has no active link, then there's nothing to flush.
+	link, err := p.server.htlcSwitch.GetLink(chanID)
+	if err != nil {
+		return false
+	}
+
+	peerLog.Infof("Flushing link of ChannelID(%v) before cooperative "+
+		"close", chanID)
+
+	p.linkFlushes[chanID] = false
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		res := &linkFlushResult{
+			chanID: chanID,
+			req:    req,
+			msg:    msg,
+			err:    link.ShutdownIfChannelClean(),
+		}
+
+		select {
+		case p.flushedLinks <- res:
+		case <-p.quit:
+		}
+	}()
+
+	return true
+}
+
+// handleCloseMsg advances the cooperative close state machine of the target
+// channel using a closing message received from the remote peer. If no state
+// machine exists for the channel yet, then the remote peer is initiating the
+// closure, so a new one will be created.
+//
+// NOTE: This MUST only be called from the channelManager goroutine.
+func (p *peer) handleCloseMsg(closeMsg *closeMsg) {
+	// We'll now fetch the matching closing state machine in order to
+	// continue, or finalize the channel closure process.
+	chanCloser, err := p.fetchActiveChanCloser(closeMsg.cid)
+	if err != nil {
+		// TODO(roasbeef): send protocol error?
+		peerLog.Errorf("unable to respond to remote close msg: %v", err)
+		return
+	}
+
+	// Next, we'll process the next message using the target state
+	// machine. We'll either continue negotiation, or halt.
+	msgs, closeFin, err := chanCloser.ProcessCloseMsg(closeMsg.msg)
+	if err != nil {
+		err := fmt.Errorf("unable to process close msg: %v", err)
+		peerLog.Error(err)
+
+		// As the negotiations failed, we'll reset the channel state to
+		// ensure we act to on-chain events as normal.
+		chanCloser.cfg.channel.ResetState()
+
+		if chanCloser.CloseRequest() != nil {
+			chanCloser.CloseRequest().Err <- err
+		}
+		delete(p.activeChanCloses, closeMsg.cid)
+		return
+	}
+
+	// Queue any messages to the remote peer that need to be sent as a
+	// part of this latest round of negotiations.
+	for _, msg := range msgs {
+		p.queueMsg(msg, nil)
+	}
+
+	// If we haven't finished close negotiations, then we'll continue as
+	// we can't yet finalize the closure.
+	if !closeFin {
+		return
+	}
+
+	// Otherwise, we've agreed on a closing fee! In this case, we'll wrap
+	// up the channel closure by notifying relevant sub-systems and
+	// launching a goroutine to wait for close tx conf.
+	p.finalizeChanClosure(chanCloser)
+}
+
 // fetchActiveChanCloser attempts to fetch the active chan closer state machine
 // for the target channel ID. If the channel isn't active an error is returned.
 // Otherwise, either an existing state machine will be returned, or a new one
@@ -1483,14 +2033,19 @@ func (p *peer) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 	// out this channel on-chain, so we execute the cooperative channel
 	// closure workflow.
 	case htlcswitch.CloseRegular:
-		// First, we'll fetch a fresh delivery address that we'll use
-		// to send the funds to in the case of a successful
-		// negotiation.
-		deliveryAddr, err := p.genDeliveryScript()
-		if err != nil {
-			peerLog.Errorf(err.Error())
-			req.Err <- err
-			return
+		// First, we'll determine the delivery script that we'll use to
+		// send the funds to in the case of a successful negotiation.
+		// If the caller didn't specify one, then we'll fetch a fresh
+		// address from the wallet.
+		deliveryAddr := req.DeliveryScript
+		if deliveryAddr == nil {
+			var err error
+			deliveryAddr, err = p.genDeliveryScript()
+			if err != nil {
+				peerLog.Errorf(err.Error())
+				req.Err <- err
+				return
+			}
 		}
 
 		// Before we create the chan closer, we'll start a new
@@ -1573,6 +2128,7 @@ func (p *peer) finalizeChanClosure(chanCloser *channelCloser) {
 
 	// First, we'll clear all indexes related to the channel in question.
 	chanPoint := chanCloser.cfg.channel.ChannelPoint()
+	delete(p.linkFlushes, lnwire.NewChanIDFromOutPoint(chanPoint))
 	if err := p.WipeChannel(chanPoint); err != nil {
 		if closeReq != nil {
 			closeReq.Err <- err
@@ -1692,11 +2248,44 @@ func (p *peer) WipeChannel(chanPoint *wire.OutPoint) error {
 		if err == htlcswitch.ErrChannelLinkNotFound {
 			peerLog.Warnf("unable remove channel link with "+
 				"ChannelPoint(%v): %v", chanID, err)
-			return nil
+			return p.server.htlcSwitch.DiscardMailBox(chanID)
 		}
 		return err
 	}
 
+	// As the channel is no longer active, its link won't be restarted,
+	// so any packets still buffered for it are failed back.
+	if err := p.server.htlcSwitch.DiscardMailBox(chanID); err != nil {
+		return err
+	}
+
+	p.server.channelNotifier.NotifyInactiveChannelEvent(*chanPoint)
+
+	return nil
+}
+
+// forceCloseChan hands the channel with the passed channel point off to the
+// chain arbitrator for on-chain resolution. The channel is wiped beforehand,
+// so that no further updates are applied to it.
+func (p *peer) forceCloseChan(chanPoint wire.OutPoint) error {
+	if err := p.WipeChannel(&chanPoint); err != nil {
+		return err
+	}
+
+	select {
+	case p.server.breachArbiter.settledContracts <- chanPoint:
+	case <-p.server.quit:
+		return fmt.Errorf("server shutting down")
+	}
+
+	closeTx, err := p.server.chainArb.ForceCloseContract(chanPoint)
+	if err != nil {
+		return err
+	}
+
+	peerLog.Infof("Force closed ChannelPoint(%v) with txid %v", chanPoint,
+		closeTx.TxHash())
+
 	return nil
 }
 
@@ -1727,6 +2316,19 @@ func (p *peer) handleInitMsg(msg *lnwire.Init) error {
 	return nil
 }
 
+// cacheRemoteFeatures persists the feature vectors announced by the remote
+// peer, such that the protocol upgrades blocked by the peer's missing
+// features can be reported for each of our channels with it.
+func (p *peer) cacheRemoteFeatures() {
+	err := p.server.chanDB.PutPeerFeatures(
+		p.pubKeyBytes, p.remoteGlobalFeatures.RawFeatureVector,
+		p.remoteLocalFeatures.RawFeatureVector,
+	)
+	if err != nil {
+		peerLog.Errorf("unable to cache features of %v: %v", p, err)
+	}
+}
+
 // sendInitMsg sends init message to remote peer which contains our currently
 // supported local and global features.
 func (p *peer) sendInitMsg() error {
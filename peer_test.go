@@ -608,3 +608,76 @@ func TestPeerChannelClosureFeeNegotiationsInitiator(t *testing.T) {
 		t.Fatalf("closing tx not broadcast")
 	}
 }
+
+// TestMsgStreamBackpressure ensures that adding a message to a full msgStream
+// blocks until the consumer has made space, and that a blocked producer is
+// released once the peer is shutting down.
+func TestMsgStreamBackpressure(t *testing.T) {
+	t.Parallel()
+
+	applied := make(chan lnwire.Message, 10)
+	release := make(chan struct{})
+	p := &peer{quit: make(chan struct{})}
+	stream := newMsgStream(p, "", "", 2, func(msg lnwire.Message) {
+		applied <- msg
+		<-release
+	})
+	stream.Start()
+	defer func() {
+		close(release)
+		stream.Stop()
+	}()
+
+	// addMsg adds a new message to the stream, returning a channel which
+	// is closed once it has been added.
+	addMsg := func() chan struct{} {
+		done := make(chan struct{})
+		go func() {
+			stream.AddMsg(&lnwire.Ping{})
+			close(done)
+		}()
+		return done
+	}
+	assertAdded := func(done chan struct{}) {
+		select {
+		case <-done:
+		case <-time.After(time.Second * 5):
+			t.Fatalf("message not added")
+		}
+	}
+	assertBlocked := func(done chan struct{}) {
+		select {
+		case <-done:
+			t.Fatalf("message added to full stream")
+		case <-time.After(time.Millisecond * 100):
+		}
+	}
+
+	// The first message is taken off the queue by the consumer, which
+	// then blocks while applying it.
+	assertAdded(addMsg())
+	select {
+	case <-applied:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("message not applied")
+	}
+
+	// The next two messages fill the queue, so adding a further message
+	// blocks.
+	assertAdded(addMsg())
+	assertAdded(addMsg())
+	blocked := addMsg()
+	assertBlocked(blocked)
+
+	// Once the consumer takes the next message off the queue, the blocked
+	// message is added, filling the queue once again.
+	release <- struct{}{}
+	assertAdded(blocked)
+
+	// A message blocked on the full queue is dropped once the peer is
+	// shutting down.
+	blocked = addMsg()
+	assertBlocked(blocked)
+	close(p.quit)
+	assertAdded(blocked)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// peerRoutingPolicy is the default fee and time lock policy to be used for
+// new channels with a particular peer, in place of the chain's default
+// routing policy.
+type peerRoutingPolicy struct {
+	// BaseFee is the base fee, in milli-satoshis, charged for forwarding
+	// an HTLC over channels with the peer.
+	BaseFee lnwire.MilliSatoshi
+
+	// FeeRate is the proportional fee, in millionths of the forwarded
+	// amount, charged for forwarding an HTLC over channels with the peer.
+	FeeRate lnwire.MilliSatoshi
+
+	// TimeLockDelta is the time lock delta required when forwarding an
+	// HTLC over channels with the peer.
+	TimeLockDelta uint32
+}
+
+// parsePeerPolicies parses the per-peer default routing policies, each of
+// which is of the form <pubkey>:<base_fee_msat>:<fee_rate>:<time_lock_delta>,
+// returning them keyed by the serialized public key of the peer. The time lock
+// delta of each must be at least the passed minimum.
+func parsePeerPolicies(policies []string,
+	minDelta uint32) (map[[33]byte]peerRoutingPolicy, error) {
+
+	peerPolicies := make(map[[33]byte]peerRoutingPolicy, len(policies))
+	for _, policy := range policies {
+		parts := strings.Split(policy, ":")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("peer policy %v must be of the "+
+				"form <pubkey>:<base_fee_msat>:<fee_rate>:"+
+				"<time_lock_delta>", policy)
+		}
+
+		keyBytes, err := hex.DecodeString(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode public key "+
+				"%v: %v", parts[0], err)
+		}
+		pubKey, err := btcec.ParsePubKey(keyBytes, btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse public key "+
+				"%v: %v", parts[0], err)
+		}
+
+		baseFee, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base fee %v: %v",
+				parts[1], err)
+		}
+		feeRate, err := strconv.ParseUint(parts[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee rate %v: %v",
+				parts[2], err)
+		}
+		timeLockDelta, err := strconv.ParseUint(parts[3], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time lock delta %v: %v",
+				parts[3], err)
+		}
+		if timeLockDelta < uint64(minDelta) {
+			return nil, fmt.Errorf("time lock delta of peer "+
+				"policy %v must be at least %v", policy,
+				minDelta)
+		}
+
+		var peer [33]byte
+		copy(peer[:], pubKey.SerializeCompressed())
+		if _, ok := peerPolicies[peer]; ok {
+			return nil, fmt.Errorf("duplicate policy for peer %x",
+				peer[:])
+		}
+
+		peerPolicies[peer] = peerRoutingPolicy{
+			BaseFee:       lnwire.MilliSatoshi(baseFee),
+			FeeRate:       lnwire.MilliSatoshi(feeRate),
+			TimeLockDelta: uint32(timeLockDelta),
+		}
+	}
+
+	return peerPolicies, nil
+}
+
+// routingPolicyForPeer returns the default routing policy to be used for new
+// channels with the passed peer. If a default policy has been configured for
+// the peer, then its fee and time lock parameters take the place of those of
+// the chain's default routing policy.
+func (c *chainControl) routingPolicyForPeer(
+	peer *btcec.PublicKey) htlcswitch.ForwardingPolicy {
+
+	policy := c.routingPolicy
+
+	var peerKey [33]byte
+	copy(peerKey[:], peer.SerializeCompressed())
+
+	peerPolicy, ok := c.peerRoutingPolicies[peerKey]
+	if !ok {
+		return policy
+	}
+
+	policy.BaseFee = peerPolicy.BaseFee
+	policy.FeeRate = peerPolicy.FeeRate
+	policy.TimeLockDelta = peerPolicy.TimeLockDelta
+
+	return policy
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestPeerRoutingPolicies ensures that per-peer default routing policies are
+// parsed correctly, and that they only override the fee and time lock
+// parameters of the chain's default routing policy for their peer.
+func TestPeerRoutingPolicies(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	peer := priv.PubKey()
+	pubHex := fmt.Sprintf("%x", peer.SerializeCompressed())
+
+	otherPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	peerPolicies, err := parsePeerPolicies([]string{
+		pubHex + ":2000:50:40",
+	}, defaultMinCltvDelta)
+	if err != nil {
+		t.Fatalf("unable to parse peer policies: %v", err)
+	}
+
+	cc := &chainControl{
+		routingPolicy: htlcswitch.ForwardingPolicy{
+			MinHTLC:       1000,
+			BaseFee:       1000,
+			FeeRate:       1,
+			TimeLockDelta: 144,
+		},
+		peerRoutingPolicies: peerPolicies,
+	}
+
+	expected := htlcswitch.ForwardingPolicy{
+		MinHTLC:       1000,
+		BaseFee:       2000,
+		FeeRate:       50,
+		TimeLockDelta: 40,
+	}
+	if policy := cc.routingPolicyForPeer(peer); policy != expected {
+		t.Fatalf("expected policy %v, got %v", expected, policy)
+	}
+
+	// Any other peer should be given the chain's default policy.
+	policy := cc.routingPolicyForPeer(otherPriv.PubKey())
+	if policy != cc.routingPolicy {
+		t.Fatalf("expected default policy %v, got %v",
+			cc.routingPolicy, policy)
+	}
+
+	// Finally, malformed policies should be rejected.
+	invalidPolicies := []string{
+		pubHex + ":2000:50",
+		"zz:2000:50:40",
+		pubHex + ":a:50:40",
+		pubHex + ":2000:50:1",
+	}
+	for _, invalid := range invalidPolicies {
+		_, err := parsePeerPolicies(
+			[]string{invalid}, defaultMinCltvDelta,
+		)
+		if err == nil {
+			t.Fatalf("expected policy %v to be rejected", invalid)
+		}
+	}
+	_, err = parsePeerPolicies([]string{
+		pubHex + ":2000:50:40", pubHex + ":1:1:40",
+	}, defaultMinCltvDelta)
+	if err == nil {
+		t.Fatalf("expected duplicate policies to be rejected")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+const (
+	// neutralPeerScore is the score of a peer we've no HTLC history with.
+	neutralPeerScore = 0.5
+
+	// peerScoreConfidenceUptime is the total uptime of the channels with a
+	// peer at which its score is fully based on their HTLC statistics.
+	// Below it, the score is drawn towards the neutral score, as a short
+	// history isn't indicative of the peer's behaviour.
+	peerScoreConfidenceUptime = 30 * 24 * time.Hour
+
+	// peerScoreRefLatency is the average settle latency at which a peer
+	// receives half of the score attributed to settle latency.
+	peerScoreRefLatency = 5 * time.Second
+
+	// peerScoreLatencyWeight is the weight of the settle latency within
+	// the score of a peer. The remainder is attributed to the ratio of
+	// HTLCs settled rather than failed by the peer.
+	peerScoreLatencyWeight = 0.25
+)
+
+// peerScore derives a score between 0 and 1 for a peer from the lifetime HTLC
+// statistics of all channels we've held with it. Peers which settle most of
+// the HTLCs we offer them, and do so quickly, score highly. The score of a
+// peer with only a brief history is drawn towards the neutral score.
+func peerScore(stats map[wire.OutPoint]*channeldb.ChannelHTLCStats) float64 {
+	var total channeldb.ChannelHTLCStats
+	for _, chanStats := range stats {
+		total.Add(chanStats)
+	}
+
+	if total.NumSettled+total.NumFailed == 0 {
+		return neutralPeerScore
+	}
+
+	// The latency score is only earned through settles, so a peer which
+	// fails all HTLCs receives none.
+	successScore := 1 - total.FailureRatio()
+	var latencyScore float64
+	if total.NumSettled != 0 {
+		latencyScore = float64(peerScoreRefLatency) /
+			float64(peerScoreRefLatency+total.AvgSettleLatency())
+	}
+	score := (1-peerScoreLatencyWeight)*successScore +
+		peerScoreLatencyWeight*latencyScore
+
+	confidence := float64(total.Uptime) / float64(peerScoreConfidenceUptime)
+	if confidence > 1 {
+		confidence = 1
+	}
+
+	return neutralPeerScore + (score-neutralPeerScore)*confidence
+}
+
+// PeerScore returns the score of the target peer, derived from the lifetime
+// HTLC statistics of all channels we've held with it, including those which
+// have since been closed.
+func (s *server) PeerScore(peer *btcec.PublicKey) (float64, error) {
+	var peerKey [33]byte
+	copy(peerKey[:], peer.SerializeCompressed())
+
+	stats, err := s.chanDB.FetchPeerHTLCStats(peerKey)
+	if err != nil {
+		return 0, err
+	}
+
+	return peerScore(stats), nil
+}
+
+// checkPeerScore returns an error if the score of the target peer is below the
+// configured minimum, in which case no new channels should be opened with the
+// peer.
+func (s *server) checkPeerScore(peer *btcec.PublicKey) error {
+	if cfg.MinPeerScore == 0 {
+		return nil
+	}
+
+	score, err := s.PeerScore(peer)
+	if err != nil {
+		return err
+	}
+
+	if score < cfg.MinPeerScore {
+		return fmt.Errorf("peer score %.2f of %x is below the "+
+			"minimum of %.2f", score, peer.SerializeCompressed(),
+			cfg.MinPeerScore)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestPeerScore ensures that the score of a peer reflects the lifetime HTLC
+// statistics of its channels, and is drawn towards the neutral score when its
+// history is brief.
+func TestPeerScore(t *testing.T) {
+	t.Parallel()
+
+	chanA := wire.OutPoint{Hash: chainhash.Hash{1}}
+	chanB := wire.OutPoint{Hash: chainhash.Hash{2}}
+
+	tests := []struct {
+		name  string
+		stats map[wire.OutPoint]*channeldb.ChannelHTLCStats
+		score float64
+	}{
+		{
+			name:  "no history",
+			score: neutralPeerScore,
+		},
+		{
+			name: "no resolved htlcs",
+			stats: map[wire.OutPoint]*channeldb.ChannelHTLCStats{
+				chanA: {
+					NumForwarded: 1,
+					Uptime:       peerScoreConfidenceUptime,
+				},
+			},
+			score: neutralPeerScore,
+		},
+		{
+			// Half of the HTLCs were settled, with an average
+			// latency equal to the reference latency, across two
+			// channels, one of which has since been closed.
+			name: "full confidence",
+			stats: map[wire.OutPoint]*channeldb.ChannelHTLCStats{
+				chanA: {
+					NumSettled:         1,
+					NumFailed:          2,
+					TotalSettleLatency: peerScoreRefLatency,
+					Uptime:             peerScoreConfidenceUptime,
+				},
+				chanB: {
+					NumSettled:         1,
+					TotalSettleLatency: peerScoreRefLatency,
+					Uptime:             peerScoreConfidenceUptime,
+				},
+			},
+			score: 0.5,
+		},
+		{
+			name: "instant settles",
+			stats: map[wire.OutPoint]*channeldb.ChannelHTLCStats{
+				chanA: {
+					NumSettled: 4,
+					Uptime:     peerScoreConfidenceUptime,
+				},
+			},
+			score: 1,
+		},
+		{
+			// With only half of the uptime required for full
+			// confidence, the score of a peer failing all HTLCs
+			// is only halfway between neutral and zero.
+			name: "partial confidence",
+			stats: map[wire.OutPoint]*channeldb.ChannelHTLCStats{
+				chanA: {
+					NumFailed: 4,
+					Uptime:    peerScoreConfidenceUptime / 2,
+				},
+			},
+			score: 0.25,
+		},
+	}
+
+	for _, test := range tests {
+		score := peerScore(test.stats)
+		if math.Abs(score-test.score) > 1e-9 {
+			t.Fatalf("%v: expected score %v, got %v", test.name,
+				test.score, score)
+		}
+	}
+
+	// A peer whose channels have only been up briefly should score close
+	// to neutral, regardless of its statistics.
+	score := peerScore(map[wire.OutPoint]*channeldb.ChannelHTLCStats{
+		chanA: {
+			NumFailed: 10,
+			Uptime:    time.Minute,
+		},
+	})
+	if math.Abs(score-neutralPeerScore) > 0.01 {
+		t.Fatalf("expected near neutral score, got %v", score)
+	}
+}
@@ -0,0 +1,429 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// peerStorageVersion is the version of the encoding used for the
+	// plaintext of the blob we ask our peers to store on our behalf.
+	peerStorageVersion = 0
+
+	// peerStorageHeaderSize is the size of the header of our plaintext
+	// blob: a 1-byte version, an 8-byte unix timestamp, and a 2-byte
+	// channel count.
+	peerStorageHeaderSize = 1 + 8 + 2
+
+	// peerStorageChanSize is the size of each channel entry within our
+	// plaintext blob: the funding outpoint, the remote node's public key
+	// and the channel's capacity.
+	peerStorageChanSize = 32 + 4 + 33 + 8
+
+	// maxPeerStorageChans is the maximum number of channels that can be
+	// included within our blob, such that the encrypted blob fits within
+	// a single PeerStorage message.
+	maxPeerStorageChans = (lnwire.MaxPeerStorageBlobSize -
+		chacha20poly1305.NonceSize - chacha20poly1305.Overhead -
+		peerStorageHeaderSize) / peerStorageChanSize
+
+	// peerStorageExpiry is the amount of time after which we'll delete a
+	// blob held on behalf of a peer that we no longer have any channels
+	// with.
+	peerStorageExpiry = time.Hour * 24 * 14
+
+	// peerStoragePruneInterval is how often we'll check for expired blobs.
+	peerStoragePruneInterval = time.Hour * 24
+)
+
+// peerStorageKeyTag is mixed with our identity private key in order to derive
+// the key used to encrypt the blobs we ask our peers to store.
+var peerStorageKeyTag = []byte("lnd peer storage")
+
+// peerStorageChan is a single channel recorded within our peer storage blob.
+// It contains the information required to locate the channel on-chain and
+// contact the remote party should we lose our local state.
+type peerStorageChan struct {
+	chanPoint wire.OutPoint
+	remotePub [33]byte
+	capacity  btcutil.Amount
+}
+
+// peerStorage implements the peer storage feature: we hold a small encrypted
+// blob on behalf of each of our channel peers, returning it to them when they
+// reconnect, and in turn they hold a blob for us. Our blob records our set of
+// open channels, so we're able to locate them should we lose our local state.
+type peerStorage struct {
+	db *channeldb.DB
+
+	// key is the symmetric key used to encrypt our own blob.
+	key [32]byte
+
+	// quota is the maximum size of a blob we'll store for a peer.
+	quota int
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// newPeerStorage creates a new peerStorage instance backed by the passed
+// database. Our own blob is encrypted with a key derived from our identity
+// key, and we'll refuse to store blobs larger than quota for our peers.
+func newPeerStorage(db *channeldb.DB, identityPriv *btcec.PrivateKey,
+	quota int) *peerStorage {
+
+	if quota <= 0 || quota > lnwire.MaxPeerStorageBlobSize {
+		quota = lnwire.MaxPeerStorageBlobSize
+	}
+
+	h := sha256.New()
+	h.Write(identityPriv.Serialize())
+	h.Write(peerStorageKeyTag)
+
+	ps := &peerStorage{
+		db:    db,
+		quota: quota,
+		quit:  make(chan struct{}),
+	}
+	copy(ps.key[:], h.Sum(nil))
+
+	return ps
+}
+
+// Start launches the goroutine which periodically deletes expired blobs.
+func (ps *peerStorage) Start() error {
+	ps.wg.Add(1)
+	go ps.pruner()
+
+	return nil
+}
+
+// Stop signals the peerStorage to exit, and waits for it to do so.
+func (ps *peerStorage) Stop() error {
+	close(ps.quit)
+	ps.wg.Wait()
+
+	return nil
+}
+
+// pruner deletes any blobs held on behalf of peers that we no longer have any
+// channels with, and that haven't been updated in peerStorageExpiry.
+//
+// NOTE: This MUST be run as a goroutine.
+func (ps *peerStorage) pruner() {
+	defer ps.wg.Done()
+
+	ticker := time.NewTicker(peerStoragePruneInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := ps.prune(); err != nil {
+			peerLog.Errorf("Unable to prune peer storage: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ps.quit:
+			return
+		}
+	}
+}
+
+// prune deletes all expired blobs.
+func (ps *peerStorage) prune() error {
+	stored, err := ps.db.ListPeerStorage()
+	if err != nil {
+		return err
+	}
+
+	for peer, storage := range stored {
+		if time.Since(storage.LastUpdate) < peerStorageExpiry {
+			continue
+		}
+
+		pub, err := btcec.ParsePubKey(peer[:], btcec.S256())
+		if err != nil {
+			return err
+		}
+		chans, err := ps.db.FetchOpenChannels(pub)
+		if err != nil {
+			return err
+		}
+		if len(chans) != 0 {
+			continue
+		}
+
+		peerLog.Infof("Deleting expired peer storage for %x, last "+
+			"updated %v", peer[:], storage.LastUpdate)
+
+		if err := ps.db.DeletePeerStorage(peer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// peerOnline is called once a peer that we've advertised the peer storage
+// feature to has come online. We'll return any blob held on its behalf, and
+// provide it with our own blob if it also supports the feature.
+func (ps *peerStorage) peerOnline(p *peer) {
+	storage, err := ps.db.FetchPeerStorage(p.PubKey())
+	switch {
+	case err == channeldb.ErrPeerStorageNotFound:
+	case err != nil:
+		peerLog.Errorf("Unable to fetch peer storage for %v: %v", p,
+			err)
+	default:
+		peerLog.Debugf("Returning peer storage blob of size %v, last "+
+			"updated %v, to %v", len(storage.Blob),
+			storage.LastUpdate, p)
+
+		p.queueMsg(lnwire.NewPeerStorageRetrieval(storage.Blob), nil)
+	}
+
+	if err := ps.sendBlob(p); err != nil {
+		peerLog.Errorf("Unable to send peer storage to %v: %v", p, err)
+	}
+}
+
+// sendBlob provides the peer with the latest version of our own blob, if it
+// supports the peer storage feature, and we have a channel with it.
+func (ps *peerStorage) sendBlob(p *peer) error {
+	if !p.remoteLocalFeatures.HasFeature(lnwire.ProvideStorageOptional) {
+		return nil
+	}
+	if len(p.ChannelSnapshots()) == 0 {
+		return nil
+	}
+
+	blob, err := ps.ourBlob()
+	if err != nil {
+		return err
+	}
+
+	p.queueMsg(lnwire.NewPeerStorage(blob), nil)
+
+	return nil
+}
+
+// handlePeerStorage stores the blob within a PeerStorage message on behalf of
+// the sending peer, replacing any prior blob. The blob is only stored if we
+// have a channel with the peer, and it falls within our quota.
+func (ps *peerStorage) handlePeerStorage(p *peer, msg *lnwire.PeerStorage) {
+	if len(p.ChannelSnapshots()) == 0 {
+		peerLog.Debugf("Ignoring peer storage from %v, as we have no "+
+			"channels with it", p)
+		return
+	}
+	if len(msg.Blob) > ps.quota {
+		peerLog.Warnf("Ignoring peer storage from %v, blob of size %v "+
+			"exceeds quota of %v", p, len(msg.Blob), ps.quota)
+		return
+	}
+
+	if err := ps.db.PutPeerStorage(p.PubKey(), msg.Blob); err != nil {
+		peerLog.Errorf("Unable to store peer storage for %v: %v", p,
+			err)
+	}
+}
+
+// handlePeerStorageRetrieval processes our blob as returned by a peer. Any
+// channels within the blob that are unknown to us are reported, as they
+// indicate that we've lost local state. If the blob doesn't reflect our
+// current set of channels, then the peer is provided with an up to date blob.
+func (ps *peerStorage) handlePeerStorageRetrieval(p *peer,
+	msg *lnwire.PeerStorageRetrieval) {
+
+	timestamp, stored, err := ps.decryptBlob(msg.Blob)
+	if err != nil {
+		peerLog.Warnf("Unable to decrypt peer storage returned by "+
+			"%v: %v", p, err)
+		return
+	}
+
+	current, err := ps.openChannels()
+	if err != nil {
+		peerLog.Errorf("Unable to fetch open channels: %v", err)
+		return
+	}
+	known := make(map[wire.OutPoint]struct{}, len(current))
+	for _, c := range current {
+		known[c.chanPoint] = struct{}{}
+	}
+
+	var numMissing int
+	for _, c := range stored {
+		if _, ok := known[c.chanPoint]; ok {
+			continue
+		}
+
+		numMissing++
+
+		// The channel may have since been closed, in which case we'll
+		// still have a record of it.
+		_, err := ps.db.FetchClosedChannel(&c.chanPoint)
+		if err == nil {
+			continue
+		}
+
+		peerLog.Warnf("Peer storage returned by %v contains "+
+			"ChannelPoint(%v) with peer %x of capacity %v, which "+
+			"is unknown locally: local channel state may have "+
+			"been lost", p, c.chanPoint, c.remotePub[:],
+			c.capacity)
+	}
+
+	// If the set of channels within the blob doesn't match our current
+	// set, then the peer holds a stale blob, so we'll send it an update.
+	if numMissing == 0 && len(stored) == len(current) {
+		peerLog.Debugf("Peer storage returned by %v, created %v, is "+
+			"up to date", p, timestamp)
+		return
+	}
+
+	peerLog.Infof("Peer storage returned by %v, created %v, is stale",
+		p, timestamp)
+
+	if err := ps.sendBlob(p); err != nil {
+		peerLog.Errorf("Unable to send peer storage to %v: %v", p, err)
+	}
+}
+
+// openChannels returns the set of open channels to be recorded within our
+// blob.
+func (ps *peerStorage) openChannels() ([]peerStorageChan, error) {
+	dbChans, err := ps.db.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	chans := make([]peerStorageChan, 0, len(dbChans))
+	for _, dbChan := range dbChans {
+		c := peerStorageChan{
+			chanPoint: dbChan.FundingOutpoint,
+			capacity:  dbChan.Capacity,
+		}
+		copy(c.remotePub[:], dbChan.IdentityPub.SerializeCompressed())
+
+		chans = append(chans, c)
+	}
+
+	return chans, nil
+}
+
+// ourBlob creates an encrypted blob recording our current set of open
+// channels. If we have more channels than can fit within a single message,
+// then only the first maxPeerStorageChans are recorded.
+func (ps *peerStorage) ourBlob() ([]byte, error) {
+	chans, err := ps.openChannels()
+	if err != nil {
+		return nil, err
+	}
+	if len(chans) > maxPeerStorageChans {
+		peerLog.Warnf("Only %v of %v channels will be included in "+
+			"peer storage", maxPeerStorageChans, len(chans))
+		chans = chans[:maxPeerStorageChans]
+	}
+
+	var b bytes.Buffer
+	b.WriteByte(peerStorageVersion)
+
+	var scratch [8]byte
+	binary.BigEndian.PutUint64(scratch[:], uint64(time.Now().Unix()))
+	b.Write(scratch[:])
+	binary.BigEndian.PutUint16(scratch[:2], uint16(len(chans)))
+	b.Write(scratch[:2])
+
+	for _, c := range chans {
+		b.Write(c.chanPoint.Hash[:])
+		binary.BigEndian.PutUint32(scratch[:4], c.chanPoint.Index)
+		b.Write(scratch[:4])
+		b.Write(c.remotePub[:])
+		binary.BigEndian.PutUint64(scratch[:], uint64(c.capacity))
+		b.Write(scratch[:])
+	}
+
+	cipher, err := chacha20poly1305.New(ps.key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+
+	return cipher.Seal(nonce[:], nonce[:], b.Bytes(), nil), nil
+}
+
+// decryptBlob decrypts and decodes one of our own blobs, returning the time
+// at which it was created, along with the channels recorded within it.
+func (ps *peerStorage) decryptBlob(blob []byte) (time.Time,
+	[]peerStorageChan, error) {
+
+	if len(blob) < chacha20poly1305.NonceSize {
+		return time.Time{}, nil, fmt.Errorf("blob of size %v is too "+
+			"short", len(blob))
+	}
+
+	cipher, err := chacha20poly1305.New(ps.key[:])
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	nonce := blob[:chacha20poly1305.NonceSize]
+	plaintext, err := cipher.Open(
+		nil, nonce, blob[chacha20poly1305.NonceSize:], nil,
+	)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	r := bytes.NewReader(plaintext)
+
+	var header [peerStorageHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return time.Time{}, nil, err
+	}
+	if header[0] != peerStorageVersion {
+		return time.Time{}, nil, fmt.Errorf("unknown peer storage "+
+			"version %v", header[0])
+	}
+	timestamp := time.Unix(int64(binary.BigEndian.Uint64(header[1:9])), 0)
+	numChans := binary.BigEndian.Uint16(header[9:])
+
+	chans := make([]peerStorageChan, numChans)
+	for i := range chans {
+		var entry [peerStorageChanSize]byte
+		if _, err := io.ReadFull(r, entry[:]); err != nil {
+			return time.Time{}, nil, err
+		}
+
+		var hash chainhash.Hash
+		copy(hash[:], entry[:32])
+		chans[i].chanPoint = wire.OutPoint{
+			Hash:  hash,
+			Index: binary.BigEndian.Uint32(entry[32:36]),
+		}
+		copy(chans[i].remotePub[:], entry[36:69])
+		chans[i].capacity = btcutil.Amount(
+			binary.BigEndian.Uint64(entry[69:]),
+		)
+	}
+
+	return timestamp, chans, nil
+}
@@ -31,6 +31,12 @@ func (c *chanController) OpenChannel(target *btcec.PublicKey,
 			"address")
 	}
 
+	// We'll also refrain from opening channels to peers whose channels
+	// have performed poorly in the past.
+	if err := c.server.checkPeerScore(target); err != nil {
+		return err
+	}
+
 	// First, we'll check if we're already connected to the target peer. If
 	// not, then we'll need to establish a connection.
 	if _, err := c.server.FindPeer(target); err != nil {
@@ -94,7 +100,7 @@ func (c *chanController) OpenChannel(target *btcec.PublicKey,
 	minHtlc := lnwire.NewMSatFromSatoshis(1)
 
 	updateStream, errChan := c.server.OpenChannel(-1, target, amt, 0,
-		minHtlc, feePerWeight, false)
+		minHtlc, 0, feePerWeight, false, 0)
 
 	select {
 	case err := <-errChan:
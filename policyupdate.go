@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// minFeeRate is the smallest permitted fee rate within the network. This is
+// dervied by the fact that fee rates are computed using a fixed point of
+// 1,000,000. As a result, the smallest representable fee rate is 1e-6, or
+// 0.000001, or 0.0001%.
+const minFeeRate = 1e-6
+
+// maxTimeLockDelta is the largest time lock delta that can be advertised for
+// a channel, as it's encoded as a uint16 within channel updates.
+const maxTimeLockDelta = math.MaxUint16
+
+// validatePolicyUpdate ensures that the policy of the passed update is one
+// we're able to advertise, independently of the channels it targets. The time
+// lock delta must be at least minCltvDelta.
+func validatePolicyUpdate(req *lnrpc.PolicyUpdateRequest,
+	minCltvDelta uint32) error {
+
+	// As a sanity check, we'll ensure that the passed fee rate is below
+	// 1e-6, or the lowest allowed fee rate, and that the passed timelock
+	// is within the bounds we're able to advertise.
+	if req.FeeRate < minFeeRate {
+		return fmt.Errorf("fee rate of %v is too small, min fee "+
+			"rate is %v", req.FeeRate, minFeeRate)
+	}
+
+	if req.TimeLockDelta < minCltvDelta {
+		return fmt.Errorf("time lock delta of %v is too small, "+
+			"minimum supported is %v", req.TimeLockDelta,
+			minCltvDelta)
+	}
+	if req.TimeLockDelta > maxTimeLockDelta {
+		return fmt.Errorf("time lock delta of %v is too large, "+
+			"maximum supported is %v", req.TimeLockDelta,
+			maxTimeLockDelta)
+	}
+
+	return nil
+}
+
+// policyUpdateTargets resolves the scope of a policy update into the channels
+// it targets amongst the passed channels, which include our pending ones.
+// Channels targeted by their channel point that we don't have open, or which
+// are still pending, are returned as failed updates, rather than failing the
+// entire request.
+func policyUpdateTargets(req *lnrpc.PolicyUpdateRequest,
+	channels []*channeldb.OpenChannel) ([]*channeldb.OpenChannel,
+	[]*lnrpc.FailedUpdate, error) {
+
+	var chanPoints []*lnrpc.ChannelPoint
+	switch scope := req.Scope.(type) {
+	// If the request is targeting all active channels, then we'll target
+	// each of our open channels.
+	case *lnrpc.PolicyUpdateRequest_Global:
+		var targets []*channeldb.OpenChannel
+		for _, channel := range channels {
+			if channel.IsPending {
+				continue
+			}
+			targets = append(targets, channel)
+		}
+
+		return targets, nil, nil
+
+	// If the request is targeting a peer, then we'll target each of our
+	// open channels with it.
+	case *lnrpc.PolicyUpdateRequest_PeerPubKey:
+		pubKeyBytes, err := hex.DecodeString(scope.PeerPubKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to decode peer "+
+				"pubkey: %v", err)
+		}
+		pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to parse peer "+
+				"pubkey: %v", err)
+		}
+		peer := pubKey.SerializeCompressed()
+
+		var targets []*channeldb.OpenChannel
+		for _, channel := range channels {
+			if channel.IsPending || !bytes.Equal(
+				channel.IdentityPub.SerializeCompressed(), peer,
+			) {
+				continue
+			}
+			targets = append(targets, channel)
+		}
+		if len(targets) == 0 {
+			return nil, nil, fmt.Errorf("no open channels with "+
+				"peer %x", peer)
+		}
+
+		return targets, nil, nil
+
+	// Otherwise, we're targeting individual channels by their channel
+	// point.
+	case *lnrpc.PolicyUpdateRequest_ChanPoint:
+		chanPoints = append(chanPoints, scope.ChanPoint)
+
+	case *lnrpc.PolicyUpdateRequest_ChanPoints:
+		chanPoints = scope.ChanPoints.GetChanPoints()
+		if len(chanPoints) == 0 {
+			return nil, nil, fmt.Errorf("no channel points " +
+				"specified")
+		}
+
+	default:
+		return nil, nil, fmt.Errorf("unknown scope: %v", scope)
+	}
+
+	chansByPoint := make(map[wire.OutPoint]*channeldb.OpenChannel)
+	for _, channel := range channels {
+		chansByPoint[channel.FundingOutpoint] = channel
+	}
+
+	var (
+		targets       []*channeldb.OpenChannel
+		failedUpdates []*lnrpc.FailedUpdate
+	)
+	for _, chanPoint := range chanPoints {
+		txid, err := chainhash.NewHash(chanPoint.FundingTxid)
+		if err != nil {
+			return nil, nil, err
+		}
+		outPoint := wire.OutPoint{
+			Hash:  *txid,
+			Index: chanPoint.OutputIndex,
+		}
+
+		channel, ok := chansByPoint[outPoint]
+		switch {
+		case !ok:
+			failedUpdates = append(failedUpdates, newFailedUpdate(
+				outPoint, "channel not found",
+			))
+
+		case channel.IsPending:
+			failedUpdates = append(failedUpdates, newFailedUpdate(
+				outPoint, "channel is pending",
+			))
+
+		default:
+			targets = append(targets, channel)
+		}
+	}
+
+	return targets, failedUpdates, nil
+}
+
+// policyUpdateChans returns the channel points of the passed target channels
+// which are able to carry the policy of the passed update. The channels
+// which can't are excluded from the update, and returned as failed updates.
+func policyUpdateChans(req *lnrpc.PolicyUpdateRequest,
+	targets []*channeldb.OpenChannel) ([]wire.OutPoint,
+	[]*lnrpc.FailedUpdate) {
+
+	var (
+		validChans    []wire.OutPoint
+		failedUpdates []*lnrpc.FailedUpdate
+	)
+	for _, channel := range targets {
+		chanPoint := channel.FundingOutpoint
+
+		capacity := lnwire.NewMSatFromSatoshis(channel.Capacity)
+		if lnwire.MilliSatoshi(req.MaxHtlcMsat) > capacity {
+			failedUpdates = append(failedUpdates, newFailedUpdate(
+				chanPoint, fmt.Sprintf("max htlc of %v "+
+					"exceeds channel capacity of %v",
+					lnwire.MilliSatoshi(req.MaxHtlcMsat),
+					capacity),
+			))
+			continue
+		}
+
+		validChans = append(validChans, chanPoint)
+	}
+
+	return validChans, failedUpdates
+}
+
+// newFailedUpdate returns the failed update reporting that a policy update
+// couldn't be applied to the channel with the given channel point.
+func newFailedUpdate(chanPoint wire.OutPoint,
+	reason string) *lnrpc.FailedUpdate {
+
+	return &lnrpc.FailedUpdate{
+		Outpoint: &lnrpc.ChannelPoint{
+			FundingTxid: chanPoint.Hash[:],
+			OutputIndex: chanPoint.Index,
+		},
+		UpdateError: reason,
+	}
+}
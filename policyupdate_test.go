@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// newPolicyTestChannel returns a channel with the passed peer, funding
+// outpoint index and capacity, to be targeted by policy updates.
+func newPolicyTestChannel(peer *btcec.PublicKey, index uint32,
+	capacity btcutil.Amount, pending bool) *channeldb.OpenChannel {
+
+	return &channeldb.OpenChannel{
+		IdentityPub:     peer,
+		FundingOutpoint: wire.OutPoint{Hash: [32]byte{1}, Index: index},
+		Capacity:        capacity,
+		IsPending:       pending,
+	}
+}
+
+// rpcChanPoint returns the RPC representation of the passed outpoint.
+func rpcChanPoint(outPoint wire.OutPoint) *lnrpc.ChannelPoint {
+	return &lnrpc.ChannelPoint{
+		FundingTxid: outPoint.Hash[:],
+		OutputIndex: outPoint.Index,
+	}
+}
+
+// TestValidatePolicyUpdate ensures that policy updates are only accepted if
+// their fee rate and time lock delta are within the bounds we're able to
+// advertise.
+func TestValidatePolicyUpdate(t *testing.T) {
+	t.Parallel()
+
+	const minCltvDelta = 9
+
+	tests := []struct {
+		name          string
+		feeRate       float64
+		timeLockDelta uint32
+		valid         bool
+	}{
+		{
+			name:          "valid policy",
+			feeRate:       0.0001,
+			timeLockDelta: 144,
+			valid:         true,
+		},
+		{
+			name:          "fee rate too small",
+			feeRate:       minFeeRate / 2,
+			timeLockDelta: 144,
+		},
+		{
+			name:          "time lock delta too small",
+			feeRate:       0.0001,
+			timeLockDelta: minCltvDelta - 1,
+		},
+		{
+			name:          "time lock delta too large",
+			feeRate:       0.0001,
+			timeLockDelta: maxTimeLockDelta + 1,
+		},
+	}
+
+	for _, test := range tests {
+		req := &lnrpc.PolicyUpdateRequest{
+			FeeRate:       test.feeRate,
+			TimeLockDelta: test.timeLockDelta,
+		}
+		err := validatePolicyUpdate(req, minCltvDelta)
+		if test.valid != (err == nil) {
+			t.Fatalf("%v: expected valid %v, got error %v",
+				test.name, test.valid, err)
+		}
+	}
+}
+
+// TestPolicyUpdateTargets ensures that each scope of a policy update resolves
+// to the expected channels, and that channels targeted by their channel point
+// which are pending or unknown are reported as failed updates.
+func TestPolicyUpdateTargets(t *testing.T) {
+	t.Parallel()
+
+	alicePriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	bobPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	carolPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	alice, bob, carol := alicePriv.PubKey(), bobPriv.PubKey(),
+		carolPriv.PubKey()
+
+	aliceChan := newPolicyTestChannel(alice, 0, 100000, false)
+	bobChan := newPolicyTestChannel(bob, 1, 100000, false)
+	bobPending := newPolicyTestChannel(bob, 2, 100000, true)
+	carolPending := newPolicyTestChannel(carol, 3, 100000, true)
+	channels := []*channeldb.OpenChannel{
+		aliceChan, bobChan, bobPending, carolPending,
+	}
+
+	unknownPoint := wire.OutPoint{Hash: [32]byte{2}, Index: 0}
+	peerScope := func(pub string) *lnrpc.PolicyUpdateRequest {
+		return &lnrpc.PolicyUpdateRequest{
+			Scope: &lnrpc.PolicyUpdateRequest_PeerPubKey{
+				PeerPubKey: pub,
+			},
+		}
+	}
+	chanPointsScope := func(
+		chanPoints ...wire.OutPoint) *lnrpc.PolicyUpdateRequest {
+
+		var rpcChanPoints []*lnrpc.ChannelPoint
+		for _, chanPoint := range chanPoints {
+			rpcChanPoints = append(
+				rpcChanPoints, rpcChanPoint(chanPoint),
+			)
+		}
+
+		return &lnrpc.PolicyUpdateRequest{
+			Scope: &lnrpc.PolicyUpdateRequest_ChanPoints{
+				ChanPoints: &lnrpc.ChannelPointList{
+					ChanPoints: rpcChanPoints,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		req           *lnrpc.PolicyUpdateRequest
+		targets       []*channeldb.OpenChannel
+		failedUpdates []*lnrpc.FailedUpdate
+		expectErr     bool
+	}{
+		{
+			name: "global",
+			req: &lnrpc.PolicyUpdateRequest{
+				Scope: &lnrpc.PolicyUpdateRequest_Global{
+					Global: true,
+				},
+			},
+			targets: []*channeldb.OpenChannel{aliceChan, bobChan},
+		},
+		{
+			name: "peer",
+			req: peerScope(
+				hex.EncodeToString(bob.SerializeCompressed()),
+			),
+			targets: []*channeldb.OpenChannel{bobChan},
+		},
+		{
+			name: "peer with only pending channels",
+			req: peerScope(
+				hex.EncodeToString(carol.SerializeCompressed()),
+			),
+			expectErr: true,
+		},
+		{
+			name:      "invalid peer",
+			req:       peerScope("zz"),
+			expectErr: true,
+		},
+		{
+			name: "chan point",
+			req: &lnrpc.PolicyUpdateRequest{
+				Scope: &lnrpc.PolicyUpdateRequest_ChanPoint{
+					ChanPoint: rpcChanPoint(
+						aliceChan.FundingOutpoint,
+					),
+				},
+			},
+			targets: []*channeldb.OpenChannel{aliceChan},
+		},
+		{
+			name: "chan points",
+			req: chanPointsScope(
+				bobChan.FundingOutpoint,
+				bobPending.FundingOutpoint, unknownPoint,
+			),
+			targets: []*channeldb.OpenChannel{bobChan},
+			failedUpdates: []*lnrpc.FailedUpdate{
+				newFailedUpdate(
+					bobPending.FundingOutpoint,
+					"channel is pending",
+				),
+				newFailedUpdate(
+					unknownPoint, "channel not found",
+				),
+			},
+		},
+		{
+			name:      "no chan points",
+			req:       chanPointsScope(),
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		targets, failedUpdates, err := policyUpdateTargets(
+			test.req, channels,
+		)
+		if test.expectErr {
+			if err == nil {
+				t.Fatalf("%v: expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: unable to resolve targets: %v",
+				test.name, err)
+		}
+
+		if !reflect.DeepEqual(targets, test.targets) {
+			t.Fatalf("%v: expected targets %v, got %v", test.name,
+				test.targets, targets)
+		}
+		if !reflect.DeepEqual(failedUpdates, test.failedUpdates) {
+			t.Fatalf("%v: expected failed updates %v, got %v",
+				test.name, test.failedUpdates, failedUpdates)
+		}
+	}
+}
+
+// TestPolicyUpdateChans ensures that a policy update is only applied to the
+// targeted channels able to carry its max HTLC, while the others are
+// rejected as failed updates.
+func TestPolicyUpdateChans(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	largeChan := newPolicyTestChannel(priv.PubKey(), 0, 100000, false)
+	smallChan := newPolicyTestChannel(priv.PubKey(), 1, 1000, false)
+	targets := []*channeldb.OpenChannel{largeChan, smallChan}
+
+	// A max HTLC within the capacity of both channels allows the update
+	// to be applied to both.
+	req := &lnrpc.PolicyUpdateRequest{
+		MaxHtlcMsat: 1000 * 1000,
+	}
+	validChans, failedUpdates := policyUpdateChans(req, targets)
+	expected := []wire.OutPoint{
+		largeChan.FundingOutpoint, smallChan.FundingOutpoint,
+	}
+	if !reflect.DeepEqual(validChans, expected) {
+		t.Fatalf("expected valid channels %v, got %v", expected,
+			validChans)
+	}
+	if len(failedUpdates) != 0 {
+		t.Fatalf("expected no failed updates, got %v", failedUpdates)
+	}
+
+	// Once the max HTLC exceeds the capacity of the smaller channel, it's
+	// rejected while the larger one is still allowed.
+	req.MaxHtlcMsat = 1001 * 1000
+	validChans, failedUpdates = policyUpdateChans(req, targets)
+	expected = []wire.OutPoint{largeChan.FundingOutpoint}
+	if !reflect.DeepEqual(validChans, expected) {
+		t.Fatalf("expected valid channels %v, got %v", expected,
+			validChans)
+	}
+	if len(failedUpdates) != 1 {
+		t.Fatalf("expected 1 failed update, got %v",
+			len(failedUpdates))
+	}
+	rejected := failedUpdates[0].Outpoint
+	if rejected.OutputIndex != smallChan.FundingOutpoint.Index {
+		t.Fatalf("expected channel %v to be rejected, got index %v",
+			smallChan.FundingOutpoint, rejected.OutputIndex)
+	}
+}
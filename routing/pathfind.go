@@ -372,6 +372,73 @@ func newRoute(amtToSend lnwire.MilliSatoshi, sourceVertex Vertex,
 	return route, nil
 }
 
+// NewRouteFromHops creates a new route from a set of hops which have been
+// fully specified by the caller, such as by an external path-finder, rather
+// than computed from the channel graph. The HTLC extended to the first hop
+// carries the passed total time lock. An error is returned if the amounts
+// and time locks of consecutive hops are inconsistent, as the payment would
+// be rejected by an intermediate node.
+func NewRouteFromHops(totalTimeLock uint32, hops []*Hop) (*Route, error) {
+	if len(hops) == 0 {
+		return nil, fmt.Errorf("route must contain at least one hop")
+	}
+
+	route := &Route{
+		Hops:          hops,
+		TotalTimeLock: totalTimeLock,
+		TotalAmount:   hops[0].AmtToForward + hops[0].Fee,
+		nodeIndex:     make(map[Vertex]struct{}),
+		chanIndex:     make(map[uint64]struct{}),
+		nextHopMap:    make(map[Vertex]*ChannelHop),
+		prevHopMap:    make(map[Vertex]*ChannelHop),
+	}
+	if hops[0].OutgoingTimeLock > totalTimeLock {
+		return nil, fmt.Errorf("time lock of first hop exceeds total "+
+			"time lock of %v", totalTimeLock)
+	}
+
+	for i, hop := range hops {
+		channel := hop.Channel
+		if channel == nil || channel.ChannelEdgePolicy == nil ||
+			channel.Node == nil || channel.Node.PubKey == nil {
+
+			return nil, fmt.Errorf("hop %v has no channel or "+
+				"node", i)
+		}
+
+		v := NewVertex(hop.Channel.Node.PubKey)
+		route.nodeIndex[v] = struct{}{}
+		route.chanIndex[hop.Channel.ChannelID] = struct{}{}
+		route.prevHopMap[v] = hop.Channel
+		route.TotalFees += hop.Fee
+
+		// The final hop is paid directly, so it mustn't take a fee.
+		if i == len(hops)-1 {
+			if hop.Fee != 0 {
+				return nil, fmt.Errorf("final hop must not " +
+					"carry a fee")
+			}
+			continue
+		}
+
+		// Otherwise, each hop must forward exactly the amount, and
+		// time lock, that the next hop expects to receive.
+		next := hops[i+1]
+		route.nextHopMap[v] = next.Channel
+		if hop.AmtToForward != next.AmtToForward+next.Fee {
+			return nil, fmt.Errorf("hop %v forwards %v, but hop "+
+				"%v requires %v", i, hop.AmtToForward, i+1,
+				next.AmtToForward+next.Fee)
+		}
+		if hop.OutgoingTimeLock < next.OutgoingTimeLock {
+			return nil, fmt.Errorf("time lock of hop %v is lower "+
+				"than that of hop %v", i, i+1)
+		}
+	}
+
+	return route, nil
+}
+
 // Vertex is a simple alias for the serialization of a compressed Bitcoin
 // public key.
 type Vertex [33]byte
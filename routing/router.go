@@ -2,6 +2,7 @@ package routing
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"runtime"
 	"sort"
@@ -133,6 +134,14 @@ type Config struct {
 	SendToSwitch func(firstHop *btcec.PublicKey, htlcAdd *lnwire.UpdateAddHTLC,
 		circuit *sphinx.Circuit) ([sha256.Size]byte, error)
 
+	// SendToLink is a function that directs a link-layer switch to
+	// forward a fully encoded payment over the link of the passed channel,
+	// rather than over any link to the first hop. A non-nil error is to
+	// be returned if the payment was unsuccessful.
+	SendToLink func(chanID lnwire.ShortChannelID,
+		htlcAdd *lnwire.UpdateAddHTLC,
+		circuit *sphinx.Circuit) ([sha256.Size]byte, error)
+
 	// ChannelPruneExpiry is the duration used to determine if a channel
 	// should be pruned or not. If the delta between now and when the
 	// channel was last updated is greater than ChannelPruneExpiry, then
@@ -1279,9 +1288,15 @@ func (r *ChannelRouter) FindRoutes(target *btcec.PublicKey,
 // generateSphinxPacket generates then encodes a sphinx packet which encodes
 // the onion route specified by the passed layer 3 route. The blob returned
 // from this function can immediately be included within an HTLC add packet to
-// be sent to the first hop within the route.
-func generateSphinxPacket(route *Route, paymentHash []byte) ([]byte,
-	*sphinx.Circuit, error) {
+// be sent to the first hop within the route. If records destined to the final
+// hop are passed, such as the preimage of a keysend payment or the record of
+// a stateless invoice, then they're included within additional payloads
+// destined to the final hop. As these payloads count towards the HopLimit, an
+// error is returned if the route along with them exceeds it.
+func generateSphinxPacket(route *Route, paymentHash []byte,
+	finalRecords *htlcswitch.FinalHopRecords) ([]byte, *sphinx.Circuit,
+	error) {
+
 	// First obtain all the public keys along the route which are contained
 	// in each hop.
 	nodes := make([]*btcec.PublicKey, len(route.Hops))
@@ -1302,6 +1317,33 @@ func generateSphinxPacket(route *Route, paymentHash []byte) ([]byte,
 	// properly forward the payment.
 	hopPayloads := route.ToHopPayloads()
 
+	// If there are records destined to the final hop, then it's directed
+	// to process the onion further, revealing the payloads which carry
+	// them.
+	if finalRecords != nil {
+		recordPayloads, err := htlcswitch.NewFinalHopData(finalRecords)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		finalHop := len(hopPayloads) - 1
+		binary.BigEndian.PutUint64(
+			hopPayloads[finalHop].NextAddress[:],
+			htlcswitch.FinalRecordsHop.ToUint64(),
+		)
+		for _, payload := range recordPayloads {
+			hopPayloads = append(hopPayloads, payload)
+			nodes = append(nodes, nodes[finalHop])
+		}
+	}
+
+	if len(hopPayloads) > HopLimit {
+		return nil, nil, newErrf(ErrMaxHopsExceeded, "route of %v "+
+			"hops requires %v onion payloads including those "+
+			"destined to the final hop, exceeding the limit of %v",
+			len(route.Hops), len(hopPayloads), HopLimit)
+	}
+
 	log.Tracef("Constructed per-hop payloads for payment_hash=%x: %v",
 		paymentHash[:], spew.Sdump(hopPayloads))
 
@@ -1361,9 +1403,123 @@ type LightningPayment struct {
 	// used.
 	FinalCLTVDelta *uint16
 
+	// KeysendPreimage, if set, is the preimage of the payment hash. It's
+	// included within the onion, allowing the target to settle the
+	// payment without an invoice, provided it accepts our experimental
+	// keysend payments.
+	KeysendPreimage *[32]byte
+
+	// StatelessRecord, if set, is the record of the stateless invoice
+	// being paid. It's included within the onion, allowing the target to
+	// reconstruct the invoice, which it doesn't store.
+	StatelessRecord *htlcswitch.StatelessRecord
+
+	// PaymentAddr, if set, is the payment secret of the invoice being
+	// paid. It's included within the onion, proving to the target that
+	// we know the invoice.
+	PaymentAddr *[32]byte
+
 	// TODO(roasbeef): add e2e message?
 }
 
+// finalHopRecords returns the records to include within the onion of the
+// payment which are destined to its target, or nil if there are none.
+func (p *LightningPayment) finalHopRecords() *htlcswitch.FinalHopRecords {
+	if p.KeysendPreimage == nil && p.StatelessRecord == nil &&
+		p.PaymentAddr == nil {
+
+		return nil
+	}
+
+	records := &htlcswitch.FinalHopRecords{
+		KeysendPreimage: p.KeysendPreimage,
+		Stateless:       p.StatelessRecord,
+	}
+
+	// As payments aren't split, the total amount of the payment is the
+	// amount paid to the target.
+	if p.PaymentAddr != nil {
+		records.PaymentAddr = p.PaymentAddr
+		records.TotalAmt = p.Amount
+	}
+
+	return records
+}
+
+// PaymentSimulation describes the route a payment would take, were it to be
+// sent, as computed by SimulatePayment.
+type PaymentSimulation struct {
+	// Route is the route that would be used for the first attempt of the
+	// payment, including the fees and time-lock of each hop.
+	Route *Route
+
+	// SuccessProbability is a rough estimate of the probability that the
+	// payment would succeed over Route, within the range [0, 1].
+	SuccessProbability float64
+}
+
+// SimulatePayment performs path finding and onion construction for the
+// payment described within the passed LightningPayment, without dispatching
+// it. The route that would be attempted first is returned, along with an
+// estimate of its probability of success. As the payment isn't sent, neither
+// the state of mission control nor that of our channels is altered.
+func (r *ChannelRouter) SimulatePayment(
+	payment *LightningPayment) (*PaymentSimulation, error) {
+
+	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	finalCLTVDelta := uint16(DefaultFinalCLTVDelta)
+	if payment.FinalCLTVDelta != nil {
+		finalCLTVDelta = *payment.FinalCLTVDelta
+	}
+
+	paySession := r.missionControl.NewPaymentSession()
+	route, err := paySession.RequestRoute(
+		payment, uint32(currentHeight), finalCLTVDelta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// We'll also construct the onion packet for the route, to ensure the
+	// payment could actually be dispatched over it.
+	_, _, err = generateSphinxPacket(
+		route, payment.PaymentHash[:], payment.finalHopRecords(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaymentSimulation{
+		Route:              route,
+		SuccessProbability: successProbability(route),
+	}, nil
+}
+
+// successProbability returns a rough estimate of the probability that a
+// payment sent over the passed route succeeds. As the balances of remote
+// channels aren't known, each channel's capacity is assumed to be uniformly
+// distributed between its two ends, such that a hop forwarding amt over a
+// channel of capacity c succeeds with a probability of (c - amt) / c. The
+// probability of the route is the product of that of each of its hops.
+func successProbability(route *Route) float64 {
+	probability := 1.0
+	for _, hop := range route.Hops {
+		capacity := lnwire.NewMSatFromSatoshis(hop.Channel.Capacity)
+		if capacity == 0 || hop.AmtToForward >= capacity {
+			return 0
+		}
+
+		probability *= float64(capacity-hop.AmtToForward) /
+			float64(capacity)
+	}
+
+	return probability
+}
+
 // SendPayment attempts to send a payment as described within the passed
 // LightningPayment. This function is blocking and will return either: when the
 // payment is successful, or all candidates routes have been attempted and
@@ -1435,7 +1591,7 @@ func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte, *Route
 		// with the htlcAdd message that we send directly to the
 		// switch.
 		onionBlob, circuit, err := generateSphinxPacket(route,
-			payment.PaymentHash[:])
+			payment.PaymentHash[:], payment.finalHopRecords())
 		if err != nil {
 			return preImage, nil, err
 		}
@@ -1683,6 +1839,187 @@ func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte, *Route
 	}
 }
 
+// CircularPayment describes a payment sent from ourselves back to ourselves,
+// which leaves over one of our channels and returns over another.
+type CircularPayment struct {
+	// OutgoingChan is the short channel ID of the channel the payment
+	// leaves over.
+	OutgoingChan uint64
+
+	// IncomingChan is the short channel ID of the channel the payment
+	// returns over.
+	IncomingChan uint64
+
+	// Amount is the value of the payment to send around the circle in
+	// milli-satoshis, excluding the fees paid to the intermediate hops.
+	Amount lnwire.MilliSatoshi
+
+	// PaymentHash is the r-hash value of the invoice of ours the payment
+	// settles.
+	PaymentHash [32]byte
+}
+
+// SendCircularPayment attempts to send a payment to ourselves as described
+// within the passed CircularPayment. The payment leaves over the outgoing
+// channel, and returns over the incoming channel, traversing the shortest path
+// between the two peers if they're distinct. As the route is fixed, unlike
+// SendPayment, only a single attempt is made. If the payment succeeds, then
+// the payment preimage is returned along with the route it traversed.
+func (r *ChannelRouter) SendCircularPayment(
+	payment *CircularPayment) ([32]byte, *Route, error) {
+
+	var preImage [32]byte
+
+	if payment.OutgoingChan == payment.IncomingChan {
+		return preImage, nil, fmt.Errorf("outgoing and incoming " +
+			"channels of a circular payment must be distinct")
+	}
+
+	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return preImage, nil, err
+	}
+
+	// The route begins with the hop from us to the peer of the outgoing
+	// channel, and ends with the hop from the peer of the incoming channel
+	// back to us.
+	firstHop, _, err := r.fetchOwnChannelHops(payment.OutgoingChan)
+	if err != nil {
+		return preImage, nil, err
+	}
+	inPeerHop, lastHop, err := r.fetchOwnChannelHops(payment.IncomingChan)
+	if err != nil {
+		return preImage, nil, err
+	}
+
+	// If the two channels are with distinct peers, then we'll need to
+	// find a path between them. We ignore ourselves during path finding,
+	// as the payment may only pass through us at either end of the route.
+	pathEdges := []*ChannelHop{firstHop}
+	outPeer, inPeer := firstHop.Node, inPeerHop.Node
+	if !outPeer.PubKey.IsEqual(inPeer.PubKey) {
+		ignoredNodes := map[Vertex]struct{}{
+			NewVertex(r.selfNode.PubKey): {},
+		}
+		path, err := findPath(
+			nil, r.cfg.Graph, outPeer, inPeer.PubKey, ignoredNodes,
+			nil, payment.Amount,
+		)
+		if err != nil {
+			return preImage, nil, err
+		}
+		pathEdges = append(pathEdges, path...)
+	}
+	pathEdges = append(pathEdges, lastHop)
+
+	route, err := newRoute(
+		payment.Amount, NewVertex(r.selfNode.PubKey), pathEdges,
+		uint32(currentHeight), DefaultFinalCLTVDelta,
+	)
+	if err != nil {
+		return preImage, nil, err
+	}
+
+	log.Debugf("Attempting to send circular payment %x, using route: %v",
+		payment.PaymentHash, newLogClosure(func() string {
+			return spew.Sdump(route)
+		}),
+	)
+
+	preImage, err = r.SendToRoute(route, payment.PaymentHash)
+	if err != nil {
+		return preImage, nil, err
+	}
+
+	return preImage, route, nil
+}
+
+// SendToRoute attempts to send a payment with the passed payment hash over
+// the given route, which may have been built by an external path-finder
+// rather than by the router itself. The payment is dispatched over the link
+// of the channel of the first hop, and, as the route is fixed, only a single
+// attempt is made. If the payment succeeds, then the payment preimage is
+// returned. Otherwise, if the failure was reported by a node within the
+// route, then a *htlcswitch.ForwardingError holding the decoded failure, and
+// the node that reported it, is returned.
+func (r *ChannelRouter) SendToRoute(route *Route,
+	paymentHash [32]byte) ([32]byte, error) {
+
+	var preImage [32]byte
+
+	if len(route.Hops) == 0 {
+		return preImage, fmt.Errorf("route must contain at least " +
+			"one hop")
+	}
+
+	// Generate the raw encoded sphinx packet to be included along with
+	// the htlcAdd message that we send directly to the switch.
+	onionBlob, circuit, err := generateSphinxPacket(
+		route, paymentHash[:], nil,
+	)
+	if err != nil {
+		return preImage, err
+	}
+
+	htlcAdd := &lnwire.UpdateAddHTLC{
+		Amount:      route.TotalAmount,
+		Expiry:      route.TotalTimeLock,
+		PaymentHash: paymentHash,
+	}
+	copy(htlcAdd.OnionBlob[:], onionBlob)
+
+	// As we may be connected to the first hop over more than a single
+	// channel, we must ensure the payment leaves over the channel
+	// specified within the route, rather than letting the switch pick
+	// any link to the first hop.
+	firstHop := lnwire.NewShortChanIDFromInt(route.Hops[0].Channel.ChannelID)
+	return r.cfg.SendToLink(firstHop, htlcAdd, circuit)
+}
+
+// fetchOwnChannelHops returns the two hops across one of our own channels:
+// the one leading from us to the channel peer, and the one leading from the
+// channel peer back to us.
+func (r *ChannelRouter) fetchOwnChannelHops(
+	chanID uint64) (*ChannelHop, *ChannelHop, error) {
+
+	info, policy1, policy2, err := r.cfg.Graph.FetchChannelEdgesByID(chanID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to fetch channel %v: %v",
+			chanID, err)
+	}
+
+	selfKey := r.selfNode.PubKey
+	if !info.NodeKey1.IsEqual(selfKey) && !info.NodeKey2.IsEqual(selfKey) {
+		return nil, nil, fmt.Errorf("channel %v isn't one of ours",
+			chanID)
+	}
+
+	// Each directed edge points towards the node it leads to, so the
+	// edge pointing towards us is the one leading back from the peer.
+	var toPeer, toSelf *ChannelHop
+	for _, policy := range []*channeldb.ChannelEdgePolicy{policy1, policy2} {
+		if policy == nil {
+			continue
+		}
+
+		hop := &ChannelHop{
+			ChannelEdgePolicy: policy,
+			Capacity:          info.Capacity,
+		}
+		if policy.Node.PubKey.IsEqual(selfKey) {
+			toSelf = hop
+		} else {
+			toPeer = hop
+		}
+	}
+	if toPeer == nil || toSelf == nil {
+		return nil, nil, fmt.Errorf("routing policies of channel %v "+
+			"are unknown", chanID)
+	}
+
+	return toPeer, toSelf, nil
+}
+
 // applyChannelUpdate applies a channel update directly to the database,
 // skipping preliminary validation.
 func (r *ChannelRouter) applyChannelUpdate(msg *lnwire.ChannelUpdate) error {
@@ -17,6 +17,7 @@ import (
 	"github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
 )
 
 type testCtx struct {
@@ -271,6 +272,81 @@ func TestSendPaymentRouteFailureFallback(t *testing.T) {
 	}
 }
 
+// TestSimulatePayment tests that a simulated payment returns the route the
+// payment would take, along with an estimate of its success probability,
+// without dispatching any HTLC.
+func TestSimulatePayment(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	// No HTLC should ever be sent to the switch.
+	ctx.router.cfg.SendToSwitch = func(_ *btcec.PublicKey,
+		_ *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte, error) {
+
+		t.Fatalf("simulated payment was dispatched")
+		return [32]byte{}, nil
+	}
+
+	payment := LightningPayment{
+		Target: ctx.aliases["luoji"],
+		Amount: lnwire.NewMSatFromSatoshis(1000),
+	}
+	simulation, err := ctx.router.SimulatePayment(&payment)
+	if err != nil {
+		t.Fatalf("unable to simulate payment: %v", err)
+	}
+
+	route := simulation.Route
+	if len(route.Hops) == 0 {
+		t.Fatalf("simulated route has no hops")
+	}
+	if route.TotalTimeLock <= startingBlockHeight {
+		t.Fatalf("route has invalid time-lock: %v",
+			route.TotalTimeLock)
+	}
+
+	probability := simulation.SuccessProbability
+	if probability <= 0 || probability > 1 {
+		t.Fatalf("success probability out of range: %v", probability)
+	}
+	if probability != successProbability(route) {
+		t.Fatalf("expected success probability %v, got %v",
+			successProbability(route), probability)
+	}
+}
+
+// TestSuccessProbability tests that the success probability of a route is the
+// product of the probabilities of its hops, and that routes which exceed the
+// capacity of any hop can't succeed.
+func TestSuccessProbability(t *testing.T) {
+	t.Parallel()
+
+	hop := func(capacity, amt btcutil.Amount) *Hop {
+		return &Hop{
+			Channel:      &ChannelHop{Capacity: capacity},
+			AmtToForward: lnwire.NewMSatFromSatoshis(amt),
+		}
+	}
+
+	route := &Route{
+		Hops: []*Hop{hop(1000, 500), hop(4000, 1000)},
+	}
+	if p := successProbability(route); p != 0.375 {
+		t.Fatalf("expected success probability 0.375, got %v", p)
+	}
+
+	route.Hops = append(route.Hops, hop(1000, 1000))
+	if p := successProbability(route); p != 0 {
+		t.Fatalf("expected success probability 0, got %v", p)
+	}
+}
+
 // TestSendPaymentErrorPathPruning tests that the send of candidate routes
 // properly gets pruned in response to ForwardingError response from the
 // underlying SendToSwitch function.
@@ -1313,3 +1389,252 @@ func TestRouterChansClosedOfflinePruneGraph(t *testing.T) {
 		t.Fatalf("channel was found in graph but shouldn't have been")
 	}
 }
+
+// TestSendCircularPayment tests that a circular payment is routed from
+// roasbeef back to itself over the requested pair of channels, and that it's
+// dispatched over the link of the outgoing channel.
+func TestSendCircularPayment(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	var preImage [32]byte
+	copy(preImage[:], bytes.Repeat([]byte{9}, 32))
+
+	var sentOver lnwire.ShortChannelID
+	ctx.router.cfg.SendToLink = func(chanID lnwire.ShortChannelID,
+		_ *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte, error) {
+
+		sentOver = chanID
+		return preImage, nil
+	}
+
+	// We'll send a payment out over the channel with luo ji, and back
+	// over the channel with satoshi. As the two are distinct peers, the
+	// payment should be routed between them over their direct channel.
+	const (
+		roasbeefLuoji   = 689530843
+		luojiSatoshi    = 523452362
+		roasbeefSatoshi = 2340213491
+	)
+	payment := &CircularPayment{
+		OutgoingChan: roasbeefLuoji,
+		IncomingChan: roasbeefSatoshi,
+		Amount:       lnwire.NewMSatFromSatoshis(10),
+	}
+	paymentPreImage, route, err := ctx.router.SendCircularPayment(payment)
+	if err != nil {
+		t.Fatalf("unable to send circular payment: %v", err)
+	}
+
+	if paymentPreImage != preImage {
+		t.Fatalf("incorrect preimage used: expected %x got %x",
+			preImage[:], paymentPreImage[:])
+	}
+	if sentOver.ToUint64() != roasbeefLuoji {
+		t.Fatalf("payment sent over channel %v, expected %v",
+			sentOver.ToUint64(), roasbeefLuoji)
+	}
+
+	expectedChans := []uint64{roasbeefLuoji, luojiSatoshi, roasbeefSatoshi}
+	if len(route.Hops) != len(expectedChans) {
+		t.Fatalf("incorrect route length: expected %v got %v",
+			len(expectedChans), len(route.Hops))
+	}
+	for i, hop := range route.Hops {
+		if hop.Channel.ChannelID != expectedChans[i] {
+			t.Fatalf("hop %v traverses channel %v, expected %v", i,
+				hop.Channel.ChannelID, expectedChans[i])
+		}
+	}
+
+	// The final hop should lead back to roasbeef, and carry exactly the
+	// amount of the payment.
+	lastHop := route.Hops[len(route.Hops)-1]
+	if !lastHop.Channel.Node.PubKey.IsEqual(ctx.aliases["roasbeef"]) {
+		t.Fatalf("final hop doesn't lead back to roasbeef")
+	}
+	if lastHop.AmtToForward != payment.Amount {
+		t.Fatalf("incorrect final amount: expected %v got %v",
+			payment.Amount, lastHop.AmtToForward)
+	}
+
+	// Sending a circular payment over a single channel, or over a channel
+	// that isn't one of ours, should be rejected.
+	payment.IncomingChan = roasbeefLuoji
+	if _, _, err := ctx.router.SendCircularPayment(payment); err == nil {
+		t.Fatalf("expected circular payment over a single channel " +
+			"to fail")
+	}
+	payment.IncomingChan = luojiSatoshi
+	if _, _, err := ctx.router.SendCircularPayment(payment); err == nil {
+		t.Fatalf("expected circular payment over a foreign channel " +
+			"to fail")
+	}
+}
+
+// TestSendToRoute tests that a payment is dispatched over a route specified
+// by the caller, and that routes with inconsistent hops are rejected.
+func TestSendToRoute(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	var preImage [32]byte
+	copy(preImage[:], bytes.Repeat([]byte{9}, 32))
+
+	var (
+		sentOver lnwire.ShortChannelID
+		sentHTLC *lnwire.UpdateAddHTLC
+	)
+	ctx.router.cfg.SendToLink = func(chanID lnwire.ShortChannelID,
+		htlcAdd *lnwire.UpdateAddHTLC,
+		_ *sphinx.Circuit) ([32]byte, error) {
+
+		sentOver = chanID
+		sentHTLC = htlcAdd
+		return preImage, nil
+	}
+
+	// We'll specify a route from roasbeef to satoshi through luo ji, with
+	// luo ji taking a fee of 1500 milli-satoshis.
+	newHop := func(chanID uint64, alias string, amt,
+		fee lnwire.MilliSatoshi, timeLock uint32) *Hop {
+
+		return &Hop{
+			Channel: &ChannelHop{
+				ChannelEdgePolicy: &channeldb.ChannelEdgePolicy{
+					ChannelID: chanID,
+					Node: &channeldb.LightningNode{
+						PubKey: ctx.aliases[alias],
+					},
+				},
+			},
+			AmtToForward:     amt,
+			Fee:              fee,
+			OutgoingTimeLock: timeLock,
+		}
+	}
+	hops := []*Hop{
+		newHop(689530843, "luoji", 100000, 1500, 110),
+		newHop(523452362, "satoshi", 100000, 0, 110),
+	}
+	route, err := NewRouteFromHops(150, hops)
+	if err != nil {
+		t.Fatalf("unable to create route: %v", err)
+	}
+	if route.TotalAmount != 101500 || route.TotalFees != 1500 {
+		t.Fatalf("incorrect route totals: amount=%v, fees=%v",
+			route.TotalAmount, route.TotalFees)
+	}
+
+	var payHash [32]byte
+	paymentPreImage, err := ctx.router.SendToRoute(route, payHash)
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+	if paymentPreImage != preImage {
+		t.Fatalf("incorrect preimage used: expected %x got %x",
+			preImage[:], paymentPreImage[:])
+	}
+
+	// The HTLC should have been sent over the channel of the first hop,
+	// carrying the total amount and time lock of the route.
+	if sentOver.ToUint64() != 689530843 {
+		t.Fatalf("payment sent over channel %v, expected %v",
+			sentOver.ToUint64(), 689530843)
+	}
+	if sentHTLC.Amount != route.TotalAmount ||
+		sentHTLC.Expiry != route.TotalTimeLock {
+
+		t.Fatalf("incorrect htlc: amount=%v, expiry=%v",
+			sentHTLC.Amount, sentHTLC.Expiry)
+	}
+
+	// Finally, routes whose hops don't agree on the amount to forward, or
+	// whose final hop takes a fee, should be rejected.
+	_, err = NewRouteFromHops(150, []*Hop{
+		newHop(689530843, "luoji", 100000, 1500, 110),
+		newHop(523452362, "satoshi", 90000, 0, 110),
+	})
+	if err == nil {
+		t.Fatalf("expected route with inconsistent amounts to fail")
+	}
+	_, err = NewRouteFromHops(150, []*Hop{
+		newHop(689530843, "luoji", 100000, 1500, 110),
+	})
+	if err == nil {
+		t.Fatalf("expected route with a fee at the final hop to fail")
+	}
+}
+
+// TestGenerateSphinxPacketHopLimit ensures that the payloads carrying records
+// destined to the final hop of a payment count towards the HopLimit.
+func TestGenerateSphinxPacketHopLimit(t *testing.T) {
+	t.Parallel()
+
+	newRoute := func(numHops int) *Route {
+		route := &Route{}
+		for i := 0; i < numHops; i++ {
+			priv, err := btcec.NewPrivateKey(btcec.S256())
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+			}
+
+			edge := &channeldb.ChannelEdgePolicy{
+				ChannelID: uint64(i + 1),
+				Node: &channeldb.LightningNode{
+					PubKey: priv.PubKey(),
+				},
+			}
+			route.Hops = append(route.Hops, &Hop{
+				Channel: &ChannelHop{ChannelEdgePolicy: edge},
+			})
+		}
+
+		return route
+	}
+
+	paymentHash := make([]byte, 32)
+	records := &htlcswitch.FinalHopRecords{
+		KeysendPreimage: &[32]byte{1},
+	}
+
+	// A route spanning the entire limit is valid without records.
+	_, _, err := generateSphinxPacket(
+		newRoute(HopLimit), paymentHash, nil,
+	)
+	if err != nil {
+		t.Fatalf("unable to generate sphinx packet: %v", err)
+	}
+
+	// The keysend record requires two further payloads, so it's only
+	// permitted on routes two hops short of the limit.
+	_, circuit, err := generateSphinxPacket(
+		newRoute(HopLimit-2), paymentHash, records,
+	)
+	if err != nil {
+		t.Fatalf("unable to generate sphinx packet: %v", err)
+	}
+	if len(circuit.PaymentPath) != HopLimit {
+		t.Fatalf("expected %v payloads, got %v", HopLimit,
+			len(circuit.PaymentPath))
+	}
+
+	_, _, err = generateSphinxPacket(
+		newRoute(HopLimit-1), paymentHash, records,
+	)
+	if !IsError(err, ErrMaxHopsExceeded) {
+		t.Fatalf("expected ErrMaxHopsExceeded, got %v", err)
+	}
+}
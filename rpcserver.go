@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
@@ -9,6 +10,7 @@ import (
 	"io"
 	"math"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -21,6 +23,7 @@ import (
 	"github.com/boltdb/bolt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -38,6 +41,7 @@ import (
 	"github.com/roasbeef/btcwallet/waddrmgr"
 	"github.com/tv42/zbase32"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
 )
 
 var (
@@ -62,10 +66,27 @@ var (
 		"listpayments",
 		"decodepayreq",
 		"feereport",
+		"forwardingfilter",
+		"liquidityhistory",
+		"forwardinghistory",
+		"timelockedbalance",
+		"subscribechannelevents",
+		"lookupcircuit",
+		"peercompatibility",
+		"listaliases",
+		"inspectcommitments",
 	}
 )
 
 const (
+	// maxBatchInvoices is the maximum number of invoices which may be
+	// created by a single BatchAddInvoice request.
+	maxBatchInvoices = 1000
+
+	// defaultNumFwdingEvents is the maximum number of forwarding events
+	// returned by ForwardingHistory if the request doesn't specify one.
+	defaultNumFwdingEvents = 100
+
 	// maxPaymentMSat is the maximum allowed payment permitted currently as
 	// defined in BOLT-0002.
 	maxPaymentMSat = lnwire.MilliSatoshi(math.MaxUint32)
@@ -83,6 +104,21 @@ type rpcServer struct {
 
 	server *server
 
+	// subServers are a set of sub-RPC servers that use the same gRPC and
+	// listening sockets as the main RPC server, but which maintain their
+	// own independent service. This allows us to expose a set of
+	// micro-service like abstractions to the outside world for users to
+	// consume.
+	subServers []lnrpc.SubServer
+
+	// subServerPerms maps the full gRPC method URI of each sub-server call
+	// to the macaroon permission required to invoke it.
+	subServerPerms lnrpc.MacaroonPerms
+
+	// profiler collects the runtime profiles requested through
+	// DebugProfile.
+	profiler *profiler
+
 	wg sync.WaitGroup
 
 	quit chan struct{}
@@ -92,13 +128,57 @@ type rpcServer struct {
 // LightningServer gRPC service.
 var _ lnrpc.LightningServer = (*rpcServer)(nil)
 
-// newRPCServer creates and returns a new instance of the rpcServer.
-func newRPCServer(s *server, authSvc *bakery.Service) *rpcServer {
+// newRPCServer creates and returns a new instance of the rpcServer. Each of
+// the sub-servers that have been compiled into the daemon will be created
+// using the configs housed within the passed subServerCgs.
+func newRPCServer(s *server, authSvc *bakery.Service,
+	subServerCgs *subRPCServerConfigs) (*rpcServer, error) {
+
+	var (
+		subServers     []lnrpc.SubServer
+		subServerPerms = make(lnrpc.MacaroonPerms)
+	)
+	for _, subServer := range lnrpc.RegisteredSubServers() {
+		subServerInstance, err := subServer.New(subServerCgs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create %v "+
+				"sub-server: %v", subServer.SubServerName, err)
+		}
+
+		// We'll collect the sub-server, and also the set of
+		// permissions it needs for macaroons so we can apply the
+		// interceptors below.
+		subServers = append(subServers, subServerInstance)
+		for method, perm := range subServer.Permissions {
+			subServerPerms[method] = perm
+		}
+	}
+
+	quit := make(chan struct{}, 1)
 	return &rpcServer{
-		server:  s,
-		authSvc: authSvc,
-		quit:    make(chan struct{}, 1),
+		server:         s,
+		authSvc:        authSvc,
+		subServers:     subServers,
+		subServerPerms: subServerPerms,
+		profiler:       newProfiler(quit),
+		quit:           quit,
+	}, nil
+}
+
+// RegisterWithGrpcServer registers the rpcServer, along with each of its
+// active sub-servers, with the passed root gRPC server.
+func (r *rpcServer) RegisterWithGrpcServer(grpcServer *grpc.Server) error {
+	lnrpc.RegisterLightningServer(grpcServer, r)
+
+	for _, subServer := range r.subServers {
+		err := subServer.RegisterWithRootServer(grpcServer)
+		if err != nil {
+			return fmt.Errorf("unable to register %v with root "+
+				"gRPC server: %v", subServer.Name(), err)
+		}
 	}
+
+	return nil
 }
 
 // Start launches any helper goroutines required for the rpcServer to function.
@@ -107,6 +187,16 @@ func (r *rpcServer) Start() error {
 		return nil
 	}
 
+	// With the main RPC server started, we'll now start all the
+	// sub-servers so they can also begin to handle requests.
+	for _, subServer := range r.subServers {
+		rpcsLog.Debugf("Starting sub RPC server: %v", subServer.Name())
+
+		if err := subServer.Start(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -116,11 +206,94 @@ func (r *rpcServer) Stop() error {
 		return nil
 	}
 
+	// Now that we know the RPC server is stopping, we'll also stop all of
+	// the sub-servers.
+	for _, subServer := range r.subServers {
+		rpcsLog.Infof("Stopping %v Sub-RPC Server",
+			subServer.Name())
+
+		if err := subServer.Stop(); err != nil {
+			rpcsLog.Errorf("unable to stop sub-server %v: %v",
+				subServer.Name(), err)
+			continue
+		}
+	}
+
 	close(r.quit)
 
 	return nil
 }
 
+// serverOpts returns the set of gRPC server options that must be applied to
+// the root gRPC server in order for the macaroon permissions of the active
+// sub-servers to be enforced. Calls that belong to the main Lightning service
+// validate their macaroons inline, so they're passed through untouched.
+func (r *rpcServer) serverOpts() []grpc.ServerOption {
+	unaryInterceptor := func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		if err := r.checkSubServerPerms(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+
+	streamInterceptor := func(srv interface{}, ss grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		err := r.checkSubServerPerms(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(unaryInterceptor),
+		grpc.StreamInterceptor(streamInterceptor),
+	}
+}
+
+// checkSubServerPerms ensures that the macaroon attached to the passed context
+// grants the permission required to invoke the target sub-server method. If
+// the method doesn't belong to a sub-server, then nil is returned.
+func (r *rpcServer) checkSubServerPerms(ctx context.Context,
+	fullMethod string) error {
+
+	// The methods of the main Lightning service all carry out their own
+	// macaroon checks.
+	if strings.HasPrefix(fullMethod, "/lnrpc.") {
+		return nil
+	}
+
+	perm, ok := r.subServerPerms[fullMethod]
+	if !ok {
+		return fmt.Errorf("%v: unknown permissions required for "+
+			"method", fullMethod)
+	}
+
+	if r.authSvc == nil {
+		return nil
+	}
+
+	return macaroons.ValidateMacaroon(ctx, perm, r.authSvc)
+}
+
+// readOnlyPermissions returns the full set of permissions that should be
+// granted to the read-only macaroon. This includes the read-only calls of the
+// main RPC server, along with those of each active sub-server.
+func readOnlyPermissions() []string {
+	perms := append([]string{}, roPermissions...)
+	for _, subServer := range lnrpc.RegisteredSubServers() {
+		perms = append(perms, subServer.ReadOnlyPermissions...)
+	}
+
+	return perms
+}
+
 // addrPairsToOutputs converts a map describing a set of outputs to be created,
 // the outputs themselves. The passed map pairs up an address, to a desired
 // output value amount. Each address is converted to its corresponding pkScript
@@ -155,6 +328,19 @@ func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64,
 		return nil, err
 	}
 
+	// Ensure that the spend doesn't dip into the on-chain balance
+	// reserved for the fees of our channels.
+	if r.server.onChainReserve != nil {
+		var amt btcutil.Amount
+		for _, output := range outputs {
+			amt += btcutil.Amount(output.Value)
+		}
+		err := r.server.onChainReserve.CheckSpend(amt, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return r.server.cc.wallet.SendOutputs(outputs, feePerByte)
 }
 
@@ -552,6 +738,7 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 	localFundingAmt := btcutil.Amount(in.LocalFundingAmount)
 	remoteInitialBalance := btcutil.Amount(in.PushSat)
 	minHtlc := lnwire.MilliSatoshi(in.MinHtlcMsat)
+	remoteChanReserve := btcutil.Amount(in.RemoteChanReserveSat)
 
 	// Ensure that the initial balance of the remote party (if pushing
 	// satoshis) does not exceed the amount the local party has requested
@@ -563,6 +750,12 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 			"state must be below the local funding amount")
 	}
 
+	// Deferring the announcement is only meaningful for public channels.
+	if in.Private && in.AnnouncementDepth != 0 {
+		return fmt.Errorf("announcement depth can't be set for " +
+			"private channels")
+	}
+
 	// Ensure that the user doesn't exceed the current soft-limit for
 	// channel size. If the funding amount is above the soft-limit, then
 	// we'll reject the request.
@@ -610,6 +803,16 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 		nodePubKeyBytes = nodePubKey.SerializeCompressed()
 	}
 
+	// Ensure that the funding amount doesn't dip into the on-chain
+	// balance reserved for the fees of our channels, including the new
+	// one.
+	if r.server.onChainReserve != nil {
+		err := r.server.onChainReserve.CheckSpend(localFundingAmt, 1)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Based on the passed fee related paramters, we'll determine an
 	// approriate fee rate for the funding transaction.
 	feePerByte, err := determineFeePerByte(
@@ -628,7 +831,8 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 	updateChan, errChan := r.server.OpenChannel(
 		in.TargetPeerId, nodePubKey, localFundingAmt,
 		lnwire.NewMSatFromSatoshis(remoteInitialBalance),
-		minHtlc, feePerByte, in.Private,
+		minHtlc, remoteChanReserve, feePerByte, in.Private,
+		in.AnnouncementDepth,
 	)
 
 	var outpoint wire.OutPoint
@@ -724,6 +928,7 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 	localFundingAmt := btcutil.Amount(in.LocalFundingAmount)
 	remoteInitialBalance := btcutil.Amount(in.PushSat)
 	minHtlc := lnwire.MilliSatoshi(in.MinHtlcMsat)
+	remoteChanReserve := btcutil.Amount(in.RemoteChanReserveSat)
 
 	// Ensure that the initial balance of the remote party (if pushing
 	// satoshis) does not exceed the amount the local party has requested
@@ -733,6 +938,22 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 			"initial state must be below the local funding amount")
 	}
 
+	// Deferring the announcement is only meaningful for public channels.
+	if in.Private && in.AnnouncementDepth != 0 {
+		return nil, fmt.Errorf("announcement depth can't be set for " +
+			"private channels")
+	}
+
+	// Ensure that the funding amount doesn't dip into the on-chain
+	// balance reserved for the fees of our channels, including the new
+	// one.
+	if r.server.onChainReserve != nil {
+		err := r.server.onChainReserve.CheckSpend(localFundingAmt, 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Based on the passed fee related paramters, we'll determine an
 	// appropriate fee rate for the funding transaction.
 	feePerByte, err := determineFeePerByte(
@@ -748,7 +969,8 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 	updateChan, errChan := r.server.OpenChannel(
 		in.TargetPeerId, nodepubKey, localFundingAmt,
 		lnwire.NewMSatFromSatoshis(remoteInitialBalance),
-		minHtlc, feePerByte, in.Private,
+		minHtlc, remoteChanReserve, feePerByte, in.Private,
+		in.AnnouncementDepth,
 	)
 
 	select {
@@ -805,6 +1027,41 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 	rpcsLog.Tracef("[closechannel] request for ChannelPoint(%v), force=%v",
 		chanPoint, force)
 
+	// If the caller specified where the funds of a cooperative closure
+	// should be paid out to, then we'll ensure the script is one our peer
+	// will accept before handing it off to the switch.
+	var deliveryScript []byte
+	switch {
+	case in.DeliveryAddress != "" && len(in.DeliveryScript) != 0:
+		return fmt.Errorf("only one of delivery_address and " +
+			"delivery_script may be set")
+
+	case in.DeliveryAddress != "":
+		addr, err := btcutil.DecodeAddress(
+			in.DeliveryAddress, activeNetParams.Params,
+		)
+		if err != nil {
+			return fmt.Errorf("invalid delivery address: %v", err)
+		}
+		deliveryScript, err = txscript.PayToAddrScript(addr)
+		if err != nil {
+			return err
+		}
+
+	case len(in.DeliveryScript) != 0:
+		deliveryScript = in.DeliveryScript
+	}
+	if deliveryScript != nil {
+		if force {
+			return fmt.Errorf("a delivery script cannot be " +
+				"specified for a force closure")
+		}
+		err := lnwallet.ValidateDeliveryScript(deliveryScript)
+		if err != nil {
+			return err
+		}
+	}
+
 	var (
 		updateChan chan *lnrpc.CloseStatusUpdate
 		errChan    chan error
@@ -826,6 +1083,29 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		}
 		channel.Stop()
 
+		// Unless the force closure has been explicitly confirmed,
+		// we'll only report the HTLCs that will be resolved on chain,
+		// along with the estimated cost of the closure, and leave the
+		// channel untouched.
+		if !in.ConfirmForce {
+			sweepFeePerByte, err := determineFeePerByte(
+				r.server.cc.feeEstimator, in.TargetConf,
+				in.SatPerByte,
+			)
+			if err != nil {
+				return err
+			}
+
+			preview := forceClosePreview(
+				channel.State(), sweepFeePerByte,
+			)
+			return updateStream.Send(&lnrpc.CloseStatusUpdate{
+				Update: &lnrpc.CloseStatusUpdate_ForceClosePreview{
+					ForceClosePreview: preview,
+				},
+			})
+		}
+
 		_, bestHeight, err := r.server.cc.chainIO.GetBestBlock()
 		if err != nil {
 			return err
@@ -844,6 +1124,7 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		} else {
 			chanID := lnwire.NewChanIDFromOutPoint(channel.ChannelPoint())
 			r.server.htlcSwitch.RemoveLink(chanID)
+			r.server.htlcSwitch.DiscardMailBox(chanID)
 		}
 
 		select {
@@ -924,7 +1205,7 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		// broadcast details.
 		feePerKw := feePerWeight * 1000
 		updateChan, errChan = r.server.htlcSwitch.CloseLink(chanPoint,
-			htlcswitch.CloseRegular, feePerKw)
+			htlcswitch.CloseRegular, feePerKw, deliveryScript)
 	}
 out:
 	for {
@@ -958,6 +1239,84 @@ out:
 	return nil
 }
 
+// forceClosePreview reports the HTLCs that will be resolved on chain if our
+// latest commitment transaction of the channel is broadcast, along with the
+// fees and time-locks we'll incur. As the fee of the commitment transaction
+// was fixed when it was signed, the passed fee rate is only used to estimate
+// the cost of sweeping our outputs back into the wallet.
+func forceClosePreview(channel *channeldb.OpenChannel,
+	sweepFeePerByte btcutil.Amount) *lnrpc.ForceClosePreview {
+
+	localCommit := channel.LocalCommitment
+	dustLimit := channel.LocalChanCfg.DustLimit
+
+	preview := &lnrpc.ForceClosePreview{
+		PendingHtlcs:    make([]*lnrpc.HTLC, 0, len(localCommit.Htlcs)),
+		CommitFee:       int64(localCommit.CommitFee),
+		SweepSatPerByte: int64(sweepFeePerByte),
+		CsvDelay:        uint32(channel.LocalChanCfg.CsvDelay),
+	}
+
+	// Each of our outputs, whether on the commitment transaction or on a
+	// second-level HTLC transaction, will be swept to a single output of
+	// our wallet.
+	var (
+		weightEstimate  lnwallet.TxWeightEstimator
+		numSweepInputs  int
+		secondLevelFees btcutil.Amount
+	)
+	weightEstimate.AddP2WKHOutput()
+
+	localBalance := localCommit.LocalBalance.ToSatoshis()
+	if localBalance >= dustLimit {
+		weightEstimate.AddWitnessInput(lnwallet.ToLocalTimeoutWitnessSize)
+		numSweepInputs++
+
+		preview.LimboBalance = int64(localBalance)
+	}
+
+	for _, htlc := range localCommit.Htlcs {
+		preview.PendingHtlcs = append(preview.PendingHtlcs, &lnrpc.HTLC{
+			Incoming:         htlc.Incoming,
+			Amount:           int64(htlc.Amt.ToSatoshis()),
+			HashLock:         htlc.RHash[:],
+			ExpirationHeight: htlc.RefundTimeout,
+		})
+		if htlc.RefundTimeout > preview.MaxHtlcExpiry {
+			preview.MaxHtlcExpiry = htlc.RefundTimeout
+		}
+
+		// Each non-dust HTLC is resolved by a second-level
+		// transaction, whose fee is paid from the HTLC's value at the
+		// fee rate of the commitment transaction. Dust HTLCs go to
+		// miners instead.
+		secondLevelWeight := btcutil.Amount(lnwallet.HtlcTimeoutWeight)
+		if htlc.Incoming {
+			secondLevelWeight = lnwallet.HtlcSuccessWeight
+		}
+		secondLevelFee := localCommit.FeePerKw * secondLevelWeight / 1000
+		if htlc.Amt.ToSatoshis() < secondLevelFee+dustLimit {
+			continue
+		}
+
+		secondLevelFees += secondLevelFee
+		weightEstimate.AddWitnessInput(
+			lnwallet.SecondLevelHtlcSuccessWitnessSize,
+		)
+		numSweepInputs++
+	}
+
+	preview.EstimatedSweepFee = int64(secondLevelFees)
+	if numSweepInputs > 0 {
+		sweepWeight := btcutil.Amount(weightEstimate.Weight())
+		preview.EstimatedSweepFee += int64(
+			sweepFeePerByte * sweepWeight / blockchain.WitnessScaleFactor,
+		)
+	}
+
+	return preview
+}
+
 // fetchActiveChannel attempts to locate a channel identified by it's channel
 // point from the database's set of all currently opened channels.
 func (r *rpcServer) fetchActiveChannel(chanPoint wire.OutPoint) (*lnwallet.LightningChannel, error) {
@@ -1019,15 +1378,50 @@ func (r *rpcServer) GetInfo(ctx context.Context,
 	idPub := r.server.identityPriv.PubKey().SerializeCompressed()
 	encodedIDPub := hex.EncodeToString(idPub)
 
+	// If the chain backend is currently deemed unhealthy, then it may not
+	// be able to answer our queries. In that case, we'll fall back to the
+	// last block it reported, and mark ourselves as not synced rather than
+	// failing the call entirely.
+	healthStatus := r.server.chainHealth.Status()
 	bestHash, bestHeight, err := r.server.cc.chainIO.GetBestBlock()
-	if err != nil {
+	switch {
+	case err != nil && healthStatus.Healthy:
 		return nil, fmt.Errorf("unable to get best block info: %v", err)
+
+	case err != nil:
+		bestHash = &healthStatus.BestHash
+		bestHeight = healthStatus.BestHeight
 	}
 
 	isSynced, err := r.server.cc.wallet.IsSynced()
-	if err != nil {
+	switch {
+	case err != nil && healthStatus.Healthy:
 		return nil, fmt.Errorf("unable to sync PoV of the wallet "+
 			"with current best block in the main chain: %v", err)
+
+	case err != nil:
+		isSynced = false
+	}
+
+	var chainBackendErr string
+	if !healthStatus.Healthy && healthStatus.LastErr != nil {
+		chainBackendErr = healthStatus.LastErr.Error()
+	}
+
+	// If the startup self-check is enabled, then we'll report its
+	// outcome.
+	selfCheckStatus := "disabled"
+	var (
+		selfCheckLatency int64
+		selfCheckErr     string
+	)
+	if r.server.selfChecker != nil {
+		status := r.server.selfChecker.Status()
+		selfCheckStatus = status.State.String()
+		selfCheckLatency = int64(status.Latency / time.Millisecond)
+		if status.Err != nil {
+			selfCheckErr = status.Err.Error()
+		}
 	}
 
 	activeChains := make([]string, registeredChains.NumActiveChains())
@@ -1050,17 +1444,22 @@ func (r *rpcServer) GetInfo(ctx context.Context,
 
 	// TODO(roasbeef): add synced height n stuff
 	return &lnrpc.GetInfoResponse{
-		IdentityPubkey:     encodedIDPub,
-		NumPendingChannels: nPendingChannels,
-		NumActiveChannels:  activeChannels,
-		NumPeers:           uint32(len(serverPeers)),
-		BlockHeight:        uint32(bestHeight),
-		BlockHash:          bestHash.String(),
-		SyncedToChain:      isSynced,
-		Testnet:            activeNetParams.Params == &chaincfg.TestNet3Params,
-		Chains:             activeChains,
-		Uris:               uris,
-		Alias:              nodeAnn.Alias.String(),
+		IdentityPubkey:      encodedIDPub,
+		NumPendingChannels:  nPendingChannels,
+		NumActiveChannels:   activeChannels,
+		NumPeers:            uint32(len(serverPeers)),
+		BlockHeight:         uint32(bestHeight),
+		BlockHash:           bestHash.String(),
+		SyncedToChain:       isSynced,
+		Testnet:             activeNetParams.Params == &chaincfg.TestNet3Params,
+		Chains:              activeChains,
+		Uris:                uris,
+		Alias:               nodeAnn.Alias.String(),
+		ChainBackendHealthy: healthStatus.Healthy,
+		ChainBackendError:   chainBackendErr,
+		SelfCheckStatus:     selfCheckStatus,
+		SelfCheckLatencyMs:  selfCheckLatency,
+		SelfCheckError:      selfCheckErr,
 	}, nil
 }
 
@@ -1111,6 +1510,9 @@ func (r *rpcServer) ListPeers(ctx context.Context,
 			SatSent:   satSent,
 			SatRecv:   satRecv,
 			PingTime:  serverPeer.PingTime(),
+			UnknownMsgs: atomic.LoadUint64(
+				&serverPeer.unknownMsgs,
+			),
 		}
 
 		resp.Peers = append(resp.Peers, peer)
@@ -1152,12 +1554,23 @@ func (r *rpcServer) WalletBalance(ctx context.Context,
 	// Get uncomfirmed balance, from txs with 0 confirmations.
 	unconfirmedBal := totalBal - confirmedBal
 
+	// Get the portion of the confirmed balance reserved for the fees of
+	// our channels, if any.
+	var reservedBal btcutil.Amount
+	if r.server.onChainReserve != nil {
+		reservedBal, err = r.server.onChainReserve.Required()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	rpcsLog.Debugf("[walletbalance] Total balance=%v", totalBal)
 
 	return &lnrpc.WalletBalanceResponse{
 		TotalBalance:       int64(totalBal),
 		ConfirmedBalance:   int64(confirmedBal),
 		UnconfirmedBalance: int64(unconfirmedBal),
+		ReservedBalance:    int64(reservedBal),
 	}, nil
 }
 
@@ -1404,12 +1817,20 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 		}
 
 		channelID := lnwire.NewChanIDFromOutPoint(&chanPoint)
-		var linkActive bool
+		var (
+			linkActive bool
+			htlcAges   map[uint64]time.Duration
+			snapshot   *htlcswitch.LinkSnapshot
+		)
 		if link, err := r.server.htlcSwitch.GetLink(channelID); err == nil {
 			// A channel is only considered active if it is known
 			// by the switch *and* able to forward
 			// incoming/outgoing payments.
-			linkActive = link.EligibleToForward()
+			snapshot, err = link.LinkSnapshot()
+			if err == nil {
+				linkActive = snapshot.EligibleToForward
+				htlcAges = snapshot.OutgoingHtlcAges
+			}
 		}
 
 		// As this is required for display purposes, we'll calculate
@@ -1454,6 +1875,7 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 			NumUpdates:            localCommit.CommitHeight,
 			PendingHtlcs:          make([]*lnrpc.HTLC, len(localCommit.Htlcs)),
 			CsvDelay:              uint32(dbChannel.LocalChanCfg.CsvDelay),
+			Private:               dbChannel.ChannelFlags&lnwire.FFAnnounceChannel == 0,
 		}
 
 		for i, htlc := range localCommit.Htlcs {
@@ -1463,6 +1885,42 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 				HashLock:         htlc.RHash[:],
 				ExpirationHeight: htlc.RefundTimeout,
 			}
+
+			if !htlc.Incoming {
+				age := htlcAges[htlc.HtlcIndex]
+				channel.PendingHtlcs[i].Age = int64(age.Seconds())
+			}
+		}
+
+		// If the channel's link is active, then we'll report its
+		// current in-flight headroom. Otherwise, we'll derive it from
+		// the HTLCs within our latest commitment.
+		if snapshot != nil {
+			channel.LocalInflightHeadroomMsat = int64(
+				snapshot.LocalInFlightHeadroom,
+			)
+			channel.RemoteInflightHeadroomMsat = int64(
+				snapshot.RemoteInFlightHeadroom,
+			)
+		} else {
+			localHeadroom, remoteHeadroom := channelInFlightHeadroom(
+				dbChannel,
+			)
+			channel.LocalInflightHeadroomMsat = int64(localHeadroom)
+			channel.RemoteInflightHeadroomMsat = int64(remoteHeadroom)
+		}
+
+		// Likewise, we'll report the amounts that can currently be sent
+		// and received over the channel.
+		if snapshot != nil {
+			channel.SendableMsat = int64(snapshot.Bandwidth)
+			channel.ReceivableMsat = int64(
+				snapshot.ReceivableBandwidth,
+			)
+		} else {
+			sendable, receivable := channelBandwidth(dbChannel)
+			channel.SendableMsat = int64(sendable)
+			channel.ReceivableMsat = int64(receivable)
 		}
 
 		resp.Channels = append(resp.Channels, channel)
@@ -1471,6 +1929,73 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 	return resp, nil
 }
 
+// channelInFlightHeadroom returns the additional value of outgoing HTLCs the
+// remote party permits us to add to the channel, along with the additional
+// value of incoming HTLCs we permit the remote party to add, based on the HTLCs
+// within our latest commitment. A maximum value in flight of zero is treated
+// as unlimited.
+func channelInFlightHeadroom(c *channeldb.OpenChannel) (lnwire.MilliSatoshi,
+	lnwire.MilliSatoshi) {
+
+	var outgoing, incoming lnwire.MilliSatoshi
+	for _, htlc := range c.LocalCommitment.Htlcs {
+		if htlc.Incoming {
+			incoming += htlc.Amt
+		} else {
+			outgoing += htlc.Amt
+		}
+	}
+
+	capacity := lnwire.NewMSatFromSatoshis(c.Capacity)
+	headroom := func(maxValue, inFlight lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+		if maxValue == 0 || maxValue > capacity {
+			maxValue = capacity
+		}
+		if inFlight >= maxValue {
+			return 0
+		}
+		return maxValue - inFlight
+	}
+
+	return headroom(c.LocalChanCfg.MaxPendingAmount, outgoing),
+		headroom(c.RemoteChanCfg.MaxPendingAmount, incoming)
+}
+
+// channelBandwidth returns the amount we're able to send over the channel,
+// along with the amount the remote party is able to send to us, based on the
+// balances within our latest commitment. Each party must keep its balance
+// above the reserve set by the other, and may only add HTLCs up to its
+// in-flight headroom.
+func channelBandwidth(c *channeldb.OpenChannel) (lnwire.MilliSatoshi,
+	lnwire.MilliSatoshi) {
+
+	localHeadroom, remoteHeadroom := channelInFlightHeadroom(c)
+
+	bandwidth := func(balance lnwire.MilliSatoshi, reserve btcutil.Amount,
+		headroom lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+		reserveMSat := lnwire.NewMSatFromSatoshis(reserve)
+		if balance < reserveMSat {
+			return 0
+		}
+		if balance-reserveMSat > headroom {
+			return headroom
+		}
+		return balance - reserveMSat
+	}
+
+	sendable := bandwidth(
+		c.LocalCommitment.LocalBalance, c.LocalChanCfg.ChanReserve,
+		localHeadroom,
+	)
+	receivable := bandwidth(
+		c.LocalCommitment.RemoteBalance, c.RemoteChanCfg.ChanReserve,
+		remoteHeadroom,
+	)
+
+	return sendable, receivable
+}
+
 // savePayment saves a successfully completed payment to the database for
 // historical record keeping.
 func (r *rpcServer) savePayment(route *routing.Route, amount lnwire.MilliSatoshi, preImage []byte) error {
@@ -1529,6 +2054,19 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 		dest      []byte
 		pHash     []byte
 		cltvDelta uint16
+		dryRun    bool
+
+		// keysendPreimage is the preimage of pHash, included
+		// within the onion for keysend payments.
+		keysendPreimage *[32]byte
+
+		// stateless is the record of the stateless invoice paid,
+		// handed back to its creator within the onion.
+		stateless *htlcswitch.StatelessRecord
+
+		// paymentAddr is the payment secret of the invoice paid,
+		// included within the onion.
+		paymentAddr *[32]byte
 	}
 	payChan := make(chan *payment)
 	errChan := make(chan error, 1)
@@ -1639,6 +2177,8 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 
 					p.pHash = payReq.PaymentHash[:]
 					p.cltvDelta = uint16(payReq.MinFinalCLTVExpiry())
+					p.stateless = payReqStatelessRecord(payReq)
+					p.paymentAddr = payReq.PaymentAddr
 				} else {
 					// If the payment request field was not
 					// specified, construct the payment from
@@ -1650,6 +2190,24 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 					p.pHash = nextPayment.PaymentHash
 					p.cltvDelta = uint16(nextPayment.FinalCltvDelta)
 				}
+				p.dryRun = nextPayment.DryRun
+
+				// For keysend payments, we'll generate the
+				// preimage ourselves, so the payment hash
+				// can't be specified.
+				if nextPayment.Keysend {
+					preimage, hash, err :=
+						newKeysendPreimage(nextPayment)
+					if err != nil {
+						select {
+						case errChan <- err:
+						case <-reqQuit:
+						}
+						return
+					}
+					p.keysendPreimage = preimage
+					p.pHash = hash[:]
+				}
 
 				select {
 				case payChan <- p:
@@ -1719,13 +2277,27 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 				// returned. Otherwise, we'll get a non-nil
 				// error.
 				payment := &routing.LightningPayment{
-					Target:      destNode,
-					Amount:      p.msat,
-					PaymentHash: rHash,
+					Target:          destNode,
+					Amount:          p.msat,
+					PaymentHash:     rHash,
+					KeysendPreimage: p.keysendPreimage,
+					StatelessRecord: p.stateless,
+					PaymentAddr:     p.paymentAddr,
 				}
 				if p.cltvDelta != 0 {
 					payment.FinalCLTVDelta = &p.cltvDelta
 				}
+
+				// If this is a dry run, then we'll only report
+				// the route the payment would take.
+				if p.dryRun {
+					resp := r.simulatePayment(payment)
+					if err := paymentStream.Send(resp); err != nil {
+						errChan <- err
+					}
+					return
+				}
+
 				preImage, route, err := r.server.chanRouter.SendPayment(payment)
 				if err != nil {
 					// If we receive payment error than,
@@ -1760,6 +2332,54 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 	}
 }
 
+// simulatePayment finds the route the passed payment would take, and
+// constructs its onion, without sending it. The response carries the route,
+// along with an estimate of its success probability, or the reason no route
+// could be found.
+func (r *rpcServer) simulatePayment(
+	payment *routing.LightningPayment) *lnrpc.SendResponse {
+
+	simulation, err := r.server.chanRouter.SimulatePayment(payment)
+	if err != nil {
+		return &lnrpc.SendResponse{
+			PaymentError: err.Error(),
+		}
+	}
+
+	return &lnrpc.SendResponse{
+		PaymentRoute:       marshallRoute(simulation.Route),
+		SuccessProbability: simulation.SuccessProbability,
+	}
+}
+
+// newKeysendPreimage generates a random preimage for the passed keysend
+// payment, along with the payment hash it's locked to. An error is returned if
+// keysend payments haven't been enabled, or if the payment hash is already
+// specified by the request.
+func newKeysendPreimage(req *lnrpc.SendRequest) (*[32]byte, [32]byte,
+	error) {
+
+	if !cfg.ExperimentalKeysend {
+		return nil, [32]byte{}, errors.New("keysend payments are " +
+			"experimental, and must be enabled with " +
+			"--experimentalkeysend")
+	}
+
+	if req.PaymentRequest != "" || len(req.PaymentHash) != 0 ||
+		req.PaymentHashString != "" {
+
+		return nil, [32]byte{}, errors.New("keysend payments may not " +
+			"specify a payment hash or payment request")
+	}
+
+	var preimage [32]byte
+	if _, err := rand.Read(preimage[:]); err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	return &preimage, sha256.Sum256(preimage[:]), nil
+}
+
 // SendPaymentSync is the synchronous non-streaming version of SendPayment.
 // This RPC is intended to be consumed by clients of the REST proxy.
 // Additionally, this RPC expects the destination's public key and the payment
@@ -1787,15 +2407,18 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 	}
 
 	var (
-		destPub   *btcec.PublicKey
-		amtMSat   lnwire.MilliSatoshi
-		rHash     [32]byte
-		cltvDelta uint16
+		destPub         *btcec.PublicKey
+		amtMSat         lnwire.MilliSatoshi
+		rHash           [32]byte
+		cltvDelta       uint16
+		keysendPreimage *[32]byte
+		stateless       *htlcswitch.StatelessRecord
+		paymentAddr     *[32]byte
 	)
 
 	// If the proto request has an encoded payment request, then we we'll
 	// use that solely to dispatch the payment.
-	if nextPayment.PaymentRequest != "" {
+	if nextPayment.PaymentRequest != "" && !nextPayment.Keysend {
 		payReq, err := zpay32.Decode(nextPayment.PaymentRequest)
 		if err != nil {
 			return nil, err
@@ -1822,16 +2445,30 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 
 		rHash = *payReq.PaymentHash
 		cltvDelta = uint16(payReq.MinFinalCLTVExpiry())
+		stateless = payReqStatelessRecord(payReq)
+		paymentAddr = payReq.PaymentAddr
 
 		// Otherwise, the payment conditions have been manually
 		// specified in the proto.
 	} else {
-		// If we're in debug HTLC mode, then all outgoing HTLCs will
-		// pay to the same debug rHash. Otherwise, we pay to the rHash
-		// specified within the RPC request.
-		if cfg.DebugHTLC && nextPayment.PaymentHashString == "" {
+		// For keysend payments, we generate the preimage ourselves.
+		// Otherwise, if we're in debug HTLC mode, then all outgoing
+		// HTLCs will pay to the same debug rHash, or else we pay to the
+		// rHash specified within the RPC request.
+		switch {
+		case nextPayment.Keysend:
+			var err error
+			keysendPreimage, rHash, err = newKeysendPreimage(
+				nextPayment,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+		case cfg.DebugHTLC && nextPayment.PaymentHashString == "":
 			rHash = debugHash
-		} else {
+
+		default:
 			paymentHash, err := hex.DecodeString(nextPayment.PaymentHashString)
 			if err != nil {
 				return nil, err
@@ -1869,13 +2506,23 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 	// payment succeeds, then the returned route will be that was used
 	// successfully within the payment.
 	payment := &routing.LightningPayment{
-		Target:      destPub,
-		Amount:      amtMSat,
-		PaymentHash: rHash,
+		Target:          destPub,
+		Amount:          amtMSat,
+		PaymentHash:     rHash,
+		KeysendPreimage: keysendPreimage,
+		StatelessRecord: stateless,
+		PaymentAddr:     paymentAddr,
 	}
 	if cltvDelta != 0 {
 		payment.FinalCLTVDelta = &cltvDelta
 	}
+
+	// If this is a dry run, then we'll only report the route the payment
+	// would take, without sending it.
+	if nextPayment.DryRun {
+		return r.simulatePayment(payment), nil
+	}
+
 	preImage, route, err := r.server.chanRouter.SendPayment(payment)
 	if err != nil {
 		return &lnrpc.SendResponse{
@@ -1909,26 +2556,105 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		}
 	}
 
-	var paymentPreimage [32]byte
-
-	switch {
-	// If a preimage wasn't specified, then we'll generate a new preimage
-	// from fresh cryptographic randomness.
-	case len(invoice.RPreimage) == 0:
-		if _, err := rand.Read(paymentPreimage[:]); err != nil {
+	// If requested, we'll include a routing hint for one of our private
+	// channels within the payment request.
+	var routeHint []zpay32.ExtraRoutingInfo
+	if invoice.Private {
+		var err error
+		routeHint, err = r.privateRouteHint()
+		if err != nil {
 			return nil, err
 		}
-
-	// Otherwise, if a preimage was specified, then it MUST be exactly
-	// 32-bytes.
-	case len(invoice.RPreimage) > 0 && len(invoice.RPreimage) != 32:
-		return nil, fmt.Errorf("payment preimage must be exactly "+
-			"32 bytes, is instead %v", len(invoice.RPreimage))
-
-	// If the preimage meets the size specifications, then it can be used
-	// as is.
-	default:
-		copy(paymentPreimage[:], invoice.RPreimage[:])
+	}
+
+	i, err := r.newInvoice(invoice, routeHint)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Tracef("[addinvoice] adding new invoice %v",
+		newLogClosure(func() string {
+			return spew.Sdump(i)
+		}),
+	)
+
+	// With all sanity checks passed, write the invoice to the database,
+	// unless it's a stateless invoice, which is reconstructed from the
+	// payment to it instead.
+	if !invoice.Stateless {
+		if err := r.server.invoices.AddInvoice(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return &lnrpc.AddInvoiceResponse{
+		RHash:          i.Terms.PaymentHash[:],
+		PaymentRequest: string(i.PaymentRequest),
+	}, nil
+}
+
+// newInvoice validates the passed invoice template, and creates the invoice
+// it describes, along with its signed payment request. If the template
+// doesn't specify a preimage, then a fresh one is generated. If the passed
+// routing hint is non-nil, then it's included within the payment request.
+// The invoice isn't added to the invoice database.
+func (r *rpcServer) newInvoice(invoice *lnrpc.Invoice,
+	routeHint []zpay32.ExtraRoutingInfo) (*channeldb.Invoice, error) {
+
+	var (
+		paymentPreimage [32]byte
+		rHash           [32]byte
+	)
+
+	// The preimage of a stateless invoice is derived from its terms, so
+	// it can't be specified, nor be released later on.
+	if invoice.Stateless {
+		switch {
+		case r.server.statelessInvoiceKey == nil:
+			return nil, fmt.Errorf("stateless invoices aren't " +
+				"enabled")
+
+		case invoice.Hold || len(invoice.RPreimage) != 0 ||
+			len(invoice.RHash) != 0:
+
+			return nil, fmt.Errorf("stateless invoices can't be " +
+				"hold invoices, nor specify a preimage or " +
+				"payment hash")
+		}
+	}
+
+	switch {
+	// A hold invoice may be created for a payment hash whose preimage
+	// we don't know yet, in which case it's only revealed once the
+	// invoice is settled.
+	case invoice.Hold && len(invoice.RHash) != 0:
+		if len(invoice.RPreimage) != 0 {
+			return nil, fmt.Errorf("only one of r_preimage and " +
+				"r_hash may be set")
+		}
+		if len(invoice.RHash) != 32 {
+			return nil, fmt.Errorf("payment hash must be exactly "+
+				"32 bytes, is instead %v", len(invoice.RHash))
+		}
+		copy(rHash[:], invoice.RHash)
+
+	// If a preimage wasn't specified, then we'll generate a new preimage
+	// from fresh cryptographic randomness.
+	case len(invoice.RPreimage) == 0:
+		if _, err := rand.Read(paymentPreimage[:]); err != nil {
+			return nil, err
+		}
+
+	// Otherwise, if a preimage was specified, then it MUST be exactly
+	// 32-bytes.
+	case len(invoice.RPreimage) > 0 && len(invoice.RPreimage) != 32:
+		return nil, fmt.Errorf("payment preimage must be exactly "+
+			"32 bytes, is instead %v", len(invoice.RPreimage))
+
+	// If the preimage meets the size specifications, then it can be used
+	// as is.
+	default:
+		copy(paymentPreimage[:], invoice.RPreimage[:])
 	}
 
 	// The size of the memo, receipt and description hash attached must not
@@ -1956,9 +2682,12 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 			"payment allowed is %v", amt, maxPaymentMSat.ToSatoshis())
 	}
 
-	// Next, generate the payment hash itself from the preimage. This will
-	// be used by clients to query for the state of a particular invoice.
-	rHash := sha256.Sum256(paymentPreimage[:])
+	// Next, generate the payment hash itself from the preimage, unless it
+	// was specified for a hold invoice. This will be used by clients to
+	// query for the state of a particular invoice.
+	if rHash == [32]byte{} {
+		rHash = sha256.Sum256(paymentPreimage[:])
+	}
 
 	// We also create an encoded payment request which allows the
 	// caller to compactly send the invoice to the payer. We'll create a
@@ -1985,12 +2714,21 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		options = append(options, zpay32.FallbackAddr(addr))
 	}
 
-	// If expiry is set, specify it. If it is not provided, no expiry time
+	// If expiry is set, specify it. If it is not provided, we'll fall back
+	// to the configured default expiry, if any. Otherwise, no expiry time
 	// will be explicitly added to this payment request, which will imply
-	// the default 3600 seconds.
-	if invoice.Expiry > 0 {
-		exp := time.Duration(invoice.Expiry) * time.Second
-		options = append(options, zpay32.Expiry(exp))
+	// the default 3600 seconds, unless the invoice is stateless.
+	var expiry time.Duration
+	switch {
+	case invoice.Expiry > 0:
+		expiry = time.Duration(invoice.Expiry) * time.Second
+	case cfg.InvoiceExpiry > 0:
+		expiry = cfg.InvoiceExpiry
+	case invoice.Stateless:
+		expiry = defaultStatelessExpiry
+	}
+	if expiry > 0 {
+		options = append(options, zpay32.Expiry(expiry))
 	}
 
 	// If the description hash is set, then we add it do the list of options.
@@ -2020,8 +2758,45 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		options = append(options, zpay32.CLTVExpiry(uint64(defaultDelta)))
 	}
 
+	// If passed, we'll include the routing hint for one of our private
+	// channels, as payers would otherwise be unable to find a route to us
+	// if all of our channels are private.
+	if routeHint != nil {
+		options = append(options, zpay32.RoutingInfo(routeHint))
+	}
+
 	// Create and encode the payment request as a bech32 (zpay32) string.
 	creationDate := time.Now()
+
+	// For stateless invoices, the preimage is derived from a fresh nonce
+	// and the terms of the invoice, all of which are included within the
+	// payment request, such that the payer is able to hand them back
+	// within the payment. As the timestamp of the payment request only
+	// has a precision of one second, so must the creation date.
+	if invoice.Stateless {
+		creationDate = time.Unix(creationDate.Unix(), 0)
+		record, err := newStatelessRecord(amtMSat, creationDate, expiry)
+		if err != nil {
+			return nil, err
+		}
+
+		paymentPreimage = record.Preimage(*r.server.statelessInvoiceKey)
+		rHash = sha256.Sum256(paymentPreimage[:])
+		options = append(options, zpay32.StatelessNonce(record.Nonce))
+	}
+
+	// Invoices which are stored are given a payment secret, which the
+	// payer includes within the onion, such that intermediate nodes,
+	// which don't know it, can't probe for the invoice. As stateless
+	// invoices aren't stored, there'd be nothing to check theirs against.
+	var paymentAddr [32]byte
+	if !invoice.Stateless {
+		if _, err := rand.Read(paymentAddr[:]); err != nil {
+			return nil, err
+		}
+		options = append(options, zpay32.PaymentAddr(paymentAddr))
+	}
+
 	payReq, err := zpay32.NewInvoice(
 		activeNetParams.Params,
 		rHash,
@@ -2047,26 +2822,88 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		Receipt:        invoice.Receipt,
 		PaymentRequest: []byte(payReqString),
 		Terms: channeldb.ContractTerm{
-			Value: amtMSat,
+			Value:       amtMSat,
+			PaymentHash: rHash,
+			Hold:        invoice.Hold,
+			PaymentAddr: paymentAddr,
 		},
 	}
 	copy(i.Terms.PaymentPreimage[:], paymentPreimage[:])
 
-	rpcsLog.Tracef("[addinvoice] adding new invoice %v",
-		newLogClosure(func() string {
-			return spew.Sdump(i)
-		}),
-	)
+	return i, nil
+}
 
-	// With all sanity checks passed, write the invoice to the database.
-	if err := r.server.invoices.AddInvoice(i); err != nil {
+// privateRouteHint returns a routing hint for the private channel with the
+// largest receivable bandwidth, and an online peer, such that a payer is able
+// to route through it to reach us. If we have no such channel, then nil is
+// returned.
+func (r *rpcServer) privateRouteHint() ([]zpay32.ExtraRoutingInfo, error) {
+	openChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
 		return nil, err
 	}
 
-	return &lnrpc.AddInvoiceResponse{
-		RHash:          rHash[:],
-		PaymentRequest: payReqString,
-	}, nil
+	var (
+		hintChan       *channeldb.OpenChannel
+		hintReceivable lnwire.MilliSatoshi
+	)
+	for _, c := range openChannels {
+		if c.IsPending || c.ChannelFlags&lnwire.FFAnnounceChannel != 0 {
+			continue
+		}
+		if _, err := r.server.FindPeer(c.IdentityPub); err != nil {
+			continue
+		}
+
+		// If the channel's link is active, then we'll use its current
+		// receivable bandwidth, as it accounts for any HTLCs that
+		// have yet to be locked in.
+		_, receivable := channelBandwidth(c)
+		chanID := lnwire.NewChanIDFromOutPoint(&c.FundingOutpoint)
+		if link, err := r.server.htlcSwitch.GetLink(chanID); err == nil {
+			receivable = link.ReceivableBandwidth()
+		}
+
+		if hintChan == nil || receivable > hintReceivable {
+			hintChan = c
+			hintReceivable = receivable
+		}
+	}
+	if hintChan == nil {
+		return nil, nil
+	}
+
+	// We'll assume the remote party uses the same forwarding policy as
+	// us, unless it has sent us its own policy for the channel.
+	policy := r.server.cc.routingPolicy
+	hint := zpay32.ExtraRoutingInfo{
+		PubKey:                    hintChan.IdentityPub,
+		ShortChanID:               hintChan.ShortChanID.ToUint64(),
+		FeeBaseMsat:               uint32(policy.BaseFee),
+		FeeProportionalMillionths: uint32(policy.FeeRate),
+		CltvExpDelta:              uint16(policy.TimeLockDelta),
+	}
+
+	graph := r.server.chanDB.ChannelGraph()
+	info, e1, e2, err := graph.FetchChannelEdgesByOutpoint(
+		&hintChan.FundingOutpoint,
+	)
+	if err == nil {
+		remotePolicy := e1
+		if info.NodeKey2.IsEqual(hintChan.IdentityPub) {
+			remotePolicy = e2
+		}
+
+		if remotePolicy != nil {
+			hint.FeeBaseMsat = uint32(remotePolicy.FeeBaseMSat)
+			hint.FeeProportionalMillionths = uint32(
+				remotePolicy.FeeProportionalMillionths,
+			)
+			hint.CltvExpDelta = remotePolicy.TimeLockDelta
+		}
+	}
+
+	return []zpay32.ExtraRoutingInfo{hint}, nil
 }
 
 // createRPCInvoice creates an *lnrpc.Invoice from the *channeldb.Invoice.
@@ -2103,6 +2940,56 @@ func createRPCInvoice(invoice *channeldb.Invoice) (*lnrpc.Invoice, error) {
 	preimage := invoice.Terms.PaymentPreimage
 	satAmt := invoice.Terms.Value.ToSatoshis()
 
+	var holdState string
+	if invoice.Terms.Hold {
+		holdState = invoice.Terms.HoldState.String()
+	}
+
+	var paymentAddr []byte
+	if invoice.Terms.PaymentAddr != [32]byte{} {
+		paymentAddr = invoice.Terms.PaymentAddr[:]
+	}
+
+	var state lnrpc.Invoice_InvoiceState
+	switch invoice.Terms.State {
+	case channeldb.ContractOpen:
+		state = lnrpc.Invoice_OPEN
+	case channeldb.ContractSettled:
+		state = lnrpc.Invoice_SETTLED
+	case channeldb.ContractCanceled:
+		state = lnrpc.Invoice_CANCELED
+	case channeldb.ContractAccepted:
+		state = lnrpc.Invoice_ACCEPTED
+	default:
+		return nil, fmt.Errorf("unknown invoice state %v",
+			invoice.Terms.State)
+	}
+
+	htlcs := make([]*lnrpc.InvoiceHTLC, 0, len(invoice.Htlcs))
+	for _, htlc := range invoice.Htlcs {
+		var htlcState lnrpc.InvoiceHTLC_HTLCState
+		switch htlc.State {
+		case channeldb.HTLCAccepted:
+			htlcState = lnrpc.InvoiceHTLC_ACCEPTED
+		case channeldb.HTLCSettled:
+			htlcState = lnrpc.InvoiceHTLC_SETTLED
+		case channeldb.HTLCCanceled:
+			htlcState = lnrpc.InvoiceHTLC_CANCELED
+		default:
+			return nil, fmt.Errorf("unknown htlc state %v",
+				htlc.State)
+		}
+
+		htlcs = append(htlcs, &lnrpc.InvoiceHTLC{
+			ChanId:       htlc.ChanID.ToUint64(),
+			HtlcIndex:    htlc.HtlcIndex,
+			AmtMsat:      uint64(htlc.Amt),
+			ExpiryHeight: htlc.Expiry,
+			AcceptTime:   htlc.AcceptTime.Unix(),
+			State:        htlcState,
+		})
+	}
+
 	return &lnrpc.Invoice{
 		Memo:            string(invoice.Memo[:]),
 		Receipt:         invoice.Receipt[:],
@@ -2111,12 +2998,18 @@ func createRPCInvoice(invoice *channeldb.Invoice) (*lnrpc.Invoice, error) {
 		Value:           int64(satAmt),
 		CreationDate:    invoice.CreationDate.Unix(),
 		SettleDate:      settleDate,
-		Settled:         invoice.Terms.Settled,
+		Settled:         state == lnrpc.Invoice_SETTLED,
 		PaymentRequest:  paymentRequest,
 		DescriptionHash: descHash,
 		Expiry:          expiry,
 		CltvExpiry:      cltvExpiry,
 		FallbackAddr:    fallbackAddr,
+		Hold:            invoice.Terms.Hold,
+		HoldState:       holdState,
+		AmtPaidMsat:     int64(invoice.AmtPaid),
+		State:           state,
+		Htlcs:           htlcs,
+		PaymentAddr:     paymentAddr,
 	}, nil
 }
 
@@ -2229,11 +3122,67 @@ func (r *rpcServer) SubscribeInvoices(req *lnrpc.InvoiceSubscription,
 	defer invoiceClient.Cancel()
 
 	for {
+		var invoice *channeldb.Invoice
 		select {
-		// TODO(roasbeef): include newly added invoices?
-		case settledInvoice := <-invoiceClient.SettledInvoices:
+		case invoice = <-invoiceClient.NewInvoices:
+		case invoice = <-invoiceClient.SettledInvoices:
+		case <-r.quit:
+			return nil
+		}
+
+		rpcInvoice, err := createRPCInvoice(invoice)
+		if err != nil {
+			return err
+		}
+
+		if err := updateStream.Send(rpcInvoice); err != nil {
+			return err
+		}
+	}
+}
+
+// SubscribeSingleInvoice returns a uni-directional stream (server -> client)
+// over which the updates of a single invoice are sent, starting with its
+// current state.
+func (r *rpcServer) SubscribeSingleInvoice(req *lnrpc.PaymentHash,
+	updateStream lnrpc.Lightning_SubscribeSingleInvoiceServer) error {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(updateStream.Context(),
+			"readinvoices", r.authSvc); err != nil {
+			return err
+		}
+	}
+
+	rHash := req.RHash
+	if req.RHashStr != "" {
+		var err error
+		rHash, err = hex.DecodeString(req.RHashStr)
+		if err != nil {
+			return err
+		}
+	}
+	if len(rHash) != 32 {
+		return fmt.Errorf("payment hash must be exactly 32 bytes, is "+
+			"instead %v", len(rHash))
+	}
+
+	var payHash chainhash.Hash
+	copy(payHash[:], rHash)
+
+	invoiceClient, err := r.server.invoices.SubscribeSingleInvoice(
+		payHash,
+	)
+	if err != nil {
+		return err
+	}
+	defer invoiceClient.Cancel()
 
-			rpcInvoice, err := createRPCInvoice(settledInvoice)
+	for {
+		select {
+		case invoice := <-invoiceClient.Updates:
+			rpcInvoice, err := createRPCInvoice(invoice)
 			if err != nil {
 				return err
 			}
@@ -2241,6 +3190,10 @@ func (r *rpcServer) SubscribeInvoices(req *lnrpc.InvoiceSubscription,
 			if err := updateStream.Send(rpcInvoice); err != nil {
 				return err
 			}
+
+		case <-updateStream.Context().Done():
+			return updateStream.Context().Err()
+
 		case <-r.quit:
 			return nil
 		}
@@ -2398,6 +3351,12 @@ func (r *rpcServer) DescribeGraph(ctx context.Context,
 	err = graph.ForEachChannel(func(edgeInfo *channeldb.ChannelEdgeInfo,
 		c1, c2 *channeldb.ChannelEdgePolicy) error {
 
+		// Private channels aren't announced to the greater network,
+		// so we'll exclude them, along with their policies.
+		if edgeInfo.AuthProof == nil {
+			return nil
+		}
+
 		edge := marshalDbEdge(edgeInfo, c1, c2)
 		resp.Edges = append(resp.Edges, edge)
 		return nil
@@ -2624,21 +3583,79 @@ func marshallRoute(route *routing.Route) *lnrpc.Route {
 		TotalTimeLock: route.TotalTimeLock,
 		TotalFees:     int64(route.TotalFees.ToSatoshis()),
 		TotalAmt:      int64(route.TotalAmount.ToSatoshis()),
+		TotalFeesMsat: int64(route.TotalFees),
+		TotalAmtMsat:  int64(route.TotalAmount),
 		Hops:          make([]*lnrpc.Hop, len(route.Hops)),
 	}
 	for i, hop := range route.Hops {
+		pubKey := hop.Channel.Node.PubKey.SerializeCompressed()
 		resp.Hops[i] = &lnrpc.Hop{
-			ChanId:       hop.Channel.ChannelID,
-			ChanCapacity: int64(hop.Channel.Capacity),
-			AmtToForward: int64(hop.AmtToForward.ToSatoshis()),
-			Fee:          int64(hop.Fee.ToSatoshis()),
-			Expiry:       uint32(hop.OutgoingTimeLock),
+			ChanId:           hop.Channel.ChannelID,
+			ChanCapacity:     int64(hop.Channel.Capacity),
+			AmtToForward:     int64(hop.AmtToForward.ToSatoshis()),
+			Fee:              int64(hop.Fee.ToSatoshis()),
+			Expiry:           uint32(hop.OutgoingTimeLock),
+			PubKey:           hex.EncodeToString(pubKey),
+			AmtToForwardMsat: int64(hop.AmtToForward),
+			FeeMsat:          int64(hop.Fee),
 		}
 	}
 
 	return resp
 }
 
+// unmarshallRoute converts a route specified over RPC into a routing.Route
+// which can be used to craft the onion of a payment. As the route may not
+// have been computed from our channel graph, only the information required
+// to craft the onion is populated.
+func unmarshallRoute(rpcRoute *lnrpc.Route) (*routing.Route, error) {
+	hops := make([]*routing.Hop, len(rpcRoute.Hops))
+	for i, rpcHop := range rpcRoute.Hops {
+		pubBytes, err := hex.DecodeString(rpcHop.PubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of hop %v: "+
+				"%v", i, err)
+		}
+		pubKey, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of hop %v: "+
+				"%v", i, err)
+		}
+
+		// The amounts in milli-satoshis take precedence, as they're
+		// required to express fees of less than a satoshi.
+		amtToForward := lnwire.MilliSatoshi(rpcHop.AmtToForwardMsat)
+		if amtToForward == 0 {
+			amtToForward = lnwire.NewMSatFromSatoshis(
+				btcutil.Amount(rpcHop.AmtToForward),
+			)
+		}
+		fee := lnwire.MilliSatoshi(rpcHop.FeeMsat)
+		if fee == 0 {
+			fee = lnwire.NewMSatFromSatoshis(
+				btcutil.Amount(rpcHop.Fee),
+			)
+		}
+
+		hops[i] = &routing.Hop{
+			Channel: &routing.ChannelHop{
+				Capacity: btcutil.Amount(rpcHop.ChanCapacity),
+				ChannelEdgePolicy: &channeldb.ChannelEdgePolicy{
+					ChannelID: rpcHop.ChanId,
+					Node: &channeldb.LightningNode{
+						PubKey: pubKey,
+					},
+				},
+			},
+			OutgoingTimeLock: rpcHop.Expiry,
+			AmtToForward:     amtToForward,
+			Fee:              fee,
+		}
+	}
+
+	return routing.NewRouteFromHops(rpcRoute.TotalTimeLock, hops)
+}
+
 // GetNetworkInfo returns some basic stats about the known channel graph from
 // the PoV of the node.
 func (r *rpcServer) GetNetworkInfo(ctx context.Context,
@@ -3050,6 +4067,11 @@ func (r *rpcServer) DecodePayReq(ctx context.Context,
 		amt = int64(payReq.MilliSat.ToSatoshis())
 	}
 
+	paymentAddr := ""
+	if payReq.PaymentAddr != nil {
+		paymentAddr = hex.EncodeToString(payReq.PaymentAddr[:])
+	}
+
 	dest := payReq.Destination.SerializeCompressed()
 	return &lnrpc.PayReq{
 		Destination:     hex.EncodeToString(dest),
@@ -3061,6 +4083,7 @@ func (r *rpcServer) DecodePayReq(ctx context.Context,
 		FallbackAddr:    fallbackAddr,
 		Expiry:          expiry,
 		CltvExpiry:      int64(payReq.MinFinalCLTVExpiry()),
+		PaymentAddr:     paymentAddr,
 	}, nil
 }
 
@@ -3121,14 +4144,11 @@ func (r *rpcServer) FeeReport(ctx context.Context,
 	}, nil
 }
 
-// minFeeRate is the smallest permitted fee rate within the network. This is
-// dervied by the fact that fee rates are computed using a fixed point of
-// 1,000,000. As a result, the smallest representable fee rate is 1e-6, or
-// 0.000001, or 0.0001%.
-const minFeeRate = 1e-6
-
 // UpdateChannelPolicy allows the caller to update the channel forwarding policy
-// for all channels globally, or a particular channel.
+// for all channels globally, a particular channel, a set of channels, or all
+// channels with a peer. The update is validated against each targeted channel
+// before anything is applied, and only committed to the channels that pass
+// validation. Those that don't are returned along with the reason why.
 func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 	req *lnrpc.PolicyUpdateRequest) (*lnrpc.PolicyUpdateResponse, error) {
 
@@ -3139,39 +4159,42 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 		}
 	}
 
-	var targetChans []wire.OutPoint
-	switch scope := req.Scope.(type) {
-	// If the request is targeting all active channels, then we don't need
-	// target any channels by their channel point.
-	case *lnrpc.PolicyUpdateRequest_Global:
+	if err := validatePolicyUpdate(req, cfg.MinCltvDelta); err != nil {
+		return nil, err
+	}
+
+	// With the policy itself validated, we'll resolve the scope of the
+	// update into the set of channels it targets.
+	channels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+	pendingChans, err := r.server.chanDB.FetchPendingChannels()
+	if err != nil {
+		return nil, err
+	}
+	channels = append(channels, pendingChans...)
 
-	// Otherwise, we're targeting an individual channel by its channel
-	// point.
-	case *lnrpc.PolicyUpdateRequest_ChanPoint:
-		txid, err := chainhash.NewHash(scope.ChanPoint.FundingTxid)
-		if err != nil {
-			return nil, err
-		}
-		targetChans = append(targetChans, wire.OutPoint{
-			Hash:  *txid,
-			Index: scope.ChanPoint.OutputIndex,
-		})
-	default:
-		return nil, fmt.Errorf("unknown scope: %v", scope)
+	targetChans, failedUpdates, err := policyUpdateTargets(req, channels)
+	if err != nil {
+		return nil, err
 	}
 
-	// As a sanity check, we'll ensure that the passed fee rate is below
-	// 1e-6, or the lowest allowed fee rate, and that the passed timelock
-	// is large enough.
-	if req.FeeRate < minFeeRate {
-		return nil, fmt.Errorf("fee rate of %v is too small, min fee "+
-			"rate is %v", req.FeeRate, minFeeRate)
+	// Each targeted channel must also be able to carry the new policy. If
+	// a channel can't, then it's excluded from the update, and reported
+	// back to the caller.
+	validChans, invalidUpdates := policyUpdateChans(req, targetChans)
+	failedUpdates = append(failedUpdates, invalidUpdates...)
+
+	resp := &lnrpc.PolicyUpdateResponse{
+		FailedUpdates: failedUpdates,
 	}
 
-	if req.TimeLockDelta < minTimeLockDelta {
-		return nil, fmt.Errorf("time lock delta of %v is too small, "+
-			"minimum supported is %v", req.TimeLockDelta,
-			minTimeLockDelta)
+	// If none of the targeted channels are able to carry the new policy,
+	// then there's nothing left to do. We return early, as an empty set
+	// of channels would otherwise target all of our channels below.
+	if len(validChans) == 0 {
+		return resp, nil
 	}
 
 	// We'll also need to convert the floating point fee rate we accept
@@ -3192,15 +4215,18 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 	}
 
 	rpcsLog.Tracef("[updatechanpolicy] updating channel policy base_fee=%v, "+
-		"rate_float=%v, rate_fixed=%v, time_lock_delta: %v, targets=%v",
-		req.BaseFeeMsat, req.FeeRate, feeRateFixed, req.TimeLockDelta,
-		spew.Sdump(targetChans))
+		"rate_float=%v, rate_fixed=%v, time_lock_delta: %v, "+
+		"max_htlc=%v, targets=%v", req.BaseFeeMsat, req.FeeRate,
+		feeRateFixed, req.TimeLockDelta, req.MaxHtlcMsat,
+		spew.Sdump(validChans))
 
 	// With the scope resolved, we'll now send this to the
 	// AuthenticatedGossiper so it can propagate the new policy for our
-	// target channel(s).
-	err := r.server.authGossiper.PropagateChanPolicyUpdate(
-		chanPolicy, targetChans...,
+	// target channel(s). If this fails, then we return before updating
+	// any links, so that the policy we enforce doesn't diverge from the
+	// one we advertise.
+	err = r.server.authGossiper.PropagateChanPolicyUpdate(
+		chanPolicy, validChans...,
 	)
 	if err != nil {
 		return nil, err
@@ -3215,14 +4241,1465 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 		BaseFee:       baseFeeMsat,
 		FeeRate:       lnwire.MilliSatoshi(feeRateFixed),
 		TimeLockDelta: req.TimeLockDelta,
+		MaxHTLC:       lnwire.MilliSatoshi(req.MaxHtlcMsat),
 	}
-	err = r.server.htlcSwitch.UpdateForwardingPolicies(p, targetChans...)
+	err = r.server.htlcSwitch.UpdateForwardingPolicies(p, validChans...)
 	if err != nil {
-		// If we're unable update the fees due to the links not being
-		// online, then we don't need to fail the call. We'll simply
-		// log the failure.
+		// If we're unable update the fees due to the switch shutting
+		// down, then we don't need to fail the call. We'll simply log
+		// the failure.
 		rpcsLog.Warnf("Unable to update link fees: %v", err)
 	}
 
-	return &lnrpc.PolicyUpdateResponse{}, nil
+	return resp, nil
+}
+
+// ExportGraph returns a snapshot of the node's validated channel graph. The
+// snapshot can later be loaded by a fresh node via ImportGraph in order to
+// skip the initial graph sync.
+func (r *rpcServer) ExportGraph(ctx context.Context,
+	req *lnrpc.ExportGraphRequest) (*lnrpc.ExportGraphResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "exportgraph",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	rpcsLog.Debugf("[exportgraph]")
+
+	var snapshot bytes.Buffer
+	stats, err := r.server.authGossiper.ExportGraph(&snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ExportGraphResponse{
+		NumChannels: stats.NumChannels,
+		NumUpdates:  stats.NumUpdates,
+		NumNodes:    stats.NumNodes,
+		Snapshot:    snapshot.Bytes(),
+	}, nil
+}
+
+// ImportGraph loads a channel graph snapshot created by ExportGraph into the
+// node's channel graph. Each announcement within the snapshot is validated
+// as if it had been received via gossip before it's applied.
+func (r *rpcServer) ImportGraph(ctx context.Context,
+	req *lnrpc.ImportGraphRequest) (*lnrpc.ImportGraphResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "importgraph",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(req.Snapshot) == 0 {
+		return nil, fmt.Errorf("a graph snapshot must be specified")
+	}
+
+	rpcsLog.Infof("[importgraph] importing graph snapshot of %v bytes",
+		len(req.Snapshot))
+
+	stats, err := r.server.authGossiper.ImportGraph(
+		bytes.NewReader(req.Snapshot),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[importgraph] imported %v channels, %v channel "+
+		"updates and %v nodes, skipped %v messages", stats.NumChannels,
+		stats.NumUpdates, stats.NumNodes, stats.NumSkipped)
+
+	return &lnrpc.ImportGraphResponse{
+		NumChannels: stats.NumChannels,
+		NumUpdates:  stats.NumUpdates,
+		NumNodes:    stats.NumNodes,
+		NumSkipped:  stats.NumSkipped,
+	}, nil
+}
+
+// forwardingFilterResponse returns the current state of the switch's
+// forwarding filter.
+func (r *rpcServer) forwardingFilterResponse() *lnrpc.ForwardingFilterResponse {
+	allowed, denied := r.server.htlcSwitch.ForwardingFilter().Lists()
+
+	resp := &lnrpc.ForwardingFilterResponse{
+		Allowed: make([]string, 0, len(allowed)),
+		Denied:  make([]string, 0, len(denied)),
+	}
+	for _, peer := range allowed {
+		resp.Allowed = append(resp.Allowed, hex.EncodeToString(peer[:]))
+	}
+	for _, peer := range denied {
+		resp.Denied = append(resp.Denied, hex.EncodeToString(peer[:]))
+	}
+
+	return resp
+}
+
+// ForwardingFilter returns the current forwarding allow and deny lists, which
+// determine the peers the node will forward HTLCs from or to.
+func (r *rpcServer) ForwardingFilter(ctx context.Context,
+	req *lnrpc.ForwardingFilterRequest) (*lnrpc.ForwardingFilterResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "forwardingfilter",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.forwardingFilterResponse(), nil
+}
+
+// UpdateForwardingFilter adds peers to, or removes peers from, the forwarding
+// allow and deny lists. The changes take effect immediately, but aren't
+// persisted across restarts.
+func (r *rpcServer) UpdateForwardingFilter(ctx context.Context,
+	req *lnrpc.UpdateForwardingFilterRequest) (*lnrpc.ForwardingFilterResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "updateforwardingfilter",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	// We'll parse all the keys up front, so an invalid key doesn't leave
+	// the filter partially updated.
+	allow, err := parsePeerPubKeys(req.Allow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allow list: %v", err)
+	}
+	deny, err := parsePeerPubKeys(req.Deny)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deny list: %v", err)
+	}
+	remove, err := parsePeerPubKeys(req.Remove)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remove list: %v", err)
+	}
+
+	rpcsLog.Infof("[updateforwardingfilter] allow=%v, deny=%v, "+
+		"remove=%v", req.Allow, req.Deny, req.Remove)
+
+	filter := r.server.htlcSwitch.ForwardingFilter()
+	for _, peer := range allow {
+		filter.Allow(peer)
+	}
+	for _, peer := range deny {
+		filter.Deny(peer)
+	}
+	for _, peer := range remove {
+		filter.Remove(peer)
+	}
+
+	return r.forwardingFilterResponse(), nil
+}
+
+// SubscribePeerMessages streams the decoded wire messages sent to, and
+// received from, the target peer, with any payment preimages redacted. It's
+// only available if the message tap has been enabled.
+func (r *rpcServer) SubscribePeerMessages(req *lnrpc.PeerMessageSubscription,
+	updateStream lnrpc.Lightning_SubscribePeerMessagesServer) error {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(updateStream.Context(),
+			"subscribepeermessages", r.authSvc); err != nil {
+			return err
+		}
+	}
+
+	if r.server.messageTap == nil {
+		return fmt.Errorf("message tap not enabled, start lnd with " +
+			"--debugmessagetap to use it")
+	}
+
+	peers, err := parsePeerPubKeys([]string{req.PubKey})
+	if err != nil {
+		return err
+	}
+
+	rpcsLog.Infof("[subscribepeermessages] tapping messages of peer %v",
+		req.PubKey)
+
+	sub := r.server.messageTap.Subscribe(peers[0])
+	defer sub.Cancel()
+
+	for {
+		select {
+		case msg := <-sub.Messages:
+			err := updateStream.Send(&lnrpc.PeerMessage{
+				PubKey:      hex.EncodeToString(msg.peer[:]),
+				Inbound:     msg.inbound,
+				MsgType:     msg.msgType.String(),
+				Summary:     msg.summary,
+				Dump:        msg.dump,
+				TimestampNs: msg.timestamp.UnixNano(),
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-updateStream.Context().Done():
+			return nil
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// LiquidityHistory returns the recorded snapshots of the balances and pending
+// HTLCs of the target channel within the given time range, in chronological
+// order.
+func (r *rpcServer) LiquidityHistory(ctx context.Context,
+	req *lnrpc.LiquidityHistoryRequest) (*lnrpc.LiquidityHistoryResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "liquidityhistory",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ChanPoint == nil {
+		return nil, fmt.Errorf("chan_point must be specified")
+	}
+
+	var txid *chainhash.Hash
+	var err error
+	if req.ChanPoint.FundingTxidStr != "" {
+		txid, err = chainhash.NewHashFromStr(req.ChanPoint.FundingTxidStr)
+	} else {
+		txid, err = chainhash.NewHash(req.ChanPoint.FundingTxid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, req.ChanPoint.OutputIndex)
+
+	startTime := time.Unix(int64(req.StartTime), 0)
+	endTime := time.Now()
+	if req.EndTime != 0 {
+		endTime = time.Unix(int64(req.EndTime), 0)
+	}
+	if endTime.Before(startTime) {
+		return nil, fmt.Errorf("end_time must not precede start_time")
+	}
+
+	rpcsLog.Debugf("[liquidityhistory] ChannelPoint(%v), start=%v, end=%v",
+		chanPoint, startTime, endTime)
+
+	snapshots, err := r.server.chanDB.FetchLiquidityHistory(
+		chanPoint, startTime, endTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.LiquidityHistoryResponse{
+		Snapshots: make([]*lnrpc.LiquiditySnapshot, 0, len(snapshots)),
+	}
+	for _, snapshot := range snapshots {
+		resp.Snapshots = append(resp.Snapshots, &lnrpc.LiquiditySnapshot{
+			Timestamp:         uint64(snapshot.Timestamp.Unix()),
+			LocalBalanceMsat:  uint64(snapshot.LocalBalance),
+			RemoteBalanceMsat: uint64(snapshot.RemoteBalance),
+			PendingHtlcMsat:   uint64(snapshot.PendingHTLCValue),
+			NumPendingHtlcs:   uint32(snapshot.NumPendingHTLCs),
+		})
+	}
+
+	return resp, nil
+}
+
+// InjectSwitchFault injects a fault into the forwarding path of the htlc
+// switch, which drops, delays or duplicates the next matching packets. It's
+// only available if fault injection has been enabled.
+func (r *rpcServer) InjectSwitchFault(ctx context.Context,
+	req *lnrpc.InjectSwitchFaultRequest) (*lnrpc.InjectSwitchFaultResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "injectswitchfault",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	faults := r.server.switchFaults
+	if faults == nil {
+		return nil, fmt.Errorf("switch fault injection not enabled, " +
+			"start lnd with --debugswitchfaults to use it")
+	}
+
+	if req.Clear {
+		rpcsLog.Infof("[injectswitchfault] clearing %v pending faults",
+			faults.Pending())
+
+		faults.Clear()
+		return &lnrpc.InjectSwitchFaultResponse{}, nil
+	}
+
+	rule := htlcswitch.FaultRule{
+		Count: req.Count,
+		Delay: time.Duration(req.DelayMs) * time.Millisecond,
+	}
+	if rule.Count == 0 {
+		rule.Count = 1
+	}
+
+	switch req.Action {
+	case "drop":
+		rule.Action = htlcswitch.FaultDrop
+	case "delay":
+		rule.Action = htlcswitch.FaultDelay
+	case "duplicate":
+		rule.Action = htlcswitch.FaultDuplicate
+	default:
+		return nil, fmt.Errorf("unknown fault action %q", req.Action)
+	}
+
+	switch req.UpdateType {
+	case "":
+	case "add":
+		rule.MsgType = lnwire.MsgUpdateAddHTLC
+	case "settle":
+		rule.MsgType = lnwire.MsgUpdateFufillHTLC
+	case "fail":
+		rule.MsgType = lnwire.MsgUpdateFailHTLC
+	default:
+		return nil, fmt.Errorf("unknown update type %q", req.UpdateType)
+	}
+
+	if err := faults.Inject(rule); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.InjectSwitchFaultResponse{
+		NumPending: uint32(faults.Pending()),
+	}, nil
+}
+
+// UpdateChanStatus pins the disabled bit of the ChannelUpdate announced for
+// the target channel, or releases a prior pin, returning the channel to the
+// status of all other channels.
+func (r *rpcServer) UpdateChanStatus(ctx context.Context,
+	req *lnrpc.UpdateChanStatusRequest) (*lnrpc.UpdateChanStatusResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "updatechanstatus",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ChanPoint == nil {
+		return nil, fmt.Errorf("chan_point must be specified")
+	}
+
+	var txid *chainhash.Hash
+	var err error
+	if req.ChanPoint.FundingTxidStr != "" {
+		txid, err = chainhash.NewHashFromStr(req.ChanPoint.FundingTxidStr)
+	} else {
+		txid, err = chainhash.NewHash(req.ChanPoint.FundingTxid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, req.ChanPoint.OutputIndex)
+
+	rpcsLog.Infof("[updatechanstatus] action=%v, chan_point=%v",
+		req.Action, chanPoint)
+
+	gossiper := r.server.authGossiper
+	switch req.Action {
+	case "disable":
+		err = gossiper.PinChanStatus(*chanPoint, true)
+	case "enable":
+		err = gossiper.PinChanStatus(*chanPoint, false)
+	case "auto":
+		err = gossiper.UnpinChanStatus(*chanPoint)
+	default:
+		return nil, fmt.Errorf("unknown channel status action %q",
+			req.Action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.UpdateChanStatusResponse{}, nil
+}
+
+// SettleHoldInvoice releases the preimage of a hold invoice, settling the
+// HTLCs paying to it which are held by their links. The invoice itself is
+// marked as settled once its HTLCs have been settled.
+func (r *rpcServer) SettleHoldInvoice(ctx context.Context,
+	req *lnrpc.SettleHoldInvoiceRequest) (*lnrpc.SettleHoldInvoiceResponse,
+	error) {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "addinvoice",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(req.RPreimage) != 32 {
+		return nil, fmt.Errorf("payment preimage must be exactly "+
+			"32 bytes, is instead %v", len(req.RPreimage))
+	}
+
+	var preimage [32]byte
+	copy(preimage[:], req.RPreimage)
+	payHash := sha256.Sum256(preimage[:])
+
+	rpcsLog.Infof("[settleholdinvoice] releasing preimage of invoice %x",
+		payHash[:])
+
+	err := r.server.invoices.SettleHodlInvoice(payHash, preimage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SettleHoldInvoiceResponse{}, nil
+}
+
+// CancelHoldInvoice cancels a hold invoice whose preimage hasn't yet been
+// released, failing the HTLCs paying to it back to their senders. Any HTLCs
+// paying to the invoice in the future are failed as well.
+func (r *rpcServer) CancelHoldInvoice(ctx context.Context,
+	req *lnrpc.PaymentHash) (*lnrpc.CancelHoldInvoiceResponse, error) {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "addinvoice",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		payHash [32]byte
+		rHash   []byte
+		err     error
+	)
+
+	// If the RHash as a raw string was provided, then decode that and use
+	// that directly. Otherwise, we use the raw bytes provided.
+	if req.RHashStr != "" {
+		rHash, err = hex.DecodeString(req.RHashStr)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		rHash = req.RHash
+	}
+
+	if len(rHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be exactly "+
+			"32 bytes, is instead %v", len(rHash))
+	}
+	copy(payHash[:], rHash)
+
+	rpcsLog.Infof("[cancelholdinvoice] canceling invoice %x", payHash[:])
+
+	if err := r.server.invoices.CancelHodlInvoice(payHash); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.CancelHoldInvoiceResponse{}, nil
+}
+
+// ForwardingHistory returns the HTLCs successfully forwarded by the node
+// within the requested time range, in chronological order, paginated by the
+// requested index offset.
+func (r *rpcServer) ForwardingHistory(ctx context.Context,
+	req *lnrpc.ForwardingHistoryRequest) (*lnrpc.ForwardingHistoryResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "forwardinghistory",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	startTime := time.Unix(int64(req.StartTime), 0)
+	endTime := time.Now()
+	if req.EndTime != 0 {
+		endTime = time.Unix(int64(req.EndTime), 0)
+	}
+	if endTime.Before(startTime) {
+		return nil, fmt.Errorf("end_time must not precede start_time")
+	}
+
+	numMaxEvents := req.NumMaxEvents
+	if numMaxEvents == 0 {
+		numMaxEvents = defaultNumFwdingEvents
+	}
+
+	rpcsLog.Debugf("[forwardinghistory] start=%v, end=%v, offset=%v, "+
+		"max_events=%v", startTime, endTime, req.IndexOffset,
+		numMaxEvents)
+
+	timeSlice, err := r.server.chanDB.ForwardingLog().Query(
+		channeldb.ForwardingEventQuery{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			IndexOffset:  req.IndexOffset,
+			NumMaxEvents: numMaxEvents,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ForwardingHistoryResponse{
+		ForwardingEvents: make(
+			[]*lnrpc.ForwardingEvent, 0,
+			len(timeSlice.ForwardingEvents),
+		),
+		LastOffsetIndex: timeSlice.LastIndexOffset,
+	}
+	for _, event := range timeSlice.ForwardingEvents {
+		resp.ForwardingEvents = append(resp.ForwardingEvents,
+			&lnrpc.ForwardingEvent{
+				Timestamp:  uint64(event.Timestamp.Unix()),
+				ChanIdIn:   event.IncomingChanID.ToUint64(),
+				ChanIdOut:  event.OutgoingChanID.ToUint64(),
+				AmtInMsat:  uint64(event.AmtIn),
+				AmtOutMsat: uint64(event.AmtOut),
+				FeeMsat:    uint64(event.Fee()),
+			},
+		)
+	}
+
+	return resp, nil
+}
+
+// BatchAddInvoice creates a batch of invoices sharing the parameters of the
+// passed template, each with a fresh preimage, and adds them to the invoice
+// database within a single transaction. The invoices are returned in the
+// order they were added.
+func (r *rpcServer) BatchAddInvoice(ctx context.Context,
+	req *lnrpc.BatchAddInvoiceRequest) (*lnrpc.BatchAddInvoiceResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "addinvoice",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	template := req.Invoice
+	switch {
+	case template == nil:
+		return nil, fmt.Errorf("an invoice template must be specified")
+
+	case req.NumInvoices == 0 || req.NumInvoices > maxBatchInvoices:
+		return nil, fmt.Errorf("num_invoices must be between 1 and %v",
+			maxBatchInvoices)
+
+	// As each invoice of the batch must have a unique payment hash, the
+	// template may specify neither a preimage nor a payment hash.
+	case len(template.RPreimage) != 0 || len(template.RHash) != 0:
+		return nil, fmt.Errorf("r_preimage and r_hash may not be set " +
+			"within an invoice template")
+	}
+
+	// The routing hint, if requested, is shared by the entire batch, so
+	// we'll only select it once.
+	var routeHint []zpay32.ExtraRoutingInfo
+	if template.Private {
+		var err error
+		routeHint, err = r.privateRouteHint()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	invoices := make([]*channeldb.Invoice, 0, req.NumInvoices)
+	for i := uint32(0); i < req.NumInvoices; i++ {
+		invoice, err := r.newInvoice(template, routeHint)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	rpcsLog.Debugf("[batchaddinvoice] adding batch of %v invoices, "+
+		"value=%v", len(invoices), template.Value)
+
+	if err := r.server.invoices.AddInvoices(invoices); err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.BatchAddInvoiceResponse{
+		Invoices: make([]*lnrpc.AddInvoiceResponse, 0, len(invoices)),
+	}
+	for _, invoice := range invoices {
+		resp.Invoices = append(resp.Invoices, &lnrpc.AddInvoiceResponse{
+			RHash:          invoice.Terms.PaymentHash[:],
+			PaymentRequest: string(invoice.PaymentRequest),
+		})
+	}
+
+	return resp, nil
+}
+
+// TimeLockedBalance returns all of our funds which are currently time-locked,
+// either within the outputs of force closed channels awaiting maturity, or
+// within the outgoing HTLCs of our open channels, bucketed by the block height
+// at which they become spendable.
+func (r *rpcServer) TimeLockedBalance(ctx context.Context,
+	req *lnrpc.TimeLockedBalanceRequest) (*lnrpc.TimeLockedBalanceResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "timelockedbalance",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	rpcsLog.Debugf("[timelockedbalance]")
+
+	_, currentHeight, err := r.server.cc.chainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	report := newTimeLockedReport()
+
+	openChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+	for _, channel := range openChannels {
+		report.addOpenChannel(channel)
+	}
+
+	// Only force closed channels may have outputs awaiting maturity within
+	// the nursery, as the outputs of cooperatively closed channels are
+	// spendable as soon as the closing transaction confirms.
+	pendingCloseChannels, err := r.server.chanDB.FetchClosedChannels(true)
+	if err != nil {
+		return nil, err
+	}
+	for _, pendingClose := range pendingCloseChannels {
+		if pendingClose.CloseType != channeldb.ForceClose {
+			continue
+		}
+
+		chanPoint := pendingClose.ChanPoint
+		nurseryInfo, err := r.server.utxoNursery.NurseryReport(&chanPoint)
+		switch {
+		case err == ErrContractNotFound:
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("unable to obtain nursery report "+
+				"for ChannelPoint(%v): %v", chanPoint, err)
+		}
+
+		report.addNurseryReport(nurseryInfo)
+	}
+
+	resp := &lnrpc.TimeLockedBalanceResponse{
+		BlockHeight: uint32(currentHeight),
+	}
+	for _, b := range report.sortedBuckets() {
+		bucket := &lnrpc.TimeLockedBucket{
+			MaturityHeight: b.maturityHeight,
+			Amount:         int64(b.csvAmount + b.cltvAmount),
+			CsvAmount:      int64(b.csvAmount),
+			CltvAmount:     int64(b.cltvAmount),
+			NumOutputs:     b.numOutputs,
+		}
+		if bucket.MaturityHeight != 0 {
+			bucket.BlocksTilMaturity = int32(bucket.MaturityHeight) -
+				currentHeight
+		}
+
+		resp.TotalTimeLockedBalance += bucket.Amount
+		resp.Buckets = append(resp.Buckets, bucket)
+	}
+
+	return resp, nil
+}
+
+// ExportDebugPackage bundles the sanitized state of all channels, along with
+// the most recent log entries, into an archive that's encrypted to the given
+// public key. No secret material is included within the package, so it can
+// be shared with maintainers when reporting stuck channels.
+func (r *rpcServer) ExportDebugPackage(ctx context.Context,
+	req *lnrpc.ExportDebugPackageRequest) (*lnrpc.ExportDebugPackageResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "exportdebugpackage",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	rpcsLog.Debugf("[exportdebugpackage] max_log_entries=%v",
+		req.MaxLogEntries)
+
+	pubKeyBytes, err := hex.DecodeString(req.RecipientPubkey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode recipient pubkey: %v",
+			err)
+	}
+	recipient, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse recipient pubkey: %v",
+			err)
+	}
+
+	pkg, err := r.server.assembleDebugPackage(int(req.MaxLogEntries))
+	if err != nil {
+		return nil, fmt.Errorf("unable to assemble debug package: %v",
+			err)
+	}
+
+	encryptedPkg, err := pkg.encrypt(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encrypt debug package: %v",
+			err)
+	}
+
+	return &lnrpc.ExportDebugPackageResponse{
+		EncryptedPackage: encryptedPkg,
+	}, nil
+}
+
+// DebugProfile collects a runtime profile of the daemon, such as a dump of
+// the stack of each running goroutine, which allows a wedged subsystem to be
+// diagnosed without access to the host. As profiles may reveal the internals
+// of the daemon, only the admin macaroon grants access to them.
+func (r *rpcServer) DebugProfile(ctx context.Context,
+	req *lnrpc.DebugProfileRequest) (*lnrpc.DebugProfileResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "debugprofile",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	rpcsLog.Debugf("[debugprofile] type=%v, duration_secs=%v", req.Type,
+		req.DurationSecs)
+
+	duration := time.Duration(req.DurationSecs) * time.Second
+	profile, truncated, err := r.profiler.collect(ctx, req.Type, duration)
+	if err != nil {
+		return nil, fmt.Errorf("unable to collect profile: %v", err)
+	}
+
+	return &lnrpc.DebugProfileResponse{
+		Profile:   profile,
+		Truncated: truncated,
+	}, nil
+}
+
+// LookupCircuit returns the payment circuits held by the switch for the HTLCs
+// paying to the requested payment hash, or the circuit whose incoming or
+// outgoing HTLC is identified by the requested channel and HTLC ID, which
+// allows stuck multi-hop payments to be debugged.
+func (r *rpcServer) LookupCircuit(ctx context.Context,
+	req *lnrpc.LookupCircuitRequest) (*lnrpc.LookupCircuitResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "lookupcircuit",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	// If the payment hash was provided as a string, then we'll decode it,
+	// otherwise we use the raw bytes provided.
+	payHash := req.PaymentHash
+	if req.PaymentHashStr != "" {
+		var err error
+		payHash, err = hex.DecodeString(req.PaymentHashStr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode payment "+
+				"hash: %v", err)
+		}
+	}
+
+	var circuits []*htlcswitch.PaymentCircuit
+	switch {
+	case len(payHash) != 0:
+		if len(payHash) != 32 {
+			return nil, fmt.Errorf("payment hash must be exactly "+
+				"32 bytes, is instead %v", len(payHash))
+		}
+
+		var hash [32]byte
+		copy(hash[:], payHash)
+
+		rpcsLog.Debugf("[lookupcircuit] payment_hash=%x", hash[:])
+
+		circuits = r.server.htlcSwitch.LookupCircuitsByHash(hash)
+
+	case req.ChanId != 0:
+		chanID := lnwire.NewShortChanIDFromInt(req.ChanId)
+
+		rpcsLog.Debugf("[lookupcircuit] chan_id=%v, htlc_id=%v",
+			chanID, req.HtlcId)
+
+		circuit := r.server.htlcSwitch.LookupCircuit(chanID, req.HtlcId)
+		if circuit != nil {
+			circuits = append(circuits, circuit)
+		}
+
+	default:
+		return nil, fmt.Errorf("either a payment hash or a channel " +
+			"ID must be specified")
+	}
+
+	resp := &lnrpc.LookupCircuitResponse{
+		Circuits: make([]*lnrpc.PaymentCircuit, 0, len(circuits)),
+	}
+	for _, circuit := range circuits {
+		resp.Circuits = append(resp.Circuits, &lnrpc.PaymentCircuit{
+			PaymentHash:     circuit.PaymentHash[:],
+			IncomingChanId:  circuit.IncomingChanID.ToUint64(),
+			IncomingHtlcId:  circuit.IncomingHTLCID,
+			IncomingAmtMsat: uint64(circuit.IncomingAmount),
+			OutgoingChanId:  circuit.OutgoingChanID.ToUint64(),
+			OutgoingHtlcId:  circuit.OutgoingHTLCID,
+			OutgoingAmtMsat: uint64(circuit.OutgoingAmount),
+			HasObfuscator:   circuit.ErrorEncrypter != nil,
+		})
+	}
+
+	return resp, nil
+}
+
+// protocolUpgrade is an optional protocol upgrade which may only be applied
+// to a channel if the channel peer supports it, as signalled by either of a
+// pair of local feature bits.
+type protocolUpgrade struct {
+	name     string
+	required lnwire.FeatureBit
+	optional lnwire.FeatureBit
+}
+
+// protocolUpgrades are the optional protocol upgrades reported on by the
+// PeerCompatibility RPC.
+var protocolUpgrades = []protocolUpgrade{
+	{
+		name:     "tlv-onion",
+		required: lnwire.TLVOnionPayloadRequired,
+		optional: lnwire.TLVOnionPayloadOptional,
+	},
+	{
+		name:     "static-remote-key",
+		required: lnwire.StaticRemoteKeyRequired,
+		optional: lnwire.StaticRemoteKeyOptional,
+	},
+	{
+		name:     "anchors",
+		required: lnwire.AnchorOutputsRequired,
+		optional: lnwire.AnchorOutputsOptional,
+	},
+}
+
+// blockedUpgrades returns the names of the protocol upgrades which the peer
+// that announced the passed local features doesn't support.
+func blockedUpgrades(localFeatures *lnwire.RawFeatureVector) []string {
+	var blocked []string
+	for _, upgrade := range protocolUpgrades {
+		if localFeatures.IsSet(upgrade.required) ||
+			localFeatures.IsSet(upgrade.optional) {
+
+			continue
+		}
+
+		blocked = append(blocked, upgrade.name)
+	}
+
+	return blocked
+}
+
+// PeerCompatibility reports, for each of our open channels, the optional
+// protocol upgrades which are blocked as the channel peer didn't announce
+// support for them.
+func (r *rpcServer) PeerCompatibility(ctx context.Context,
+	req *lnrpc.PeerCompatibilityRequest) (
+	*lnrpc.PeerCompatibilityResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "peercompatibility",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	dbChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[peercompatibility] fetched %v channels from DB",
+		len(dbChannels))
+
+	// As we'll often have several channels with the same peer, we'll
+	// only fetch the features of each peer once.
+	peerFeatures := make(map[[33]byte]*channeldb.PeerFeatures)
+
+	resp := &lnrpc.PeerCompatibilityResponse{}
+	for _, dbChannel := range dbChannels {
+		if dbChannel.IsPending {
+			continue
+		}
+
+		var peer [33]byte
+		copy(peer[:], dbChannel.IdentityPub.SerializeCompressed())
+
+		features, ok := peerFeatures[peer]
+		if !ok {
+			features, err = r.server.chanDB.FetchPeerFeatures(peer)
+			switch {
+			case err == channeldb.ErrPeerFeaturesNotFound:
+			case err != nil:
+				return nil, err
+			}
+			peerFeatures[peer] = features
+		}
+
+		compat := &lnrpc.ChannelCompatibility{
+			RemotePubkey: hex.EncodeToString(peer[:]),
+			ChannelPoint: dbChannel.FundingOutpoint.String(),
+			ChanId:       dbChannel.ShortChanID.ToUint64(),
+		}
+
+		// If the peer hasn't connected since we started caching its
+		// features, then we're unable to tell which upgrades are
+		// blocked.
+		if features != nil {
+			fingerprint, err := features.Fingerprint()
+			if err != nil {
+				return nil, err
+			}
+
+			compat.FeaturesKnown = true
+			compat.Fingerprint = fingerprint
+			compat.FeaturesUpdated = features.LastUpdate.Unix()
+			compat.BlockedUpgrades = blockedUpgrades(
+				features.LocalFeatures,
+			)
+		}
+
+		resp.Channels = append(resp.Channels, compat)
+	}
+
+	return resp, nil
+}
+
+// ListAliases lists, for each of our open channels, its confirmed short
+// channel ID, the short channel ID used for it within our invoices, and the
+// aliases accepted for forwards over it, which allows UnknownNextPeer failures
+// of forwards specifying an alias to be debugged.
+func (r *rpcServer) ListAliases(ctx context.Context,
+	req *lnrpc.ListAliasesRequest) (*lnrpc.ListAliasesResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "listaliases",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	dbChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[listaliases] fetched %v channels from DB",
+		len(dbChannels))
+
+	// We'll group the aliases registered with the switch by the channel
+	// they map to, sorting them so the output is stable.
+	aliases := r.server.htlcSwitch.ForwardingAliases()
+	chanAliases := make(map[lnwire.ShortChannelID][]uint64)
+	for alias, chanID := range aliases {
+		chanAliases[chanID] = append(
+			chanAliases[chanID], alias.ToUint64(),
+		)
+	}
+	for _, a := range chanAliases {
+		sort.Slice(a, func(i, j int) bool {
+			return a[i] < a[j]
+		})
+	}
+
+	resp := &lnrpc.ListAliasesResponse{}
+	for _, dbChannel := range dbChannels {
+		if dbChannel.IsPending {
+			continue
+		}
+
+		shortChanID := dbChannel.ShortChanID
+		channel := &lnrpc.ChannelAliases{
+			RemotePubkey: hex.EncodeToString(
+				dbChannel.IdentityPub.SerializeCompressed(),
+			),
+			ChannelPoint:      dbChannel.FundingOutpoint.String(),
+			ConfirmedChanId:   shortChanID.ToUint64(),
+			ForwardingAliases: chanAliases[shortChanID],
+		}
+
+		// Route hints are only included within our invoices for
+		// private channels, in which case they use the confirmed
+		// short channel ID.
+		if dbChannel.ChannelFlags&lnwire.FFAnnounceChannel == 0 {
+			channel.InvoiceChanId = shortChanID.ToUint64()
+		}
+
+		chanPoint := &dbChannel.FundingOutpoint
+		chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+		if _, err := r.server.htlcSwitch.GetLink(chanID); err == nil {
+			channel.Active = true
+		}
+
+		resp.Channels = append(resp.Channels, channel)
+		delete(chanAliases, shortChanID)
+	}
+
+	// Any aliases left map to a channel that isn't open, so forwards
+	// specifying them will fail.
+	for alias, chanID := range aliases {
+		if _, ok := chanAliases[chanID]; !ok {
+			continue
+		}
+
+		resp.UnknownAliases = append(
+			resp.UnknownAliases, &lnrpc.AliasMapping{
+				Alias:  alias.ToUint64(),
+				ChanId: chanID.ToUint64(),
+			},
+		)
+	}
+	sort.Slice(resp.UnknownAliases, func(i, j int) bool {
+		return resp.UnknownAliases[i].Alias <
+			resp.UnknownAliases[j].Alias
+	})
+
+	return resp, nil
+}
+
+// rpcCommitmentState converts the passed commitment into its RPC
+// representation.
+func rpcCommitmentState(
+	commit *channeldb.ChannelCommitment) *lnrpc.CommitmentState {
+
+	return &lnrpc.CommitmentState{
+		CommitHeight:      commit.CommitHeight,
+		LocalBalanceMsat:  int64(commit.LocalBalance),
+		RemoteBalanceMsat: int64(commit.RemoteBalance),
+		CommitFee:         int64(commit.CommitFee),
+		FeePerKw:          int64(commit.FeePerKw),
+		NumHtlcs:          uint32(len(commit.Htlcs)),
+		LocalLogIndex:     commit.LocalLogIndex,
+		LocalHtlcIndex:    commit.LocalHtlcIndex,
+		RemoteLogIndex:    commit.RemoteLogIndex,
+		RemoteHtlcIndex:   commit.RemoteHtlcIndex,
+	}
+}
+
+// rpcChanSyncFields converts the passed ChannelReestablish message into its
+// RPC representation.
+func rpcChanSyncFields(msg *lnwire.ChannelReestablish) *lnrpc.ChanSyncFields {
+	fields := &lnrpc.ChanSyncFields{
+		NextLocalCommitHeight:  msg.NextLocalCommitHeight,
+		RemoteCommitTailHeight: msg.RemoteCommitTailHeight,
+	}
+
+	// The commitment secret and point are optional, and only included
+	// together.
+	if msg.LocalUnrevokedCommitPoint != nil {
+		fields.LastRemoteCommitSecret = hex.EncodeToString(
+			msg.LastRemoteCommitSecret[:],
+		)
+		fields.LocalUnrevokedCommitPoint = hex.EncodeToString(
+			msg.LocalUnrevokedCommitPoint.SerializeCompressed(),
+		)
+	}
+
+	return fields
+}
+
+// InspectCommitments dumps the persisted commitment state of the target
+// channel, along with the ChannelReestablish fields last received from the
+// remote party, which allows a failure to re-synchronize the channel to be
+// diagnosed without inspecting the database directly.
+func (r *rpcServer) InspectCommitments(ctx context.Context,
+	req *lnrpc.InspectCommitmentsRequest) (
+	*lnrpc.InspectCommitmentsResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "inspectcommitments",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ChanPoint == nil {
+		return nil, fmt.Errorf("chan_point must be specified")
+	}
+
+	var txid *chainhash.Hash
+	var err error
+	if req.ChanPoint.FundingTxidStr != "" {
+		txid, err = chainhash.NewHashFromStr(
+			req.ChanPoint.FundingTxidStr,
+		)
+	} else {
+		txid, err = chainhash.NewHash(req.ChanPoint.FundingTxid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, req.ChanPoint.OutputIndex)
+
+	rpcsLog.Debugf("[inspectcommitments] chan_point=%v", chanPoint)
+
+	dbChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var channel *channeldb.OpenChannel
+	for _, dbChannel := range dbChannels {
+		if dbChannel.FundingOutpoint == *chanPoint {
+			channel = dbChannel
+			break
+		}
+	}
+	if channel == nil {
+		return nil, fmt.Errorf("unable to find channel")
+	}
+
+	resp := &lnrpc.InspectCommitmentsResponse{
+		ChanId:           channel.ShortChanID.ToUint64(),
+		Borked:           channel.IsBorked,
+		LocalCommitment:  rpcCommitmentState(&channel.LocalCommitment),
+		RemoteCommitment: rpcCommitmentState(&channel.RemoteCommitment),
+	}
+	resp.NextLocalCommitHeight = channel.LocalCommitment.CommitHeight + 1
+	resp.NextRemoteCommitHeight = channel.RemoteCommitment.CommitHeight + 1
+
+	// If we've signed a commitment for the remote party which it hasn't
+	// revoked its prior commitment for yet, then the updates covered by
+	// it are yet to be acknowledged.
+	pendingRemote, err := channel.RemoteCommitChainTip()
+	switch {
+	case err == channeldb.ErrNoPendingCommit:
+
+	case err != nil:
+		return nil, err
+
+	default:
+		resp.PendingRemoteCommitment = rpcCommitmentState(
+			&pendingRemote.Commitment,
+		)
+		resp.NextRemoteCommitHeight =
+			pendingRemote.Commitment.CommitHeight + 1
+
+		for _, logUpdate := range pendingRemote.LogUpdates {
+			msg := logUpdate.UpdateMsg
+			resp.UnackedUpdates = append(
+				resp.UnackedUpdates, &lnrpc.UnackedUpdate{
+					Type:    msg.MsgType().String(),
+					Summary: messageSummary(msg),
+					Signed:  true,
+				},
+			)
+		}
+	}
+
+	// Updates we've sent since, but haven't signed for yet, are recorded
+	// as outgoing intents.
+	intents, err := channel.OutgoingIntents()
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range intents {
+		resp.UnackedUpdates = append(
+			resp.UnackedUpdates, &lnrpc.UnackedUpdate{
+				Type:    msg.MsgType().String(),
+				Summary: messageSummary(msg),
+			},
+		)
+	}
+
+	localChanSync, err := localChanSyncMsg(channel)
+	if err != nil {
+		return nil, err
+	}
+	resp.LocalChanSync = rpcChanSyncFields(localChanSync)
+
+	record, err := channel.LastChanSync()
+	switch {
+	case err == channeldb.ErrNoChanSync:
+		resp.SyncStatus = "no ChannelReestablish message has been " +
+			"received from the remote party"
+		return resp, nil
+
+	case err != nil:
+		return nil, err
+	}
+
+	resp.RemoteChanSync = rpcChanSyncFields(record.Msg)
+	resp.RemoteChanSync.Received = record.Received.Unix()
+
+	resp.Recoverable, resp.SyncStatus, err = assessChanSync(
+		channel, pendingRemote, record.Msg,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// SendToRoute sends a payment over a route which has been fully specified by
+// the caller, such as by an external path-finder, bypassing the path finding
+// of the internal router. A single attempt is made, after which either the
+// preimage or the decoded failure is returned.
+func (r *rpcServer) SendToRoute(ctx context.Context,
+	req *lnrpc.SendToRouteRequest) (*lnrpc.SendResponse, error) {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "sendtoroute",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	// We don't allow payments to be sent while the daemon itself is still
+	// syncing as we may be trying to sent a payment over a "stale"
+	// channel.
+	if !r.server.Started() {
+		return nil, fmt.Errorf("chain backend is still syncing, " +
+			"server not active yet")
+	}
+
+	paymentHash := req.PaymentHash
+	if len(paymentHash) == 0 {
+		var err error
+		paymentHash, err = hex.DecodeString(req.PaymentHashString)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(paymentHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be exactly 32 "+
+			"bytes, is instead %v", len(paymentHash))
+	}
+	var rHash [32]byte
+	copy(rHash[:], paymentHash)
+
+	if req.Route == nil {
+		return nil, fmt.Errorf("route must be specified")
+	}
+	route, err := unmarshallRoute(req.Route)
+	if err != nil {
+		return nil, err
+	}
+
+	// The limit on the size of payments applies to routes specified by
+	// the caller as well.
+	if route.TotalAmount > maxPaymentMSat {
+		err := fmt.Errorf("payment of %v is too large, max payment "+
+			"allowed is %v", route.TotalAmount.ToSatoshis(),
+			maxPaymentMSat.ToSatoshis())
+		return &lnrpc.SendResponse{
+			PaymentError: err.Error(),
+		}, nil
+	}
+
+	preImage, err := r.server.chanRouter.SendToRoute(route, rHash)
+	if err != nil {
+		resp := &lnrpc.SendResponse{
+			PaymentError: err.Error(),
+		}
+
+		// If the failure was reported by a node within the route,
+		// then we'll also return the decoded failure, such that the
+		// caller's path-finder can account for it.
+		fErr, ok := err.(*htlcswitch.ForwardingError)
+		if ok && fErr.ErrorSource != nil {
+			resp.FailureSourcePubkey = hex.EncodeToString(
+				fErr.ErrorSource.SerializeCompressed(),
+			)
+			resp.FailureCode = fErr.FailureMessage.Code().String()
+		}
+
+		return resp, nil
+	}
+
+	// With the payment completed successfully, we'll save its details to
+	// the database, recording the amount received by the final hop.
+	amount := route.Hops[len(route.Hops)-1].AmtToForward
+	if err := r.savePayment(route, amount, preImage[:]); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SendResponse{
+		PaymentPreimage: preImage[:],
+		PaymentRoute:    marshallRoute(route),
+	}, nil
+}
+
+// SubscribeChannelEvents returns a uni-directional stream (server -> client)
+// of the updates relevant to the state of our channels: a channel being
+// opened, becoming active or inactive, or being fully closed.
+func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
+	updateStream lnrpc.Lightning_SubscribeChannelEventsServer) error {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(updateStream.Context(),
+			"subscribechannelevents", r.authSvc); err != nil {
+			return err
+		}
+	}
+
+	rpcsLog.Debugf("[subscribechannelevents]")
+
+	sub, err := r.server.channelNotifier.SubscribeChannelEvents()
+	if err != nil {
+		return err
+	}
+	defer sub.Cancel()
+
+	for {
+		select {
+		case e, ok := <-sub.Updates:
+			// If the notifier is shutting down, then we will as
+			// well.
+			if !ok {
+				return nil
+			}
+
+			update, err := createRPCChannelEvent(e)
+			if err != nil {
+				return err
+			}
+
+			if err := updateStream.Send(update); err != nil {
+				return err
+			}
+
+		case <-updateStream.Context().Done():
+			return nil
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// createRPCChannelEvent returns the RPC representation of the passed event
+// dispatched by the channel notifier.
+func createRPCChannelEvent(e interface{}) (*lnrpc.ChannelEventUpdate, error) {
+	switch event := e.(type) {
+	case *channelnotifier.OpenChannelEvent:
+		return &lnrpc.ChannelEventUpdate{
+			Type:        lnrpc.ChannelEventUpdate_OPEN_CHANNEL,
+			OpenChannel: createRPCOpenChannel(event.Channel),
+		}, nil
+
+	case *channelnotifier.ActiveChannelEvent:
+		return &lnrpc.ChannelEventUpdate{
+			Type: lnrpc.ChannelEventUpdate_ACTIVE_CHANNEL,
+			ActiveChannel: &lnrpc.ChannelPoint{
+				FundingTxid: event.ChannelPoint.Hash[:],
+				OutputIndex: event.ChannelPoint.Index,
+			},
+		}, nil
+
+	case *channelnotifier.InactiveChannelEvent:
+		return &lnrpc.ChannelEventUpdate{
+			Type: lnrpc.ChannelEventUpdate_INACTIVE_CHANNEL,
+			InactiveChannel: &lnrpc.ChannelPoint{
+				FundingTxid: event.ChannelPoint.Hash[:],
+				OutputIndex: event.ChannelPoint.Index,
+			},
+		}, nil
+
+	case *channelnotifier.ClosedChannelEvent:
+		return &lnrpc.ChannelEventUpdate{
+			Type:          lnrpc.ChannelEventUpdate_CLOSED_CHANNEL,
+			ClosedChannel: createRPCCloseSummary(event.CloseSummary),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected channel event type: %T", e)
+	}
+}
+
+// createRPCOpenChannel returns a summary of the passed newly opened channel,
+// in the form used by the ListChannels RPC. Only the details describing the
+// channel itself and its initial balances are populated.
+func createRPCOpenChannel(
+	dbChannel *channeldb.OpenChannel) *lnrpc.ActiveChannel {
+
+	nodePub := dbChannel.IdentityPub.SerializeCompressed()
+	localCommit := dbChannel.LocalCommitment
+	private := dbChannel.ChannelFlags&lnwire.FFAnnounceChannel == 0
+
+	return &lnrpc.ActiveChannel{
+		RemotePubkey:  hex.EncodeToString(nodePub),
+		ChannelPoint:  dbChannel.FundingOutpoint.String(),
+		ChanId:        dbChannel.ShortChanID.ToUint64(),
+		Capacity:      int64(dbChannel.Capacity),
+		LocalBalance:  int64(localCommit.LocalBalance.ToSatoshis()),
+		RemoteBalance: int64(localCommit.RemoteBalance.ToSatoshis()),
+		FeePerKw:      int64(localCommit.FeePerKw),
+		NumUpdates:    localCommit.CommitHeight,
+		CsvDelay:      uint32(dbChannel.LocalChanCfg.CsvDelay),
+		Private:       private,
+	}
+}
+
+// createRPCCloseSummary returns the RPC representation of the passed channel
+// close summary.
+func createRPCCloseSummary(
+	summary *channeldb.ChannelCloseSummary) *lnrpc.ChannelCloseSummary {
+
+	remotePub := summary.RemotePub.SerializeCompressed()
+
+	var closeType lnrpc.ChannelCloseSummary_ClosureType
+	switch summary.CloseType {
+	case channeldb.CooperativeClose:
+		closeType = lnrpc.ChannelCloseSummary_COOPERATIVE_CLOSE
+	case channeldb.ForceClose:
+		closeType = lnrpc.ChannelCloseSummary_FORCE_CLOSE
+	case channeldb.BreachClose:
+		closeType = lnrpc.ChannelCloseSummary_BREACH_CLOSE
+	case channeldb.FundingCanceled:
+		closeType = lnrpc.ChannelCloseSummary_FUNDING_CANCELED
+	}
+
+	return &lnrpc.ChannelCloseSummary{
+		ChannelPoint:      summary.ChanPoint.String(),
+		ChanId:            summary.ShortChanID.ToUint64(),
+		ChainHash:         summary.ChainHash.String(),
+		ClosingTxHash:     summary.ClosingTXID.String(),
+		RemotePubkey:      hex.EncodeToString(remotePub),
+		Capacity:          int64(summary.Capacity),
+		CloseHeight:       summary.CloseHeight,
+		SettledBalance:    int64(summary.SettledBalance),
+		TimeLockedBalance: int64(summary.TimeLockedBalance),
+		CloseType:         closeType,
+	}
 }
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// defaultSelfCheckAmt is the default amount sent around the circle
+	// by the startup self-check.
+	defaultSelfCheckAmt = btcutil.Amount(10)
+
+	// defaultSelfCheckMaxLatency is the default amount of time the
+	// self-check payment may take to be settled before the check fails.
+	defaultSelfCheckMaxLatency = 30 * time.Second
+
+	// defaultSelfCheckTimeout is the default amount of time we'll wait
+	// for the channels of the self-check to become active after startup.
+	defaultSelfCheckTimeout = 5 * time.Minute
+
+	// selfCheckPollInterval is the interval at which we check whether the
+	// channels of the self-check have become active.
+	selfCheckPollInterval = time.Second
+)
+
+// errSelfCheckExiting is returned by runCheck if the self-check is stopped
+// before it completes.
+var errSelfCheckExiting = errors.New("self-check exiting")
+
+// selfCheckState describes the progress of the startup self-check.
+type selfCheckState uint8
+
+const (
+	// selfCheckPending indicates that the self-check hasn't completed
+	// yet.
+	selfCheckPending selfCheckState = iota
+
+	// selfCheckPassed indicates that the self-check payment was settled
+	// within the maximum latency.
+	selfCheckPassed
+
+	// selfCheckFailed indicates that the self-check payment couldn't be
+	// sent, failed, or took too long to be settled.
+	selfCheckFailed
+)
+
+// String returns a human readable version of the self-check state.
+func (s selfCheckState) String() string {
+	switch s {
+	case selfCheckPending:
+		return "pending"
+	case selfCheckPassed:
+		return "passed"
+	case selfCheckFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// selfCheckerConfig houses the dependencies and parameters of the
+// selfChecker.
+type selfCheckerConfig struct {
+	// OutgoingChan is the short channel ID of the channel the self-check
+	// payment leaves over.
+	OutgoingChan uint64
+
+	// IncomingChan is the short channel ID of the channel the self-check
+	// payment returns over.
+	IncomingChan uint64
+
+	// Amount is the amount sent around the circle, excluding fees.
+	Amount lnwire.MilliSatoshi
+
+	// MaxLatency is the maximum amount of time the payment may take to be
+	// settled for the check to pass.
+	MaxLatency time.Duration
+
+	// Timeout is the maximum amount of time we'll wait for both channels
+	// to become active before failing the check.
+	Timeout time.Duration
+
+	// ChannelsActive returns true once the links of both channels are
+	// eligible to forward HTLCs.
+	ChannelsActive func() bool
+
+	// AddInvoice adds the invoice settled by the self-check payment.
+	AddInvoice func(invoice *channeldb.Invoice) error
+
+	// SendPayment sends the circular self-check payment, returning its
+	// preimage once it has been settled.
+	SendPayment func(*routing.CircularPayment) ([32]byte, *routing.Route,
+		error)
+}
+
+// selfCheckStatus is a snapshot of the outcome of the startup self-check.
+type selfCheckStatus struct {
+	// State is the progress of the self-check.
+	State selfCheckState
+
+	// Latency is the time taken for the self-check payment to be
+	// settled, or to fail.
+	Latency time.Duration
+
+	// Err is the reason the self-check failed, if it did.
+	Err error
+}
+
+// selfChecker verifies that our forwarding pipeline is functional after
+// startup. Once both of the configured channels are active, it sends a tiny
+// payment to ourselves which leaves over one channel and returns over the
+// other, and checks that it's settled in time. As the payment is settled by
+// one of our own invoices, only the routing fees of any intermediate hops are
+// spent.
+type selfChecker struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg selfCheckerConfig
+
+	mu     sync.RWMutex
+	status selfCheckStatus
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newSelfChecker creates a new selfChecker from the passed config.
+func newSelfChecker(cfg selfCheckerConfig) *selfChecker {
+	return &selfChecker{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine that carries out the self-check.
+func (c *selfChecker) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	srvrLog.Infof("Starting self-check, outgoing_chan=%v, "+
+		"incoming_chan=%v, amt=%v", c.cfg.OutgoingChan,
+		c.cfg.IncomingChan, c.cfg.Amount)
+
+	c.wg.Add(1)
+	go c.run()
+
+	return nil
+}
+
+// Stop signals the self-check to exit, and waits for it to do so.
+func (c *selfChecker) Stop() error {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return nil
+	}
+
+	close(c.quit)
+	c.wg.Wait()
+
+	return nil
+}
+
+// Status returns a snapshot of the outcome of the self-check.
+//
+// NOTE: This method is safe for concurrent access.
+func (c *selfChecker) Status() selfCheckStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.status
+}
+
+// run carries out the self-check, and records its outcome.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *selfChecker) run() {
+	defer c.wg.Done()
+
+	latency, err := c.runCheck()
+	if err == errSelfCheckExiting {
+		return
+	}
+
+	status := selfCheckStatus{
+		State:   selfCheckPassed,
+		Latency: latency,
+		Err:     err,
+	}
+	if err != nil {
+		status.State = selfCheckFailed
+		srvrLog.Errorf("Self-check failed: %v", err)
+	} else {
+		srvrLog.Infof("Self-check passed, payment settled in %v",
+			latency)
+	}
+
+	c.mu.Lock()
+	c.status = status
+	c.mu.Unlock()
+}
+
+// runCheck waits for both channels to become active, then sends the circular
+// payment, returning the time it took to be settled.
+func (c *selfChecker) runCheck() (time.Duration, error) {
+	if err := c.waitForChannels(); err != nil {
+		return 0, err
+	}
+
+	// We'll create a fresh invoice to be settled by the payment, such
+	// that it can be told apart from any other payment we receive.
+	var preimage [32]byte
+	if _, err := rand.Read(preimage[:]); err != nil {
+		return 0, err
+	}
+	paymentHash := sha256.Sum256(preimage[:])
+
+	invoice := &channeldb.Invoice{
+		CreationDate: time.Now(),
+		Memo:         []byte("startup self-check"),
+		Terms: channeldb.ContractTerm{
+			Value:           c.cfg.Amount,
+			PaymentPreimage: preimage,
+			PaymentHash:     paymentHash,
+		},
+	}
+	if err := c.cfg.AddInvoice(invoice); err != nil {
+		return 0, fmt.Errorf("unable to add invoice: %v", err)
+	}
+
+	payment := &routing.CircularPayment{
+		OutgoingChan: c.cfg.OutgoingChan,
+		IncomingChan: c.cfg.IncomingChan,
+		Amount:       c.cfg.Amount,
+		PaymentHash:  paymentHash,
+	}
+
+	// The payment is sent within a distinct goroutine, such that we don't
+	// wait on it for longer than the maximum latency. The channel is
+	// buffered so the goroutine is able to exit once the payment is
+	// eventually resolved.
+	type paymentResult struct {
+		preimage [32]byte
+		err      error
+	}
+	resultChan := make(chan paymentResult, 1)
+	start := time.Now()
+	go func() {
+		preimage, _, err := c.cfg.SendPayment(payment)
+		resultChan <- paymentResult{preimage: preimage, err: err}
+	}()
+
+	select {
+	case result := <-resultChan:
+		latency := time.Since(start)
+		switch {
+		case result.err != nil:
+			return latency, fmt.Errorf("unable to send payment: "+
+				"%v", result.err)
+
+		case result.preimage != preimage:
+			return latency, fmt.Errorf("payment settled with "+
+				"unexpected preimage %x", result.preimage)
+
+		case latency > c.cfg.MaxLatency:
+			return latency, fmt.Errorf("payment settled in %v, "+
+				"exceeding the maximum latency of %v", latency,
+				c.cfg.MaxLatency)
+		}
+
+		return latency, nil
+
+	case <-time.After(c.cfg.MaxLatency):
+		return time.Since(start), fmt.Errorf("payment not settled "+
+			"within %v", c.cfg.MaxLatency)
+
+	case <-c.quit:
+		return 0, errSelfCheckExiting
+	}
+}
+
+// waitForChannels blocks until both channels are active, failing if they
+// don't become active before the timeout.
+func (c *selfChecker) waitForChannels() error {
+	if c.cfg.ChannelsActive() {
+		return nil
+	}
+
+	ticker := time.NewTicker(selfCheckPollInterval)
+	defer ticker.Stop()
+
+	timeout := time.After(c.cfg.Timeout)
+	for {
+		select {
+		case <-ticker.C:
+			if c.cfg.ChannelsActive() {
+				return nil
+			}
+
+		case <-timeout:
+			return fmt.Errorf("channels not active within %v",
+				c.cfg.Timeout)
+
+		case <-c.quit:
+			return errSelfCheckExiting
+		}
+	}
+}
+
+// selfCheckChannelsActive returns true if the links of both of the self-check
+// channels are among the passed links, and are eligible to forward HTLCs.
+func selfCheckChannelsActive(links []htlcswitch.ChannelLink, outChan,
+	inChan uint64) bool {
+
+	var numActive int
+	for _, link := range links {
+		sid := link.ShortChanID()
+		chanID := sid.ToUint64()
+		if chanID != outChan && chanID != inChan {
+			continue
+		}
+
+		if link.EligibleToForward() {
+			numActive++
+		}
+	}
+
+	return numActive == 2
+}
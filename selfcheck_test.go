@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/routing"
+)
+
+// TestSelfChecker ensures that the self-check only passes if the circular
+// payment settles our invoice within the maximum latency, and that it fails
+// otherwise.
+func TestSelfChecker(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		channelsActive bool
+		payDelay       time.Duration
+		payErr         error
+		expectedState  selfCheckState
+	}{
+		{
+			name:           "passed",
+			channelsActive: true,
+			expectedState:  selfCheckPassed,
+		},
+		{
+			name:           "channels inactive",
+			channelsActive: false,
+			expectedState:  selfCheckFailed,
+		},
+		{
+			name:           "payment failed",
+			channelsActive: true,
+			payErr:         errors.New("temporary channel failure"),
+			expectedState:  selfCheckFailed,
+		},
+		{
+			name:           "payment too slow",
+			channelsActive: true,
+			payDelay:       time.Second,
+			expectedState:  selfCheckFailed,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		var invoice *channeldb.Invoice
+		checker := newSelfChecker(selfCheckerConfig{
+			OutgoingChan: 1,
+			IncomingChan: 2,
+			Amount:       10000,
+			MaxLatency:   100 * time.Millisecond,
+			Timeout:      100 * time.Millisecond,
+			ChannelsActive: func() bool {
+				return test.channelsActive
+			},
+			AddInvoice: func(i *channeldb.Invoice) error {
+				invoice = i
+				return nil
+			},
+			SendPayment: func(p *routing.CircularPayment) ([32]byte,
+				*routing.Route, error) {
+
+				if p.PaymentHash != invoice.Terms.PaymentHash {
+					t.Errorf("%v: payment hash mismatch",
+						test.name)
+				}
+
+				time.Sleep(test.payDelay)
+				preimage := invoice.Terms.PaymentPreimage
+				return preimage, &routing.Route{}, test.payErr
+			},
+		})
+
+		if checker.Status().State != selfCheckPending {
+			t.Fatalf("%v: expected self-check to be pending",
+				test.name)
+		}
+
+		if err := checker.Start(); err != nil {
+			t.Fatalf("%v: unable to start self-check: %v",
+				test.name, err)
+		}
+
+		var status selfCheckStatus
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			status = checker.Status()
+			if status.State != selfCheckPending {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		checker.Stop()
+
+		if status.State != test.expectedState {
+			t.Fatalf("%v: expected state %v, got %v (err=%v)",
+				test.name, test.expectedState, status.State,
+				status.Err)
+		}
+		passed := test.expectedState == selfCheckPassed
+		if (status.Err == nil) != passed {
+			t.Fatalf("%v: unexpected error: %v", test.name,
+				status.Err)
+		}
+	}
+}
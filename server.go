@@ -17,6 +17,7 @@ import (
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/brontide"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/lnrpc"
@@ -64,6 +65,11 @@ type server struct {
 	// long-term identity private key.
 	lightningID [32]byte
 
+	// statelessInvoiceKey is the secret key from which the preimages of
+	// our stateless invoices are derived. It's nil if stateless invoices
+	// are disabled.
+	statelessInvoiceKey *[32]byte
+
 	mu         sync.RWMutex
 	peersByID  map[int32]*peer
 	peersByPub map[string]*peer
@@ -100,14 +106,80 @@ type server struct {
 
 	authGossiper *discovery.AuthenticatedGossiper
 
+	// chainHealth periodically checks the chain backend and fee
+	// estimator. Once they fail for a sustained period, our channels are
+	// disabled within the network, and new channel funding and commitment
+	// fee updates are paused until the backend recovers.
+	chainHealth *chainHealthMonitor
+
 	utxoNursery *utxoNursery
 
 	chainArb *contractcourt.ChainArbitrator
 
-	sphinx *htlcswitch.OnionProcessor
+	// channelNotifier dispatches events to its subscribers whenever one
+	// of our channels is opened, becomes active or inactive, or is
+	// closed.
+	channelNotifier *channelnotifier.ChannelNotifier
+
+	// sphinx decodes the onion packets of the HTLCs forwarded through
+	// our channels, and detects those which are replayed.
+	sphinx htlcswitch.OnionProcessor
+
+	// linkDampener delays the restarts of the links of channels whose
+	// peer is rapidly connecting and disconnecting.
+	linkDampener *linkDampener
 
 	connMgr *connmgr.ConnManager
 
+	// peerStorage stores backup blobs on behalf of our channel peers, and
+	// provides them with our own. It's nil if the peer storage feature is
+	// disabled.
+	peerStorage *peerStorage
+
+	// debugConsole serves the debug console, which allows the live state
+	// of the htlc switch to be inspected. It's nil if the debug console is
+	// disabled.
+	debugConsole *debugConsole
+
+	// metricsExporter serves the metrics of the htlc switch and its links
+	// to Prometheus. It's nil if the exporter is disabled.
+	metricsExporter *metricsExporter
+
+	// messageTap streams the wire messages exchanged with a peer to its
+	// subscribers. It's nil if the message tap is disabled.
+	messageTap *messageTap
+
+	// switchFaults injects faults into the forwarding path of the htlc
+	// switch. It's nil unless fault injection has been enabled.
+	switchFaults *htlcswitch.FaultInjector
+
+	// chanReaper cooperatively closes channels which have been inactive
+	// for too long. It's nil if auto-closing channels is disabled.
+	chanReaper *channelReaper
+
+	// liquidityRecorder periodically records the liquidity of our
+	// channels within the channel database. It's nil if the liquidity
+	// history is disabled.
+	liquidityRecorder *liquidityRecorder
+
+	// selfChecker verifies that our forwarding pipeline is functional
+	// after startup by sending a circular payment. It's nil if the
+	// self-check is disabled.
+	selfChecker *selfChecker
+
+	// onChainReserve tracks the on-chain balance set aside for the fees
+	// of resolving our channels on-chain. It's nil if no reserve is
+	// configured.
+	onChainReserve *onChainReserve
+
+	// alerter notifies the operator of critical events through webhooks
+	// and commands. It's nil if no webhooks or commands are configured.
+	alerter *alerter
+
+	// lifecycle starts and stops the server's subsystems in dependency
+	// order.
+	lifecycle *lifecycleManager
+
 	// globalFeatures feature vector which affects HTLCs and thus are also
 	// advertised to other nodes.
 	globalFeatures *lnwire.FeatureVector
@@ -144,15 +216,17 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		chanDB: chanDB,
 		cc:     cc,
 
-		invoices: newInvoiceRegistry(chanDB),
+		invoices: newInvoiceRegistry(chanDB, cfg.MaxPendingSettles),
 
 		identityPriv: privKey,
 		nodeSigner:   newNodeSigner(privKey),
 
 		// TODO(roasbeef): derive proper onion key based on rotation
 		// schedule
-		sphinx: htlcswitch.NewOnionProcessor(
-			sphinx.NewRouter(privKey, activeNetParams.Params)),
+		sphinx: htlcswitch.NewSphinxOnionProcessor(
+			sphinx.NewRouter(privKey, activeNetParams.Params),
+			htlcswitch.NewMemoryReplayLog(),
+		),
 		lightningID: sha256.Sum256(serializedPubKey),
 
 		persistentPeers:       make(map[string]struct{}),
@@ -170,6 +244,11 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		quit: make(chan struct{}),
 	}
 
+	if cfg.StatelessInvoices {
+		key := statelessInvoiceKey(privKey)
+		s.statelessInvoiceKey = &key
+	}
+
 	s.witnessBeacon = &preimageBeacon{
 		invoices:    s.invoices,
 		wCache:      chanDB.NewWitnessCache(),
@@ -186,6 +265,26 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 			debugPre[:], debugHash[:])
 	}
 
+	// Parse the configured forwarding allow and deny lists, which
+	// determine the set of peers we'll forward HTLCs between.
+	fwdAllow, err := parsePeerPubKeys(cfg.ForwardAllow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid forwarding allow list: %v", err)
+	}
+	fwdDeny, err := parsePeerPubKeys(cfg.ForwardDeny)
+	if err != nil {
+		return nil, fmt.Errorf("invalid forwarding deny list: %v", err)
+	}
+
+	if cfg.DebugSwitchFaults {
+		s.switchFaults = htlcswitch.NewFaultInjector()
+	}
+
+	s.channelNotifier = channelnotifier.New(chanDB)
+	s.linkDampener = newLinkDampener(
+		cfg.LinkRestartBackoff, cfg.MaxLinkRestartBackoff,
+	)
+
 	s.htlcSwitch = htlcswitch.New(htlcswitch.Config{
 		SelfKey: s.identityPriv.PubKey(),
 		LocalChannelClose: func(pubKey []byte,
@@ -210,6 +309,22 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 					pubKey[:], err)
 			}
 		},
+		ForwardingFilter: htlcswitch.NewForwardingFilter(
+			fwdAllow, fwdDeny,
+		),
+		ForwardingCaps: htlcswitch.ForwardingCaps{
+			Hourly:     cfg.MaxForwardHourly,
+			Daily:      cfg.MaxForwardDaily,
+			PeerHourly: cfg.MaxPeerForwardHourly,
+			PeerDaily:  cfg.MaxPeerForwardDaily,
+		},
+		DB:                    chanDB,
+		ExtractErrorEncrypter: s.sphinx.ReextractErrorEncrypter,
+		FwdingLog:             chanDB.ForwardingLog(),
+		PaymentStore:          chanDB,
+		FaultInjector:         s.switchFaults,
+		MailBoxMaxMessages:    cfg.MailBoxMaxMsgs,
+		MailBoxMaxPackets:     cfg.MailBoxMaxPkts,
 	})
 
 	// If external IP addresses have been specified, add those to the list
@@ -308,6 +423,18 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 
 			return s.htlcSwitch.SendHTLC(firstHopPub, htlcAdd, errorDecryptor)
 		},
+		SendToLink: func(chanID lnwire.ShortChannelID,
+			htlcAdd *lnwire.UpdateAddHTLC,
+			circuit *sphinx.Circuit) ([32]byte, error) {
+
+			errorDecryptor := &htlcswitch.SphinxErrorDecrypter{
+				OnionErrorDecrypter: sphinx.NewOnionErrorDecrypter(circuit),
+			}
+
+			return s.htlcSwitch.SendHTLCOverLink(
+				chanID, htlcAdd, errorDecryptor,
+			)
+		},
 		ChannelPruneExpiry: time.Duration(time.Hour * 24 * 14),
 		GraphPruneInterval: time.Duration(time.Hour),
 	})
@@ -334,6 +461,175 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		return nil, err
 	}
 
+	if cfg.PeerStorage {
+		s.peerStorage = newPeerStorage(
+			chanDB, privKey, cfg.PeerStorageQuota,
+		)
+	}
+
+	if cfg.DebugConsole != "" {
+		s.debugConsole = newDebugConsole(
+			net.JoinHostPort("127.0.0.1", cfg.DebugConsole),
+			s.htlcSwitch,
+		)
+	}
+
+	if cfg.MetricsListen != "" {
+		s.metricsExporter = newMetricsExporter(
+			cfg.MetricsListen, s.htlcSwitch,
+		)
+	}
+
+	if cfg.DebugMessageTap {
+		s.messageTap = newMessageTap()
+	}
+
+	if cfg.AutoClose.Active {
+		exempt, err := parseExemptPeers(cfg.AutoClose.Exempt)
+		if err != nil {
+			return nil, err
+		}
+
+		s.chanReaper = newChannelReaper(channelReaperConfig{
+			FetchChannels: chanDB.FetchAllChannels,
+			IsPeerOnline: func(pub *btcec.PublicKey) bool {
+				_, err := s.FindPeer(pub)
+				return err == nil
+			},
+			CloseChannel: func(chanPoint *wire.OutPoint) error {
+				feePerWeight, err := lnwallet.EstimateRelayableFeePerWeight(
+					cc.feeEstimator, 6,
+				)
+				if err != nil {
+					return err
+				}
+
+				// We only wait for the close to be initiated,
+				// as the remainder of the closing process is
+				// carried out by the peer.
+				updates, errChan := s.htlcSwitch.CloseLink(
+					chanPoint, htlcswitch.CloseRegular,
+					feePerWeight*1000, nil,
+				)
+				select {
+				case err := <-errChan:
+					return err
+				case <-updates:
+					return nil
+				case <-s.quit:
+					return fmt.Errorf("server shutting down")
+				}
+			},
+			Interval:       cfg.AutoClose.Interval,
+			MaxIdle:        cfg.AutoClose.MaxIdle,
+			MaxPeerOffline: cfg.AutoClose.MaxPeerOffline,
+			MaxDepleted:    cfg.AutoClose.MaxDepleted,
+			DryRun:         cfg.AutoClose.DryRun,
+			Exempt:         exempt,
+		})
+	}
+
+	if cfg.LiquidityHistory.Interval != 0 {
+		recorderCfg := liquidityRecorderConfig{
+			FetchChannels:   chanDB.FetchAllChannels,
+			RecordSnapshots: chanDB.RecordLiquiditySnapshots,
+			PruneSnapshots:  chanDB.PruneLiquidityHistory,
+			Interval:        cfg.LiquidityHistory.Interval,
+			Retention:       cfg.LiquidityHistory.Retention,
+		}
+		s.liquidityRecorder = newLiquidityRecorder(recorderCfg)
+	}
+
+	if cfg.SelfCheck.OutChan != 0 {
+		outChan, inChan := cfg.SelfCheck.OutChan, cfg.SelfCheck.InChan
+		s.selfChecker = newSelfChecker(selfCheckerConfig{
+			OutgoingChan: outChan,
+			IncomingChan: inChan,
+			Amount: lnwire.NewMSatFromSatoshis(
+				btcutil.Amount(cfg.SelfCheck.Amt),
+			),
+			MaxLatency: cfg.SelfCheck.MaxLatency,
+			Timeout:    cfg.SelfCheck.Timeout,
+			ChannelsActive: func() bool {
+				links, err := s.htlcSwitch.Links()
+				if err != nil {
+					return false
+				}
+
+				return selfCheckChannelsActive(
+					links, outChan, inChan,
+				)
+			},
+			AddInvoice:  s.invoices.AddInvoice,
+			SendPayment: s.chanRouter.SendCircularPayment,
+		})
+	}
+
+	if cfg.OnChainReservePerChan != 0 {
+		perChan := btcutil.Amount(cfg.OnChainReservePerChan)
+		s.onChainReserve = newOnChainReserve(onChainReserveConfig{
+			PerChannel:    perChan,
+			FetchChannels: chanDB.FetchAllChannels,
+			ConfirmedBalance: func() (btcutil.Amount, error) {
+				return cc.wallet.ConfirmedBalance(1, true)
+			},
+		})
+	}
+
+	if len(cfg.Alerts.Webhook) != 0 || len(cfg.Alerts.Exec) != 0 {
+		alerterCfg := alerterConfig{
+			Webhooks:      cfg.Alerts.Webhook,
+			Commands:      cfg.Alerts.Exec,
+			Timeout:       cfg.Alerts.Timeout,
+			Interval:      cfg.Alerts.Interval,
+			PeerOffline:   cfg.Alerts.PeerOffline,
+			MinReserve:    btcutil.Amount(cfg.Alerts.MinReserve),
+			SweepDeadline: cfg.Alerts.SweepDeadline,
+			FetchChannels: chanDB.FetchAllChannels,
+			IsPeerOnline: func(pub *btcec.PublicKey) bool {
+				_, err := s.FindPeer(pub)
+				return err == nil
+			},
+			ConfirmedBalance: func() (btcutil.Amount, error) {
+				return cc.wallet.ConfirmedBalance(1, true)
+			},
+			BestHeight: func() (uint32, error) {
+				_, height, err := cc.chainIO.GetBestBlock()
+				return uint32(height), err
+			},
+			FetchNurseryReports: s.fetchNurseryReports,
+		}
+		if s.onChainReserve != nil {
+			alerterCfg.RequiredReserve = s.onChainReserve.Required
+		}
+		s.alerter = newAlerter(alerterCfg)
+	}
+
+	s.chainHealth = newChainHealthMonitor(chainHealthConfig{
+		ChainIO:      cc.chainIO,
+		FeeEstimator: cc.feeEstimator,
+		Interval:     cfg.HealthCheck.Interval,
+		Timeout:      cfg.HealthCheck.Timeout,
+		MaxFailures:  cfg.HealthCheck.MaxFailures,
+		OnDegraded: func(err error) {
+			// As we can no longer trust our view of the chain,
+			// we'll signal to the network that our channels
+			// shouldn't be used for routing for the time being.
+			err = s.authGossiper.PropagateChanStatusUpdate(true)
+			if err != nil {
+				srvrLog.Errorf("Unable to disable channels: %v",
+					err)
+			}
+		},
+		OnRecovered: func() {
+			err := s.authGossiper.PropagateChanStatusUpdate(false)
+			if err != nil {
+				srvrLog.Errorf("Unable to re-enable channels: %v",
+					err)
+			}
+		},
+	})
+
 	utxnStore, err := newNurseryStore(&bitcoinGenesis, chanDB)
 	if err != nil {
 		srvrLog.Errorf("unable to create nursery store: %v", err)
@@ -359,7 +655,7 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		closureType htlcswitch.ChannelCloseType) {
 		// TODO(conner): Properly respect the update and error channels
 		// returned by CloseLink.
-		s.htlcSwitch.CloseLink(chanPoint, closureType, 0)
+		s.htlcSwitch.CloseLink(chanPoint, closureType, 0, nil)
 	}
 
 	s.chainArb = contractcourt.NewChainArbitrator(contractcourt.ChainArbitratorConfig{
@@ -367,6 +663,11 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		// TODO(roasbeef): properly configure
 		//  * needs to be << or specified final hop time delta
 		BroadcastDelta: defaultBroadcastDelta,
+		SweepBudget: contractcourt.SweepBudget{
+			HTLC:         cfg.HTLCSweepBudget,
+			CommitOutput: cfg.CommitSweepBudget,
+		},
+		RecordHTLCLoss: chanDB.RecordHTLCLoss,
 		NewSweepAddr: func() ([]byte, error) {
 			return newSweepPkScript(cc.wallet)
 		},
@@ -407,8 +708,23 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		ChainIO:      cc.chainIO,
 		MarkLinkInactive: func(chanPoint wire.OutPoint) error {
 			chanID := lnwire.NewChanIDFromOutPoint(&chanPoint)
-			return s.htlcSwitch.RemoveLink(chanID)
+			err := s.htlcSwitch.RemoveLink(chanID)
+			if err != nil {
+				return err
+			}
+
+			// As the channel is being closed, its link won't be
+			// restarted, so any packets still buffered for it are
+			// failed back.
+			err = s.htlcSwitch.DiscardMailBox(chanID)
+			if err != nil {
+				return err
+			}
+
+			s.channelNotifier.NotifyInactiveChannelEvent(chanPoint)
+			return nil
 		},
+		NotifyClosedChannel: s.channelNotifier.NotifyClosedChannelEvent,
 		IsOurAddress: func(addr btcutil.Address) bool {
 			_, err := cc.wallet.GetPrivKey(addr)
 			return err == nil
@@ -430,8 +746,18 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 			// state.
 			return s.chainArb.SubscribeChannelEvents(chanPoint, true)
 		},
-		Signer: cc.wallet.Cfg.Signer,
-		Store:  newRetributionStore(chanDB),
+		Signer:              cc.wallet.Cfg.Signer,
+		Store:               newRetributionStore(chanDB),
+		NotifyClosedChannel: s.channelNotifier.NotifyClosedChannelEvent,
+		NotifyBreach: func(chanPoint wire.OutPoint,
+			revokedStateNum uint64) {
+
+			if s.alerter != nil {
+				s.alerter.NotifyBreach(
+					chanPoint, revokedStateNum,
+				)
+			}
+		},
 	})
 
 	// Create the connection manager which will be responsible for
@@ -450,9 +776,221 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 	}
 	s.connMgr = cmgr
 
+	s.lifecycle = newLifecycleManager()
+	if err := s.registerSubsystems(); err != nil {
+		return nil, err
+	}
+
 	return s, nil
 }
 
+// registerSubsystems registers each of the server's subsystems, along with
+// their dependencies, with the lifecycle manager. Subsystems without a start
+// function are started before the server, but are stopped along with it.
+func (s *server) registerSubsystems() error {
+	subsystems := []*subsystem{
+		{
+			name: "feeestimator",
+			stop: s.cc.feeEstimator.Stop,
+		},
+		{
+			name: "wallet",
+			stop: s.cc.wallet.Shutdown,
+		},
+		{
+			name: "chainview",
+			stop: s.cc.chainView.Stop,
+		},
+		{
+			name:  "chainnotifier",
+			start: s.cc.chainNotifier.Start,
+			stop:  s.cc.chainNotifier.Stop,
+		},
+		{
+			name:  "channelnotifier",
+			start: s.channelNotifier.Start,
+			stop:  s.channelNotifier.Stop,
+		},
+		{
+			name:  "htlcswitch",
+			deps:  []string{"chainnotifier"},
+			start: s.htlcSwitch.Start,
+			stop:  s.htlcSwitch.Stop,
+		},
+		{
+			name:  "utxonursery",
+			deps:  []string{"chainnotifier", "wallet"},
+			start: s.utxoNursery.Start,
+			stop:  s.utxoNursery.Stop,
+		},
+		{
+			name: "chainarb",
+			deps: []string{
+				"chainnotifier", "wallet", "htlcswitch",
+				"channelnotifier",
+			},
+			start: s.chainArb.Start,
+			stop:  s.chainArb.Stop,
+		},
+		{
+			name: "breacharbiter",
+			deps: []string{
+				"chainnotifier", "wallet", "chainarb",
+				"channelnotifier",
+			},
+			start: s.breachArbiter.Start,
+			stop:  s.breachArbiter.Stop,
+		},
+		{
+			name:  "gossiper",
+			deps:  []string{"chainnotifier"},
+			start: s.authGossiper.Start,
+			stop: func() error {
+				s.authGossiper.Stop()
+				return nil
+			},
+		},
+		{
+			name:  "router",
+			deps:  []string{"chainnotifier", "chainview"},
+			start: s.chanRouter.Start,
+			stop:  s.chanRouter.Stop,
+		},
+		{
+			name:  "chainhealth",
+			deps:  []string{"wallet", "feeestimator", "gossiper"},
+			start: s.chainHealth.Start,
+			stop:  s.chainHealth.Stop,
+		},
+		{
+			name: "connmgr",
+			stop: func() error {
+				s.connMgr.Stop()
+				return nil
+			},
+		},
+
+		// The peers are torn down before any of the subsystems their
+		// channel links rely on, so no link is left waiting on a
+		// subsystem that has already exited.
+		{
+			name: "peers",
+			deps: []string{
+				"connmgr", "htlcswitch", "chainarb",
+				"breacharbiter", "gossiper", "router",
+				"chainhealth", "feeestimator", "channelnotifier",
+			},
+			stop: func() error {
+				for _, peer := range s.Peers() {
+					s.DisconnectPeer(peer.addr.IdentityKey)
+				}
+				return nil
+			},
+		},
+	}
+
+	if s.peerStorage != nil {
+		subsystems = append(subsystems, &subsystem{
+			name:  "peerstorage",
+			start: s.peerStorage.Start,
+			stop:  s.peerStorage.Stop,
+		})
+	}
+
+	if s.debugConsole != nil {
+		subsystems = append(subsystems, &subsystem{
+			name:  "debugconsole",
+			deps:  []string{"htlcswitch"},
+			start: s.debugConsole.Start,
+			stop:  s.debugConsole.Stop,
+		})
+	}
+
+	if s.metricsExporter != nil {
+		subsystems = append(subsystems, &subsystem{
+			name:  "metricsexporter",
+			deps:  []string{"htlcswitch"},
+			start: s.metricsExporter.Start,
+			stop:  s.metricsExporter.Stop,
+		})
+	}
+
+	if s.chanReaper != nil {
+		subsystems = append(subsystems, &subsystem{
+			name:  "chanreaper",
+			deps:  []string{"htlcswitch", "peers", "feeestimator"},
+			start: s.chanReaper.Start,
+			stop:  s.chanReaper.Stop,
+		})
+	}
+
+	if s.liquidityRecorder != nil {
+		subsystems = append(subsystems, &subsystem{
+			name:  "liquidityrecorder",
+			start: s.liquidityRecorder.Start,
+			stop:  s.liquidityRecorder.Stop,
+		})
+	}
+
+	if s.selfChecker != nil {
+		subsystems = append(subsystems, &subsystem{
+			name:  "selfcheck",
+			deps:  []string{"htlcswitch", "router"},
+			start: s.selfChecker.Start,
+			stop:  s.selfChecker.Stop,
+		})
+	}
+
+	if s.alerter != nil {
+		subsystems = append(subsystems, &subsystem{
+			name:  "alerter",
+			deps:  []string{"wallet", "utxonursery"},
+			start: s.alerter.Start,
+			stop:  s.alerter.Stop,
+		})
+	}
+
+	for _, sub := range subsystems {
+		if err := s.lifecycle.Register(sub); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchNurseryReports returns the maturity reports of the force closed
+// channels whose time-locked outputs are tracked by the utxoNursery.
+func (s *server) fetchNurseryReports() ([]*contractMaturityReport, error) {
+	// Only force closed channels may have outputs within the nursery, as
+	// the outputs of cooperatively closed channels are spendable as soon
+	// as the closing transaction confirms.
+	pendingCloseChannels, err := s.chanDB.FetchClosedChannels(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []*contractMaturityReport
+	for _, pendingClose := range pendingCloseChannels {
+		if pendingClose.CloseType != channeldb.ForceClose {
+			continue
+		}
+
+		chanPoint := pendingClose.ChanPoint
+		report, err := s.utxoNursery.NurseryReport(&chanPoint)
+		switch {
+		case err == ErrContractNotFound:
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
 // Started returns true if the server has been started, and false otherwise.
 // NOTE: This function is safe for concurrent access.
 func (s *server) Started() bool {
@@ -468,32 +1006,22 @@ func (s *server) Start() error {
 		return nil
 	}
 
-	// Start the notification server. This is used so channel management
-	// goroutines can be notified when a funding transaction reaches a
-	// sufficient number of confirmations, or when the input for the
-	// funding transaction is spent in an attempt at an uncooperative close
-	// by the counterparty.
-	if err := s.cc.chainNotifier.Start(); err != nil {
+	// Start all of our subsystems in dependency order. The chain notifier
+	// is started first, as it's used so channel management goroutines can
+	// be notified when a funding transaction reaches a sufficient number
+	// of confirmations, or when the input for the funding transaction is
+	// spent in an attempt at an uncooperative close by the counterparty.
+	if err := s.lifecycle.Start(); err != nil {
 		return err
 	}
 
-	if err := s.htlcSwitch.Start(); err != nil {
-		return err
-	}
-	if err := s.utxoNursery.Start(); err != nil {
-		return err
-	}
-	if err := s.chainArb.Start(); err != nil {
-		return err
-	}
-	if err := s.breachArbiter.Start(); err != nil {
-		return err
-	}
-	if err := s.authGossiper.Start(); err != nil {
-		return err
-	}
-	if err := s.chanRouter.Start(); err != nil {
-		return err
+	// Now that the gossiper and switch are running, we'll bring the
+	// policies of our channels in line with any change to the default
+	// routing policies since we last started, before any of our peers
+	// reconnect and their links come up. A failure here isn't fatal, as
+	// the channels will be reconciled once we restart.
+	if err := s.reconcileChanPolicies(); err != nil {
+		srvrLog.Errorf("Unable to reconcile channel policies: %v", err)
 	}
 
 	// With all the relevant sub-systems started, we'll now attempt to
@@ -535,24 +1063,10 @@ func (s *server) Stop() error {
 
 	close(s.quit)
 
-	// Shutdown the wallet, funding manager, and the rpc server.
-	s.cc.chainNotifier.Stop()
-	s.chanRouter.Stop()
-	s.htlcSwitch.Stop()
-	s.utxoNursery.Stop()
-	s.breachArbiter.Stop()
-	s.authGossiper.Stop()
-	s.chainArb.Stop()
-	s.cc.wallet.Shutdown()
-	s.cc.chainView.Stop()
-	s.connMgr.Stop()
-	s.cc.feeEstimator.Stop()
-
-	// Disconnect from each active peers to ensure that
+	// Shutdown all subsystems in the reverse order of their dependencies.
+	// This includes disconnecting from each active peer to ensure that
 	// peerTerminationWatchers signal completion to each peer.
-	for _, peer := range s.Peers() {
-		s.DisconnectPeer(peer.addr.IdentityKey)
-	}
+	s.lifecycle.Stop()
 
 	// Wait for all lingering goroutines to quit.
 	s.wg.Wait()
@@ -1205,7 +1719,12 @@ func (s *server) peerTerminationWatcher(p *peer) {
 		if err != nil {
 			srvrLog.Errorf("unable to remove channel link: %v",
 				err)
+			continue
 		}
+
+		s.channelNotifier.NotifyInactiveChannelEvent(
+			*link.ChannelPoint(),
+		)
 	}
 
 	s.mu.Lock()
@@ -1287,6 +1806,17 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		localFeatures.Set(lnwire.InitialRoutingSync)
 	}
 
+	// We're able to bring channels into a quiescent state, whether
+	// requested by the peer or by us, so we'll signal that we support
+	// quiescence.
+	localFeatures.Set(lnwire.QuiesceOptional)
+
+	// If the peer storage feature is enabled, then we'll offer to store a
+	// backup blob on behalf of the peer.
+	if s.peerStorage != nil {
+		localFeatures.Set(lnwire.ProvideStorageOptional)
+	}
+
 	// Now that we've established a connection, create a peer, and it to
 	// the set of currently active peers.
 	p, err := newPeer(conn, connReq, s, peerAddr, inbound, localFeatures)
@@ -1597,6 +2127,15 @@ type openChanReq struct {
 
 	minHtlc lnwire.MilliSatoshi
 
+	// remoteChanReserve, if non-zero, is the reserve we'll propose the
+	// remote party maintain in place of the default.
+	remoteChanReserve btcutil.Amount
+
+	// announceDepth, if non-zero, is the number of confirmations the
+	// funding transaction must reach before the channel is announced, in
+	// place of the default.
+	announceDepth uint32
+
 	// TODO(roasbeef): add ability to specify channel constraints as well
 
 	updates chan *lnrpc.OpenStatusUpdate
@@ -1709,14 +2248,16 @@ func (s *server) DisconnectPeer(pubKey *btcec.PublicKey) error {
 }
 
 // OpenChannel sends a request to the server to open a channel to the specified
-// peer identified by ID with the passed channel funding parameters.
+// peer identified by ID with the passed channel funding parameters. If
+// remoteChanReserve is non-zero, then it's proposed as the reserve the remote
+// party must maintain in place of the default.
 //
 // NOTE: This function is safe for concurrent access.
 func (s *server) OpenChannel(peerID int32, nodeKey *btcec.PublicKey,
 	localAmt btcutil.Amount, pushAmt lnwire.MilliSatoshi,
-	minHtlc lnwire.MilliSatoshi,
-	fundingFeePerByte btcutil.Amount,
-	private bool) (chan *lnrpc.OpenStatusUpdate, chan error) {
+	minHtlc lnwire.MilliSatoshi, remoteChanReserve btcutil.Amount,
+	fundingFeePerByte btcutil.Amount, private bool,
+	announceDepth uint32) (chan *lnrpc.OpenStatusUpdate, chan error) {
 
 	updateChan := make(chan *lnrpc.OpenStatusUpdate, 1)
 	errChan := make(chan error, 1)
@@ -1778,6 +2319,8 @@ func (s *server) OpenChannel(peerID int32, nodeKey *btcec.PublicKey,
 		pushAmt:             pushAmt,
 		private:             private,
 		minHtlc:             minHtlc,
+		remoteChanReserve:   remoteChanReserve,
+		announceDepth:       announceDepth,
 		updates:             updateChan,
 		err:                 errChan,
 	}
@@ -1821,3 +2364,25 @@ func parseHexColor(colorStr string) (color.RGBA, error) {
 
 	return color.RGBA{R: colorBytes[0], G: colorBytes[1], B: colorBytes[2]}, nil
 }
+
+// parsePeerPubKeys parses a list of hex-encoded compressed public keys
+// identifying peers.
+func parsePeerPubKeys(pubKeys []string) ([][33]byte, error) {
+	peers := make([][33]byte, 0, len(pubKeys))
+	for _, pubKeyStr := range pubKeys {
+		pubKeyBytes, err := hex.DecodeString(pubKeyStr)
+		if err != nil {
+			return nil, err
+		}
+		pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+
+		var peer [33]byte
+		copy(peer[:], pubKey.SerializeCompressed())
+		peers = append(peers, peer)
+	}
+
+	return peers, nil
+}
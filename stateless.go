@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"time"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// defaultStatelessExpiry is the expiry of stateless invoices which don't
+// specify one, matching the default of the payment request encoding. Unlike
+// other invoices, stateless ones always specify their expiry within their
+// payment request, as it's part of the record their preimage is derived
+// from.
+const defaultStatelessExpiry = time.Hour
+
+// statelessInvoiceKey derives the secret key from which the preimages of our
+// stateless invoices are derived from our identity private key, such that
+// they remain payable across restarts without the key being stored.
+func statelessInvoiceKey(identityPriv *btcec.PrivateKey) [32]byte {
+	mac := hmac.New(sha256.New, []byte("stateless invoice key"))
+	mac.Write(identityPriv.Serialize())
+
+	var key [32]byte
+	copy(key[:], mac.Sum(nil))
+
+	return key
+}
+
+// newStatelessRecord creates the record of a new stateless invoice of the
+// passed value and expiry, created at the passed time, with a fresh nonce.
+func newStatelessRecord(value lnwire.MilliSatoshi, creationDate time.Time,
+	expiry time.Duration) (*htlcswitch.StatelessRecord, error) {
+
+	record := &htlcswitch.StatelessRecord{
+		Value:        value,
+		CreationDate: creationDate,
+		Expiry:       expiry,
+	}
+	if _, err := rand.Read(record.Nonce[:]); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// payReqStatelessRecord returns the record of the stateless invoice described
+// by the passed payment request, which is to be handed back to its creator
+// within the onion of the payment. If the payment request isn't that of a
+// stateless invoice, then nil is returned.
+func payReqStatelessRecord(
+	payReq *zpay32.Invoice) *htlcswitch.StatelessRecord {
+
+	if payReq.StatelessNonce == nil {
+		return nil
+	}
+
+	record := &htlcswitch.StatelessRecord{
+		Nonce:        *payReq.StatelessNonce,
+		CreationDate: payReq.Timestamp,
+		Expiry:       payReq.Expiry(),
+	}
+	if payReq.MilliSat != nil {
+		record.Value = *payReq.MilliSat
+	}
+
+	return record
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+)
+
+// TestPayReqStatelessRecord ensures that the record of a stateless invoice
+// reconstructed by the payer from its payment request matches the one it was
+// created with, such that the preimage derived by its creator once it's paid
+// matches the payment hash of the payment request.
+func TestPayReqStatelessRecord(t *testing.T) {
+	t.Parallel()
+
+	identityPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	key := statelessInvoiceKey(identityPriv)
+
+	creationDate := time.Unix(time.Now().Unix(), 0)
+	record, err := newStatelessRecord(5000, creationDate, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("unable to create stateless record: %v", err)
+	}
+	preimage := record.Preimage(key)
+
+	payReq, err := zpay32.NewInvoice(
+		&chaincfg.MainNetParams, record.Invoice(key).Terms.PaymentHash,
+		creationDate, zpay32.Amount(record.Value),
+		zpay32.Expiry(record.Expiry), zpay32.Description(""),
+		zpay32.StatelessNonce(record.Nonce),
+	)
+	if err != nil {
+		t.Fatalf("unable to create payment request: %v", err)
+	}
+	encoded, err := payReq.Encode(zpay32.MessageSigner{
+		SignCompact: func(hash []byte) ([]byte, error) {
+			return btcec.SignCompact(
+				btcec.S256(), identityPriv, hash, true,
+			)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to encode payment request: %v", err)
+	}
+	decoded, err := zpay32.Decode(encoded)
+	if err != nil {
+		t.Fatalf("unable to decode payment request: %v", err)
+	}
+
+	payerRecord := payReqStatelessRecord(decoded)
+	if payerRecord == nil {
+		t.Fatalf("stateless record not found within payment request")
+	}
+	if payerRecord.Preimage(key) != preimage {
+		t.Fatalf("preimage derived from payment request doesn't " +
+			"match")
+	}
+
+	// Payment requests of other invoices don't carry a record.
+	decoded.StatelessNonce = nil
+	if payReqStatelessRecord(decoded) != nil {
+		t.Fatalf("unexpected stateless record")
+	}
+}
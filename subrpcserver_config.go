@@ -0,0 +1,64 @@
+package main
+
+import (
+	"gopkg.in/macaroon-bakery.v1/bakery"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// subServerConfigPopulator is a function that's able to fill in the
+// configuration of a single sub-server given the primary dependencies of the
+// daemon. Each optional sub-server that's compiled into lnd registers one of
+// these from a build-tagged file within this package.
+type subServerConfigPopulator func(subCfgs *subRPCServerConfigs, s *server,
+	cc *chainControl, macService *bakery.Service) error
+
+// subServerConfigPopulators is the set of populators registered by the
+// build-tagged sub-server configuration files within this package.
+var subServerConfigPopulators []subServerConfigPopulator
+
+// subRPCServerConfigs houses the configuration of each of the optional
+// sub-servers that have been compiled into lnd. As the set of sub-servers is
+// only known at build time, the configs are stored keyed by the name of the
+// sub-server that they belong to.
+type subRPCServerConfigs struct {
+	configs map[string]interface{}
+}
+
+// A compile time check to ensure that subRPCServerConfigs implements the
+// lnrpc.SubServerConfigDispatcher interface.
+var _ lnrpc.SubServerConfigDispatcher = (*subRPCServerConfigs)(nil)
+
+// newSubRPCServerConfigs creates a new subRPCServerConfigs instance, and runs
+// each of the registered populators in order to hand each active sub-server
+// the set of dependencies it needs to operate.
+func newSubRPCServerConfigs(s *server, cc *chainControl,
+	macService *bakery.Service) (*subRPCServerConfigs, error) {
+
+	subCfgs := &subRPCServerConfigs{
+		configs: make(map[string]interface{}),
+	}
+	for _, populate := range subServerConfigPopulators {
+		if err := populate(subCfgs, s, cc, macService); err != nil {
+			return nil, err
+		}
+	}
+
+	return subCfgs, nil
+}
+
+// setConfig stores the configuration for the target sub-server, overriding
+// any prior config.
+func (s *subRPCServerConfigs) setConfig(subServerName string, cfg interface{}) {
+	s.configs[subServerName] = cfg
+}
+
+// FetchConfig attempts to locate an existing configuration file mapped to the
+// target sub-server. If we're unable to find a config file matching the
+// subServerName name, then false will be returned for the second parameter.
+//
+// NOTE: Part of the lnrpc.SubServerConfigDispatcher interface.
+func (s *subRPCServerConfigs) FetchConfig(subServerName string) (interface{}, bool) {
+	cfg, ok := s.configs[subServerName]
+	return cfg, ok
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -43,8 +44,9 @@ var (
 		0x6a, 0x49, 0x18, 0x83, 0x31, 0x98, 0x47, 0x53,
 	}
 
-	// Just use some arbitrary bytes as delivery script.
-	dummyDeliveryScript = alicesPrivKey[:]
+	// Just use some arbitrary bytes as the witness program of a P2WPKH
+	// delivery script.
+	dummyDeliveryScript = append([]byte{0x00, 0x14}, alicesPrivKey[:20]...)
 )
 
 // createTestPeer creates a channel between two nodes, and returns a peer for
@@ -259,10 +261,11 @@ func createTestPeer(notifier chainntnfs.ChainNotifier,
 	chainArb.WatchNewChannel(aliceChannelState)
 
 	s := &server{
-		chanDB:        dbAlice,
-		cc:            cc,
-		breachArbiter: breachArbiter,
-		chainArb:      chainArb,
+		chanDB:          dbAlice,
+		cc:              cc,
+		breachArbiter:   breachArbiter,
+		chainArb:        chainArb,
+		channelNotifier: channelnotifier.New(dbAlice),
 	}
 	s.htlcSwitch = htlcswitch.New(htlcswitch.Config{})
 	s.htlcSwitch.Start()
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcutil"
+)
+
+// timeLockedBucket aggregates the funds which become spendable at the same
+// block height.
+type timeLockedBucket struct {
+	// maturityHeight is the block height at which the funds become
+	// spendable. It's zero if the height isn't known yet, as the output
+	// locking the funds hasn't confirmed.
+	maturityHeight uint32
+
+	// csvAmount is the value of the funds locked behind CSV delays.
+	csvAmount btcutil.Amount
+
+	// cltvAmount is the value of the funds locked behind the CLTV timeouts
+	// of outgoing HTLCs.
+	cltvAmount btcutil.Amount
+
+	// numOutputs is the number of outputs, or HTLCs, locking the funds.
+	numOutputs uint32
+}
+
+// timeLockedReport aggregates all of our funds which are currently
+// time-locked, either within the outputs of force closed channels that are
+// awaiting maturity within the utxoNursery, or within the outgoing HTLCs of
+// our open channels which are awaiting their CLTV timeout, bucketed by the
+// height at which they become spendable.
+type timeLockedReport struct {
+	buckets map[uint32]*timeLockedBucket
+}
+
+// newTimeLockedReport returns an empty timeLockedReport.
+func newTimeLockedReport() *timeLockedReport {
+	return &timeLockedReport{
+		buckets: make(map[uint32]*timeLockedBucket),
+	}
+}
+
+// bucket returns the bucket of the funds maturing at the passed height,
+// creating it if it doesn't exist yet.
+func (r *timeLockedReport) bucket(maturityHeight uint32) *timeLockedBucket {
+	b, ok := r.buckets[maturityHeight]
+	if !ok {
+		b = &timeLockedBucket{maturityHeight: maturityHeight}
+		r.buckets[maturityHeight] = b
+	}
+
+	return b
+}
+
+// addCSV adds funds locked behind a CSV delay to the report.
+func (r *timeLockedReport) addCSV(maturityHeight uint32, amt btcutil.Amount) {
+	b := r.bucket(maturityHeight)
+	b.csvAmount += amt
+	b.numOutputs++
+}
+
+// addCLTV adds funds locked behind a CLTV timeout to the report.
+func (r *timeLockedReport) addCLTV(maturityHeight uint32, amt btcutil.Amount) {
+	b := r.bucket(maturityHeight)
+	b.cltvAmount += amt
+	b.numOutputs++
+}
+
+// addOpenChannel adds the outgoing HTLCs of the passed open channel to the
+// report. Until an outgoing HTLC is settled, its value can only be reclaimed
+// once its CLTV timeout has expired, so it's considered locked until then.
+func (r *timeLockedReport) addOpenChannel(channel *channeldb.OpenChannel) {
+	for _, htlc := range channel.LocalCommitment.Htlcs {
+		if htlc.Incoming {
+			continue
+		}
+
+		r.addCLTV(htlc.RefundTimeout, htlc.Amt.ToSatoshis())
+	}
+}
+
+// addNurseryReport adds the outputs of a force closed channel which are still
+// awaiting maturity within the utxoNursery to the report.
+func (r *timeLockedReport) addNurseryReport(report *contractMaturityReport) {
+	// The limbo balance of the contract is made up of its commitment
+	// output, if it hasn't been swept yet, and its unswept htlc outputs.
+	// So we'll add each of the unswept htlc outputs, then attribute the
+	// remainder of the limbo balance to the commitment output.
+	commitLimbo := report.limboBalance
+	for _, htlc := range report.htlcs {
+		if htlc.recovered {
+			continue
+		}
+
+		commitLimbo -= htlc.amount
+		if htlc.cltvLocked {
+			r.addCLTV(htlc.maturityHeight, htlc.amount)
+		} else {
+			r.addCSV(htlc.maturityHeight, htlc.amount)
+		}
+	}
+
+	if commitLimbo > 0 {
+		r.addCSV(report.maturityHeight, commitLimbo)
+	}
+}
+
+// sortedBuckets returns the buckets of the report in ascending order of their
+// maturity height. The bucket of funds whose maturity height isn't yet known,
+// if any, is returned last.
+func (r *timeLockedReport) sortedBuckets() []*timeLockedBucket {
+	buckets := make([]*timeLockedBucket, 0, len(r.buckets))
+	for _, b := range r.buckets {
+		buckets = append(buckets, b)
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		hi, hj := buckets[i].maturityHeight, buckets[j].maturityHeight
+		switch {
+		case hi == 0:
+			return false
+		case hj == 0:
+			return true
+		default:
+			return hi < hj
+		}
+	})
+
+	return buckets
+}
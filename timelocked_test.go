@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestTimeLockedReport ensures that the time-locked report buckets the
+// outgoing HTLCs of open channels, and the unswept outputs of force closed
+// channels, by the height at which they become spendable.
+func TestTimeLockedReport(t *testing.T) {
+	t.Parallel()
+
+	report := newTimeLockedReport()
+
+	// Only the outgoing HTLCs of the open channel should be reported, as
+	// the value of incoming HTLCs isn't ours until they're settled.
+	report.addOpenChannel(&channeldb.OpenChannel{
+		LocalCommitment: channeldb.ChannelCommitment{
+			Htlcs: []channeldb.HTLC{
+				{
+					Amt:           lnwire.NewMSatFromSatoshis(1000),
+					RefundTimeout: 120,
+				},
+				{
+					Amt:           lnwire.NewMSatFromSatoshis(2000),
+					RefundTimeout: 110,
+					Incoming:      true,
+				},
+			},
+		},
+	})
+
+	// The force closed channel has an unswept commitment output maturing
+	// at height 120, a stage one htlc awaiting its CLTV expiry, a stage two
+	// htlc whose maturity isn't known yet, and an htlc which has already
+	// been swept.
+	report.addNurseryReport(&contractMaturityReport{
+		limboBalance:     10000 + 300 + 400,
+		recoveredBalance: 500,
+		maturityHeight:   120,
+		htlcs: []htlcMaturityReport{
+			{
+				amount:         300,
+				maturityHeight: 100,
+				cltvLocked:     true,
+			},
+			{
+				amount: 400,
+			},
+			{
+				amount:         500,
+				maturityHeight: 90,
+				recovered:      true,
+			},
+		},
+	})
+
+	expected := []*timeLockedBucket{
+		{
+			maturityHeight: 100,
+			cltvAmount:     300,
+			numOutputs:     1,
+		},
+		{
+			maturityHeight: 120,
+			csvAmount:      10000,
+			cltvAmount:     1000,
+			numOutputs:     2,
+		},
+		{
+			maturityHeight: 0,
+			csvAmount:      400,
+			numOutputs:     1,
+		},
+	}
+
+	buckets := report.sortedBuckets()
+	if !reflect.DeepEqual(buckets, expected) {
+		t.Fatalf("unexpected buckets: expected %v, got %v",
+			spew.Sdump(expected), spew.Sdump(buckets))
+	}
+}
@@ -1023,7 +1023,9 @@ func (u *utxoNursery) populateSweepTx(txWeight uint64, classHeight uint32,
 	}
 
 	// Using the txn weight estimate, compute the required txn fee.
-	feePerWeight, err := u.cfg.Estimator.EstimateFeePerWeight(6)
+	feePerWeight, err := lnwallet.EstimateRelayableFeePerWeight(
+		u.cfg.Estimator, 6,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -1446,6 +1448,14 @@ type htlcMaturityReport struct {
 	// to it's expiry height, while a stage 2 htlc's maturity height will be
 	// set to it's confirmation height plus the maturity requirement.
 	stage uint32
+
+	// cltvLocked is true if the htlc is time-locked by its CLTV expiry,
+	// rather than by a CSV delay.
+	cltvLocked bool
+
+	// recovered is true if the htlc has already been swept back to the
+	// wallet.
+	recovered bool
 }
 
 // AddLimboCommitment adds an incubating commitment output to maturity
@@ -1487,6 +1497,7 @@ func (c *contractMaturityReport) AddLimboStage1TimeoutHtlc(baby *babyOutput) {
 		confHeight:     baby.ConfHeight(),
 		maturityHeight: baby.expiry,
 		stage:          1,
+		cltvLocked:     true,
 	})
 }
 
@@ -1502,6 +1513,7 @@ func (c *contractMaturityReport) AddLimboDirectHtlc(kid *kidOutput) {
 		confHeight:     kid.ConfHeight(),
 		maturityHeight: kid.absoluteMaturity,
 		stage:          2,
+		cltvLocked:     true,
 	}
 
 	c.htlcs = append(c.htlcs, htlcReport)
@@ -1556,6 +1568,7 @@ func (c *contractMaturityReport) AddRecoveredHtlc(kid *kidOutput) {
 		confHeight:          kid.ConfHeight(),
 		maturityRequirement: kid.BlocksToMaturity(),
 		maturityHeight:      kid.ConfHeight() + kid.BlocksToMaturity(),
+		recovered:           true,
 	})
 }
 
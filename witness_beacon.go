@@ -83,8 +83,14 @@ func (p *preimageBeacon) LookupPreimage(payHash []byte) ([]byte, bool) {
 	}
 
 	// If we've found the invoice, then we can return the preimage
-	// directly.
+	// directly, unless it's a hold invoice whose preimage hasn't been
+	// released yet.
 	if err != channeldb.ErrInvoiceNotFound {
+		if invoice.Terms.Hold &&
+			invoice.Terms.HoldState != channeldb.HoldSettling {
+
+			return nil, false
+		}
 		return invoice.Terms.PaymentPreimage[:], true
 	}
 
@@ -68,6 +68,13 @@ const (
 
 	// fieldTypeC contains an optional requested final CLTV delta.
 	fieldTypeC = 24
+
+	// fieldTypeS contains the payment secret of the invoice.
+	fieldTypeS = 16
+
+	// fieldTypeJ contains the nonce of a stateless invoice. It's specific
+	// to lnd, and isn't assigned by BOLT #11.
+	fieldTypeJ = 18
 )
 
 // MessageSigner is passed to the Encode method to provide a signature
@@ -145,6 +152,19 @@ type Invoice struct {
 	// information for a private route to the target node.
 	// Optional.
 	RoutingInfo []ExtraRoutingInfo
+
+	// StatelessNonce is the nonce of a stateless invoice, which isn't
+	// stored by its creator. The payer is to pass it back, along with the
+	// amount, timestamp and expiry of the invoice, within the payment, so
+	// that the creator is able to derive the preimage from them.
+	// Optional.
+	StatelessNonce *[32]byte
+
+	// PaymentAddr is the payment secret of the invoice, which the payer is
+	// to include within the onion of the payment, proving that it knows
+	// the invoice.
+	// Optional.
+	PaymentAddr *[32]byte
 }
 
 // ExtraRoutingInfo holds the information needed to route a payment along one
@@ -240,6 +260,22 @@ func RoutingInfo(routingInfo []ExtraRoutingInfo) func(*Invoice) {
 	}
 }
 
+// StatelessNonce is a functional option that allows callers of NewInvoice to
+// set the nonce of a stateless invoice.
+func StatelessNonce(nonce [32]byte) func(*Invoice) {
+	return func(i *Invoice) {
+		i.StatelessNonce = &nonce
+	}
+}
+
+// PaymentAddr is a functional option that allows callers of NewInvoice to set
+// the payment secret of the invoice.
+func PaymentAddr(addr [32]byte) func(*Invoice) {
+	return func(i *Invoice) {
+		i.PaymentAddr = &addr
+	}
+}
+
 // NewInvoice creates a new Invoice object. The last parameter is a set of
 // variadic arguments for setting optional fields of the invoice.
 //
@@ -684,6 +720,25 @@ func parseTaggedFields(invoice *Invoice, fields []byte, net *chaincfg.Params) er
 			}
 
 			invoice.RoutingInfo, err = parseRoutingInfo(base32Data)
+		case fieldTypeS:
+			if invoice.PaymentAddr != nil {
+				// We skip the field if we have already seen a
+				// supported one.
+				continue
+			}
+
+			// The payment secret is encoded just as the payment
+			// hash is.
+			invoice.PaymentAddr, err = parsePaymentHash(base32Data)
+		case fieldTypeJ:
+			if invoice.StatelessNonce != nil {
+				// We skip the field if we have already seen a
+				// supported one.
+				continue
+			}
+
+			// The nonce is encoded just as the payment hash is.
+			invoice.StatelessNonce, err = parsePaymentHash(base32Data)
 		default:
 			// Ignore unknown type.
 		}
@@ -1012,6 +1067,42 @@ func writeTaggedFields(bufferBase32 *bytes.Buffer, invoice *Invoice) error {
 		}
 	}
 
+	if invoice.StatelessNonce != nil {
+		// Convert 32 byte nonce to 52 5-bit groups.
+		base32, err := bech32.ConvertBits(invoice.StatelessNonce[:], 8,
+			5, true)
+		if err != nil {
+			return err
+		}
+		if len(base32) != hashBase32Len {
+			return fmt.Errorf("invalid stateless nonce length: %d",
+				len(invoice.StatelessNonce))
+		}
+
+		err = writeTaggedField(bufferBase32, fieldTypeJ, base32)
+		if err != nil {
+			return err
+		}
+	}
+
+	if invoice.PaymentAddr != nil {
+		// Convert 32 byte payment secret to 52 5-bit groups.
+		base32, err := bech32.ConvertBits(invoice.PaymentAddr[:], 8, 5,
+			true)
+		if err != nil {
+			return err
+		}
+		if len(base32) != hashBase32Len {
+			return fmt.Errorf("invalid payment secret length: %d",
+				len(invoice.PaymentAddr))
+		}
+
+		err = writeTaggedField(bufferBase32, fieldTypeS, base32)
+		if err != nil {
+			return err
+		}
+	}
+
 	if invoice.Destination != nil {
 		// Convert 33 byte pubkey to 53 5-bit groups.
 		pubKeyBase32, err := bech32.ConvertBits(
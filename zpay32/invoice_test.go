@@ -620,6 +620,70 @@ func TestNewInvoice(t *testing.T) {
 	}
 }
 
+// TestStatelessNonce tests that the nonce of a stateless invoice survives an
+// encoding round trip.
+func TestStatelessNonce(t *testing.T) {
+	t.Parallel()
+
+	nonce := [32]byte{1, 2, 3}
+	invoice, err := NewInvoice(&chaincfg.MainNetParams, testPaymentHash,
+		time.Unix(1496314658, 0), Amount(testMillisat2500uBTC),
+		Description(testCupOfCoffee), Expiry(time.Minute),
+		StatelessNonce(nonce))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+
+	encoded, err := invoice.Encode(testMessageSigner)
+	if err != nil {
+		t.Fatalf("unable to encode invoice: %v", err)
+	}
+
+	decoded, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("unable to decode invoice: %v", err)
+	}
+	if decoded.StatelessNonce == nil || *decoded.StatelessNonce != nonce {
+		t.Fatalf("expected stateless nonce %x, got %x", nonce,
+			decoded.StatelessNonce)
+	}
+	if decoded.PaymentAddr != nil {
+		t.Fatalf("unexpected payment secret %x", decoded.PaymentAddr)
+	}
+}
+
+// TestPaymentAddr tests that the payment secret of an invoice survives an
+// encoding round trip, without being mistaken for a stateless nonce.
+func TestPaymentAddr(t *testing.T) {
+	t.Parallel()
+
+	addr := [32]byte{4, 5, 6}
+	invoice, err := NewInvoice(&chaincfg.MainNetParams, testPaymentHash,
+		time.Unix(1496314658, 0), Amount(testMillisat2500uBTC),
+		Description(testCupOfCoffee), PaymentAddr(addr))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+
+	encoded, err := invoice.Encode(testMessageSigner)
+	if err != nil {
+		t.Fatalf("unable to encode invoice: %v", err)
+	}
+
+	decoded, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("unable to decode invoice: %v", err)
+	}
+	if decoded.PaymentAddr == nil || *decoded.PaymentAddr != addr {
+		t.Fatalf("expected payment secret %x, got %x", addr,
+			decoded.PaymentAddr)
+	}
+	if decoded.StatelessNonce != nil {
+		t.Fatalf("unexpected stateless nonce %x",
+			decoded.StatelessNonce)
+	}
+}
+
 func compareInvoices(expected, actual *Invoice) error {
 	if !reflect.DeepEqual(expected.Net, actual.Net) {
 		return fmt.Errorf("expected net %v, got %v",
@@ -641,6 +705,16 @@ func compareInvoices(expected, actual *Invoice) error {
 			*expected.PaymentHash, *actual.PaymentHash)
 	}
 
+	if !compareHashes(expected.StatelessNonce, actual.StatelessNonce) {
+		return fmt.Errorf("expected stateless nonce %x, got %x",
+			expected.StatelessNonce, actual.StatelessNonce)
+	}
+
+	if !compareHashes(expected.PaymentAddr, actual.PaymentAddr) {
+		return fmt.Errorf("expected payment secret %x, got %x",
+			expected.PaymentAddr, actual.PaymentAddr)
+	}
+
 	if !reflect.DeepEqual(expected.Description, actual.Description) {
 		return fmt.Errorf("expected description \"%s\", got \"%s\"",
 			*expected.Description, *actual.Description)